@@ -112,6 +112,31 @@ func (b *Backend) PruneCache(ctx context.Context, opts types.BuildCachePruneOpti
 	return &types.BuildCachePruneReport{SpaceReclaimed: uint64(buildCacheSize), CachesDeleted: cacheIDs}, nil
 }
 
+// BuildRecords returns the records of the BuildKit builds of the daemon
+func (b *Backend) BuildRecords(ctx context.Context) ([]types.BuildRecord, error) {
+	return b.buildkit.BuildRecords(ctx)
+}
+
+// BuildRecord returns the record of a BuildKit build by ID
+func (b *Backend) BuildRecord(ctx context.Context, id string) (types.BuildRecord, error) {
+	return b.buildkit.BuildRecord(ctx, id)
+}
+
+// BuildFrontends returns the frontends registered with BuildKit
+func (b *Backend) BuildFrontends(ctx context.Context) ([]types.BuildFrontend, error) {
+	return b.buildkit.BuildFrontends(ctx)
+}
+
+// RegisterBuildFrontend registers a frontend image with BuildKit
+func (b *Backend) RegisterBuildFrontend(ctx context.Context, f types.BuildFrontend) (types.BuildFrontend, error) {
+	return b.buildkit.RegisterBuildFrontend(ctx, f)
+}
+
+// RemoveBuildFrontend removes a frontend registered with BuildKit by name
+func (b *Backend) RemoveBuildFrontend(ctx context.Context, name string) error {
+	return b.buildkit.RemoveBuildFrontend(ctx, name)
+}
+
 // Cancel cancels the build by ID
 func (b *Backend) Cancel(ctx context.Context, id string) error {
 	return b.buildkit.Cancel(ctx, id)
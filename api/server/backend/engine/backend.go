@@ -0,0 +1,61 @@
+package engine // import "github.com/docker/docker/api/server/backend/engine"
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/backend"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/registry"
+	"github.com/docker/docker/api/types/volume"
+	containerpkg "github.com/docker/docker/container"
+	"github.com/docker/docker/libnetwork"
+	"github.com/docker/docker/volume/service/opts"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// ContainerBackend provides the container operations of the service.
+type ContainerBackend interface {
+	Containers(ctx context.Context, config *types.ContainerListOptions) ([]*types.Container, error)
+	ContainerInspect(name string, size bool, version string) (interface{}, error)
+	ContainerCreate(ctx context.Context, config types.ContainerCreateConfig) (container.CreateResponse, error)
+	ContainerStart(ctx context.Context, name string, hostConfig *container.HostConfig, checkpoint string, checkpointDir string) error
+	ContainerStop(ctx context.Context, name string, options container.StopOptions) error
+	ContainerRm(name string, config *types.ContainerRmConfig) error
+	ContainerWait(ctx context.Context, name string, condition containerpkg.WaitCondition) (<-chan containerpkg.StateStatus, error)
+	ContainerLogs(ctx context.Context, name string, config *types.ContainerLogsOptions) (msgs <-chan *backend.LogMessage, tty bool, err error)
+	ContainerAttach(name string, c *backend.ContainerAttachConfig) error
+	ContainerResize(name string, height, width int) error
+}
+
+// ImageBackend provides the image operations of the service.
+type ImageBackend interface {
+	Images(ctx context.Context, opts types.ImageListOptions) ([]*types.ImageSummary, error)
+	ImageDelete(ctx context.Context, imageRef string, force, prune bool) ([]types.ImageDeleteResponseItem, error)
+	PullImage(ctx context.Context, image, tag string, platform *specs.Platform, metaHeaders map[string][]string, authConfig *registry.AuthConfig, outStream io.Writer) error
+}
+
+// NetworkBackend provides the network operations of the service.
+type NetworkBackend interface {
+	FindNetwork(idName string) (libnetwork.Network, error)
+	GetNetworks(filters.Args, types.NetworkListConfig) ([]types.NetworkResource, error)
+	CreateNetwork(nc types.NetworkCreateRequest) (*types.NetworkCreateResponse, error)
+	DeleteNetwork(networkID string) error
+}
+
+// VolumeBackend provides the volume operations of the service.
+type VolumeBackend interface {
+	List(ctx context.Context, filter filters.Args) ([]*volume.Volume, []string, error)
+	Create(ctx context.Context, name, driverName string, opts ...opts.CreateOption) (*volume.Volume, error)
+	Remove(ctx context.Context, name string, opts ...opts.RemoveOption) error
+}
+
+// EventsBackend provides the event stream of the service.
+type EventsBackend interface {
+	SubscribeToEvents(since, until time.Time, ef filters.Args) ([]events.Message, chan interface{})
+	UnsubscribeFromEvents(chan interface{})
+}
@@ -0,0 +1,296 @@
+package engine // import "github.com/docker/docker/api/server/backend/engine"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/containerd/containerd/platforms"
+	containerrouter "github.com/docker/docker/api/server/router/container"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/backend"
+	"github.com/docker/docker/api/types/container"
+	enginetypes "github.com/docker/docker/api/types/engine"
+	"github.com/docker/docker/api/types/filters"
+	containerpkg "github.com/docker/docker/container"
+	"github.com/docker/docker/errdefs"
+	gogotypes "github.com/gogo/protobuf/types"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+// ContainerList lists containers.
+func (s *Service) ContainerList(ctx context.Context, req *enginetypes.ContainerListRequest) (*enginetypes.ContainerListResponse, error) {
+	filter, err := filters.FromJSON(req.Filters)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+	containers, err := s.containers.Containers(ctx, &types.ContainerListOptions{
+		All:     req.All,
+		Limit:   int(req.Limit),
+		Filters: filter,
+	})
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+	resp := &enginetypes.ContainerListResponse{}
+	for _, c := range containers {
+		resp.Containers = append(resp.Containers, &enginetypes.ContainerSummary{
+			Id:      c.ID,
+			Names:   c.Names,
+			Image:   c.Image,
+			ImageId: c.ImageID,
+			Command: c.Command,
+			Created: toTimestamp(time.Unix(c.Created, 0)),
+			State:   c.State,
+			Status:  c.Status,
+			Labels:  c.Labels,
+		})
+	}
+	return resp, nil
+}
+
+// ContainerInspect returns the JSON-encoded low-level information of a
+// container.
+func (s *Service) ContainerInspect(ctx context.Context, req *enginetypes.ContainerInspectRequest) (*enginetypes.ContainerInspectResponse, error) {
+	c, err := s.containers.ContainerInspect(req.Id, req.WithSize, s.apiVersion)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+	dt, err := json.Marshal(c)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+	return &enginetypes.ContainerInspectResponse{Json: dt}, nil
+}
+
+// ContainerCreate creates a container.
+func (s *Service) ContainerCreate(ctx context.Context, req *enginetypes.ContainerCreateRequest) (*enginetypes.ContainerCreateResponse, error) {
+	config, hostConfig, networkingConfig, err := s.decoder.DecodeConfig(bytes.NewReader(req.Config))
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+	if err := containerrouter.AdjustCreateConfig(s.apiVersion, s.cgroup2, hostConfig, networkingConfig); err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	var platform *specs.Platform
+	if req.Platform != "" {
+		p, err := platforms.Parse(req.Platform)
+		if err != nil {
+			return nil, toGRPCError(errdefs.InvalidParameter(err))
+		}
+		platform = &p
+	}
+
+	ccr, err := s.containers.ContainerCreate(ctx, types.ContainerCreateConfig{
+		Name:             req.Name,
+		Config:           config,
+		HostConfig:       hostConfig,
+		NetworkingConfig: networkingConfig,
+		Platform:         platform,
+	})
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+	return &enginetypes.ContainerCreateResponse{Id: ccr.ID, Warnings: ccr.Warnings}, nil
+}
+
+// ContainerStart starts a container.
+func (s *Service) ContainerStart(ctx context.Context, req *enginetypes.ContainerStartRequest) (*enginetypes.ContainerStartResponse, error) {
+	if err := s.containers.ContainerStart(ctx, req.Id, nil, "", ""); err != nil {
+		return nil, toGRPCError(err)
+	}
+	return &enginetypes.ContainerStartResponse{}, nil
+}
+
+// ContainerStop stops a container.
+func (s *Service) ContainerStop(ctx context.Context, req *enginetypes.ContainerStopRequest) (*enginetypes.ContainerStopResponse, error) {
+	options := container.StopOptions{Signal: req.Signal}
+	if req.Timeout != nil {
+		d, err := gogotypes.DurationFromProto(req.Timeout)
+		if err != nil {
+			return nil, toGRPCError(errdefs.InvalidParameter(err))
+		}
+		timeout := int(d.Seconds())
+		if d < 0 {
+			timeout = -1
+		}
+		options.Timeout = &timeout
+	}
+	if err := s.containers.ContainerStop(ctx, req.Id, options); err != nil {
+		return nil, toGRPCError(err)
+	}
+	return &enginetypes.ContainerStopResponse{}, nil
+}
+
+// ContainerRemove removes a container.
+func (s *Service) ContainerRemove(ctx context.Context, req *enginetypes.ContainerRemoveRequest) (*enginetypes.ContainerRemoveResponse, error) {
+	err := s.containers.ContainerRm(req.Id, &types.ContainerRmConfig{
+		ForceRemove:  req.Force,
+		RemoveVolume: req.RemoveVolumes,
+		RemoveLink:   req.RemoveLink,
+	})
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+	return &enginetypes.ContainerRemoveResponse{}, nil
+}
+
+// ContainerWait waits for a container to reach the requested condition.
+func (s *Service) ContainerWait(ctx context.Context, req *enginetypes.ContainerWaitRequest) (*enginetypes.ContainerWaitResponse, error) {
+	var condition containerpkg.WaitCondition
+	switch container.WaitCondition(req.Condition) {
+	case "", container.WaitConditionNotRunning:
+		condition = containerpkg.WaitConditionNotRunning
+	case container.WaitConditionNextExit:
+		condition = containerpkg.WaitConditionNextExit
+	case container.WaitConditionRemoved:
+		condition = containerpkg.WaitConditionRemoved
+	default:
+		return nil, toGRPCError(errdefs.InvalidParameter(errors.Errorf("invalid condition: %q", req.Condition)))
+	}
+
+	waitC, err := s.containers.ContainerWait(ctx, req.Id, condition)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+	select {
+	case st := <-waitC:
+		resp := &enginetypes.ContainerWaitResponse{StatusCode: int64(st.ExitCode())}
+		if err := st.Err(); err != nil {
+			resp.Error = err.Error()
+		}
+		return resp, nil
+	case <-ctx.Done():
+		return nil, toGRPCError(ctx.Err())
+	}
+}
+
+// ContainerLogs streams the logs of a container.
+func (s *Service) ContainerLogs(req *enginetypes.ContainerLogsRequest, stream enginetypes.Engine_ContainerLogsServer) error {
+	if !(req.Stdout || req.Stderr) {
+		return toGRPCError(errdefs.InvalidParameter(errors.New("you must choose at least one stream")))
+	}
+	config := &types.ContainerLogsOptions{
+		ShowStdout: req.Stdout,
+		ShowStderr: req.Stderr,
+		Follow:     req.Follow,
+		Tail:       req.Tail,
+		Details:    req.Details,
+	}
+	for _, t := range []struct {
+		ts  *gogotypes.Timestamp
+		out *string
+	}{{req.Since, &config.Since}, {req.Until, &config.Until}} {
+		if t.ts == nil {
+			continue
+		}
+		if _, err := gogotypes.TimestampFromProto(t.ts); err != nil {
+			return toGRPCError(errdefs.InvalidParameter(err))
+		}
+		*t.out = fmt.Sprintf("%d.%09d", t.ts.Seconds, t.ts.Nanos)
+	}
+
+	msgs, _, err := s.containers.ContainerLogs(stream.Context(), req.Id, config)
+	if err != nil {
+		return toGRPCError(err)
+	}
+	for msg := range msgs {
+		if msg.Err != nil {
+			return toGRPCError(msg.Err)
+		}
+		entry := &enginetypes.LogEntry{
+			Stream: enginetypes.Stream_STDOUT,
+			Data:   msg.Line,
+			Time:   toTimestamp(msg.Timestamp),
+		}
+		if msg.Source == "stderr" {
+			entry.Stream = enginetypes.Stream_STDERR
+		}
+		if len(msg.Attrs) > 0 {
+			entry.Attrs = make(map[string]string, len(msg.Attrs))
+			for _, a := range msg.Attrs {
+				entry.Attrs[a.Key] = a.Value
+			}
+		}
+		if err := stream.Send(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ContainerAttach attaches to the streams of a container.
+func (s *Service) ContainerAttach(stream enginetypes.Engine_ContainerAttachServer) error {
+	req, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	start := req.GetStart()
+	if start == nil {
+		return toGRPCError(errdefs.InvalidParameter(errors.New("the first attach request must be a start request")))
+	}
+
+	stdinR, stdinW := io.Pipe()
+	defer stdinR.Close()
+
+	var mu sync.Mutex
+	stdout := &attachWriter{stream: stream, mu: &mu, kind: enginetypes.Stream_STDOUT}
+	stderr := &attachWriter{stream: stream, mu: &mu, kind: enginetypes.Stream_STDERR}
+
+	go func() {
+		for {
+			req, err := stream.Recv()
+			if err != nil {
+				stdinW.CloseWithError(err)
+				return
+			}
+			switch m := req.Msg.(type) {
+			case *enginetypes.AttachRequest_Stdin:
+				if _, err := stdinW.Write(m.Stdin); err != nil {
+					return
+				}
+			case *enginetypes.AttachRequest_Resize:
+				// Resizing fails if the container has no TTY, or is not
+				// running anymore; neither should end the attach.
+				_ = s.containers.ContainerResize(start.Id, int(m.Resize.Height), int(m.Resize.Width))
+			case *enginetypes.AttachRequest_CloseStdin:
+				stdinW.Close()
+			}
+		}
+	}()
+
+	err = s.containers.ContainerAttach(start.Id, &backend.ContainerAttachConfig{
+		GetStreams: func(bool) (io.ReadCloser, io.Writer, io.Writer, error) {
+			return stdinR, stdout, stderr, nil
+		},
+		UseStdin:   start.Stdin,
+		UseStdout:  start.Stdout,
+		UseStderr:  start.Stderr,
+		Logs:       start.Logs,
+		Stream:     true,
+		DetachKeys: start.DetachKeys,
+	})
+	return toGRPCError(err)
+}
+
+// attachWriter sends the output of a container on an attach stream.
+type attachWriter struct {
+	stream enginetypes.Engine_ContainerAttachServer
+	mu     *sync.Mutex
+	kind   enginetypes.Stream
+}
+
+func (w *attachWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.stream.Send(&enginetypes.AttachResponse{Stream: w.kind, Data: p}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -0,0 +1,281 @@
+// Package engine implements the gRPC flavor of the Engine API.
+package engine // import "github.com/docker/docker/api/server/backend/engine"
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/server/httputils"
+	enginetypes "github.com/docker/docker/api/types/engine"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/errdefs"
+	gogotypes "github.com/gogo/protobuf/types"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// serviceName is the full name of the Engine gRPC service.
+const serviceName = "docker.engine.v1.Engine"
+
+// errorDomain is the domain of the ErrorInfo details attached to errors.
+const errorDomain = "docker.com"
+
+// Config holds the backends of the service.
+type Config struct {
+	// APIVersion is the version of the HTTP API whose JSON documents are
+	// used in requests and responses.
+	APIVersion   string
+	Experimental bool
+
+	// Cgroup2 reports whether the host uses cgroup v2.
+	Cgroup2 bool
+
+	Decoder    httputils.ContainerDecoder
+	Containers ContainerBackend
+	Images     ImageBackend
+	Volumes    VolumeBackend
+	Events     EventsBackend
+
+	// Networks is nil if networking is disabled.
+	Networks NetworkBackend
+
+	// Restricted reports whether the API is protected by authorization,
+	// access or audit policies. These are only enforced on HTTP requests,
+	// so the calls to the service are rejected while it returns true.
+	Restricted func() bool
+}
+
+// Service implements the Engine gRPC service.
+type Service struct {
+	apiVersion   string
+	experimental bool
+	cgroup2      bool
+	decoder      httputils.ContainerDecoder
+	containers   ContainerBackend
+	images       ImageBackend
+	volumes      VolumeBackend
+	events       EventsBackend
+	networks     NetworkBackend
+	restricted   func() bool
+}
+
+var _ enginetypes.EngineServer = &Service{}
+
+// NewService creates a new Service.
+func NewService(config Config) *Service {
+	return &Service{
+		apiVersion:   config.APIVersion,
+		experimental: config.Experimental,
+		cgroup2:      config.Cgroup2,
+		decoder:      config.Decoder,
+		containers:   config.Containers,
+		images:       config.Images,
+		volumes:      config.Volumes,
+		events:       config.Events,
+		networks:     config.Networks,
+		restricted:   config.Restricted,
+	}
+}
+
+// RegisterGRPC registers the service to the grpc server.
+func (s *Service) RegisterGRPC(srv *grpc.Server) {
+	enginetypes.RegisterEngineServer(srv, s)
+}
+
+// errRestricted is returned by the calls to the service while the API is
+// protected by policies which are not enforced on gRPC calls.
+var errRestricted = status.Error(codes.PermissionDenied, "the Engine gRPC API is disabled while authorization plugins, a socket access policy or the audit log are configured")
+
+func (s *Service) checkRestricted(method string) error {
+	if s.restricted != nil && s.restricted() && strings.HasPrefix(method, "/"+serviceName+"/") {
+		return errRestricted
+	}
+	return nil
+}
+
+// UnaryInterceptor rejects the unary calls to the service while the API is
+// restricted.
+func (s *Service) UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := s.checkRestricted(info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// StreamInterceptor rejects the streaming calls to the service while the API
+// is restricted.
+func (s *Service) StreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := s.checkRestricted(info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
+
+// Ping returns information about the API.
+func (s *Service) Ping(ctx context.Context, req *enginetypes.PingRequest) (*enginetypes.PingResponse, error) {
+	return &enginetypes.PingResponse{
+		ApiVersion:   s.apiVersion,
+		OsType:       runtime.GOOS,
+		Experimental: s.experimental,
+	}, nil
+}
+
+// Events streams the events of the daemon.
+func (s *Service) Events(req *enginetypes.EventsRequest, stream enginetypes.Engine_EventsServer) error {
+	since, err := fromTimestamp(req.Since)
+	if err != nil {
+		return toGRPCError(err)
+	}
+	until, err := fromTimestamp(req.Until)
+	if err != nil {
+		return toGRPCError(err)
+	}
+	ef, err := filters.FromJSON(req.Filters)
+	if err != nil {
+		return toGRPCError(err)
+	}
+
+	var (
+		timeout        <-chan time.Time
+		onlyPastEvents bool
+	)
+	if !until.IsZero() {
+		if until.Before(since) {
+			return toGRPCError(errdefs.InvalidParameter(fmt.Errorf("`since` time (%s) cannot be after `until` time (%s)", since, until)))
+		}
+		now := time.Now()
+		onlyPastEvents = until.Before(now)
+		if !onlyPastEvents {
+			timer := time.NewTimer(until.Sub(now))
+			defer timer.Stop()
+			timeout = timer.C
+		}
+	}
+
+	buffered, l := s.events.SubscribeToEvents(since, until, ef)
+	defer s.events.UnsubscribeFromEvents(l)
+
+	for _, ev := range buffered {
+		if err := stream.Send(toEvent(ev)); err != nil {
+			return err
+		}
+	}
+	if onlyPastEvents {
+		return nil
+	}
+
+	ctx := stream.Context()
+	for {
+		select {
+		case ev := <-l:
+			jev, ok := ev.(events.Message)
+			if !ok {
+				logrus.Warnf("unexpected event message: %q", ev)
+				continue
+			}
+			if err := stream.Send(toEvent(jev)); err != nil {
+				return err
+			}
+		case <-timeout:
+			return nil
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func toEvent(ev events.Message) *enginetypes.Event {
+	return &enginetypes.Event{
+		Type:       string(ev.Type),
+		Action:     ev.Action,
+		ActorId:    ev.Actor.ID,
+		Attributes: ev.Actor.Attributes,
+		Scope:      ev.Scope,
+		Time:       toTimestamp(time.Unix(0, ev.TimeNano)),
+	}
+}
+
+func toTimestamp(t time.Time) *gogotypes.Timestamp {
+	ts, err := gogotypes.TimestampProto(t)
+	if err != nil {
+		return nil
+	}
+	return ts
+}
+
+// fromTimestamp converts a timestamp of a request. It returns the zero time
+// if ts is not set.
+func fromTimestamp(ts *gogotypes.Timestamp) (time.Time, error) {
+	if ts == nil {
+		return time.Time{}, nil
+	}
+	t, err := gogotypes.TimestampFromProto(ts)
+	if err != nil {
+		return time.Time{}, errdefs.InvalidParameter(err)
+	}
+	return t, nil
+}
+
+// toGRPCError converts an error to a gRPC status error. The machine-readable
+// code and details of the error, if any, are attached as an ErrorInfo.
+func toGRPCError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+
+	st := status.New(grpcCode(err), err.Error())
+	info := &errdetails.ErrorInfo{
+		Reason:   errdefs.Code(err),
+		Domain:   errorDomain,
+		Metadata: errdefs.Details(err),
+	}
+	if withInfo, err := st.WithDetails(info); err == nil {
+		st = withInfo
+	}
+	return st.Err()
+}
+
+func grpcCode(err error) codes.Code {
+	switch {
+	case errdefs.IsNotFound(err):
+		return codes.NotFound
+	case errdefs.IsInvalidParameter(err):
+		return codes.InvalidArgument
+	case errdefs.IsConflict(err):
+		return codes.AlreadyExists
+	case errdefs.IsUnauthorized(err):
+		return codes.Unauthenticated
+	case errdefs.IsForbidden(err):
+		return codes.PermissionDenied
+	case errdefs.IsUnavailable(err):
+		return codes.Unavailable
+	case errdefs.IsNotModified(err):
+		return codes.FailedPrecondition
+	case errdefs.IsNotImplemented(err):
+		return codes.Unimplemented
+	case errdefs.IsCancelled(err):
+		return codes.Canceled
+	case errdefs.IsDeadline(err):
+		return codes.DeadlineExceeded
+	case errdefs.IsDataLoss(err):
+		return codes.DataLoss
+	case errdefs.IsSystem(err):
+		return codes.Internal
+	default:
+		return codes.Unknown
+	}
+}
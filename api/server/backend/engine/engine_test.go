@@ -0,0 +1,217 @@
+package engine // import "github.com/docker/docker/api/server/backend/engine"
+
+import (
+	"context"
+	"io"
+	"net"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/backend"
+	"github.com/docker/docker/api/types/container"
+	enginetypes "github.com/docker/docker/api/types/engine"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+type fakeContainers struct {
+	ContainerBackend
+	containers []*types.Container
+	logs       []*backend.LogMessage
+	resized    chan [2]int
+}
+
+func (f *fakeContainers) Containers(ctx context.Context, config *types.ContainerListOptions) ([]*types.Container, error) {
+	var out []*types.Container
+	for _, c := range f.containers {
+		if config.Filters.Contains("label") && !config.Filters.MatchKVList("label", c.Labels) {
+			continue
+		}
+		out = append(out, c)
+	}
+	return out, nil
+}
+
+func (f *fakeContainers) ContainerStart(ctx context.Context, name string, _ *container.HostConfig, _, _ string) error {
+	return errdefs.NotFound(errors.Errorf("No such container: %s", name))
+}
+
+func (f *fakeContainers) ContainerLogs(ctx context.Context, name string, config *types.ContainerLogsOptions) (<-chan *backend.LogMessage, bool, error) {
+	ch := make(chan *backend.LogMessage, len(f.logs))
+	for _, m := range f.logs {
+		ch <- m
+	}
+	close(ch)
+	return ch, false, nil
+}
+
+func (f *fakeContainers) ContainerResize(name string, height, width int) error {
+	f.resized <- [2]int{height, width}
+	return nil
+}
+
+// ContainerAttach echoes stdin to stdout, and reports the end of stdin on
+// stderr.
+func (f *fakeContainers) ContainerAttach(name string, c *backend.ContainerAttachConfig) error {
+	stdin, stdout, stderr, err := c.GetStreams(false)
+	if err != nil {
+		return err
+	}
+	defer stdin.Close()
+	if _, err := io.Copy(stdout, stdin); err != nil {
+		return err
+	}
+	_, err = stderr.Write([]byte("eof"))
+	return err
+}
+
+func newTestClient(t *testing.T, s *Service) enginetypes.EngineClient {
+	t.Helper()
+	sock := filepath.Join(t.TempDir(), "engine.sock")
+	l, err := net.Listen("unix", sock)
+	assert.NilError(t, err)
+	srv := grpc.NewServer(grpc.UnaryInterceptor(s.UnaryInterceptor), grpc.StreamInterceptor(s.StreamInterceptor))
+	s.RegisterGRPC(srv)
+	go srv.Serve(l)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.Dial("unix://"+sock, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NilError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return enginetypes.NewEngineClient(conn)
+}
+
+func TestService(t *testing.T) {
+	containers := &fakeContainers{
+		containers: []*types.Container{
+			{ID: "a", Names: []string{"/a"}, Created: 1000, Labels: map[string]string{"app": "web"}},
+			{ID: "b", Names: []string{"/b"}, Created: 2000},
+		},
+		logs: []*backend.LogMessage{
+			{Line: []byte("out\n"), Source: "stdout", Timestamp: time.Unix(10, 0)},
+			{Line: []byte("err\n"), Source: "stderr", Timestamp: time.Unix(11, 0), Attrs: []backend.LogAttr{{Key: "k", Value: "v"}}},
+		},
+		resized: make(chan [2]int, 1),
+	}
+	client := newTestClient(t, NewService(Config{APIVersion: "1.43", Containers: containers}))
+	ctx := context.Background()
+
+	t.Run("ping", func(t *testing.T) {
+		resp, err := client.Ping(ctx, &enginetypes.PingRequest{})
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(resp.ApiVersion, "1.43"))
+	})
+
+	t.Run("list", func(t *testing.T) {
+		resp, err := client.ContainerList(ctx, &enginetypes.ContainerListRequest{Filters: `{"label":{"app=web":true}}`})
+		assert.NilError(t, err)
+		assert.Assert(t, is.Len(resp.Containers, 1))
+		assert.Check(t, is.Equal(resp.Containers[0].Id, "a"))
+		assert.Check(t, is.Equal(resp.Containers[0].Created.Seconds, int64(1000)))
+
+		_, err = client.ContainerList(ctx, &enginetypes.ContainerListRequest{Filters: "{"})
+		assert.Check(t, is.Equal(status.Code(err), codes.InvalidArgument))
+	})
+
+	t.Run("error", func(t *testing.T) {
+		_, err := client.ContainerStart(ctx, &enginetypes.ContainerStartRequest{Id: "missing"})
+		st := status.Convert(err)
+		assert.Check(t, is.Equal(st.Code(), codes.NotFound))
+		assert.Check(t, is.Equal(st.Message(), "No such container: missing"))
+		assert.Assert(t, is.Len(st.Details(), 1))
+		info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+		assert.Assert(t, ok)
+		assert.Check(t, is.Equal(info.Reason, errdefs.CodeNotFound))
+	})
+
+	t.Run("logs", func(t *testing.T) {
+		_, err := receiveLogs(ctx, client, &enginetypes.ContainerLogsRequest{Id: "a"})
+		assert.Check(t, is.Equal(status.Code(err), codes.InvalidArgument))
+
+		entries, err := receiveLogs(ctx, client, &enginetypes.ContainerLogsRequest{Id: "a", Stdout: true, Stderr: true})
+		assert.NilError(t, err)
+		assert.Assert(t, is.Len(entries, 2))
+		assert.Check(t, is.Equal(entries[0].Stream, enginetypes.Stream_STDOUT))
+		assert.Check(t, is.Equal(string(entries[0].Data), "out\n"))
+		assert.Check(t, is.Equal(entries[1].Stream, enginetypes.Stream_STDERR))
+		assert.Check(t, is.Equal(entries[1].Time.Seconds, int64(11)))
+		assert.Check(t, is.DeepEqual(entries[1].Attrs, map[string]string{"k": "v"}))
+	})
+
+	t.Run("attach", func(t *testing.T) {
+		stream, err := client.ContainerAttach(ctx)
+		assert.NilError(t, err)
+		send := func(req *enginetypes.AttachRequest) {
+			assert.NilError(t, stream.Send(req))
+		}
+		send(&enginetypes.AttachRequest{Msg: &enginetypes.AttachRequest_Start{Start: &enginetypes.AttachStart{Id: "a", Stdin: true, Stdout: true, Stderr: true}}})
+		send(&enginetypes.AttachRequest{Msg: &enginetypes.AttachRequest_Resize{Resize: &enginetypes.Resize{Height: 24, Width: 80}}})
+		assert.Check(t, is.Equal(<-containers.resized, [2]int{24, 80}))
+
+		send(&enginetypes.AttachRequest{Msg: &enginetypes.AttachRequest_Stdin{Stdin: []byte("hello")}})
+		resp, err := stream.Recv()
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(resp.Stream, enginetypes.Stream_STDOUT))
+		assert.Check(t, is.Equal(string(resp.Data), "hello"))
+
+		send(&enginetypes.AttachRequest{Msg: &enginetypes.AttachRequest_CloseStdin{CloseStdin: &enginetypes.CloseStdin{}}})
+		resp, err = stream.Recv()
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(resp.Stream, enginetypes.Stream_STDERR))
+		assert.Check(t, is.Equal(string(resp.Data), "eof"))
+
+		_, err = stream.Recv()
+		assert.Check(t, is.Equal(err, io.EOF))
+	})
+
+	t.Run("attach without start", func(t *testing.T) {
+		stream, err := client.ContainerAttach(ctx)
+		assert.NilError(t, err)
+		assert.NilError(t, stream.Send(&enginetypes.AttachRequest{Msg: &enginetypes.AttachRequest_Stdin{Stdin: []byte("hello")}}))
+		_, err = stream.Recv()
+		assert.Check(t, is.Equal(status.Code(err), codes.InvalidArgument))
+	})
+}
+
+func TestServiceRestricted(t *testing.T) {
+	var restricted atomic.Bool
+	client := newTestClient(t, NewService(Config{APIVersion: "1.43", Restricted: restricted.Load}))
+	ctx := context.Background()
+
+	_, err := client.Ping(ctx, &enginetypes.PingRequest{})
+	assert.NilError(t, err)
+
+	restricted.Store(true)
+	_, err = client.Ping(ctx, &enginetypes.PingRequest{})
+	assert.Check(t, is.Equal(status.Code(err), codes.PermissionDenied))
+	_, err = receiveLogs(ctx, client, &enginetypes.ContainerLogsRequest{Id: "a", Stdout: true})
+	assert.Check(t, is.Equal(status.Code(err), codes.PermissionDenied))
+}
+
+func receiveLogs(ctx context.Context, client enginetypes.EngineClient, req *enginetypes.ContainerLogsRequest) ([]*enginetypes.LogEntry, error) {
+	stream, err := client.ContainerLogs(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	var entries []*enginetypes.LogEntry
+	for {
+		entry, err := stream.Recv()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+}
@@ -0,0 +1,124 @@
+package engine // import "github.com/docker/docker/api/server/backend/engine"
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/containerd/containerd/platforms"
+	"github.com/docker/docker/api/types"
+	enginetypes "github.com/docker/docker/api/types/engine"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/registry"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/jsonmessage"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// ImageList lists images.
+func (s *Service) ImageList(ctx context.Context, req *enginetypes.ImageListRequest) (*enginetypes.ImageListResponse, error) {
+	filter, err := filters.FromJSON(req.Filters)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+	images, err := s.images.Images(ctx, types.ImageListOptions{All: req.All, Filters: filter})
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+	resp := &enginetypes.ImageListResponse{}
+	for _, img := range images {
+		resp.Images = append(resp.Images, &enginetypes.ImageSummary{
+			Id:          img.ID,
+			ParentId:    img.ParentID,
+			RepoTags:    img.RepoTags,
+			RepoDigests: img.RepoDigests,
+			Created:     toTimestamp(time.Unix(img.Created, 0)),
+			SizeBytes:   img.Size,
+			Labels:      img.Labels,
+		})
+	}
+	return resp, nil
+}
+
+// ImagePull pulls an image, and streams the progress of the pull.
+func (s *Service) ImagePull(req *enginetypes.ImagePullRequest, stream enginetypes.Engine_ImagePullServer) error {
+	var platform *specs.Platform
+	if req.Platform != "" {
+		p, err := platforms.Parse(req.Platform)
+		if err != nil {
+			return toGRPCError(errdefs.InvalidParameter(err))
+		}
+		platform = &p
+	}
+	authConfig := &registry.AuthConfig{}
+	if a := req.Auth; a != nil {
+		authConfig = &registry.AuthConfig{
+			Username:      a.Username,
+			Password:      a.Password,
+			ServerAddress: a.ServerAddress,
+			IdentityToken: a.IdentityToken,
+			RegistryToken: a.RegistryToken,
+		}
+	}
+
+	pr, pw := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		done <- sendPullProgress(pr, stream)
+		// Unblock the pull if the progress can't be sent anymore.
+		pr.Close()
+	}()
+
+	err := s.images.PullImage(stream.Context(), req.Ref, "", platform, nil, authConfig, pw)
+	pw.Close()
+	if sendErr := <-done; err == nil {
+		err = sendErr
+	}
+	return toGRPCError(err)
+}
+
+// sendPullProgress decodes the JSON progress messages written by a pull, and
+// sends them on the stream.
+func sendPullProgress(r io.Reader, stream enginetypes.Engine_ImagePullServer) error {
+	dec := json.NewDecoder(r)
+	for {
+		var msg jsonmessage.JSONMessage
+		if err := dec.Decode(&msg); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		if msg.Error != nil {
+			// Errors are returned by the pull itself.
+			continue
+		}
+		p := &enginetypes.PullProgress{Id: msg.ID, Status: msg.Status}
+		if msg.Progress != nil {
+			p.Current = msg.Progress.Current
+			p.Total = msg.Progress.Total
+		}
+		if err := stream.Send(p); err != nil {
+			return err
+		}
+	}
+}
+
+// ImageRemove removes an image.
+func (s *Service) ImageRemove(ctx context.Context, req *enginetypes.ImageRemoveRequest) (*enginetypes.ImageRemoveResponse, error) {
+	items, err := s.images.ImageDelete(ctx, req.Ref, req.Force, !req.NoPrune)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+	resp := &enginetypes.ImageRemoveResponse{}
+	for _, item := range items {
+		if item.Untagged != "" {
+			resp.Untagged = append(resp.Untagged, item.Untagged)
+		}
+		if item.Deleted != "" {
+			resp.Deleted = append(resp.Deleted, item.Deleted)
+		}
+	}
+	return resp, nil
+}
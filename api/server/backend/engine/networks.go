@@ -0,0 +1,74 @@
+package engine // import "github.com/docker/docker/api/server/backend/engine"
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/docker/docker/api/types"
+	enginetypes "github.com/docker/docker/api/types/engine"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+)
+
+var errNetworkingDisabled = errdefs.Unavailable(errors.New("networking is disabled"))
+
+// NetworkList lists the networks of the daemon. Swarm-scoped networks
+// are not included.
+func (s *Service) NetworkList(ctx context.Context, req *enginetypes.NetworkListRequest) (*enginetypes.NetworkListResponse, error) {
+	if s.networks == nil {
+		return nil, toGRPCError(errNetworkingDisabled)
+	}
+	filter, err := filters.FromJSON(req.Filters)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+	networks, err := s.networks.GetNetworks(filter, types.NetworkListConfig{})
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+	resp := &enginetypes.NetworkListResponse{}
+	for _, nw := range networks {
+		resp.Networks = append(resp.Networks, &enginetypes.NetworkSummary{
+			Id:         nw.ID,
+			Name:       nw.Name,
+			Driver:     nw.Driver,
+			Scope:      nw.Scope,
+			Internal:   nw.Internal,
+			Attachable: nw.Attachable,
+			Labels:     nw.Labels,
+		})
+	}
+	return resp, nil
+}
+
+// NetworkCreate creates a network.
+func (s *Service) NetworkCreate(ctx context.Context, req *enginetypes.NetworkCreateRequest) (*enginetypes.NetworkCreateResponse, error) {
+	if s.networks == nil {
+		return nil, toGRPCError(errNetworkingDisabled)
+	}
+	var create types.NetworkCreateRequest
+	if err := json.Unmarshal(req.Config, &create); err != nil {
+		return nil, toGRPCError(errdefs.InvalidParameter(err))
+	}
+	nw, err := s.networks.CreateNetwork(create)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+	return &enginetypes.NetworkCreateResponse{Id: nw.ID, Warning: nw.Warning}, nil
+}
+
+// NetworkRemove removes a network.
+func (s *Service) NetworkRemove(ctx context.Context, req *enginetypes.NetworkRemoveRequest) (*enginetypes.NetworkRemoveResponse, error) {
+	if s.networks == nil {
+		return nil, toGRPCError(errNetworkingDisabled)
+	}
+	nw, err := s.networks.FindNetwork(req.Id)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+	if err := s.networks.DeleteNetwork(nw.ID()); err != nil {
+		return nil, toGRPCError(err)
+	}
+	return &enginetypes.NetworkRemoveResponse{}, nil
+}
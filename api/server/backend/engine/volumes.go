@@ -0,0 +1,55 @@
+package engine // import "github.com/docker/docker/api/server/backend/engine"
+
+import (
+	"context"
+
+	enginetypes "github.com/docker/docker/api/types/engine"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/volume/service/opts"
+)
+
+// VolumeList lists the local volumes.
+func (s *Service) VolumeList(ctx context.Context, req *enginetypes.VolumeListRequest) (*enginetypes.VolumeListResponse, error) {
+	filter, err := filters.FromJSON(req.Filters)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+	volumes, warnings, err := s.volumes.List(ctx, filter)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+	resp := &enginetypes.VolumeListResponse{Warnings: warnings}
+	for _, v := range volumes {
+		resp.Volumes = append(resp.Volumes, toVolume(v))
+	}
+	return resp, nil
+}
+
+// VolumeCreate creates a local volume.
+func (s *Service) VolumeCreate(ctx context.Context, req *enginetypes.VolumeCreateRequest) (*enginetypes.Volume, error) {
+	v, err := s.volumes.Create(ctx, req.Name, req.Driver, opts.WithCreateOptions(req.DriverOpts), opts.WithCreateLabels(req.Labels))
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+	return toVolume(v), nil
+}
+
+// VolumeRemove removes a local volume.
+func (s *Service) VolumeRemove(ctx context.Context, req *enginetypes.VolumeRemoveRequest) (*enginetypes.VolumeRemoveResponse, error) {
+	if err := s.volumes.Remove(ctx, req.Name, opts.WithPurgeOnError(req.Force)); err != nil {
+		return nil, toGRPCError(err)
+	}
+	return &enginetypes.VolumeRemoveResponse{}, nil
+}
+
+func toVolume(v *volume.Volume) *enginetypes.Volume {
+	return &enginetypes.Volume{
+		Name:       v.Name,
+		Driver:     v.Driver,
+		Mountpoint: v.Mountpoint,
+		Scope:      v.Scope,
+		Labels:     v.Labels,
+		Options:    v.Options,
+	}
+}
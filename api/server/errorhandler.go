@@ -7,6 +7,7 @@ import (
 	"github.com/docker/docker/api/server/httputils"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/versions"
+	"github.com/docker/docker/errdefs"
 	"github.com/gorilla/mux"
 	"google.golang.org/grpc/status"
 )
@@ -21,6 +22,12 @@ func makeErrorHandler(err error) http.HandlerFunc {
 			response := &types.ErrorResponse{
 				Message: err.Error(),
 			}
+			// Error codes and details were added in API 1.43. Requests
+			// without a version use the default version, which has them.
+			if v := vars["version"]; v == "" || versions.GreaterThanOrEqualTo(v, "1.43") {
+				response.Code = errdefs.Code(err)
+				response.Details = errdefs.Details(err)
+			}
 			_ = httputils.WriteJSON(w, statusCode, response)
 		} else {
 			http.Error(w, status.Convert(err).Message(), statusCode)
@@ -5,9 +5,12 @@ import (
 	"encoding/json"
 	"io"
 	"mime"
+	"net"
 	"net/http"
 	"strings"
 
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/versions"
 	"github.com/docker/docker/errdefs"
 	"github.com/pkg/errors"
 )
@@ -15,6 +18,22 @@ import (
 // APIVersionKey is the client's requested API version.
 type APIVersionKey struct{}
 
+// connKey is the context key under which the client's connection is stored.
+type connKey struct{}
+
+// WithConn returns a copy of ctx that holds the connection the request was
+// received on. It is used as http.Server.ConnContext.
+func WithConn(ctx context.Context, c net.Conn) context.Context {
+	return context.WithValue(ctx, connKey{}, c)
+}
+
+// ConnFromContext returns the connection the request was received on, or nil
+// if it is not known.
+func ConnFromContext(ctx context.Context) net.Conn {
+	c, _ := ctx.Value(connKey{}).(net.Conn)
+	return c
+}
+
 // APIFunc is an adapter to allow the use of ordinary functions as Docker API endpoints.
 // Any function that has the appropriate signature can be registered as an API endpoint (e.g. getVersion).
 type APIFunc func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error
@@ -132,3 +151,17 @@ func matchesContentType(contentType, expectedType string) error {
 	}
 	return nil
 }
+
+// AcceptsProgressV2 returns whether the client requested the v2 progress
+// stream in the Accept header, which requires API v1.43 or up.
+func AcceptsProgressV2(ctx context.Context, r *http.Request) bool {
+	if versions.LessThan(VersionFromContext(ctx), "1.43") {
+		return false
+	}
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		if mimetype, _, err := mime.ParseMediaType(strings.TrimSpace(accept)); err == nil && mimetype == types.MediaTypeProgressV2 {
+			return true
+		}
+	}
+	return false
+}
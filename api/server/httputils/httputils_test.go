@@ -1,6 +1,7 @@
 package httputils // import "github.com/docker/docker/api/server/httputils"
 
 import (
+	"context"
 	"net/http"
 	"strings"
 	"testing"
@@ -128,3 +129,54 @@ func TestReadJSON(t *testing.T) {
 		}
 	})
 }
+
+func TestReadLabelsUpdate(t *testing.T) {
+	for _, tc := range []struct {
+		body     string
+		expected string
+	}{
+		{body: `{"Add": {"a": "1"}, "Remove": ["b"]}`},
+		{body: `{}`, expected: "no labels to add or remove"},
+		{body: `{"Add": {"": "1"}}`, expected: "label key cannot be empty"},
+		{body: `{"Remove": [""]}`, expected: "label key cannot be empty"},
+		{body: `{"Add": {"a": "1"}, "Remove": ["a"]}`, expected: "label a cannot be both added and removed"},
+	} {
+		req, err := http.NewRequest("POST", "https://example.com/containers/web/labels", strings.NewReader(tc.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		_, err = ReadLabelsUpdate(req)
+		if tc.expected == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", tc.body, err)
+			}
+		} else if err == nil || err.Error() != tc.expected {
+			t.Errorf(`%s: expected "%s", got "%v"`, tc.body, tc.expected, err)
+		}
+	}
+}
+
+func TestAcceptsProgressV2(t *testing.T) {
+	for _, tc := range []struct {
+		version  string
+		accept   string
+		expected bool
+	}{
+		{version: "1.43", accept: "application/vnd.docker.progress.v2+json", expected: true},
+		{version: "1.43", accept: "application/json, application/vnd.docker.progress.v2+json; q=0.9", expected: true},
+		{version: "1.43", accept: "application/json"},
+		{version: "1.43"},
+		{version: "1.42", accept: "application/vnd.docker.progress.v2+json"},
+	} {
+		req, err := http.NewRequest("POST", "https://example.com/images/create", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Accept", tc.accept)
+		ctx := context.WithValue(context.Background(), APIVersionKey{}, tc.version)
+		if actual := AcceptsProgressV2(ctx, req); actual != tc.expected {
+			t.Errorf("version %s, Accept %q: expected %v, got %v", tc.version, tc.accept, tc.expected, actual)
+		}
+	}
+}
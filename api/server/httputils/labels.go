@@ -0,0 +1,35 @@
+package httputils // import "github.com/docker/docker/api/server/httputils"
+
+import (
+	"net/http"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+)
+
+// ReadLabelsUpdate decodes the request's Body into a LabelsUpdate. It fails if
+// the update is empty, has an empty key, or both adds and removes a label.
+func ReadLabelsUpdate(r *http.Request) (types.LabelsUpdate, error) {
+	var update types.LabelsUpdate
+	if err := ReadJSON(r, &update); err != nil {
+		return update, err
+	}
+	if len(update.Add) == 0 && len(update.Remove) == 0 {
+		return update, errdefs.InvalidParameter(errors.New("no labels to add or remove"))
+	}
+	for k := range update.Add {
+		if k == "" {
+			return update, errdefs.InvalidParameter(errors.New("label key cannot be empty"))
+		}
+	}
+	for _, k := range update.Remove {
+		if k == "" {
+			return update, errdefs.InvalidParameter(errors.New("label key cannot be empty"))
+		}
+		if _, ok := update.Add[k]; ok {
+			return update, errdefs.InvalidParameter(errors.Errorf("label %s cannot be both added and removed", k))
+		}
+	}
+	return update, nil
+}
@@ -0,0 +1,187 @@
+package middleware // import "github.com/docker/docker/api/server/middleware"
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/server/httpstatus"
+	"github.com/docker/docker/api/server/httputils"
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Audit decisions recorded in an AuditEntry.
+const (
+	AuditDecisionAllow = "allow"
+	AuditDecisionDeny  = "deny"
+	AuditDecisionError = "error"
+)
+
+// PeerCredentials holds the credentials of the process that connected to the
+// API over a unix socket.
+type PeerCredentials struct {
+	UID uint32 `json:"uid"`
+	GID uint32 `json:"gid"`
+	PID int32  `json:"pid"`
+}
+
+// AuditEntry describes a single API request as recorded in the audit log.
+type AuditEntry struct {
+	Time       time.Time        `json:"time"`
+	Method     string           `json:"method"`
+	Path       string           `json:"path"`
+	Endpoint   string           `json:"endpoint,omitempty"`
+	APIVersion string           `json:"api_version,omitempty"`
+	Target     string           `json:"target,omitempty"`
+	User       string           `json:"user,omitempty"`
+	AuthMethod string           `json:"auth_method,omitempty"`
+	Peer       *PeerCredentials `json:"peer,omitempty"`
+	RemoteAddr string           `json:"remote_addr,omitempty"`
+	UserAgent  string           `json:"user_agent,omitempty"`
+	Status     int              `json:"status"`
+	Decision   string           `json:"decision"`
+	Error      string           `json:"error,omitempty"`
+	LatencyMS  float64          `json:"latency_ms"`
+}
+
+// AuditSink is the destination audit entries are written to.
+type AuditSink interface {
+	WriteEntry(AuditEntry) error
+}
+
+// AuditMiddleware records every API request to an AuditSink.
+type AuditMiddleware struct {
+	sink AuditSink
+}
+
+// NewAuditMiddleware creates a new AuditMiddleware writing to the given sink.
+func NewAuditMiddleware(sink AuditSink) AuditMiddleware {
+	return AuditMiddleware{sink: sink}
+}
+
+// WrapHandler returns a new handler function wrapping the previous one in the request chain.
+func (a AuditMiddleware) WrapHandler(handler func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error) func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w}
+
+		err := handler(ctx, rec, r, vars)
+
+		entry := newAuditEntry(ctx, r, vars)
+		entry.Time = start.UTC()
+		entry.LatencyMS = float64(time.Since(start).Microseconds()) / 1000
+		entry.Status, entry.Decision = auditDecision(rec, err)
+		if err != nil {
+			entry.Error = err.Error()
+		}
+		if werr := a.sink.WriteEntry(entry); werr != nil {
+			logrus.WithError(werr).Warn("failed to write audit log entry")
+		}
+		return err
+	}
+}
+
+func newAuditEntry(ctx context.Context, r *http.Request, vars map[string]string) AuditEntry {
+	entry := AuditEntry{
+		Method:     r.Method,
+		Path:       r.URL.Path,
+		APIVersion: vars["version"],
+		Target:     auditTarget(vars),
+		RemoteAddr: r.RemoteAddr,
+		UserAgent:  r.Header.Get("User-Agent"),
+	}
+	if route := mux.CurrentRoute(r); route != nil {
+		if tmpl, err := route.GetPathTemplate(); err == nil {
+			entry.Endpoint = strings.TrimPrefix(tmpl, "/v{version:[0-9.]+}")
+		}
+	}
+	entry.User, entry.AuthMethod, entry.Peer = ClientIdentity(ctx, r)
+	return entry
+}
+
+// ClientIdentity returns the identity of the client of an API request: the
+// common name of its TLS client certificate, or the credentials of the
+// client process if it connected over a unix socket, along with the method
+// the identity was obtained with.
+func ClientIdentity(ctx context.Context, r *http.Request) (user, authMethod string, peer *PeerCredentials) {
+	if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+		return r.TLS.PeerCertificates[0].Subject.CommonName, "TLS", nil
+	}
+	if conn := httputils.ConnFromContext(ctx); conn != nil {
+		if cred, ok := peerCredentials(conn); ok {
+			return "", "peercred", cred
+		}
+	}
+	return "", "", nil
+}
+
+// auditTarget returns the object an API request operates on, based on the
+// route variables of the request.
+func auditTarget(vars map[string]string) string {
+	for _, k := range []string{"name", "id", "execid", "plugin"} {
+		if v := vars[k]; v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func auditDecision(rec *statusRecorder, err error) (int, string) {
+	if err == nil {
+		status := rec.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		return status, AuditDecisionAllow
+	}
+	status := httpstatus.FromError(err)
+	if status == http.StatusUnauthorized || status == http.StatusForbidden {
+		return status, AuditDecisionDeny
+	}
+	return status, AuditDecisionError
+}
+
+// statusRecorder is a http.ResponseWriter that records the status code of the
+// response, while still allowing the connection to be hijacked and flushed.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(status int) {
+	if s.status == 0 {
+		s.status = status
+	}
+	s.ResponseWriter.WriteHeader(status)
+}
+
+func (s *statusRecorder) Write(b []byte) (int, error) {
+	if s.status == 0 {
+		s.status = http.StatusOK
+	}
+	return s.ResponseWriter.Write(b)
+}
+
+// Hijack returns the underlying connection of the wrapped http.ResponseWriter.
+func (s *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := s.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("internal response writer doesn't support the Hijacker interface")
+	}
+	if s.status == 0 {
+		s.status = http.StatusSwitchingProtocols
+	}
+	return hijacker.Hijack()
+}
+
+// Flush flushes the buffered data of the wrapped http.ResponseWriter.
+func (s *statusRecorder) Flush() {
+	if flusher, ok := s.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,98 @@
+package middleware // import "github.com/docker/docker/api/server/middleware"
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+type recordingSink struct {
+	entries []AuditEntry
+}
+
+func (s *recordingSink) WriteEntry(e AuditEntry) error {
+	s.entries = append(s.entries, e)
+	return nil
+}
+
+func TestAuditMiddleware(t *testing.T) {
+	tests := []struct {
+		doc              string
+		handler          func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error
+		expectedStatus   int
+		expectedDecision string
+		expectedError    string
+	}{
+		{
+			doc: "allowed",
+			handler: func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+				w.WriteHeader(http.StatusNoContent)
+				return nil
+			},
+			expectedStatus:   http.StatusNoContent,
+			expectedDecision: AuditDecisionAllow,
+		},
+		{
+			doc: "implicit status",
+			handler: func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+				return nil
+			},
+			expectedStatus:   http.StatusOK,
+			expectedDecision: AuditDecisionAllow,
+		},
+		{
+			doc: "denied",
+			handler: func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+				return errdefs.Forbidden(errors.New("denied by policy"))
+			},
+			expectedStatus:   http.StatusForbidden,
+			expectedDecision: AuditDecisionDeny,
+			expectedError:    "denied by policy",
+		},
+		{
+			doc: "error",
+			handler: func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+				return errdefs.NotFound(errors.New("no such container"))
+			},
+			expectedStatus:   http.StatusNotFound,
+			expectedDecision: AuditDecisionError,
+			expectedError:    "no such container",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.doc, func(t *testing.T) {
+			sink := &recordingSink{}
+			h := NewAuditMiddleware(sink).WrapHandler(tc.handler)
+
+			req := httptest.NewRequest(http.MethodPost, "/v1.43/containers/foo/start", nil)
+			req.Header.Set("User-Agent", "test-agent")
+			vars := map[string]string{"version": "1.43", "name": "foo"}
+			err := h(context.Background(), httptest.NewRecorder(), req, vars)
+			if tc.expectedError != "" {
+				assert.Check(t, is.Error(err, tc.expectedError))
+			} else {
+				assert.NilError(t, err)
+			}
+
+			assert.Assert(t, is.Len(sink.entries, 1))
+			entry := sink.entries[0]
+			assert.Check(t, is.Equal(entry.Method, http.MethodPost))
+			assert.Check(t, is.Equal(entry.Path, "/v1.43/containers/foo/start"))
+			assert.Check(t, is.Equal(entry.APIVersion, "1.43"))
+			assert.Check(t, is.Equal(entry.Target, "foo"))
+			assert.Check(t, is.Equal(entry.UserAgent, "test-agent"))
+			assert.Check(t, is.Equal(entry.Status, tc.expectedStatus))
+			assert.Check(t, is.Equal(entry.Decision, tc.expectedDecision))
+			assert.Check(t, is.Equal(entry.Error, tc.expectedError))
+			assert.Check(t, !entry.Time.IsZero())
+		})
+	}
+}
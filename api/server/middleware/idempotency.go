@@ -0,0 +1,223 @@
+package middleware // import "github.com/docker/docker/api/server/middleware"
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net"
+	"net/http"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/server/httputils"
+	"github.com/docker/docker/api/types/versions"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+)
+
+const (
+	// IdempotencyKeyHeader is the request header holding the idempotency key
+	// of a create request.
+	IdempotencyKeyHeader = "Idempotency-Key"
+
+	// IdempotentReplayHeader is set on responses that are replayed from an
+	// earlier request with the same idempotency key.
+	IdempotentReplayHeader = "Docker-Idempotent-Replay"
+
+	// DefaultIdempotencyKeyTTL is how long the result of a request is
+	// remembered for its idempotency key.
+	DefaultIdempotencyKeyTTL = 24 * time.Hour
+)
+
+// idempotentEndpoints are the endpoints that accept an idempotency key.
+var idempotentEndpoints = map[string]bool{
+	"/containers/create": true,
+	"/networks/create":   true,
+	"/volumes/create":    true,
+	"/services/create":   true,
+}
+
+var versionPrefix = regexp.MustCompile(`^/v[0-9.]+`)
+
+// idempotencyEntry holds the outcome of the request that first used an
+// idempotency key. done is closed once the request has completed; ok is set
+// if it succeeded and its response was recorded.
+type idempotencyEntry struct {
+	done    chan struct{}
+	digest  string
+	ok      bool
+	expires time.Time
+	status  int
+	header  http.Header
+	body    []byte
+}
+
+// IdempotencyMiddleware remembers the responses of successful create requests
+// carrying an Idempotency-Key header, and replays them when a request with the
+// same key is retried, instead of creating the object again.
+type IdempotencyMiddleware struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*idempotencyEntry
+}
+
+// NewIdempotencyMiddleware creates a new IdempotencyMiddleware, which
+// remembers the response for an idempotency key for the given duration.
+func NewIdempotencyMiddleware(ttl time.Duration) *IdempotencyMiddleware {
+	if ttl <= 0 {
+		ttl = DefaultIdempotencyKeyTTL
+	}
+	return &IdempotencyMiddleware{
+		ttl:     ttl,
+		entries: make(map[string]*idempotencyEntry),
+	}
+}
+
+// WrapHandler returns a new handler function wrapping the previous one in the request chain.
+func (m *IdempotencyMiddleware) WrapHandler(handler func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error) func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if key == "" || r.Method != http.MethodPost || versions.LessThan(httputils.VersionFromContext(ctx), "1.43") {
+			return handler(ctx, w, r, vars)
+		}
+		endpoint := versionPrefix.ReplaceAllString(r.URL.Path, "")
+		if !idempotentEndpoints[endpoint] {
+			return handler(ctx, w, r, vars)
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			return err
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		digest := requestDigest(r.URL.RawQuery, body)
+		scope := endpoint + "\x00" + key
+
+		for {
+			e, owner := m.acquire(scope, digest)
+			if owner {
+				return m.execute(ctx, scope, e, handler, w, r, vars)
+			}
+			if e.digest != digest {
+				return errdefs.InvalidParameter(errors.Errorf("idempotency key %q was already used for a different request", key))
+			}
+			select {
+			case <-e.done:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			if e.ok {
+				replay(w, e)
+				return nil
+			}
+			// The original request failed, and its key was released; try
+			// again on behalf of this request.
+		}
+	}
+}
+
+// acquire returns the entry for the given scope. If there was none, a new
+// entry is created and owner is true, in which case the caller must execute
+// the request and complete the entry.
+func (m *IdempotencyMiddleware) acquire(scope, digest string) (e *idempotencyEntry, owner bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	for k, e := range m.entries {
+		if e.ok && now.After(e.expires) {
+			delete(m.entries, k)
+		}
+	}
+	if e, ok := m.entries[scope]; ok {
+		return e, false
+	}
+	e = &idempotencyEntry{done: make(chan struct{}), digest: digest}
+	m.entries[scope] = e
+	return e, true
+}
+
+func (m *IdempotencyMiddleware) execute(ctx context.Context, scope string, e *idempotencyEntry, handler func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	rec := &responseRecorder{ResponseWriter: w}
+	defer close(e.done)
+
+	err := handler(ctx, rec, r, vars)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err != nil || rec.hijacked || rec.status < 200 || rec.status > 299 {
+		// Only successful results are remembered, so that a failed request
+		// can be retried with the same key.
+		delete(m.entries, scope)
+		return err
+	}
+	e.ok = true
+	e.expires = time.Now().Add(m.ttl)
+	e.status = rec.status
+	e.header = w.Header().Clone()
+	e.body = rec.body.Bytes()
+	return nil
+}
+
+func replay(w http.ResponseWriter, e *idempotencyEntry) {
+	for k, v := range e.header {
+		w.Header()[k] = v
+	}
+	w.Header().Set(IdempotentReplayHeader, "true")
+	w.WriteHeader(e.status)
+	_, _ = w.Write(e.body)
+}
+
+func requestDigest(query string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(query))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// responseRecorder is a http.ResponseWriter that records the status code and
+// body of the response, while passing them on to the wrapped writer.
+type responseRecorder struct {
+	http.ResponseWriter
+	status   int
+	body     bytes.Buffer
+	hijacked bool
+}
+
+func (rr *responseRecorder) WriteHeader(status int) {
+	if rr.status == 0 {
+		rr.status = status
+	}
+	rr.ResponseWriter.WriteHeader(status)
+}
+
+func (rr *responseRecorder) Write(b []byte) (int, error) {
+	if rr.status == 0 {
+		rr.status = http.StatusOK
+	}
+	rr.body.Write(b)
+	return rr.ResponseWriter.Write(b)
+}
+
+// Hijack returns the underlying connection of the wrapped http.ResponseWriter.
+func (rr *responseRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := rr.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("internal response writer doesn't support the Hijacker interface")
+	}
+	rr.hijacked = true
+	return hijacker.Hijack()
+}
+
+// Flush flushes the buffered data of the wrapped http.ResponseWriter.
+func (rr *responseRecorder) Flush() {
+	if flusher, ok := rr.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
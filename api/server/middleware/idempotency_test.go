@@ -0,0 +1,79 @@
+package middleware // import "github.com/docker/docker/api/server/middleware"
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/server/httputils"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	var created int
+	fail := false
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+		if fail {
+			return errdefs.System(errors.New("failed"))
+		}
+		body, _ := io.ReadAll(r.Body)
+		created++
+		return httputils.WriteJSON(w, http.StatusCreated, map[string]string{"Id": fmt.Sprintf("%s-%d", body, created)})
+	}
+	m := NewIdempotencyMiddleware(0)
+	h := m.WrapHandler(handler)
+
+	do := func(path, key, body string) (*httptest.ResponseRecorder, error) {
+		ctx := context.WithValue(context.Background(), httputils.APIVersionKey{}, "1.43")
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		if key != "" {
+			req.Header.Set(IdempotencyKeyHeader, key)
+		}
+		resp := httptest.NewRecorder()
+		return resp, h(ctx, resp, req, map[string]string{})
+	}
+
+	resp, err := do("/v1.43/containers/create", "key1", "a")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(resp.Code, http.StatusCreated))
+	first := resp.Body.String()
+
+	resp, err = do("/v1.43/containers/create", "key1", "a")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(resp.Code, http.StatusCreated))
+	assert.Check(t, is.Equal(resp.Body.String(), first))
+	assert.Check(t, is.Equal(resp.Header().Get(IdempotentReplayHeader), "true"))
+	assert.Check(t, is.Equal(created, 1))
+
+	_, err = do("/v1.43/containers/create", "key1", "b")
+	assert.Check(t, errdefs.IsInvalidParameter(err))
+
+	// Keys are scoped to the endpoint.
+	_, err = do("/volumes/create", "key1", "b")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(created, 2))
+
+	// Requests without a key, or to other endpoints, are not remembered.
+	_, err = do("/containers/create", "", "a")
+	assert.NilError(t, err)
+	_, err = do("/containers/abc/start", "key1", "a")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(created, 4))
+
+	// Failed requests release their key.
+	fail = true
+	_, err = do("/networks/create", "key2", "a")
+	assert.Check(t, errdefs.IsSystem(err))
+	fail = false
+	resp, err = do("/networks/create", "key2", "a")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(resp.Header().Get(IdempotentReplayHeader), ""))
+	assert.Check(t, is.Equal(created, 5))
+}
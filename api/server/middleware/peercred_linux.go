@@ -0,0 +1,31 @@
+package middleware // import "github.com/docker/docker/api/server/middleware"
+
+import (
+	"net"
+
+	"golang.org/x/sys/unix"
+)
+
+// peerCredentials returns the credentials of the process at the other end of
+// a unix socket connection. It returns false if the connection is not a unix
+// socket, or if the credentials could not be obtained.
+func peerCredentials(conn net.Conn) (*PeerCredentials, bool) {
+	uc, ok := conn.(*net.UnixConn)
+	if !ok {
+		return nil, false
+	}
+	raw, err := uc.SyscallConn()
+	if err != nil {
+		return nil, false
+	}
+	var (
+		cred    *unix.Ucred
+		credErr error
+	)
+	if err := raw.Control(func(fd uintptr) {
+		cred, credErr = unix.GetsockoptUcred(int(fd), unix.SOL_SOCKET, unix.SO_PEERCRED)
+	}); err != nil || credErr != nil {
+		return nil, false
+	}
+	return &PeerCredentials{UID: cred.Uid, GID: cred.Gid, PID: cred.Pid}, true
+}
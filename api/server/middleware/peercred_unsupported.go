@@ -0,0 +1,11 @@
+//go:build !linux
+// +build !linux
+
+package middleware // import "github.com/docker/docker/api/server/middleware"
+
+import "net"
+
+// peerCredentials is not supported on this platform.
+func peerCredentials(net.Conn) (*PeerCredentials, bool) {
+	return nil, false
+}
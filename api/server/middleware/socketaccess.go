@@ -0,0 +1,240 @@
+package middleware // import "github.com/docker/docker/api/server/middleware"
+
+import (
+	"context"
+	"net/http"
+	"os/user"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/server/httputils"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// socketAccessReadOnly is the endpoint pattern allowing GET and HEAD requests
+// to the endpoints listed in socketAccessReadOnlyEndpoints.
+const socketAccessReadOnly = "read-only"
+
+// socketAccessReadOnlyEndpoints matches the inspect, list and log endpoints
+// allowed by the "read-only" pattern. GET endpoints giving access to the
+// streams or the filesystem of containers, such as attach and archive, are
+// deliberately not matched. Image names can contain slashes.
+var socketAccessReadOnlyEndpoints = regexp.MustCompile(`^(` + strings.Join([]string{
+	`/_ping`,
+	`/version`,
+	`/info`,
+	`/events(/watch)?`,
+	`/system/df`,
+	`/containers/json`,
+	`/containers/[^/]+/(json|logs|top|stats|changes)`,
+	`/exec/[^/]+/json`,
+	`/images/json`,
+	`/images/.+/(json|history)`,
+	`/volumes(/[^/]+)?`,
+	`/networks(/[^/]+)?`,
+	`/plugins`,
+	`/plugins/.+/json`,
+	`/swarm`,
+	`/(nodes|services|tasks|secrets|configs)(/[^/]+)?`,
+	`/(services|tasks)/[^/]+/logs`,
+}, "|") + `)$`)
+
+// socketIdentityTTL is how long the names and groups of a uid are cached.
+const socketIdentityTTL = time.Minute
+
+// SocketAccessRule lists the endpoints a set of users and groups can call.
+// See the SocketAccessConfig of the daemon configuration for the format of
+// the endpoint patterns.
+type SocketAccessRule struct {
+	Users  []string
+	Groups []string
+	Allow  []string
+}
+
+// SocketAccessPolicy restricts the endpoints local users can call over a unix
+// socket. Rules are matched in order; callers matching no rule are denied if
+// DenyByDefault is set.
+type SocketAccessPolicy struct {
+	Rules         []SocketAccessRule
+	DenyByDefault bool
+}
+
+// socketIdentity holds the names a caller can be matched by.
+type socketIdentity struct {
+	users   map[string]bool
+	groups  map[string]bool
+	expires time.Time
+}
+
+// SocketAccessMiddleware restricts the endpoints that non-root users can call
+// over a unix socket, based on the uid and gid of the calling process.
+type SocketAccessMiddleware struct {
+	mu     sync.RWMutex
+	policy SocketAccessPolicy
+
+	identitiesMu sync.Mutex
+	identities   map[uint32]*socketIdentity
+}
+
+// NewSocketAccessMiddleware creates a new SocketAccessMiddleware enforcing
+// the given policy.
+func NewSocketAccessMiddleware(policy SocketAccessPolicy) *SocketAccessMiddleware {
+	return &SocketAccessMiddleware{
+		policy:     policy,
+		identities: make(map[uint32]*socketIdentity),
+	}
+}
+
+// Enabled returns whether the middleware enforces a policy.
+func (m *SocketAccessMiddleware) Enabled() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.policy.DenyByDefault || len(m.policy.Rules) > 0
+}
+
+// SetPolicy replaces the policy enforced by the middleware.
+func (m *SocketAccessMiddleware) SetPolicy(policy SocketAccessPolicy) {
+	m.mu.Lock()
+	m.policy = policy
+	m.mu.Unlock()
+
+	// Drop cached identities, so that changes to users and groups can be
+	// picked up by reloading the configuration.
+	m.identitiesMu.Lock()
+	m.identities = make(map[uint32]*socketIdentity)
+	m.identitiesMu.Unlock()
+}
+
+// WrapHandler returns a new handler function wrapping the previous one in the request chain.
+func (m *SocketAccessMiddleware) WrapHandler(handler func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error) func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+		conn := httputils.ConnFromContext(ctx)
+		if conn == nil {
+			return handler(ctx, w, r, vars)
+		}
+		cred, ok := peerCredentials(conn)
+		if !ok || cred.UID == 0 {
+			return handler(ctx, w, r, vars)
+		}
+		if !m.allowed(cred, r.Method, versionPrefix.ReplaceAllString(r.URL.Path, "")) {
+			return errdefs.Forbidden(errors.Errorf("socket access denied: uid %d is not allowed to call %s %s", cred.UID, r.Method, r.URL.Path))
+		}
+		return handler(ctx, w, r, vars)
+	}
+}
+
+// allowed returns whether the caller is allowed to call the endpoint.
+func (m *SocketAccessMiddleware) allowed(cred *PeerCredentials, method, p string) bool {
+	m.mu.RLock()
+	policy := m.policy
+	m.mu.RUnlock()
+	if len(policy.Rules) == 0 {
+		return !policy.DenyByDefault
+	}
+
+	id := m.identity(cred)
+	for _, rule := range policy.Rules {
+		if !id.matches(rule) {
+			continue
+		}
+		for _, pattern := range rule.Allow {
+			if matchEndpoint(pattern, method, p) {
+				return true
+			}
+		}
+		return false
+	}
+	return !policy.DenyByDefault
+}
+
+// identity returns the user and group names and ids of the caller. Lookups
+// are cached, as they may involve NSS, which can be slow.
+func (m *SocketAccessMiddleware) identity(cred *PeerCredentials) *socketIdentity {
+	m.identitiesMu.Lock()
+	defer m.identitiesMu.Unlock()
+	// Processes of the same user can run with different primary groups, so
+	// the cached identity is only used if it includes the caller's group.
+	if id, ok := m.identities[cred.UID]; ok && time.Now().Before(id.expires) && id.groups[strconv.FormatUint(uint64(cred.GID), 10)] {
+		return id
+	}
+	id := lookupSocketIdentity(cred)
+	m.identities[cred.UID] = id
+	return id
+}
+
+func lookupSocketIdentity(cred *PeerCredentials) *socketIdentity {
+	uid := strconv.FormatUint(uint64(cred.UID), 10)
+	gid := strconv.FormatUint(uint64(cred.GID), 10)
+	id := &socketIdentity{
+		users:   map[string]bool{uid: true},
+		groups:  map[string]bool{gid: true},
+		expires: time.Now().Add(socketIdentityTTL),
+	}
+	gids := []string{gid}
+	if u, err := user.LookupId(uid); err == nil {
+		id.users[u.Username] = true
+		if ids, err := u.GroupIds(); err == nil {
+			gids = append(gids, ids...)
+		} else {
+			logrus.WithError(err).WithField("uid", uid).Debug("failed to look up supplementary groups of socket peer")
+		}
+	}
+	for _, g := range gids {
+		id.groups[g] = true
+		if grp, err := user.LookupGroupId(g); err == nil {
+			id.groups[grp.Name] = true
+		}
+	}
+	return id
+}
+
+func (id *socketIdentity) matches(rule SocketAccessRule) bool {
+	for _, u := range rule.Users {
+		if id.users[u] {
+			return true
+		}
+	}
+	for _, g := range rule.Groups {
+		if id.groups[g] {
+			return true
+		}
+	}
+	return false
+}
+
+// matchEndpoint returns whether a request matches an endpoint pattern.
+func matchEndpoint(pattern, method, p string) bool {
+	if pattern == socketAccessReadOnly {
+		if method != http.MethodGet && method != http.MethodHead {
+			return false
+		}
+		return socketAccessReadOnlyEndpoints.MatchString(p)
+	}
+	pm, pp, ok := strings.Cut(pattern, " ")
+	if !ok || (pm != "*" && pm != method) {
+		return false
+	}
+	if pp == "*" {
+		return true
+	}
+	if prefix := strings.TrimSuffix(pp, "/**"); prefix != pp {
+		if p == prefix || strings.HasPrefix(p, prefix+"/") {
+			return true
+		}
+		// Allow wildcards in the prefix, for example "/containers/*/**".
+		for ; p != "/" && p != "."; p = path.Dir(p) {
+			if ok, _ := path.Match(prefix, p); ok {
+				return true
+			}
+		}
+		return false
+	}
+	ok, _ = path.Match(pp, p)
+	return ok
+}
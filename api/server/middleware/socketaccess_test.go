@@ -0,0 +1,65 @@
+package middleware // import "github.com/docker/docker/api/server/middleware"
+
+import (
+	"net/http"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestMatchEndpoint(t *testing.T) {
+	tests := []struct {
+		pattern, method, path string
+		expected              bool
+	}{
+		{pattern: "read-only", method: http.MethodGet, path: "/containers/json", expected: true},
+		{pattern: "read-only", method: http.MethodHead, path: "/_ping", expected: true},
+		{pattern: "read-only", method: http.MethodPost, path: "/containers/create"},
+		{pattern: "read-only", method: http.MethodGet, path: "/containers/web/logs", expected: true},
+		{pattern: "read-only", method: http.MethodGet, path: "/images/library/busybox/json", expected: true},
+		{pattern: "read-only", method: http.MethodGet, path: "/volumes/data", expected: true},
+		{pattern: "read-only", method: http.MethodGet, path: "/containers/web/attach/ws"},
+		{pattern: "read-only", method: http.MethodGet, path: "/containers/web/archive"},
+		{pattern: "read-only", method: http.MethodGet, path: "/containers/web/export"},
+		{pattern: "read-only", method: http.MethodGet, path: "/images/get"},
+		{pattern: "GET /containers/json", method: http.MethodGet, path: "/containers/json", expected: true},
+		{pattern: "GET /containers/json", method: http.MethodPost, path: "/containers/json"},
+		{pattern: "* /containers/*/start", method: http.MethodPost, path: "/containers/web/start", expected: true},
+		{pattern: "* /containers/*/start", method: http.MethodPost, path: "/containers/web/stop"},
+		{pattern: "POST /exec/**", method: http.MethodPost, path: "/exec/abc/start", expected: true},
+		{pattern: "POST /exec/**", method: http.MethodPost, path: "/exec", expected: true},
+		{pattern: "POST /exec/**", method: http.MethodPost, path: "/execs"},
+		{pattern: "GET /containers/*/**", method: http.MethodGet, path: "/containers/web/logs", expected: true},
+		{pattern: "GET /containers/*/**", method: http.MethodGet, path: "/images/json"},
+		{pattern: "DELETE *", method: http.MethodDelete, path: "/volumes/data", expected: true},
+	}
+	for _, tc := range tests {
+		assert.Check(t, matchEndpoint(tc.pattern, tc.method, tc.path) == tc.expected, "%s %s %s", tc.pattern, tc.method, tc.path)
+	}
+}
+
+func TestSocketAccessPolicy(t *testing.T) {
+	// Use a uid and gid that are unlikely to exist, so that the caller can
+	// only be matched by its numeric ids.
+	observer := &PeerCredentials{UID: 54321, GID: 54321}
+	other := &PeerCredentials{UID: 54322, GID: 54322}
+
+	m := NewSocketAccessMiddleware(SocketAccessPolicy{
+		Rules: []SocketAccessRule{
+			{Groups: []string{"54321"}, Allow: []string{"read-only", "POST /containers/*/start"}},
+		},
+	})
+	assert.Check(t, m.allowed(observer, http.MethodGet, "/containers/json"))
+	assert.Check(t, m.allowed(observer, http.MethodPost, "/containers/web/start"))
+	assert.Check(t, !m.allowed(observer, http.MethodPost, "/containers/create"))
+	assert.Check(t, m.allowed(other, http.MethodPost, "/containers/create"))
+
+	m.SetPolicy(SocketAccessPolicy{
+		Rules: []SocketAccessRule{
+			{Users: []string{"54322"}, Allow: []string{"* *"}},
+		},
+		DenyByDefault: true,
+	})
+	assert.Check(t, !m.allowed(observer, http.MethodGet, "/containers/json"))
+	assert.Check(t, m.allowed(other, http.MethodPost, "/containers/create"))
+}
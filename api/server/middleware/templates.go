@@ -0,0 +1,131 @@
+package middleware // import "github.com/docker/docker/api/server/middleware"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/versions"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+)
+
+// TemplateGetter returns the container create templates stored in the daemon.
+type TemplateGetter interface {
+	ContainerTemplateInspect(name string) (container.Template, error)
+}
+
+// ContainerTemplateMiddleware applies the body of container create requests
+// naming a template in their template parameter to the spec of the template.
+// It must wrap the authorization middlewares, so that they see the config
+// the container is created with, and not only the overrides of the request.
+type ContainerTemplateMiddleware struct {
+	mu        sync.RWMutex
+	templates TemplateGetter
+}
+
+// NewContainerTemplateMiddleware creates a new ContainerTemplateMiddleware.
+// Requests naming a template are rejected until SetTemplates is called.
+func NewContainerTemplateMiddleware() *ContainerTemplateMiddleware {
+	return &ContainerTemplateMiddleware{}
+}
+
+// SetTemplates sets the store the templates are looked up in.
+func (m *ContainerTemplateMiddleware) SetTemplates(templates TemplateGetter) {
+	m.mu.Lock()
+	m.templates = templates
+	m.mu.Unlock()
+}
+
+// WrapHandler returns a new handler function wrapping the previous one in the request chain.
+func (m *ContainerTemplateMiddleware) WrapHandler(handler func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error) func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+		if r.Method != http.MethodPost || versionPrefix.ReplaceAllString(r.URL.Path, "") != "/containers/create" {
+			return handler(ctx, w, r, vars)
+		}
+		// Templates were added in API 1.43. Requests without a version use
+		// the default version, which supports them.
+		if v := vars["version"]; v != "" && versions.LessThan(v, "1.43") {
+			return handler(ctx, w, r, vars)
+		}
+		q := r.URL.Query()
+		name := q.Get("template")
+		if name == "" {
+			return handler(ctx, w, r, vars)
+		}
+
+		m.mu.RLock()
+		templates := m.templates
+		m.mu.RUnlock()
+		if templates == nil {
+			return errdefs.Unavailable(errors.New("container templates are not available yet"))
+		}
+		body, err := applyTemplate(templates, name, r.Body)
+		if err != nil {
+			return err
+		}
+
+		q.Del("template")
+		r.URL.RawQuery = q.Encode()
+		r.RequestURI = r.URL.RequestURI()
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		r.ContentLength = int64(len(body))
+		r.Header.Set("Content-Type", "application/json")
+		return handler(ctx, w, r, vars)
+	}
+}
+
+// applyTemplate returns the body of a container create request, with the
+// given overrides applied to the spec of the named template as a JSON merge
+// patch.
+func applyTemplate(templates TemplateGetter, name string, overrides io.Reader) ([]byte, error) {
+	t, err := templates.ContainerTemplateInspect(name)
+	if err != nil {
+		return nil, err
+	}
+	var spec interface{}
+	dec := json.NewDecoder(bytes.NewReader(t.Spec))
+	dec.UseNumber()
+	if err := dec.Decode(&spec); err != nil {
+		return nil, errdefs.InvalidParameter(errors.Wrapf(err, "invalid spec in template %s", name))
+	}
+
+	var patch interface{} = map[string]interface{}{}
+	dec = json.NewDecoder(overrides)
+	dec.UseNumber()
+	if err := dec.Decode(&patch); err != nil && err != io.EOF {
+		return nil, errdefs.InvalidParameter(err)
+	}
+	if patch == nil {
+		// A null body keeps the template as-is.
+		patch = map[string]interface{}{}
+	}
+
+	return json.Marshal(mergePatch(spec, patch))
+}
+
+// mergePatch applies a JSON merge patch, as defined in RFC 7386, to the
+// target: objects are merged recursively, nulls remove fields, and other
+// values replace the target.
+func mergePatch(target, patch interface{}) interface{} {
+	p, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]interface{})
+	if !ok {
+		t = make(map[string]interface{}, len(p))
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+		} else {
+			t[k] = mergePatch(t[k], v)
+		}
+	}
+	return t
+}
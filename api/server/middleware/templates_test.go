@@ -0,0 +1,108 @@
+package middleware // import "github.com/docker/docker/api/server/middleware"
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+type fakeTemplates map[string]container.Template
+
+func (f fakeTemplates) ContainerTemplateInspect(name string) (container.Template, error) {
+	t, ok := f[name]
+	if !ok {
+		return t, errdefs.NotFound(errors.Errorf("no such template: %s", name))
+	}
+	return t, nil
+}
+
+var testTemplates = fakeTemplates{
+	"runner": {Name: "runner", Spec: json.RawMessage(`{
+		"Image": "runner:1",
+		"Env": ["A=1"],
+		"Labels": {"role": "runner", "tier": "ci"},
+		"HostConfig": {"Memory": 9007199254740993, "Privileged": true}
+	}`)},
+	"broken": {Name: "broken", Spec: json.RawMessage(`{`)},
+}
+
+func TestApplyTemplate(t *testing.T) {
+	for _, tc := range []struct {
+		doc       string
+		overrides string
+		expected  string
+	}{
+		{
+			doc:      "no overrides",
+			expected: `{"Env":["A=1"],"HostConfig":{"Memory":9007199254740993,"Privileged":true},"Image":"runner:1","Labels":{"role":"runner","tier":"ci"}}`,
+		},
+		{
+			doc:       "overrides",
+			overrides: `{"Image": "runner:2", "Env": ["B=2"], "Labels": {"tier": null, "id": "7"}, "HostConfig": {"Privileged": false}}`,
+			expected:  `{"Env":["B=2"],"HostConfig":{"Memory":9007199254740993,"Privileged":false},"Image":"runner:2","Labels":{"id":"7","role":"runner"}}`,
+		},
+		{
+			doc:       "remove object",
+			overrides: `{"HostConfig": null}`,
+			expected:  `{"Env":["A=1"],"Image":"runner:1","Labels":{"role":"runner","tier":"ci"}}`,
+		},
+	} {
+		t.Run(tc.doc, func(t *testing.T) {
+			b, err := applyTemplate(testTemplates, "runner", strings.NewReader(tc.overrides))
+			assert.NilError(t, err)
+			assert.Check(t, is.Equal(string(b), tc.expected))
+		})
+	}
+
+	_, err := applyTemplate(testTemplates, "missing", strings.NewReader(`{}`))
+	assert.Check(t, errdefs.IsNotFound(err))
+	_, err = applyTemplate(testTemplates, "runner", strings.NewReader(`{`))
+	assert.Check(t, errdefs.IsInvalidParameter(err))
+	_, err = applyTemplate(testTemplates, "broken", strings.NewReader(`{}`))
+	assert.Check(t, errdefs.IsInvalidParameter(err))
+}
+
+func TestContainerTemplateMiddleware(t *testing.T) {
+	m := NewContainerTemplateMiddleware()
+	var (
+		gotURI  string
+		gotBody string
+	)
+	h := m.WrapHandler(func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+		gotURI = r.RequestURI
+		b, err := io.ReadAll(r.Body)
+		gotBody = string(b)
+		return err
+	})
+	newRequest := func(target string) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, target, strings.NewReader(`{"Image": "runner:2"}`))
+		r.Header.Set("Content-Type", "application/json")
+		return r
+	}
+
+	err := h(context.Background(), httptest.NewRecorder(), newRequest("/v1.43/containers/create?template=runner"), map[string]string{"version": "1.43"})
+	assert.Check(t, errdefs.IsUnavailable(err), err)
+
+	m.SetTemplates(testTemplates)
+	err = h(context.Background(), httptest.NewRecorder(), newRequest("/v1.43/containers/create?name=web&template=runner"), map[string]string{"version": "1.43"})
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(gotURI, "/v1.43/containers/create?name=web"))
+	assert.Check(t, is.Contains(gotBody, `"Privileged":true`))
+	assert.Check(t, is.Contains(gotBody, `"Image":"runner:2"`))
+
+	// Templates are ignored by older API versions.
+	err = h(context.Background(), httptest.NewRecorder(), newRequest("/v1.42/containers/create?template=runner"), map[string]string{"version": "1.42"})
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(gotURI, "/v1.42/containers/create?template=runner"))
+	assert.Check(t, is.Equal(gotBody, `{"Image": "runner:2"}`))
+}
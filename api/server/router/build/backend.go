@@ -17,6 +17,21 @@ type Backend interface {
 	PruneCache(context.Context, types.BuildCachePruneOptions) (*types.BuildCachePruneReport, error)
 
 	Cancel(context.Context, string) error
+
+	// BuildRecords returns the records of the builds of the daemon
+	BuildRecords(context.Context) ([]types.BuildRecord, error)
+
+	// BuildRecord returns the record of a build, with its provenance
+	BuildRecord(context.Context, string) (types.BuildRecord, error)
+
+	// BuildFrontends returns the frontends registered with the builder
+	BuildFrontends(context.Context) ([]types.BuildFrontend, error)
+
+	// RegisterBuildFrontend registers a frontend image with the builder
+	RegisterBuildFrontend(context.Context, types.BuildFrontend) (types.BuildFrontend, error)
+
+	// RemoveBuildFrontend removes a registered frontend by name
+	RemoveBuildFrontend(context.Context, string) error
 }
 
 type experimentalProvider interface {
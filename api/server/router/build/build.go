@@ -36,6 +36,11 @@ func (r *buildRouter) initRoutes() {
 		router.NewPostRoute("/build", r.postBuild),
 		router.NewPostRoute("/build/prune", r.postPrune),
 		router.NewPostRoute("/build/cancel", r.postCancel),
+		router.NewGetRoute("/build/records", r.getBuildRecords),
+		router.NewGetRoute("/build/records/{id}", r.getBuildRecord),
+		router.NewGetRoute("/build/frontends", r.getBuildFrontends),
+		router.NewPostRoute("/build/frontends", r.postBuildFrontends),
+		router.NewDeleteRoute("/build/frontends/{name:.*}", r.deleteBuildFrontend),
 	}
 }
 
@@ -90,6 +90,31 @@ func newImageBuildOptions(ctx context.Context, r *http.Request) (*types.ImageBui
 			options.Outputs = outputs
 		}
 	}
+	if versions.GreaterThanOrEqualTo(version, "1.43") {
+		if cacheImportsJSON := r.FormValue("cacheimports"); cacheImportsJSON != "" {
+			var cacheImports []types.ImageBuildCache
+			if err := json.Unmarshal([]byte(cacheImportsJSON), &cacheImports); err != nil {
+				return nil, invalidParam{errors.Wrap(err, "invalid cache imports specified")}
+			}
+			options.CacheImports = cacheImports
+		}
+		if cacheExportsJSON := r.FormValue("cacheexports"); cacheExportsJSON != "" {
+			var cacheExports []types.ImageBuildCache
+			if err := json.Unmarshal([]byte(cacheExportsJSON), &cacheExports); err != nil {
+				return nil, invalidParam{errors.Wrap(err, "invalid cache exports specified")}
+			}
+			options.CacheExports = cacheExports
+		}
+		if secretsJSON := r.FormValue("secrets"); secretsJSON != "" {
+			var secrets []types.ImageBuildSecret
+			if err := json.Unmarshal([]byte(secretsJSON), &secrets); err != nil {
+				return nil, invalidParam{errors.Wrap(err, "invalid secrets specified")}
+			}
+			options.Secrets = secrets
+		}
+		options.PidsLimit = httputils.Int64ValueOrZero(r, "pidslimit")
+		options.ScratchLimit = httputils.Int64ValueOrZero(r, "scratchlimit")
+	}
 
 	if s := r.Form.Get("shmsize"); s != "" {
 		shmSize, err := strconv.ParseInt(s, 10, 64)
@@ -213,6 +238,50 @@ func (br *buildRouter) postCancel(ctx context.Context, w http.ResponseWriter, r
 	return br.backend.Cancel(ctx, id)
 }
 
+func (br *buildRouter) getBuildRecords(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	records, err := br.backend.BuildRecords(ctx)
+	if err != nil {
+		return err
+	}
+	return httputils.WriteJSON(w, http.StatusOK, records)
+}
+
+func (br *buildRouter) getBuildRecord(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	record, err := br.backend.BuildRecord(ctx, vars["id"])
+	if err != nil {
+		return err
+	}
+	return httputils.WriteJSON(w, http.StatusOK, record)
+}
+
+func (br *buildRouter) getBuildFrontends(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	frontends, err := br.backend.BuildFrontends(ctx)
+	if err != nil {
+		return err
+	}
+	return httputils.WriteJSON(w, http.StatusOK, frontends)
+}
+
+func (br *buildRouter) postBuildFrontends(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	var f types.BuildFrontend
+	if err := httputils.ReadJSON(r, &f); err != nil {
+		return err
+	}
+	frontend, err := br.backend.RegisterBuildFrontend(ctx, f)
+	if err != nil {
+		return err
+	}
+	return httputils.WriteJSON(w, http.StatusCreated, frontend)
+}
+
+func (br *buildRouter) deleteBuildFrontend(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := br.backend.RemoveBuildFrontend(ctx, vars["name"]); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
 func (br *buildRouter) postBuild(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
 	var (
 		notVerboseBuffer = bytes.NewBuffer(nil)
@@ -236,9 +305,23 @@ func (br *buildRouter) postBuild(ctx context.Context, w http.ResponseWriter, r *
 	output := ioutils.NewWriteFlusher(ww)
 	defer func() { _ = output.Close() }()
 
+	stream := io.Writer(output)
+	if httputils.AcceptsProgressV2(ctx, r) {
+		w.Header().Set("Content-Type", types.MediaTypeProgressV2)
+		pw := streamformatter.NewProgressV2Writer(output)
+		defer func() {
+			// No summary is written if an error is returned instead of the
+			// stream.
+			if output.Flushed() {
+				_ = pw.Close()
+			}
+		}()
+		stream = pw
+	}
+
 	errf := func(err error) error {
 		if httputils.BoolValue(r, "q") && notVerboseBuffer.Len() > 0 {
-			_, _ = output.Write(notVerboseBuffer.Bytes())
+			_, _ = stream.Write(notVerboseBuffer.Bytes())
 		}
 
 		// Do not write the error in the http output if it's still empty.
@@ -246,7 +329,7 @@ func (br *buildRouter) postBuild(ctx context.Context, w http.ResponseWriter, r *
 		if !output.Flushed() {
 			return err
 		}
-		_, err = output.Write(streamformatter.FormatError(err))
+		_, err = stream.Write(streamformatter.FormatError(err))
 		if err != nil {
 			logrus.Warnf("could not write error response: %v", err)
 		}
@@ -263,7 +346,7 @@ func (br *buildRouter) postBuild(ctx context.Context, w http.ResponseWriter, r *
 		return invalidParam{errors.New("squash is only supported with experimental mode")}
 	}
 
-	out := io.Writer(output)
+	out := stream
 	if buildOptions.SuppressOutput {
 		out = notVerboseBuffer
 	}
@@ -289,7 +372,7 @@ func (br *buildRouter) postBuild(ctx context.Context, w http.ResponseWriter, r *
 	// Everything worked so if -q was provided the output from the daemon
 	// should be just the image ID and we'll print that to stdout.
 	if buildOptions.SuppressOutput {
-		_, _ = fmt.Fprintln(streamformatter.NewStdoutWriter(output), imgID)
+		_, _ = fmt.Fprintln(streamformatter.NewStdoutWriter(stream), imgID)
 	}
 	return nil
 }
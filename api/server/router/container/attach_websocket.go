@@ -0,0 +1,281 @@
+package container // import "github.com/docker/docker/api/server/router/container"
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/server/httputils"
+	"github.com/docker/docker/api/types/backend"
+	"github.com/docker/docker/api/types/container"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/websocket"
+)
+
+// wsAttachPingInterval is how often the daemon pings clients of version 2 of
+// the websocket attach protocol.
+var wsAttachPingInterval = 30 * time.Second
+
+// wsAttachStdinBuffer is the number of stdin messages that are buffered while
+// the container does not read its stdin, before the messages of the client
+// stop being read.
+const wsAttachStdinBuffer = 32
+
+// wantsWSAttachV2 returns whether the client asked for version 2 of the
+// websocket attach protocol.
+func wantsWSAttachV2(r *http.Request) bool {
+	for _, h := range r.Header.Values("Sec-WebSocket-Protocol") {
+		for _, p := range strings.Split(h, ",") {
+			if strings.TrimSpace(p) == container.AttachWebSocketProtocolV2 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// wsContainersAttachV2 attaches to a container using version 2 of the
+// websocket attach protocol, which frames the streams of the container, and
+// carries resize, close, and keepalive messages in-band.
+func (s *containerRouter) wsContainersAttachV2(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	containerName := vars["name"]
+
+	var (
+		done         = make(chan struct{})
+		started      = make(chan struct{})
+		session      *wsAttachSession
+		attachConfig *backend.ContainerAttachConfig
+	)
+	setupStreams := func(multiplexed bool) (io.ReadCloser, io.Writer, io.Writer, error) {
+		wsChan := make(chan *websocket.Conn)
+		served := make(chan struct{})
+		srv := websocket.Server{
+			Handshake: func(config *websocket.Config, _ *http.Request) error {
+				config.Protocol = []string{container.AttachWebSocketProtocolV2}
+				return nil
+			},
+			Handler: func(conn *websocket.Conn) {
+				wsChan <- conn
+				<-done
+			},
+		}
+		go func() {
+			close(started)
+			srv.ServeHTTP(w, r)
+			close(served)
+		}()
+
+		select {
+		case conn := <-wsChan:
+			conn.PayloadType = websocket.BinaryFrame
+			session = newWSAttachSession(conn, attachConfig.UseStdin, func(height, width int) error {
+				return s.backend.ContainerResize(containerName, height, width)
+			})
+			go session.stdinLoop()
+			go session.readLoop(wsAttachPingInterval)
+			go session.pingLoop(wsAttachPingInterval)
+			return session.stdin, session.writer(container.AttachMessageStdout), session.writer(container.AttachMessageStderr), nil
+		case <-served:
+			return nil, nil, nil, errors.New("websocket handshake failed")
+		}
+	}
+
+	attachConfig = &backend.ContainerAttachConfig{
+		GetStreams: setupStreams,
+		UseStdin:   httputils.BoolValue(r, "stdin"),
+		UseStdout:  httputils.BoolValue(r, "stdout"),
+		UseStderr:  httputils.BoolValue(r, "stderr"),
+		Logs:       httputils.BoolValue(r, "logs"),
+		Stream:     httputils.BoolValue(r, "stream"),
+		DetachKeys: r.FormValue("detachKeys"),
+		MuxStreams: false,
+	}
+
+	err := s.backend.ContainerAttach(containerName, attachConfig)
+	if session != nil {
+		session.close(err)
+	}
+	close(done)
+	select {
+	case <-started:
+		if err != nil {
+			logrus.Errorf("Error attaching websocket: %s", err)
+		} else {
+			logrus.Debug("websocket connection was closed by client")
+		}
+		return nil
+	default:
+	}
+	return err
+}
+
+// wsAttachSession holds the state of a connection using version 2 of the
+// websocket attach protocol.
+type wsAttachSession struct {
+	conn     *websocket.Conn
+	useStdin bool
+	resize   func(height, width int) error
+
+	stdin  *io.PipeReader
+	stdinW *io.PipeWriter
+	// stdinCh queues the stdin messages for stdinLoop, so that a container
+	// not reading its stdin doesn't keep the other messages from being
+	// handled. It is closed by readLoop, after setting stdinErr.
+	stdinCh  chan []byte
+	stdinErr error
+
+	mu     sync.Mutex // serializes writes to conn
+	closed chan struct{}
+	once   sync.Once
+}
+
+func newWSAttachSession(conn *websocket.Conn, useStdin bool, resize func(height, width int) error) *wsAttachSession {
+	stdin, stdinW := io.Pipe()
+	return &wsAttachSession{
+		conn:     conn,
+		useStdin: useStdin,
+		resize:   resize,
+		stdin:    stdin,
+		stdinW:   stdinW,
+		stdinCh:  make(chan []byte, wsAttachStdinBuffer),
+		closed:   make(chan struct{}),
+	}
+}
+
+func (s *wsAttachSession) send(typ container.AttachMessageType, payload []byte) error {
+	msg := make([]byte, 1+len(payload))
+	msg[0] = byte(typ)
+	copy(msg[1:], payload)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return websocket.Message.Send(s.conn, msg)
+}
+
+// writer returns a writer sending data as messages of the given type.
+func (s *wsAttachSession) writer(typ container.AttachMessageType) io.Writer {
+	return wsAttachWriter{session: s, typ: typ}
+}
+
+type wsAttachWriter struct {
+	session *wsAttachSession
+	typ     container.AttachMessageType
+}
+
+func (w wsAttachWriter) Write(p []byte) (int, error) {
+	if err := w.session.send(w.typ, p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// readLoop handles the messages sent by the client, until the connection is
+// closed, or the client has not sent anything for twice the ping interval.
+func (s *wsAttachSession) readLoop(pingInterval time.Duration) {
+	stdinClosed := false
+	closeStdin := func(err error) {
+		if !stdinClosed {
+			stdinClosed = true
+			s.stdinErr = err
+			close(s.stdinCh)
+		}
+	}
+	defer func() {
+		// The client is gone; closing the connection makes writes of
+		// the container's output fail, which ends the attach.
+		s.conn.Close()
+	}()
+	for {
+		if err := s.conn.SetReadDeadline(time.Now().Add(2 * pingInterval)); err != nil {
+			closeStdin(err)
+			return
+		}
+		var msg []byte
+		if err := websocket.Message.Receive(s.conn, &msg); err != nil {
+			closeStdin(err)
+			return
+		}
+		if len(msg) == 0 {
+			continue
+		}
+		payload := msg[1:]
+		switch container.AttachMessageType(msg[0]) {
+		case container.AttachMessageStdin:
+			if !s.useStdin || stdinClosed {
+				continue
+			}
+			select {
+			case s.stdinCh <- payload:
+			case <-s.closed:
+			}
+		case container.AttachMessageResize:
+			var size container.AttachResize
+			if err := json.Unmarshal(payload, &size); err != nil {
+				logrus.WithError(err).Debug("invalid websocket attach resize message")
+				continue
+			}
+			if err := s.resize(int(size.Height), int(size.Width)); err != nil {
+				logrus.WithError(err).Debug("failed to resize container TTY")
+			}
+		case container.AttachMessageClose:
+			closeStdin(nil)
+		case container.AttachMessagePing:
+			if err := s.send(container.AttachMessagePong, payload); err != nil {
+				closeStdin(err)
+				return
+			}
+		case container.AttachMessagePong:
+			// Receiving it extended the read deadline.
+		default:
+			// Ignore unknown messages, so that clients can use
+			// messages added to later versions of the protocol.
+		}
+	}
+}
+
+// stdinLoop writes the stdin messages queued by readLoop to the stdin of the
+// container, and closes it once readLoop is done with stdin.
+func (s *wsAttachSession) stdinLoop() {
+	for payload := range s.stdinCh {
+		// Writes fail once the attach ended; the remaining messages
+		// are dropped.
+		_, _ = s.stdinW.Write(payload)
+	}
+	s.stdinW.CloseWithError(s.stdinErr)
+}
+
+// pingLoop pings the client at the given interval, until the session is
+// closed.
+func (s *wsAttachSession) pingLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.closed:
+			return
+		case <-ticker.C:
+			if err := s.send(container.AttachMessagePing, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// close tells the client that the attach ended, with the given error.
+func (s *wsAttachSession) close(err error) {
+	s.once.Do(func() {
+		close(s.closed)
+		var msg container.AttachClose
+		if err != nil {
+			msg.Error = err.Error()
+		}
+		payload, _ := json.Marshal(msg)
+		_ = s.send(container.AttachMessageClose, payload)
+		s.stdin.Close()
+	})
+}
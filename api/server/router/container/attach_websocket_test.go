@@ -0,0 +1,170 @@
+package container // import "github.com/docker/docker/api/server/router/container"
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/server/httputils"
+	"github.com/docker/docker/api/types/backend"
+	"github.com/docker/docker/api/types/container"
+	"golang.org/x/net/websocket"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+type fakeAttachBackend struct {
+	Backend
+	resized chan [2]int
+	// readStdin, if set, delays reading stdin until it is closed.
+	readStdin chan struct{}
+}
+
+func (b *fakeAttachBackend) ContainerResize(name string, height, width int) error {
+	b.resized <- [2]int{height, width}
+	return nil
+}
+
+// ContainerAttach echoes stdin to stdout, and writes "bye" to stderr once
+// stdin is closed.
+func (b *fakeAttachBackend) ContainerAttach(name string, c *backend.ContainerAttachConfig) error {
+	stdin, stdout, stderr, err := c.GetStreams(false)
+	if err != nil {
+		return err
+	}
+	defer stdin.Close()
+	if b.readStdin != nil {
+		<-b.readStdin
+	}
+	if _, err := io.Copy(stdout, stdin); err != nil {
+		return err
+	}
+	_, err = stderr.Write([]byte("bye"))
+	return err
+}
+
+type wsAttachMessage struct {
+	typ     container.AttachMessageType
+	payload string
+}
+
+// dialWSAttachV2 attaches to a container of the backend using version 2 of
+// the websocket attach protocol. It returns functions to send messages, and
+// to receive the messages of the daemon other than pings, which are answered.
+func dialWSAttachV2(t *testing.T, b Backend) (send func(container.AttachMessageType, []byte), receive func() (container.AttachMessageType, string)) {
+	r := &containerRouter{backend: b}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := context.WithValue(req.Context(), httputils.APIVersionKey{}, "1.43")
+		assert.Check(t, r.wsContainersAttach(ctx, w, req, map[string]string{"name": "web"}))
+	}))
+	t.Cleanup(srv.Close)
+
+	wsURL := "ws" + strings.TrimPrefix(srv.URL, "http") + "/containers/web/attach/ws?stdin=1&stdout=1&stderr=1&stream=1"
+	config, err := websocket.NewConfig(wsURL, srv.URL)
+	assert.NilError(t, err)
+	config.Protocol = []string{container.AttachWebSocketProtocolV2}
+	conn, err := websocket.DialConfig(config)
+	assert.NilError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	send = func(typ container.AttachMessageType, payload []byte) {
+		t.Helper()
+		assert.NilError(t, websocket.Message.Send(conn, append([]byte{byte(typ)}, payload...)))
+	}
+	// Answer the daemon's pings in the background, and forward the other
+	// messages.
+	messages := make(chan wsAttachMessage, 10)
+	go func() {
+		defer close(messages)
+		for {
+			var msg []byte
+			if err := websocket.Message.Receive(conn, &msg); err != nil || len(msg) == 0 {
+				return
+			}
+			if container.AttachMessageType(msg[0]) == container.AttachMessagePing {
+				if err := websocket.Message.Send(conn, append([]byte{byte(container.AttachMessagePong)}, msg[1:]...)); err != nil {
+					return
+				}
+				continue
+			}
+			messages <- wsAttachMessage{typ: container.AttachMessageType(msg[0]), payload: string(msg[1:])}
+		}
+	}()
+	receive = func() (container.AttachMessageType, string) {
+		t.Helper()
+		msg, ok := <-messages
+		assert.Assert(t, ok, "connection closed")
+		return msg.typ, msg.payload
+	}
+	return send, receive
+}
+
+func TestWebSocketAttachV2(t *testing.T) {
+	defer func(interval time.Duration) { wsAttachPingInterval = interval }(wsAttachPingInterval)
+	wsAttachPingInterval = 50 * time.Millisecond
+
+	b := &fakeAttachBackend{resized: make(chan [2]int, 1)}
+	send, receive := dialWSAttachV2(t, b)
+
+	send(container.AttachMessageResize, []byte(`{"height":24,"width":80}`))
+	assert.Check(t, is.Equal(<-b.resized, [2]int{24, 80}))
+
+	send(container.AttachMessagePing, []byte("1"))
+	typ, payload := receive()
+	assert.Check(t, is.Equal(typ, container.AttachMessagePong))
+	assert.Check(t, is.Equal(payload, "1"))
+
+	// Outlive the read deadline by answering the daemon's pings.
+	time.Sleep(3 * wsAttachPingInterval)
+
+	send(container.AttachMessageStdin, []byte("hello"))
+	typ, payload = receive()
+	assert.Check(t, is.Equal(typ, container.AttachMessageStdout))
+	assert.Check(t, is.Equal(payload, "hello"))
+
+	send(container.AttachMessageClose, nil)
+	typ, payload = receive()
+	assert.Check(t, is.Equal(typ, container.AttachMessageStderr))
+	assert.Check(t, is.Equal(payload, "bye"))
+
+	typ, payload = receive()
+	assert.Check(t, is.Equal(typ, container.AttachMessageClose))
+	var closeMsg container.AttachClose
+	assert.NilError(t, json.Unmarshal([]byte(payload), &closeMsg))
+	assert.Check(t, is.Equal(closeMsg.Error, ""))
+}
+
+func TestWebSocketAttachV2StdinNotRead(t *testing.T) {
+	b := &fakeAttachBackend{readStdin: make(chan struct{})}
+	send, receive := dialWSAttachV2(t, b)
+
+	// Messages are still handled while the container does not read its
+	// stdin.
+	send(container.AttachMessageStdin, []byte("hello"))
+	send(container.AttachMessageStdin, []byte(" world"))
+	send(container.AttachMessagePing, []byte("1"))
+	typ, payload := receive()
+	assert.Check(t, is.Equal(typ, container.AttachMessagePong))
+	assert.Check(t, is.Equal(payload, "1"))
+
+	// Buffered stdin is written in order once it is read, and before stdin
+	// is closed.
+	send(container.AttachMessageClose, nil)
+	close(b.readStdin)
+	var stdout string
+	for {
+		typ, payload = receive()
+		if typ != container.AttachMessageStdout {
+			break
+		}
+		stdout += payload
+	}
+	assert.Check(t, is.Equal(stdout, "hello world"))
+	assert.Check(t, is.Equal(typ, container.AttachMessageStderr))
+	assert.Check(t, is.Equal(payload, "bye"))
+}
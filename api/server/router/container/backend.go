@@ -16,6 +16,8 @@ import (
 type execBackend interface {
 	ContainerExecCreate(name string, config *types.ExecConfig) (string, error)
 	ContainerExecInspect(id string) (*backend.ExecInspect, error)
+	ContainerExecKill(name string, signal string) error
+	ContainerExecList(name string) ([]*backend.ExecInspect, error)
 	ContainerExecResize(name string, height, width int) error
 	ContainerExecStart(ctx context.Context, name string, options container.ExecStartOptions) error
 	ExecExists(name string) (bool, error)
@@ -43,6 +45,7 @@ type stateBackend interface {
 	ContainerStop(ctx context.Context, name string, options container.StopOptions) error
 	ContainerUnpause(name string) error
 	ContainerUpdate(name string, hostConfig *container.HostConfig) (container.ContainerUpdateOKBody, error)
+	ContainerUpdateLabels(name string, update types.LabelsUpdate) (map[string]string, error)
 	ContainerWait(ctx context.Context, name string, condition containerpkg.WaitCondition) (<-chan containerpkg.StateStatus, error)
 }
 
@@ -52,9 +55,10 @@ type monitorBackend interface {
 	ContainerInspect(name string, size bool, version string) (interface{}, error)
 	ContainerLogs(ctx context.Context, name string, config *types.ContainerLogsOptions) (msgs <-chan *backend.LogMessage, tty bool, err error)
 	ContainerStats(ctx context.Context, name string, config *backend.ContainerStatsConfig) error
+	ContainersStats(ctx context.Context, config *backend.ContainersStatsConfig) error
 	ContainerTop(name string, psArgs string) (*container.ContainerTopOKBody, error)
 
-	Containers(ctx context.Context, config *types.ContainerListOptions) ([]*types.Container, error)
+	ContainersPage(ctx context.Context, config *types.ContainerListOptions) ([]*types.Container, string, error)
 }
 
 // attachBackend includes function to implement to provide container attaching functionality.
@@ -71,6 +75,27 @@ type commitBackend interface {
 	CreateImageFromContainer(ctx context.Context, name string, config *backend.CreateImageConfig) (imageID string, err error)
 }
 
+// templateBackend includes functions to implement to provide container create
+// templates functionality.
+type templateBackend interface {
+	ContainerTemplateCreate(req container.TemplateCreateRequest) (container.Template, error)
+	ContainerTemplateInspect(name string) (container.Template, error)
+	ContainerTemplateList() []container.Template
+	ContainerTemplateRemove(name string) error
+}
+
+// groupBackend includes functions to implement to provide container groups
+// functionality.
+type groupBackend interface {
+	ContainerGroupCreate(req container.GroupCreateRequest) (container.Group, error)
+	ContainerGroupInspect(idOrName string) (container.Group, error)
+	ContainerGroupList() []container.Group
+	ContainerGroupRemove(idOrName string, force bool) error
+	ContainerGroupStart(ctx context.Context, idOrName string) error
+	ContainerGroupStop(ctx context.Context, idOrName string, options container.StopOptions) error
+	ContainerGroupStats(ctx context.Context, idOrName string, version string) ([]types.StatsJSON, error)
+}
+
 // Backend is all the methods that need to be implemented to provide container specific functionality.
 type Backend interface {
 	commitBackend
@@ -80,4 +105,6 @@ type Backend interface {
 	monitorBackend
 	attachBackend
 	systemBackend
+	templateBackend
+	groupBackend
 }
@@ -0,0 +1,95 @@
+package container // import "github.com/docker/docker/api/server/router/container"
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/docker/docker/api/server/httputils"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+)
+
+// batchMaxParallelism is the maximum number of containers a batch request
+// applies its action to at once.
+const batchMaxParallelism = 16
+
+func (s *containerRouter) postContainersBatch(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	var req container.BatchRequest
+	if err := httputils.ReadJSON(r, &req); err != nil {
+		return err
+	}
+	if len(req.Containers) == 0 {
+		return errdefs.InvalidParameter(errors.New("no containers specified"))
+	}
+	action, err := s.batchAction(ctx, req)
+	if err != nil {
+		return err
+	}
+	parallelism := req.Parallelism
+	if parallelism <= 0 || parallelism > batchMaxParallelism {
+		parallelism = batchMaxParallelism
+	}
+
+	results := make([]container.BatchResult, len(req.Containers))
+	sem := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+	for i, name := range req.Containers {
+		results[i].ID = name
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			setBatchError(&results[i], ctx.Err())
+			continue
+		}
+		wg.Add(1)
+		go func(res *container.BatchResult) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			// Containers already in the requested state are not errors.
+			if err := action(res.ID); err != nil && !errdefs.IsNotModified(err) {
+				setBatchError(res, err)
+			}
+		}(&results[i])
+	}
+	wg.Wait()
+
+	return httputils.WriteJSON(w, http.StatusOK, container.BatchResponse{Results: results})
+}
+
+// batchAction returns the function applying the action of a batch request to
+// a container.
+func (s *containerRouter) batchAction(ctx context.Context, req container.BatchRequest) (func(name string) error, error) {
+	switch req.Action {
+	case container.BatchStart:
+		return func(name string) error {
+			return s.backend.ContainerStart(ctx, name, nil, "", "")
+		}, nil
+	case container.BatchStop:
+		return func(name string) error {
+			return s.backend.ContainerStop(ctx, name, container.StopOptions{Signal: req.Signal, Timeout: req.Timeout})
+		}, nil
+	case container.BatchRestart:
+		return func(name string) error {
+			return s.backend.ContainerRestart(ctx, name, container.StopOptions{Signal: req.Signal, Timeout: req.Timeout})
+		}, nil
+	case container.BatchKill:
+		return func(name string) error {
+			return s.backend.ContainerKill(name, req.Signal)
+		}, nil
+	case container.BatchPause:
+		return s.backend.ContainerPause, nil
+	case container.BatchUnpause:
+		return s.backend.ContainerUnpause, nil
+	default:
+		return nil, errdefs.InvalidParameter(errors.Errorf("invalid batch action: %q", req.Action))
+	}
+}
+
+func setBatchError(res *container.BatchResult, err error) {
+	res.Error = err.Error()
+	res.ErrorCode = errdefs.Code(err)
+}
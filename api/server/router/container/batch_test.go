@@ -0,0 +1,85 @@
+package container // import "github.com/docker/docker/api/server/router/container"
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+type fakeBatchBackend struct {
+	Backend
+
+	mu      sync.Mutex
+	running int
+	max     int
+	stopped []string
+}
+
+func (b *fakeBatchBackend) ContainerStop(ctx context.Context, name string, options container.StopOptions) error {
+	b.mu.Lock()
+	b.running++
+	if b.running > b.max {
+		b.max = b.running
+	}
+	b.mu.Unlock()
+
+	time.Sleep(10 * time.Millisecond)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.running--
+	switch name {
+	case "missing":
+		return errdefs.NotFound(errors.New("No such container: missing"))
+	case "stopped":
+		return errdefs.NotModified(errors.New("container is already stopped"))
+	}
+	b.stopped = append(b.stopped, name)
+	return nil
+}
+
+func postBatch(t *testing.T, r *containerRouter, body string) (container.BatchResponse, error) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/containers/batch", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	if err := r.postContainersBatch(context.Background(), rec, req, nil); err != nil {
+		return container.BatchResponse{}, err
+	}
+	var resp container.BatchResponse
+	assert.NilError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	return resp, nil
+}
+
+func TestPostContainersBatch(t *testing.T) {
+	b := &fakeBatchBackend{}
+	r := &containerRouter{backend: b}
+
+	resp, err := postBatch(t, r, `{"Action":"stop","Containers":["a","missing","b","stopped","c"],"Parallelism":2}`)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(resp.Results, []container.BatchResult{
+		{ID: "a"},
+		{ID: "missing", Error: "No such container: missing", ErrorCode: errdefs.CodeNotFound},
+		{ID: "b"},
+		{ID: "stopped"},
+		{ID: "c"},
+	}))
+	assert.Check(t, is.Len(b.stopped, 3))
+	assert.Check(t, is.Equal(b.max, 2))
+
+	_, err = postBatch(t, r, `{"Action":"destroy","Containers":["a"]}`)
+	assert.Check(t, errdefs.IsInvalidParameter(err))
+	_, err = postBatch(t, r, `{"Action":"stop"}`)
+	assert.Check(t, errdefs.IsInvalidParameter(err))
+}
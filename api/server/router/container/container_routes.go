@@ -6,8 +6,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"runtime"
 	"strconv"
+	"time"
 
 	"github.com/containerd/containerd/platforms"
 	"github.com/docker/docker/api/server/httpstatus"
@@ -16,7 +16,7 @@ import (
 	"github.com/docker/docker/api/types/backend"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
-	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/pagination"
 	"github.com/docker/docker/api/types/versions"
 	containerpkg "github.com/docker/docker/container"
 	"github.com/docker/docker/errdefs"
@@ -90,11 +90,17 @@ func (s *containerRouter) getContainersJSON(ctx context.Context, w http.Response
 		}
 		config.Limit = limit
 	}
+	if versions.GreaterThanOrEqualTo(httputils.VersionFromContext(ctx), "1.43") {
+		config.Cursor = r.Form.Get("cursor")
+	}
 
-	containers, err := s.backend.Containers(ctx, config)
+	containers, nextPage, err := s.backend.ContainersPage(ctx, config)
 	if err != nil {
 		return err
 	}
+	if nextPage != "" {
+		w.Header().Set(pagination.NextCursorHeader, nextPage)
+	}
 
 	return httputils.WriteJSON(w, http.StatusOK, containers)
 }
@@ -123,6 +129,34 @@ func (s *containerRouter) getContainersStats(ctx context.Context, w http.Respons
 	return s.backend.ContainerStats(ctx, vars["name"], config)
 }
 
+func (s *containerRouter) getAllContainersStats(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+
+	filter, err := filters.FromJSON(r.Form.Get("filters"))
+	if err != nil {
+		return err
+	}
+
+	var interval time.Duration
+	if v := r.Form.Get("interval"); v != "" {
+		seconds, err := strconv.Atoi(v)
+		if err != nil {
+			return errdefs.InvalidParameter(errors.Wrap(err, "invalid value for interval"))
+		}
+		interval = time.Duration(seconds) * time.Second
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return s.backend.ContainersStats(ctx, &backend.ContainersStatsConfig{
+		Stream:    httputils.BoolValueOrDefault(r, "stream", false),
+		Interval:  interval,
+		Filters:   filter,
+		OutStream: w,
+	})
+}
+
 func (s *containerRouter) getContainersLogs(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
 	if err := httputils.ParseForm(r); err != nil {
 		return err
@@ -432,6 +466,18 @@ func (s *containerRouter) postContainerRename(ctx context.Context, w http.Respon
 	return nil
 }
 
+func (s *containerRouter) postContainerLabels(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	update, err := httputils.ReadLabelsUpdate(r)
+	if err != nil {
+		return err
+	}
+	labels, err := s.backend.ContainerUpdateLabels(vars["name"], update)
+	if err != nil {
+		return err
+	}
+	return httputils.WriteJSON(w, http.StatusOK, types.LabelsUpdateResponse{Labels: labels})
+}
+
 func (s *containerRouter) postContainerUpdate(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
 	if err := httputils.ParseForm(r); err != nil {
 		return err
@@ -481,82 +527,17 @@ func (s *containerRouter) postContainersCreate(ctx context.Context, w http.Respo
 	}
 
 	name := r.Form.Get("name")
+	version := httputils.VersionFromContext(ctx)
 
+	// The template parameter is applied by the ContainerTemplateMiddleware.
 	config, hostConfig, networkingConfig, err := s.decoder.DecodeConfig(r.Body)
 	if err != nil {
 		return err
 	}
-	version := httputils.VersionFromContext(ctx)
 	adjustCPUShares := versions.LessThan(version, "1.19")
 
-	// When using API 1.24 and under, the client is responsible for removing the container
-	if hostConfig != nil && versions.LessThan(version, "1.25") {
-		hostConfig.AutoRemove = false
-	}
-
-	if hostConfig != nil && versions.LessThan(version, "1.40") {
-		// Ignore BindOptions.NonRecursive because it was added in API 1.40.
-		for _, m := range hostConfig.Mounts {
-			if bo := m.BindOptions; bo != nil {
-				bo.NonRecursive = false
-			}
-		}
-		// Ignore KernelMemoryTCP because it was added in API 1.40.
-		hostConfig.KernelMemoryTCP = 0
-
-		// Older clients (API < 1.40) expects the default to be shareable, make them happy
-		if hostConfig.IpcMode.IsEmpty() {
-			hostConfig.IpcMode = container.IPCModeShareable
-		}
-	}
-	if hostConfig != nil && versions.LessThan(version, "1.41") && !s.cgroup2 {
-		// Older clients expect the default to be "host" on cgroup v1 hosts
-		if hostConfig.CgroupnsMode.IsEmpty() {
-			hostConfig.CgroupnsMode = container.CgroupnsModeHost
-		}
-	}
-
-	if hostConfig != nil && versions.LessThan(version, "1.42") {
-		for _, m := range hostConfig.Mounts {
-			// Ignore BindOptions.CreateMountpoint because it was added in API 1.42.
-			if bo := m.BindOptions; bo != nil {
-				bo.CreateMountpoint = false
-			}
-
-			// These combinations are invalid, but weren't validated in API < 1.42.
-			// We reset them here, so that validation doesn't produce an error.
-			if o := m.VolumeOptions; o != nil && m.Type != mount.TypeVolume {
-				m.VolumeOptions = nil
-			}
-			if o := m.TmpfsOptions; o != nil && m.Type != mount.TypeTmpfs {
-				m.TmpfsOptions = nil
-			}
-			if bo := m.BindOptions; bo != nil {
-				// Ignore BindOptions.CreateMountpoint because it was added in API 1.42.
-				bo.CreateMountpoint = false
-			}
-		}
-	}
-
-	if hostConfig != nil && versions.GreaterThanOrEqualTo(version, "1.42") {
-		// Ignore KernelMemory removed in API 1.42.
-		hostConfig.KernelMemory = 0
-		for _, m := range hostConfig.Mounts {
-			if o := m.VolumeOptions; o != nil && m.Type != mount.TypeVolume {
-				return errdefs.InvalidParameter(fmt.Errorf("VolumeOptions must not be specified on mount type %q", m.Type))
-			}
-			if o := m.BindOptions; o != nil && m.Type != mount.TypeBind {
-				return errdefs.InvalidParameter(fmt.Errorf("BindOptions must not be specified on mount type %q", m.Type))
-			}
-			if o := m.TmpfsOptions; o != nil && m.Type != mount.TypeTmpfs {
-				return errdefs.InvalidParameter(fmt.Errorf("TmpfsOptions must not be specified on mount type %q", m.Type))
-			}
-		}
-	}
-
-	if hostConfig != nil && runtime.GOOS == "linux" && versions.LessThan(version, "1.42") {
-		// ConsoleSize is not respected by Linux daemon before API 1.42
-		hostConfig.ConsoleSize = [2]uint{0, 0}
+	if err := AdjustCreateConfig(version, s.cgroup2, hostConfig, networkingConfig); err != nil {
+		return err
 	}
 
 	var platform *specs.Platform
@@ -570,14 +551,6 @@ func (s *containerRouter) postContainersCreate(ctx context.Context, w http.Respo
 		}
 	}
 
-	if hostConfig != nil && hostConfig.PidsLimit != nil && *hostConfig.PidsLimit <= 0 {
-		// Don't set a limit if either no limit was specified, or "unlimited" was
-		// explicitly set.
-		// Both `0` and `-1` are accepted as "unlimited", and historically any
-		// negative value was accepted, so treat those as "unlimited" as well.
-		hostConfig.PidsLimit = nil
-	}
-
 	ccr, err := s.backend.ContainerCreate(ctx, types.ContainerCreateConfig{
 		Name:             name,
 		Config:           config,
@@ -703,6 +676,9 @@ func (s *containerRouter) wsContainersAttach(ctx context.Context, w http.Respons
 	if err := httputils.ParseForm(r); err != nil {
 		return err
 	}
+	if versions.GreaterThanOrEqualTo(httputils.VersionFromContext(ctx), "1.43") && wantsWSAttachV2(r) {
+		return s.wsContainersAttachV2(ctx, w, r, vars)
+	}
 	containerName := vars["name"]
 
 	var err error
@@ -12,7 +12,11 @@ import (
 	"github.com/docker/docker/api/server/httputils"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/versions"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/archive"
 	gddohttputil "github.com/golang/gddo/httputil"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pkg/errors"
 )
 
 type pathError struct{}
@@ -82,9 +86,26 @@ func (s *containerRouter) headContainersArchive(ctx context.Context, w http.Resp
 	return setContainerPathStatHeader(stat, w.Header())
 }
 
-func writeCompressedResponse(w http.ResponseWriter, r *http.Request, body io.Reader) error {
+// archiveEncodings returns the content encodings of archives supported by the
+// given API version, in order of preference.
+func archiveEncodings(version string) []string {
+	if versions.GreaterThanOrEqualTo(version, "1.43") {
+		return []string{"zstd", "gzip", "deflate"}
+	}
+	return []string{"gzip", "deflate"}
+}
+
+func writeCompressedResponse(w http.ResponseWriter, r *http.Request, body io.Reader, encodings []string) error {
 	var cw io.Writer
-	switch gddohttputil.NegotiateContentEncoding(r, []string{"gzip", "deflate"}) {
+	switch gddohttputil.NegotiateContentEncoding(r, encodings) {
+	case "zstd":
+		zw, err := zstd.NewWriter(w)
+		if err != nil {
+			return err
+		}
+		defer zw.Close()
+		cw = zw
+		w.Header().Set("Content-Encoding", "zstd")
 	case "gzip":
 		gw := gzip.NewWriter(w)
 		defer gw.Close()
@@ -105,6 +126,45 @@ func writeCompressedResponse(w http.ResponseWriter, r *http.Request, body io.Rea
 	return err
 }
 
+// decompressedBody returns the body of the request, decoded according to its
+// Content-Encoding header.
+func decompressedBody(r *http.Request) (io.ReadCloser, error) {
+	switch enc := r.Header.Get("Content-Encoding"); enc {
+	case "", "identity":
+		return r.Body, nil
+	case "zstd":
+		zr, err := zstd.NewReader(r.Body)
+		if err != nil {
+			return nil, errdefs.InvalidParameter(err)
+		}
+		return zr.IOReadCloser(), nil
+	case "gzip":
+		gr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			return nil, errdefs.InvalidParameter(err)
+		}
+		return gr, nil
+	case "deflate":
+		return flate.NewReader(r.Body), nil
+	default:
+		return nil, errdefs.InvalidParameter(errors.Errorf("unsupported content encoding: %s", enc))
+	}
+}
+
+// filterArchive returns the archive filtered by the include and exclude
+// patterns of the request, if any.
+func filterArchive(r *http.Request, tarArchive io.Reader) (io.ReadCloser, error) {
+	include, exclude := r.Form["include"], r.Form["exclude"]
+	if len(include) == 0 && len(exclude) == 0 {
+		return io.NopCloser(tarArchive), nil
+	}
+	rc, err := archive.FilterTar(tarArchive, include, exclude)
+	if err != nil {
+		return nil, errdefs.InvalidParameter(err)
+	}
+	return rc, nil
+}
+
 func (s *containerRouter) getContainersArchive(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
 	v, err := httputils.ArchiveFormValues(r, vars)
 	if err != nil {
@@ -117,12 +177,21 @@ func (s *containerRouter) getContainersArchive(ctx context.Context, w http.Respo
 	}
 	defer tarArchive.Close()
 
+	version := httputils.VersionFromContext(ctx)
+	var body io.ReadCloser = tarArchive
+	if versions.GreaterThanOrEqualTo(version, "1.43") {
+		if body, err = filterArchive(r, tarArchive); err != nil {
+			return err
+		}
+		defer body.Close()
+	}
+
 	if err := setContainerPathStatHeader(stat, w.Header()); err != nil {
 		return err
 	}
 
 	w.Header().Set("Content-Type", "application/x-tar")
-	return writeCompressedResponse(w, r, tarArchive)
+	return writeCompressedResponse(w, r, body, archiveEncodings(version))
 }
 
 func (s *containerRouter) putContainersArchive(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
@@ -134,5 +203,30 @@ func (s *containerRouter) putContainersArchive(ctx context.Context, w http.Respo
 	noOverwriteDirNonDir := httputils.BoolValue(r, "noOverwriteDirNonDir")
 	copyUIDGID := httputils.BoolValue(r, "copyUIDGID")
 
-	return s.backend.ContainerExtractToDir(v.Name, v.Path, copyUIDGID, noOverwriteDirNonDir, r.Body)
+	var content io.Reader = r.Body
+	if versions.GreaterThanOrEqualTo(httputils.VersionFromContext(ctx), "1.43") {
+		body, err := decompressedBody(r)
+		if err != nil {
+			return err
+		}
+		defer body.Close()
+		content = body
+
+		if len(r.Form["include"]) > 0 || len(r.Form["exclude"]) > 0 {
+			// Archives can also be compressed without a Content-Encoding.
+			decompressed, err := archive.DecompressStream(body)
+			if err != nil {
+				return errdefs.InvalidParameter(err)
+			}
+			defer decompressed.Close()
+			filtered, err := filterArchive(r, decompressed)
+			if err != nil {
+				return err
+			}
+			defer filtered.Close()
+			content = filtered
+		}
+	}
+
+	return s.backend.ContainerExtractToDir(v.Name, v.Path, copyUIDGID, noOverwriteDirNonDir, content)
 }
@@ -0,0 +1,106 @@
+package container // import "github.com/docker/docker/api/server/router/container"
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/docker/docker/api/server/httputils"
+	"github.com/docker/docker/api/types"
+	"github.com/klauspost/compress/zstd"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+type fakeArchiveBackend struct {
+	Backend
+	extracted []string
+}
+
+func (b *fakeArchiveBackend) ContainerArchivePath(name string, path string) (io.ReadCloser, *types.ContainerPathStat, error) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, n := range []string{"app/main.go", "app/vendor/lib.go"} {
+		if err := tw.WriteHeader(&tar.Header{Name: n, Typeflag: tar.TypeReg, Mode: 0o644}); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, nil, err
+	}
+	return io.NopCloser(&buf), &types.ContainerPathStat{Name: "app"}, nil
+}
+
+func (b *fakeArchiveBackend) ContainerExtractToDir(name, path string, copyUIDGID, noOverwriteDirNonDir bool, content io.Reader) error {
+	var err error
+	b.extracted, err = tarNames(content)
+	return err
+}
+
+func tarNames(r io.Reader) ([]string, error) {
+	var names []string
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return names, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, hdr.Name)
+	}
+}
+
+func TestGetContainersArchiveCompressionAndFilter(t *testing.T) {
+	r := &containerRouter{backend: &fakeArchiveBackend{}}
+	ctx := context.WithValue(context.Background(), httputils.APIVersionKey{}, "1.43")
+
+	req := httptest.NewRequest(http.MethodGet, "/containers/web/archive?path=/app&exclude=app/vendor", nil)
+	req.Header.Set("Accept-Encoding", "gzip, zstd")
+	rec := httptest.NewRecorder()
+	assert.NilError(t, r.getContainersArchive(ctx, rec, req, map[string]string{"name": "web"}))
+	assert.Check(t, is.Equal(rec.Header().Get("Content-Encoding"), "zstd"))
+
+	zr, err := zstd.NewReader(rec.Body)
+	assert.NilError(t, err)
+	defer zr.Close()
+	names, err := tarNames(zr)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(names, []string{"app/main.go"}))
+
+	// Older API versions don't offer zstd.
+	ctx = context.WithValue(context.Background(), httputils.APIVersionKey{}, "1.42")
+	rec = httptest.NewRecorder()
+	assert.NilError(t, r.getContainersArchive(ctx, rec, req, map[string]string{"name": "web"}))
+	assert.Check(t, is.Equal(rec.Header().Get("Content-Encoding"), "gzip"))
+}
+
+func TestPutContainersArchiveCompressionAndFilter(t *testing.T) {
+	b := &fakeArchiveBackend{}
+	r := &containerRouter{backend: b}
+	ctx := context.WithValue(context.Background(), httputils.APIVersionKey{}, "1.43")
+
+	content, _, err := b.ContainerArchivePath("web", "/app")
+	assert.NilError(t, err)
+	var buf bytes.Buffer
+	zw, err := zstd.NewWriter(&buf)
+	assert.NilError(t, err)
+	_, err = io.Copy(zw, content)
+	assert.NilError(t, err)
+	assert.NilError(t, zw.Close())
+
+	req := httptest.NewRequest(http.MethodPut, "/containers/web/archive?path=/&include=**/lib.go", &buf)
+	req.Header.Set("Content-Encoding", "zstd")
+	assert.NilError(t, r.putContainersArchive(ctx, httptest.NewRecorder(), req, map[string]string{"name": "web"}))
+	assert.Check(t, is.DeepEqual(b.extracted, []string{"app/vendor/lib.go"}))
+
+	req = httptest.NewRequest(http.MethodPut, "/containers/web/archive?path=/", &buf)
+	req.Header.Set("Content-Encoding", "br")
+	err = r.putContainersArchive(ctx, httptest.NewRecorder(), req, map[string]string{"name": "web"})
+	assert.Check(t, is.ErrorContains(err, "unsupported content encoding"))
+}
@@ -0,0 +1,133 @@
+package container // import "github.com/docker/docker/api/server/router/container"
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/versions"
+	"github.com/docker/docker/errdefs"
+)
+
+// AdjustCreateConfig adjusts the configuration of a container to create for
+// the API version of the request: the fields added in later versions are
+// ignored, and the defaults of earlier versions are applied. The options of
+// the mounts are then validated. It is shared by the HTTP and the gRPC
+// flavors of the API. cgroup2 reports whether the host uses cgroup v2.
+func AdjustCreateConfig(version string, cgroup2 bool, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig) error {
+	// When using API 1.24 and under, the client is responsible for removing the container
+	if hostConfig != nil && versions.LessThan(version, "1.25") {
+		hostConfig.AutoRemove = false
+	}
+
+	if hostConfig != nil && versions.LessThan(version, "1.40") {
+		// Ignore BindOptions.NonRecursive because it was added in API 1.40.
+		for _, m := range hostConfig.Mounts {
+			if bo := m.BindOptions; bo != nil {
+				bo.NonRecursive = false
+			}
+		}
+		// Ignore KernelMemoryTCP because it was added in API 1.40.
+		hostConfig.KernelMemoryTCP = 0
+
+		// Older clients (API < 1.40) expects the default to be shareable, make them happy
+		if hostConfig.IpcMode.IsEmpty() {
+			hostConfig.IpcMode = container.IPCModeShareable
+		}
+	}
+	if hostConfig != nil && versions.LessThan(version, "1.41") && !cgroup2 {
+		// Older clients expect the default to be "host" on cgroup v1 hosts
+		if hostConfig.CgroupnsMode.IsEmpty() {
+			hostConfig.CgroupnsMode = container.CgroupnsModeHost
+		}
+	}
+
+	if hostConfig != nil && versions.LessThan(version, "1.42") {
+		for _, m := range hostConfig.Mounts {
+			// Ignore BindOptions.CreateMountpoint because it was added in API 1.42.
+			if bo := m.BindOptions; bo != nil {
+				bo.CreateMountpoint = false
+			}
+
+			// These combinations are invalid, but weren't validated in API < 1.42.
+			// We reset them here, so that validation doesn't produce an error.
+			if o := m.VolumeOptions; o != nil && m.Type != mount.TypeVolume {
+				m.VolumeOptions = nil
+			}
+			if o := m.TmpfsOptions; o != nil && m.Type != mount.TypeTmpfs {
+				m.TmpfsOptions = nil
+			}
+			if bo := m.BindOptions; bo != nil {
+				// Ignore BindOptions.CreateMountpoint because it was added in API 1.42.
+				bo.CreateMountpoint = false
+			}
+		}
+	}
+
+	if hostConfig != nil && versions.LessThan(version, "1.43") {
+		for _, m := range hostConfig.Mounts {
+			// Ignore the tmpfs options that were added in API 1.43.
+			if o := m.TmpfsOptions; o != nil {
+				o.UID = nil
+				o.GID = nil
+				o.HugePages = ""
+				o.NoSwap = false
+			}
+			// Ignore BindOptions.IDMap because it was added in API 1.43.
+			if bo := m.BindOptions; bo != nil {
+				bo.IDMap = false
+			}
+		}
+		// Ignore Hooks, NotifyReady, Secrets, Configs and Lifetime because
+		// they were added in API 1.43.
+		hostConfig.Hooks = nil
+		hostConfig.NotifyReady = false
+		hostConfig.Secrets = nil
+		hostConfig.Configs = nil
+		hostConfig.Lifetime = nil
+	}
+
+	if networkingConfig != nil && versions.LessThan(version, "1.43") {
+		// Ignore AliasOptions and HealthGatedDNS because they were added in
+		// API 1.43.
+		for _, epConfig := range networkingConfig.EndpointsConfig {
+			if epConfig != nil {
+				epConfig.AliasOptions = nil
+				epConfig.HealthGatedDNS = false
+			}
+		}
+	}
+
+	if hostConfig != nil && versions.GreaterThanOrEqualTo(version, "1.42") {
+		// Ignore KernelMemory removed in API 1.42.
+		hostConfig.KernelMemory = 0
+		for _, m := range hostConfig.Mounts {
+			if o := m.VolumeOptions; o != nil && m.Type != mount.TypeVolume {
+				return errdefs.InvalidParameter(fmt.Errorf("VolumeOptions must not be specified on mount type %q", m.Type))
+			}
+			if o := m.BindOptions; o != nil && m.Type != mount.TypeBind {
+				return errdefs.InvalidParameter(fmt.Errorf("BindOptions must not be specified on mount type %q", m.Type))
+			}
+			if o := m.TmpfsOptions; o != nil && m.Type != mount.TypeTmpfs {
+				return errdefs.InvalidParameter(fmt.Errorf("TmpfsOptions must not be specified on mount type %q", m.Type))
+			}
+		}
+	}
+
+	if hostConfig != nil && runtime.GOOS == "linux" && versions.LessThan(version, "1.42") {
+		// ConsoleSize is not respected by Linux daemon before API 1.42
+		hostConfig.ConsoleSize = [2]uint{0, 0}
+	}
+
+	if hostConfig != nil && hostConfig.PidsLimit != nil && *hostConfig.PidsLimit <= 0 {
+		// Don't set a limit if either no limit was specified, or "unlimited" was
+		// explicitly set.
+		// Both `0` and `-1` are accepted as "unlimited", and historically any
+		// negative value was accepted, so treat those as "unlimited" as well.
+		hostConfig.PidsLimit = nil
+	}
+
+	return nil
+}
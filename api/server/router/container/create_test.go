@@ -0,0 +1,42 @@
+package container // import "github.com/docker/docker/api/server/router/container"
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/errdefs"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestAdjustCreateConfig(t *testing.T) {
+	uid := 1000
+	newConfig := func() (*container.HostConfig, *network.NetworkingConfig) {
+		return &container.HostConfig{
+			Mounts: []mount.Mount{{Type: mount.TypeTmpfs, Target: "/tmp", TmpfsOptions: &mount.TmpfsOptions{UID: &uid}}},
+			Hooks:  &container.Hooks{},
+		}, &network.NetworkingConfig{
+			EndpointsConfig: map[string]*network.EndpointSettings{"net1": {HealthGatedDNS: true}},
+		}
+	}
+
+	hostConfig, networkingConfig := newConfig()
+	assert.NilError(t, AdjustCreateConfig("1.43", true, hostConfig, networkingConfig))
+	assert.Check(t, hostConfig.Hooks != nil)
+	assert.Check(t, hostConfig.Mounts[0].TmpfsOptions.UID != nil)
+	assert.Check(t, networkingConfig.EndpointsConfig["net1"].HealthGatedDNS)
+
+	hostConfig, networkingConfig = newConfig()
+	assert.NilError(t, AdjustCreateConfig("1.42", true, hostConfig, networkingConfig))
+	assert.Check(t, is.Nil(hostConfig.Hooks))
+	assert.Check(t, is.Nil(hostConfig.Mounts[0].TmpfsOptions.UID))
+	assert.Check(t, !networkingConfig.EndpointsConfig["net1"].HealthGatedDNS)
+
+	hostConfig = &container.HostConfig{
+		Mounts: []mount.Mount{{Type: mount.TypeBind, Source: "/src", Target: "/dst", TmpfsOptions: &mount.TmpfsOptions{}}},
+	}
+	err := AdjustCreateConfig("1.43", true, hostConfig, nil)
+	assert.Check(t, errdefs.IsInvalidParameter(err), err)
+}
@@ -8,6 +8,7 @@ import (
 	"strconv"
 
 	"github.com/docker/docker/api/server/httputils"
+	"github.com/docker/docker/api/server/middleware"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/versions"
@@ -25,6 +26,44 @@ func (s *containerRouter) getExecByID(ctx context.Context, w http.ResponseWriter
 	return httputils.WriteJSON(w, http.StatusOK, eConfig)
 }
 
+func (s *containerRouter) getContainerExecs(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	execs, err := s.backend.ContainerExecList(vars["name"])
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, execs)
+}
+
+func (s *containerRouter) postContainerExecKill(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+
+	if err := s.backend.ContainerExecKill(vars["name"], r.Form.Get("signal")); err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// execClient returns the identity of the API client which starts an exec.
+func execClient(ctx context.Context, r *http.Request) *container.ExecClient {
+	user, _, peer := middleware.ClientIdentity(ctx, r)
+	client := &container.ExecClient{
+		User:       user,
+		RemoteAddr: r.RemoteAddr,
+		UserAgent:  r.UserAgent(),
+	}
+	if peer != nil {
+		uid := peer.UID
+		client.UID = &uid
+		client.PID = peer.PID
+	}
+	return client
+}
+
 type execCommandError struct{}
 
 func (execCommandError) Error() string {
@@ -52,6 +91,10 @@ func (s *containerRouter) postContainerExecCreate(ctx context.Context, w http.Re
 		// Not supported by API versions before 1.42
 		execConfig.ConsoleSize = nil
 	}
+	if versions.LessThan(version, "1.43") {
+		// Not supported by API versions before 1.43
+		execConfig.Resources = nil
+	}
 
 	// Register an instance of Exec in container.
 	id, err := s.backend.ContainerExecCreate(vars["name"], execConfig)
@@ -107,6 +150,9 @@ func (s *containerRouter) postContainerExecStart(ctx context.Context, w http.Res
 		}
 	}
 
+	// Identify the client before the connection is hijacked.
+	client := execClient(ctx, r)
+
 	if !execStartCheck.Detach {
 		var err error
 		// Setting up the streaming http interface.
@@ -145,6 +191,7 @@ func (s *containerRouter) postContainerExecStart(ctx context.Context, w http.Res
 		Stdout:      stdout,
 		Stderr:      stderr,
 		ConsoleSize: execStartCheck.ConsoleSize,
+		Client:      client,
 	}
 
 	// Now run the user process in container.
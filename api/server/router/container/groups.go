@@ -0,0 +1,87 @@
+package container // import "github.com/docker/docker/api/server/router/container"
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/docker/docker/api/server/httputils"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+)
+
+func (s *containerRouter) getGroups(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	return httputils.WriteJSON(w, http.StatusOK, s.backend.ContainerGroupList())
+}
+
+func (s *containerRouter) getGroupByName(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	g, err := s.backend.ContainerGroupInspect(vars["name"])
+	if err != nil {
+		return err
+	}
+	return httputils.WriteJSON(w, http.StatusOK, g)
+}
+
+func (s *containerRouter) getGroupStats(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	stats, err := s.backend.ContainerGroupStats(ctx, vars["name"], httputils.VersionFromContext(ctx))
+	if err != nil {
+		return err
+	}
+	return httputils.WriteJSON(w, http.StatusOK, stats)
+}
+
+func (s *containerRouter) postGroupsCreate(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	var req container.GroupCreateRequest
+	if err := httputils.ReadJSON(r, &req); err != nil {
+		return err
+	}
+	if req.Name == "" {
+		return errdefs.InvalidParameter(errors.New("group name is required"))
+	}
+	g, err := s.backend.ContainerGroupCreate(req)
+	if err != nil {
+		return err
+	}
+	return httputils.WriteJSON(w, http.StatusCreated, g)
+}
+
+func (s *containerRouter) postGroupsStart(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := s.backend.ContainerGroupStart(ctx, vars["name"]); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func (s *containerRouter) postGroupsStop(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+
+	options := container.StopOptions{Signal: r.Form.Get("signal")}
+	if tmpSeconds := r.Form.Get("t"); tmpSeconds != "" {
+		valSeconds, err := strconv.Atoi(tmpSeconds)
+		if err != nil {
+			return errdefs.InvalidParameter(err)
+		}
+		options.Timeout = &valSeconds
+	}
+
+	if err := s.backend.ContainerGroupStop(ctx, vars["name"], options); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func (s *containerRouter) deleteGroups(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+	if err := s.backend.ContainerGroupRemove(vars["name"], httputils.BoolValue(r, "force")); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
@@ -0,0 +1,51 @@
+package container // import "github.com/docker/docker/api/server/router/container"
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+
+	"github.com/docker/docker/api/server/httputils"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+)
+
+func (s *containerRouter) getTemplates(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	return httputils.WriteJSON(w, http.StatusOK, s.backend.ContainerTemplateList())
+}
+
+func (s *containerRouter) getTemplateByName(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	t, err := s.backend.ContainerTemplateInspect(vars["name"])
+	if err != nil {
+		return err
+	}
+	return httputils.WriteJSON(w, http.StatusOK, t)
+}
+
+func (s *containerRouter) postTemplatesCreate(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	var req container.TemplateCreateRequest
+	if err := httputils.ReadJSON(r, &req); err != nil {
+		return err
+	}
+	if len(req.Spec) == 0 {
+		return errdefs.InvalidParameter(errors.New("template spec is required"))
+	}
+	// Reject specs that could never be used to create a container.
+	if _, _, _, err := s.decoder.DecodeConfig(bytes.NewReader(req.Spec)); err != nil {
+		return errdefs.InvalidParameter(errors.Wrap(err, "invalid template spec"))
+	}
+	t, err := s.backend.ContainerTemplateCreate(req)
+	if err != nil {
+		return err
+	}
+	return httputils.WriteJSON(w, http.StatusCreated, t)
+}
+
+func (s *containerRouter) deleteTemplates(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := s.backend.ContainerTemplateRemove(vars["name"]); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
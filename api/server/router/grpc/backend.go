@@ -1,8 +1,21 @@
 package grpc // import "github.com/docker/docker/api/server/router/grpc"
 
-import "google.golang.org/grpc"
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
 
 // Backend abstracts a registerable GRPC service.
 type Backend interface {
 	RegisterGRPC(*grpc.Server)
 }
+
+// InterceptingBackend is a Backend which checks the calls to the server
+// before they are handled. The interceptors are called for the calls to
+// every service of the server.
+type InterceptingBackend interface {
+	Backend
+	UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error)
+	StreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error
+}
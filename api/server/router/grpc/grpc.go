@@ -15,11 +15,19 @@ type grpcRouter struct {
 
 // NewRouter initializes a new grpc http router
 func NewRouter(backends ...Backend) router.Router {
+	unary := []grpc.UnaryServerInterceptor{grpcerrors.UnaryServerInterceptor}
+	stream := []grpc.StreamServerInterceptor{grpcerrors.StreamServerInterceptor}
+	for _, b := range backends {
+		if ib, ok := b.(InterceptingBackend); ok {
+			unary = append(unary, ib.UnaryInterceptor)
+			stream = append(stream, ib.StreamInterceptor)
+		}
+	}
 	r := &grpcRouter{
 		h2Server: &http2.Server{},
 		grpcServer: grpc.NewServer(
-			grpc.UnaryInterceptor(grpcerrors.UnaryServerInterceptor),
-			grpc.StreamInterceptor(grpcerrors.StreamServerInterceptor),
+			grpc.ChainUnaryInterceptor(unary...),
+			grpc.ChainStreamInterceptor(stream...),
 		),
 	}
 	for _, b := range backends {
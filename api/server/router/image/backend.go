@@ -27,6 +27,7 @@ type imageBackend interface {
 	Images(ctx context.Context, opts types.ImageListOptions) ([]*types.ImageSummary, error)
 	GetImage(ctx context.Context, refOrID string, options image.GetImageOpts) (*dockerimage.Image, error)
 	TagImage(imageName, repository, tag string) (string, error)
+	ImageUpdateLabels(ctx context.Context, refOrID string, update types.LabelsUpdate) (id string, labels map[string]string, err error)
 	ImagesPrune(ctx context.Context, pruneFilters filters.Args) (*types.ImagesPruneReport, error)
 }
 
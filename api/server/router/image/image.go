@@ -48,6 +48,7 @@ func (ir *imageRouter) initRoutes() {
 		router.NewPostRoute("/images/create", ir.postImagesCreate),
 		router.NewPostRoute("/images/{name:.*}/push", ir.postImagesPush),
 		router.NewPostRoute("/images/{name:.*}/tag", ir.postImagesTag),
+		router.NewPostRoute("/images/{name:.*}/labels", ir.postImagesLabels),
 		router.NewPostRoute("/images/prune", ir.postImagesPrune),
 		// DELETE
 		router.NewDeleteRoute("/images/{name:.*}", ir.deleteImages),
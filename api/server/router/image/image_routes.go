@@ -15,6 +15,7 @@ import (
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/filters"
 	opts "github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/pagination"
 	"github.com/docker/docker/api/types/registry"
 	"github.com/docker/docker/api/types/versions"
 	"github.com/docker/docker/builder/remotecontext"
@@ -39,12 +40,25 @@ func (ir *imageRouter) postImagesCreate(ctx context.Context, w http.ResponseWrit
 		tag         = r.Form.Get("tag")
 		comment     = r.Form.Get("message")
 		progressErr error
-		output      = ioutils.NewWriteFlusher(w)
+		flusher     = ioutils.NewWriteFlusher(w)
+		output      = io.Writer(flusher)
 		platform    *specs.Platform
 	)
-	defer output.Close()
+	defer flusher.Close()
 
 	w.Header().Set("Content-Type", "application/json")
+	if httputils.AcceptsProgressV2(ctx, r) {
+		w.Header().Set("Content-Type", types.MediaTypeProgressV2)
+		pw := streamformatter.NewProgressV2Writer(flusher)
+		defer func() {
+			// No summary is written if an error is returned instead of
+			// the stream.
+			if flusher.Flushed() {
+				_ = pw.Close()
+			}
+		}()
+		output = pw
+	}
 
 	version := httputils.VersionFromContext(ctx)
 	if versions.GreaterThanOrEqualTo(version, "1.32") {
@@ -128,7 +142,7 @@ func (ir *imageRouter) postImagesCreate(ctx context.Context, w http.ResponseWrit
 		}
 	}
 	if progressErr != nil {
-		if !output.Flushed() {
+		if !flusher.Flushed() {
 			return progressErr
 		}
 		_, _ = output.Write(streamformatter.FormatError(progressErr))
@@ -162,15 +176,28 @@ func (ir *imageRouter) postImagesPush(ctx context.Context, w http.ResponseWriter
 		}
 	}
 
-	output := ioutils.NewWriteFlusher(w)
-	defer output.Close()
+	flusher := ioutils.NewWriteFlusher(w)
+	defer flusher.Close()
+	output := io.Writer(flusher)
 
 	w.Header().Set("Content-Type", "application/json")
+	if httputils.AcceptsProgressV2(ctx, r) {
+		w.Header().Set("Content-Type", types.MediaTypeProgressV2)
+		pw := streamformatter.NewProgressV2Writer(flusher)
+		defer func() {
+			// No summary is written if an error is returned instead of
+			// the stream.
+			if flusher.Flushed() {
+				_ = pw.Close()
+			}
+		}()
+		output = pw
+	}
 
 	img := vars["name"]
 	tag := r.Form.Get("tag")
 	if err := ir.backend.PushImage(ctx, img, tag, metaHeaders, authConfig, output); err != nil {
-		if !output.Flushed() {
+		if !flusher.Flushed() {
 			return err
 		}
 		_, _ = output.Write(streamformatter.FormatError(err))
@@ -344,14 +371,35 @@ func (ir *imageRouter) getImagesJSON(ctx context.Context, w http.ResponseWriter,
 		sharedSize = httputils.BoolValue(r, "shared-size")
 	}
 
+	var (
+		limit  int
+		cursor string
+	)
+	if versions.GreaterThanOrEqualTo(version, "1.43") {
+		if l := r.Form.Get("limit"); l != "" {
+			var err error
+			limit, err = strconv.Atoi(l)
+			if err != nil {
+				return errdefs.InvalidParameter(errors.Wrapf(err, "invalid limit %q", l))
+			}
+		}
+		cursor = r.Form.Get("cursor")
+	}
+
 	images, err := ir.backend.Images(ctx, types.ImageListOptions{
 		All:        httputils.BoolValue(r, "all"),
 		Filters:    imageFilters,
 		SharedSize: sharedSize,
+		Limit:      limit,
+		Cursor:     cursor,
 	})
 	if err != nil {
 		return err
 	}
+	if limit > 0 && len(images) == limit {
+		last := images[len(images)-1]
+		w.Header().Set(pagination.NextCursorHeader, pagination.Cursor{Created: last.Created, ID: last.ID}.String())
+	}
 
 	return httputils.WriteJSON(w, http.StatusOK, images)
 }
@@ -376,6 +424,18 @@ func (ir *imageRouter) postImagesTag(ctx context.Context, w http.ResponseWriter,
 	return nil
 }
 
+func (ir *imageRouter) postImagesLabels(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	update, err := httputils.ReadLabelsUpdate(r)
+	if err != nil {
+		return err
+	}
+	id, labels, err := ir.backend.ImageUpdateLabels(ctx, vars["name"], update)
+	if err != nil {
+		return err
+	}
+	return httputils.WriteJSON(w, http.StatusOK, types.LabelsUpdateResponse{ID: id, Labels: labels})
+}
+
 func (ir *imageRouter) getImagesSearch(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
 	if err := httputils.ParseForm(r); err != nil {
 		return err
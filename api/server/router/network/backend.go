@@ -2,6 +2,7 @@ package network // import "github.com/docker/docker/api/server/router/network"
 
 import (
 	"context"
+	"time"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/filters"
@@ -19,6 +20,9 @@ type Backend interface {
 	DisconnectContainerFromNetwork(containerName string, networkName string, force bool) error
 	DeleteNetwork(networkID string) error
 	NetworksPrune(ctx context.Context, pruneFilters filters.Args) (*types.NetworksPruneReport, error)
+	NetworkUpdateLabels(id string, update types.LabelsUpdate) (map[string]string, error)
+	ReconcileIPAM(release bool) (*network.IPAMReconcileReport, error)
+	RebalanceNetwork(idOrName string, drain time.Duration) (*network.RebalanceReport, error)
 }
 
 // ClusterBackend is all the methods that need to be implemented
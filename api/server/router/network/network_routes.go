@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/docker/docker/api/server/httputils"
 	"github.com/docker/docker/api/types"
@@ -254,6 +255,13 @@ func (n *networkRouter) postNetworkConnect(ctx context.Context, w http.ResponseW
 		return err
 	}
 
+	if connect.EndpointConfig != nil && versions.LessThan(httputils.VersionFromContext(ctx), "1.43") {
+		// Ignore AliasOptions and HealthGatedDNS because they were added in
+		// API 1.43.
+		connect.EndpointConfig.AliasOptions = nil
+		connect.EndpointConfig.HealthGatedDNS = false
+	}
+
 	// Unlike other operations, we does not check ambiguity of the name/ID here.
 	// The reason is that, In case of attachable network in swarm scope, the actual local network
 	// may not be available at the time. At the same time, inside daemon `ConnectContainerToNetwork`
@@ -274,6 +282,46 @@ func (n *networkRouter) postNetworkDisconnect(ctx context.Context, w http.Respon
 	return n.backend.DisconnectContainerFromNetwork(disconnect.Container, vars["id"], disconnect.Force)
 }
 
+func (n *networkRouter) postNetworkLabels(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	update, err := httputils.ReadLabelsUpdate(r)
+	if err != nil {
+		return err
+	}
+	nw, err := n.findUniqueNetwork(vars["id"])
+	if err != nil {
+		return err
+	}
+	if nw.Scope == "swarm" {
+		return errdefs.InvalidParameter(errors.Errorf("the labels of swarm network %s cannot be updated", nw.Name))
+	}
+	labels, err := n.backend.NetworkUpdateLabels(nw.ID, update)
+	if err != nil {
+		return err
+	}
+	return httputils.WriteJSON(w, http.StatusOK, types.LabelsUpdateResponse{Labels: labels})
+}
+
+func (n *networkRouter) postNetworkRebalance(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+
+	var drain time.Duration
+	if v := r.Form.Get("drain"); v != "" {
+		seconds, err := strconv.Atoi(v)
+		if err != nil || seconds < 0 {
+			return errdefs.InvalidParameter(errors.Errorf("invalid drain period: %q", v))
+		}
+		drain = time.Duration(seconds) * time.Second
+	}
+
+	report, err := n.backend.RebalanceNetwork(vars["id"], drain)
+	if err != nil {
+		return err
+	}
+	return httputils.WriteJSON(w, http.StatusOK, report)
+}
+
 func (n *networkRouter) deleteNetwork(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
 	if err := httputils.ParseForm(r); err != nil {
 		return err
@@ -313,6 +361,18 @@ func (n *networkRouter) postNetworksPrune(ctx context.Context, w http.ResponseWr
 	return httputils.WriteJSON(w, http.StatusOK, pruneReport)
 }
 
+func (n *networkRouter) postNetworksIPAMReconcile(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+
+	report, err := n.backend.ReconcileIPAM(httputils.BoolValue(r, "release"))
+	if err != nil {
+		return err
+	}
+	return httputils.WriteJSON(w, http.StatusOK, report)
+}
+
 // findUniqueNetwork will search network across different scopes (both local and swarm).
 // NOTE: This findUniqueNetwork is different from FindNetwork in the daemon.
 // In case multiple networks have duplicate names, return error.
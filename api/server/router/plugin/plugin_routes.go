@@ -11,6 +11,7 @@ import (
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/registry"
+	"github.com/docker/docker/api/types/versions"
 	"github.com/docker/docker/pkg/ioutils"
 	"github.com/docker/docker/pkg/streamformatter"
 	"github.com/pkg/errors"
@@ -280,6 +281,11 @@ func (pr *pluginRouter) listPlugins(ctx context.Context, w http.ResponseWriter,
 	if err != nil {
 		return err
 	}
+	if versions.LessThan(httputils.VersionFromContext(ctx), "1.43") {
+		for i := range l {
+			l[i].Health = nil
+		}
+	}
 	return httputils.WriteJSON(w, http.StatusOK, l)
 }
 
@@ -288,5 +294,8 @@ func (pr *pluginRouter) inspectPlugin(ctx context.Context, w http.ResponseWriter
 	if err != nil {
 		return err
 	}
+	if versions.LessThan(httputils.VersionFromContext(ctx), "1.43") {
+		result.Health = nil
+	}
 	return httputils.WriteJSON(w, http.StatusOK, result)
 }
@@ -23,6 +23,7 @@ type Backend interface {
 	CreateService(types.ServiceSpec, string, bool) (*basictypes.ServiceCreateResponse, error)
 	UpdateService(string, uint64, types.ServiceSpec, basictypes.ServiceUpdateOptions, bool) (*basictypes.ServiceUpdateResponse, error)
 	RemoveService(string) error
+	RestartService(idOrName string, version uint64) error
 
 	ServiceLogs(context.Context, *backend.LogSelector, *basictypes.ContainerLogsOptions) (<-chan *backend.LogMessage, error)
 
@@ -39,10 +40,17 @@ type Backend interface {
 	RemoveSecret(idOrName string) error
 	GetSecret(id string) (types.Secret, error)
 	UpdateSecret(idOrName string, version uint64, spec types.SecretSpec) error
+	RotateSecret(idOrName string, spec types.SecretSpec) (string, error)
 
 	GetConfigs(opts basictypes.ConfigListOptions) ([]types.Config, error)
 	CreateConfig(s types.ConfigSpec) (string, error)
 	RemoveConfig(id string) error
 	GetConfig(id string) (types.Config, error)
 	UpdateConfig(idOrName string, version uint64, spec types.ConfigSpec) error
+	RotateConfig(idOrName string, spec types.ConfigSpec) (string, error)
+
+	DeployStack(spec types.StackSpec, options basictypes.StackDeployOptions) (*types.StackDeployResponse, error)
+	GetStacks() ([]types.Stack, error)
+	GetStack(name string) (types.Stack, error)
+	RemoveStack(name string) error
 }
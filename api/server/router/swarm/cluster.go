@@ -36,6 +36,7 @@ func (sr *swarmRouter) initRoutes() {
 		router.NewGetRoute("/services/{id}", sr.getService),
 		router.NewPostRoute("/services/create", sr.createService),
 		router.NewPostRoute("/services/{id}/update", sr.updateService),
+		router.NewPostRoute("/services/{id}/restart", sr.restartService),
 		router.NewDeleteRoute("/services/{id}", sr.removeService),
 		router.NewGetRoute("/services/{id}/logs", sr.getServiceLogs),
 
@@ -53,11 +54,18 @@ func (sr *swarmRouter) initRoutes() {
 		router.NewDeleteRoute("/secrets/{id}", sr.removeSecret),
 		router.NewGetRoute("/secrets/{id}", sr.getSecret),
 		router.NewPostRoute("/secrets/{id}/update", sr.updateSecret),
+		router.NewPostRoute("/secrets/{id}/rotate", sr.rotateSecret),
 
 		router.NewGetRoute("/configs", sr.getConfigs),
 		router.NewPostRoute("/configs/create", sr.createConfig),
 		router.NewDeleteRoute("/configs/{id}", sr.removeConfig),
 		router.NewGetRoute("/configs/{id}", sr.getConfig),
 		router.NewPostRoute("/configs/{id}/update", sr.updateConfig),
+		router.NewPostRoute("/configs/{id}/rotate", sr.rotateConfig),
+
+		router.NewGetRoute("/stacks", sr.getStacks),
+		router.NewPostRoute("/stacks", sr.deployStack),
+		router.NewGetRoute("/stacks/{name}", sr.getStack),
+		router.NewDeleteRoute("/stacks/{name}", sr.removeStack),
 	}
 }
@@ -260,6 +260,21 @@ func (sr *swarmRouter) updateService(ctx context.Context, w http.ResponseWriter,
 	return httputils.WriteJSON(w, http.StatusOK, resp)
 }
 
+func (sr *swarmRouter) restartService(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	rawVersion := r.URL.Query().Get("version")
+	version, err := strconv.ParseUint(rawVersion, 10, 64)
+	if err != nil {
+		err := fmt.Errorf("invalid service version '%s': %v", rawVersion, err)
+		return errdefs.InvalidParameter(err)
+	}
+
+	if err := sr.backend.RestartService(vars["id"], version); err != nil {
+		logrus.Errorf("Error restarting service %s: %v", vars["id"], err)
+		return err
+	}
+	return nil
+}
+
 func (sr *swarmRouter) removeService(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
 	if err := sr.backend.RemoveService(vars["id"]); err != nil {
 		logrus.Errorf("Error removing service %s: %v", vars["id"], err)
@@ -333,6 +348,11 @@ func (sr *swarmRouter) updateNode(ctx context.Context, w http.ResponseWriter, r
 		return errdefs.InvalidParameter(err)
 	}
 
+	// DrainOptions was added in API 1.43. Ignore this option on older API versions.
+	if versions.LessThan(httputils.VersionFromContext(ctx), "1.43") {
+		node.DrainOptions = nil
+	}
+
 	if err := sr.backend.UpdateNode(vars["id"], version, node); err != nil {
 		logrus.Errorf("Error updating node %s: %v", vars["id"], err)
 		return err
@@ -408,6 +428,9 @@ func (sr *swarmRouter) createSecret(ctx context.Context, w http.ResponseWriter,
 	if secret.Templating != nil && versions.LessThan(version, "1.37") {
 		return errdefs.InvalidParameter(errors.Errorf("secret templating is not supported on the specified API version: %s", version))
 	}
+	if secret.Provider != nil && versions.LessThan(version, "1.43") {
+		return errdefs.InvalidParameter(errors.Errorf("secret providers are not supported on the specified API version: %s", version))
+	}
 
 	id, err := sr.backend.CreateSecret(secret)
 	if err != nil {
@@ -453,6 +476,22 @@ func (sr *swarmRouter) updateSecret(ctx context.Context, w http.ResponseWriter,
 	return sr.backend.UpdateSecret(id, version, secret)
 }
 
+func (sr *swarmRouter) rotateSecret(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	var secret types.SecretSpec
+	if err := httputils.ReadJSON(r, &secret); err != nil {
+		return err
+	}
+
+	id, err := sr.backend.RotateSecret(vars["id"], secret)
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusCreated, &basictypes.SecretCreateResponse{
+		ID: id,
+	})
+}
+
 func (sr *swarmRouter) getConfigs(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
 	if err := httputils.ParseForm(r); err != nil {
 		return err
@@ -524,3 +563,76 @@ func (sr *swarmRouter) updateConfig(ctx context.Context, w http.ResponseWriter,
 	id := vars["id"]
 	return sr.backend.UpdateConfig(id, version, config)
 }
+
+func (sr *swarmRouter) rotateConfig(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	var config types.ConfigSpec
+	if err := httputils.ReadJSON(r, &config); err != nil {
+		return err
+	}
+
+	id, err := sr.backend.RotateConfig(vars["id"], config)
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusCreated, &basictypes.ConfigCreateResponse{
+		ID: id,
+	})
+}
+
+func (sr *swarmRouter) deployStack(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+	var spec types.StackSpec
+	if err := httputils.ReadJSON(r, &spec); err != nil {
+		return err
+	}
+
+	options := basictypes.StackDeployOptions{
+		// Get returns "" if the header does not exist
+		EncodedRegistryAuth: r.Header.Get(registry.AuthHeader),
+		Prune:               httputils.BoolValue(r, "prune"),
+	}
+	if v := httputils.VersionFromContext(ctx); v != "" {
+		for name, service := range spec.Services {
+			adjustForAPIVersion(v, &service)
+			spec.Services[name] = service
+		}
+	}
+	resp, err := sr.backend.DeployStack(spec, options)
+	if err != nil {
+		logrus.Errorf("Error deploying stack %s: %v", spec.Name, err)
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, resp)
+}
+
+func (sr *swarmRouter) getStacks(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	stacks, err := sr.backend.GetStacks()
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, stacks)
+}
+
+func (sr *swarmRouter) getStack(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	stack, err := sr.backend.GetStack(vars["name"])
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, stack)
+}
+
+func (sr *swarmRouter) removeStack(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := sr.backend.RemoveStack(vars["name"]); err != nil {
+		logrus.Errorf("Error removing stack %s: %v", vars["name"], err)
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+
+	return nil
+}
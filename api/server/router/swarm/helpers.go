@@ -118,4 +118,39 @@ func adjustForAPIVersion(cliVersion string, service *swarm.ServiceSpec) {
 		service.Mode.ReplicatedJob = nil
 		service.Mode.GlobalJob = nil
 	}
+
+	if versions.LessThan(cliVersion, "1.43") {
+		// Job schedules were introduced in API version 1.43.
+		if service.Mode.ReplicatedJob != nil {
+			service.Mode.ReplicatedJob.Schedule = nil
+		}
+		if service.Mode.GlobalJob != nil {
+			service.Mode.GlobalJob.Schedule = nil
+		}
+		if service.TaskTemplate.ContainerSpec != nil {
+			// Following the latest version of secrets and configs was
+			// introduced in API version 1.43.
+			for _, secret := range service.TaskTemplate.ContainerSpec.Secrets {
+				secret.Latest = false
+			}
+			for _, config := range service.TaskTemplate.ContainerSpec.Configs {
+				config.Latest = false
+			}
+			// Seccomp and AppArmor profiles were introduced in API version 1.43.
+			if p := service.TaskTemplate.ContainerSpec.Privileges; p != nil {
+				p.Seccomp = nil
+				p.AppArmor = nil
+			}
+			// The UID, GID, HugePages, and NoSwap tmpfs options were
+			// introduced in API version 1.43.
+			for _, m := range service.TaskTemplate.ContainerSpec.Mounts {
+				if o := m.TmpfsOptions; o != nil {
+					o.UID = nil
+					o.GID = nil
+					o.HugePages = ""
+					o.NoSwap = false
+				}
+			}
+		}
+	}
 }
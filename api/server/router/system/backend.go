@@ -21,17 +21,24 @@ type DiskUsageOptions struct {
 
 	// Volumes controls whether volume disk usage should be computed.
 	Volumes bool
+
+	// Networks controls whether network resource usage should be computed.
+	Networks bool
 }
 
 // Backend is the methods that need to be implemented to provide
 // system specific functionality.
 type Backend interface {
 	SystemInfo() *types.Info
+	StartupPhases() []types.StartupPhase
 	SystemVersion() types.Version
 	SystemDiskUsage(ctx context.Context, opts DiskUsageOptions) (*types.DiskUsage, error)
 	SubscribeToEvents(since, until time.Time, ef filters.Args) ([]events.Message, chan interface{})
 	UnsubscribeFromEvents(chan interface{})
+	Watch(ctx context.Context, typ events.Type, filter filters.Args, send func(types.WatchEvent) error) error
 	AuthenticateToRegistry(ctx context.Context, authConfig *registry.AuthConfig) (string, string, error)
+	ConfigReloadResult() (types.ConfigReload, error)
+	CheckConfig(data []byte) (types.ConfigCheck, error)
 }
 
 // ClusterBackend is all the methods that need to be implemented
@@ -0,0 +1,76 @@
+package system // import "github.com/docker/docker/api/server/router/system"
+
+import (
+	"sort"
+	"time"
+
+	"github.com/docker/docker/api/types"
+)
+
+// defaultBuilderName is the name of the builder embedded in the daemon, as
+// reported in the build cache usage.
+const defaultBuilderName = "default"
+
+// buildCacheAges are the upper bounds of the ranges of time since build cache
+// records were last used, by which their usage is broken down. The last range
+// has no upper bound.
+var buildCacheAges = []time.Duration{
+	24 * time.Hour,
+	7 * 24 * time.Hour,
+	30 * 24 * time.Hour,
+}
+
+// buildCacheUsage breaks down the usage of the build cache records of a
+// builder by type, and by the time since they were last used.
+func buildCacheUsage(builder string, records []*types.BuildCache, now time.Time) *types.BuildCacheUsage {
+	usage := &types.BuildCacheUsage{
+		Builder: builder,
+		ByType:  []types.BuildCacheTypeUsage{},
+		ByAge:   make([]types.BuildCacheAgeUsage, len(buildCacheAges)+1),
+	}
+	var minAge time.Duration
+	for i := range usage.ByAge {
+		usage.ByAge[i].MinAge = int64(minAge / time.Second)
+		if i < len(buildCacheAges) {
+			usage.ByAge[i].MaxAge = int64(buildCacheAges[i] / time.Second)
+			minAge = buildCacheAges[i]
+		}
+	}
+
+	byType := make(map[string]*types.BuildCacheTypeUsage)
+	for _, r := range records {
+		var reclaimable int64
+		if !r.InUse && !r.Shared {
+			reclaimable = r.Size
+		}
+		usage.Size += r.Size
+		usage.Reclaimable += reclaimable
+
+		t, ok := byType[r.Type]
+		if !ok {
+			t = &types.BuildCacheTypeUsage{Type: r.Type}
+			byType[r.Type] = t
+		}
+		t.Count++
+		t.Size += r.Size
+		t.Reclaimable += reclaimable
+
+		lastUsed := r.CreatedAt
+		if r.LastUsedAt != nil {
+			lastUsed = *r.LastUsedAt
+		}
+		age := now.Sub(lastUsed)
+		i := sort.Search(len(buildCacheAges), func(i int) bool { return age < buildCacheAges[i] })
+		usage.ByAge[i].Count++
+		usage.ByAge[i].Size += r.Size
+		usage.ByAge[i].Reclaimable += reclaimable
+	}
+
+	for _, t := range byType {
+		usage.ByType = append(usage.ByType, *t)
+	}
+	sort.Slice(usage.ByType, func(i, j int) bool {
+		return usage.ByType[i].Type < usage.ByType[j].Type
+	})
+	return usage
+}
@@ -0,0 +1,44 @@
+package system // import "github.com/docker/docker/api/server/router/system"
+
+import (
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestBuildCacheUsage(t *testing.T) {
+	now := time.Now()
+	hoursAgo := func(h int) *time.Time {
+		t := now.Add(-time.Duration(h) * time.Hour)
+		return &t
+	}
+	records := []*types.BuildCache{
+		{Type: "regular", Size: 10, InUse: true, CreatedAt: *hoursAgo(2)},
+		{Type: "regular", Size: 20, CreatedAt: *hoursAgo(100), LastUsedAt: hoursAgo(1)},
+		{Type: "source.local", Size: 30, Shared: true, CreatedAt: *hoursAgo(100), LastUsedAt: hoursAgo(48)},
+		{Type: "exec.cachemount", Size: 40, CreatedAt: *hoursAgo(2000), LastUsedAt: hoursAgo(1000)},
+	}
+
+	usage := buildCacheUsage(defaultBuilderName, records, now)
+	assert.Check(t, is.Equal(usage.Builder, "default"))
+	assert.Check(t, is.Equal(usage.Size, int64(100)))
+	assert.Check(t, is.Equal(usage.Reclaimable, int64(60)))
+	assert.Check(t, is.DeepEqual(usage.ByType, []types.BuildCacheTypeUsage{
+		{Type: "exec.cachemount", Count: 1, Size: 40, Reclaimable: 40},
+		{Type: "regular", Count: 2, Size: 30, Reclaimable: 20},
+		{Type: "source.local", Count: 1, Size: 30},
+	}))
+	assert.Check(t, is.DeepEqual(usage.ByAge, []types.BuildCacheAgeUsage{
+		{MinAge: 0, MaxAge: 86400, Count: 2, Size: 30, Reclaimable: 20},
+		{MinAge: 86400, MaxAge: 604800, Count: 1, Size: 30},
+		{MinAge: 604800, MaxAge: 2592000},
+		{MinAge: 2592000, Count: 1, Size: 40, Reclaimable: 40},
+	}))
+
+	usage = buildCacheUsage(defaultBuilderName, nil, now)
+	assert.Check(t, is.Len(usage.ByType, 0))
+	assert.Check(t, is.Len(usage.ByAge, 4))
+}
@@ -29,9 +29,12 @@ func NewRouter(b Backend, c ClusterBackend, builder *buildkit.Builder, features
 		router.NewGetRoute("/_ping", r.pingHandler),
 		router.NewHeadRoute("/_ping", r.pingHandler),
 		router.NewGetRoute("/events", r.getEvents),
+		router.NewGetRoute("/events/watch", r.getWatch),
 		router.NewGetRoute("/info", r.getInfo),
 		router.NewGetRoute("/version", r.getVersion),
 		router.NewGetRoute("/system/df", r.getDiskUsage),
+		router.NewGetRoute("/system/config/reload", r.getConfigReload),
+		router.NewPostRoute("/system/config/check", r.postConfigCheck),
 		router.NewPostRoute("/auth", r.postAuth),
 	}
 
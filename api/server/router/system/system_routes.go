@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"time"
 
@@ -16,6 +17,7 @@ import (
 	"github.com/docker/docker/api/types/swarm"
 	timetypes "github.com/docker/docker/api/types/time"
 	"github.com/docker/docker/api/types/versions"
+	"github.com/docker/docker/errdefs"
 	"github.com/docker/docker/pkg/ioutils"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -97,6 +99,9 @@ func (s *systemRouter) getInfo(ctx context.Context, w http.ResponseWriter, r *ht
 	if versions.GreaterThanOrEqualTo(version, "1.42") {
 		info.KernelMemory = false
 	}
+	if versions.GreaterThanOrEqualTo(version, "1.43") && httputils.BoolValue(r, "verbose") {
+		info.StartupPhases = s.backend.StartupPhases()
+	}
 	return httputils.WriteJSON(w, http.StatusOK, info)
 }
 
@@ -106,6 +111,26 @@ func (s *systemRouter) getVersion(ctx context.Context, w http.ResponseWriter, r
 	return httputils.WriteJSON(w, http.StatusOK, info)
 }
 
+func (s *systemRouter) getConfigReload(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	result, err := s.backend.ConfigReloadResult()
+	if err != nil {
+		return err
+	}
+	return httputils.WriteJSON(w, http.StatusOK, result)
+}
+
+func (s *systemRouter) postConfigCheck(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	result, err := s.backend.CheckConfig(data)
+	if err != nil {
+		return err
+	}
+	return httputils.WriteJSON(w, http.StatusOK, result)
+}
+
 func (s *systemRouter) getDiskUsage(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
 	if err := httputils.ParseForm(r); err != nil {
 		return err
@@ -113,10 +138,11 @@ func (s *systemRouter) getDiskUsage(ctx context.Context, w http.ResponseWriter,
 
 	version := httputils.VersionFromContext(ctx)
 
-	var getContainers, getImages, getVolumes, getBuildCache bool
+	var getContainers, getImages, getVolumes, getBuildCache, getNetworks bool
 	typeStrs, ok := r.Form["type"]
 	if versions.LessThan(version, "1.42") || !ok {
 		getContainers, getImages, getVolumes, getBuildCache = true, true, true, s.builder != nil
+		getNetworks = versions.GreaterThanOrEqualTo(version, "1.43")
 	} else {
 		for _, typ := range typeStrs {
 			switch types.DiskUsageObject(typ) {
@@ -128,6 +154,11 @@ func (s *systemRouter) getDiskUsage(ctx context.Context, w http.ResponseWriter,
 				getVolumes = true
 			case types.BuildCacheObject:
 				getBuildCache = true
+			case types.NetworkObject:
+				if versions.LessThan(version, "1.43") {
+					return invalidRequestError{Err: fmt.Errorf("unknown object type: %s", typ)}
+				}
+				getNetworks = true
 			default:
 				return invalidRequestError{Err: fmt.Errorf("unknown object type: %s", typ)}
 			}
@@ -137,13 +168,14 @@ func (s *systemRouter) getDiskUsage(ctx context.Context, w http.ResponseWriter,
 	eg, ctx := errgroup.WithContext(ctx)
 
 	var systemDiskUsage *types.DiskUsage
-	if getContainers || getImages || getVolumes {
+	if getContainers || getImages || getVolumes || getNetworks {
 		eg.Go(func() error {
 			var err error
 			systemDiskUsage, err = s.backend.SystemDiskUsage(ctx, DiskUsageOptions{
 				Containers: getContainers,
 				Images:     getImages,
 				Volumes:    getVolumes,
+				Networks:   getNetworks,
 			})
 			return err
 		})
@@ -190,11 +222,15 @@ func (s *systemRouter) getDiskUsage(ctx context.Context, w http.ResponseWriter,
 		BuildCache:  buildCache,
 		BuilderSize: builderSize,
 	}
+	if getBuildCache && versions.GreaterThanOrEqualTo(version, "1.43") {
+		du.BuildCacheUsage = []*types.BuildCacheUsage{buildCacheUsage(defaultBuilderName, buildCache, time.Now())}
+	}
 	if systemDiskUsage != nil {
 		du.LayersSize = systemDiskUsage.LayersSize
 		du.Images = systemDiskUsage.Images
 		du.Containers = systemDiskUsage.Containers
 		du.Volumes = systemDiskUsage.Volumes
+		du.Networks = systemDiskUsage.Networks
 	}
 	return httputils.WriteJSON(w, http.StatusOK, du)
 }
@@ -248,6 +284,9 @@ func (s *systemRouter) getEvents(ctx context.Context, w http.ResponseWriter, r *
 	if err != nil {
 		return err
 	}
+	if err := ef.ValidateLabelSelectors("label"); err != nil {
+		return err
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	output := ioutils.NewWriteFlusher(w)
@@ -289,6 +328,34 @@ func (s *systemRouter) getEvents(ctx context.Context, w http.ResponseWriter, r *
 	}
 }
 
+// getWatch streams the state changes of the objects of the type of the
+// request.
+func (s *systemRouter) getWatch(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+	typ := events.Type(r.Form.Get("type"))
+	switch typ {
+	case events.ContainerEventType, events.ImageEventType, events.VolumeEventType, events.NetworkEventType:
+	default:
+		return errdefs.InvalidParameter(errors.Errorf("cannot watch objects of type %q", typ))
+	}
+	filter, err := filters.FromJSON(r.Form.Get("filters"))
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	output := ioutils.NewWriteFlusher(w)
+	defer output.Close()
+	output.Flush()
+
+	enc := json.NewEncoder(output)
+	return s.backend.Watch(ctx, typ, filter, func(ev types.WatchEvent) error {
+		return enc.Encode(ev)
+	})
+}
+
 func (s *systemRouter) postAuth(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
 	var config *registry.AuthConfig
 	err := json.NewDecoder(r.Body).Decode(&config)
@@ -2,6 +2,7 @@ package volume // import "github.com/docker/docker/api/server/router/volume"
 
 import (
 	"context"
+	"io"
 
 	"github.com/docker/docker/volume/service/opts"
 	// TODO return types need to be refactored into pkg
@@ -18,6 +19,16 @@ type Backend interface {
 	Create(ctx context.Context, name, driverName string, opts ...opts.CreateOption) (*volume.Volume, error)
 	Remove(ctx context.Context, name string, opts ...opts.RemoveOption) error
 	Prune(ctx context.Context, pruneFilters filters.Args) (*types.VolumesPruneReport, error)
+	PruneAsync(ctx context.Context, pruneFilters filters.Args) (*volume.PruneJob, error)
+	PruneJob(ctx context.Context, id string) (*volume.PruneJob, error)
+	CancelPruneJob(ctx context.Context, id string) error
+	CreateSnapshot(ctx context.Context, name string, options volume.SnapshotCreateOptions) (*volume.Snapshot, error)
+	ListSnapshots(ctx context.Context, name string) ([]volume.Snapshot, error)
+	RemoveSnapshot(ctx context.Context, name, snapshot string) error
+	RestoreSnapshot(ctx context.Context, name, snapshot string) error
+	Export(ctx context.Context, name string, options volume.ExportOptions) (io.ReadCloser, error)
+	Import(ctx context.Context, name string, in io.Reader) error
+	UpdateLabels(ctx context.Context, name string, update types.LabelsUpdate) (map[string]string, error)
 }
 
 // ClusterBackend is the backend used for Swarm Cluster Volumes. Regular
@@ -30,5 +41,8 @@ type ClusterBackend interface {
 	CreateVolume(volume volume.CreateOptions) (*volume.Volume, error)
 	RemoveVolume(nameOrID string, force bool) error
 	UpdateVolume(nameOrID string, version uint64, volume volume.UpdateOptions) error
+	CreateVolumeSnapshot(nameOrID string, options volume.SnapshotCreateOptions) (*volume.Snapshot, error)
+	GetVolumeSnapshots(nameOrID string) ([]volume.Snapshot, error)
+	RemoveVolumeSnapshot(nameOrID, snapshot string) error
 	IsManager() bool
 }
@@ -26,6 +26,17 @@ func (r *volumeRouter) Routes() []router.Route {
 
 func (r *volumeRouter) initRoutes() {
 	r.routes = []router.Route{
+		// Snapshot, archive, labels, and prune job routes are registered first, so that they are not
+		// matched by the "/volumes/{name:.*}" routes.
+		router.NewGetRoute("/volumes/{name:.*}/snapshots", r.getVolumeSnapshots),
+		router.NewPostRoute("/volumes/{name:.*}/snapshot", r.postVolumeSnapshot),
+		router.NewPostRoute("/volumes/{name:.*}/snapshots/{snapshot}/restore", r.postVolumeSnapshotRestore),
+		router.NewDeleteRoute("/volumes/{name:.*}/snapshots/{snapshot}", r.deleteVolumeSnapshot),
+		router.NewGetRoute("/volumes/{name:.*}/export", r.getVolumeExport),
+		router.NewPutRoute("/volumes/{name:.*}/import", r.putVolumeImport),
+		router.NewPostRoute("/volumes/{name:.*}/labels", r.postVolumeLabels),
+		router.NewGetRoute("/volumes/prune/{id}", r.getVolumesPruneJob),
+		router.NewDeleteRoute("/volumes/prune/{id}", r.deleteVolumesPruneJob),
 		// GET
 		router.NewGetRoute("/volumes", r.getVolumesList),
 		router.NewGetRoute("/volumes/{name:.*}", r.getVolumeByName),
@@ -3,10 +3,12 @@ package volume // import "github.com/docker/docker/api/server/router/volume"
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 
 	"github.com/docker/docker/api/server/httputils"
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/versions"
 	"github.com/docker/docker/api/types/volume"
@@ -20,6 +22,22 @@ const (
 	// clusterVolumesVersion defines the API version that swarm cluster volume
 	// functionality was introduced. avoids the use of magic numbers.
 	clusterVolumesVersion = "1.42"
+
+	// volumeFromVersion defines the API version that creating a volume from
+	// another volume or snapshot was introduced.
+	volumeFromVersion = "1.43"
+
+	// volumeImageVersion defines the API version that creating a volume from
+	// an image was introduced.
+	volumeImageVersion = "1.43"
+
+	// pruneAsyncVersion defines the API version that asynchronous volume
+	// prune was introduced.
+	pruneAsyncVersion = "1.43"
+
+	// clusterVolumeSnapshotsVersion defines the API version that snapshots
+	// of cluster volumes were introduced.
+	clusterVolumeSnapshotsVersion = "1.43"
 )
 
 func (v *volumeRouter) getVolumesList(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
@@ -117,10 +135,21 @@ func (v *volumeRouter) postVolumesCreate(ctx context.Context, w http.ResponseWri
 	// should not break anything.
 	if req.ClusterVolumeSpec != nil && versions.GreaterThanOrEqualTo(version, clusterVolumesVersion) {
 		logrus.Debug("using cluster volume")
+		if versions.LessThan(version, clusterVolumeSnapshotsVersion) {
+			req.From = ""
+			req.ClusterVolumeSpec.SnapshotSchedule = nil
+		}
 		vol, err = v.cluster.CreateVolume(req)
 	} else {
 		logrus.Debug("using regular volume")
-		vol, err = v.backend.Create(ctx, req.Name, req.Driver, opts.WithCreateOptions(req.DriverOpts), opts.WithCreateLabels(req.Labels))
+		createOpts := []opts.CreateOption{opts.WithCreateOptions(req.DriverOpts), opts.WithCreateLabels(req.Labels)}
+		if req.From != "" && versions.GreaterThanOrEqualTo(version, volumeFromVersion) {
+			createOpts = append(createOpts, opts.WithCreateFrom(req.From))
+		}
+		if req.Image != "" && versions.GreaterThanOrEqualTo(version, volumeImageVersion) {
+			createOpts = append(createOpts, opts.WithCreateImage(req.Image))
+		}
+		vol, err = v.backend.Create(ctx, req.Name, req.Driver, createOpts...)
 	}
 
 	if err != nil {
@@ -149,10 +178,28 @@ func (v *volumeRouter) putVolumesUpdate(ctx context.Context, w http.ResponseWrit
 	if err := httputils.ReadJSON(r, &req); err != nil {
 		return err
 	}
+	if req.Spec != nil && versions.LessThan(httputils.VersionFromContext(ctx), clusterVolumeSnapshotsVersion) {
+		req.Spec.SnapshotSchedule = nil
+	}
 
 	return v.cluster.UpdateVolume(vars["name"], version, req)
 }
 
+func (v *volumeRouter) postVolumeLabels(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	update, err := httputils.ReadLabelsUpdate(r)
+	if err != nil {
+		return err
+	}
+	if v.isClusterVolume(ctx, vars["name"]) {
+		return errdefs.InvalidParameter(errors.New("the labels of cluster volumes are updated with the volume spec"))
+	}
+	labels, err := v.backend.UpdateLabels(ctx, vars["name"], update)
+	if err != nil {
+		return err
+	}
+	return httputils.WriteJSON(w, http.StatusOK, types.LabelsUpdateResponse{Labels: labels})
+}
+
 func (v *volumeRouter) deleteVolumes(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
 	if err := httputils.ParseForm(r); err != nil {
 		return err
@@ -198,9 +245,140 @@ func (v *volumeRouter) postVolumesPrune(ctx context.Context, w http.ResponseWrit
 		pruneFilters.Add("all", "true")
 	}
 
+	if versions.GreaterThanOrEqualTo(httputils.VersionFromContext(ctx), pruneAsyncVersion) && httputils.BoolValue(r, "async") {
+		job, err := v.backend.PruneAsync(ctx, pruneFilters)
+		if err != nil {
+			return err
+		}
+		return httputils.WriteJSON(w, http.StatusAccepted, job)
+	}
+
 	pruneReport, err := v.backend.Prune(ctx, pruneFilters)
 	if err != nil {
 		return err
 	}
 	return httputils.WriteJSON(w, http.StatusOK, pruneReport)
 }
+
+func (v *volumeRouter) getVolumesPruneJob(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	job, err := v.backend.PruneJob(ctx, vars["id"])
+	if err != nil {
+		return err
+	}
+	return httputils.WriteJSON(w, http.StatusOK, job)
+}
+
+func (v *volumeRouter) deleteVolumesPruneJob(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := v.backend.CancelPruneJob(ctx, vars["id"]); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func (v *volumeRouter) postVolumeSnapshot(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+
+	var req volume.SnapshotCreateOptions
+	if err := httputils.ReadJSON(r, &req); err != nil {
+		return err
+	}
+	var (
+		snap *volume.Snapshot
+		err  error
+	)
+	if v.isClusterVolume(ctx, vars["name"]) {
+		snap, err = v.cluster.CreateVolumeSnapshot(vars["name"], req)
+	} else {
+		snap, err = v.backend.CreateSnapshot(ctx, vars["name"], req)
+	}
+	if err != nil {
+		return err
+	}
+	return httputils.WriteJSON(w, http.StatusCreated, snap)
+}
+
+func (v *volumeRouter) getVolumeSnapshots(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	var (
+		snapshots []volume.Snapshot
+		err       error
+	)
+	if v.isClusterVolume(ctx, vars["name"]) {
+		snapshots, err = v.cluster.GetVolumeSnapshots(vars["name"])
+	} else {
+		snapshots, err = v.backend.ListSnapshots(ctx, vars["name"])
+	}
+	if err != nil {
+		return err
+	}
+	return httputils.WriteJSON(w, http.StatusOK, snapshots)
+}
+
+func (v *volumeRouter) deleteVolumeSnapshot(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	var err error
+	if v.isClusterVolume(ctx, vars["name"]) {
+		err = v.cluster.RemoveVolumeSnapshot(vars["name"], vars["snapshot"])
+	} else {
+		err = v.backend.RemoveSnapshot(ctx, vars["name"], vars["snapshot"])
+	}
+	if err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func (v *volumeRouter) postVolumeSnapshotRestore(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if v.isClusterVolume(ctx, vars["name"]) {
+		return errdefs.NotImplemented(errors.New("cluster volumes cannot be restored in place: create a new volume from the snapshot instead"))
+	}
+	if err := v.backend.RestoreSnapshot(ctx, vars["name"], vars["snapshot"]); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// isClusterVolume returns whether a volume is a cluster volume: that is, no
+// local volume has the given name, and the cluster has a volume with the
+// given name or ID.
+func (v *volumeRouter) isClusterVolume(ctx context.Context, name string) bool {
+	version := httputils.VersionFromContext(ctx)
+	if !versions.GreaterThanOrEqualTo(version, clusterVolumeSnapshotsVersion) || !v.cluster.IsManager() {
+		return false
+	}
+	if _, err := v.backend.Get(ctx, name); !errdefs.IsNotFound(err) {
+		return false
+	}
+	_, err := v.cluster.GetVolume(name)
+	return err == nil
+}
+
+func (v *volumeRouter) getVolumeExport(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+
+	rc, err := v.backend.Export(ctx, vars["name"], volume.ExportOptions{
+		Compression: r.Form.Get("compression"),
+		Quiesce:     httputils.BoolValue(r, "quiesce"),
+	})
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	w.Header().Set("Content-Type", "application/x-tar")
+	_, err = io.Copy(w, rc)
+	return err
+}
+
+func (v *volumeRouter) putVolumeImport(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := v.backend.Import(ctx, vars["name"], r.Body); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http/httptest"
 	"testing"
 
@@ -577,6 +578,108 @@ func TestVolumeRemoveSwarmForce(t *testing.T) {
 	assert.Equal(t, len(c.volumes), 0)
 }
 
+func TestClusterVolumeSnapshots(t *testing.T) {
+	b := &fakeVolumeBackend{
+		volumes: map[string]*volume.Volume{
+			"local": {Name: "local", Driver: "local"},
+		},
+	}
+	c := &fakeClusterBackend{
+		swarm:   true,
+		manager: true,
+		volumes: map[string]*volume.Volume{
+			"vol1": {
+				Name:          "vol1",
+				Driver:        "someCSI",
+				ClusterVolume: &volume.ClusterVolume{ID: "vol1id"},
+			},
+		},
+	}
+	v := &volumeRouter{backend: b, cluster: c}
+	ctx := context.WithValue(context.Background(), httputils.APIVersionKey{}, clusterVolumeSnapshotsVersion)
+
+	buf := bytes.Buffer{}
+	json.NewEncoder(&buf).Encode(volume.SnapshotCreateOptions{Name: "snap1"})
+	req := httptest.NewRequest("POST", "/volumes/vol1/snapshot", &buf)
+	req.Header.Add("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	err := v.postVolumeSnapshot(ctx, resp, req, map[string]string{"name": "vol1"})
+	assert.NilError(t, err)
+	assert.Equal(t, len(c.snapshots["vol1"]), 1)
+
+	req = httptest.NewRequest("GET", "/volumes/vol1/snapshots", nil)
+	resp = httptest.NewRecorder()
+	err = v.getVolumeSnapshots(ctx, resp, req, map[string]string{"name": "vol1"})
+	assert.NilError(t, err)
+	var snapshots []volume.Snapshot
+	assert.NilError(t, json.NewDecoder(resp.Result().Body).Decode(&snapshots))
+	assert.Equal(t, len(snapshots), 1)
+	assert.Equal(t, snapshots[0].Name, "snap1")
+
+	req = httptest.NewRequest("POST", "/volumes/vol1/snapshots/snap1/restore", nil)
+	resp = httptest.NewRecorder()
+	err = v.postVolumeSnapshotRestore(ctx, resp, req, map[string]string{"name": "vol1", "snapshot": "snap1"})
+	assert.Assert(t, errdefs.IsNotImplemented(err))
+
+	req = httptest.NewRequest("DELETE", "/volumes/vol1/snapshots/snap1", nil)
+	resp = httptest.NewRecorder()
+	err = v.deleteVolumeSnapshot(ctx, resp, req, map[string]string{"name": "vol1", "snapshot": "snap1"})
+	assert.NilError(t, err)
+	assert.Equal(t, len(c.snapshots["vol1"]), 0)
+
+	// snapshots of local volumes are still handled by the volume service
+	req = httptest.NewRequest("GET", "/volumes/local/snapshots", nil)
+	resp = httptest.NewRecorder()
+	err = v.getVolumeSnapshots(ctx, resp, req, map[string]string{"name": "local"})
+	assert.Assert(t, errdefs.IsNotImplemented(err))
+}
+
+func TestPostVolumeLabels(t *testing.T) {
+	b := &fakeVolumeBackend{
+		volumes: map[string]*volume.Volume{
+			"local": {Name: "local", Driver: "local", Labels: map[string]string{"a": "1"}},
+		},
+	}
+	c := &fakeClusterBackend{
+		swarm:   true,
+		manager: true,
+		volumes: map[string]*volume.Volume{
+			"vol1": {
+				Name:          "vol1",
+				Driver:        "someCSI",
+				ClusterVolume: &volume.ClusterVolume{ID: "vol1id"},
+			},
+		},
+	}
+	v := &volumeRouter{backend: b, cluster: c}
+	ctx := context.WithValue(context.Background(), httputils.APIVersionKey{}, "1.43")
+
+	postLabels := func(name string, update types.LabelsUpdate) (*httptest.ResponseRecorder, error) {
+		buf := bytes.Buffer{}
+		json.NewEncoder(&buf).Encode(update)
+		req := httptest.NewRequest("POST", "/volumes/"+name+"/labels", &buf)
+		req.Header.Add("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+		return resp, v.postVolumeLabels(ctx, resp, req, map[string]string{"name": name})
+	}
+
+	resp, err := postLabels("local", types.LabelsUpdate{Add: map[string]string{"b": "2"}, Remove: []string{"a"}})
+	assert.NilError(t, err)
+	var res types.LabelsUpdateResponse
+	assert.NilError(t, json.NewDecoder(resp.Result().Body).Decode(&res))
+	assert.DeepEqual(t, res.Labels, map[string]string{"b": "2"})
+	assert.DeepEqual(t, b.volumes["local"].Labels, map[string]string{"b": "2"})
+
+	_, err = postLabels("local", types.LabelsUpdate{})
+	assert.Assert(t, errdefs.IsInvalidParameter(err))
+
+	_, err = postLabels("vol1", types.LabelsUpdate{Add: map[string]string{"b": "2"}})
+	assert.Assert(t, errdefs.IsInvalidParameter(err))
+
+	_, err = postLabels("notReal", types.LabelsUpdate{Add: map[string]string{"b": "2"}})
+	assert.Assert(t, errdefs.IsNotFound(err))
+}
+
 type fakeVolumeBackend struct {
 	volumes map[string]*volume.Volume
 }
@@ -640,11 +743,57 @@ func (b *fakeVolumeBackend) Prune(_ context.Context, _ filters.Args) (*types.Vol
 	return nil, nil
 }
 
+func (b *fakeVolumeBackend) CreateSnapshot(_ context.Context, name string, options volume.SnapshotCreateOptions) (*volume.Snapshot, error) {
+	return nil, errdefs.NotImplemented(fmt.Errorf("not implemented"))
+}
+
+func (b *fakeVolumeBackend) ListSnapshots(_ context.Context, name string) ([]volume.Snapshot, error) {
+	return nil, errdefs.NotImplemented(fmt.Errorf("not implemented"))
+}
+
+func (b *fakeVolumeBackend) RemoveSnapshot(_ context.Context, name, snapshot string) error {
+	return errdefs.NotImplemented(fmt.Errorf("not implemented"))
+}
+
+func (b *fakeVolumeBackend) RestoreSnapshot(_ context.Context, name, snapshot string) error {
+	return errdefs.NotImplemented(fmt.Errorf("not implemented"))
+}
+
+func (b *fakeVolumeBackend) Export(_ context.Context, name string, options volume.ExportOptions) (io.ReadCloser, error) {
+	return nil, errdefs.NotImplemented(fmt.Errorf("not implemented"))
+}
+
+func (b *fakeVolumeBackend) Import(_ context.Context, name string, in io.Reader) error {
+	return errdefs.NotImplemented(fmt.Errorf("not implemented"))
+}
+
+func (b *fakeVolumeBackend) UpdateLabels(_ context.Context, name string, update types.LabelsUpdate) (map[string]string, error) {
+	v, ok := b.volumes[name]
+	if !ok {
+		return nil, errdefs.NotFound(fmt.Errorf("volume %s not found", name))
+	}
+	v.Labels = update.Apply(v.Labels)
+	return v.Labels, nil
+}
+
+func (b *fakeVolumeBackend) PruneAsync(_ context.Context, _ filters.Args) (*volume.PruneJob, error) {
+	return nil, errdefs.NotImplemented(fmt.Errorf("not implemented"))
+}
+
+func (b *fakeVolumeBackend) PruneJob(_ context.Context, id string) (*volume.PruneJob, error) {
+	return nil, errdefs.NotImplemented(fmt.Errorf("not implemented"))
+}
+
+func (b *fakeVolumeBackend) CancelPruneJob(_ context.Context, id string) error {
+	return errdefs.NotImplemented(fmt.Errorf("not implemented"))
+}
+
 type fakeClusterBackend struct {
-	swarm   bool
-	manager bool
-	idCount int
-	volumes map[string]*volume.Volume
+	swarm     bool
+	manager   bool
+	idCount   int
+	volumes   map[string]*volume.Volume
+	snapshots map[string][]volume.Snapshot
 }
 
 func (c *fakeClusterBackend) checkSwarm() error {
@@ -758,3 +907,45 @@ func (c *fakeClusterBackend) UpdateVolume(nameOrID string, version uint64, _ vol
 
 	return nil
 }
+
+func (c *fakeClusterBackend) CreateVolumeSnapshot(nameOrID string, options volume.SnapshotCreateOptions) (*volume.Snapshot, error) {
+	if err := c.checkSwarm(); err != nil {
+		return nil, err
+	}
+
+	v, ok := c.volumes[nameOrID]
+	if !ok {
+		return nil, errdefs.NotFound(fmt.Errorf("volume %s not found", nameOrID))
+	}
+	snap := volume.Snapshot{Name: options.Name, Volume: v.Name, Driver: v.Driver}
+	if c.snapshots == nil {
+		c.snapshots = map[string][]volume.Snapshot{}
+	}
+	c.snapshots[v.Name] = append(c.snapshots[v.Name], snap)
+	return &snap, nil
+}
+
+func (c *fakeClusterBackend) GetVolumeSnapshots(nameOrID string) ([]volume.Snapshot, error) {
+	if err := c.checkSwarm(); err != nil {
+		return nil, err
+	}
+
+	if _, ok := c.volumes[nameOrID]; !ok {
+		return nil, errdefs.NotFound(fmt.Errorf("volume %s not found", nameOrID))
+	}
+	return c.snapshots[nameOrID], nil
+}
+
+func (c *fakeClusterBackend) RemoveVolumeSnapshot(nameOrID, snapshot string) error {
+	if err := c.checkSwarm(); err != nil {
+		return err
+	}
+
+	for i, snap := range c.snapshots[nameOrID] {
+		if snap.Name == snapshot {
+			c.snapshots[nameOrID] = append(c.snapshots[nameOrID][:i], c.snapshots[nameOrID][i+1:]...)
+			return nil
+		}
+	}
+	return errdefs.NotFound(fmt.Errorf("no such snapshot: %s", snapshot))
+}
@@ -6,6 +6,7 @@ import (
 	"net"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/docker/docker/api/server/httpstatus"
@@ -38,14 +39,46 @@ type Server struct {
 	servers     []*HTTPServer
 	routers     []router.Router
 	middlewares []middleware.Middleware
+
+	mu sync.Mutex
+	// conns is the number of open connections, not counting the hijacked
+	// connections, and lastActive is the time of the last change of state
+	// of a connection.
+	conns      int
+	lastActive time.Time
 }
 
 // New returns a new instance of the server based on the specified configuration.
 // It allocates resources which will be needed for ServeAPI(ports, unix-sockets).
 func New(cfg *Config) *Server {
 	return &Server{
-		cfg: cfg,
+		cfg:        cfg,
+		lastActive: time.Now(),
+	}
+}
+
+func (s *Server) trackConn(_ net.Conn, state http.ConnState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch state {
+	case http.StateNew:
+		s.conns++
+	case http.StateHijacked, http.StateClosed:
+		s.conns--
+	}
+	s.lastActive = time.Now()
+}
+
+// IdleTime returns how long the server has had no open connections, or zero
+// if it has open connections. Hijacked connections, such as those attached to
+// containers, are not counted.
+func (s *Server) IdleTime() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conns > 0 {
+		return 0
 	}
+	return time.Since(s.lastActive)
 }
 
 // UseMiddleware appends a new middleware to the request chain.
@@ -61,6 +94,8 @@ func (s *Server) Accept(addr string, listeners ...net.Listener) {
 			srv: &http.Server{
 				Addr:              addr,
 				ReadHeaderTimeout: 5 * time.Minute, // "G112: Potential Slowloris Attack (gosec)"; not a real concern for our use, so setting a long timeout.
+				ConnContext:       httputils.WithConn,
+				ConnState:         s.trackConn,
 			},
 			l: listener,
 		}
@@ -2,14 +2,17 @@ package server // import "github.com/docker/docker/api/server"
 
 import (
 	"context"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/docker/docker/api"
 	"github.com/docker/docker/api/server/httputils"
 	"github.com/docker/docker/api/server/middleware"
+	"github.com/gorilla/mux"
 )
 
 func TestMiddlewares(t *testing.T) {
@@ -43,3 +46,40 @@ func TestMiddlewares(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestIdleTime(t *testing.T) {
+	srv := New(&Config{})
+	c1, c2 := net.Pipe()
+	defer c1.Close()
+	defer c2.Close()
+
+	srv.trackConn(c1, http.StateNew)
+	if idle := srv.IdleTime(); idle != 0 {
+		t.Fatalf("Expected no idle time with an open connection, got %s", idle)
+	}
+	srv.trackConn(c1, http.StateActive)
+	srv.trackConn(c1, http.StateHijacked)
+	srv.lastActive = srv.lastActive.Add(-time.Minute)
+	if idle := srv.IdleTime(); idle < time.Minute {
+		t.Fatalf("Expected idle time once the connection is hijacked, got %s", idle)
+	}
+}
+
+func TestErrorHandlerCode(t *testing.T) {
+	for _, tc := range []struct {
+		version  string
+		expected string
+	}{
+		{version: "", expected: `"code":"not_found"`},
+		{version: "1.43", expected: `"code":"not_found"`},
+		{version: "1.42", expected: `{"message":"page not found"}`},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/foo", nil)
+		req = mux.SetURLVars(req, map[string]string{"version": tc.version})
+		resp := httptest.NewRecorder()
+		makeErrorHandler(pageNotFoundError{})(resp, req)
+		if !strings.Contains(resp.Body.String(), tc.expected) {
+			t.Errorf("version %q: expected %s in the response, got %s", tc.version, tc.expected, resp.Body.String())
+		}
+	}
+}
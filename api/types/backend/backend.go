@@ -5,7 +5,9 @@ import (
 	"io"
 	"time"
 
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
 )
 
 // ContainerAttachConfig holds the streams to use when connecting to a container to view logs.
@@ -73,6 +75,15 @@ type ContainerStatsConfig struct {
 	Version   string
 }
 
+// ContainersStatsConfig holds information for configuring the runtime
+// behavior of a backend.ContainersStats() call.
+type ContainersStatsConfig struct {
+	Stream    bool
+	Interval  time.Duration
+	Filters   filters.Args
+	OutStream io.Writer
+}
+
 // ExecInspect holds information about a running process started
 // with docker exec.
 type ExecInspect struct {
@@ -87,6 +98,14 @@ type ExecInspect struct {
 	ContainerID   string
 	DetachKeys    []byte
 	Pid           int
+	// StartedAt is the time the exec process was started at, in RFC 3339
+	// format with nano-seconds, or empty if it was not started.
+	StartedAt string `json:",omitempty"`
+	// Client is the API client which started the exec process.
+	Client *container.ExecClient `json:",omitempty"`
+	// Usage is the usage of the exec process while it runs, if it was
+	// created with resource limits.
+	Usage *types.ExecStats `json:",omitempty"`
 }
 
 // ExecProcessConfig holds information about the exec process
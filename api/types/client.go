@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"io"
 	"net"
+	"time"
 
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
@@ -56,6 +57,9 @@ type ContainerExecInspect struct {
 	Running     bool
 	ExitCode    int
 	Pid         int
+	StartedAt   string                `json:",omitempty"`
+	Client      *container.ExecClient `json:",omitempty"`
+	Usage       *ExecStats            `json:",omitempty"`
 }
 
 // ContainerListOptions holds parameters to list containers with.
@@ -67,6 +71,19 @@ type ContainerListOptions struct {
 	Before  string
 	Limit   int
 	Filters filters.Args
+
+	// Cursor is the cursor of the page of the list to return, as returned
+	// with the previous page. It is used with Limit to list the containers
+	// page by page.
+	Cursor string
+}
+
+// ContainersStatsOptions holds parameters to get the stats of all running
+// containers with.
+type ContainersStatsOptions struct {
+	Stream   bool
+	Interval time.Duration
+	Filters  filters.Args
 }
 
 // ContainerLogsOptions holds parameters to filter logs with.
@@ -99,6 +116,11 @@ type ContainerStartOptions struct {
 type CopyToContainerOptions struct {
 	AllowOverwriteDirWithFile bool
 	CopyUIDGID                bool
+
+	// Include and Exclude are patterns, in the syntax of .dockerignore
+	// files, selecting the entries of the archive to extract.
+	Include []string
+	Exclude []string
 }
 
 // EventsOptions holds parameters to filter events with.
@@ -205,6 +227,28 @@ type ImageBuildOptions struct {
 	// Outputs defines configurations for exporting build results. Only supported
 	// in BuildKit mode
 	Outputs []ImageBuildOutput
+	// CacheImports defines the build caches to import, such as registry
+	// caches, in addition to CacheFrom. Only supported in BuildKit mode
+	CacheImports []ImageBuildCache
+	// CacheExports defines where to export the build cache, such as to a
+	// registry. Only supported in BuildKit mode
+	CacheExports []ImageBuildCache
+	// Secrets defines the swarm secrets the build can mount with
+	// RUN --mount=type=secret, in addition to the secrets provided by the
+	// client. Only supported in BuildKit mode
+	Secrets []ImageBuildSecret
+	// PidsLimit is the maximum number of processes of the build. In
+	// BuildKit mode, the build runs in a cgroup of its own, which is limited
+	// by PidsLimit, Memory, MemorySwap, CPUShares, CPUQuota, CPUPeriod,
+	// CPUSetCPUs and CPUSetMems as a whole.
+	PidsLimit int64
+	// ScratchLimit is the maximum size, in bytes, of the files the steps of
+	// the build write to their root filesystem. Only supported in BuildKit
+	// mode.
+	ScratchLimit int64
+	// ProgressV2 requests the output in the v2 progress format, which
+	// carries a digest summary as its last event.
+	ProgressV2 bool
 }
 
 // ImageBuildOutput defines configuration for exporting a build result
@@ -213,6 +257,22 @@ type ImageBuildOutput struct {
 	Attrs map[string]string
 }
 
+// ImageBuildCache defines configuration for importing or exporting a build
+// cache. Type is the type of the cache, such as "registry", and Attrs are
+// the options of the cache, such as "ref".
+type ImageBuildCache struct {
+	Type  string
+	Attrs map[string]string
+}
+
+// ImageBuildSecret defines a swarm secret a build can mount. ID is the ID
+// of the secret in the RUN --mount=type=secret instruction, and Name is the
+// name or ID of the swarm secret. Name defaults to ID.
+type ImageBuildSecret struct {
+	ID   string
+	Name string `json:",omitempty"`
+}
+
 // BuilderVersion sets the version of underlying builder to use
 type BuilderVersion string
 
@@ -265,6 +325,14 @@ type ImageListOptions struct {
 
 	// ContainerCount indicates whether container count should be computed.
 	ContainerCount bool
+
+	// Limit is the maximum number of images to return. Images are ordered
+	// by creation time, newest first.
+	Limit int
+
+	// Cursor is the cursor of the page of the list to return, as returned
+	// with the previous page.
+	Cursor string
 }
 
 // ImageLoadResponse returns information to the client about a load process.
@@ -280,6 +348,9 @@ type ImagePullOptions struct {
 	RegistryAuth  string // RegistryAuth is the base64 encoded credentials for the registry
 	PrivilegeFunc RequestPrivilegeFunc
 	Platform      string
+	// ProgressV2 requests the progress in the v2 progress format, which
+	// carries a digest summary as its last event.
+	ProgressV2 bool
 }
 
 // RequestPrivilegeFunc is a function interface that
@@ -394,6 +465,19 @@ type ServiceListOptions struct {
 	Status bool
 }
 
+// StackDeployOptions holds parameters to deploy stacks with.
+type StackDeployOptions struct {
+	// EncodedRegistryAuth is the encoded registry authorization credentials to
+	// use when creating and updating the services of the stack.
+	//
+	// This field follows the format of the X-Registry-Auth header.
+	EncodedRegistryAuth string
+
+	// Prune indicates whether the services, networks, secrets, and configs
+	// of the stack that are no longer in its spec should be removed.
+	Prune bool
+}
+
 // ServiceInspectOptions holds parameters related to the "service inspect"
 // operation.
 type ServiceInspectOptions struct {
@@ -42,6 +42,19 @@ type ExecConfig struct {
 	Env          []string // Environment variables
 	WorkingDir   string   // Working directory
 	Cmd          []string // Execution commands and args
+
+	// Resources are the limits of the exec process, which are applied in a
+	// sub-cgroup of the container's cgroup. API versions >= 1.43.
+	Resources *ExecResources `json:",omitempty"`
+}
+
+// ExecResources holds the resource limits of an exec process. The usage of an
+// exec process which has resource limits is accounted separately from the
+// container's in its stats.
+type ExecResources struct {
+	Memory    int64  // Memory limit (in bytes)
+	NanoCPUs  int64  `json:"NanoCpus"` // CPU quota in units of 10<sup>-9</sup> CPUs.
+	PidsLimit *int64 // Setting PIDs limit for the exec process; set `0`, `-1`, or `null` for unlimited.
 }
 
 // PluginRmConfig holds arguments for plugin remove.
@@ -0,0 +1,52 @@
+package container // import "github.com/docker/docker/api/types/container"
+
+// AttachWebSocketProtocolV2 is the websocket subprotocol that selects
+// version 2 of the websocket attach protocol.
+const AttachWebSocketProtocolV2 = "v2.attach.docker.com"
+
+// AttachMessageType is the type of a message of version 2 of the websocket
+// attach protocol. Every message is sent in a binary frame, whose first byte
+// is the message type, and whose remaining bytes are the payload.
+type AttachMessageType byte
+
+// Message types of version 2 of the websocket attach protocol.
+//
+// AttachMessageStdin holds data written to the stdin of the container. It is
+// sent by the client.
+//
+// AttachMessageStdout and AttachMessageStderr hold output of the container.
+// They are sent by the daemon. Containers with a TTY only produce stdout.
+//
+// AttachMessageResize resizes the TTY of the container. It is sent by the
+// client, and its payload is a JSON-encoded AttachResize.
+//
+// AttachMessageClose closes the stdin of the container when sent by the
+// client. The daemon sends it when the attach ends, with a JSON-encoded
+// AttachClose payload, before closing the connection.
+//
+// AttachMessagePing and AttachMessagePong keep the connection alive. Either
+// side can send a ping, and the other side must reply with a pong holding
+// the same payload. The daemon closes connections on which it received no
+// message for twice its ping interval.
+const (
+	AttachMessageStdin AttachMessageType = iota
+	AttachMessageStdout
+	AttachMessageStderr
+	AttachMessageResize
+	AttachMessageClose
+	AttachMessagePing
+	AttachMessagePong
+)
+
+// AttachResize is the payload of an AttachMessageResize message.
+type AttachResize struct {
+	Height uint `json:"height"`
+	Width  uint `json:"width"`
+}
+
+// AttachClose is the payload of the AttachMessageClose message sent by the
+// daemon.
+type AttachClose struct {
+	// Error is the error that ended the attach, if any.
+	Error string `json:"error,omitempty"`
+}
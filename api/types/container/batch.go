@@ -0,0 +1,57 @@
+package container // import "github.com/docker/docker/api/types/container"
+
+// BatchAction is a lifecycle action applied to containers by a batch request.
+type BatchAction string
+
+// Actions of batch requests.
+const (
+	BatchStart   BatchAction = "start"
+	BatchStop    BatchAction = "stop"
+	BatchRestart BatchAction = "restart"
+	BatchKill    BatchAction = "kill"
+	BatchPause   BatchAction = "pause"
+	BatchUnpause BatchAction = "unpause"
+)
+
+// BatchRequest is the body of a request applying a lifecycle action to
+// several containers.
+type BatchRequest struct {
+	// Action is the action to apply to the containers.
+	Action BatchAction
+
+	// Containers are the IDs or names of the containers.
+	Containers []string
+
+	// Signal is the signal sent by the kill action, or used to stop the
+	// containers by the stop and restart actions.
+	Signal string `json:",omitempty"`
+
+	// Timeout is the number of seconds to wait for the containers to stop
+	// before killing them, for the stop and restart actions.
+	Timeout *int `json:",omitempty"`
+
+	// Parallelism is the maximum number of containers the action is
+	// applied to at once. The daemon uses its own limit if it is zero or
+	// above that limit.
+	Parallelism int `json:",omitempty"`
+}
+
+// BatchResponse is the response of a batch request.
+type BatchResponse struct {
+	// Results holds the result of the action for each container, in the
+	// order of the request.
+	Results []BatchResult
+}
+
+// BatchResult is the result of the action of a batch request for one
+// container.
+type BatchResult struct {
+	// ID is the ID or name of the container, as given in the request.
+	ID string
+
+	// Error is the error applying the action to the container, if any.
+	Error string `json:",omitempty"`
+
+	// ErrorCode is the machine-readable code of the error, if any.
+	ErrorCode string `json:",omitempty"`
+}
@@ -59,6 +59,21 @@ type ExecStartOptions struct {
 	Stdout      io.Writer
 	Stderr      io.Writer
 	ConsoleSize *[2]uint `json:",omitempty"`
+	// Client identifies the API client which starts the exec.
+	Client *ExecClient `json:",omitempty"`
+}
+
+// ExecClient identifies the API client which started an exec process.
+type ExecClient struct {
+	// User is the common name of the TLS client certificate of the client.
+	User string `json:",omitempty"`
+	// UID and PID are the credentials of the client process, if the client
+	// connected over a unix socket.
+	UID *uint32 `json:",omitempty"`
+	PID int32   `json:",omitempty"`
+	// RemoteAddr is the network address of the client.
+	RemoteAddr string `json:",omitempty"`
+	UserAgent  string `json:",omitempty"`
 }
 
 // Config contains the configuration data about a container.
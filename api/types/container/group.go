@@ -0,0 +1,77 @@
+package container // import "github.com/docker/docker/api/types/container"
+
+import (
+	"time"
+
+	"github.com/docker/go-connections/nat"
+)
+
+// Group is a group of containers, like a pod. The group owns a network
+// sandbox, which its members join with the "group:<name|id>" network mode,
+// and the members can share their IPC and PID namespaces.
+type Group struct {
+	// ID is the ID of the group.
+	ID string
+
+	// Name is the name of the group.
+	Name string
+
+	// Created is when the group was created.
+	Created time.Time
+
+	// Config is the configuration of the group.
+	Config GroupConfig
+
+	// Members are the containers of the group, in the order they joined it.
+	Members []GroupMember
+
+	// SandboxID is the ID of the network sandbox of the group, while it
+	// exists.
+	SandboxID string `json:",omitempty"`
+
+	// SandboxKey is the path of the network namespace of the group, while it
+	// exists.
+	SandboxKey string `json:",omitempty"`
+
+	// HolderPID is the pid of the process holding the IPC and PID
+	// namespaces shared by the members, while it runs.
+	HolderPID int `json:",omitempty"`
+}
+
+// GroupConfig is the configuration of a container group.
+type GroupConfig struct {
+	// Hostname is the hostname of the members. It defaults to the name of
+	// the group.
+	Hostname string `json:",omitempty"`
+
+	// Networks are the networks the group is connected to. The group is
+	// connected to the default network if empty.
+	Networks []string `json:",omitempty"`
+
+	// PortBindings are the ports of the members published on the host.
+	PortBindings nat.PortMap `json:",omitempty"`
+
+	// ShareIPC makes the members share their IPC namespace and /dev/shm.
+	ShareIPC bool `json:",omitempty"`
+
+	// SharePID makes the members share their PID namespace.
+	SharePID bool `json:",omitempty"`
+
+	// Labels are the labels of the group.
+	Labels map[string]string `json:",omitempty"`
+}
+
+// GroupMember is a container of a group.
+type GroupMember struct {
+	ID    string
+	Name  string `json:",omitempty"`
+	State string `json:",omitempty"`
+}
+
+// GroupCreateRequest is the body of a group create request.
+type GroupCreateRequest struct {
+	// Name is the name of the group.
+	Name string
+
+	GroupConfig
+}
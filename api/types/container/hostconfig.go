@@ -1,7 +1,9 @@
 package container // import "github.com/docker/docker/api/types/container"
 
 import (
+	"os"
 	"strings"
+	"time"
 
 	"github.com/docker/docker/api/types/blkiodev"
 	"github.com/docker/docker/api/types/mount"
@@ -142,7 +144,7 @@ func (n NetworkMode) IsDefault() bool {
 
 // IsPrivate indicates whether container uses its private network stack.
 func (n NetworkMode) IsPrivate() bool {
-	return !(n.IsHost() || n.IsContainer())
+	return !(n.IsHost() || n.IsContainer() || n.IsGroup())
 }
 
 // IsContainer indicates whether container uses a container network stack.
@@ -157,6 +159,20 @@ func (n NetworkMode) ConnectedContainer() (idOrName string) {
 	return idOrName
 }
 
+// IsGroup indicates whether container uses the network stack of a container
+// group.
+func (n NetworkMode) IsGroup() bool {
+	_, ok := groupID(string(n))
+	return ok
+}
+
+// ConnectedGroup is the id of the container group which network this
+// container is connected to.
+func (n NetworkMode) ConnectedGroup() (idOrName string) {
+	idOrName, _ = groupID(string(n))
+	return idOrName
+}
+
 // UserDefined indicates user-created network
 func (n NetworkMode) UserDefined() string {
 	if n.IsUserDefined() {
@@ -431,6 +447,83 @@ type HostConfig struct {
 
 	// Run a custom init inside the container, if null, use the daemon's configured settings
 	Init *bool `json:",omitempty"`
+
+	// Hooks are the OCI lifecycle hooks of the container. Their binaries
+	// must be allowed by the daemon configuration.
+	Hooks *Hooks `json:",omitempty"`
+
+	// NotifyReady provides a NOTIFY_SOCKET to the container, on which it
+	// notifies the daemon that it is ready with READY=1, as with sd_notify.
+	// Containers without healthcheck are healthy once they are ready.
+	NotifyReady bool `json:",omitempty"`
+
+	// Secrets are the secrets of the daemon's local secret store exposed to
+	// the container, outside of swarm services.
+	Secrets []FileReference `json:",omitempty"`
+
+	// Configs are the configs of the daemon's local config store exposed to
+	// the container, outside of swarm services.
+	Configs []FileReference `json:",omitempty"`
+
+	// Lifetime makes the daemon stop or remove the container once it ran
+	// for a maximum duration, or at a deadline.
+	Lifetime *Lifetime `json:",omitempty"`
+}
+
+// LifetimeAction is the action taken on a container at the end of its
+// lifetime.
+type LifetimeAction string
+
+// Actions taken on a container at the end of its lifetime.
+const (
+	LifetimeActionStop   LifetimeAction = "stop"
+	LifetimeActionRemove LifetimeAction = "remove"
+)
+
+// Lifetime limits the time a container runs for.
+type Lifetime struct {
+	// MaxRuntime is the maximum duration of each run of the container.
+	MaxRuntime time.Duration `json:",omitempty"`
+	// Deadline is the time at which the container is stopped, if running.
+	Deadline *time.Time `json:",omitempty"`
+	// Action is the action taken at the end of the lifetime, "stop" if
+	// empty.
+	Action      LifetimeAction `json:",omitempty"`
+	StopSignal  string         `json:",omitempty"` // Signal to stop the container, its stop signal if empty
+	StopTimeout *int           `json:",omitempty"` // Number of seconds before the container is killed, its stop timeout if nil
+}
+
+// FileReference is a reference to a secret or config of the daemon's local
+// store, which is exposed as a file in the container.
+type FileReference struct {
+	// Source is the name or ID of the secret or config.
+	Source string
+	// Target is the path of the file in the container. Relative paths are
+	// relative to /run/secrets for secrets, and to / for configs. It defaults
+	// to the name of the secret or config.
+	Target string      `json:",omitempty"`
+	UID    string      `json:",omitempty"` // Owner of the file, "0" by default
+	GID    string      `json:",omitempty"` // Group of the file, "0" by default
+	Mode   os.FileMode `json:",omitempty"` // Permissions of the file, 0444 by default
+}
+
+// Hook is an OCI lifecycle hook, which runs a binary of the host.
+type Hook struct {
+	Path    string   // Absolute path of the binary on the host
+	Args    []string `json:",omitempty"` // Arguments of the binary, including argv[0]
+	Env     []string `json:",omitempty"` // Environment of the binary, with variables prefixed with HOOK_
+	Timeout *int     `json:",omitempty"` // Number of seconds before the hook is aborted
+}
+
+// Hooks are the OCI lifecycle hooks of a container.
+type Hooks struct {
+	// CreateRuntime hooks run after the container is created, in the
+	// runtime namespace, before pivot_root.
+	CreateRuntime []Hook `json:",omitempty"`
+	// Poststart hooks run after the user process of the container is started.
+	Poststart []Hook `json:",omitempty"`
+	// Poststop hooks run after the container is deleted.
+	Poststop []Hook `json:",omitempty"`
 }
 
 // containerID splits "container:<ID|name>" values. It returns the container
@@ -442,3 +535,10 @@ func containerID(val string) (idOrName string, ok bool) {
 	k, idOrName, ok := strings.Cut(val, ":")
 	return idOrName, ok && k == "container"
 }
+
+// groupID splits "group:<ID|name>" values. It returns the group ID or name,
+// and whether an ID or name was found.
+func groupID(val string) (idOrName string, ok bool) {
+	k, idOrName, ok := strings.Cut(val, ":")
+	return idOrName, ok && k == "group"
+}
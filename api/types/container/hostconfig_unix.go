@@ -16,6 +16,8 @@ func (n NetworkMode) NetworkName() string {
 		return "host"
 	} else if n.IsContainer() {
 		return "container"
+	} else if n.IsGroup() {
+		return "group"
 	} else if n.IsNone() {
 		return "none"
 	} else if n.IsDefault() {
@@ -38,5 +40,5 @@ func (n NetworkMode) IsHost() bool {
 
 // IsUserDefined indicates user-created network
 func (n NetworkMode) IsUserDefined() bool {
-	return !n.IsDefault() && !n.IsBridge() && !n.IsHost() && !n.IsNone() && !n.IsContainer()
+	return !n.IsDefault() && !n.IsBridge() && !n.IsHost() && !n.IsNone() && !n.IsContainer() && !n.IsGroup()
 }
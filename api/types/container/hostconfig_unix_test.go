@@ -35,16 +35,17 @@ func TestCgroupnsMode(t *testing.T) {
 // TODO Windows: This will need addressing for a Windows daemon.
 func TestNetworkMode(t *testing.T) {
 	modes := map[NetworkMode]struct {
-		private, bridge, host, container, none, isDefault bool
-		name                                              string
+		private, bridge, host, container, group, none, isDefault bool
+		name                                                     string
 	}{
-		"":                {private: true, bridge: false, host: false, container: false, none: false, isDefault: false, name: ""},
-		"something:weird": {private: true, bridge: false, host: false, container: false, none: false, isDefault: false, name: "something:weird"},
-		"bridge":          {private: true, bridge: true, host: false, container: false, none: false, isDefault: false, name: "bridge"},
-		"host":            {private: false, bridge: false, host: true, container: false, none: false, isDefault: false, name: "host"},
-		"container:name":  {private: false, bridge: false, host: false, container: true, none: false, isDefault: false, name: "container"},
-		"none":            {private: true, bridge: false, host: false, container: false, none: true, isDefault: false, name: "none"},
-		"default":         {private: true, bridge: false, host: false, container: false, none: false, isDefault: true, name: "default"},
+		"":                {private: true, bridge: false, host: false, container: false, group: false, none: false, isDefault: false, name: ""},
+		"something:weird": {private: true, bridge: false, host: false, container: false, group: false, none: false, isDefault: false, name: "something:weird"},
+		"bridge":          {private: true, bridge: true, host: false, container: false, group: false, none: false, isDefault: false, name: "bridge"},
+		"host":            {private: false, bridge: false, host: true, container: false, group: false, none: false, isDefault: false, name: "host"},
+		"container:name":  {private: false, bridge: false, host: false, container: true, group: false, none: false, isDefault: false, name: "container"},
+		"group:name":      {private: false, bridge: false, host: false, container: false, group: true, none: false, isDefault: false, name: "group"},
+		"none":            {private: true, bridge: false, host: false, container: false, group: false, none: true, isDefault: false, name: "none"},
+		"default":         {private: true, bridge: false, host: false, container: false, group: false, none: false, isDefault: true, name: "default"},
 	}
 	for mode, expected := range modes {
 		t.Run("mode="+string(mode), func(t *testing.T) {
@@ -52,6 +53,7 @@ func TestNetworkMode(t *testing.T) {
 			assert.Check(t, is.Equal(mode.IsBridge(), expected.bridge))
 			assert.Check(t, is.Equal(mode.IsHost(), expected.host))
 			assert.Check(t, is.Equal(mode.IsContainer(), expected.container))
+			assert.Check(t, is.Equal(mode.IsGroup(), expected.group))
 			assert.Check(t, is.Equal(mode.IsNone(), expected.none))
 			assert.Check(t, is.Equal(mode.IsDefault(), expected.isDefault))
 			assert.Check(t, is.Equal(mode.NetworkName(), expected.name))
@@ -14,7 +14,7 @@ func (n NetworkMode) IsHost() bool {
 
 // IsUserDefined indicates user-created network
 func (n NetworkMode) IsUserDefined() bool {
-	return !n.IsDefault() && !n.IsNone() && !n.IsBridge() && !n.IsContainer()
+	return !n.IsDefault() && !n.IsNone() && !n.IsBridge() && !n.IsContainer() && !n.IsGroup()
 }
 
 // IsValid indicates if an isolation technology is valid
@@ -32,6 +32,8 @@ func (n NetworkMode) NetworkName() string {
 		return "none"
 	} else if n.IsContainer() {
 		return "container"
+	} else if n.IsGroup() {
+		return "group"
 	} else if n.IsUserDefined() {
 		return n.UserDefined()
 	}
@@ -0,0 +1,30 @@
+package container // import "github.com/docker/docker/api/types/container"
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Template is a named, partial container specification stored by the daemon.
+// Containers can be created from a template, with the body of the create
+// request applied to the template's Spec as a JSON merge patch (RFC 7386).
+type Template struct {
+	// Name is the name of the template.
+	Name string
+
+	// Spec is the partial body of a container create request, holding any
+	// of its Config, HostConfig, and NetworkingConfig fields.
+	Spec json.RawMessage
+
+	// CreatedAt is when the template was stored.
+	CreatedAt time.Time
+}
+
+// TemplateCreateRequest is the body of a template create request.
+type TemplateCreateRequest struct {
+	// Name is the name of the template.
+	Name string
+
+	// Spec is the partial body of a container create request.
+	Spec json.RawMessage
+}
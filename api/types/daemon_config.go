@@ -0,0 +1,42 @@
+package types // import "github.com/docker/docker/api/types"
+
+import "time"
+
+// ConfigReload is the result of the last reload of the configuration of the
+// daemon.
+type ConfigReload struct {
+	// Time is when the configuration was reloaded.
+	Time time.Time
+
+	// Reloaded lists the options whose changes were applied.
+	Reloaded []string
+
+	// RestartRequired lists the options whose changes only take effect
+	// when the daemon is restarted.
+	RestartRequired []string
+
+	// Error is the error that aborted the reload, if any. None of the
+	// changes are applied when the configuration file is invalid.
+	Error string `json:",omitempty"`
+}
+
+// ConfigCheck is the result of checking a proposed configuration file
+// against the running daemon, without applying it.
+type ConfigCheck struct {
+	// Errors lists the problems that would prevent the configuration from
+	// being applied. The configuration is valid if it's empty.
+	Errors []string
+
+	// Warnings lists the problems that don't prevent the configuration
+	// from being applied, such as default address pools that overlap the
+	// subnets of existing networks.
+	Warnings []string
+
+	// Reloadable lists the options whose changes would be applied when
+	// the daemon reloads its configuration.
+	Reloadable []string
+
+	// RestartRequired lists the options whose changes would only take
+	// effect when the daemon is restarted.
+	RestartRequired []string
+}
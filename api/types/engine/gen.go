@@ -0,0 +1,7 @@
+//go:generate protoc -I . --gogofast_out=plugins=grpc,import_path=github.com/docker/docker/api/types/engine,Mgoogle/protobuf/duration.proto=github.com/gogo/protobuf/types,Mgoogle/protobuf/timestamp.proto=github.com/gogo/protobuf/types:. engine.proto
+
+// Package engine holds the types of the gRPC flavor of the Engine API.
+//
+// Clients connect to it by upgrading a "POST /grpc" request on the API socket
+// to h2c, as done by Client.DialHijack.
+package engine // import "github.com/docker/docker/api/types/engine"
@@ -7,6 +7,12 @@ package types
 // swagger:model ErrorResponse
 type ErrorResponse struct {
 
+	// A stable, machine-readable code identifying the error.
+	Code string `json:"code,omitempty"`
+
+	// Structured details about the error, such as the ID of a conflicting object.
+	Details map[string]string `json:"details,omitempty"`
+
 	// The error message.
 	// Required: true
 	Message string `json:"message"`
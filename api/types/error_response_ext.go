@@ -4,3 +4,13 @@ package types
 func (e ErrorResponse) Error() string {
 	return e.Message
 }
+
+// ErrorCode returns the machine-readable code of the error
+func (e ErrorResponse) ErrorCode() string {
+	return e.Code
+}
+
+// ErrorDetails returns the structured details of the error
+func (e ErrorResponse) ErrorDetails() map[string]string {
+	return e.Details
+}
@@ -15,6 +15,7 @@ const (
 	PluginEventType    Type = "plugin"    // PluginEventType is the event type that plugins generate.
 	SecretEventType    Type = "secret"    // SecretEventType is the event type that secrets generate.
 	ServiceEventType   Type = "service"   // ServiceEventType is the event type that services generate.
+	SwarmEventType     Type = "swarm"     // SwarmEventType is the event type that the swarm generates.
 	VolumeEventType    Type = "volume"    // VolumeEventType is the event type that volumes generate.
 )
 
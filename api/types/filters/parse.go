@@ -148,28 +148,14 @@ func (args Args) Len() int {
 	return len(args.fields)
 }
 
-// MatchKVList returns true if all the pairs in sources exist as key=value
-// pairs in the mapping at key, or if there are no values at key.
+// MatchKVList returns true if sources satisfy all the label selector
+// requirements in the mapping at key, or if there are no values at key. See
+// ParseLabelSelector for the syntax of the requirements; invalid requirements
+// never match.
 func (args Args) MatchKVList(key string, sources map[string]string) bool {
-	fieldValues := args.fields[key]
-
-	// do not filter if there is no filter set or cannot determine filter
-	if len(fieldValues) == 0 {
-		return true
-	}
-
-	if len(sources) == 0 {
-		return false
-	}
-
-	for value := range fieldValues {
-		testK, testV, hasValue := strings.Cut(value, "=")
-
-		v, ok := sources[testK]
-		if !ok {
-			return false
-		}
-		if hasValue && testV != v {
+	for value := range args.fields[key] {
+		s, err := ParseLabelSelector(value)
+		if err != nil || !s.Matches(sources) {
 			return false
 		}
 	}
@@ -0,0 +1,132 @@
+package filters // import "github.com/docker/docker/api/types/filters"
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Operator is the operator of a label selector requirement.
+type Operator string
+
+// Operators of label selector requirements.
+const (
+	// Exists matches labels that are set, for example "key".
+	Exists Operator = "exists"
+	// DoesNotExist matches labels that are not set, for example "!key".
+	DoesNotExist Operator = "!"
+	// Equals matches labels set to a value, for example "key=value", or
+	// "key==value".
+	Equals Operator = "="
+	// NotEquals matches labels that are not set, or set to another value,
+	// for example "key!=value".
+	NotEquals Operator = "!="
+	// In matches labels set to one of a set of values, for example
+	// "key in (value1, value2)".
+	In Operator = "in"
+	// NotIn matches labels that are not set, or not set to one of a set of
+	// values, for example "key notin (value1, value2)".
+	NotIn Operator = "notin"
+)
+
+// setSelector matches the set-based requirements "key in (...)", and
+// "key notin (...)".
+var setSelector = regexp.MustCompile(`^\s*([^\s=!()]+)\s+(in|notin)\s*\((.*)\)\s*$`)
+
+// setSelectorPrefix matches what looks like the start of a set-based
+// requirement, to report malformed ones instead of treating them as a key.
+var setSelectorPrefix = regexp.MustCompile(`^\s*[^\s=!()]+\s+(in|notin)\s*\(`)
+
+// LabelSelector is a requirement on the labels of an object, parsed from the
+// value of a "label" filter.
+type LabelSelector struct {
+	Key      string
+	Operator Operator
+	Values   []string
+}
+
+// ParseLabelSelector parses a label selector requirement. Besides "key", and
+// "key=value", which were the only forms supported before API v1.43, the
+// requirement can be "!key", "key==value", "key!=value",
+// "key in (value1, value2)", or "key notin (value1, value2)".
+func ParseLabelSelector(expr string) (LabelSelector, error) {
+	if !strings.Contains(expr, "(") {
+		return parseEqualitySelector(expr)
+	}
+	if m := setSelector.FindStringSubmatch(expr); m != nil {
+		s := LabelSelector{Key: m[1], Operator: Operator(m[2])}
+		for _, v := range strings.Split(m[3], ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				s.Values = append(s.Values, v)
+			}
+		}
+		if len(s.Values) == 0 {
+			return LabelSelector{}, invalidFilter{errors.Errorf("invalid label selector %q: no values", expr)}
+		}
+		return s, nil
+	}
+	if setSelectorPrefix.MatchString(expr) {
+		return LabelSelector{}, invalidFilter{errors.Errorf("invalid label selector %q: missing closing parenthesis", expr)}
+	}
+	return parseEqualitySelector(expr)
+}
+
+// parseEqualitySelector parses the requirements that aren't set-based.
+func parseEqualitySelector(expr string) (LabelSelector, error) {
+	var s LabelSelector
+	if k, v, ok := strings.Cut(expr, "="); ok {
+		switch {
+		case strings.HasSuffix(k, "!"):
+			s = LabelSelector{Key: k[:len(k)-1], Operator: NotEquals, Values: []string{v}}
+		case strings.HasPrefix(v, "="):
+			s = LabelSelector{Key: k, Operator: Equals, Values: []string{v[1:]}}
+		default:
+			s = LabelSelector{Key: k, Operator: Equals, Values: []string{v}}
+		}
+	} else if strings.HasPrefix(expr, "!") {
+		s = LabelSelector{Key: expr[1:], Operator: DoesNotExist}
+	} else {
+		s = LabelSelector{Key: expr, Operator: Exists}
+	}
+	if s.Key == "" {
+		return LabelSelector{}, invalidFilter{errors.Errorf("invalid label selector %q: empty key", expr)}
+	}
+	return s, nil
+}
+
+// Matches returns whether labels satisfy the requirement.
+func (s LabelSelector) Matches(labels map[string]string) bool {
+	v, ok := labels[s.Key]
+	switch s.Operator {
+	case Exists:
+		return ok
+	case DoesNotExist:
+		return !ok
+	case Equals, In:
+		return ok && s.hasValue(v)
+	case NotEquals, NotIn:
+		return !ok || !s.hasValue(v)
+	}
+	return false
+}
+
+func (s LabelSelector) hasValue(v string) bool {
+	for _, sv := range s.Values {
+		if sv == v {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateLabelSelectors returns an error if any of the values at key is not a
+// valid label selector requirement.
+func (args Args) ValidateLabelSelectors(key string) error {
+	for value := range args.fields[key] {
+		if _, err := ParseLabelSelector(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
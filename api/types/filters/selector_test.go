@@ -0,0 +1,74 @@
+package filters // import "github.com/docker/docker/api/types/filters"
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestParseLabelSelector(t *testing.T) {
+	for _, tc := range []struct {
+		expr     string
+		expected LabelSelector
+	}{
+		{expr: "env", expected: LabelSelector{Key: "env", Operator: Exists}},
+		{expr: "!env", expected: LabelSelector{Key: "env", Operator: DoesNotExist}},
+		{expr: "env=prod", expected: LabelSelector{Key: "env", Operator: Equals, Values: []string{"prod"}}},
+		{expr: "env==prod", expected: LabelSelector{Key: "env", Operator: Equals, Values: []string{"prod"}}},
+		{expr: "env!=prod", expected: LabelSelector{Key: "env", Operator: NotEquals, Values: []string{"prod"}}},
+		{expr: "env=", expected: LabelSelector{Key: "env", Operator: Equals, Values: []string{""}}},
+		{expr: "env in (prod, staging)", expected: LabelSelector{Key: "env", Operator: In, Values: []string{"prod", "staging"}}},
+		{expr: "env notin (dev)", expected: LabelSelector{Key: "env", Operator: NotIn, Values: []string{"dev"}}},
+		// Values that were valid before selectors keep their meaning.
+		{expr: "com.example.cmd=a!=b", expected: LabelSelector{Key: "com.example.cmd", Operator: Equals, Values: []string{"a!=b"}}},
+		{expr: "com.example.cmd=run in (x)", expected: LabelSelector{Key: "com.example.cmd", Operator: Equals, Values: []string{"run in (x)"}}},
+		{expr: "com.example.list=a,b", expected: LabelSelector{Key: "com.example.list", Operator: Equals, Values: []string{"a,b"}}},
+	} {
+		s, err := ParseLabelSelector(tc.expr)
+		assert.NilError(t, err, tc.expr)
+		assert.Check(t, is.DeepEqual(s, tc.expected), tc.expr)
+	}
+
+	for _, expr := range []string{"", "!", "=prod", "!=prod", "env in ()", "env in (prod"} {
+		_, err := ParseLabelSelector(expr)
+		assert.Check(t, err != nil, expr)
+	}
+}
+
+func TestArgsMatchKVListSelectors(t *testing.T) {
+	labels := map[string]string{"env": "prod", "tier": "web"}
+	for _, tc := range []struct {
+		selectors []string
+		expected  bool
+	}{
+		{selectors: []string{"env", "tier=web"}, expected: true},
+		{selectors: []string{"!owner"}, expected: true},
+		{selectors: []string{"!env"}, expected: false},
+		{selectors: []string{"env!=dev"}, expected: true},
+		{selectors: []string{"owner!=dev"}, expected: true},
+		{selectors: []string{"env!=prod"}, expected: false},
+		{selectors: []string{"env in (prod,staging)", "tier notin (db)"}, expected: true},
+		{selectors: []string{"env in (dev,staging)"}, expected: false},
+		{selectors: []string{"owner in (a)"}, expected: false},
+		{selectors: []string{"owner notin (a)"}, expected: true},
+		{selectors: []string{"env in (prod"}, expected: false},
+	} {
+		args := NewArgs()
+		for _, s := range tc.selectors {
+			args.Add("label", s)
+		}
+		assert.Check(t, is.Equal(args.MatchKVList("label", labels), tc.expected), tc.selectors)
+	}
+
+	// Objects without labels only match requirements on absent labels.
+	assert.Check(t, NewArgs(Arg("label", "!env")).MatchKVList("label", nil))
+	assert.Check(t, !NewArgs(Arg("label", "env")).MatchKVList("label", nil))
+}
+
+func TestArgsValidateLabelSelectors(t *testing.T) {
+	assert.NilError(t, NewArgs(Arg("label", "env in (prod)"), Arg("label", "!tier")).ValidateLabelSelectors("label"))
+
+	err := NewArgs(Arg("label", "env in (prod")).ValidateLabelSelectors("label")
+	assert.Check(t, is.ErrorContains(err, "missing closing parenthesis"))
+}
@@ -0,0 +1,41 @@
+package types // import "github.com/docker/docker/api/types"
+
+// LabelsUpdate is the request body of the Engine API endpoints updating the
+// labels of an existing object:
+// POST "/containers/{name}/labels", "/images/{name}/labels",
+// "/volumes/{name}/labels", and "/networks/{id}/labels"
+type LabelsUpdate struct {
+	// Add holds the labels to add, or to replace if they're already set.
+	Add map[string]string `json:",omitempty"`
+
+	// Remove holds the keys of the labels to remove.
+	Remove []string `json:",omitempty"`
+}
+
+// LabelsUpdateResponse is the response of the Engine API endpoints updating
+// the labels of an existing object.
+type LabelsUpdateResponse struct {
+	// ID holds the ID of the object after the update, if updating its labels
+	// changed it, as for images, whose labels are part of their
+	// configuration.
+	ID string `json:",omitempty"`
+
+	// Labels holds the labels of the object after the update.
+	Labels map[string]string
+}
+
+// Apply returns a copy of labels with the update applied. Labels are removed
+// before being added.
+func (u LabelsUpdate) Apply(labels map[string]string) map[string]string {
+	updated := make(map[string]string, len(labels)+len(u.Add))
+	for k, v := range labels {
+		updated[k] = v
+	}
+	for _, k := range u.Remove {
+		delete(updated, k)
+	}
+	for k, v := range u.Add {
+		updated[k] = v
+	}
+	return updated
+}
@@ -85,6 +85,9 @@ type BindOptions struct {
 	Propagation      Propagation `json:",omitempty"`
 	NonRecursive     bool        `json:",omitempty"`
 	CreateMountpoint bool        `json:",omitempty"`
+	// IDMap maps the ownership of the files in the mount with the user
+	// namespace of the container, recursively unless NonRecursive is set.
+	IDMap bool `json:",omitempty"`
 }
 
 // VolumeOptions represents the options for a mount of type volume.
@@ -113,6 +116,17 @@ type TmpfsOptions struct {
 	SizeBytes int64 `json:",omitempty"`
 	// Mode of the tmpfs upon creation
 	Mode os.FileMode `json:",omitempty"`
+	// UID of the owner of the root directory of the tmpfs.
+	UID *int `json:",omitempty"`
+	// GID of the group of the root directory of the tmpfs.
+	GID *int `json:",omitempty"`
+	// HugePages sets the huge page policy of the tmpfs; one of "never",
+	// "always", "within_size", or "advise". The kernel default is used if
+	// not set.
+	HugePages string `json:",omitempty"`
+	// NoSwap disables swapping of the tmpfs contents. It requires Linux 6.4
+	// or later.
+	NoSwap bool `json:",omitempty"`
 
 	// TODO(stevvooe): There are several more tmpfs flags, specified in the
 	// daemon, that are accepted. Only the most basic are added for now.
@@ -123,15 +137,12 @@ type TmpfsOptions struct {
 	// 	"":          true,
 	// 	"size":      true, X
 	// 	"mode":      true, X
-	// 	"uid":       true,
-	// 	"gid":       true,
+	// 	"uid":       true, X
+	// 	"gid":       true, X
 	// 	"nr_inodes": true,
 	// 	"nr_blocks": true,
 	// 	"mpol":      true,
 	// }
-	//
-	// Some of these may be straightforward to add, but others, such as
-	// uid/gid have implications in a clustered system.
 }
 
 // ClusterOptions specifies options for a Cluster volume.
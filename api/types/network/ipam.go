@@ -0,0 +1,20 @@
+package network // import "github.com/docker/docker/api/types/network"
+
+// IPAMReconcileReport is the result of a reconciliation of the allocations of
+// the IPAM drivers with the networks and endpoints.
+type IPAMReconcileReport struct {
+	// Leaks are the allocations owned by no network or endpoint.
+	Leaks []IPAMLeak
+}
+
+// IPAMLeak is an allocation of an IPAM driver owned by no network or endpoint.
+type IPAMLeak struct {
+	// Driver is the name of the IPAM driver.
+	Driver string
+	// PoolID is the ID of the pool in the IPAM driver.
+	PoolID string
+	// Address is the leaked address, or empty if the pool is leaked.
+	Address string `json:",omitempty"`
+	// Released is set if the allocation was released.
+	Released bool
+}
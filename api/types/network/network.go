@@ -39,6 +39,17 @@ func (cfg *EndpointIPAMConfig) Copy() *EndpointIPAMConfig {
 	return &cfgCopy
 }
 
+// AliasOptions are the DNS options of a network alias of a container.
+type AliasOptions struct {
+	// TTL is the TTL of the DNS answers for the alias, in seconds. The
+	// default TTL of the embedded DNS server is used if it is 0.
+	TTL uint32 `json:",omitempty"`
+	// Peers are the names or IDs of the containers which can resolve the
+	// alias. The alias can be resolved by all the containers on the network
+	// if it is empty.
+	Peers []string `json:",omitempty"`
+}
+
 // PeerInfo represents one peer of an overlay network
 type PeerInfo struct {
 	Name string
@@ -51,6 +62,12 @@ type EndpointSettings struct {
 	IPAMConfig *EndpointIPAMConfig
 	Links      []string
 	Aliases    []string
+	// AliasOptions are the DNS options of the aliases, by alias.
+	AliasOptions map[string]AliasOptions `json:",omitempty"`
+	// HealthGatedDNS holds back the DNS records of the container on the
+	// network until its healthcheck passes, and removes them while it is
+	// unhealthy.
+	HealthGatedDNS bool `json:",omitempty"`
 	// Operational data
 	NetworkID           string
 	EndpointID          string
@@ -80,6 +97,18 @@ type ServiceInfo struct {
 	Tasks        []Task
 }
 
+// DriverState is the runtime state of a network in its driver on the node,
+// such as the peers of an overlay network or the ports of a bridge.
+type DriverState struct {
+	// Driver is the name of the network driver.
+	Driver string
+	// State is the driver-specific state of the network.
+	State map[string]interface{} `json:",omitempty"`
+	// Error is set if the driver failed to report the state, which may then
+	// be incomplete.
+	Error string `json:",omitempty"`
+}
+
 // Copy makes a deep copy of `EndpointSettings`
 func (es *EndpointSettings) Copy() *EndpointSettings {
 	epCopy := *es
@@ -96,6 +125,14 @@ func (es *EndpointSettings) Copy() *EndpointSettings {
 		aliases := make([]string, 0, len(es.Aliases))
 		epCopy.Aliases = append(aliases, es.Aliases...)
 	}
+
+	if es.AliasOptions != nil {
+		epCopy.AliasOptions = make(map[string]AliasOptions, len(es.AliasOptions))
+		for alias, opts := range es.AliasOptions {
+			opts.Peers = append([]string(nil), opts.Peers...)
+			epCopy.AliasOptions[alias] = opts
+		}
+	}
 	return &epCopy
 }
 
@@ -122,5 +159,8 @@ var acceptedFilters = map[string]bool{
 
 // ValidateFilters validates the list of filter args with the available filters.
 func ValidateFilters(filter filters.Args) error {
-	return filter.Validate(acceptedFilters)
+	if err := filter.Validate(acceptedFilters); err != nil {
+		return err
+	}
+	return filter.ValidateLabelSelectors("label")
 }
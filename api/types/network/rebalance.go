@@ -0,0 +1,27 @@
+package network // import "github.com/docker/docker/api/types/network"
+
+// RebalanceReport is the result of a rebalance of the load balancers of the
+// services on a network.
+type RebalanceReport struct {
+	// LoadBalancers are the load balancers of the services on the network
+	// on this node.
+	LoadBalancers []LoadBalancerRebalance
+}
+
+// LoadBalancerRebalance is the result of the rebalance of the load balancer
+// of a service.
+type LoadBalancerRebalance struct {
+	ServiceID   string
+	ServiceName string
+	// VIP is the virtual IP of the service on the network.
+	VIP string
+	// Added are the backends which were missing from the load balancer.
+	Added []string `json:",omitempty"`
+	// Removed are the backends which were no longer part of the service.
+	Removed []string `json:",omitempty"`
+	// Reweighted are the backends whose weight was reset.
+	Reweighted []string `json:",omitempty"`
+	// Drained are the backends which receive no new connections during the
+	// drain period.
+	Drained []string `json:",omitempty"`
+}
@@ -0,0 +1,65 @@
+// Package pagination provides the cursors of the pages of the container and
+// image lists.
+package pagination // import "github.com/docker/docker/api/types/pagination"
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+)
+
+// NextCursorHeader is the HTTP header containing the cursor of the next page
+// of a list, if the page returned is cut at the limit of the request.
+const NextCursorHeader = "Docker-Next-Cursor"
+
+// Cursor is the position of the last object of a page of a list. Lists are
+// ordered by creation time, newest first, and then by ID, and the next page
+// of a list starts with the object following the cursor in this order. The
+// cursor does not refer to the object itself, so pages stay stable when the
+// object is removed.
+type Cursor struct {
+	// Created is the creation time of the object, in the unit the list is
+	// ordered by.
+	Created int64
+	// ID is the ID of the object.
+	ID string
+}
+
+// After reports whether an object created at created, with the given ID, is
+// after the cursor in the order of the list.
+func (c Cursor) After(created int64, id string) bool {
+	if created != c.Created {
+		return created < c.Created
+	}
+	return id > c.ID
+}
+
+// String returns the opaque representation of the cursor, as used in the API.
+func (c Cursor) String() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(c.Created, 10) + ":" + c.ID))
+}
+
+type invalidCursor string
+
+func (e invalidCursor) Error() string {
+	return "invalid cursor " + strconv.Quote(string(e))
+}
+
+func (invalidCursor) InvalidParameter() {}
+
+// Parse parses the opaque representation of a cursor.
+func Parse(s string) (Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, invalidCursor(s)
+	}
+	created, id, ok := strings.Cut(string(b), ":")
+	if !ok || id == "" {
+		return Cursor{}, invalidCursor(s)
+	}
+	c, err := strconv.ParseInt(created, 10, 64)
+	if err != nil {
+		return Cursor{}, invalidCursor(s)
+	}
+	return Cursor{Created: c, ID: id}, nil
+}
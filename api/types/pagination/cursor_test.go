@@ -0,0 +1,31 @@
+package pagination // import "github.com/docker/docker/api/types/pagination"
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	c := Cursor{Created: 1665000000123456789, ID: "4c0a8e8a9b"}
+	parsed, err := Parse(c.String())
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(parsed, c))
+}
+
+func TestParseInvalidCursor(t *testing.T) {
+	for _, s := range []string{"", "not base64!", "bm8tc2VwYXJhdG9y", "YWJjOmRlZg", "MTIzOg"} {
+		_, err := Parse(s)
+		assert.Check(t, is.ErrorContains(err, "invalid cursor"), s)
+	}
+}
+
+func TestCursorAfter(t *testing.T) {
+	c := Cursor{Created: 100, ID: "b"}
+	assert.Check(t, c.After(99, "a"))
+	assert.Check(t, c.After(100, "c"))
+	assert.Check(t, !c.After(100, "b"))
+	assert.Check(t, !c.After(100, "a"))
+	assert.Check(t, !c.After(101, "z"))
+}
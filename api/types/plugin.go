@@ -15,6 +15,9 @@ type Plugin struct {
 	// Required: true
 	Enabled bool `json:"Enabled"`
 
+	// health
+	Health *PluginHealth `json:"Health,omitempty"`
+
 	// Id
 	ID string `json:"Id,omitempty"`
 
@@ -96,6 +99,35 @@ type PluginConfig struct {
 	Rootfs *PluginConfigRootfs `json:"rootfs,omitempty"`
 }
 
+// PluginHealth Health of a running plugin, as reported by the liveness probes of its
+// socket. Plugins failing their probes are restarted.
+//
+// This field is omitted if the plugin is not running.
+//
+// <p><br /></p>
+//
+// > **Note**: This field was added in API v1.43.
+//
+// swagger:model PluginHealth
+type PluginHealth struct {
+
+	// Number of consecutive failed probes.
+	FailingStreak int64 `json:"FailingStreak,omitempty"`
+
+	// Error of the last failed probe.
+	LastError string `json:"LastError,omitempty"`
+
+	// Number of times the plugin was restarted in a row because it exited
+	// or failed its probes.
+	//
+	Restarts int64 `json:"Restarts,omitempty"`
+
+	// Status is one of `starting`, `healthy` or `unhealthy`.
+	//
+	// Enum: [starting healthy unhealthy]
+	Status string `json:"Status,omitempty"`
+}
+
 // PluginConfigArgs plugin config args
 // swagger:model PluginConfigArgs
 type PluginConfigArgs struct {
@@ -122,6 +154,7 @@ type PluginConfigArgs struct {
 type PluginConfigInterface struct {
 
 	// Protocol to use for clients connecting to the plugin.
+	// Enum: [ moby.plugins.http/v1 moby.plugins.grpc/v1]
 	ProtocolScheme string `json:"ProtocolScheme,omitempty"`
 
 	// socket
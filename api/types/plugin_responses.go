@@ -9,6 +9,13 @@ import (
 // PluginsListResponse contains the response for the Engine API
 type PluginsListResponse []*Plugin
 
+// Health statuses of plugins.
+const (
+	PluginStarting  = "starting"  // PluginStarting indicates that the plugin was started, but not probed yet.
+	PluginHealthy   = "healthy"   // PluginHealthy indicates that the plugin answers its probes.
+	PluginUnhealthy = "unhealthy" // PluginUnhealthy indicates that the plugin failed its probes, and is restarted.
+)
+
 // UnmarshalJSON implements json.Unmarshaler for PluginInterfaceType
 func (t *PluginInterfaceType) UnmarshalJSON(p []byte) error {
 	versionIndex := len(p)
@@ -134,8 +134,12 @@ type NetworkStats struct {
 	TxErrors uint64 `json:"tx_errors"`
 	// Outgoing packets dropped. Windows and Linux.
 	TxDropped uint64 `json:"tx_dropped"`
-	// Endpoint ID. Not used on Linux.
+	// Endpoint ID.
 	EndpointID string `json:"endpoint_id,omitempty"`
+	// ID of the network the endpoint is attached to. Not used on Windows.
+	NetworkID string `json:"network_id,omitempty"`
+	// Name of the network the endpoint is attached to. Not used on Windows.
+	NetworkName string `json:"network_name,omitempty"`
 	// Instance ID. Not used on Linux.
 	InstanceID string `json:"instance_id,omitempty"`
 }
@@ -178,4 +182,22 @@ type StatsJSON struct {
 
 	// Networks request version >=1.21
 	Networks map[string]NetworkStats `json:"networks,omitempty"`
+
+	// Execs holds the usage of the running exec processes which have resource
+	// limits, keyed by exec ID. Their usage is also included in the usage of
+	// the container. Linux only.
+	Execs map[string]ExecStats `json:"execs,omitempty"`
+}
+
+// ExecStats is the usage of an exec process which has resource limits,
+// accounted in the sub-cgroup of the container's cgroup it runs in.
+type ExecStats struct {
+	// Total CPU time consumed, in nanoseconds.
+	CPUUsage uint64 `json:"cpu_usage"`
+	// Current memory usage, in bytes.
+	MemoryUsage uint64 `json:"memory_usage"`
+	// Memory limit of the exec process, in bytes.
+	MemoryLimit uint64 `json:"memory_limit,omitempty"`
+	// Number of pids of the exec process and its children.
+	PidsCurrent uint64 `json:"pids_current"`
 }
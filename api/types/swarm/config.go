@@ -37,4 +37,10 @@ type ConfigReference struct {
 	Runtime    *ConfigReferenceRuntimeTarget `json:",omitempty"`
 	ConfigID   string
 	ConfigName string
+
+	// Latest makes the reference follow the latest version of the config.
+	// When the config is rotated, the service is updated to use the new
+	// version. Only references with a File target can follow the latest
+	// version.
+	Latest bool `json:",omitempty"`
 }
@@ -39,10 +39,58 @@ type CredentialSpec struct {
 	Registry string
 }
 
+// SeccompMode is the type used for the enumeration of possible seccomp modes
+// in SeccompOpts
+type SeccompMode string
+
+const (
+	// SeccompModeDefault DEFAULT applies the default seccomp profile of the
+	// engine.
+	SeccompModeDefault SeccompMode = "default"
+	// SeccompModeUnconfined UNCONFINED runs the container without seccomp
+	// filtering.
+	SeccompModeUnconfined SeccompMode = "unconfined"
+	// SeccompModeCustom CUSTOM applies the profile in SeccompOpts.Profile.
+	SeccompModeCustom SeccompMode = "custom"
+)
+
+// SeccompOpts defines the seccomp profile of the container.
+type SeccompOpts struct {
+	Mode SeccompMode `json:",omitempty"`
+	// Profile is the JSON seccomp profile to apply. It is only used if
+	// Mode is "custom".
+	Profile []byte `json:",omitempty"`
+}
+
+// AppArmorMode is the type used for the enumeration of possible AppArmor
+// modes in AppArmorOpts
+type AppArmorMode string
+
+const (
+	// AppArmorModeDefault DEFAULT applies the default AppArmor profile of
+	// the engine.
+	AppArmorModeDefault AppArmorMode = "default"
+	// AppArmorModeDisabled DISABLED runs the container without AppArmor.
+	AppArmorModeDisabled AppArmorMode = "disabled"
+	// AppArmorModeCustom CUSTOM applies the profile named in
+	// AppArmorOpts.Profile, which must be loaded on the node.
+	AppArmorModeCustom AppArmorMode = "custom"
+)
+
+// AppArmorOpts defines the AppArmor profile of the container.
+type AppArmorOpts struct {
+	Mode AppArmorMode `json:",omitempty"`
+	// Profile is the name of the AppArmor profile to apply. It is only used
+	// if Mode is "custom".
+	Profile string `json:",omitempty"`
+}
+
 // Privileges defines the security options for the container.
 type Privileges struct {
 	CredentialSpec *CredentialSpec
 	SELinuxContext *SELinuxContext
+	Seccomp        *SeccompOpts  `json:",omitempty"`
+	AppArmor       *AppArmorOpts `json:",omitempty"`
 }
 
 // ContainerSpec represents the spec of a container.
@@ -1,5 +1,7 @@
 package swarm // import "github.com/docker/docker/api/types/swarm"
 
+import "time"
+
 // Node represents a node.
 type Node struct {
 	ID string
@@ -22,6 +24,20 @@ type NodeSpec struct {
 	Annotations
 	Role         NodeRole         `json:",omitempty"`
 	Availability NodeAvailability `json:",omitempty"`
+
+	// DrainOptions, if set when the Availability is "drain", drains the
+	// node gradually: the tasks of replicated services are moved to other
+	// nodes while respecting the update parallelism of each service, and
+	// the node is only fully drained once they are running elsewhere.
+	DrainOptions *NodeDrainOptions `json:",omitempty"`
+}
+
+// NodeDrainOptions controls how a node is drained gradually.
+type NodeDrainOptions struct {
+	// MaxDuration is how long the node may take to drain. Once exceeded, the
+	// remaining tasks on the node are stopped at once. If this field is
+	// empty, there is no limit.
+	MaxDuration time.Duration `json:",omitempty"`
 }
 
 // NodeRole represents the role of a node.
@@ -95,6 +111,37 @@ type NodeStatus struct {
 	State   NodeState `json:",omitempty"`
 	Message string    `json:",omitempty"`
 	Addr    string    `json:",omitempty"`
+
+	// Drain is the status of the gradual drain of the node, if the node is
+	// drained with DrainOptions.
+	Drain *NodeDrainStatus `json:",omitempty"`
+}
+
+// NodeDrainState represents the state of the gradual drain of a node.
+type NodeDrainState string
+
+const (
+	// NodeDrainStateDraining DRAINING
+	NodeDrainStateDraining NodeDrainState = "draining"
+	// NodeDrainStateComplete COMPLETE
+	NodeDrainStateComplete NodeDrainState = "complete"
+	// NodeDrainStateDeadlineExceeded DEADLINE_EXCEEDED
+	NodeDrainStateDeadlineExceeded NodeDrainState = "deadline-exceeded"
+)
+
+// NodeDrainStatus is the status of the gradual drain of a node.
+type NodeDrainStatus struct {
+	// State is "draining" while tasks are moved to other nodes, "complete"
+	// once all tasks of replicated services are running elsewhere, and
+	// "deadline-exceeded" if the MaxDuration was exceeded before.
+	State NodeDrainState
+
+	// StartedAt is the time the drain started.
+	StartedAt time.Time `json:",omitempty"`
+
+	// CompletedAt is the time the drain completed, or its deadline was
+	// exceeded.
+	CompletedAt *time.Time `json:",omitempty"`
 }
 
 // Reachability represents the reachability of a node.
@@ -15,11 +15,27 @@ type SecretSpec struct {
 	Data   []byte  `json:",omitempty"`
 	Driver *Driver `json:",omitempty"` // name of the secrets driver used to fetch the secret's value from an external secret store
 
+	// Provider is the secret provider the value of the secret is fetched
+	// from, by the node running a task, when the task starts. The value is
+	// never stored by the managers. Data must be empty if it is set.
+	Provider *SecretProvider `json:",omitempty"`
+
 	// Templating controls whether and how to evaluate the secret payload as
 	// a template. If it is not set, no templating is used.
 	Templating *Driver `json:",omitempty"`
 }
 
+// SecretProvider is a secret provider plugin, which fetches the value of a
+// secret from an external secret store.
+type SecretProvider struct {
+	// Name is the name of the secret provider plugin.
+	Name string
+
+	// Options are passed to the plugin, such as the path of the secret in
+	// the external store.
+	Options map[string]string `json:",omitempty"`
+}
+
 // SecretReferenceFileTarget is a file target in a secret reference
 type SecretReferenceFileTarget struct {
 	Name string
@@ -33,4 +49,9 @@ type SecretReference struct {
 	File       *SecretReferenceFileTarget
 	SecretID   string
 	SecretName string
+
+	// Latest makes the reference follow the latest version of the secret.
+	// When the secret is rotated, the service is updated to use the new
+	// version.
+	Latest bool `json:",omitempty"`
 }
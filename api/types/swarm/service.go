@@ -100,14 +100,43 @@ type ReplicatedJob struct {
 	//
 	// If this field is empty, the value of MaxConcurrent will be used.
 	TotalCompletions *uint64 `json:",omitempty"`
+
+	// Schedule, if set, runs the job periodically.
+	Schedule *JobSchedule `json:",omitempty"`
 }
 
 // GlobalJob is the type of a Service which executes a Task on every Node
 // matching the Service's placement constraints. These tasks run to completion
 // and then exit.
-//
-// This type is deliberately empty.
-type GlobalJob struct{}
+type GlobalJob struct {
+	// Schedule, if set, runs the job periodically.
+	Schedule *JobSchedule `json:",omitempty"`
+}
+
+const (
+	// JobConcurrencyReplace REPLACE
+	JobConcurrencyReplace = "replace"
+	// JobConcurrencyForbid FORBID
+	JobConcurrencyForbid = "forbid"
+)
+
+// JobSchedule is the schedule of a periodic job.
+type JobSchedule struct {
+	// Cron is the cron expression, in UTC, that defines when the job is
+	// executed.
+	Cron string
+
+	// ConcurrencyPolicy defines what happens if the job is due while tasks
+	// of the previous execution are still running. With "replace", the
+	// default, these tasks are stopped, and the job is executed. With
+	// "forbid", the execution is skipped.
+	ConcurrencyPolicy string `json:",omitempty"`
+
+	// HistoryLimit is the number of previous executions of which the tasks
+	// are kept. If this field is empty, tasks are kept until they are
+	// removed by the task history retention of the swarm.
+	HistoryLimit *uint64 `json:",omitempty"`
+}
 
 const (
 	// UpdateFailureActionPause PAUSE
@@ -199,4 +228,8 @@ type JobStatus struct {
 	// LastExecution is the time that the job was last executed, as observed by
 	// Swarm manager.
 	LastExecution time.Time `json:",omitempty"`
+
+	// NextExecution is the time that the job is next executed, if the job
+	// has a schedule.
+	NextExecution *time.Time `json:",omitempty"`
 }
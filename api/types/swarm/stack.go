@@ -0,0 +1,54 @@
+package swarm // import "github.com/docker/docker/api/types/swarm"
+
+// StackNamespaceLabel is the label that is set, on the services, networks,
+// secrets, and configs of a stack, to the name of the stack.
+const StackNamespaceLabel = "com.docker.stack.namespace"
+
+// StackSpec represents the spec of a stack: a set of services, and the
+// networks, secrets, and configs they use, that are deployed together.
+//
+// Objects are keyed by their name in the stack, and are created with the
+// name of the stack as prefix, as in "<stack>_<name>". Services refer to the
+// networks, secrets, and configs of the stack by their name in the stack.
+//
+// The daemon does not parse compose files: clients convert them to a
+// StackSpec.
+type StackSpec struct {
+	Name     string
+	Services map[string]ServiceSpec `json:",omitempty"`
+	Networks map[string]NetworkSpec `json:",omitempty"`
+	Secrets  map[string]SecretSpec  `json:",omitempty"`
+	Configs  map[string]ConfigSpec  `json:",omitempty"`
+
+	// DependsOn lists, by service name, the services of the stack that a
+	// service depends on, and which are deployed before it.
+	DependsOn map[string][]string `json:",omitempty"`
+}
+
+// Stack represents the status of a deployed stack.
+type Stack struct {
+	Name     string
+	Services []StackService `json:",omitempty"`
+
+	// Converged is set once no service of the stack is being updated, and
+	// all their desired tasks are running.
+	Converged bool
+}
+
+// StackService represents the status of a service of a stack.
+type StackService struct {
+	ID           string
+	Name         string
+	RunningTasks uint64
+	DesiredTasks uint64
+	UpdateStatus *UpdateStatus `json:",omitempty"`
+}
+
+// StackDeployResponse is the response of a stack deploy. It lists the names
+// of the objects that were created, updated, and removed.
+type StackDeployResponse struct {
+	Created  []string `json:",omitempty"`
+	Updated  []string `json:",omitempty"`
+	Removed  []string `json:",omitempty"`
+	Warnings []string `json:",omitempty"`
+}
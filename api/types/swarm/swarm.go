@@ -15,6 +15,14 @@ type ClusterInfo struct {
 	DefaultAddrPool        []string
 	SubnetSize             uint32
 	DataPathPort           uint32
+
+	// UnlockKeyRotatedAt is the time the unlock key was last rotated, if it
+	// was rotated automatically or with the RotateManagerUnlockKey flag.
+	UnlockKeyRotatedAt *time.Time `json:",omitempty"`
+
+	// NextUnlockKeyRotation is the time the unlock key is rotated next, if
+	// EncryptionConfig.UnlockKeyRotationInterval is set.
+	NextUnlockKeyRotation *time.Time `json:",omitempty"`
 }
 
 // Swarm represents a swarm.
@@ -67,6 +75,11 @@ type EncryptionConfig struct {
 	// should be encrypted at rest in such a way that they must be unlocked
 	// before the manager node starts up again.
 	AutoLockManagers bool
+
+	// UnlockKeyRotationInterval is how often the unlock key of the managers
+	// is rotated automatically, if AutoLockManagers is set. If this field is
+	// empty, the unlock key is only rotated on request.
+	UnlockKeyRotationInterval time.Duration `json:",omitempty"`
 }
 
 // RaftConfig represents raft configuration.
@@ -210,6 +223,14 @@ type Info struct {
 
 	Cluster *ClusterInfo `json:",omitempty"`
 
+	// DataPathKeysRotatedAt is the time the node last received rotated data
+	// path encryption keys, if it did since the daemon started.
+	DataPathKeysRotatedAt *time.Time `json:",omitempty"`
+
+	// NextDataPathKeyRotation is the time the data path encryption keys are
+	// expected to be rotated next. The managers rotate them every 12 hours.
+	NextDataPathKeyRotation *time.Time `json:",omitempty"`
+
 	Warnings []string `json:",omitempty"`
 }
 
@@ -1,6 +1,7 @@
 package types // import "github.com/docker/docker/api/types"
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -9,6 +10,7 @@ import (
 	"time"
 
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/mount"
 	"github.com/docker/docker/api/types/network"
@@ -24,6 +26,11 @@ const (
 
 	// MediaTypeMultiplexedStream is vendor specific MIME-Type set for stdin/stdout/stderr multiplexed streams
 	MediaTypeMultiplexedStream = "application/vnd.docker.multiplexed-stream"
+
+	// MediaTypeProgressV2 is vendor specific MIME-Type of the v2 progress
+	// stream of pull, push, and build, which clients request in the Accept
+	// header.
+	MediaTypeProgressV2 = "application/vnd.docker.progress.v2+json"
 )
 
 // RootFS returns Image's RootFS description including the layer IDs.
@@ -313,6 +320,10 @@ type Info struct {
 	ProductLicense      string               `json:",omitempty"`
 	DefaultAddressPools []NetworkAddressPool `json:",omitempty"`
 
+	// StartupPhases are the durations of the phases of the startup of the
+	// daemon. They are only returned by verbose requests.
+	StartupPhases []StartupPhase `json:",omitempty"`
+
 	// Warnings contains a slice of warnings that occurred  while collecting
 	// system information. These warnings are intended to be informational
 	// messages for the user, and are not intended to be parsed / used for
@@ -320,6 +331,24 @@ type Info struct {
 	Warnings []string
 }
 
+// StartupPhase is a phase of the startup of the daemon.
+type StartupPhase struct {
+	// Name is the name of the phase. The phases within another phase are
+	// prefixed with its name, for example "restore.network-controller".
+	Name string
+	// Started is when the phase started.
+	Started time.Time
+	// Duration is the duration of the phase, in nanoseconds.
+	Duration time.Duration
+}
+
+// InfoOptions holds parameters to get the information about the daemon.
+type InfoOptions struct {
+	// Verbose includes the details which are only useful for debugging,
+	// such as the phases of the startup of the daemon.
+	Verbose bool
+}
+
 // KeyValue holds a key/value pair
 type KeyValue struct {
 	Key, Value string
@@ -582,6 +611,9 @@ type NetworkResource struct {
 	Labels     map[string]string              // Labels holds metadata specific to the network being created
 	Peers      []network.PeerInfo             `json:",omitempty"` // List of peer nodes for an overlay network
 	Services   map[string]network.ServiceInfo `json:",omitempty"`
+	// DriverState is the runtime state of the network in its driver on the
+	// node. It is only set by verbose inspects, and if the driver reports it.
+	DriverState *network.DriverState `json:",omitempty"`
 }
 
 // EndpointResource contains network resources allocated and used for a container in a network
@@ -679,6 +711,8 @@ const (
 	VolumeObject DiskUsageObject = "volume"
 	// BuildCacheObject represents a build-cache DiskUsageObject.
 	BuildCacheObject DiskUsageObject = "build-cache"
+	// NetworkObject represents a network DiskUsageObject.
+	NetworkObject DiskUsageObject = "network"
 )
 
 // DiskUsageOptions holds parameters for system disk usage query.
@@ -697,6 +731,35 @@ type DiskUsage struct {
 	Volumes     []*volume.Volume
 	BuildCache  []*BuildCache
 	BuilderSize int64 `json:",omitempty"` // Deprecated: deprecated in API 1.38, and no longer used since API 1.40.
+
+	// BuildCacheUsage is the disk usage of the build cache of each builder,
+	// broken down by type and age of the cache records. It was added in
+	// API 1.43.
+	BuildCacheUsage []*BuildCacheUsage `json:",omitempty"`
+	// Networks is the resource usage of each network. It was added in
+	// API 1.43.
+	Networks []*NetworkUsage `json:",omitempty"`
+}
+
+// WatchEvent is a state change of an object, streamed by the Engine API:
+// GET "/events/watch"
+type WatchEvent struct {
+	// Type is the type of the object.
+	Type events.Type
+	// Action is the action of the event that changed the object, or "sync"
+	// for the objects listed when the watch starts.
+	Action string
+	// ID is the ID of the object, or its name for volumes.
+	ID       string
+	TimeNano int64
+
+	// Container, Image, Volume or Network is the state of the object after
+	// the change, depending on Type. It is not set if the object was removed,
+	// or no longer matches the filters of the watch.
+	Container *Container       `json:",omitempty"`
+	Image     *ImageSummary    `json:",omitempty"`
+	Volume    *volume.Volume   `json:",omitempty"`
+	Network   *NetworkResource `json:",omitempty"`
 }
 
 // ContainersPruneReport contains the response for Engine API:
@@ -798,6 +861,138 @@ type BuildCache struct {
 	UsageCount int
 }
 
+// BuildCacheUsage is the disk usage of the build cache of a builder.
+type BuildCacheUsage struct {
+	// Builder is the name of the builder.
+	Builder string
+	// Size is the amount of disk space used by the build cache (in bytes).
+	Size int64
+	// Reclaimable is the amount of disk space used by cache records that are
+	// neither in use nor shared, and can be pruned (in bytes).
+	Reclaimable int64
+	// ByType breaks down the usage by type of cache record.
+	ByType []BuildCacheTypeUsage
+	// ByAge breaks down the usage by the time since the cache records were
+	// last used.
+	ByAge []BuildCacheAgeUsage
+}
+
+// BuildCacheTypeUsage is the disk usage of the build cache records of a type.
+type BuildCacheTypeUsage struct {
+	// Type is the cache record type.
+	Type string
+	// Count is the number of cache records.
+	Count int
+	// Size is the amount of disk space used by the cache records (in bytes).
+	Size int64
+	// Reclaimable is the amount of disk space used by cache records that are
+	// neither in use nor shared (in bytes).
+	Reclaimable int64
+}
+
+// BuildCacheAgeUsage is the disk usage of the build cache records last used
+// within a range of time.
+type BuildCacheAgeUsage struct {
+	// MinAge is the minimum time since the cache records were last used, in
+	// seconds.
+	MinAge int64
+	// MaxAge is the maximum time since the cache records were last used, in
+	// seconds. It is omitted for the oldest cache records.
+	MaxAge int64 `json:",omitempty"`
+	// Count is the number of cache records.
+	Count int
+	// Size is the amount of disk space used by the cache records (in bytes).
+	Size int64
+	// Reclaimable is the amount of disk space used by cache records that are
+	// neither in use nor shared (in bytes).
+	Reclaimable int64
+}
+
+// NetworkUsage is the resource usage of a network.
+type NetworkUsage struct {
+	// ID is the ID of the network.
+	ID string
+	// Name is the name of the network.
+	Name string
+	// Driver is the network driver.
+	Driver string
+	// Scope is the scope of the network.
+	Scope string
+	// Subnets are the subnets of the network.
+	Subnets []string
+	// Endpoints is the number of endpoints connected to the network.
+	Endpoints int
+	// Sandboxes is the number of network sandboxes, such as containers,
+	// with an endpoint in the network.
+	Sandboxes int
+	// AllocatedIPs is the number of addresses allocated to the endpoints
+	// and gateways of the network.
+	AllocatedIPs int
+}
+
+// BuildRecord contains information about a BuildKit build, as recorded by the
+// daemon once the build completed.
+type BuildRecord struct {
+	// ID is the unique ID of the build.
+	ID string
+	// Frontend is the BuildKit frontend that ran the build.
+	Frontend string
+	// FrontendAttrs are the options of the frontend, such as the target of
+	// the build. The build args are not included.
+	FrontendAttrs map[string]string `json:",omitempty"`
+	// BuildArgs are the names of the build args of the build. Their values
+	// are not recorded, as they may hold secrets.
+	BuildArgs []string `json:",omitempty"`
+	// Context is the remote context of the build, such as a git URL, with
+	// its credentials redacted. It is empty if the client sent the build
+	// context.
+	Context string `json:",omitempty"`
+	// Tags are the tags requested for the image produced by the build.
+	Tags []string `json:",omitempty"`
+	// ImageID is the ID of the image produced by the build, if any.
+	ImageID string `json:",omitempty"`
+	// Materials are the images resolved by the build, such as its base
+	// images.
+	Materials []BuildMaterial `json:",omitempty"`
+	// CreatedAt is the date and time at which the build started.
+	CreatedAt time.Time
+	// CompletedAt is the date and time at which the build completed.
+	CompletedAt time.Time
+	// Error is the error the build failed with, if any.
+	Error string `json:",omitempty"`
+	// NumSteps is the number of steps of the build, and NumCachedSteps the
+	// number of these steps that were cached.
+	NumSteps       int
+	NumCachedSteps int
+	// Provenance is the SLSA provenance of the build, as an in-toto
+	// statement. It is only set when inspecting a build record.
+	Provenance json.RawMessage `json:",omitempty"`
+}
+
+// BuildMaterial is an image resolved by a build.
+type BuildMaterial struct {
+	// URI is the reference of the image.
+	URI string
+	// Digest is the digest the reference resolved to.
+	Digest string
+}
+
+// BuildFrontend is a BuildKit frontend image registered with the daemon.
+// Builds whose syntax directive, or gateway source, refers to the frontend by
+// name run the image the frontend is pinned to, without pulling it.
+type BuildFrontend struct {
+	// Name is the reference builds refer to the frontend by, such as
+	// "docker/dockerfile:1".
+	Name string
+	// Image is the reference of the image of the frontend, pinned by digest.
+	// The image must be available locally when the frontend is registered.
+	Image string
+	// ImageID is the ID of the image of the frontend.
+	ImageID string `json:",omitempty"`
+	// CreatedAt is the date and time at which the frontend was registered.
+	CreatedAt time.Time `json:",omitempty"`
+}
+
 // BuildCachePruneOptions hold parameters to prune the build cache
 type BuildCachePruneOptions struct {
 	All         bool
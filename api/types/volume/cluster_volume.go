@@ -63,6 +63,24 @@ type ClusterVolumeSpec struct {
 	// Availability, this allows the user to take volumes offline in order to
 	// update or delete them.
 	Availability Availability `json:",omitempty"`
+
+	// SnapshotSchedule defines when the managers take snapshots of the
+	// volume, with the controller service of the CSI plugin. If nil,
+	// snapshots are only taken on demand.
+	SnapshotSchedule *SnapshotSchedule `json:",omitempty"`
+}
+
+// SnapshotSchedule is the schedule of the snapshots of a cluster volume.
+type SnapshotSchedule struct {
+	// Cron is the cron expression, in UTC, that defines when snapshots of
+	// the volume are taken. An empty expression removes the schedule when
+	// the volume is updated.
+	Cron string
+
+	// Retain is the number of scheduled snapshots that are kept. Older
+	// scheduled snapshots are removed. Snapshots taken on demand are never
+	// removed. If zero, all scheduled snapshots are kept.
+	Retain uint64 `json:",omitempty"`
 }
 
 // Availability specifies the availability of the volume.
@@ -20,6 +20,17 @@ type CreateOptions struct {
 	//
 	DriverOpts map[string]string `json:"DriverOpts,omitempty"`
 
+	// Name of an existing volume to populate the new volume with. Use the
+	// `volume@snapshot` form to populate the volume from a snapshot.
+	//
+	From string `json:"From,omitempty"`
+
+	// Reference of an image to populate the new volume with. The image must
+	// be present locally. Volumes created from an image are mounted
+	// read-only into containers.
+	//
+	Image string `json:"Image,omitempty"`
+
 	// User-defined key/value metadata.
 	Labels map[string]string `json:"Labels,omitempty"`
 
@@ -0,0 +1,12 @@
+package volume // import "github.com/docker/docker/api/types/volume"
+
+// ExportOptions holds parameters to export the contents of a volume as a
+// tar archive.
+type ExportOptions struct {
+	// Compression of the archive; one of "none" (default), "gzip", or "zstd".
+	Compression string
+
+	// Quiesce pauses the running containers that use the volume while its
+	// contents are being exported, so that the archive is consistent.
+	Quiesce bool
+}
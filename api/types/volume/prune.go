@@ -0,0 +1,37 @@
+package volume // import "github.com/docker/docker/api/types/volume"
+
+// Status of an asynchronous volume prune.
+const (
+	PruneJobRunning   = "running"
+	PruneJobCompleted = "completed"
+	PruneJobCanceled  = "canceled"
+	PruneJobFailed    = "failed"
+)
+
+// PruneJob is the progress, or the result, of an asynchronous volume prune.
+type PruneJob struct {
+	// ID of the prune job.
+	ID string `json:"ID"`
+
+	// Status of the prune job; one of "running", "completed", "canceled",
+	// or "failed".
+	Status string `json:"Status"`
+
+	// Total is the number of volumes that matched the prune filters.
+	Total int `json:"Total"`
+
+	// VolumesDeleted are the names of the volumes that were deleted so far.
+	VolumesDeleted []string `json:"VolumesDeleted"`
+
+	// SpaceReclaimed is the disk space reclaimed so far, in bytes.
+	SpaceReclaimed uint64 `json:"SpaceReclaimed"`
+
+	// Error is set if the prune job failed.
+	Error string `json:"Error,omitempty"`
+
+	// Date/Time the prune job was started.
+	StartedAt string `json:"StartedAt"`
+
+	// Date/Time the prune job finished, if it is no longer running.
+	FinishedAt string `json:"FinishedAt,omitempty"`
+}
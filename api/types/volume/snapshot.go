@@ -0,0 +1,27 @@
+package volume // import "github.com/docker/docker/api/types/volume"
+
+// Snapshot is a point-in-time copy of a volume.
+type Snapshot struct {
+	// Name of the snapshot.
+	Name string `json:"Name"`
+
+	// Name of the volume the snapshot was taken of.
+	Volume string `json:"Volume"`
+
+	// Name of the volume driver that manages the snapshot.
+	Driver string `json:"Driver"`
+
+	// Date/Time the snapshot was taken.
+	CreatedAt string `json:"CreatedAt,omitempty"`
+
+	// Space used by the snapshot in bytes, or -1 if the driver does not
+	// report it.
+	Size int64 `json:"Size"`
+}
+
+// SnapshotCreateOptions holds parameters to take a snapshot of a volume.
+type SnapshotCreateOptions struct {
+	// Name of the snapshot. If not specified, a name is generated from the
+	// current time.
+	Name string `json:"Name,omitempty"`
+}
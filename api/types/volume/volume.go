@@ -72,4 +72,10 @@ type UsageData struct {
 	//
 	// Required: true
 	Size int64 `json:"Size"`
+
+	// The size limit of the volume (in bytes), as configured with the
+	// `size` option of the `"local"` volume driver. This field is omitted
+	// if the volume has no size limit.
+	//
+	SizeLimit int64 `json:"SizeLimit,omitempty"`
 }
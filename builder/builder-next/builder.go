@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -29,6 +31,9 @@ import (
 	"github.com/moby/buildkit/session"
 	"github.com/moby/buildkit/util/entitlements"
 	"github.com/moby/buildkit/util/tracing"
+	"github.com/moby/buildkit/util/urlutil"
+	digest "github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/pkg/errors"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
@@ -77,12 +82,25 @@ type Opt struct {
 	IdentityMapping     idtools.IdentityMapping
 	DNSConfig           config.DNSConfig
 	ApparmorProfile     string
+
+	// Secrets returns the value of the daemon-managed secrets builds can
+	// use, if set.
+	Secrets SecretGetter
 }
 
 // Builder can build using BuildKit backend
 type Builder struct {
 	controller     *control.Controller
 	dnsconfig      config.DNSConfig
+	cacheConfig    config.BuilderCacheConfig
+	resources      config.BuilderResources
+	cgroupParent   string
+	rootless       bool
+	graphDriver    string
+	scratch        *scratchLimits
+	secrets        *buildSecrets
+	history        *buildHistory
+	frontends      *frontendRegistry
 	reqBodyHandler *reqBodyHandler
 
 	mu   sync.Mutex
@@ -91,15 +109,45 @@ type Builder struct {
 
 // New creates a new builder
 func New(opt Opt) (*Builder, error) {
+	if opt.BuilderConfig.Resources != (config.BuilderResources{}) {
+		if err := checkBuildCgroups(opt.DefaultCgroupParent, opt.Rootless); err != nil {
+			return nil, err
+		}
+	}
+	graphDriver := opt.Dist.LayerStore.DriverName()
+	if opt.BuilderConfig.Resources.ScratchLimit != "" {
+		if err := checkScratchLimit(graphDriver); err != nil {
+			return nil, err
+		}
+	}
+	scratch := newScratchLimits()
 	reqHandler := newReqBodyHandler(tracing.DefaultTransport)
+	secrets := newBuildSecrets(opt.SessionManager, opt.Secrets, opt.BuilderConfig.GitAuth)
+	history, err := newBuildHistory(filepath.Join(opt.Root, "history"))
+	if err != nil {
+		return nil, err
+	}
+	frontends, err := newFrontendRegistry(filepath.Join(opt.Root, "frontends.json"), opt.Dist.ReferenceStore)
+	if err != nil {
+		return nil, err
+	}
 
-	c, err := newController(reqHandler, opt)
+	c, err := newController(reqHandler, secrets, history, frontends, scratch, opt)
 	if err != nil {
 		return nil, err
 	}
 	b := &Builder{
 		controller:     c,
 		dnsconfig:      opt.DNSConfig,
+		cacheConfig:    opt.BuilderConfig.Cache,
+		resources:      opt.BuilderConfig.Resources,
+		cgroupParent:   opt.DefaultCgroupParent,
+		rootless:       opt.Rootless,
+		graphDriver:    graphDriver,
+		scratch:        scratch,
+		secrets:        secrets,
+		history:        history,
+		frontends:      frontends,
 		reqBodyHandler: reqHandler,
 		jobs:           map[string]*buildJob{},
 	}
@@ -111,6 +159,38 @@ func (b *Builder) RegisterGRPC(s *grpc.Server) {
 	b.controller.Register(s)
 }
 
+// BuildRecords returns the records of the completed builds, newest first.
+func (b *Builder) BuildRecords(ctx context.Context) ([]types.BuildRecord, error) {
+	return b.history.list()
+}
+
+// BuildRecord returns the record of a completed build, with its provenance.
+func (b *Builder) BuildRecord(ctx context.Context, id string) (types.BuildRecord, error) {
+	rec, err := b.history.get(id)
+	if err != nil {
+		return rec, err
+	}
+	rec.Provenance, err = provenance(rec)
+	return rec, err
+}
+
+// BuildFrontends returns the frontends registered with the builder, by name.
+func (b *Builder) BuildFrontends(ctx context.Context) ([]types.BuildFrontend, error) {
+	return b.frontends.list(), nil
+}
+
+// RegisterBuildFrontend registers a frontend image, pinned by digest, which
+// builds run for the syntax directives and gateway builds that refer to the
+// frontend by name.
+func (b *Builder) RegisterBuildFrontend(ctx context.Context, f types.BuildFrontend) (types.BuildFrontend, error) {
+	return b.frontends.register(f)
+}
+
+// RemoveBuildFrontend removes a registered frontend by name.
+func (b *Builder) RemoveBuildFrontend(ctx context.Context, name string) error {
+	return b.frontends.unregister(name)
+}
+
 // Cancel cancels a build using ID
 func (b *Builder) Cancel(ctx context.Context, id string) error {
 	b.mu.Lock()
@@ -331,6 +411,38 @@ func (b *Builder) Build(ctx context.Context, opt backend.BuildConfig) (*builder.
 		frontendAttrs["ulimit"] = ulimits
 	}
 
+	resources, err := buildResources(*opt.Options, b.resources)
+	if err != nil {
+		return nil, err
+	}
+	scratchLimit, err := buildScratchLimit(*opt.Options, b.resources)
+	if err != nil {
+		return nil, err
+	}
+	if scratchLimit > 0 {
+		if err := checkScratchLimit(b.graphDriver); err != nil {
+			return nil, err
+		}
+		if resources == nil {
+			// the steps of the build are identified by its cgroup
+			resources = &specs.LinuxResources{}
+		}
+	}
+	if resources != nil {
+		if err := checkBuildCgroups(b.cgroupParent, b.rootless); err != nil {
+			return nil, err
+		}
+		cgroupParent, removeCgroup, err := newBuildCgroup(b.cgroupParent, id, resources)
+		if err != nil {
+			return nil, err
+		}
+		defer removeCgroup()
+		frontendAttrs["cgroup-parent"] = cgroupParent
+		if scratchLimit > 0 {
+			defer b.scratch.add(cgroupParent, scratchLimit)()
+		}
+	}
+
 	exporterName := ""
 	exporterAttrs := map[string]string{}
 
@@ -361,6 +473,37 @@ func (b *Builder) Build(ctx context.Context, opt backend.BuildConfig) (*builder.
 			})
 		}
 	}
+	for _, c := range opt.Options.CacheExports {
+		cache.Exports = append(cache.Exports, &controlapi.CacheOptionsEntry{
+			Type:  c.Type,
+			Attrs: c.Attrs,
+		})
+	}
+	for _, c := range opt.Options.CacheImports {
+		cache.Imports = append(cache.Imports, &controlapi.CacheOptionsEntry{
+			Type:  c.Type,
+			Attrs: c.Attrs,
+		})
+	}
+
+	// The caches configured for the builder are only used by builds that
+	// do not specify their own, as BuildKit exports to a single cache.
+	if len(cache.Exports) == 0 {
+		for _, c := range b.cacheConfig.Exports {
+			cache.Exports = append(cache.Exports, &controlapi.CacheOptionsEntry{
+				Type:  c.Type,
+				Attrs: c.Attrs,
+			})
+		}
+	}
+	if len(cache.Imports) == 0 && len(opt.Options.CacheFrom) == 0 {
+		for _, c := range b.cacheConfig.Imports {
+			cache.Imports = append(cache.Imports, &controlapi.CacheOptionsEntry{
+				Type:  c.Type,
+				Attrs: c.Attrs,
+			})
+		}
+	}
 
 	req := &controlapi.SolveRequest{
 		Ref:           id,
@@ -376,6 +519,40 @@ func (b *Builder) Build(ctx context.Context, opt backend.BuildConfig) (*builder.
 		req.Entitlements = append(req.Entitlements, entitlements.EntitlementNetworkHost)
 	}
 
+	stopSecrets, err := b.secrets.start(ctx, opt.Options.SessionID, opt.Options.Secrets)
+	if err != nil {
+		return nil, err
+	}
+	defer stopSecrets()
+
+	rec := &types.BuildRecord{
+		ID:            id,
+		Frontend:      req.Frontend,
+		FrontendAttrs: make(map[string]string, len(frontendAttrs)),
+		Tags:          opt.Options.Tags,
+	}
+	for k, v := range frontendAttrs {
+		switch {
+		case strings.HasPrefix(k, "build-arg:"):
+			// build args may hold secrets, so only their names are
+			// recorded.
+			rec.BuildArgs = append(rec.BuildArgs, strings.TrimPrefix(k, "build-arg:"))
+		case k == "context" || strings.HasPrefix(k, "context:"):
+			rec.FrontendAttrs[k] = urlutil.RedactCredentials(v)
+		default:
+			rec.FrontendAttrs[k] = v
+		}
+	}
+	sort.Strings(rec.BuildArgs)
+	if remoteContext := opt.Options.RemoteContext; remoteContext != "" && remoteContext != "client-session" {
+		rec.Context = urlutil.RedactCredentials(remoteContext)
+	} else {
+		// the context is sent by the client, through a URL internal to the
+		// builder
+		delete(rec.FrontendAttrs, "context")
+	}
+	finishRecord := b.history.start(opt.Options.SessionID, rec)
+
 	aux := streamformatter.AuxFormatter{Writer: opt.ProgressWriter.Output}
 
 	eg, ctx := errgroup.WithContext(ctx)
@@ -407,7 +584,21 @@ func (b *Builder) Build(ctx context.Context, opt backend.BuildConfig) (*builder.
 	})
 
 	eg.Go(func() error {
+		cached := make(map[digest.Digest]bool)
+		defer func() {
+			rec.NumSteps = len(cached)
+			for _, c := range cached {
+				if c {
+					rec.NumCachedSteps++
+				}
+			}
+		}()
 		for sr := range ch {
+			for _, v := range sr.Vertexes {
+				if v.Completed != nil {
+					cached[v.Digest] = v.Cached
+				}
+			}
 			dt, err := sr.Marshal()
 			if err != nil {
 				return err
@@ -419,7 +610,10 @@ func (b *Builder) Build(ctx context.Context, opt backend.BuildConfig) (*builder.
 		return nil
 	})
 
-	if err := eg.Wait(); err != nil {
+	err = eg.Wait()
+	rec.ImageID = out.ImageID
+	finishRecord(err)
+	if err != nil {
 		return nil, err
 	}
 
@@ -25,6 +25,7 @@ import (
 	"github.com/moby/buildkit/cache/remotecache"
 	inlineremotecache "github.com/moby/buildkit/cache/remotecache/inline"
 	localremotecache "github.com/moby/buildkit/cache/remotecache/local"
+	registryremotecache "github.com/moby/buildkit/cache/remotecache/registry"
 	"github.com/moby/buildkit/client"
 	"github.com/moby/buildkit/control"
 	"github.com/moby/buildkit/frontend"
@@ -41,7 +42,7 @@ import (
 	bolt "go.etcd.io/bbolt"
 )
 
-func newController(rt http.RoundTripper, opt Opt) (*control.Controller, error) {
+func newController(rt http.RoundTripper, secrets *buildSecrets, history *buildHistory, registry *frontendRegistry, scratch *scratchLimits, opt Opt) (*control.Controller, error) {
 	if err := os.MkdirAll(opt.Root, 0711); err != nil {
 		return nil, err
 	}
@@ -133,7 +134,7 @@ func newController(rt http.RoundTripper, opt Opt) (*control.Controller, error) {
 
 	dns := getDNSConfig(opt.DNSConfig)
 
-	exec, err := newExecutor(root, opt.DefaultCgroupParent, opt.NetworkController, dns, opt.Rootless, opt.IdentityMapping, opt.ApparmorProfile)
+	exec, err := newExecutor(root, opt.DefaultCgroupParent, opt.NetworkController, dns, opt.Rootless, opt.IdentityMapping, opt.ApparmorProfile, scratch)
 	if err != nil {
 		return nil, err
 	}
@@ -189,6 +190,9 @@ func newController(rt http.RoundTripper, opt Opt) (*control.Controller, error) {
 		Transport:         rt,
 		Layers:            layers,
 		Platforms:         archutil.SupportedPlatforms(true),
+		SessionGroup:      secrets.sessionGroup,
+		GitSessionGroup:   secrets.gitSessionGroup,
+		ImageResolved:     history.imageResolved,
 	}
 
 	wc := &worker.Controller{}
@@ -200,7 +204,7 @@ func newController(rt http.RoundTripper, opt Opt) (*control.Controller, error) {
 
 	frontends := map[string]frontend.Frontend{
 		"dockerfile.v0": forwarder.NewGatewayForwarder(wc, dockerfile.Build),
-		"gateway.v0":    gateway.NewGatewayFrontend(wc),
+		"gateway.v0":    &registeredFrontends{Frontend: gateway.NewGatewayFrontend(wc), registry: registry},
 	}
 
 	return control.NewController(control.Opt{
@@ -213,7 +217,9 @@ func newController(rt http.RoundTripper, opt Opt) (*control.Controller, error) {
 			"local":    localremotecache.ResolveCacheImporterFunc(opt.SessionManager),
 		},
 		ResolveCacheExporterFuncs: map[string]remotecache.ResolveCacheExporterFunc{
-			"inline": inlineremotecache.ResolveCacheExporterFunc(),
+			"inline":   inlineremotecache.ResolveCacheExporterFunc(),
+			"registry": registryremotecache.ResolveCacheExporterFunc(opt.SessionManager, opt.RegistryHosts),
+			"local":    localremotecache.ResolveCacheExporterFunc(opt.SessionManager),
 		},
 		Entitlements: getEntitlements(opt.BuilderConfig),
 	})
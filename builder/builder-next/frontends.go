@@ -0,0 +1,208 @@
+package buildkit
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	distref "github.com/docker/distribution/reference"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/ioutils"
+	"github.com/docker/docker/reference"
+	"github.com/moby/buildkit/frontend"
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/pkg/errors"
+)
+
+const (
+	// gatewaySourceOpt and gatewayDevelOpt are the options of the gateway
+	// frontend for the image of the frontend a build runs, and for running a
+	// frontend built by another frontend instead.
+	gatewaySourceOpt = "source"
+	gatewayDevelOpt  = "gateway-devel"
+)
+
+// frontendRegistry holds the frontend images registered with the daemon. The
+// frontends are stored as a JSON file, by name.
+type frontendRegistry struct {
+	file string
+	refs reference.Store
+
+	mu        sync.Mutex
+	frontends map[string]types.BuildFrontend
+}
+
+func newFrontendRegistry(file string, refs reference.Store) (*frontendRegistry, error) {
+	r := &frontendRegistry{
+		file:      file,
+		refs:      refs,
+		frontends: make(map[string]types.BuildFrontend),
+	}
+	dt, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return r, nil
+		}
+		return nil, err
+	}
+	var frontends []types.BuildFrontend
+	if err := json.Unmarshal(dt, &frontends); err != nil {
+		return nil, errors.Wrapf(err, "invalid build frontends file %s", file)
+	}
+	for _, f := range frontends {
+		r.frontends[f.Name] = f
+	}
+	return r, nil
+}
+
+// frontendName returns the normalized name of the frontend builds refer to
+// with name.
+func frontendName(name string) (distref.Named, error) {
+	ref, err := distref.ParseNormalizedNamed(name)
+	if err != nil {
+		return nil, errdefs.InvalidParameter(errors.Wrapf(err, "invalid frontend name %q", name))
+	}
+	if _, ok := ref.(distref.Canonical); ok {
+		return nil, errdefs.InvalidParameter(errors.Errorf("invalid frontend name %q: name must not contain a digest", name))
+	}
+	return distref.TagNameOnly(ref), nil
+}
+
+// register registers a frontend, replacing the frontend with the same name if
+// any. The image of the frontend must be pinned by digest, and be available
+// locally, so builds can run it without pulling it.
+func (r *frontendRegistry) register(f types.BuildFrontend) (types.BuildFrontend, error) {
+	name, err := frontendName(f.Name)
+	if err != nil {
+		return f, err
+	}
+	img, err := distref.ParseNormalizedNamed(f.Image)
+	if err != nil {
+		return f, errdefs.InvalidParameter(errors.Wrapf(err, "invalid frontend image %q", f.Image))
+	}
+	canonical, ok := img.(distref.Canonical)
+	if !ok {
+		return f, errdefs.InvalidParameter(errors.Errorf("invalid frontend image %q: image must be pinned by digest", f.Image))
+	}
+	pinned, err := distref.WithDigest(distref.TrimNamed(canonical), canonical.Digest())
+	if err != nil {
+		return f, errdefs.InvalidParameter(err)
+	}
+	id, err := r.refs.Get(pinned)
+	if err != nil {
+		if errors.Is(err, reference.ErrDoesNotExist) {
+			return f, errdefs.NotFound(errors.Errorf("no such image: %s", distref.FamiliarString(pinned)))
+		}
+		return f, err
+	}
+
+	f = types.BuildFrontend{
+		Name:      name.String(),
+		Image:     pinned.String(),
+		ImageID:   id.String(),
+		CreatedAt: time.Now().UTC(),
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	prev, replaced := r.frontends[f.Name]
+	r.frontends[f.Name] = f
+	if err := r.save(); err != nil {
+		if replaced {
+			r.frontends[f.Name] = prev
+		} else {
+			delete(r.frontends, f.Name)
+		}
+		return f, err
+	}
+	return f, nil
+}
+
+// unregister removes the frontend with the given name.
+func (r *frontendRegistry) unregister(name string) error {
+	ref, err := frontendName(name)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	f, ok := r.frontends[ref.String()]
+	if !ok {
+		return errdefs.NotFound(errors.Errorf("no such build frontend: %s", name))
+	}
+	delete(r.frontends, f.Name)
+	if err := r.save(); err != nil {
+		r.frontends[f.Name] = f
+		return err
+	}
+	return nil
+}
+
+// list returns the registered frontends, by name.
+func (r *frontendRegistry) list() []types.BuildFrontend {
+	r.mu.Lock()
+	frontends := make([]types.BuildFrontend, 0, len(r.frontends))
+	for _, f := range r.frontends {
+		frontends = append(frontends, f)
+	}
+	r.mu.Unlock()
+	sort.Slice(frontends, func(i, j int) bool {
+		return frontends[i].Name < frontends[j].Name
+	})
+	return frontends
+}
+
+// resolve returns the pinned image of the frontend a build refers to with
+// name, if the frontend is registered.
+func (r *frontendRegistry) resolve(name string) (string, bool) {
+	ref, err := frontendName(name)
+	if err != nil {
+		return "", false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	f, ok := r.frontends[ref.String()]
+	return f.Image, ok
+}
+
+// save must be called with r.mu held.
+func (r *frontendRegistry) save() error {
+	frontends := make([]types.BuildFrontend, 0, len(r.frontends))
+	for _, f := range r.frontends {
+		frontends = append(frontends, f)
+	}
+	dt, err := json.Marshal(frontends)
+	if err != nil {
+		return err
+	}
+	return ioutils.AtomicWriteFile(r.file, dt, 0600)
+}
+
+// registeredFrontends wraps the gateway frontend to run the pinned image of
+// registered frontends. The Dockerfile frontend runs the frontend of the
+// syntax directive of Dockerfiles through the gateway frontend, so it is used
+// for both the syntax directive and gateway builds.
+type registeredFrontends struct {
+	frontend.Frontend
+	registry *frontendRegistry
+}
+
+func (f *registeredFrontends) Solve(ctx context.Context, llbBridge frontend.FrontendLLBBridge, opts map[string]string, inputs map[string]*pb.Definition, sid string, sm *session.Manager) (*frontend.Result, error) {
+	if _, isDevel := opts[gatewayDevelOpt]; !isDevel {
+		if img, ok := f.registry.resolve(opts[gatewaySourceOpt]); ok {
+			o := make(map[string]string, len(opts))
+			for k, v := range opts {
+				o[k] = v
+			}
+			o[gatewaySourceOpt] = img
+			opts = o
+		}
+	}
+	return f.Frontend.Solve(ctx, llbBridge, opts, inputs, sid, sm)
+}
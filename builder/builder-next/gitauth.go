@@ -0,0 +1,80 @@
+package buildkit
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"os/exec"
+	"path"
+	"strings"
+
+	"github.com/docker/docker/daemon/config"
+	"github.com/pkg/errors"
+)
+
+const (
+	// gitAuthHeaderSecret and gitAuthTokenSecret are the IDs of the secrets
+	// BuildKit fetches the credentials of git repositories from. The ID of
+	// the secret for a given host is suffixed with "." and the host.
+	gitAuthHeaderSecret = "GIT_AUTH_HEADER"
+	gitAuthTokenSecret  = "GIT_AUTH_TOKEN"
+
+	// gitTokenUsername is the username HTTP(S) git servers take access
+	// tokens with.
+	gitTokenUsername = "x-access-token"
+
+	// credentialsNotFound is the output of docker credential helpers that do
+	// not have credentials for a server.
+	credentialsNotFound = "credentials not found in native keychain"
+)
+
+// gitAuthHeader returns the Authorization header for the git repositories of
+// host, from the first entry of auth matching the host. It returns an empty
+// header if no entry matches, or if the credential helper of the entry has
+// no credentials for the host.
+func gitAuthHeader(ctx context.Context, auth []config.BuilderGitAuth, host string) (string, error) {
+	for _, a := range auth {
+		if ok, _ := path.Match(a.Host, host); !ok {
+			continue
+		}
+		if a.Token != "" {
+			return basicAuthHeader(gitTokenUsername, a.Token), nil
+		}
+		username, secret, err := credentialHelperGet(ctx, a.CredentialHelper, host)
+		if err != nil || secret == "" {
+			return "", err
+		}
+		if username == "" {
+			username = gitTokenUsername
+		}
+		return basicAuthHeader(username, secret), nil
+	}
+	return "", nil
+}
+
+func basicAuthHeader(username, password string) string {
+	return "basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
+}
+
+// credentialHelperGet fetches the credentials of host from a docker
+// credential helper.
+func credentialHelperGet(ctx context.Context, helper, host string) (username, secret string, _ error) {
+	cmd := exec.CommandContext(ctx, "docker-credential-"+helper, "get")
+	cmd.Stdin = strings.NewReader(host)
+	out, err := cmd.Output()
+	if err != nil {
+		if strings.Contains(string(out), credentialsNotFound) {
+			return "", "", nil
+		}
+		return "", "", errors.Wrapf(err, "error getting credentials of %s from credential helper %s: %s", host, helper, bytes.TrimSpace(out))
+	}
+	var creds struct {
+		Username string
+		Secret   string
+	}
+	if err := json.Unmarshal(out, &creds); err != nil {
+		return "", "", errors.Wrapf(err, "invalid credentials of %s from credential helper %s", host, helper)
+	}
+	return creds.Username, creds.Secret, nil
+}
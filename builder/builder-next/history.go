@@ -0,0 +1,243 @@
+package buildkit
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/ioutils"
+	"github.com/moby/buildkit/session"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// maxBuildRecords is the number of build records the daemon keeps. The
+	// oldest records are removed once this number is exceeded.
+	maxBuildRecords = 500
+
+	// provenanceBuildType is the SLSA build type of the builds of the
+	// daemon, as used by BuildKit.
+	provenanceBuildType = "https://mobyproject.org/buildkit@v1"
+)
+
+// buildHistory records the builds of the daemon. The records of completed
+// builds are stored as JSON files, one per build.
+type buildHistory struct {
+	root string
+
+	mu sync.Mutex
+	// active holds the records of running builds, by the ID of the session
+	// of the build, so the images they resolve can be recorded.
+	active map[string][]*types.BuildRecord
+}
+
+func newBuildHistory(root string) (*buildHistory, error) {
+	if err := os.MkdirAll(root, 0700); err != nil {
+		return nil, err
+	}
+	return &buildHistory{
+		root:   root,
+		active: make(map[string][]*types.BuildRecord),
+	}, nil
+}
+
+// start records the start of a build with the given session. The returned
+// function records the completion of the build.
+func (h *buildHistory) start(sessionID string, rec *types.BuildRecord) func(error) {
+	rec.CreatedAt = time.Now().UTC()
+	if sessionID != "" {
+		h.mu.Lock()
+		h.active[sessionID] = append(h.active[sessionID], rec)
+		h.mu.Unlock()
+	}
+
+	return func(err error) {
+		h.mu.Lock()
+		rec.CompletedAt = time.Now().UTC()
+		if err != nil {
+			rec.Error = err.Error()
+		}
+		if sessionID != "" {
+			recs := h.active[sessionID]
+			for i, r := range recs {
+				if r == rec {
+					recs = append(recs[:i], recs[i+1:]...)
+					break
+				}
+			}
+			if len(recs) == 0 {
+				delete(h.active, sessionID)
+			} else {
+				h.active[sessionID] = recs
+			}
+		}
+		h.mu.Unlock()
+
+		if err := h.save(rec); err != nil {
+			logrus.WithError(err).WithField("build", rec.ID).Error("failed to save build record")
+		}
+	}
+}
+
+// imageResolved records an image resolved by the builds of the sessions of g.
+func (h *buildHistory) imageResolved(g session.Group, ref string, dgst digest.Digest) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, id := range session.AllSessionIDs(g) {
+		if p := strings.SplitN(id, ":", 2); len(p) == 2 && len(p[1]) > 0 {
+			id = p[1]
+		}
+		for _, rec := range h.active[id] {
+			rec.Materials = addMaterial(rec.Materials, types.BuildMaterial{URI: ref, Digest: dgst.String()})
+		}
+	}
+}
+
+func addMaterial(materials []types.BuildMaterial, m types.BuildMaterial) []types.BuildMaterial {
+	for _, existing := range materials {
+		if existing == m {
+			return materials
+		}
+	}
+	return append(materials, m)
+}
+
+func (h *buildHistory) save(rec *types.BuildRecord) error {
+	dt, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	if err := ioutils.AtomicWriteFile(filepath.Join(h.root, rec.ID+".json"), dt, 0600); err != nil {
+		return err
+	}
+
+	records, err := h.list()
+	if err != nil || len(records) <= maxBuildRecords {
+		return err
+	}
+	for _, r := range records[maxBuildRecords:] {
+		if err := os.Remove(filepath.Join(h.root, r.ID+".json")); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// list returns the build records, newest first.
+func (h *buildHistory) list() ([]types.BuildRecord, error) {
+	entries, err := os.ReadDir(h.root)
+	if err != nil {
+		return nil, err
+	}
+	records := make([]types.BuildRecord, 0, len(entries))
+	for _, e := range entries {
+		id := strings.TrimSuffix(e.Name(), ".json")
+		if id == e.Name() {
+			continue
+		}
+		rec, err := h.get(id)
+		if err != nil {
+			logrus.WithError(err).WithField("build", id).Warn("failed to read build record")
+			continue
+		}
+		records = append(records, rec)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].CreatedAt.After(records[j].CreatedAt)
+	})
+	return records, nil
+}
+
+func (h *buildHistory) get(id string) (types.BuildRecord, error) {
+	var rec types.BuildRecord
+	if id == "" || strings.ContainsAny(id, `/\.`) {
+		return rec, errdefs.InvalidParameter(errors.Errorf("invalid build record ID %q", id))
+	}
+	dt, err := os.ReadFile(filepath.Join(h.root, id+".json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return rec, errdefs.NotFound(errors.Errorf("no such build record: %s", id))
+		}
+		return rec, err
+	}
+	if err := json.Unmarshal(dt, &rec); err != nil {
+		return rec, errors.Wrapf(err, "invalid build record %s", id)
+	}
+	return rec, nil
+}
+
+// provenance returns the SLSA provenance of a build, as an in-toto statement.
+func provenance(rec types.BuildRecord) ([]byte, error) {
+	type digestSet map[string]string
+	type subject struct {
+		Name   string    `json:"name"`
+		Digest digestSet `json:"digest"`
+	}
+	type material struct {
+		URI    string    `json:"uri"`
+		Digest digestSet `json:"digest,omitempty"`
+	}
+
+	var subjects []subject
+	if dgst, err := digest.Parse(rec.ImageID); err == nil {
+		names := rec.Tags
+		if len(names) == 0 {
+			names = []string{rec.ImageID}
+		}
+		for _, name := range names {
+			subjects = append(subjects, subject{
+				Name:   name,
+				Digest: digestSet{dgst.Algorithm().String(): dgst.Encoded()},
+			})
+		}
+	}
+
+	materials := make([]material, 0, len(rec.Materials))
+	for _, m := range rec.Materials {
+		mat := material{URI: m.URI}
+		if dgst, err := digest.Parse(m.Digest); err == nil {
+			mat.Digest = digestSet{dgst.Algorithm().String(): dgst.Encoded()}
+		}
+		materials = append(materials, mat)
+	}
+
+	statement := map[string]interface{}{
+		"_type":         "https://in-toto.io/Statement/v0.1",
+		"predicateType": "https://slsa.dev/provenance/v0.2",
+		"subject":       subjects,
+		"predicate": map[string]interface{}{
+			"buildType": provenanceBuildType,
+			"invocation": map[string]interface{}{
+				"configSource": map[string]interface{}{
+					"uri":        rec.Context,
+					"entryPoint": rec.FrontendAttrs["filename"],
+				},
+				"parameters": map[string]interface{}{
+					"frontend": rec.Frontend,
+					"args":     rec.FrontendAttrs,
+				},
+			},
+			"metadata": map[string]interface{}{
+				"buildInvocationID": rec.ID,
+				"buildStartedOn":    rec.CreatedAt,
+				"buildFinishedOn":   rec.CompletedAt,
+				"completeness": map[string]bool{
+					"parameters":  len(rec.BuildArgs) == 0,
+					"environment": false,
+					"materials":   false,
+				},
+				"reproducible": false,
+			},
+			"materials": materials,
+		},
+	}
+	return json.Marshal(statement)
+}
@@ -0,0 +1,167 @@
+package buildkit
+
+import (
+	"sync"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/daemon/config"
+	"github.com/docker/docker/errdefs"
+	units "github.com/docker/go-units"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/pkg/errors"
+)
+
+// defaultCPUPeriod is the CPU period the number of CPUs of a build is
+// converted to a quota for, in microseconds.
+const defaultCPUPeriod = 100000
+
+// buildResources returns the resource limits of the cgroup of a build, from
+// the options of the build and the default limits of the builder. It returns
+// nil if the build is not limited. The limits of the build take precedence
+// over the defaults of the builder.
+func buildResources(opt types.ImageBuildOptions, defaults config.BuilderResources) (*specs.LinuxResources, error) {
+	var r specs.LinuxResources
+
+	memory := opt.Memory
+	if memory == 0 && defaults.Memory != "" {
+		m, err := units.RAMInBytes(defaults.Memory)
+		if err != nil {
+			return nil, err
+		}
+		memory = m
+	}
+	if memory > 0 || opt.MemorySwap != 0 {
+		r.Memory = &specs.LinuxMemory{}
+		if memory > 0 {
+			r.Memory.Limit = &memory
+		}
+		if opt.MemorySwap != 0 {
+			swap := opt.MemorySwap
+			r.Memory.Swap = &swap
+		}
+	}
+
+	cpu := specs.LinuxCPU{
+		Cpus: opt.CPUSetCPUs,
+		Mems: opt.CPUSetMems,
+	}
+	if opt.CPUShares > 0 {
+		shares := uint64(opt.CPUShares)
+		cpu.Shares = &shares
+	}
+	if opt.CPUQuota > 0 || opt.CPUPeriod > 0 {
+		if opt.CPUQuota > 0 {
+			quota := opt.CPUQuota
+			cpu.Quota = &quota
+		}
+		if opt.CPUPeriod > 0 {
+			period := uint64(opt.CPUPeriod)
+			cpu.Period = &period
+		}
+	} else if defaults.CPUs > 0 {
+		quota := int64(defaults.CPUs * defaultCPUPeriod)
+		period := uint64(defaultCPUPeriod)
+		cpu.Quota = &quota
+		cpu.Period = &period
+	}
+	if cpu.Shares != nil || cpu.Quota != nil || cpu.Period != nil || cpu.Cpus != "" || cpu.Mems != "" {
+		r.CPU = &cpu
+	}
+
+	pids := opt.PidsLimit
+	if pids == 0 {
+		pids = defaults.PidsLimit
+	}
+	if pids > 0 {
+		r.Pids = &specs.LinuxPids{Limit: pids}
+	}
+
+	if r.Memory == nil && r.CPU == nil && r.Pids == nil {
+		return nil, nil
+	}
+	return &r, nil
+}
+
+// buildScratchLimit returns the scratch limit of a build, from the options of
+// the build and the default limits of the builder, or 0 if the scratch usage
+// of the build is not limited.
+func buildScratchLimit(opt types.ImageBuildOptions, defaults config.BuilderResources) (int64, error) {
+	if opt.ScratchLimit != 0 || defaults.ScratchLimit == "" {
+		return opt.ScratchLimit, nil
+	}
+	return units.RAMInBytes(defaults.ScratchLimit)
+}
+
+// checkScratchLimit returns an error if the scratch usage of builds cannot be
+// limited with the given storage driver. The usage of a step is the size of
+// the upper directory of its root filesystem, which only overlay2 exposes.
+func checkScratchLimit(driver string) error {
+	if driver != "overlay2" {
+		return errdefs.NotImplemented(errors.Errorf("build scratch limits are not supported with the %s storage driver", driver))
+	}
+	return nil
+}
+
+// scratchUsage is the size of the files written by the steps of a build to
+// their root filesystem.
+type scratchUsage struct {
+	limit int64
+
+	mu      sync.Mutex
+	done    int64
+	running map[string]int64
+}
+
+// update sets the usage of a running step, and returns false if the usage of
+// the build exceeds its limit.
+func (u *scratchUsage) update(id string, size int64) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.running[id] = size
+	total := u.done
+	for _, s := range u.running {
+		total += s
+	}
+	return total <= u.limit
+}
+
+// finish adds the final usage of a step to the usage of the build.
+func (u *scratchUsage) finish(id string, size int64) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	delete(u.running, id)
+	u.done += size
+}
+
+// scratchLimits tracks the scratch usage of the builds with a scratch limit.
+// The steps of a build are identified by the cgroup of the build, which is
+// their cgroup parent.
+type scratchLimits struct {
+	mu     sync.Mutex
+	builds map[string]*scratchUsage
+}
+
+func newScratchLimits() *scratchLimits {
+	return &scratchLimits{builds: make(map[string]*scratchUsage)}
+}
+
+// add limits the scratch usage of the build with the given cgroup, and
+// returns a function to call once the build completes.
+func (l *scratchLimits) add(cgroupParent string, limit int64) func() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.builds[cgroupParent] = &scratchUsage{limit: limit, running: make(map[string]int64)}
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		delete(l.builds, cgroupParent)
+	}
+}
+
+// get returns the scratch usage of the build with the given cgroup, or nil if
+// the scratch usage of the build is not limited.
+func (l *scratchLimits) get(cgroupParent string) *scratchUsage {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.builds[cgroupParent]
+}
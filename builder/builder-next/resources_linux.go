@@ -0,0 +1,232 @@
+package buildkit
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/containerd/cgroups"
+	cgroupsV2 "github.com/containerd/cgroups/v2"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/directory"
+	units "github.com/docker/go-units"
+	"github.com/moby/buildkit/executor"
+	"github.com/moby/buildkit/identity"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const unifiedMountpoint = "/sys/fs/cgroup"
+
+// scratchPollInterval is the interval at which the scratch usage of the
+// steps of the builds with a scratch limit is measured.
+const scratchPollInterval = 2 * time.Second
+
+func checkBuildCgroups(cgroupParent string, rootless bool) error {
+	if rootless {
+		return errdefs.NotImplemented(errors.New("build resource limits are not supported in rootless mode"))
+	}
+	// on cgroup v1, the cgroupfs path of the slice of a build is only known
+	// if the slice of the daemon is not nested
+	if slice, _, ok := systemdCgroupParent(cgroupParent); ok && cgroups.Mode() != cgroups.Unified && strings.Contains(slice, "-") {
+		return errdefs.NotImplemented(errors.Errorf("build resource limits are not supported with the nested cgroup parent %s on cgroup v1", slice))
+	}
+	return nil
+}
+
+// systemdCgroupParent splits a cgroup parent of the "slice:prefix:" form,
+// used with the systemd cgroup driver.
+func systemdCgroupParent(cgroupParent string) (slice, prefix string, ok bool) {
+	parts := strings.Split(cgroupParent, ":")
+	if len(parts) != 3 || !strings.HasSuffix(parts[0], ".slice") {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+// newBuildCgroup creates the cgroup of the build with the given ID, with the
+// given resource limits, under cgroupParent. It returns the cgroup parent of
+// the steps of the build, and a function removing the cgroup once the build
+// completes.
+func newBuildCgroup(cgroupParent, id string, resources *specs.LinuxResources) (string, func(), error) {
+	if slice, prefix, ok := systemdCgroupParent(cgroupParent); ok {
+		return newBuildSlice(slice, prefix, id, resources)
+	}
+
+	path := filepath.Join("/", cgroupParent, "build-"+id)
+
+	var remove func() error
+	if cgroups.Mode() == cgroups.Unified {
+		m, err := cgroupsV2.NewManager(unifiedMountpoint, path, cgroupsV2.ToResources(resources))
+		if err != nil {
+			return "", nil, errors.Wrap(err, "failed to create build cgroup")
+		}
+		remove = m.Delete
+	} else {
+		cg, err := cgroups.New(cgroups.V1, cgroups.StaticPath(path), resources)
+		if err != nil {
+			return "", nil, errors.Wrap(err, "failed to create build cgroup")
+		}
+		remove = cg.Delete
+	}
+
+	return path, func() {
+		if err := remove(); err != nil {
+			logrus.WithError(err).WithField("cgroup", path).Warn("failed to remove build cgroup")
+		}
+	}, nil
+}
+
+// newBuildSlice creates a transient systemd slice for the build with the
+// given ID, nested in the slice of the daemon, with the given resource
+// limits. The steps of the build run in scopes of this slice.
+func newBuildSlice(parent, prefix, id string, resources *specs.LinuxResources) (string, func(), error) {
+	// systemd derives the parent of a slice from its name, "a-b.slice"
+	// being nested in "a.slice"
+	name := "build_" + strings.ReplaceAll(id, "-", "_") + ".slice"
+	if parent != "-.slice" {
+		name = strings.TrimSuffix(parent, ".slice") + "-" + name
+	}
+
+	var remove func() error
+	if cgroups.Mode() == cgroups.Unified {
+		m, err := cgroupsV2.NewSystemd(parent, name, -1, cgroupsV2.ToResources(resources))
+		if err != nil {
+			return "", nil, errors.Wrap(err, "failed to create build slice")
+		}
+		remove = m.DeleteSystemd
+		// systemd does not set the cpuset of slices
+		if resources.CPU != nil && (resources.CPU.Cpus != "" || resources.CPU.Mems != "") {
+			cg, err := cgroupsV2.LoadManager(unifiedMountpoint, expandSlice(name))
+			if err == nil {
+				err = cg.Update(&cgroupsV2.Resources{CPU: &cgroupsV2.CPU{Cpus: resources.CPU.Cpus, Mems: resources.CPU.Mems}})
+			}
+			if err != nil {
+				_ = remove()
+				return "", nil, errors.Wrap(err, "failed to set the cpuset of build slice")
+			}
+		}
+	} else {
+		cg, err := cgroups.New(cgroups.Systemd, cgroups.Slice(parent, name), resources)
+		if err != nil {
+			return "", nil, errors.Wrap(err, "failed to create build slice")
+		}
+		remove = cg.Delete
+	}
+
+	return name + ":" + prefix + ":", func() {
+		if err := remove(); err != nil {
+			logrus.WithError(err).WithField("slice", name).Warn("failed to remove build slice")
+		}
+	}, nil
+}
+
+// expandSlice returns the cgroupfs path of a systemd slice, for example
+// "/a.slice/a-b.slice" for "a-b.slice".
+func expandSlice(slice string) string {
+	name := strings.TrimSuffix(slice, ".slice")
+	var path, prefix string
+	for _, part := range strings.Split(name, "-") {
+		prefix += part
+		path += "/" + prefix + ".slice"
+		prefix += "-"
+	}
+	return path
+}
+
+// scratchExecutor is an executor killing the steps of the builds which
+// exceed their scratch limit.
+type scratchExecutor struct {
+	executor.Executor
+	limits *scratchLimits
+}
+
+func withScratchLimits(exec executor.Executor, limits *scratchLimits) executor.Executor {
+	return &scratchExecutor{Executor: exec, limits: limits}
+}
+
+func (e *scratchExecutor) Run(ctx context.Context, id string, root executor.Mount, mounts []executor.Mount, process executor.ProcessInfo, started chan<- struct{}) error {
+	usage := e.limits.get(process.Meta.CgroupParent)
+	if usage == nil {
+		return e.Executor.Run(ctx, id, root, mounts, process, started)
+	}
+	dir, release, err := scratchDir(ctx, root)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	if id == "" {
+		id = identity.NewID()
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	exceeded := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(scratchPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			size, err := directory.Size(ctx, dir)
+			if err != nil {
+				continue
+			}
+			if !usage.update(id, size) {
+				close(exceeded)
+				cancel()
+				return
+			}
+		}
+	}()
+
+	err = e.Executor.Run(ctx, id, root, mounts, process, started)
+	cancel()
+	<-done
+
+	// the root filesystem of a failed step is discarded
+	var size int64
+	if err == nil {
+		size, _ = directory.Size(context.Background(), dir)
+	}
+	usage.finish(id, size)
+
+	select {
+	case <-exceeded:
+		return errors.Errorf("build exceeded its scratch limit of %s", units.BytesSize(float64(usage.limit)))
+	default:
+	}
+	return err
+}
+
+// scratchDir returns the directory of the files written to the root
+// filesystem of a step, which is the upper directory of the overlay2 mount of
+// the root filesystem.
+func scratchDir(ctx context.Context, root executor.Mount) (string, func() error, error) {
+	mountable, err := root.Src.Mount(ctx, false)
+	if err != nil {
+		return "", nil, err
+	}
+	mounts, release, err := mountable.Mount()
+	if err != nil {
+		return "", nil, err
+	}
+	if len(mounts) == 1 && mounts[0].Type == "bind" {
+		// layers without parent are not mounted
+		switch src := mounts[0].Source; filepath.Base(src) {
+		case "merged":
+			return filepath.Join(filepath.Dir(src), "diff"), release, nil
+		case "diff":
+			return src, release, nil
+		}
+	}
+	_ = release()
+	return "", nil, errdefs.NotImplemented(errors.New("build scratch limits are only supported with the overlay2 storage driver"))
+}
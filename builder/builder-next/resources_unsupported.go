@@ -0,0 +1,23 @@
+//go:build !linux
+// +build !linux
+
+package buildkit
+
+import (
+	"github.com/docker/docker/errdefs"
+	"github.com/moby/buildkit/executor"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/pkg/errors"
+)
+
+func checkBuildCgroups(cgroupParent string, rootless bool) error {
+	return errdefs.NotImplemented(errors.New("build resource limits are not supported on this platform"))
+}
+
+func newBuildCgroup(cgroupParent, id string, resources *specs.LinuxResources) (string, func(), error) {
+	return "", nil, checkBuildCgroups(cgroupParent, false)
+}
+
+func withScratchLimits(exec executor.Executor, limits *scratchLimits) executor.Executor {
+	return exec
+}
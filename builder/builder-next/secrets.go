@@ -0,0 +1,270 @@
+package buildkit
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/daemon/config"
+	"github.com/docker/docker/errdefs"
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/session/secrets"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SecretGetter returns the value of the daemon-managed secrets builds can
+// mount with RUN --mount=type=secret.
+type SecretGetter interface {
+	GetSecretValue(nameOrID string) ([]byte, error)
+}
+
+// buildSecrets provides the daemon-managed secrets of builds, and the
+// credentials of the git repositories they fetch. The secrets of a build are
+// provided by a session of the daemon, which only serves the secrets the
+// build request gives access to, and the git credentials by another session,
+// which is only used by git sources, so that RUN --mount=type=secret can't
+// read them. Exec operations and git sources resolve secrets from the daemon
+// session of their builds first. The daemon sessions look up secrets in the
+// session of the build before their own, so secrets provided by the client
+// take precedence.
+type buildSecrets struct {
+	sm      *session.Manager
+	getter  SecretGetter
+	gitAuth []config.BuilderGitAuth
+
+	mu sync.Mutex
+	// sessions and gitSessions hold the daemon sessions of builds, by the
+	// ID of the session of the build.
+	sessions    map[string]string
+	gitSessions map[string]string
+}
+
+func newBuildSecrets(sm *session.Manager, getter SecretGetter, gitAuth []config.BuilderGitAuth) *buildSecrets {
+	return &buildSecrets{
+		sm:          sm,
+		getter:      getter,
+		gitAuth:     gitAuth,
+		sessions:    make(map[string]string),
+		gitSessions: make(map[string]string),
+	}
+}
+
+// start starts the daemon sessions providing the given secrets, and the git
+// credentials of the daemon, to the build with the given session. The
+// returned function stops the sessions.
+func (s *buildSecrets) start(ctx context.Context, buildSessionID string, refs []types.ImageBuildSecret) (func(), error) {
+	if len(refs) > 0 {
+		if s.getter == nil {
+			return nil, errdefs.NotImplemented(errors.New("daemon-managed build secrets are not supported"))
+		}
+		if buildSessionID == "" {
+			return nil, errdefs.InvalidParameter(errors.New("daemon-managed build secrets require a build session"))
+		}
+	}
+	if buildSessionID == "" {
+		return func() {}, nil
+	}
+	names := make(map[string]string, len(refs))
+	for _, ref := range refs {
+		if ref.ID == "" {
+			return nil, errdefs.InvalidParameter(errors.New("build secret must have an ID"))
+		}
+		if _, ok := names[ref.ID]; ok {
+			return nil, errdefs.InvalidParameter(errors.Errorf("duplicate build secret %s", ref.ID))
+		}
+		name := ref.Name
+		if name == "" {
+			name = ref.ID
+		}
+		names[ref.ID] = name
+	}
+
+	var stops []func()
+	stop := func() {
+		for _, f := range stops {
+			f()
+		}
+	}
+	if len(names) > 0 {
+		f, err := s.run(ctx, buildSessionID, s.sessions, &secretProvider{
+			sm:             s.sm,
+			buildSessionID: buildSessionID,
+			getter:         s.getter,
+			names:          names,
+		})
+		if err != nil {
+			return nil, err
+		}
+		stops = append(stops, f)
+	}
+	if len(s.gitAuth) > 0 {
+		f, err := s.run(ctx, buildSessionID, s.gitSessions, &secretProvider{
+			sm:             s.sm,
+			buildSessionID: buildSessionID,
+			gitAuth:        s.gitAuth,
+		})
+		if err != nil {
+			stop()
+			return nil, err
+		}
+		stops = append(stops, f)
+	}
+	return stop, nil
+}
+
+// run starts a daemon session serving the secrets of p, and records it in
+// sessions under the ID of the session of the build. The returned function
+// stops the session.
+func (s *buildSecrets) run(ctx context.Context, buildSessionID string, sessions map[string]string, p *secretProvider) (func(), error) {
+	sess, err := session.NewSession(ctx, "daemon-secrets", "")
+	if err != nil {
+		return nil, err
+	}
+	sess.Allow(p)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		if err := sess.Run(ctx, s.dial); err != nil {
+			logrus.WithError(err).Error("failed to run build secrets session")
+		}
+	}()
+	getCtx, getCancel := context.WithTimeout(ctx, 5*time.Second)
+	defer getCancel()
+	if _, err := s.sm.Get(getCtx, sess.ID(), false); err != nil {
+		cancel()
+		sess.Close()
+		return nil, err
+	}
+
+	s.mu.Lock()
+	sessions[buildSessionID] = sess.ID()
+	s.mu.Unlock()
+
+	return func() {
+		s.mu.Lock()
+		delete(sessions, buildSessionID)
+		s.mu.Unlock()
+		cancel()
+		sess.Close()
+	}, nil
+}
+
+// dial connects a daemon session to the session manager.
+func (s *buildSecrets) dial(ctx context.Context, _ string, meta map[string][]string) (net.Conn, error) {
+	c1, c2 := net.Pipe()
+	go func() {
+		if err := s.sm.HandleConn(ctx, c2, meta); err != nil {
+			logrus.WithError(err).Error("failed to handle build secrets session")
+		}
+	}()
+	return c1, nil
+}
+
+// sessionGroup returns the daemon sessions serving the daemon-managed
+// secrets of the sessions of g, followed by the sessions of g.
+func (s *buildSecrets) sessionGroup(g session.Group) session.Group {
+	return &secretsGroup{Group: g, secrets: s, sessions: s.sessions}
+}
+
+// gitSessionGroup returns the daemon sessions serving the git credentials of
+// the daemon to the sessions of g, followed by the sessions of g.
+func (s *buildSecrets) gitSessionGroup(g session.Group) session.Group {
+	return &secretsGroup{Group: g, secrets: s, sessions: s.gitSessions}
+}
+
+type secretsGroup struct {
+	session.Group
+	secrets  *buildSecrets
+	sessions map[string]string
+}
+
+func (g *secretsGroup) SessionIterator() session.Iterator {
+	ids := session.AllSessionIDs(g.Group)
+	var daemonIDs []string
+	g.secrets.mu.Lock()
+	for _, id := range ids {
+		// session IDs may be prefixed to tell apart the vertexes of
+		// different contexts
+		if p := strings.SplitN(id, ":", 2); len(p) == 2 && len(p[1]) > 0 {
+			id = p[1]
+		}
+		if daemonID, ok := g.sessions[id]; ok {
+			daemonIDs = append(daemonIDs, daemonID)
+		}
+	}
+	g.secrets.mu.Unlock()
+	return session.NewGroup(append(daemonIDs, ids...)...).SessionIterator()
+}
+
+// secretProvider serves the secrets of a build to BuildKit, by the ID of the
+// secret in the build, or the git credentials of the daemon.
+type secretProvider struct {
+	sm             *session.Manager
+	buildSessionID string
+	getter         SecretGetter
+	names          map[string]string
+	gitAuth        []config.BuilderGitAuth
+}
+
+func (p *secretProvider) Register(server *grpc.Server) {
+	secrets.RegisterSecretsServer(server, p)
+}
+
+func (p *secretProvider) GetSecret(ctx context.Context, req *secrets.GetSecretRequest) (*secrets.GetSecretResponse, error) {
+	dt, err := p.clientSecret(ctx, req.ID)
+	if err == nil {
+		return &secrets.GetSecretResponse{Data: dt}, nil
+	}
+	if !errors.Is(err, secrets.ErrNotFound) {
+		return nil, err
+	}
+
+	if name, ok := p.names[req.ID]; ok && p.getter != nil {
+		dt, err := p.getter.GetSecretValue(name)
+		if err != nil {
+			return nil, status.Errorf(codes.Unavailable, "failed to get secret %s: %v", req.ID, err)
+		}
+		return &secrets.GetSecretResponse{Data: dt}, nil
+	}
+
+	if strings.HasPrefix(req.ID, gitAuthHeaderSecret+".") && !p.hasClientGitAuth(ctx, req.ID) {
+		header, err := gitAuthHeader(ctx, p.gitAuth, strings.TrimPrefix(req.ID, gitAuthHeaderSecret+"."))
+		if err != nil {
+			return nil, status.Errorf(codes.Unavailable, "failed to get git credentials: %v", err)
+		}
+		if header != "" {
+			return &secrets.GetSecretResponse{Data: []byte(header)}, nil
+		}
+	}
+	return nil, status.Errorf(codes.NotFound, "secret %s not found", req.ID)
+}
+
+// clientSecret returns a secret provided by the client of the build.
+func (p *secretProvider) clientSecret(ctx context.Context, id string) ([]byte, error) {
+	caller, err := p.sm.Get(ctx, p.buildSessionID, true)
+	if err != nil || caller == nil {
+		return nil, secrets.ErrNotFound
+	}
+	return secrets.GetSecret(ctx, caller, id)
+}
+
+// hasClientGitAuth returns whether the client of the build provides other
+// git credentials for the host of the git auth header secret id, which
+// BuildKit looks up after it, and which take precedence over the git
+// credentials of the daemon.
+func (p *secretProvider) hasClientGitAuth(ctx context.Context, id string) bool {
+	host := strings.TrimPrefix(id, gitAuthHeaderSecret+".")
+	for _, id := range []string{gitAuthTokenSecret + "." + host, gitAuthHeaderSecret, gitAuthTokenSecret} {
+		if _, err := p.clientSecret(ctx, id); err == nil {
+			return true
+		}
+	}
+	return false
+}
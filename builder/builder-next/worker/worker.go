@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	nethttp "net/http"
+	"strings"
 	"time"
 
 	"github.com/containerd/containerd/content"
@@ -35,6 +36,7 @@ import (
 	"github.com/moby/buildkit/source/git"
 	"github.com/moby/buildkit/source/http"
 	"github.com/moby/buildkit/source/local"
+	srctypes "github.com/moby/buildkit/source/types"
 	"github.com/moby/buildkit/util/archutil"
 	"github.com/moby/buildkit/util/compression"
 	"github.com/moby/buildkit/util/contentutil"
@@ -70,6 +72,18 @@ type Opt struct {
 	Exporter          exporter.Exporter
 	Layers            LayerAccess
 	Platforms         []ocispec.Platform
+
+	// SessionGroup, if set, returns the sessions exec operations resolve
+	// secrets from, given the sessions of the builds that run them.
+	SessionGroup func(session.Group) session.Group
+
+	// GitSessionGroup, if set, returns the sessions git sources resolve
+	// credentials from, given the sessions of the builds that fetch them.
+	GitSessionGroup func(session.Group) session.Group
+
+	// ImageResolved, if set, is called with the images resolved by the
+	// builds of the sessions of g.
+	ImageResolved func(g session.Group, ref string, dgst digest.Digest)
 }
 
 // Worker is a local worker instance with dedicated snapshotter, cache, and so on.
@@ -178,9 +192,17 @@ func (w *Worker) ResolveOp(v solver.Vertex, s frontend.FrontendLLBBridge, sm *se
 		var parallelism *semaphore.Weighted
 		switch op := baseOp.Op.(type) {
 		case *pb.Op_Source:
-			return ops.NewSourceOp(v, op, baseOp.Platform, w.SourceManager, parallelism, sm, w)
+			sourceOp, err := ops.NewSourceOp(v, op, baseOp.Platform, w.SourceManager, parallelism, sm, w)
+			if err != nil || w.GitSessionGroup == nil || !strings.HasPrefix(op.Source.Identifier, srctypes.GitScheme+"://") {
+				return sourceOp, err
+			}
+			return &sessionGroupOp{Op: sourceOp, sessionGroup: w.GitSessionGroup}, nil
 		case *pb.Op_Exec:
-			return ops.NewExecOp(v, op, baseOp.Platform, w.CacheManager(), parallelism, sm, w.Executor(), w)
+			execOp, err := ops.NewExecOp(v, op, baseOp.Platform, w.CacheManager(), parallelism, sm, w.Executor(), w)
+			if err != nil || w.SessionGroup == nil {
+				return execOp, err
+			}
+			return &sessionGroupOp{Op: execOp, sessionGroup: w.SessionGroup}, nil
 		case *pb.Op_File:
 			return ops.NewFileOp(v, op, w.CacheManager(), parallelism, w)
 		case *pb.Op_Build:
@@ -194,9 +216,28 @@ func (w *Worker) ResolveOp(v solver.Vertex, s frontend.FrontendLLBBridge, sm *se
 	return nil, errors.Errorf("could not resolve %v", v)
 }
 
+// sessionGroupOp is an operation that runs with the sessions returned by
+// sessionGroup instead of the sessions of the builds that run it.
+type sessionGroupOp struct {
+	solver.Op
+	sessionGroup func(session.Group) session.Group
+}
+
+func (op *sessionGroupOp) CacheMap(ctx context.Context, g session.Group, index int) (*solver.CacheMap, bool, error) {
+	return op.Op.CacheMap(ctx, op.sessionGroup(g), index)
+}
+
+func (op *sessionGroupOp) Exec(ctx context.Context, g session.Group, inputs []solver.Result) ([]solver.Result, error) {
+	return op.Op.Exec(ctx, op.sessionGroup(g), inputs)
+}
+
 // ResolveImageConfig returns image config for an image
 func (w *Worker) ResolveImageConfig(ctx context.Context, ref string, opt llb.ResolveImageConfigOpt, sm *session.Manager, g session.Group) (digest.Digest, []byte, error) {
-	return w.ImageSource.ResolveImageConfig(ctx, ref, opt, sm, g)
+	dgst, dt, err := w.ImageSource.ResolveImageConfig(ctx, ref, opt, sm, g)
+	if err == nil && w.ImageResolved != nil {
+		w.ImageResolved(g, ref, dgst)
+	}
+	return dgst, dt, err
 }
 
 // DiskUsage returns disk usage report
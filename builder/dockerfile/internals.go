@@ -367,6 +367,10 @@ func hostConfigFromOptions(options *types.ImageBuildOptions) *container.HostConf
 		MemorySwap:   options.MemorySwap,
 		Ulimits:      options.Ulimits,
 	}
+	if options.PidsLimit != 0 {
+		pidsLimit := options.PidsLimit
+		resources.PidsLimit = &pidsLimit
+	}
 
 	hc := &container.HostConfig{
 		SecurityOpt: options.SecurityOpt,
@@ -0,0 +1,24 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/docker/docker/api/types"
+)
+
+// BuildFrontendList returns the BuildKit frontends registered with the daemon.
+func (cli *Client) BuildFrontendList(ctx context.Context) ([]types.BuildFrontend, error) {
+	if err := cli.NewVersionError("1.43", "build frontend list"); err != nil {
+		return nil, err
+	}
+	resp, err := cli.get(ctx, "/build/frontends", nil, nil)
+	defer ensureReaderClosed(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	var frontends []types.BuildFrontend
+	err = json.NewDecoder(resp.body).Decode(&frontends)
+	return frontends, err
+}
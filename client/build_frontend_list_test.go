@@ -0,0 +1,63 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/errdefs"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestBuildFrontendListUnsupported(t *testing.T) {
+	client := &Client{
+		version: "1.42",
+		client:  &http.Client{},
+	}
+	_, err := client.BuildFrontendList(context.Background())
+	assert.Check(t, is.Error(err, `"build frontend list" requires API version 1.43, but the Docker daemon API version is 1.42`))
+}
+
+func TestBuildFrontendListError(t *testing.T) {
+	client := &Client{
+		client: newMockClient(errorMock(http.StatusInternalServerError, "Server error")),
+	}
+	_, err := client.BuildFrontendList(context.Background())
+	assert.Check(t, is.ErrorType(err, errdefs.IsSystem))
+}
+
+func TestBuildFrontendList(t *testing.T) {
+	expectedURL := "/build/frontends"
+	client := &Client{
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			if !strings.HasPrefix(req.URL.Path, expectedURL) {
+				return nil, fmt.Errorf("Expected URL '%s', got '%s'", expectedURL, req.URL)
+			}
+			content, err := json.Marshal([]types.BuildFrontend{
+				{
+					Name:  "docker.io/docker/dockerfile:1",
+					Image: "docker.io/docker/dockerfile@sha256:39b85bbfa7536a5feceb7372a0817649ecb2724562a38360f4d6a7782a409b14",
+				},
+			})
+			if err != nil {
+				return nil, err
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewReader(content)),
+			}, nil
+		}),
+	}
+
+	frontends, err := client.BuildFrontendList(context.Background())
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(frontends, 1))
+	assert.Check(t, is.Equal(frontends[0].Name, "docker.io/docker/dockerfile:1"))
+}
@@ -0,0 +1,25 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/docker/docker/api/types"
+)
+
+// BuildFrontendRegister registers a BuildKit frontend image with the daemon.
+// The image must be pinned by digest, and be available on the daemon.
+func (cli *Client) BuildFrontendRegister(ctx context.Context, frontend types.BuildFrontend) (types.BuildFrontend, error) {
+	var response types.BuildFrontend
+	if err := cli.NewVersionError("1.43", "build frontend register"); err != nil {
+		return response, err
+	}
+	resp, err := cli.post(ctx, "/build/frontends", nil, frontend, nil)
+	defer ensureReaderClosed(resp)
+	if err != nil {
+		return response, err
+	}
+
+	err = json.NewDecoder(resp.body).Decode(&response)
+	return response, err
+}
@@ -0,0 +1,60 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/errdefs"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestBuildFrontendRegisterError(t *testing.T) {
+	client := &Client{
+		client: newMockClient(errorMock(http.StatusNotFound, "no such image")),
+	}
+	_, err := client.BuildFrontendRegister(context.Background(), types.BuildFrontend{Name: "docker/dockerfile:1"})
+	assert.Check(t, is.ErrorType(err, errdefs.IsNotFound))
+}
+
+func TestBuildFrontendRegister(t *testing.T) {
+	expectedURL := "/build/frontends"
+	image := "docker.io/docker/dockerfile@sha256:39b85bbfa7536a5feceb7372a0817649ecb2724562a38360f4d6a7782a409b14"
+	client := &Client{
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			if !strings.HasPrefix(req.URL.Path, expectedURL) {
+				return nil, fmt.Errorf("Expected URL '%s', got '%s'", expectedURL, req.URL)
+			}
+			if req.Method != http.MethodPost {
+				return nil, fmt.Errorf("expected POST method, got %s", req.Method)
+			}
+			var frontend types.BuildFrontend
+			if err := json.NewDecoder(req.Body).Decode(&frontend); err != nil {
+				return nil, err
+			}
+			if frontend.Image != image {
+				return nil, fmt.Errorf("expected image %s, got %s", image, frontend.Image)
+			}
+			frontend.Name = "docker.io/docker/dockerfile:1"
+			content, err := json.Marshal(frontend)
+			if err != nil {
+				return nil, err
+			}
+			return &http.Response{
+				StatusCode: http.StatusCreated,
+				Body:       io.NopCloser(bytes.NewReader(content)),
+			}, nil
+		}),
+	}
+
+	frontend, err := client.BuildFrontendRegister(context.Background(), types.BuildFrontend{Name: "docker/dockerfile:1", Image: image})
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(frontend.Name, "docker.io/docker/dockerfile:1"))
+}
@@ -0,0 +1,13 @@
+package client // import "github.com/docker/docker/client"
+
+import "context"
+
+// BuildFrontendRemove removes a BuildKit frontend registered with the daemon.
+func (cli *Client) BuildFrontendRemove(ctx context.Context, name string) error {
+	if err := cli.NewVersionError("1.43", "build frontend remove"); err != nil {
+		return err
+	}
+	resp, err := cli.delete(ctx, "/build/frontends/"+name, nil, nil)
+	defer ensureReaderClosed(resp)
+	return err
+}
@@ -0,0 +1,44 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/errdefs"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestBuildFrontendRemoveNotFound(t *testing.T) {
+	client := &Client{
+		client: newMockClient(errorMock(http.StatusNotFound, "no such build frontend")),
+	}
+	err := client.BuildFrontendRemove(context.Background(), "docker/dockerfile:1")
+	assert.Check(t, is.ErrorType(err, errdefs.IsNotFound))
+}
+
+func TestBuildFrontendRemove(t *testing.T) {
+	expectedURL := "/build/frontends/docker/dockerfile:1"
+	client := &Client{
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			if !strings.HasPrefix(req.URL.Path, expectedURL) {
+				return nil, fmt.Errorf("Expected URL '%s', got '%s'", expectedURL, req.URL)
+			}
+			if req.Method != http.MethodDelete {
+				return nil, fmt.Errorf("expected DELETE method, got %s", req.Method)
+			}
+			return &http.Response{
+				StatusCode: http.StatusNoContent,
+				Body:       io.NopCloser(bytes.NewReader(nil)),
+			}, nil
+		}),
+	}
+
+	err := client.BuildFrontendRemove(context.Background(), "docker/dockerfile:1")
+	assert.NilError(t, err)
+}
@@ -0,0 +1,28 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/docker/docker/api/types"
+)
+
+// BuildRecordInspect returns the record of a BuildKit build, with the SLSA
+// provenance of the build.
+func (cli *Client) BuildRecordInspect(ctx context.Context, id string) (types.BuildRecord, error) {
+	if id == "" {
+		return types.BuildRecord{}, objectNotFoundError{object: "build record", id: id}
+	}
+	if err := cli.NewVersionError("1.43", "build record inspect"); err != nil {
+		return types.BuildRecord{}, err
+	}
+	resp, err := cli.get(ctx, "/build/records/"+id, nil, nil)
+	defer ensureReaderClosed(resp)
+	if err != nil {
+		return types.BuildRecord{}, err
+	}
+
+	var record types.BuildRecord
+	err = json.NewDecoder(resp.body).Decode(&record)
+	return record, err
+}
@@ -0,0 +1,64 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestBuildRecordInspectWithEmptyID(t *testing.T) {
+	client := &Client{
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			return nil, errors.New("should not make request")
+		}),
+	}
+	_, err := client.BuildRecordInspect(context.Background(), "")
+	assert.Check(t, is.ErrorType(err, errdefs.IsNotFound))
+}
+
+func TestBuildRecordInspectNotFound(t *testing.T) {
+	client := &Client{
+		client: newMockClient(errorMock(http.StatusNotFound, "no such build record")),
+	}
+	_, err := client.BuildRecordInspect(context.Background(), "unknown")
+	assert.Check(t, is.ErrorType(err, errdefs.IsNotFound))
+}
+
+func TestBuildRecordInspect(t *testing.T) {
+	expectedURL := "/build/records/build1"
+	client := &Client{
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			if !strings.HasPrefix(req.URL.Path, expectedURL) {
+				return nil, fmt.Errorf("Expected URL '%s', got '%s'", expectedURL, req.URL)
+			}
+			content, err := json.Marshal(types.BuildRecord{
+				ID:         "build1",
+				ImageID:    "sha256:4c0fdaa8b6341bfdeca5f18f7837462c80cff90527ee35ef185571e1c327beac",
+				Provenance: json.RawMessage(`{"predicateType":"https://slsa.dev/provenance/v0.2"}`),
+			})
+			if err != nil {
+				return nil, err
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewReader(content)),
+			}, nil
+		}),
+	}
+
+	record, err := client.BuildRecordInspect(context.Background(), "build1")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(record.ID, "build1"))
+	assert.Check(t, is.Contains(string(record.Provenance), "slsa.dev/provenance"))
+}
@@ -0,0 +1,25 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/docker/docker/api/types"
+)
+
+// BuildRecordList returns the records of the BuildKit builds of the daemon,
+// newest first.
+func (cli *Client) BuildRecordList(ctx context.Context) ([]types.BuildRecord, error) {
+	if err := cli.NewVersionError("1.43", "build record list"); err != nil {
+		return nil, err
+	}
+	resp, err := cli.get(ctx, "/build/records", nil, nil)
+	defer ensureReaderClosed(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []types.BuildRecord
+	err = json.NewDecoder(resp.body).Decode(&records)
+	return records, err
+}
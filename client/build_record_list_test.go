@@ -0,0 +1,61 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/errdefs"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestBuildRecordListUnsupported(t *testing.T) {
+	client := &Client{
+		version: "1.42",
+		client:  &http.Client{},
+	}
+	_, err := client.BuildRecordList(context.Background())
+	assert.Check(t, is.Error(err, `"build record list" requires API version 1.43, but the Docker daemon API version is 1.42`))
+}
+
+func TestBuildRecordListError(t *testing.T) {
+	client := &Client{
+		client: newMockClient(errorMock(http.StatusInternalServerError, "Server error")),
+	}
+	_, err := client.BuildRecordList(context.Background())
+	assert.Check(t, is.ErrorType(err, errdefs.IsSystem))
+}
+
+func TestBuildRecordList(t *testing.T) {
+	expectedURL := "/build/records"
+	client := &Client{
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			if !strings.HasPrefix(req.URL.Path, expectedURL) {
+				return nil, fmt.Errorf("Expected URL '%s', got '%s'", expectedURL, req.URL)
+			}
+			if req.Method != http.MethodGet {
+				return nil, fmt.Errorf("expected GET method, got %s", req.Method)
+			}
+			content, err := json.Marshal([]types.BuildRecord{{ID: "build1"}, {ID: "build2"}})
+			if err != nil {
+				return nil, err
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewReader(content)),
+			}, nil
+		}),
+	}
+
+	records, err := client.BuildRecordList(context.Background())
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(records, 2))
+	assert.Check(t, is.Equal(records[0].ID, "build1"))
+}
@@ -0,0 +1,27 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/docker/docker/api/types"
+)
+
+// ConfigCheck checks the contents of a proposed configuration file of the
+// daemon, without applying them.
+func (cli *Client) ConfigCheck(ctx context.Context, config []byte) (types.ConfigCheck, error) {
+	var result types.ConfigCheck
+	if err := cli.NewVersionError("1.43", "configuration check"); err != nil {
+		return result, err
+	}
+	headers := http.Header{"Content-Type": {"application/json"}}
+	resp, err := cli.postRaw(ctx, "/system/config/check", nil, bytes.NewReader(config), headers)
+	defer ensureReaderClosed(resp)
+	if err != nil {
+		return result, err
+	}
+	err = json.NewDecoder(resp.body).Decode(&result)
+	return result, err
+}
@@ -0,0 +1,24 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/docker/docker/api/types"
+)
+
+// ConfigReloadResult returns the result of the last reload of the
+// configuration of the daemon.
+func (cli *Client) ConfigReloadResult(ctx context.Context) (types.ConfigReload, error) {
+	var result types.ConfigReload
+	if err := cli.NewVersionError("1.43", "configuration reload results"); err != nil {
+		return result, err
+	}
+	resp, err := cli.get(ctx, "/system/config/reload", nil, nil)
+	defer ensureReaderClosed(resp)
+	if err != nil {
+		return result, err
+	}
+	err = json.NewDecoder(resp.body).Decode(&result)
+	return result, err
+}
@@ -0,0 +1,26 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+)
+
+// ConfigRotate creates a new version of a config, and updates the services that
+// follow the latest version of the config to use it.
+func (cli *Client) ConfigRotate(ctx context.Context, id string, config swarm.ConfigSpec) (types.ConfigCreateResponse, error) {
+	var response types.ConfigCreateResponse
+	if err := cli.NewVersionError("1.43", "config rotate"); err != nil {
+		return response, err
+	}
+	resp, err := cli.post(ctx, "/configs/"+id+"/rotate", nil, config, nil)
+	defer ensureReaderClosed(resp)
+	if err != nil {
+		return response, err
+	}
+
+	err = json.NewDecoder(resp.body).Decode(&response)
+	return response, err
+}
@@ -0,0 +1,24 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// ContainersBatch applies a lifecycle action to several containers, and
+// returns the result of the action for each of them.
+func (cli *Client) ContainersBatch(ctx context.Context, req container.BatchRequest) (container.BatchResponse, error) {
+	var response container.BatchResponse
+	if err := cli.NewVersionError("1.43", "container batch operations"); err != nil {
+		return response, err
+	}
+	resp, err := cli.post(ctx, "/containers/batch", nil, req, nil)
+	defer ensureReaderClosed(resp)
+	if err != nil {
+		return response, err
+	}
+	err = json.NewDecoder(resp.body).Decode(&response)
+	return response, err
+}
@@ -42,6 +42,14 @@ func (cli *Client) CopyToContainer(ctx context.Context, containerID, dstPath str
 		query.Set("copyUIDGID", "true")
 	}
 
+	if len(options.Include) > 0 || len(options.Exclude) > 0 {
+		if err := cli.NewVersionError("1.43", "archive filters"); err != nil {
+			return err
+		}
+		query["include"] = options.Include
+		query["exclude"] = options.Exclude
+	}
+
 	apiPath := "/containers/" + containerID + "/archive"
 
 	response, err := cli.putRaw(ctx, apiPath, query, content, nil)
@@ -0,0 +1,38 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// ContainerCreateFromTemplate creates a new container from a container create
+// template stored in the daemon. The overrides are a partial body of a
+// container create request, applied to the template as a JSON merge patch
+// (RFC 7386); they can be nil to use the template as-is.
+func (cli *Client) ContainerCreateFromTemplate(ctx context.Context, template string, overrides json.RawMessage, containerName string) (container.CreateResponse, error) {
+	var response container.CreateResponse
+	if err := cli.NewVersionError("1.43", "container templates"); err != nil {
+		return response, err
+	}
+
+	query := url.Values{}
+	query.Set("template", template)
+	if containerName != "" {
+		query.Set("name", containerName)
+	}
+	if overrides == nil {
+		overrides = json.RawMessage("{}")
+	}
+
+	serverResp, err := cli.post(ctx, "/containers/create", query, overrides, nil)
+	defer ensureReaderClosed(serverResp)
+	if err != nil {
+		return response, err
+	}
+
+	err = json.NewDecoder(serverResp.body).Decode(&response)
+	return response, err
+}
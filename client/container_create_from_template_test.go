@@ -0,0 +1,67 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/errdefs"
+)
+
+func TestContainerCreateFromTemplateError(t *testing.T) {
+	client := &Client{
+		client: newMockClient(errorMock(http.StatusNotFound, "no such template: runner")),
+	}
+
+	_, err := client.ContainerCreateFromTemplate(context.Background(), "runner", nil, "")
+	if !errdefs.IsNotFound(err) {
+		t.Fatalf("expected a Not Found Error, got %[1]T: %[1]v", err)
+	}
+}
+
+func TestContainerCreateFromTemplate(t *testing.T) {
+	expectedURL := "/containers/create"
+
+	client := &Client{
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			if !strings.HasPrefix(req.URL.Path, expectedURL) {
+				return nil, fmt.Errorf("Expected URL '%s', got '%s'", expectedURL, req.URL)
+			}
+			if template := req.URL.Query().Get("template"); template != "runner" {
+				return nil, fmt.Errorf("expected template 'runner', got '%s'", template)
+			}
+			if name := req.URL.Query().Get("name"); name != "runner-1" {
+				return nil, fmt.Errorf("expected name 'runner-1', got '%s'", name)
+			}
+			body, err := io.ReadAll(req.Body)
+			if err != nil {
+				return nil, err
+			}
+			if strings.TrimSpace(string(body)) != `{"Env":["ID=1"]}` {
+				return nil, fmt.Errorf("unexpected overrides: %s", body)
+			}
+			content, err := json.Marshal(container.CreateResponse{ID: "container_id"})
+			if err != nil {
+				return nil, err
+			}
+			return &http.Response{
+				StatusCode: http.StatusCreated,
+				Body:       io.NopCloser(bytes.NewReader(content)),
+			}, nil
+		}),
+	}
+
+	r, err := client.ContainerCreateFromTemplate(context.Background(), "runner", json.RawMessage(`{"Env":["ID=1"]}`), "runner-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.ID != "container_id" {
+		t.Fatalf("expected `container_id`, got %s", r.ID)
+	}
+}
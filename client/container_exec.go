@@ -3,6 +3,7 @@ package client // import "github.com/docker/docker/client"
 import (
 	"context"
 	"encoding/json"
+	"net/url"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/versions"
@@ -18,6 +19,9 @@ func (cli *Client) ContainerExecCreate(ctx context.Context, container string, co
 	if versions.LessThan(cli.ClientVersion(), "1.42") {
 		config.ConsoleSize = nil
 	}
+	if err := cli.NewVersionError("1.43", "exec resource limits"); config.Resources != nil && err != nil {
+		return response, err
+	}
 
 	resp, err := cli.post(ctx, "/containers/"+container+"/exec", nil, config, nil)
 	defer ensureReaderClosed(resp)
@@ -64,3 +68,36 @@ func (cli *Client) ContainerExecInspect(ctx context.Context, execID string) (typ
 	ensureReaderClosed(resp)
 	return response, err
 }
+
+// ContainerExecList returns the exec processes of a container, including
+// those which exited but were not cleaned up yet.
+func (cli *Client) ContainerExecList(ctx context.Context, container string) ([]types.ContainerExecInspect, error) {
+	if err := cli.NewVersionError("1.43", "exec list"); err != nil {
+		return nil, err
+	}
+	var response []types.ContainerExecInspect
+	resp, err := cli.get(ctx, "/containers/"+container+"/execs", nil, nil)
+	defer ensureReaderClosed(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.NewDecoder(resp.body).Decode(&response)
+	return response, err
+}
+
+// ContainerExecKill sends a signal to an exec process. The process is killed
+// if no signal is given.
+func (cli *Client) ContainerExecKill(ctx context.Context, execID, signal string) error {
+	if err := cli.NewVersionError("1.43", "exec kill"); err != nil {
+		return err
+	}
+	query := url.Values{}
+	if signal != "" {
+		query.Set("signal", signal)
+	}
+
+	resp, err := cli.post(ctx, "/exec/"+execID+"/kill", query, nil, nil)
+	ensureReaderClosed(resp)
+	return err
+}
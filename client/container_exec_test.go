@@ -155,3 +155,57 @@ func TestContainerExecInspect(t *testing.T) {
 		t.Fatalf("expected ContainerID `container_id`, got %s", inspect.ContainerID)
 	}
 }
+
+func TestContainerExecList(t *testing.T) {
+	expectedURL := "/containers/container_id/execs"
+	client := &Client{
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			if !strings.HasPrefix(req.URL.Path, expectedURL) {
+				return nil, fmt.Errorf("Expected URL '%s', got '%s'", expectedURL, req.URL)
+			}
+			b, err := json.Marshal([]types.ContainerExecInspect{
+				{ExecID: "exec_id", ContainerID: "container_id", Running: true, StartedAt: "2023-01-01T00:00:00Z"},
+			})
+			if err != nil {
+				return nil, err
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewReader(b)),
+			}, nil
+		}),
+	}
+
+	execs, err := client.ContainerExecList(context.Background(), "container_id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(execs) != 1 || execs[0].ExecID != "exec_id" || execs[0].StartedAt != "2023-01-01T00:00:00Z" {
+		t.Fatalf("unexpected execs: %+v", execs)
+	}
+}
+
+func TestContainerExecKill(t *testing.T) {
+	expectedURL := "/exec/exec_id/kill"
+	client := &Client{
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			if !strings.HasPrefix(req.URL.Path, expectedURL) {
+				return nil, fmt.Errorf("Expected URL '%s', got '%s'", expectedURL, req.URL)
+			}
+			if req.Method != http.MethodPost {
+				return nil, fmt.Errorf("expected POST method, got %s", req.Method)
+			}
+			if signal := req.URL.Query().Get("signal"); signal != "SIGTERM" {
+				return nil, fmt.Errorf("signal not set in URL query properly. Expected 'SIGTERM', got %s", signal)
+			}
+			return &http.Response{
+				StatusCode: http.StatusNoContent,
+				Body:       io.NopCloser(bytes.NewReader(nil)),
+			}, nil
+		}),
+	}
+
+	if err := client.ContainerExecKill(context.Background(), "exec_id", "SIGTERM"); err != nil {
+		t.Fatal(err)
+	}
+}
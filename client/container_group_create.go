@@ -0,0 +1,23 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// ContainerGroupCreate creates a container group.
+func (cli *Client) ContainerGroupCreate(ctx context.Context, req container.GroupCreateRequest) (container.Group, error) {
+	var g container.Group
+	if err := cli.NewVersionError("1.43", "container groups"); err != nil {
+		return g, err
+	}
+	resp, err := cli.post(ctx, "/groups/create", nil, req, nil)
+	defer ensureReaderClosed(resp)
+	if err != nil {
+		return g, err
+	}
+	err = json.NewDecoder(resp.body).Decode(&g)
+	return g, err
+}
@@ -0,0 +1,27 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// ContainerGroupInspect returns the container group with the given ID or
+// name.
+func (cli *Client) ContainerGroupInspect(ctx context.Context, group string) (container.Group, error) {
+	var g container.Group
+	if err := cli.NewVersionError("1.43", "container groups"); err != nil {
+		return g, err
+	}
+	if group == "" {
+		return g, objectNotFoundError{object: "group", id: group}
+	}
+	resp, err := cli.get(ctx, "/groups/"+group, nil, nil)
+	defer ensureReaderClosed(resp)
+	if err != nil {
+		return g, err
+	}
+	err = json.NewDecoder(resp.body).Decode(&g)
+	return g, err
+}
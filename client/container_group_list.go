@@ -0,0 +1,23 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// ContainerGroupList returns the container groups.
+func (cli *Client) ContainerGroupList(ctx context.Context) ([]container.Group, error) {
+	var groups []container.Group
+	if err := cli.NewVersionError("1.43", "container groups"); err != nil {
+		return groups, err
+	}
+	resp, err := cli.get(ctx, "/groups", nil, nil)
+	defer ensureReaderClosed(resp)
+	if err != nil {
+		return groups, err
+	}
+	err = json.NewDecoder(resp.body).Decode(&groups)
+	return groups, err
+}
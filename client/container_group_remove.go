@@ -0,0 +1,21 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"net/url"
+)
+
+// ContainerGroupRemove removes a container group. A group with members is only
+// removed if force is set, in which case its members are removed too.
+func (cli *Client) ContainerGroupRemove(ctx context.Context, group string, force bool) error {
+	if err := cli.NewVersionError("1.43", "container groups"); err != nil {
+		return err
+	}
+	query := url.Values{}
+	if force {
+		query.Set("force", "1")
+	}
+	resp, err := cli.delete(ctx, "/groups/"+group, query, nil)
+	defer ensureReaderClosed(resp)
+	return err
+}
@@ -0,0 +1,13 @@
+package client // import "github.com/docker/docker/client"
+
+import "context"
+
+// ContainerGroupStart starts the members of a container group.
+func (cli *Client) ContainerGroupStart(ctx context.Context, group string) error {
+	if err := cli.NewVersionError("1.43", "container groups"); err != nil {
+		return err
+	}
+	resp, err := cli.post(ctx, "/groups/"+group+"/start", nil, nil, nil)
+	ensureReaderClosed(resp)
+	return err
+}
@@ -0,0 +1,24 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/docker/docker/api/types"
+)
+
+// ContainerGroupStats returns a single sample of the stats of each member of a
+// container group.
+func (cli *Client) ContainerGroupStats(ctx context.Context, group string) ([]types.StatsJSON, error) {
+	var stats []types.StatsJSON
+	if err := cli.NewVersionError("1.43", "container groups"); err != nil {
+		return stats, err
+	}
+	resp, err := cli.get(ctx, "/groups/"+group+"/stats", nil, nil)
+	defer ensureReaderClosed(resp)
+	if err != nil {
+		return stats, err
+	}
+	err = json.NewDecoder(resp.body).Decode(&stats)
+	return stats, err
+}
@@ -0,0 +1,27 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// ContainerGroupStop stops the members of a container group, and releases
+// the network sandbox of the group.
+func (cli *Client) ContainerGroupStop(ctx context.Context, group string, options container.StopOptions) error {
+	if err := cli.NewVersionError("1.43", "container groups"); err != nil {
+		return err
+	}
+	query := url.Values{}
+	if options.Timeout != nil {
+		query.Set("t", strconv.Itoa(*options.Timeout))
+	}
+	if options.Signal != "" {
+		query.Set("signal", options.Signal)
+	}
+	resp, err := cli.post(ctx, "/groups/"+group+"/stop", query, nil, nil)
+	ensureReaderClosed(resp)
+	return err
+}
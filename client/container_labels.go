@@ -0,0 +1,24 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/docker/docker/api/types"
+)
+
+// ContainerUpdateLabels adds and removes labels of a container, and returns the
+// new labels.
+func (cli *Client) ContainerUpdateLabels(ctx context.Context, containerID string, update types.LabelsUpdate) (map[string]string, error) {
+	if err := cli.NewVersionError("1.43", "container label updates"); err != nil {
+		return nil, err
+	}
+	resp, err := cli.post(ctx, "/containers/"+containerID+"/labels", nil, update, nil)
+	defer ensureReaderClosed(resp)
+	if err != nil {
+		return nil, err
+	}
+	var result types.LabelsUpdateResponse
+	err = json.NewDecoder(resp.body).Decode(&result)
+	return result.Labels, err
+}
@@ -8,10 +8,19 @@ import (
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/pagination"
 )
 
 // ContainerList returns the list of containers in the docker host.
 func (cli *Client) ContainerList(ctx context.Context, options types.ContainerListOptions) ([]types.Container, error) {
+	containers, _, err := cli.ContainerListPage(ctx, options)
+	return containers, err
+}
+
+// ContainerListPage returns a page of the list of containers in the docker
+// host, and the cursor of the next page, if there may be more containers to
+// list. The cursor is passed in the options to get the next page.
+func (cli *Client) ContainerListPage(ctx context.Context, options types.ContainerListOptions) ([]types.Container, string, error) {
 	query := url.Values{}
 
 	if options.All {
@@ -34,12 +43,19 @@ func (cli *Client) ContainerList(ctx context.Context, options types.ContainerLis
 		query.Set("size", "1")
 	}
 
+	if options.Cursor != "" {
+		if err := cli.NewVersionError("1.43", "cursor"); err != nil {
+			return nil, "", err
+		}
+		query.Set("cursor", options.Cursor)
+	}
+
 	if options.Filters.Len() > 0 {
 		//nolint:staticcheck // ignore SA1019 for old code
 		filterJSON, err := filters.ToParamWithVersion(cli.version, options.Filters)
 
 		if err != nil {
-			return nil, err
+			return nil, "", err
 		}
 
 		query.Set("filters", filterJSON)
@@ -48,10 +64,10 @@ func (cli *Client) ContainerList(ctx context.Context, options types.ContainerLis
 	resp, err := cli.get(ctx, "/containers/json", query, nil)
 	defer ensureReaderClosed(resp)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	var containers []types.Container
 	err = json.NewDecoder(resp.body).Decode(&containers)
-	return containers, err
+	return containers, resp.header.Get(pagination.NextCursorHeader), err
 }
@@ -95,3 +95,42 @@ func TestContainerList(t *testing.T) {
 		t.Fatalf("expected 2 containers, got %v", containers)
 	}
 }
+
+func TestContainerListPage(t *testing.T) {
+	client := &Client{
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			query := req.URL.Query()
+			if limit := query.Get("limit"); limit != "2" {
+				return nil, fmt.Errorf("limit not set in URL query properly. Expected '2', got %s", limit)
+			}
+			if cursor := query.Get("cursor"); cursor != "cursor1" {
+				return nil, fmt.Errorf("cursor not set in URL query properly. Expected 'cursor1', got %s", cursor)
+			}
+			b, err := json.Marshal([]types.Container{{ID: "container_id1"}, {ID: "container_id2"}})
+			if err != nil {
+				return nil, err
+			}
+			header := http.Header{}
+			header.Set("Docker-Next-Cursor", "cursor2")
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     header,
+				Body:       io.NopCloser(bytes.NewReader(b)),
+			}, nil
+		}),
+	}
+
+	containers, next, err := client.ContainerListPage(context.Background(), types.ContainerListOptions{
+		Limit:  2,
+		Cursor: "cursor1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(containers) != 2 {
+		t.Fatalf("expected 2 containers, got %v", containers)
+	}
+	if next != "cursor2" {
+		t.Fatalf("expected next cursor 'cursor2', got %s", next)
+	}
+}
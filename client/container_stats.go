@@ -3,8 +3,10 @@ package client // import "github.com/docker/docker/client"
 import (
 	"context"
 	"net/url"
+	"strconv"
 
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
 )
 
 // ContainerStats returns near realtime stats for a given container.
@@ -40,3 +42,33 @@ func (cli *Client) ContainerStatsOneShot(ctx context.Context, containerID string
 	osType := getDockerOS(resp.header.Get("Server"))
 	return types.ContainerStats{Body: resp.body, OSType: osType}, err
 }
+
+// ContainersStats returns the stats of all running containers matching the
+// filters in a single response. If options.Stream is set, a new snapshot is
+// sent at every options.Interval. It's up to the caller to close the
+// io.ReadCloser returned.
+func (cli *Client) ContainersStats(ctx context.Context, options types.ContainersStatsOptions) (types.ContainerStats, error) {
+	query := url.Values{}
+	query.Set("stream", "0")
+	if options.Stream {
+		query.Set("stream", "1")
+	}
+	if options.Interval > 0 {
+		query.Set("interval", strconv.Itoa(int(options.Interval.Seconds())))
+	}
+	if options.Filters.Len() > 0 {
+		filterJSON, err := filters.ToJSON(options.Filters)
+		if err != nil {
+			return types.ContainerStats{}, err
+		}
+		query.Set("filters", filterJSON)
+	}
+
+	resp, err := cli.get(ctx, "/containers/stats", query, nil)
+	if err != nil {
+		return types.ContainerStats{}, err
+	}
+
+	osType := getDockerOS(resp.header.Get("Server"))
+	return types.ContainerStats{Body: resp.body, OSType: osType}, err
+}
@@ -8,7 +8,10 @@ import (
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/errdefs"
 )
 
@@ -69,3 +72,46 @@ func TestContainerStats(t *testing.T) {
 		}
 	}
 }
+
+func TestContainersStats(t *testing.T) {
+	expectedURL := "/containers/stats"
+	client := &Client{
+		client: newMockClient(func(r *http.Request) (*http.Response, error) {
+			if r.URL.Path != expectedURL {
+				return nil, fmt.Errorf("Expected URL '%s', got '%s'", expectedURL, r.URL)
+			}
+
+			query := r.URL.Query()
+			if stream := query.Get("stream"); stream != "1" {
+				return nil, fmt.Errorf("stream not set in URL query properly. Expected '1', got %s", stream)
+			}
+			if interval := query.Get("interval"); interval != "5" {
+				return nil, fmt.Errorf("interval not set in URL query properly. Expected '5', got %s", interval)
+			}
+			if f := query.Get("filters"); f != `{"label":{"foo=bar":true}}` {
+				return nil, fmt.Errorf("filters not set in URL query properly. Got %s", f)
+			}
+
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewReader([]byte("response"))),
+			}, nil
+		}),
+	}
+	resp, err := client.ContainersStats(context.Background(), types.ContainersStatsOptions{
+		Stream:   true,
+		Interval: 5 * time.Second,
+		Filters:  filters.NewArgs(filters.Arg("label", "foo=bar")),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "response" {
+		t.Fatalf("expected response to contain 'response', got %s", string(content))
+	}
+}
@@ -0,0 +1,23 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// ContainerTemplateCreate stores a container create template in the daemon.
+func (cli *Client) ContainerTemplateCreate(ctx context.Context, req container.TemplateCreateRequest) (container.Template, error) {
+	var t container.Template
+	if err := cli.NewVersionError("1.43", "container templates"); err != nil {
+		return t, err
+	}
+	resp, err := cli.post(ctx, "/templates/create", nil, req, nil)
+	defer ensureReaderClosed(resp)
+	if err != nil {
+		return t, err
+	}
+	err = json.NewDecoder(resp.body).Decode(&t)
+	return t, err
+}
@@ -0,0 +1,27 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// ContainerTemplateInspect returns the container create template with the
+// given name.
+func (cli *Client) ContainerTemplateInspect(ctx context.Context, name string) (container.Template, error) {
+	var t container.Template
+	if err := cli.NewVersionError("1.43", "container templates"); err != nil {
+		return t, err
+	}
+	if name == "" {
+		return t, objectNotFoundError{object: "template", id: name}
+	}
+	resp, err := cli.get(ctx, "/templates/"+name, nil, nil)
+	defer ensureReaderClosed(resp)
+	if err != nil {
+		return t, err
+	}
+	err = json.NewDecoder(resp.body).Decode(&t)
+	return t, err
+}
@@ -0,0 +1,24 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// ContainerTemplateList returns the container create templates stored in the
+// daemon.
+func (cli *Client) ContainerTemplateList(ctx context.Context) ([]container.Template, error) {
+	var templates []container.Template
+	if err := cli.NewVersionError("1.43", "container templates"); err != nil {
+		return templates, err
+	}
+	resp, err := cli.get(ctx, "/templates", nil, nil)
+	defer ensureReaderClosed(resp)
+	if err != nil {
+		return templates, err
+	}
+	err = json.NewDecoder(resp.body).Decode(&templates)
+	return templates, err
+}
@@ -0,0 +1,14 @@
+package client // import "github.com/docker/docker/client"
+
+import "context"
+
+// ContainerTemplateRemove removes the container create template with the
+// given name.
+func (cli *Client) ContainerTemplateRemove(ctx context.Context, name string) error {
+	if err := cli.NewVersionError("1.43", "container templates"); err != nil {
+		return err
+	}
+	resp, err := cli.delete(ctx, "/templates/"+name, nil, nil)
+	defer ensureReaderClosed(resp)
+	return err
+}
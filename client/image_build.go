@@ -31,6 +31,12 @@ func (cli *Client) ImageBuild(ctx context.Context, buildContext io.Reader, optio
 	headers.Add("X-Registry-Config", base64.URLEncoding.EncodeToString(buf))
 
 	headers.Set("Content-Type", "application/x-tar")
+	if options.ProgressV2 {
+		if err := cli.NewVersionError("1.43", "progress v2"); err != nil {
+			return types.ImageBuildResponse{}, err
+		}
+		headers.Set("Accept", types.MediaTypeProgressV2)
+	}
 
 	serverResp, err := cli.postRaw(ctx, "/build", query, buildContext, headers)
 	if err != nil {
@@ -142,5 +148,47 @@ func (cli *Client) imageBuildOptionsToQuery(options types.ImageBuildOptions) (ur
 		}
 		query.Set("outputs", string(outputsJSON))
 	}
+	if options.CacheImports != nil {
+		if err := cli.NewVersionError("1.43", "cacheimports"); err != nil {
+			return query, err
+		}
+		cacheImportsJSON, err := json.Marshal(options.CacheImports)
+		if err != nil {
+			return query, err
+		}
+		query.Set("cacheimports", string(cacheImportsJSON))
+	}
+	if options.CacheExports != nil {
+		if err := cli.NewVersionError("1.43", "cacheexports"); err != nil {
+			return query, err
+		}
+		cacheExportsJSON, err := json.Marshal(options.CacheExports)
+		if err != nil {
+			return query, err
+		}
+		query.Set("cacheexports", string(cacheExportsJSON))
+	}
+	if options.Secrets != nil {
+		if err := cli.NewVersionError("1.43", "secrets"); err != nil {
+			return query, err
+		}
+		secretsJSON, err := json.Marshal(options.Secrets)
+		if err != nil {
+			return query, err
+		}
+		query.Set("secrets", string(secretsJSON))
+	}
+	if options.PidsLimit != 0 {
+		if err := cli.NewVersionError("1.43", "pidslimit"); err != nil {
+			return query, err
+		}
+		query.Set("pidslimit", strconv.FormatInt(options.PidsLimit, 10))
+	}
+	if options.ScratchLimit != 0 {
+		if err := cli.NewVersionError("1.43", "scratchlimit"); err != nil {
+			return query, err
+		}
+		query.Set("scratchlimit", strconv.FormatInt(options.ScratchLimit, 10))
+	}
 	return query, nil
 }
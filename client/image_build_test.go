@@ -157,6 +157,44 @@ func TestImageBuild(t *testing.T) {
 			expectedTags:           []string{},
 			expectedRegistryConfig: "eyJodHRwczovL2luZGV4LmRvY2tlci5pby92MS8iOnsiYXV0aCI6ImRHOTBid289In19",
 		},
+		{
+			buildOptions: types.ImageBuildOptions{
+				CacheImports: []types.ImageBuildCache{
+					{Type: "registry", Attrs: map[string]string{"ref": "example.com/app:cache"}},
+				},
+				CacheExports: []types.ImageBuildCache{
+					{Type: "registry", Attrs: map[string]string{"ref": "example.com/app:cache", "mode": "max"}},
+				},
+			},
+			expectedQueryParams: map[string]string{
+				"cacheimports": `[{"Type":"registry","Attrs":{"ref":"example.com/app:cache"}}]`,
+				"cacheexports": `[{"Type":"registry","Attrs":{"mode":"max","ref":"example.com/app:cache"}}]`,
+			},
+			expectedTags:           []string{},
+			expectedRegistryConfig: emptyRegistryConfig,
+		},
+		{
+			buildOptions: types.ImageBuildOptions{
+				Secrets: []types.ImageBuildSecret{{ID: "npmrc"}, {ID: "token", Name: "ci_token"}},
+			},
+			expectedQueryParams: map[string]string{
+				"secrets": `[{"ID":"npmrc"},{"ID":"token","Name":"ci_token"}]`,
+			},
+			expectedTags:           []string{},
+			expectedRegistryConfig: emptyRegistryConfig,
+		},
+		{
+			buildOptions: types.ImageBuildOptions{
+				PidsLimit:    512,
+				ScratchLimit: 1 << 30,
+			},
+			expectedQueryParams: map[string]string{
+				"pidslimit":    "512",
+				"scratchlimit": "1073741824",
+			},
+			expectedTags:           []string{},
+			expectedRegistryConfig: emptyRegistryConfig,
+		},
 	}
 	for _, buildCase := range buildCases {
 		expectedURL := "/build"
@@ -25,14 +25,17 @@ func (cli *Client) ImageCreate(ctx context.Context, parentReference string, opti
 	if options.Platform != "" {
 		query.Set("platform", strings.ToLower(options.Platform))
 	}
-	resp, err := cli.tryImageCreate(ctx, query, options.RegistryAuth)
+	resp, err := cli.tryImageCreate(ctx, query, options.RegistryAuth, false)
 	if err != nil {
 		return nil, err
 	}
 	return resp.body, nil
 }
 
-func (cli *Client) tryImageCreate(ctx context.Context, query url.Values, registryAuth string) (serverResponse, error) {
+func (cli *Client) tryImageCreate(ctx context.Context, query url.Values, registryAuth string, progressV2 bool) (serverResponse, error) {
 	headers := map[string][]string{registry.AuthHeader: {registryAuth}}
+	if progressV2 {
+		headers["Accept"] = []string{types.MediaTypeProgressV2}
+	}
 	return cli.post(ctx, "/images/create", query, nil, headers)
 }
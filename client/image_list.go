@@ -4,14 +4,24 @@ import (
 	"context"
 	"encoding/json"
 	"net/url"
+	"strconv"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/pagination"
 	"github.com/docker/docker/api/types/versions"
 )
 
 // ImageList returns a list of images in the docker host.
 func (cli *Client) ImageList(ctx context.Context, options types.ImageListOptions) ([]types.ImageSummary, error) {
+	images, _, err := cli.ImageListPage(ctx, options)
+	return images, err
+}
+
+// ImageListPage returns a page of the list of images in the docker host, and
+// the cursor of the next page, if there may be more images to list. The
+// cursor is passed in the options to get the next page.
+func (cli *Client) ImageListPage(ctx context.Context, options types.ImageListOptions) ([]types.ImageSummary, string, error) {
 	var images []types.ImageSummary
 	query := url.Values{}
 
@@ -27,7 +37,7 @@ func (cli *Client) ImageList(ctx context.Context, options types.ImageListOptions
 		//nolint:staticcheck // ignore SA1019 for old code
 		filterJSON, err := filters.ToParamWithVersion(cli.version, optionFilters)
 		if err != nil {
-			return images, err
+			return images, "", err
 		}
 		query.Set("filters", filterJSON)
 	}
@@ -37,13 +47,24 @@ func (cli *Client) ImageList(ctx context.Context, options types.ImageListOptions
 	if options.SharedSize && versions.GreaterThanOrEqualTo(cli.version, "1.42") {
 		query.Set("shared-size", "1")
 	}
+	if options.Limit > 0 || options.Cursor != "" {
+		if err := cli.NewVersionError("1.43", "limit"); err != nil {
+			return images, "", err
+		}
+		if options.Limit > 0 {
+			query.Set("limit", strconv.Itoa(options.Limit))
+		}
+		if options.Cursor != "" {
+			query.Set("cursor", options.Cursor)
+		}
+	}
 
 	serverResp, err := cli.get(ctx, "/images/json", query, nil)
 	defer ensureReaderClosed(serverResp)
 	if err != nil {
-		return images, err
+		return images, "", err
 	}
 
 	err = json.NewDecoder(serverResp.body).Decode(&images)
-	return images, err
+	return images, serverResp.header.Get(pagination.NextCursorHeader), err
 }
@@ -199,3 +199,29 @@ func TestImageListWithSharedSize(t *testing.T) {
 		})
 	}
 }
+
+func TestImageListPage(t *testing.T) {
+	var query url.Values
+	client := &Client{
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			query = req.URL.Query()
+			header := http.Header{}
+			header.Set("Docker-Next-Cursor", "cursor2")
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     header,
+				Body:       io.NopCloser(strings.NewReader(`[{"Id":"image_id1"}]`)),
+			}, nil
+		}),
+	}
+	images, next, err := client.ImageListPage(context.Background(), types.ImageListOptions{Limit: 1, Cursor: "cursor1"})
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(images, 1))
+	assert.Check(t, is.Equal(next, "cursor2"))
+	assert.Check(t, is.Equal(query.Get("limit"), "1"))
+	assert.Check(t, is.Equal(query.Get("cursor"), "cursor1"))
+
+	client.version = "1.42"
+	_, _, err = client.ImageListPage(context.Background(), types.ImageListOptions{Limit: 1})
+	assert.Check(t, is.ErrorContains(err, `"limit" requires API version 1.43`))
+}
@@ -198,3 +198,32 @@ func TestImagePullWithoutErrors(t *testing.T) {
 		}
 	}
 }
+
+func TestImagePullProgressV2(t *testing.T) {
+	client := &Client{
+		version: "1.42",
+		client:  newMockClient(errorMock(http.StatusInternalServerError, "Server error")),
+	}
+	_, err := client.ImagePull(context.Background(), "myimage", types.ImagePullOptions{ProgressV2: true})
+	if err == nil || !strings.Contains(err.Error(), `"progress v2" requires API version 1.43`) {
+		t.Fatalf("expected a version error, got %v", err)
+	}
+
+	client = &Client{
+		version: "1.43",
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			if accept := req.Header.Get("Accept"); accept != types.MediaTypeProgressV2 {
+				return nil, fmt.Errorf("expected Accept header %s, got %s", types.MediaTypeProgressV2, accept)
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewReader([]byte(`{"type":"summary","summary":{"bytes":0}}`))),
+			}, nil
+		}),
+	}
+	resp, err := client.ImagePull(context.Background(), "myimage", types.ImagePullOptions{ProgressV2: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Close()
+}
@@ -35,13 +35,19 @@ func (cli *Client) ImagePush(ctx context.Context, image string, options types.Im
 		}
 	}
 
-	resp, err := cli.tryImagePush(ctx, name, query, options.RegistryAuth)
+	if options.ProgressV2 {
+		if err := cli.NewVersionError("1.43", "progress v2"); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := cli.tryImagePush(ctx, name, query, options.RegistryAuth, options.ProgressV2)
 	if errdefs.IsUnauthorized(err) && options.PrivilegeFunc != nil {
 		newAuthHeader, privilegeErr := options.PrivilegeFunc()
 		if privilegeErr != nil {
 			return nil, privilegeErr
 		}
-		resp, err = cli.tryImagePush(ctx, name, query, newAuthHeader)
+		resp, err = cli.tryImagePush(ctx, name, query, newAuthHeader, options.ProgressV2)
 	}
 	if err != nil {
 		return nil, err
@@ -49,7 +55,10 @@ func (cli *Client) ImagePush(ctx context.Context, image string, options types.Im
 	return resp.body, nil
 }
 
-func (cli *Client) tryImagePush(ctx context.Context, imageID string, query url.Values, registryAuth string) (serverResponse, error) {
+func (cli *Client) tryImagePush(ctx context.Context, imageID string, query url.Values, registryAuth string, progressV2 bool) (serverResponse, error) {
 	headers := map[string][]string{registry.AuthHeader: {registryAuth}}
+	if progressV2 {
+		headers["Accept"] = []string{types.MediaTypeProgressV2}
+	}
 	return cli.post(ctx, "/images/"+imageID+"/push", query, nil, headers)
 }
@@ -11,8 +11,21 @@ import (
 
 // Info returns information about the docker server.
 func (cli *Client) Info(ctx context.Context) (types.Info, error) {
+	return cli.InfoWithOptions(ctx, types.InfoOptions{})
+}
+
+// InfoWithOptions returns information about the docker server, including the
+// details which are only useful for debugging if options.Verbose is set.
+func (cli *Client) InfoWithOptions(ctx context.Context, options types.InfoOptions) (types.Info, error) {
 	var info types.Info
-	serverResp, err := cli.get(ctx, "/info", url.Values{}, nil)
+	query := url.Values{}
+	if options.Verbose {
+		if err := cli.NewVersionError("1.43", "verbose info"); err != nil {
+			return info, err
+		}
+		query.Set("verbose", "1")
+	}
+	serverResp, err := cli.get(ctx, "/info", query, nil)
 	defer ensureReaderClosed(serverResp)
 	if err != nil {
 		return info, err
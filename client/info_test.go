@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/errdefs"
@@ -75,3 +76,34 @@ func TestInfo(t *testing.T) {
 		t.Fatalf("expected 3 containers, got %d", info.Containers)
 	}
 }
+
+func TestInfoVerbose(t *testing.T) {
+	client := &Client{
+		version: "1.43",
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			if verbose := req.URL.Query().Get("verbose"); verbose != "1" {
+				return nil, fmt.Errorf("verbose not set in URL query properly. Expected '1', got %s", verbose)
+			}
+			info := &types.Info{
+				ID:            "daemonID",
+				StartupPhases: []types.StartupPhase{{Name: "restore", Duration: time.Second}},
+			}
+			b, err := json.Marshal(info)
+			if err != nil {
+				return nil, err
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewReader(b)),
+			}, nil
+		}),
+	}
+
+	info, err := client.InfoWithOptions(context.Background(), types.InfoOptions{Verbose: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(info.StartupPhases) != 1 || info.StartupPhases[0].Name != "restore" {
+		t.Fatalf("expected the restore startup phase, got %v", info.StartupPhases)
+	}
+}
@@ -2,9 +2,11 @@ package client // import "github.com/docker/docker/client"
 
 import (
 	"context"
+	"encoding/json"
 	"io"
 	"net"
 	"net/http"
+	"time"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
@@ -30,6 +32,7 @@ type CommonAPIClient interface {
 	ServiceAPIClient
 	SwarmAPIClient
 	SecretAPIClient
+	StackAPIClient
 	SystemAPIClient
 	VolumeAPIClient
 	ClientVersion() string
@@ -48,17 +51,28 @@ type ContainerAPIClient interface {
 	ContainerAttach(ctx context.Context, container string, options types.ContainerAttachOptions) (types.HijackedResponse, error)
 	ContainerCommit(ctx context.Context, container string, options types.ContainerCommitOptions) (types.IDResponse, error)
 	ContainerCreate(ctx context.Context, config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig, platform *specs.Platform, containerName string) (container.CreateResponse, error)
+	ContainerCreateFromTemplate(ctx context.Context, template string, overrides json.RawMessage, containerName string) (container.CreateResponse, error)
 	ContainerDiff(ctx context.Context, container string) ([]container.ContainerChangeResponseItem, error)
 	ContainerExecAttach(ctx context.Context, execID string, config types.ExecStartCheck) (types.HijackedResponse, error)
 	ContainerExecCreate(ctx context.Context, container string, config types.ExecConfig) (types.IDResponse, error)
 	ContainerExecInspect(ctx context.Context, execID string) (types.ContainerExecInspect, error)
+	ContainerExecKill(ctx context.Context, execID, signal string) error
+	ContainerExecList(ctx context.Context, container string) ([]types.ContainerExecInspect, error)
 	ContainerExecResize(ctx context.Context, execID string, options types.ResizeOptions) error
 	ContainerExecStart(ctx context.Context, execID string, config types.ExecStartCheck) error
 	ContainerExport(ctx context.Context, container string) (io.ReadCloser, error)
+	ContainerGroupCreate(ctx context.Context, req container.GroupCreateRequest) (container.Group, error)
+	ContainerGroupInspect(ctx context.Context, group string) (container.Group, error)
+	ContainerGroupList(ctx context.Context) ([]container.Group, error)
+	ContainerGroupRemove(ctx context.Context, group string, force bool) error
+	ContainerGroupStart(ctx context.Context, group string) error
+	ContainerGroupStop(ctx context.Context, group string, options container.StopOptions) error
+	ContainerGroupStats(ctx context.Context, group string) ([]types.StatsJSON, error)
 	ContainerInspect(ctx context.Context, container string) (types.ContainerJSON, error)
 	ContainerInspectWithRaw(ctx context.Context, container string, getSize bool) (types.ContainerJSON, []byte, error)
 	ContainerKill(ctx context.Context, container, signal string) error
 	ContainerList(ctx context.Context, options types.ContainerListOptions) ([]types.Container, error)
+	ContainerListPage(ctx context.Context, options types.ContainerListOptions) ([]types.Container, string, error)
 	ContainerLogs(ctx context.Context, container string, options types.ContainerLogsOptions) (io.ReadCloser, error)
 	ContainerPause(ctx context.Context, container string) error
 	ContainerRemove(ctx context.Context, container string, options types.ContainerRemoveOptions) error
@@ -68,12 +82,19 @@ type ContainerAPIClient interface {
 	ContainerStatPath(ctx context.Context, container, path string) (types.ContainerPathStat, error)
 	ContainerStats(ctx context.Context, container string, stream bool) (types.ContainerStats, error)
 	ContainerStatsOneShot(ctx context.Context, container string) (types.ContainerStats, error)
+	ContainersStats(ctx context.Context, options types.ContainersStatsOptions) (types.ContainerStats, error)
 	ContainerStart(ctx context.Context, container string, options types.ContainerStartOptions) error
 	ContainerStop(ctx context.Context, container string, options container.StopOptions) error
+	ContainerTemplateCreate(ctx context.Context, req container.TemplateCreateRequest) (container.Template, error)
+	ContainerTemplateInspect(ctx context.Context, name string) (container.Template, error)
+	ContainerTemplateList(ctx context.Context) ([]container.Template, error)
+	ContainerTemplateRemove(ctx context.Context, name string) error
 	ContainerTop(ctx context.Context, container string, arguments []string) (container.ContainerTopOKBody, error)
 	ContainerUnpause(ctx context.Context, container string) error
 	ContainerUpdate(ctx context.Context, container string, updateConfig container.UpdateConfig) (container.ContainerUpdateOKBody, error)
+	ContainerUpdateLabels(ctx context.Context, container string, update types.LabelsUpdate) (map[string]string, error)
 	ContainerWait(ctx context.Context, container string, condition container.WaitCondition) (<-chan container.WaitResponse, <-chan error)
+	ContainersBatch(ctx context.Context, req container.BatchRequest) (container.BatchResponse, error)
 	CopyFromContainer(ctx context.Context, container, srcPath string) (io.ReadCloser, types.ContainerPathStat, error)
 	CopyToContainer(ctx context.Context, container, path string, content io.Reader, options types.CopyToContainerOptions) error
 	ContainersPrune(ctx context.Context, pruneFilters filters.Args) (types.ContainersPruneReport, error)
@@ -89,11 +110,17 @@ type ImageAPIClient interface {
 	ImageBuild(ctx context.Context, context io.Reader, options types.ImageBuildOptions) (types.ImageBuildResponse, error)
 	BuildCachePrune(ctx context.Context, opts types.BuildCachePruneOptions) (*types.BuildCachePruneReport, error)
 	BuildCancel(ctx context.Context, id string) error
+	BuildRecordList(ctx context.Context) ([]types.BuildRecord, error)
+	BuildRecordInspect(ctx context.Context, id string) (types.BuildRecord, error)
+	BuildFrontendList(ctx context.Context) ([]types.BuildFrontend, error)
+	BuildFrontendRegister(ctx context.Context, frontend types.BuildFrontend) (types.BuildFrontend, error)
+	BuildFrontendRemove(ctx context.Context, name string) error
 	ImageCreate(ctx context.Context, parentReference string, options types.ImageCreateOptions) (io.ReadCloser, error)
 	ImageHistory(ctx context.Context, image string) ([]image.HistoryResponseItem, error)
 	ImageImport(ctx context.Context, source types.ImageImportSource, ref string, options types.ImageImportOptions) (io.ReadCloser, error)
 	ImageInspectWithRaw(ctx context.Context, image string) (types.ImageInspect, []byte, error)
 	ImageList(ctx context.Context, options types.ImageListOptions) ([]types.ImageSummary, error)
+	ImageListPage(ctx context.Context, options types.ImageListOptions) ([]types.ImageSummary, string, error)
 	ImageLoad(ctx context.Context, input io.Reader, quiet bool) (types.ImageLoadResponse, error)
 	ImagePull(ctx context.Context, ref string, options types.ImagePullOptions) (io.ReadCloser, error)
 	ImagePush(ctx context.Context, ref string, options types.ImagePushOptions) (io.ReadCloser, error)
@@ -101,6 +128,7 @@ type ImageAPIClient interface {
 	ImageSearch(ctx context.Context, term string, options types.ImageSearchOptions) ([]registry.SearchResult, error)
 	ImageSave(ctx context.Context, images []string) (io.ReadCloser, error)
 	ImageTag(ctx context.Context, image, ref string) error
+	ImageUpdateLabels(ctx context.Context, image string, update types.LabelsUpdate) (map[string]string, error)
 	ImagesPrune(ctx context.Context, pruneFilter filters.Args) (types.ImagesPruneReport, error)
 }
 
@@ -113,6 +141,9 @@ type NetworkAPIClient interface {
 	NetworkInspectWithRaw(ctx context.Context, network string, options types.NetworkInspectOptions) (types.NetworkResource, []byte, error)
 	NetworkList(ctx context.Context, options types.NetworkListOptions) ([]types.NetworkResource, error)
 	NetworkRemove(ctx context.Context, network string) error
+	NetworkUpdateLabels(ctx context.Context, network string, update types.LabelsUpdate) (map[string]string, error)
+	NetworksIPAMReconcile(ctx context.Context, release bool) (network.IPAMReconcileReport, error)
+	NetworkRebalance(ctx context.Context, network string, drain time.Duration) (network.RebalanceReport, error)
 	NetworksPrune(ctx context.Context, pruneFilter filters.Args) (types.NetworksPruneReport, error)
 }
 
@@ -145,12 +176,21 @@ type ServiceAPIClient interface {
 	ServiceList(ctx context.Context, options types.ServiceListOptions) ([]swarm.Service, error)
 	ServiceRemove(ctx context.Context, serviceID string) error
 	ServiceUpdate(ctx context.Context, serviceID string, version swarm.Version, service swarm.ServiceSpec, options types.ServiceUpdateOptions) (types.ServiceUpdateResponse, error)
+	ServiceRestart(ctx context.Context, serviceID string, version swarm.Version) error
 	ServiceLogs(ctx context.Context, serviceID string, options types.ContainerLogsOptions) (io.ReadCloser, error)
 	TaskLogs(ctx context.Context, taskID string, options types.ContainerLogsOptions) (io.ReadCloser, error)
 	TaskInspectWithRaw(ctx context.Context, taskID string) (swarm.Task, []byte, error)
 	TaskList(ctx context.Context, options types.TaskListOptions) ([]swarm.Task, error)
 }
 
+// StackAPIClient defines API client methods for the stacks
+type StackAPIClient interface {
+	StackDeploy(ctx context.Context, stack swarm.StackSpec, options types.StackDeployOptions) (swarm.StackDeployResponse, error)
+	StackList(ctx context.Context) ([]swarm.Stack, error)
+	StackInspect(ctx context.Context, name string) (swarm.Stack, error)
+	StackRemove(ctx context.Context, name string) error
+}
+
 // SwarmAPIClient defines API client methods for the swarm
 type SwarmAPIClient interface {
 	SwarmInit(ctx context.Context, req swarm.InitRequest) (string, error)
@@ -166,9 +206,13 @@ type SwarmAPIClient interface {
 type SystemAPIClient interface {
 	Events(ctx context.Context, options types.EventsOptions) (<-chan events.Message, <-chan error)
 	Info(ctx context.Context) (types.Info, error)
+	InfoWithOptions(ctx context.Context, options types.InfoOptions) (types.Info, error)
 	RegistryLogin(ctx context.Context, auth registry.AuthConfig) (registry.AuthenticateOKBody, error)
 	DiskUsage(ctx context.Context, options types.DiskUsageOptions) (types.DiskUsage, error)
 	Ping(ctx context.Context) (types.Ping, error)
+	Watch(ctx context.Context, typ events.Type, filter filters.Args) (<-chan types.WatchEvent, <-chan error)
+	ConfigReloadResult(ctx context.Context) (types.ConfigReload, error)
+	ConfigCheck(ctx context.Context, config []byte) (types.ConfigCheck, error)
 }
 
 // VolumeAPIClient defines API client methods for the volumes
@@ -179,7 +223,17 @@ type VolumeAPIClient interface {
 	VolumeList(ctx context.Context, options volume.ListOptions) (volume.ListResponse, error)
 	VolumeRemove(ctx context.Context, volumeID string, force bool) error
 	VolumesPrune(ctx context.Context, pruneFilter filters.Args) (types.VolumesPruneReport, error)
+	VolumesPruneAsync(ctx context.Context, pruneFilter filters.Args) (volume.PruneJob, error)
+	VolumePruneJob(ctx context.Context, jobID string) (volume.PruneJob, error)
+	VolumePruneJobCancel(ctx context.Context, jobID string) error
 	VolumeUpdate(ctx context.Context, volumeID string, version swarm.Version, options volume.UpdateOptions) error
+	VolumeUpdateLabels(ctx context.Context, volumeID string, update types.LabelsUpdate) (map[string]string, error)
+	VolumeSnapshotCreate(ctx context.Context, volumeID string, options volume.SnapshotCreateOptions) (volume.Snapshot, error)
+	VolumeSnapshotList(ctx context.Context, volumeID string) ([]volume.Snapshot, error)
+	VolumeSnapshotRemove(ctx context.Context, volumeID, snapshot string) error
+	VolumeSnapshotRestore(ctx context.Context, volumeID, snapshot string) error
+	VolumeExport(ctx context.Context, volumeID string, options volume.ExportOptions) (io.ReadCloser, error)
+	VolumeImport(ctx context.Context, volumeID string, content io.Reader) error
 }
 
 // SecretAPIClient defines API client methods for secrets
@@ -189,6 +243,7 @@ type SecretAPIClient interface {
 	SecretRemove(ctx context.Context, id string) error
 	SecretInspectWithRaw(ctx context.Context, name string) (swarm.Secret, []byte, error)
 	SecretUpdate(ctx context.Context, id string, version swarm.Version, secret swarm.SecretSpec) error
+	SecretRotate(ctx context.Context, id string, secret swarm.SecretSpec) (types.SecretCreateResponse, error)
 }
 
 // ConfigAPIClient defines API client methods for configs
@@ -198,4 +253,5 @@ type ConfigAPIClient interface {
 	ConfigRemove(ctx context.Context, id string) error
 	ConfigInspectWithRaw(ctx context.Context, name string) (swarm.Config, []byte, error)
 	ConfigUpdate(ctx context.Context, id string, version swarm.Version, config swarm.ConfigSpec) error
+	ConfigRotate(ctx context.Context, id string, config swarm.ConfigSpec) (types.ConfigCreateResponse, error)
 }
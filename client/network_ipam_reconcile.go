@@ -0,0 +1,31 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+
+	"github.com/docker/docker/api/types/network"
+)
+
+// NetworksIPAMReconcile requests the daemon to report the addresses and pools
+// allocated by its IPAM drivers which no network or endpoint owns. If release
+// is set, the leaks which were already reported by the previous reconciliation
+// are released.
+func (cli *Client) NetworksIPAMReconcile(ctx context.Context, release bool) (network.IPAMReconcileReport, error) {
+	var report network.IPAMReconcileReport
+	if err := cli.NewVersionError("1.43", "IPAM reconciliation"); err != nil {
+		return report, err
+	}
+	query := url.Values{}
+	if release {
+		query.Set("release", "1")
+	}
+	resp, err := cli.post(ctx, "/networks/ipam/reconcile", query, nil, nil)
+	defer ensureReaderClosed(resp)
+	if err != nil {
+		return report, err
+	}
+	err = json.NewDecoder(resp.body).Decode(&report)
+	return report, err
+}
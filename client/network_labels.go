@@ -0,0 +1,24 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/docker/docker/api/types"
+)
+
+// NetworkUpdateLabels adds and removes labels of a local network, and returns the
+// new labels.
+func (cli *Client) NetworkUpdateLabels(ctx context.Context, networkID string, update types.LabelsUpdate) (map[string]string, error) {
+	if err := cli.NewVersionError("1.43", "network label updates"); err != nil {
+		return nil, err
+	}
+	resp, err := cli.post(ctx, "/networks/"+networkID+"/labels", nil, update, nil)
+	defer ensureReaderClosed(resp)
+	if err != nil {
+		return nil, err
+	}
+	var result types.LabelsUpdateResponse
+	err = json.NewDecoder(resp.body).Decode(&result)
+	return result.Labels, err
+}
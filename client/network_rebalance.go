@@ -0,0 +1,33 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/docker/docker/api/types/network"
+)
+
+// NetworkRebalance re-synchronizes the load balancers of the services on the
+// network with the backends of the services on the node of the daemon. If
+// drain is set, the backends holding significantly more active connections
+// than the others receive no new connections for that long.
+func (cli *Client) NetworkRebalance(ctx context.Context, networkID string, drain time.Duration) (network.RebalanceReport, error) {
+	var report network.RebalanceReport
+	if err := cli.NewVersionError("1.43", "network rebalance"); err != nil {
+		return report, err
+	}
+	query := url.Values{}
+	if drain > 0 {
+		query.Set("drain", strconv.Itoa(int(drain.Seconds())))
+	}
+	resp, err := cli.post(ctx, "/networks/"+networkID+"/rebalance", query, nil, nil)
+	defer ensureReaderClosed(resp)
+	if err != nil {
+		return report, err
+	}
+	err = json.NewDecoder(resp.body).Decode(&report)
+	return report, err
+}
@@ -0,0 +1,58 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/errdefs"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestNetworkRebalanceError(t *testing.T) {
+	client := &Client{
+		client: newMockClient(errorMock(http.StatusInternalServerError, "Server error")),
+	}
+	_, err := client.NetworkRebalance(context.Background(), "network_id", 0)
+	assert.Check(t, is.ErrorType(err, errdefs.IsSystem))
+}
+
+func TestNetworkRebalance(t *testing.T) {
+	expectedURL := "/networks/network_id/rebalance"
+	client := &Client{
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			if !strings.HasPrefix(req.URL.Path, expectedURL) {
+				return nil, fmt.Errorf("Expected URL '%s', got '%s'", expectedURL, req.URL)
+			}
+			if req.Method != http.MethodPost {
+				return nil, fmt.Errorf("expected POST method, got %s", req.Method)
+			}
+			if drain := req.URL.Query().Get("drain"); drain != "30" {
+				return nil, fmt.Errorf("drain not set in URL query properly. Expected '30', got %s", drain)
+			}
+			b, err := json.Marshal(network.RebalanceReport{
+				LoadBalancers: []network.LoadBalancerRebalance{{ServiceID: "svc", VIP: "10.0.0.2", Drained: []string{"10.0.0.3"}}},
+			})
+			if err != nil {
+				return nil, err
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewReader(b)),
+			}, nil
+		}),
+	}
+
+	report, err := client.NetworkRebalance(context.Background(), "network_id", 30*time.Second)
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(report.LoadBalancers, 1))
+	assert.Check(t, is.DeepEqual(report.LoadBalancers[0].Drained, []string{"10.0.0.3"}))
+}
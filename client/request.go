@@ -228,18 +228,17 @@ func (cli *Client) checkResponseErr(serverResp serverResponse) error {
 		ct = serverResp.header.Get("Content-Type")
 	}
 
-	var errorMessage string
+	var errorResponse types.ErrorResponse
 	if (cli.version == "" || versions.GreaterThan(cli.version, "1.23")) && ct == "application/json" {
-		var errorResponse types.ErrorResponse
 		if err := json.Unmarshal(body, &errorResponse); err != nil {
 			return errors.Wrap(err, "Error reading JSON")
 		}
-		errorMessage = strings.TrimSpace(errorResponse.Message)
+		errorResponse.Message = strings.TrimSpace(errorResponse.Message)
 	} else {
-		errorMessage = strings.TrimSpace(string(body))
+		errorResponse.Message = strings.TrimSpace(string(body))
 	}
 
-	return errors.Wrap(errors.New(errorMessage), "Error response from daemon")
+	return errors.Wrap(&errorResponse, "Error response from daemon")
 }
 
 func (cli *Client) addHeaders(req *http.Request, headers headers) *http.Request {
@@ -93,6 +93,30 @@ func TestPlainTextError(t *testing.T) {
 	}
 }
 
+func TestErrorCode(t *testing.T) {
+	client := &Client{
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			header := http.Header{}
+			header.Set("Content-Type", "application/json")
+			body := `{"message":"network foo not found","code":"network_not_found","details":{"network":"foo"}}`
+			return &http.Response{
+				StatusCode: http.StatusNotFound,
+				Body:       io.NopCloser(strings.NewReader(body)),
+				Header:     header,
+			}, nil
+		}),
+	}
+	_, err := client.NetworkInspect(context.Background(), "foo", types.NetworkInspectOptions{})
+	assert.Check(t, is.Error(err, "Error response from daemon: network foo not found"))
+	assert.Check(t, errdefs.IsNotFound(err))
+	assert.Check(t, is.Equal(errdefs.Code(err), "network_not_found"))
+	assert.Check(t, is.DeepEqual(errdefs.Details(err), map[string]string{"network": "foo"}))
+
+	var errResp *types.ErrorResponse
+	assert.Assert(t, errors.As(err, &errResp))
+	assert.Check(t, is.Equal(errResp.Code, "network_not_found"))
+}
+
 func TestInfiniteError(t *testing.T) {
 	infinitR := rand.New(rand.NewSource(42))
 	client := &Client{
@@ -0,0 +1,26 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+)
+
+// SecretRotate creates a new version of a secret, and updates the services that
+// follow the latest version of the secret to use it.
+func (cli *Client) SecretRotate(ctx context.Context, id string, secret swarm.SecretSpec) (types.SecretCreateResponse, error) {
+	var response types.SecretCreateResponse
+	if err := cli.NewVersionError("1.43", "secret rotate"); err != nil {
+		return response, err
+	}
+	resp, err := cli.post(ctx, "/secrets/"+id+"/rotate", nil, secret, nil)
+	defer ensureReaderClosed(resp)
+	if err != nil {
+		return response, err
+	}
+
+	err = json.NewDecoder(resp.body).Decode(&response)
+	return response, err
+}
@@ -0,0 +1,65 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/errdefs"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestSecretRotateUnsupported(t *testing.T) {
+	client := &Client{
+		version: "1.42",
+		client:  &http.Client{},
+	}
+	_, err := client.SecretRotate(context.Background(), "secret_id", swarm.SecretSpec{})
+	assert.Check(t, is.Error(err, `"secret rotate" requires API version 1.43, but the Docker daemon API version is 1.42`))
+}
+
+func TestSecretRotateError(t *testing.T) {
+	client := &Client{
+		version: "1.43",
+		client:  newMockClient(errorMock(http.StatusInternalServerError, "Server error")),
+	}
+	_, err := client.SecretRotate(context.Background(), "secret_id", swarm.SecretSpec{})
+	assert.Check(t, is.ErrorType(err, errdefs.IsSystem))
+}
+
+func TestSecretRotate(t *testing.T) {
+	expectedURL := "/v1.43/secrets/secret_id/rotate"
+	client := &Client{
+		version: "1.43",
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			if !strings.HasPrefix(req.URL.Path, expectedURL) {
+				return nil, fmt.Errorf("Expected URL '%s', got '%s'", expectedURL, req.URL)
+			}
+			if req.Method != http.MethodPost {
+				return nil, fmt.Errorf("expected POST method, got %s", req.Method)
+			}
+			b, err := json.Marshal(types.SecretCreateResponse{
+				ID: "secret_v2",
+			})
+			if err != nil {
+				return nil, err
+			}
+			return &http.Response{
+				StatusCode: http.StatusCreated,
+				Body:       io.NopCloser(bytes.NewReader(b)),
+			}, nil
+		}),
+	}
+
+	r, err := client.SecretRotate(context.Background(), "secret_id", swarm.SecretSpec{Data: []byte("new data")})
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(r.ID, "secret_v2"))
+}
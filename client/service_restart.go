@@ -0,0 +1,22 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/docker/docker/api/types/swarm"
+)
+
+// ServiceRestart recreates the tasks of a service without changing its spec,
+// according to the update config of the service. The version number is
+// required to avoid conflicting writes.
+func (cli *Client) ServiceRestart(ctx context.Context, serviceID string, version swarm.Version) error {
+	if err := cli.NewVersionError("1.43", "service restart"); err != nil {
+		return err
+	}
+	query := url.Values{}
+	query.Set("version", version.String())
+	resp, err := cli.post(ctx, "/services/"+serviceID+"/restart", query, nil, nil)
+	ensureReaderClosed(resp)
+	return err
+}
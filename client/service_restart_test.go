@@ -0,0 +1,58 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/errdefs"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestServiceRestartUnsupported(t *testing.T) {
+	client := &Client{
+		version: "1.42",
+		client:  &http.Client{},
+	}
+	err := client.ServiceRestart(context.Background(), "service_id", swarm.Version{})
+	assert.Check(t, is.Error(err, `"service restart" requires API version 1.43, but the Docker daemon API version is 1.42`))
+}
+
+func TestServiceRestartError(t *testing.T) {
+	client := &Client{
+		version: "1.43",
+		client:  newMockClient(errorMock(http.StatusInternalServerError, "Server error")),
+	}
+	err := client.ServiceRestart(context.Background(), "service_id", swarm.Version{})
+	assert.Check(t, is.ErrorType(err, errdefs.IsSystem))
+}
+
+func TestServiceRestart(t *testing.T) {
+	expectedURL := "/v1.43/services/service_id/restart"
+	client := &Client{
+		version: "1.43",
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			if !strings.HasPrefix(req.URL.Path, expectedURL) {
+				return nil, fmt.Errorf("Expected URL '%s', got '%s'", expectedURL, req.URL)
+			}
+			if req.Method != http.MethodPost {
+				return nil, fmt.Errorf("expected POST method, got %s", req.Method)
+			}
+			if version := req.URL.Query().Get("version"); version != "10" {
+				return nil, fmt.Errorf("version not set in URL query properly, expected '10', got %s", version)
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewReader([]byte("body"))),
+			}, nil
+		}),
+	}
+	err := client.ServiceRestart(context.Background(), "service_id", swarm.Version{Index: 10})
+	assert.NilError(t, err)
+}
@@ -0,0 +1,38 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/registry"
+	"github.com/docker/docker/api/types/swarm"
+)
+
+// StackDeploy creates or updates the services, networks, secrets, and configs
+// of a stack to match its spec.
+func (cli *Client) StackDeploy(ctx context.Context, stack swarm.StackSpec, options types.StackDeployOptions) (swarm.StackDeployResponse, error) {
+	var response swarm.StackDeployResponse
+	if err := cli.NewVersionError("1.43", "stack deploy"); err != nil {
+		return response, err
+	}
+
+	query := url.Values{}
+	if options.Prune {
+		query.Set("prune", "1")
+	}
+	headers := map[string][]string{}
+	if options.EncodedRegistryAuth != "" {
+		headers[registry.AuthHeader] = []string{options.EncodedRegistryAuth}
+	}
+
+	resp, err := cli.post(ctx, "/stacks", query, stack, headers)
+	defer ensureReaderClosed(resp)
+	if err != nil {
+		return response, err
+	}
+
+	err = json.NewDecoder(resp.body).Decode(&response)
+	return response, err
+}
@@ -0,0 +1,80 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/registry"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/errdefs"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestStackDeployUnsupported(t *testing.T) {
+	client := &Client{
+		version: "1.42",
+		client:  &http.Client{},
+	}
+	_, err := client.StackDeploy(context.Background(), swarm.StackSpec{}, types.StackDeployOptions{})
+	assert.Check(t, is.Error(err, `"stack deploy" requires API version 1.43, but the Docker daemon API version is 1.42`))
+}
+
+func TestStackDeployError(t *testing.T) {
+	client := &Client{
+		version: "1.43",
+		client:  newMockClient(errorMock(http.StatusInternalServerError, "Server error")),
+	}
+	_, err := client.StackDeploy(context.Background(), swarm.StackSpec{}, types.StackDeployOptions{})
+	assert.Check(t, is.ErrorType(err, errdefs.IsSystem))
+}
+
+func TestStackDeploy(t *testing.T) {
+	expectedURL := "/v1.43/stacks"
+	client := &Client{
+		version: "1.43",
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			if !strings.HasPrefix(req.URL.Path, expectedURL) {
+				return nil, fmt.Errorf("Expected URL '%s', got '%s'", expectedURL, req.URL)
+			}
+			if req.Method != http.MethodPost {
+				return nil, fmt.Errorf("expected POST method, got %s", req.Method)
+			}
+			if prune := req.URL.Query().Get("prune"); prune != "1" {
+				return nil, fmt.Errorf("prune not set in URL query properly, expected '1', got %s", prune)
+			}
+			if auth := req.Header.Get(registry.AuthHeader); auth != "auth" {
+				return nil, fmt.Errorf("expected registry auth header 'auth', got %q", auth)
+			}
+			var spec swarm.StackSpec
+			if err := json.NewDecoder(req.Body).Decode(&spec); err != nil {
+				return nil, err
+			}
+			if spec.Name != "app" {
+				return nil, fmt.Errorf("expected stack name 'app', got %q", spec.Name)
+			}
+			b, err := json.Marshal(swarm.StackDeployResponse{Created: []string{"app_web"}})
+			if err != nil {
+				return nil, err
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewReader(b)),
+			}, nil
+		}),
+	}
+
+	resp, err := client.StackDeploy(context.Background(), swarm.StackSpec{Name: "app"}, types.StackDeployOptions{
+		EncodedRegistryAuth: "auth",
+		Prune:               true,
+	})
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(resp.Created, []string{"app_web"}))
+}
@@ -0,0 +1,27 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/docker/docker/api/types/swarm"
+)
+
+// StackInspect returns the status of a stack, and whether it has converged.
+func (cli *Client) StackInspect(ctx context.Context, name string) (swarm.Stack, error) {
+	if err := cli.NewVersionError("1.43", "stack inspect"); err != nil {
+		return swarm.Stack{}, err
+	}
+	if name == "" {
+		return swarm.Stack{}, objectNotFoundError{object: "stack", id: name}
+	}
+	resp, err := cli.get(ctx, "/stacks/"+name, nil, nil)
+	defer ensureReaderClosed(resp)
+	if err != nil {
+		return swarm.Stack{}, err
+	}
+
+	var stack swarm.Stack
+	err = json.NewDecoder(resp.body).Decode(&stack)
+	return stack, err
+}
@@ -0,0 +1,72 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestStackInspectUnsupported(t *testing.T) {
+	client := &Client{
+		version: "1.42",
+		client:  &http.Client{},
+	}
+	_, err := client.StackInspect(context.Background(), "app")
+	assert.Check(t, is.Error(err, `"stack inspect" requires API version 1.43, but the Docker daemon API version is 1.42`))
+}
+
+func TestStackInspectError(t *testing.T) {
+	client := &Client{
+		version: "1.43",
+		client:  newMockClient(errorMock(http.StatusInternalServerError, "Server error")),
+	}
+	_, err := client.StackInspect(context.Background(), "app")
+	assert.Check(t, is.ErrorType(err, errdefs.IsSystem))
+}
+
+func TestStackInspectWithEmptyName(t *testing.T) {
+	client := &Client{
+		version: "1.43",
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			return nil, errors.New("should not make request")
+		}),
+	}
+	_, err := client.StackInspect(context.Background(), "")
+	assert.Check(t, IsErrNotFound(err))
+}
+
+func TestStackInspect(t *testing.T) {
+	expectedURL := "/v1.43/stacks/app"
+	client := &Client{
+		version: "1.43",
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			if !strings.HasPrefix(req.URL.Path, expectedURL) {
+				return nil, fmt.Errorf("Expected URL '%s', got '%s'", expectedURL, req.URL)
+			}
+			b, err := json.Marshal(swarm.Stack{Name: "app", Converged: true})
+			if err != nil {
+				return nil, err
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewReader(b)),
+			}, nil
+		}),
+	}
+
+	stack, err := client.StackInspect(context.Background(), "app")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(stack.Name, "app"))
+	assert.Check(t, stack.Converged)
+}
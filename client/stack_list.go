@@ -0,0 +1,24 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/docker/docker/api/types/swarm"
+)
+
+// StackList returns the stacks deployed in the cluster.
+func (cli *Client) StackList(ctx context.Context) ([]swarm.Stack, error) {
+	if err := cli.NewVersionError("1.43", "stack list"); err != nil {
+		return nil, err
+	}
+	resp, err := cli.get(ctx, "/stacks", nil, nil)
+	defer ensureReaderClosed(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	var stacks []swarm.Stack
+	err = json.NewDecoder(resp.body).Decode(&stacks)
+	return stacks, err
+}
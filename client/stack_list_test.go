@@ -0,0 +1,59 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/errdefs"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestStackListUnsupported(t *testing.T) {
+	client := &Client{
+		version: "1.42",
+		client:  &http.Client{},
+	}
+	_, err := client.StackList(context.Background())
+	assert.Check(t, is.Error(err, `"stack list" requires API version 1.43, but the Docker daemon API version is 1.42`))
+}
+
+func TestStackListError(t *testing.T) {
+	client := &Client{
+		version: "1.43",
+		client:  newMockClient(errorMock(http.StatusInternalServerError, "Server error")),
+	}
+	_, err := client.StackList(context.Background())
+	assert.Check(t, is.ErrorType(err, errdefs.IsSystem))
+}
+
+func TestStackList(t *testing.T) {
+	expectedURL := "/v1.43/stacks"
+	client := &Client{
+		version: "1.43",
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			if !strings.HasPrefix(req.URL.Path, expectedURL) {
+				return nil, fmt.Errorf("Expected URL '%s', got '%s'", expectedURL, req.URL)
+			}
+			b, err := json.Marshal([]swarm.Stack{{Name: "app"}, {Name: "monitoring"}})
+			if err != nil {
+				return nil, err
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewReader(b)),
+			}, nil
+		}),
+	}
+
+	stacks, err := client.StackList(context.Background())
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(stacks, 2))
+}
@@ -0,0 +1,13 @@
+package client // import "github.com/docker/docker/client"
+
+import "context"
+
+// StackRemove removes the services, networks, secrets, and configs of a stack.
+func (cli *Client) StackRemove(ctx context.Context, name string) error {
+	if err := cli.NewVersionError("1.43", "stack remove"); err != nil {
+		return err
+	}
+	resp, err := cli.delete(ctx, "/stacks/"+name, nil, nil)
+	defer ensureReaderClosed(resp)
+	return err
+}
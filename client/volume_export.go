@@ -0,0 +1,29 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"io"
+	"net/url"
+
+	"github.com/docker/docker/api/types/volume"
+)
+
+// VolumeExport retrieves the contents of a volume as a tar archive, and
+// returns it as an io.ReadCloser. It's up to the caller to close the stream.
+func (cli *Client) VolumeExport(ctx context.Context, volumeID string, options volume.ExportOptions) (io.ReadCloser, error) {
+	if err := cli.NewVersionError("1.43", "volume export"); err != nil {
+		return nil, err
+	}
+	query := url.Values{}
+	if options.Compression != "" {
+		query.Set("compression", options.Compression)
+	}
+	if options.Quiesce {
+		query.Set("quiesce", "1")
+	}
+	resp, err := cli.get(ctx, "/volumes/"+volumeID+"/export", query, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.body, nil
+}
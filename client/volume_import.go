@@ -0,0 +1,17 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"io"
+)
+
+// VolumeImport extracts a tar archive into a volume. The archive may be
+// compressed. The volume must not be in use.
+func (cli *Client) VolumeImport(ctx context.Context, volumeID string, content io.Reader) error {
+	if err := cli.NewVersionError("1.43", "volume import"); err != nil {
+		return err
+	}
+	resp, err := cli.putRaw(ctx, "/volumes/"+volumeID+"/import", nil, content, nil)
+	defer ensureReaderClosed(resp)
+	return err
+}
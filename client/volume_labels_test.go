@@ -0,0 +1,56 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/errdefs"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestVolumeUpdateLabelsError(t *testing.T) {
+	client := &Client{
+		client: newMockClient(errorMock(http.StatusInternalServerError, "Server error")),
+	}
+
+	_, err := client.VolumeUpdateLabels(context.Background(), "volume_id", types.LabelsUpdate{})
+	assert.Check(t, is.ErrorType(err, errdefs.IsSystem))
+}
+
+func TestVolumeUpdateLabels(t *testing.T) {
+	expectedURL := "/volumes/volume_id/labels"
+
+	client := &Client{
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Path != expectedURL {
+				return nil, fmt.Errorf("Expected URL '%s', got '%s'", expectedURL, req.URL)
+			}
+			if req.Method != http.MethodPost {
+				return nil, fmt.Errorf("expected POST method, got %s", req.Method)
+			}
+			var update types.LabelsUpdate
+			if err := json.NewDecoder(req.Body).Decode(&update); err != nil {
+				return nil, err
+			}
+			content, err := json.Marshal(types.LabelsUpdateResponse{Labels: update.Apply(map[string]string{"a": "1"})})
+			if err != nil {
+				return nil, err
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewReader(content)),
+			}, nil
+		}),
+	}
+
+	labels, err := client.VolumeUpdateLabels(context.Background(), "volume_id", types.LabelsUpdate{Add: map[string]string{"b": "2"}, Remove: []string{"a"}})
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(labels, map[string]string{"b": "2"}))
+}
@@ -0,0 +1,34 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/volume"
+)
+
+// VolumesPruneAsync requests the daemon to delete unused volumes in the
+// background. The progress of the returned job can be retrieved with
+// VolumePruneJob.
+func (cli *Client) VolumesPruneAsync(ctx context.Context, pruneFilters filters.Args) (volume.PruneJob, error) {
+	var job volume.PruneJob
+
+	if err := cli.NewVersionError("1.43", "asynchronous volume prune"); err != nil {
+		return job, err
+	}
+
+	query, err := getFiltersQuery(pruneFilters)
+	if err != nil {
+		return job, err
+	}
+	query.Set("async", "1")
+
+	resp, err := cli.post(ctx, "/volumes/prune", query, nil, nil)
+	defer ensureReaderClosed(resp)
+	if err != nil {
+		return job, err
+	}
+	err = json.NewDecoder(resp.body).Decode(&job)
+	return job, err
+}
@@ -0,0 +1,83 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/errdefs"
+)
+
+func TestVolumesPruneAsyncError(t *testing.T) {
+	client := &Client{
+		client: newMockClient(errorMock(http.StatusInternalServerError, "Server error")),
+	}
+
+	_, err := client.VolumesPruneAsync(context.Background(), filters.NewArgs())
+	if !errdefs.IsSystem(err) {
+		t.Fatalf("expected a Server Error, got %[1]T: %[1]v", err)
+	}
+}
+
+func TestVolumesPruneAsync(t *testing.T) {
+	expectedURL := "/volumes/prune"
+
+	client := &Client{
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Path != expectedURL {
+				return nil, fmt.Errorf("Expected URL '%s', got '%s'", expectedURL, req.URL)
+			}
+			if req.Method != http.MethodPost {
+				return nil, fmt.Errorf("expected POST method, got %s", req.Method)
+			}
+			if async := req.URL.Query().Get("async"); async != "1" {
+				return nil, fmt.Errorf("expected async '1', got '%s'", async)
+			}
+			content, err := json.Marshal(volume.PruneJob{ID: "job_id", Status: volume.PruneJobRunning})
+			if err != nil {
+				return nil, err
+			}
+			return &http.Response{
+				StatusCode: http.StatusAccepted,
+				Body:       io.NopCloser(bytes.NewReader(content)),
+			}, nil
+		}),
+	}
+
+	job, err := client.VolumesPruneAsync(context.Background(), filters.NewArgs(filters.Arg("until", "24h")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.ID != "job_id" {
+		t.Fatalf("expected job ID 'job_id', got '%s'", job.ID)
+	}
+}
+
+func TestVolumePruneJobCancel(t *testing.T) {
+	expectedURL := "/volumes/prune/job_id"
+
+	client := &Client{
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Path != expectedURL {
+				return nil, fmt.Errorf("Expected URL '%s', got '%s'", expectedURL, req.URL)
+			}
+			if req.Method != http.MethodDelete {
+				return nil, fmt.Errorf("expected DELETE method, got %s", req.Method)
+			}
+			return &http.Response{
+				StatusCode: http.StatusNoContent,
+				Body:       io.NopCloser(bytes.NewReader(nil)),
+			}, nil
+		}),
+	}
+
+	if err := client.VolumePruneJobCancel(context.Background(), "job_id"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,25 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/docker/docker/api/types/volume"
+)
+
+// VolumePruneJob returns the progress, or the result, of an asynchronous
+// volume prune.
+func (cli *Client) VolumePruneJob(ctx context.Context, jobID string) (volume.PruneJob, error) {
+	var job volume.PruneJob
+
+	if err := cli.NewVersionError("1.43", "asynchronous volume prune"); err != nil {
+		return job, err
+	}
+	resp, err := cli.get(ctx, "/volumes/prune/"+jobID, nil, nil)
+	defer ensureReaderClosed(resp)
+	if err != nil {
+		return job, err
+	}
+	err = json.NewDecoder(resp.body).Decode(&job)
+	return job, err
+}
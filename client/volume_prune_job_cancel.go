@@ -0,0 +1,14 @@
+package client // import "github.com/docker/docker/client"
+
+import "context"
+
+// VolumePruneJobCancel stops an asynchronous volume prune. Volumes that were
+// already removed are not restored.
+func (cli *Client) VolumePruneJobCancel(ctx context.Context, jobID string) error {
+	if err := cli.NewVersionError("1.43", "asynchronous volume prune"); err != nil {
+		return err
+	}
+	resp, err := cli.delete(ctx, "/volumes/prune/"+jobID, nil, nil)
+	defer ensureReaderClosed(resp)
+	return err
+}
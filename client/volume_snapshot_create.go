@@ -0,0 +1,23 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/docker/docker/api/types/volume"
+)
+
+// VolumeSnapshotCreate takes a snapshot of a volume.
+func (cli *Client) VolumeSnapshotCreate(ctx context.Context, volumeID string, options volume.SnapshotCreateOptions) (volume.Snapshot, error) {
+	var snap volume.Snapshot
+	if err := cli.NewVersionError("1.43", "volume snapshots"); err != nil {
+		return snap, err
+	}
+	resp, err := cli.post(ctx, "/volumes/"+volumeID+"/snapshot", nil, options, nil)
+	defer ensureReaderClosed(resp)
+	if err != nil {
+		return snap, err
+	}
+	err = json.NewDecoder(resp.body).Decode(&snap)
+	return snap, err
+}
@@ -0,0 +1,67 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/errdefs"
+)
+
+func TestVolumeSnapshotCreateError(t *testing.T) {
+	client := &Client{
+		client: newMockClient(errorMock(http.StatusInternalServerError, "Server error")),
+	}
+
+	_, err := client.VolumeSnapshotCreate(context.Background(), "volume_id", volume.SnapshotCreateOptions{})
+	if !errdefs.IsSystem(err) {
+		t.Fatalf("expected a Server Error, got %[1]T: %[1]v", err)
+	}
+}
+
+func TestVolumeSnapshotCreate(t *testing.T) {
+	expectedURL := "/volumes/volume_id/snapshot"
+
+	client := &Client{
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Path != expectedURL {
+				return nil, fmt.Errorf("Expected URL '%s', got '%s'", expectedURL, req.URL)
+			}
+			if req.Method != http.MethodPost {
+				return nil, fmt.Errorf("expected POST method, got %s", req.Method)
+			}
+			var opts volume.SnapshotCreateOptions
+			if err := json.NewDecoder(req.Body).Decode(&opts); err != nil {
+				return nil, err
+			}
+			content, err := json.Marshal(volume.Snapshot{
+				Name:   opts.Name,
+				Volume: "volume_id",
+				Driver: "local",
+			})
+			if err != nil {
+				return nil, err
+			}
+			return &http.Response{
+				StatusCode: http.StatusCreated,
+				Body:       io.NopCloser(bytes.NewReader(content)),
+			}, nil
+		}),
+	}
+
+	snap, err := client.VolumeSnapshotCreate(context.Background(), "volume_id", volume.SnapshotCreateOptions{Name: "snap1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snap.Name != "snap1" {
+		t.Fatalf("expected snapshot name to be 'snap1', got %s", snap.Name)
+	}
+	if snap.Volume != "volume_id" {
+		t.Fatalf("expected volume to be 'volume_id', got %s", snap.Volume)
+	}
+}
@@ -0,0 +1,13 @@
+package client // import "github.com/docker/docker/client"
+
+import "context"
+
+// VolumeSnapshotRemove removes a snapshot of a volume.
+func (cli *Client) VolumeSnapshotRemove(ctx context.Context, volumeID, snapshot string) error {
+	if err := cli.NewVersionError("1.43", "volume snapshots"); err != nil {
+		return err
+	}
+	resp, err := cli.delete(ctx, "/volumes/"+volumeID+"/snapshots/"+snapshot, nil, nil)
+	defer ensureReaderClosed(resp)
+	return err
+}
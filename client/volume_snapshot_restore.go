@@ -0,0 +1,14 @@
+package client // import "github.com/docker/docker/client"
+
+import "context"
+
+// VolumeSnapshotRestore reverts a volume to one of its snapshots. The volume
+// must not be in use.
+func (cli *Client) VolumeSnapshotRestore(ctx context.Context, volumeID, snapshot string) error {
+	if err := cli.NewVersionError("1.43", "volume snapshots"); err != nil {
+		return err
+	}
+	resp, err := cli.post(ctx, "/volumes/"+volumeID+"/snapshots/"+snapshot+"/restore", nil, nil, nil)
+	defer ensureReaderClosed(resp)
+	return err
+}
@@ -0,0 +1,83 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/pkg/errors"
+)
+
+// Watch returns a stream of the state changes of the containers, images,
+// volumes or networks matching the filters, depending on typ. The objects
+// matching the filters are sent first, with the "sync" action. It's up to the
+// caller to close the stream by cancelling the context. Once the stream has
+// been completely read an io.EOF error will be sent over the error channel.
+func (cli *Client) Watch(ctx context.Context, typ events.Type, filter filters.Args) (<-chan types.WatchEvent, <-chan error) {
+	watchEvents := make(chan types.WatchEvent)
+	errs := make(chan error, 1)
+
+	switch typ {
+	case events.ContainerEventType, events.ImageEventType, events.VolumeEventType, events.NetworkEventType:
+	default:
+		errs <- errors.Errorf("cannot watch objects of type %q", typ)
+		close(errs)
+		return watchEvents, errs
+	}
+	if err := cli.NewVersionError("1.43", "watch"); err != nil {
+		errs <- err
+		close(errs)
+		return watchEvents, errs
+	}
+
+	started := make(chan struct{})
+	go func() {
+		defer close(errs)
+
+		query := url.Values{"type": []string{string(typ)}}
+		if filter.Len() > 0 {
+			filterJSON, err := filters.ToJSON(filter)
+			if err != nil {
+				close(started)
+				errs <- err
+				return
+			}
+			query.Set("filters", filterJSON)
+		}
+
+		resp, err := cli.get(ctx, "/events/watch", query, nil)
+		if err != nil {
+			close(started)
+			errs <- err
+			return
+		}
+		defer resp.body.Close()
+
+		decoder := json.NewDecoder(resp.body)
+
+		close(started)
+		for {
+			var ev types.WatchEvent
+			if err := decoder.Decode(&ev); err != nil {
+				if ctx.Err() != nil {
+					err = ctx.Err()
+				}
+				errs <- err
+				return
+			}
+
+			select {
+			case watchEvents <- ev:
+			case <-ctx.Done():
+				errs <- ctx.Err()
+				return
+			}
+		}
+	}()
+	<-started
+
+	return watchEvents, errs
+}
@@ -0,0 +1,59 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestWatch(t *testing.T) {
+	const expectedURL = "/events/watch"
+	client := &Client{
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			if !strings.HasPrefix(req.URL.Path, expectedURL) {
+				return nil, fmt.Errorf("Expected URL '%s', got '%s'", expectedURL, req.URL)
+			}
+			if typ := req.URL.Query().Get("type"); typ != "container" {
+				return nil, fmt.Errorf("unexpected type %s", typ)
+			}
+			if f := req.URL.Query().Get("filters"); f != `{"label":{"app":true}}` {
+				return nil, fmt.Errorf("unexpected filters %s", f)
+			}
+			body := `{"Type":"container","Action":"sync","ID":"c1","Container":{"Id":"c1","State":"running"}}
+{"Type":"container","Action":"destroy","ID":"c1"}
+`
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(body)),
+			}, nil
+		}),
+	}
+
+	watchEvents, errs := client.Watch(context.Background(), events.ContainerEventType, filters.NewArgs(filters.Arg("label", "app")))
+
+	ev := <-watchEvents
+	assert.Check(t, is.Equal(ev.Action, "sync"))
+	assert.Assert(t, ev.Container != nil)
+	assert.Check(t, is.Equal(ev.Container.State, "running"))
+
+	ev = <-watchEvents
+	assert.Check(t, is.Equal(ev.Action, "destroy"))
+	assert.Check(t, is.Equal(ev.ID, "c1"))
+	assert.Check(t, ev.Container == nil)
+
+	assert.Check(t, is.Equal(<-errs, io.EOF))
+}
+
+func TestWatchInvalidType(t *testing.T) {
+	client := &Client{}
+	_, errs := client.Watch(context.Background(), events.PluginEventType, filters.NewArgs())
+	assert.Check(t, is.ErrorContains(<-errs, `cannot watch objects of type "plugin"`))
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	imagetypes "github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/daemon"
+	"github.com/docker/docker/daemon/cluster"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/authorization"
+)
+
+// authzObjectResolver returns the resolver of the objects API requests
+// operate on, whose labels are checked against the scopes returned by
+// authorization plugins.
+func authzObjectResolver(d *daemon.Daemon, c *cluster.Cluster) authorization.ObjectResolver {
+	return func(ctx context.Context, method, endpoint string, target map[string]string) (*authorization.TargetObject, error) {
+		obj, err := resolveAuthzObject(ctx, d, c, method, endpoint, target)
+		if errdefs.IsNotFound(err) || errdefs.IsUnavailable(err) {
+			// The handler reports the error.
+			return nil, nil
+		}
+		return obj, err
+	}
+}
+
+func resolveAuthzObject(ctx context.Context, d *daemon.Daemon, c *cluster.Cluster, method, endpoint string, target map[string]string) (*authorization.TargetObject, error) {
+	kind := strings.SplitN(strings.TrimPrefix(endpoint, "/"), "/", 2)[0]
+	switch kind {
+	case "containers":
+		return containerObject(d, target["name"])
+	case "exec":
+		id := target["id"]
+		if id == "" {
+			id = target["name"]
+		}
+		ec, err := d.ContainerExecInspect(id)
+		if err != nil {
+			return nil, err
+		}
+		return containerObject(d, ec.ContainerID)
+	case "images":
+		img, err := d.ImageService().GetImage(ctx, target["name"], imagetypes.GetImageOpts{})
+		if err != nil {
+			return nil, err
+		}
+		obj := &authorization.TargetObject{ID: img.ID().String()}
+		if img.Config != nil {
+			obj.Labels = img.Config.Labels
+		}
+		return obj, nil
+	case "volumes":
+		v, err := d.VolumesService().Get(ctx, target["name"])
+		if err != nil {
+			return nil, err
+		}
+		return &authorization.TargetObject{ID: v.Name, Labels: v.Labels}, nil
+	case "networks":
+		if d.NetworkControllerEnabled() {
+			n, err := d.FindNetwork(target["id"])
+			if err == nil {
+				return &authorization.TargetObject{ID: n.ID(), Labels: n.Info().Labels()}, nil
+			}
+			if !errdefs.IsNotFound(err) {
+				return nil, err
+			}
+		}
+		// Swarm networks may have no local instance.
+		n, err := c.GetNetwork(target["id"])
+		if err != nil {
+			return nil, err
+		}
+		return &authorization.TargetObject{ID: n.ID, Labels: n.Labels}, nil
+	case "services":
+		s, err := c.GetService(target["id"], false)
+		if err != nil {
+			return nil, err
+		}
+		return &authorization.TargetObject{ID: s.ID, Labels: s.Spec.Labels}, nil
+	case "tasks":
+		t, err := c.GetTask(target["id"])
+		if err != nil {
+			return nil, err
+		}
+		return &authorization.TargetObject{ID: t.ID, Labels: t.Labels}, nil
+	case "nodes":
+		n, err := c.GetNode(target["id"])
+		if err != nil {
+			return nil, err
+		}
+		return &authorization.TargetObject{ID: n.ID, Labels: n.Spec.Labels}, nil
+	case "secrets":
+		s, err := c.GetSecret(target["id"])
+		if err != nil {
+			return nil, err
+		}
+		return &authorization.TargetObject{ID: s.ID, Labels: s.Spec.Labels}, nil
+	case "groups":
+		return groupObject(d, method, endpoint, target["name"])
+	case "configs":
+		cfg, err := c.GetConfig(target["id"])
+		if err != nil {
+			return nil, err
+		}
+		return &authorization.TargetObject{ID: cfg.ID, Labels: cfg.Spec.Labels}, nil
+	}
+	return nil, nil
+}
+
+func containerObject(d *daemon.Daemon, name string) (*authorization.TargetObject, error) {
+	ctr, err := d.GetContainer(name)
+	if err != nil {
+		return nil, err
+	}
+	return &authorization.TargetObject{ID: ctr.ID, Labels: ctr.Config.Labels}, nil
+}
+
+// groupMemberEndpoints maps the group endpoints acting on the members of a
+// group to the container endpoints they act on the members with.
+var groupMemberEndpoints = map[string]struct{ method, endpoint, uri string }{
+	http.MethodPost + " /groups/{name:.*}/start": {http.MethodPost, "/containers/{name:.*}/start", "/containers/%s/start"},
+	http.MethodPost + " /groups/{name:.*}/stop":  {http.MethodPost, "/containers/{name:.*}/stop", "/containers/%s/stop"},
+	http.MethodGet + " /groups/{name:.*}/stats":  {http.MethodGet, "/containers/{name:.*}/stats", "/containers/%s/stats?stream=0"},
+	http.MethodDelete + " /groups/{name:.*}":     {http.MethodDelete, "/containers/{name:.*}", "/containers/%s?force=1"},
+}
+
+// groupObject returns a container group, with the requests on its members
+// implied by requests acting on them, so that they are authorized like the
+// requests on the members.
+func groupObject(d *daemon.Daemon, method, endpoint, name string) (*authorization.TargetObject, error) {
+	g, err := d.ContainerGroupInspect(name)
+	if err != nil {
+		return nil, err
+	}
+	obj := &authorization.TargetObject{ID: g.ID, Labels: g.Config.Labels}
+	if me, ok := groupMemberEndpoints[method+" "+endpoint]; ok {
+		for _, m := range g.Members {
+			obj.Implied = append(obj.Implied, authorization.ImpliedRequest{
+				Method:   me.method,
+				URI:      fmt.Sprintf(me.uri, m.ID),
+				Endpoint: me.endpoint,
+				Target:   map[string]string{"name": m.ID},
+			})
+		}
+	}
+	return obj, nil
+}
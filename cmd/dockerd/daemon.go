@@ -16,6 +16,7 @@ import (
 	"github.com/docker/docker/api"
 	apiserver "github.com/docker/docker/api/server"
 	buildbackend "github.com/docker/docker/api/server/backend/build"
+	"github.com/docker/docker/api/server/backend/engine"
 	"github.com/docker/docker/api/server/middleware"
 	"github.com/docker/docker/api/server/router"
 	"github.com/docker/docker/api/server/router/build"
@@ -35,6 +36,7 @@ import (
 	"github.com/docker/docker/cli/debug"
 	"github.com/docker/docker/cmd/dockerd/trap"
 	"github.com/docker/docker/daemon"
+	"github.com/docker/docker/daemon/auditlog"
 	"github.com/docker/docker/daemon/cluster"
 	"github.com/docker/docker/daemon/config"
 	"github.com/docker/docker/daemon/listeners"
@@ -67,7 +69,12 @@ type DaemonCli struct {
 
 	api             *apiserver.Server
 	d               *daemon.Daemon
-	authzMiddleware *authorization.Middleware // authzMiddleware enables to dynamically reload the authorization plugins
+	authzMiddleware *authorization.Middleware               // authzMiddleware enables to dynamically reload the authorization plugins
+	socketAccess    *middleware.SocketAccessMiddleware      // socketAccess enables to dynamically reload the socket access policy
+	auditLog        auditlog.Sink                           // auditLog is the sink API requests are recorded to, if audit logging is enabled
+	templates       *middleware.ContainerTemplateMiddleware // templates applies container templates to create requests before they are authorized
+	tlsReloader     *tlsReloader                            // tlsReloader enables to reload the TLS certificates of the API server, if TLS is enabled
+	metrics         metricsServer                           // metrics serves the metrics API, and can be moved on reload
 }
 
 // NewDaemonCli returns a daemon CLI
@@ -80,7 +87,7 @@ func (cli *DaemonCli) start(opts *daemonOptions) (err error) {
 		return err
 	}
 
-	serverConfig, err := newAPIServerConfig(cli.Config)
+	serverConfig, tlsReloader, err := newAPIServerConfig(cli.Config)
 	if err != nil {
 		return err
 	}
@@ -91,6 +98,11 @@ func (cli *DaemonCli) start(opts *daemonOptions) (err error) {
 		return nil
 	}
 
+	if tlsReloader != nil {
+		cli.tlsReloader = tlsReloader
+		go tlsReloader.watch(tlsReloadInterval, nil)
+	}
+
 	configureProxyEnv(cli.Config)
 	configureDaemonLogs(cli.Config)
 
@@ -195,6 +207,9 @@ func (cli *DaemonCli) start(opts *daemonOptions) (err error) {
 	if err := cli.initMiddlewares(cli.api, serverConfig, pluginStore); err != nil {
 		logrus.Fatalf("Error creating middlewares: %v", err)
 	}
+	if cli.auditLog != nil {
+		defer cli.auditLog.Close()
+	}
 
 	d, err := daemon.NewDaemon(ctx, cli.Config, pluginStore)
 	if err != nil {
@@ -202,6 +217,7 @@ func (cli *DaemonCli) start(opts *daemonOptions) (err error) {
 	}
 
 	d.StoreHosts(hosts)
+	d.SetConfigFlags(cli.flags)
 
 	// validate after NewDaemon has restored enabled plugins. Don't change order.
 	if err := validateAuthzPlugins(cli.Config.AuthorizationPlugins, pluginStore); err != nil {
@@ -210,7 +226,7 @@ func (cli *DaemonCli) start(opts *daemonOptions) (err error) {
 
 	cli.d = d
 
-	if err := startMetricsServer(cli.Config.MetricsAddress); err != nil {
+	if err := cli.metrics.listen(cli.Config.MetricsAddress); err != nil {
 		return errors.Wrap(err, "failed to start metrics server")
 	}
 
@@ -218,6 +234,8 @@ func (cli *DaemonCli) start(opts *daemonOptions) (err error) {
 	if err != nil {
 		logrus.Fatalf("Error starting cluster component: %v", err)
 	}
+	cli.authzMiddleware.SetObjectResolver(authzObjectResolver(d, c))
+	cli.templates.SetTemplates(d)
 
 	// Restart all autostart containers which has a swarm endpoint
 	// and is not yet running now that we have successfully
@@ -226,15 +244,24 @@ func (cli *DaemonCli) start(opts *daemonOptions) (err error) {
 
 	logrus.Info("Daemon has completed initialization")
 
-	routerOptions, err := newRouterOptions(cli.Config, d)
+	routerOptions, err := newRouterOptions(cli.Config, d, c)
 	if err != nil {
 		return err
 	}
 	routerOptions.api = cli.api
-	routerOptions.cluster = c
+	routerOptions.restricted = func() bool {
+		return cli.authzMiddleware.HasPlugins() || cli.socketAccess.Enabled() || cli.auditLog != nil
+	}
 
 	initRouter(routerOptions)
 
+	// Don't pass a nil builder as a non-nil interface.
+	if routerOptions.buildkit != nil {
+		d.StartPruneSchedules(routerOptions.buildkit)
+	} else {
+		d.StartPruneSchedules(nil)
+	}
+
 	go d.ProcessClusterNotifications(ctx, c.GetWatchStream())
 
 	cli.setupConfigReloadTrap()
@@ -248,6 +275,10 @@ func (cli *DaemonCli) start(opts *daemonOptions) (err error) {
 	// after the daemon is done setting up we can notify systemd api
 	notifyReady()
 
+	if cli.Config.IdleExitTimeout > 0 {
+		go cli.exitWhenIdle(ctx, d, c, time.Duration(cli.Config.IdleExitTimeout)*time.Second)
+	}
+
 	// Daemon is fully initialized and handling API traffic
 	// Wait for serve API to complete
 	errAPI := <-serveAPIWait
@@ -276,9 +307,12 @@ type routerOptions struct {
 	daemon         *daemon.Daemon
 	api            *apiserver.Server
 	cluster        *cluster.Cluster
+	// restricted reports whether the API is protected by policies which
+	// are only enforced on HTTP requests.
+	restricted func() bool
 }
 
-func newRouterOptions(config *config.Config, d *daemon.Daemon) (routerOptions, error) {
+func newRouterOptions(config *config.Config, d *daemon.Daemon, c *cluster.Cluster) (routerOptions, error) {
 	opts := routerOptions{}
 	sm, err := session.NewManager()
 	if err != nil {
@@ -294,6 +328,7 @@ func newRouterOptions(config *config.Config, d *daemon.Daemon) (routerOptions, e
 		sessionManager: sm,
 		features:       d.Features(),
 		daemon:         d,
+		cluster:        c,
 	}
 	if !d.UsesSnapshotter() {
 		bk, err := buildkit.New(buildkit.Opt{
@@ -308,6 +343,7 @@ func newRouterOptions(config *config.Config, d *daemon.Daemon) (routerOptions, e
 			IdentityMapping:     d.IdentityMapping(),
 			DNSConfig:           config.DNSConfig,
 			ApparmorProfile:     daemon.DefaultApparmorProfile(),
+			Secrets:             c,
 		})
 		if err != nil {
 			return opts, err
@@ -333,12 +369,30 @@ func (cli *DaemonCli) reloadConfig() {
 			return
 		}
 		cli.authzMiddleware.SetPlugins(c.AuthorizationPlugins)
+		cli.authzMiddleware.SetCacheTTL(time.Duration(c.AuthorizationCacheTTL) * time.Second)
+
+		if c.IsValueSet("socket-access") {
+			cli.socketAccess.SetPolicy(socketAccessPolicy(c.SocketAccess))
+		}
+
+		if cli.tlsReloader != nil {
+			if err := cli.tlsReloader.Reload(c); err != nil {
+				logrus.WithError(err).Error("Error reloading the API server TLS configuration, keeping the current one")
+			}
+		}
 
 		if err := cli.d.Reload(c); err != nil {
 			logrus.Errorf("Error reconfiguring the daemon: %v", err)
 			return
 		}
 
+		if c.IsValueSet("metrics-addr") {
+			if err := cli.metrics.listen(c.MetricsAddress); err != nil {
+				logrus.WithError(err).Error("Error moving the metrics API, keeping the current address")
+				cli.d.MetricsListenFailed(cli.metrics.address(), err)
+			}
+		}
+
 		if c.IsValueSet("debug") {
 			debugEnabled := debug.IsEnabled()
 			switch {
@@ -352,6 +406,7 @@ func (cli *DaemonCli) reloadConfig() {
 
 	if err := config.Reload(*cli.configFile, cli.flags, reload); err != nil {
 		logrus.Error(err)
+		cli.d.ReloadFailed(err)
 	}
 }
 
@@ -524,9 +579,35 @@ func initRouter(opts routerOptions) {
 		distributionrouter.NewRouter(opts.daemon.ImageService()),
 	}
 
+	engineConfig := engine.Config{
+		APIVersion:   api.DefaultVersion,
+		Experimental: opts.daemon.HasExperimental(),
+		Cgroup2:      opts.daemon.RawSysInfo().CgroupUnified,
+		Decoder:      decoder,
+		Containers:   opts.daemon,
+		Images:       opts.daemon.ImageService(),
+		Volumes:      opts.daemon.VolumesService(),
+		Events:       opts.daemon,
+		Restricted:   opts.restricted,
+	}
+	if opts.daemon.NetworkControllerEnabled() {
+		engineConfig.Networks = opts.daemon
+	}
+	var grpcBackends []grpcrouter.Backend
+	// Authorization plugins, the socket access policy and the audit log only
+	// see the single POST /grpc request carrying the calls, so the Engine
+	// service is not registered when any of them is configured. They can
+	// also be configured by reloading the configuration, in which case the
+	// calls to the service are rejected.
+	if opts.restricted() {
+		logrus.Warn("The Engine gRPC API is disabled because authorization plugins, a socket access policy or the audit log are configured")
+	} else {
+		grpcBackends = append(grpcBackends, engine.NewService(engineConfig))
+	}
 	if opts.buildBackend != nil {
-		routers = append(routers, grpcrouter.NewRouter(opts.buildBackend))
+		grpcBackends = append(grpcBackends, opts.buildBackend)
 	}
+	routers = append(routers, grpcrouter.NewRouter(grpcBackends...))
 
 	if opts.daemon.NetworkControllerEnabled() {
 		routers = append(routers, network.NewRouter(opts.daemon, opts.cluster))
@@ -549,6 +630,11 @@ func initRouter(opts routerOptions) {
 func (cli *DaemonCli) initMiddlewares(s *apiserver.Server, cfg *apiserver.Config, pluginStore plugingetter.PluginGetter) error {
 	v := cfg.Version
 
+	// The idempotency middleware is registered first, so that it runs after
+	// the version and authorization checks, and a remembered response is
+	// only replayed for requests that would be allowed to create it.
+	s.UseMiddleware(middleware.NewIdempotencyMiddleware(middleware.DefaultIdempotencyKeyTTL))
+
 	exp := middleware.NewExperimentalMiddleware(cli.Config.Experimental)
 	s.UseMiddleware(exp)
 
@@ -561,11 +647,49 @@ func (cli *DaemonCli) initMiddlewares(s *apiserver.Server, cfg *apiserver.Config
 	}
 
 	cli.authzMiddleware = authorization.NewMiddleware(cli.Config.AuthorizationPlugins, pluginStore)
+	cli.authzMiddleware.SetCacheTTL(time.Duration(cli.Config.AuthorizationCacheTTL) * time.Second)
 	cli.Config.AuthzMiddleware = cli.authzMiddleware
 	s.UseMiddleware(cli.authzMiddleware)
+
+	// Templates are applied to container create requests before the
+	// authorization plugins are consulted, so that they authorize the
+	// config the container is created with.
+	cli.templates = middleware.NewContainerTemplateMiddleware()
+	s.UseMiddleware(cli.templates)
+
+	// The socket access policy is checked before the authorization plugins
+	// are consulted. It is always registered, so that a policy can be added
+	// by reloading the configuration.
+	cli.socketAccess = middleware.NewSocketAccessMiddleware(socketAccessPolicy(cli.Config.SocketAccess))
+	s.UseMiddleware(cli.socketAccess)
+
+	// The audit middleware is registered last, so that it wraps all other
+	// middlewares, and records requests that were rejected by them.
+	if cli.Config.AuditLog.Enabled() {
+		sink, err := auditlog.New(cli.Config.AuditLog)
+		if err != nil {
+			return errors.Wrap(err, "failed to initialize audit log")
+		}
+		cli.auditLog = sink
+		s.UseMiddleware(middleware.NewAuditMiddleware(sink))
+	}
 	return nil
 }
 
+// socketAccessPolicy converts the socket access configuration of the daemon
+// to the policy enforced by the API server.
+func socketAccessPolicy(c config.SocketAccessConfig) middleware.SocketAccessPolicy {
+	policy := middleware.SocketAccessPolicy{DenyByDefault: c.Default == config.SocketAccessDeny}
+	for _, r := range c.Rules {
+		policy.Rules = append(policy.Rules, middleware.SocketAccessRule{
+			Users:  r.Users,
+			Groups: r.Groups,
+			Allow:  r.Allow,
+		})
+	}
+	return policy
+}
+
 func (cli *DaemonCli) getContainerdDaemonOpts() ([]supervisor.DaemonOpt, error) {
 	opts, err := cli.getPlatformContainerdDaemonOpts()
 	if err != nil {
@@ -597,8 +721,11 @@ func (cli *DaemonCli) getContainerdDaemonOpts() ([]supervisor.DaemonOpt, error)
 	return opts, nil
 }
 
-func newAPIServerConfig(config *config.Config) (*apiserver.Config, error) {
-	var tlsConfig *tls.Config
+func newAPIServerConfig(config *config.Config) (*apiserver.Config, *tlsReloader, error) {
+	var (
+		tlsConfig *tls.Config
+		reloader  *tlsReloader
+	)
 	if config.TLS != nil && *config.TLS {
 		var (
 			clientAuth tls.ClientAuthType
@@ -608,7 +735,7 @@ func newAPIServerConfig(config *config.Config) (*apiserver.Config, error) {
 			// server requires and verifies client's certificate
 			clientAuth = tls.RequireAndVerifyClientCert
 		}
-		tlsConfig, err = tlsconfig.Server(tlsconfig.Options{
+		reloader, err = newTLSReloader(tlsconfig.Options{
 			CAFile:             config.TLSOptions.CAFile,
 			CertFile:           config.TLSOptions.CertFile,
 			KeyFile:            config.TLSOptions.KeyFile,
@@ -616,8 +743,9 @@ func newAPIServerConfig(config *config.Config) (*apiserver.Config, error) {
 			ClientAuth:         clientAuth,
 		})
 		if err != nil {
-			return nil, errors.Wrap(err, "invalid TLS configuration")
+			return nil, nil, err
 		}
+		tlsConfig = reloader.TLSConfig()
 	}
 
 	return &apiserver.Config{
@@ -626,7 +754,7 @@ func newAPIServerConfig(config *config.Config) (*apiserver.Config, error) {
 		CorsHeaders: config.CorsHeaders,
 		TLSConfig:   tlsConfig,
 		Hosts:       config.Hosts,
-	}, nil
+	}, reloader, nil
 }
 
 // checkTLSAuthOK checks basically for an explicitly disabled TLS/TLSVerify
@@ -744,6 +872,7 @@ func createAndStartCluster(cli *DaemonCli, d *daemon.Daemon) (*cluster.Cluster,
 		RaftElectionTick:       cli.Config.SwarmRaftElectionTick,
 		RuntimeRoot:            cli.getSwarmRunRoot(),
 		WatchStream:            watchStream,
+		LocalSecrets:           d.LocalSecrets(),
 	})
 	if err != nil {
 		return nil, err
@@ -758,6 +887,9 @@ func createAndStartCluster(cli *DaemonCli, d *daemon.Daemon) (*cluster.Cluster,
 // plugins present on the host and available to the daemon
 func validateAuthzPlugins(requestedPlugins []string, pg plugingetter.PluginGetter) error {
 	for _, reqPlugin := range requestedPlugins {
+		if _, err := pg.Get(reqPlugin, authorization.AuthZApiImplementsV2, plugingetter.Lookup); err == nil {
+			continue
+		}
 		if _, err := pg.Get(reqPlugin, authorization.AuthZApiImplements, plugingetter.Lookup); err != nil {
 			return err
 		}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/docker/docker/daemon"
+	"github.com/docker/docker/daemon/cluster"
+	"github.com/sirupsen/logrus"
+)
+
+// exitWhenIdle stops the daemon once it had no API connections and no running
+// containers for the given timeout, and was not part of a swarm. It is used
+// with sockets activated by systemd, which starts the daemon again on the next
+// connection.
+func (cli *DaemonCli) exitWhenIdle(ctx context.Context, d *daemon.Daemon, c *cluster.Cluster, timeout time.Duration) {
+	interval := timeout / 10
+	if interval < time.Second {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	lastBusy := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if d.ActiveContainers() > 0 || c.IsAgent() {
+			lastBusy = time.Now()
+			continue
+		}
+		idle := cli.api.IdleTime()
+		if sinceBusy := time.Since(lastBusy); sinceBusy < idle {
+			idle = sinceBusy
+		}
+		if idle >= timeout {
+			logrus.Infof("Exiting after being idle for %s", timeout)
+			cli.stop()
+			return
+		}
+	}
+}
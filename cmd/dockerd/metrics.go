@@ -4,34 +4,67 @@ import (
 	"net"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	metrics "github.com/docker/go-metrics"
 	"github.com/sirupsen/logrus"
 )
 
-func startMetricsServer(addr string) error {
-	if addr == "" {
+// metricsServer serves the metrics API, and can be moved to another address
+// when the configuration is reloaded.
+type metricsServer struct {
+	mu   sync.Mutex
+	addr string
+	srv  *http.Server
+}
+
+// listen serves the metrics API on the given address, instead of the address
+// it is served on, if any. The metrics API is not served if addr is empty.
+func (m *metricsServer) listen(addr string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if addr == m.addr {
 		return nil
 	}
-	if err := allocateDaemonPort(addr); err != nil {
-		return err
+
+	var l net.Listener
+	if addr != "" {
+		if err := allocateDaemonPort(addr); err != nil {
+			return err
+		}
+		var err error
+		if l, err = net.Listen("tcp", addr); err != nil {
+			return err
+		}
+	}
+	if m.srv != nil {
+		m.srv.Close()
+		logrus.Infof("metrics API stopped listening on %s", m.addr)
 	}
-	l, err := net.Listen("tcp", addr)
-	if err != nil {
-		return err
+	m.addr, m.srv = addr, nil
+	if l == nil {
+		return nil
 	}
+
 	mux := http.NewServeMux()
 	mux.Handle("/metrics", metrics.Handler())
-	go func() {
+	m.srv = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Minute, // "G112: Potential Slowloris Attack (gosec)"; not a real concern for our use, so setting a long timeout.
+	}
+	go func(srv *http.Server) {
 		logrus.Infof("metrics API listening on %s", l.Addr())
-		srv := &http.Server{
-			Handler:           mux,
-			ReadHeaderTimeout: 5 * time.Minute, // "G112: Potential Slowloris Attack (gosec)"; not a real concern for our use, so setting a long timeout.
-		}
-		if err := srv.Serve(l); err != nil && !strings.Contains(err.Error(), "use of closed network connection") {
+		if err := srv.Serve(l); err != nil && err != http.ErrServerClosed && !strings.Contains(err.Error(), "use of closed network connection") {
 			logrus.WithError(err).Error("error serving metrics API")
 		}
-	}()
+	}(m.srv)
 	return nil
 }
+
+// address returns the address the metrics API is served on.
+func (m *metricsServer) address() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.addr
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"crypto/tls"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/daemon/config"
+	"github.com/docker/go-connections/tlsconfig"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// tlsReloadInterval is how often the TLS files of the API server are checked
+// for changes.
+const tlsReloadInterval = 10 * time.Second
+
+// tlsReloader holds the TLS configuration of the API server, and allows the
+// certificate, key, and CA to be replaced without restarting the daemon.
+// Connections that were already established keep using the configuration
+// they were set up with.
+type tlsReloader struct {
+	mu       sync.Mutex
+	opts     tlsconfig.Options
+	current  *tls.Config
+	modTimes map[string]time.Time
+}
+
+func newTLSReloader(opts tlsconfig.Options) (*tlsReloader, error) {
+	r := &tlsReloader{opts: opts}
+	if err := r.load(opts); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// load loads the TLS configuration from the given options, and makes it the
+// configuration for new connections.
+func (r *tlsReloader) load(opts tlsconfig.Options) error {
+	modTimes := tlsModTimes(opts)
+	c, err := tlsconfig.Server(opts)
+	if err != nil {
+		return errors.Wrap(err, "invalid TLS configuration")
+	}
+	// The listeners set this on the config they are given, which isn't
+	// the config returned for each connection.
+	c.NextProtos = []string{"http/1.1"}
+
+	r.mu.Lock()
+	r.opts = opts
+	r.current = c
+	r.modTimes = modTimes
+	r.mu.Unlock()
+	return nil
+}
+
+// TLSConfig returns the configuration to create the API listeners with. It
+// looks up the current configuration for every new connection.
+func (r *tlsReloader) TLSConfig() *tls.Config {
+	r.mu.Lock()
+	c := r.current.Clone()
+	r.mu.Unlock()
+	c.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		return r.current, nil
+	}
+	return c
+}
+
+// Reload reloads the TLS files. Paths set in conf replace the ones in use,
+// so that they can be changed through the configuration file. The current
+// configuration is kept if the new one is invalid.
+func (r *tlsReloader) Reload(conf *config.Config) error {
+	r.mu.Lock()
+	opts := r.opts
+	r.mu.Unlock()
+	if conf != nil {
+		if conf.TLSOptions.CAFile != "" {
+			opts.CAFile = conf.TLSOptions.CAFile
+		}
+		if conf.TLSOptions.CertFile != "" {
+			opts.CertFile = conf.TLSOptions.CertFile
+		}
+		if conf.TLSOptions.KeyFile != "" {
+			opts.KeyFile = conf.TLSOptions.KeyFile
+		}
+	}
+	if err := r.load(opts); err != nil {
+		return err
+	}
+	logrus.WithFields(logrus.Fields{
+		"cert": opts.CertFile,
+		"key":  opts.KeyFile,
+		"ca":   opts.CAFile,
+	}).Info("Reloaded API server TLS configuration")
+	return nil
+}
+
+// changed returns whether any of the TLS files was modified since it was
+// last loaded.
+func (r *tlsReloader) changed() bool {
+	r.mu.Lock()
+	opts, modTimes := r.opts, r.modTimes
+	r.mu.Unlock()
+	for f, t := range tlsModTimes(opts) {
+		if !t.Equal(modTimes[f]) {
+			return true
+		}
+	}
+	return false
+}
+
+// watch reloads the TLS files when they are modified, until stop is closed.
+func (r *tlsReloader) watch(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if !r.changed() {
+				continue
+			}
+			if err := r.Reload(nil); err != nil {
+				logrus.WithError(err).Error("Failed to reload API server TLS configuration after the TLS files changed")
+				// Don't retry until the files change again.
+				r.mu.Lock()
+				r.modTimes = tlsModTimes(r.opts)
+				r.mu.Unlock()
+			}
+		}
+	}
+}
+
+func tlsModTimes(opts tlsconfig.Options) map[string]time.Time {
+	modTimes := make(map[string]time.Time)
+	for _, f := range []string{opts.CAFile, opts.CertFile, opts.KeyFile} {
+		if f == "" {
+			continue
+		}
+		// Stat follows symlinks, so that files replaced by swapping a
+		// symlink, such as mounted Kubernetes secrets, are noticed.
+		if fi, err := os.Stat(f); err == nil {
+			modTimes[f] = fi.ModTime()
+		}
+	}
+	return modTimes
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/daemon/config"
+	"github.com/docker/go-connections/tlsconfig"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+// writeTestCert writes a self-signed certificate with the given common name,
+// and its key, to certFile and keyFile.
+func writeTestCert(t *testing.T, cn, certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NilError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IsCA:         true,
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	assert.NilError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.NilError(t, err)
+
+	assert.NilError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o644))
+	assert.NilError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+}
+
+func servedCommonName(t *testing.T, c *tls.Config) string {
+	t.Helper()
+	cc, err := c.GetConfigForClient(&tls.ClientHelloInfo{})
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(cc.Certificates, 1))
+	cert, err := x509.ParseCertificate(cc.Certificates[0].Certificate[0])
+	assert.NilError(t, err)
+	return cert.Subject.CommonName
+}
+
+func TestTLSReloader(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	writeTestCert(t, "first", certFile, keyFile)
+
+	r, err := newTLSReloader(tlsconfig.Options{CertFile: certFile, KeyFile: keyFile})
+	assert.NilError(t, err)
+	serverConfig := r.TLSConfig()
+	assert.Check(t, is.Equal(servedCommonName(t, serverConfig), "first"))
+	assert.Check(t, !r.changed())
+
+	// Make sure the modification time changes on file systems with a
+	// coarse timestamp resolution.
+	past := time.Now().Add(-time.Minute)
+	assert.NilError(t, os.Chtimes(certFile, past, past))
+	writeTestCert(t, "second", certFile, keyFile)
+	assert.Check(t, r.changed())
+	assert.NilError(t, r.Reload(nil))
+	assert.Check(t, is.Equal(servedCommonName(t, serverConfig), "second"))
+	assert.Check(t, !r.changed())
+
+	// An invalid configuration is rejected, and the current one is kept.
+	assert.NilError(t, os.WriteFile(keyFile, []byte("garbage"), 0o600))
+	assert.Check(t, is.ErrorContains(r.Reload(nil), "invalid TLS configuration"))
+	assert.Check(t, is.Equal(servedCommonName(t, serverConfig), "second"))
+
+	// Paths can be changed through the configuration file.
+	otherCert := filepath.Join(dir, "other-cert.pem")
+	otherKey := filepath.Join(dir, "other-key.pem")
+	writeTestCert(t, "third", otherCert, otherKey)
+	conf := &config.Config{}
+	conf.TLSOptions.CertFile = otherCert
+	conf.TLSOptions.KeyFile = otherKey
+	assert.NilError(t, r.Reload(conf))
+	assert.Check(t, is.Equal(servedCommonName(t, serverConfig), "third"))
+}
@@ -0,0 +1,108 @@
+package container // import "github.com/docker/docker/container"
+
+import (
+	"encoding/json"
+	"os"
+
+	containertypes "github.com/docker/docker/api/types/container"
+)
+
+// Compact releases the parts of the container's Config and HostConfig which
+// are not needed while the container is not running, keeping only the fields
+// used to list, filter and prune containers. Hydrate reads the released parts
+// back from disk. Callers must hold a Container lock, and must not call
+// Compact on a running container.
+func (container *Container) Compact() {
+	container.compactMu.Lock()
+	defer container.compactMu.Unlock()
+
+	if container.compacted || container.Config == nil || container.HostConfig == nil {
+		return
+	}
+	// The configuration is replaced rather than modified, so that the
+	// callers which hold a reference to the full one can keep using it.
+	container.Config = &containertypes.Config{
+		Image:        container.Config.Image,
+		Labels:       container.Config.Labels,
+		ExposedPorts: container.Config.ExposedPorts,
+		StopSignal:   container.Config.StopSignal,
+		StopTimeout:  container.Config.StopTimeout,
+	}
+	container.HostConfig = &containertypes.HostConfig{
+		AutoRemove:    container.HostConfig.AutoRemove,
+		Configs:       container.HostConfig.Configs,
+		Isolation:     container.HostConfig.Isolation,
+		Links:         container.HostConfig.Links,
+		NetworkMode:   container.HostConfig.NetworkMode,
+		PortBindings:  container.HostConfig.PortBindings,
+		RestartPolicy: container.HostConfig.RestartPolicy,
+		Secrets:       container.HostConfig.Secrets,
+	}
+	container.compacted = true
+}
+
+// Hydrate reads back the configuration released by Compact from disk. It is
+// a no-op if the container is not compacted.
+func (container *Container) Hydrate() error {
+	container.compactMu.Lock()
+	defer container.compactMu.Unlock()
+
+	if !container.compacted {
+		return nil
+	}
+	pth, err := container.ConfigPath()
+	if err != nil {
+		return err
+	}
+	var cfg struct {
+		Config *containertypes.Config
+	}
+	if err := decodeFile(pth, &cfg); err != nil {
+		return err
+	}
+	if cfg.Config == nil {
+		cfg.Config = &containertypes.Config{}
+	}
+
+	pth, err = container.HostConfigPath()
+	if err != nil {
+		return err
+	}
+	hostConfig := &containertypes.HostConfig{}
+	if err := decodeFile(pth, hostConfig); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	// Same as InitDNSHostConfig, which cannot be used as the caller may
+	// hold the container lock.
+	if hostConfig.DNS == nil {
+		hostConfig.DNS = make([]string, 0)
+	}
+	if hostConfig.DNSSearch == nil {
+		hostConfig.DNSSearch = make([]string, 0)
+	}
+	if hostConfig.DNSOptions == nil {
+		hostConfig.DNSOptions = make([]string, 0)
+	}
+
+	container.Config = cfg.Config
+	container.HostConfig = hostConfig
+	container.compacted = false
+	return nil
+}
+
+func decodeFile(pth string, v interface{}) error {
+	f, err := os.Open(pth)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return json.NewDecoder(f).Decode(v)
+}
+
+// IsCompacted returns whether the container's configuration was released by
+// Compact, and not read back yet.
+func (container *Container) IsCompacted() bool {
+	container.compactMu.Lock()
+	defer container.compactMu.Unlock()
+	return container.compacted
+}
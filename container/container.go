@@ -96,6 +96,10 @@ type Container struct {
 	restartManager *restartmanager.RestartManager
 	attachContext  *attachContext
 
+	// compactMu protects compacted, see Compact.
+	compactMu sync.Mutex
+	compacted bool
+
 	// Fields here are specific to Unix platforms
 	AppArmorProfile string
 	HostnamePath    string
@@ -162,6 +166,10 @@ func (container *Container) FromDisk() error {
 // toDisk writes the container's configuration (config.v2.json, hostconfig.json)
 // to disk and returns a deep copy.
 func (container *Container) toDisk() (*Container, error) {
+	// Never persist a compacted configuration.
+	if err := container.Hydrate(); err != nil {
+		return nil, err
+	}
 	pth, err := container.ConfigPath()
 	if err != nil {
 		return nil, err
@@ -238,6 +246,9 @@ func (container *Container) WriteHostConfig() (*containertypes.HostConfig, error
 		deepCopy containertypes.HostConfig
 	)
 
+	if err := container.Hydrate(); err != nil {
+		return nil, err
+	}
 	pth, err := container.HostConfigPath()
 	if err != nil {
 		return nil, err
@@ -11,6 +11,7 @@ import (
 	"github.com/docker/docker/daemon/logger/jsonfilelog"
 	"github.com/moby/sys/signal"
 	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
 )
 
 func TestContainerStopSignal(t *testing.T) {
@@ -123,3 +124,39 @@ func TestContainerLogPathSetForRingLogger(t *testing.T) {
 	assert.NilError(t, err)
 	assert.Equal(t, c.LogPath, expectedLogPath)
 }
+
+func TestContainerCompact(t *testing.T) {
+	c := NewBaseContainer("TestContainerCompact", t.TempDir())
+	c.Config = &container.Config{
+		Image:  "busybox",
+		Env:    []string{"FOO=bar"},
+		Labels: map[string]string{"foo": "bar"},
+	}
+	c.HostConfig = &container.HostConfig{
+		Binds:       []string{"/src:/dst"},
+		NetworkMode: "bridge",
+	}
+	_, err := c.toDisk()
+	assert.NilError(t, err)
+
+	c.Compact()
+	assert.Check(t, c.IsCompacted())
+	assert.Check(t, is.Nil(c.Config.Env))
+	assert.Check(t, is.Equal(c.Config.Image, "busybox"))
+	assert.Check(t, is.DeepEqual(c.Config.Labels, map[string]string{"foo": "bar"}))
+	assert.Check(t, is.Nil(c.HostConfig.Binds))
+	assert.Check(t, is.Equal(c.HostConfig.NetworkMode, container.NetworkMode("bridge")))
+
+	// Persisting the container reads back its configuration first.
+	deepCopy, err := c.toDisk()
+	assert.NilError(t, err)
+	assert.Check(t, !c.IsCompacted())
+	assert.Check(t, is.DeepEqual(c.Config.Env, []string{"FOO=bar"}))
+	assert.Check(t, is.DeepEqual(c.HostConfig.Binds, []string{"/src:/dst"}))
+	assert.Check(t, is.DeepEqual(deepCopy.Config.Env, []string{"FOO=bar"}))
+
+	c.Compact()
+	assert.NilError(t, c.Hydrate())
+	assert.Check(t, is.DeepEqual(c.Config.Env, []string{"FOO=bar"}))
+	assert.Check(t, is.DeepEqual(c.HostConfig.Binds, []string{"/src:/dst"}))
+}
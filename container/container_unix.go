@@ -66,7 +66,7 @@ func (container *Container) BuildHostnameFile() error {
 // NetworkMounts returns the list of network mounts.
 func (container *Container) NetworkMounts() []Mount {
 	var mounts []Mount
-	shared := container.HostConfig.NetworkMode.IsContainer()
+	shared := container.HostConfig.NetworkMode.IsContainer() || container.HostConfig.NetworkMode.IsGroup()
 	parser := volumemounts.NewParser()
 	if container.ResolvConfPath != "" {
 		if _, err := os.Stat(container.ResolvConfPath); err != nil {
@@ -3,8 +3,11 @@ package container // import "github.com/docker/docker/container"
 import (
 	"runtime"
 	"sync"
+	"time"
 
 	"github.com/containerd/containerd/cio"
+	apitypes "github.com/docker/docker/api/types"
+	containertypes "github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/container/stream"
 	"github.com/docker/docker/libcontainerd/types"
 	"github.com/docker/docker/pkg/stringid"
@@ -36,6 +39,12 @@ type ExecConfig struct {
 	Env          []string
 	Process      types.Process
 	ConsoleSize  *[2]uint
+	Resources    *apitypes.ExecResources
+	StartedAt    time.Time
+	Client       *containertypes.ExecClient
+	// CgroupPath is the path of the sub-cgroup the exec process runs in if
+	// it has resource limits, relative to the cgroup mountpoint.
+	CgroupPath string
 }
 
 // NewExecConfig initializes the a new exec configuration
@@ -11,4 +11,5 @@ type Mount struct {
 	Data         string `json:"data"`
 	Propagation  string `json:"mountpropagation"`
 	NonRecursive bool   `json:"nonrecursive"`
+	IDMap        bool   `json:"idmap"`
 }
@@ -23,12 +23,17 @@ ERROR() {
 # constants
 DOCKERD_ROOTLESS_SH="dockerd-rootless.sh"
 SYSTEMD_UNIT="docker.service"
+SYSTEMD_SOCKET_UNIT="docker.socket"
 CLI_CONTEXT="rootless"
 
 # CLI opt: --force
 OPT_FORCE=""
 # CLI opt: --skip-iptables
 OPT_SKIP_IPTABLES=""
+# CLI opt: --socket-activation
+OPT_SOCKET_ACTIVATION=""
+# seconds without API connections or running containers after which the socket-activated daemon exits
+: "${DOCKERD_ROOTLESS_IDLE_EXIT_TIMEOUT:=600}"
 
 # global vars
 ARG0="$0"
@@ -280,10 +285,44 @@ show_systemd_error() {
 	fi
 }
 
+# install the socket unit (systemd)
+install_systemd_socket() {
+	socket_file="${CFG_DIR}/systemd/user/${SYSTEMD_SOCKET_UNIT}"
+	if [ -f "${socket_file}" ]; then
+		WARNING "File already exists, skipping: ${socket_file}"
+	else
+		INFO "Creating ${socket_file}"
+		cat <<- EOT > "${socket_file}"
+			[Unit]
+			Description=Docker Socket for the API (Rootless)
+			Documentation=https://docs.docker.com/go/rootless/
+
+			[Socket]
+			ListenStream=%t/docker.sock
+			SocketMode=0600
+
+			[Install]
+			WantedBy=sockets.target
+		EOT
+	fi
+}
+
 # install (systemd)
 install_systemd() {
 	mkdir -p "${CFG_DIR}/systemd/user"
 	unit_file="${CFG_DIR}/systemd/user/${SYSTEMD_UNIT}"
+	# With socket activation, systemd starts the daemon on the first connection to the socket, and
+	# the daemon exits once idle. The service is only restarted on failure, and is not enabled.
+	unit_deps=""
+	restart="always"
+	enabled_unit="${SYSTEMD_UNIT}"
+	if [ -n "$OPT_SOCKET_ACTIVATION" ]; then
+		install_systemd_socket
+		unit_deps="$(printf 'Requires=%s\nAfter=%s' "${SYSTEMD_SOCKET_UNIT}" "${SYSTEMD_SOCKET_UNIT}")"
+		restart="on-failure"
+		enabled_unit="${SYSTEMD_SOCKET_UNIT}"
+		DOCKERD_ROOTLESS_SH_FLAGS="${DOCKERD_ROOTLESS_SH_FLAGS} -H fd:// --idle-exit-timeout=${DOCKERD_ROOTLESS_IDLE_EXIT_TIMEOUT}"
+	fi
 	if [ -f "${unit_file}" ]; then
 		WARNING "File already exists, skipping: ${unit_file}"
 	else
@@ -292,6 +331,7 @@ install_systemd() {
 			[Unit]
 			Description=Docker Application Container Engine (Rootless)
 			Documentation=https://docs.docker.com/go/rootless/
+			${unit_deps}
 
 			[Service]
 			Environment=PATH=$BIN:/sbin:/usr/sbin:$PATH
@@ -299,7 +339,7 @@ install_systemd() {
 			ExecReload=/bin/kill -s HUP \$MAINPID
 			TimeoutSec=0
 			RestartSec=2
-			Restart=always
+			Restart=${restart}
 			StartLimitBurst=3
 			StartLimitInterval=60s
 			LimitNOFILE=infinity
@@ -316,11 +356,11 @@ install_systemd() {
 		EOT
 		systemctl --user daemon-reload
 	fi
-	if ! systemctl --user --no-pager status "${SYSTEMD_UNIT}" > /dev/null 2>&1; then
-		INFO "starting systemd service ${SYSTEMD_UNIT}"
+	if ! systemctl --user --no-pager status "${enabled_unit}" > /dev/null 2>&1; then
+		INFO "starting systemd unit ${enabled_unit}"
 		(
 			set -x
-			if ! systemctl --user start "${SYSTEMD_UNIT}"; then
+			if ! systemctl --user start "${enabled_unit}"; then
 				set +x
 				show_systemd_error
 				exit 1
@@ -330,17 +370,21 @@ install_systemd() {
 	fi
 	(
 		set -x
+		# With socket activation, this starts the service.
+		DOCKER_HOST="unix://$XDG_RUNTIME_DIR/docker.sock" $BIN/docker version
 		if ! systemctl --user --no-pager --full status "${SYSTEMD_UNIT}"; then
 			set +x
 			show_systemd_error
 			exit 1
 		fi
-		DOCKER_HOST="unix://$XDG_RUNTIME_DIR/docker.sock" $BIN/docker version
-		systemctl --user enable "${SYSTEMD_UNIT}"
+		systemctl --user enable "${enabled_unit}"
 	)
-	INFO "Installed ${SYSTEMD_UNIT} successfully."
+	INFO "Installed ${enabled_unit} successfully."
+	if [ -n "$OPT_SOCKET_ACTIVATION" ]; then
+		INFO "${SYSTEMD_UNIT} is started on demand, and exits after ${DOCKERD_ROOTLESS_IDLE_EXIT_TIMEOUT} seconds without API connections or running containers."
+	fi
 	INFO "To control ${SYSTEMD_UNIT}, run: \`systemctl --user (start|stop|restart) ${SYSTEMD_UNIT}\`"
-	INFO "To run ${SYSTEMD_UNIT} on system startup, run: \`sudo loginctl enable-linger $(id -un)\`"
+	INFO "To run ${enabled_unit} on system startup, run: \`sudo loginctl enable-linger $(id -un)\`"
 	echo
 }
 
@@ -414,6 +458,19 @@ cmd_entrypoint_uninstall() {
 		INFO "systemd not detected, ${DOCKERD_ROOTLESS_SH} needs to be stopped manually:"
 	else
 		unit_file="${CFG_DIR}/systemd/user/${SYSTEMD_UNIT}"
+		socket_file="${CFG_DIR}/systemd/user/${SYSTEMD_SOCKET_UNIT}"
+		if [ -f "${socket_file}" ]; then
+			(
+				set -x
+				systemctl --user stop "${SYSTEMD_SOCKET_UNIT}"
+			) || :
+			(
+				set -x
+				systemctl --user disable "${SYSTEMD_SOCKET_UNIT}"
+			) || :
+			rm -f "${socket_file}"
+			INFO "Uninstalled ${SYSTEMD_SOCKET_UNIT}"
+		fi
 		(
 			set -x
 			systemctl --user stop "${SYSTEMD_UNIT}"
@@ -451,6 +508,7 @@ usage() {
 	echo "Options:"
 	echo "  -f, --force                Ignore rootful Docker (/var/run/docker.sock)"
 	echo "      --skip-iptables        Ignore missing iptables"
+	echo "      --socket-activation    Start the daemon on demand via systemd socket activation, and exit it when idle"
 	echo
 	echo "Commands:"
 	echo "  check        Check prerequisites"
@@ -459,7 +517,7 @@ usage() {
 }
 
 # parse CLI args
-if ! args="$(getopt -o hf --long help,force,skip-iptables -n "$ARG0" -- "$@")"; then
+if ! args="$(getopt -o hf --long help,force,skip-iptables,socket-activation -n "$ARG0" -- "$@")"; then
 	usage
 	exit 1
 fi
@@ -478,6 +536,9 @@ while [ "$#" -gt 0 ]; do
 		--skip-iptables)
 			OPT_SKIP_IPTABLES=1
 			;;
+		--socket-activation)
+			OPT_SOCKET_ACTIVATION=1
+			;;
 		--)
 			break
 			;;
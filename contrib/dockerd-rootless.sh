@@ -15,6 +15,8 @@
 # * DOCKERD_ROOTLESS_ROOTLESSKIT_SLIRP4NETNS_SANDBOX=(auto|true|false): whether to protect slirp4netns with a dedicated mount namespace. Defaults to "auto".
 # * DOCKERD_ROOTLESS_ROOTLESSKIT_SLIRP4NETNS_SECCOMP=(auto|true|false): whether to protect slirp4netns with seccomp. Defaults to "auto".
 #
+# Up to 3 sockets activated by systemd (LISTEN_FDS) are passed to dockerd, to be used with `-H fd://`.
+#
 # See the documentation for the further information: https://docs.docker.com/go/rootless/
 
 set -e -x
@@ -92,6 +94,23 @@ if [ -z "$_DOCKERD_ROOTLESS_CHILD" ]; then
 		_DOCKERD_ROOTLESS_SELINUX=1
 		export _DOCKERD_ROOTLESS_SELINUX
 	fi
+	# RootlessKit uses the file descriptors from 3 for itself, so the sockets activated by systemd
+	# are moved to the file descriptors from 7, and moved back in the child.
+	if [ -n "${LISTEN_FDS:-}" ] && [ "${LISTEN_PID:-}" = "$$" ]; then
+		if [ "$LISTEN_FDS" -gt 3 ]; then
+			echo "At most 3 sockets activated by systemd are supported, got $LISTEN_FDS"
+			exit 1
+		fi
+		i=0
+		while [ $i -lt "$LISTEN_FDS" ]; do
+			eval "exec $((7 + i))<&$((3 + i)) $((3 + i))<&-"
+			i=$((i + 1))
+		done
+		_DOCKERD_ROOTLESS_LISTEN_FDS=$LISTEN_FDS
+		_DOCKERD_ROOTLESS_LISTEN_FDNAMES=${LISTEN_FDNAMES:-}
+		export _DOCKERD_ROOTLESS_LISTEN_FDS _DOCKERD_ROOTLESS_LISTEN_FDNAMES
+		unset LISTEN_FDS LISTEN_PID LISTEN_FDNAMES
+	fi
 	# Re-exec the script via RootlessKit, so as to create unprivileged {user,mount,network} namespaces.
 	#
 	# --copy-up allows removing/creating files in the directories by creating tmpfs and symlinks
@@ -130,6 +149,20 @@ else
 		mount --rbind ${realpath_etc_ssl} /etc/ssl
 	fi
 
+	if [ -n "${_DOCKERD_ROOTLESS_LISTEN_FDS:-}" ]; then
+		i=0
+		while [ $i -lt "$_DOCKERD_ROOTLESS_LISTEN_FDS" ]; do
+			eval "exec $((3 + i))<&$((7 + i)) $((7 + i))<&-"
+			i=$((i + 1))
+		done
+		# exec preserves the PID, which dockerd checks against LISTEN_PID.
+		LISTEN_FDS=$_DOCKERD_ROOTLESS_LISTEN_FDS
+		LISTEN_FDNAMES=$_DOCKERD_ROOTLESS_LISTEN_FDNAMES
+		LISTEN_PID=$$
+		export LISTEN_FDS LISTEN_FDNAMES LISTEN_PID
+		unset _DOCKERD_ROOTLESS_LISTEN_FDS _DOCKERD_ROOTLESS_LISTEN_FDNAMES
+	fi
+
 	# shellcheck disable=SC2086
 	exec $dockerd "$@"
 fi
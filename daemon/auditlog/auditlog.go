@@ -0,0 +1,125 @@
+// Package auditlog provides the sinks the API audit log is written to.
+package auditlog // import "github.com/docker/docker/daemon/auditlog"
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+
+	syslog "github.com/RackSec/srslog"
+	"github.com/docker/docker/api/server/middleware"
+	"github.com/docker/docker/daemon/config"
+	"github.com/pkg/errors"
+)
+
+const defaultSyslogTag = "dockerd-audit"
+
+// Sink writes audit entries to a destination.
+type Sink interface {
+	middleware.AuditSink
+	io.Closer
+}
+
+// New returns the sink for the given audit log configuration.
+func New(cfg config.AuditLogConfig) (Sink, error) {
+	switch cfg.Driver {
+	case config.AuditLogDriverFile:
+		return newFileSink(cfg.Path)
+	case config.AuditLogDriverSyslog:
+		return newSyslogSink(cfg.Address, cfg.Tag)
+	case config.AuditLogDriverOTLP:
+		return newOTLPSink(cfg.Address)
+	default:
+		return nil, errors.Errorf("unsupported audit log driver: %s", cfg.Driver)
+	}
+}
+
+// fileSink appends audit entries to a file, one JSON object per line.
+type fileSink struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+func newFileSink(path string) (*fileSink, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, errors.Wrap(err, "failed to create audit log directory")
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open audit log")
+	}
+	return &fileSink{f: f}, nil
+}
+
+func (s *fileSink) WriteEntry(e middleware.AuditEntry) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.f.Write(b)
+	return err
+}
+
+func (s *fileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.f.Close()
+}
+
+// syslogSink sends audit entries to syslog, formatted as JSON.
+type syslogSink struct {
+	w *syslog.Writer
+}
+
+func newSyslogSink(address, tag string) (*syslogSink, error) {
+	if tag == "" {
+		tag = defaultSyslogTag
+	}
+	priority := syslog.LOG_AUTHPRIV | syslog.LOG_INFO
+
+	var (
+		w   *syslog.Writer
+		err error
+	)
+	if address == "" {
+		w, err = syslog.New(priority, tag)
+	} else {
+		var u *url.URL
+		u, err = url.Parse(address)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid audit log syslog address")
+		}
+		switch u.Scheme {
+		case "udp", "tcp":
+			w, err = syslog.Dial(u.Scheme, u.Host, priority, tag)
+		case "unix", "unixgram":
+			w, err = syslog.Dial(u.Scheme, u.Path, priority, tag)
+		default:
+			return nil, errors.Errorf("unsupported audit log syslog protocol: %s", u.Scheme)
+		}
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to connect to syslog for audit log")
+	}
+	w.SetFormatter(syslog.RFC5424Formatter)
+	return &syslogSink{w: w}, nil
+}
+
+func (s *syslogSink) WriteEntry(e middleware.AuditEntry) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return s.w.Info(string(b))
+}
+
+func (s *syslogSink) Close() error {
+	return s.w.Close()
+}
@@ -0,0 +1,249 @@
+package auditlog // import "github.com/docker/docker/daemon/auditlog"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/server/middleware"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	otlpLogsPath      = "/v1/logs"
+	otlpQueueSize     = 1024
+	otlpBatchSize     = 100
+	otlpFlushInterval = time.Second
+	otlpExportTimeout = 10 * time.Second
+
+	// Severity numbers of the OTLP log data model.
+	otlpSeverityInfo = 9
+	otlpSeverityWarn = 13
+)
+
+// otlpSink exports audit entries as OTLP log records to a collector, using
+// the JSON encoding of OTLP/HTTP. Entries are queued, and exported in
+// batches in the background, so that a slow collector does not delay API
+// requests; entries are dropped while the queue is full.
+type otlpSink struct {
+	endpoint string
+	client   *http.Client
+	entries  chan middleware.AuditEntry
+	done     chan struct{}
+
+	mu      sync.Mutex
+	dropped int
+	closed  bool
+}
+
+func newOTLPSink(address string) (*otlpSink, error) {
+	u, err := url.Parse(address)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid audit log otlp address")
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = otlpLogsPath
+	}
+	s := &otlpSink{
+		endpoint: u.String(),
+		client:   &http.Client{Timeout: otlpExportTimeout},
+		entries:  make(chan middleware.AuditEntry, otlpQueueSize),
+		done:     make(chan struct{}),
+	}
+	go s.run()
+	return s, nil
+}
+
+func (s *otlpSink) WriteEntry(e middleware.AuditEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return errors.New("audit log is closed")
+	}
+	select {
+	case s.entries <- e:
+		return nil
+	default:
+		// Dropped entries are reported by run, rather than for each
+		// request.
+		s.dropped++
+		return nil
+	}
+}
+
+// Close exports the queued entries, and stops the sink.
+func (s *otlpSink) Close() error {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.entries)
+	}
+	s.mu.Unlock()
+	<-s.done
+	return nil
+}
+
+func (s *otlpSink) run() {
+	defer close(s.done)
+	ticker := time.NewTicker(otlpFlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]middleware.AuditEntry, 0, otlpBatchSize)
+	flush := func() {
+		s.mu.Lock()
+		dropped := s.dropped
+		s.dropped = 0
+		s.mu.Unlock()
+		if dropped > 0 {
+			logrus.WithField("dropped", dropped).Warn("audit log export queue is full, entries were dropped")
+		}
+		if len(batch) == 0 {
+			return
+		}
+		if err := s.export(batch); err != nil {
+			logrus.WithError(err).WithField("entries", len(batch)).Error("failed to export audit log entries")
+		}
+		batch = batch[:0]
+	}
+	for {
+		select {
+		case e, ok := <-s.entries:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, e)
+			if len(batch) == otlpBatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+func (s *otlpSink) export(entries []middleware.AuditEntry) error {
+	body, err := json.Marshal(newOTLPLogsRequest(entries))
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), otlpExportTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("collector returned %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
+
+// The types below are the subset of the JSON encoding of the OTLP logs
+// protocol that the sink uses.
+
+type otlpLogsRequest struct {
+	ResourceLogs []otlpResourceLogs `json:"resourceLogs"`
+}
+
+type otlpResourceLogs struct {
+	Resource  otlpResource    `json:"resource"`
+	ScopeLogs []otlpScopeLogs `json:"scopeLogs"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeLogs struct {
+	Scope      otlpScope       `json:"scope"`
+	LogRecords []otlpLogRecord `json:"logRecords"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpLogRecord struct {
+	TimeUnixNano   string         `json:"timeUnixNano"`
+	SeverityNumber int            `json:"severityNumber"`
+	SeverityText   string         `json:"severityText"`
+	Body           otlpAnyValue   `json:"body"`
+	Attributes     []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue *string `json:"stringValue,omitempty"`
+	IntValue    *string `json:"intValue,omitempty"`
+}
+
+func otlpString(key, value string) otlpKeyValue {
+	return otlpKeyValue{Key: key, Value: otlpAnyValue{StringValue: &value}}
+}
+
+func otlpInt(key string, value int) otlpKeyValue {
+	v := strconv.Itoa(value)
+	return otlpKeyValue{Key: key, Value: otlpAnyValue{IntValue: &v}}
+}
+
+// newOTLPLogsRequest returns the export request of audit entries. The body of
+// the log records is the entry formatted as JSON, as written by the other
+// sinks, and the main fields of the entry are set as attributes.
+func newOTLPLogsRequest(entries []middleware.AuditEntry) otlpLogsRequest {
+	records := make([]otlpLogRecord, 0, len(entries))
+	for _, e := range entries {
+		b, err := json.Marshal(e)
+		if err != nil {
+			continue
+		}
+		body := string(b)
+		rec := otlpLogRecord{
+			TimeUnixNano:   strconv.FormatInt(e.Time.UnixNano(), 10),
+			SeverityNumber: otlpSeverityInfo,
+			SeverityText:   "INFO",
+			Body:           otlpAnyValue{StringValue: &body},
+			Attributes: []otlpKeyValue{
+				otlpString("http.method", e.Method),
+				otlpInt("http.status_code", e.Status),
+				otlpString("docker.audit.decision", e.Decision),
+			},
+		}
+		if e.Decision != middleware.AuditDecisionAllow {
+			rec.SeverityNumber, rec.SeverityText = otlpSeverityWarn, "WARN"
+		}
+		if e.Endpoint != "" {
+			rec.Attributes = append(rec.Attributes, otlpString("http.route", e.Endpoint))
+		}
+		if e.User != "" {
+			rec.Attributes = append(rec.Attributes, otlpString("enduser.id", e.User))
+		}
+		records = append(records, rec)
+	}
+	return otlpLogsRequest{ResourceLogs: []otlpResourceLogs{{
+		Resource: otlpResource{Attributes: []otlpKeyValue{otlpString("service.name", "dockerd")}},
+		ScopeLogs: []otlpScopeLogs{{
+			Scope:      otlpScope{Name: defaultSyslogTag},
+			LogRecords: records,
+		}},
+	}}}
+}
@@ -0,0 +1,52 @@
+package auditlog // import "github.com/docker/docker/daemon/auditlog"
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/server/middleware"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestOTLPSink(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		requests []otlpLogsRequest
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Check(t, is.Equal(r.URL.Path, "/v1/logs"))
+		assert.Check(t, is.Equal(r.Header.Get("Content-Type"), "application/json"))
+		var req otlpLogsRequest
+		assert.Check(t, json.NewDecoder(r.Body).Decode(&req))
+		mu.Lock()
+		requests = append(requests, req)
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	s, err := newOTLPSink(srv.URL)
+	assert.NilError(t, err)
+	now := time.Now()
+	assert.NilError(t, s.WriteEntry(middleware.AuditEntry{Time: now, Method: "GET", Endpoint: "/containers/json", Status: 200, Decision: middleware.AuditDecisionAllow}))
+	assert.NilError(t, s.WriteEntry(middleware.AuditEntry{Time: now, Method: "POST", Status: 403, Decision: middleware.AuditDecisionDeny, User: "alice"}))
+	assert.NilError(t, s.Close())
+	assert.Check(t, s.WriteEntry(middleware.AuditEntry{}) != nil)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Assert(t, is.Len(requests, 1))
+	records := requests[0].ResourceLogs[0].ScopeLogs[0].LogRecords
+	assert.Assert(t, is.Len(records, 2))
+	assert.Check(t, is.Equal(records[0].SeverityText, "INFO"))
+	assert.Check(t, is.Equal(records[1].SeverityText, "WARN"))
+	assert.Check(t, is.Contains(records[1].Attributes, otlpString("enduser.id", "alice")))
+
+	var entry middleware.AuditEntry
+	assert.NilError(t, json.Unmarshal([]byte(*records[0].Body.StringValue), &entry))
+	assert.Check(t, is.Equal(entry.Endpoint, "/containers/json"))
+}
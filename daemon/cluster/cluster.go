@@ -53,6 +53,7 @@ import (
 	types "github.com/docker/docker/api/types/swarm"
 	"github.com/docker/docker/daemon/cluster/controllers/plugin"
 	executorpkg "github.com/docker/docker/daemon/cluster/executor"
+	"github.com/docker/docker/daemon/localsecrets"
 	lncluster "github.com/docker/docker/libnetwork/cluster"
 	"github.com/docker/docker/pkg/stack"
 	swarmapi "github.com/moby/swarmkit/v2/api"
@@ -108,6 +109,11 @@ type Config struct {
 	// RaftElectionTick is the number of ticks to elapse before followers propose a new round of leader election
 	// This value should be 10x that of RaftHeartbeatTick
 	RaftElectionTick uint32
+
+	// LocalSecrets is the store of the secrets and configs of standalone
+	// containers, which are managed through the secrets and configs
+	// endpoints while the node is not part of a swarm.
+	LocalSecrets *localsecrets.Store
 }
 
 // Cluster provides capabilities to participate in a cluster as a worker or a
@@ -367,6 +373,18 @@ func (c *Cluster) errNoManager(st nodeState) error {
 	return errors.WithStack(notAvailableError("This node is not a swarm manager. Worker nodes can't be used to view or modify cluster state. Please run this command on a manager node or promote the current node to a manager."))
 }
 
+// localSecretStore returns the store of the secrets and configs of standalone
+// containers if the node is not part of a swarm, or nil otherwise.
+func (c *Cluster) localSecretStore() *localsecrets.Store {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.currentNodeState().status != types.LocalNodeStateInactive {
+		return nil
+	}
+	return c.config.LocalSecrets
+}
+
 // Cleanup stops active swarm node. This is run before daemon shutdown.
 func (c *Cluster) Cleanup() {
 	c.controlMutex.Lock()
@@ -10,8 +10,17 @@ import (
 	"google.golang.org/grpc"
 )
 
-// GetConfig returns a config from a managed swarm cluster
+// GetConfig returns a config from a managed swarm cluster, or from the local
+// store if the node is not part of a swarm.
 func (c *Cluster) GetConfig(input string) (types.Config, error) {
+	if store := c.localSecretStore(); store != nil {
+		config, err := store.GetConfig(input)
+		if err != nil {
+			return types.Config{}, err
+		}
+		return convert.ConfigFromGRPC(config), nil
+	}
+
 	var config *swarmapi.Config
 
 	if err := c.lockedManagerAction(func(ctx context.Context, state nodeState) error {
@@ -27,8 +36,25 @@ func (c *Cluster) GetConfig(input string) (types.Config, error) {
 	return convert.ConfigFromGRPC(config), nil
 }
 
-// GetConfigs returns all configs of a managed swarm cluster.
+// GetConfigs returns all configs of a managed swarm cluster, or of the local
+// store if the node is not part of a swarm.
 func (c *Cluster) GetConfigs(options apitypes.ConfigListOptions) ([]types.Config, error) {
+	if store := c.localSecretStore(); store != nil {
+		filters, err := newListConfigsFilters(options.Filters)
+		if err != nil {
+			return nil, err
+		}
+		r, err := store.ListConfigs(filters)
+		if err != nil {
+			return nil, err
+		}
+		configs := []types.Config{}
+		for _, config := range r {
+			configs = append(configs, convert.ConfigFromGRPC(config))
+		}
+		return configs, nil
+	}
+
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
@@ -60,8 +86,12 @@ func (c *Cluster) GetConfigs(options apitypes.ConfigListOptions) ([]types.Config
 	return configs, nil
 }
 
-// CreateConfig creates a new config in a managed swarm cluster.
+// CreateConfig creates a new config in a managed swarm cluster, or in the
+// local store if the node is not part of a swarm.
 func (c *Cluster) CreateConfig(s types.ConfigSpec) (string, error) {
+	if store := c.localSecretStore(); store != nil {
+		return store.CreateConfig(convert.ConfigSpecToGRPC(s))
+	}
 	var resp *swarmapi.CreateConfigResponse
 	if err := c.lockedManagerAction(func(ctx context.Context, state nodeState) error {
 		configSpec := convert.ConfigSpecToGRPC(s)
@@ -79,8 +109,12 @@ func (c *Cluster) CreateConfig(s types.ConfigSpec) (string, error) {
 	return resp.Config.ID, nil
 }
 
-// RemoveConfig removes a config from a managed swarm cluster.
+// RemoveConfig removes a config from a managed swarm cluster, or from the
+// local store if the node is not part of a swarm.
 func (c *Cluster) RemoveConfig(input string) error {
+	if store := c.localSecretStore(); store != nil {
+		return store.RemoveConfig(input)
+	}
 	return c.lockedManagerAction(func(ctx context.Context, state nodeState) error {
 		config, err := getConfig(ctx, state.controlClient, input)
 		if err != nil {
@@ -96,9 +130,13 @@ func (c *Cluster) RemoveConfig(input string) error {
 	})
 }
 
-// UpdateConfig updates a config in a managed swarm cluster.
+// UpdateConfig updates a config in a managed swarm cluster, or in the local
+// store if the node is not part of a swarm.
 // Note: this is not exposed to the CLI but is available from the API only
 func (c *Cluster) UpdateConfig(input string, version uint64, spec types.ConfigSpec) error {
+	if store := c.localSecretStore(); store != nil {
+		return store.UpdateConfig(input, version, convert.ConfigSpecToGRPC(spec))
+	}
 	return c.lockedManagerAction(func(ctx context.Context, state nodeState) error {
 		config, err := getConfig(ctx, state.controlClient, input)
 		if err != nil {
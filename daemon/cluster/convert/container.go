@@ -2,6 +2,7 @@ package convert // import "github.com/docker/docker/daemon/cluster/convert"
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/docker/docker/api/types/container"
@@ -14,6 +15,56 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// tmpfsOptionsToGRPC converts the tmpfs options of a mount. The options which
+// swarmkit has no field for are passed in its Options, as tmpfs mount options.
+func tmpfsOptionsToGRPC(o *mounttypes.TmpfsOptions) *swarmapi.Mount_TmpfsOptions {
+	var opts []string
+	if o.UID != nil {
+		opts = append(opts, fmt.Sprintf("uid=%d", *o.UID))
+	}
+	if o.GID != nil {
+		opts = append(opts, fmt.Sprintf("gid=%d", *o.GID))
+	}
+	if o.HugePages != "" {
+		opts = append(opts, "huge="+o.HugePages)
+	}
+	if o.NoSwap {
+		opts = append(opts, "noswap")
+	}
+	return &swarmapi.Mount_TmpfsOptions{
+		SizeBytes: o.SizeBytes,
+		Mode:      o.Mode,
+		Options:   strings.Join(opts, ","),
+	}
+}
+
+// TmpfsOptionsFromGRPC converts the tmpfs options of a mount of a service.
+// Unknown and invalid options are ignored.
+func TmpfsOptionsFromGRPC(o *swarmapi.Mount_TmpfsOptions) *mounttypes.TmpfsOptions {
+	opts := &mounttypes.TmpfsOptions{
+		SizeBytes: o.SizeBytes,
+		Mode:      o.Mode,
+	}
+	for _, opt := range strings.Split(o.Options, ",") {
+		k, v, _ := strings.Cut(opt, "=")
+		switch k {
+		case "uid":
+			if uid, err := strconv.Atoi(v); err == nil {
+				opts.UID = &uid
+			}
+		case "gid":
+			if gid, err := strconv.Atoi(v); err == nil {
+				opts.GID = &gid
+			}
+		case "huge":
+			opts.HugePages = v
+		case "noswap":
+			opts.NoSwap = true
+		}
+	}
+	return opts
+}
+
 func containerSpecFromGRPC(c *swarmapi.ContainerSpec) *types.ContainerSpec {
 	if c == nil {
 		return nil
@@ -69,6 +120,16 @@ func containerSpecFromGRPC(c *swarmapi.ContainerSpec) *types.ContainerSpec {
 			}
 		}
 	}
+	if profiles, _ := SecurityProfilesFromGRPC(c); profiles != nil {
+		if containerSpec.Privileges == nil {
+			containerSpec.Privileges = &types.Privileges{}
+		}
+		containerSpec.Privileges.Seccomp = profiles.Seccomp
+		containerSpec.Privileges.AppArmor = profiles.AppArmor
+	}
+	if _, ok := c.Labels[SecurityProfilesLabel]; ok {
+		containerSpec.Labels = withoutLabel(c.Labels, SecurityProfilesLabel)
+	}
 
 	// Mounts
 	for _, m := range c.Mounts {
@@ -100,10 +161,7 @@ func containerSpecFromGRPC(c *swarmapi.ContainerSpec) *types.ContainerSpec {
 		}
 
 		if m.TmpfsOptions != nil {
-			mount.TmpfsOptions = &mounttypes.TmpfsOptions{
-				SizeBytes: m.TmpfsOptions.SizeBytes,
-				Mode:      m.TmpfsOptions.Mode,
-			}
+			mount.TmpfsOptions = TmpfsOptionsFromGRPC(m.TmpfsOptions)
 		}
 		containerSpec.Mounts = append(containerSpec.Mounts, mount)
 	}
@@ -306,6 +364,22 @@ func containerToGRPC(c *types.ContainerSpec) (*swarmapi.ContainerSpec, error) {
 		}
 	}
 
+	if _, ok := c.Labels[SecurityProfilesLabel]; ok {
+		containerSpec.Labels = withoutLabel(c.Labels, SecurityProfilesLabel)
+	}
+	profiles, err := securityProfilesToGRPC(c.Privileges)
+	if err != nil {
+		return nil, err
+	}
+	if profiles != "" {
+		labels := make(map[string]string, len(containerSpec.Labels)+1)
+		for k, v := range containerSpec.Labels {
+			labels[k] = v
+		}
+		labels[SecurityProfilesLabel] = profiles
+		containerSpec.Labels = labels
+	}
+
 	if c.Configs != nil {
 		configs, err := configReferencesToGRPC(c.Configs)
 		if err != nil {
@@ -358,10 +432,7 @@ func containerToGRPC(c *types.ContainerSpec) (*swarmapi.ContainerSpec, error) {
 		}
 
 		if m.TmpfsOptions != nil {
-			mount.TmpfsOptions = &swarmapi.Mount_TmpfsOptions{
-				SizeBytes: m.TmpfsOptions.SizeBytes,
-				Mode:      m.TmpfsOptions.Mode,
-			}
+			mount.TmpfsOptions = tmpfsOptionsToGRPC(m.TmpfsOptions)
 		}
 
 		containerSpec.Mounts = append(containerSpec.Mounts, mount)
@@ -0,0 +1,53 @@
+package convert // import "github.com/docker/docker/daemon/cluster/convert"
+
+import (
+	"encoding/json"
+	"fmt"
+
+	types "github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/pkg/cron"
+	swarmapi "github.com/moby/swarmkit/v2/api"
+)
+
+// jobScheduleLabel is the service label the schedule of a job is stored in,
+// as swarmkit has no notion of periodic jobs. The label is not visible
+// through the API.
+const jobScheduleLabel = "com.docker.swarm.job.schedule"
+
+// JobScheduleFromGRPC returns the schedule of a job, or nil if the service
+// is not a scheduled job.
+func JobScheduleFromGRPC(spec *swarmapi.ServiceSpec) (*types.JobSchedule, error) {
+	if spec.GetReplicatedJob() == nil && spec.GetGlobalJob() == nil {
+		return nil, nil
+	}
+	v, ok := spec.Annotations.Labels[jobScheduleLabel]
+	if !ok {
+		return nil, nil
+	}
+	var schedule types.JobSchedule
+	if err := json.Unmarshal([]byte(v), &schedule); err != nil {
+		return nil, fmt.Errorf("invalid job schedule: %v", err)
+	}
+	return &schedule, nil
+}
+
+// jobScheduleToGRPC validates the schedule of a job, and returns it encoded
+// as the value of the schedule label.
+func jobScheduleToGRPC(s *types.JobSchedule) (string, error) {
+	if s == nil {
+		return "", nil
+	}
+	if _, err := cron.Parse(s.Cron); err != nil {
+		return "", err
+	}
+	switch s.ConcurrencyPolicy {
+	case "", types.JobConcurrencyReplace, types.JobConcurrencyForbid:
+	default:
+		return "", fmt.Errorf("invalid job concurrency policy: %q", s.ConcurrencyPolicy)
+	}
+	v, err := json.Marshal(s)
+	if err != nil {
+		return "", err
+	}
+	return string(v), nil
+}
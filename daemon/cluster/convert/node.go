@@ -1,8 +1,10 @@
 package convert // import "github.com/docker/docker/daemon/cluster/convert"
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	types "github.com/docker/docker/api/types/swarm"
 	gogotypes "github.com/gogo/protobuf/types"
@@ -32,6 +34,17 @@ func NodeFromGRPC(n swarmapi.Node) types.Node {
 	// Annotations
 	node.Spec.Annotations = annotationsFromGRPC(n.Spec.Annotations)
 
+	if d := NodeDrainFromGRPC(n.Spec); d != nil {
+		node.Spec.Availability = types.NodeAvailabilityDrain
+		node.Spec.DrainOptions = &types.NodeDrainOptions{MaxDuration: d.MaxDuration}
+		node.Status.Drain = &types.NodeDrainStatus{
+			State:       d.State,
+			StartedAt:   d.StartedAt,
+			CompletedAt: d.CompletedAt,
+		}
+		node.Spec.Labels = withoutLabel(node.Spec.Labels, nodeDrainLabel)
+	}
+
 	// Description
 	if n.Description != nil {
 		node.Description.Hostname = n.Description.Hostname
@@ -102,5 +115,88 @@ func NodeSpecToGRPC(s types.NodeSpec) (swarmapi.NodeSpec, error) {
 		return swarmapi.NodeSpec{}, fmt.Errorf("invalid Availability: %q", s.Availability)
 	}
 
+	if _, ok := spec.Annotations.Labels[nodeDrainLabel]; ok {
+		spec.Annotations.Labels = withoutLabel(spec.Annotations.Labels, nodeDrainLabel)
+	}
+	if s.DrainOptions != nil {
+		if s.Availability != types.NodeAvailabilityDrain {
+			return swarmapi.NodeSpec{}, fmt.Errorf("drain options can only be set with Availability %q", types.NodeAvailabilityDrain)
+		}
+		if s.DrainOptions.MaxDuration < 0 {
+			return swarmapi.NodeSpec{}, fmt.Errorf("invalid drain MaxDuration: %s", s.DrainOptions.MaxDuration)
+		}
+		if err := SetNodeDrain(&spec, &NodeDrain{
+			MaxDuration: s.DrainOptions.MaxDuration,
+			State:       types.NodeDrainStateDraining,
+		}); err != nil {
+			return swarmapi.NodeSpec{}, err
+		}
+	}
+
 	return spec, nil
 }
+
+// nodeDrainLabel is the node label the state of a gradual drain is stored in,
+// as swarmkit has no notion of gradual drains. The label is not visible
+// through the API.
+const nodeDrainLabel = "com.docker.swarm.drain"
+
+// NodeDrain is the state of the gradual drain of a node.
+type NodeDrain struct {
+	MaxDuration time.Duration `json:",omitempty"`
+	State       types.NodeDrainState
+	StartedAt   time.Time  `json:",omitempty"`
+	CompletedAt *time.Time `json:",omitempty"`
+
+	// Services are the IDs of the services of which tasks were removed
+	// from the node.
+	Services []string `json:",omitempty"`
+}
+
+// NodeDrainFromGRPC returns the state of the gradual drain of a node, or nil
+// if the node is not drained gradually.
+func NodeDrainFromGRPC(spec swarmapi.NodeSpec) *NodeDrain {
+	v, ok := spec.Annotations.Labels[nodeDrainLabel]
+	if !ok {
+		return nil
+	}
+	var d NodeDrain
+	if err := json.Unmarshal([]byte(v), &d); err != nil {
+		return nil
+	}
+	return &d
+}
+
+// SetNodeDrain sets the state of the gradual drain of a node. While the node
+// is draining, its availability is "pause", so that no new tasks are
+// scheduled to it; once the drain completes, its availability is "drain".
+func SetNodeDrain(spec *swarmapi.NodeSpec, d *NodeDrain) error {
+	v, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	labels := make(map[string]string, len(spec.Annotations.Labels)+1)
+	for k, v := range spec.Annotations.Labels {
+		labels[k] = v
+	}
+	labels[nodeDrainLabel] = string(v)
+	spec.Annotations.Labels = labels
+
+	if d.State == types.NodeDrainStateDraining {
+		spec.Availability = swarmapi.NodeAvailabilityPause
+	} else {
+		spec.Availability = swarmapi.NodeAvailabilityDrain
+	}
+	return nil
+}
+
+// withoutLabel returns a copy of labels without the given label.
+func withoutLabel(labels map[string]string, label string) map[string]string {
+	out := make(map[string]string, len(labels))
+	for k, v := range labels {
+		if k != label {
+			out[k] = v
+		}
+	}
+	return out
+}
@@ -0,0 +1,70 @@
+package convert // import "github.com/docker/docker/daemon/cluster/convert"
+
+import (
+	"testing"
+	"time"
+
+	types "github.com/docker/docker/api/types/swarm"
+	swarmapi "github.com/moby/swarmkit/v2/api"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestNodeDrainToGRPC(t *testing.T) {
+	spec, err := NodeSpecToGRPC(types.NodeSpec{
+		Annotations:  types.Annotations{Labels: map[string]string{"foo": "bar"}},
+		Role:         types.NodeRoleWorker,
+		Availability: types.NodeAvailabilityDrain,
+		DrainOptions: &types.NodeDrainOptions{MaxDuration: time.Minute},
+	})
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(spec.Availability, swarmapi.NodeAvailabilityPause))
+
+	d := NodeDrainFromGRPC(spec)
+	assert.Assert(t, d != nil)
+	assert.Check(t, is.Equal(d.MaxDuration, time.Minute))
+	assert.Check(t, is.Equal(d.State, types.NodeDrainStateDraining))
+
+	node := NodeFromGRPC(swarmapi.Node{Spec: spec})
+	assert.Check(t, is.Equal(node.Spec.Availability, types.NodeAvailabilityDrain))
+	assert.Check(t, is.DeepEqual(node.Spec.DrainOptions, &types.NodeDrainOptions{MaxDuration: time.Minute}))
+	assert.Check(t, is.DeepEqual(node.Spec.Labels, map[string]string{"foo": "bar"}))
+	assert.Assert(t, node.Status.Drain != nil)
+	assert.Check(t, is.Equal(node.Status.Drain.State, types.NodeDrainStateDraining))
+
+	now := time.Now().UTC()
+	d.State = types.NodeDrainStateComplete
+	d.CompletedAt = &now
+	assert.NilError(t, SetNodeDrain(&spec, d))
+	assert.Check(t, is.Equal(spec.Availability, swarmapi.NodeAvailabilityDrain))
+
+	node = NodeFromGRPC(swarmapi.Node{Spec: spec})
+	assert.Check(t, is.Equal(node.Status.Drain.State, types.NodeDrainStateComplete))
+}
+
+func TestNodeDrainToGRPCInvalid(t *testing.T) {
+	_, err := NodeSpecToGRPC(types.NodeSpec{
+		Role:         types.NodeRoleWorker,
+		Availability: types.NodeAvailabilityActive,
+		DrainOptions: &types.NodeDrainOptions{},
+	})
+	assert.Check(t, is.ErrorContains(err, "drain options can only be set"))
+
+	_, err = NodeSpecToGRPC(types.NodeSpec{
+		Role:         types.NodeRoleWorker,
+		Availability: types.NodeAvailabilityDrain,
+		DrainOptions: &types.NodeDrainOptions{MaxDuration: -time.Second},
+	})
+	assert.Check(t, is.ErrorContains(err, "invalid drain MaxDuration"))
+}
+
+func TestNodeDrainLabelNotSettable(t *testing.T) {
+	spec, err := NodeSpecToGRPC(types.NodeSpec{
+		Annotations:  types.Annotations{Labels: map[string]string{nodeDrainLabel: "{}"}},
+		Role:         types.NodeRoleWorker,
+		Availability: types.NodeAvailabilityDrain,
+	})
+	assert.NilError(t, err)
+	assert.Check(t, NodeDrainFromGRPC(spec) == nil)
+	assert.Check(t, is.Equal(spec.Availability, swarmapi.NodeAvailabilityDrain))
+}
@@ -0,0 +1,101 @@
+package convert // import "github.com/docker/docker/daemon/cluster/convert"
+
+import (
+	"encoding/json"
+	"fmt"
+
+	types "github.com/docker/docker/api/types/swarm"
+	swarmapi "github.com/moby/swarmkit/v2/api"
+)
+
+// SecurityProfilesLabel is the container label the seccomp and AppArmor
+// profiles of a service are stored in, as swarmkit has no notion of them. It
+// is a label of the container spec, rather than of the service, so that
+// changing the profiles updates the tasks of the service. The label is not
+// visible through the API, and is not set on the containers of the tasks.
+const SecurityProfilesLabel = "com.docker.swarm.privileges.profiles"
+
+// SecurityProfiles are the seccomp and AppArmor profiles of a container.
+type SecurityProfiles struct {
+	Seccomp  *types.SeccompOpts  `json:",omitempty"`
+	AppArmor *types.AppArmorOpts `json:",omitempty"`
+}
+
+// SecurityProfilesFromGRPC returns the seccomp and AppArmor profiles of a
+// container spec, or nil if it has none.
+func SecurityProfilesFromGRPC(c *swarmapi.ContainerSpec) (*SecurityProfiles, error) {
+	v, ok := c.Labels[SecurityProfilesLabel]
+	if !ok {
+		return nil, nil
+	}
+	var p SecurityProfiles
+	if err := json.Unmarshal([]byte(v), &p); err != nil {
+		return nil, fmt.Errorf("invalid security profiles: %v", err)
+	}
+	return &p, nil
+}
+
+func securityProfilesToGRPC(p *types.Privileges) (string, error) {
+	if p == nil || (p.Seccomp == nil && p.AppArmor == nil) {
+		return "", nil
+	}
+	if s := p.Seccomp; s != nil {
+		switch s.Mode {
+		case types.SeccompModeDefault, types.SeccompModeUnconfined:
+			if len(s.Profile) != 0 {
+				return "", fmt.Errorf("a seccomp profile can only be set with seccomp mode %q", types.SeccompModeCustom)
+			}
+		case types.SeccompModeCustom:
+			if len(s.Profile) == 0 {
+				return "", fmt.Errorf("seccomp mode %q requires a profile", types.SeccompModeCustom)
+			}
+			if !json.Valid(s.Profile) {
+				return "", fmt.Errorf("invalid seccomp profile: not valid JSON")
+			}
+		default:
+			return "", fmt.Errorf("invalid seccomp mode: %q", s.Mode)
+		}
+	}
+	if a := p.AppArmor; a != nil {
+		switch a.Mode {
+		case types.AppArmorModeDefault, types.AppArmorModeDisabled:
+			if a.Profile != "" {
+				return "", fmt.Errorf("an AppArmor profile can only be set with AppArmor mode %q", types.AppArmorModeCustom)
+			}
+		case types.AppArmorModeCustom:
+			if a.Profile == "" {
+				return "", fmt.Errorf("AppArmor mode %q requires a profile", types.AppArmorModeCustom)
+			}
+		default:
+			return "", fmt.Errorf("invalid AppArmor mode: %q", a.Mode)
+		}
+	}
+	v, err := json.Marshal(SecurityProfiles{Seccomp: p.Seccomp, AppArmor: p.AppArmor})
+	if err != nil {
+		return "", err
+	}
+	return string(v), nil
+}
+
+// SecurityOpts returns the security options that apply the profiles to a
+// container.
+func (p *SecurityProfiles) SecurityOpts() []string {
+	var opts []string
+	if s := p.Seccomp; s != nil {
+		switch s.Mode {
+		case types.SeccompModeUnconfined:
+			opts = append(opts, "seccomp=unconfined")
+		case types.SeccompModeCustom:
+			opts = append(opts, "seccomp="+string(s.Profile))
+		}
+	}
+	if a := p.AppArmor; a != nil {
+		switch a.Mode {
+		case types.AppArmorModeDisabled:
+			opts = append(opts, "apparmor=unconfined")
+		case types.AppArmorModeCustom:
+			opts = append(opts, "apparmor="+a.Profile)
+		}
+	}
+	return opts
+}
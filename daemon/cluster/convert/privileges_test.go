@@ -0,0 +1,64 @@
+package convert // import "github.com/docker/docker/daemon/cluster/convert"
+
+import (
+	"testing"
+
+	swarmtypes "github.com/docker/docker/api/types/swarm"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestSecurityProfilesRoundTrip(t *testing.T) {
+	spec := &swarmtypes.ContainerSpec{
+		Labels: map[string]string{"foo": "bar"},
+		Privileges: &swarmtypes.Privileges{
+			Seccomp:  &swarmtypes.SeccompOpts{Mode: swarmtypes.SeccompModeCustom, Profile: []byte(`{"defaultAction":"SCMP_ACT_ERRNO"}`)},
+			AppArmor: &swarmtypes.AppArmorOpts{Mode: swarmtypes.AppArmorModeDisabled},
+		},
+	}
+	grpcSpec, err := containerToGRPC(spec)
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(grpcSpec.Labels, 2))
+
+	profiles, err := SecurityProfilesFromGRPC(grpcSpec)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(profiles.SecurityOpts(), []string{
+		`seccomp={"defaultAction":"SCMP_ACT_ERRNO"}`,
+		"apparmor=unconfined",
+	}))
+
+	c := containerSpecFromGRPC(grpcSpec)
+	assert.Check(t, is.DeepEqual(c.Labels, map[string]string{"foo": "bar"}))
+	assert.Check(t, is.DeepEqual(c.Privileges, spec.Privileges))
+}
+
+func TestSecurityProfilesToGRPCInvalid(t *testing.T) {
+	for _, tc := range []struct {
+		privileges swarmtypes.Privileges
+		err        string
+	}{
+		{
+			privileges: swarmtypes.Privileges{Seccomp: &swarmtypes.SeccompOpts{Mode: swarmtypes.SeccompModeCustom}},
+			err:        "requires a profile",
+		},
+		{
+			privileges: swarmtypes.Privileges{Seccomp: &swarmtypes.SeccompOpts{Mode: swarmtypes.SeccompModeCustom, Profile: []byte("{")}},
+			err:        "not valid JSON",
+		},
+		{
+			privileges: swarmtypes.Privileges{Seccomp: &swarmtypes.SeccompOpts{Mode: swarmtypes.SeccompModeDefault, Profile: []byte("{}")}},
+			err:        "can only be set with seccomp mode",
+		},
+		{
+			privileges: swarmtypes.Privileges{AppArmor: &swarmtypes.AppArmorOpts{Mode: "bogus"}},
+			err:        "invalid AppArmor mode",
+		},
+		{
+			privileges: swarmtypes.Privileges{AppArmor: &swarmtypes.AppArmorOpts{Mode: swarmtypes.AppArmorModeCustom}},
+			err:        "requires a profile",
+		},
+	} {
+		_, err := containerToGRPC(&swarmtypes.ContainerSpec{Privileges: &tc.privileges})
+		assert.Check(t, is.ErrorContains(err, tc.err))
+	}
+}
@@ -0,0 +1,95 @@
+package convert // import "github.com/docker/docker/daemon/cluster/convert"
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	types "github.com/docker/docker/api/types/swarm"
+	swarmapi "github.com/moby/swarmkit/v2/api"
+)
+
+// latestReferencesLabel is the service label that records which secret and
+// config references of a service follow the latest version of the secret or
+// config, as swarmkit has no notion of versions. References are identified by
+// the name of their file target.
+const latestReferencesLabel = "com.docker.swarm.rotation.latest"
+
+// LatestReferences are the secret and config references of a service that
+// follow the latest version of the secret or config, identified by the name
+// of their file target.
+type LatestReferences struct {
+	Secrets []string `json:",omitempty"`
+	Configs []string `json:",omitempty"`
+}
+
+// LatestReferencesFromGRPC returns the secret and config references of a
+// service that follow the latest version of the secret or config.
+func LatestReferencesFromGRPC(spec *swarmapi.ServiceSpec) (LatestReferences, error) {
+	var latest LatestReferences
+	v, ok := spec.Annotations.Labels[latestReferencesLabel]
+	if !ok {
+		return latest, nil
+	}
+	if err := json.Unmarshal([]byte(v), &latest); err != nil {
+		return latest, fmt.Errorf("invalid latest references: %v", err)
+	}
+	return latest, nil
+}
+
+func latestReferencesToGRPC(c *types.ContainerSpec) (string, error) {
+	if c == nil {
+		return "", nil
+	}
+	var latest LatestReferences
+	for _, ref := range c.Secrets {
+		if ref.Latest {
+			if ref.File == nil {
+				return "", errors.New("only secret references with a file target can follow the latest version")
+			}
+			latest.Secrets = append(latest.Secrets, ref.File.Name)
+		}
+	}
+	for _, ref := range c.Configs {
+		if ref.Latest {
+			if ref.File == nil {
+				return "", errors.New("only config references with a file target can follow the latest version")
+			}
+			latest.Configs = append(latest.Configs, ref.File.Name)
+		}
+	}
+	if len(latest.Secrets) == 0 && len(latest.Configs) == 0 {
+		return "", nil
+	}
+	v, err := json.Marshal(latest)
+	if err != nil {
+		return "", err
+	}
+	return string(v), nil
+}
+
+func latestReferencesFromGRPC(spec *swarmapi.ServiceSpec, c *types.ContainerSpec) error {
+	if c == nil {
+		return nil
+	}
+	latest, err := LatestReferencesFromGRPC(spec)
+	if err != nil {
+		return err
+	}
+	for _, ref := range c.Secrets {
+		ref.Latest = ref.File != nil && contains(latest.Secrets, ref.File.Name)
+	}
+	for _, ref := range c.Configs {
+		ref.Latest = ref.File != nil && contains(latest.Configs, ref.File.Name)
+	}
+	return nil
+}
+
+func contains(s []string, v string) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
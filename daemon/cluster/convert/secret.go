@@ -1,8 +1,11 @@
 package convert // import "github.com/docker/docker/daemon/cluster/convert"
 
 import (
+	"encoding/json"
+
 	swarmtypes "github.com/docker/docker/api/types/swarm"
 	types "github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/daemon/secretprovider"
 	gogotypes "github.com/gogo/protobuf/types"
 	swarmapi "github.com/moby/swarmkit/v2/api"
 )
@@ -30,6 +33,13 @@ func SecretFromGRPC(s *swarmapi.Secret) swarmtypes.Secret {
 		}
 	}
 
+	// secrets backed by a secret provider only store a placeholder as data
+	if p, err := secretprovider.FromLabels(s.Spec.Annotations.Labels); err == nil && p != nil {
+		secret.Spec.Provider = p
+		secret.Spec.Labels = withoutLabel(secret.Spec.Labels, secretprovider.Label)
+		secret.Spec.Data = nil
+	}
+
 	return secret
 }
 
@@ -50,10 +60,28 @@ func SecretSpecToGRPC(s swarmtypes.SecretSpec) swarmapi.SecretSpec {
 			Options: s.Templating.Options,
 		}
 	}
+	SetSecretProvider(&spec, s.Provider)
 
 	return spec
 }
 
+// SetSecretProvider sets the secret provider of a grpc SecretSpec, which
+// swarmkit has no notion of, as a label. The managers require secrets to
+// have data, so the provider is stored as data as well. The data of the
+// secret is left untouched if the provider is nil.
+func SetSecretProvider(spec *swarmapi.SecretSpec, p *types.SecretProvider) {
+	if p == nil {
+		if _, ok := spec.Annotations.Labels[secretprovider.Label]; ok {
+			spec.Annotations.Labels = withoutLabel(spec.Annotations.Labels, secretprovider.Label)
+		}
+		return
+	}
+	b, _ := json.Marshal(p)
+	spec.Annotations.Labels = withoutLabel(spec.Annotations.Labels, secretprovider.Label)
+	spec.Annotations.Labels[secretprovider.Label] = string(b)
+	spec.Data = b
+}
+
 // SecretReferencesFromGRPC converts a slice of grpc SecretReference to SecretReference
 func SecretReferencesFromGRPC(s []*swarmapi.SecretReference) []*swarmtypes.SecretReference {
 	refs := []*swarmtypes.SecretReference{}
@@ -0,0 +1,40 @@
+package convert // import "github.com/docker/docker/daemon/cluster/convert"
+
+import (
+	"testing"
+
+	swarmtypes "github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/daemon/secretprovider"
+	swarmapi "github.com/moby/swarmkit/v2/api"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestSecretProviderRoundTrip(t *testing.T) {
+	spec := swarmtypes.SecretSpec{
+		Annotations: swarmtypes.Annotations{Name: "db", Labels: map[string]string{"foo": "bar"}},
+		Provider:    &swarmtypes.SecretProvider{Name: "vault", Options: map[string]string{"path": "secret/data/db"}},
+	}
+
+	grpcSpec := SecretSpecToGRPC(spec)
+	assert.Check(t, is.Contains(grpcSpec.Annotations.Labels, secretprovider.Label))
+	assert.Check(t, len(grpcSpec.Data) > 0, "the managers require secrets to have data")
+	assert.Check(t, is.Len(spec.Labels, 1), "the labels of the spec should not be modified")
+
+	secret := SecretFromGRPC(&swarmapi.Secret{ID: "id", Spec: grpcSpec})
+	assert.Check(t, is.DeepEqual(secret.Spec.Provider, spec.Provider))
+	assert.Check(t, is.DeepEqual(secret.Spec.Labels, map[string]string{"foo": "bar"}))
+	assert.Check(t, is.Len(secret.Spec.Data, 0))
+}
+
+func TestSetSecretProviderRemovesProvider(t *testing.T) {
+	spec := SecretSpecToGRPC(swarmtypes.SecretSpec{
+		Annotations: swarmtypes.Annotations{Name: "db"},
+		Provider:    &swarmtypes.SecretProvider{Name: "vault"},
+	})
+	spec.Data = []byte("password")
+
+	SetSecretProvider(&spec, nil)
+	assert.Check(t, is.Len(spec.Annotations.Labels, 0))
+	assert.Check(t, is.Equal(string(spec.Data), "password"))
+}
@@ -3,9 +3,11 @@ package convert // import "github.com/docker/docker/daemon/cluster/convert"
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	types "github.com/docker/docker/api/types/swarm"
 	"github.com/docker/docker/api/types/swarm/runtime"
+	"github.com/docker/docker/pkg/cron"
 	"github.com/docker/docker/pkg/namesgenerator"
 	"github.com/gogo/protobuf/proto"
 	gogotypes "github.com/gogo/protobuf/types"
@@ -51,6 +53,13 @@ func ServiceFromGRPC(s swarmapi.Service) (types.Service, error) {
 			},
 		}
 		service.JobStatus.LastExecution, _ = gogotypes.TimestampFromProto(s.JobStatus.LastExecution)
+		if schedule := jobSchedule(curSpec.Mode); schedule != nil {
+			if sched, err := cron.Parse(schedule.Cron); err == nil {
+				if next := sched.Next(time.Now().UTC()); !next.IsZero() {
+					service.JobStatus.NextExecution = &next
+				}
+			}
+		}
 	}
 
 	// UpdateStatus
@@ -148,6 +157,10 @@ func serviceSpecFromGRPC(spec *swarmapi.ServiceSpec) (*types.ServiceSpec, error)
 		convertedSpec.Mode.GlobalJob = &types.GlobalJob{}
 	}
 
+	if err := serviceLabelsFromGRPC(spec, convertedSpec); err != nil {
+		return nil, err
+	}
+
 	return convertedSpec, nil
 }
 
@@ -319,6 +332,11 @@ func ServiceSpecToGRPC(s types.ServiceSpec) (swarmapi.ServiceSpec, error) {
 		return swarmapi.ServiceSpec{}, fmt.Errorf("must specify only one service mode")
 	}
 
+	spec.Annotations.Labels, err = serviceLabelsToGRPC(s)
+	if err != nil {
+		return swarmapi.ServiceSpec{}, err
+	}
+
 	if s.Mode.Global != nil {
 		spec.Mode = &swarmapi.ServiceSpec_Global{
 			Global: &swarmapi.GlobalService{},
@@ -360,6 +378,101 @@ func ServiceSpecToGRPC(s types.ServiceSpec) (swarmapi.ServiceSpec, error) {
 	return spec, nil
 }
 
+// reservedServiceLabels are the service labels the engine stores the options
+// of a service in that swarmkit has no notion of. They are not visible through
+// the API.
+var reservedServiceLabels = []string{jobScheduleLabel, latestReferencesLabel}
+
+// serviceLabelsToGRPC returns the labels of a service, including the reserved
+// labels.
+func serviceLabelsToGRPC(s types.ServiceSpec) (map[string]string, error) {
+	schedule, err := jobScheduleToGRPC(jobSchedule(s.Mode))
+	if err != nil {
+		return nil, err
+	}
+	latest, err := latestReferencesToGRPC(s.TaskTemplate.ContainerSpec)
+	if err != nil {
+		return nil, err
+	}
+	reserved := map[string]string{
+		jobScheduleLabel:      schedule,
+		latestReferencesLabel: latest,
+	}
+
+	changed := false
+	for k, v := range reserved {
+		if _, ok := s.Labels[k]; ok || v != "" {
+			changed = true
+		}
+	}
+	if !changed {
+		return s.Labels, nil
+	}
+
+	labels := make(map[string]string, len(s.Labels)+len(reserved))
+	for k, v := range s.Labels {
+		if _, ok := reserved[k]; !ok {
+			labels[k] = v
+		}
+	}
+	for k, v := range reserved {
+		if v != "" {
+			labels[k] = v
+		}
+	}
+	return labels, nil
+}
+
+// serviceLabelsFromGRPC sets the options of a service that are stored in
+// reserved labels, and removes these labels from the spec.
+func serviceLabelsFromGRPC(spec *swarmapi.ServiceSpec, s *types.ServiceSpec) error {
+	changed := false
+	for _, k := range reservedServiceLabels {
+		if _, ok := s.Labels[k]; ok {
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	schedule, err := JobScheduleFromGRPC(spec)
+	if err != nil {
+		return err
+	}
+	switch {
+	case s.Mode.ReplicatedJob != nil:
+		s.Mode.ReplicatedJob.Schedule = schedule
+	case s.Mode.GlobalJob != nil:
+		s.Mode.GlobalJob.Schedule = schedule
+	}
+	if err := latestReferencesFromGRPC(spec, s.TaskTemplate.ContainerSpec); err != nil {
+		return err
+	}
+
+	labels := make(map[string]string, len(s.Labels))
+	for k, v := range s.Labels {
+		labels[k] = v
+	}
+	for _, k := range reservedServiceLabels {
+		delete(labels, k)
+	}
+	s.Labels = labels
+	return nil
+}
+
+// jobSchedule returns the schedule of a job, or nil if the service mode is
+// not a scheduled job.
+func jobSchedule(mode types.ServiceMode) *types.JobSchedule {
+	switch {
+	case mode.ReplicatedJob != nil:
+		return mode.ReplicatedJob.Schedule
+	case mode.GlobalJob != nil:
+		return mode.GlobalJob.Schedule
+	}
+	return nil
+}
+
 func annotationsFromGRPC(ann swarmapi.Annotations) types.Annotations {
 	a := types.Annotations{
 		Name:   ann.Name,
@@ -4,11 +4,13 @@ import (
 	"testing"
 
 	containertypes "github.com/docker/docker/api/types/container"
+	mounttypes "github.com/docker/docker/api/types/mount"
 	swarmtypes "github.com/docker/docker/api/types/swarm"
 	"github.com/docker/docker/api/types/swarm/runtime"
 	google_protobuf3 "github.com/gogo/protobuf/types"
 	swarmapi "github.com/moby/swarmkit/v2/api"
 	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
 )
 
 func TestServiceConvertFromGRPCRuntimeContainer(t *testing.T) {
@@ -611,3 +613,127 @@ func TestServiceConvertToGRPCConfigs(t *testing.T) {
 		})
 	}
 }
+
+func TestServiceConvertJobSchedule(t *testing.T) {
+	historyLimit := uint64(3)
+	s := swarmtypes.ServiceSpec{
+		Annotations: swarmtypes.Annotations{
+			Name:   "job",
+			Labels: map[string]string{"foo": "bar"},
+		},
+		TaskTemplate: swarmtypes.TaskSpec{
+			ContainerSpec: &swarmtypes.ContainerSpec{Image: "alpine:latest"},
+		},
+		Mode: swarmtypes.ServiceMode{
+			GlobalJob: &swarmtypes.GlobalJob{
+				Schedule: &swarmtypes.JobSchedule{
+					Cron:              "*/5 * * * *",
+					ConcurrencyPolicy: swarmtypes.JobConcurrencyForbid,
+					HistoryLimit:      &historyLimit,
+				},
+			},
+		},
+	}
+
+	gs, err := ServiceSpecToGRPC(s)
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(s.Labels, 1), "labels of the original spec must not be modified")
+	assert.Check(t, is.Contains(gs.Annotations.Labels, jobScheduleLabel))
+
+	spec, err := serviceSpecFromGRPC(&gs)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(spec.Labels, map[string]string{"foo": "bar"}))
+	assert.Assert(t, spec.Mode.GlobalJob != nil)
+	assert.Check(t, is.DeepEqual(spec.Mode.GlobalJob.Schedule, s.Mode.GlobalJob.Schedule))
+
+	service, err := ServiceFromGRPC(swarmapi.Service{Spec: gs, JobStatus: &swarmapi.JobStatus{}})
+	assert.NilError(t, err)
+	assert.Assert(t, service.JobStatus.NextExecution != nil)
+	assert.Check(t, is.Equal(service.JobStatus.NextExecution.Minute()%5, 0))
+
+	// removing the schedule removes the label
+	s.Mode.GlobalJob.Schedule = nil
+	s.Labels = spec.Labels
+	s.Labels[jobScheduleLabel] = gs.Annotations.Labels[jobScheduleLabel]
+	gs, err = ServiceSpecToGRPC(s)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(gs.Annotations.Labels, map[string]string{"foo": "bar"}))
+}
+
+func TestServiceConvertJobScheduleInvalid(t *testing.T) {
+	for _, schedule := range []swarmtypes.JobSchedule{
+		{Cron: "* * *"},
+		{Cron: "* * * * *", ConcurrencyPolicy: "allow"},
+	} {
+		schedule := schedule
+		_, err := ServiceSpecToGRPC(swarmtypes.ServiceSpec{
+			TaskTemplate: swarmtypes.TaskSpec{
+				ContainerSpec: &swarmtypes.ContainerSpec{Image: "alpine:latest"},
+			},
+			Mode: swarmtypes.ServiceMode{
+				ReplicatedJob: &swarmtypes.ReplicatedJob{Schedule: &schedule},
+			},
+		})
+		assert.Check(t, err != nil, "expected an error for schedule %+v", schedule)
+	}
+}
+
+func TestServiceConvertLatestReferences(t *testing.T) {
+	s := swarmtypes.ServiceSpec{
+		TaskTemplate: swarmtypes.TaskSpec{
+			ContainerSpec: &swarmtypes.ContainerSpec{
+				Image: "alpine:latest",
+				Secrets: []*swarmtypes.SecretReference{
+					{File: &swarmtypes.SecretReferenceFileTarget{Name: "pinned"}, SecretID: "a", SecretName: "a"},
+					{File: &swarmtypes.SecretReferenceFileTarget{Name: "latest"}, SecretID: "b", SecretName: "b", Latest: true},
+				},
+				Configs: []*swarmtypes.ConfigReference{
+					{File: &swarmtypes.ConfigReferenceFileTarget{Name: "config"}, ConfigID: "c", ConfigName: "c", Latest: true},
+				},
+			},
+		},
+	}
+
+	gs, err := ServiceSpecToGRPC(s)
+	assert.NilError(t, err)
+	latest, err := LatestReferencesFromGRPC(&gs)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(latest, LatestReferences{Secrets: []string{"latest"}, Configs: []string{"config"}}))
+
+	spec, err := serviceSpecFromGRPC(&gs)
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(spec.Labels, 0))
+	assert.Check(t, !spec.TaskTemplate.ContainerSpec.Secrets[0].Latest)
+	assert.Check(t, spec.TaskTemplate.ContainerSpec.Secrets[1].Latest)
+	assert.Check(t, spec.TaskTemplate.ContainerSpec.Configs[0].Latest)
+
+	s.TaskTemplate.ContainerSpec.Configs = []*swarmtypes.ConfigReference{
+		{Runtime: &swarmtypes.ConfigReferenceRuntimeTarget{}, ConfigID: "c", ConfigName: "c", Latest: true},
+	}
+	_, err = ServiceSpecToGRPC(s)
+	assert.Check(t, is.ErrorContains(err, "only config references with a file target"))
+}
+
+func TestServiceConvertTmpfsOptions(t *testing.T) {
+	uid, gid := 1000, 0
+	opts := &mounttypes.TmpfsOptions{SizeBytes: 1 << 20, Mode: 0o700, UID: &uid, GID: &gid, HugePages: "within_size", NoSwap: true}
+	s := swarmtypes.ServiceSpec{
+		TaskTemplate: swarmtypes.TaskSpec{
+			ContainerSpec: &swarmtypes.ContainerSpec{
+				Image:  "alpine:latest",
+				Mounts: []mounttypes.Mount{{Type: mounttypes.TypeTmpfs, Target: "/tmp", TmpfsOptions: opts}},
+			},
+		},
+	}
+
+	gs, err := ServiceSpecToGRPC(s)
+	assert.NilError(t, err)
+	gopts := gs.Task.GetContainer().Mounts[0].TmpfsOptions
+	assert.Check(t, is.Equal(gopts.Options, "uid=1000,gid=0,huge=within_size,noswap"))
+
+	spec, err := serviceSpecFromGRPC(&gs)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(spec.TaskTemplate.ContainerSpec.Mounts[0].TmpfsOptions, opts))
+
+	assert.Check(t, is.DeepEqual(TmpfsOptionsFromGRPC(&swarmapi.Mount_TmpfsOptions{Options: "uid=x,nr_inodes=10"}), &mounttypes.TmpfsOptions{}))
+}
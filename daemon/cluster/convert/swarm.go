@@ -1,8 +1,11 @@
 package convert // import "github.com/docker/docker/daemon/cluster/convert"
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	types "github.com/docker/docker/api/types/swarm"
 	gogotypes "github.com/gogo/protobuf/types"
@@ -78,6 +81,16 @@ func SwarmFromGRPC(c swarmapi.Cluster) types.Swarm {
 	// Annotations
 	swarm.Spec.Annotations = annotationsFromGRPC(c.Spec.Annotations)
 
+	if _, ok := c.Spec.Annotations.Labels[unlockKeyRotationLabel]; ok {
+		rotation := UnlockKeyRotationFromGRPC(c.Spec)
+		swarm.Spec.EncryptionConfig.UnlockKeyRotationInterval = rotation.Interval
+		swarm.UnlockKeyRotatedAt = rotation.RotatedAt
+		if next := rotation.Next(c.Spec.EncryptionConfig.AutoLockManagers, swarm.CreatedAt); !next.IsZero() {
+			swarm.NextUnlockKeyRotation = &next
+		}
+		swarm.Spec.Labels = withoutLabel(swarm.Spec.Labels, unlockKeyRotationLabel)
+	}
+
 	return swarm
 }
 
@@ -94,8 +107,9 @@ func MergeSwarmSpecToGRPC(s types.Spec, spec swarmapi.ClusterSpec) (swarmapi.Clu
 	if s.Annotations.Name != "" {
 		spec.Annotations.Name = s.Annotations.Name
 	}
+	rotation := UnlockKeyRotationFromGRPC(spec)
 	if len(s.Annotations.Labels) != 0 {
-		spec.Annotations.Labels = s.Annotations.Labels
+		spec.Annotations.Labels = withoutLabel(s.Annotations.Labels, unlockKeyRotationLabel)
 	}
 
 	if s.Orchestration.TaskHistoryRetentionLimit != nil {
@@ -146,5 +160,72 @@ func MergeSwarmSpecToGRPC(s types.Spec, spec swarmapi.ClusterSpec) (swarmapi.Clu
 
 	spec.EncryptionConfig.AutoLockManagers = s.EncryptionConfig.AutoLockManagers
 
+	if s.EncryptionConfig.UnlockKeyRotationInterval < 0 {
+		return swarmapi.ClusterSpec{}, fmt.Errorf("invalid UnlockKeyRotationInterval: %s", s.EncryptionConfig.UnlockKeyRotationInterval)
+	}
+	if s.EncryptionConfig.UnlockKeyRotationInterval > 0 && !s.EncryptionConfig.AutoLockManagers {
+		return swarmapi.ClusterSpec{}, errors.New("UnlockKeyRotationInterval requires AutoLockManagers")
+	}
+	rotation.Interval = s.EncryptionConfig.UnlockKeyRotationInterval
+	if err := SetUnlockKeyRotation(&spec, rotation); err != nil {
+		return swarmapi.ClusterSpec{}, err
+	}
+
 	return spec, nil
 }
+
+// unlockKeyRotationLabel is the cluster label the automatic rotation of the
+// unlock key is stored in, as swarmkit has no notion of it. The label is not
+// visible through the API.
+const unlockKeyRotationLabel = "com.docker.swarm.encryption.unlock-key-rotation"
+
+// UnlockKeyRotation is the state of the automatic rotation of the unlock key.
+type UnlockKeyRotation struct {
+	Interval  time.Duration `json:",omitempty"`
+	RotatedAt *time.Time    `json:",omitempty"`
+}
+
+// UnlockKeyRotationFromGRPC returns the state of the automatic rotation of
+// the unlock key of a cluster.
+func UnlockKeyRotationFromGRPC(spec swarmapi.ClusterSpec) UnlockKeyRotation {
+	var rotation UnlockKeyRotation
+	if v, ok := spec.Annotations.Labels[unlockKeyRotationLabel]; ok {
+		_ = json.Unmarshal([]byte(v), &rotation)
+	}
+	return rotation
+}
+
+// SetUnlockKeyRotation sets the state of the automatic rotation of the unlock
+// key of a cluster.
+func SetUnlockKeyRotation(spec *swarmapi.ClusterSpec, rotation UnlockKeyRotation) error {
+	if rotation.Interval == 0 && rotation.RotatedAt == nil {
+		if _, ok := spec.Annotations.Labels[unlockKeyRotationLabel]; ok {
+			spec.Annotations.Labels = withoutLabel(spec.Annotations.Labels, unlockKeyRotationLabel)
+		}
+		return nil
+	}
+	v, err := json.Marshal(rotation)
+	if err != nil {
+		return err
+	}
+	labels := make(map[string]string, len(spec.Annotations.Labels)+1)
+	for k, v := range spec.Annotations.Labels {
+		labels[k] = v
+	}
+	labels[unlockKeyRotationLabel] = string(v)
+	spec.Annotations.Labels = labels
+	return nil
+}
+
+// Next returns the time the unlock key is rotated next, or the zero time if
+// it is not rotated automatically. If the unlock key was never rotated, the
+// interval counts from the creation of the cluster.
+func (r UnlockKeyRotation) Next(autoLock bool, createdAt time.Time) time.Time {
+	if !autoLock || r.Interval <= 0 {
+		return time.Time{}
+	}
+	if r.RotatedAt != nil {
+		return r.RotatedAt.Add(r.Interval)
+	}
+	return createdAt.Add(r.Interval)
+}
@@ -0,0 +1,55 @@
+package convert // import "github.com/docker/docker/daemon/cluster/convert"
+
+import (
+	"testing"
+	"time"
+
+	swarmtypes "github.com/docker/docker/api/types/swarm"
+	gogotypes "github.com/gogo/protobuf/types"
+	swarmapi "github.com/moby/swarmkit/v2/api"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestUnlockKeyRotationSpec(t *testing.T) {
+	spec, err := SwarmSpecToGRPC(swarmtypes.Spec{
+		Annotations: swarmtypes.Annotations{Name: "default", Labels: map[string]string{"foo": "bar"}},
+		EncryptionConfig: swarmtypes.EncryptionConfig{
+			AutoLockManagers:          true,
+			UnlockKeyRotationInterval: time.Hour,
+		},
+	})
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(spec.Annotations.Labels, 2))
+
+	rotatedAt := time.Date(2023, time.March, 1, 10, 0, 0, 0, time.UTC)
+	assert.NilError(t, SetUnlockKeyRotation(&spec, UnlockKeyRotation{Interval: time.Hour, RotatedAt: &rotatedAt}))
+
+	createdAt, _ := gogotypes.TimestampProto(rotatedAt.Add(-24 * time.Hour))
+	swarm := SwarmFromGRPC(swarmapi.Cluster{Spec: spec, Meta: swarmapi.Meta{CreatedAt: createdAt}})
+	assert.Check(t, is.DeepEqual(swarm.Spec.Labels, map[string]string{"foo": "bar"}))
+	assert.Check(t, is.Equal(swarm.Spec.EncryptionConfig.UnlockKeyRotationInterval, time.Hour))
+	assert.Check(t, is.DeepEqual(swarm.UnlockKeyRotatedAt, &rotatedAt))
+	assert.Assert(t, swarm.NextUnlockKeyRotation != nil)
+	assert.Check(t, is.Equal(*swarm.NextUnlockKeyRotation, rotatedAt.Add(time.Hour)))
+
+	// disabling the rotation keeps track of the last rotation
+	spec, err = MergeSwarmSpecToGRPC(swarmtypes.Spec{EncryptionConfig: swarmtypes.EncryptionConfig{AutoLockManagers: true}}, spec)
+	assert.NilError(t, err)
+	swarm = SwarmFromGRPC(swarmapi.Cluster{Spec: spec})
+	assert.Check(t, is.Equal(swarm.Spec.EncryptionConfig.UnlockKeyRotationInterval, time.Duration(0)))
+	assert.Check(t, is.DeepEqual(swarm.UnlockKeyRotatedAt, &rotatedAt))
+	assert.Check(t, swarm.NextUnlockKeyRotation == nil)
+}
+
+func TestUnlockKeyRotationSpecInvalid(t *testing.T) {
+	_, err := SwarmSpecToGRPC(swarmtypes.Spec{
+		EncryptionConfig: swarmtypes.EncryptionConfig{UnlockKeyRotationInterval: time.Hour},
+	})
+	assert.Check(t, is.ErrorContains(err, "requires AutoLockManagers"))
+
+	_, err = SwarmSpecToGRPC(swarmtypes.Spec{
+		EncryptionConfig: swarmtypes.EncryptionConfig{AutoLockManagers: true, UnlockKeyRotationInterval: -time.Hour},
+	})
+	assert.Check(t, is.ErrorContains(err, "invalid UnlockKeyRotationInterval"))
+}
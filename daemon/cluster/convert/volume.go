@@ -17,6 +17,7 @@ func VolumeFromGRPC(v *swarmapi.Volume) volumetypes.Volume {
 		Secrets:                   volumeSecretsFromGRPC(v.Spec.Secrets),
 		Availability:              volumeAvailabilityFromGRPC(v.Spec.Availability),
 	}
+	clusterVolumeSpec.SnapshotSchedule, _ = VolumeSnapshotScheduleFromGRPC(&v.Spec)
 
 	clusterVolume := &volumetypes.ClusterVolume{
 		ID:            v.ID,
@@ -33,7 +34,7 @@ func VolumeFromGRPC(v *swarmapi.Volume) volumetypes.Volume {
 		ClusterVolume: clusterVolume,
 		CreatedAt:     clusterVolume.CreatedAt.String(),
 		Driver:        v.Spec.Driver.Name,
-		Labels:        v.Spec.Annotations.Labels,
+		Labels:        volumeLabelsFromGRPC(v.Spec.Annotations.Labels),
 		Name:          v.Spec.Annotations.Name,
 		Options:       v.Spec.Driver.Options,
 		Scope:         "global",
@@ -144,7 +145,10 @@ func VolumeCreateToGRPC(volume *volumetypes.CreateOptions) *swarmapi.VolumeSpec
 
 	swarmSpec.Annotations = swarmapi.Annotations{
 		Name:   volume.Name,
-		Labels: volume.Labels,
+		Labels: volumeLabelsFromGRPC(volume.Labels),
+	}
+	if volume.ClusterVolumeSpec != nil {
+		SetVolumeSnapshotSchedule(swarmSpec, volume.ClusterVolumeSpec.SnapshotSchedule)
 	}
 
 	swarmSpec.Driver = &swarmapi.Driver{
@@ -0,0 +1,111 @@
+package convert // import "github.com/docker/docker/daemon/cluster/convert"
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	volumetypes "github.com/docker/docker/api/types/volume"
+	gogotypes "github.com/gogo/protobuf/types"
+	swarmapi "github.com/moby/swarmkit/v2/api"
+)
+
+const (
+	// volumeSnapshotScheduleLabel is the volume label the snapshot schedule
+	// of a cluster volume is stored in, as swarmkit has no notion of
+	// snapshots. The label is not visible through the API.
+	volumeSnapshotScheduleLabel = "com.docker.swarm.volume.snapshot-schedule"
+
+	// VolumeSnapshotsKind is the kind of the swarmkit resources the
+	// snapshots taken of cluster volumes are recorded in, one resource per
+	// volume. Unlike labels, resources cannot be modified by updating the
+	// volume.
+	VolumeSnapshotsKind = "com.docker.volume.snapshots"
+)
+
+// reservedVolumeLabels are the volume labels the engine stores the options
+// of a cluster volume in that swarmkit has no notion of.
+var reservedVolumeLabels = []string{volumeSnapshotScheduleLabel}
+
+// VolumeSnapshot is a snapshot of a cluster volume, as recorded in the
+// snapshots resource of the volume.
+type VolumeSnapshot struct {
+	// SnapshotID is the ID of the snapshot given by the CSI plugin.
+	SnapshotID string
+
+	CreatedAt time.Time
+	Size      int64
+
+	// Scheduled is set if the snapshot was taken by the snapshot schedule of
+	// the volume, in which case it is removed once it is no longer retained.
+	Scheduled bool `json:",omitempty"`
+}
+
+// VolumeSnapshotScheduleFromGRPC returns the snapshot schedule of a cluster
+// volume, or nil if the volume has none.
+func VolumeSnapshotScheduleFromGRPC(spec *swarmapi.VolumeSpec) (*volumetypes.SnapshotSchedule, error) {
+	v, ok := spec.Annotations.Labels[volumeSnapshotScheduleLabel]
+	if !ok {
+		return nil, nil
+	}
+	var schedule volumetypes.SnapshotSchedule
+	if err := json.Unmarshal([]byte(v), &schedule); err != nil {
+		return nil, fmt.Errorf("invalid volume snapshot schedule: %v", err)
+	}
+	return &schedule, nil
+}
+
+// SetVolumeSnapshotSchedule sets the snapshot schedule of a cluster volume.
+// The schedule is removed if s is nil or has no cron expression.
+func SetVolumeSnapshotSchedule(spec *swarmapi.VolumeSpec, s *volumetypes.SnapshotSchedule) {
+	if s == nil || s.Cron == "" {
+		if _, ok := spec.Annotations.Labels[volumeSnapshotScheduleLabel]; ok {
+			spec.Annotations.Labels = withoutLabel(spec.Annotations.Labels, volumeSnapshotScheduleLabel)
+		}
+		return
+	}
+	v, _ := json.Marshal(s)
+	spec.Annotations.Labels = withLabel(spec.Annotations.Labels, volumeSnapshotScheduleLabel, string(v))
+}
+
+// VolumeSnapshotsFromGRPC returns the snapshots recorded in the snapshots
+// resource of a cluster volume, by name. The volume has no snapshots if r is
+// nil.
+func VolumeSnapshotsFromGRPC(r *swarmapi.Resource) (map[string]VolumeSnapshot, error) {
+	snapshots := make(map[string]VolumeSnapshot)
+	if r == nil || r.Payload == nil {
+		return snapshots, nil
+	}
+	if err := json.Unmarshal(r.Payload.Value, &snapshots); err != nil {
+		return nil, fmt.Errorf("invalid volume snapshots: %v", err)
+	}
+	return snapshots, nil
+}
+
+// VolumeSnapshotsToGRPC returns the payload of the snapshots resource of a
+// cluster volume.
+func VolumeSnapshotsToGRPC(snapshots map[string]VolumeSnapshot) *gogotypes.Any {
+	v, _ := json.Marshal(snapshots)
+	return &gogotypes.Any{TypeUrl: VolumeSnapshotsKind, Value: v}
+}
+
+// volumeLabelsFromGRPC returns the labels of a cluster volume, without the
+// reserved labels.
+func volumeLabelsFromGRPC(labels map[string]string) map[string]string {
+	for _, k := range reservedVolumeLabels {
+		if _, ok := labels[k]; ok {
+			labels = withoutLabel(labels, k)
+		}
+	}
+	return labels
+}
+
+// withLabel returns a copy of labels, with the given label set.
+func withLabel(labels map[string]string, label, value string) map[string]string {
+	out := make(map[string]string, len(labels)+1)
+	for k, v := range labels {
+		out[k] = v
+	}
+	out[label] = value
+	return out
+}
@@ -0,0 +1,52 @@
+package convert // import "github.com/docker/docker/daemon/cluster/convert"
+
+import (
+	"testing"
+	"time"
+
+	volumetypes "github.com/docker/docker/api/types/volume"
+	swarmapi "github.com/moby/swarmkit/v2/api"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestVolumeSnapshotSchedule(t *testing.T) {
+	labels := map[string]string{"foo": "bar"}
+	spec := VolumeCreateToGRPC(&volumetypes.CreateOptions{
+		Name:   "vol1",
+		Driver: "plug1",
+		Labels: labels,
+		ClusterVolumeSpec: &volumetypes.ClusterVolumeSpec{
+			SnapshotSchedule: &volumetypes.SnapshotSchedule{Cron: "@daily", Retain: 7},
+		},
+	})
+	assert.Check(t, is.Len(labels, 1), "the labels of the request are not modified")
+
+	v := VolumeFromGRPC(&swarmapi.Volume{ID: "vol1id", Spec: *spec})
+	assert.Check(t, is.DeepEqual(v.Labels, labels))
+	assert.Check(t, is.DeepEqual(v.ClusterVolume.Spec.SnapshotSchedule, &volumetypes.SnapshotSchedule{Cron: "@daily", Retain: 7}))
+
+	SetVolumeSnapshotSchedule(spec, &volumetypes.SnapshotSchedule{})
+	schedule, err := VolumeSnapshotScheduleFromGRPC(spec)
+	assert.NilError(t, err)
+	assert.Check(t, is.Nil(schedule))
+	assert.Check(t, is.DeepEqual(spec.Annotations.Labels, labels))
+}
+
+func TestVolumeSnapshots(t *testing.T) {
+	snapshots, err := VolumeSnapshotsFromGRPC(nil)
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(snapshots, 0))
+
+	created := time.Date(2023, time.March, 15, 10, 0, 0, 0, time.UTC)
+	snapshots["snap1"] = VolumeSnapshot{SnapshotID: "csi-snap1", CreatedAt: created, Size: 1024, Scheduled: true}
+	r := &swarmapi.Resource{Kind: VolumeSnapshotsKind, Payload: VolumeSnapshotsToGRPC(snapshots)}
+
+	recorded, err := VolumeSnapshotsFromGRPC(r)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(recorded, snapshots))
+
+	r.Payload.Value = []byte("invalid")
+	_, err = VolumeSnapshotsFromGRPC(r)
+	assert.Check(t, is.ErrorContains(err, "invalid volume snapshots"))
+}
@@ -0,0 +1,189 @@
+package cluster // import "github.com/docker/docker/daemon/cluster"
+
+import (
+	"context"
+	"time"
+
+	types "github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/daemon/cluster/convert"
+	swarmapi "github.com/moby/swarmkit/v2/api"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// A node is drained gradually by pausing it, so that no new tasks are
+// scheduled to it, and removing the tasks of the replicated services on it,
+// which swarmkit then replaces with tasks on other nodes. No more tasks of a
+// service are removed at once than the update parallelism of the service
+// allows to be unavailable. Once the replacements of all tasks are running,
+// or the maximum duration of the drain is exceeded, the node is drained,
+// which stops the tasks of global services and jobs that remain on it.
+
+// drainNodes progresses the gradual drains of nodes.
+func drainNodes(ctx context.Context, client swarmapi.ControlClient, now time.Time) error {
+	nodes, err := client.ListNodes(ctx, &swarmapi.ListNodesRequest{})
+	if err != nil {
+		return err
+	}
+	for _, node := range nodes.Nodes {
+		d := convert.NodeDrainFromGRPC(node.Spec)
+		if d == nil || d.State != types.NodeDrainStateDraining {
+			continue
+		}
+		if err := drainNode(ctx, client, node, d, now); err != nil {
+			logrus.WithError(err).WithField("node", node.ID).Warn("failed to drain node")
+		}
+	}
+	return nil
+}
+
+func drainNode(ctx context.Context, client swarmapi.ControlClient, node *swarmapi.Node, d *convert.NodeDrain, now time.Time) error {
+	if d.MaxDuration > 0 && now.Sub(d.StartedAt) > d.MaxDuration {
+		return updateNodeDrain(ctx, client, node, d, types.NodeDrainStateDeadlineExceeded, now)
+	}
+
+	tasks, err := client.ListTasks(ctx, &swarmapi.ListTasksRequest{
+		Filters: &swarmapi.ListTasksRequest_Filters{NodeIDs: []string{node.ID}},
+	})
+	if err != nil {
+		return err
+	}
+	onNode := make(map[string][]*swarmapi.Task)
+	for _, t := range tasks.Tasks {
+		if t.DesiredState <= swarmapi.TaskStateRunning {
+			onNode[t.ServiceID] = append(onNode[t.ServiceID], t)
+		}
+	}
+
+	remaining := false
+	changed := false
+	for serviceID, serviceTasksOnNode := range onNode {
+		r, err := client.GetService(ctx, &swarmapi.GetServiceRequest{ServiceID: serviceID})
+		if err != nil {
+			if status.Code(err) != codes.NotFound {
+				remaining = true
+			}
+			continue
+		}
+		service := r.Service
+		if service.Spec.GetReplicated() == nil {
+			// tasks of global services and jobs are stopped once the node
+			// is drained.
+			continue
+		}
+		remaining = true
+
+		serviceTasks, err := client.ListTasks(ctx, &swarmapi.ListTasksRequest{
+			Filters: &swarmapi.ListTasksRequest_Filters{ServiceIDs: []string{service.ID}},
+		})
+		if err != nil {
+			return err
+		}
+		evict := tasksToEvict(service, serviceTasks.Tasks, serviceTasksOnNode)
+		if len(evict) > 0 && !contains(d.Services, service.ID) {
+			d.Services = append(d.Services, service.ID)
+			changed = true
+		}
+		for _, t := range evict {
+			if _, err := client.RemoveTask(ctx, &swarmapi.RemoveTaskRequest{TaskID: t.ID}); err != nil {
+				logrus.WithError(err).WithFields(logrus.Fields{"node": node.ID, "task": t.ID}).Warn("failed to remove task from draining node")
+			}
+		}
+	}
+
+	if !remaining {
+		available, err := servicesAvailable(ctx, client, d.Services)
+		if err != nil {
+			return err
+		}
+		if available {
+			return updateNodeDrain(ctx, client, node, d, types.NodeDrainStateComplete, now)
+		}
+	}
+	if changed {
+		return updateNodeDrain(ctx, client, node, d, types.NodeDrainStateDraining, now)
+	}
+	return nil
+}
+
+// tasksToEvict returns the tasks of a replicated service on a draining node
+// that can be removed without exceeding the number of tasks of the service
+// that may be unavailable, which is the update parallelism of the service.
+func tasksToEvict(service *swarmapi.Service, serviceTasks, onNode []*swarmapi.Task) []*swarmapi.Task {
+	budget := uint64(1)
+	if service.Spec.Update != nil {
+		budget = service.Spec.Update.Parallelism
+	}
+	if budget == 0 {
+		return onNode
+	}
+
+	var unavailable uint64
+	if replicas, running := service.Spec.GetReplicated().Replicas, runningTasks(serviceTasks); running < replicas {
+		unavailable = replicas - running
+	}
+	if unavailable >= budget {
+		return nil
+	}
+	n := budget - unavailable
+	if n > uint64(len(onNode)) {
+		n = uint64(len(onNode))
+	}
+	return onNode[:n]
+}
+
+func runningTasks(tasks []*swarmapi.Task) uint64 {
+	var running uint64
+	for _, t := range tasks {
+		if t.DesiredState == swarmapi.TaskStateRunning && t.Status.State == swarmapi.TaskStateRunning {
+			running++
+		}
+	}
+	return running
+}
+
+// servicesAvailable returns whether all desired tasks of the given replicated
+// services are running.
+func servicesAvailable(ctx context.Context, client swarmapi.ControlClient, serviceIDs []string) (bool, error) {
+	for _, id := range serviceIDs {
+		r, err := client.GetService(ctx, &swarmapi.GetServiceRequest{ServiceID: id})
+		if err != nil {
+			if status.Code(err) == codes.NotFound {
+				continue
+			}
+			return false, err
+		}
+		replicated := r.Service.Spec.GetReplicated()
+		if replicated == nil {
+			continue
+		}
+		tasks, err := client.ListTasks(ctx, &swarmapi.ListTasksRequest{
+			Filters: &swarmapi.ListTasksRequest_Filters{ServiceIDs: []string{id}},
+		})
+		if err != nil {
+			return false, err
+		}
+		if runningTasks(tasks.Tasks) < replicated.Replicas {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func updateNodeDrain(ctx context.Context, client swarmapi.ControlClient, node *swarmapi.Node, d *convert.NodeDrain, state types.NodeDrainState, now time.Time) error {
+	d.State = state
+	if state != types.NodeDrainStateDraining {
+		d.CompletedAt = &now
+	}
+	spec := node.Spec.Copy()
+	if err := convert.SetNodeDrain(spec, d); err != nil {
+		return err
+	}
+	_, err := client.UpdateNode(ctx, &swarmapi.UpdateNodeRequest{
+		NodeID:      node.ID,
+		NodeVersion: &node.Meta.Version,
+		Spec:        spec,
+	})
+	return err
+}
@@ -0,0 +1,58 @@
+package cluster // import "github.com/docker/docker/daemon/cluster"
+
+import (
+	"testing"
+
+	swarmapi "github.com/moby/swarmkit/v2/api"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func replicatedService(replicas, parallelism uint64) *swarmapi.Service {
+	return &swarmapi.Service{
+		ID: "service",
+		Spec: swarmapi.ServiceSpec{
+			Mode:   &swarmapi.ServiceSpec_Replicated{Replicated: &swarmapi.ReplicatedService{Replicas: replicas}},
+			Update: &swarmapi.UpdateConfig{Parallelism: parallelism},
+		},
+	}
+}
+
+func runningTask(id string) *swarmapi.Task {
+	return &swarmapi.Task{
+		ID:           id,
+		DesiredState: swarmapi.TaskStateRunning,
+		Status:       swarmapi.TaskStatus{State: swarmapi.TaskStateRunning},
+	}
+}
+
+func TestTasksToEvict(t *testing.T) {
+	a, b, c, d := runningTask("a"), runningTask("b"), runningTask("c"), runningTask("d")
+	all := []*swarmapi.Task{a, b, c, d}
+	onNode := []*swarmapi.Task{a, b, c}
+
+	assert.Check(t, is.Len(tasksToEvict(replicatedService(4, 1), all, onNode), 1))
+	assert.Check(t, is.Len(tasksToEvict(replicatedService(4, 2), all, onNode), 2))
+	assert.Check(t, is.Len(tasksToEvict(replicatedService(4, 5), all, onNode), 3))
+
+	// no parallelism means all tasks may be unavailable at once
+	assert.Check(t, is.Len(tasksToEvict(replicatedService(4, 0), all, onNode), 3))
+
+	// tasks that are not running yet count against the parallelism
+	assert.Check(t, is.Len(tasksToEvict(replicatedService(5, 1), all, onNode), 0))
+	assert.Check(t, is.Len(tasksToEvict(replicatedService(5, 2), all, onNode), 1))
+
+	// swarmkit defaults to a parallelism of 1
+	service := replicatedService(4, 0)
+	service.Spec.Update = nil
+	assert.Check(t, is.Len(tasksToEvict(service, all, onNode), 1))
+}
+
+func TestRunningTasks(t *testing.T) {
+	shutdown := runningTask("b")
+	shutdown.DesiredState = swarmapi.TaskStateShutdown
+	pending := runningTask("c")
+	pending.Status.State = swarmapi.TaskStatePending
+
+	assert.Check(t, is.Equal(runningTasks([]*swarmapi.Task{runningTask("a"), shutdown, pending}), uint64(1)))
+}
@@ -0,0 +1,42 @@
+package cluster // import "github.com/docker/docker/daemon/cluster"
+
+import (
+	"context"
+	"time"
+
+	"github.com/docker/docker/daemon/cluster/convert"
+	gogotypes "github.com/gogo/protobuf/types"
+	swarmapi "github.com/moby/swarmkit/v2/api"
+)
+
+// rotateUnlockKey rotates the unlock key of the managers, if it is rotated
+// automatically and the rotation interval has elapsed.
+func rotateUnlockKey(ctx context.Context, client swarmapi.ControlClient, now time.Time) error {
+	swarm, err := getSwarm(ctx, client)
+	if err != nil {
+		return err
+	}
+	rotation := convert.UnlockKeyRotationFromGRPC(swarm.Spec)
+	createdAt, _ := gogotypes.TimestampFromProto(swarm.Meta.CreatedAt)
+	if !unlockKeyRotationDue(rotation, swarm.Spec.EncryptionConfig.AutoLockManagers, createdAt, now) {
+		return nil
+	}
+
+	rotation.RotatedAt = &now
+	spec := swarm.Spec.Copy()
+	if err := convert.SetUnlockKeyRotation(spec, rotation); err != nil {
+		return err
+	}
+	_, err = client.UpdateCluster(ctx, &swarmapi.UpdateClusterRequest{
+		ClusterID:      swarm.ID,
+		ClusterVersion: &swarm.Meta.Version,
+		Spec:           spec,
+		Rotation:       swarmapi.KeyRotation{ManagerUnlockKey: true},
+	})
+	return err
+}
+
+func unlockKeyRotationDue(rotation convert.UnlockKeyRotation, autoLock bool, createdAt, now time.Time) bool {
+	next := rotation.Next(autoLock, createdAt)
+	return !next.IsZero() && !now.Before(next)
+}
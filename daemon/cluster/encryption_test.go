@@ -0,0 +1,29 @@
+package cluster // import "github.com/docker/docker/daemon/cluster"
+
+import (
+	"testing"
+	"time"
+
+	"github.com/docker/docker/daemon/cluster/convert"
+	"gotest.tools/v3/assert"
+)
+
+func TestUnlockKeyRotationDue(t *testing.T) {
+	createdAt := time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)
+	rotation := convert.UnlockKeyRotation{Interval: 24 * time.Hour}
+
+	assert.Check(t, !unlockKeyRotationDue(rotation, true, createdAt, createdAt.Add(23*time.Hour)))
+	assert.Check(t, unlockKeyRotationDue(rotation, true, createdAt, createdAt.Add(24*time.Hour)))
+
+	// the managers are not locked
+	assert.Check(t, !unlockKeyRotationDue(rotation, false, createdAt, createdAt.Add(48*time.Hour)))
+
+	// the interval counts from the last rotation
+	rotatedAt := createdAt.Add(30 * time.Hour)
+	rotation.RotatedAt = &rotatedAt
+	assert.Check(t, !unlockKeyRotationDue(rotation, true, createdAt, createdAt.Add(48*time.Hour)))
+	assert.Check(t, unlockKeyRotationDue(rotation, true, createdAt, createdAt.Add(54*time.Hour)))
+
+	// no interval
+	assert.Check(t, !unlockKeyRotationDue(convert.UnlockKeyRotation{}, true, createdAt, createdAt.Add(48*time.Hour)))
+}
@@ -239,6 +239,7 @@ func (c *containerConfig) labels() map[string]string {
 	for k, v := range c.spec().Labels {
 		labels[k] = v
 	}
+	delete(labels, convert.SecurityProfilesLabel)
 
 	// we then apply the overrides from the task, which may be set via the
 	// orchestrator.
@@ -356,10 +357,7 @@ func convertMount(m api.Mount) enginemount.Mount {
 	}
 
 	if m.TmpfsOptions != nil {
-		mount.TmpfsOptions = &enginemount.TmpfsOptions{
-			SizeBytes: m.TmpfsOptions.SizeBytes,
-			Mode:      m.TmpfsOptions.Mode,
-		}
+		mount.TmpfsOptions = convert.TmpfsOptionsFromGRPC(m.TmpfsOptions)
 	}
 
 	return mount
@@ -679,6 +677,12 @@ func (c *containerConfig) networkCreateRequest(name string) (clustertypes.Networ
 }
 
 func (c *containerConfig) applyPrivileges(hc *enginecontainer.HostConfig) {
+	if profiles, err := convert.SecurityProfilesFromGRPC(c.spec()); err != nil {
+		logrus.WithError(err).WithField("task.id", c.task.ID).Warn("ignoring the security profiles of the task")
+	} else if profiles != nil {
+		hc.SecurityOpt = append(hc.SecurityOpt, profiles.SecurityOpts()...)
+	}
+
 	privileges := c.spec().Privileges
 	if privileges == nil {
 		return
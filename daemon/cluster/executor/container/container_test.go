@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/daemon/cluster/convert"
 	swarmapi "github.com/moby/swarmkit/v2/api"
 	"gotest.tools/v3/assert"
 )
@@ -139,3 +140,25 @@ func TestCredentialSpecConversion(t *testing.T) {
 		})
 	}
 }
+
+func TestSecurityProfiles(t *testing.T) {
+	task := swarmapi.Task{
+		Spec: swarmapi.TaskSpec{
+			Runtime: &swarmapi.TaskSpec_Container{
+				Container: &swarmapi.ContainerSpec{
+					Labels: map[string]string{
+						"foo":                         "bar",
+						convert.SecurityProfilesLabel: `{"Seccomp":{"Mode":"unconfined"},"AppArmor":{"Mode":"custom","Profile":"my-profile"}}`,
+					},
+				},
+			},
+		},
+	}
+	config := containerConfig{task: &task}
+	assert.DeepEqual(t, []string{"seccomp=unconfined", "apparmor=my-profile"}, config.hostConfig(nil).SecurityOpt)
+
+	labels := config.labels()
+	assert.Equal(t, labels["foo"], "bar")
+	_, ok := labels[convert.SecurityProfilesLabel]
+	assert.Check(t, !ok)
+}
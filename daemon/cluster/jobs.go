@@ -0,0 +1,147 @@
+package cluster // import "github.com/docker/docker/daemon/cluster"
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	types "github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/daemon/cluster/convert"
+	"github.com/docker/docker/pkg/cron"
+	gogotypes "github.com/gogo/protobuf/types"
+	swarmapi "github.com/moby/swarmkit/v2/api"
+	"github.com/sirupsen/logrus"
+)
+
+// jobScheduler executes jobs that have a schedule. Swarmkit executes a job
+// each time its spec is updated, so the scheduler executes a job by
+// restarting it.
+type jobScheduler struct {
+	// skipped records, per service, the last time an execution was skipped
+	// because of the "forbid" concurrency policy.
+	skipped map[string]time.Time
+}
+
+func newJobScheduler() *jobScheduler {
+	return &jobScheduler{skipped: make(map[string]time.Time)}
+}
+
+func (s *jobScheduler) run(ctx context.Context, client swarmapi.ControlClient, now time.Time) error {
+	services, err := client.ListServices(ctx, &swarmapi.ListServicesRequest{})
+	if err != nil {
+		return err
+	}
+	seen := make(map[string]struct{})
+	for _, service := range services.Services {
+		schedule, err := convert.JobScheduleFromGRPC(&service.Spec)
+		if err != nil || schedule == nil || service.JobStatus == nil {
+			continue
+		}
+		seen[service.ID] = struct{}{}
+		if err := s.runJob(ctx, client, service, schedule, now); err != nil {
+			logrus.WithError(err).WithField("service", service.ID).Warn("failed to run scheduled job")
+		}
+	}
+	for id := range s.skipped {
+		if _, ok := seen[id]; !ok {
+			delete(s.skipped, id)
+		}
+	}
+	return nil
+}
+
+func (s *jobScheduler) runJob(ctx context.Context, client swarmapi.ControlClient, service *swarmapi.Service, schedule *types.JobSchedule, now time.Time) error {
+	sched, err := cron.Parse(schedule.Cron)
+	if err != nil {
+		return err
+	}
+	tasks, err := client.ListTasks(ctx, &swarmapi.ListTasksRequest{
+		Filters: &swarmapi.ListTasksRequest_Filters{ServiceIDs: []string{service.ID}},
+	})
+	if err != nil {
+		return err
+	}
+
+	iteration := service.JobStatus.JobIteration.Index
+	if schedule.HistoryLimit != nil {
+		for _, t := range expiredJobTasks(tasks.Tasks, iteration, *schedule.HistoryLimit) {
+			if _, err := client.RemoveTask(ctx, &swarmapi.RemoveTaskRequest{TaskID: t.ID}); err != nil {
+				logrus.WithError(err).WithField("task", t.ID).Warn("failed to remove task of previous job execution")
+			}
+		}
+	}
+
+	last, _ := gogotypes.TimestampFromProto(service.JobStatus.LastExecution)
+	if !jobDue(sched, last, s.skipped[service.ID], now) {
+		return nil
+	}
+	if schedule.ConcurrencyPolicy == types.JobConcurrencyForbid && jobRunning(tasks.Tasks, iteration) {
+		logrus.WithField("service", service.ID).Info("skipping scheduled job execution, as the previous execution is still running")
+		s.skipped[service.ID] = now
+		return nil
+	}
+
+	if err := restartService(ctx, client, service, service.Meta.Version); err != nil {
+		return err
+	}
+	delete(s.skipped, service.ID)
+	return nil
+}
+
+// jobDue returns whether a job with the given schedule must be executed,
+// given the last time it was executed, and the last time an execution was
+// skipped.
+func jobDue(sched *cron.Schedule, last, skipped, now time.Time) bool {
+	if skipped.After(last) {
+		last = skipped
+	}
+	next := sched.Next(last)
+	return !next.IsZero() && !next.After(now)
+}
+
+// jobRunning returns whether any of the tasks of the given job iteration is
+// still running.
+func jobRunning(tasks []*swarmapi.Task, iteration uint64) bool {
+	for _, t := range tasks {
+		if t.JobIteration != nil && t.JobIteration.Index == iteration &&
+			t.DesiredState <= swarmapi.TaskStateCompleted && t.Status.State <= swarmapi.TaskStateRunning {
+			return true
+		}
+	}
+	return false
+}
+
+// expiredJobTasks returns the finished tasks of job iterations older than the
+// current iteration and the limit iterations before it.
+func expiredJobTasks(tasks []*swarmapi.Task, iteration, limit uint64) []*swarmapi.Task {
+	var iterations []uint64
+	seen := make(map[uint64]struct{})
+	for _, t := range tasks {
+		if t.JobIteration == nil || t.JobIteration.Index == iteration {
+			continue
+		}
+		if _, ok := seen[t.JobIteration.Index]; !ok {
+			seen[t.JobIteration.Index] = struct{}{}
+			iterations = append(iterations, t.JobIteration.Index)
+		}
+	}
+	if uint64(len(iterations)) <= limit {
+		return nil
+	}
+	sort.Slice(iterations, func(i, j int) bool { return iterations[i] > iterations[j] })
+	expired := make(map[uint64]struct{})
+	for _, i := range iterations[limit:] {
+		expired[i] = struct{}{}
+	}
+
+	var out []*swarmapi.Task
+	for _, t := range tasks {
+		if t.JobIteration == nil || t.Status.State <= swarmapi.TaskStateRunning {
+			continue
+		}
+		if _, ok := expired[t.JobIteration.Index]; ok {
+			out = append(out, t)
+		}
+	}
+	return out
+}
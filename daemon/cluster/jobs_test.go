@@ -0,0 +1,67 @@
+package cluster // import "github.com/docker/docker/daemon/cluster"
+
+import (
+	"testing"
+	"time"
+
+	"github.com/docker/docker/pkg/cron"
+	swarmapi "github.com/moby/swarmkit/v2/api"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestJobDue(t *testing.T) {
+	sched, err := cron.Parse("0 * * * *")
+	assert.NilError(t, err)
+
+	last := time.Date(2023, time.March, 15, 10, 5, 0, 0, time.UTC)
+	assert.Check(t, !jobDue(sched, last, time.Time{}, last.Add(50*time.Minute)))
+	assert.Check(t, jobDue(sched, last, time.Time{}, last.Add(55*time.Minute)))
+	assert.Check(t, jobDue(sched, last, time.Time{}, last.Add(3*time.Hour)))
+
+	// an execution that was skipped is not retried until the schedule fires again
+	skipped := last.Add(56 * time.Minute)
+	assert.Check(t, !jobDue(sched, last, skipped, last.Add(57*time.Minute)))
+	assert.Check(t, jobDue(sched, last, skipped, last.Add(115*time.Minute)))
+}
+
+func jobTask(id string, iteration uint64, state swarmapi.TaskState) *swarmapi.Task {
+	return &swarmapi.Task{
+		ID:           id,
+		JobIteration: &swarmapi.Version{Index: iteration},
+		DesiredState: swarmapi.TaskStateCompleted,
+		Status:       swarmapi.TaskStatus{State: state},
+	}
+}
+
+func TestJobRunning(t *testing.T) {
+	tasks := []*swarmapi.Task{
+		jobTask("a", 1, swarmapi.TaskStateRunning),
+		jobTask("b", 2, swarmapi.TaskStateCompleted),
+	}
+	assert.Check(t, jobRunning(tasks, 1))
+	assert.Check(t, !jobRunning(tasks, 2))
+
+	tasks[0].DesiredState = swarmapi.TaskStateRemove
+	assert.Check(t, !jobRunning(tasks, 1))
+}
+
+func TestExpiredJobTasks(t *testing.T) {
+	tasks := []*swarmapi.Task{
+		jobTask("1a", 1, swarmapi.TaskStateCompleted),
+		jobTask("1b", 1, swarmapi.TaskStateRunning),
+		jobTask("2a", 2, swarmapi.TaskStateFailed),
+		jobTask("3a", 3, swarmapi.TaskStateCompleted),
+		jobTask("5a", 5, swarmapi.TaskStateCompleted),
+		jobTask("6a", 6, swarmapi.TaskStateRunning),
+	}
+
+	var ids []string
+	for _, task := range expiredJobTasks(tasks, 6, 2) {
+		ids = append(ids, task.ID)
+	}
+	assert.Check(t, is.DeepEqual(ids, []string{"1a", "2a"}))
+
+	assert.Check(t, is.Len(expiredJobTasks(tasks, 6, 4), 0))
+	assert.Check(t, is.Len(expiredJobTasks(tasks, 6, 0), 4))
+}
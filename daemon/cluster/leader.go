@@ -0,0 +1,63 @@
+package cluster // import "github.com/docker/docker/daemon/cluster"
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// leaderTasksInterval is how often the tasks that the engine performs on the
+// swarm leader are run.
+const leaderTasksInterval = 10 * time.Second
+
+// runLeaderTasks runs, until ctx is canceled, the tasks that the engine
+// performs on behalf of swarmkit: executing scheduled jobs, draining nodes
+// gradually, rotating the unlock key, and taking scheduled volume snapshots.
+// The loop runs on every manager, but the tasks are only run on the leader.
+func (n *nodeRunner) runLeaderTasks(ctx context.Context) {
+	jobs := newJobScheduler()
+	ticker := time.NewTicker(leaderTasksInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		state := n.State()
+		if !state.IsActiveManager() {
+			continue
+		}
+		n.runLeaderTasksOnce(ctx, state, jobs)
+	}
+}
+
+func (n *nodeRunner) runLeaderTasksOnce(ctx context.Context, state nodeState, jobs *jobScheduler) {
+	ctx, cancel := context.WithTimeout(ctx, swarmRequestTimeout)
+	defer cancel()
+
+	node, err := getNode(ctx, state.controlClient, state.NodeID())
+	if err != nil {
+		logrus.WithError(err).Debug("failed to get the status of the local node")
+		return
+	}
+	if node.ManagerStatus == nil || !node.ManagerStatus.Leader {
+		return
+	}
+
+	now := time.Now().UTC()
+	if err := jobs.run(ctx, state.controlClient, now); err != nil {
+		logrus.WithError(err).Warn("failed to run scheduled jobs")
+	}
+	if err := drainNodes(ctx, state.controlClient, now); err != nil {
+		logrus.WithError(err).Warn("failed to drain nodes")
+	}
+	if err := rotateUnlockKey(ctx, state.controlClient, now); err != nil {
+		logrus.WithError(err).Warn("failed to rotate the unlock key")
+	}
+	if err := snapshotVolumes(ctx, state.controlClient, n.cluster.config.Backend.PluginGetter(), now); err != nil {
+		logrus.WithError(err).Warn("failed to take scheduled volume snapshots")
+	}
+}
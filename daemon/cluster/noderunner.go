@@ -179,6 +179,7 @@ func (n *nodeRunner) start(conf nodeStartConfig) error {
 
 	go n.handleReadyEvent(ctx, node, n.ready)
 	go n.handleControlSocketChange(ctx, node)
+	go n.runLeaderTasks(ctx)
 
 	return nil
 }
@@ -227,6 +228,15 @@ func (n *nodeRunner) watchClusterEvents(ctx context.Context, conn *grpc.ClientCo
 				Kind:   "config",
 				Action: swarmapi.WatchActionKindCreate | swarmapi.WatchActionKindUpdate | swarmapi.WatchActionKindRemove,
 			},
+			{
+				Kind:   "cluster",
+				Action: swarmapi.WatchActionKindUpdate,
+			},
+			{
+				// tasks are watched to report the rollout of services
+				Kind:   "task",
+				Action: swarmapi.WatchActionKindCreate | swarmapi.WatchActionKindUpdate | swarmapi.WatchActionKindRemove,
+			},
 		},
 		IncludeOldObject: true,
 	})
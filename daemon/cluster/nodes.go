@@ -2,6 +2,7 @@ package cluster // import "github.com/docker/docker/daemon/cluster"
 
 import (
 	"context"
+	"time"
 
 	apitypes "github.com/docker/docker/api/types"
 	types "github.com/docker/docker/api/types/swarm"
@@ -67,11 +68,6 @@ func (c *Cluster) GetNode(input string) (types.Node, error) {
 // UpdateNode updates existing nodes properties.
 func (c *Cluster) UpdateNode(input string, version uint64, spec types.NodeSpec) error {
 	return c.lockedManagerAction(func(_ context.Context, state nodeState) error {
-		nodeSpec, err := convert.NodeSpecToGRPC(spec)
-		if err != nil {
-			return errdefs.InvalidParameter(err)
-		}
-
 		ctx, cancel := c.getRequestContext()
 		defer cancel()
 
@@ -80,6 +76,34 @@ func (c *Cluster) UpdateNode(input string, version uint64, spec types.NodeSpec)
 			return err
 		}
 
+		// the drain options of a drained node are returned by inspect, and
+		// are sent back unchanged by clients updating other fields of the
+		// node, such as its availability.
+		if spec.DrainOptions != nil && spec.Availability != types.NodeAvailabilityDrain {
+			if current := convert.NodeDrainFromGRPC(currentNode.Spec); current != nil && current.MaxDuration == spec.DrainOptions.MaxDuration {
+				spec.DrainOptions = nil
+			}
+		}
+		nodeSpec, err := convert.NodeSpecToGRPC(spec)
+		if err != nil {
+			return errdefs.InvalidParameter(err)
+		}
+
+		if d := convert.NodeDrainFromGRPC(nodeSpec); d != nil {
+			// keep the progress of a gradual drain that is already in
+			// progress or done, so that updating other fields of the node
+			// does not restart it.
+			if current := convert.NodeDrainFromGRPC(currentNode.Spec); current != nil {
+				current.MaxDuration = d.MaxDuration
+				d = current
+			} else {
+				d.StartedAt = time.Now().UTC()
+			}
+			if err := convert.SetNodeDrain(&nodeSpec, d); err != nil {
+				return err
+			}
+		}
+
 		_, err = state.controlClient.UpdateNode(
 			ctx,
 			&swarmapi.UpdateNodeRequest{
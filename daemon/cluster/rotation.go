@@ -0,0 +1,289 @@
+package cluster // import "github.com/docker/docker/daemon/cluster"
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	types "github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/daemon/cluster/convert"
+	"github.com/docker/docker/errdefs"
+	swarmapi "github.com/moby/swarmkit/v2/api"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+)
+
+// Secrets and configs can't be changed once created. Rotating a secret or
+// config creates a new secret or config, which is a new version of it: it is
+// named after the first version, and has the rotation labels set.
+const (
+	// rotationNameLabel is set on the versions of a secret or config created
+	// by rotating it. Its value is the name of the first version.
+	rotationNameLabel = "com.docker.swarm.rotation.name"
+	// rotationVersionLabel is set on the versions of a secret or config
+	// created by rotating it. Its value is the version number; the first
+	// version, which has no rotation labels, is version 1.
+	rotationVersionLabel = "com.docker.swarm.rotation.version"
+)
+
+// versionedObject is a secret or config.
+type versionedObject struct {
+	id          string
+	annotations swarmapi.Annotations
+}
+
+// rotationVersion returns the name of the first version of a secret or
+// config, and its version number.
+func rotationVersion(a swarmapi.Annotations) (string, uint64) {
+	name, ok := a.Labels[rotationNameLabel]
+	if !ok {
+		return a.Name, 1
+	}
+	version, err := strconv.ParseUint(a.Labels[rotationVersionLabel], 10, 64)
+	if err != nil {
+		return name, 1
+	}
+	return name, version
+}
+
+// latestVersion returns the latest version, and the IDs of all versions, of
+// the secret or config of which the first version is called name.
+func latestVersion(name string, objects []versionedObject) (latest versionedObject, version uint64, ids map[string]struct{}) {
+	ids = make(map[string]struct{})
+	for _, o := range objects {
+		n, v := rotationVersion(o.annotations)
+		if n != name {
+			continue
+		}
+		ids[o.id] = struct{}{}
+		if v > version {
+			latest, version = o, v
+		}
+	}
+	return latest, version, ids
+}
+
+// rotatedAnnotations returns the annotations of a new version of a secret or
+// config.
+func rotatedAnnotations(name string, version uint64, labels map[string]string) swarmapi.Annotations {
+	a := swarmapi.Annotations{
+		Name:   fmt.Sprintf("%s.v%d", name, version),
+		Labels: make(map[string]string, len(labels)+2),
+	}
+	for k, v := range labels {
+		a.Labels[k] = v
+	}
+	a.Labels[rotationNameLabel] = name
+	a.Labels[rotationVersionLabel] = strconv.FormatUint(version, 10)
+	return a
+}
+
+func listSecretVersions(ctx context.Context, client swarmapi.ControlClient) ([]versionedObject, error) {
+	r, err := client.ListSecrets(ctx, &swarmapi.ListSecretsRequest{}, grpc.MaxCallRecvMsgSize(defaultRecvSizeForListResponse))
+	if err != nil {
+		return nil, err
+	}
+	objects := make([]versionedObject, 0, len(r.Secrets))
+	for _, s := range r.Secrets {
+		objects = append(objects, versionedObject{id: s.ID, annotations: s.Spec.Annotations})
+	}
+	return objects, nil
+}
+
+func listConfigVersions(ctx context.Context, client swarmapi.ControlClient) ([]versionedObject, error) {
+	r, err := client.ListConfigs(ctx, &swarmapi.ListConfigsRequest{}, grpc.MaxCallRecvMsgSize(defaultRecvSizeForListResponse))
+	if err != nil {
+		return nil, err
+	}
+	objects := make([]versionedObject, 0, len(r.Configs))
+	for _, c := range r.Configs {
+		objects = append(objects, versionedObject{id: c.ID, annotations: c.Spec.Annotations})
+	}
+	return objects, nil
+}
+
+// populateLatestReferences resolves the secret and config references of a
+// service that follow the latest version of the secret or config to that
+// version.
+func (c *Cluster) populateLatestReferences(ctx context.Context, client swarmapi.ControlClient, s *types.ServiceSpec) error {
+	if s.TaskTemplate.ContainerSpec == nil {
+		return nil
+	}
+
+	var secrets []versionedObject
+	for _, ref := range s.TaskTemplate.ContainerSpec.Secrets {
+		if !ref.Latest {
+			continue
+		}
+		input := ref.SecretID
+		if input == "" {
+			input = ref.SecretName
+		}
+		secret, err := getSecret(ctx, client, input)
+		if err != nil {
+			return err
+		}
+		if secrets == nil {
+			if secrets, err = listSecretVersions(ctx, client); err != nil {
+				return err
+			}
+		}
+		name, _ := rotationVersion(secret.Spec.Annotations)
+		latest, _, _ := latestVersion(name, secrets)
+		ref.SecretID, ref.SecretName = latest.id, latest.annotations.Name
+	}
+
+	var configs []versionedObject
+	for _, ref := range s.TaskTemplate.ContainerSpec.Configs {
+		if !ref.Latest {
+			continue
+		}
+		input := ref.ConfigID
+		if input == "" {
+			input = ref.ConfigName
+		}
+		config, err := getConfig(ctx, client, input)
+		if err != nil {
+			return err
+		}
+		if configs == nil {
+			if configs, err = listConfigVersions(ctx, client); err != nil {
+				return err
+			}
+		}
+		name, _ := rotationVersion(config.Spec.Annotations)
+		latest, _, _ := latestVersion(name, configs)
+		ref.ConfigID, ref.ConfigName = latest.id, latest.annotations.Name
+	}
+	return nil
+}
+
+// rolloverServices updates the services for which update returns true. The
+// services are rolled out according to their update config.
+func rolloverServices(ctx context.Context, client swarmapi.ControlClient, update func(c *swarmapi.ContainerSpec, latest convert.LatestReferences) bool) {
+	r, err := client.ListServices(ctx, &swarmapi.ListServicesRequest{}, grpc.MaxCallRecvMsgSize(defaultRecvSizeForListResponse))
+	if err != nil {
+		logrus.WithError(err).Warn("failed to list services to roll over")
+		return
+	}
+	for _, service := range r.Services {
+		latest, err := convert.LatestReferencesFromGRPC(&service.Spec)
+		if err != nil || (len(latest.Secrets) == 0 && len(latest.Configs) == 0) {
+			continue
+		}
+		spec := service.Spec.Copy()
+		ctnr := spec.Task.GetContainer()
+		if ctnr == nil || !update(ctnr, latest) {
+			continue
+		}
+		_, err = client.UpdateService(ctx, &swarmapi.UpdateServiceRequest{
+			ServiceID:      service.ID,
+			ServiceVersion: &service.Meta.Version,
+			Spec:           spec,
+		})
+		if err != nil {
+			logrus.WithError(err).WithField("service", service.ID).Warn("failed to roll over service")
+		}
+	}
+}
+
+// RotateSecret creates a new version of a secret in a managed swarm cluster,
+// and updates the services that follow the latest version of the secret to
+// use it. It returns the ID of the new version.
+func (c *Cluster) RotateSecret(input string, s types.SecretSpec) (string, error) {
+	if err := validateSecretProvider(s); err != nil {
+		return "", errdefs.InvalidParameter(err)
+	}
+	var id string
+	err := c.lockedManagerAction(func(ctx context.Context, state nodeState) error {
+		secret, err := getSecret(ctx, state.controlClient, input)
+		if err != nil {
+			return err
+		}
+		secrets, err := listSecretVersions(ctx, state.controlClient)
+		if err != nil {
+			return err
+		}
+		name, _ := rotationVersion(secret.Spec.Annotations)
+		_, version, ids := latestVersion(name, secrets)
+
+		labels := s.Labels
+		if labels == nil {
+			labels = secret.Spec.Annotations.Labels
+		}
+		secretSpec := convert.SecretSpecToGRPC(s)
+		secretSpec.Annotations = rotatedAnnotations(name, version+1, labels)
+		convert.SetSecretProvider(&secretSpec, s.Provider)
+		r, err := state.controlClient.CreateSecret(ctx, &swarmapi.CreateSecretRequest{Spec: &secretSpec})
+		if err != nil {
+			return err
+		}
+		id = r.Secret.ID
+
+		rolloverServices(ctx, state.controlClient, func(ctnr *swarmapi.ContainerSpec, latest convert.LatestReferences) bool {
+			changed := false
+			for _, ref := range ctnr.Secrets {
+				if _, ok := ids[ref.SecretID]; ok && ref.GetFile() != nil && contains(latest.Secrets, ref.GetFile().Name) {
+					ref.SecretID, ref.SecretName = r.Secret.ID, r.Secret.Spec.Annotations.Name
+					changed = true
+				}
+			}
+			return changed
+		})
+		return nil
+	})
+	return id, err
+}
+
+// RotateConfig creates a new version of a config in a managed swarm cluster,
+// and updates the services that follow the latest version of the config to
+// use it. It returns the ID of the new version.
+func (c *Cluster) RotateConfig(input string, s types.ConfigSpec) (string, error) {
+	var id string
+	err := c.lockedManagerAction(func(ctx context.Context, state nodeState) error {
+		config, err := getConfig(ctx, state.controlClient, input)
+		if err != nil {
+			return err
+		}
+		configs, err := listConfigVersions(ctx, state.controlClient)
+		if err != nil {
+			return err
+		}
+		name, _ := rotationVersion(config.Spec.Annotations)
+		_, version, ids := latestVersion(name, configs)
+
+		labels := s.Labels
+		if labels == nil {
+			labels = config.Spec.Annotations.Labels
+		}
+		configSpec := convert.ConfigSpecToGRPC(s)
+		configSpec.Annotations = rotatedAnnotations(name, version+1, labels)
+		r, err := state.controlClient.CreateConfig(ctx, &swarmapi.CreateConfigRequest{Spec: &configSpec})
+		if err != nil {
+			return err
+		}
+		id = r.Config.ID
+
+		rolloverServices(ctx, state.controlClient, func(ctnr *swarmapi.ContainerSpec, latest convert.LatestReferences) bool {
+			changed := false
+			for _, ref := range ctnr.Configs {
+				if _, ok := ids[ref.ConfigID]; ok && ref.GetFile() != nil && contains(latest.Configs, ref.GetFile().Name) {
+					ref.ConfigID, ref.ConfigName = r.Config.ID, r.Config.Spec.Annotations.Name
+					changed = true
+				}
+			}
+			return changed
+		})
+		return nil
+	})
+	return id, err
+}
+
+func contains(s []string, v string) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
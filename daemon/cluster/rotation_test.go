@@ -0,0 +1,37 @@
+package cluster // import "github.com/docker/docker/daemon/cluster"
+
+import (
+	"testing"
+
+	swarmapi "github.com/moby/swarmkit/v2/api"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestLatestVersion(t *testing.T) {
+	v2 := rotatedAnnotations("foo", 2, map[string]string{"label": "value"})
+	assert.Check(t, is.Equal(v2.Name, "foo.v2"))
+	assert.Check(t, is.Equal(v2.Labels["label"], "value"))
+
+	objects := []versionedObject{
+		{id: "foo-1", annotations: swarmapi.Annotations{Name: "foo"}},
+		{id: "foo-3", annotations: rotatedAnnotations("foo", 3, nil)},
+		{id: "foo-2", annotations: v2},
+		// not a version of foo, despite its name
+		{id: "foo.v4", annotations: swarmapi.Annotations{Name: "foo.v4"}},
+		{id: "bar-2", annotations: rotatedAnnotations("bar", 2, nil)},
+	}
+
+	latest, version, ids := latestVersion("foo", objects)
+	assert.Check(t, is.Equal(latest.id, "foo-3"))
+	assert.Check(t, is.Equal(version, uint64(3)))
+	assert.Check(t, is.DeepEqual(ids, map[string]struct{}{"foo-1": {}, "foo-2": {}, "foo-3": {}}))
+
+	name, version := rotationVersion(objects[2].annotations)
+	assert.Check(t, is.Equal(name, "foo"))
+	assert.Check(t, is.Equal(version, uint64(2)))
+
+	name, version = rotationVersion(objects[3].annotations)
+	assert.Check(t, is.Equal(name, "foo.v4"))
+	assert.Check(t, is.Equal(version, uint64(1)))
+}
@@ -6,12 +6,24 @@ import (
 	apitypes "github.com/docker/docker/api/types"
 	types "github.com/docker/docker/api/types/swarm"
 	"github.com/docker/docker/daemon/cluster/convert"
+	"github.com/docker/docker/daemon/secretprovider"
+	"github.com/docker/docker/errdefs"
 	swarmapi "github.com/moby/swarmkit/v2/api"
+	"github.com/pkg/errors"
 	"google.golang.org/grpc"
 )
 
-// GetSecret returns a secret from a managed swarm cluster
+// GetSecret returns a secret from a managed swarm cluster, or from the local
+// store if the node is not part of a swarm.
 func (c *Cluster) GetSecret(input string) (types.Secret, error) {
+	if store := c.localSecretStore(); store != nil {
+		secret, err := store.GetSecret(input)
+		if err != nil {
+			return types.Secret{}, err
+		}
+		return convert.SecretFromGRPC(secret), nil
+	}
+
 	var secret *swarmapi.Secret
 
 	if err := c.lockedManagerAction(func(ctx context.Context, state nodeState) error {
@@ -27,8 +39,67 @@ func (c *Cluster) GetSecret(input string) (types.Secret, error) {
 	return convert.SecretFromGRPC(secret), nil
 }
 
-// GetSecrets returns all secrets of a managed swarm cluster.
+// GetSecretValue returns the value of a secret of a managed swarm cluster, to
+// be used by builds. The managers never return the data of secrets, so only
+// the value of secrets backed by a secret provider can be fetched, from the
+// secret provider plugin.
+func (c *Cluster) GetSecretValue(input string) ([]byte, error) {
+	var secret *swarmapi.Secret
+
+	if store := c.localSecretStore(); store != nil {
+		s, err := store.GetSecret(input)
+		if err != nil {
+			return nil, err
+		}
+		secret = s
+	} else if err := c.lockedManagerAction(func(ctx context.Context, state nodeState) error {
+		s, err := getSecret(ctx, state.controlClient, input)
+		if err != nil {
+			return err
+		}
+		secret = s
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	p, err := secretprovider.FromLabels(secret.Spec.Annotations.Labels)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return nil, errdefs.InvalidParameter(errors.Errorf("secret %s has no secret provider: only the value of secrets backed by a secret provider can be used", input))
+	}
+	resp, err := secretprovider.Get(c.config.Backend.PluginGetter(), p.Name, secretprovider.Request{
+		SecretName:   secret.Spec.Annotations.Name,
+		SecretLabels: secret.Spec.Annotations.Labels,
+		Options:      p.Options,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Value, nil
+}
+
+// GetSecrets returns all secrets of a managed swarm cluster, or of the local
+// store if the node is not part of a swarm.
 func (c *Cluster) GetSecrets(options apitypes.SecretListOptions) ([]types.Secret, error) {
+	if store := c.localSecretStore(); store != nil {
+		filters, err := newListSecretsFilters(options.Filters)
+		if err != nil {
+			return nil, err
+		}
+		r, err := store.ListSecrets(filters)
+		if err != nil {
+			return nil, err
+		}
+		secrets := make([]types.Secret, 0, len(r))
+		for _, secret := range r {
+			secrets = append(secrets, convert.SecretFromGRPC(secret))
+		}
+		return secrets, nil
+	}
+
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
@@ -61,8 +132,15 @@ func (c *Cluster) GetSecrets(options apitypes.SecretListOptions) ([]types.Secret
 	return secrets, nil
 }
 
-// CreateSecret creates a new secret in a managed swarm cluster.
+// CreateSecret creates a new secret in a managed swarm cluster, or in the
+// local store if the node is not part of a swarm.
 func (c *Cluster) CreateSecret(s types.SecretSpec) (string, error) {
+	if err := validateSecretProvider(s); err != nil {
+		return "", errdefs.InvalidParameter(err)
+	}
+	if store := c.localSecretStore(); store != nil {
+		return store.CreateSecret(convert.SecretSpecToGRPC(s))
+	}
 	var resp *swarmapi.CreateSecretResponse
 	if err := c.lockedManagerAction(func(ctx context.Context, state nodeState) error {
 		secretSpec := convert.SecretSpecToGRPC(s)
@@ -80,8 +158,12 @@ func (c *Cluster) CreateSecret(s types.SecretSpec) (string, error) {
 	return resp.Secret.ID, nil
 }
 
-// RemoveSecret removes a secret from a managed swarm cluster.
+// RemoveSecret removes a secret from a managed swarm cluster, or from the
+// local store if the node is not part of a swarm.
 func (c *Cluster) RemoveSecret(input string) error {
+	if store := c.localSecretStore(); store != nil {
+		return store.RemoveSecret(input)
+	}
 	return c.lockedManagerAction(func(ctx context.Context, state nodeState) error {
 		secret, err := getSecret(ctx, state.controlClient, input)
 		if err != nil {
@@ -97,9 +179,16 @@ func (c *Cluster) RemoveSecret(input string) error {
 	})
 }
 
-// UpdateSecret updates a secret in a managed swarm cluster.
+// UpdateSecret updates a secret in a managed swarm cluster, or in the local
+// store if the node is not part of a swarm.
 // Note: this is not exposed to the CLI but is available from the API only
 func (c *Cluster) UpdateSecret(input string, version uint64, spec types.SecretSpec) error {
+	if err := validateSecretProvider(spec); err != nil {
+		return errdefs.InvalidParameter(err)
+	}
+	if store := c.localSecretStore(); store != nil {
+		return store.UpdateSecret(input, version, convert.SecretSpecToGRPC(spec))
+	}
 	return c.lockedManagerAction(func(ctx context.Context, state nodeState) error {
 		secret, err := getSecret(ctx, state.controlClient, input)
 		if err != nil {
@@ -119,3 +208,22 @@ func (c *Cluster) UpdateSecret(input string, version uint64, spec types.SecretSp
 		return err
 	})
 }
+
+// validateSecretProvider validates the secret provider of a secret spec. The
+// value of secrets backed by a secret provider is fetched by the nodes, so
+// these secrets cannot have data, nor a secret driver.
+func validateSecretProvider(s types.SecretSpec) error {
+	if s.Provider == nil {
+		return nil
+	}
+	if s.Provider.Name == "" {
+		return errors.New("secret provider must have a name")
+	}
+	if len(s.Data) > 0 {
+		return errors.New("secrets with a secret provider cannot have data")
+	}
+	if s.Driver != nil {
+		return errors.New("secrets cannot have both a secret driver and a secret provider")
+	}
+	return nil
+}
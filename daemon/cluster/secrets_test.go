@@ -0,0 +1,19 @@
+package cluster // import "github.com/docker/docker/daemon/cluster"
+
+import (
+	"testing"
+
+	types "github.com/docker/docker/api/types/swarm"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestValidateSecretProvider(t *testing.T) {
+	provider := &types.SecretProvider{Name: "vault"}
+
+	assert.Check(t, validateSecretProvider(types.SecretSpec{Data: []byte("password")}))
+	assert.Check(t, validateSecretProvider(types.SecretSpec{Provider: provider}))
+	assert.Check(t, is.ErrorContains(validateSecretProvider(types.SecretSpec{Provider: &types.SecretProvider{}}), "must have a name"))
+	assert.Check(t, is.ErrorContains(validateSecretProvider(types.SecretSpec{Provider: provider, Data: []byte("password")}), "cannot have data"))
+	assert.Check(t, is.ErrorContains(validateSecretProvider(types.SecretSpec{Provider: provider, Driver: &types.Driver{Name: "vault"}}), "secret driver and a secret provider"))
+}
@@ -187,6 +187,9 @@ func (c *Cluster) CreateService(s swarm.ServiceSpec, encodedAuth string, queryRe
 		if err != nil {
 			return err
 		}
+		if err := c.populateLatestReferences(ctx, state.controlClient, &s); err != nil {
+			return err
+		}
 
 		serviceSpec, err := convert.ServiceSpecToGRPC(s)
 		if err != nil {
@@ -288,6 +291,9 @@ func (c *Cluster) UpdateService(serviceIDOrName string, version uint64, spec swa
 		if err != nil {
 			return err
 		}
+		if err := c.populateLatestReferences(ctx, state.controlClient, &spec); err != nil {
+			return err
+		}
 
 		serviceSpec, err := convert.ServiceSpecToGRPC(spec)
 		if err != nil {
@@ -408,6 +414,32 @@ func (c *Cluster) UpdateService(serviceIDOrName string, version uint64, spec swa
 	return resp, err
 }
 
+// RestartService recreates the tasks of a service in a managed swarm cluster,
+// without changing its spec. The tasks are recreated according to the update
+// config of the service.
+func (c *Cluster) RestartService(input string, version uint64) error {
+	return c.lockedManagerAction(func(ctx context.Context, state nodeState) error {
+		service, err := getService(ctx, state.controlClient, input, false)
+		if err != nil {
+			return err
+		}
+		return restartService(ctx, state.controlClient, service, swarmapi.Version{Index: version})
+	})
+}
+
+// restartService forces an update of a service, which recreates its tasks,
+// or executes it again if the service is a job.
+func restartService(ctx context.Context, client swarmapi.ControlClient, service *swarmapi.Service, version swarmapi.Version) error {
+	spec := service.Spec.Copy()
+	spec.Task.ForceUpdate++
+	_, err := client.UpdateService(ctx, &swarmapi.UpdateServiceRequest{
+		ServiceID:      service.ID,
+		ServiceVersion: &version,
+		Spec:           spec,
+	})
+	return err
+}
+
 // RemoveService removes a service from a managed swarm cluster.
 func (c *Cluster) RemoveService(input string) error {
 	return c.lockedManagerAction(func(ctx context.Context, state nodeState) error {
@@ -0,0 +1,636 @@
+package cluster // import "github.com/docker/docker/daemon/cluster"
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/docker/distribution/reference"
+	apitypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/network"
+	types "github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/daemon/cluster/convert"
+	"github.com/docker/docker/daemon/names"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// stackObjects holds the services, networks, secrets, and configs of a
+// deployed stack, keyed by their full name.
+type stackObjects struct {
+	services map[string]types.Service
+	networks map[string]string
+	secrets  map[string]types.Secret
+	configs  map[string]types.Config
+}
+
+func (o *stackObjects) empty() bool {
+	return len(o.services) == 0 && len(o.networks) == 0 && len(o.secrets) == 0 && len(o.configs) == 0
+}
+
+// DeployStack creates or updates the services, networks, secrets, and
+// configs of a stack to match its spec. Networks, secrets, and configs are
+// created before the services that use them, and services are deployed after
+// the services they depend on. Networks that already exist are left
+// untouched, the labels of existing secrets and configs are updated, and
+// services are only updated if their spec changed.
+func (c *Cluster) DeployStack(spec types.StackSpec, options apitypes.StackDeployOptions) (*types.StackDeployResponse, error) {
+	if err := validateStackSpec(spec); err != nil {
+		return nil, errdefs.InvalidParameter(err)
+	}
+
+	current, err := c.getStackObjects(spec.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &types.StackDeployResponse{}
+	for _, name := range sortedKeys(spec.Networks) {
+		fullName := stackObjectName(spec.Name, name)
+		if _, ok := current.networks[fullName]; ok {
+			continue
+		}
+		id, err := c.CreateNetwork(stackNetworkCreateRequest(spec.Name, fullName, spec.Networks[name]))
+		if err != nil {
+			return resp, errors.Wrapf(err, "failed to create network %s", fullName)
+		}
+		current.networks[fullName] = id
+		resp.Created = append(resp.Created, fullName)
+	}
+	for _, name := range sortedKeys(spec.Secrets) {
+		fullName := stackObjectName(spec.Name, name)
+		s := spec.Secrets[name]
+		s.Name = fullName
+		s.Labels = withStackLabel(s.Labels, spec.Name)
+		if existing, ok := current.secrets[fullName]; ok {
+			// The data of the secret is not returned by the manager, which
+			// rejects the update if it changed.
+			if err := c.UpdateSecret(existing.ID, existing.Version.Index, s); err != nil {
+				return resp, stackObjectUpdateError(err, "secret", fullName)
+			}
+			if !reflect.DeepEqual(existing.Spec.Labels, s.Labels) {
+				resp.Updated = append(resp.Updated, fullName)
+			}
+			continue
+		}
+		id, err := c.CreateSecret(s)
+		if err != nil {
+			return resp, errors.Wrapf(err, "failed to create secret %s", fullName)
+		}
+		current.secrets[fullName] = types.Secret{ID: id}
+		resp.Created = append(resp.Created, fullName)
+	}
+	for _, name := range sortedKeys(spec.Configs) {
+		fullName := stackObjectName(spec.Name, name)
+		s := spec.Configs[name]
+		s.Name = fullName
+		s.Labels = withStackLabel(s.Labels, spec.Name)
+		if existing, ok := current.configs[fullName]; ok {
+			if reflect.DeepEqual(existing.Spec.Labels, s.Labels) && bytes.Equal(existing.Spec.Data, s.Data) {
+				continue
+			}
+			if err := c.UpdateConfig(existing.ID, existing.Version.Index, s); err != nil {
+				return resp, stackObjectUpdateError(err, "config", fullName)
+			}
+			resp.Updated = append(resp.Updated, fullName)
+			continue
+		}
+		id, err := c.CreateConfig(s)
+		if err != nil {
+			return resp, errors.Wrapf(err, "failed to create config %s", fullName)
+		}
+		current.configs[fullName] = types.Config{ID: id}
+		resp.Created = append(resp.Created, fullName)
+	}
+
+	order, err := stackServiceOrder(spec)
+	if err != nil {
+		return resp, errdefs.InvalidParameter(err)
+	}
+	for _, name := range order {
+		service := stackServiceSpec(spec, name)
+		if err := c.resolveStackReferences(&service, current); err != nil {
+			return resp, errors.Wrapf(err, "failed to deploy service %s", service.Name)
+		}
+
+		existing, ok := current.services[service.Name]
+		if !ok {
+			r, err := c.CreateService(service, options.EncodedRegistryAuth, true)
+			if err != nil {
+				return resp, errors.Wrapf(err, "failed to create service %s", service.Name)
+			}
+			resp.Created = append(resp.Created, service.Name)
+			resp.Warnings = append(resp.Warnings, r.Warnings...)
+			continue
+		}
+
+		changed, err := serviceSpecChanged(existing.Spec, service)
+		if err != nil {
+			return resp, errdefs.InvalidParameter(errors.Wrapf(err, "invalid spec for service %s", service.Name))
+		}
+		if !changed {
+			continue
+		}
+		r, err := c.UpdateService(existing.ID, existing.Version.Index, service, apitypes.ServiceUpdateOptions{
+			EncodedRegistryAuth: options.EncodedRegistryAuth,
+			RegistryAuthFrom:    apitypes.RegistryAuthFromSpec,
+		}, true)
+		if err != nil {
+			return resp, errors.Wrapf(err, "failed to update service %s", service.Name)
+		}
+		resp.Updated = append(resp.Updated, service.Name)
+		resp.Warnings = append(resp.Warnings, r.Warnings...)
+	}
+
+	if options.Prune {
+		removed, err := c.pruneStack(spec, current)
+		resp.Removed = removed
+		if err != nil {
+			return resp, err
+		}
+	}
+	return resp, nil
+}
+
+// GetStacks returns the stacks deployed in a managed swarm cluster. A stack
+// is listed if it has at least one service.
+func (c *Cluster) GetStacks() ([]types.Stack, error) {
+	services, err := c.GetServices(apitypes.ServiceListOptions{
+		Filters: filters.NewArgs(filters.Arg("label", types.StackNamespaceLabel)),
+		Status:  true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string][]types.Service)
+	for _, s := range services {
+		name := s.Spec.Labels[types.StackNamespaceLabel]
+		byName[name] = append(byName[name], s)
+	}
+	stacks := make([]types.Stack, 0, len(byName))
+	for _, name := range sortedKeys(byName) {
+		stacks = append(stacks, stackFromServices(name, byName[name]))
+	}
+	return stacks, nil
+}
+
+// GetStack returns the status of a stack.
+func (c *Cluster) GetStack(name string) (types.Stack, error) {
+	current, err := c.getStackObjects(name)
+	if err != nil {
+		return types.Stack{}, err
+	}
+	if current.empty() {
+		return types.Stack{}, errdefs.NotFound(fmt.Errorf("stack %s not found", name))
+	}
+	services := make([]types.Service, 0, len(current.services))
+	for _, s := range current.services {
+		services = append(services, s)
+	}
+	return stackFromServices(name, services), nil
+}
+
+// RemoveStack removes the services, networks, secrets, and configs of a
+// stack.
+func (c *Cluster) RemoveStack(name string) error {
+	current, err := c.getStackObjects(name)
+	if err != nil {
+		return err
+	}
+	if current.empty() {
+		return errdefs.NotFound(fmt.Errorf("stack %s not found", name))
+	}
+	_, err = c.pruneStack(types.StackSpec{Name: name}, current)
+	return err
+}
+
+// getStackObjects returns the objects of a stack, which are the objects
+// labeled with its namespace.
+func (c *Cluster) getStackObjects(stack string) (*stackObjects, error) {
+	args := filters.NewArgs(filters.Arg("label", types.StackNamespaceLabel+"="+stack))
+
+	services, err := c.GetServices(apitypes.ServiceListOptions{Filters: args, Status: true})
+	if err != nil {
+		return nil, err
+	}
+	networks, err := c.GetNetworks(args)
+	if err != nil {
+		return nil, err
+	}
+	secrets, err := c.GetSecrets(apitypes.SecretListOptions{Filters: args})
+	if err != nil {
+		return nil, err
+	}
+	configs, err := c.GetConfigs(apitypes.ConfigListOptions{Filters: args})
+	if err != nil {
+		return nil, err
+	}
+
+	o := &stackObjects{
+		services: make(map[string]types.Service, len(services)),
+		networks: make(map[string]string, len(networks)),
+		secrets:  make(map[string]types.Secret, len(secrets)),
+		configs:  make(map[string]types.Config, len(configs)),
+	}
+	for _, s := range services {
+		o.services[s.Spec.Name] = s
+	}
+	for _, n := range networks {
+		o.networks[n.Name] = n.ID
+	}
+	for _, s := range secrets {
+		o.secrets[s.Spec.Name] = s
+	}
+	for _, s := range configs {
+		o.configs[s.Spec.Name] = s
+	}
+	return o, nil
+}
+
+// resolveStackReferences resolves the networks, secrets, and configs a
+// service refers to, to their IDs, so that the service can be compared to
+// its current spec.
+func (c *Cluster) resolveStackReferences(s *types.ServiceSpec, current *stackObjects) error {
+	for i, n := range s.TaskTemplate.Networks {
+		if id, ok := current.networks[n.Target]; ok {
+			s.TaskTemplate.Networks[i].Target = id
+		} else if nw, err := c.GetNetwork(n.Target); err == nil {
+			s.TaskTemplate.Networks[i].Target = nw.ID
+		}
+	}
+
+	cs := s.TaskTemplate.ContainerSpec
+	if cs == nil {
+		return nil
+	}
+	for _, ref := range cs.Secrets {
+		if ref.SecretID != "" {
+			continue
+		}
+		if secret, ok := current.secrets[ref.SecretName]; ok {
+			ref.SecretID = secret.ID
+			continue
+		}
+		secret, err := c.GetSecret(ref.SecretName)
+		if err != nil {
+			return err
+		}
+		ref.SecretID = secret.ID
+	}
+	for _, ref := range cs.Configs {
+		if ref.ConfigID != "" {
+			continue
+		}
+		if config, ok := current.configs[ref.ConfigName]; ok {
+			ref.ConfigID = config.ID
+			continue
+		}
+		config, err := c.GetConfig(ref.ConfigName)
+		if err != nil {
+			return err
+		}
+		ref.ConfigID = config.ID
+	}
+	return nil
+}
+
+// pruneStack removes the objects of a stack that are not in its spec.
+// Services are removed first, so that the networks, secrets, and configs
+// they use are no longer in use when they are removed.
+func (c *Cluster) pruneStack(spec types.StackSpec, current *stackObjects) ([]string, error) {
+	var (
+		removed []string
+		failed  []string
+	)
+	remove := func(fullName string, fn func(string) error) {
+		if err := fn(fullName); err != nil {
+			logrus.WithError(err).WithField("stack", spec.Name).Errorf("failed to remove %s", fullName)
+			failed = append(failed, fullName)
+			return
+		}
+		removed = append(removed, fullName)
+	}
+
+	for _, fullName := range sortedKeys(current.services) {
+		if _, ok := spec.Services[stackLocalName(spec.Name, fullName)]; !ok {
+			remove(fullName, c.RemoveService)
+		}
+	}
+	for _, fullName := range sortedKeys(current.networks) {
+		if _, ok := spec.Networks[stackLocalName(spec.Name, fullName)]; !ok {
+			remove(fullName, c.RemoveNetwork)
+		}
+	}
+	for _, fullName := range sortedKeys(current.secrets) {
+		if _, ok := spec.Secrets[stackLocalName(spec.Name, fullName)]; !ok {
+			remove(fullName, c.RemoveSecret)
+		}
+	}
+	for _, fullName := range sortedKeys(current.configs) {
+		if _, ok := spec.Configs[stackLocalName(spec.Name, fullName)]; !ok {
+			remove(fullName, c.RemoveConfig)
+		}
+	}
+
+	if len(failed) > 0 {
+		return removed, errors.Errorf("failed to remove some resources from stack %s: %s", spec.Name, strings.Join(failed, ", "))
+	}
+	return removed, nil
+}
+
+func validateStackSpec(spec types.StackSpec) error {
+	if !names.RestrictedNamePattern.MatchString(spec.Name) {
+		return errors.Errorf("invalid stack name %q: only %s are allowed", spec.Name, names.RestrictedNameChars)
+	}
+	if len(spec.Services) == 0 {
+		return errors.New("stack has no services")
+	}
+	for name, s := range spec.Services {
+		if name == "" {
+			return errors.New("stack has a service without a name")
+		}
+		if s.TaskTemplate.ContainerSpec == nil {
+			return errors.Errorf("service %s does not use container tasks", name)
+		}
+	}
+	for name := range spec.DependsOn {
+		if _, ok := spec.Services[name]; !ok {
+			return errors.Errorf("dependencies set for undefined service %s", name)
+		}
+	}
+	_, err := stackServiceOrder(spec)
+	return err
+}
+
+// stackServiceOrder returns the names of the services of a stack in the
+// order they are deployed in. Services are deployed after the services they
+// depend on, and in the order of their names otherwise.
+func stackServiceOrder(spec types.StackSpec) ([]string, error) {
+	const (
+		visiting = iota + 1
+		visited
+	)
+	state := make(map[string]int, len(spec.Services))
+	order := make([]string, 0, len(spec.Services))
+
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		path = append(path, name)
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			return errors.Errorf("circular dependency between services: %s", strings.Join(path, " -> "))
+		}
+		state[name] = visiting
+		deps := append([]string(nil), spec.DependsOn[name]...)
+		sort.Strings(deps)
+		for _, dep := range deps {
+			if _, ok := spec.Services[dep]; !ok {
+				return errors.Errorf("service %s depends on undefined service %s", name, dep)
+			}
+			if err := visit(dep, path); err != nil {
+				return err
+			}
+		}
+		state[name] = visited
+		order = append(order, name)
+		return nil
+	}
+	for _, name := range sortedKeys(spec.Services) {
+		if err := visit(name, nil); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// stackObjectUpdateError returns the error of the update of a secret or
+// config of a stack. The manager only allows updating their labels, so a
+// stack cannot change their data: it must deploy the new data under another
+// name.
+func stackObjectUpdateError(err error, kind, fullName string) error {
+	if status.Code(err) == codes.InvalidArgument {
+		return errdefs.InvalidParameter(errors.Wrapf(err, "failed to update %s %s: the data of a %s cannot be changed, deploy it under another name", kind, fullName, kind))
+	}
+	return errors.Wrapf(err, "failed to update %s %s", kind, fullName)
+}
+
+// stackObjectName returns the full name of an object of a stack.
+func stackObjectName(stack, name string) string {
+	return stack + "_" + name
+}
+
+// stackLocalName returns the name, in the stack, of an object of a stack.
+func stackLocalName(stack, fullName string) string {
+	return strings.TrimPrefix(fullName, stack+"_")
+}
+
+// withStackLabel returns a copy of labels, with the namespace label of the
+// stack set.
+func withStackLabel(labels map[string]string, stack string) map[string]string {
+	l := make(map[string]string, len(labels)+1)
+	for k, v := range labels {
+		l[k] = v
+	}
+	l[types.StackNamespaceLabel] = stack
+	return l
+}
+
+// stackServiceSpec returns the spec of a service of a stack, named and
+// labeled for the stack, and with its references to the networks, secrets,
+// and configs of the stack replaced with their full names.
+func stackServiceSpec(spec types.StackSpec, name string) types.ServiceSpec {
+	s := spec.Services[name]
+	s.Name = stackObjectName(spec.Name, name)
+	s.Labels = withStackLabel(s.Labels, spec.Name)
+
+	// Always prefer NetworkAttachmentConfigs from TaskTemplate
+	// but fallback to service spec for backward compatibility
+	networks := s.TaskTemplate.Networks
+	if len(networks) == 0 {
+		networks = s.Networks
+	}
+	s.TaskTemplate.Networks = make([]types.NetworkAttachmentConfig, len(networks))
+	s.Networks = nil
+	for i, n := range networks {
+		if _, ok := spec.Networks[n.Target]; ok {
+			n.Target = stackObjectName(spec.Name, n.Target)
+		}
+		s.TaskTemplate.Networks[i] = n
+	}
+	if len(s.TaskTemplate.Networks) == 0 {
+		s.TaskTemplate.Networks = nil
+	}
+
+	if s.TaskTemplate.ContainerSpec == nil {
+		return s
+	}
+	cs := *s.TaskTemplate.ContainerSpec
+	cs.Labels = withStackLabel(cs.Labels, spec.Name)
+	cs.Secrets = make([]*types.SecretReference, len(s.TaskTemplate.ContainerSpec.Secrets))
+	for i, ref := range s.TaskTemplate.ContainerSpec.Secrets {
+		r := *ref
+		if _, ok := spec.Secrets[r.SecretName]; ok && r.SecretID == "" {
+			r.SecretName = stackObjectName(spec.Name, r.SecretName)
+		}
+		cs.Secrets[i] = &r
+	}
+	cs.Configs = make([]*types.ConfigReference, len(s.TaskTemplate.ContainerSpec.Configs))
+	for i, ref := range s.TaskTemplate.ContainerSpec.Configs {
+		r := *ref
+		if _, ok := spec.Configs[r.ConfigName]; ok && r.ConfigID == "" {
+			r.ConfigName = stackObjectName(spec.Name, r.ConfigName)
+		}
+		cs.Configs[i] = &r
+	}
+	if len(cs.Secrets) == 0 {
+		cs.Secrets = nil
+	}
+	if len(cs.Configs) == 0 {
+		cs.Configs = nil
+	}
+	s.TaskTemplate.ContainerSpec = &cs
+	return s
+}
+
+// stackNetworkCreateRequest returns the request to create a network of a
+// stack.
+func stackNetworkCreateRequest(stack, fullName string, spec types.NetworkSpec) apitypes.NetworkCreateRequest {
+	req := apitypes.NetworkCreateRequest{
+		Name: fullName,
+		NetworkCreate: apitypes.NetworkCreate{
+			Driver:     "overlay",
+			Scope:      "swarm",
+			EnableIPv6: spec.IPv6Enabled,
+			Internal:   spec.Internal,
+			Attachable: spec.Attachable,
+			ConfigFrom: spec.ConfigFrom,
+			Labels:     withStackLabel(spec.Labels, stack),
+		},
+	}
+	if spec.DriverConfiguration != nil {
+		if spec.DriverConfiguration.Name != "" {
+			req.Driver = spec.DriverConfiguration.Name
+		}
+		req.Options = spec.DriverConfiguration.Options
+	}
+	if spec.IPAMOptions != nil {
+		req.IPAM = &network.IPAM{
+			Driver:  spec.IPAMOptions.Driver.Name,
+			Options: spec.IPAMOptions.Driver.Options,
+		}
+		for _, cfg := range spec.IPAMOptions.Configs {
+			req.IPAM.Config = append(req.IPAM.Config, network.IPAMConfig{
+				Subnet:  cfg.Subnet,
+				IPRange: cfg.Range,
+				Gateway: cfg.Gateway,
+			})
+		}
+	}
+	return req
+}
+
+// serviceSpecChanged returns whether the desired spec of a service differs
+// from its current spec. The image of the current spec is pinned by digest
+// when the service is created or updated, so it is compared to the desired
+// image without its digest, unless the desired image is pinned as well.
+func serviceSpecChanged(current, desired types.ServiceSpec) (bool, error) {
+	if desired.TaskTemplate.ForceUpdate == 0 {
+		desired.TaskTemplate.ForceUpdate = current.TaskTemplate.ForceUpdate
+	}
+	c, err := convert.ServiceSpecToGRPC(current)
+	if err != nil {
+		return false, err
+	}
+	d, err := convert.ServiceSpecToGRPC(desired)
+	if err != nil {
+		return false, err
+	}
+	if cc, dc := c.Task.GetContainer(), d.Task.GetContainer(); cc != nil && dc != nil {
+		cc.PullOptions, dc.PullOptions = nil, nil
+		if sameImage(cc.Image, dc.Image) {
+			dc.Image = cc.Image
+		}
+	}
+	return !reflect.DeepEqual(&c, &d), nil
+}
+
+// sameImage returns whether the current image of a service, which may be
+// pinned by digest, is the desired image.
+func sameImage(current, desired string) bool {
+	if current == desired {
+		return true
+	}
+	desiredRef, err := reference.ParseNormalizedNamed(desired)
+	if err != nil {
+		return false
+	}
+	if _, ok := desiredRef.(reference.Canonical); ok {
+		return false
+	}
+	currentRef, err := reference.ParseNormalizedNamed(current)
+	if err != nil {
+		return false
+	}
+	if _, ok := currentRef.(reference.Canonical); !ok {
+		return false
+	}
+	currentTagged, ok := currentRef.(reference.NamedTagged)
+	if !ok {
+		return false
+	}
+	desiredTagged, ok := reference.TagNameOnly(desiredRef).(reference.NamedTagged)
+	if !ok {
+		return false
+	}
+	return currentTagged.Name() == desiredTagged.Name() && currentTagged.Tag() == desiredTagged.Tag()
+}
+
+// stackFromServices returns the status of a stack from the status of its
+// services. A stack has converged if none of its services is being updated
+// or rolled back, and all their desired tasks are running.
+func stackFromServices(name string, services []types.Service) types.Stack {
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].Spec.Name < services[j].Spec.Name
+	})
+
+	stack := types.Stack{Name: name, Converged: true}
+	for _, s := range services {
+		ss := types.StackService{
+			ID:           s.ID,
+			Name:         s.Spec.Name,
+			UpdateStatus: s.UpdateStatus,
+		}
+		if s.ServiceStatus != nil {
+			ss.RunningTasks = s.ServiceStatus.RunningTasks
+			ss.DesiredTasks = s.ServiceStatus.DesiredTasks
+		}
+		if ss.RunningTasks != ss.DesiredTasks {
+			stack.Converged = false
+		}
+		if s.UpdateStatus != nil && s.UpdateStatus.State != types.UpdateStateCompleted {
+			stack.Converged = false
+		}
+		stack.Services = append(stack.Services, ss)
+	}
+	return stack
+}
+
+// sortedKeys returns the sorted keys of a map, so that the objects of a stack
+// are deployed in a deterministic order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,184 @@
+package cluster // import "github.com/docker/docker/daemon/cluster"
+
+import (
+	"testing"
+
+	types "github.com/docker/docker/api/types/swarm"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestStackServiceSpec(t *testing.T) {
+	spec := types.StackSpec{
+		Name: "app",
+		Services: map[string]types.ServiceSpec{
+			"web": {
+				Annotations: types.Annotations{Labels: map[string]string{"tier": "front"}},
+				TaskTemplate: types.TaskSpec{
+					ContainerSpec: &types.ContainerSpec{
+						Image: "nginx",
+						Secrets: []*types.SecretReference{
+							{SecretName: "cert"},
+							{SecretName: "external"},
+						},
+						Configs: []*types.ConfigReference{{ConfigName: "site"}},
+					},
+					Networks: []types.NetworkAttachmentConfig{{Target: "front"}, {Target: "ingress"}},
+				},
+			},
+		},
+		Networks: map[string]types.NetworkSpec{"front": {}},
+		Secrets:  map[string]types.SecretSpec{"cert": {}},
+		Configs:  map[string]types.ConfigSpec{"site": {}},
+	}
+
+	s := stackServiceSpec(spec, "web")
+	assert.Check(t, is.Equal(s.Name, "app_web"))
+	assert.Check(t, is.DeepEqual(s.Labels, map[string]string{"tier": "front", types.StackNamespaceLabel: "app"}))
+	assert.Check(t, is.Equal(s.TaskTemplate.ContainerSpec.Labels[types.StackNamespaceLabel], "app"))
+	assert.Check(t, is.Equal(s.TaskTemplate.Networks[0].Target, "app_front"))
+	assert.Check(t, is.Equal(s.TaskTemplate.Networks[1].Target, "ingress"))
+	assert.Check(t, is.Equal(s.TaskTemplate.ContainerSpec.Secrets[0].SecretName, "app_cert"))
+	assert.Check(t, is.Equal(s.TaskTemplate.ContainerSpec.Secrets[1].SecretName, "external"))
+	assert.Check(t, is.Equal(s.TaskTemplate.ContainerSpec.Configs[0].ConfigName, "app_site"))
+
+	// the spec of the stack is not modified
+	web := spec.Services["web"]
+	assert.Check(t, is.Len(web.Labels, 1))
+	assert.Check(t, is.Equal(web.TaskTemplate.Networks[0].Target, "front"))
+	assert.Check(t, is.Equal(web.TaskTemplate.ContainerSpec.Secrets[0].SecretName, "cert"))
+}
+
+func TestValidateStackSpec(t *testing.T) {
+	service := types.ServiceSpec{TaskTemplate: types.TaskSpec{ContainerSpec: &types.ContainerSpec{Image: "nginx"}}}
+
+	assert.Check(t, validateStackSpec(types.StackSpec{Name: "app", Services: map[string]types.ServiceSpec{"web": service}}))
+	assert.Check(t, is.ErrorContains(validateStackSpec(types.StackSpec{Name: "my app", Services: map[string]types.ServiceSpec{"web": service}}), "invalid stack name"))
+	assert.Check(t, is.ErrorContains(validateStackSpec(types.StackSpec{Name: "app"}), "no services"))
+	assert.Check(t, is.ErrorContains(validateStackSpec(types.StackSpec{Name: "app", Services: map[string]types.ServiceSpec{"web": {}}}), "does not use container tasks"))
+	assert.Check(t, is.ErrorContains(validateStackSpec(types.StackSpec{
+		Name:      "app",
+		Services:  map[string]types.ServiceSpec{"web": service},
+		DependsOn: map[string][]string{"web": {"db"}},
+	}), "depends on undefined service db"))
+	assert.Check(t, is.ErrorContains(validateStackSpec(types.StackSpec{
+		Name:      "app",
+		Services:  map[string]types.ServiceSpec{"web": service},
+		DependsOn: map[string][]string{"db": {"web"}},
+	}), "dependencies set for undefined service db"))
+}
+
+func TestStackServiceOrder(t *testing.T) {
+	service := types.ServiceSpec{TaskTemplate: types.TaskSpec{ContainerSpec: &types.ContainerSpec{Image: "nginx"}}}
+	spec := types.StackSpec{
+		Name: "app",
+		Services: map[string]types.ServiceSpec{
+			"api":    service,
+			"cache":  service,
+			"db":     service,
+			"web":    service,
+			"worker": service,
+		},
+		DependsOn: map[string][]string{
+			"api":    {"db", "cache"},
+			"web":    {"api"},
+			"worker": {"db"},
+		},
+	}
+	order, err := stackServiceOrder(spec)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(order, []string{"cache", "db", "api", "web", "worker"}))
+
+	spec.DependsOn["db"] = []string{"web"}
+	_, err = stackServiceOrder(spec)
+	assert.Check(t, is.ErrorContains(err, "circular dependency between services: api -> db -> web -> api"))
+}
+
+func TestServiceSpecChanged(t *testing.T) {
+	spec := func(image string) types.ServiceSpec {
+		return types.ServiceSpec{
+			Annotations:  types.Annotations{Name: "app_web"},
+			TaskTemplate: types.TaskSpec{ContainerSpec: &types.ContainerSpec{Image: image}},
+		}
+	}
+	pinned := "nginx:1.25@sha256:4c0fdaa8b6341bfdeca5f18f7837462c80cff90527ee35ef185571e1c327beac"
+
+	changed, err := serviceSpecChanged(spec(pinned), spec("nginx:1.25"))
+	assert.NilError(t, err)
+	assert.Check(t, !changed)
+
+	changed, err = serviceSpecChanged(spec(pinned), spec("nginx:1.26"))
+	assert.NilError(t, err)
+	assert.Check(t, changed)
+
+	current := spec(pinned)
+	current.TaskTemplate.ForceUpdate = 2
+	changed, err = serviceSpecChanged(current, spec("nginx:1.25"))
+	assert.NilError(t, err)
+	assert.Check(t, !changed, "restarting a service should not be undone by a deploy")
+
+	desired := spec("nginx:1.25")
+	desired.Labels = map[string]string{"tier": "front"}
+	changed, err = serviceSpecChanged(spec(pinned), desired)
+	assert.NilError(t, err)
+	assert.Check(t, changed)
+}
+
+func TestSameImage(t *testing.T) {
+	const digest = "@sha256:4c0fdaa8b6341bfdeca5f18f7837462c80cff90527ee35ef185571e1c327beac"
+
+	assert.Check(t, sameImage("nginx:1.25", "nginx:1.25"))
+	assert.Check(t, sameImage("nginx:1.25"+digest, "nginx:1.25"))
+	assert.Check(t, sameImage("nginx:latest"+digest, "nginx"))
+	assert.Check(t, sameImage("nginx:latest"+digest, "docker.io/library/nginx"))
+	assert.Check(t, !sameImage("nginx:1.25"+digest, "nginx:1.26"))
+	assert.Check(t, !sameImage("nginx:1.25", "nginx:1.26"))
+
+	// a desired digest must match exactly
+	assert.Check(t, !sameImage("nginx:1.25"+digest, "nginx@sha256:0000000000000000000000000000000000000000000000000000000000000000"))
+}
+
+func TestStackFromServices(t *testing.T) {
+	service := func(name string, running, desired uint64, update *types.UpdateStatus) types.Service {
+		return types.Service{
+			ID:            name,
+			Spec:          types.ServiceSpec{Annotations: types.Annotations{Name: name}},
+			ServiceStatus: &types.ServiceStatus{RunningTasks: running, DesiredTasks: desired},
+			UpdateStatus:  update,
+		}
+	}
+
+	stack := stackFromServices("app", []types.Service{
+		service("app_web", 2, 2, &types.UpdateStatus{State: types.UpdateStateCompleted}),
+		service("app_db", 1, 1, nil),
+	})
+	assert.Check(t, stack.Converged)
+	assert.Check(t, is.Len(stack.Services, 2))
+	assert.Check(t, is.Equal(stack.Services[0].Name, "app_db"))
+
+	stack = stackFromServices("app", []types.Service{
+		service("app_web", 1, 2, nil),
+	})
+	assert.Check(t, !stack.Converged)
+
+	stack = stackFromServices("app", []types.Service{
+		service("app_web", 2, 2, &types.UpdateStatus{State: types.UpdateStateUpdating}),
+	})
+	assert.Check(t, !stack.Converged)
+}
+
+func TestStackNetworkCreateRequest(t *testing.T) {
+	req := stackNetworkCreateRequest("app", "app_front", types.NetworkSpec{
+		Attachable: true,
+		IPAMOptions: &types.IPAMOptions{
+			Driver:  types.Driver{Name: "default"},
+			Configs: []types.IPAMConfig{{Subnet: "10.10.0.0/24"}},
+		},
+	})
+	assert.Check(t, is.Equal(req.Name, "app_front"))
+	assert.Check(t, is.Equal(req.Driver, "overlay"))
+	assert.Check(t, is.Equal(req.Scope, "swarm"))
+	assert.Check(t, req.Attachable)
+	assert.Check(t, is.Equal(req.Labels[types.StackNamespaceLabel], "app"))
+	assert.Check(t, is.Equal(req.IPAM.Config[0].Subnet, "10.10.0.0/24"))
+}
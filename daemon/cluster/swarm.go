@@ -16,6 +16,7 @@ import (
 	"github.com/docker/docker/pkg/stack"
 	swarmapi "github.com/moby/swarmkit/v2/api"
 	"github.com/moby/swarmkit/v2/manager/encryption"
+	"github.com/moby/swarmkit/v2/manager/keymanager"
 	swarmnode "github.com/moby/swarmkit/v2/node"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -261,6 +262,18 @@ func (c *Cluster) Update(version uint64, spec types.Spec, flags types.UpdateFlag
 			return errdefs.InvalidParameter(err)
 		}
 
+		// keep track of when the unlock key was last rotated, which is not
+		// part of the spec provided by the client.
+		rotation := convert.UnlockKeyRotationFromGRPC(swarm.Spec)
+		rotation.Interval = spec.EncryptionConfig.UnlockKeyRotationInterval
+		if flags.RotateManagerUnlockKey {
+			now := time.Now().UTC()
+			rotation.RotatedAt = &now
+		}
+		if err := convert.SetUnlockKeyRotation(&clusterSpec, rotation); err != nil {
+			return err
+		}
+
 		_, err = state.controlClient.UpdateCluster(
 			ctx,
 			&swarmapi.UpdateClusterRequest{
@@ -440,6 +453,11 @@ func (c *Cluster) Info() types.Info {
 	if state.err != nil {
 		info.Error = state.err.Error()
 	}
+	if rotatedAt := c.config.Backend.DataPathKeysRotatedAt(); !rotatedAt.IsZero() {
+		next := rotatedAt.Add(keymanager.DefaultKeyRotationInterval)
+		info.DataPathKeysRotatedAt = &rotatedAt
+		info.NextDataPathKeyRotation = &next
+	}
 
 	ctx, cancel := c.getRequestContext()
 	defer cancel()
@@ -0,0 +1,593 @@
+package cluster // import "github.com/docker/docker/daemon/cluster"
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	csi "github.com/container-storage-interface/spec/lib/go/csi"
+	volumetypes "github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/daemon/cluster/convert"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/cron"
+	"github.com/docker/docker/pkg/plugingetter"
+	gogotypes "github.com/gogo/protobuf/types"
+	swarmapi "github.com/moby/swarmkit/v2/api"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Snapshots of cluster volumes are taken by the managers, with the
+// controller service of the CSI plugin of the volume, as swarmkit has no
+// notion of snapshots. The snapshots taken of a volume are recorded in a
+// swarmkit resource of the volume, and scheduled snapshots are taken by the
+// leader. Snapshots cannot be restored in place with CSI; instead, a new
+// volume is created from a snapshot.
+
+// csiControllerCapability is the plugin capability of the CSI plugins that
+// provide the controller service.
+const csiControllerCapability = "csicontroller"
+
+// csiController is a connection to the controller service of a CSI plugin
+// that supports snapshots.
+type csiController struct {
+	csi.ControllerClient
+	cc *grpc.ClientConn
+}
+
+// dialCSIController connects to the controller service of a CSI plugin. An
+// error is returned if the plugin does not support snapshots.
+func dialCSIController(ctx context.Context, pg plugingetter.PluginGetter, driver string) (*csiController, error) {
+	p, err := pg.Get(driver, csiControllerCapability, plugingetter.Lookup)
+	if err != nil {
+		return nil, err
+	}
+	pa, ok := p.(plugingetter.PluginAddr)
+	if !ok {
+		return nil, errdefs.System(fmt.Errorf("plugin %s does not provide a CSI socket", driver))
+	}
+
+	// even though this is a unix socket, we must set WithInsecure or the
+	// connection will not be allowed.
+	cc, err := grpc.DialContext(ctx, pa.Addr().Network()+"://"+pa.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		return nil, errdefs.Unavailable(fmt.Errorf("error connecting to CSI plugin %s: %w", driver, err))
+	}
+	c := &csiController{ControllerClient: csi.NewControllerClient(cc), cc: cc}
+	caps, err := c.ControllerGetCapabilities(ctx, &csi.ControllerGetCapabilitiesRequest{})
+	if err != nil {
+		cc.Close()
+		return nil, errdefs.Unavailable(fmt.Errorf("error querying CSI plugin %s: %w", driver, err))
+	}
+	for _, capability := range caps.GetCapabilities() {
+		if capability.GetRpc().GetType() == csi.ControllerServiceCapability_RPC_CREATE_DELETE_SNAPSHOT {
+			return c, nil
+		}
+	}
+	cc.Close()
+	return nil, errdefs.NotImplemented(fmt.Errorf("CSI plugin %s does not support snapshots", driver))
+}
+
+func (c *csiController) Close() error {
+	return c.cc.Close()
+}
+
+// CreateVolumeSnapshot takes a snapshot of a cluster volume. If no snapshot
+// name is given, a name is generated from the current time.
+func (c *Cluster) CreateVolumeSnapshot(nameOrID string, options volumetypes.SnapshotCreateOptions) (*volumetypes.Snapshot, error) {
+	var snapshot volumetypes.Snapshot
+	if err := c.lockedManagerAction(func(ctx context.Context, state nodeState) error {
+		v, err := getVolume(ctx, state.controlClient, nameOrID)
+		if err != nil {
+			return err
+		}
+		name := options.Name
+		if name == "" {
+			name = time.Now().UTC().Format("20060102T150405Z")
+		}
+		snap, _, err := takeVolumeSnapshot(ctx, state.controlClient, c.config.Backend.PluginGetter(), v, name, false)
+		if err != nil {
+			return err
+		}
+		snapshot = volumeSnapshotToAPI(v, name, snap)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// GetVolumeSnapshots returns the snapshots taken of a cluster volume, oldest
+// first.
+func (c *Cluster) GetVolumeSnapshots(nameOrID string) ([]volumetypes.Snapshot, error) {
+	var snapshots []volumetypes.Snapshot
+	if err := c.lockedManagerAction(func(ctx context.Context, state nodeState) error {
+		v, err := getVolume(ctx, state.controlClient, nameOrID)
+		if err != nil {
+			return err
+		}
+		recorded, _, err := getVolumeSnapshots(ctx, state.controlClient, v)
+		if err != nil {
+			return err
+		}
+		snapshots = make([]volumetypes.Snapshot, 0, len(recorded))
+		for _, name := range volumeSnapshotNames(recorded) {
+			snapshots = append(snapshots, volumeSnapshotToAPI(v, name, recorded[name]))
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return snapshots, nil
+}
+
+// RemoveVolumeSnapshot removes a snapshot of a cluster volume.
+func (c *Cluster) RemoveVolumeSnapshot(nameOrID, snapshot string) error {
+	return c.lockedManagerAction(func(ctx context.Context, state nodeState) error {
+		v, err := getVolume(ctx, state.controlClient, nameOrID)
+		if err != nil {
+			return err
+		}
+		snapshots, r, err := getVolumeSnapshots(ctx, state.controlClient, v)
+		if err != nil {
+			return err
+		}
+		if _, ok := snapshots[snapshot]; !ok {
+			return errdefs.NotFound(fmt.Errorf("no such snapshot: %s", snapshot))
+		}
+		ctrl, err := dialCSIController(ctx, c.config.Backend.PluginGetter(), v.Spec.Driver.Name)
+		if err != nil {
+			return err
+		}
+		defer ctrl.Close()
+		return deleteVolumeSnapshots(ctx, state.controlClient, ctrl, v, r, snapshots, []string{snapshot})
+	})
+}
+
+// takeVolumeSnapshot takes a snapshot of a cluster volume, and records it in
+// the snapshots resource of the volume. It returns the snapshot, and the
+// updated resource.
+func takeVolumeSnapshot(ctx context.Context, client swarmapi.ControlClient, pg plugingetter.PluginGetter, v *swarmapi.Volume, name string, scheduled bool) (convert.VolumeSnapshot, *swarmapi.Resource, error) {
+	if v.VolumeInfo == nil || v.VolumeInfo.VolumeID == "" {
+		return convert.VolumeSnapshot{}, nil, errdefs.Conflict(fmt.Errorf("volume %s has not been created by its plugin yet", v.Spec.Annotations.Name))
+	}
+	if len(v.Spec.Secrets) > 0 {
+		return convert.VolumeSnapshot{}, nil, errdefs.NotImplemented(errors.New("snapshots of cluster volumes with secrets are not supported"))
+	}
+	snapshots, r, err := getVolumeSnapshots(ctx, client, v)
+	if err != nil {
+		return convert.VolumeSnapshot{}, nil, err
+	}
+	if _, ok := snapshots[name]; ok {
+		return convert.VolumeSnapshot{}, nil, errdefs.Conflict(fmt.Errorf("snapshot %s already exists", name))
+	}
+
+	ctrl, err := dialCSIController(ctx, pg, v.Spec.Driver.Name)
+	if err != nil {
+		return convert.VolumeSnapshot{}, nil, err
+	}
+	defer ctrl.Close()
+	resp, err := ctrl.CreateSnapshot(ctx, &csi.CreateSnapshotRequest{
+		SourceVolumeId: v.VolumeInfo.VolumeID,
+		// Snapshot names must be unique for the plugin, not just the
+		// volume, and volume names can be reused once a volume is removed.
+		Name: v.ID + "-" + name,
+	})
+	if err != nil {
+		return convert.VolumeSnapshot{}, nil, errdefs.System(fmt.Errorf("error creating snapshot of volume %s: %w", v.Spec.Annotations.Name, err))
+	}
+	snap := convert.VolumeSnapshot{
+		SnapshotID: resp.GetSnapshot().GetSnapshotId(),
+		CreatedAt:  time.Now().UTC(),
+		Size:       resp.GetSnapshot().GetSizeBytes(),
+		Scheduled:  scheduled,
+	}
+	if t := resp.GetSnapshot().GetCreationTime(); t != nil {
+		snap.CreatedAt = time.Unix(t.GetSeconds(), int64(t.GetNanos())).UTC()
+	}
+
+	snapshots[name] = snap
+	updated, err := updateVolumeSnapshots(ctx, client, v, r, snapshots)
+	if err != nil {
+		// the snapshot would be unknown to the engine if it was kept
+		if _, rmErr := ctrl.DeleteSnapshot(ctx, &csi.DeleteSnapshotRequest{SnapshotId: snap.SnapshotID}); rmErr != nil {
+			logrus.WithError(rmErr).WithField("volume", v.ID).Warn("failed to remove snapshot after failing to record it")
+		}
+		return convert.VolumeSnapshot{}, nil, err
+	}
+	return snap, updated, nil
+}
+
+// deleteVolumeSnapshots deletes snapshots of a cluster volume, and removes
+// them from the snapshots recorded in the snapshots resource r of the volume.
+// Snapshots that fail to be deleted are kept.
+func deleteVolumeSnapshots(ctx context.Context, client swarmapi.ControlClient, ctrl *csiController, v *swarmapi.Volume, r *swarmapi.Resource, snapshots map[string]convert.VolumeSnapshot, names []string) error {
+	var deleteErr error
+	for _, name := range names {
+		_, err := ctrl.DeleteSnapshot(ctx, &csi.DeleteSnapshotRequest{SnapshotId: snapshots[name].SnapshotID})
+		if err != nil && status.Code(err) != codes.NotFound {
+			deleteErr = errdefs.System(fmt.Errorf("error deleting snapshot %s of volume %s: %w", name, v.Spec.Annotations.Name, err))
+			continue
+		}
+		delete(snapshots, name)
+	}
+	if _, err := updateVolumeSnapshots(ctx, client, v, r, snapshots); err != nil {
+		return err
+	}
+	return deleteErr
+}
+
+// volumeSnapshotsResource returns the name of the swarmkit resource the
+// snapshots of a cluster volume are recorded in.
+func volumeSnapshotsResource(volumeID string) string {
+	return "volume-snapshots-" + volumeID
+}
+
+// getVolumeSnapshots returns the snapshots recorded for a cluster volume, and
+// the resource they are recorded in, which is nil if no snapshot was
+// recorded yet.
+func getVolumeSnapshots(ctx context.Context, client swarmapi.ControlClient, v *swarmapi.Volume) (map[string]convert.VolumeSnapshot, *swarmapi.Resource, error) {
+	resp, err := client.ListResources(ctx, &swarmapi.ListResourcesRequest{
+		Filters: &swarmapi.ListResourcesRequest_Filters{
+			Names: []string{volumeSnapshotsResource(v.ID)},
+			Kind:  convert.VolumeSnapshotsKind,
+		},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	var r *swarmapi.Resource
+	if len(resp.Resources) > 0 {
+		r = resp.Resources[0]
+	}
+	snapshots, err := convert.VolumeSnapshotsFromGRPC(r)
+	if err != nil {
+		return nil, nil, errdefs.System(err)
+	}
+	return snapshots, r, nil
+}
+
+// updateVolumeSnapshots records the snapshots of a cluster volume in its
+// snapshots resource r, creating the resource if r is nil, and removing it if
+// no snapshots are left. It returns the updated resource.
+func updateVolumeSnapshots(ctx context.Context, client swarmapi.ControlClient, v *swarmapi.Volume, r *swarmapi.Resource, snapshots map[string]convert.VolumeSnapshot) (*swarmapi.Resource, error) {
+	switch {
+	case r == nil && len(snapshots) == 0:
+		return nil, nil
+	case r == nil:
+		_, err := client.CreateExtension(ctx, &swarmapi.CreateExtensionRequest{
+			Annotations: &swarmapi.Annotations{Name: convert.VolumeSnapshotsKind},
+			Description: "snapshots of cluster volumes",
+		})
+		if err != nil && status.Code(err) != codes.AlreadyExists {
+			return nil, err
+		}
+		resp, err := client.CreateResource(ctx, &swarmapi.CreateResourceRequest{
+			Annotations: &swarmapi.Annotations{Name: volumeSnapshotsResource(v.ID)},
+			Kind:        convert.VolumeSnapshotsKind,
+			Payload:     convert.VolumeSnapshotsToGRPC(snapshots),
+		})
+		if err != nil {
+			return nil, err
+		}
+		return resp.Resource, nil
+	case len(snapshots) == 0:
+		_, err := client.RemoveResource(ctx, &swarmapi.RemoveResourceRequest{ResourceID: r.ID})
+		return nil, err
+	}
+	resp, err := client.UpdateResource(ctx, &swarmapi.UpdateResourceRequest{
+		ResourceID:      r.ID,
+		ResourceVersion: &r.Meta.Version,
+		Payload:         convert.VolumeSnapshotsToGRPC(snapshots),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Resource, nil
+}
+
+// removeStaleVolumeSnapshots removes the snapshots resources of the cluster
+// volumes that no longer exist.
+func removeStaleVolumeSnapshots(ctx context.Context, client swarmapi.ControlClient, volumes []*swarmapi.Volume) error {
+	resp, err := client.ListResources(ctx, &swarmapi.ListResourcesRequest{
+		Filters: &swarmapi.ListResourcesRequest_Filters{Kind: convert.VolumeSnapshotsKind},
+	})
+	if err != nil {
+		return err
+	}
+	exists := make(map[string]bool, len(volumes))
+	for _, v := range volumes {
+		exists[volumeSnapshotsResource(v.ID)] = true
+	}
+	for _, r := range resp.Resources {
+		if exists[r.Annotations.Name] {
+			continue
+		}
+		if _, err := client.RemoveResource(ctx, &swarmapi.RemoveResourceRequest{ResourceID: r.ID}); err != nil && status.Code(err) != codes.NotFound {
+			return err
+		}
+	}
+	return nil
+}
+
+// snapshotVolumes takes the scheduled snapshots of cluster volumes, and
+// removes the scheduled snapshots that are no longer retained.
+func snapshotVolumes(ctx context.Context, client swarmapi.ControlClient, pg plugingetter.PluginGetter, now time.Time) error {
+	volumes, err := client.ListVolumes(ctx, &swarmapi.ListVolumesRequest{})
+	if err != nil {
+		return err
+	}
+	if err := removeStaleVolumeSnapshots(ctx, client, volumes.Volumes); err != nil {
+		logrus.WithError(err).Warn("failed to remove the snapshots of removed volumes")
+	}
+	for _, v := range volumes.Volumes {
+		if v.PendingDelete || v.VolumeInfo == nil {
+			continue
+		}
+		schedule, err := convert.VolumeSnapshotScheduleFromGRPC(&v.Spec)
+		if err != nil || schedule == nil {
+			continue
+		}
+		if err := snapshotVolume(ctx, client, pg, v, schedule, now); err != nil {
+			logrus.WithError(err).WithField("volume", v.ID).Warn("failed to take scheduled snapshot of volume")
+		}
+	}
+	return nil
+}
+
+func snapshotVolume(ctx context.Context, client swarmapi.ControlClient, pg plugingetter.PluginGetter, v *swarmapi.Volume, schedule *volumetypes.SnapshotSchedule, now time.Time) error {
+	sched, err := cron.Parse(schedule.Cron)
+	if err != nil {
+		return err
+	}
+	snapshots, r, err := getVolumeSnapshots(ctx, client, v)
+	if err != nil {
+		return err
+	}
+	created, _ := gogotypes.TimestampFromProto(v.Meta.CreatedAt)
+	if jobDue(sched, lastScheduledSnapshot(snapshots, created), time.Time{}, now) {
+		name := now.Format("20060102T150405Z")
+		snap, updated, err := takeVolumeSnapshot(ctx, client, pg, v, name, true)
+		if err != nil {
+			return err
+		}
+		r = updated
+		snapshots[name] = snap
+	}
+
+	expired := expiredVolumeSnapshots(snapshots, schedule.Retain)
+	if len(expired) == 0 {
+		return nil
+	}
+	ctrl, err := dialCSIController(ctx, pg, v.Spec.Driver.Name)
+	if err != nil {
+		return err
+	}
+	defer ctrl.Close()
+	return deleteVolumeSnapshots(ctx, client, ctrl, v, r, snapshots, expired)
+}
+
+// lastScheduledSnapshot returns the time the last scheduled snapshot of a
+// volume was taken, or the time the volume was created if no scheduled
+// snapshot was taken yet.
+func lastScheduledSnapshot(snapshots map[string]convert.VolumeSnapshot, created time.Time) time.Time {
+	last := created
+	for _, snap := range snapshots {
+		if snap.Scheduled && snap.CreatedAt.After(last) {
+			last = snap.CreatedAt
+		}
+	}
+	return last
+}
+
+// expiredVolumeSnapshots returns the names of the scheduled snapshots that
+// are older than the retain most recent ones. No snapshots expire if retain
+// is zero.
+func expiredVolumeSnapshots(snapshots map[string]convert.VolumeSnapshot, retain uint64) []string {
+	if retain == 0 {
+		return nil
+	}
+	var scheduled []string
+	for _, name := range volumeSnapshotNames(snapshots) {
+		if snapshots[name].Scheduled {
+			scheduled = append(scheduled, name)
+		}
+	}
+	if uint64(len(scheduled)) <= retain {
+		return nil
+	}
+	return scheduled[:uint64(len(scheduled))-retain]
+}
+
+// volumeSnapshotNames returns the names of the snapshots of a volume, oldest
+// first.
+func volumeSnapshotNames(snapshots map[string]convert.VolumeSnapshot) []string {
+	names := make([]string, 0, len(snapshots))
+	for name := range snapshots {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		a, b := snapshots[names[i]], snapshots[names[j]]
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+func volumeSnapshotToAPI(v *swarmapi.Volume, name string, snap convert.VolumeSnapshot) volumetypes.Snapshot {
+	return volumetypes.Snapshot{
+		Name:      name,
+		Volume:    v.Spec.Annotations.Name,
+		Driver:    v.Spec.Driver.Name,
+		CreatedAt: snap.CreatedAt.Format(time.RFC3339),
+		Size:      snap.Size,
+	}
+}
+
+// validateSnapshotSchedule validates the snapshot schedule of a cluster
+// volume.
+func validateSnapshotSchedule(s *volumetypes.SnapshotSchedule) error {
+	if s == nil || s.Cron == "" {
+		return nil
+	}
+	if _, err := cron.Parse(s.Cron); err != nil {
+		return errdefs.InvalidParameter(fmt.Errorf("invalid snapshot schedule: %w", err))
+	}
+	return nil
+}
+
+// createVolumeFromSnapshot creates the volume of the given spec with its CSI
+// plugin from a snapshot of another cluster volume, given in the
+// volume@snapshot form, ahead of swarmkit. CSI plugins must create volumes
+// idempotently by name, so swarmkit then uses the volume created from the
+// snapshot, and a create that is retried after failing in swarmkit reuses the
+// volume created by the plugin on the first attempt. It returns a function
+// that deletes the volume, for when the volume fails to be created in
+// swarmkit.
+func createVolumeFromSnapshot(ctx context.Context, client swarmapi.ControlClient, pg plugingetter.PluginGetter, spec *swarmapi.VolumeSpec, from string) (func(), error) {
+	srcName, snapshot, _ := strings.Cut(from, "@")
+	if snapshot == "" {
+		return nil, errdefs.InvalidParameter(errors.New("cluster volumes can only be created from a snapshot, in the volume@snapshot form"))
+	}
+	if len(spec.Secrets) > 0 {
+		return nil, errdefs.NotImplemented(errors.New("creating cluster volumes with secrets from a snapshot is not supported"))
+	}
+	src, err := getVolume(ctx, client, srcName)
+	if err != nil {
+		return nil, err
+	}
+	if src.Spec.Driver.Name != spec.Driver.Name {
+		return nil, errdefs.InvalidParameter(fmt.Errorf("volume %s uses driver %s, and cannot be created from a snapshot of driver %s", spec.Annotations.Name, spec.Driver.Name, src.Spec.Driver.Name))
+	}
+	// the plugin returns the volume of an existing cluster volume of the same
+	// name, which must not be deleted if the volume fails to be created in
+	// swarmkit.
+	existing, err := client.ListVolumes(ctx, &swarmapi.ListVolumesRequest{
+		Filters: &swarmapi.ListVolumesRequest_Filters{Names: []string{spec.Annotations.Name}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(existing.Volumes) > 0 {
+		return nil, errdefs.Conflict(fmt.Errorf("volume %s already exists", spec.Annotations.Name))
+	}
+	snapshots, _, err := getVolumeSnapshots(ctx, client, src)
+	if err != nil {
+		return nil, err
+	}
+	snap, ok := snapshots[snapshot]
+	if !ok {
+		return nil, errdefs.NotFound(fmt.Errorf("no such snapshot: %s", snapshot))
+	}
+
+	ctrl, err := dialCSIController(ctx, pg, spec.Driver.Name)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := ctrl.CreateVolume(ctx, &csi.CreateVolumeRequest{
+		Name:                      spec.Annotations.Name,
+		Parameters:                spec.Driver.Options,
+		VolumeCapabilities:        []*csi.VolumeCapability{csiVolumeCapability(spec.AccessMode)},
+		AccessibilityRequirements: csiTopologyRequirement(spec.AccessibilityRequirements),
+		CapacityRange:             csiCapacityRange(spec.CapacityRange),
+		VolumeContentSource: &csi.VolumeContentSource{
+			Type: &csi.VolumeContentSource_Snapshot{
+				Snapshot: &csi.VolumeContentSource_SnapshotSource{SnapshotId: snap.SnapshotID},
+			},
+		},
+	})
+	if err != nil {
+		ctrl.Close()
+		if status.Code(err) == codes.AlreadyExists {
+			// a volume of the same name was created by the plugin with
+			// another source or other parameters.
+			return nil, errdefs.Conflict(fmt.Errorf("volume %s already exists in plugin %s, and was not created from snapshot %s", spec.Annotations.Name, spec.Driver.Name, from))
+		}
+		return nil, errdefs.System(fmt.Errorf("error creating volume %s from snapshot %s: %w", spec.Annotations.Name, from, err))
+	}
+	return func() {
+		defer ctrl.Close()
+		if _, err := ctrl.DeleteVolume(context.Background(), &csi.DeleteVolumeRequest{VolumeId: resp.GetVolume().GetVolumeId()}); err != nil {
+			logrus.WithError(err).WithField("volume", spec.Annotations.Name).Warn("failed to remove volume created from snapshot")
+		}
+	}, nil
+}
+
+func csiVolumeCapability(am *swarmapi.VolumeAccessMode) *csi.VolumeCapability {
+	capability := &csi.VolumeCapability{
+		AccessMode: &csi.VolumeCapability_AccessMode{},
+	}
+	if am == nil {
+		return capability
+	}
+	switch am.Scope {
+	case swarmapi.VolumeScopeSingleNode:
+		switch am.Sharing {
+		case swarmapi.VolumeSharingNone, swarmapi.VolumeSharingOneWriter, swarmapi.VolumeSharingAll:
+			capability.AccessMode.Mode = csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER
+		case swarmapi.VolumeSharingReadOnly:
+			capability.AccessMode.Mode = csi.VolumeCapability_AccessMode_SINGLE_NODE_READER_ONLY
+		}
+	case swarmapi.VolumeScopeMultiNode:
+		switch am.Sharing {
+		case swarmapi.VolumeSharingReadOnly:
+			capability.AccessMode.Mode = csi.VolumeCapability_AccessMode_MULTI_NODE_READER_ONLY
+		case swarmapi.VolumeSharingOneWriter:
+			capability.AccessMode.Mode = csi.VolumeCapability_AccessMode_MULTI_NODE_SINGLE_WRITER
+		case swarmapi.VolumeSharingAll:
+			capability.AccessMode.Mode = csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER
+		}
+	}
+	if am.GetBlock() != nil {
+		capability.AccessType = &csi.VolumeCapability_Block{
+			Block: &csi.VolumeCapability_BlockVolume{},
+		}
+	}
+	if mount := am.GetMount(); mount != nil {
+		capability.AccessType = &csi.VolumeCapability_Mount{
+			Mount: &csi.VolumeCapability_MountVolume{
+				FsType:     mount.FsType,
+				MountFlags: mount.MountFlags,
+			},
+		}
+	}
+	return capability
+}
+
+func csiTopologyRequirement(t *swarmapi.TopologyRequirement) *csi.TopologyRequirement {
+	if t == nil {
+		return nil
+	}
+	return &csi.TopologyRequirement{
+		Requisite: csiTopologies(t.Requisite),
+		Preferred: csiTopologies(t.Preferred),
+	}
+}
+
+func csiTopologies(ts []*swarmapi.Topology) []*csi.Topology {
+	if ts == nil {
+		return nil
+	}
+	out := make([]*csi.Topology, 0, len(ts))
+	for _, t := range ts {
+		out = append(out, &csi.Topology{Segments: t.Segments})
+	}
+	return out
+}
+
+func csiCapacityRange(cr *swarmapi.CapacityRange) *csi.CapacityRange {
+	if cr == nil {
+		return nil
+	}
+	return &csi.CapacityRange{
+		RequiredBytes: cr.RequiredBytes,
+		LimitBytes:    cr.LimitBytes,
+	}
+}
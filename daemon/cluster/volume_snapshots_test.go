@@ -0,0 +1,41 @@
+package cluster // import "github.com/docker/docker/daemon/cluster"
+
+import (
+	"testing"
+	"time"
+
+	"github.com/docker/docker/daemon/cluster/convert"
+	swarmapi "github.com/moby/swarmkit/v2/api"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestExpiredVolumeSnapshots(t *testing.T) {
+	created := time.Date(2023, time.March, 15, 0, 0, 0, 0, time.UTC)
+	snapshots := map[string]convert.VolumeSnapshot{
+		"day1":   {CreatedAt: created.Add(24 * time.Hour), Scheduled: true},
+		"day2":   {CreatedAt: created.Add(48 * time.Hour), Scheduled: true},
+		"manual": {CreatedAt: created.Add(time.Hour)},
+		"day3":   {CreatedAt: created.Add(72 * time.Hour), Scheduled: true},
+	}
+
+	assert.Check(t, is.Len(expiredVolumeSnapshots(snapshots, 0), 0))
+	assert.Check(t, is.Len(expiredVolumeSnapshots(snapshots, 3), 0))
+	assert.Check(t, is.DeepEqual(expiredVolumeSnapshots(snapshots, 1), []string{"day1", "day2"}))
+
+	assert.Check(t, is.DeepEqual(volumeSnapshotNames(snapshots), []string{"manual", "day1", "day2", "day3"}))
+	assert.Check(t, lastScheduledSnapshot(snapshots, created).Equal(created.Add(72*time.Hour)))
+	assert.Check(t, lastScheduledSnapshot(nil, created).Equal(created))
+}
+
+func TestCSIVolumeCapability(t *testing.T) {
+	capability := csiVolumeCapability(&swarmapi.VolumeAccessMode{
+		Scope:   swarmapi.VolumeScopeMultiNode,
+		Sharing: swarmapi.VolumeSharingReadOnly,
+		AccessType: &swarmapi.VolumeAccessMode_Mount{
+			Mount: &swarmapi.VolumeAccessMode_MountVolume{FsType: "ext4"},
+		},
+	})
+	assert.Check(t, is.Equal(capability.GetAccessMode().GetMode().String(), "MULTI_NODE_READER_ONLY"))
+	assert.Check(t, is.Equal(capability.GetMount().GetFsType(), "ext4"))
+}
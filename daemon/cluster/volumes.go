@@ -9,6 +9,8 @@ import (
 	"github.com/docker/docker/errdefs"
 	swarmapi "github.com/moby/swarmkit/v2/api"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // GetVolume returns a volume from the swarm cluster.
@@ -58,14 +60,36 @@ func (c *Cluster) GetVolumes(options volumetypes.ListOptions) ([]*volumetypes.Vo
 //
 // Returns the volume ID if creation is successful, or an error if not.
 func (c *Cluster) CreateVolume(v volumetypes.CreateOptions) (*volumetypes.Volume, error) {
+	if v.ClusterVolumeSpec != nil {
+		if err := validateSnapshotSchedule(v.ClusterVolumeSpec.SnapshotSchedule); err != nil {
+			return nil, err
+		}
+	}
+
 	var resp *swarmapi.CreateVolumeResponse
 	if err := c.lockedManagerAction(func(ctx context.Context, state nodeState) error {
 		volumeSpec := convert.VolumeCreateToGRPC(&v)
 
+		// a volume created from a snapshot is created with its plugin before
+		// it is created in swarmkit.
+		undo := func() {}
+		if v.From != "" {
+			var err error
+			undo, err = createVolumeFromSnapshot(ctx, state.controlClient, c.config.Backend.PluginGetter(), volumeSpec, v.From)
+			if err != nil {
+				return err
+			}
+		}
+
 		r, err := state.controlClient.CreateVolume(
 			ctx, &swarmapi.CreateVolumeRequest{Spec: volumeSpec},
 		)
 		if err != nil {
+			// the volume of the plugin belongs to the existing volume if a
+			// volume of the same name was created concurrently.
+			if status.Code(err) != codes.AlreadyExists {
+				undo()
+			}
 			return err
 		}
 		resp = r
@@ -110,9 +134,9 @@ func (c *Cluster) UpdateVolume(nameOrID string, version uint64, volume volumetyp
 			return err
 		}
 
-		// For now, the only thing we can update is availability. Instead of
-		// converting the whole spec, just pluck out the availability if it has
-		// been set.
+		// For now, the only things we can update are availability and the
+		// snapshot schedule. Instead of converting the whole spec, just pluck
+		// them out if they have been set.
 
 		if volume.Spec != nil {
 			switch volume.Spec.Availability {
@@ -124,6 +148,13 @@ func (c *Cluster) UpdateVolume(nameOrID string, version uint64, volume volumetyp
 				v.Spec.Availability = swarmapi.VolumeAvailabilityDrain
 			}
 			// if default empty value, change nothing.
+
+			if volume.Spec.SnapshotSchedule != nil {
+				if err := validateSnapshotSchedule(volume.Spec.SnapshotSchedule); err != nil {
+					return err
+				}
+				convert.SetVolumeSnapshotSchedule(&v.Spec, volume.Spec.SnapshotSchedule)
+			}
 		}
 
 		_, err = state.controlClient.UpdateVolume(
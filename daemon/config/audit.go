@@ -0,0 +1,63 @@
+package config // import "github.com/docker/docker/daemon/config"
+
+import (
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+// Audit log drivers supported by the daemon.
+const (
+	AuditLogDriverFile   = "file"
+	AuditLogDriverSyslog = "syslog"
+	AuditLogDriverOTLP   = "otlp"
+)
+
+// AuditLogConfig contains the configuration of the API audit log.
+// Audit logging is disabled if no driver is set.
+type AuditLogConfig struct {
+	// Driver is the sink audit entries are written to ("file", "syslog", or
+	// "otlp").
+	Driver string `json:"driver,omitempty"`
+
+	// Path is the file audit entries are appended to when using the "file"
+	// driver.
+	Path string `json:"path,omitempty"`
+
+	// Address is the syslog server to send entries to when using the
+	// "syslog" driver, for example "udp://1.2.3.4:514". The local syslog
+	// daemon is used if no address is set.
+	//
+	// When using the "otlp" driver, it is the URL of the OTLP/HTTP endpoint
+	// of the collector, for example "http://collector:4318". Entries are
+	// sent to the "/v1/logs" path if the URL has no path.
+	Address string `json:"address,omitempty"`
+
+	// Tag is the syslog tag used when using the "syslog" driver.
+	Tag string `json:"tag,omitempty"`
+}
+
+// Enabled returns whether audit logging is configured.
+func (c AuditLogConfig) Enabled() bool {
+	return c.Driver != ""
+}
+
+func (c AuditLogConfig) validate() error {
+	switch c.Driver {
+	case "":
+		return nil
+	case AuditLogDriverFile:
+		if c.Path == "" {
+			return errors.New("audit-log: path is required for the file driver")
+		}
+	case AuditLogDriverSyslog:
+	case AuditLogDriverOTLP:
+		u, err := url.Parse(c.Address)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errors.Errorf("audit-log: invalid address for the otlp driver: %q: must be an http or https URL", c.Address)
+		}
+	default:
+		return errors.Errorf("audit-log: unsupported driver: %s", c.Driver)
+	}
+	return nil
+}
@@ -2,10 +2,13 @@ package config
 
 import (
 	"encoding/json"
+	"path"
 	"sort"
 	"strings"
 
 	"github.com/docker/docker/api/types/filters"
+	units "github.com/docker/go-units"
+	"github.com/pkg/errors"
 )
 
 // BuilderGCRule represents a GC rule for buildkit cache
@@ -66,8 +69,90 @@ type BuilderEntitlements struct {
 	SecurityInsecure *bool `json:"security-insecure,omitempty"`
 }
 
+// BuilderCacheEntry represents a build cache to import or export, such as a
+// registry cache
+type BuilderCacheEntry struct {
+	Type  string            `json:",omitempty"`
+	Attrs map[string]string `json:",omitempty"`
+}
+
+// BuilderCacheConfig contains the build caches a buildkit builder imports
+// and exports for builds that do not specify their own
+type BuilderCacheConfig struct {
+	Imports []BuilderCacheEntry `json:",omitempty"`
+	Exports []BuilderCacheEntry `json:",omitempty"`
+}
+
+// BuilderGitAuth contains the credentials a buildkit builder uses to fetch
+// git repositories over HTTP(S) from the hosts matching Host, such as
+// "github.com" or "*.example.com". Either Token or CredentialHelper must be
+// set.
+type BuilderGitAuth struct {
+	Host string
+
+	// Token is an access token for the repositories of the host.
+	Token string `json:",omitempty"`
+
+	// CredentialHelper is the name of the docker credential helper the
+	// credentials of the host are fetched from, such as "pass" for the
+	// docker-credential-pass helper.
+	CredentialHelper string `json:",omitempty"`
+}
+
+// BuilderResources contains the default resource limits of the builds of a
+// buildkit builder. Each build runs in a cgroup of its own with these limits,
+// unless the build request sets its own.
+type BuilderResources struct {
+	// Memory is the memory limit of a build, such as "2g".
+	Memory string `json:",omitempty"`
+
+	// CPUs is the number of CPUs a build can use, such as 1.5.
+	CPUs float64 `json:",omitempty"`
+
+	// PidsLimit is the maximum number of processes of a build.
+	PidsLimit int64 `json:",omitempty"`
+
+	// ScratchLimit is the maximum size of the files the steps of a build
+	// write to their root filesystem, such as "10g".
+	ScratchLimit string `json:",omitempty"`
+}
+
 // BuilderConfig contains config for the builder
 type BuilderConfig struct {
 	GC           BuilderGCConfig     `json:",omitempty"`
 	Entitlements BuilderEntitlements `json:",omitempty"`
+	Cache        BuilderCacheConfig  `json:",omitempty"`
+	GitAuth      []BuilderGitAuth    `json:",omitempty"`
+	Resources    BuilderResources    `json:",omitempty"`
+}
+
+func (cfg *BuilderConfig) validate() error {
+	for _, a := range cfg.GitAuth {
+		if a.Host == "" {
+			return errors.New("builder git auth must have a host")
+		}
+		if _, err := path.Match(a.Host, ""); err != nil {
+			return errors.Errorf("invalid builder git auth host %q", a.Host)
+		}
+		if (a.Token == "") == (a.CredentialHelper == "") {
+			return errors.Errorf("builder git auth for %s must have either a token or a credential helper", a.Host)
+		}
+	}
+	if m := cfg.Resources.Memory; m != "" {
+		if _, err := units.RAMInBytes(m); err != nil {
+			return errors.Wrapf(err, "invalid builder memory limit %q", m)
+		}
+	}
+	if s := cfg.Resources.ScratchLimit; s != "" {
+		if _, err := units.RAMInBytes(s); err != nil {
+			return errors.Wrapf(err, "invalid builder scratch limit %q", s)
+		}
+	}
+	if cfg.Resources.CPUs < 0 {
+		return errors.Errorf("invalid builder CPUs %v: must not be negative", cfg.Resources.CPUs)
+	}
+	if cfg.Resources.PidsLimit < 0 {
+		return errors.Errorf("invalid builder pids limit %d: must not be negative", cfg.Resources.PidsLimit)
+	}
+	return nil
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/docker/docker/api/types/filters"
 	"github.com/google/go-cmp/cmp"
 	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
 	"gotest.tools/v3/fs"
 )
 
@@ -56,3 +57,42 @@ func TestBuilderGCFilterUnmarshal(t *testing.T) {
 	}}
 	assert.DeepEqual(t, cfg.Policy, expectedPolicy, cmp.AllowUnexported(BuilderGCFilter{}))
 }
+
+func TestBuilderGitAuthValidate(t *testing.T) {
+	valid := BuilderConfig{GitAuth: []BuilderGitAuth{
+		{Host: "github.com", Token: "token"},
+		{Host: "*.example.com", CredentialHelper: "pass"},
+	}}
+	assert.Check(t, valid.validate())
+
+	for _, tc := range []struct {
+		auth        BuilderGitAuth
+		expectedErr string
+	}{
+		{auth: BuilderGitAuth{Token: "token"}, expectedErr: "must have a host"},
+		{auth: BuilderGitAuth{Host: "[example.com", Token: "token"}, expectedErr: "invalid builder git auth host"},
+		{auth: BuilderGitAuth{Host: "github.com"}, expectedErr: "either a token or a credential helper"},
+		{auth: BuilderGitAuth{Host: "github.com", Token: "token", CredentialHelper: "pass"}, expectedErr: "either a token or a credential helper"},
+	} {
+		cfg := BuilderConfig{GitAuth: []BuilderGitAuth{tc.auth}}
+		assert.Check(t, is.ErrorContains(cfg.validate(), tc.expectedErr))
+	}
+}
+
+func TestBuilderResourcesValidate(t *testing.T) {
+	valid := BuilderConfig{Resources: BuilderResources{Memory: "2g", CPUs: 1.5, PidsLimit: 1024, ScratchLimit: "10g"}}
+	assert.Check(t, valid.validate())
+
+	for _, tc := range []struct {
+		resources   BuilderResources
+		expectedErr string
+	}{
+		{resources: BuilderResources{Memory: "lots"}, expectedErr: "invalid builder memory limit"},
+		{resources: BuilderResources{ScratchLimit: "lots"}, expectedErr: "invalid builder scratch limit"},
+		{resources: BuilderResources{CPUs: -1}, expectedErr: "invalid builder CPUs"},
+		{resources: BuilderResources{PidsLimit: -1}, expectedErr: "invalid builder pids limit"},
+	} {
+		cfg := BuilderConfig{Resources: tc.resources}
+		assert.Check(t, is.ErrorContains(cfg.validate(), tc.expectedErr))
+	}
+}
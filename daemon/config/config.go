@@ -16,6 +16,7 @@ import (
 	"golang.org/x/text/transform"
 
 	"github.com/containerd/containerd/runtime/v2/shim"
+	"github.com/docker/docker/libnetwork/ipamutils"
 	"github.com/docker/docker/opts"
 	"github.com/docker/docker/pkg/authorization"
 	"github.com/docker/docker/registry"
@@ -83,14 +84,32 @@ var flatOptions = map[string]bool{
 	"default-ulimits":    true,
 	"features":           true,
 	"builder":            true,
+	"audit-log":          true,
+	"csi-drivers":        true,
+	"socket-access":      true,
+
+	"volume-plugin-timeouts":      true,
+	"ipam-reconcile":              true,
+	"lb-rebalance":                true,
+	"registry-credential-helpers": true,
 }
 
 // skipValidateOptions contains configuration keys
 // that will be skipped from findConfigurationConflicts
 // for unknown flag validation.
 var skipValidateOptions = map[string]bool{
-	"features": true,
-	"builder":  true,
+	"features":        true,
+	"builder":         true,
+	"audit-log":       true,
+	"event-sinks":     true,
+	"csi-drivers":     true,
+	"socket-access":   true,
+	"prune-schedules": true,
+
+	"volume-plugin-timeouts":      true,
+	"ipam-reconcile":              true,
+	"lb-rebalance":                true,
+	"registry-credential-helpers": true,
 	// Corresponding flag has been removed because it was already unusable
 	"deprecated-key-path": true,
 }
@@ -124,8 +143,29 @@ type commonBridgeConfig struct {
 type NetworkConfig struct {
 	// Default address pools for docker networks
 	DefaultAddressPools opts.PoolsOpt `json:"default-address-pools,omitempty"`
+	// DefaultAddressPoolsRouteOverlap is how the subnets of the default
+	// address pools which overlap the routes of the host are handled: they
+	// are skipped ("skip", the default), fail the creation of the network
+	// ("fail"), or the routes are not consulted ("ignore").
+	DefaultAddressPoolsRouteOverlap string `json:"default-address-pools-route-overlap,omitempty"`
 	// NetworkControlPlaneMTU allows to specify the control plane MTU, this will allow to optimize the network use in some components
 	NetworkControlPlaneMTU int `json:"network-control-plane-mtu,omitempty"`
+	// IPAMReconcile configures the periodic detection of the IPAM allocations
+	// owned by no network or endpoint.
+	IPAMReconcile IPAMReconcileConfig `json:"ipam-reconcile,omitempty"`
+	// LBRebalance configures the periodic rebalance of the load balancers
+	// of the services.
+	LBRebalance LBRebalanceConfig `json:"lb-rebalance,omitempty"`
+	// DefaultNetworks is the chain of default networks of the containers
+	// created without network configuration. The default network of the
+	// daemon is used if it is empty, or none of its entries can be used.
+	DefaultNetworks []DefaultNetwork `json:"default-networks,omitempty"`
+	// DefaultEndpointOpts are the driver options, by network driver, of the
+	// endpoints of the containers created without network configuration.
+	DefaultEndpointOpts map[string]map[string]string `json:"default-endpoint-opts,omitempty"`
+	// GlobalKVStore configures the KV store backing the global scope of the
+	// networks.
+	GlobalKVStore GlobalKVStoreConfig `json:"global-kv-store,omitempty"`
 }
 
 // TLSOptions defines TLS configuration for the daemon server.
@@ -151,7 +191,8 @@ type DNSConfig struct {
 // using the same names that the flags in the command line use.
 type CommonConfig struct {
 	AuthzMiddleware       *authorization.Middleware `json:"-"`
-	AuthorizationPlugins  []string                  `json:"authorization-plugins,omitempty"` // AuthorizationPlugins holds list of authorization plugins
+	AuthorizationPlugins  []string                  `json:"authorization-plugins,omitempty"`   // AuthorizationPlugins holds list of authorization plugins
+	AuthorizationCacheTTL int                       `json:"authorization-cache-ttl,omitempty"` // AuthorizationCacheTTL is the number of seconds the decisions of authorization plugins are reused
 	AutoRestart           bool                      `json:"-"`
 	Context               map[string][]string       `json:"-"`
 	DisableBridge         bool                      `json:"-"`
@@ -191,6 +232,12 @@ type CommonConfig struct {
 	// to stop when daemon is being shutdown
 	ShutdownTimeout int `json:"shutdown-timeout,omitempty"`
 
+	// IdleExitTimeout is the number of seconds after which the daemon exits
+	// if it had no API connections and no running containers. It requires
+	// the API to listen on sockets activated by systemd, which starts the
+	// daemon again on the next connection.
+	IdleExitTimeout int `json:"idle-exit-timeout,omitempty"`
+
 	Debug     bool     `json:"debug,omitempty"`
 	Hosts     []string `json:"hosts,omitempty"`
 	LogLevel  string   `json:"log-level,omitempty"`
@@ -219,6 +266,15 @@ type CommonConfig struct {
 
 	MetricsAddress string `json:"metrics-addr"`
 
+	// MetricsContainerStats enables per-container resource usage metrics
+	// on the metrics endpoint. These metrics have a high cardinality on
+	// hosts running many containers, and are therefore disabled by default.
+	MetricsContainerStats bool `json:"metrics-container-stats,omitempty"`
+
+	// MetricsContainerLabels is the list of container label keys to add as
+	// labels to per-container metrics.
+	MetricsContainerLabels []string `json:"metrics-container-labels,omitempty"`
+
 	DNSConfig
 	LogConfig
 	BridgeConfig // BridgeConfig holds bridge network specific configuration.
@@ -236,6 +292,15 @@ type CommonConfig struct {
 	// e.g: ["orange=red", "orange=green", "orange=blue", "apple=3"]
 	NodeGenericResources []string `json:"node-generic-resources,omitempty"`
 
+	// NodeGenericResourcesDiscovery enables advertising the NVIDIA GPUs and
+	// the CDI devices of the host as node generic resources, in addition to
+	// NodeGenericResources.
+	NodeGenericResourcesDiscovery bool `json:"node-generic-resources-discovery,omitempty"`
+
+	// NodeMetadataLabels enables populating engine labels from the facts of
+	// the host, and the metadata of the cloud instance it runs on.
+	NodeMetadataLabels bool `json:"node-metadata-labels,omitempty"`
+
 	// ContainerAddr is the address used to connect to containerd if we're
 	// not starting it ourselves
 	ContainerdAddr string `json:"containerd,omitempty"`
@@ -251,6 +316,29 @@ type CommonConfig struct {
 
 	Builder BuilderConfig `json:"builder,omitempty"`
 
+	// AuditLog configures structured audit logging of API requests.
+	AuditLog AuditLogConfig `json:"audit-log,omitempty"`
+
+	// SocketAccess restricts the API endpoints local users can call over
+	// the unix socket.
+	SocketAccess SocketAccessConfig `json:"socket-access,omitempty"`
+
+	// EventSinks lists the external destinations engine events are
+	// exported to.
+	EventSinks []EventSinkConfig `json:"event-sinks,omitempty"`
+
+	// PruneSchedules configures the periodic pruning of unused objects.
+	PruneSchedules []PruneScheduleConfig `json:"prune-schedules,omitempty"`
+
+	// CSIDrivers maps volume driver names to the unix socket of a CSI
+	// plugin that provides volumes for the driver.
+	CSIDrivers map[string]string `json:"csi-drivers,omitempty"`
+
+	// VolumePluginTimeouts configures the timeouts of the calls to volume
+	// plugins, by plugin name. The timeouts under "*" apply to the plugins
+	// which are not listed.
+	VolumePluginTimeouts map[string]VolumePluginTimeoutsConfig `json:"volume-plugin-timeouts,omitempty"`
+
 	ContainerdNamespace       string `json:"containerd-namespace,omitempty"`
 	ContainerdPluginNamespace string `json:"containerd-plugin-namespace,omitempty"`
 
@@ -376,6 +464,42 @@ func Reload(configFile string, flags *pflag.FlagSet, reload func(*Config)) error
 	return nil
 }
 
+// Check validates the contents of a configuration file without applying
+// them. It returns the configuration from the file, and an error if it cannot
+// be decoded, conflicts with the configuration provided by the flags, or is
+// invalid. Unlike Reload, it does not update the flags.
+func Check(b []byte, flags *pflag.FlagSet) (*Config, error) {
+	b, err := decodeConfigJSON(b)
+	if err != nil {
+		return nil, err
+	}
+
+	var config Config
+	if len(b) > 0 {
+		var jsonConfig map[string]interface{}
+		if err := json.Unmarshal(b, &jsonConfig); err != nil {
+			return nil, err
+		}
+		config.ValuesSet = configValuesSet(jsonConfig)
+		if flags != nil {
+			if err := findConfigurationConflicts(config.ValuesSet, flags); err != nil {
+				return nil, err
+			}
+		}
+		if err := json.Unmarshal(b, &config); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := GetConflictFreeLabels(config.Labels); err != nil {
+		return nil, err
+	}
+	if err := Validate(&config); err != nil {
+		return nil, errors.Wrap(err, "file configuration validation failed")
+	}
+	return &config, nil
+}
+
 // boolValue is an interface that boolean value flags implement
 // to tell the command line how to make -name equivalent to -name=true.
 type boolValue interface {
@@ -413,38 +537,10 @@ func getConflictFreeConfiguration(configFile string, flags *pflag.FlagSet) (*Con
 	if err != nil {
 		return nil, err
 	}
-
-	// Decode the contents of the JSON file using a [byte order mark] if present, instead of assuming UTF-8 without BOM.
-	// The BOM, if present, will be used to determine the encoding. If no BOM is present, we will assume the default
-	// and preferred encoding for JSON as defined by [RFC 8259], UTF-8 without BOM.
-	//
-	// While JSON is normatively UTF-8 with no BOM, there are a couple of reasons to decode here:
-	//   * UTF-8 with BOM is something that new implementations should avoid producing; however, [RFC 8259 Section 8.1]
-	//     allows implementations to ignore the UTF-8 BOM when present for interoperability. Older versions of Notepad,
-	//     the only text editor available out of the box on Windows Server, writes UTF-8 with a BOM by default.
-	//   * The default encoding for [Windows PowerShell] is UTF-16 LE with BOM. While encodings in PowerShell can be a
-	//     bit idiosyncratic, BOMs are still generally written. There is no support for selecting UTF-8 without a BOM as
-	//     the encoding in Windows PowerShell, though some Cmdlets only write UTF-8 with no BOM. PowerShell Core
-	//     introduces `utf8NoBOM` and makes it the default, but PowerShell Core is unlikely to be the implementation for
-	//     a majority of Windows Server + PowerShell users.
-	//   * While [RFC 8259 Section 8.1] asserts that software that is not part of a closed ecosystem or that crosses a
-	//     network boundary should only support UTF-8, and should never write a BOM, it does acknowledge older versions
-	//     of the standard, such as [RFC 7159 Section 8.1]. In the interest of pragmatism and easing pain for Windows
-	//     users, we consider Windows tools such as Windows PowerShell and Notepad part of our ecosystem, and support
-	//     the two most common encodings: UTF-16 LE with BOM, and UTF-8 with BOM, in addition to the standard UTF-8
-	//     without BOM.
-	//
-	// [byte order mark]: https://www.unicode.org/faq/utf_bom.html#BOM
-	// [RFC 8259]: https://www.rfc-editor.org/rfc/rfc8259
-	// [RFC 8259 Section 8.1]: https://www.rfc-editor.org/rfc/rfc8259#section-8.1
-	// [RFC 7159 Section 8.1]: https://www.rfc-editor.org/rfc/rfc7159#section-8.1
-	// [Windows PowerShell]: https://learn.microsoft.com/en-us/powershell/module/microsoft.powershell.core/about/about_character_encoding?view=powershell-5.1
-	b, n, err := transform.Bytes(transform.Chain(unicode.BOMOverride(transform.Nop), encoding.UTF8Validator), b)
+	b, err = decodeConfigJSON(b)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to decode configuration JSON at offset %d", n)
+		return nil, err
 	}
-	// Trim whitespace so that an empty config can be detected for an early return.
-	b = bytes.TrimSpace(b)
 
 	var config Config
 	if len(b) == 0 {
@@ -503,6 +599,42 @@ func getConflictFreeConfiguration(configFile string, flags *pflag.FlagSet) (*Con
 	return &config, nil
 }
 
+// decodeConfigJSON decodes the contents of a configuration file to UTF-8,
+// and trims surrounding whitespace.
+func decodeConfigJSON(b []byte) ([]byte, error) {
+	// Decode the contents of the JSON file using a [byte order mark] if present, instead of assuming UTF-8 without BOM.
+	// The BOM, if present, will be used to determine the encoding. If no BOM is present, we will assume the default
+	// and preferred encoding for JSON as defined by [RFC 8259], UTF-8 without BOM.
+	//
+	// While JSON is normatively UTF-8 with no BOM, there are a couple of reasons to decode here:
+	//   * UTF-8 with BOM is something that new implementations should avoid producing; however, [RFC 8259 Section 8.1]
+	//     allows implementations to ignore the UTF-8 BOM when present for interoperability. Older versions of Notepad,
+	//     the only text editor available out of the box on Windows Server, writes UTF-8 with a BOM by default.
+	//   * The default encoding for [Windows PowerShell] is UTF-16 LE with BOM. While encodings in PowerShell can be a
+	//     bit idiosyncratic, BOMs are still generally written. There is no support for selecting UTF-8 without a BOM as
+	//     the encoding in Windows PowerShell, though some Cmdlets only write UTF-8 with no BOM. PowerShell Core
+	//     introduces `utf8NoBOM` and makes it the default, but PowerShell Core is unlikely to be the implementation for
+	//     a majority of Windows Server + PowerShell users.
+	//   * While [RFC 8259 Section 8.1] asserts that software that is not part of a closed ecosystem or that crosses a
+	//     network boundary should only support UTF-8, and should never write a BOM, it does acknowledge older versions
+	//     of the standard, such as [RFC 7159 Section 8.1]. In the interest of pragmatism and easing pain for Windows
+	//     users, we consider Windows tools such as Windows PowerShell and Notepad part of our ecosystem, and support
+	//     the two most common encodings: UTF-16 LE with BOM, and UTF-8 with BOM, in addition to the standard UTF-8
+	//     without BOM.
+	//
+	// [byte order mark]: https://www.unicode.org/faq/utf_bom.html#BOM
+	// [RFC 8259]: https://www.rfc-editor.org/rfc/rfc8259
+	// [RFC 8259 Section 8.1]: https://www.rfc-editor.org/rfc/rfc8259#section-8.1
+	// [RFC 7159 Section 8.1]: https://www.rfc-editor.org/rfc/rfc7159#section-8.1
+	// [Windows PowerShell]: https://learn.microsoft.com/en-us/powershell/module/microsoft.powershell.core/about/about_character_encoding?view=powershell-5.1
+	b, n, err := transform.Bytes(transform.Chain(unicode.BOMOverride(transform.Nop), encoding.UTF8Validator), b)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decode configuration JSON at offset %d", n)
+	}
+	// Trim whitespace so that an empty config can be detected for an early return.
+	return bytes.TrimSpace(b), nil
+}
+
 // configValuesSet returns the configuration values explicitly set in the file.
 func configValuesSet(config map[string]interface{}) map[string]interface{} {
 	flatten := make(map[string]interface{})
@@ -631,6 +763,12 @@ func Validate(config *Config) error {
 	if config.MaxDownloadAttempts < 0 {
 		return errors.Errorf("invalid max download attempts: %d", config.MaxDownloadAttempts)
 	}
+	if config.AuthorizationCacheTTL < 0 {
+		return errors.Errorf("invalid authorization cache TTL: %d", config.AuthorizationCacheTTL)
+	}
+	if err := validateIdleExitTimeout(config); err != nil {
+		return err
+	}
 
 	// validate that "default" runtime is not reset
 	if runtimes := config.GetAllRuntimes(); len(runtimes) > 0 {
@@ -658,6 +796,60 @@ func Validate(config *Config) error {
 		}
 	}
 
+	if err := config.AuditLog.validate(); err != nil {
+		return err
+	}
+
+	if err := config.SocketAccess.validate(); err != nil {
+		return err
+	}
+
+	if err := config.Builder.validate(); err != nil {
+		return err
+	}
+
+	for _, sink := range config.EventSinks {
+		if err := sink.validate(); err != nil {
+			return err
+		}
+	}
+
+	if err := validatePruneSchedules(config.PruneSchedules); err != nil {
+		return err
+	}
+
+	if err := validateCSIDrivers(config.CSIDrivers); err != nil {
+		return err
+	}
+
+	if err := validateVolumePluginTimeouts(config.VolumePluginTimeouts); err != nil {
+		return err
+	}
+
+	if err := validateIPAMReconcile(config.IPAMReconcile); err != nil {
+		return err
+	}
+
+	if err := validateLBRebalance(config.LBRebalance); err != nil {
+		return err
+	}
+
+	if err := validateGlobalKVStore(config.GlobalKVStore); err != nil {
+		return err
+	}
+
+	if err := validateDefaultNetworks(config.DefaultNetworks); err != nil {
+		return err
+	}
+
+	if err := ipamutils.ValidateRouteOverlapMode(ipamutils.RouteOverlapMode(config.DefaultAddressPoolsRouteOverlap)); err != nil {
+		return errors.Wrap(err, "default-address-pools-route-overlap")
+	}
+
+	if err := registry.ValidateCredentialHelpers(config.CredentialHelpers); err != nil {
+		return err
+	}
+
 	// validate platform-specific settings
 	return config.ValidatePlatformConfig()
 }
@@ -12,6 +12,7 @@ import (
 	"github.com/docker/docker/opts"
 	"github.com/docker/docker/pkg/homedir"
 	"github.com/docker/docker/pkg/rootless"
+	"github.com/docker/go-connections/nat"
 	units "github.com/docker/go-units"
 	"github.com/pkg/errors"
 )
@@ -51,6 +52,24 @@ type BridgeConfig struct {
 	EnableUserlandProxy bool   `json:"userland-proxy,omitempty"`
 	UserlandProxyPath   string `json:"userland-proxy-path,omitempty"`
 	FixedCIDRv6         string `json:"fixed-cidr-v6,omitempty"`
+	PublishedPortRange  string `json:"published-port-range,omitempty"`
+}
+
+// PublishedPortRangeToInt returns the first and last port of the range that
+// host ports are allocated from when a port is published without a host port.
+// Both are zero if no range is configured.
+func (conf *BridgeConfig) PublishedPortRangeToInt() (start, end int, err error) {
+	if conf.PublishedPortRange == "" {
+		return 0, 0, nil
+	}
+	start, end, err = nat.ParsePortRangeToInt(conf.PublishedPortRange)
+	if err == nil && (start == 0 || end < start) {
+		err = errors.New("port range must not include port 0, and must not end before it starts")
+	}
+	if err != nil {
+		return 0, 0, errors.Wrapf(err, "invalid published port range %q", conf.PublishedPortRange)
+	}
+	return start, end, nil
 }
 
 // Config defines the configuration of a docker daemon.
@@ -79,6 +98,12 @@ type Config struct {
 	// ResolvConf is the path to the configuration of the host resolver
 	ResolvConf string `json:"resolv-conf,omitempty"`
 	Rootless   bool   `json:"rootless,omitempty"`
+	// AllowedHooks are the absolute paths of the binaries which containers
+	// may run as OCI lifecycle hooks. The arguments of the hooks are set by
+	// the containers, so only binaries which are safe to run with any
+	// arguments should be allowed. Containers can only set environment
+	// variables prefixed with HOOK_ for hooks.
+	AllowedHooks []string `json:"allowed-hooks,omitempty"`
 }
 
 // GetRuntime returns the runtime path and arguments for a given
@@ -154,11 +179,28 @@ func verifyDefaultCgroupNsMode(mode string) error {
 	return nil
 }
 
+func verifyAllowedHooks(paths []string) error {
+	for _, p := range paths {
+		if !filepath.IsAbs(p) || filepath.Clean(p) != p {
+			return fmt.Errorf("allowed hook %q must be a clean absolute path", p)
+		}
+	}
+	return nil
+}
+
 // ValidatePlatformConfig checks if any platform-specific configuration settings are invalid.
 func (conf *Config) ValidatePlatformConfig() error {
 	if err := verifyDefaultIpcMode(conf.IpcMode); err != nil {
 		return err
 	}
+	if conf.BridgeConfig.PublishedPortRange != "" {
+		if _, _, err := conf.BridgeConfig.PublishedPortRangeToInt(); err != nil {
+			return err
+		}
+	}
+	if err := verifyAllowedHooks(conf.AllowedHooks); err != nil {
+		return err
+	}
 
 	return verifyDefaultCgroupNsMode(conf.CgroupNamespaceMode)
 }
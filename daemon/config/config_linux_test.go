@@ -132,6 +132,27 @@ func TestUnixValidateConfigurationErrors(t *testing.T) {
 			},
 			expectedErr: `runtime name 'runc' is reserved`,
 		},
+		{
+			doc: `invalid published port range`,
+			config: &Config{
+				BridgeConfig: BridgeConfig{PublishedPortRange: "40999-40000"},
+			},
+			expectedErr: `invalid published port range "40999-40000"`,
+		},
+		{
+			doc: `published port range including port 0`,
+			config: &Config{
+				BridgeConfig: BridgeConfig{PublishedPortRange: "0-100"},
+			},
+			expectedErr: `invalid published port range "0-100"`,
+		},
+		{
+			doc: `relative allowed hook`,
+			config: &Config{
+				AllowedHooks: []string{"bin/device-setup"},
+			},
+			expectedErr: `allowed hook "bin/device-setup" must be a clean absolute path`,
+		},
 	}
 	for _, tc := range testCases {
 		tc := tc
@@ -145,6 +166,20 @@ func TestUnixValidateConfigurationErrors(t *testing.T) {
 	}
 }
 
+func TestPublishedPortRangeToInt(t *testing.T) {
+	conf := BridgeConfig{}
+	start, end, err := conf.PublishedPortRangeToInt()
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(start, 0))
+	assert.Check(t, is.Equal(end, 0))
+
+	conf.PublishedPortRange = "40000-40999"
+	start, end, err = conf.PublishedPortRangeToInt()
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(start, 40000))
+	assert.Check(t, is.Equal(end, 40999))
+}
+
 func TestUnixGetInitPath(t *testing.T) {
 	testCases := []struct {
 		config           *Config
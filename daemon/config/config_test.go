@@ -9,6 +9,7 @@ import (
 
 	"github.com/docker/docker/libnetwork/ipamutils"
 	"github.com/docker/docker/opts"
+	"github.com/docker/docker/registry"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/imdario/mergo"
@@ -318,6 +319,34 @@ func TestValidateConfigurationErrors(t *testing.T) {
 			},
 			expectedErr: "invalid max download attempts: -10",
 		},
+		{
+			name: "negative authorization-cache-ttl",
+			config: &Config{
+				CommonConfig: CommonConfig{
+					AuthorizationCacheTTL: -1,
+				},
+			},
+			expectedErr: "invalid authorization cache TTL: -1",
+		},
+		{
+			name: "negative idle-exit-timeout",
+			config: &Config{
+				CommonConfig: CommonConfig{
+					IdleExitTimeout: -1,
+				},
+			},
+			expectedErr: "invalid idle exit timeout: -1",
+		},
+		{
+			name: "idle-exit-timeout without socket activation",
+			config: &Config{
+				CommonConfig: CommonConfig{
+					IdleExitTimeout: 600,
+					Hosts:           []string{"fd://", "unix:///run/user/1000/docker.sock"},
+				},
+			},
+			expectedErr: "idle-exit-timeout requires the API to only listen on sockets activated by systemd (fd://): unix:///run/user/1000/docker.sock",
+		},
 		// TODO(thaJeztah) temporarily excluding this test as it assumes defaults are set before validating and applying updated configs
 		/*
 			{
@@ -367,6 +396,212 @@ func TestValidateConfigurationErrors(t *testing.T) {
 			},
 			expectedErr: "invalid logging level: foobar",
 		},
+		{
+			name: "with unsupported audit-log driver",
+			config: &Config{
+				CommonConfig: CommonConfig{
+					AuditLog: AuditLogConfig{Driver: "foobar"},
+				},
+			},
+			expectedErr: "audit-log: unsupported driver: foobar",
+		},
+		{
+			name: "with audit-log file driver without path",
+			config: &Config{
+				CommonConfig: CommonConfig{
+					AuditLog: AuditLogConfig{Driver: AuditLogDriverFile},
+				},
+			},
+			expectedErr: "audit-log: path is required for the file driver",
+		},
+		{
+			name: "with audit-log otlp driver without http address",
+			config: &Config{
+				CommonConfig: CommonConfig{
+					AuditLog: AuditLogConfig{Driver: AuditLogDriverOTLP, Address: "collector:4317"},
+				},
+			},
+			expectedErr: `audit-log: invalid address for the otlp driver: "collector:4317": must be an http or https URL`,
+		},
+		{
+			name: "with invalid socket-access default",
+			config: &Config{
+				CommonConfig: CommonConfig{
+					SocketAccess: SocketAccessConfig{Default: "maybe"},
+				},
+			},
+			expectedErr: `socket-access: invalid default: "maybe"`,
+		},
+		{
+			name: "with socket-access rule without users or groups",
+			config: &Config{
+				CommonConfig: CommonConfig{
+					SocketAccess: SocketAccessConfig{Rules: []SocketAccessRule{{Allow: []string{SocketAccessReadOnly}}}},
+				},
+			},
+			expectedErr: "socket-access: rule 0 must have users or groups",
+		},
+		{
+			name: "with invalid socket-access endpoint pattern",
+			config: &Config{
+				CommonConfig: CommonConfig{
+					SocketAccess: SocketAccessConfig{Rules: []SocketAccessRule{{Groups: []string{"observers"}, Allow: []string{"/containers/json"}}}},
+				},
+			},
+			expectedErr: `socket-access: rule 0: invalid endpoint pattern "/containers/json": must be in the form "METHOD PATH"`,
+		},
+		{
+			name: "with unsupported event sink type",
+			config: &Config{
+				CommonConfig: CommonConfig{
+					EventSinks: []EventSinkConfig{{Type: "kafka", URL: "http://localhost"}},
+				},
+			},
+			expectedErr: `event-sinks: unsupported sink type: "kafka"`,
+		},
+		{
+			name: "with invalid event sink URL",
+			config: &Config{
+				CommonConfig: CommonConfig{
+					EventSinks: []EventSinkConfig{{Type: EventSinkTypeWebhook, URL: "localhost:8080"}},
+				},
+			},
+			expectedErr: `event-sinks: invalid webhook URL: "localhost:8080"`,
+		},
+		{
+			name: "with unsupported prune schedule type",
+			config: &Config{
+				CommonConfig: CommonConfig{
+					PruneSchedules: []PruneScheduleConfig{{Type: "volumes", Interval: "24h"}},
+				},
+			},
+			expectedErr: `prune-schedules: unsupported type: "volumes"`,
+		},
+		{
+			name: "with too short prune schedule interval",
+			config: &Config{
+				CommonConfig: CommonConfig{
+					PruneSchedules: []PruneScheduleConfig{{Type: PruneTypeImages, Interval: "10s"}},
+				},
+			},
+			expectedErr: `prune-schedules: invalid interval for images: "10s": must be a duration of at least 1m`,
+		},
+		{
+			name: "with prune schedule jitter longer than the interval",
+			config: &Config{
+				CommonConfig: CommonConfig{
+					PruneSchedules: []PruneScheduleConfig{{Type: PruneTypeImages, Interval: "1h", Jitter: "2h"}},
+				},
+			},
+			expectedErr: `prune-schedules: invalid jitter for images: "2h": must be a positive duration not longer than the interval`,
+		},
+		{
+			name: "with duplicate prune schedule names",
+			config: &Config{
+				CommonConfig: CommonConfig{
+					PruneSchedules: []PruneScheduleConfig{
+						{Type: PruneTypeImages, Interval: "1h"},
+						{Type: PruneTypeImages, Interval: "24h", Filters: []string{"dangling=false"}},
+					},
+				},
+			},
+			expectedErr: `prune-schedules: duplicate schedule name: "images"`,
+		},
+		{
+			name: "with relative CSI driver address",
+			config: &Config{
+				CommonConfig: CommonConfig{
+					CSIDrivers: map[string]string{"ebs": "csi.sock"},
+				},
+			},
+			expectedErr: `csi-drivers: address of driver "ebs" must be an absolute path to a unix socket: "csi.sock"`,
+		},
+		{
+			name: "with invalid volume plugin timeout",
+			config: &Config{
+				CommonConfig: CommonConfig{
+					VolumePluginTimeouts: map[string]VolumePluginTimeoutsConfig{"nfs": {Mount: "10ms"}},
+				},
+			},
+			expectedErr: `volume-plugin-timeouts: invalid mount timeout for "nfs": "10ms": must be a duration of at least 1s`,
+		},
+		{
+			name: "with invalid ipam reconcile interval",
+			config: &Config{
+				CommonConfig: CommonConfig{
+					NetworkConfig: NetworkConfig{
+						IPAMReconcile: IPAMReconcileConfig{Interval: "1s"},
+					},
+				},
+			},
+			expectedErr: `ipam-reconcile: invalid interval: "1s": must be a duration of at least 1m`,
+		},
+		{
+			name: "with lb rebalance drain longer than the interval",
+			config: &Config{
+				CommonConfig: CommonConfig{
+					NetworkConfig: NetworkConfig{
+						LBRebalance: LBRebalanceConfig{Interval: "10m", Drain: "1h"},
+					},
+				},
+			},
+			expectedErr: `lb-rebalance: invalid drain: "1h": must be a duration shorter than the interval`,
+		},
+		{
+			name: "with global kv store without address",
+			config: &Config{
+				CommonConfig: CommonConfig{
+					NetworkConfig: NetworkConfig{
+						GlobalKVStore: GlobalKVStoreConfig{Provider: "etcd"},
+					},
+				},
+			},
+			expectedErr: `global-kv-store: address is required`,
+		},
+		{
+			name: "with global kv store with an invalid connection timeout",
+			config: &Config{
+				CommonConfig: CommonConfig{
+					NetworkConfig: NetworkConfig{
+						GlobalKVStore: GlobalKVStoreConfig{Provider: "etcd", Address: "host1:2379", ConnectionTimeout: "soon"},
+					},
+				},
+			},
+			expectedErr: `global-kv-store: invalid connection-timeout: "soon": must be a positive duration`,
+		},
+		{
+			name: "with invalid default address pools route overlap",
+			config: &Config{
+				CommonConfig: CommonConfig{
+					NetworkConfig: NetworkConfig{
+						DefaultAddressPoolsRouteOverlap: "warn",
+					},
+				},
+			},
+			expectedErr: `default-address-pools-route-overlap: invalid route overlap mode "warn": must be "skip", "fail" or "ignore"`,
+		},
+		{
+			name: "with host network in default networks",
+			config: &Config{
+				CommonConfig: CommonConfig{
+					NetworkConfig: NetworkConfig{
+						DefaultNetworks: []DefaultNetwork{{Networks: []string{"corp-bridge"}}, {Networks: []string{"host"}}},
+					},
+				},
+			},
+			expectedErr: `default-networks: invalid network "host": must be the name of a network`,
+		},
+		{
+			name: "with invalid registry credential helper",
+			config: &Config{
+				CommonConfig: CommonConfig{
+					ServiceOptions: registry.ServiceOptions{
+						CredentialHelpers: map[string]registry.CredentialHelper{"gcr.io": {Helper: "../gcr"}},
+					},
+				},
+			},
+			expectedErr: `invalid credential helper "../gcr" for registry gcr.io`,
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -610,6 +845,37 @@ func TestReloadWithDuplicateLabels(t *testing.T) {
 	assert.Check(t, reloaded)
 }
 
+func TestCheck(t *testing.T) {
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.Bool("debug", false, "")
+	flags.String("log-level", "", "")
+	flags.StringSlice("labels", nil, "")
+
+	conf, err := Check([]byte(`{"log-level": "debug", "labels": ["foo=bar"]}`), flags)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(conf.LogLevel, "debug"))
+	assert.Check(t, conf.IsValueSet("labels"))
+
+	conf, err = Check([]byte("  "), flags)
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(conf.ValuesSet, 0))
+
+	_, err = Check([]byte(`{wrong: "configuration"}`), flags)
+	assert.Check(t, is.ErrorContains(err, "invalid character"))
+
+	_, err = Check([]byte(`{"labels": ["foo=bar", "foo=baz"]}`), flags)
+	assert.Check(t, is.ErrorContains(err, "conflict labels for foo=baz and foo=bar"))
+
+	_, err = Check([]byte(`{"log-level": "verbose"}`), flags)
+	assert.Check(t, is.ErrorContains(err, "file configuration validation failed"))
+
+	assert.Check(t, flags.Set("debug", "true"))
+	_, err = Check([]byte(`{"debug": false}`), flags)
+	assert.Check(t, is.ErrorContains(err, "specified both as a flag and in the configuration file"))
+	// Checking the configuration does not update the flags.
+	assert.Check(t, is.Equal(flags.Lookup("debug").Value.String(), "true"))
+}
+
 func TestMaskURLCredentials(t *testing.T) {
 	tests := []struct {
 		rawURL    string
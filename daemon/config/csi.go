@@ -0,0 +1,21 @@
+package config // import "github.com/docker/docker/daemon/config"
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/daemon/names"
+	"github.com/pkg/errors"
+)
+
+func validateCSIDrivers(drivers map[string]string) error {
+	for name, address := range drivers {
+		if name == "local" || !names.RestrictedNamePattern.MatchString(name) {
+			return errors.Errorf("csi-drivers: invalid driver name: %q", name)
+		}
+		if !filepath.IsAbs(strings.TrimPrefix(address, "unix://")) {
+			return errors.Errorf("csi-drivers: address of driver %q must be an absolute path to a unix socket: %q", name, address)
+		}
+	}
+	return nil
+}
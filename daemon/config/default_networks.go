@@ -0,0 +1,35 @@
+package config // import "github.com/docker/docker/daemon/config"
+
+import (
+	"github.com/docker/docker/api/types/container"
+	"github.com/pkg/errors"
+)
+
+// DefaultNetwork is an entry of the chain of default networks, to which the
+// containers created without network configuration are connected. The first
+// entry whose networks all exist is used.
+type DefaultNetwork struct {
+	// Networks are the names of the networks. Containers are connected to
+	// all of them at once.
+	Networks []string `json:"networks"`
+}
+
+func validateDefaultNetworks(chain []DefaultNetwork) error {
+	for i, entry := range chain {
+		if len(entry.Networks) == 0 {
+			return errors.Errorf("default-networks: entry %d has no networks", i)
+		}
+		seen := make(map[string]bool, len(entry.Networks))
+		for _, name := range entry.Networks {
+			mode := container.NetworkMode(name)
+			if name == "" || mode.IsDefault() || mode.IsHost() || mode.IsNone() || mode.IsContainer() {
+				return errors.Errorf("default-networks: invalid network %q: must be the name of a network", name)
+			}
+			if seen[name] {
+				return errors.Errorf("default-networks: duplicate network %q in entry %d", name, i)
+			}
+			seen[name] = true
+		}
+	}
+	return nil
+}
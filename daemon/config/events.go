@@ -0,0 +1,56 @@
+package config // import "github.com/docker/docker/daemon/config"
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/docker/docker/api/types/filters"
+	"github.com/pkg/errors"
+)
+
+// EventSinkTypeWebhook is the event sink type that POSTs events to a URL.
+const EventSinkTypeWebhook = "webhook"
+
+// EventSinkConfig configures an external destination engine events are
+// exported to.
+type EventSinkConfig struct {
+	// Type is the type of the sink. Only "webhook" is currently supported:
+	// NATS and Kafka sinks need client libraries which are not vendored.
+	Type string `json:"type"`
+
+	// URL is the address events are delivered to.
+	URL string `json:"url"`
+
+	// Filters limits the events exported to the sink, using the same
+	// filters as the events API, in "key=value" form (for example
+	// "type=container").
+	Filters []string `json:"filters,omitempty"`
+}
+
+// GetFilters returns the filters of the sink as filters.Args.
+func (c EventSinkConfig) GetFilters() filters.Args {
+	f := filters.NewArgs()
+	for _, s := range c.Filters {
+		k, v, _ := strings.Cut(s, "=")
+		f.Add(strings.TrimSpace(k), strings.TrimSpace(v))
+	}
+	return f
+}
+
+func (c EventSinkConfig) validate() error {
+	switch c.Type {
+	case EventSinkTypeWebhook:
+	default:
+		return errors.Errorf("event-sinks: unsupported sink type: %q", c.Type)
+	}
+	u, err := url.Parse(c.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.Errorf("event-sinks: invalid webhook URL: %q", c.URL)
+	}
+	for _, f := range c.Filters {
+		if k, _, ok := strings.Cut(f, "="); !ok || strings.TrimSpace(k) == "" {
+			return errors.Errorf("event-sinks: invalid filter: %q", f)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package config // import "github.com/docker/docker/daemon/config"
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// validateIdleExitTimeout validates that the daemon only exits when idle if
+// systemd can start it again, which is when all the API sockets are activated
+// by systemd.
+func validateIdleExitTimeout(config *Config) error {
+	if config.IdleExitTimeout < 0 {
+		return errors.Errorf("invalid idle exit timeout: %d", config.IdleExitTimeout)
+	}
+	if config.IdleExitTimeout == 0 {
+		return nil
+	}
+	if len(config.Hosts) == 0 {
+		return errors.New("idle-exit-timeout requires the API to only listen on sockets activated by systemd (fd://)")
+	}
+	for _, h := range config.Hosts {
+		if !strings.HasPrefix(h, "fd://") {
+			return errors.Errorf("idle-exit-timeout requires the API to only listen on sockets activated by systemd (fd://): %s", h)
+		}
+	}
+	return nil
+}
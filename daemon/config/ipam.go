@@ -0,0 +1,40 @@
+package config // import "github.com/docker/docker/daemon/config"
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// IPAMReconcileConfig configures the periodic reconciliation of the addresses
+// and pools allocated by the IPAM drivers with the networks and endpoints,
+// which reports the allocations leaked by networks and endpoints which no
+// longer exist.
+type IPAMReconcileConfig struct {
+	// Interval is the time between two reconciliations, as a duration string
+	// (for example "1h"). Reconciliations are disabled if it is empty.
+	Interval string `json:"interval,omitempty"`
+
+	// Release releases the leaked allocations, once they were reported by
+	// two reconciliations in a row.
+	Release bool `json:"release,omitempty"`
+}
+
+// GetInterval returns the interval of the reconciliations.
+func (c IPAMReconcileConfig) GetInterval() time.Duration {
+	d, _ := time.ParseDuration(c.Interval)
+	return d
+}
+
+func validateIPAMReconcile(c IPAMReconcileConfig) error {
+	if c.Interval == "" {
+		if c.Release {
+			return errors.New("ipam-reconcile: release requires an interval")
+		}
+		return nil
+	}
+	if d, err := time.ParseDuration(c.Interval); err != nil || d < time.Minute {
+		return errors.Errorf("ipam-reconcile: invalid interval: %q: must be a duration of at least 1m", c.Interval)
+	}
+	return nil
+}
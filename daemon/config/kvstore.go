@@ -0,0 +1,68 @@
+package config // import "github.com/docker/docker/daemon/config"
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// GlobalKVStoreConfig configures the KV store backing the global scope of
+// the networks, which lets daemons outside of swarm mode share the networks
+// and endpoints of the global scope.
+type GlobalKVStoreConfig struct {
+	// Provider is the KV store provider, such as "etcd". The global scope
+	// has no store if it is empty.
+	Provider string `json:"provider,omitempty"`
+
+	// Address is the address of the store, as a comma separated list of
+	// endpoints, optionally followed by a slash and a key prefix (for
+	// example "host1:2379,host2:2379/docker").
+	Address string `json:"address,omitempty"`
+
+	// Username and Password authenticate the daemon with the store.
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+
+	// TLSCACert, TLSCert and TLSKey are the paths of the files of the TLS
+	// configuration of the connections to the store.
+	TLSCACert string `json:"tlscacert,omitempty"`
+	TLSCert   string `json:"tlscert,omitempty"`
+	TLSKey    string `json:"tlskey,omitempty"`
+
+	// ConnectionTimeout is the timeout of the requests to the store, as a
+	// duration string (for example "10s"). The default of the provider is
+	// used if it is empty.
+	ConnectionTimeout string `json:"connection-timeout,omitempty"`
+}
+
+// GetConnectionTimeout returns the timeout of the requests to the store.
+func (c GlobalKVStoreConfig) GetConnectionTimeout() time.Duration {
+	d, _ := time.ParseDuration(c.ConnectionTimeout)
+	return d
+}
+
+// HasTLS returns whether the connections to the store use TLS.
+func (c GlobalKVStoreConfig) HasTLS() bool {
+	return c.TLSCACert != "" || c.TLSCert != "" || c.TLSKey != ""
+}
+
+func validateGlobalKVStore(c GlobalKVStoreConfig) error {
+	if c.Provider == "" {
+		if c != (GlobalKVStoreConfig{}) {
+			return errors.New("global-kv-store: provider is required")
+		}
+		return nil
+	}
+	if c.Address == "" {
+		return errors.New("global-kv-store: address is required")
+	}
+	if (c.TLSCert == "") != (c.TLSKey == "") {
+		return errors.New("global-kv-store: tlscert and tlskey must be set together")
+	}
+	if c.ConnectionTimeout != "" {
+		if d, err := time.ParseDuration(c.ConnectionTimeout); err != nil || d <= 0 {
+			return errors.Errorf("global-kv-store: invalid connection-timeout: %q: must be a positive duration", c.ConnectionTimeout)
+		}
+	}
+	return nil
+}
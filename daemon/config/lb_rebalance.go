@@ -0,0 +1,52 @@
+package config // import "github.com/docker/docker/daemon/config"
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// LBRebalanceConfig configures the periodic rebalance of the load balancers
+// of the services, such as the routing mesh, which evens out the connections
+// among the backends after scaling events or node flaps.
+type LBRebalanceConfig struct {
+	// Interval is the time between two rebalances, as a duration string
+	// (for example "15m"). Rebalances are disabled if it is empty.
+	Interval string `json:"interval,omitempty"`
+
+	// Drain is how long the backends holding significantly more active
+	// connections than the others receive no new connections, as a
+	// duration string. Backends are not drained if it is empty.
+	Drain string `json:"drain,omitempty"`
+}
+
+// GetInterval returns the interval of the rebalances.
+func (c LBRebalanceConfig) GetInterval() time.Duration {
+	d, _ := time.ParseDuration(c.Interval)
+	return d
+}
+
+// GetDrain returns the drain period of the rebalances.
+func (c LBRebalanceConfig) GetDrain() time.Duration {
+	d, _ := time.ParseDuration(c.Drain)
+	return d
+}
+
+func validateLBRebalance(c LBRebalanceConfig) error {
+	if c.Interval == "" {
+		if c.Drain != "" {
+			return errors.New("lb-rebalance: drain requires an interval")
+		}
+		return nil
+	}
+	interval, err := time.ParseDuration(c.Interval)
+	if err != nil || interval < time.Minute {
+		return errors.Errorf("lb-rebalance: invalid interval: %q: must be a duration of at least 1m", c.Interval)
+	}
+	if c.Drain != "" {
+		if d, err := time.ParseDuration(c.Drain); err != nil || d < 0 || d >= interval {
+			return errors.Errorf("lb-rebalance: invalid drain: %q: must be a duration shorter than the interval", c.Drain)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package config // import "github.com/docker/docker/daemon/config"
+
+import (
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/filters"
+	"github.com/pkg/errors"
+)
+
+// Object types that can be pruned on a schedule.
+const (
+	PruneTypeContainers = "containers"
+	PruneTypeImages     = "images"
+	PruneTypeBuildCache = "build-cache"
+	PruneTypeNetworks   = "networks"
+)
+
+// PruneScheduleConfig configures the periodic pruning of unused objects of
+// a type.
+type PruneScheduleConfig struct {
+	// Name identifies the schedule in events and logs. It defaults to the
+	// type of the schedule.
+	Name string `json:"name,omitempty"`
+
+	// Type is the type of the objects to prune: "containers", "images",
+	// "build-cache", or "networks".
+	Type string `json:"type"`
+
+	// Interval is the time between two runs, as a duration string (for
+	// example "24h").
+	Interval string `json:"interval"`
+
+	// Jitter is the maximum random delay added to the interval, as a
+	// duration string, which spreads the runs of hosts sharing the same
+	// configuration.
+	Jitter string `json:"jitter,omitempty"`
+
+	// Filters limits the objects that are pruned, using the same filters
+	// as the prune API of the type, in "key=value" form (for example
+	// "until=168h").
+	Filters []string `json:"filters,omitempty"`
+
+	// DryRun only reports the objects that would be pruned, without
+	// removing them.
+	DryRun bool `json:"dry-run,omitempty"`
+}
+
+// GetName returns the name of the schedule.
+func (c PruneScheduleConfig) GetName() string {
+	if c.Name != "" {
+		return c.Name
+	}
+	return c.Type
+}
+
+// GetInterval returns the interval of the schedule.
+func (c PruneScheduleConfig) GetInterval() time.Duration {
+	d, _ := time.ParseDuration(c.Interval)
+	return d
+}
+
+// GetJitter returns the jitter of the schedule.
+func (c PruneScheduleConfig) GetJitter() time.Duration {
+	if c.Jitter == "" {
+		return 0
+	}
+	d, _ := time.ParseDuration(c.Jitter)
+	return d
+}
+
+// GetFilters returns the filters of the schedule as filters.Args.
+func (c PruneScheduleConfig) GetFilters() filters.Args {
+	f := filters.NewArgs()
+	for _, s := range c.Filters {
+		k, v, _ := strings.Cut(s, "=")
+		f.Add(strings.TrimSpace(k), strings.TrimSpace(v))
+	}
+	return f
+}
+
+func validatePruneSchedules(schedules []PruneScheduleConfig) error {
+	names := make(map[string]bool)
+	for _, c := range schedules {
+		switch c.Type {
+		case PruneTypeContainers, PruneTypeImages, PruneTypeBuildCache, PruneTypeNetworks:
+		default:
+			return errors.Errorf("prune-schedules: unsupported type: %q", c.Type)
+		}
+		if names[c.GetName()] {
+			return errors.Errorf("prune-schedules: duplicate schedule name: %q", c.GetName())
+		}
+		names[c.GetName()] = true
+
+		interval, err := time.ParseDuration(c.Interval)
+		if err != nil || interval < time.Minute {
+			return errors.Errorf("prune-schedules: invalid interval for %s: %q: must be a duration of at least 1m", c.GetName(), c.Interval)
+		}
+		if c.Jitter != "" {
+			jitter, err := time.ParseDuration(c.Jitter)
+			if err != nil || jitter < 0 || jitter > interval {
+				return errors.Errorf("prune-schedules: invalid jitter for %s: %q: must be a positive duration not longer than the interval", c.GetName(), c.Jitter)
+			}
+		}
+		for _, f := range c.Filters {
+			if k, _, ok := strings.Cut(f, "="); !ok || strings.TrimSpace(k) == "" {
+				return errors.Errorf("prune-schedules: invalid filter for %s: %q", c.GetName(), f)
+			}
+		}
+	}
+	return nil
+}
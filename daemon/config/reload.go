@@ -0,0 +1,95 @@
+package config // import "github.com/docker/docker/daemon/config"
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+)
+
+// reloadableOptions are the configuration options whose changes are applied
+// when the daemon reloads its configuration. Changes of other options only
+// take effect when the daemon is restarted.
+var reloadableOptions = map[string]bool{
+	"allow-nondistributable-artifacts":    true,
+	"allowed-hooks":                       true,
+	"authorization-cache-ttl":             true,
+	"authorization-plugins":               true,
+	"debug":                               true,
+	"default-address-pools":               true,
+	"default-address-pools-route-overlap": true,
+	"default-cgroupns-mode":               true,
+	"default-endpoint-opts":               true,
+	"default-ipc-mode":                    true,
+	"default-networks":                    true,
+	"default-runtime":                     true,
+	"default-shm-size":                    true,
+	"features":                            true,
+	"insecure-registries":                 true,
+	"labels":                              true,
+	"live-restore":                        true,
+	"log-driver":                          true,
+	"log-opts":                            true,
+	"max-concurrent-downloads":            true,
+	"max-concurrent-uploads":              true,
+	"max-download-attempts":               true,
+	"metrics-addr":                        true,
+	"network-diagnostic-port":             true,
+	"registry-credential-helpers":         true,
+	"registry-mirrors":                    true,
+	"runtimes":                            true,
+	"shutdown-timeout":                    true,
+	"socket-access":                       true,
+	"tlscacert":                           true,
+	"tlscert":                             true,
+	"tlskey":                              true,
+	"volume-plugin-timeouts":              true,
+}
+
+// ChangedOptions returns the options set in the configuration file of
+// updated whose value differs from the current configuration, sorted, and
+// split between the options applied on reload, and the options that need a
+// restart of the daemon.
+func ChangedOptions(current, updated *Config) (reloaded, restartRequired []string, err error) {
+	b, err := json.Marshal(current)
+	if err != nil {
+		return nil, nil, err
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, nil, err
+	}
+	currentValues := configValuesSet(m)
+
+	for key, v := range updated.ValuesSet {
+		if sameValue(currentValues[key], v) {
+			continue
+		}
+		if reloadableOptions[key] {
+			reloaded = append(reloaded, key)
+		} else {
+			restartRequired = append(restartRequired, key)
+		}
+	}
+	sort.Strings(reloaded)
+	sort.Strings(restartRequired)
+	return reloaded, restartRequired, nil
+}
+
+// sameValue returns whether two JSON-decoded values are the same, treating
+// zero values as the same as values omitted from the current configuration.
+func sameValue(current, updated interface{}) bool {
+	if current == nil {
+		return updated == nil || reflect.ValueOf(updated).IsZero() || isEmpty(updated)
+	}
+	return reflect.DeepEqual(current, updated)
+}
+
+func isEmpty(v interface{}) bool {
+	switch v := v.(type) {
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+	return false
+}
@@ -0,0 +1,32 @@
+package config // import "github.com/docker/docker/daemon/config"
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestChangedOptions(t *testing.T) {
+	current, err := New()
+	assert.NilError(t, err)
+	current.Debug = true
+	current.Root = "/var/lib/docker"
+	current.Mirrors = []string{"https://mirror.example.com"}
+
+	// ValuesSet holds the options set in the configuration file, as decoded
+	// from JSON.
+	updated := &Config{ValuesSet: map[string]interface{}{
+		"debug":            true,
+		"data-root":        "/srv/docker",
+		"registry-mirrors": []interface{}{"https://other.example.com"},
+		"log-driver":       "local",
+		"log-opts":         map[string]interface{}{"max-size": "10m"},
+		"experimental":     false,
+	}}
+
+	reloaded, restartRequired, err := ChangedOptions(current, updated)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(reloaded, []string{"log-driver", "log-opts", "registry-mirrors"}))
+	assert.Check(t, is.DeepEqual(restartRequired, []string{"data-root"}))
+}
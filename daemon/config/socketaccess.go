@@ -0,0 +1,97 @@
+package config // import "github.com/docker/docker/daemon/config"
+
+import (
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Socket access decisions for callers that don't match any rule.
+const (
+	SocketAccessAllow = "allow"
+	SocketAccessDeny  = "deny"
+)
+
+// SocketAccessReadOnly is an endpoint pattern allowing GET and HEAD requests
+// to the inspect, list and log endpoints.
+const SocketAccessReadOnly = "read-only"
+
+// SocketAccessConfig restricts the API endpoints that local users can call
+// over a unix socket, based on the credentials of the calling process.
+// Requests from root, and requests over TCP, are not restricted.
+type SocketAccessConfig struct {
+	// Rules are matched in order, and the first rule matching the caller
+	// applies.
+	Rules []SocketAccessRule `json:"rules,omitempty"`
+
+	// Default is the access of callers that don't match any rule, either
+	// "allow" (the default), or "deny".
+	Default string `json:"default,omitempty"`
+}
+
+// SocketAccessRule lists the endpoints a set of users and groups can call.
+type SocketAccessRule struct {
+	// Users holds the names or uids the rule applies to.
+	Users []string `json:"users,omitempty"`
+
+	// Groups holds the names or gids the rule applies to. A caller matches
+	// if any of its groups is listed.
+	Groups []string `json:"groups,omitempty"`
+
+	// Allow holds the endpoints matching callers can call, in the form
+	// "METHOD PATH" (for example, "GET /containers/json"). The method can
+	// be "*" to match any method. In the path, "*" matches a single path
+	// element, a trailing "/**" matches any path below the prefix, and "*"
+	// alone matches any path. The path is matched without the API version
+	// prefix. "read-only" allows GET and HEAD requests to the inspect, list
+	// and log endpoints, but not to endpoints such as attach or archive.
+	Allow []string `json:"allow,omitempty"`
+}
+
+// Enabled returns whether a socket access policy is configured.
+func (c SocketAccessConfig) Enabled() bool {
+	return len(c.Rules) > 0 || c.Default == SocketAccessDeny
+}
+
+func (c SocketAccessConfig) validate() error {
+	switch c.Default {
+	case "", SocketAccessAllow, SocketAccessDeny:
+	default:
+		return errors.Errorf("socket-access: invalid default: %q", c.Default)
+	}
+	for i, r := range c.Rules {
+		if len(r.Users) == 0 && len(r.Groups) == 0 {
+			return errors.Errorf("socket-access: rule %d must have users or groups", i)
+		}
+		for _, p := range r.Allow {
+			if err := validateSocketAccessPattern(p); err != nil {
+				return errors.Wrapf(err, "socket-access: rule %d", i)
+			}
+		}
+	}
+	return nil
+}
+
+func validateSocketAccessPattern(p string) error {
+	if p == SocketAccessReadOnly {
+		return nil
+	}
+	method, pth, ok := strings.Cut(p, " ")
+	if !ok || method == "" || pth == "" {
+		return errors.Errorf("invalid endpoint pattern %q: must be in the form \"METHOD PATH\"", p)
+	}
+	if method != "*" && strings.ToUpper(method) != method {
+		return errors.Errorf("invalid endpoint pattern %q: method must be uppercase", p)
+	}
+	if pth == "*" {
+		return nil
+	}
+	if !strings.HasPrefix(pth, "/") {
+		return errors.Errorf("invalid endpoint pattern %q: path must start with /", p)
+	}
+	if _, err := path.Match(strings.TrimSuffix(pth, "/**"), ""); err != nil {
+		return errors.Errorf("invalid endpoint pattern %q: %v", p, err)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package config // import "github.com/docker/docker/daemon/config"
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// VolumePluginTimeoutsConfig configures the timeouts of the calls the daemon
+// makes to a volume plugin, as duration strings (for example "30s"). Unset
+// timeouts keep their default.
+type VolumePluginTimeoutsConfig struct {
+	Create  string `json:"create,omitempty"`
+	Remove  string `json:"remove,omitempty"`
+	Mount   string `json:"mount,omitempty"`
+	Unmount string `json:"unmount,omitempty"`
+
+	// Other is the timeout of the other calls, such as listing and
+	// inspecting volumes.
+	Other string `json:"other,omitempty"`
+
+	// Async is how long the daemon waits for a mount or unmount which the
+	// plugin reported as pending.
+	Async string `json:"async,omitempty"`
+}
+
+func validateVolumePluginTimeouts(timeouts map[string]VolumePluginTimeoutsConfig) error {
+	for name, c := range timeouts {
+		if name == "" {
+			return errors.New("volume-plugin-timeouts: plugin name cannot be empty")
+		}
+		for _, t := range []struct{ key, value string }{
+			{"create", c.Create},
+			{"remove", c.Remove},
+			{"mount", c.Mount},
+			{"unmount", c.Unmount},
+			{"other", c.Other},
+			{"async", c.Async},
+		} {
+			if t.value == "" {
+				continue
+			}
+			if d, err := time.ParseDuration(t.value); err != nil || d < time.Second {
+				return errors.Errorf("volume-plugin-timeouts: invalid %s timeout for %q: %q: must be a duration of at least 1s", t.key, name, t.value)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/daemon/config"
+	"github.com/docker/docker/daemon/graphdriver"
+	"github.com/docker/docker/daemon/logger"
+	"github.com/docker/docker/libnetwork/netutils"
+	"github.com/docker/docker/pkg/authorization"
+	"github.com/docker/docker/pkg/plugingetter"
+)
+
+// CheckConfig checks the contents of a proposed configuration file against
+// the running daemon, without applying them. Problems with the configuration
+// are reported in the result, not as an error.
+func (daemon *Daemon) CheckConfig(data []byte) (types.ConfigCheck, error) {
+	var result types.ConfigCheck
+	conf, err := config.Check(data, daemon.configFlags)
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+		return result, nil
+	}
+
+	daemon.configStore.Lock()
+	result.Reloadable, result.RestartRequired, err = config.ChangedOptions(daemon.configStore, conf)
+	daemon.configStore.Unlock()
+	if err != nil {
+		return types.ConfigCheck{}, err
+	}
+
+	if err := daemon.checkLogConfig(conf); err != nil {
+		result.Errors = append(result.Errors, err.Error())
+	}
+	if err := daemon.checkStorageDriver(conf); err != nil {
+		result.Errors = append(result.Errors, err.Error())
+	}
+	if err := daemon.checkAuthzPlugins(conf); err != nil {
+		result.Errors = append(result.Errors, err.Error())
+	}
+	warnings, err := daemon.checkDefaultAddressPools(conf)
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+	}
+	result.Warnings = append(result.Warnings, warnings...)
+	return result, nil
+}
+
+// checkLogConfig checks that the default log driver is available, and
+// accepts the default log options, following the rules of reloadLogConfig.
+func (daemon *Daemon) checkLogConfig(conf *config.Config) error {
+	if !conf.IsValueSet("log-driver") && !conf.IsValueSet("log-opts") {
+		return nil
+	}
+	logConfig := daemon.getDefaultLogConfig()
+	if conf.IsValueSet("log-driver") {
+		logConfig.Type = conf.LogConfig.Type
+		logConfig.Config = nil
+	}
+	if conf.IsValueSet("log-opts") {
+		logConfig.Config = conf.LogConfig.Config
+	}
+	return logger.ValidateLogOpts(logConfig.Type, logConfig.Config)
+}
+
+// checkStorageDriver checks that the storage driver is either a built-in
+// driver, or provided by a plugin.
+func (daemon *Daemon) checkStorageDriver(conf *config.Config) error {
+	if !conf.IsValueSet("storage-driver") || daemon.UsesSnapshotter() || graphdriver.IsRegistered(conf.GraphDriver) {
+		return nil
+	}
+	if daemon.PluginStore != nil {
+		if _, err := daemon.PluginStore.Get(conf.GraphDriver, "GraphDriver", plugingetter.Lookup); err == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("storage driver %s is not available", conf.GraphDriver)
+}
+
+// checkAuthzPlugins checks that the authorization plugins are installed.
+func (daemon *Daemon) checkAuthzPlugins(conf *config.Config) error {
+	if !conf.IsValueSet("authorization-plugins") || daemon.PluginStore == nil {
+		return nil
+	}
+	for _, name := range conf.AuthorizationPlugins {
+		if _, err := daemon.PluginStore.Get(name, authorization.AuthZApiImplementsV2, plugingetter.Lookup); err == nil {
+			continue
+		}
+		if _, err := daemon.PluginStore.Get(name, authorization.AuthZApiImplements, plugingetter.Lookup); err != nil {
+			return fmt.Errorf("authorization plugin %s is not available: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// checkDefaultAddressPools returns a warning for every default address pool
+// that overlaps the subnet of an existing network.
+func (daemon *Daemon) checkDefaultAddressPools(conf *config.Config) ([]string, error) {
+	if !conf.IsValueSet("default-address-pools") || daemon.netController == nil {
+		return nil, nil
+	}
+	var warnings []string
+	for _, p := range conf.DefaultAddressPools.Value() {
+		_, base, err := net.ParseCIDR(p.Base)
+		if err != nil {
+			return nil, fmt.Errorf("invalid default address pool %s: %v", p.Base, err)
+		}
+		for _, nw := range daemon.netController.Networks() {
+			v4, v6 := nw.Info().IpamInfo()
+			for _, info := range append(v4, v6...) {
+				if info.Pool != nil && netutils.NetworkOverlaps(base, info.Pool) {
+					warnings = append(warnings, fmt.Sprintf("default address pool %s overlaps with subnet %s of network %s", p.Base, info.Pool, nw.Name()))
+				}
+			}
+		}
+	}
+	return warnings, nil
+}
@@ -0,0 +1,52 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"testing"
+
+	"github.com/docker/docker/daemon/config"
+	"github.com/spf13/pflag"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestDaemonCheckConfig(t *testing.T) {
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.Bool("debug", false, "")
+	flags.String("data-root", "", "")
+	flags.String("log-driver", "", "")
+	flags.String("storage-driver", "", "")
+	assert.NilError(t, flags.Set("debug", "true"))
+
+	daemon := &Daemon{
+		configStore: &config.Config{
+			CommonConfig: config.CommonConfig{
+				LogConfig: config.LogConfig{Type: "json-file"},
+			},
+		},
+		configFlags: flags,
+	}
+	assert.NilError(t, daemon.setupDefaultLogConfig())
+	muteLogs()
+
+	result, err := daemon.CheckConfig([]byte(`{"log-driver": "local", "data-root": "/srv/docker"}`))
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(result.Errors, 0))
+	assert.Check(t, is.DeepEqual(result.Reloadable, []string{"log-driver"}))
+	assert.Check(t, is.DeepEqual(result.RestartRequired, []string{"data-root"}))
+
+	result, err = daemon.CheckConfig([]byte(`{"debug": false}`))
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(result.Errors, 1))
+	assert.Check(t, is.Contains(result.Errors[0], "specified both as a flag and in the configuration file"))
+
+	result, err = daemon.CheckConfig([]byte(`{"log-driver": "no-such-driver", "storage-driver": "no-such-driver"}`))
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(result.Errors, []string{
+		"logger: no log driver named 'no-such-driver' is registered",
+		"storage driver no-such-driver is not available",
+	}))
+
+	// The configuration of the daemon is unchanged.
+	assert.Check(t, is.Equal(daemon.getDefaultLogConfig().Type, "json-file"))
+	assert.Check(t, is.Equal(daemon.configStore.Root, ""))
+}
@@ -2,9 +2,11 @@ package daemon // import "github.com/docker/docker/daemon"
 
 import (
 	"fmt"
+	"net"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"time"
 
 	containertypes "github.com/docker/docker/api/types/container"
@@ -39,13 +41,14 @@ func (daemon *Daemon) GetContainer(prefixOrName string) (*container.Container, e
 
 	if containerByID := daemon.containers.Get(prefixOrName); containerByID != nil {
 		// prefix is an exact match to a full container ID
-		return containerByID, nil
+		return containerByID, daemon.containerConfigs.use(containerByID)
 	}
 
 	// GetByName will match only an exact name provided; we ignore errors
-	if containerByName, _ := daemon.GetByName(prefixOrName); containerByName != nil {
+	// other than those loading its configuration
+	if containerByName, err := daemon.GetByName(prefixOrName); containerByName != nil {
 		// prefix is an exact match to a full container Name
-		return containerByName, nil
+		return containerByName, err
 	}
 
 	containerID, err := daemon.containersReplica.GetByPrefix(prefixOrName)
@@ -63,7 +66,7 @@ func (daemon *Daemon) GetContainer(prefixOrName string) (*container.Container, e
 			Debugf("daemon.GetContainer: container is known to daemon.containersReplica but not daemon.containers")
 		return nil, containerNotFound(prefixOrName)
 	}
-	return ctr, nil
+	return ctr, daemon.containerConfigs.use(ctr)
 }
 
 // checkContainer make sure the specified container validates the specified conditions
@@ -183,7 +186,7 @@ func (daemon *Daemon) GetByName(name string) (*container.Container, error) {
 	if e == nil {
 		return nil, fmt.Errorf("Could not find container for entity id %s", id)
 	}
-	return e, nil
+	return e, daemon.containerConfigs.use(e)
 }
 
 // newBaseContainer creates a new container with its initial
@@ -242,6 +245,12 @@ func (daemon *Daemon) verifyContainerSettings(hostConfig *containertypes.HostCon
 	if err := validateHostConfig(hostConfig); err != nil {
 		return warnings, err
 	}
+	if err := daemon.validateLocalSecretReferences(hostConfig); err != nil {
+		return warnings, err
+	}
+	if err := daemon.validateGroupMember(hostConfig); err != nil {
+		return warnings, err
+	}
 
 	// Now do platform-specific verification
 	warnings, err = verifyPlatformContainerSettings(daemon, hostConfig, update)
@@ -299,6 +308,9 @@ func validateHostConfig(hostConfig *containertypes.HostConfig) error {
 	if err := validateRestartPolicy(hostConfig.RestartPolicy); err != nil {
 		return err
 	}
+	if err := validateLifetime(hostConfig.Lifetime); err != nil {
+		return err
+	}
 	if err := validateCapabilities(hostConfig); err != nil {
 		return err
 	}
@@ -350,11 +362,29 @@ func validatePortBindings(ports nat.PortMap) error {
 			if err != nil {
 				return errors.Errorf("invalid port specification: %q", pb.HostPort)
 			}
+			if err := validatePortBindingHost(pb.HostIP); err != nil {
+				return err
+			}
 		}
 	}
 	return nil
 }
 
+// validatePortBindingHost validates the host side of a port binding, which is
+// either an IP address or the name of a host interface.
+func validatePortBindingHost(host string) error {
+	if host == "" || net.ParseIP(host) != nil {
+		return nil
+	}
+	if runtime.GOOS != "linux" {
+		return errors.Errorf("invalid host IP address: %q", host)
+	}
+	if len(host) > 15 || strings.ContainsAny(host, "/: \t\n") {
+		return errors.Errorf("invalid host IP address or interface name: %q", host)
+	}
+	return nil
+}
+
 func validateRestartPolicy(policy containertypes.RestartPolicy) error {
 	switch policy.Name {
 	case "always", "unless-stopped", "no":
@@ -374,6 +404,29 @@ func validateRestartPolicy(policy containertypes.RestartPolicy) error {
 	return nil
 }
 
+func validateLifetime(lifetime *containertypes.Lifetime) error {
+	if lifetime == nil {
+		return nil
+	}
+	if lifetime.MaxRuntime < 0 {
+		return errors.Errorf("max runtime in lifetime cannot be negative")
+	}
+	if lifetime.MaxRuntime == 0 && lifetime.Deadline == nil {
+		return errors.Errorf("lifetime requires a max runtime or a deadline")
+	}
+	switch lifetime.Action {
+	case "", containertypes.LifetimeActionStop, containertypes.LifetimeActionRemove:
+	default:
+		return errors.Errorf("invalid lifetime action '%s'", lifetime.Action)
+	}
+	if lifetime.StopSignal != "" {
+		if _, err := signal.ParseSignal(lifetime.StopSignal); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // translateWorkingDir translates the working-dir for the target platform,
 // and returns an error if the given path is not an absolute path.
 func translateWorkingDir(config *containertypes.Config) error {
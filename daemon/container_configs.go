@@ -0,0 +1,130 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// containerConfigsSize is the number of containers which are not
+	// running whose full configuration is kept in memory.
+	containerConfigsSize = 256
+	// containerConfigsIdle is how long the full configuration of a
+	// container which is not running is kept in memory after its last use,
+	// when more than containerConfigsSize of them are.
+	containerConfigsIdle = 5 * time.Minute
+)
+
+// containerConfigs keeps track of the use of the containers, to release the
+// configuration of the least recently used ones which are not running. On
+// hosts with many exited containers, this avoids keeping all their
+// configurations in memory. Listing and filtering containers only uses the
+// fields kept by container.Compact, and the snapshots of the ViewDB.
+type containerConfigs struct {
+	mu       sync.Mutex
+	size     int
+	idle     time.Duration
+	lastUsed map[string]time.Time
+}
+
+func newContainerConfigs(size int, idle time.Duration) *containerConfigs {
+	return &containerConfigs{
+		size:     size,
+		idle:     idle,
+		lastUsed: make(map[string]time.Time),
+	}
+}
+
+// use reads back the configuration of the container if it was released,
+// and records its use.
+func (cc *containerConfigs) use(ctr *container.Container) error {
+	if cc != nil {
+		cc.mu.Lock()
+		cc.lastUsed[ctr.ID] = time.Now()
+		cc.mu.Unlock()
+	}
+	if err := ctr.Hydrate(); err != nil {
+		return errdefs.System(errors.Wrapf(err, "failed to load the configuration of container %s", ctr.ID))
+	}
+	return nil
+}
+
+// forget stops tracking a removed container.
+func (cc *containerConfigs) forget(id string) {
+	if cc == nil {
+		return
+	}
+	cc.mu.Lock()
+	delete(cc.lastUsed, id)
+	cc.mu.Unlock()
+}
+
+// compact releases the configuration of the containers which are not
+// running, except for the most recently used ones and those used less than
+// the idle duration ago. It returns the number of compacted containers.
+func (cc *containerConfigs) compact(containers []*container.Container) int {
+	cc.mu.Lock()
+	lastUsed := make(map[string]time.Time, len(cc.lastUsed))
+	for id, t := range cc.lastUsed {
+		lastUsed[id] = t
+	}
+	cc.mu.Unlock()
+
+	var candidates []*container.Container
+	for _, ctr := range containers {
+		if !ctr.IsCompacted() && !ctr.IsRunning() {
+			candidates = append(candidates, ctr)
+		}
+	}
+	if len(candidates) <= cc.size {
+		return 0
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return lastUsed[candidates[i].ID].After(lastUsed[candidates[j].ID])
+	})
+
+	var compacted int
+	for _, ctr := range candidates[cc.size:] {
+		// Skip the containers which are being operated on; they are
+		// compacted by a later pass.
+		if !ctr.TryLock() {
+			continue
+		}
+		if !ctr.Running && !ctr.Paused && !ctr.Restarting && !ctr.RemovalInProgress && cc.compactIdle(ctr) {
+			compacted++
+		}
+		ctr.Unlock()
+	}
+	return compacted
+}
+
+// compactIdle compacts the container if it was not used for the idle
+// duration. The use of the container is checked again, as it may have been
+// used since the candidates were selected.
+func (cc *containerConfigs) compactIdle(ctr *container.Container) bool {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	if time.Since(cc.lastUsed[ctr.ID]) < cc.idle {
+		return false
+	}
+	ctr.Compact()
+	delete(cc.lastUsed, ctr.ID)
+	return true
+}
+
+// containerConfigGC runs a ticker to release the configuration of the
+// containers which are not running and were not used recently.
+func (daemon *Daemon) containerConfigGC() {
+	for {
+		if n := daemon.containerConfigs.compact(daemon.containers.List()); n > 0 {
+			logrus.Debugf("released the configuration of %d containers", n)
+		}
+		time.Sleep(time.Minute)
+	}
+}
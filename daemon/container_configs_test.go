@@ -0,0 +1,69 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"testing"
+	"time"
+
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/container"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func newSavedContainer(t *testing.T, id string) *container.Container {
+	t.Helper()
+	c := container.NewBaseContainer(id, t.TempDir())
+	c.Config = &containertypes.Config{
+		Image: "busybox",
+		Env:   []string{"FOO=bar"},
+	}
+	c.HostConfig = &containertypes.HostConfig{}
+	db, err := container.NewViewDB()
+	assert.NilError(t, err)
+	assert.NilError(t, c.CheckpointTo(db))
+	return c
+}
+
+func TestContainerConfigsCompact(t *testing.T) {
+	cc := newContainerConfigs(1, 0)
+
+	recent := newSavedContainer(t, "recent")
+	exited := newSavedContainer(t, "exited")
+	running := newSavedContainer(t, "running")
+	running.Running = true
+	containers := []*container.Container{exited, recent, running}
+
+	assert.NilError(t, cc.use(exited))
+	assert.NilError(t, cc.use(recent))
+
+	assert.Check(t, is.Equal(cc.compact(containers), 1))
+	assert.Check(t, exited.IsCompacted())
+	assert.Check(t, is.Nil(exited.Config.Env))
+	assert.Check(t, is.Equal(exited.Config.Image, "busybox"))
+	assert.Check(t, !recent.IsCompacted())
+	assert.Check(t, !running.IsCompacted())
+
+	// Using the container reads back its configuration, and the least
+	// recently used one is compacted instead.
+	assert.NilError(t, cc.use(exited))
+	assert.Check(t, !exited.IsCompacted())
+	assert.Check(t, is.DeepEqual(exited.Config.Env, []string{"FOO=bar"}))
+
+	assert.Check(t, is.Equal(cc.compact(containers), 1))
+	assert.Check(t, recent.IsCompacted())
+	assert.Check(t, !exited.IsCompacted())
+}
+
+func TestContainerConfigsCompactIdle(t *testing.T) {
+	cc := newContainerConfigs(0, time.Hour)
+
+	c := newSavedContainer(t, "idle")
+	assert.NilError(t, cc.use(c))
+	assert.Check(t, is.Equal(cc.compact([]*container.Container{c}), 0))
+	assert.Check(t, !c.IsCompacted())
+
+	// Containers which were never used are idle.
+	cc.forget(c.ID)
+	assert.Check(t, is.Equal(cc.compact([]*container.Container{c}), 1))
+	assert.Check(t, c.IsCompacted())
+}
@@ -3,7 +3,6 @@ package daemon // import "github.com/docker/docker/daemon"
 import (
 	"errors"
 	"fmt"
-	"net"
 	"os"
 	"path"
 	"strings"
@@ -55,9 +54,10 @@ func (daemon *Daemon) buildSandboxOptions(container *container.Container) ([]lib
 
 	if container.HostConfig.NetworkMode.IsHost() {
 		sboxOptions = append(sboxOptions, libnetwork.OptionUseDefaultSandbox())
-	} else {
+	} else if !daemon.isGroupSandbox(container) {
 		// OptionUseExternalKey is mandatory for userns support.
-		// But optional for non-userns support
+		// But optional for non-userns support. The network namespace of a
+		// group is not created by a container, so libnetwork creates it.
 		sboxOptions = append(sboxOptions, libnetwork.OptionUseExternalKey())
 	}
 
@@ -161,7 +161,7 @@ func (daemon *Daemon) buildSandboxOptions(container *container.Container) ([]lib
 			}
 			pbCopy.HostPort = uint16(portStart)
 			pbCopy.HostPortEnd = uint16(portEnd)
-			pbCopy.HostIP = net.ParseIP(binding[i].HostIP)
+			setPortBindingHost(&pbCopy, binding[i].HostIP)
 			pbList = append(pbList, pbCopy)
 		}
 
@@ -452,10 +452,15 @@ func (daemon *Daemon) updateContainerNetworkSettings(container *container.Contai
 	var n libnetwork.Network
 
 	mode := container.HostConfig.NetworkMode
-	if container.Config.NetworkDisabled || mode.IsContainer() {
+	if container.Config.NetworkDisabled || mode.IsContainer() || mode.IsGroup() {
 		return
 	}
 
+	if mode.IsDefault() && len(endpointsConfig) == 0 {
+		mode, endpointsConfig = daemon.defaultNetworkConfig()
+		container.HostConfig.NetworkMode = mode
+	}
+
 	networkName := mode.NetworkName()
 	if mode.IsDefault() {
 		networkName = daemon.netController.Config().DefaultNetwork
@@ -519,6 +524,71 @@ func (daemon *Daemon) updateContainerNetworkSettings(container *container.Contai
 	}
 }
 
+// defaultNetworkConfig returns the network mode and the endpoint settings of
+// a container created without network configuration. The container is
+// connected to the networks of the first usable entry of the chain of default
+// networks, or else to the default network of the daemon, with the default
+// endpoint options of their drivers.
+func (daemon *Daemon) defaultNetworkConfig() (containertypes.NetworkMode, map[string]*networktypes.EndpointSettings) {
+	mode := containertypes.NetworkMode("default")
+	if daemon.netController == nil {
+		return mode, nil
+	}
+	networks := daemon.defaultNetworks()
+	if len(networks) > 0 {
+		mode = containertypes.NetworkMode(networks[0].Name())
+	} else {
+		n, err := daemon.FindNetwork(daemon.netController.Config().DefaultNetwork)
+		if err != nil || daemon.defaultEndpointOpts(n.Type()) == nil {
+			return mode, nil
+		}
+		networks = append(networks, n)
+	}
+
+	endpointsConfig := make(map[string]*networktypes.EndpointSettings, len(networks))
+	for _, n := range networks {
+		endpointsConfig[n.Name()] = &networktypes.EndpointSettings{
+			DriverOpts: daemon.defaultEndpointOpts(n.Type()),
+		}
+	}
+	return mode, endpointsConfig
+}
+
+// defaultNetworks returns the networks of the first entry of the chain of
+// default networks whose networks all exist, or nil if there is none.
+func (daemon *Daemon) defaultNetworks() []libnetwork.Network {
+	for _, entry := range daemon.configStore.DefaultNetworks {
+		networks := make([]libnetwork.Network, 0, len(entry.Networks))
+		for _, name := range entry.Networks {
+			n, err := daemon.FindNetwork(name)
+			if err != nil {
+				logrus.WithError(err).Debugf("Skipping default networks %v", entry.Networks)
+				networks = nil
+				break
+			}
+			networks = append(networks, n)
+		}
+		if len(networks) > 0 {
+			return networks
+		}
+	}
+	return nil
+}
+
+// defaultEndpointOpts returns a copy of the default endpoint options of a
+// network driver.
+func (daemon *Daemon) defaultEndpointOpts(driver string) map[string]string {
+	opts := daemon.configStore.DefaultEndpointOpts[driver]
+	if len(opts) == 0 {
+		return nil
+	}
+	c := make(map[string]string, len(opts))
+	for k, v := range opts {
+		c[k] = v
+	}
+	return c
+}
+
 func (daemon *Daemon) allocateNetwork(container *container.Container) (retErr error) {
 	if daemon.netController == nil {
 		return nil
@@ -534,7 +604,7 @@ func (daemon *Daemon) allocateNetwork(container *container.Container) (retErr er
 		logrus.WithError(err).Errorf("failed to cleanup up stale network sandbox for container %s", container.ID)
 	}
 
-	if container.Config.NetworkDisabled || container.HostConfig.NetworkMode.IsContainer() {
+	if container.Config.NetworkDisabled || container.HostConfig.NetworkMode.IsContainer() || container.HostConfig.NetworkMode.IsGroup() {
 		return nil
 	}
 
@@ -632,6 +702,23 @@ func validateNetworkingConfig(n libnetwork.Network, epConfig *networktypes.Endpo
 			return runconfig.ErrUnsupportedNetworkAndAlias
 		}
 	}
+	// The options of aliases are only known to the node of the container,
+	// not propagated to the other nodes of multi-host networks.
+	if len(epConfig.AliasOptions) > 0 && n.Info().Scope() != netconst.LocalScope {
+		return errdefs.InvalidParameter(fmt.Errorf("alias options are only supported on local networks, and network %s has %s scope", n.Name(), n.Info().Scope()))
+	}
+	for alias := range epConfig.AliasOptions {
+		var found bool
+		for _, a := range epConfig.Aliases {
+			if a == alias {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return errdefs.InvalidParameter(fmt.Errorf("options set for %q, which is not an alias of the container on network %s", alias, n.Name()))
+		}
+	}
 	if !hasUserDefinedIPAddress(epConfig.IPAMConfig) {
 		return nil
 	}
@@ -724,7 +811,7 @@ func (daemon *Daemon) updateNetworkConfig(container *container.Container, n libn
 
 func (daemon *Daemon) connectToNetwork(container *container.Container, idOrName string, endpointConfig *networktypes.EndpointSettings, updateSettings bool) (err error) {
 	start := time.Now()
-	if container.HostConfig.NetworkMode.IsContainer() {
+	if container.HostConfig.NetworkMode.IsContainer() || container.HostConfig.NetworkMode.IsGroup() {
 		return runconfig.ErrConflictSharedNetwork
 	}
 	if containertypes.NetworkMode(idOrName).IsBridge() &&
@@ -966,6 +1053,10 @@ func (daemon *Daemon) initializeNetworking(container *container.Container) error
 		return nil
 	}
 
+	if container.HostConfig.NetworkMode.IsGroup() {
+		return daemon.joinGroupNetwork(container)
+	}
+
 	if container.HostConfig.NetworkMode.IsHost() {
 		if container.Config.Hostname == "" {
 			container.Config.Hostname, err = os.Hostname()
@@ -1005,7 +1096,7 @@ func (daemon *Daemon) releaseNetwork(container *container.Container) {
 	if daemon.netController == nil {
 		return
 	}
-	if container.HostConfig.NetworkMode.IsContainer() || container.Config.NetworkDisabled {
+	if container.HostConfig.NetworkMode.IsContainer() || container.HostConfig.NetworkMode.IsGroup() || container.Config.NetworkDisabled {
 		return
 	}
 
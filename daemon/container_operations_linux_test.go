@@ -0,0 +1,69 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"os"
+	"testing"
+
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/daemon/config"
+	"github.com/docker/docker/libnetwork"
+	nwconfig "github.com/docker/docker/libnetwork/config"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/skip"
+)
+
+func TestDefaultNetworkConfig(t *testing.T) {
+	skip.If(t, os.Getuid() != 0, "skipping test that requires root")
+
+	controller, err := libnetwork.New(
+		nwconfig.OptionDataDir(t.TempDir()),
+		nwconfig.OptionExecRoot(t.TempDir()),
+		nwconfig.OptionDefaultNetwork("bridge"),
+	)
+	assert.NilError(t, err)
+	defer controller.Stop()
+	for _, name := range []string{"bridge", "corp-a", "corp-b"} {
+		n, err := controller.NewNetwork("bridge", name, "")
+		assert.NilError(t, err)
+		defer n.Delete() //nolint:errcheck
+	}
+
+	daemon := &Daemon{
+		configStore:   &config.Config{},
+		netController: controller,
+	}
+	endpointOpts := map[string]string{"com.example.opt": "value"}
+
+	// without configuration, the default network of the daemon is used
+	mode, endpoints := daemon.defaultNetworkConfig()
+	assert.Check(t, is.Equal(mode, containertypes.NetworkMode("default")))
+	assert.Check(t, is.Len(endpoints, 0))
+
+	// the first entry of the chain whose networks all exist is used
+	daemon.configStore.DefaultNetworks = []config.DefaultNetwork{
+		{Networks: []string{"corp-a", "missing"}},
+		{Networks: []string{"corp-a", "corp-b"}},
+		{Networks: []string{"bridge"}},
+	}
+	daemon.configStore.DefaultEndpointOpts = map[string]map[string]string{"bridge": endpointOpts}
+	mode, endpoints = daemon.defaultNetworkConfig()
+	assert.Check(t, is.Equal(mode, containertypes.NetworkMode("corp-a")))
+	assert.Assert(t, is.Len(endpoints, 2))
+	assert.Check(t, is.DeepEqual(endpoints["corp-a"].DriverOpts, endpointOpts))
+	assert.Check(t, is.DeepEqual(endpoints["corp-b"].DriverOpts, endpointOpts))
+
+	// the default network of the daemon is used if no entry is usable, with
+	// the endpoint options of its driver
+	daemon.configStore.DefaultNetworks = []config.DefaultNetwork{
+		{Networks: []string{"missing"}},
+	}
+	mode, endpoints = daemon.defaultNetworkConfig()
+	assert.Check(t, is.Equal(mode, containertypes.NetworkMode("default")))
+	assert.Assert(t, is.Len(endpoints, 1))
+	assert.Check(t, is.DeepEqual(endpoints["bridge"].DriverOpts, endpointOpts))
+
+	// the endpoint options are copied
+	endpoints["bridge"].DriverOpts["com.example.opt"] = "changed"
+	assert.Check(t, is.Equal(endpointOpts["com.example.opt"], "value"))
+}
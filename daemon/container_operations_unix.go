@@ -10,6 +10,7 @@ import (
 	"strconv"
 	"syscall"
 
+	swarmtypes "github.com/docker/docker/api/types/swarm"
 	"github.com/docker/docker/container"
 	"github.com/docker/docker/daemon/links"
 	"github.com/docker/docker/errdefs"
@@ -19,6 +20,7 @@ import (
 	"github.com/docker/docker/pkg/stringid"
 	"github.com/docker/docker/runconfig"
 	"github.com/moby/sys/mount"
+	"github.com/moby/sys/mountinfo"
 	"github.com/opencontainers/selinux/go-selinux/label"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -107,6 +109,16 @@ func containerIsNotRestarting(c *container.Container) error {
 func (daemon *Daemon) setupIpcDirs(c *container.Container) error {
 	ipcMode := c.HostConfig.IpcMode
 
+	if ipcMode.IsShareable() && c.HostConfig.NetworkMode.IsGroup() {
+		g, err := daemon.groups.Get(c.HostConfig.NetworkMode.ConnectedGroup())
+		if err != nil {
+			return err
+		}
+		if g.Config.ShareIPC {
+			return daemon.setupGroupShm(c, g.ID)
+		}
+	}
+
 	switch {
 	case ipcMode.IsContainer():
 		ic, err := daemon.getIpcContainer(ipcMode.Container())
@@ -160,6 +172,46 @@ func (daemon *Daemon) setupIpcDirs(c *container.Container) error {
 	return nil
 }
 
+// setupGroupShm makes a member of a group sharing its IPC namespace use the
+// /dev/shm of the group, which is mounted when the first member starts.
+func (daemon *Daemon) setupGroupShm(c *container.Container, groupID string) error {
+	if c.HasMountFor("/dev/shm") {
+		return nil
+	}
+
+	daemon.groupsMu.Lock()
+	defer daemon.groupsMu.Unlock()
+
+	rootIDs := daemon.idMapping.RootPair()
+	shmPath := filepath.Join(daemon.groups.Dir(groupID), "shm")
+	if err := idtools.MkdirAllAndChown(shmPath, 0700, rootIDs); err != nil {
+		return err
+	}
+	mounted, err := mountinfo.Mounted(shmPath)
+	if err != nil {
+		return err
+	}
+	if !mounted {
+		shmproperty := "mode=1777,size=" + strconv.FormatInt(c.HostConfig.ShmSize, 10)
+		if err := unix.Mount("shm", shmPath, "tmpfs", uintptr(unix.MS_NOEXEC|unix.MS_NOSUID|unix.MS_NODEV), label.FormatMountLabel(shmproperty, c.GetMountLabel())); err != nil {
+			return fmt.Errorf("mounting shm tmpfs: %s", err)
+		}
+		if err := os.Chown(shmPath, rootIDs.UID, rootIDs.GID); err != nil {
+			return err
+		}
+	}
+	c.ShmPath = shmPath
+	return nil
+}
+
+// unmountGroupShm unmounts the /dev/shm of a group, if mounted.
+func (daemon *Daemon) unmountGroupShm(groupID string) error {
+	if err := mount.Unmount(filepath.Join(daemon.groups.Dir(groupID), "shm")); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
 func (daemon *Daemon) setupSecretDir(c *container.Container) (setupErr error) {
 	if len(c.SecretReferences) == 0 && len(c.ConfigReferences) == 0 {
 		return nil
@@ -206,7 +258,11 @@ func (daemon *Daemon) setupSecretDir(c *container.Container) (setupErr error) {
 		if err != nil {
 			return errors.Wrap(err, "unable to get secret from secret store")
 		}
-		if err := os.WriteFile(fPath, secret.Spec.Data, s.File.Mode); err != nil {
+		data, lease, err := daemon.secretData(c, secret)
+		if err != nil {
+			return errors.Wrap(err, "unable to get secret from secret provider")
+		}
+		if err := os.WriteFile(fPath, data, s.File.Mode); err != nil {
 			return errors.Wrap(err, "error injecting secret")
 		}
 
@@ -225,6 +281,9 @@ func (daemon *Daemon) setupSecretDir(c *container.Container) (setupErr error) {
 		if err := os.Chmod(fPath, s.File.Mode); err != nil {
 			return errors.Wrap(err, "error setting file mode for secret")
 		}
+		if lease > 0 {
+			daemon.renewSecret(c, secret, s, fPath, data, lease)
+		}
 	}
 
 	for _, configRef := range c.ConfigReferences {
@@ -322,7 +381,71 @@ func (daemon *Daemon) remountSecretDir(c *container.Container) error {
 	return nil
 }
 
+// updateSecretFile replaces the content of the file of a secret of a running
+// container. The secrets directory is remounted read-write for the duration
+// of the update, and the new content is written to a temporary file renamed
+// over the secret file, so that the container never reads a partial secret.
+func (daemon *Daemon) updateSecretFile(c *container.Container, s *swarmtypes.SecretReference, fPath string, data []byte) error {
+	uid, err := strconv.Atoi(s.File.UID)
+	if err != nil {
+		return err
+	}
+	gid, err := strconv.Atoi(s.File.GID)
+	if err != nil {
+		return err
+	}
+	dir, err := c.SecretMountPath()
+	if err != nil {
+		return errors.Wrap(err, "error getting container secrets path")
+	}
+	rootIDs := daemon.idMapping.RootPair()
+	tmpfsOwnership := fmt.Sprintf("uid=%d,gid=%d", rootIDs.UID, rootIDs.GID)
+
+	// the secrets of a container share its secrets directory
+	daemon.secretFilesMu.Lock()
+	defer daemon.secretFilesMu.Unlock()
+
+	if err := mount.Mount("tmpfs", dir, "tmpfs", "remount,rw,nodev,nosuid,noexec,"+tmpfsOwnership); err != nil {
+		return errors.Wrap(err, "unable to remount secrets dir as read-write")
+	}
+	err = writeSecretFile(fPath, data, s.File.Mode, rootIDs.UID+uid, rootIDs.GID+gid)
+	if rerr := daemon.remountSecretDir(c); rerr != nil {
+		return rerr
+	}
+	return err
+}
+
+// writeSecretFile atomically replaces the content of a secret file, with the
+// given mode and ownership.
+func writeSecretFile(fPath string, data []byte, mode os.FileMode, uid, gid int) (retErr error) {
+	f, err := os.CreateTemp(filepath.Dir(fPath), ".tmp-"+filepath.Base(fPath))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if retErr != nil {
+			os.Remove(f.Name())
+		}
+	}()
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.Chown(f.Name(), uid, gid); err != nil {
+		return err
+	}
+	if err := os.Chmod(f.Name(), mode); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), fPath)
+}
+
 func (daemon *Daemon) cleanupSecretDir(c *container.Container) {
+	daemon.stopSecretLeases(c.ID)
+
 	dir, err := c.SecretMountPath()
 	if err != nil {
 		logrus.WithError(err).WithField("container", c.ID).Warn("error getting secrets mount path for container")
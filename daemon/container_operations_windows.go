@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"os"
 
+	swarmtypes "github.com/docker/docker/api/types/swarm"
 	"github.com/docker/docker/container"
 	"github.com/docker/docker/libnetwork"
+	"github.com/docker/docker/pkg/ioutils"
 	"github.com/docker/docker/pkg/system"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -77,6 +79,10 @@ func (daemon *Daemon) setupIpcDirs(container *container.Container) error {
 	return nil
 }
 
+func (daemon *Daemon) unmountGroupShm(groupID string) error {
+	return nil
+}
+
 // TODO Windows: Fix Post-TP5. This is a hack to allow docker cp to work
 // against containers which have volumes. You will still be able to cp
 // to somewhere on the container drive, but not to any mounted volumes
@@ -136,14 +142,27 @@ func (daemon *Daemon) setupSecretDir(c *container.Container) (setupErr error) {
 		if err != nil {
 			return errors.Wrap(err, "unable to get secret from secret store")
 		}
-		if err := os.WriteFile(fPath, secret.Spec.Data, s.File.Mode); err != nil {
+		data, lease, err := daemon.secretData(c, secret)
+		if err != nil {
+			return errors.Wrap(err, "unable to get secret from secret provider")
+		}
+		if err := os.WriteFile(fPath, data, s.File.Mode); err != nil {
 			return errors.Wrap(err, "error injecting secret")
 		}
+		if lease > 0 {
+			daemon.renewSecret(c, secret, s, fPath, data, lease)
+		}
 	}
 
 	return nil
 }
 
+// updateSecretFile atomically replaces the content of the file of a secret
+// of a running container.
+func (daemon *Daemon) updateSecretFile(c *container.Container, s *swarmtypes.SecretReference, fPath string, data []byte) error {
+	return ioutils.AtomicWriteFile(fPath, data, s.File.Mode)
+}
+
 func killProcessDirectly(container *container.Container) error {
 	return nil
 }
@@ -0,0 +1,14 @@
+package containerd
+
+import (
+	"context"
+	"errors"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/errdefs"
+)
+
+// ImageUpdateLabels replaces the labels of an image.
+func (i *ImageService) ImageUpdateLabels(ctx context.Context, refOrID string, update types.LabelsUpdate) (string, map[string]string, error) {
+	return "", nil, errdefs.NotImplemented(errors.New("not implemented"))
+}
@@ -7,6 +7,8 @@ import (
 	"github.com/docker/distribution/reference"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/pagination"
+	"github.com/docker/docker/daemon/images"
 	"github.com/opencontainers/go-digest"
 	"github.com/opencontainers/image-spec/identity"
 )
@@ -21,7 +23,6 @@ var acceptedImageFilterTags = map[string]bool{
 
 // Images returns a filtered list of images.
 //
-// TODO(thaJeztah): sort the results by created (descending); see https://github.com/moby/moby/issues/43848
 // TODO(thaJeztah): implement opts.ContainerCount (used for docker system df); see https://github.com/moby/moby/issues/43853
 // TODO(thaJeztah): add labels to results; see https://github.com/moby/moby/issues/43852
 // TODO(thaJeztah): verify behavior of `RepoDigests` and `RepoTags` for images without (untagged) or multiple tags; see https://github.com/moby/moby/issues/43861
@@ -31,6 +32,15 @@ func (i *ImageService) Images(ctx context.Context, opts types.ImageListOptions)
 		return nil, err
 	}
 
+	var cursor *pagination.Cursor
+	if opts.Cursor != "" {
+		c, err := pagination.Parse(opts.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		cursor = &c
+	}
+
 	filter, err := i.setupFilters(ctx, opts.Filters)
 	if err != nil {
 		return nil, err
@@ -118,7 +128,7 @@ func (i *ImageService) Images(ctx context.Context, opts types.ImageListOptions)
 		}
 	}
 
-	return summaries, nil
+	return images.PageSummaries(summaries, opts.Limit, cursor), nil
 }
 
 type imageFilterFunc func(image containerd.Image) bool
@@ -1,6 +1,7 @@
 package containerd
 
 import (
+	"context"
 	"net/http"
 	"strings"
 
@@ -33,8 +34,10 @@ func hostsWrapper(hostsFn docker.RegistryHosts, authConfig *registrytypes.AuthCo
 		for i := range hosts {
 			if hosts[i].Authorizer == nil {
 				var opts []docker.AuthorizerOpt
-				if authConfig != nil {
+				if authConfig != nil && *authConfig != (registrytypes.AuthConfig{}) {
 					opts = append(opts, authorizationCredsFromAuthConfig(*authConfig))
+				} else {
+					opts = append(opts, authorizationCredsFromHelper(regService))
 				}
 				hosts[i].Authorizer = docker.NewDockerAuthorizer(opts...)
 
@@ -66,6 +69,25 @@ func authorizationCredsFromAuthConfig(authConfig registrytypes.AuthConfig) docke
 	})
 }
 
+// authorizationCredsFromHelper returns the credentials of the credential
+// helper of the registry, for the operations which were given no credentials.
+func authorizationCredsFromHelper(regService registry.Service) docker.AuthorizerOpt {
+	return docker.WithAuthCreds(func(host string) (string, string, error) {
+		authConfig, err := regService.HelperAuthConfig(context.TODO(), host)
+		if err != nil {
+			logrus.WithError(err).WithField("host", host).Warn("Continuing without credentials")
+			return "", "", nil
+		}
+		if authConfig == nil {
+			return "", "", nil
+		}
+		if authConfig.IdentityToken != "" {
+			return "", authConfig.IdentityToken, nil
+		}
+		return authConfig.Username, authConfig.Password, nil
+	})
+}
+
 type httpFallback struct {
 	super http.RoundTripper
 }
@@ -205,6 +205,9 @@ func (daemon *Daemon) create(ctx context.Context, opts createOpts) (retC *contai
 	if err := daemon.Register(ctr); err != nil {
 		return nil, err
 	}
+	if err := daemon.joinGroup(ctr); err != nil {
+		return nil, err
+	}
 	stateCtr.set(ctr.ID, "stopped")
 	daemon.LogContainerEvent(ctr, "create")
 	return ctr, nil
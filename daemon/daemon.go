@@ -33,10 +33,14 @@ import (
 	ctrd "github.com/docker/docker/daemon/containerd"
 	"github.com/docker/docker/daemon/events"
 	_ "github.com/docker/docker/daemon/graphdriver/register" // register graph drivers
+	"github.com/docker/docker/daemon/groups"
 	"github.com/docker/docker/daemon/images"
+	"github.com/docker/docker/daemon/localsecrets"
 	dlogger "github.com/docker/docker/daemon/logger"
 	"github.com/docker/docker/daemon/network"
+	"github.com/docker/docker/daemon/prune"
 	"github.com/docker/docker/daemon/stats"
+	"github.com/docker/docker/daemon/templates"
 	dmetadata "github.com/docker/docker/distribution/metadata"
 	"github.com/docker/docker/dockerversion"
 	"github.com/docker/docker/errdefs"
@@ -46,6 +50,7 @@ import (
 	"github.com/docker/docker/libnetwork"
 	"github.com/docker/docker/libnetwork/cluster"
 	nwconfig "github.com/docker/docker/libnetwork/config"
+	"github.com/docker/docker/libnetwork/ipamutils"
 	"github.com/docker/docker/pkg/fileutils"
 	"github.com/docker/docker/pkg/idtools"
 	"github.com/docker/docker/pkg/plugingetter"
@@ -56,12 +61,15 @@ import (
 	refstore "github.com/docker/docker/reference"
 	"github.com/docker/docker/registry"
 	"github.com/docker/docker/runconfig"
+	csivolume "github.com/docker/docker/volume/csi"
 	volumesservice "github.com/docker/docker/volume/service"
+	"github.com/docker/libkv/store"
 	"github.com/moby/buildkit/util/resolver"
 	resolverconfig "github.com/moby/buildkit/util/resolver/config"
 	"github.com/moby/locker"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	"github.com/spf13/pflag"
 	"go.etcd.io/bbolt"
 	"golang.org/x/sync/semaphore"
 	"google.golang.org/grpc"
@@ -76,13 +84,20 @@ type Daemon struct {
 	repository            string
 	containers            container.Store
 	containersReplica     *container.ViewDB
+	containerConfigs      *containerConfigs
 	execCommands          *container.ExecStore
 	imageService          ImageService
 	configStore           *config.Config
 	statsCollector        *stats.Collector
+	defaultLogConfigMu    sync.RWMutex
 	defaultLogConfig      containertypes.LogConfig
 	registryService       registry.Service
 	EventsService         *events.Events
+	eventExporters        []*events.Exporter
+	pruneScheduler        *prune.Scheduler
+	startup               *startupTrace
+	ipamReconcileStop     chan struct{}
+	lbRebalanceStop       chan struct{}
 	netController         *libnetwork.Controller
 	volumes               *volumesservice.VolumesService
 	root                  string
@@ -93,12 +108,33 @@ type Daemon struct {
 	PluginStore           *plugin.Store // TODO: remove
 	pluginManager         *plugin.Manager
 	linkIndex             *linkIndex
+	templates             *templates.Store
+	groupsMu              sync.Mutex
+	groups                *groups.Store
+	localSecrets          *localsecrets.Store
 	containerdCli         *containerd.Client
 	containerd            libcontainerdtypes.Client
 	defaultIsolation      containertypes.Isolation // Default isolation mode on Windows
 	clusterProvider       cluster.Provider
 	cluster               Cluster
 	genericResources      []swarm.GenericResource
+	genericResourceLabels []string
+	nodeLabelsMu          sync.Mutex
+	nodeLabels            []string
+	nodeLabelsCancel      context.CancelFunc
+	configReloadMu        sync.Mutex
+	configReload          *types.ConfigReload
+	configFlags           *pflag.FlagSet
+	secretLeasesMu        sync.Mutex
+	secretLeases          map[string]chan struct{}
+	secretFilesMu         sync.Mutex
+	notifySocketsMu       sync.Mutex
+	notifySockets         map[string]*net.UnixConn
+	lifetimeTimersMu      sync.Mutex
+	lifetimeTimers        map[string]*time.Timer
+	dataPathKeysMu        sync.Mutex
+	dataPathKeysClock     uint64
+	dataPathKeysRotatedAt time.Time
 	metricsPluginListener net.Listener
 	ReferenceStore        refstore.Store
 
@@ -119,6 +155,9 @@ type Daemon struct {
 	attachmentStore       network.AttachmentStore
 	attachableNetworkLock *locker.Locker
 
+	serviceBindings serviceBindingUpdates
+	serviceRollouts map[string]*serviceRollout
+
 	// This is used for Windows which doesn't currently support running on containerd
 	// It stores metadata for the content store (used for manifest caching)
 	// This needs to be closed on daemon exit
@@ -218,10 +257,13 @@ func (daemon *Daemon) RegistryHosts() docker.RegistryHosts {
 }
 
 func (daemon *Daemon) restore() error {
+	defer daemon.startup.phase("restore")()
+
 	var mapLock sync.Mutex
 	containers := make(map[string]*container.Container)
 
 	logrus.Info("Loading containers: start.")
+	loadDone := daemon.startup.phase("restore.load")
 
 	dir, err := os.ReadDir(daemon.repository)
 	if err != nil {
@@ -275,7 +317,9 @@ func (daemon *Daemon) restore() error {
 		}(v.Name())
 	}
 	group.Wait()
+	loadDone()
 
+	containersDone := daemon.startup.phase("restore.containers")
 	removeContainers := make(map[string]*container.Container)
 	restartContainers := make(map[*container.Container]chan struct{})
 	activeSandboxes := make(map[string]interface{})
@@ -394,11 +438,18 @@ func (daemon *Daemon) restore() error {
 						}
 						c.Unlock()
 					}
+					daemon.startLifetimeTimer(c)
 				case !c.IsPaused() && alive:
 					logger(c).Debug("restoring healthcheck")
 					c.Lock()
 					daemon.updateHealthMonitor(c)
 					c.Unlock()
+					daemon.startLifetimeTimer(c)
+					if c.HostConfig.NotifyReady {
+						if err := daemon.listenNotifySocket(c); err != nil {
+							log.WithError(err).Error("failed to restore the notify socket")
+						}
+					}
 				}
 
 				if !alive {
@@ -434,7 +485,7 @@ func (daemon *Daemon) restore() error {
 				}
 
 				c.ResetRestartManager(false)
-				if !c.HostConfig.NetworkMode.IsContainer() && c.IsRunning() {
+				if !c.HostConfig.NetworkMode.IsContainer() && !c.HostConfig.NetworkMode.IsGroup() && c.IsRunning() {
 					options, err := daemon.buildSandboxOptions(c)
 					if err != nil {
 						logger(c).WithError(err).Warn("failed to build sandbox option to restore container")
@@ -486,14 +537,19 @@ func (daemon *Daemon) restore() error {
 		}(c)
 	}
 	group.Wait()
+	containersDone()
+
+	daemon.restoreGroupSandboxes(activeSandboxes)
 
 	// Initialize the network controller and configure network settings.
 	//
 	// Note that we cannot initialize the network controller earlier, as it
 	// needs to know if there's active sandboxes (running containers).
+	networkDone := daemon.startup.phase("restore.network-controller")
 	if err = daemon.initNetworkController(activeSandboxes); err != nil {
 		return fmt.Errorf("Error initializing network controller: %v", err)
 	}
+	networkDone()
 
 	// Now that all the containers are registered, register the links
 	for _, c := range containers {
@@ -511,6 +567,7 @@ func (daemon *Daemon) restore() error {
 	}
 	group.Wait()
 
+	restartDone := daemon.startup.phase("restore.restart")
 	for c, notifier := range restartContainers {
 		group.Add(1)
 		go func(c *container.Container, chNotify chan struct{}) {
@@ -548,7 +605,9 @@ func (daemon *Daemon) restore() error {
 		}(c, notifier)
 	}
 	group.Wait()
+	restartDone()
 
+	removeDone := daemon.startup.phase("restore.remove")
 	for id := range removeContainers {
 		group.Add(1)
 		go func(cid string) {
@@ -563,12 +622,14 @@ func (daemon *Daemon) restore() error {
 		}(id)
 	}
 	group.Wait()
+	removeDone()
 
 	// any containers that were started above would already have had this done,
 	// however we need to now prepare the mountpoints for the rest of the containers as well.
 	// This shouldn't cause any issue running on the containers that already had this run.
 	// This must be run after any containers with a restart policy so that containerized plugins
 	// can have a chance to be running before we try to initialize them.
+	mountsDone := daemon.startup.phase("restore.mounts")
 	for _, c := range containers {
 		// if the container has restart policy, do not
 		// prepare the mountpoints since it has been done on restarting.
@@ -594,6 +655,7 @@ func (daemon *Daemon) restore() error {
 		}(c)
 	}
 	group.Wait()
+	mountsDone()
 
 	logrus.Info("Loading containers: done.")
 
@@ -643,7 +705,13 @@ func (daemon *Daemon) RestartSwarmContainers() {
 }
 
 func (daemon *Daemon) children(c *container.Container) map[string]*container.Container {
-	return daemon.linkIndex.children(c)
+	children := daemon.linkIndex.children(c)
+	for _, child := range children {
+		if err := daemon.containerConfigs.use(child); err != nil {
+			logrus.WithError(err).WithField("container", c.ID).Warn("failed to load the configuration of a linked container")
+		}
+	}
+	return children
 }
 
 // parents returns the names of the parent containers of the container
@@ -681,6 +749,11 @@ func (daemon *Daemon) DaemonLeavesCluster() {
 	daemon.setClusterProvider(nil)
 	// Wait for the networking cluster agent to stop
 	daemon.netController.AgentStopWait()
+	// The data path keys of another swarm are not a rotation.
+	daemon.dataPathKeysMu.Lock()
+	daemon.dataPathKeysClock = 0
+	daemon.dataPathKeysRotatedAt = time.Time{}
+	daemon.dataPathKeysMu.Unlock()
 	// Daemon is in charge of removing the ingress network when the
 	// node leaves the swarm. Wait for job to be done or timeout.
 	// This is called also on graceful daemon shutdown. We need to
@@ -722,6 +795,9 @@ func (daemon *Daemon) IsSwarmCompatible() error {
 // NewDaemon sets up everything for the daemon to be able to service
 // requests from the webserver.
 func NewDaemon(ctx context.Context, config *config.Config, pluginStore *plugin.Store) (daemon *Daemon, err error) {
+	startup := &startupTrace{}
+	daemonDone := startup.phase("daemon")
+
 	// Verify platform-specific requirements.
 	// TODO(thaJeztah): this should be called before we try to create the daemon; perhaps together with the config validation.
 	if err := checkSystem(); err != nil {
@@ -781,6 +857,7 @@ func NewDaemon(ctx context.Context, config *config.Config, pluginStore *plugin.S
 		configStore: config,
 		PluginStore: pluginStore,
 		startupDone: make(chan struct{}),
+		startup:     startup,
 	}
 
 	// Ensure the daemon is properly shutdown if there is a failure during
@@ -920,6 +997,7 @@ func NewDaemon(ctx context.Context, config *config.Config, pluginStore *plugin.S
 	}
 
 	// Plugin system initialization should happen before restore. Do not change order.
+	pluginsDone := d.startup.phase("plugins")
 	d.pluginManager, err = plugin.NewManager(plugin.ManagerConfig{
 		Root:               filepath.Join(config.Root, "plugins"),
 		ExecRoot:           getPluginExecRoot(config),
@@ -933,15 +1011,30 @@ func NewDaemon(ctx context.Context, config *config.Config, pluginStore *plugin.S
 	if err != nil {
 		return nil, errors.Wrap(err, "couldn't create plugin manager")
 	}
+	pluginsDone()
 
 	if err := d.setupDefaultLogConfig(); err != nil {
 		return nil, err
 	}
 
+	volumesDone := d.startup.phase("volumes")
 	d.volumes, err = volumesservice.NewVolumeService(config.Root, d.PluginStore, rootIDs, d)
 	if err != nil {
 		return nil, err
 	}
+	d.volumes.SetQuiesceFunc(d.quiesceVolumeUsers)
+	d.volumes.SetImageMountFunc(d.mountImage)
+	d.volumes.SetPluginTimeouts(volumePluginTimeouts(config.VolumePluginTimeouts))
+	for name, address := range config.CSIDrivers {
+		drv, err := csivolume.New(name, address, filepath.Join(config.Root, "csi"))
+		if err != nil {
+			return nil, errors.Wrapf(err, "error setting up CSI volume driver %s", name)
+		}
+		if err := d.volumes.RegisterDriver(drv, name); err != nil {
+			return nil, err
+		}
+	}
+	volumesDone()
 
 	// Check if Devices cgroup is mounted, it is hard requirement for container security,
 	// on Linux.
@@ -961,6 +1054,7 @@ func NewDaemon(ctx context.Context, config *config.Config, pluginStore *plugin.S
 	}
 	d.repository = daemonRepo
 	d.containers = container.NewMemoryStore()
+	d.containerConfigs = newContainerConfigs(containerConfigsSize, containerConfigsIdle)
 	if d.containersReplica, err = container.NewViewDB(); err != nil {
 		return nil, err
 	}
@@ -968,11 +1062,27 @@ func NewDaemon(ctx context.Context, config *config.Config, pluginStore *plugin.S
 	d.statsCollector = d.newStatsCollector(1 * time.Second)
 
 	d.EventsService = events.New()
+	if config.AuthzMiddleware != nil {
+		d.invalidateAuthzDecisions(config.AuthzMiddleware)
+	}
 	d.root = config.Root
+	d.startEventExporters(config.EventSinks)
 	d.idMapping = idMapping
 
 	d.linkIndex = newLinkIndex()
 
+	if d.templates, err = templates.NewStore(filepath.Join(config.Root, "templates")); err != nil {
+		return nil, err
+	}
+
+	if d.groups, err = groups.NewStore(filepath.Join(config.Root, "groups")); err != nil {
+		return nil, err
+	}
+
+	if d.localSecrets, err = localsecrets.New(filepath.Join(config.Root, "secrets"), d.localSecretUsers); err != nil {
+		return nil, err
+	}
+
 	// On Windows we don't support the environment variable, or a user supplied graphdriver
 	// Unix platforms however run a single graphdriver for all containers, and it can
 	// be set through an environment variable, a daemon start parameter, or chosen through
@@ -986,6 +1096,7 @@ func NewDaemon(ctx context.Context, config *config.Config, pluginStore *plugin.S
 		driverName = config.GraphDriver
 	}
 
+	imageServiceDone := d.startup.phase("image-service")
 	if d.UsesSnapshotter() {
 		if os.Getenv("TEST_INTEGRATION_USE_SNAPSHOTTER") != "" {
 			logrus.Warn("Enabling containerd snapshotter through the $TEST_INTEGRATION_USE_SNAPSHOTTER environment variable. This should only be used for testing.")
@@ -1094,17 +1205,30 @@ func NewDaemon(ctx context.Context, config *config.Config, pluginStore *plugin.S
 		logrus.Debugf("Max Concurrent Uploads: %d", imgSvcConfig.MaxConcurrentUploads)
 		logrus.Debugf("Max Download Attempts: %d", imgSvcConfig.MaxDownloadAttempts)
 	}
+	imageServiceDone()
 
 	go d.execCommandGC()
 
+	if config.NodeMetadataLabels {
+		var nodeLabelsCtx context.Context
+		nodeLabelsCtx, d.nodeLabelsCancel = context.WithCancel(context.Background())
+		go d.refreshNodeLabels(nodeLabelsCtx)
+	}
+
+	libcontainerdDone := d.startup.phase("libcontainerd")
 	if err := d.initLibcontainerd(ctx); err != nil {
 		return nil, err
 	}
+	libcontainerdDone()
 
 	if err := d.restore(); err != nil {
 		return nil, err
 	}
+	go d.containerConfigGC()
 	close(d.startupDone)
+	daemonDone()
+	d.startIPAMReconcile(config.IPAMReconcile)
+	d.startLBRebalance(config.LBRebalance)
 
 	info := d.SystemInfo()
 	for _, w := range info.Warnings {
@@ -1125,6 +1249,10 @@ func NewDaemon(ctx context.Context, config *config.Config, pluginStore *plugin.S
 	engineCpus.Set(float64(info.NCPU))
 	engineMemory.Set(float64(info.MemTotal))
 
+	if config.MetricsContainerStats {
+		registerContainerMetrics(d, config.MetricsContainerLabels)
+	}
+
 	logrus.WithFields(logrus.Fields{
 		"version":     dockerversion.Version,
 		"commit":      dockerversion.GitCommit,
@@ -1187,6 +1315,8 @@ func (daemon *Daemon) ShutdownTimeout() int {
 // Shutdown stops the daemon.
 func (daemon *Daemon) Shutdown(ctx context.Context) error {
 	daemon.shutdown = true
+	daemon.stopNodeLabels()
+
 	// Keep mounts and networking running on daemon shutdown if
 	// we are to keep containers running and restore them.
 
@@ -1238,6 +1368,10 @@ func (daemon *Daemon) Shutdown(ctx context.Context) error {
 	}
 
 	daemon.cleanupMetricsPlugins()
+	daemon.stopEventExporters()
+	daemon.stopPruneSchedules()
+	daemon.stopIPAMReconcile()
+	daemon.stopLBRebalance()
 
 	// Shutdown plugins after containers and layerstore. Don't change the order.
 	daemon.pluginShutdown()
@@ -1351,6 +1485,10 @@ func (daemon *Daemon) setGenericResources(conf *config.Config) error {
 		return err
 	}
 
+	if conf.NodeGenericResourcesDiscovery {
+		genericResources, daemon.genericResourceLabels = mergeGenericResources(genericResources, discoverGenericResources())
+	}
+
 	daemon.genericResources = genericResources
 
 	return nil
@@ -1380,18 +1518,41 @@ func (daemon *Daemon) networkOptions(pg plugingetter.PluginGetter, activeSandbox
 		nwconfig.OptionDefaultNetwork(dd.NetworkName()),
 		nwconfig.OptionLabels(conf.Labels),
 		nwconfig.OptionNetworkControlPlaneMTU(conf.NetworkControlPlaneMTU),
+		nwconfig.OptionStartupTracer(daemon.startup.tracer("restore.network-controller")),
 		driverOptions(conf),
 	}
 
 	if len(conf.NetworkConfig.DefaultAddressPools.Value()) > 0 {
 		options = append(options, nwconfig.OptionDefaultAddressPoolConfig(conf.NetworkConfig.DefaultAddressPools.Value()))
 	}
+	if conf.NetworkConfig.DefaultAddressPoolsRouteOverlap != "" {
+		options = append(options, nwconfig.OptionRouteOverlapMode(ipamutils.RouteOverlapMode(conf.NetworkConfig.DefaultAddressPoolsRouteOverlap)))
+	}
 	if conf.LiveRestoreEnabled && len(activeSandboxes) != 0 {
 		options = append(options, nwconfig.OptionActiveSandboxes(activeSandboxes))
 	}
 	if pg != nil {
 		options = append(options, nwconfig.OptionPluginGetter(pg))
 	}
+	if kv := conf.NetworkConfig.GlobalKVStore; kv.Provider != "" {
+		storeConfig := &store.Config{
+			ConnectionTimeout: kv.GetConnectionTimeout(),
+			Username:          kv.Username,
+			Password:          kv.Password,
+		}
+		if kv.HasTLS() {
+			storeConfig.ClientTLS = &store.ClientTLSConfig{
+				CACertFile: kv.TLSCACert,
+				CertFile:   kv.TLSCert,
+				KeyFile:    kv.TLSKey,
+			}
+		}
+		options = append(options,
+			nwconfig.OptionGlobalKVProvider(kv.Provider),
+			nwconfig.OptionGlobalKVProviderURL(kv.Address),
+			nwconfig.OptionGlobalKVProviderConfig(storeConfig),
+		)
+	}
 
 	return options, nil
 }
@@ -1406,6 +1567,12 @@ func (daemon *Daemon) SetCluster(cluster Cluster) {
 	daemon.cluster = cluster
 }
 
+// SetConfigFlags sets the command line flags the daemon was started with,
+// against which configuration files are checked.
+func (daemon *Daemon) SetConfigFlags(flags *pflag.FlagSet) {
+	daemon.configFlags = flags
+}
+
 func (daemon *Daemon) pluginShutdown() {
 	manager := daemon.pluginManager
 	// Check for a valid manager object. In error conditions, daemon init can fail
@@ -1473,6 +1640,12 @@ func (daemon *Daemon) IdentityMapping() idtools.IdentityMapping {
 	return daemon.idMapping
 }
 
+// LocalSecrets returns the store of the secrets and configs of standalone
+// containers.
+func (daemon *Daemon) LocalSecrets() *localsecrets.Store {
+	return daemon.localSecrets
+}
+
 // ImageService returns the Daemon's ImageService
 func (daemon *Daemon) ImageService() ImageService {
 	return daemon.imageService
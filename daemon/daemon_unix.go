@@ -352,6 +352,7 @@ func (daemon *Daemon) adaptContainerSettings(hostConfig *containertypes.HostConf
 	}
 
 	adaptSharedNamespaceContainer(daemon, hostConfig)
+	daemon.adaptGroupMember(hostConfig)
 
 	var err error
 	secOpts, err := daemon.generateSecurityOpt(hostConfig)
@@ -695,6 +696,17 @@ func verifyPlatformContainerSettings(daemon *Daemon, hostConfig *containertypes.
 			return warnings, fmt.Errorf("cannot share the host PID namespace when user namespaces are enabled")
 		}
 	}
+	for _, m := range hostConfig.Mounts {
+		if m.BindOptions == nil || !m.BindOptions.IDMap {
+			continue
+		}
+		if daemon.configStore.RemappedRoot == "" {
+			return warnings, errors.Errorf("idmapped bind mount %s requires the daemon to run with user namespace remapping (--userns-remap)", m.Target)
+		}
+		if !hostConfig.UsernsMode.IsPrivate() {
+			return warnings, errors.Errorf("idmapped bind mount %s cannot be used in the host user namespace (--userns=host)", m.Target)
+		}
+	}
 	if hostConfig.CgroupParent != "" && UsingSystemd(daemon.configStore) {
 		// CgroupParent for systemd cgroup should be named as "xxx.slice"
 		if len(hostConfig.CgroupParent) <= 6 || !strings.HasSuffix(hostConfig.CgroupParent, ".slice") {
@@ -725,9 +737,52 @@ func verifyPlatformContainerSettings(daemon *Daemon, hostConfig *containertypes.
 		}
 	}
 
+	if err := verifyHooks(hostConfig.Hooks, daemon.configStore.AllowedHooks); err != nil {
+		return warnings, err
+	}
+
 	return warnings, nil
 }
 
+// hookEnvPrefix is the prefix of the names of the environment variables which
+// can be set for hooks. Hooks run as root on the host, and many variables
+// make the dynamic loader, shells, or interpreters load arbitrary code or
+// files, so only variables in a namespace which they don't read are allowed.
+const hookEnvPrefix = "HOOK_"
+
+// verifyHooks checks that the binaries of the OCI lifecycle hooks of a
+// container are allowed by the daemon, and that their environment only sets
+// variables prefixed with hookEnvPrefix.
+func verifyHooks(hooks *containertypes.Hooks, allowed []string) error {
+	if hooks == nil {
+		return nil
+	}
+	for _, hs := range [][]containertypes.Hook{hooks.CreateRuntime, hooks.Poststart, hooks.Poststop} {
+		for _, h := range hs {
+			found := false
+			for _, p := range allowed {
+				if h.Path == p {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return errdefs.InvalidParameter(errors.Errorf("hook %q is not allowed by the daemon", h.Path))
+			}
+			if h.Timeout != nil && *h.Timeout <= 0 {
+				return errdefs.InvalidParameter(errors.Errorf("invalid timeout %d of hook %q: must be positive", *h.Timeout, h.Path))
+			}
+			for _, e := range h.Env {
+				k, _, _ := strings.Cut(e, "=")
+				if !strings.HasPrefix(k, hookEnvPrefix) || k == hookEnvPrefix {
+					return errdefs.InvalidParameter(errors.Errorf("environment variable %s of hook %q is not allowed: only variables prefixed with %s can be set", k, h.Path, hookEnvPrefix))
+				}
+			}
+		}
+	}
+	return nil
+}
+
 // verifyDaemonSettings performs validation of daemon config struct
 func verifyDaemonSettings(conf *config.Config) error {
 	if conf.ContainerdNamespace == conf.ContainerdPluginNamespace {
@@ -918,13 +973,17 @@ func setHostGatewayIP(controller *libnetwork.Controller, config *config.Config)
 }
 
 func driverOptions(config *config.Config) nwconfig.Option {
+	// the range is validated when the configuration is loaded
+	portStart, portEnd, _ := config.BridgeConfig.PublishedPortRangeToInt()
 	return nwconfig.OptionDriverConfig("bridge", options.Generic{
 		netlabel.GenericData: options.Generic{
-			"EnableIPForwarding":  config.BridgeConfig.EnableIPForward,
-			"EnableIPTables":      config.BridgeConfig.EnableIPTables,
-			"EnableIP6Tables":     config.BridgeConfig.EnableIP6Tables,
-			"EnableUserlandProxy": config.BridgeConfig.EnableUserlandProxy,
-			"UserlandProxyPath":   config.BridgeConfig.UserlandProxyPath,
+			"EnableIPForwarding":      config.BridgeConfig.EnableIPForward,
+			"EnableIPTables":          config.BridgeConfig.EnableIPTables,
+			"EnableIP6Tables":         config.BridgeConfig.EnableIP6Tables,
+			"EnableUserlandProxy":     config.BridgeConfig.EnableUserlandProxy,
+			"UserlandProxyPath":       config.BridgeConfig.UserlandProxyPath,
+			"PublishedPortRangeStart": portStart,
+			"PublishedPortRangeEnd":   portEnd,
 		},
 	})
 }
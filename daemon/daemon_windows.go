@@ -178,6 +178,12 @@ func verifyPlatformContainerSettings(daemon *Daemon, hostConfig *containertypes.
 	if hostConfig == nil {
 		return nil, nil
 	}
+	if hostConfig.Hooks != nil {
+		return nil, errdefs.InvalidParameter(errors.New("hooks are not supported on Windows"))
+	}
+	if hostConfig.NotifyReady {
+		return nil, errdefs.InvalidParameter(errors.New("readiness notifications are not supported on Windows"))
+	}
 	return verifyPlatformContainerResources(&hostConfig.Resources, daemon.runAsHyperVContainer(hostConfig))
 }
 
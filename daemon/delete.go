@@ -154,7 +154,9 @@ func (daemon *Daemon) cleanupContainer(container *container.Container, config ty
 	linkNames := daemon.linkIndex.delete(container)
 	selinux.ReleaseLabel(container.ProcessLabel)
 	daemon.containers.Delete(container.ID)
+	daemon.containerConfigs.forget(container.ID)
 	daemon.containersReplica.Delete(container)
+	daemon.leaveGroup(container)
 	if err := daemon.removeMountPoints(container, config.RemoveVolume); err != nil {
 		logrus.Error(err)
 	}
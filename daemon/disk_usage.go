@@ -74,6 +74,51 @@ func (daemon *Daemon) layerDiskUsage(ctx context.Context) (int64, error) {
 	return usage, err
 }
 
+// networkUsage returns the number of endpoints, sandboxes, and allocated
+// addresses of each network.
+func (daemon *Daemon) networkUsage() []*types.NetworkUsage {
+	if daemon.netController == nil {
+		return nil
+	}
+	var usage []*types.NetworkUsage
+	for _, nw := range daemon.netController.Networks() {
+		u := &types.NetworkUsage{
+			ID:     nw.ID(),
+			Name:   nw.Name(),
+			Driver: nw.Type(),
+			Scope:  nw.Info().Scope(),
+		}
+		v4, v6 := nw.Info().IpamInfo()
+		for _, info := range append(v4, v6...) {
+			if info.Pool != nil {
+				u.Subnets = append(u.Subnets, info.Pool.String())
+			}
+			if info.Gateway != nil {
+				u.AllocatedIPs++
+			}
+		}
+
+		sandboxes := make(map[string]struct{})
+		for _, ep := range nw.Endpoints() {
+			u.Endpoints++
+			if sb := ep.Sandbox(); sb != nil {
+				sandboxes[sb.ID()] = struct{}{}
+			}
+			if iface := ep.Iface(); iface != nil {
+				if iface.Address() != nil {
+					u.AllocatedIPs++
+				}
+				if iface.AddressIPv6() != nil {
+					u.AllocatedIPs++
+				}
+			}
+		}
+		u.Sandboxes = len(sandboxes)
+		usage = append(usage, u)
+	}
+	return usage
+}
+
 // SystemDiskUsage returns information about the daemon data disk usage.
 // Callers must not mutate contents of the returned fields.
 func (daemon *Daemon) SystemDiskUsage(ctx context.Context, opts system.DiskUsageOptions) (*types.DiskUsage, error) {
@@ -114,6 +159,11 @@ func (daemon *Daemon) SystemDiskUsage(ctx context.Context, opts system.DiskUsage
 		})
 	}
 
+	var networks []*types.NetworkUsage
+	if opts.Networks {
+		networks = daemon.networkUsage()
+	}
+
 	if err := eg.Wait(); err != nil {
 		return nil, err
 	}
@@ -122,5 +172,6 @@ func (daemon *Daemon) SystemDiskUsage(ctx context.Context, opts system.DiskUsage
 		Containers: containers,
 		Volumes:    volumes,
 		Images:     images,
+		Networks:   networks,
 	}, nil
 }
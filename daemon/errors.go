@@ -59,6 +59,14 @@ func (e nameConflictError) Error() string {
 
 func (nameConflictError) Conflict() {}
 
+func (nameConflictError) ErrorCode() string {
+	return "container_name_conflict"
+}
+
+func (e nameConflictError) ErrorDetails() map[string]string {
+	return map[string]string{"name": e.name, "container_id": e.id}
+}
+
 type containerNotModifiedError struct {
 	running bool
 }
@@ -1,7 +1,9 @@
 package daemon // import "github.com/docker/docker/daemon"
 
 import (
+	"bytes"
 	"context"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -9,10 +11,13 @@ import (
 	"github.com/docker/docker/api/types/events"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/container"
+	"github.com/docker/docker/daemon/config"
 	daemonevents "github.com/docker/docker/daemon/events"
 	"github.com/docker/docker/libnetwork"
+	"github.com/docker/docker/pkg/authorization"
 	gogotypes "github.com/gogo/protobuf/types"
 	swarmapi "github.com/moby/swarmkit/v2/api"
+	"github.com/opencontainers/go-digest"
 	"github.com/sirupsen/logrus"
 )
 
@@ -109,6 +114,65 @@ func (daemon *Daemon) UnsubscribeFromEvents(listener chan interface{}) {
 	daemon.EventsService.Evict(listener)
 }
 
+// startEventExporters starts exporting events to the configured sinks.
+func (daemon *Daemon) startEventExporters(sinks []config.EventSinkConfig) {
+	for _, sc := range sinks {
+		var sink daemonevents.Sink
+		switch sc.Type {
+		case config.EventSinkTypeWebhook:
+			sink = daemonevents.NewWebhookSink(sc.URL)
+		default:
+			logrus.Warnf("ignoring event sink with unsupported type %q", sc.Type)
+			continue
+		}
+		root := filepath.Join(daemon.root, "events", digest.FromString(sink.String()).Encoded()[:12])
+		x := daemonevents.NewExporter(daemon.EventsService, sink, daemonevents.NewFilter(sc.GetFilters()), root)
+		if err := x.Start(); err != nil {
+			logrus.WithError(err).WithField("sink", sink.String()).Error("failed to start exporting events")
+			continue
+		}
+		daemon.eventExporters = append(daemon.eventExporters, x)
+		logrus.WithField("sink", sink.String()).Info("exporting events")
+	}
+}
+
+// stopEventExporters stops exporting events to the configured sinks.
+func (daemon *Daemon) stopEventExporters() {
+	for _, x := range daemon.eventExporters {
+		x.Stop()
+	}
+	daemon.eventExporters = nil
+}
+
+// invalidateAuthzDecisions drops the cached decisions of the authorization
+// plugins about objects that are removed or renamed, so that they don't apply
+// to another object taking the name of the object.
+func (daemon *Daemon) invalidateAuthzDecisions(m *authorization.Middleware) {
+	ef := daemonevents.NewFilter(filters.NewArgs(
+		filters.Arg("event", "destroy"),
+		filters.Arg("event", "delete"),
+		filters.Arg("event", "untag"),
+		filters.Arg("event", "remove"),
+		filters.Arg("event", "rename"),
+	))
+	_, l := daemon.EventsService.SubscribeTopic(time.Time{}, time.Time{}, ef)
+	go func() {
+		for ev := range l {
+			msg, ok := ev.(events.Message)
+			if !ok {
+				continue
+			}
+			names := []string{msg.Actor.ID}
+			for _, attr := range []string{"name", "oldName"} {
+				if name := strings.TrimPrefix(msg.Actor.Attributes[attr], "/"); name != "" {
+					names = append(names, name)
+				}
+			}
+			m.InvalidateCache(names...)
+		}
+	}()
+}
+
 // copyAttributes guarantees that labels are not mutated by event triggers.
 func copyAttributes(attributes, labels map[string]string) {
 	if labels == nil {
@@ -152,6 +216,11 @@ func (daemon *Daemon) generateClusterEvent(msg *swarmapi.WatchMessage) {
 			daemon.logSecretEvent(event.Action, v.Secret, event.OldObject.GetSecret())
 		case *swarmapi.Object_Config:
 			daemon.logConfigEvent(event.Action, v.Config, event.OldObject.GetConfig())
+		case *swarmapi.Object_Cluster:
+			daemon.logSwarmEvent(v.Cluster, event.OldObject.GetCluster())
+		case *swarmapi.Object_Task:
+			// tasks do not generate events of their own
+			daemon.trackRolloutTask(event.Action, v.Task)
 		default:
 			logrus.Warnf("unrecognized event: %v", event)
 		}
@@ -272,6 +341,95 @@ func (daemon *Daemon) logServiceEvent(action swarmapi.WatchActionKind, service *
 		}
 	}
 	daemon.logClusterEvent(action, service.ID, "service", attributes, eventTime)
+
+	if action == swarmapi.WatchActionKindUpdate && oldService != nil {
+		daemon.logServiceRolloutEvent(service, oldService, eventTime)
+	}
+	daemon.trackServiceRollout(action, service, oldService, eventTime)
+}
+
+// rolloutPhases are the phases of the rollout of a service reported by
+// "rollout" events, by update state of the service.
+var rolloutPhases = map[swarmapi.UpdateStatus_UpdateState]string{
+	swarmapi.UpdateStatus_UPDATING:           "update-started",
+	swarmapi.UpdateStatus_PAUSED:             "update-paused",
+	swarmapi.UpdateStatus_COMPLETED:          "converged",
+	swarmapi.UpdateStatus_ROLLBACK_STARTED:   "rollback-started",
+	swarmapi.UpdateStatus_ROLLBACK_PAUSED:    "rollback-paused",
+	swarmapi.UpdateStatus_ROLLBACK_COMPLETED: "rolled-back",
+}
+
+// logServiceRolloutEvent generates a "rollout" event when the update of a
+// service enters a new phase, so that clients can wait for a service to
+// converge without polling its tasks.
+func (daemon *Daemon) logServiceRolloutEvent(service, oldService *swarmapi.Service, eventTime time.Time) {
+	if service.UpdateStatus == nil {
+		return
+	}
+	if oldService.UpdateStatus != nil && oldService.UpdateStatus.State == service.UpdateStatus.State {
+		return
+	}
+	phase, ok := rolloutPhases[service.UpdateStatus.State]
+	if !ok {
+		return
+	}
+	var attributes map[string]string
+	if service.UpdateStatus.Message != "" {
+		attributes = map[string]string{"message": service.UpdateStatus.Message}
+	}
+	daemon.logRolloutEvent(service.ID, service.Spec.Annotations.Name, phase, attributes, eventTime)
+}
+
+// logSwarmEvent generates a "rotate" event when the unlock key or the
+// data path encryption keys of the swarm are rotated. Other updates of the swarm do not generate events.
+func (daemon *Daemon) logSwarmEvent(cluster *swarmapi.Cluster, oldCluster *swarmapi.Cluster) {
+	if oldCluster == nil {
+		return
+	}
+	eventTime := eventTimestamp(cluster.Meta, swarmapi.WatchActionKindUpdate)
+	var rotated []string
+	if unlockKeyRotated(cluster, oldCluster) {
+		rotated = append(rotated, "unlock-key")
+	}
+	if dataPathKeyRotated(cluster, oldCluster) {
+		rotated = append(rotated, "data-path-key")
+	}
+	for _, key := range rotated {
+		daemon.EventsService.PublishMessage(events.Message{
+			Action: "rotate",
+			Type:   events.SwarmEventType,
+			Actor: events.Actor{
+				ID:         cluster.ID,
+				Attributes: map[string]string{"key": key},
+			},
+			Scope:    "swarm",
+			Time:     eventTime.UTC().Unix(),
+			TimeNano: eventTime.UTC().UnixNano(),
+		})
+	}
+}
+
+func unlockKeyRotated(cluster, oldCluster *swarmapi.Cluster) bool {
+	if len(cluster.UnlockKeys) == 0 || len(oldCluster.UnlockKeys) == 0 {
+		// the managers were locked or unlocked, not rotated
+		return false
+	}
+	return !bytes.Equal(cluster.UnlockKeys[0].Key, oldCluster.UnlockKeys[0].Key)
+}
+
+func dataPathKeyRotated(cluster, oldCluster *swarmapi.Cluster) bool {
+	var latest, oldLatest uint64
+	for _, k := range cluster.NetworkBootstrapKeys {
+		if k.LamportTime > latest {
+			latest = k.LamportTime
+		}
+	}
+	for _, k := range oldCluster.NetworkBootstrapKeys {
+		if k.LamportTime > oldLatest {
+			oldLatest = k.LamportTime
+		}
+	}
+	return oldLatest != 0 && latest > oldLatest
 }
 
 func (daemon *Daemon) logClusterEvent(action swarmapi.WatchActionKind, id, eventType string, attributes map[string]string, eventTime time.Time) {
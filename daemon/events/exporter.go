@@ -0,0 +1,284 @@
+package events // import "github.com/docker/docker/daemon/events"
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	eventtypes "github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/errdefs"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// exportQueueSize is the maximum number of events kept in memory per
+	// sink while waiting to be delivered. Events are spooled to disk if the
+	// queue is full.
+	exportQueueSize = 10000
+
+	exportInitialBackoff = 100 * time.Millisecond
+	exportMaxBackoff     = 30 * time.Second
+)
+
+// Sink is an external destination engine events are exported to.
+type Sink interface {
+	// Send delivers a single event to the sink. Delivery is retried for as
+	// long as Send returns an error, unless the error is an
+	// errdefs.InvalidParameter, which means that the sink rejected the event.
+	Send(ctx context.Context, ev eventtypes.Message) error
+	// String returns a description of the sink for logging.
+	String() string
+}
+
+// Exporter delivers the events matching a filter to a Sink. An event is
+// retried until the sink accepts or rejects it, so that each event is
+// delivered at least once. The events received while the queue is full, and
+// the events not delivered yet when the exporter stops, are spooled to disk,
+// and delivered after the queued events, or when the exporter starts again.
+// Events rejected by the sink are logged, and added to a dead-letter file.
+type Exporter struct {
+	events *Events
+	sink   Sink
+	filter *Filter
+	root   string
+	queue  chan eventtypes.Message
+
+	// spool holds the events received while events are spilled, replay the
+	// spooled events being delivered, and dead the events rejected by the
+	// sink.
+	spool, replay, dead spool
+
+	mu sync.Mutex
+	// spilled is set while events are spooled rather than queued, which
+	// they are from the moment the queue is full until the spool is
+	// delivered, so that events are delivered in order.
+	spilled bool
+	spooled chan struct{}
+
+	cancel   context.CancelFunc
+	received chan struct{}
+	done     chan struct{}
+}
+
+// NewExporter returns an Exporter that delivers the events matching ef to
+// sink. The spooled and the rejected events are stored in the root
+// directory, which must be specific to the sink. The exporter must be
+// started with Start.
+func NewExporter(e *Events, sink Sink, ef *Filter, root string) *Exporter {
+	return &Exporter{
+		events:   e,
+		sink:     sink,
+		filter:   ef,
+		root:     root,
+		queue:    make(chan eventtypes.Message, exportQueueSize),
+		spool:    spool{path: filepath.Join(root, "spool.json")},
+		replay:   spool{path: filepath.Join(root, "replay.json")},
+		dead:     spool{path: filepath.Join(root, "dead-letter.json")},
+		spooled:  make(chan struct{}, 1),
+		received: make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start subscribes the exporter to the events, and starts delivering them,
+// after the events spooled when the exporter was last stopped.
+func (x *Exporter) Start() error {
+	if err := os.MkdirAll(x.root, 0o700); err != nil {
+		return err
+	}
+	if x.spool.exists() || x.replay.exists() {
+		x.spilled = true
+		x.spooled <- struct{}{}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	x.cancel = cancel
+
+	_, l := x.events.SubscribeTopic(time.Time{}, time.Time{}, x.filter)
+	go x.receive(ctx, l)
+	go x.deliver(ctx)
+	return nil
+}
+
+// Stop stops the exporter. Events that were not delivered yet are spooled,
+// and delivered when the exporter is started again.
+func (x *Exporter) Stop() {
+	if x.cancel == nil {
+		return
+	}
+	x.cancel()
+	<-x.done
+}
+
+// receive moves events from the subscription into the exporter's queue, so
+// that a slow sink does not cause events to be skipped by the publisher.
+func (x *Exporter) receive(ctx context.Context, l chan interface{}) {
+	defer close(x.received)
+	defer x.events.Evict(l)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev, ok := <-l:
+			if !ok {
+				return
+			}
+			x.enqueue(ev.(eventtypes.Message))
+		}
+	}
+}
+
+// enqueue queues ev, or spools it if the queue is full or events are
+// spilled.
+func (x *Exporter) enqueue(ev eventtypes.Message) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if !x.spilled {
+		select {
+		case x.queue <- ev:
+			return
+		default:
+			x.spilled = true
+		}
+	}
+	if err := x.spool.append(ev); err != nil {
+		logrus.WithError(err).WithField("sink", x.sink.String()).Error("failed to spool event, dropping event")
+		return
+	}
+	select {
+	case x.spooled <- struct{}{}:
+	default:
+	}
+}
+
+func (x *Exporter) deliver(ctx context.Context) {
+	defer close(x.done)
+	for {
+		select {
+		case <-ctx.Done():
+			x.persist()
+			return
+		case ev := <-x.queue:
+			if !x.send(ctx, ev) {
+				x.persist(ev)
+				return
+			}
+		case <-x.spooled:
+			// The queued events were received before the spooled ones.
+			if !x.drain(ctx) {
+				return
+			}
+			if !x.deliverSpooled(ctx) {
+				x.persist()
+				return
+			}
+		}
+	}
+}
+
+// drain delivers the queued events. It returns false if the exporter was
+// stopped.
+func (x *Exporter) drain(ctx context.Context) bool {
+	for {
+		select {
+		case ev := <-x.queue:
+			if !x.send(ctx, ev) {
+				x.persist(ev)
+				return false
+			}
+		default:
+			return true
+		}
+	}
+}
+
+// deliverSpooled delivers the spooled events until there are none left, and
+// events are queued again. It returns false if the exporter was stopped, in
+// which case the events being delivered are kept, and delivered again when
+// the exporter starts.
+func (x *Exporter) deliverSpooled(ctx context.Context) bool {
+	for {
+		x.mu.Lock()
+		if !x.replay.exists() {
+			if !x.spool.exists() {
+				x.spilled = false
+				x.mu.Unlock()
+				return true
+			}
+			if err := x.spool.moveTo(&x.replay); err != nil {
+				// The events stay spooled until the next spilled event.
+				logrus.WithError(err).WithField("sink", x.sink.String()).Error("failed to deliver spooled events")
+				x.spilled = false
+				x.mu.Unlock()
+				return true
+			}
+		}
+		x.mu.Unlock()
+
+		done, err := x.replay.each(func(ev eventtypes.Message) bool {
+			return x.send(ctx, ev)
+		})
+		if err != nil {
+			logrus.WithError(err).WithField("sink", x.sink.String()).Error("failed to read spooled events, dropping them")
+		} else if !done {
+			return false
+		}
+		if err := x.replay.remove(); err != nil {
+			logrus.WithError(err).WithField("sink", x.sink.String()).Error("failed to remove delivered events")
+			return true
+		}
+	}
+}
+
+// persist spools evs and the queued events once the exporter is stopped, so
+// that they are delivered when it starts again.
+func (x *Exporter) persist(evs ...eventtypes.Message) {
+	<-x.received
+	for len(x.queue) > 0 {
+		evs = append(evs, <-x.queue)
+	}
+	if len(evs) == 0 {
+		return
+	}
+	if err := x.spool.append(evs...); err != nil {
+		logrus.WithError(err).WithField("sink", x.sink.String()).Errorf("failed to spool %d events, dropping them", len(evs))
+	}
+}
+
+// send delivers ev to the sink, retrying with an exponential backoff until
+// the sink accepts or rejects it. Events rejected by the sink are added to
+// the dead-letter file. It returns false if the exporter was stopped before
+// the event was delivered.
+func (x *Exporter) send(ctx context.Context, ev eventtypes.Message) bool {
+	backoff := exportInitialBackoff
+	for {
+		err := x.sink.Send(ctx, ev)
+		if err == nil {
+			return true
+		}
+		if errdefs.IsInvalidParameter(err) {
+			logrus.WithError(err).WithFields(logrus.Fields{
+				"sink":   x.sink.String(),
+				"type":   ev.Type,
+				"action": ev.Action,
+			}).Error("event rejected by sink, adding it to the dead-letter file")
+			if err := x.dead.append(ev); err != nil {
+				logrus.WithError(err).WithField("sink", x.sink.String()).Error("failed to add event to the dead-letter file")
+			}
+			return true
+		}
+		logrus.WithError(err).WithField("sink", x.sink.String()).Debugf("failed to export event, retrying in %s", backoff)
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > exportMaxBackoff {
+			backoff = exportMaxBackoff
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package events // import "github.com/docker/docker/daemon/events"
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	eventtypes "github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestExporterWebhookRetry(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		attempts int
+		received []eventtypes.Message
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts++
+		if attempts <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var ev eventtypes.Message
+		if err := json.NewDecoder(r.Body).Decode(&ev); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received = append(received, ev)
+	}))
+	defer srv.Close()
+
+	e := New()
+	x := NewExporter(e, NewWebhookSink(srv.URL), NewFilter(filters.NewArgs(filters.Arg("type", "container"))), t.TempDir())
+	assert.NilError(t, x.Start())
+	defer x.Stop()
+
+	e.Log("create", eventtypes.NetworkEventType, eventtypes.Actor{ID: "net1"})
+	e.Log("start", eventtypes.ContainerEventType, eventtypes.Actor{ID: "ctr1"})
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		mu.Lock()
+		n := len(received)
+		mu.Unlock()
+		if n > 0 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Assert(t, is.Len(received, 1))
+	assert.Check(t, is.Equal(received[0].Action, "start"))
+	assert.Check(t, is.Equal(received[0].Actor.ID, "ctr1"))
+	assert.Check(t, is.Equal(attempts, 3))
+}
+
+type fakeSink struct {
+	mu       sync.Mutex
+	err      error
+	received []eventtypes.Message
+}
+
+func (s *fakeSink) Send(ctx context.Context, ev eventtypes.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.received = append(s.received, ev)
+	return nil
+}
+
+func (s *fakeSink) setErr(err error) {
+	s.mu.Lock()
+	s.err = err
+	s.mu.Unlock()
+}
+
+func (s *fakeSink) actors() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var ids []string
+	for _, ev := range s.received {
+		ids = append(ids, ev.Actor.ID)
+	}
+	return ids
+}
+
+func (s *fakeSink) String() string {
+	return "fake"
+}
+
+func waitActors(t *testing.T, sink *fakeSink, n int) []string {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		ids := sink.actors()
+		if len(ids) >= n || time.Now().After(deadline) {
+			return ids
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestExporterWebhookReject(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	err := NewWebhookSink(srv.URL).Send(context.Background(), eventtypes.Message{})
+	assert.Check(t, errdefs.IsInvalidParameter(err), err)
+}
+
+func TestExporterDeadLetter(t *testing.T) {
+	root := t.TempDir()
+	sink := &fakeSink{err: errdefs.InvalidParameter(errors.New("rejected"))}
+	e := New()
+	x := NewExporter(e, sink, NewFilter(filters.NewArgs()), root)
+	assert.NilError(t, x.Start())
+	defer x.Stop()
+
+	e.Log("start", eventtypes.ContainerEventType, eventtypes.Actor{ID: "ctr1"})
+	deadline := time.Now().Add(5 * time.Second)
+	for !x.dead.exists() && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	sink.setErr(nil)
+	e.Log("start", eventtypes.ContainerEventType, eventtypes.Actor{ID: "ctr2"})
+	assert.Check(t, is.DeepEqual(waitActors(t, sink, 1), []string{"ctr2"}))
+
+	var dead []string
+	_, err := x.dead.each(func(ev eventtypes.Message) bool {
+		dead = append(dead, ev.Actor.ID)
+		return true
+	})
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(dead, []string{"ctr1"}))
+}
+
+func TestExporterSpool(t *testing.T) {
+	root := t.TempDir()
+	sink := &fakeSink{err: errors.New("unavailable")}
+	e := New()
+	x := NewExporter(e, sink, NewFilter(filters.NewArgs()), root)
+	x.queue = make(chan eventtypes.Message, 2)
+	assert.NilError(t, x.Start())
+
+	// The events which don't fit in the queue are spooled, and the events
+	// not delivered when the exporter stops are spooled too.
+	for _, id := range []string{"ctr1", "ctr2", "ctr3", "ctr4", "ctr5"} {
+		e.Log("start", eventtypes.ContainerEventType, eventtypes.Actor{ID: id})
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for !x.spool.exists() && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	x.Stop()
+	assert.Check(t, is.Len(sink.actors(), 0))
+
+	sink.setErr(nil)
+	x = NewExporter(e, sink, NewFilter(filters.NewArgs()), root)
+	assert.NilError(t, x.Start())
+	defer x.Stop()
+	e.Log("start", eventtypes.ContainerEventType, eventtypes.Actor{ID: "ctr6"})
+
+	ids := waitActors(t, sink, 6)
+	assert.Check(t, is.Len(ids, 6))
+	assert.Check(t, is.Equal(ids[len(ids)-1], "ctr6"))
+}
@@ -31,6 +31,7 @@ func (ef *Filter) Include(ev events.Message) bool {
 		ef.matchService(ev) &&
 		ef.matchSecret(ev) &&
 		ef.matchConfig(ev) &&
+		ef.matchHealthStatus(ev) &&
 		ef.matchLabels(ev.Actor.Attributes)
 }
 
@@ -66,6 +67,16 @@ func (ef *Filter) matchLabels(attributes map[string]string) bool {
 	return ef.filter.MatchKVList("label", attributes)
 }
 
+// matchHealthStatus matches the health status a container transitioned to in
+// health_status events. Other events are excluded when filtering on health
+// status.
+func (ef *Filter) matchHealthStatus(ev events.Message) bool {
+	if !ef.filter.Contains("health_status") {
+		return true
+	}
+	return ef.filter.ExactMatch("health_status", ev.Actor.Attributes["healthStatus"])
+}
+
 func (ef *Filter) matchDaemon(ev events.Message) bool {
 	return ef.fuzzyMatchName(ev, events.DaemonEventType)
 }
@@ -0,0 +1,69 @@
+package events // import "github.com/docker/docker/daemon/events"
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	"gotest.tools/v3/assert"
+)
+
+func TestFilterHealthStatus(t *testing.T) {
+	healthy := events.Message{
+		Type:   events.ContainerEventType,
+		Action: "health_status: healthy",
+		Actor:  events.Actor{ID: "foo", Attributes: map[string]string{"healthStatus": "healthy"}},
+	}
+	unhealthy := events.Message{
+		Type:   events.ContainerEventType,
+		Action: "health_status: unhealthy",
+		Actor:  events.Actor{ID: "foo", Attributes: map[string]string{"healthStatus": "unhealthy"}},
+	}
+	start := events.Message{
+		Type:   events.ContainerEventType,
+		Action: "start",
+		Actor:  events.Actor{ID: "foo"},
+	}
+
+	ef := NewFilter(filters.NewArgs(filters.Arg("health_status", "unhealthy")))
+	assert.Check(t, !ef.Include(healthy))
+	assert.Check(t, ef.Include(unhealthy))
+	assert.Check(t, !ef.Include(start))
+
+	ef = NewFilter(filters.NewArgs())
+	assert.Check(t, ef.Include(healthy))
+	assert.Check(t, ef.Include(unhealthy))
+	assert.Check(t, ef.Include(start))
+}
+
+func TestFilterLabelSelectors(t *testing.T) {
+	prod := events.Message{
+		Type:   events.ContainerEventType,
+		Action: "start",
+		Actor:  events.Actor{ID: "foo", Attributes: map[string]string{"env": "prod", "image": "busybox"}},
+	}
+	dev := events.Message{
+		Type:   events.ContainerEventType,
+		Action: "start",
+		Actor:  events.Actor{ID: "bar", Attributes: map[string]string{"env": "dev", "image": "busybox"}},
+	}
+	unlabeled := events.Message{
+		Type:   events.ContainerEventType,
+		Action: "start",
+		Actor:  events.Actor{ID: "baz", Attributes: map[string]string{"image": "busybox"}},
+	}
+
+	ef := NewFilter(filters.NewArgs(filters.Arg("label", "env in (prod, staging)")))
+	assert.Check(t, ef.Include(prod))
+	assert.Check(t, !ef.Include(dev))
+	assert.Check(t, !ef.Include(unlabeled))
+
+	ef = NewFilter(filters.NewArgs(filters.Arg("label", "env!=prod")))
+	assert.Check(t, !ef.Include(prod))
+	assert.Check(t, ef.Include(dev))
+	assert.Check(t, ef.Include(unlabeled))
+
+	ef = NewFilter(filters.NewArgs(filters.Arg("label", "!env")))
+	assert.Check(t, !ef.Include(prod))
+	assert.Check(t, ef.Include(unlabeled))
+}
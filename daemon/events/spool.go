@@ -0,0 +1,84 @@
+package events // import "github.com/docker/docker/daemon/events"
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+
+	eventtypes "github.com/docker/docker/api/types/events"
+	"github.com/pkg/errors"
+)
+
+// spool is a file of events, stored as JSON lines, which persists the events
+// of an exporter that cannot be kept in memory.
+type spool struct {
+	path string
+}
+
+// append adds events at the end of the spool.
+func (s *spool) append(evs ...eventtypes.Message) error {
+	f, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, ev := range evs {
+		if err := enc.Encode(ev); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// exists returns whether the spool holds events.
+func (s *spool) exists() bool {
+	_, err := os.Stat(s.path)
+	return err == nil
+}
+
+// each calls fn with each event of the spool, in order, until fn returns
+// false. It returns whether all the events were passed to fn.
+func (s *spool) each(fn func(eventtypes.Message) bool) (bool, error) {
+	f, err := os.Open(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return true, nil
+		}
+		return false, err
+	}
+	defer f.Close()
+
+	dec := json.NewDecoder(f)
+	for {
+		var ev eventtypes.Message
+		if err := dec.Decode(&ev); err != nil {
+			if err == io.EOF {
+				return true, nil
+			}
+			return false, errors.Wrapf(err, "invalid event in %s", s.path)
+		}
+		if !fn(ev) {
+			return false, nil
+		}
+	}
+}
+
+// moveTo moves the events of the spool to another, empty, spool.
+func (s *spool) moveTo(dst *spool) error {
+	return os.Rename(s.path, dst.path)
+}
+
+// remove removes all the events of the spool.
+func (s *spool) remove() error {
+	if err := os.Remove(s.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
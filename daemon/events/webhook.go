@@ -0,0 +1,68 @@
+package events // import "github.com/docker/docker/daemon/events"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	eventtypes "github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+)
+
+const webhookTimeout = 10 * time.Second
+
+// WebhookSink is a Sink that POSTs each event as JSON to a URL. The event is
+// considered delivered if the server responds with a 2xx status code, and
+// rejected if it responds with a 4xx status code other than 408 and 429.
+type WebhookSink struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookSink returns a Sink that delivers events to the given URL.
+func NewWebhookSink(url string) *WebhookSink {
+	return &WebhookSink{
+		url:    url,
+		client: &http.Client{Timeout: webhookTimeout},
+	}
+}
+
+// Send delivers a single event to the webhook.
+func (s *WebhookSink) Send(ctx context.Context, ev eventtypes.Message) error {
+	b, err := json.Marshal(ev)
+	if err != nil {
+		return errdefs.InvalidParameter(err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Docker-Event-Type", string(ev.Type))
+	req.Header.Set("X-Docker-Event-Time", fmt.Sprint(ev.TimeNano))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		err := errors.Errorf("webhook returned status %d", resp.StatusCode)
+		// Client errors are permanent, except for timeouts and rate limits.
+		if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests {
+			return errdefs.InvalidParameter(err)
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *WebhookSink) String() string {
+	return "webhook " + s.url
+}
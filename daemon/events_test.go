@@ -8,6 +8,8 @@ import (
 	eventtypes "github.com/docker/docker/api/types/events"
 	"github.com/docker/docker/container"
 	"github.com/docker/docker/daemon/events"
+	networktypes "github.com/docker/docker/libnetwork/types"
+	swarmapi "github.com/moby/swarmkit/v2/api"
 )
 
 func TestLogContainerEventCopyLabels(t *testing.T) {
@@ -88,3 +90,80 @@ func validateTestAttributes(t *testing.T, l chan interface{}, expectedAttributes
 		t.Fatal("LogEvent test timed out")
 	}
 }
+
+func TestLogSwarmEventRotation(t *testing.T) {
+	e := events.New()
+	_, l, _ := e.Subscribe()
+	defer e.Evict(l)
+
+	daemon := &Daemon{
+		EventsService: e,
+	}
+	oldCluster := &swarmapi.Cluster{
+		ID:                   "cluster_id",
+		UnlockKeys:           []*swarmapi.EncryptionKey{{Key: []byte("old")}},
+		NetworkBootstrapKeys: []*swarmapi.EncryptionKey{{LamportTime: 1}, {LamportTime: 2}},
+	}
+	cluster := oldCluster.Copy()
+
+	// unrelated updates do not generate events
+	daemon.logSwarmEvent(cluster, oldCluster)
+
+	cluster.UnlockKeys = []*swarmapi.EncryptionKey{{Key: []byte("new")}}
+	daemon.logSwarmEvent(cluster, oldCluster)
+	validateTestAttributes(t, l, map[string]string{"key": "unlock-key"})
+
+	cluster = oldCluster.Copy()
+	cluster.NetworkBootstrapKeys = append(cluster.NetworkBootstrapKeys[1:], &swarmapi.EncryptionKey{LamportTime: 3})
+	daemon.logSwarmEvent(cluster, oldCluster)
+	validateTestAttributes(t, l, map[string]string{"key": "data-path-key"})
+}
+
+func TestTrackDataPathKeys(t *testing.T) {
+	daemon := &Daemon{}
+	now := time.Now().UTC()
+
+	// the keys received when joining the swarm are not a rotation
+	daemon.trackDataPathKeys([]*networktypes.EncryptionKey{{LamportTime: 1}, {LamportTime: 2}}, now)
+	if rotatedAt := daemon.DataPathKeysRotatedAt(); !rotatedAt.IsZero() {
+		t.Fatalf("expected no rotation, got %s", rotatedAt)
+	}
+
+	daemon.trackDataPathKeys([]*networktypes.EncryptionKey{{LamportTime: 2}, {LamportTime: 3}}, now)
+	if rotatedAt := daemon.DataPathKeysRotatedAt(); !rotatedAt.Equal(now) {
+		t.Fatalf("expected a rotation at %s, got %s", now, rotatedAt)
+	}
+
+	// keys received again are not a rotation
+	daemon.trackDataPathKeys([]*networktypes.EncryptionKey{{LamportTime: 2}, {LamportTime: 3}}, now.Add(time.Hour))
+	if rotatedAt := daemon.DataPathKeysRotatedAt(); !rotatedAt.Equal(now) {
+		t.Fatalf("expected a rotation at %s, got %s", now, rotatedAt)
+	}
+}
+
+func TestLogServiceRolloutEvent(t *testing.T) {
+	e := events.New()
+	_, l, _ := e.Subscribe()
+	defer e.Evict(l)
+
+	daemon := &Daemon{
+		EventsService: e,
+	}
+	oldService := &swarmapi.Service{
+		ID: "service_id",
+		Spec: swarmapi.ServiceSpec{
+			Annotations: swarmapi.Annotations{Name: "service_name"},
+		},
+	}
+	service := oldService.Copy()
+	service.UpdateStatus = &swarmapi.UpdateStatus{State: swarmapi.UpdateStatus_UPDATING}
+	daemon.logServiceRolloutEvent(service, oldService, time.Now())
+	validateTestAttributes(t, l, map[string]string{"name": "service_name", "phase": "update-started"})
+
+	oldService = service
+	service = oldService.Copy()
+	service.UpdateStatus.State = swarmapi.UpdateStatus_PAUSED
+	service.UpdateStatus.Message = "update paused due to failure or early termination of task"
+	daemon.logServiceRolloutEvent(service, oldService, time.Now())
+	validateTestAttributes(t, l, map[string]string{"phase": "update-paused", "message": service.UpdateStatus.Message})
+}
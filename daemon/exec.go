@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"io"
 	"runtime"
+	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/containerd/containerd"
@@ -111,6 +113,10 @@ func (daemon *Daemon) ContainerExecCreate(name string, config *types.ExecConfig)
 		}
 	}
 
+	if err := validateExecResources(config.Resources); err != nil {
+		return "", err
+	}
+
 	execConfig := container.NewExecConfig(cntr)
 	execConfig.OpenStdin = config.AttachStdin
 	execConfig.OpenStdout = config.AttachStdout
@@ -123,6 +129,7 @@ func (daemon *Daemon) ContainerExecCreate(name string, config *types.ExecConfig)
 	execConfig.Privileged = config.Privileged
 	execConfig.User = config.User
 	execConfig.WorkingDir = config.WorkingDir
+	execConfig.Resources = config.Resources
 
 	linkedEnv, err := daemon.setupLinkedContainers(cntr)
 	if err != nil {
@@ -172,6 +179,8 @@ func (daemon *Daemon) ContainerExecStart(ctx context.Context, name string, optio
 		return errdefs.Conflict(fmt.Errorf("Error: Exec command %s is already running", ec.ID))
 	}
 	ec.Running = true
+	ec.StartedAt = time.Now().UTC()
+	ec.Client = options.Client
 	ec.Unlock()
 
 	logrus.Debugf("starting exec command %s in container %s", ec.ID, ec.Container.ID)
@@ -282,16 +291,40 @@ func (daemon *Daemon) ContainerExecStart(ctx context.Context, name string, optio
 
 	// Synchronize with libcontainerd event loop
 	ec.Lock()
+	if ec.Resources != nil {
+		if err := daemon.execCreateCgroup(ec); err != nil {
+			close(ec.Started)
+			ec.Unlock()
+			return err
+		}
+	}
 	ec.Process, err = tsk.Exec(ctx, ec.ID, p, cStdin != nil, ec.InitializeStdio)
 	// the exec context should be ready, or error happened.
 	// close the chan to notify readiness
 	close(ec.Started)
 	if err != nil {
 		defer ec.Unlock()
+		daemon.execRemoveCgroup(ec)
 		return setExitCodeFromError(ec.SetExitCode, err)
 	}
+	var resourcesErr error
+	if ec.Resources != nil {
+		// The exit of the exec process is handled with its lock held, so
+		// its cgroup can't be removed before the process is moved to it.
+		resourcesErr = daemon.execApplyResources(ec)
+	}
 	ec.Unlock()
 
+	if resourcesErr != nil {
+		// Do not leave the exec process running without its limits.
+		killCtx, cancelFunc := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancelFunc()
+		if err := ec.Process.Kill(killCtx, signal.SignalMap["KILL"]); err != nil {
+			logrus.WithError(err).WithField("exec", ec.ID).Error("Could not send KILL signal to exec process")
+		}
+		return resourcesErr
+	}
+
 	select {
 	case <-ctx.Done():
 		log := logrus.
@@ -319,6 +352,43 @@ func (daemon *Daemon) ContainerExecStart(ctx context.Context, name string, optio
 	return nil
 }
 
+// ContainerExecKill sends a signal to a running exec process. The process is
+// killed if no signal is given.
+func (daemon *Daemon) ContainerExecKill(name, sig string) error {
+	s := syscall.SIGKILL
+	if sig != "" {
+		var err error
+		s, err = signal.ParseSignal(sig)
+		if err != nil {
+			return errdefs.InvalidParameter(err)
+		}
+		if !signal.ValidSignalForPlatform(s) {
+			return errdefs.InvalidParameter(errors.Errorf("the %s daemon does not support signal %d", runtime.GOOS, s))
+		}
+	}
+
+	ec, err := daemon.getExecConfig(name)
+	if err != nil {
+		return err
+	}
+	ec.Lock()
+	running, p := ec.Running, ec.Process
+	ec.Unlock()
+	if !running || p == nil {
+		return errdefs.Conflict(errors.Errorf("Exec %s is not running", ec.ID))
+	}
+
+	if err := p.Kill(context.Background(), s); err != nil {
+		return errors.Wrapf(err, "Cannot kill exec %s", ec.ID)
+	}
+	attributes := map[string]string{
+		"execID": ec.ID,
+		"signal": strconv.Itoa(int(s)),
+	}
+	daemon.LogContainerEventWithAttributes(ec.Container, "exec_kill", attributes)
+	return nil
+}
+
 // execCommandGC runs a ticker to clean up the daemon references
 // of exec configs that are no longer part of the container.
 func (daemon *Daemon) execCommandGC() {
@@ -2,11 +2,21 @@ package daemon // import "github.com/docker/docker/daemon"
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
 
+	"github.com/containerd/cgroups"
 	"github.com/containerd/containerd/pkg/apparmor"
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/container"
+	"github.com/docker/docker/errdefs"
 	"github.com/docker/docker/oci/caps"
+	"github.com/docker/docker/pkg/sysinfo"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 )
 
 func (daemon *Daemon) execSetPlatformOpt(ctx context.Context, ec *container.ExecConfig, p *specs.Process) error {
@@ -52,3 +62,171 @@ func (daemon *Daemon) execSetPlatformOpt(ctx context.Context, ec *container.Exec
 	s := &specs.Spec{Process: p}
 	return WithRlimits(daemon, ec.Container)(ctx, nil, nil, s)
 }
+
+// validateExecResources validates the resource limits of an exec process.
+func validateExecResources(r *types.ExecResources) error {
+	if r == nil {
+		return nil
+	}
+	if cgroups.Mode() == cgroups.Unified {
+		// The controllers of a cgroup v2 can only be enabled for its
+		// sub-cgroups if it has no processes, which isn't the case of the
+		// container's cgroup.
+		return errdefs.InvalidParameter(errors.New("resource limits of exec processes are not supported with cgroup v2"))
+	}
+	if r.Memory < 0 || (r.Memory > 0 && r.Memory < linuxMinMemory) {
+		return errdefs.InvalidParameter(errors.New("Minimum memory limit allowed is 6MB"))
+	}
+	if r.NanoCPUs < 0 || r.NanoCPUs > int64(sysinfo.NumCPU())*1e9 {
+		return errdefs.InvalidParameter(fmt.Errorf("Range of CPUs is from 0.01 to %d.00, as there are only %d CPUs available", sysinfo.NumCPU(), sysinfo.NumCPU()))
+	}
+	return nil
+}
+
+func execLinuxResources(r *types.ExecResources) *specs.LinuxResources {
+	resources := &specs.LinuxResources{}
+	if r.Memory > 0 {
+		memory := r.Memory
+		resources.Memory = &specs.LinuxMemory{Limit: &memory}
+	}
+	if r.NanoCPUs > 0 {
+		period := uint64(100 * time.Millisecond / time.Microsecond)
+		quota := r.NanoCPUs * int64(period) / 1e9
+		resources.CPU = &specs.LinuxCPU{Period: &period, Quota: &quota}
+	}
+	if r.PidsLimit != nil && *r.PidsLimit > 0 {
+		resources.Pids = &specs.LinuxPids{Limit: *r.PidsLimit}
+	}
+	return resources
+}
+
+// execHierarchy is the cgroup v1 hierarchy of the sub-cgroups of execs, which
+// only consists of the subsystems the limits and the accounting of execs use.
+func execHierarchy() ([]cgroups.Subsystem, error) {
+	subsystems, err := cgroups.V1()
+	if err != nil {
+		return nil, err
+	}
+	var filtered []cgroups.Subsystem
+	for _, s := range subsystems {
+		switch s.Name() {
+		case cgroups.Memory, cgroups.Cpu, cgroups.Cpuacct, cgroups.Pids:
+			filtered = append(filtered, s)
+		}
+	}
+	return filtered, nil
+}
+
+// execCreateCgroup creates the sub-cgroup of the container's cgroup the exec
+// process runs in, with its resource limits. The limits of the container still
+// apply to the exec process, as the sub-cgroup is nested. It must be called
+// with the lock of the exec held, before the exec process is started.
+func (daemon *Daemon) execCreateCgroup(ec *container.ExecConfig) error {
+	paths, err := cgroups.ParseCgroupFile(fmt.Sprintf("/proc/%d/cgroup", ec.Container.State.GetPID()))
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the cgroup of container %s", ec.Container.ID)
+	}
+	cgroupPath := filepath.Join(paths[string(cgroups.Memory)], "exec-"+ec.ID)
+	if _, err := cgroups.New(execHierarchy, cgroups.StaticPath(cgroupPath), execLinuxResources(ec.Resources)); err != nil {
+		return errors.Wrapf(err, "failed to create the cgroup of exec %s", ec.ID)
+	}
+	ec.CgroupPath = cgroupPath
+	return nil
+}
+
+// execApplyResources moves the exec process into the sub-cgroup created by
+// execCreateCgroup. Processes can only be moved once started, so the exec
+// process runs without its limits until it is moved. It must be called with
+// the lock of the exec held, so that the sub-cgroup is only removed once the
+// exec process exits.
+func (daemon *Daemon) execApplyResources(ec *container.ExecConfig) error {
+	cg, err := cgroups.Load(execHierarchy, cgroups.StaticPath(ec.CgroupPath))
+	if err != nil {
+		return errors.Wrapf(err, "failed to load the cgroup of exec %s", ec.ID)
+	}
+	pid := int(ec.Process.Pid())
+	if err := cg.Add(cgroups.Process{Pid: pid}); err != nil {
+		if _, statErr := os.Stat(fmt.Sprintf("/proc/%d", pid)); os.IsNotExist(statErr) {
+			// The exec process already exited.
+			return nil
+		}
+		return errors.Wrapf(err, "failed to move exec %s to its cgroup", ec.ID)
+	}
+	return nil
+}
+
+// execRemoveCgroup removes the sub-cgroup of an exec process which exited.
+// It must be called with the lock of the exec held.
+func (daemon *Daemon) execRemoveCgroup(ec *container.ExecConfig) {
+	if ec.CgroupPath == "" {
+		return
+	}
+	cg, err := cgroups.Load(execHierarchy, cgroups.StaticPath(ec.CgroupPath))
+	if err == nil {
+		err = cg.Delete()
+	}
+	if err != nil && !errors.Is(err, cgroups.ErrCgroupDeleted) {
+		logrus.WithError(err).WithField("exec", ec.ID).Warn("failed to remove the cgroup of exec")
+	}
+	ec.CgroupPath = ""
+}
+
+// getExecStats returns the usage of the running exec processes of the
+// container which have resource limits.
+func (daemon *Daemon) getExecStats(c *container.Container) map[string]types.ExecStats {
+	var stats map[string]types.ExecStats
+	for _, id := range c.ExecCommands.List() {
+		ec := c.ExecCommands.Get(id)
+		if ec == nil {
+			continue
+		}
+		ec.Lock()
+		s := execUsage(ec.CgroupPath)
+		ec.Unlock()
+		if s == nil {
+			continue
+		}
+		if stats == nil {
+			stats = make(map[string]types.ExecStats)
+		}
+		stats[id] = *s
+	}
+	return stats
+}
+
+// execUsage returns the usage of the exec process in the sub-cgroup at
+// cgroupPath, or nil if the exec process has no resource limits.
+func execUsage(cgroupPath string) *types.ExecStats {
+	if cgroupPath == "" {
+		return nil
+	}
+	s, err := execCgroupStats(cgroupPath)
+	if err != nil {
+		logrus.WithError(err).WithField("cgroup", cgroupPath).Debug("failed to get the stats of exec")
+		return nil
+	}
+	return &s
+}
+
+func execCgroupStats(cgroupPath string) (types.ExecStats, error) {
+	var s types.ExecStats
+	cg, err := cgroups.Load(execHierarchy, cgroups.StaticPath(cgroupPath))
+	if err != nil {
+		return s, err
+	}
+	m, err := cg.Stat(cgroups.IgnoreNotExist)
+	if err != nil {
+		return s, err
+	}
+	if m.CPU != nil && m.CPU.Usage != nil {
+		s.CPUUsage = m.CPU.Usage.Total
+	}
+	if m.Memory != nil && m.Memory.Usage != nil {
+		s.MemoryUsage = m.Memory.Usage.Usage
+		s.MemoryLimit = m.Memory.Usage.Limit
+	}
+	if m.Pids != nil {
+		s.PidsCurrent = m.Pids.Current
+	}
+	return s, nil
+}
@@ -7,12 +7,15 @@ import (
 	"context"
 	"testing"
 
+	"github.com/containerd/cgroups"
 	"github.com/containerd/containerd/pkg/apparmor"
+	"github.com/docker/docker/api/types"
 	containertypes "github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/container"
 	"github.com/docker/docker/daemon/config"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
 	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
 )
 
 func TestExecSetPlatformOptAppArmor(t *testing.T) {
@@ -89,3 +92,29 @@ func TestExecSetPlatformOptAppArmor(t *testing.T) {
 		}
 	}
 }
+
+func TestExecLinuxResources(t *testing.T) {
+	pids := int64(10)
+	r := execLinuxResources(&types.ExecResources{Memory: 64 << 20, NanoCPUs: 5e8, PidsLimit: &pids})
+	assert.Check(t, is.Equal(*r.Memory.Limit, int64(64<<20)))
+	assert.Check(t, is.Equal(*r.CPU.Period, uint64(100000)))
+	assert.Check(t, is.Equal(*r.CPU.Quota, int64(50000)))
+	assert.Check(t, is.Equal(r.Pids.Limit, int64(10)))
+
+	unlimited := int64(-1)
+	r = execLinuxResources(&types.ExecResources{PidsLimit: &unlimited})
+	assert.Check(t, is.Nil(r.Memory))
+	assert.Check(t, is.Nil(r.CPU))
+	assert.Check(t, is.Nil(r.Pids))
+}
+
+func TestValidateExecResources(t *testing.T) {
+	assert.Check(t, validateExecResources(nil))
+	if cgroups.Mode() == cgroups.Unified {
+		assert.Check(t, is.ErrorContains(validateExecResources(&types.ExecResources{Memory: linuxMinMemory}), "not supported with cgroup v2"))
+		return
+	}
+	assert.Check(t, validateExecResources(&types.ExecResources{Memory: linuxMinMemory, NanoCPUs: 1e9}))
+	assert.Check(t, is.ErrorContains(validateExecResources(&types.ExecResources{Memory: 1 << 20}), "Minimum memory limit allowed is 6MB"))
+	assert.Check(t, is.ErrorContains(validateExecResources(&types.ExecResources{NanoCPUs: -1}), "Range of CPUs is from 0.01"))
+}
@@ -3,8 +3,11 @@ package daemon // import "github.com/docker/docker/daemon"
 import (
 	"context"
 
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/container"
+	"github.com/docker/docker/errdefs"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/pkg/errors"
 )
 
 func (daemon *Daemon) execSetPlatformOpt(ctx context.Context, ec *container.ExecConfig, p *specs.Process) error {
@@ -13,3 +16,28 @@ func (daemon *Daemon) execSetPlatformOpt(ctx context.Context, ec *container.Exec
 	}
 	return nil
 }
+
+func validateExecResources(r *types.ExecResources) error {
+	if r != nil {
+		return errdefs.InvalidParameter(errors.New("resource limits of exec processes are not supported on Windows"))
+	}
+	return nil
+}
+
+func (daemon *Daemon) execCreateCgroup(ec *container.ExecConfig) error {
+	return errors.New("resource limits of exec processes are not supported on Windows")
+}
+
+func (daemon *Daemon) execApplyResources(ec *container.ExecConfig) error {
+	return errors.New("resource limits of exec processes are not supported on Windows")
+}
+
+func (daemon *Daemon) execRemoveCgroup(ec *container.ExecConfig) {}
+
+func (daemon *Daemon) getExecStats(c *container.Container) map[string]types.ExecStats {
+	return nil
+}
+
+func execUsage(cgroupPath string) *types.ExecStats {
+	return nil
+}
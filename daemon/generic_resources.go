@@ -0,0 +1,179 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// nvidiaGPUResource is the kind of the generic resources that NVIDIA
+	// GPUs are advertised as.
+	nvidiaGPUResource = "NVIDIA-GPU"
+
+	// genericResourceLabelPrefix is the prefix of the engine labels that
+	// describe the attributes of discovered generic resources, in the form
+	// "com.docker.resource.<kind>.<value>.<attribute>=<value>".
+	genericResourceLabelPrefix = "com.docker.resource."
+
+	// nvidiaSMITimeout is how long nvidia-smi may run, as it hangs when the
+	// driver is in a bad state.
+	nvidiaSMITimeout = 10 * time.Second
+)
+
+var (
+	// cdiSpecDirs are the directories CDI specs are read from.
+	cdiSpecDirs = []string{"/etc/cdi", "/var/run/cdi"}
+
+	// invalidResourceKindChars matches the characters of CDI kinds which
+	// are not valid in the name of the environment variables swarm tasks
+	// get their generic resources from.
+	invalidResourceKindChars = regexp.MustCompile(`[^A-Za-z0-9_]`)
+)
+
+// discoveredResource is a generic resource discovered on the host.
+type discoveredResource struct {
+	kind, value string
+	attributes  map[string]string
+}
+
+// discoverGenericResources discovers the devices of the host that can be
+// advertised as generic resources: NVIDIA GPUs, if nvidia-smi is installed,
+// and the devices described by the CDI specs in cdiSpecDirs.
+func discoverGenericResources() []discoveredResource {
+	var resources []discoveredResource
+	if path, err := exec.LookPath("nvidia-smi"); err == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), nvidiaSMITimeout)
+		out, err := exec.CommandContext(ctx, path, "--query-gpu=uuid,name,memory.total", "--format=csv,noheader,nounits").Output()
+		cancel()
+		if err != nil {
+			logrus.WithError(err).Warn("failed to discover NVIDIA GPUs")
+		} else {
+			resources = append(resources, parseNvidiaGPUs(out)...)
+		}
+	}
+	for _, dir := range cdiSpecDirs {
+		var files []string
+		for _, ext := range []string{"*.json", "*.yaml"} {
+			matches, _ := filepath.Glob(filepath.Join(dir, ext))
+			files = append(files, matches...)
+		}
+		for _, file := range files {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				logrus.WithError(err).WithField("file", file).Warn("failed to read CDI spec")
+				continue
+			}
+			devices, err := parseCDISpec(data)
+			if err != nil {
+				logrus.WithError(err).WithField("file", file).Warn("failed to parse CDI spec")
+				continue
+			}
+			resources = append(resources, devices...)
+		}
+	}
+	return resources
+}
+
+// parseNvidiaGPUs parses the output of
+// "nvidia-smi --query-gpu=uuid,name,memory.total --format=csv,noheader,nounits".
+func parseNvidiaGPUs(out []byte) []discoveredResource {
+	var gpus []discoveredResource
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), ",")
+		if len(fields) != 3 {
+			continue
+		}
+		uuid := strings.TrimSpace(fields[0])
+		if uuid == "" {
+			continue
+		}
+		gpus = append(gpus, discoveredResource{
+			kind:  nvidiaGPUResource,
+			value: uuid,
+			attributes: map[string]string{
+				"model":  strings.TrimSpace(fields[1]),
+				"memory": strings.TrimSpace(fields[2]) + "MiB",
+			},
+		})
+	}
+	return gpus
+}
+
+// parseCDISpec returns the devices described by a CDI spec, in JSON or YAML
+// format. The devices are advertised with the kind of the spec, for example
+// "vendor.com/device", as resource kind, with the characters that are not
+// valid in environment variable names replaced by underscores, as in
+// "vendor_com_device". Their name is the value of the resources, and their
+// fully qualified CDI name, as in "vendor.com/device=dev0", is set as their
+// "cdi-device" attribute.
+func parseCDISpec(data []byte) ([]discoveredResource, error) {
+	var spec struct {
+		Kind    string `yaml:"kind"`
+		Devices []struct {
+			Name string `yaml:"name"`
+		} `yaml:"devices"`
+	}
+	// JSON specs are valid YAML.
+	if err := yaml.Unmarshal(data, &spec); err != nil {
+		return nil, err
+	}
+	if spec.Kind == "" {
+		return nil, fmt.Errorf("missing kind")
+	}
+	kind := invalidResourceKindChars.ReplaceAllString(spec.Kind, "_")
+	var devices []discoveredResource
+	for _, d := range spec.Devices {
+		if d.Name != "" {
+			devices = append(devices, discoveredResource{
+				kind:       kind,
+				value:      d.Name,
+				attributes: map[string]string{"cdi-device": spec.Kind + "=" + d.Name},
+			})
+		}
+	}
+	return devices, nil
+}
+
+// mergeGenericResources adds the discovered resources to the configured
+// generic resources, and returns the engine labels describing their
+// attributes. Discovered resources of a kind that is configured are ignored,
+// so that the configuration can override discovery.
+func mergeGenericResources(configured []swarm.GenericResource, discovered []discoveredResource) ([]swarm.GenericResource, []string) {
+	kinds := make(map[string]bool)
+	for _, r := range configured {
+		if r.NamedResourceSpec != nil {
+			kinds[r.NamedResourceSpec.Kind] = true
+		}
+		if r.DiscreteResourceSpec != nil {
+			kinds[r.DiscreteResourceSpec.Kind] = true
+		}
+	}
+
+	resources := configured
+	var labels []string
+	for _, r := range discovered {
+		if kinds[r.kind] {
+			continue
+		}
+		resources = append(resources, swarm.GenericResource{
+			NamedResourceSpec: &swarm.NamedGenericResource{Kind: r.kind, Value: r.value},
+		})
+		for k, v := range r.attributes {
+			labels = append(labels, genericResourceLabelPrefix+r.kind+"."+r.value+"."+k+"="+v)
+		}
+	}
+	return resources, labels
+}
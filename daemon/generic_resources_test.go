@@ -0,0 +1,71 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/google/go-cmp/cmp"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+var cmpDiscoveredResource = cmp.AllowUnexported(discoveredResource{})
+
+func TestParseNvidiaGPUs(t *testing.T) {
+	out := []byte("GPU-1a2b, Tesla T4, 15360\nGPU-3c4d, NVIDIA A100-SXM4-40GB, 40960\n\nmalformed\n")
+	gpus := parseNvidiaGPUs(out)
+	assert.Assert(t, is.Len(gpus, 2))
+	assert.Check(t, is.DeepEqual(gpus[0], discoveredResource{
+		kind:       nvidiaGPUResource,
+		value:      "GPU-1a2b",
+		attributes: map[string]string{"model": "Tesla T4", "memory": "15360MiB"},
+	}, cmpDiscoveredResource))
+	assert.Check(t, is.Equal(gpus[1].attributes["model"], "NVIDIA A100-SXM4-40GB"))
+}
+
+func TestParseCDISpec(t *testing.T) {
+	expected := []discoveredResource{
+		{kind: "vendor_com_device", value: "dev0", attributes: map[string]string{"cdi-device": "vendor.com/device=dev0"}},
+		{kind: "vendor_com_device", value: "dev1", attributes: map[string]string{"cdi-device": "vendor.com/device=dev1"}},
+	}
+	devices, err := parseCDISpec([]byte(`{"cdiVersion":"0.5.0","kind":"vendor.com/device","devices":[{"name":"dev0"},{"name":"dev1"}]}`))
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(devices, expected, cmpDiscoveredResource))
+
+	devices, err = parseCDISpec([]byte(`cdiVersion: "0.5.0"
+kind: vendor.com/device
+devices:
+  - name: dev0
+    containerEdits:
+      deviceNodes:
+        - path: /dev/dev0
+  - name: dev1
+`))
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(devices, expected, cmpDiscoveredResource))
+
+	_, err = parseCDISpec([]byte(`{"devices":[{"name":"dev0"}]}`))
+	assert.Check(t, is.ErrorContains(err, "missing kind"))
+}
+
+func TestMergeGenericResources(t *testing.T) {
+	configured := []swarm.GenericResource{
+		{DiscreteResourceSpec: &swarm.DiscreteGenericResource{Kind: "vendor_com_device", Value: 1}},
+	}
+	discovered := []discoveredResource{
+		{kind: nvidiaGPUResource, value: "GPU-1a2b", attributes: map[string]string{"model": "Tesla T4", "memory": "15360MiB"}},
+		{kind: "vendor_com_device", value: "dev0"},
+	}
+
+	resources, labels := mergeGenericResources(configured, discovered)
+	assert.Check(t, is.DeepEqual(resources, []swarm.GenericResource{
+		configured[0],
+		{NamedResourceSpec: &swarm.NamedGenericResource{Kind: nvidiaGPUResource, Value: "GPU-1a2b"}},
+	}))
+	sort.Strings(labels)
+	assert.Check(t, is.DeepEqual(labels, []string{
+		"com.docker.resource.NVIDIA-GPU.GPU-1a2b.memory=15360MiB",
+		"com.docker.resource.NVIDIA-GPU.GPU-1a2b.model=Tesla T4",
+	}))
+}
@@ -157,6 +157,13 @@ func Register(name string, initFunc InitFunc) error {
 	return nil
 }
 
+// IsRegistered returns whether a built-in driver is registered with the
+// given name.
+func IsRegistered(name string) bool {
+	_, exists := drivers[name]
+	return exists
+}
+
 // GetDriver initializes and returns the registered driver
 func GetDriver(name string, pg plugingetter.PluginGetter, config Options) (Driver, error) {
 	if initFunc, exists := drivers[name]; exists {
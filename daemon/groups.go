@@ -0,0 +1,391 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/backend"
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/daemon/network"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/libnetwork/datastore"
+	"github.com/docker/docker/runconfig"
+	"github.com/docker/go-connections/nat"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// ContainerGroupCreate creates a container group.
+func (daemon *Daemon) ContainerGroupCreate(req containertypes.GroupCreateRequest) (containertypes.Group, error) {
+	if isWindows {
+		return containertypes.Group{}, errdefs.NotImplemented(errors.New("container groups are not supported on Windows"))
+	}
+	if !daemon.idMapping.Empty() {
+		return containertypes.Group{}, errdefs.NotImplemented(errors.New("container groups are not supported with user namespaces"))
+	}
+	for _, name := range req.Networks {
+		n, err := daemon.FindNetwork(name)
+		if err != nil {
+			return containertypes.Group{}, err
+		}
+		if n.Info().Dynamic() || n.Info().Scope() == datastore.SwarmScope {
+			return containertypes.Group{}, errdefs.InvalidParameter(errors.Errorf("container groups cannot be connected to swarm-scoped network %s", name))
+		}
+		if containertypes.NetworkMode(n.Type()).IsHost() {
+			return containertypes.Group{}, errdefs.InvalidParameter(errors.New("container groups cannot be connected to the host network"))
+		}
+	}
+	if err := validatePortBindings(req.PortBindings); err != nil {
+		return containertypes.Group{}, errdefs.InvalidParameter(err)
+	}
+	return daemon.groups.Create(req.Name, req.GroupConfig)
+}
+
+// ContainerGroupInspect returns the container group with the given ID or
+// name.
+func (daemon *Daemon) ContainerGroupInspect(idOrName string) (containertypes.Group, error) {
+	g, err := daemon.groups.Get(idOrName)
+	if err != nil {
+		return containertypes.Group{}, err
+	}
+	daemon.fillGroupMembers(&g)
+	return g, nil
+}
+
+// ContainerGroupList returns the container groups.
+func (daemon *Daemon) ContainerGroupList() []containertypes.Group {
+	groups := daemon.groups.List()
+	for i := range groups {
+		daemon.fillGroupMembers(&groups[i])
+	}
+	return groups
+}
+
+// ContainerGroupRemove removes a container group. A group with members is
+// only removed if force is set, in which case its members are removed too.
+func (daemon *Daemon) ContainerGroupRemove(idOrName string, force bool) error {
+	g, err := daemon.groups.Get(idOrName)
+	if err != nil {
+		return err
+	}
+	if len(g.Members) > 0 {
+		if !force {
+			return errdefs.Conflict(errors.Errorf("container group %s has %d member(s), remove them first or use force", g.Name, len(g.Members)))
+		}
+		for _, m := range g.Members {
+			if err := daemon.ContainerRm(m.ID, &types.ContainerRmConfig{ForceRemove: true}); err != nil && !errdefs.IsNotFound(err) {
+				return errors.Wrapf(err, "error removing member %s of container group %s", m.ID, g.Name)
+			}
+		}
+	}
+	if err := daemon.releaseGroupSandbox(g.ID); err != nil {
+		return err
+	}
+	return daemon.groups.Remove(g.ID)
+}
+
+// ContainerGroupStart starts the members of a container group, in the order
+// they joined the group.
+func (daemon *Daemon) ContainerGroupStart(ctx context.Context, idOrName string) error {
+	g, err := daemon.groups.Get(idOrName)
+	if err != nil {
+		return err
+	}
+	for _, m := range g.Members {
+		if err := daemon.ContainerStart(ctx, m.ID, nil, "", ""); err != nil && !errdefs.IsNotModified(err) {
+			return errors.Wrapf(err, "error starting member %s of container group %s", m.ID, g.Name)
+		}
+	}
+	return nil
+}
+
+// ContainerGroupStop stops the members of a container group, in the reverse
+// order they joined the group, and releases the network sandbox of the group.
+func (daemon *Daemon) ContainerGroupStop(ctx context.Context, idOrName string, options containertypes.StopOptions) error {
+	g, err := daemon.groups.Get(idOrName)
+	if err != nil {
+		return err
+	}
+	for i := len(g.Members) - 1; i >= 0; i-- {
+		if err := daemon.ContainerStop(ctx, g.Members[i].ID, options); err != nil && !errdefs.IsNotModified(err) {
+			return errors.Wrapf(err, "error stopping member %s of container group %s", g.Members[i].ID, g.Name)
+		}
+	}
+	return daemon.releaseGroupSandbox(g.ID)
+}
+
+// ContainerGroupStats returns a single sample of the stats of each member of
+// a container group.
+func (daemon *Daemon) ContainerGroupStats(ctx context.Context, idOrName string, version string) ([]types.StatsJSON, error) {
+	g, err := daemon.groups.Get(idOrName)
+	if err != nil {
+		return nil, err
+	}
+	stats := make([]types.StatsJSON, 0, len(g.Members))
+	for _, m := range g.Members {
+		var buf bytes.Buffer
+		if err := daemon.ContainerStats(ctx, m.ID, &backend.ContainerStatsConfig{OneShot: true, OutStream: &buf, Version: version}); err != nil {
+			return nil, err
+		}
+		var s types.StatsJSON
+		if err := json.NewDecoder(&buf).Decode(&s); err != nil {
+			return nil, err
+		}
+		stats = append(stats, s)
+	}
+	return stats, nil
+}
+
+// fillGroupMembers sets the name and state of the members of a group.
+func (daemon *Daemon) fillGroupMembers(g *containertypes.Group) {
+	for i, m := range g.Members {
+		if c, err := daemon.GetContainer(m.ID); err == nil {
+			g.Members[i].Name = strings.TrimPrefix(c.Name, "/")
+			g.Members[i].State = c.State.StateString()
+		}
+	}
+}
+
+// groupRunning returns whether a member of a group is running.
+func (daemon *Daemon) groupRunning(g containertypes.Group) bool {
+	for _, m := range g.Members {
+		if c, err := daemon.GetContainer(m.ID); err == nil && c.IsRunning() {
+			return true
+		}
+	}
+	return false
+}
+
+// validateGroupMember validates the settings of a container joining a group
+// with the "group:<name|id>" network mode.
+func (daemon *Daemon) validateGroupMember(hostConfig *containertypes.HostConfig) error {
+	if hostConfig == nil || !hostConfig.NetworkMode.IsGroup() {
+		return nil
+	}
+	g, err := daemon.groups.Get(hostConfig.NetworkMode.ConnectedGroup())
+	if err != nil {
+		return err
+	}
+	ipcMode := hostConfig.IpcMode
+	if g.Config.ShareIPC && !(ipcMode.IsEmpty() || ipcMode.IsPrivate() || ipcMode.IsShareable()) {
+		return errdefs.InvalidParameter(errors.Errorf("conflicting options: IPC mode %s and container group %s sharing its IPC namespace", ipcMode, g.Name))
+	}
+	if g.Config.SharePID && hostConfig.PidMode != "" {
+		return errdefs.InvalidParameter(errors.Errorf("conflicting options: PID mode %s and container group %s sharing its PID namespace", hostConfig.PidMode, g.Name))
+	}
+	return nil
+}
+
+// adaptGroupMember replaces the name of the group of a container with its ID
+// in hostConfig. The IPC namespace of members of groups sharing it is made
+// shareable, as members join the IPC namespace of other members.
+func (daemon *Daemon) adaptGroupMember(hostConfig *containertypes.HostConfig) {
+	if !hostConfig.NetworkMode.IsGroup() {
+		return
+	}
+	g, err := daemon.groups.Get(hostConfig.NetworkMode.ConnectedGroup())
+	if err != nil {
+		return
+	}
+	hostConfig.NetworkMode = containertypes.NetworkMode("group:" + g.ID)
+	if g.Config.ShareIPC {
+		hostConfig.IpcMode = containertypes.IPCModeShareable
+	}
+}
+
+// joinGroup adds a newly created container to its group.
+func (daemon *Daemon) joinGroup(c *container.Container) error {
+	if !c.HostConfig.NetworkMode.IsGroup() {
+		return nil
+	}
+	return daemon.groups.AddMember(c.HostConfig.NetworkMode.ConnectedGroup(), c.ID)
+}
+
+// leaveGroup removes a container from its group.
+func (daemon *Daemon) leaveGroup(c *container.Container) {
+	if c.HostConfig == nil || !c.HostConfig.NetworkMode.IsGroup() {
+		return
+	}
+	if err := daemon.groups.RemoveMember(c.HostConfig.NetworkMode.ConnectedGroup(), c.ID); err != nil && !errdefs.IsNotFound(err) {
+		logrus.WithError(err).WithField("container", c.ID).Warn("failed to remove container from its group")
+	}
+}
+
+// isGroupSandbox returns whether a container stands for a group, to manage
+// the network sandbox of the group.
+func (daemon *Daemon) isGroupSandbox(c *container.Container) bool {
+	if daemon.groups == nil {
+		return false
+	}
+	g, err := daemon.groups.Get(c.ID)
+	return err == nil && g.ID == c.ID
+}
+
+// groupSandboxContainer returns the container which stands for a group to
+// manage the network sandbox of the group. It is not registered in the daemon,
+// and its files are in the directory of the group.
+func (daemon *Daemon) groupSandboxContainer(g containertypes.Group) *container.Container {
+	c := container.NewBaseContainer(g.ID, daemon.groups.Dir(g.ID))
+	c.Name = "/" + g.Name
+	c.Managed = true
+	c.Config = &containertypes.Config{
+		Hostname:     g.Config.Hostname,
+		ExposedPorts: make(nat.PortSet),
+	}
+	if c.Config.Hostname == "" {
+		c.Config.Hostname = g.Name
+	}
+	for p := range g.Config.PortBindings {
+		c.Config.ExposedPorts[p] = struct{}{}
+	}
+	c.HostConfig = &containertypes.HostConfig{
+		NetworkMode:  runconfig.DefaultDaemonNetworkMode(),
+		PortBindings: g.Config.PortBindings,
+	}
+	if len(g.Config.Networks) > 0 {
+		c.HostConfig.NetworkMode = containertypes.NetworkMode(g.Config.Networks[0])
+	}
+	c.NetworkSettings = &network.Settings{Networks: make(map[string]*network.EndpointSettings)}
+	c.HostnamePath = filepath.Join(c.Root, "hostname")
+	c.HostsPath = filepath.Join(c.Root, "hosts")
+	c.ResolvConfPath = filepath.Join(c.Root, "resolv.conf")
+	return c
+}
+
+// joinGroupNetwork makes a member of a group use the network sandbox of the
+// group, which is created if it does not exist.
+func (daemon *Daemon) joinGroupNetwork(c *container.Container) error {
+	g, err := daemon.allocateGroupSandbox(c.HostConfig.NetworkMode.ConnectedGroup())
+	if err != nil {
+		return err
+	}
+	holder := daemon.groupSandboxContainer(g)
+	c.HostnamePath = holder.HostnamePath
+	c.HostsPath = holder.HostsPath
+	c.ResolvConfPath = holder.ResolvConfPath
+	c.Config.Hostname = holder.Config.Hostname
+	return nil
+}
+
+// allocateGroupSandbox creates the network sandbox of a group if it does not
+// exist, and connects it to the networks of the group. It also starts the
+// holder of the IPC and PID namespaces shared by the members.
+func (daemon *Daemon) allocateGroupSandbox(idOrName string) (_ containertypes.Group, retErr error) {
+	daemon.groupsMu.Lock()
+	defer daemon.groupsMu.Unlock()
+
+	g, err := daemon.groups.Get(idOrName)
+	if err != nil {
+		return g, err
+	}
+	if g, err = daemon.startGroupHolder(g); err != nil {
+		return g, err
+	}
+	if daemon.netController == nil {
+		return g, nil
+	}
+	if g.SandboxID != "" {
+		if _, err := daemon.netController.SandboxByID(g.SandboxID); err == nil {
+			return g, nil
+		}
+	}
+
+	// Cleanup any stale sandbox left over due to ungraceful daemon shutdown
+	if err := daemon.netController.SandboxDestroy(g.ID); err != nil {
+		logrus.WithError(err).Errorf("failed to cleanup up stale network sandbox for container group %s", g.ID)
+	}
+
+	holder := daemon.groupSandboxContainer(g)
+	defer func() {
+		if retErr != nil {
+			if sb := daemon.getNetworkSandbox(holder); sb != nil {
+				if err := sb.Delete(); err != nil {
+					logrus.WithError(err).Warnf("failed to delete network sandbox of container group %s", g.ID)
+				}
+			}
+		}
+	}()
+
+	networks := g.Config.Networks
+	if len(networks) == 0 {
+		networks = []string{holder.HostConfig.NetworkMode.NetworkName()}
+	}
+	for _, n := range networks {
+		if err := daemon.connectToNetwork(holder, n, nil, true); err != nil {
+			return g, err
+		}
+	}
+
+	sb := daemon.getNetworkSandbox(holder)
+	if sb == nil {
+		// The group is not connected to any network, because the default
+		// bridge network is disabled.
+		sbOptions, err := daemon.buildSandboxOptions(holder)
+		if err != nil {
+			return g, err
+		}
+		if sb, err = daemon.netController.NewSandbox(holder.ID, sbOptions...); err != nil {
+			return g, err
+		}
+	}
+	if err := sb.EnableService(); err != nil {
+		return g, err
+	}
+	if err := holder.BuildHostnameFile(); err != nil {
+		return g, err
+	}
+	if err := daemon.groups.SetSandbox(g.ID, sb.ID(), sb.Key()); err != nil {
+		return g, err
+	}
+	return daemon.groups.Get(g.ID)
+}
+
+// releaseGroupSandbox deletes the network sandbox of a group, stops the
+// holder of its namespaces, and unmounts its /dev/shm.
+func (daemon *Daemon) releaseGroupSandbox(id string) error {
+	daemon.groupsMu.Lock()
+	defer daemon.groupsMu.Unlock()
+
+	g, err := daemon.groups.Get(id)
+	if err != nil {
+		return err
+	}
+	if err := daemon.unmountGroupShm(g.ID); err != nil {
+		logrus.WithError(err).Warnf("failed to unmount /dev/shm of container group %s", g.ID)
+	}
+	if err := daemon.stopGroupHolder(g); err != nil {
+		return err
+	}
+	if g.SandboxID == "" {
+		return nil
+	}
+	if daemon.netController != nil {
+		if sb, err := daemon.netController.SandboxByID(g.SandboxID); err == nil {
+			if err := sb.Delete(); err != nil {
+				return errors.Wrapf(err, "error deleting network sandbox of container group %s", g.Name)
+			}
+		}
+	}
+	return daemon.groups.SetSandbox(g.ID, "", "")
+}
+
+// restoreGroupSandboxes adds the network sandboxes of the groups with running
+// members to the sandboxes to restore.
+func (daemon *Daemon) restoreGroupSandboxes(activeSandboxes map[string]interface{}) {
+	daemon.restoreGroupHolders()
+	for _, g := range daemon.groups.List() {
+		if g.SandboxID == "" || !daemon.groupRunning(g) {
+			continue
+		}
+		options, err := daemon.buildSandboxOptions(daemon.groupSandboxContainer(g))
+		if err != nil {
+			logrus.WithError(err).WithField("group", g.ID).Warn("failed to build sandbox option to restore container group")
+		}
+		activeSandboxes[g.SandboxID] = options
+	}
+}
@@ -0,0 +1,227 @@
+// Package groups stores the container groups of the daemon.
+package groups // import "github.com/docker/docker/daemon/groups"
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/daemon/names"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/ioutils"
+	"github.com/docker/docker/pkg/stringid"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const configFile = "group.json"
+
+// Store persists groups as JSON files, in a directory per group which also
+// holds the network files of the group.
+type Store struct {
+	mu     sync.RWMutex
+	root   string
+	groups map[string]container.Group
+}
+
+// NewStore creates a store in the given directory, and loads the groups it
+// holds.
+func NewStore(root string) (*Store, error) {
+	if err := os.MkdirAll(root, 0o700); err != nil {
+		return nil, errors.Wrap(err, "error creating groups directory")
+	}
+	s := &Store{root: root, groups: make(map[string]container.Group)}
+	files, err := filepath.Glob(filepath.Join(root, "*", configFile))
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		b, err := os.ReadFile(f)
+		if err != nil {
+			return nil, errors.Wrap(err, "error loading group")
+		}
+		var g container.Group
+		if err := json.Unmarshal(b, &g); err != nil || g.ID != filepath.Base(filepath.Dir(f)) {
+			logrus.WithError(err).WithField("file", f).Warn("ignoring invalid container group")
+			continue
+		}
+		s.groups[g.ID] = g
+	}
+	return s, nil
+}
+
+// Create stores a new group. It fails if a group with the same name exists.
+func (s *Store) Create(name string, config container.GroupConfig) (container.Group, error) {
+	if !names.RestrictedNamePattern.MatchString(name) {
+		return container.Group{}, errdefs.InvalidParameter(errors.Errorf("invalid group name %q, only %s are allowed", name, names.RestrictedNameChars))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, g := range s.groups {
+		if g.Name == name {
+			return container.Group{}, errdefs.Conflict(errors.Errorf("group %s already exists", name))
+		}
+	}
+	g := container.Group{
+		ID:      stringid.GenerateRandomID(),
+		Name:    name,
+		Created: time.Now().UTC(),
+		Config:  config,
+	}
+	if err := os.MkdirAll(s.Dir(g.ID), 0o700); err != nil {
+		return container.Group{}, errors.Wrap(err, "error creating group directory")
+	}
+	if err := s.save(g); err != nil {
+		os.RemoveAll(s.Dir(g.ID))
+		return container.Group{}, err
+	}
+	return copyGroup(g), nil
+}
+
+// Get returns the group with the given ID, name, or ID prefix.
+func (s *Store) Get(idOrName string) (container.Group, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	g, err := s.get(idOrName)
+	if err != nil {
+		return container.Group{}, err
+	}
+	return copyGroup(g), nil
+}
+
+func (s *Store) get(idOrName string) (container.Group, error) {
+	if g, ok := s.groups[idOrName]; ok {
+		return g, nil
+	}
+	for _, g := range s.groups {
+		if g.Name == idOrName {
+			return g, nil
+		}
+	}
+	var (
+		match container.Group
+		found bool
+	)
+	for id, g := range s.groups {
+		if idOrName != "" && strings.HasPrefix(id, idOrName) {
+			if found {
+				return container.Group{}, errdefs.InvalidParameter(errors.Errorf("multiple groups found with provided prefix: %s", idOrName))
+			}
+			match, found = g, true
+		}
+	}
+	if !found {
+		return container.Group{}, errdefs.NotFound(errors.Errorf("no such group: %s", idOrName))
+	}
+	return match, nil
+}
+
+// List returns the groups, sorted by name.
+func (s *Store) List() []container.Group {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]container.Group, 0, len(s.groups))
+	for _, g := range s.groups {
+		out = append(out, copyGroup(g))
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// AddMember adds a container to a group.
+func (s *Store) AddMember(id, containerID string) error {
+	return s.update(id, func(g *container.Group) {
+		for _, m := range g.Members {
+			if m.ID == containerID {
+				return
+			}
+		}
+		g.Members = append(g.Members, container.GroupMember{ID: containerID})
+	})
+}
+
+// RemoveMember removes a container from a group.
+func (s *Store) RemoveMember(id, containerID string) error {
+	return s.update(id, func(g *container.Group) {
+		members := g.Members[:0]
+		for _, m := range g.Members {
+			if m.ID != containerID {
+				members = append(members, m)
+			}
+		}
+		g.Members = members
+	})
+}
+
+// SetSandbox records the network sandbox of a group. An empty sandboxID
+// records that the group has no sandbox.
+func (s *Store) SetSandbox(id, sandboxID, sandboxKey string) error {
+	return s.update(id, func(g *container.Group) {
+		g.SandboxID = sandboxID
+		g.SandboxKey = sandboxKey
+	})
+}
+
+// SetHolder records the pid of the process holding the namespaces of a group.
+// A zero pid records that the process is not running.
+func (s *Store) SetHolder(id string, pid int) error {
+	return s.update(id, func(g *container.Group) {
+		g.HolderPID = pid
+	})
+}
+
+// Remove removes the group with the given ID, along with its directory.
+func (s *Store) Remove(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.groups[id]; !ok {
+		return errdefs.NotFound(errors.Errorf("no such group: %s", id))
+	}
+	if err := os.RemoveAll(s.Dir(id)); err != nil {
+		return errors.Wrap(err, "error removing group")
+	}
+	delete(s.groups, id)
+	return nil
+}
+
+// Dir returns the directory of the group with the given ID.
+func (s *Store) Dir(id string) string {
+	return filepath.Join(s.root, id)
+}
+
+func (s *Store) update(id string, fn func(*container.Group)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	g, ok := s.groups[id]
+	if !ok {
+		return errdefs.NotFound(errors.Errorf("no such group: %s", id))
+	}
+	g = copyGroup(g)
+	fn(&g)
+	return s.save(g)
+}
+
+func (s *Store) save(g container.Group) error {
+	b, err := json.Marshal(g)
+	if err != nil {
+		return err
+	}
+	if err := ioutils.AtomicWriteFile(filepath.Join(s.Dir(g.ID), configFile), b, 0o600); err != nil {
+		return errors.Wrap(err, "error saving group")
+	}
+	s.groups[g.ID] = g
+	return nil
+}
+
+// copyGroup returns a copy of a group which does not share its members with
+// the stored group.
+func copyGroup(g container.Group) container.Group {
+	g.Members = append([]container.GroupMember(nil), g.Members...)
+	return g
+}
@@ -0,0 +1,67 @@
+package groups // import "github.com/docker/docker/daemon/groups"
+
+import (
+	"os"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/errdefs"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestStore(t *testing.T) {
+	root := t.TempDir()
+	s, err := NewStore(root)
+	assert.NilError(t, err)
+
+	g, err := s.Create("web", container.GroupConfig{Networks: []string{"front"}, ShareIPC: true})
+	assert.NilError(t, err)
+	_, err = s.Create("web", container.GroupConfig{})
+	assert.Check(t, errdefs.IsConflict(err))
+	_, err = s.Create("../web", container.GroupConfig{})
+	assert.Check(t, errdefs.IsInvalidParameter(err))
+	_, err = s.Create("db", container.GroupConfig{})
+	assert.NilError(t, err)
+
+	assert.NilError(t, s.AddMember(g.ID, "c1"))
+	assert.NilError(t, s.AddMember(g.ID, "c2"))
+	assert.NilError(t, s.AddMember(g.ID, "c1"))
+	assert.NilError(t, s.SetSandbox(g.ID, "sb", "/var/run/docker/netns/sb"))
+	assert.NilError(t, s.SetHolder(g.ID, 42))
+	assert.Check(t, errdefs.IsNotFound(s.AddMember("unknown", "c1")))
+
+	// Groups are persisted.
+	s, err = NewStore(root)
+	assert.NilError(t, err)
+	for _, input := range []string{g.ID, "web", g.ID[:5]} {
+		got, err := s.Get(input)
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(got.ID, g.ID))
+		assert.Check(t, got.Config.ShareIPC)
+		assert.Check(t, is.DeepEqual(got.Config.Networks, []string{"front"}))
+		assert.Check(t, is.DeepEqual(got.Members, []container.GroupMember{{ID: "c1"}, {ID: "c2"}}))
+		assert.Check(t, is.Equal(got.SandboxKey, "/var/run/docker/netns/sb"))
+		assert.Check(t, is.Equal(got.HolderPID, 42))
+	}
+	_, err = s.Get("unknown")
+	assert.Check(t, errdefs.IsNotFound(err))
+
+	list := s.List()
+	assert.Assert(t, is.Len(list, 2))
+	assert.Check(t, is.Equal(list[0].Name, "db"))
+
+	// Groups returned by the store do not share their members with it.
+	list[1].Members[0].ID = "changed"
+	assert.NilError(t, s.RemoveMember(g.ID, "c1"))
+	got, err := s.Get(g.ID)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(got.Members, []container.GroupMember{{ID: "c2"}}))
+
+	assert.NilError(t, s.Remove(g.ID))
+	_, err = s.Get("web")
+	assert.Check(t, errdefs.IsNotFound(err))
+	_, err = os.Stat(s.Dir(g.ID))
+	assert.Check(t, os.IsNotExist(err))
+	assert.Check(t, errdefs.IsNotFound(s.Remove(g.ID)))
+}
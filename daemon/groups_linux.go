@@ -0,0 +1,136 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/reexec"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+)
+
+// groupHolderReexec is the name of the process holding the IPC and PID
+// namespaces shared by the members of a container group.
+const groupHolderReexec = "docker-group-holder"
+
+func init() {
+	reexec.Register(groupHolderReexec, groupHolderMain)
+}
+
+// groupHolderMain is the init process of the namespaces of a container group.
+// It reaps the processes of the members reparented to it, until it is
+// terminated.
+func groupHolderMain() {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, unix.SIGCHLD, unix.SIGTERM, unix.SIGINT)
+	for sig := range sigs {
+		if sig != unix.SIGCHLD {
+			os.Exit(0)
+		}
+		for {
+			var ws unix.WaitStatus
+			if pid, err := unix.Wait4(-1, &ws, unix.WNOHANG, nil); pid <= 0 || err != nil {
+				break
+			}
+		}
+	}
+}
+
+// startGroupHolder starts the process holding the IPC and PID namespaces
+// shared by the members of a group, if the group shares any and the process
+// is not running. The namespaces outlive the members, so that members can be
+// restarted, or exit, without affecting the others. It must be called with
+// groupsMu held.
+func (daemon *Daemon) startGroupHolder(g containertypes.Group) (containertypes.Group, error) {
+	var flags uintptr
+	if g.Config.ShareIPC {
+		flags |= unix.CLONE_NEWIPC
+	}
+	if g.Config.SharePID {
+		flags |= unix.CLONE_NEWPID
+	}
+	if flags == 0 || groupHolderRunning(g) {
+		return g, nil
+	}
+
+	cmd := reexec.Command(groupHolderReexec, g.ID)
+	// The holder is not bound to the daemon, so that the members keep their
+	// namespaces when the daemon is restarted with live-restore.
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true, Cloneflags: flags}
+	if err := cmd.Start(); err != nil {
+		return g, errors.Wrapf(err, "error starting the namespace holder of container group %s", g.Name)
+	}
+	go func() {
+		_ = cmd.Wait()
+	}()
+	if err := daemon.groups.SetHolder(g.ID, cmd.Process.Pid); err != nil {
+		_ = cmd.Process.Kill()
+		return g, err
+	}
+	return daemon.groups.Get(g.ID)
+}
+
+// stopGroupHolder kills the process holding the namespaces of a group. It
+// must be called with groupsMu held.
+func (daemon *Daemon) stopGroupHolder(g containertypes.Group) error {
+	if g.HolderPID == 0 {
+		return nil
+	}
+	if groupHolderRunning(g) {
+		if err := unix.Kill(g.HolderPID, unix.SIGKILL); err != nil && err != unix.ESRCH {
+			return errors.Wrapf(err, "error stopping the namespace holder of container group %s", g.Name)
+		}
+	}
+	return daemon.groups.SetHolder(g.ID, 0)
+}
+
+// groupHolderRunning returns whether the holder of the namespaces of a group
+// is running, and was not replaced by another process with the same pid.
+func groupHolderRunning(g containertypes.Group) bool {
+	if g.HolderPID == 0 {
+		return false
+	}
+	cmdline, err := os.ReadFile(fmt.Sprintf("/proc/%d/cmdline", g.HolderPID))
+	return err == nil && string(cmdline) == groupHolderReexec+"\x00"+g.ID+"\x00"
+}
+
+// groupNamespacePath returns the path of the namespace of the given type
+// ("ipc" or "pid") shared by the members of the group of a container, or an
+// empty path if the container is not a member of a group sharing it.
+func (daemon *Daemon) groupNamespacePath(c *container.Container, nsType string) (string, error) {
+	if !c.HostConfig.NetworkMode.IsGroup() {
+		return "", nil
+	}
+	g, err := daemon.groups.Get(c.HostConfig.NetworkMode.ConnectedGroup())
+	if err != nil {
+		return "", err
+	}
+	if (nsType == "ipc" && !g.Config.ShareIPC) || (nsType == "pid" && !g.Config.SharePID) {
+		return "", nil
+	}
+	if !groupHolderRunning(g) {
+		return "", errdefs.Conflict(errors.Errorf("the namespace holder of container group %s is not running", g.Name))
+	}
+	return fmt.Sprintf("/proc/%d/ns/%s", g.HolderPID, nsType), nil
+}
+
+// restoreGroupHolders stops the namespace holders of the groups without
+// running members, which were left over by a shutdown of the daemon.
+func (daemon *Daemon) restoreGroupHolders() {
+	daemon.groupsMu.Lock()
+	defer daemon.groupsMu.Unlock()
+	for _, g := range daemon.groups.List() {
+		if g.HolderPID == 0 || daemon.groupRunning(g) {
+			continue
+		}
+		if err := daemon.stopGroupHolder(g); err != nil {
+			logrus.WithError(err).WithField("group", g.ID).Warn("failed to stop the namespace holder of container group")
+		}
+	}
+}
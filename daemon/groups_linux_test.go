@@ -0,0 +1,57 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/daemon/groups"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/poll"
+	"gotest.tools/v3/skip"
+)
+
+func TestGroupHolder(t *testing.T) {
+	skip.If(t, os.Getuid() != 0, "skipping test that requires root")
+
+	store, err := groups.NewStore(t.TempDir())
+	assert.NilError(t, err)
+	d := &Daemon{groups: store}
+	g, err := store.Create("web", containertypes.GroupConfig{ShareIPC: true, SharePID: true})
+	assert.NilError(t, err)
+
+	g, err = d.startGroupHolder(g)
+	assert.NilError(t, err)
+	assert.Assert(t, g.HolderPID != 0)
+	assert.Check(t, groupHolderRunning(g))
+
+	// The holder is only started once.
+	again, err := d.startGroupHolder(g)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(again.HolderPID, g.HolderPID))
+
+	c := &containertypes.HostConfig{NetworkMode: containertypes.NetworkMode("group:" + g.ID)}
+	for _, nsType := range []string{"ipc", "pid"} {
+		p, err := d.groupNamespacePath(&container.Container{HostConfig: c}, nsType)
+		assert.NilError(t, err)
+		ns, err := os.Readlink(p)
+		assert.NilError(t, err)
+		self, err := os.Readlink("/proc/self/ns/" + nsType)
+		assert.NilError(t, err)
+		assert.Check(t, ns != self, "%s namespace is not unshared", nsType)
+	}
+
+	assert.NilError(t, d.stopGroupHolder(g))
+	poll.WaitOn(t, func(poll.LogT) poll.Result {
+		if groupHolderRunning(g) {
+			return poll.Continue("holder is running")
+		}
+		return poll.Success()
+	}, poll.WithTimeout(10*time.Second))
+	g, err = store.Get(g.ID)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(g.HolderPID, 0))
+}
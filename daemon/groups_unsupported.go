@@ -0,0 +1,16 @@
+//go:build !linux
+// +build !linux
+
+package daemon // import "github.com/docker/docker/daemon"
+
+import containertypes "github.com/docker/docker/api/types/container"
+
+func (daemon *Daemon) startGroupHolder(g containertypes.Group) (containertypes.Group, error) {
+	return g, nil
+}
+
+func (daemon *Daemon) stopGroupHolder(g containertypes.Group) error {
+	return nil
+}
+
+func (daemon *Daemon) restoreGroupHolders() {}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"runtime"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -13,6 +14,7 @@ import (
 	containertypes "github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/strslice"
 	"github.com/docker/docker/container"
+	"github.com/moby/locker"
 	"github.com/sirupsen/logrus"
 )
 
@@ -20,6 +22,10 @@ const (
 	// Longest healthcheck probe output message to store. Longer messages will be truncated.
 	maxOutputLen = 4096
 
+	// Longest healthcheck probe output to include in health_status events.
+	// Longer messages will be truncated.
+	maxEventOutputLen = 256
+
 	// Default interval between probe runs (from the end of the first to the start of the second).
 	// Also the time before the first probe.
 	defaultProbeInterval = 30 * time.Second
@@ -196,6 +202,7 @@ func handleProbeResult(d *Daemon, c *container.Container, result *types.Healthch
 
 	h := c.State.Health
 	oldStatus := h.Status()
+	oldStreak := h.FailingStreak
 
 	if len(h.Log) >= maxLogEntries {
 		h.Log = append(h.Log[len(h.Log)+1-maxLogEntries:], result)
@@ -239,10 +246,102 @@ func handleProbeResult(d *Daemon, c *container.Container, result *types.Healthch
 		logrus.Errorf("Error replicating health state for container %s: %v", c.ID, err)
 	}
 
+	if c.Managed {
+		if change, active := serviceBindingChange(oldStatus, oldStreak, h.FailingStreak); change {
+			d.serviceBindings.request(c.ID, active, d.updateServiceBinding)
+		}
+	}
+
 	current := h.Status()
 	if oldStatus != current {
-		d.LogContainerEvent(c, "health_status: "+current)
+		if d.netController != nil {
+			if sb := d.getNetworkSandbox(c); sb != nil {
+				sb.SetHealthy(current == types.Healthy)
+			}
+		}
+		d.LogContainerEventWithAttributes(c, "health_status: "+current, map[string]string{
+			"healthStatus":         current,
+			"previousHealthStatus": oldStatus,
+			"failingStreak":        strconv.Itoa(h.FailingStreak),
+			"exitCode":             strconv.Itoa(result.ExitCode),
+			"output":               truncateProbeOutput(result.Output),
+		})
+	}
+}
+
+// serviceBindingChange returns whether the load balancer rotation of a swarm
+// task has to change after a probe, and whether the task should be in it.
+// Tasks are taken out of the rotation as soon as a probe fails, rather than
+// only once they are unhealthy and shut down, and put back in once probes
+// succeed again. Tasks that were not healthy yet have not been put in the
+// rotation by the executor, and are left alone.
+func serviceBindingChange(oldStatus string, oldStreak, streak int) (change, active bool) {
+	if oldStatus != types.Healthy {
+		return false, false
 	}
+	wasFailing, failing := oldStreak > 0, streak > 0
+	if wasFailing == failing {
+		return false, false
+	}
+	return true, !failing
+}
+
+// serviceBindingUpdates serializes the updates of the load balancer rotation
+// of containers after probes. Updates run in the background, one at a time
+// per container, and apply the state last requested for the container, so
+// that an earlier update cannot override a later one.
+type serviceBindingUpdates struct {
+	mu      sync.Mutex
+	pending map[string]bool
+	locker  locker.Locker
+}
+
+// request schedules a call of apply with the state requested for the
+// container with the given ID.
+func (u *serviceBindingUpdates) request(id string, active bool, apply func(id string, active bool)) {
+	u.mu.Lock()
+	if u.pending == nil {
+		u.pending = make(map[string]bool)
+	}
+	u.pending[id] = active
+	u.mu.Unlock()
+
+	go func() {
+		u.locker.Lock(id)
+		defer u.locker.Unlock(id)
+
+		u.mu.Lock()
+		active, ok := u.pending[id]
+		delete(u.pending, id)
+		u.mu.Unlock()
+		if ok {
+			apply(id, active)
+		}
+	}()
+}
+
+// updateServiceBinding puts a container in, or takes it out of, the load
+// balancer rotation of its service.
+func (d *Daemon) updateServiceBinding(id string, active bool) {
+	var err error
+	if active {
+		err = d.ActivateContainerServiceBinding(id)
+	} else {
+		err = d.DeactivateContainerServiceBinding(id)
+	}
+	if err != nil {
+		logrus.WithError(err).WithField("container", id).Warn("failed to update service binding after health check")
+	}
+}
+
+// truncateProbeOutput truncates the output of a probe to maxEventOutputLen
+// bytes, so that it can be included in events.
+func truncateProbeOutput(out string) string {
+	out = strings.TrimSpace(out)
+	if len(out) <= maxEventOutputLen {
+		return out
+	}
+	return strings.ToValidUTF8(out[:maxEventOutputLen], "")
 }
 
 // Run the container's monitoring thread until notified via "stop".
@@ -347,6 +446,12 @@ func (daemon *Daemon) updateHealthMonitor(c *container.Container) {
 func (daemon *Daemon) initHealthMonitor(c *container.Container) {
 	// If no healthcheck is setup then don't init the monitor
 	if getProbe(c) == nil {
+		// Containers notifying their readiness are healthy once ready.
+		if c.HostConfig != nil && c.HostConfig.NotifyReady {
+			h := &container.Health{}
+			h.SetStatus(types.Starting)
+			c.State.Health = h
+		}
 		return
 	}
 
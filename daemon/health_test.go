@@ -1,6 +1,7 @@
 package daemon // import "github.com/docker/docker/daemon"
 
 import (
+	"sync"
 	"testing"
 	"time"
 
@@ -96,8 +97,33 @@ func TestHealthStates(t *testing.T) {
 
 	// starting -> failed -> success -> failed
 
-	handleResult(c.State.StartedAt.Add(1*time.Second), 1)
-	expect("health_status: unhealthy")
+	handleProbeResult(daemon, c, &types.HealthcheckResult{
+		Start:    c.State.StartedAt.Add(1 * time.Second),
+		End:      c.State.StartedAt.Add(1 * time.Second),
+		ExitCode: 1,
+		Output:   "connection refused\n",
+	}, nil)
+	select {
+	case event := <-l:
+		ev := event.(eventtypes.Message)
+		if ev.Status != "health_status: unhealthy" {
+			t.Errorf("Expecting event %#v, but got %#v\n", "health_status: unhealthy", ev.Status)
+		}
+		expectedAttrs := map[string]string{
+			"healthStatus":         types.Unhealthy,
+			"previousHealthStatus": types.Starting,
+			"failingStreak":        "1",
+			"exitCode":             "1",
+			"output":               "connection refused",
+		}
+		for k, v := range expectedAttrs {
+			if ev.Actor.Attributes[k] != v {
+				t.Errorf("Expecting attribute %s=%q, but got %q\n", k, v, ev.Actor.Attributes[k])
+			}
+		}
+	case <-time.After(1 * time.Second):
+		t.Errorf("Expecting event %#v, but got nothing\n", "health_status: unhealthy")
+	}
 
 	handleResult(c.State.StartedAt.Add(2*time.Second), 0)
 	expect("health_status: healthy")
@@ -153,3 +179,62 @@ func TestHealthStates(t *testing.T) {
 		t.Errorf("Expecting FailingStreak=0, but got %d\n", c.State.Health.FailingStreak)
 	}
 }
+
+func TestServiceBindingChange(t *testing.T) {
+	for _, tc := range []struct {
+		oldStatus         string
+		oldStreak, streak int
+		change, active    bool
+	}{
+		{oldStatus: types.Starting, oldStreak: 0, streak: 1},
+		{oldStatus: types.Starting, oldStreak: 2, streak: 0},
+		{oldStatus: types.Healthy, oldStreak: 0, streak: 0},
+		{oldStatus: types.Healthy, oldStreak: 0, streak: 1, change: true, active: false},
+		{oldStatus: types.Healthy, oldStreak: 1, streak: 2},
+		{oldStatus: types.Healthy, oldStreak: 2, streak: 0, change: true, active: true},
+		{oldStatus: types.Unhealthy, oldStreak: 3, streak: 0},
+	} {
+		change, active := serviceBindingChange(tc.oldStatus, tc.oldStreak, tc.streak)
+		if change != tc.change || active != tc.active {
+			t.Errorf("serviceBindingChange(%q, %d, %d) = %v, %v; expected %v, %v", tc.oldStatus, tc.oldStreak, tc.streak, change, active, tc.change, tc.active)
+		}
+	}
+}
+
+func TestServiceBindingUpdates(t *testing.T) {
+	var (
+		u       serviceBindingUpdates
+		mu      sync.Mutex
+		applied []bool
+		wg      sync.WaitGroup
+	)
+	started := make(chan struct{}, 1)
+	block := make(chan struct{})
+	apply := func(id string, active bool) {
+		started <- struct{}{}
+		<-block
+		mu.Lock()
+		applied = append(applied, active)
+		mu.Unlock()
+		wg.Done()
+	}
+
+	// The next updates wait for the first one, and are coalesced into the
+	// state requested last.
+	wg.Add(2)
+	u.request("id", false, apply)
+	<-started
+	for _, active := range []bool{true, false, true} {
+		u.request("id", active, apply)
+	}
+	close(block)
+	wg.Wait()
+
+	// Let the coalesced updates find nothing to apply.
+	time.Sleep(10 * time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	if len(applied) != 2 || applied[0] || !applied[1] {
+		t.Fatalf("expected updates [false true], got %v", applied)
+	}
+}
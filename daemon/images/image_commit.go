@@ -22,6 +22,10 @@ func (i *ImageService) CommitImage(ctx context.Context, c backend.CommitConfig)
 	if err != nil {
 		return "", err
 	}
+	// Stop reading the container's changes when ctx is cancelled, for
+	// example because the client disconnected, instead of registering a
+	// layer that is no longer wanted.
+	rwTar = ioutils.NewCancelReadCloser(ctx, rwTar)
 	defer func() {
 		if rwTar != nil {
 			rwTar.Close()
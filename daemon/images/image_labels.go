@@ -0,0 +1,46 @@
+package images // import "github.com/docker/docker/daemon/images"
+
+import (
+	"context"
+
+	"github.com/docker/distribution/reference"
+	"github.com/docker/docker/api/types"
+	imagetypes "github.com/docker/docker/api/types/image"
+)
+
+// ImageUpdateLabels adds and removes labels of an image, and returns the ID
+// of the image with the new labels, and the new labels. The labels are part
+// of the configuration of the image, so the image with the new labels is a
+// new image, which the tags of the image are moved to. References by digest
+// keep pointing to the original image, as its manifest doesn't match the new
+// image.
+func (i *ImageService) ImageUpdateLabels(ctx context.Context, refOrID string, update types.LabelsUpdate) (string, map[string]string, error) {
+	i.labelsLock.Lock()
+	defer i.labelsLock.Unlock()
+
+	img, err := i.GetImage(ctx, refOrID, imagetypes.GetImageOpts{})
+	if err != nil {
+		return "", nil, err
+	}
+	var current map[string]string
+	if img.Config != nil {
+		current = img.Config.Labels
+	}
+	labels := update.Apply(current)
+	id, err := i.imageStore.SetLabels(img.ID(), labels)
+	if err != nil {
+		return "", nil, err
+	}
+	if id != img.ID() {
+		for _, ref := range i.referenceStore.References(img.ID().Digest()) {
+			if _, ok := ref.(reference.NamedTagged); !ok {
+				continue
+			}
+			if err := i.TagImageWithReference(id, ref); err != nil {
+				return "", nil, err
+			}
+		}
+	}
+	i.LogImageEvent(id.String(), id.String(), "update")
+	return id.String(), labels, nil
+}
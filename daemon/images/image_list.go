@@ -10,6 +10,7 @@ import (
 	"github.com/docker/distribution/reference"
 	"github.com/docker/docker/api/types"
 	imagetypes "github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/pagination"
 	"github.com/docker/docker/container"
 	"github.com/docker/docker/image"
 	"github.com/docker/docker/layer"
@@ -24,13 +25,35 @@ var acceptedImageFilterTags = map[string]bool{
 	"reference": true,
 }
 
-// byCreated is a temporary type used to sort a list of images by creation
-// time.
-type byCreated []*types.ImageSummary
+// byCreatedDescending is a temporary type used to sort a list of images by
+// creation time, newest first, and then by ID.
+type byCreatedDescending []*types.ImageSummary
 
-func (r byCreated) Len() int           { return len(r) }
-func (r byCreated) Swap(i, j int)      { r[i], r[j] = r[j], r[i] }
-func (r byCreated) Less(i, j int) bool { return r[i].Created < r[j].Created }
+func (r byCreatedDescending) Len() int      { return len(r) }
+func (r byCreatedDescending) Swap(i, j int) { r[i], r[j] = r[j], r[i] }
+func (r byCreatedDescending) Less(i, j int) bool {
+	if r[i].Created != r[j].Created {
+		return r[i].Created > r[j].Created
+	}
+	return r[i].ID < r[j].ID
+}
+
+// PageSummaries sorts image summaries by creation time, newest first, and
+// returns the summaries following the cursor, if any, up to limit summaries
+// if limit is positive.
+func PageSummaries(summaries []*types.ImageSummary, limit int, cursor *pagination.Cursor) []*types.ImageSummary {
+	sort.Sort(byCreatedDescending(summaries))
+	if cursor != nil {
+		start := sort.Search(len(summaries), func(n int) bool {
+			return cursor.After(summaries[n].Created, summaries[n].ID)
+		})
+		summaries = summaries[start:]
+	}
+	if limit > 0 && len(summaries) > limit {
+		summaries = summaries[:limit]
+	}
+	return summaries
+}
 
 // Images returns a filtered list of images.
 func (i *ImageService) Images(ctx context.Context, opts types.ImageListOptions) ([]*types.ImageSummary, error) {
@@ -38,6 +61,15 @@ func (i *ImageService) Images(ctx context.Context, opts types.ImageListOptions)
 		return nil, err
 	}
 
+	var cursor *pagination.Cursor
+	if opts.Cursor != "" {
+		c, err := pagination.Parse(opts.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		cursor = &c
+	}
+
 	var danglingOnly bool
 	if opts.Filters.Contains("dangling") {
 		if opts.Filters.ExactMatch("dangling", "true") {
@@ -257,9 +289,7 @@ func (i *ImageService) Images(ctx context.Context, opts types.ImageListOptions)
 		}
 	}
 
-	sort.Sort(sort.Reverse(byCreated(summaries)))
-
-	return summaries, nil
+	return PageSummaries(summaries, opts.Limit, cursor), nil
 }
 
 func newImageSummary(image *image.Image, size int64) *types.ImageSummary {
@@ -3,6 +3,8 @@ package images
 import (
 	"testing"
 
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/pagination"
 	specs "github.com/opencontainers/image-spec/specs-go/v1"
 	"gotest.tools/v3/assert"
 )
@@ -31,3 +33,25 @@ func TestOnlyPlatformWithFallback(t *testing.T) {
 		Architecture: "amd64",
 	}))
 }
+
+func TestPageSummaries(t *testing.T) {
+	summaries := []*types.ImageSummary{
+		{ID: "b", Created: 100},
+		{ID: "d", Created: 50},
+		{ID: "a", Created: 100},
+		{ID: "c", Created: 200},
+	}
+	ids := func(summaries []*types.ImageSummary) (ids []string) {
+		for _, s := range summaries {
+			ids = append(ids, s.ID)
+		}
+		return ids
+	}
+
+	assert.DeepEqual(t, ids(PageSummaries(summaries, 0, nil)), []string{"c", "a", "b", "d"})
+	assert.DeepEqual(t, ids(PageSummaries(summaries, 2, nil)), []string{"c", "a"})
+	assert.DeepEqual(t, ids(PageSummaries(summaries, 2, &pagination.Cursor{Created: 100, ID: "a"})), []string{"b", "d"})
+	// the cursor does not need to refer to an image of the list
+	assert.DeepEqual(t, ids(PageSummaries(summaries, 0, &pagination.Cursor{Created: 150, ID: "x"})), []string{"a", "b", "d"})
+	assert.Check(t, len(PageSummaries(summaries, 2, &pagination.Cursor{Created: 50, ID: "d"})) == 0)
+}
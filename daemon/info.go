@@ -38,7 +38,7 @@ func (daemon *Daemon) SystemInfo() *types.Info {
 		BridgeNfIP6tables:  !sysInfo.BridgeNFCallIP6TablesDisabled,
 		Name:               hostName(),
 		SystemTime:         time.Now().Format(time.RFC3339Nano),
-		LoggingDriver:      daemon.defaultLogConfig.Type,
+		LoggingDriver:      daemon.getDefaultLogConfig().Type,
 		KernelVersion:      kernelVersion(),
 		OperatingSystem:    operatingSystem(),
 		OSVersion:          osVersion(),
@@ -50,7 +50,7 @@ func (daemon *Daemon) SystemInfo() *types.Info {
 		MemTotal:           memInfo().MemTotal,
 		GenericResources:   daemon.genericResources,
 		DockerRootDir:      daemon.configStore.Root,
-		Labels:             daemon.configStore.Labels,
+		Labels:             daemon.engineLabels(),
 		ExperimentalBuild:  daemon.configStore.Experimental,
 		ServerVersion:      dockerversion.Version,
 		HTTPProxy:          config.MaskCredentials(getConfigOrEnv(daemon.configStore.HTTPProxy, "HTTP_PROXY", "http_proxy")),
@@ -181,6 +181,12 @@ func (daemon *Daemon) fillContainerStates(v *types.Info) {
 	v.ContainersStopped = cStopped
 }
 
+// ActiveContainers returns the number of running and paused containers.
+func (daemon *Daemon) ActiveContainers() int {
+	cRunning, cPaused, _ := stateCtr.get()
+	return cRunning + cPaused
+}
+
 // fillDebugInfo sets the current debugging state of the daemon, and additional
 // debugging information, such as the number of Go-routines, and file descriptors.
 //
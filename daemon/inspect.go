@@ -3,6 +3,7 @@ package daemon // import "github.com/docker/docker/daemon"
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/docker/docker/api/types"
@@ -218,6 +219,45 @@ func (daemon *Daemon) ContainerExecInspect(id string) (*backend.ExecInspect, err
 		return nil, errExecNotFound(id)
 	}
 
+	return daemon.execInspect(e), nil
+}
+
+// ContainerExecList returns the exec instances of a container, ordered by the
+// time they were started at. Exec instances which exited are listed until
+// they are cleaned up.
+func (daemon *Daemon) ContainerExecList(name string) ([]*backend.ExecInspect, error) {
+	ctr, err := daemon.GetContainer(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var ecs []*container.ExecConfig
+	startedAt := make(map[string]time.Time)
+	for _, e := range daemon.execCommands.Commands() {
+		if e.Container.ID != ctr.ID {
+			continue
+		}
+		e.Lock()
+		startedAt[e.ID] = e.StartedAt
+		e.Unlock()
+		ecs = append(ecs, e)
+	}
+	sort.Slice(ecs, func(i, j int) bool {
+		ti, tj := startedAt[ecs[i].ID], startedAt[ecs[j].ID]
+		if !ti.Equal(tj) {
+			return ti.Before(tj)
+		}
+		return ecs[i].ID < ecs[j].ID
+	})
+
+	execs := make([]*backend.ExecInspect, 0, len(ecs))
+	for _, e := range ecs {
+		execs = append(execs, daemon.execInspect(e))
+	}
+	return execs, nil
+}
+
+func (daemon *Daemon) execInspect(e *container.ExecConfig) *backend.ExecInspect {
 	e.Lock()
 	defer e.Unlock()
 	pc := inspectExecProcessConfig(e)
@@ -225,6 +265,14 @@ func (daemon *Daemon) ContainerExecInspect(id string) (*backend.ExecInspect, err
 	if e.Process != nil {
 		pid = int(e.Process.Pid())
 	}
+	var startedAt string
+	if !e.StartedAt.IsZero() {
+		startedAt = e.StartedAt.Format(time.RFC3339Nano)
+	}
+	var usage *types.ExecStats
+	if e.Running {
+		usage = execUsage(e.CgroupPath)
+	}
 
 	return &backend.ExecInspect{
 		ID:            e.ID,
@@ -238,7 +286,10 @@ func (daemon *Daemon) ContainerExecInspect(id string) (*backend.ExecInspect, err
 		ContainerID:   e.Container.ID,
 		DetachKeys:    e.DetachKeys,
 		Pid:           pid,
-	}, nil
+		StartedAt:     startedAt,
+		Client:        e.Client,
+		Usage:         usage,
+	}
 }
 
 func (daemon *Daemon) getBackwardsCompatibleNetworkSettings(settings *network.Settings) *v1p20.NetworkSettings {
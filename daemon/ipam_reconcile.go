@@ -0,0 +1,71 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"time"
+
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/daemon/config"
+	"github.com/sirupsen/logrus"
+)
+
+// ReconcileIPAM reports the addresses and pools allocated by the IPAM drivers
+// which no network or endpoint owns. If release is set, the leaks which were
+// already reported by the previous reconciliation are released.
+func (daemon *Daemon) ReconcileIPAM(release bool) (*network.IPAMReconcileReport, error) {
+	leaks, err := daemon.netController.ReconcileIPAM(release)
+	if err != nil {
+		return nil, err
+	}
+	report := &network.IPAMReconcileReport{Leaks: []network.IPAMLeak{}}
+	for _, l := range leaks {
+		leak := network.IPAMLeak{Driver: l.Driver, PoolID: l.PoolID, Released: l.Released}
+		if l.Address != nil {
+			leak.Address = l.Address.String()
+		}
+		report.Leaks = append(report.Leaks, leak)
+	}
+	return report, nil
+}
+
+// startIPAMReconcile starts reconciling the IPAM allocations periodically, if
+// an interval is configured.
+func (daemon *Daemon) startIPAMReconcile(conf config.IPAMReconcileConfig) {
+	interval := conf.GetInterval()
+	if interval <= 0 {
+		return
+	}
+	stop := make(chan struct{})
+	daemon.ipamReconcileStop = stop
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+			report, err := daemon.ReconcileIPAM(conf.Release)
+			if err != nil {
+				logrus.WithError(err).Warn("IPAM reconciliation failed")
+				continue
+			}
+			for _, l := range report.Leaks {
+				logrus.WithFields(logrus.Fields{
+					"driver":   l.Driver,
+					"pool":     l.PoolID,
+					"address":  l.Address,
+					"released": l.Released,
+				}).Warn("IPAM allocation owned by no network or endpoint")
+			}
+		}
+	}()
+}
+
+// stopIPAMReconcile stops reconciling the IPAM allocations.
+func (daemon *Daemon) stopIPAMReconcile() {
+	if daemon.ipamReconcileStop != nil {
+		close(daemon.ipamReconcileStop)
+		daemon.ipamReconcileStop = nil
+	}
+}
@@ -0,0 +1,61 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"github.com/docker/docker/api/types"
+	daemonlabels "github.com/docker/docker/daemon/labels"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+)
+
+// ContainerUpdateLabels adds and removes labels of a container, and returns
+// the new labels.
+func (daemon *Daemon) ContainerUpdateLabels(name string, update types.LabelsUpdate) (map[string]string, error) {
+	if err := daemonlabels.ValidateUpdate(update); err != nil {
+		return nil, err
+	}
+	ctr, err := daemon.GetContainer(name)
+	if err != nil {
+		return nil, err
+	}
+
+	ctr.Lock()
+	if ctr.RemovalInProgress || ctr.Dead {
+		ctr.Unlock()
+		return nil, errCannotUpdate(ctr.ID, errors.New("container is marked for removal and cannot be \"update\""))
+	}
+	// The labels are replaced, not modified in place, so that they can be
+	// returned without holding the lock.
+	previous := ctr.Config.Labels
+	labels := update.Apply(previous)
+	ctr.Config.Labels = labels
+	if err := ctr.CheckpointTo(daemon.containersReplica); err != nil {
+		ctr.Config.Labels = previous
+		ctr.Unlock()
+		return nil, errCannotUpdate(ctr.ID, err)
+	}
+	ctr.Unlock()
+
+	daemon.LogContainerEvent(ctr, "update")
+	return labels, nil
+}
+
+// NetworkUpdateLabels adds and removes labels of a local network, and returns
+// the new labels. The labels of swarm networks are managed by the cluster.
+func (daemon *Daemon) NetworkUpdateLabels(id string, update types.LabelsUpdate) (map[string]string, error) {
+	if err := daemonlabels.ValidateUpdate(update, daemonlabels.NetworkPrefix); err != nil {
+		return nil, err
+	}
+	nw, err := daemon.FindNetwork(id)
+	if err != nil {
+		return nil, err
+	}
+	if nw.Info().Dynamic() {
+		return nil, errdefs.InvalidParameter(errors.Errorf("the labels of swarm network %s cannot be updated", nw.Name()))
+	}
+	labels, err := nw.UpdateLabels(update.Apply)
+	if err != nil {
+		return nil, err
+	}
+	daemon.LogNetworkEvent(nw, "update")
+	return labels, nil
+}
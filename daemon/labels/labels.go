@@ -0,0 +1,41 @@
+// Package labels contains the checks applied by the daemon to the updates of
+// the labels of existing objects.
+package labels // import "github.com/docker/docker/daemon/labels"
+
+import (
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+)
+
+// reservedPrefixes are the prefixes of the labels set by the orchestrators
+// managing objects, which cannot be updated.
+var reservedPrefixes = []string{"com.docker.swarm.", "com.docker.compose."}
+
+// Reserved prefixes of the labels set by the daemon itself on some types of
+// objects, which cannot be updated either.
+const (
+	VolumePrefix  = "com.docker.volume."
+	NetworkPrefix = "com.docker.network."
+)
+
+// ValidateUpdate returns an error if a labels update adds or removes reserved
+// labels, that is labels with the prefixes reserved for orchestrators, or with
+// one of the given prefixes.
+func ValidateUpdate(update types.LabelsUpdate, prefixes ...string) error {
+	keys := append([]string(nil), update.Remove...)
+	for k := range update.Add {
+		keys = append(keys, k)
+	}
+	prefixes = append(prefixes, reservedPrefixes...)
+	for _, k := range keys {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(k, prefix) {
+				return errdefs.InvalidParameter(errors.Errorf("label %s is reserved, and cannot be updated", k))
+			}
+		}
+	}
+	return nil
+}
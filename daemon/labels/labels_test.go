@@ -0,0 +1,28 @@
+package labels // import "github.com/docker/docker/daemon/labels"
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/errdefs"
+	"gotest.tools/v3/assert"
+)
+
+func TestValidateUpdate(t *testing.T) {
+	assert.Check(t, ValidateUpdate(types.LabelsUpdate{
+		Add:    map[string]string{"com.example.team": "a", "com.docker.swarmkit": "b"},
+		Remove: []string{"com.docker.composer"},
+	}))
+
+	err := ValidateUpdate(types.LabelsUpdate{Add: map[string]string{"com.docker.swarm.service.id": "x"}})
+	assert.Check(t, errdefs.IsInvalidParameter(err))
+	err = ValidateUpdate(types.LabelsUpdate{Remove: []string{"com.docker.compose.project"}})
+	assert.Check(t, errdefs.IsInvalidParameter(err))
+
+	update := types.LabelsUpdate{Remove: []string{"com.docker.volume.image"}}
+	assert.Check(t, ValidateUpdate(update))
+	err = ValidateUpdate(update, VolumePrefix)
+	assert.Check(t, errdefs.IsInvalidParameter(err))
+	err = ValidateUpdate(types.LabelsUpdate{Add: map[string]string{"com.docker.swarm.node.id": "x"}}, VolumePrefix)
+	assert.Check(t, errdefs.IsInvalidParameter(err))
+}
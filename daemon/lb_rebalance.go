@@ -0,0 +1,105 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"net"
+	"time"
+
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/daemon/config"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/libnetwork"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// RebalanceNetwork re-synchronizes the load balancers of the services on the
+// network on this node with the backends of the services, and resets their
+// weights. If drain is set, the backends holding significantly more active
+// connections than the others receive no new connections for that long.
+func (daemon *Daemon) RebalanceNetwork(idOrName string, drain time.Duration) (*network.RebalanceReport, error) {
+	if drain < 0 {
+		return nil, errdefs.InvalidParameter(errors.Errorf("invalid drain period: %s", drain))
+	}
+	nw, err := daemon.FindNetwork(idOrName)
+	if err != nil {
+		return nil, err
+	}
+	rebalances, err := daemon.netController.RebalanceLoadBalancers(nw.ID(), drain)
+	if err != nil {
+		return nil, err
+	}
+	return rebalanceReport(rebalances), nil
+}
+
+func rebalanceReport(rebalances []libnetwork.LBRebalance) *network.RebalanceReport {
+	report := &network.RebalanceReport{LoadBalancers: []network.LoadBalancerRebalance{}}
+	for _, r := range rebalances {
+		report.LoadBalancers = append(report.LoadBalancers, network.LoadBalancerRebalance{
+			ServiceID:   r.ServiceID,
+			ServiceName: r.ServiceName,
+			VIP:         r.VIP.String(),
+			Added:       ipStrings(r.Added),
+			Removed:     ipStrings(r.Removed),
+			Reweighted:  ipStrings(r.Reweighted),
+			Drained:     ipStrings(r.Drained),
+		})
+	}
+	return report
+}
+
+func ipStrings(ips []net.IP) []string {
+	var s []string
+	for _, ip := range ips {
+		s = append(s, ip.String())
+	}
+	return s
+}
+
+// startLBRebalance starts rebalancing the load balancers of the services on
+// all networks periodically, if an interval is configured.
+func (daemon *Daemon) startLBRebalance(conf config.LBRebalanceConfig) {
+	interval := conf.GetInterval()
+	if interval <= 0 {
+		return
+	}
+	stop := make(chan struct{})
+	daemon.lbRebalanceStop = stop
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+			rebalances, err := daemon.netController.RebalanceLoadBalancers("", conf.GetDrain())
+			if err != nil {
+				logrus.WithError(err).Warn("Load balancer rebalance failed")
+				continue
+			}
+			for _, r := range rebalances {
+				if len(r.Added)+len(r.Removed)+len(r.Reweighted)+len(r.Drained) == 0 {
+					continue
+				}
+				logrus.WithFields(logrus.Fields{
+					"network":    r.NetworkID,
+					"service":    r.ServiceName,
+					"vip":        r.VIP,
+					"added":      r.Added,
+					"removed":    r.Removed,
+					"reweighted": r.Reweighted,
+					"drained":    r.Drained,
+				}).Info("Rebalanced load balancer")
+			}
+		}
+	}()
+}
+
+// stopLBRebalance stops rebalancing the load balancers.
+func (daemon *Daemon) stopLBRebalance() {
+	if daemon.lbRebalanceStop != nil {
+		close(daemon.lbRebalanceStop)
+		daemon.lbRebalanceStop = nil
+	}
+}
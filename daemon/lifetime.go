@@ -0,0 +1,110 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"context"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/container"
+	"github.com/sirupsen/logrus"
+)
+
+// Reasons for the end of the lifetime of a container, as reported by the
+// "expire" event.
+const (
+	lifetimeMaxRuntime = "max-runtime"
+	lifetimeDeadline   = "deadline"
+)
+
+// startLifetimeTimer arms the timer which ends the lifetime of a running
+// container, replacing the previous one if any. The max runtime is counted
+// from the start of the container.
+func (daemon *Daemon) startLifetimeTimer(c *container.Container) {
+	lifetime := c.HostConfig.Lifetime
+	if lifetime == nil {
+		return
+	}
+	remaining, reason := lifetimeRemaining(lifetime, c.State.StartedAt, time.Now())
+
+	timer := time.AfterFunc(remaining, func() {
+		daemon.expireContainer(c, reason)
+	})
+
+	daemon.lifetimeTimersMu.Lock()
+	defer daemon.lifetimeTimersMu.Unlock()
+	if daemon.lifetimeTimers == nil {
+		daemon.lifetimeTimers = make(map[string]*time.Timer)
+	}
+	if old, ok := daemon.lifetimeTimers[c.ID]; ok {
+		old.Stop()
+	}
+	daemon.lifetimeTimers[c.ID] = timer
+}
+
+// lifetimeRemaining returns the remaining lifetime of a container started at
+// the given time, and the reason for the end of its lifetime.
+func lifetimeRemaining(lifetime *containertypes.Lifetime, startedAt, now time.Time) (time.Duration, string) {
+	var (
+		remaining time.Duration
+		reason    string
+	)
+	if lifetime.MaxRuntime > 0 {
+		remaining = startedAt.Add(lifetime.MaxRuntime).Sub(now)
+		reason = lifetimeMaxRuntime
+	}
+	if lifetime.Deadline != nil {
+		if d := lifetime.Deadline.Sub(now); reason == "" || d < remaining {
+			remaining = d
+			reason = lifetimeDeadline
+		}
+	}
+	if remaining < 0 {
+		remaining = 0
+	}
+	return remaining, reason
+}
+
+// stopLifetimeTimer disarms the lifetime timer of a container.
+func (daemon *Daemon) stopLifetimeTimer(id string) {
+	daemon.lifetimeTimersMu.Lock()
+	defer daemon.lifetimeTimersMu.Unlock()
+	if timer, ok := daemon.lifetimeTimers[id]; ok {
+		timer.Stop()
+		delete(daemon.lifetimeTimers, id)
+	}
+}
+
+// expireContainer ends the lifetime of a container: it is stopped with the
+// signal and timeout of its lifetime, without being restarted by its restart
+// policy, and removed if requested.
+func (daemon *Daemon) expireContainer(c *container.Container, reason string) {
+	if daemon.IsShuttingDown() || !c.IsRunning() {
+		return
+	}
+	lifetime := c.HostConfig.Lifetime
+	action := lifetime.Action
+	if action == "" {
+		action = containertypes.LifetimeActionStop
+	}
+	daemon.LogContainerEventWithAttributes(c, "expire", map[string]string{
+		"reason":         reason,
+		"lifetimeAction": string(action),
+	})
+
+	c.Lock()
+	c.ExitOnNext()
+	c.Unlock()
+
+	logger := logrus.WithFields(logrus.Fields{"container": c.ID, "reason": reason})
+	options := containertypes.StopOptions{Signal: lifetime.StopSignal, Timeout: lifetime.StopTimeout}
+	if err := daemon.containerStop(context.Background(), c, options); err != nil {
+		logger.WithError(err).Error("failed to stop expired container")
+		return
+	}
+	if action == containertypes.LifetimeActionRemove {
+		if err := daemon.ContainerRm(c.ID, &types.ContainerRmConfig{ForceRemove: true}); err != nil {
+			logger.WithError(err).Error("failed to remove expired container")
+		}
+	}
+}
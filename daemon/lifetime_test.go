@@ -0,0 +1,77 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"testing"
+	"time"
+
+	containertypes "github.com/docker/docker/api/types/container"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestLifetimeRemaining(t *testing.T) {
+	now := time.Now()
+	soon := now.Add(time.Minute)
+	past := now.Add(-time.Minute)
+
+	for _, tc := range []struct {
+		doc       string
+		lifetime  containertypes.Lifetime
+		startedAt time.Time
+		remaining time.Duration
+		reason    string
+	}{
+		{
+			doc:       "max runtime",
+			lifetime:  containertypes.Lifetime{MaxRuntime: time.Hour},
+			startedAt: now.Add(-10 * time.Minute),
+			remaining: 50 * time.Minute,
+			reason:    lifetimeMaxRuntime,
+		},
+		{
+			doc:       "deadline",
+			lifetime:  containertypes.Lifetime{Deadline: &soon},
+			startedAt: now,
+			remaining: time.Minute,
+			reason:    lifetimeDeadline,
+		},
+		{
+			doc:       "deadline before max runtime",
+			lifetime:  containertypes.Lifetime{MaxRuntime: time.Hour, Deadline: &soon},
+			startedAt: now,
+			remaining: time.Minute,
+			reason:    lifetimeDeadline,
+		},
+		{
+			doc:       "max runtime before deadline",
+			lifetime:  containertypes.Lifetime{MaxRuntime: time.Second, Deadline: &soon},
+			startedAt: now,
+			remaining: time.Second,
+			reason:    lifetimeMaxRuntime,
+		},
+		{
+			doc:       "past deadline",
+			lifetime:  containertypes.Lifetime{MaxRuntime: time.Hour, Deadline: &past},
+			startedAt: now,
+			remaining: 0,
+			reason:    lifetimeDeadline,
+		},
+	} {
+		t.Run(tc.doc, func(t *testing.T) {
+			remaining, reason := lifetimeRemaining(&tc.lifetime, tc.startedAt, now)
+			assert.Check(t, is.Equal(remaining, tc.remaining))
+			assert.Check(t, is.Equal(reason, tc.reason))
+		})
+	}
+}
+
+func TestValidateLifetime(t *testing.T) {
+	deadline := time.Now()
+	assert.Check(t, validateLifetime(nil))
+	assert.Check(t, validateLifetime(&containertypes.Lifetime{MaxRuntime: time.Minute, Action: containertypes.LifetimeActionRemove, StopSignal: "SIGINT"}))
+	assert.Check(t, validateLifetime(&containertypes.Lifetime{Deadline: &deadline}))
+	assert.Check(t, is.ErrorContains(validateLifetime(&containertypes.Lifetime{}), "requires a max runtime or a deadline"))
+	assert.Check(t, is.ErrorContains(validateLifetime(&containertypes.Lifetime{MaxRuntime: -time.Second}), "cannot be negative"))
+	assert.Check(t, is.ErrorContains(validateLifetime(&containertypes.Lifetime{MaxRuntime: time.Minute, Action: "pause"}), "invalid lifetime action"))
+	assert.Check(t, is.ErrorContains(validateLifetime(&containertypes.Lifetime{MaxRuntime: time.Minute, StopSignal: "SIGNOPE"}), "invalid signal"))
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/filters"
 	imagetypes "github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/pagination"
 	"github.com/docker/docker/container"
 	"github.com/docker/docker/daemon/images"
 	"github.com/docker/docker/errdefs"
@@ -81,6 +82,8 @@ type listContext struct {
 	beforeFilter *container.Snapshot
 	// sinceFilter is a filter to stop the filtering when the iterator arrives to the given container
 	sinceFilter *container.Snapshot
+	// cursor is a filter to ignore the containers up to the end of the previous page
+	cursor *pagination.Cursor
 
 	// taskFilter tells if we should filter based on whether a container is part of a task
 	taskFilter bool
@@ -102,11 +105,24 @@ type byCreatedDescending []container.Snapshot
 func (r byCreatedDescending) Len() int      { return len(r) }
 func (r byCreatedDescending) Swap(i, j int) { r[i], r[j] = r[j], r[i] }
 func (r byCreatedDescending) Less(i, j int) bool {
-	return r[j].CreatedAt.UnixNano() < r[i].CreatedAt.UnixNano()
+	if !r[i].CreatedAt.Equal(r[j].CreatedAt) {
+		return r[j].CreatedAt.UnixNano() < r[i].CreatedAt.UnixNano()
+	}
+	// order containers created at the same time by ID, so that pages
+	// of the list are stable
+	return r[i].ID < r[j].ID
 }
 
 // Containers returns the list of containers to show given the user's filtering.
 func (daemon *Daemon) Containers(ctx context.Context, config *types.ContainerListOptions) ([]*types.Container, error) {
+	containers, _, err := daemon.reduceContainers(ctx, config, daemon.refreshImage)
+	return containers, err
+}
+
+// ContainersPage returns the list of containers to show given the user's
+// filtering, and the cursor of the next page if the list was cut at the
+// limit of the options.
+func (daemon *Daemon) ContainersPage(ctx context.Context, config *types.ContainerListOptions) ([]*types.Container, string, error) {
 	return daemon.reduceContainers(ctx, config, daemon.refreshImage)
 }
 
@@ -180,9 +196,13 @@ func (daemon *Daemon) filterByNameIDMatches(view *container.View, filter *listCo
 }
 
 // reduceContainers parses the user's filtering options and generates the list of containers to return based on a reducer.
-func (daemon *Daemon) reduceContainers(ctx context.Context, config *types.ContainerListOptions, reducer containerReducer) ([]*types.Container, error) {
+// It also returns the cursor of the next page if the list was cut at the limit of the options.
+func (daemon *Daemon) reduceContainers(ctx context.Context, config *types.ContainerListOptions, reducer containerReducer) ([]*types.Container, string, error) {
 	if err := config.Filters.Validate(acceptedPsFilterTags); err != nil {
-		return nil, err
+		return nil, "", err
+	}
+	if err := config.Filters.ValidateLabelSelectors("label"); err != nil {
+		return nil, "", err
 	}
 
 	var (
@@ -192,7 +212,7 @@ func (daemon *Daemon) reduceContainers(ctx context.Context, config *types.Contai
 
 	filter, err := daemon.foldFilter(ctx, view, config)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	// fastpath to only look at a subset of containers if specific name
@@ -200,24 +220,32 @@ func (daemon *Daemon) reduceContainers(ctx context.Context, config *types.Contai
 	// end up querying many more containers than intended
 	containerList, err := daemon.filterByNameIDMatches(view, filter)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
+	var (
+		last     *container.Snapshot
+		nextPage string
+	)
 	for i := range containerList {
 		t, err := daemon.reducePsContainer(ctx, &containerList[i], filter, reducer)
 		if err != nil {
 			if err != errStopIteration {
-				return nil, err
+				return nil, "", err
+			}
+			if filter.Limit > 0 && filter.idx == filter.Limit && last != nil {
+				nextPage = pagination.Cursor{Created: last.CreatedAt.UnixNano(), ID: last.ID}.String()
 			}
 			break
 		}
 		if t != nil {
 			containers = append(containers, t)
+			last = &containerList[i]
 			filter.idx++
 		}
 	}
 
-	return containers, nil
+	return containers, nextPage, nil
 }
 
 // reducePsContainer is the basic representation for a container as expected by the ps command.
@@ -317,6 +345,15 @@ func (daemon *Daemon) foldFilter(ctx context.Context, view *container.View, conf
 		return nil, err
 	}
 
+	var cursor *pagination.Cursor
+	if config.Cursor != "" {
+		c, err := pagination.Parse(config.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		cursor = &c
+	}
+
 	imagesFilter := map[image.ID]bool{}
 	var ancestorFilter bool
 	if psFilters.Contains("ancestor") {
@@ -356,6 +393,7 @@ func (daemon *Daemon) foldFilter(ctx context.Context, view *container.View, conf
 		exitAllowed:          filtExited,
 		beforeFilter:         beforeContFilter,
 		sinceFilter:          sinceContFilter,
+		cursor:               cursor,
 		taskFilter:           taskFilter,
 		isTask:               isTask,
 		publish:              publishFilter,
@@ -421,6 +459,11 @@ func includeContainerInList(container *container.Snapshot, filter *listContext)
 		return excludeContainer
 	}
 
+	// Do not include container if it was listed in a previous page.
+	if filter.cursor != nil && !filter.cursor.After(container.CreatedAt.UnixNano(), container.ID) {
+		return excludeContainer
+	}
+
 	// Stop iteration when the container arrives to the filter container
 	if filter.sinceFilter != nil {
 		if container.ID == filter.sinceFilter.ID {
@@ -11,6 +11,7 @@ import (
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/container"
 	"github.com/docker/docker/image"
+	"github.com/docker/docker/pkg/reexec"
 	"github.com/google/uuid"
 	"github.com/opencontainers/go-digest"
 	"gotest.tools/v3/assert"
@@ -20,6 +21,9 @@ import (
 var root string
 
 func TestMain(m *testing.M) {
+	if reexec.Init() {
+		return
+	}
 	var err error
 	root, err = os.MkdirTemp("", "docker-container-test-")
 	if err != nil {
@@ -143,3 +147,32 @@ func TestNameFilter(t *testing.T) {
 	assert.Assert(t, is.Len(containerListWithPrefix, 1))
 	assert.Assert(t, containerListContainsName(containerListWithPrefix, three.Name))
 }
+
+func TestContainersPage(t *testing.T) {
+	db, err := container.NewViewDB()
+	assert.Assert(t, err == nil)
+	d := &Daemon{
+		containersReplica: db,
+	}
+
+	for _, name := range []string{"a1", "a2", "a3"} {
+		setupContainerWithName(t, name, d)
+	}
+
+	first, next, err := d.ContainersPage(context.Background(), &types.ContainerListOptions{Limit: 2})
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(first, 2))
+	assert.Assert(t, next != "")
+
+	second, next, err := d.ContainersPage(context.Background(), &types.ContainerListOptions{Limit: 2, Cursor: next})
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(second, 1))
+	assert.Check(t, is.Equal(next, ""))
+
+	all, err := d.Containers(context.Background(), &types.ContainerListOptions{All: true})
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(append(first, second...), all))
+
+	_, _, err = d.ContainersPage(context.Background(), &types.ContainerListOptions{Cursor: "invalid!"})
+	assert.Check(t, is.ErrorContains(err, "invalid cursor"))
+}
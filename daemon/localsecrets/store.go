@@ -0,0 +1,588 @@
+// Package localsecrets stores the secrets and configs of standalone
+// containers, which are not managed by a swarm.
+//
+// The objects are stored as swarm objects, so that they are exposed to
+// containers the same way as the secrets and configs of swarm tasks. Their
+// content is encrypted at rest with a key which is generated on the first
+// use of the store, and only readable by root.
+package localsecrets // import "github.com/docker/docker/daemon/localsecrets"
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/ioutils"
+	gogotypes "github.com/gogo/protobuf/types"
+	"github.com/moby/swarmkit/v2/agent/exec"
+	swarmapi "github.com/moby/swarmkit/v2/api"
+	"github.com/moby/swarmkit/v2/identity"
+	"github.com/pkg/errors"
+)
+
+const (
+	keyFile    = "key"
+	keySize    = 32
+	secretsDir = "secrets"
+	configsDir = "configs"
+)
+
+// validID matches the IDs of the objects, which are generated like the IDs
+// of swarm objects.
+var validID = regexp.MustCompile(`^[a-z0-9]+$`)
+
+// object is a swarm object persisted by the store.
+type object interface {
+	Marshal() ([]byte, error)
+	Unmarshal([]byte) error
+}
+
+// UsersFunc returns the names of the containers which have a reference to a
+// secret or config of the given kind ("secret" or "config") for which uses
+// returns true.
+type UsersFunc func(kind string, uses func(source string) bool) []string
+
+// Store is the local store of secrets and configs.
+type Store struct {
+	mu    sync.Mutex
+	root  string
+	aead  cipher.AEAD
+	users UsersFunc
+}
+
+// New returns the store of secrets and configs in the root directory. The
+// secrets and configs which users reports in use by containers cannot be
+// removed.
+func New(root string, users UsersFunc) (*Store, error) {
+	for _, dir := range []string{secretsDir, configsDir} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0o700); err != nil {
+			return nil, err
+		}
+	}
+	key, err := loadKey(filepath.Join(root, keyFile))
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Store{root: root, aead: aead, users: users}, nil
+}
+
+// loadKey reads the encryption key of the store, generating it if it does
+// not exist yet.
+func loadKey(path string) ([]byte, error) {
+	key, err := os.ReadFile(path)
+	if err == nil {
+		if len(key) != keySize {
+			return nil, errors.Errorf("invalid encryption key in %s", path)
+		}
+		return key, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+	key = make([]byte, keySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, errors.Wrap(err, "failed to generate encryption key")
+	}
+	if err := ioutils.AtomicWriteFile(path, key, 0o600); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// write encrypts an object to its file. The ID is authenticated along with
+// the content, so that files cannot be swapped.
+func (s *Store) write(dir, id string, obj object) error {
+	b, err := obj.Marshal()
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return err
+	}
+	return ioutils.AtomicWriteFile(filepath.Join(s.root, dir, id), s.aead.Seal(nonce, nonce, b, []byte(id)), 0o600)
+}
+
+// read decrypts an object from its file.
+func (s *Store) read(dir, id string, obj object) error {
+	data, err := os.ReadFile(filepath.Join(s.root, dir, id))
+	if err != nil {
+		return err
+	}
+	n := s.aead.NonceSize()
+	if len(data) < n {
+		return errors.Errorf("%s %s is corrupted", strings.TrimSuffix(dir, "s"), id)
+	}
+	b, err := s.aead.Open(nil, data[:n], data[n:], []byte(id))
+	if err != nil {
+		return errors.Wrapf(err, "failed to decrypt %s %s", strings.TrimSuffix(dir, "s"), id)
+	}
+	return obj.Unmarshal(b)
+}
+
+// ids returns the IDs of the objects of a directory.
+func (s *Store) ids(dir string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(s.root, dir))
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, e := range entries {
+		if validID.MatchString(e.Name()) {
+			ids = append(ids, e.Name())
+		}
+	}
+	return ids, nil
+}
+
+// lookup returns the index of the object referenced by input, which is
+// either the ID, the name, or a prefix of the ID of the object, like
+// references to swarm objects.
+func lookup(kind, input string, ids, names []string) (int, error) {
+	for i, id := range ids {
+		if id == input {
+			return i, nil
+		}
+	}
+	for i, name := range names {
+		if name == input {
+			return i, nil
+		}
+	}
+	match := -1
+	for i, id := range ids {
+		if input != "" && strings.HasPrefix(id, input) {
+			if match >= 0 {
+				return 0, errdefs.InvalidParameter(fmt.Errorf("%s %s is ambiguous", kind, input))
+			}
+			match = i
+		}
+	}
+	if match < 0 {
+		return 0, errdefs.NotFound(fmt.Errorf("%s %s not found", kind, input))
+	}
+	return match, nil
+}
+
+// matches returns whether an object matches list filters, with the same
+// semantics as the filters of swarm objects.
+func matches(id string, annotations swarmapi.Annotations, names, namePrefixes, idPrefixes []string, labels map[string]string) bool {
+	if len(names) > 0 && !hasMatch(annotations.Name, names, func(s, n string) bool { return s == n }) {
+		return false
+	}
+	if len(namePrefixes) > 0 && !hasMatch(annotations.Name, namePrefixes, strings.HasPrefix) {
+		return false
+	}
+	if len(idPrefixes) > 0 && !hasMatch(id, idPrefixes, strings.HasPrefix) {
+		return false
+	}
+	for k, v := range labels {
+		l, ok := annotations.Labels[k]
+		if !ok || (v != "" && l != v) {
+			return false
+		}
+	}
+	return true
+}
+
+func hasMatch(s string, values []string, match func(string, string) bool) bool {
+	for _, v := range values {
+		if match(s, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func validateName(kind string, annotations swarmapi.Annotations, names []string) error {
+	if annotations.Name == "" {
+		return errdefs.InvalidParameter(fmt.Errorf("%s name must be provided", kind))
+	}
+	for _, name := range names {
+		if name == annotations.Name {
+			return errdefs.Conflict(fmt.Errorf("%s %s already exists", kind, name))
+		}
+	}
+	return nil
+}
+
+func newMeta() swarmapi.Meta {
+	now := gogotypes.TimestampNow()
+	return swarmapi.Meta{Version: swarmapi.Version{Index: 1}, CreatedAt: now, UpdatedAt: now}
+}
+
+// updateMeta checks the version of an update of an object, and bumps it.
+func updateMeta(meta *swarmapi.Meta, version uint64) error {
+	if meta.Version.Index != version {
+		return errdefs.Conflict(errors.New("update out of sequence"))
+	}
+	meta.Version.Index++
+	meta.UpdatedAt = gogotypes.TimestampNow()
+	return nil
+}
+
+func (s *Store) secrets() ([]*swarmapi.Secret, error) {
+	ids, err := s.ids(secretsDir)
+	if err != nil {
+		return nil, err
+	}
+	secrets := make([]*swarmapi.Secret, 0, len(ids))
+	for _, id := range ids {
+		secret := &swarmapi.Secret{}
+		if err := s.read(secretsDir, id, secret); err != nil {
+			return nil, err
+		}
+		secrets = append(secrets, secret)
+	}
+	sort.Slice(secrets, func(i, j int) bool {
+		return secrets[i].Spec.Annotations.Name < secrets[j].Spec.Annotations.Name
+	})
+	return secrets, nil
+}
+
+func (s *Store) findSecret(input string) (*swarmapi.Secret, error) {
+	secrets, err := s.secrets()
+	if err != nil {
+		return nil, err
+	}
+	ids, names := secretKeys(secrets)
+	i, err := lookup("secret", input, ids, names)
+	if err != nil {
+		return nil, err
+	}
+	return secrets[i], nil
+}
+
+// secretKeys returns the IDs and names of secrets, to look them up.
+func secretKeys(secrets []*swarmapi.Secret) (ids, names []string) {
+	ids = make([]string, len(secrets))
+	names = make([]string, len(secrets))
+	for i, secret := range secrets {
+		ids[i], names[i] = secret.ID, secret.Spec.Annotations.Name
+	}
+	return ids, names
+}
+
+// GetSecret returns a secret by ID, name, or ID prefix.
+func (s *Store) GetSecret(input string) (*swarmapi.Secret, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.findSecret(input)
+}
+
+// ListSecrets returns the secrets matching the filters.
+func (s *Store) ListSecrets(filters *swarmapi.ListSecretsRequest_Filters) ([]*swarmapi.Secret, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	secrets, err := s.secrets()
+	if err != nil || filters == nil {
+		return secrets, err
+	}
+	var matching []*swarmapi.Secret
+	for _, secret := range secrets {
+		if matches(secret.ID, secret.Spec.Annotations, filters.Names, filters.NamePrefixes, filters.IDPrefixes, filters.Labels) {
+			matching = append(matching, secret)
+		}
+	}
+	return matching, nil
+}
+
+// CreateSecret creates a secret, and returns its ID.
+func (s *Store) CreateSecret(spec swarmapi.SecretSpec) (string, error) {
+	if spec.Driver != nil {
+		return "", errdefs.InvalidParameter(errors.New("secret drivers are only supported in swarm mode"))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	secrets, err := s.secrets()
+	if err != nil {
+		return "", err
+	}
+	names := make([]string, len(secrets))
+	for i, secret := range secrets {
+		names[i] = secret.Spec.Annotations.Name
+	}
+	if err := validateName("secret", spec.Annotations, names); err != nil {
+		return "", err
+	}
+	secret := &swarmapi.Secret{ID: identity.NewID(), Meta: newMeta(), Spec: spec}
+	if err := s.write(secretsDir, secret.ID, secret); err != nil {
+		return "", err
+	}
+	return secret.ID, nil
+}
+
+// UpdateSecret updates the labels of a secret. As for swarm secrets, the
+// name and data of a secret cannot be updated.
+func (s *Store) UpdateSecret(input string, version uint64, spec swarmapi.SecretSpec) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	secret, err := s.findSecret(input)
+	if err != nil {
+		return err
+	}
+	if spec.Annotations.Name != secret.Spec.Annotations.Name || spec.Data != nil {
+		return errdefs.InvalidParameter(errors.New("only updates to Labels are allowed"))
+	}
+	if err := updateMeta(&secret.Meta, version); err != nil {
+		return err
+	}
+	secret.Spec.Annotations.Labels = spec.Annotations.Labels
+	return s.write(secretsDir, secret.ID, secret)
+}
+
+// RemoveSecret removes a secret.
+func (s *Store) RemoveSecret(input string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	secrets, err := s.secrets()
+	if err != nil {
+		return err
+	}
+	ids, names := secretKeys(secrets)
+	i, err := lookup("secret", input, ids, names)
+	if err != nil {
+		return err
+	}
+	if err := s.checkUnused("secret", ids, names, i); err != nil {
+		return err
+	}
+	return os.Remove(filepath.Join(s.root, secretsDir, ids[i]))
+}
+
+func (s *Store) configs() ([]*swarmapi.Config, error) {
+	ids, err := s.ids(configsDir)
+	if err != nil {
+		return nil, err
+	}
+	configs := make([]*swarmapi.Config, 0, len(ids))
+	for _, id := range ids {
+		config := &swarmapi.Config{}
+		if err := s.read(configsDir, id, config); err != nil {
+			return nil, err
+		}
+		configs = append(configs, config)
+	}
+	sort.Slice(configs, func(i, j int) bool {
+		return configs[i].Spec.Annotations.Name < configs[j].Spec.Annotations.Name
+	})
+	return configs, nil
+}
+
+func (s *Store) findConfig(input string) (*swarmapi.Config, error) {
+	configs, err := s.configs()
+	if err != nil {
+		return nil, err
+	}
+	ids, names := configKeys(configs)
+	i, err := lookup("config", input, ids, names)
+	if err != nil {
+		return nil, err
+	}
+	return configs[i], nil
+}
+
+// configKeys returns the IDs and names of configs, to look them up.
+func configKeys(configs []*swarmapi.Config) (ids, names []string) {
+	ids = make([]string, len(configs))
+	names = make([]string, len(configs))
+	for i, config := range configs {
+		ids[i], names[i] = config.ID, config.Spec.Annotations.Name
+	}
+	return ids, names
+}
+
+// GetConfig returns a config by ID, name, or ID prefix.
+func (s *Store) GetConfig(input string) (*swarmapi.Config, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.findConfig(input)
+}
+
+// ListConfigs returns the configs matching the filters.
+func (s *Store) ListConfigs(filters *swarmapi.ListConfigsRequest_Filters) ([]*swarmapi.Config, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	configs, err := s.configs()
+	if err != nil || filters == nil {
+		return configs, err
+	}
+	var matching []*swarmapi.Config
+	for _, config := range configs {
+		if matches(config.ID, config.Spec.Annotations, filters.Names, filters.NamePrefixes, filters.IDPrefixes, filters.Labels) {
+			matching = append(matching, config)
+		}
+	}
+	return matching, nil
+}
+
+// CreateConfig creates a config, and returns its ID.
+func (s *Store) CreateConfig(spec swarmapi.ConfigSpec) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	configs, err := s.configs()
+	if err != nil {
+		return "", err
+	}
+	names := make([]string, len(configs))
+	for i, config := range configs {
+		names[i] = config.Spec.Annotations.Name
+	}
+	if err := validateName("config", spec.Annotations, names); err != nil {
+		return "", err
+	}
+	config := &swarmapi.Config{ID: identity.NewID(), Meta: newMeta(), Spec: spec}
+	if err := s.write(configsDir, config.ID, config); err != nil {
+		return "", err
+	}
+	return config.ID, nil
+}
+
+// UpdateConfig updates the labels of a config. As for swarm configs, the
+// name and data of a config cannot be updated.
+func (s *Store) UpdateConfig(input string, version uint64, spec swarmapi.ConfigSpec) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	config, err := s.findConfig(input)
+	if err != nil {
+		return err
+	}
+	if spec.Annotations.Name != config.Spec.Annotations.Name || spec.Data != nil {
+		return errdefs.InvalidParameter(errors.New("only updates to Labels are allowed"))
+	}
+	if err := updateMeta(&config.Meta, version); err != nil {
+		return err
+	}
+	config.Spec.Annotations.Labels = spec.Annotations.Labels
+	return s.write(configsDir, config.ID, config)
+}
+
+// RemoveConfig removes a config.
+func (s *Store) RemoveConfig(input string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	configs, err := s.configs()
+	if err != nil {
+		return err
+	}
+	ids, names := configKeys(configs)
+	i, err := lookup("config", input, ids, names)
+	if err != nil {
+		return err
+	}
+	if err := s.checkUnused("config", ids, names, i); err != nil {
+		return err
+	}
+	return os.Remove(filepath.Join(s.root, configsDir, ids[i]))
+}
+
+// checkUnused returns a conflict error if the secret or config with the i-th
+// of ids and names is used by containers. The references of the containers
+// are resolved against all the secrets or configs, as they are when the
+// containers start, so that a reference by name or ID prefix only matches the
+// object it resolves to.
+func (s *Store) checkUnused(kind string, ids, names []string, i int) error {
+	if s.users == nil {
+		return nil
+	}
+	users := s.users(kind, func(source string) bool {
+		j, err := lookup(kind, source, ids, names)
+		return err == nil && j == i
+	})
+	if len(users) > 0 {
+		return errdefs.Conflict(fmt.Errorf("%s '%s' is in use by the following container(s): %s", kind, names[i], strings.Join(users, ", ")))
+	}
+	return nil
+}
+
+// Secrets returns the getter of the secrets of the store, to expose them to
+// containers.
+func (s *Store) Secrets() exec.SecretGetter {
+	return secretGetter{s}
+}
+
+// Configs returns the getter of the configs of the store, to expose them to
+// containers.
+func (s *Store) Configs() exec.ConfigGetter {
+	return configGetter{s}
+}
+
+// Volumes returns a getter which has no volumes: cluster volumes are only
+// available to swarm tasks.
+func (s *Store) Volumes() exec.VolumeGetter {
+	return volumeGetter{}
+}
+
+type secretGetter struct {
+	s *Store
+}
+
+func (g secretGetter) Get(secretID string) (*swarmapi.Secret, error) {
+	if !validID.MatchString(secretID) {
+		return nil, errdefs.NotFound(fmt.Errorf("secret %s not found", secretID))
+	}
+	g.s.mu.Lock()
+	defer g.s.mu.Unlock()
+
+	secret := &swarmapi.Secret{}
+	if err := g.s.read(secretsDir, secretID, secret); err != nil {
+		if os.IsNotExist(err) {
+			return nil, errdefs.NotFound(fmt.Errorf("secret %s not found", secretID))
+		}
+		return nil, err
+	}
+	return secret, nil
+}
+
+type configGetter struct {
+	s *Store
+}
+
+func (g configGetter) Get(configID string) (*swarmapi.Config, error) {
+	if !validID.MatchString(configID) {
+		return nil, errdefs.NotFound(fmt.Errorf("config %s not found", configID))
+	}
+	g.s.mu.Lock()
+	defer g.s.mu.Unlock()
+
+	config := &swarmapi.Config{}
+	if err := g.s.read(configsDir, configID, config); err != nil {
+		if os.IsNotExist(err) {
+			return nil, errdefs.NotFound(fmt.Errorf("config %s not found", configID))
+		}
+		return nil, err
+	}
+	return config, nil
+}
+
+type volumeGetter struct{}
+
+func (volumeGetter) Get(volumeID string) (string, error) {
+	return "", errdefs.NotFound(fmt.Errorf("volume %s not found", volumeID))
+}
@@ -0,0 +1,139 @@
+package localsecrets // import "github.com/docker/docker/daemon/localsecrets"
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/errdefs"
+	swarmapi "github.com/moby/swarmkit/v2/api"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestStoreSecrets(t *testing.T) {
+	root := t.TempDir()
+	s, err := New(root, nil)
+	assert.NilError(t, err)
+
+	data := []byte("s3cr3t")
+	id, err := s.CreateSecret(swarmapi.SecretSpec{
+		Annotations: swarmapi.Annotations{Name: "db-password", Labels: map[string]string{"app": "db"}},
+		Data:        data,
+	})
+	assert.NilError(t, err)
+	_, err = s.CreateSecret(swarmapi.SecretSpec{Annotations: swarmapi.Annotations{Name: "db-password"}})
+	assert.Check(t, errdefs.IsConflict(err))
+	_, err = s.CreateSecret(swarmapi.SecretSpec{Annotations: swarmapi.Annotations{Name: "api-key"}, Data: []byte("key")})
+	assert.NilError(t, err)
+
+	// Secrets are encrypted at rest.
+	b, err := os.ReadFile(filepath.Join(root, secretsDir, id))
+	assert.NilError(t, err)
+	assert.Check(t, !bytes.Contains(b, data))
+
+	// Secrets are persisted.
+	s, err = New(root, nil)
+	assert.NilError(t, err)
+	for _, input := range []string{id, "db-password", id[:5]} {
+		secret, err := s.GetSecret(input)
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(secret.ID, id))
+		assert.Check(t, is.DeepEqual(secret.Spec.Data, data))
+	}
+	secret, err := s.Secrets().Get(id)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(secret.Spec.Annotations.Name, "db-password"))
+	_, err = s.Secrets().Get("db-password")
+	assert.Check(t, errdefs.IsNotFound(err))
+	_, err = s.Secrets().Get("../key")
+	assert.Check(t, errdefs.IsNotFound(err))
+
+	list, err := s.ListSecrets(nil)
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(list, 2))
+	assert.Check(t, is.Equal(list[0].Spec.Annotations.Name, "api-key"))
+	list, err = s.ListSecrets(&swarmapi.ListSecretsRequest_Filters{Labels: map[string]string{"app": ""}})
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(list, 1))
+	assert.Check(t, is.Equal(list[0].ID, id))
+
+	// Only the labels of secrets can be updated.
+	spec := secret.Spec
+	spec.Data = nil
+	spec.Annotations.Labels = map[string]string{"app": "db", "tier": "backend"}
+	assert.NilError(t, s.UpdateSecret(id, secret.Meta.Version.Index, spec))
+	assert.Check(t, errdefs.IsConflict(s.UpdateSecret(id, secret.Meta.Version.Index, spec)))
+	spec.Data = []byte("other")
+	assert.Check(t, errdefs.IsInvalidParameter(s.UpdateSecret(id, secret.Meta.Version.Index+1, spec)))
+	secret, err = s.GetSecret(id)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(secret.Spec.Annotations.Labels["tier"], "backend"))
+	assert.Check(t, is.DeepEqual(secret.Spec.Data, data))
+
+	assert.NilError(t, s.RemoveSecret("db-password"))
+	_, err = s.GetSecret(id)
+	assert.Check(t, errdefs.IsNotFound(err))
+	assert.Check(t, errdefs.IsNotFound(s.RemoveSecret("db-password")))
+}
+
+func TestStoreConfigs(t *testing.T) {
+	var inUse bool
+	s, err := New(t.TempDir(), func(kind string, uses func(string) bool) []string {
+		if inUse && kind == "config" && uses("nginx.conf") {
+			return []string{"web"}
+		}
+		return nil
+	})
+	assert.NilError(t, err)
+
+	id, err := s.CreateConfig(swarmapi.ConfigSpec{Annotations: swarmapi.Annotations{Name: "nginx.conf"}, Data: []byte("server {}")})
+	assert.NilError(t, err)
+	_, err = s.CreateConfig(swarmapi.ConfigSpec{})
+	assert.Check(t, errdefs.IsInvalidParameter(err))
+
+	config, err := s.Configs().Get(id)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(config.Spec.Data), "server {}"))
+	list, err := s.ListConfigs(&swarmapi.ListConfigsRequest_Filters{NamePrefixes: []string{"nginx"}})
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(list, 1))
+
+	inUse = true
+	err = s.RemoveConfig(id)
+	assert.Check(t, errdefs.IsConflict(err))
+	assert.Check(t, is.ErrorContains(err, "in use by the following container(s): web"))
+
+	inUse = false
+	assert.NilError(t, s.RemoveConfig(id))
+	_, err = s.Configs().Get(id)
+	assert.Check(t, errdefs.IsNotFound(err))
+}
+
+func TestStoreRemoveResolvesReferences(t *testing.T) {
+	var refs []string
+	s, err := New(t.TempDir(), func(kind string, uses func(string) bool) []string {
+		for _, r := range refs {
+			if uses(r) {
+				return []string{"web"}
+			}
+		}
+		return nil
+	})
+	assert.NilError(t, err)
+
+	id, err := s.CreateSecret(swarmapi.SecretSpec{Annotations: swarmapi.Annotations{Name: "api-key"}, Data: []byte("secret")})
+	assert.NilError(t, err)
+	// A reference to a secret named after a prefix of the ID of another
+	// secret only uses the secret with that name.
+	_, err = s.CreateSecret(swarmapi.SecretSpec{Annotations: swarmapi.Annotations{Name: id[:4]}, Data: []byte("other")})
+	assert.NilError(t, err)
+
+	refs = []string{id[:4]}
+	assert.Check(t, errdefs.IsConflict(s.RemoveSecret(id[:4])))
+	assert.NilError(t, s.RemoveSecret(id))
+
+	refs = []string{"api-key"}
+	assert.NilError(t, s.RemoveSecret(id[:4]))
+}
@@ -175,23 +175,24 @@ func (daemon *Daemon) getLogger(container *container.Container) (l logger.Logger
 
 // mergeLogConfig merges the daemon log config to the container's log config if the container's log driver is not specified.
 func (daemon *Daemon) mergeAndVerifyLogConfig(cfg *containertypes.LogConfig) error {
+	defaultLogConfig := daemon.getDefaultLogConfig()
 	if cfg.Type == "" {
-		cfg.Type = daemon.defaultLogConfig.Type
+		cfg.Type = defaultLogConfig.Type
 	}
 
 	if cfg.Config == nil {
 		cfg.Config = make(map[string]string)
 	}
 
-	if cfg.Type == daemon.defaultLogConfig.Type {
-		for k, v := range daemon.defaultLogConfig.Config {
+	if cfg.Type == defaultLogConfig.Type {
+		for k, v := range defaultLogConfig.Config {
 			if _, ok := cfg.Config[k]; !ok {
 				cfg.Config[k] = v
 			}
 		}
 	}
 
-	logcache.MergeDefaultLogConfig(cfg.Config, daemon.defaultLogConfig.Config)
+	logcache.MergeDefaultLogConfig(cfg.Config, defaultLogConfig.Config)
 
 	return logger.ValidateLogOpts(cfg.Type, cfg.Config)
 }
@@ -203,11 +204,23 @@ func (daemon *Daemon) setupDefaultLogConfig() error {
 			return errors.Wrap(err, "failed to set log opts")
 		}
 	}
-	daemon.defaultLogConfig = containertypes.LogConfig{
+	daemon.setDefaultLogConfig(containertypes.LogConfig{
 		Type:   config.LogConfig.Type,
 		Config: config.LogConfig.Config,
-	}
+	})
 
-	logrus.Debugf("Using default logging driver %s", daemon.defaultLogConfig.Type)
+	logrus.Debugf("Using default logging driver %s", config.LogConfig.Type)
 	return nil
 }
+
+func (daemon *Daemon) getDefaultLogConfig() containertypes.LogConfig {
+	daemon.defaultLogConfigMu.RLock()
+	defer daemon.defaultLogConfigMu.RUnlock()
+	return daemon.defaultLogConfig
+}
+
+func (daemon *Daemon) setDefaultLogConfig(cfg containertypes.LogConfig) {
+	daemon.defaultLogConfigMu.Lock()
+	daemon.defaultLogConfig = cfg
+	daemon.defaultLogConfigMu.Unlock()
+}
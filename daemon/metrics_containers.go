@@ -0,0 +1,163 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/container"
+	metrics "github.com/docker/go-metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+// containerStatsSource provides the containers and their resource usage
+// reported by the per-container metrics collector.
+type containerStatsSource interface {
+	List() []*container.Container
+	GetContainerStats(*container.Container) (*types.StatsJSON, error)
+}
+
+// containerMetricsCollector is a prometheus.Collector that reports resource
+// usage of every running container on each scrape. Series are labeled with
+// the container's ID, name, and image, and optionally with a configurable
+// set of container labels.
+type containerMetricsCollector struct {
+	src       containerStatsSource
+	labelKeys []string
+
+	cpuUsage     *prometheus.Desc
+	memUsage     *prometheus.Desc
+	memLimit     *prometheus.Desc
+	pids         *prometheus.Desc
+	blkioRead    *prometheus.Desc
+	blkioWrite   *prometheus.Desc
+	netRxBytes   *prometheus.Desc
+	netTxBytes   *prometheus.Desc
+	netRxPackets *prometheus.Desc
+	netTxPackets *prometheus.Desc
+	netRxDropped *prometheus.Desc
+	netTxDropped *prometheus.Desc
+}
+
+// registerContainerMetrics registers the per-container metrics collector with
+// the metrics endpoint.
+func registerContainerMetrics(src containerStatsSource, labelKeys []string) {
+	ns := metrics.NewNamespace("engine", "container", nil)
+	ns.Add(newContainerMetricsCollector(ns, src, labelKeys))
+	metrics.Register(ns)
+}
+
+func newContainerMetricsCollector(ns *metrics.Namespace, src containerStatsSource, labelKeys []string) *containerMetricsCollector {
+	labels := []string{"id", "name", "image"}
+	var keys []string
+	seen := map[string]string{}
+	for _, k := range labelKeys {
+		name := containerMetricsLabelName(k)
+		if other, ok := seen[name]; ok {
+			// the series of a collector must have distinct label names
+			logrus.Warnf("container label %q is not reported in container metrics, as it is reported as %q like container label %q", k, name, other)
+			continue
+		}
+		seen[name] = k
+		keys = append(keys, k)
+		labels = append(labels, name)
+	}
+	netLabels := append(append([]string{}, labels...), "interface")
+
+	return &containerMetricsCollector{
+		src:          src,
+		labelKeys:    keys,
+		cpuUsage:     ns.NewDesc("cpu_usage_seconds", "Total CPU time consumed by the container", metrics.Total, labels...),
+		memUsage:     ns.NewDesc("memory_usage", "Memory usage of the container", metrics.Bytes, labels...),
+		memLimit:     ns.NewDesc("memory_limit", "Memory limit of the container", metrics.Bytes, labels...),
+		pids:         ns.NewDesc("pids", "Number of processes running in the container", "", labels...),
+		blkioRead:    ns.NewDesc("blkio_read_bytes", "Bytes read from block devices by the container", metrics.Total, labels...),
+		blkioWrite:   ns.NewDesc("blkio_write_bytes", "Bytes written to block devices by the container", metrics.Total, labels...),
+		netRxBytes:   ns.NewDesc("network_receive_bytes", "Bytes received by the container", metrics.Total, netLabels...),
+		netTxBytes:   ns.NewDesc("network_transmit_bytes", "Bytes transmitted by the container", metrics.Total, netLabels...),
+		netRxPackets: ns.NewDesc("network_receive_packets", "Packets received by the container", metrics.Total, netLabels...),
+		netTxPackets: ns.NewDesc("network_transmit_packets", "Packets transmitted by the container", metrics.Total, netLabels...),
+		netRxDropped: ns.NewDesc("network_receive_dropped", "Received packets dropped by the container", metrics.Total, netLabels...),
+		netTxDropped: ns.NewDesc("network_transmit_dropped", "Transmitted packets dropped by the container", metrics.Total, netLabels...),
+	}
+}
+
+// containerMetricsLabelName converts a container label key to a valid
+// Prometheus label name, for example "com.example.team" becomes
+// "label_com_example_team".
+func containerMetricsLabelName(key string) string {
+	return "label_" + strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+			return r
+		}
+		return '_'
+	}, key)
+}
+
+func (c *containerMetricsCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range []*prometheus.Desc{
+		c.cpuUsage, c.memUsage, c.memLimit, c.pids, c.blkioRead, c.blkioWrite,
+		c.netRxBytes, c.netTxBytes, c.netRxPackets, c.netTxPackets, c.netRxDropped, c.netTxDropped,
+	} {
+		ch <- d
+	}
+}
+
+func (c *containerMetricsCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, ctr := range c.src.List() {
+		if !ctr.IsRunning() || ctr.IsRestarting() {
+			continue
+		}
+		s, err := c.src.GetContainerStats(ctr)
+		if err != nil {
+			logrus.WithError(err).WithField("container", ctr.ID).Debug("failed to collect container metrics")
+			continue
+		}
+		c.collectContainer(ch, ctr, s)
+	}
+}
+
+func (c *containerMetricsCollector) collectContainer(ch chan<- prometheus.Metric, ctr *container.Container, s *types.StatsJSON) {
+	labels := []string{ctr.ID, strings.TrimPrefix(ctr.Name, "/"), ctr.Config.Image}
+	for _, k := range c.labelKeys {
+		labels = append(labels, ctr.Config.Labels[k])
+	}
+
+	var read, write uint64
+	for _, e := range s.BlkioStats.IoServiceBytesRecursive {
+		switch strings.ToLower(e.Op) {
+		case "read":
+			read += e.Value
+		case "write":
+			write += e.Value
+		}
+	}
+	read += s.StorageStats.ReadSizeBytes
+	write += s.StorageStats.WriteSizeBytes
+
+	send := func(desc *prometheus.Desc, typ prometheus.ValueType, value float64, labels ...string) {
+		m, err := prometheus.NewConstMetric(desc, typ, value, labels...)
+		if err != nil {
+			logrus.WithError(err).WithField("container", ctr.ID).Debug("failed to collect container metric")
+			return
+		}
+		ch <- m
+	}
+
+	send(c.cpuUsage, prometheus.CounterValue, float64(s.CPUStats.CPUUsage.TotalUsage)/1e9, labels...)
+	send(c.memUsage, prometheus.GaugeValue, float64(s.MemoryStats.Usage), labels...)
+	send(c.memLimit, prometheus.GaugeValue, float64(s.MemoryStats.Limit), labels...)
+	send(c.pids, prometheus.GaugeValue, float64(s.PidsStats.Current), labels...)
+	send(c.blkioRead, prometheus.CounterValue, float64(read), labels...)
+	send(c.blkioWrite, prometheus.CounterValue, float64(write), labels...)
+
+	for iface, n := range s.Networks {
+		netLabels := append(append([]string{}, labels...), iface)
+		send(c.netRxBytes, prometheus.CounterValue, float64(n.RxBytes), netLabels...)
+		send(c.netTxBytes, prometheus.CounterValue, float64(n.TxBytes), netLabels...)
+		send(c.netRxPackets, prometheus.CounterValue, float64(n.RxPackets), netLabels...)
+		send(c.netTxPackets, prometheus.CounterValue, float64(n.TxPackets), netLabels...)
+		send(c.netRxDropped, prometheus.CounterValue, float64(n.RxDropped), netLabels...)
+		send(c.netTxDropped, prometheus.CounterValue, float64(n.TxDropped), netLabels...)
+	}
+}
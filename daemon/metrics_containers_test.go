@@ -0,0 +1,103 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/container"
+	metrics "github.com/docker/go-metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+type fakeStatsSource struct {
+	containers []*container.Container
+	stats      map[string]*types.StatsJSON
+}
+
+func (f *fakeStatsSource) List() []*container.Container {
+	return f.containers
+}
+
+func (f *fakeStatsSource) GetContainerStats(c *container.Container) (*types.StatsJSON, error) {
+	return f.stats[c.ID], nil
+}
+
+func TestContainerMetricsLabelName(t *testing.T) {
+	assert.Check(t, is.Equal(containerMetricsLabelName("com.example.team"), "label_com_example_team"))
+	assert.Check(t, is.Equal(containerMetricsLabelName("owner"), "label_owner"))
+}
+
+func TestContainerMetricsCollector(t *testing.T) {
+	running := &container.Container{
+		ID:    "running",
+		Name:  "/web",
+		State: container.NewState(),
+		Config: &containertypes.Config{
+			Image:  "nginx",
+			Labels: map[string]string{"com.example.team": "blue"},
+		},
+	}
+	running.State.Running = true
+	stopped := &container.Container{
+		ID:     "stopped",
+		Name:   "/db",
+		State:  container.NewState(),
+		Config: &containertypes.Config{Image: "postgres"},
+	}
+
+	s := &types.StatsJSON{}
+	s.CPUStats.CPUUsage.TotalUsage = 2e9
+	s.MemoryStats.Usage = 1024
+	s.BlkioStats.IoServiceBytesRecursive = []types.BlkioStatEntry{
+		{Op: "Read", Value: 10},
+		{Op: "read", Value: 5},
+		{Op: "Write", Value: 7},
+	}
+	s.Networks = map[string]types.NetworkStats{"eth0": {RxBytes: 100, TxBytes: 200}}
+
+	src := &fakeStatsSource{
+		containers: []*container.Container{running, stopped},
+		stats:      map[string]*types.StatsJSON{"running": s},
+	}
+	// "com_example.team" is reported with the same label name as
+	// "com.example.team", and is dropped.
+	c := newContainerMetricsCollector(metrics.NewNamespace("test", "container", nil), src, []string{"com.example.team", "com_example.team"})
+	assert.Check(t, is.DeepEqual(c.labelKeys, []string{"com.example.team"}))
+
+	ch := make(chan prometheus.Metric, 100)
+	c.Collect(ch)
+	close(ch)
+
+	values := map[string]float64{}
+	for m := range ch {
+		var out dto.Metric
+		assert.NilError(t, m.Write(&out))
+		labels := map[string]string{}
+		for _, l := range out.GetLabel() {
+			labels[l.GetName()] = l.GetValue()
+		}
+		assert.Check(t, is.Equal(labels["id"], "running"))
+		assert.Check(t, is.Equal(labels["name"], "web"))
+		assert.Check(t, is.Equal(labels["image"], "nginx"))
+		assert.Check(t, is.Equal(labels["label_com_example_team"], "blue"))
+
+		name := m.Desc().String()
+		switch {
+		case out.Counter != nil:
+			values[name] = out.GetCounter().GetValue()
+		case out.Gauge != nil:
+			values[name] = out.GetGauge().GetValue()
+		}
+	}
+	assert.Check(t, is.Len(values, 12))
+	assert.Check(t, is.Equal(values[c.cpuUsage.String()], 2.0))
+	assert.Check(t, is.Equal(values[c.memUsage.String()], 1024.0))
+	assert.Check(t, is.Equal(values[c.blkioRead.String()], 15.0))
+	assert.Check(t, is.Equal(values[c.blkioWrite.String()], 7.0))
+	assert.Check(t, is.Equal(values[c.netRxBytes.String()], 100.0))
+	assert.Check(t, is.Equal(values[c.netTxBytes.String()], 200.0))
+}
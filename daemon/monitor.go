@@ -174,6 +174,7 @@ func (daemon *Daemon) ProcessEvent(id string, e libcontainerdtypes.EventType, ei
 
 			execConfig.ExitCode = &ec
 			execConfig.Running = false
+			daemon.execRemoveCgroup(execConfig)
 
 			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 			execConfig.StreamConfig.Wait(ctx)
@@ -231,6 +232,7 @@ func (daemon *Daemon) ProcessEvent(id string, e libcontainerdtypes.EventType, ei
 			daemon.setStateCounter(c)
 
 			daemon.initHealthMonitor(c)
+			daemon.startLifetimeTimer(c)
 
 			if err := c.CheckpointTo(daemon.containersReplica); err != nil {
 				return err
@@ -8,6 +8,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/docker/docker/api/types"
 	containertypes "github.com/docker/docker/api/types/container"
@@ -248,10 +249,39 @@ func (daemon *Daemon) SetNetworkBootstrapKeys(keys []*networktypes.EncryptionKey
 	if err == nil {
 		// Upon successful key setting dispatch the keys available event
 		daemon.cluster.SendClusterEvent(lncluster.EventNetworkKeysAvailable)
+		daemon.trackDataPathKeys(keys, time.Now().UTC())
 	}
 	return err
 }
 
+// trackDataPathKeys records when the data path keys were rotated, which is
+// when a key with a later lamport time than the keys already set is
+// received. The keys received when the node joins the swarm, or when the
+// daemon starts, are not a rotation.
+func (daemon *Daemon) trackDataPathKeys(keys []*networktypes.EncryptionKey, now time.Time) {
+	var clock uint64
+	for _, k := range keys {
+		if k.LamportTime > clock {
+			clock = k.LamportTime
+		}
+	}
+	daemon.dataPathKeysMu.Lock()
+	defer daemon.dataPathKeysMu.Unlock()
+	if daemon.dataPathKeysClock != 0 && clock > daemon.dataPathKeysClock {
+		daemon.dataPathKeysRotatedAt = now
+	}
+	daemon.dataPathKeysClock = clock
+}
+
+// DataPathKeysRotatedAt returns the time the data path keys of the swarm were
+// last rotated while the node was part of it, or the zero time if they were
+// not.
+func (daemon *Daemon) DataPathKeysRotatedAt() time.Time {
+	daemon.dataPathKeysMu.Lock()
+	defer daemon.dataPathKeysMu.Unlock()
+	return daemon.dataPathKeysRotatedAt
+}
+
 // UpdateAttachment notifies the attacher about the attachment config.
 func (daemon *Daemon) UpdateAttachment(networkName, networkID, containerID string, config *network.NetworkingConfig) error {
 	if daemon.clusterProvider == nil {
@@ -655,6 +685,18 @@ func buildDetailedNetworkResources(r *types.NetworkResource, nw libnetwork.Netwo
 			LocalLBIndex: service.LocalLBIndex,
 		}
 	}
+	r.DriverState = buildDriverState(nw)
+}
+
+func buildDriverState(nw libnetwork.Network) *network.DriverState {
+	state, err := nw.Info().DriverState()
+	if err != nil {
+		return &network.DriverState{Driver: nw.Type(), State: state, Error: err.Error()}
+	}
+	if state == nil {
+		return nil
+	}
+	return &network.DriverState{Driver: nw.Type(), State: state}
 }
 
 func buildPeerInfoResources(peers []networkdb.PeerInfo) []network.PeerInfo {
@@ -827,10 +869,23 @@ func buildCreateEndpointOptions(c *container.Container, n libnetwork.Network, ep
 
 		for _, alias := range epConfig.Aliases {
 			createOptions = append(createOptions, libnetwork.CreateOptionMyAlias(alias))
+			if opts, ok := epConfig.AliasOptions[alias]; ok {
+				createOptions = append(createOptions, libnetwork.CreateOptionMyAliasOptions(alias, libnetwork.AliasOptions{
+					TTL:   opts.TTL,
+					Peers: opts.Peers,
+				}))
+			}
 		}
 		for k, v := range epConfig.DriverOpts {
 			createOptions = append(createOptions, libnetwork.EndpointOptionGeneric(options.Generic{k: v}))
 		}
+
+		// Without a healthcheck the container never becomes healthy, so its
+		// records are not held back.
+		if epConfig.HealthGatedDNS && getProbe(c) != nil {
+			healthy := c.State.Health != nil && c.State.Health.Status() == types.Healthy
+			createOptions = append(createOptions, libnetwork.CreateOptionHealthGated(healthy))
+		}
 	}
 
 	if c.NetworkSettings.Service != nil {
@@ -924,7 +979,7 @@ func buildCreateEndpointOptions(c *container.Container, n libnetwork.Network, ep
 			}
 			pbCopy.HostPort = uint16(portStart)
 			pbCopy.HostPortEnd = uint16(portEnd)
-			pbCopy.HostIP = net.ParseIP(binding[i].HostIP)
+			setPortBindingHost(&pbCopy, binding[i].HostIP)
 			pbList = append(pbList, pbCopy)
 		}
 
@@ -1012,6 +1067,17 @@ func getEndpointPortMapInfo(ep *libnetwork.Endpoint) (nat.PortMap, error) {
 	return pm, nil
 }
 
+// setPortBindingHost sets the host side of a port binding, which is either an
+// IP address or the name of a host interface to bind the port on the addresses
+// of.
+func setPortBindingHost(pb *networktypes.PortBinding, host string) {
+	if ip := net.ParseIP(host); ip != nil || host == "" {
+		pb.HostIP = ip
+		return
+	}
+	pb.HostIface = host
+}
+
 // buildEndpointInfo sets endpoint-related fields on container.NetworkSettings based on the provided network and endpoint.
 func buildEndpointInfo(networkSettings *internalnetwork.Settings, n libnetwork.Network, ep *libnetwork.Endpoint) error {
 	if ep == nil {
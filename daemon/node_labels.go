@@ -0,0 +1,244 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/pkg/platform"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// nodeLabelPrefix is the prefix of the engine labels populated from the
+	// facts of the host and the metadata of its cloud instance.
+	nodeLabelPrefix = "com.docker.node."
+
+	// nodeLabelsRefreshInterval is how often the node labels are refreshed.
+	nodeLabelsRefreshInterval = 10 * time.Minute
+
+	// metadataTimeout bounds each request to a cloud metadata service, so
+	// that hosts outside of a cloud are not slowed down.
+	metadataTimeout = 2 * time.Second
+)
+
+// Endpoints of the metadata services of the supported clouds. They are
+// variables so that they can be replaced in tests.
+var (
+	awsMetadataURL   = "http://169.254.169.254/latest"
+	gcpMetadataURL   = "http://metadata.google.internal/computeMetadata/v1/instance"
+	azureMetadataURL = "http://169.254.169.254/metadata/instance/compute?api-version=2021-02-01"
+)
+
+// metadataClient queries the metadata services of the clouds. They are
+// link-local services, so they're reached directly rather than through the
+// proxy configured in the environment of the daemon.
+var metadataClient = &http.Client{Transport: &http.Transport{Proxy: nil}}
+
+// numaNodesGlob matches the NUMA nodes of the host.
+const numaNodesGlob = "/sys/devices/system/node/node[0-9]*"
+
+// cloudInstance is the metadata of the cloud instance the host runs on.
+type cloudInstance struct {
+	provider, region, zone, instanceType string
+}
+
+// hostLabels returns the labels describing the facts of the host: its kernel
+// version, architecture, and number of NUMA nodes.
+func hostLabels() map[string]string {
+	labels := map[string]string{
+		nodeLabelPrefix + "kernel":       kernelVersion(),
+		nodeLabelPrefix + "architecture": platform.Architecture,
+	}
+	if nodes, _ := filepath.Glob(numaNodesGlob); len(nodes) > 0 {
+		labels[nodeLabelPrefix+"numa-nodes"] = strconv.Itoa(len(nodes))
+	}
+	return labels
+}
+
+// cloudLabels returns the labels describing the cloud instance the host runs
+// on, if any.
+func cloudLabels(ctx context.Context) map[string]string {
+	for _, detect := range []func(context.Context) (cloudInstance, error){awsInstance, gcpInstance, azureInstance} {
+		instance, err := detect(ctx)
+		if err != nil || instance.provider == "" {
+			continue
+		}
+		labels := map[string]string{nodeLabelPrefix + "cloud.provider": instance.provider}
+		for k, v := range map[string]string{
+			"cloud.region":        instance.region,
+			"cloud.zone":          instance.zone,
+			"cloud.instance-type": instance.instanceType,
+		} {
+			if v != "" {
+				labels[nodeLabelPrefix+k] = v
+			}
+		}
+		return labels
+	}
+	return nil
+}
+
+// refreshNodeLabels populates the node labels, and refreshes them every
+// nodeLabelsRefreshInterval until ctx is done.
+func (daemon *Daemon) refreshNodeLabels(ctx context.Context) {
+	ticker := time.NewTicker(nodeLabelsRefreshInterval)
+	defer ticker.Stop()
+	for {
+		reqCtx, cancel := context.WithTimeout(ctx, 4*metadataTimeout)
+		labels := hostLabels()
+		for k, v := range cloudLabels(reqCtx) {
+			labels[k] = v
+		}
+		cancel()
+
+		daemon.nodeLabelsMu.Lock()
+		daemon.nodeLabels = formatLabels(labels)
+		daemon.nodeLabelsMu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// stopNodeLabels stops refreshing the node labels.
+func (daemon *Daemon) stopNodeLabels() {
+	if daemon.nodeLabelsCancel != nil {
+		daemon.nodeLabelsCancel()
+	}
+}
+
+func formatLabels(labels map[string]string) []string {
+	out := make([]string, 0, len(labels))
+	for k, v := range labels {
+		out = append(out, k+"="+v)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func metadataGet(ctx context.Context, url string, header http.Header) (string, error) {
+	return metadataRequest(ctx, http.MethodGet, url, header)
+}
+
+func metadataRequest(ctx context.Context, method, url string, header http.Header) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, metadataTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header = header
+	resp, err := metadataClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %d from %s", resp.StatusCode, url)
+	}
+	return strings.TrimSpace(string(body)), nil
+}
+
+// awsInstance queries the EC2 instance metadata service, using IMDSv2.
+func awsInstance(ctx context.Context) (cloudInstance, error) {
+	token, err := metadataRequest(ctx, http.MethodPut, awsMetadataURL+"/api/token", http.Header{
+		"X-Aws-Ec2-Metadata-Token-Ttl-Seconds": {"60"},
+	})
+	if err != nil {
+		return cloudInstance{}, err
+	}
+	header := http.Header{"X-Aws-Ec2-Metadata-Token": {token}}
+	instance := cloudInstance{provider: "aws"}
+	for field, dst := range map[string]*string{
+		"placement/region":            &instance.region,
+		"placement/availability-zone": &instance.zone,
+		"instance-type":               &instance.instanceType,
+	} {
+		if *dst, err = metadataGet(ctx, awsMetadataURL+"/meta-data/"+field, header); err != nil {
+			logrus.WithError(err).Debugf("failed to get %s from the EC2 instance metadata", field)
+		}
+	}
+	return instance, nil
+}
+
+// gcpInstance queries the metadata server of Google Compute Engine.
+func gcpInstance(ctx context.Context) (cloudInstance, error) {
+	header := http.Header{"Metadata-Flavor": {"Google"}}
+	zone, err := metadataGet(ctx, gcpMetadataURL+"/zone", header)
+	if err != nil {
+		return cloudInstance{}, err
+	}
+	machineType, err := metadataGet(ctx, gcpMetadataURL+"/machine-type", header)
+	if err != nil {
+		logrus.WithError(err).Debug("failed to get the machine type from the GCE metadata")
+	}
+
+	// values are of the form "projects/<project>/zones/<zone>" and
+	// "projects/<project>/machineTypes/<type>".
+	instance := cloudInstance{
+		provider: "gcp",
+		zone:     path.Base(zone),
+	}
+	if machineType != "" {
+		instance.instanceType = path.Base(machineType)
+	}
+	if i := strings.LastIndex(instance.zone, "-"); i > 0 {
+		instance.region = instance.zone[:i]
+	}
+	return instance, nil
+}
+
+// azureInstance queries the Azure instance metadata service.
+func azureInstance(ctx context.Context) (cloudInstance, error) {
+	out, err := metadataGet(ctx, azureMetadataURL, http.Header{"Metadata": {"true"}})
+	if err != nil {
+		return cloudInstance{}, err
+	}
+	var compute struct {
+		Location string `json:"location"`
+		Zone     string `json:"zone"`
+		VMSize   string `json:"vmSize"`
+	}
+	if err := json.Unmarshal([]byte(out), &compute); err != nil {
+		return cloudInstance{}, err
+	}
+	instance := cloudInstance{
+		provider:     "azure",
+		region:       compute.Location,
+		instanceType: compute.VMSize,
+	}
+	if compute.Zone != "" {
+		instance.zone = compute.Location + "-" + compute.Zone
+	}
+	return instance, nil
+}
+
+// engineLabels returns the configured engine labels, the labels that describe
+// the attributes of discovered generic resources, and the node labels.
+func (daemon *Daemon) engineLabels() []string {
+	daemon.nodeLabelsMu.Lock()
+	defer daemon.nodeLabelsMu.Unlock()
+	if len(daemon.genericResourceLabels) == 0 && len(daemon.nodeLabels) == 0 {
+		return daemon.configStore.Labels
+	}
+	labels := make([]string, 0, len(daemon.configStore.Labels)+len(daemon.genericResourceLabels)+len(daemon.nodeLabels))
+	labels = append(labels, daemon.configStore.Labels...)
+	labels = append(labels, daemon.genericResourceLabels...)
+	return append(labels, daemon.nodeLabels...)
+}
@@ -0,0 +1,103 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/docker/docker/daemon/config"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestAWSInstance(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/latest/api/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.Write([]byte("token"))
+	})
+	for p, v := range map[string]string{
+		"/latest/meta-data/placement/region":            "eu-west-1",
+		"/latest/meta-data/placement/availability-zone": "eu-west-1b",
+		"/latest/meta-data/instance-type":               "m5.large",
+	} {
+		v := v
+		mux.HandleFunc(p, func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("X-Aws-Ec2-Metadata-Token") != "token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(v))
+		})
+	}
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	defer func(u string) { awsMetadataURL = u }(awsMetadataURL)
+	awsMetadataURL = server.URL + "/latest"
+
+	instance, err := awsInstance(context.Background())
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(instance, cloudInstance{provider: "aws", region: "eu-west-1", zone: "eu-west-1b", instanceType: "m5.large"}))
+}
+
+func TestGCPInstance(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata-Flavor") != "Google" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/zone":
+			w.Write([]byte("projects/1234/zones/us-central1-a"))
+		case "/machine-type":
+			w.Write([]byte("projects/1234/machineTypes/n1-standard-4"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	defer func(u string) { gcpMetadataURL = u }(gcpMetadataURL)
+	gcpMetadataURL = server.URL
+
+	instance, err := gcpInstance(context.Background())
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(instance, cloudInstance{provider: "gcp", region: "us-central1", zone: "us-central1-a", instanceType: "n1-standard-4"}))
+}
+
+func TestAzureInstance(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata") != "true" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"location":"westeurope","zone":"2","vmSize":"Standard_D2s_v3"}`))
+	}))
+	defer server.Close()
+
+	defer func(u string) { azureMetadataURL = u }(azureMetadataURL)
+	azureMetadataURL = server.URL
+
+	instance, err := azureInstance(context.Background())
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(instance, cloudInstance{provider: "azure", region: "westeurope", zone: "westeurope-2", instanceType: "Standard_D2s_v3"}))
+}
+
+func TestEngineLabels(t *testing.T) {
+	d := &Daemon{
+		configStore:           &config.Config{},
+		genericResourceLabels: []string{"com.docker.resource.gpu.0.model=T4"},
+		nodeLabels:            []string{"com.docker.node.kernel=5.15"},
+	}
+	d.configStore.Labels = []string{"foo=bar"}
+	assert.Check(t, is.DeepEqual(d.engineLabels(), []string{
+		"foo=bar",
+		"com.docker.resource.gpu.0.model=T4",
+		"com.docker.node.kernel=5.15",
+	}))
+}
@@ -0,0 +1,89 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/container"
+	"github.com/sirupsen/logrus"
+)
+
+// notifyReady is the notification of a container which is ready, as sent by
+// sd_notify.
+const notifyReady = "READY=1"
+
+// setNotifySocket registers the notify socket of a container, closing the
+// previous one if any.
+func (daemon *Daemon) setNotifySocket(id string, conn *net.UnixConn) {
+	daemon.notifySocketsMu.Lock()
+	defer daemon.notifySocketsMu.Unlock()
+	if daemon.notifySockets == nil {
+		daemon.notifySockets = make(map[string]*net.UnixConn)
+	}
+	if old, ok := daemon.notifySockets[id]; ok {
+		old.Close()
+	}
+	daemon.notifySockets[id] = conn
+}
+
+// closeNotifySocket stops receiving the notifications of a container.
+func (daemon *Daemon) closeNotifySocket(id string) {
+	daemon.notifySocketsMu.Lock()
+	defer daemon.notifySocketsMu.Unlock()
+	if conn, ok := daemon.notifySockets[id]; ok {
+		conn.Close()
+		delete(daemon.notifySockets, id)
+		if err := os.RemoveAll(filepath.Dir(conn.LocalAddr().String())); err != nil {
+			logrus.WithError(err).WithField("container", id).Warn("Failed to remove the notify socket")
+		}
+	}
+}
+
+// handleNotifications reads the notifications of a container until its
+// notify socket is closed.
+func (daemon *Daemon) handleNotifications(c *container.Container, conn *net.UnixConn) {
+	buf := make([]byte, 4096)
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			return
+		}
+		for _, line := range strings.Split(string(buf[:n]), "\n") {
+			if line == notifyReady {
+				daemon.setContainerReady(c)
+			}
+		}
+	}
+}
+
+// setContainerReady handles the readiness notification of a container. A
+// container without healthcheck becomes healthy.
+func (daemon *Daemon) setContainerReady(c *container.Container) {
+	c.Lock()
+	defer c.Unlock()
+
+	if !c.Running || getProbe(c) != nil {
+		return
+	}
+	h := c.State.Health
+	if h == nil || h.Status() == types.Healthy {
+		return
+	}
+	oldStatus := h.Status()
+	h.SetStatus(types.Healthy)
+	if err := c.CheckpointTo(daemon.containersReplica); err != nil {
+		logrus.WithError(err).WithField("container", c.ID).Error("Error replicating health state")
+	}
+	if daemon.netController != nil {
+		if sb := daemon.getNetworkSandbox(c); sb != nil {
+			sb.SetHealthy(true)
+		}
+	}
+	daemon.LogContainerEventWithAttributes(c, "health_status: "+types.Healthy, map[string]string{
+		"healthStatus":         types.Healthy,
+		"previousHealthStatus": oldStatus,
+	})
+}
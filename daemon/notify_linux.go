@@ -0,0 +1,75 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+
+	"github.com/containerd/containerd/containers"
+	coci "github.com/containerd/containerd/oci"
+	"github.com/docker/docker/container"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/pkg/errors"
+)
+
+const (
+	// notifySocketDir is where the directory of the notify socket of a
+	// container is mounted in the container.
+	notifySocketDir  = "/run/notify"
+	notifySocketName = "notify.sock"
+)
+
+// notifySocketPath returns the directory of the notify socket of a container
+// on the host.
+func (daemon *Daemon) notifySocketPath(id string) string {
+	return filepath.Join(daemon.configStore.GetExecRoot(), "notify", id)
+}
+
+// listenNotifySocket creates the notify socket of a container, and starts
+// handling its notifications.
+func (daemon *Daemon) listenNotifySocket(c *container.Container) error {
+	dir := daemon.notifySocketPath(c.ID)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	path := filepath.Join(dir, notifySocketName)
+	// Paths of unix sockets are limited to the size of sun_path.
+	if len(path) >= 108 {
+		return errors.Errorf("the path of the notify socket is too long: %s", path)
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		return errors.Wrap(err, "failed to create the notify socket")
+	}
+	// Processes of any user of the container can notify their readiness.
+	if err := os.Chmod(path, 0o666); err != nil {
+		conn.Close()
+		return err
+	}
+	daemon.setNotifySocket(c.ID, conn)
+	go daemon.handleNotifications(c, conn)
+	return nil
+}
+
+// WithNotifySocket provides a notify socket to the container, and sets
+// NOTIFY_SOCKET in its environment.
+func WithNotifySocket(daemon *Daemon, c *container.Container) coci.SpecOpts {
+	return func(ctx context.Context, _ coci.Client, _ *containers.Container, s *coci.Spec) error {
+		if err := daemon.listenNotifySocket(c); err != nil {
+			return err
+		}
+		s.Mounts = append(s.Mounts, specs.Mount{
+			Destination: notifySocketDir,
+			Type:        "bind",
+			Source:      daemon.notifySocketPath(c.ID),
+			Options:     []string{"rbind", "rw"},
+		})
+		s.Process.Env = append(s.Process.Env, fmt.Sprintf("NOTIFY_SOCKET=%s", filepath.Join(notifySocketDir, notifySocketName)))
+		return nil
+	}
+}
@@ -0,0 +1,62 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/daemon/config"
+	"github.com/docker/docker/daemon/events"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/poll"
+)
+
+func TestNotifyReady(t *testing.T) {
+	store, err := container.NewViewDB()
+	assert.NilError(t, err)
+	cfg := &config.Config{}
+	cfg.ExecRoot = t.TempDir()
+	daemon := &Daemon{
+		EventsService:     events.New(),
+		containersReplica: store,
+		configStore:       cfg,
+	}
+	c := &container.Container{
+		ID:         "container_id",
+		Name:       "container_name",
+		Config:     &containertypes.Config{Image: "image_name"},
+		HostConfig: &containertypes.HostConfig{NotifyReady: true},
+		State:      &container.State{Running: true},
+	}
+
+	daemon.initHealthMonitor(c)
+	assert.Assert(t, c.State.Health != nil)
+	assert.Check(t, is.Equal(c.State.Health.Status(), types.Starting))
+
+	assert.NilError(t, daemon.listenNotifySocket(c))
+	path := filepath.Join(daemon.notifySocketPath(c.ID), notifySocketName)
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: path, Net: "unixgram"})
+	assert.NilError(t, err)
+	defer conn.Close()
+
+	_, err = conn.Write([]byte("STATUS=starting\n"))
+	assert.NilError(t, err)
+	_, err = conn.Write([]byte("STATUS=up\nREADY=1\n"))
+	assert.NilError(t, err)
+
+	poll.WaitOn(t, func(poll.LogT) poll.Result {
+		if s := c.State.Health.Status(); s != types.Healthy {
+			return poll.Continue("health status is %s", s)
+		}
+		return poll.Success()
+	}, poll.WithDelay(10*time.Millisecond), poll.WithTimeout(5*time.Second))
+
+	daemon.closeNotifySocket(c.ID)
+	_, err = conn.Write([]byte("READY=1"))
+	assert.Check(t, err != nil)
+}
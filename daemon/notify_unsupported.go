@@ -0,0 +1,14 @@
+//go:build !linux
+// +build !linux
+
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"errors"
+
+	"github.com/docker/docker/container"
+)
+
+func (daemon *Daemon) listenNotifySocket(c *container.Container) error {
+	return errors.New("readiness notifications are not supported on this platform")
+}
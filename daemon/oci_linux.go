@@ -84,6 +84,40 @@ func WithLibnetwork(daemon *Daemon, c *container.Container) coci.SpecOpts {
 	}
 }
 
+// WithHooks adds the OCI lifecycle hooks of the container's HostConfig. The
+// hooks are checked again against the allowed hooks, which may have been
+// reloaded since the container was created.
+func WithHooks(daemon *Daemon, c *container.Container) coci.SpecOpts {
+	return func(ctx context.Context, _ coci.Client, _ *containers.Container, s *coci.Spec) error {
+		hooks := c.HostConfig.Hooks
+		if hooks == nil {
+			return nil
+		}
+		if err := verifyHooks(hooks, daemon.configStore.AllowedHooks); err != nil {
+			return err
+		}
+		if s.Hooks == nil {
+			s.Hooks = &specs.Hooks{}
+		}
+		s.Hooks.CreateRuntime = appendHooks(s.Hooks.CreateRuntime, hooks.CreateRuntime)
+		s.Hooks.Poststart = appendHooks(s.Hooks.Poststart, hooks.Poststart)
+		s.Hooks.Poststop = appendHooks(s.Hooks.Poststop, hooks.Poststop)
+		return nil
+	}
+}
+
+func appendHooks(specHooks []specs.Hook, hooks []containertypes.Hook) []specs.Hook {
+	for _, h := range hooks {
+		specHooks = append(specHooks, specs.Hook{
+			Path:    h.Path,
+			Args:    h.Args,
+			Env:     h.Env,
+			Timeout: h.Timeout,
+		})
+	}
+	return specHooks
+}
+
 // WithRootless sets the spec to the rootless configuration
 func WithRootless(daemon *Daemon) coci.SpecOpts {
 	return func(_ context.Context, _ coci.Client, _ *containers.Container, s *coci.Spec) error {
@@ -253,6 +287,12 @@ func WithNamespaces(daemon *Daemon, c *container.Container) coci.SpecOpts {
 					nsUser.Path = fmt.Sprintf("/proc/%d/ns/user", nc.State.GetPID())
 					setNamespace(s, nsUser)
 				}
+			} else if c.HostConfig.NetworkMode.IsGroup() {
+				g, err := daemon.groups.Get(c.HostConfig.NetworkMode.ConnectedGroup())
+				if err != nil {
+					return err
+				}
+				ns.Path = g.SandboxKey
 			} else if c.HostConfig.NetworkMode.IsHost() {
 				ns.Path = c.NetworkSettings.SandboxKey
 			}
@@ -264,7 +304,13 @@ func WithNamespaces(daemon *Daemon, c *container.Container) coci.SpecOpts {
 		if !ipcMode.Valid() {
 			return errdefs.InvalidParameter(errors.Errorf("invalid IPC mode: %v", ipcMode))
 		}
+		ipcGroupNS, err := daemon.groupNamespacePath(c, "ipc")
+		if err != nil {
+			return err
+		}
 		switch {
+		case ipcMode.IsShareable() && ipcGroupNS != "":
+			setNamespace(s, specs.LinuxNamespace{Type: "ipc", Path: ipcGroupNS})
 		case ipcMode.IsContainer():
 			ns := specs.LinuxNamespace{Type: "ipc"}
 			ic, err := daemon.getIpcContainer(ipcMode.Container())
@@ -294,6 +340,10 @@ func WithNamespaces(daemon *Daemon, c *container.Container) coci.SpecOpts {
 		if !c.HostConfig.PidMode.Valid() {
 			return errdefs.InvalidParameter(errors.Errorf("invalid PID mode: %v", c.HostConfig.PidMode))
 		}
+		pidGroupNS, err := daemon.groupNamespacePath(c, "pid")
+		if err != nil {
+			return err
+		}
 		if c.HostConfig.PidMode.IsContainer() {
 			pc, err := daemon.getPidContainer(c)
 			if err != nil {
@@ -312,6 +362,8 @@ func WithNamespaces(daemon *Daemon, c *container.Container) coci.SpecOpts {
 				}
 				setNamespace(s, nsUser)
 			}
+		} else if pidGroupNS != "" {
+			setNamespace(s, specs.LinuxNamespace{Type: "pid", Path: pidGroupNS})
 		} else if c.HostConfig.PidMode.IsHost() {
 			oci.RemoveNamespace(s, "pid")
 		} else {
@@ -489,6 +541,55 @@ func inSlice(slice []string, s string) bool {
 	return false
 }
 
+// extendedTmpfsOptions are tmpfs options that are supported by the kernel,
+// but are not accepted by mount.MergeTmpfsOptions.
+var extendedTmpfsOptions = map[string]bool{
+	"huge":   true,
+	"noswap": true,
+}
+
+// mergeTmpfsOptions merges the tmpfs options like mount.MergeTmpfsOptions,
+// and adds the options in extendedTmpfsOptions. Later options override
+// earlier ones.
+func mergeTmpfsOptions(options []string) ([]string, error) {
+	var (
+		rest     []string
+		extended []string
+		index    = make(map[string]int)
+	)
+	for _, o := range options {
+		key, _, _ := strings.Cut(o, "=")
+		if !extendedTmpfsOptions[key] {
+			rest = append(rest, o)
+			continue
+		}
+		if i, ok := index[key]; ok {
+			extended[i] = o
+			continue
+		}
+		index[key] = len(extended)
+		extended = append(extended, o)
+	}
+	merged, err := mount.MergeTmpfsOptions(rest)
+	if err != nil {
+		return nil, err
+	}
+	return append(merged, extended...), nil
+}
+
+// idmapMountOption returns the mount option for an idmapped bind mount. No
+// mappings are set on the mount, so that the runtime maps it with the user
+// namespace of the container, which the container must therefore have.
+func idmapMountOption(m container.Mount, userNS bool) (string, error) {
+	if !userNS {
+		return "", errdefs.InvalidParameter(errors.Errorf("idmapped bind mount %s requires the daemon to run with user namespace remapping (--userns-remap), and the container not to use the host user namespace", m.Destination))
+	}
+	if m.NonRecursive {
+		return "idmap", nil
+	}
+	return "ridmap", nil
+}
+
 // WithMounts sets the container's mounts
 func WithMounts(daemon *Daemon, c *container.Container) coci.SpecOpts {
 	return func(ctx context.Context, _ coci.Client, _ *containers.Container, s *coci.Spec) (err error) {
@@ -581,7 +682,7 @@ func WithMounts(daemon *Daemon, c *container.Container) coci.SpecOpts {
 					options = append(options, strings.Split(data, ",")...)
 				}
 
-				merged, err := mount.MergeTmpfsOptions(options)
+				merged, err := mergeTmpfsOptions(options)
 				if err != nil {
 					return err
 				}
@@ -651,6 +752,14 @@ func WithMounts(daemon *Daemon, c *container.Container) coci.SpecOpts {
 			if pFlag != 0 {
 				opts = append(opts, mountPropagationReverseMap[pFlag])
 			}
+			if m.IDMap {
+				userNS := c.HostConfig.UsernsMode.IsPrivate() && daemon.idMapping.UIDMaps != nil
+				idmapOpt, err := idmapMountOption(m, userNS)
+				if err != nil {
+					return err
+				}
+				opts = append(opts, idmapOpt)
+			}
 
 			// If we are using user namespaces, then we must make sure that we
 			// don't drop any of the CL_UNPRIVILEGED "locked" flags of the source
@@ -1024,10 +1133,14 @@ func (daemon *Daemon) createSpec(ctx context.Context, c *container.Container) (r
 		WithSeccomp(daemon, c),
 		WithMounts(daemon, c),
 		WithLibnetwork(daemon, c),
+		WithHooks(daemon, c),
 		WithApparmor(c),
 		WithSelinux(c),
 		WithOOMScore(&c.HostConfig.OomScoreAdj),
 	)
+	if c.HostConfig.NotifyReady {
+		opts = append(opts, WithNotifySocket(daemon, c))
+	}
 	if c.NoNewPrivileges {
 		opts = append(opts, coci.WithNoNewPrivileges)
 	}
@@ -10,7 +10,9 @@ import (
 	"github.com/docker/docker/container"
 	"github.com/docker/docker/daemon/config"
 	"github.com/docker/docker/daemon/network"
+	"github.com/docker/docker/errdefs"
 	"github.com/docker/docker/libnetwork"
+	"github.com/opencontainers/runtime-spec/specs-go"
 	"gotest.tools/v3/assert"
 	is "gotest.tools/v3/assert/cmp"
 	"gotest.tools/v3/skip"
@@ -78,6 +80,61 @@ func TestTmpfsDevShmNoDupMount(t *testing.T) {
 	assert.Check(t, err)
 }
 
+func TestMergeTmpfsOptions(t *testing.T) {
+	merged, err := mergeTmpfsOptions([]string{"noexec", "size=1g", "huge=always", "noswap", "size=2g", "huge=within_size"})
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(merged, []string{"noexec", "size=2g", "huge=within_size", "noswap"}))
+
+	_, err = mergeTmpfsOptions([]string{"bogus=1"})
+	assert.Check(t, is.ErrorContains(err, "invalid tmpfs option"))
+}
+
+func TestWithHooks(t *testing.T) {
+	timeout := 5
+	d := &Daemon{configStore: &config.Config{}}
+	d.configStore.AllowedHooks = []string{"/usr/libexec/device-setup"}
+	c := &container.Container{HostConfig: &containertypes.HostConfig{
+		Hooks: &containertypes.Hooks{
+			CreateRuntime: []containertypes.Hook{{Path: "/usr/libexec/device-setup", Args: []string{"device-setup", "attach"}, Timeout: &timeout}},
+			Poststop:      []containertypes.Hook{{Path: "/usr/libexec/device-setup", Args: []string{"device-setup", "detach"}, Env: []string{"HOOK_DEBUG=1"}}},
+		},
+	}}
+
+	var s specs.Spec
+	assert.NilError(t, WithHooks(d, c)(context.Background(), nil, nil, &s))
+	assert.Check(t, is.DeepEqual(s.Hooks, &specs.Hooks{
+		CreateRuntime: []specs.Hook{{Path: "/usr/libexec/device-setup", Args: []string{"device-setup", "attach"}, Timeout: &timeout}},
+		Poststop:      []specs.Hook{{Path: "/usr/libexec/device-setup", Args: []string{"device-setup", "detach"}, Env: []string{"HOOK_DEBUG=1"}}},
+	}))
+
+	// The hook is no longer allowed after a reload of the configuration.
+	d.configStore.AllowedHooks = nil
+	err := WithHooks(d, c)(context.Background(), nil, nil, &specs.Spec{})
+	assert.Check(t, errdefs.IsInvalidParameter(err), err)
+
+	// Only variables prefixed with HOOK_ can be set.
+	d.configStore.AllowedHooks = []string{"/usr/libexec/device-setup"}
+	for _, env := range []string{"LD_PRELOAD=/tmp/evil.so", "BASH_ENV=/tmp/evil.sh", "PYTHONPATH=/tmp", "HOOK_"} {
+		c.HostConfig.Hooks.Poststop[0].Env = []string{"HOOK_DEBUG=1", env}
+		err = WithHooks(d, c)(context.Background(), nil, nil, &specs.Spec{})
+		assert.Check(t, errdefs.IsInvalidParameter(err), env)
+	}
+}
+
+func TestIDMapMountOption(t *testing.T) {
+	opt, err := idmapMountOption(container.Mount{Destination: "/data", IDMap: true}, true)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(opt, "ridmap"))
+
+	opt, err = idmapMountOption(container.Mount{Destination: "/data", IDMap: true, NonRecursive: true}, true)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(opt, "idmap"))
+
+	_, err = idmapMountOption(container.Mount{Destination: "/data", IDMap: true}, false)
+	assert.Check(t, errdefs.IsInvalidParameter(err), err)
+	assert.Check(t, is.ErrorContains(err, "--userns-remap"))
+}
+
 // TestIpcPrivateVsReadonly checks that in case of IpcMode: private
 // and ReadonlyRootfs: true (as in "docker run --ipc private --read-only")
 // the resulting /dev/shm mount is NOT made read-only.
@@ -12,6 +12,7 @@ import (
 	"github.com/docker/docker/api/types/events"
 	"github.com/docker/docker/api/types/filters"
 	timetypes "github.com/docker/docker/api/types/time"
+	"github.com/docker/docker/container"
 	"github.com/docker/docker/errdefs"
 	"github.com/docker/docker/libnetwork"
 	"github.com/docker/docker/runconfig"
@@ -33,6 +34,7 @@ var (
 	networksAcceptedFilters = map[string]bool{
 		"label":  true,
 		"label!": true,
+		"since":  true,
 		"until":  true,
 	}
 )
@@ -66,13 +68,7 @@ func (daemon *Daemon) ContainersPrune(ctx context.Context, pruneFilters filters.
 		default:
 		}
 
-		if !c.IsRunning() {
-			if !until.IsZero() && c.Created.After(until) {
-				continue
-			}
-			if !matchLabels(pruneFilters, c.Config.Labels) {
-				continue
-			}
+		if prunableContainer(c, until, pruneFilters) {
 			cSize, _ := daemon.imageService.GetContainerLayerSize(c.ID)
 			// TODO: sets RmLink to true?
 			err := daemon.ContainerRm(c.ID, &types.ContainerRmConfig{})
@@ -92,11 +88,23 @@ func (daemon *Daemon) ContainersPrune(ctx context.Context, pruneFilters filters.
 	return rep, nil
 }
 
+// prunableContainer returns whether a container is stopped, and matches the
+// prune filters.
+func prunableContainer(c *container.Container, until time.Time, pruneFilters filters.Args) bool {
+	if c.IsRunning() {
+		return false
+	}
+	if !until.IsZero() && c.Created.After(until) {
+		return false
+	}
+	return matchLabels(pruneFilters, c.Config.Labels)
+}
+
 // localNetworksPrune removes unused local networks
 func (daemon *Daemon) localNetworksPrune(ctx context.Context, pruneFilters filters.Args) *types.NetworksPruneReport {
 	rep := &types.NetworksPruneReport{}
 
-	until, _ := getUntilFromPruneFilters(pruneFilters)
+	created, _ := getNetworkCreatedFromPruneFilters(pruneFilters)
 
 	// When the function returns true, the walk will stop.
 	l := func(nw libnetwork.Network) bool {
@@ -106,22 +114,10 @@ func (daemon *Daemon) localNetworksPrune(ctx context.Context, pruneFilters filte
 			return true
 		default:
 		}
-		if nw.Info().ConfigOnly() {
-			return false
-		}
-		if !until.IsZero() && nw.Info().Created().After(until) {
-			return false
-		}
-		if !matchLabels(pruneFilters, nw.Info().Labels()) {
+		if !prunableLocalNetwork(nw, created, pruneFilters) {
 			return false
 		}
 		nwName := nw.Name()
-		if runconfig.IsPreDefinedNetwork(nwName) {
-			return false
-		}
-		if len(nw.Endpoints()) > 0 {
-			return false
-		}
 		if err := daemon.DeleteNetwork(nw.ID()); err != nil {
 			logrus.Warnf("could not remove local network %s: %v", nwName, err)
 			return false
@@ -133,11 +129,29 @@ func (daemon *Daemon) localNetworksPrune(ctx context.Context, pruneFilters filte
 	return rep
 }
 
+// prunableLocalNetwork returns whether a local network is unused, and
+// matches the prune filters.
+func prunableLocalNetwork(nw libnetwork.Network, created networkCreated, pruneFilters filters.Args) bool {
+	if nw.Info().ConfigOnly() {
+		return false
+	}
+	if !created.match(nw.Info().Created()) {
+		return false
+	}
+	if !matchLabels(pruneFilters, nw.Info().Labels()) {
+		return false
+	}
+	if runconfig.IsPreDefinedNetwork(nw.Name()) {
+		return false
+	}
+	return len(nw.Endpoints()) == 0
+}
+
 // clusterNetworksPrune removes unused cluster networks
 func (daemon *Daemon) clusterNetworksPrune(ctx context.Context, pruneFilters filters.Args) (*types.NetworksPruneReport, error) {
 	rep := &types.NetworksPruneReport{}
 
-	until, _ := getUntilFromPruneFilters(pruneFilters)
+	created, _ := getNetworkCreatedFromPruneFilters(pruneFilters)
 
 	cluster := daemon.GetCluster()
 
@@ -159,7 +173,7 @@ func (daemon *Daemon) clusterNetworksPrune(ctx context.Context, pruneFilters fil
 				// Routing-mesh network removal has to be explicitly invoked by user
 				continue
 			}
-			if !until.IsZero() && nw.Created.After(until) {
+			if !created.match(nw.Created) {
 				continue
 			}
 			if !matchLabels(pruneFilters, nw.Labels) {
@@ -196,7 +210,7 @@ func (daemon *Daemon) NetworksPrune(ctx context.Context, pruneFilters filters.Ar
 		return nil, err
 	}
 
-	if _, err := getUntilFromPruneFilters(pruneFilters); err != nil {
+	if _, err := getNetworkCreatedFromPruneFilters(pruneFilters); err != nil {
 		return nil, err
 	}
 
@@ -221,24 +235,63 @@ func (daemon *Daemon) NetworksPrune(ctx context.Context, pruneFilters filters.Ar
 }
 
 func getUntilFromPruneFilters(pruneFilters filters.Args) (time.Time, error) {
-	until := time.Time{}
-	if !pruneFilters.Contains("until") {
-		return until, nil
+	return getTimeFromPruneFilters(pruneFilters, "until")
+}
+
+// getTimeFromPruneFilters returns the time given with a filter, such as
+// "until", or the zero time if the filter is not set.
+func getTimeFromPruneFilters(pruneFilters filters.Args, name string) (time.Time, error) {
+	t := time.Time{}
+	if !pruneFilters.Contains(name) {
+		return t, nil
 	}
-	untilFilters := pruneFilters.Get("until")
-	if len(untilFilters) > 1 {
-		return until, fmt.Errorf("more than one until filter specified")
+	timeFilters := pruneFilters.Get(name)
+	if len(timeFilters) > 1 {
+		return t, fmt.Errorf("more than one %s filter specified", name)
 	}
-	ts, err := timetypes.GetTimestamp(untilFilters[0], time.Now())
+	ts, err := timetypes.GetTimestamp(timeFilters[0], time.Now())
 	if err != nil {
-		return until, err
+		return t, err
 	}
 	seconds, nanoseconds, err := timetypes.ParseTimestamps(ts, 0)
 	if err != nil {
-		return until, err
+		return t, err
 	}
-	until = time.Unix(seconds, nanoseconds)
-	return until, nil
+	t = time.Unix(seconds, nanoseconds)
+	return t, nil
+}
+
+// networkCreated is the range of creation times of the networks to prune,
+// given with the "since" and "until" filters.
+type networkCreated struct {
+	since, until time.Time
+}
+
+func getNetworkCreatedFromPruneFilters(pruneFilters filters.Args) (networkCreated, error) {
+	var (
+		c   networkCreated
+		err error
+	)
+	if c.since, err = getTimeFromPruneFilters(pruneFilters, "since"); err != nil {
+		return c, err
+	}
+	if c.until, err = getTimeFromPruneFilters(pruneFilters, "until"); err != nil {
+		return c, err
+	}
+	return c, nil
+}
+
+// match returns whether a network created at the given time is in the range.
+// Networks created by daemons which did not store the creation time have a
+// zero creation time, so they are matched by "until", but never by "since".
+func (c networkCreated) match(created time.Time) bool {
+	if !c.until.IsZero() && created.After(c.until) {
+		return false
+	}
+	if !c.since.IsZero() && !created.After(c.since) {
+		return false
+	}
+	return true
 }
 
 func matchLabels(pruneFilters filters.Args, labels map[string]string) bool {
@@ -0,0 +1,136 @@
+// Package prune runs the periodic pruning of unused objects configured for
+// the daemon.
+package prune // import "github.com/docker/docker/daemon/prune"
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types/filters"
+	"github.com/sirupsen/logrus"
+)
+
+// Report is the outcome of pruning objects of a type.
+type Report struct {
+	// Deleted lists the objects that were removed, or that would be
+	// removed on a dry run.
+	Deleted []string
+	// SpaceReclaimed is the disk space that was freed, or that would be
+	// freed on a dry run, in bytes.
+	SpaceReclaimed uint64
+}
+
+// Pruner removes unused objects of a type.
+type Pruner interface {
+	// Prune removes the unused objects matching the filters.
+	Prune(ctx context.Context, pruneFilters filters.Args) (Report, error)
+	// Preview returns the unused objects matching the filters, which Prune
+	// would remove, without removing them.
+	Preview(ctx context.Context, pruneFilters filters.Args) (Report, error)
+}
+
+// Schedule is the periodic pruning of unused objects of a type.
+type Schedule struct {
+	// Name identifies the schedule.
+	Name string
+	// Type is the type of the objects to prune, which selects the Pruner.
+	Type string
+	// Interval is the time between two runs.
+	Interval time.Duration
+	// Jitter is the maximum random delay added to the interval.
+	Jitter time.Duration
+	// Filters limits the objects that are pruned.
+	Filters filters.Args
+	// DryRun previews the objects that would be pruned, without removing
+	// them.
+	DryRun bool
+}
+
+// Result is the result of a run of a schedule.
+type Result struct {
+	Schedule Schedule
+	Report   Report
+	Err      error
+}
+
+// Scheduler runs prune schedules until it is stopped.
+type Scheduler struct {
+	pruners map[string]Pruner
+	notify  func(Result)
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// NewScheduler returns a Scheduler that prunes objects with the pruner of
+// their type, and passes the result of every run to notify.
+func NewScheduler(pruners map[string]Pruner, notify func(Result)) *Scheduler {
+	return &Scheduler{
+		pruners: pruners,
+		notify:  notify,
+	}
+}
+
+// Start starts running the schedules. Schedules for a type that has no
+// pruner are skipped.
+func (s *Scheduler) Start(schedules []Schedule) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	for _, sc := range schedules {
+		p, ok := s.pruners[sc.Type]
+		if !ok {
+			logrus.WithField("schedule", sc.Name).Warnf("ignoring prune schedule: pruning %s is not supported by this daemon", sc.Type)
+			continue
+		}
+		s.wg.Add(1)
+		go s.run(ctx, sc, p)
+	}
+}
+
+// Stop stops running the schedules, and cancels the runs in progress.
+func (s *Scheduler) Stop() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	s.wg.Wait()
+}
+
+func (s *Scheduler) run(ctx context.Context, sc Schedule, p Pruner) {
+	defer s.wg.Done()
+	for {
+		t := time.NewTimer(nextDelay(sc))
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return
+		case <-t.C:
+		}
+		res := Run(ctx, sc, p)
+		if ctx.Err() != nil {
+			return
+		}
+		s.notify(res)
+	}
+}
+
+// Run runs a schedule once with the given pruner.
+func Run(ctx context.Context, sc Schedule, p Pruner) Result {
+	res := Result{Schedule: sc}
+	if sc.DryRun {
+		res.Report, res.Err = p.Preview(ctx, sc.Filters)
+	} else {
+		res.Report, res.Err = p.Prune(ctx, sc.Filters)
+	}
+	return res
+}
+
+// nextDelay returns the time until the next run of a schedule: its interval
+// plus a random delay of up to its jitter.
+func nextDelay(sc Schedule) time.Duration {
+	if sc.Jitter <= 0 {
+		return sc.Interval
+	}
+	return sc.Interval + time.Duration(rand.Int63n(int64(sc.Jitter)))
+}
@@ -0,0 +1,74 @@
+package prune // import "github.com/docker/docker/daemon/prune"
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/filters"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+type fakePruner struct {
+	pruned, previewed int
+}
+
+func (p *fakePruner) Prune(ctx context.Context, pruneFilters filters.Args) (Report, error) {
+	p.pruned++
+	if pruneFilters.Contains("fail") {
+		return Report{}, errors.New("prune failed")
+	}
+	return Report{Deleted: []string{"a", "b"}, SpaceReclaimed: 42}, nil
+}
+
+func (p *fakePruner) Preview(ctx context.Context, pruneFilters filters.Args) (Report, error) {
+	p.previewed++
+	return Report{Deleted: []string{"a"}, SpaceReclaimed: 10}, nil
+}
+
+func TestRun(t *testing.T) {
+	p := &fakePruner{}
+	res := Run(context.Background(), Schedule{Name: "images", Filters: filters.NewArgs()}, p)
+	assert.NilError(t, res.Err)
+	assert.Check(t, is.DeepEqual(res.Report, Report{Deleted: []string{"a", "b"}, SpaceReclaimed: 42}))
+
+	res = Run(context.Background(), Schedule{Name: "images", Filters: filters.NewArgs(), DryRun: true}, p)
+	assert.NilError(t, res.Err)
+	assert.Check(t, is.Equal(res.Report.SpaceReclaimed, uint64(10)))
+	assert.Check(t, is.Equal(p.pruned, 1))
+	assert.Check(t, is.Equal(p.previewed, 1))
+
+	res = Run(context.Background(), Schedule{Name: "images", Filters: filters.NewArgs(filters.Arg("fail", "1"))}, p)
+	assert.Check(t, is.Error(res.Err, "prune failed"))
+}
+
+func TestNextDelay(t *testing.T) {
+	sc := Schedule{Interval: time.Hour}
+	assert.Check(t, is.Equal(nextDelay(sc), time.Hour))
+
+	sc.Jitter = 10 * time.Minute
+	for i := 0; i < 100; i++ {
+		d := nextDelay(sc)
+		assert.Assert(t, d >= time.Hour && d < time.Hour+10*time.Minute, "unexpected delay %s", d)
+	}
+}
+
+func TestScheduler(t *testing.T) {
+	results := make(chan Result, 10)
+	s := NewScheduler(map[string]Pruner{"images": &fakePruner{}}, func(r Result) { results <- r })
+	s.Start([]Schedule{
+		{Name: "images", Type: "images", Interval: 10 * time.Millisecond, Filters: filters.NewArgs()},
+		{Name: "volumes", Type: "volumes", Interval: 10 * time.Millisecond, Filters: filters.NewArgs()},
+	})
+	defer s.Stop()
+
+	select {
+	case r := <-results:
+		assert.Check(t, is.Equal(r.Schedule.Name, "images"))
+		assert.Check(t, is.Len(r.Report.Deleted, 2))
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for the schedule to run")
+	}
+}
@@ -0,0 +1,259 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/daemon/config"
+	"github.com/docker/docker/daemon/prune"
+	"github.com/docker/docker/libnetwork"
+	"github.com/sirupsen/logrus"
+)
+
+// BuildCachePruner prunes the build cache of the builder embedded in the
+// daemon.
+type BuildCachePruner interface {
+	DiskUsage(ctx context.Context) ([]*types.BuildCache, error)
+	Prune(ctx context.Context, opts types.BuildCachePruneOptions) (int64, []string, error)
+}
+
+// StartPruneSchedules starts pruning unused objects on the schedules of the
+// configuration. buildCache is nil if the daemon has no BuildKit builder, in
+// which case the schedules of the build cache are skipped.
+func (daemon *Daemon) StartPruneSchedules(buildCache BuildCachePruner) {
+	if len(daemon.configStore.PruneSchedules) == 0 {
+		return
+	}
+	pruners := map[string]prune.Pruner{
+		config.PruneTypeContainers: containerPruner{daemon},
+		config.PruneTypeImages:     imagePruner{daemon},
+		config.PruneTypeNetworks:   networkPruner{daemon},
+	}
+	if buildCache != nil {
+		pruners[config.PruneTypeBuildCache] = buildCachePruner{buildCache}
+	}
+
+	var schedules []prune.Schedule
+	for _, c := range daemon.configStore.PruneSchedules {
+		schedules = append(schedules, prune.Schedule{
+			Name:     c.GetName(),
+			Type:     c.Type,
+			Interval: c.GetInterval(),
+			Jitter:   c.GetJitter(),
+			Filters:  c.GetFilters(),
+			DryRun:   c.DryRun,
+		})
+	}
+	daemon.pruneScheduler = prune.NewScheduler(pruners, daemon.logPruneResult)
+	daemon.pruneScheduler.Start(schedules)
+}
+
+// stopPruneSchedules stops pruning unused objects on schedules.
+func (daemon *Daemon) stopPruneSchedules() {
+	if daemon.pruneScheduler != nil {
+		daemon.pruneScheduler.Stop()
+	}
+}
+
+// logPruneResult logs the result of a run of a prune schedule, and emits a
+// "prune" daemon event with its outcome.
+func (daemon *Daemon) logPruneResult(res prune.Result) {
+	attributes := map[string]string{
+		"schedule":  res.Schedule.Name,
+		"type":      res.Schedule.Type,
+		"dryRun":    strconv.FormatBool(res.Schedule.DryRun),
+		"deleted":   strconv.Itoa(len(res.Report.Deleted)),
+		"reclaimed": strconv.FormatUint(res.Report.SpaceReclaimed, 10),
+	}
+	l := logrus.WithFields(logrus.Fields{
+		"schedule":  res.Schedule.Name,
+		"dry-run":   res.Schedule.DryRun,
+		"deleted":   res.Report.Deleted,
+		"reclaimed": res.Report.SpaceReclaimed,
+	})
+	if res.Err != nil {
+		attributes["error"] = res.Err.Error()
+		l.WithError(res.Err).Warn("scheduled prune failed")
+	} else {
+		l.Info("scheduled prune completed")
+	}
+	daemon.LogDaemonEventWithAttributes("prune", attributes)
+}
+
+type containerPruner struct {
+	daemon *Daemon
+}
+
+func (p containerPruner) Prune(ctx context.Context, pruneFilters filters.Args) (prune.Report, error) {
+	rep, err := p.daemon.ContainersPrune(ctx, pruneFilters)
+	if err != nil {
+		return prune.Report{}, err
+	}
+	return prune.Report{Deleted: rep.ContainersDeleted, SpaceReclaimed: rep.SpaceReclaimed}, nil
+}
+
+func (p containerPruner) Preview(ctx context.Context, pruneFilters filters.Args) (prune.Report, error) {
+	var rep prune.Report
+	if err := pruneFilters.Validate(containersAcceptedFilters); err != nil {
+		return rep, err
+	}
+	until, err := getUntilFromPruneFilters(pruneFilters)
+	if err != nil {
+		return rep, err
+	}
+	for _, c := range p.daemon.List() {
+		if ctx.Err() != nil {
+			return rep, ctx.Err()
+		}
+		if prunableContainer(c, until, pruneFilters) {
+			if cSize, _ := p.daemon.imageService.GetContainerLayerSize(c.ID); cSize > 0 {
+				rep.SpaceReclaimed += uint64(cSize)
+			}
+			rep.Deleted = append(rep.Deleted, c.ID)
+		}
+	}
+	return rep, nil
+}
+
+type imagePruner struct {
+	daemon *Daemon
+}
+
+func (p imagePruner) Prune(ctx context.Context, pruneFilters filters.Args) (prune.Report, error) {
+	rep, err := p.daemon.imageService.ImagesPrune(ctx, pruneFilters)
+	if err != nil {
+		return prune.Report{}, err
+	}
+	res := prune.Report{SpaceReclaimed: rep.SpaceReclaimed}
+	for _, item := range rep.ImagesDeleted {
+		if item.Deleted != "" {
+			res.Deleted = append(res.Deleted, item.Deleted)
+		}
+	}
+	return res, nil
+}
+
+// Preview returns the images that are not used by any container, and match
+// the filters. The space reclaimed is estimated from the size of the images
+// that isn't shared with other images.
+func (p imagePruner) Preview(ctx context.Context, pruneFilters filters.Args) (prune.Report, error) {
+	var rep prune.Report
+	until, err := getUntilFromPruneFilters(pruneFilters)
+	if err != nil {
+		return rep, err
+	}
+	listFilters := filters.NewArgs(filters.Arg("dangling", "true"))
+	if pruneFilters.ExactMatch("dangling", "false") || pruneFilters.ExactMatch("dangling", "0") {
+		listFilters = filters.NewArgs()
+	}
+	imgs, err := p.daemon.imageService.Images(ctx, types.ImageListOptions{
+		Filters:        listFilters,
+		SharedSize:     true,
+		ContainerCount: true,
+	})
+	if err != nil {
+		return rep, err
+	}
+	for _, img := range imgs {
+		if img.Containers > 0 {
+			continue
+		}
+		if !until.IsZero() && time.Unix(img.Created, 0).After(until) {
+			continue
+		}
+		if !matchLabels(pruneFilters, img.Labels) {
+			continue
+		}
+		size := img.Size
+		if img.SharedSize > 0 {
+			size -= img.SharedSize
+		}
+		if size > 0 {
+			rep.SpaceReclaimed += uint64(size)
+		}
+		rep.Deleted = append(rep.Deleted, img.ID)
+	}
+	return rep, nil
+}
+
+type networkPruner struct {
+	daemon *Daemon
+}
+
+func (p networkPruner) Prune(ctx context.Context, pruneFilters filters.Args) (prune.Report, error) {
+	rep, err := p.daemon.NetworksPrune(ctx, pruneFilters)
+	if err != nil {
+		return prune.Report{}, err
+	}
+	return prune.Report{Deleted: rep.NetworksDeleted}, nil
+}
+
+// Preview returns the local networks that have no endpoints, and match the
+// filters. Whether cluster networks are in use is only known when removing
+// them, so they are not included.
+func (p networkPruner) Preview(ctx context.Context, pruneFilters filters.Args) (prune.Report, error) {
+	var rep prune.Report
+	if err := pruneFilters.Validate(networksAcceptedFilters); err != nil {
+		return rep, err
+	}
+	created, err := getNetworkCreatedFromPruneFilters(pruneFilters)
+	if err != nil {
+		return rep, err
+	}
+	p.daemon.netController.WalkNetworks(func(nw libnetwork.Network) bool {
+		if ctx.Err() != nil {
+			return true
+		}
+		if prunableLocalNetwork(nw, created, pruneFilters) {
+			rep.Deleted = append(rep.Deleted, nw.Name())
+		}
+		return false
+	})
+	return rep, ctx.Err()
+}
+
+type buildCachePruner struct {
+	builder BuildCachePruner
+}
+
+func (p buildCachePruner) Prune(ctx context.Context, pruneFilters filters.Args) (prune.Report, error) {
+	reclaimed, deleted, err := p.builder.Prune(ctx, types.BuildCachePruneOptions{Filters: pruneFilters})
+	if err != nil {
+		return prune.Report{}, err
+	}
+	return prune.Report{Deleted: deleted, SpaceReclaimed: uint64(reclaimed)}, nil
+}
+
+// Preview returns the build cache records that are not in use, and were last
+// used before the "until" filter, if any. Other filters are not applied.
+func (p buildCachePruner) Preview(ctx context.Context, pruneFilters filters.Args) (prune.Report, error) {
+	var rep prune.Report
+	until, err := getUntilFromPruneFilters(pruneFilters)
+	if err != nil {
+		return rep, err
+	}
+	records, err := p.builder.DiskUsage(ctx)
+	if err != nil {
+		return rep, err
+	}
+	for _, r := range records {
+		if r.InUse {
+			continue
+		}
+		lastUsed := r.CreatedAt
+		if r.LastUsedAt != nil {
+			lastUsed = *r.LastUsedAt
+		}
+		if !until.IsZero() && lastUsed.After(until) {
+			continue
+		}
+		rep.Deleted = append(rep.Deleted, r.ID)
+		if r.Size > 0 {
+			rep.SpaceReclaimed += uint64(r.Size)
+		}
+	}
+	return rep, nil
+}
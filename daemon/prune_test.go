@@ -0,0 +1,34 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/filters"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestNetworkCreatedFromPruneFilters(t *testing.T) {
+	now := time.Now()
+	created, err := getNetworkCreatedFromPruneFilters(filters.NewArgs(
+		filters.Arg("since", "168h"),
+		filters.Arg("until", "1h"),
+	))
+	assert.NilError(t, err)
+
+	assert.Check(t, created.match(now.Add(-24*time.Hour)))
+	// Networks created in the last hour may be being attached to.
+	assert.Check(t, !created.match(now.Add(-time.Minute)))
+	assert.Check(t, !created.match(now.Add(-200*time.Hour)))
+	// Networks without a creation time are only matched by "until".
+	assert.Check(t, !created.match(time.Time{}))
+	created.since = time.Time{}
+	assert.Check(t, created.match(time.Time{}))
+
+	_, err = getNetworkCreatedFromPruneFilters(filters.NewArgs(
+		filters.Arg("since", "1h"),
+		filters.Arg("since", "2h"),
+	))
+	assert.Check(t, is.Error(err, "more than one since filter specified"))
+}
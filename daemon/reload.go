@@ -4,8 +4,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"time"
 
+	"github.com/docker/docker/api/types"
+	containertypes "github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/daemon/config"
+	"github.com/docker/docker/daemon/logger"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/libnetwork/ipamutils"
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
 
@@ -23,11 +30,31 @@ import (
 // - Insecure registries
 // - Registry mirrors
 // - Daemon live restore
+// - Default log driver and log options
+// - Default address pools
+// - Default networks and endpoint options
+// - Metrics address
+//
+// The result of the reload is recorded, and returned by ConfigReloadResult.
 func (daemon *Daemon) Reload(conf *config.Config) (err error) {
 	daemon.configStore.Lock()
 	attributes := map[string]string{}
 
+	reloaded, restartRequired, cmpErr := config.ChangedOptions(daemon.configStore, conf)
+	if cmpErr != nil {
+		logrus.WithError(cmpErr).Warn("Failed to compare the reloaded configuration with the current one")
+	}
+
 	defer func() {
+		result := types.ConfigReload{
+			Time:            time.Now().UTC(),
+			Reloaded:        reloaded,
+			RestartRequired: restartRequired,
+		}
+		if err != nil {
+			result.Error = err.Error()
+		}
+		daemon.setConfigReloadResult(result)
 		if err == nil {
 			jsonString, _ := json.Marshal(&struct {
 				*config.Config
@@ -69,9 +96,25 @@ func (daemon *Daemon) Reload(conf *config.Config) (err error) {
 	if err := daemon.reloadRegistryMirrors(conf, attributes); err != nil {
 		return err
 	}
+	if err := daemon.reloadRegistryCredentialHelpers(conf, attributes); err != nil {
+		return err
+	}
 	if err := daemon.reloadLiveRestore(conf, attributes); err != nil {
 		return err
 	}
+	if err := daemon.reloadLogConfig(conf, attributes); err != nil {
+		return err
+	}
+	if err := daemon.reloadDefaultAddressPools(conf, attributes); err != nil {
+		return err
+	}
+	if err := daemon.reloadDefaultNetworks(conf, attributes); err != nil {
+		return err
+	}
+	if err := daemon.reloadVolumePluginTimeouts(conf, attributes); err != nil {
+		return err
+	}
+	daemon.reloadMetricsAddress(conf, attributes)
 	return daemon.reloadNetworkDiagnosticPort(conf, attributes)
 }
 
@@ -237,6 +280,26 @@ func (daemon *Daemon) reloadRegistryMirrors(conf *config.Config, attributes map[
 	return nil
 }
 
+// reloadRegistryCredentialHelpers updates the credential helpers of the
+// registries, and updates the passed attributes. The credentials cached from
+// the previous helpers are dropped.
+func (daemon *Daemon) reloadRegistryCredentialHelpers(conf *config.Config, attributes map[string]string) error {
+	if conf.IsValueSet("registry-credential-helpers") {
+		daemon.configStore.CredentialHelpers = conf.CredentialHelpers
+		if err := daemon.registryService.LoadCredentialHelpers(conf.CredentialHelpers); err != nil {
+			return err
+		}
+	}
+
+	// prepare reload event attributes with updatable configurations
+	helpers, err := json.Marshal(daemon.configStore.CredentialHelpers)
+	if err != nil {
+		return err
+	}
+	attributes["registry-credential-helpers"] = string(helpers)
+	return nil
+}
+
 // reloadLiveRestore updates configuration with live restore option
 // and updates the passed attributes
 func (daemon *Daemon) reloadLiveRestore(conf *config.Config, attributes map[string]string) error {
@@ -250,6 +313,185 @@ func (daemon *Daemon) reloadLiveRestore(conf *config.Config, attributes map[stri
 	return nil
 }
 
+// reloadLogConfig updates the default log driver and log options of
+// containers, and updates the passed attributes. Containers that are
+// running keep their log configuration.
+func (daemon *Daemon) reloadLogConfig(conf *config.Config, attributes map[string]string) error {
+	if conf.IsValueSet("log-driver") || conf.IsValueSet("log-opts") {
+		logConfig := daemon.configStore.LogConfig
+		if conf.IsValueSet("log-driver") {
+			// The options of the previous driver don't apply to the
+			// new one.
+			logConfig.Type = conf.LogConfig.Type
+			logConfig.Config = nil
+		}
+		if conf.IsValueSet("log-opts") {
+			logConfig.Config = conf.LogConfig.Config
+		}
+		if err := logger.ValidateLogOpts(logConfig.Type, logConfig.Config); err != nil {
+			return errors.Wrap(err, "failed to set log opts")
+		}
+		daemon.configStore.LogConfig = logConfig
+		daemon.setDefaultLogConfig(containertypes.LogConfig{
+			Type:   logConfig.Type,
+			Config: logConfig.Config,
+		})
+	}
+
+	// prepare reload event attributes with updatable configurations
+	attributes["log-driver"] = daemon.configStore.LogConfig.Type
+	opts, err := json.Marshal(daemon.configStore.LogConfig.Config)
+	if err != nil {
+		return err
+	}
+	attributes["log-opts"] = string(opts)
+	return nil
+}
+
+// reloadDefaultAddressPools updates the default address pools from which
+// subnets are assigned to new networks, and how their subnets which overlap
+// the routes of the host are handled, and updates the passed attributes.
+// Existing networks keep their subnets.
+func (daemon *Daemon) reloadDefaultAddressPools(conf *config.Config, attributes map[string]string) error {
+	if conf.IsValueSet("default-address-pools") {
+		if daemon.netController != nil {
+			if err := daemon.netController.SetDefaultAddressPools(conf.DefaultAddressPools.Value()); err != nil {
+				return errdefs.InvalidParameter(errors.Wrap(err, "failed to set default address pools"))
+			}
+		}
+		daemon.configStore.DefaultAddressPools = conf.DefaultAddressPools
+	}
+	if conf.IsValueSet("default-address-pools-route-overlap") {
+		if daemon.netController != nil {
+			if err := daemon.netController.SetRouteOverlapMode(ipamutils.RouteOverlapMode(conf.DefaultAddressPoolsRouteOverlap)); err != nil {
+				return errdefs.InvalidParameter(errors.Wrap(err, "failed to set the route overlap mode of default address pools"))
+			}
+		}
+		daemon.configStore.DefaultAddressPoolsRouteOverlap = conf.DefaultAddressPoolsRouteOverlap
+	}
+
+	// prepare reload event attributes with updatable configurations
+	pools, err := json.Marshal(daemon.configStore.DefaultAddressPools.Value())
+	if err != nil {
+		return err
+	}
+	attributes["default-address-pools"] = string(pools)
+	attributes["default-address-pools-route-overlap"] = daemon.configStore.DefaultAddressPoolsRouteOverlap
+	return nil
+}
+
+// reloadDefaultNetworks updates the chain of default networks and the default
+// endpoint options, and updates the passed attributes. They apply to the
+// containers created after the reload.
+func (daemon *Daemon) reloadDefaultNetworks(conf *config.Config, attributes map[string]string) error {
+	if conf.IsValueSet("default-networks") {
+		daemon.configStore.DefaultNetworks = conf.DefaultNetworks
+	}
+	if conf.IsValueSet("default-endpoint-opts") {
+		daemon.configStore.DefaultEndpointOpts = conf.DefaultEndpointOpts
+	}
+
+	chain := daemon.configStore.DefaultNetworks
+	if chain == nil {
+		chain = []config.DefaultNetwork{}
+	}
+	b, err := json.Marshal(chain)
+	if err != nil {
+		return err
+	}
+	attributes["default-networks"] = string(b)
+	endpointOpts := daemon.configStore.DefaultEndpointOpts
+	if endpointOpts == nil {
+		endpointOpts = map[string]map[string]string{}
+	}
+	b, err = json.Marshal(endpointOpts)
+	if err != nil {
+		return err
+	}
+	attributes["default-endpoint-opts"] = string(b)
+	return nil
+}
+
+// reloadVolumePluginTimeouts updates the timeouts of the calls to volume
+// plugins, and updates the passed attributes. The timeouts apply to the calls
+// made after the reload, including those to volumes already in use.
+func (daemon *Daemon) reloadVolumePluginTimeouts(conf *config.Config, attributes map[string]string) error {
+	if conf.IsValueSet("volume-plugin-timeouts") {
+		daemon.configStore.VolumePluginTimeouts = conf.VolumePluginTimeouts
+		if daemon.volumes != nil {
+			daemon.volumes.SetPluginTimeouts(volumePluginTimeouts(conf.VolumePluginTimeouts))
+		}
+	}
+
+	// prepare reload event attributes with updatable configurations
+	timeouts, err := json.Marshal(daemon.configStore.VolumePluginTimeouts)
+	if err != nil {
+		return err
+	}
+	attributes["volume-plugin-timeouts"] = string(timeouts)
+	return nil
+}
+
+// reloadMetricsAddress updates configuration with the address of the metrics
+// API, and updates the passed attributes. The metrics server itself is moved
+// by the caller of Reload.
+func (daemon *Daemon) reloadMetricsAddress(conf *config.Config, attributes map[string]string) {
+	if conf.IsValueSet("metrics-addr") {
+		daemon.configStore.MetricsAddress = conf.MetricsAddress
+	}
+
+	// prepare reload event attributes with updatable configurations
+	attributes["metrics-addr"] = daemon.configStore.MetricsAddress
+}
+
+// ReloadFailed records a reload of the configuration that failed before the
+// daemon could apply it, for example because the configuration file is
+// invalid.
+func (daemon *Daemon) ReloadFailed(err error) {
+	daemon.setConfigReloadResult(types.ConfigReload{
+		Time:  time.Now().UTC(),
+		Error: err.Error(),
+	})
+}
+
+// MetricsListenFailed records that the metrics API could not be moved to the
+// reloaded metrics address, and restores the address it is still served on.
+func (daemon *Daemon) MetricsListenFailed(addr string, err error) {
+	daemon.configStore.Lock()
+	daemon.configStore.MetricsAddress = addr
+	daemon.configStore.Unlock()
+
+	daemon.configReloadMu.Lock()
+	defer daemon.configReloadMu.Unlock()
+	if r := daemon.configReload; r != nil {
+		reloaded := make([]string, 0, len(r.Reloaded))
+		for _, opt := range r.Reloaded {
+			if opt != "metrics-addr" {
+				reloaded = append(reloaded, opt)
+			}
+		}
+		r.Reloaded = reloaded
+		r.Error = "failed to move the metrics API: " + err.Error()
+	}
+}
+
+// ConfigReloadResult returns the result of the last reload of the
+// configuration.
+func (daemon *Daemon) ConfigReloadResult() (types.ConfigReload, error) {
+	daemon.configReloadMu.Lock()
+	defer daemon.configReloadMu.Unlock()
+	if daemon.configReload == nil {
+		return types.ConfigReload{}, errdefs.NotFound(errors.New("the configuration was not reloaded since the daemon started"))
+	}
+	return *daemon.configReload, nil
+}
+
+func (daemon *Daemon) setConfigReloadResult(result types.ConfigReload) {
+	daemon.configReloadMu.Lock()
+	daemon.configReload = &result
+	daemon.configReloadMu.Unlock()
+}
+
 // reloadNetworkDiagnosticPort updates the network controller starting the diagnostic if the config is valid
 func (daemon *Daemon) reloadNetworkDiagnosticPort(conf *config.Config, attributes map[string]string) error {
 	if conf == nil || daemon.netController == nil || !conf.IsValueSet("network-diagnostic-port") ||
@@ -405,3 +405,47 @@ func TestDaemonReloadNetworkDiagnosticPort(t *testing.T) {
 		t.Fatalf("diagnostic should be enable")
 	}
 }
+
+func TestDaemonReloadLogConfig(t *testing.T) {
+	daemon := &Daemon{
+		configStore: &config.Config{
+			CommonConfig: config.CommonConfig{
+				LogConfig: config.LogConfig{
+					Type:   "json-file",
+					Config: map[string]string{"max-size": "10m"},
+				},
+			},
+		},
+		imageService: images.NewImageService(images.ImageServiceConfig{}),
+	}
+	muteLogs()
+
+	newConfig := &config.Config{
+		CommonConfig: config.CommonConfig{
+			LogConfig: config.LogConfig{Type: "local"},
+			ValuesSet: map[string]interface{}{"log-driver": "local"},
+		},
+	}
+	assert.NilError(t, daemon.Reload(newConfig))
+	assert.Check(t, is.Equal(daemon.getDefaultLogConfig().Type, "local"))
+	assert.Check(t, is.Len(daemon.getDefaultLogConfig().Config, 0))
+
+	result, err := daemon.ConfigReloadResult()
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(result.Reloaded, []string{"log-driver"}))
+	assert.Check(t, is.Len(result.RestartRequired, 0))
+	assert.Check(t, is.Equal(result.Error, ""))
+
+	newConfig = &config.Config{
+		CommonConfig: config.CommonConfig{
+			LogConfig: config.LogConfig{Config: map[string]string{"no-such-opt": "1"}},
+			ValuesSet: map[string]interface{}{"log-opts": map[string]interface{}{"no-such-opt": "1"}},
+		},
+	}
+	assert.Check(t, is.ErrorContains(daemon.Reload(newConfig), "failed to set log opts"))
+	assert.Check(t, is.Equal(daemon.getDefaultLogConfig().Type, "local"))
+
+	result, err = daemon.ConfigReloadResult()
+	assert.NilError(t, err)
+	assert.Check(t, is.Contains(result.Error, "failed to set log opts"))
+}
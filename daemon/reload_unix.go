@@ -5,6 +5,7 @@ package daemon // import "github.com/docker/docker/daemon"
 
 import (
 	"bytes"
+	"encoding/json"
 	"strconv"
 
 	"github.com/docker/docker/api/types"
@@ -43,6 +44,10 @@ func (daemon *Daemon) reloadPlatform(conf *config.Config, attributes map[string]
 		daemon.configStore.IpcMode = conf.IpcMode
 	}
 
+	if conf.IsValueSet("allowed-hooks") {
+		daemon.configStore.AllowedHooks = conf.AllowedHooks
+	}
+
 	// Update attributes
 	var runtimeList bytes.Buffer
 	for name, rt := range daemon.configStore.Runtimes {
@@ -58,5 +63,15 @@ func (daemon *Daemon) reloadPlatform(conf *config.Config, attributes map[string]
 	attributes["default-ipc-mode"] = daemon.configStore.IpcMode
 	attributes["default-cgroupns-mode"] = daemon.configStore.CgroupNamespaceMode
 
+	allowedHooks := daemon.configStore.AllowedHooks
+	if allowedHooks == nil {
+		allowedHooks = []string{}
+	}
+	b, err := json.Marshal(allowedHooks)
+	if err != nil {
+		return err
+	}
+	attributes["allowed-hooks"] = string(b)
+
 	return nil
 }
@@ -0,0 +1,117 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"bytes"
+	"time"
+
+	swarmtypes "github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/daemon/secretprovider"
+	swarmapi "github.com/moby/swarmkit/v2/api"
+	"github.com/sirupsen/logrus"
+)
+
+// secretLeaseRetry is the interval at which fetching a secret from its
+// secret provider is retried, if fetching it again before its lease expires
+// failed.
+const secretLeaseRetry = 10 * time.Second
+
+// secretData returns the data of a secret to inject in a container. The
+// value of secrets backed by a secret provider is fetched from the secret
+// provider plugin, along with the duration the value is valid for.
+func (daemon *Daemon) secretData(c *container.Container, secret *swarmapi.Secret) ([]byte, time.Duration, error) {
+	p, err := secretprovider.FromLabels(secret.Spec.Annotations.Labels)
+	if err != nil {
+		return nil, 0, err
+	}
+	if p == nil {
+		return secret.Spec.Data, 0, nil
+	}
+
+	resp, err := secretprovider.Get(daemon.PluginStore, p.Name, secretprovider.Request{
+		SecretName:   secret.Spec.Annotations.Name,
+		SecretLabels: secret.Spec.Annotations.Labels,
+		Options:      p.Options,
+		ServiceID:    c.Config.Labels["com.docker.swarm.service.id"],
+		ServiceName:  c.Config.Labels["com.docker.swarm.service.name"],
+		TaskID:       c.Config.Labels["com.docker.swarm.task.id"],
+		TaskName:     c.Config.Labels["com.docker.swarm.task.name"],
+		TaskImage:    c.Config.Image,
+		NodeID:       c.Config.Labels["com.docker.swarm.node.id"],
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return resp.Value, resp.Lease(), nil
+}
+
+// renewSecret fetches the value of a secret from its secret provider again
+// before its lease expires, and updates the secret file of the container if
+// the value changed, for instance because the secret was rotated in the
+// external store. The file is replaced atomically, so that the container
+// never reads a partially written secret. It stops once the container is cleaned up, or once the
+// secret provider returns a value that never expires.
+func (daemon *Daemon) renewSecret(c *container.Container, secret *swarmapi.Secret, ref *swarmtypes.SecretReference, fPath string, data []byte, lease time.Duration) {
+	stop := daemon.secretLeaseStop(c.ID)
+	logger := logrus.WithFields(logrus.Fields{
+		"container": c.ID,
+		"secret":    secret.Spec.Annotations.Name,
+	})
+
+	go func() {
+		wait := lease * 2 / 3
+		for {
+			select {
+			case <-stop:
+				return
+			case <-time.After(wait):
+			}
+
+			value, next, err := daemon.secretData(c, secret)
+			if err != nil {
+				logger.WithError(err).Warn("failed to renew the lease of secret")
+				wait = secretLeaseRetry
+				continue
+			}
+			if !bytes.Equal(value, data) {
+				if err := daemon.updateSecretFile(c, ref, fPath, value); err != nil {
+					logger.WithError(err).Error("failed to update secret")
+					wait = secretLeaseRetry
+					continue
+				}
+				logger.Debug("updated secret from its secret provider")
+				data = value
+			}
+			if next == 0 {
+				return
+			}
+			wait = next * 2 / 3
+		}
+	}()
+}
+
+// secretLeaseStop returns a channel that is closed once the secrets of a
+// container are cleaned up.
+func (daemon *Daemon) secretLeaseStop(id string) <-chan struct{} {
+	daemon.secretLeasesMu.Lock()
+	defer daemon.secretLeasesMu.Unlock()
+	if daemon.secretLeases == nil {
+		daemon.secretLeases = make(map[string]chan struct{})
+	}
+	stop, ok := daemon.secretLeases[id]
+	if !ok {
+		stop = make(chan struct{})
+		daemon.secretLeases[id] = stop
+	}
+	return stop
+}
+
+// stopSecretLeases stops renewing the secrets of a container.
+func (daemon *Daemon) stopSecretLeases(id string) {
+	daemon.secretLeasesMu.Lock()
+	defer daemon.secretLeasesMu.Unlock()
+	if stop, ok := daemon.secretLeases[id]; ok {
+		close(stop)
+		delete(daemon.secretLeases, id)
+	}
+}
@@ -0,0 +1,101 @@
+// Package secretprovider fetches the value of swarm secrets that are backed
+// by an external secret store, such as Vault or AWS Secrets Manager.
+//
+// The value of these secrets is not stored by the managers: they only store a
+// reference to the secret provider plugin, and its options. The agent running
+// a task fetches the value from the plugin when the task starts, and writes it
+// to the in-memory secrets mount of the container.
+package secretprovider // import "github.com/docker/docker/daemon/secretprovider"
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/pkg/plugingetter"
+	"github.com/pkg/errors"
+)
+
+const (
+	// Label is the label of a secret that holds its provider, as JSON.
+	Label = "com.docker.swarm.secret.provider"
+
+	// Capability is the capability of secret provider plugins. It is the
+	// same capability as the secret drivers that are called by the managers,
+	// so the same plugin can be used for both.
+	Capability = "secretprovider"
+
+	// getSecretEndpoint is the endpoint to fetch a secret from a plugin.
+	getSecretEndpoint = "/SecretProvider.GetSecret"
+)
+
+// Request is the request to fetch a secret from a secret provider plugin.
+type Request struct {
+	SecretName   string            `json:",omitempty"`
+	SecretLabels map[string]string `json:",omitempty"`
+
+	// Options are the options of the secret provider of the secret, such as
+	// the path of the secret in the external store.
+	Options map[string]string `json:",omitempty"`
+
+	ServiceID   string `json:",omitempty"`
+	ServiceName string `json:",omitempty"`
+	TaskID      string `json:",omitempty"`
+	TaskName    string `json:",omitempty"`
+	TaskImage   string `json:",omitempty"`
+	NodeID      string `json:",omitempty"`
+}
+
+// Response is the response of a secret provider plugin.
+type Response struct {
+	Value []byte `json:",omitempty"`
+	Err   string `json:",omitempty"`
+
+	// LeaseDuration is the number of seconds the value is valid for. The
+	// value is fetched again before the lease expires, and the secret is
+	// updated in the containers that use it if the value changed. A zero
+	// lease never expires.
+	LeaseDuration int64 `json:",omitempty"`
+}
+
+// Lease returns the duration the value of the secret is valid for.
+func (r *Response) Lease() time.Duration {
+	return time.Duration(r.LeaseDuration) * time.Second
+}
+
+// FromLabels returns the provider of a secret from its labels, or nil if
+// the secret is not backed by a secret provider.
+func FromLabels(labels map[string]string) (*swarm.SecretProvider, error) {
+	v, ok := labels[Label]
+	if !ok {
+		return nil, nil
+	}
+	var p swarm.SecretProvider
+	if err := json.Unmarshal([]byte(v), &p); err != nil {
+		return nil, errors.Wrap(err, "invalid secret provider label")
+	}
+	return &p, nil
+}
+
+// Get fetches the value of a secret from a secret provider plugin.
+func Get(pg plugingetter.PluginGetter, name string, req Request) (*Response, error) {
+	if pg == nil {
+		return nil, errors.New("secret providers are not supported")
+	}
+	p, err := pg.Get(name, Capability, plugingetter.Lookup)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error looking up secret provider plugin %s", name)
+	}
+
+	var resp Response
+	if err := p.Client().Call(getSecretEndpoint, req, &resp); err != nil {
+		return nil, errors.Wrapf(err, "error fetching secret %s from secret provider plugin %s", req.SecretName, name)
+	}
+	if resp.Err != "" {
+		return nil, errors.Errorf("error fetching secret %s from secret provider plugin %s: %s", req.SecretName, name, resp.Err)
+	}
+	if resp.LeaseDuration < 0 {
+		return nil, errors.Errorf("secret provider plugin %s returned an invalid lease for secret %s", name, req.SecretName)
+	}
+	return &resp, nil
+}
@@ -0,0 +1,101 @@
+package secretprovider // import "github.com/docker/docker/daemon/secretprovider"
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/pkg/plugingetter"
+	"github.com/docker/docker/pkg/plugins"
+	"github.com/docker/go-connections/tlsconfig"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+type fakePlugin struct {
+	plugingetter.CompatPlugin
+	client *plugins.Client
+}
+
+func (p *fakePlugin) Client() *plugins.Client {
+	return p.client
+}
+
+type fakePluginGetter struct {
+	plugingetter.PluginGetter
+	plugins map[string]*fakePlugin
+}
+
+func (g *fakePluginGetter) Get(name, capability string, mode int) (plugingetter.CompatPlugin, error) {
+	if p, ok := g.plugins[name]; ok && capability == Capability {
+		return p, nil
+	}
+	return nil, plugins.ErrNotFound
+}
+
+func newTestPluginGetter(t *testing.T, fn func(req Request) Response) plugingetter.PluginGetter {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(getSecretEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/vnd.docker.plugins.v1+json")
+		_ = json.NewEncoder(w).Encode(fn(req))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	u, _ := url.Parse(server.URL)
+	client, err := plugins.NewClient("tcp://"+u.Host, &tlsconfig.Options{InsecureSkipVerify: true})
+	assert.NilError(t, err)
+	return &fakePluginGetter{plugins: map[string]*fakePlugin{"vault": {client: client}}}
+}
+
+func TestGet(t *testing.T) {
+	pg := newTestPluginGetter(t, func(req Request) Response {
+		if req.Options["path"] != "secret/data/db" {
+			return Response{Err: "unknown path " + req.Options["path"]}
+		}
+		return Response{Value: []byte("password"), LeaseDuration: 60}
+	})
+
+	resp, err := Get(pg, "vault", Request{SecretName: "db", Options: map[string]string{"path": "secret/data/db"}})
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(resp.Value), "password"))
+	assert.Check(t, is.Equal(resp.Lease().Seconds(), float64(60)))
+
+	_, err = Get(pg, "vault", Request{SecretName: "db", Options: map[string]string{"path": "secret/data/other"}})
+	assert.Check(t, is.ErrorContains(err, "unknown path secret/data/other"))
+
+	_, err = Get(pg, "aws", Request{SecretName: "db"})
+	assert.Check(t, is.ErrorContains(err, "error looking up secret provider plugin aws"))
+}
+
+func TestGetInvalidLease(t *testing.T) {
+	pg := newTestPluginGetter(t, func(req Request) Response {
+		return Response{Value: []byte("password"), LeaseDuration: -1}
+	})
+
+	_, err := Get(pg, "vault", Request{SecretName: "db"})
+	assert.Check(t, is.ErrorContains(err, "invalid lease"))
+}
+
+func TestFromLabels(t *testing.T) {
+	p, err := FromLabels(map[string]string{"foo": "bar"})
+	assert.NilError(t, err)
+	assert.Check(t, is.Nil(p))
+
+	p, err = FromLabels(map[string]string{Label: `{"Name":"vault","Options":{"path":"secret/data/db"}}`})
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(p, &swarm.SecretProvider{Name: "vault", Options: map[string]string{"path": "secret/data/db"}}))
+
+	_, err = FromLabels(map[string]string{Label: "vault"})
+	assert.Check(t, is.ErrorContains(err, "invalid secret provider label"))
+}
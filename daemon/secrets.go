@@ -1,10 +1,23 @@
 package daemon // import "github.com/docker/docker/daemon"
 
 import (
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	containertypes "github.com/docker/docker/api/types/container"
 	swarmtypes "github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
 
+// defaultFileReferenceMode is the mode of the files of the secrets and
+// configs of standalone containers, as for swarm services.
+const defaultFileReferenceMode os.FileMode = 0o444
+
 // SetContainerSecretReferences sets the container secret references needed
 func (daemon *Daemon) SetContainerSecretReferences(name string, refs []*swarmtypes.SecretReference) error {
 	if !secretsSupported() && len(refs) > 0 {
@@ -21,3 +34,142 @@ func (daemon *Daemon) SetContainerSecretReferences(name string, refs []*swarmtyp
 
 	return nil
 }
+
+// validateLocalSecretReferences validates the references of a container to
+// the secrets and configs of the local store.
+func (daemon *Daemon) validateLocalSecretReferences(hostConfig *containertypes.HostConfig) error {
+	if hostConfig == nil || (len(hostConfig.Secrets) == 0 && len(hostConfig.Configs) == 0) {
+		return nil
+	}
+	if len(hostConfig.Secrets) > 0 && !secretsSupported() {
+		return errors.New("secrets are not supported on this platform")
+	}
+	if len(hostConfig.Configs) > 0 && !configsSupported() {
+		return errors.New("configs are not supported on this platform")
+	}
+	for _, r := range hostConfig.Secrets {
+		if err := validateFileReference("secret", r); err != nil {
+			return err
+		}
+		if _, err := daemon.localSecrets.GetSecret(r.Source); err != nil {
+			return err
+		}
+	}
+	for _, r := range hostConfig.Configs {
+		if err := validateFileReference("config", r); err != nil {
+			return err
+		}
+		if _, err := daemon.localSecrets.GetConfig(r.Source); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// localSecretUsers returns the names of the containers which have a reference
+// to a secret or config of the local store for which uses returns true.
+func (daemon *Daemon) localSecretUsers(kind string, uses func(source string) bool) []string {
+	var users []string
+	for _, c := range daemon.containers.List() {
+		refs := c.HostConfig.Secrets
+		if kind == "config" {
+			refs = c.HostConfig.Configs
+		}
+		for _, r := range refs {
+			if uses(r.Source) {
+				users = append(users, strings.TrimPrefix(c.Name, "/"))
+				break
+			}
+		}
+	}
+	sort.Strings(users)
+	return users
+}
+
+func validateFileReference(kind string, r containertypes.FileReference) error {
+	if r.Source == "" {
+		return errors.Errorf("%s reference must have a source", kind)
+	}
+	for _, id := range []string{r.UID, r.GID} {
+		if id == "" {
+			continue
+		}
+		if _, err := strconv.ParseUint(id, 10, 32); err != nil {
+			return errors.Errorf("invalid owner %q for %s %s: must be numeric", id, kind, r.Source)
+		}
+	}
+	return nil
+}
+
+// setLocalSecretReferences exposes the secrets and configs of the local store
+// referenced by a standalone container. The references are resolved on each
+// start, so that the container gets the current secrets and configs with
+// these names.
+func (daemon *Daemon) setLocalSecretReferences(c *container.Container) error {
+	hc := c.HostConfig
+	if hc == nil || (len(hc.Secrets) == 0 && len(hc.Configs) == 0) {
+		return nil
+	}
+
+	secretRefs := make([]*swarmtypes.SecretReference, 0, len(hc.Secrets))
+	for _, r := range hc.Secrets {
+		secret, err := daemon.localSecrets.GetSecret(r.Source)
+		if err != nil {
+			return errdefs.InvalidParameter(errors.Wrap(err, "unable to get secret from local secret store"))
+		}
+		name, uid, gid, mode := fileReferenceTarget(r, secret.Spec.Annotations.Name)
+		secretRefs = append(secretRefs, &swarmtypes.SecretReference{
+			File: &swarmtypes.SecretReferenceFileTarget{
+				Name: name,
+				UID:  uid,
+				GID:  gid,
+				Mode: mode,
+			},
+			SecretID:   secret.ID,
+			SecretName: secret.Spec.Annotations.Name,
+		})
+	}
+
+	configRefs := make([]*swarmtypes.ConfigReference, 0, len(hc.Configs))
+	for _, r := range hc.Configs {
+		config, err := daemon.localSecrets.GetConfig(r.Source)
+		if err != nil {
+			return errdefs.InvalidParameter(errors.Wrap(err, "unable to get config from local config store"))
+		}
+		name, uid, gid, mode := fileReferenceTarget(r, config.Spec.Annotations.Name)
+		configRefs = append(configRefs, &swarmtypes.ConfigReference{
+			File: &swarmtypes.ConfigReferenceFileTarget{
+				Name: name,
+				UID:  uid,
+				GID:  gid,
+				Mode: mode,
+			},
+			ConfigID:   config.ID,
+			ConfigName: config.Spec.Annotations.Name,
+		})
+	}
+
+	c.SecretReferences = secretRefs
+	c.ConfigReferences = configRefs
+	c.DependencyStore = daemon.localSecrets
+	return nil
+}
+
+// fileReferenceTarget returns the file target of a reference, with the
+// defaults of swarm services.
+func fileReferenceTarget(r containertypes.FileReference, name string) (target, uid, gid string, mode os.FileMode) {
+	target, uid, gid, mode = r.Target, r.UID, r.GID, r.Mode
+	if target == "" {
+		target = name
+	}
+	if uid == "" {
+		uid = "0"
+	}
+	if gid == "" {
+		gid = "0"
+	}
+	if mode == 0 {
+		mode = defaultFileReferenceMode
+	}
+	return target, uid, gid, mode
+}
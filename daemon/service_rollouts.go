@@ -0,0 +1,202 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/docker/docker/api/types/events"
+	swarmapi "github.com/moby/swarmkit/v2/api"
+	"github.com/moby/swarmkit/v2/api/defaults"
+)
+
+// serviceRollout tracks the tasks of a service being deployed or updated,
+// to report when a batch of its tasks is healthy, and when a new service
+// converges. Rollouts are only accessed by the goroutine processing the
+// cluster events.
+type serviceRollout struct {
+	name string
+	// created is set for the deployment of a new service, which converges
+	// when all its tasks are running. Updates converge when swarmkit
+	// completes them.
+	created bool
+	// specVersion is the version of the spec of the tasks that are rolled
+	// out by an update.
+	specVersion uint64
+	parallelism uint64
+	replicated  bool
+	replicas    uint64
+	// pending are the tasks rolled out that are not running yet.
+	pending map[string]struct{}
+	// healthy is the number of tasks rolled out that are running, and
+	// healthy if they have a health check.
+	healthy uint64
+}
+
+func newServiceRollout(service *swarmapi.Service, created bool) *serviceRollout {
+	r := &serviceRollout{
+		created: created,
+		pending: make(map[string]struct{}),
+	}
+	if !created && service.SpecVersion != nil {
+		r.specVersion = service.SpecVersion.Index
+	}
+	update := service.Spec.Update
+	if service.UpdateStatus != nil && service.UpdateStatus.State == swarmapi.UpdateStatus_ROLLBACK_STARTED {
+		update = service.Spec.Rollback
+	}
+	if update == nil {
+		update = defaults.Service.Update
+	}
+	r.parallelism = update.Parallelism
+	r.setService(service)
+	return r
+}
+
+func (r *serviceRollout) setService(service *swarmapi.Service) {
+	r.name = service.Spec.Annotations.Name
+	if mode, ok := service.Spec.GetMode().(*swarmapi.ServiceSpec_Replicated); ok {
+		r.replicated = true
+		r.replicas = mode.Replicated.Replicas
+	}
+}
+
+// converged returns whether all the tasks of a new service are running. The
+// number of tasks of a global service is not known, it converges when the
+// tasks created by the orchestrator are running.
+func (r *serviceRollout) converged() bool {
+	if len(r.pending) != 0 {
+		return false
+	}
+	if r.replicated {
+		return r.healthy >= r.replicas
+	}
+	return r.healthy > 0
+}
+
+// batchHealthy returns whether the last task that became healthy completed
+// a batch of the update. The last batch of a global service is not reported
+// if it is incomplete, the update converging instead.
+func (r *serviceRollout) batchHealthy() bool {
+	if r.parallelism > 0 && r.healthy%r.parallelism == 0 {
+		return true
+	}
+	return r.replicated && r.healthy == r.replicas
+}
+
+func isServiceJob(service *swarmapi.Service) bool {
+	switch service.Spec.GetMode().(type) {
+	case *swarmapi.ServiceSpec_ReplicatedJob, *swarmapi.ServiceSpec_GlobalJob:
+		return true
+	}
+	return false
+}
+
+// trackServiceRollout starts tracking the tasks of a service when it is
+// created or an update of its tasks starts, and stops when the rollout ends.
+func (daemon *Daemon) trackServiceRollout(action swarmapi.WatchActionKind, service, oldService *swarmapi.Service, eventTime time.Time) {
+	if daemon.serviceRollouts == nil {
+		daemon.serviceRollouts = make(map[string]*serviceRollout)
+	}
+	switch action {
+	case swarmapi.WatchActionKindCreate:
+		if isServiceJob(service) {
+			return
+		}
+		r := newServiceRollout(service, true)
+		daemon.serviceRollouts[service.ID] = r
+		if r.replicated && r.replicas == 0 {
+			daemon.endServiceRollout(service.ID, r, eventTime)
+		}
+	case swarmapi.WatchActionKindUpdate:
+		if service.UpdateStatus != nil && (oldService == nil || oldService.UpdateStatus == nil || oldService.UpdateStatus.State != service.UpdateStatus.State) {
+			switch service.UpdateStatus.State {
+			case swarmapi.UpdateStatus_UPDATING, swarmapi.UpdateStatus_ROLLBACK_STARTED:
+				daemon.serviceRollouts[service.ID] = newServiceRollout(service, false)
+			default:
+				delete(daemon.serviceRollouts, service.ID)
+			}
+			return
+		}
+		if r, ok := daemon.serviceRollouts[service.ID]; ok {
+			// the service may be scaled while it is deployed
+			r.setService(service)
+			if r.created && r.converged() {
+				daemon.endServiceRollout(service.ID, r, eventTime)
+			}
+		}
+	case swarmapi.WatchActionKindRemove:
+		delete(daemon.serviceRollouts, service.ID)
+	}
+}
+
+// trackRolloutTask updates the rollout of the service of a task, and
+// generates a "rollout" event when a batch of tasks is healthy, or when a
+// new service converges.
+func (daemon *Daemon) trackRolloutTask(action swarmapi.WatchActionKind, task *swarmapi.Task) {
+	r, ok := daemon.serviceRollouts[task.ServiceID]
+	if !ok {
+		return
+	}
+	if !r.created && (task.SpecVersion == nil || task.SpecVersion.Index != r.specVersion) {
+		return
+	}
+	if action == swarmapi.WatchActionKindCreate {
+		if task.DesiredState <= swarmapi.TaskStateRunning {
+			r.pending[task.ID] = struct{}{}
+		}
+		return
+	}
+	if _, ok := r.pending[task.ID]; !ok {
+		return
+	}
+	switch {
+	case action == swarmapi.WatchActionKindRemove, task.DesiredState > swarmapi.TaskStateRunning, task.Status.State > swarmapi.TaskStateRunning:
+		// the orchestrator replaces the task if needed
+		delete(r.pending, task.ID)
+		return
+	case task.Status.State < swarmapi.TaskStateRunning:
+		return
+	}
+	delete(r.pending, task.ID)
+	r.healthy++
+
+	eventTime := eventTimestamp(task.Meta, action)
+	if r.created {
+		if r.converged() {
+			daemon.endServiceRollout(task.ServiceID, r, eventTime)
+		}
+		return
+	}
+	if r.batchHealthy() {
+		daemon.logRolloutEvent(task.ServiceID, r.name, "batch-healthy", map[string]string{
+			"tasks.healthy": strconv.FormatUint(r.healthy, 10),
+		}, eventTime)
+	}
+}
+
+// endServiceRollout generates the "converged" event of a new service.
+func (daemon *Daemon) endServiceRollout(id string, r *serviceRollout, eventTime time.Time) {
+	delete(daemon.serviceRollouts, id)
+	daemon.logRolloutEvent(id, r.name, "converged", nil, eventTime)
+}
+
+func (daemon *Daemon) logRolloutEvent(id, name, phase string, attributes map[string]string, eventTime time.Time) {
+	actorAttributes := map[string]string{
+		"name":  name,
+		"phase": phase,
+	}
+	for k, v := range attributes {
+		actorAttributes[k] = v
+	}
+	daemon.EventsService.PublishMessage(events.Message{
+		Action: "rollout",
+		Type:   events.ServiceEventType,
+		Actor: events.Actor{
+			ID:         id,
+			Attributes: actorAttributes,
+		},
+		Scope:    "swarm",
+		Time:     eventTime.UTC().Unix(),
+		TimeNano: eventTime.UTC().UnixNano(),
+	})
+}
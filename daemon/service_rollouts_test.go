@@ -0,0 +1,107 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"testing"
+	"time"
+
+	"github.com/docker/docker/daemon/events"
+	swarmapi "github.com/moby/swarmkit/v2/api"
+)
+
+func rolloutTestTask(id string, version uint64, state swarmapi.TaskState) *swarmapi.Task {
+	return &swarmapi.Task{
+		ID:           id,
+		ServiceID:    "service_id",
+		SpecVersion:  &swarmapi.Version{Index: version},
+		DesiredState: swarmapi.TaskStateRunning,
+		Status:       swarmapi.TaskStatus{State: state},
+	}
+}
+
+func assertNoEvent(t *testing.T, l chan interface{}) {
+	t.Helper()
+	select {
+	case ev := <-l:
+		t.Fatalf("unexpected event: %v", ev)
+	default:
+	}
+}
+
+func TestServiceRolloutConverged(t *testing.T) {
+	e := events.New()
+	_, l, _ := e.Subscribe()
+	defer e.Evict(l)
+
+	daemon := &Daemon{
+		EventsService: e,
+	}
+	service := &swarmapi.Service{
+		ID: "service_id",
+		Spec: swarmapi.ServiceSpec{
+			Annotations: swarmapi.Annotations{Name: "service_name"},
+			Mode:        &swarmapi.ServiceSpec_Replicated{Replicated: &swarmapi.ReplicatedService{Replicas: 2}},
+		},
+	}
+	daemon.trackServiceRollout(swarmapi.WatchActionKindCreate, service, nil, time.Now())
+	daemon.trackRolloutTask(swarmapi.WatchActionKindCreate, rolloutTestTask("t1", 0, swarmapi.TaskStateNew))
+	daemon.trackRolloutTask(swarmapi.WatchActionKindCreate, rolloutTestTask("t2", 0, swarmapi.TaskStateNew))
+
+	daemon.trackRolloutTask(swarmapi.WatchActionKindUpdate, rolloutTestTask("t1", 0, swarmapi.TaskStateRunning))
+	assertNoEvent(t, l)
+
+	// a failed task is replaced by the orchestrator
+	daemon.trackRolloutTask(swarmapi.WatchActionKindUpdate, rolloutTestTask("t2", 0, swarmapi.TaskStateFailed))
+	assertNoEvent(t, l)
+	daemon.trackRolloutTask(swarmapi.WatchActionKindCreate, rolloutTestTask("t3", 0, swarmapi.TaskStateNew))
+	daemon.trackRolloutTask(swarmapi.WatchActionKindUpdate, rolloutTestTask("t3", 0, swarmapi.TaskStateRunning))
+	validateTestAttributes(t, l, map[string]string{"name": "service_name", "phase": "converged"})
+
+	// the service converges once
+	daemon.trackRolloutTask(swarmapi.WatchActionKindUpdate, rolloutTestTask("t3", 0, swarmapi.TaskStateRunning))
+	assertNoEvent(t, l)
+}
+
+func TestServiceRolloutBatchHealthy(t *testing.T) {
+	e := events.New()
+	_, l, _ := e.Subscribe()
+	defer e.Evict(l)
+
+	daemon := &Daemon{
+		EventsService: e,
+	}
+	service := &swarmapi.Service{
+		ID: "service_id",
+		Spec: swarmapi.ServiceSpec{
+			Annotations: swarmapi.Annotations{Name: "service_name"},
+			Mode:        &swarmapi.ServiceSpec_Replicated{Replicated: &swarmapi.ReplicatedService{Replicas: 3}},
+			Update:      &swarmapi.UpdateConfig{Parallelism: 2},
+		},
+		SpecVersion:  &swarmapi.Version{Index: 10},
+		UpdateStatus: &swarmapi.UpdateStatus{State: swarmapi.UpdateStatus_UPDATING},
+	}
+	daemon.trackServiceRollout(swarmapi.WatchActionKindUpdate, service, nil, time.Now())
+
+	// tasks of the previous spec are not part of the rollout
+	daemon.trackRolloutTask(swarmapi.WatchActionKindCreate, rolloutTestTask("t0", 9, swarmapi.TaskStateNew))
+	daemon.trackRolloutTask(swarmapi.WatchActionKindUpdate, rolloutTestTask("t0", 9, swarmapi.TaskStateRunning))
+	assertNoEvent(t, l)
+
+	for _, id := range []string{"t1", "t2", "t3"} {
+		daemon.trackRolloutTask(swarmapi.WatchActionKindCreate, rolloutTestTask(id, 10, swarmapi.TaskStateNew))
+	}
+	daemon.trackRolloutTask(swarmapi.WatchActionKindUpdate, rolloutTestTask("t1", 10, swarmapi.TaskStateRunning))
+	assertNoEvent(t, l)
+	daemon.trackRolloutTask(swarmapi.WatchActionKindUpdate, rolloutTestTask("t2", 10, swarmapi.TaskStateRunning))
+	validateTestAttributes(t, l, map[string]string{"phase": "batch-healthy", "tasks.healthy": "2"})
+
+	// the last batch is smaller
+	daemon.trackRolloutTask(swarmapi.WatchActionKindUpdate, rolloutTestTask("t3", 10, swarmapi.TaskStateRunning))
+	validateTestAttributes(t, l, map[string]string{"phase": "batch-healthy", "tasks.healthy": "3"})
+
+	oldService := service.Copy()
+	service.UpdateStatus.State = swarmapi.UpdateStatus_COMPLETED
+	daemon.trackServiceRollout(swarmapi.WatchActionKindUpdate, service, oldService, time.Now())
+	if _, ok := daemon.serviceRollouts[service.ID]; ok {
+		t.Fatal("expected the rollout to end when the update completes")
+	}
+}
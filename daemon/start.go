@@ -151,6 +151,10 @@ func (daemon *Daemon) containerStart(ctx context.Context, container *container.C
 		return err
 	}
 
+	if err := daemon.setLocalSecretReferences(container); err != nil {
+		return err
+	}
+
 	spec, err := daemon.createSpec(ctx, container)
 	if err != nil {
 		return errdefs.System(err)
@@ -200,6 +204,7 @@ func (daemon *Daemon) containerStart(ctx context.Context, container *container.C
 	daemon.setStateCounter(container)
 
 	daemon.initHealthMonitor(container)
+	daemon.startLifetimeTimer(container)
 
 	if err := container.CheckpointTo(daemon.containersReplica); err != nil {
 		logrus.WithError(err).WithField("container", container.ID).
@@ -237,6 +242,9 @@ func (daemon *Daemon) Cleanup(container *container.Container) {
 		}
 	}
 
+	daemon.stopSecretLeases(container.ID)
+	daemon.closeNotifySocket(container.ID)
+	daemon.stopLifetimeTimer(container.ID)
 	if err := container.UnmountSecrets(); err != nil {
 		logrus.Warnf("%s cleanup: failed to unmount secrets: %s", container.ID, err)
 	}
@@ -0,0 +1,58 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/sirupsen/logrus"
+)
+
+// startupTrace records the durations of the phases of the startup of the
+// daemon, to diagnose slow startups.
+type startupTrace struct {
+	mu     sync.Mutex
+	phases []types.StartupPhase
+}
+
+// phase starts the phase with the given name, and returns the function to call
+// when it ends. The phases of a nil trace are not recorded.
+func (t *startupTrace) phase(name string) func() {
+	if t == nil {
+		return func() {}
+	}
+	start := time.Now()
+	return func() {
+		d := time.Since(start)
+		t.mu.Lock()
+		t.phases = append(t.phases, types.StartupPhase{Name: name, Started: start, Duration: d})
+		t.mu.Unlock()
+		startupPhases.WithValues(name).Set(d.Seconds())
+		logrus.WithField("duration", d).Debugf("startup phase %s done", name)
+	}
+}
+
+// tracer returns a function starting the phases within the phase with the
+// given name, for the components which report their own phases.
+func (t *startupTrace) tracer(parent string) func(string) func() {
+	return func(name string) func() {
+		return t.phase(parent + "." + name)
+	}
+}
+
+// StartupPhases returns the phases of the startup of the daemon which ended,
+// in the order in which they started.
+func (daemon *Daemon) StartupPhases() []types.StartupPhase {
+	t := daemon.startup
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	phases := append([]types.StartupPhase(nil), t.phases...)
+	t.mu.Unlock()
+	sort.SliceStable(phases, func(i, j int) bool {
+		return phases[i].Started.Before(phases[j].Started)
+	})
+	return phases
+}
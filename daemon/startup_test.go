@@ -0,0 +1,28 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestStartupPhases(t *testing.T) {
+	d := &Daemon{startup: &startupTrace{}}
+
+	restoreDone := d.startup.phase("restore")
+	d.startup.tracer("restore")("network-controller")()
+	restoreDone()
+	d.startup.phase("unfinished")
+
+	phases := d.StartupPhases()
+	assert.Assert(t, is.Len(phases, 2))
+	assert.Check(t, is.Equal(phases[0].Name, "restore"))
+	assert.Check(t, is.Equal(phases[1].Name, "restore.network-controller"))
+	assert.Check(t, phases[0].Duration >= phases[1].Duration)
+
+	// The phases of a daemon which was not started are not recorded.
+	d = &Daemon{}
+	d.startup.phase("restore")()
+	assert.Check(t, is.Len(d.StartupPhases(), 0))
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"runtime"
+	"sync"
 	"time"
 
 	"github.com/docker/docker/api/types"
@@ -14,6 +15,17 @@ import (
 	"github.com/docker/docker/container"
 	"github.com/docker/docker/errdefs"
 	"github.com/docker/docker/pkg/ioutils"
+	"golang.org/x/sync/semaphore"
+)
+
+const (
+	// defaultContainersStatsInterval is the interval at which snapshots are
+	// written by ContainersStats if no interval is specified.
+	defaultContainersStatsInterval = time.Second
+
+	// containersStatsParallelism is the maximum number of containers for
+	// which ContainersStats collects stats concurrently.
+	containersStatsParallelism = 16
 )
 
 // ContainerStats writes information about the container to the stream
@@ -136,6 +148,105 @@ func (daemon *Daemon) ContainerStats(ctx context.Context, prefixOrName string, c
 	}
 }
 
+// ContainersStats writes a snapshot of the stats of all running containers
+// matching the filters to the stream given in the config object. If streaming
+// is enabled, a new snapshot is written at every interval until the context
+// is cancelled.
+func (daemon *Daemon) ContainersStats(ctx context.Context, config *backend.ContainersStatsConfig) error {
+	interval := config.Interval
+	if interval == 0 {
+		interval = defaultContainersStatsInterval
+	}
+	if interval < time.Second {
+		return errdefs.InvalidParameter(errors.New("interval must be at least 1 second"))
+	}
+
+	// List the containers before writing any output, so that invalid filters
+	// are returned as an error instead of terminating the stream.
+	list, err := daemon.Containers(ctx, &types.ContainerListOptions{Filters: config.Filters})
+	if err != nil {
+		return err
+	}
+
+	outStream := config.OutStream
+	if config.Stream {
+		wf := ioutils.NewWriteFlusher(outStream)
+		defer wf.Close()
+		outStream = wf
+	}
+	enc := json.NewEncoder(outStream)
+
+	previous := make(map[string]*types.StatsJSON)
+	for {
+		current := daemon.collectContainersStats(ctx, list, previous)
+		snapshot := make([]*types.StatsJSON, 0, len(list))
+		for _, c := range list {
+			if s, ok := current[c.ID]; ok {
+				snapshot = append(snapshot, s)
+			}
+		}
+		if err := enc.Encode(snapshot); err != nil {
+			return err
+		}
+		if !config.Stream {
+			return nil
+		}
+		previous = current
+
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return nil
+		}
+		if list, err = daemon.Containers(ctx, &types.ContainerListOptions{Filters: config.Filters}); err != nil {
+			return err
+		}
+	}
+}
+
+// collectContainersStats collects the stats of the given containers, using
+// the stats of the previous collection to populate the PreCPUStats. Containers
+// that stopped, or for which no stats could be collected, are omitted.
+func (daemon *Daemon) collectContainersStats(ctx context.Context, list []*types.Container, previous map[string]*types.StatsJSON) map[string]*types.StatsJSON {
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		sem     = semaphore.NewWeighted(containersStatsParallelism)
+		current = make(map[string]*types.StatsJSON, len(list))
+	)
+	for _, c := range list {
+		if err := sem.Acquire(ctx, 1); err != nil {
+			break
+		}
+		wg.Add(1)
+		go func(id string) {
+			defer func() {
+				sem.Release(1)
+				wg.Done()
+			}()
+			ctr, err := daemon.GetContainer(id)
+			if err != nil || !ctr.IsRunning() || ctr.IsRestarting() {
+				return
+			}
+			s, err := daemon.GetContainerStats(ctr)
+			if err != nil {
+				return
+			}
+			s.Name = ctr.Name
+			s.ID = ctr.ID
+			if prev, ok := previous[ctr.ID]; ok {
+				s.PreCPUStats = prev.CPUStats
+				s.PreRead = prev.Read
+			}
+			mu.Lock()
+			current[ctr.ID] = s
+			mu.Unlock()
+		}(c.ID)
+	}
+	wg.Wait()
+	return current
+}
+
 func (daemon *Daemon) subscribeToContainerStats(c *container.Container) chan interface{} {
 	return daemon.statsCollector.Collect(c)
 }
@@ -157,6 +268,7 @@ func (daemon *Daemon) GetContainerStats(container *container.Container) (*types.
 			return nil, err
 		}
 	}
+	stats.Execs = daemon.getExecStats(container)
 
 	return stats, nil
 }
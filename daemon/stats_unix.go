@@ -9,7 +9,8 @@ import (
 	"github.com/pkg/errors"
 )
 
-// Resolve Network SandboxID in case the container reuse another container's network stack
+// Resolve Network SandboxID in case the container reuse another container's network stack,
+// or the network stack of a group
 func (daemon *Daemon) getNetworkSandboxID(c *container.Container) (string, error) {
 	curr := c
 	for curr.HostConfig.NetworkMode.IsContainer() {
@@ -20,6 +21,13 @@ func (daemon *Daemon) getNetworkSandboxID(c *container.Container) (string, error
 		}
 		curr = connected
 	}
+	if curr.HostConfig.NetworkMode.IsGroup() {
+		g, err := daemon.groups.Get(curr.HostConfig.NetworkMode.ConnectedGroup())
+		if err != nil {
+			return "", err
+		}
+		return g.SandboxID, nil
+	}
 	return curr.NetworkSettings.SandboxID, nil
 }
 
@@ -39,10 +47,11 @@ func (daemon *Daemon) getNetworkStats(c *container.Container) (map[string]types.
 		return nil, err
 	}
 
+	endpoints := sb.InterfaceEndpoints()
 	stats := make(map[string]types.NetworkStats)
 	// Convert libnetwork nw stats into api stats
 	for ifName, ifStats := range lnstats {
-		stats[ifName] = types.NetworkStats{
+		nwStats := types.NetworkStats{
 			RxBytes:   ifStats.RxBytes,
 			RxPackets: ifStats.RxPackets,
 			RxErrors:  ifStats.RxErrors,
@@ -52,6 +61,14 @@ func (daemon *Daemon) getNetworkStats(c *container.Container) (map[string]types.
 			TxErrors:  ifStats.TxErrors,
 			TxDropped: ifStats.TxDropped,
 		}
+		// Attribute the counters to the network the interface is attached to,
+		// so that a saturated network can be told apart from the others.
+		if ep, ok := endpoints[ifName]; ok {
+			nwStats.EndpointID = ep.ID()
+			nwStats.NetworkID = ep.NetworkID()
+			nwStats.NetworkName = ep.Network()
+		}
+		stats[ifName] = nwStats
 	}
 
 	return stats, nil
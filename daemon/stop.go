@@ -90,6 +90,11 @@ func (daemon *Daemon) containerStop(ctx context.Context, ctr *container.Containe
 		// the container has still not exited, and the kill function errored, so log the error here:
 		logrus.WithError(err).WithField("container", ctr.ID).Errorf("Error sending stop (signal %d) to container", stopSignal)
 	}
+	if ctx.Err() != nil {
+		// the request was cancelled (for example, the client disconnected) before
+		// the container exited; don't escalate to killing the container on its behalf.
+		return ctx.Err()
+	}
 	if stopTimeout < 0 {
 		// if the client requested that we never kill / wait forever, but container.Wait was still
 		// interrupted (parent context cancelled, for example), we should propagate the signal failure
@@ -0,0 +1,25 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import "github.com/docker/docker/api/types/container"
+
+// ContainerTemplateCreate stores a container create template.
+func (daemon *Daemon) ContainerTemplateCreate(req container.TemplateCreateRequest) (container.Template, error) {
+	return daemon.templates.Create(req.Name, req.Spec)
+}
+
+// ContainerTemplateInspect returns the container create template with the
+// given name.
+func (daemon *Daemon) ContainerTemplateInspect(name string) (container.Template, error) {
+	return daemon.templates.Get(name)
+}
+
+// ContainerTemplateList returns the container create templates.
+func (daemon *Daemon) ContainerTemplateList() []container.Template {
+	return daemon.templates.List()
+}
+
+// ContainerTemplateRemove removes the container create template with the
+// given name.
+func (daemon *Daemon) ContainerTemplateRemove(name string) error {
+	return daemon.templates.Remove(name)
+}
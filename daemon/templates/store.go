@@ -0,0 +1,120 @@
+// Package templates stores the container create templates of the daemon.
+package templates // import "github.com/docker/docker/daemon/templates"
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/daemon/names"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/ioutils"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Store persists templates as JSON files in a directory.
+type Store struct {
+	mu        sync.RWMutex
+	root      string
+	templates map[string]container.Template
+}
+
+// NewStore creates a store in the given directory, and loads the templates
+// it holds.
+func NewStore(root string) (*Store, error) {
+	if err := os.MkdirAll(root, 0o700); err != nil {
+		return nil, errors.Wrap(err, "error creating templates directory")
+	}
+	s := &Store{root: root, templates: make(map[string]container.Template)}
+	files, err := filepath.Glob(filepath.Join(root, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		b, err := os.ReadFile(f)
+		if err != nil {
+			return nil, errors.Wrap(err, "error loading template")
+		}
+		var t container.Template
+		if err := json.Unmarshal(b, &t); err != nil || t.Name+".json" != filepath.Base(f) {
+			logrus.WithError(err).WithField("file", f).Warn("ignoring invalid container template")
+			continue
+		}
+		s.templates[t.Name] = t
+	}
+	return s, nil
+}
+
+// Create stores a new template. It fails if a template with the same name
+// exists.
+func (s *Store) Create(name string, spec json.RawMessage) (container.Template, error) {
+	if !names.RestrictedNamePattern.MatchString(name) {
+		return container.Template{}, errdefs.InvalidParameter(errors.Errorf("invalid template name %q, only %s are allowed", name, names.RestrictedNameChars))
+	}
+	if !strings.HasPrefix(strings.TrimSpace(string(spec)), "{") || !json.Valid(spec) {
+		return container.Template{}, errdefs.InvalidParameter(errors.New("template spec must be a JSON object"))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.templates[name]; ok {
+		return container.Template{}, errdefs.Conflict(errors.Errorf("template %s already exists", name))
+	}
+	t := container.Template{Name: name, Spec: spec, CreatedAt: time.Now().UTC()}
+	b, err := json.Marshal(t)
+	if err != nil {
+		return container.Template{}, err
+	}
+	if err := ioutils.AtomicWriteFile(s.path(name), b, 0o600); err != nil {
+		return container.Template{}, errors.Wrap(err, "error saving template")
+	}
+	s.templates[name] = t
+	return t, nil
+}
+
+// Get returns the template with the given name.
+func (s *Store) Get(name string) (container.Template, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t, ok := s.templates[name]
+	if !ok {
+		return container.Template{}, errdefs.NotFound(errors.Errorf("no such template: %s", name))
+	}
+	return t, nil
+}
+
+// List returns the templates, sorted by name.
+func (s *Store) List() []container.Template {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]container.Template, 0, len(s.templates))
+	for _, t := range s.templates {
+		out = append(out, t)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// Remove removes the template with the given name.
+func (s *Store) Remove(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.templates[name]; !ok {
+		return errdefs.NotFound(errors.Errorf("no such template: %s", name))
+	}
+	if err := os.Remove(s.path(name)); err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "error removing template")
+	}
+	delete(s.templates, name)
+	return nil
+}
+
+func (s *Store) path(name string) string {
+	return filepath.Join(s.root, name+".json")
+}
@@ -0,0 +1,45 @@
+package templates // import "github.com/docker/docker/daemon/templates"
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/docker/docker/errdefs"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestStore(t *testing.T) {
+	root := t.TempDir()
+	s, err := NewStore(root)
+	assert.NilError(t, err)
+
+	_, err = s.Create("runner", json.RawMessage(`{"Image":"busybox"}`))
+	assert.NilError(t, err)
+	_, err = s.Create("runner", json.RawMessage(`{}`))
+	assert.Check(t, errdefs.IsConflict(err))
+	_, err = s.Create("../runner", json.RawMessage(`{}`))
+	assert.Check(t, errdefs.IsInvalidParameter(err))
+	_, err = s.Create("agent", json.RawMessage(`["busybox"]`))
+	assert.Check(t, errdefs.IsInvalidParameter(err))
+	_, err = s.Create("agent", json.RawMessage(`{"Image":"alpine"}`))
+	assert.NilError(t, err)
+
+	// Templates are persisted.
+	s, err = NewStore(root)
+	assert.NilError(t, err)
+	list := s.List()
+	assert.Assert(t, is.Len(list, 2))
+	assert.Check(t, is.Equal(list[0].Name, "agent"))
+	assert.Check(t, is.Equal(list[1].Name, "runner"))
+	assert.Check(t, is.Equal(string(list[1].Spec), `{"Image":"busybox"}`))
+
+	assert.NilError(t, s.Remove("runner"))
+	assert.Check(t, errdefs.IsNotFound(s.Remove("runner")))
+	_, err = s.Get("runner")
+	assert.Check(t, errdefs.IsNotFound(err))
+
+	s, err = NewStore(root)
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(s.List(), 1))
+}
@@ -7,13 +7,16 @@ import (
 	"strings"
 	"time"
 
+	"github.com/docker/docker/api/types/backend"
 	containertypes "github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/mount"
 	mounttypes "github.com/docker/docker/api/types/mount"
 	volumetypes "github.com/docker/docker/api/types/volume"
 	"github.com/docker/docker/container"
+	"github.com/docker/docker/daemon/config"
 	"github.com/docker/docker/errdefs"
 	"github.com/docker/docker/volume"
+	"github.com/docker/docker/volume/drivers"
 	volumemounts "github.com/docker/docker/volume/mounts"
 	"github.com/docker/docker/volume/service"
 	volumeopts "github.com/docker/docker/volume/service/opts"
@@ -149,6 +152,9 @@ func (daemon *Daemon) registerMountPoints(container *container.Container, hostCo
 			}
 			bind.Volume = &volumeWrapper{v: v, s: daemon.volumes}
 			bind.Source = v.Mountpoint
+			if isImageVolume(v) {
+				bind.RW = false
+			}
 			// bind.Name is an already existing volume, we need to use that here
 			bind.Driver = v.Driver
 			if bind.Driver == volume.DefaultDriverName {
@@ -202,6 +208,9 @@ func (daemon *Daemon) registerMountPoints(container *container.Container, hostCo
 			mp.Volume = &volumeWrapper{v: v, s: daemon.volumes}
 			mp.Name = v.Name
 			mp.Driver = v.Driver
+			if isImageVolume(v) {
+				mp.RW = false
+			}
 
 			// need to selinux-relabel local mounts
 			mp.Source = v.Mountpoint
@@ -256,6 +265,7 @@ func (daemon *Daemon) VolumesService() *service.VolumesService {
 
 type volumeMounter interface {
 	Mount(ctx context.Context, v *volumetypes.Volume, ref string) (string, error)
+	MountWithContext(ctx context.Context, v *volumetypes.Volume, ref string, mctx volume.MountContext) (string, error)
 	Unmount(ctx context.Context, v *volumetypes.Volume, ref string) error
 }
 
@@ -280,6 +290,10 @@ func (v *volumeWrapper) Mount(ref string) (string, error) {
 	return v.s.Mount(context.TODO(), v.v, ref)
 }
 
+func (v *volumeWrapper) MountWithContext(ref string, mctx volume.MountContext) (string, error) {
+	return v.s.MountWithContext(context.TODO(), v.v, ref, mctx)
+}
+
 func (v *volumeWrapper) Unmount(ref string) error {
 	return v.s.Unmount(context.TODO(), v.v, ref)
 }
@@ -291,3 +305,83 @@ func (v *volumeWrapper) CreatedAt() (time.Time, error) {
 func (v *volumeWrapper) Status() map[string]interface{} {
 	return v.v.Status
 }
+
+// isImageVolume returns whether a volume was populated from an image. Such
+// volumes are always mounted read-only.
+func isImageVolume(v *volumetypes.Volume) bool {
+	_, ok := v.Labels[service.ImageLabel]
+	return ok
+}
+
+// mountImage mounts the root filesystem of a local image, so that a volume
+// can be populated with its contents. It returns a function that unmounts
+// the image.
+func (daemon *Daemon) mountImage(ctx context.Context, ref string) (string, func(), error) {
+	_, roLayer, err := daemon.imageService.GetImageAndReleasableLayer(ctx, ref, backend.GetImageAndLayerOptions{PullOption: backend.PullOptionNoPull})
+	if err != nil {
+		return "", nil, err
+	}
+	rwLayer, err := roLayer.NewRWLayer()
+	if err != nil {
+		roLayer.Release()
+		return "", nil, errors.Wrapf(err, "failed to mount image %s", ref)
+	}
+	release := func() {
+		if err := rwLayer.Release(); err != nil {
+			logrus.WithError(err).WithField("image", ref).Warn("failed to unmount image after populating volume")
+		}
+		if err := roLayer.Release(); err != nil {
+			logrus.WithError(err).WithField("image", ref).Warn("failed to release image layer after populating volume")
+		}
+	}
+	return rwLayer.Root(), release, nil
+}
+
+// quiesceVolumeUsers pauses the running containers that hold the given
+// volume references, so that the contents of the volume are consistent
+// while it is exported. It returns a function that unpauses the containers.
+func (daemon *Daemon) quiesceVolumeUsers(ctx context.Context, refs []string) (func(), error) {
+	var paused []*container.Container
+	resume := func() {
+		for _, ctr := range paused {
+			if err := daemon.containerUnpause(ctr); err != nil {
+				logrus.WithError(err).WithField("container", ctr.ID).Warn("failed to unpause container after exporting volume")
+			}
+		}
+	}
+	for _, ref := range refs {
+		// References that are not container IDs, for example those held
+		// while another volume is populated, are skipped.
+		ctr := daemon.containers.Get(ref)
+		if ctr == nil || !ctr.IsRunning() || ctr.IsPaused() {
+			continue
+		}
+		if err := daemon.containerPause(ctr); err != nil {
+			resume()
+			return nil, errors.Wrapf(err, "failed to pause container %s", ctr.ID)
+		}
+		paused = append(paused, ctr)
+	}
+	return resume, nil
+}
+
+// volumePluginTimeouts converts the configured timeouts of the calls to
+// volume plugins. The configuration is validated, so that the timeouts parse.
+func volumePluginTimeouts(conf map[string]config.VolumePluginTimeoutsConfig) map[string]drivers.Timeouts {
+	parse := func(s string) time.Duration {
+		d, _ := time.ParseDuration(s)
+		return d
+	}
+	timeouts := make(map[string]drivers.Timeouts, len(conf))
+	for name, c := range conf {
+		timeouts[name] = drivers.Timeouts{
+			Create:  parse(c.Create),
+			Remove:  parse(c.Remove),
+			Mount:   parse(c.Mount),
+			Unmount: parse(c.Unmount),
+			Other:   parse(c.Other),
+			Async:   parse(c.Async),
+		}
+	}
+	return timeouts
+}
@@ -12,6 +12,7 @@ import (
 
 	mounttypes "github.com/docker/docker/api/types/mount"
 	"github.com/docker/docker/container"
+	"github.com/docker/docker/volume"
 	volumemounts "github.com/docker/docker/volume/mounts"
 )
 
@@ -47,7 +48,12 @@ func (daemon *Daemon) setupMounts(c *container.Container) ([]container.Mount, er
 			return nil
 		}
 
-		path, err := m.Setup(c.MountLabel, daemon.idMapping.RootPair(), checkfunc)
+		mctx := volume.MountContext{
+			ContainerID: c.ID,
+			UIDMaps:     daemon.idMapping.UIDMaps,
+			GIDMaps:     daemon.idMapping.GIDMaps,
+		}
+		path, err := m.SetupWithContext(c.MountLabel, daemon.idMapping.RootPair(), mctx, checkfunc)
 		if err != nil {
 			return nil, err
 		}
@@ -60,6 +66,7 @@ func (daemon *Daemon) setupMounts(c *container.Container) ([]container.Mount, er
 			}
 			if m.Spec.Type == mounttypes.TypeBind && m.Spec.BindOptions != nil {
 				mnt.NonRecursive = m.Spec.BindOptions.NonRecursive
+				mnt.IDMap = m.Spec.BindOptions.IDMap
 			}
 			if m.Volume != nil {
 				attributes := map[string]string{
@@ -0,0 +1,187 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"context"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	imagetypes "github.com/docker/docker/api/types/image"
+	daemonevents "github.com/docker/docker/daemon/events"
+	internalnetwork "github.com/docker/docker/daemon/network"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// watchLister lists the objects matching filter as watch events. If id is
+// set, it only lists the object with this ID, if it matches filter.
+type watchLister func(ctx context.Context, filter filters.Args, id string) ([]types.WatchEvent, error)
+
+// Watch streams the state changes of the containers, images, volumes or
+// networks matching filter to send, until ctx is done or send fails. The
+// objects matching filter are sent first, with the "sync" action. Then, on
+// each event of an object, the current state of the object is looked up and
+// sent with the action of the event. The state is not set if the object was
+// removed, or no longer matches filter.
+func (daemon *Daemon) Watch(ctx context.Context, typ events.Type, filter filters.Args, send func(types.WatchEvent) error) error {
+	var list watchLister
+	switch typ {
+	case events.ContainerEventType:
+		list = daemon.watchContainers
+	case events.ImageEventType:
+		list = daemon.watchImages
+	case events.VolumeEventType:
+		list = daemon.watchVolumes
+	case events.NetworkEventType:
+		list = daemon.watchNetworks
+	default:
+		return errdefs.InvalidParameter(errors.Errorf("cannot watch objects of type %q", typ))
+	}
+
+	// subscribe before listing the objects, so that no change is missed
+	ef := daemonevents.NewFilter(filters.NewArgs(filters.Arg("type", string(typ))))
+	_, l := daemon.EventsService.SubscribeTopic(time.Time{}, time.Time{}, ef)
+	defer daemon.EventsService.Evict(l)
+
+	objects, err := list(ctx, filter, "")
+	if err != nil {
+		return err
+	}
+	// known holds the objects the watcher knows about, so that it is only
+	// told about the removal of those
+	known := make(map[string]bool, len(objects))
+	now := time.Now().UnixNano()
+	for _, ev := range objects {
+		ev.Type = typ
+		ev.Action = "sync"
+		ev.TimeNano = now
+		known[ev.ID] = true
+		if err := send(ev); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case m := <-l:
+			msg, ok := m.(events.Message)
+			if !ok {
+				logrus.Warnf("unexpected event message: %q", m)
+				continue
+			}
+			ev := types.WatchEvent{ID: msg.Actor.ID}
+			if typ == events.ImageEventType {
+				// image events may refer to the image by reference
+				if img, err := daemon.imageService.GetImage(ctx, msg.Actor.ID, imagetypes.GetImageOpts{}); err == nil {
+					ev.ID = img.ID().String()
+				}
+			}
+			objects, err := list(ctx, filter, ev.ID)
+			if err != nil {
+				return err
+			}
+			if len(objects) > 0 {
+				ev = objects[0]
+				known[ev.ID] = true
+			} else if known[ev.ID] {
+				delete(known, ev.ID)
+			} else {
+				continue
+			}
+			ev.Type = typ
+			ev.Action = msg.Action
+			ev.TimeNano = msg.TimeNano
+			if err := send(ev); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (daemon *Daemon) watchContainers(ctx context.Context, filter filters.Args, id string) ([]types.WatchEvent, error) {
+	if id != "" && !filter.Contains("id") {
+		// narrow down the list, unless that would extend the id filter
+		// of the watch
+		filter = filter.Clone()
+		filter.Add("id", id)
+	}
+	containers, err := daemon.Containers(ctx, &types.ContainerListOptions{All: true, Filters: filter})
+	if err != nil {
+		return nil, err
+	}
+	var out []types.WatchEvent
+	for _, c := range containers {
+		if id == "" || c.ID == id {
+			out = append(out, types.WatchEvent{ID: c.ID, Container: c})
+		}
+	}
+	return out, nil
+}
+
+func (daemon *Daemon) watchImages(ctx context.Context, filter filters.Args, id string) ([]types.WatchEvent, error) {
+	images, err := daemon.imageService.Images(ctx, types.ImageListOptions{All: true, Filters: filter})
+	if err != nil {
+		return nil, err
+	}
+	var out []types.WatchEvent
+	for _, img := range images {
+		if id == "" || img.ID == id {
+			out = append(out, types.WatchEvent{ID: img.ID, Image: img})
+		}
+	}
+	return out, nil
+}
+
+func (daemon *Daemon) watchVolumes(ctx context.Context, filter filters.Args, id string) ([]types.WatchEvent, error) {
+	if id != "" {
+		v, err := daemon.volumes.GetMatching(ctx, id, filter)
+		if err != nil || v == nil {
+			if errdefs.IsNotFound(err) {
+				err = nil
+			}
+			return nil, err
+		}
+		return []types.WatchEvent{{ID: v.Name, Volume: v}}, nil
+	}
+	volumes, _, err := daemon.volumes.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]types.WatchEvent, 0, len(volumes))
+	for _, v := range volumes {
+		out = append(out, types.WatchEvent{ID: v.Name, Volume: v})
+	}
+	return out, nil
+}
+
+func (daemon *Daemon) watchNetworks(ctx context.Context, filter filters.Args, id string) ([]types.WatchEvent, error) {
+	var (
+		networks []types.NetworkResource
+		err      error
+	)
+	if id != "" {
+		// only look up the network of the event
+		n, findErr := daemon.FindNetwork(id)
+		if findErr != nil {
+			if errdefs.IsNotFound(findErr) {
+				return nil, nil
+			}
+			return nil, findErr
+		}
+		networks, err = internalnetwork.FilterNetworks([]types.NetworkResource{buildNetworkResource(n)}, filter)
+	} else {
+		networks, err = daemon.GetNetworks(filter, types.NetworkListConfig{})
+	}
+	if err != nil {
+		return nil, err
+	}
+	out := make([]types.WatchEvent, 0, len(networks))
+	for i := range networks {
+		out = append(out, types.WatchEvent{ID: networks[i].ID, Network: &networks[i]})
+	}
+	return out, nil
+}
@@ -0,0 +1,56 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"context"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/container"
+	daemonevents "github.com/docker/docker/daemon/events"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestWatchContainers(t *testing.T) {
+	db, err := container.NewViewDB()
+	assert.NilError(t, err)
+	d := &Daemon{
+		containersReplica: db,
+		EventsService:     daemonevents.New(),
+	}
+	c := setupContainerWithName(t, "watched", d)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	watchEvents := make(chan types.WatchEvent)
+	done := make(chan error, 1)
+	go func() {
+		done <- d.Watch(ctx, events.ContainerEventType, filters.NewArgs(), func(ev types.WatchEvent) error {
+			watchEvents <- ev
+			return nil
+		})
+	}()
+
+	ev := <-watchEvents
+	assert.Check(t, is.Equal(ev.Action, "sync"))
+	assert.Check(t, is.Equal(ev.ID, c.ID))
+	assert.Assert(t, ev.Container != nil)
+	assert.Check(t, is.Equal(ev.Container.State, "running"))
+
+	// events of containers the watcher does not know about are not sent
+	d.EventsService.Log("destroy", events.ContainerEventType, events.Actor{ID: "unknown"})
+
+	assert.NilError(t, db.Delete(c))
+	d.EventsService.Log("destroy", events.ContainerEventType, events.Actor{ID: c.ID})
+
+	ev = <-watchEvents
+	assert.Check(t, is.Equal(ev.Action, "destroy"))
+	assert.Check(t, is.Equal(ev.ID, c.ID))
+	assert.Check(t, ev.Container == nil)
+
+	cancel()
+	assert.Check(t, <-done)
+}
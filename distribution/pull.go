@@ -6,6 +6,7 @@ import (
 
 	"github.com/docker/distribution/reference"
 	"github.com/docker/docker/api"
+	"github.com/docker/docker/api/types/registry"
 	refstore "github.com/docker/docker/reference"
 	"github.com/opencontainers/go-digest"
 	"github.com/pkg/errors"
@@ -26,6 +27,17 @@ func Pull(ctx context.Context, ref reference.Named, config *ImagePullConfig, loc
 		return err
 	}
 
+	if config.AuthConfig == nil || *config.AuthConfig == (registry.AuthConfig{}) {
+		// Pulls which were given no credentials, such as the pulls of swarm
+		// tasks, use the credential helper of the registry, if any.
+		authConfig, err := config.RegistryService.HelperAuthConfig(ctx, repoInfo.Index.Name)
+		if err != nil {
+			logrus.WithError(err).Warn("Continuing without credentials")
+		} else if authConfig != nil {
+			config.AuthConfig = authConfig
+		}
+	}
+
 	endpoints, err := config.RegistryService.LookupPullEndpoints(reference.Domain(repoInfo.Name))
 	if err != nil {
 		return err
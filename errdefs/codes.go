@@ -0,0 +1,114 @@
+package errdefs // import "github.com/docker/docker/errdefs"
+
+// ErrCode is implemented by errors that carry a stable, machine-readable code
+// describing the specific failure, e.g. "container_name_conflict".
+type ErrCode interface {
+	ErrorCode() string
+}
+
+// ErrDetails is implemented by errors that carry structured details about
+// the failure, such as the ID of a conflicting object.
+type ErrDetails interface {
+	ErrorDetails() map[string]string
+}
+
+// Generic error codes, used when an error doesn't provide a more specific
+// code itself. They mirror the error classes defined in this package.
+const (
+	CodeNotFound         = "not_found"
+	CodeInvalidParameter = "invalid_parameter"
+	CodeConflict         = "conflict"
+	CodeUnauthorized     = "unauthorized"
+	CodeUnavailable      = "unavailable"
+	CodeForbidden        = "forbidden"
+	CodeSystem           = "system"
+	CodeNotModified      = "not_modified"
+	CodeNotImplemented   = "not_implemented"
+	CodeCancelled        = "cancelled"
+	CodeDeadline         = "deadline_exceeded"
+	CodeDataLoss         = "data_loss"
+	CodeUnknown          = "unknown"
+)
+
+type unwrapper interface {
+	Unwrap() error
+}
+
+// walk calls fn for err and every error in its causal chain, following both
+// Cause() and Unwrap(), until fn returns true.
+func walk(err error, fn func(error) bool) {
+	for err != nil {
+		if fn(err) {
+			return
+		}
+		switch e := err.(type) {
+		case causer:
+			err = e.Cause()
+		case unwrapper:
+			err = e.Unwrap()
+		default:
+			return
+		}
+	}
+}
+
+// Code returns the machine-readable code for err. The first non-empty code
+// found in the causal chain is used; if there is none, the generic code for
+// the error's class is returned.
+func Code(err error) string {
+	if err == nil {
+		return ""
+	}
+	var code string
+	walk(err, func(e error) bool {
+		if c, ok := e.(ErrCode); ok {
+			code = c.ErrorCode()
+		}
+		return code != ""
+	})
+	if code != "" {
+		return code
+	}
+
+	switch getImplementer(err).(type) {
+	case ErrNotFound:
+		return CodeNotFound
+	case ErrInvalidParameter:
+		return CodeInvalidParameter
+	case ErrConflict:
+		return CodeConflict
+	case ErrUnauthorized:
+		return CodeUnauthorized
+	case ErrUnavailable:
+		return CodeUnavailable
+	case ErrForbidden:
+		return CodeForbidden
+	case ErrSystem:
+		return CodeSystem
+	case ErrNotModified:
+		return CodeNotModified
+	case ErrNotImplemented:
+		return CodeNotImplemented
+	case ErrCancelled:
+		return CodeCancelled
+	case ErrDeadline:
+		return CodeDeadline
+	case ErrDataLoss:
+		return CodeDataLoss
+	default:
+		return CodeUnknown
+	}
+}
+
+// Details returns the structured details of the first error in the causal
+// chain of err that provides any, or nil.
+func Details(err error) map[string]string {
+	var details map[string]string
+	walk(err, func(e error) bool {
+		if d, ok := e.(ErrDetails); ok {
+			details = d.ErrorDetails()
+		}
+		return len(details) > 0
+	})
+	return details
+}
@@ -0,0 +1,50 @@
+package errdefs // import "github.com/docker/docker/errdefs"
+
+import (
+	"fmt"
+	"testing"
+)
+
+type codedError struct {
+	code    string
+	details map[string]string
+}
+
+func (e codedError) Error() string                   { return "coded error" }
+func (e codedError) ErrorCode() string               { return e.code }
+func (e codedError) ErrorDetails() map[string]string { return e.details }
+
+func TestCode(t *testing.T) {
+	coded := codedError{code: "thing_exploded", details: map[string]string{"thing": "foo"}}
+
+	tests := []struct {
+		err  error
+		code string
+	}{
+		{err: nil, code: ""},
+		{err: errTest, code: CodeUnknown},
+		{err: NotFound(errTest), code: CodeNotFound},
+		{err: Conflict(errTest), code: CodeConflict},
+		{err: Deadline(errTest), code: CodeDeadline},
+		{err: coded, code: "thing_exploded"},
+		{err: Conflict(coded), code: "thing_exploded"},
+		{err: fmt.Errorf("wrapped: %w", coded), code: "thing_exploded"},
+		{err: Conflict(codedError{}), code: CodeConflict},
+	}
+	for _, tc := range tests {
+		if code := Code(tc.err); code != tc.code {
+			t.Errorf("expected code %q for %v, got %q", tc.code, tc.err, code)
+		}
+	}
+}
+
+func TestDetails(t *testing.T) {
+	if d := Details(NotFound(errTest)); d != nil {
+		t.Fatalf("expected no details, got %v", d)
+	}
+	coded := codedError{code: "thing_exploded", details: map[string]string{"thing": "foo"}}
+	d := Details(System(fmt.Errorf("wrapped: %w", coded)))
+	if d["thing"] != "foo" {
+		t.Fatalf("expected details of wrapped error, got %v", d)
+	}
+}
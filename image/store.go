@@ -1,6 +1,7 @@
 package image // import "github.com/docker/docker/image"
 
 import (
+	"encoding/json"
 	"fmt"
 	"sync"
 	"time"
@@ -24,6 +25,7 @@ type Store interface {
 	GetParent(id ID) (ID, error)
 	SetLastUpdated(id ID) error
 	GetLastUpdated(id ID) (time.Time, error)
+	SetLabels(id ID, labels map[string]string) (ID, error)
 	Children(id ID) []ID
 	Map() map[ID]*Image
 	Heads() map[ID]*Image
@@ -295,6 +297,56 @@ func (is *store) GetLastUpdated(id ID) (time.Time, error) {
 	return time.Parse(time.RFC3339Nano, string(bytes))
 }
 
+// SetLabels creates an image from the configuration of the image ID with its
+// labels replaced, and returns the ID of the new image. The labels of an
+// image are part of its configuration, and so of its ID. Other fields of the
+// configuration are kept as is, and the new image has the parent of the
+// image ID.
+func (is *store) SetLabels(id ID, labels map[string]string) (ID, error) {
+	config, err := is.fs.Get(id.Digest())
+	if err != nil {
+		return "", errdefs.NotFound(err)
+	}
+	var img map[string]*json.RawMessage
+	if err := json.Unmarshal(config, &img); err != nil {
+		return "", errdefs.System(err)
+	}
+	var runConfig map[string]*json.RawMessage
+	if raw := img["config"]; raw != nil {
+		if err := json.Unmarshal(*raw, &runConfig); err != nil {
+			return "", errdefs.System(err)
+		}
+	}
+	if runConfig == nil {
+		runConfig = make(map[string]*json.RawMessage)
+	}
+	b, err := json.Marshal(labels)
+	if err != nil {
+		return "", err
+	}
+	rawLabels := json.RawMessage(b)
+	runConfig["Labels"] = &rawLabels
+	if b, err = json.Marshal(runConfig); err != nil {
+		return "", err
+	}
+	rawConfig := json.RawMessage(b)
+	img["config"] = &rawConfig
+	if config, err = json.Marshal(img); err != nil {
+		return "", err
+	}
+
+	newID, err := is.Create(config)
+	if err != nil {
+		return "", err
+	}
+	if parent, err := is.GetParent(id); err == nil && newID != id {
+		if err := is.SetParent(newID, parent); err != nil {
+			return "", err
+		}
+	}
+	return newID, nil
+}
+
 func (is *store) Children(id ID) []ID {
 	is.RLock()
 	defer is.RUnlock()
@@ -2,6 +2,7 @@ package image // import "github.com/docker/docker/image"
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/docker/docker/errdefs"
@@ -188,6 +189,35 @@ func TestGetAndSetLastUpdated(t *testing.T) {
 	assert.Check(t, is.Equal(updated.IsZero(), false))
 }
 
+func TestSetLabels(t *testing.T) {
+	store, cleanup := defaultImageStore(t)
+	defer cleanup()
+
+	id, err := store.Create([]byte(`{"config": {"Labels": {"a": "1"}, "User": "foo"}, "rootfs": {"type": "layers"}, "extra": 1}`))
+	assert.NilError(t, err)
+
+	newID, err := store.SetLabels(id, map[string]string{"b": "2"})
+	assert.NilError(t, err)
+	assert.Check(t, newID != id)
+
+	img, err := store.Get(newID)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(img.Config.Labels, map[string]string{"b": "2"}))
+	assert.Check(t, is.Equal(img.Config.User, "foo"))
+	assert.Check(t, is.Contains(string(img.RawJSON()), `"extra":1`))
+
+	img, err = store.Get(id)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(img.Config.Labels, map[string]string{"a": "1"}))
+
+	sameID, err := store.SetLabels(newID, map[string]string{"b": "2"})
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(sameID, newID))
+
+	_, err = store.SetLabels(ID("sha256:"+strings.Repeat("0", 64)), nil)
+	assert.Check(t, errdefs.IsNotFound(err))
+}
+
 func TestStoreLen(t *testing.T) {
 	store, cleanup := defaultImageStore(t)
 	defer cleanup()
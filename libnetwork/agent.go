@@ -666,7 +666,14 @@ func (ep *Endpoint) addServiceInfoToCluster(sb *Sandbox) error {
 	}
 
 	if agent != nil {
-		if err := agent.networkDB.CreateEntry(libnetworkEPTable, n.ID(), ep.ID(), buf); err != nil {
+		// The entry of a task which was disabled still exists, and is
+		// updated to enable it again on the other nodes.
+		if _, err := agent.networkDB.GetEntry(libnetworkEPTable, n.ID(), ep.ID()); err == nil {
+			if err := agent.networkDB.UpdateEntry(libnetworkEPTable, n.ID(), ep.ID(), buf); err != nil {
+				logrus.Warnf("addServiceInfoToCluster NetworkDB UpdateEntry failed for %s %s err:%s", ep.id, n.id, err)
+				return err
+			}
+		} else if err := agent.networkDB.CreateEntry(libnetworkEPTable, n.ID(), ep.ID(), buf); err != nil {
 			logrus.Warnf("addServiceInfoToCluster NetworkDB CreateEntry failed for %s %s err:%s", ep.id, n.id, err)
 			return err
 		}
@@ -946,6 +953,12 @@ func (c *Controller) handleEpTableEvent(ev events.Event) {
 	switch ev.(type) {
 	case networkdb.CreateEvent:
 		logrus.Debugf("handleEpTableEvent ADD %s R:%v", eid, epRec)
+		if svcID != "" && epRec.ServiceDisabled {
+			// The entry of a task which was taken out of its service
+			// before this node joined the network, for example to attach
+			// a standalone container to it. It is added once re-enabled.
+			return
+		}
 		if svcID != "" {
 			// This is a remote task part of a service
 			if err := c.addServiceBinding(svcName, svcID, nid, eid, containerName, vip, ingressPorts, serviceAliases, taskAliases, ip, "handleEpTableEvent"); err != nil {
@@ -975,12 +988,19 @@ func (c *Controller) handleEpTableEvent(ev events.Event) {
 		}
 	case networkdb.UpdateEvent:
 		logrus.Debugf("handleEpTableEvent UPD %s R:%v", eid, epRec)
-		// We currently should only get these to inform us that an endpoint
-		// is disabled.  Report if otherwise.
-		if svcID == "" || !epRec.ServiceDisabled {
+		// We should only get these to inform us that the endpoint of a
+		// task is disabled or enabled again. Report if otherwise.
+		if svcID == "" {
 			logrus.Errorf("Unexpected update table event for %s epRec:%v", eid, epRec)
 			return
 		}
+		if !epRec.ServiceDisabled {
+			// This is a remote task that is part of a service again
+			if err := c.addServiceBinding(svcName, svcID, nid, eid, containerName, vip, ingressPorts, serviceAliases, taskAliases, ip, "handleEpTableEvent"); err != nil {
+				logrus.Errorf("failed enabling service binding for %s epRec:%v err:%v", eid, epRec, err)
+			}
+			return
+		}
 		// This is a remote task that is part of a service that is now disabled
 		if err := c.rmServiceBinding(svcName, svcID, nid, eid, containerName, vip, ingressPorts, serviceAliases, taskAliases, ip, "handleEpTableEvent", true, false); err != nil {
 			logrus.Errorf("failed disabling service binding for %s epRec:%v err:%v", eid, epRec, err)
@@ -0,0 +1,101 @@
+package libnetwork
+
+import (
+	"strings"
+
+	"github.com/docker/docker/pkg/stringid"
+)
+
+// AliasOptions are the DNS options of an alias of an endpoint.
+type AliasOptions struct {
+	// TTL is the TTL of the DNS answers for the alias, in seconds. The
+	// default TTL of the embedded DNS server is used if it is 0.
+	TTL uint32 `json:",omitempty"`
+	// Peers are the names or IDs of the containers which can resolve the
+	// alias. The alias resolves for all the containers on the network if it
+	// is empty.
+	Peers []string `json:",omitempty"`
+}
+
+func (o AliasOptions) isZero() bool {
+	return o.TTL == 0 && len(o.Peers) == 0
+}
+
+// aliasPeer identifies the container which resolves a name, to check the
+// aliases scoped to some peers.
+type aliasPeer struct {
+	name        string
+	containerID string
+}
+
+func (o AliasOptions) visibleTo(peer aliasPeer) bool {
+	if len(o.Peers) == 0 {
+		return true
+	}
+	for _, p := range o.Peers {
+		p = strings.TrimPrefix(p, "/")
+		if p == "" {
+			continue
+		}
+		if p == peer.name || p == peer.containerID || (peer.containerID != "" && p == stringid.TruncateID(peer.containerID)) {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateOptionMyAliasOptions function returns an option setter for setting
+// the DNS options of an endpoint's self alias.
+func CreateOptionMyAliasOptions(alias string, opts AliasOptions) EndpointOption {
+	return func(ep *Endpoint) {
+		if opts.isZero() {
+			return
+		}
+		if ep.myAliasOptions == nil {
+			ep.myAliasOptions = make(map[string]AliasOptions)
+		}
+		ep.myAliasOptions[alias] = opts
+	}
+}
+
+// MyAliasOptions returns the DNS options of the endpoint's self aliases
+// which have some.
+func (ep *Endpoint) MyAliasOptions() map[string]AliasOptions {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+
+	return ep.myAliasOptions
+}
+
+// setAliasOptions records the DNS options of an alias of a service on the
+// network, which the resolution of the alias honors. Options are removed
+// when opts is nil.
+func (n *network) setAliasOptions(alias, serviceID string, opts *AliasOptions) {
+	if n.ingress {
+		return
+	}
+	c := n.getController()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	sr, ok := c.svcRecords[n.ID()]
+	if !ok {
+		return
+	}
+	alias = strings.ToLower(alias)
+	if opts == nil {
+		delete(sr.aliasOptions[alias], serviceID)
+		if len(sr.aliasOptions[alias]) == 0 {
+			delete(sr.aliasOptions, alias)
+		}
+		return
+	}
+	if sr.aliasOptions == nil {
+		sr.aliasOptions = make(map[string]map[string]AliasOptions)
+		c.svcRecords[n.ID()] = sr
+	}
+	if sr.aliasOptions[alias] == nil {
+		sr.aliasOptions[alias] = make(map[string]AliasOptions)
+	}
+	sr.aliasOptions[alias][serviceID] = *opts
+}
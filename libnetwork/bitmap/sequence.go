@@ -342,6 +342,29 @@ func (h *Bitmap) Unselected() uint64 {
 	return h.unselected
 }
 
+// SetBits returns the ordinals of the bits which are set, in ascending order.
+func (h *Bitmap) SetBits() []uint64 {
+	var (
+		ordinals []uint64
+		pos      uint64
+	)
+	for s := h.head; s != nil; s = s.next {
+		if s.block == 0 {
+			pos += s.count * uint64(blockLen)
+			continue
+		}
+		for i := uint64(0); i < s.count; i++ {
+			for bitSel := blockFirstBit; bitSel > 0; bitSel >>= 1 {
+				if s.block&bitSel != 0 && pos < h.bits {
+					ordinals = append(ordinals, pos)
+				}
+				pos++
+			}
+		}
+	}
+	return ordinals
+}
+
 func (h *Bitmap) String() string {
 	return fmt.Sprintf("Bits: %d, Unselected: %d, Sequence: %s Curr:%d",
 		h.bits, h.unselected, h.head.toString(), h.curr)
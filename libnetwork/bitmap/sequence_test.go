@@ -1253,3 +1253,37 @@ func TestMarshalJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestSetBits(t *testing.T) {
+	hnd := New(100)
+	if bits := hnd.SetBits(); len(bits) != 0 {
+		t.Fatalf("Expected no bits set, got %v", bits)
+	}
+
+	expected := []uint64{0, 1, 31, 32, 64, 65, 66, 99}
+	for _, o := range []uint64{99, 64, 0, 32, 1, 66, 31, 65} {
+		if err := hnd.Set(o); err != nil {
+			t.Fatal(err)
+		}
+	}
+	bits := hnd.SetBits()
+	if len(bits) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, bits)
+	}
+	for i := range expected {
+		if bits[i] != expected[i] {
+			t.Fatalf("Expected %v, got %v", expected, bits)
+		}
+	}
+
+	// A run of full blocks.
+	hnd = New(128)
+	for i := uint64(0); i < 96; i++ {
+		if err := hnd.Set(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if bits := hnd.SetBits(); len(bits) != 96 || bits[95] != 95 {
+		t.Fatalf("Expected the first 96 bits, got %v", bits)
+	}
+}
@@ -219,6 +219,13 @@ func (h *Handle) Unselected() uint64 {
 	return h.bm.Unselected()
 }
 
+// SetBits returns the ordinals of the bits which are set, in ascending order.
+func (h *Handle) SetBits() []uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.bm.SetBits()
+}
+
 func (h *Handle) String() string {
 	h.mu.Lock()
 	defer h.mu.Unlock()
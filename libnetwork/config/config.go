@@ -29,9 +29,11 @@ type Config struct {
 	ClusterProvider        cluster.Provider
 	NetworkControlPlaneMTU int
 	DefaultAddressPool     []*ipamutils.NetworkToSplit
+	RouteOverlapMode       ipamutils.RouteOverlapMode
 	Scopes                 map[string]*datastore.ScopeCfg
 	ActiveSandboxes        map[string]interface{}
 	PluginGetter           plugingetter.PluginGetter
+	StartupTracer          func(phase string) func()
 }
 
 // New creates a new Config and initializes it with the given Options.
@@ -83,6 +85,14 @@ func OptionDefaultAddressPoolConfig(addressPool []*ipamutils.NetworkToSplit) Opt
 	}
 }
 
+// OptionRouteOverlapMode returns an option setter for how the subnets of
+// the default address pools which overlap the routes of the host are handled.
+func OptionRouteOverlapMode(mode ipamutils.RouteOverlapMode) Option {
+	return func(c *Config) {
+		c.RouteOverlapMode = mode
+	}
+}
+
 // OptionDriverConfig returns an option setter for driver configuration.
 func OptionDriverConfig(networkType string, config map[string]interface{}) Option {
 	return func(c *Config) {
@@ -176,6 +186,42 @@ func OptionLocalKVProviderConfig(config *store.Config) Option {
 	}
 }
 
+// OptionGlobalKVProvider function returns an option setter for the global
+// kvstore provider
+func OptionGlobalKVProvider(provider string) Option {
+	return func(c *Config) {
+		logrus.Debugf("Option OptionGlobalKVProvider: %s", provider)
+		if _, ok := c.Scopes[datastore.GlobalScope]; !ok {
+			c.Scopes[datastore.GlobalScope] = &datastore.ScopeCfg{}
+		}
+		c.Scopes[datastore.GlobalScope].Client.Provider = strings.TrimSpace(provider)
+	}
+}
+
+// OptionGlobalKVProviderURL function returns an option setter for the global
+// kvstore url
+func OptionGlobalKVProviderURL(url string) Option {
+	return func(c *Config) {
+		logrus.Debugf("Option OptionGlobalKVProviderURL: %s", url)
+		if _, ok := c.Scopes[datastore.GlobalScope]; !ok {
+			c.Scopes[datastore.GlobalScope] = &datastore.ScopeCfg{}
+		}
+		c.Scopes[datastore.GlobalScope].Client.Address = strings.TrimSpace(url)
+	}
+}
+
+// OptionGlobalKVProviderConfig function returns an option setter for the
+// global kvstore config
+func OptionGlobalKVProviderConfig(config *store.Config) Option {
+	return func(c *Config) {
+		logrus.Debugf("Option OptionGlobalKVProviderConfig: %v", config)
+		if _, ok := c.Scopes[datastore.GlobalScope]; !ok {
+			c.Scopes[datastore.GlobalScope] = &datastore.ScopeCfg{}
+		}
+		c.Scopes[datastore.GlobalScope].Client.Config = config
+	}
+}
+
 // OptionActiveSandboxes function returns an option setter for passing the sandboxes
 // which were active during previous daemon life
 func OptionActiveSandboxes(sandboxes map[string]interface{}) Option {
@@ -183,3 +229,12 @@ func OptionActiveSandboxes(sandboxes map[string]interface{}) Option {
 		c.ActiveSandboxes = sandboxes
 	}
 }
+
+// OptionStartupTracer function returns an option setter for the function
+// called when the controller starts a phase of its initialization, which
+// returns the function called when the phase ends.
+func OptionStartupTracer(tracer func(phase string) func()) Option {
+	return func(c *Config) {
+		c.StartupTracer = tracer
+	}
+}
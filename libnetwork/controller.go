@@ -104,6 +104,7 @@ type Controller struct {
 	agentStopDone    chan struct{}
 	keys             []*types.EncryptionKey
 	DiagnosticServer *diagnostic.Server
+	ipamLeaks        map[string]time.Time
 	mu               sync.Mutex
 }
 
@@ -125,11 +126,15 @@ func New(cfgOptions ...config.Option) (*Controller, error) {
 		DiagnosticServer: diagnostic.New(),
 	}
 	c.DiagnosticServer.Init()
+	c.DiagnosticServer.RegisterHandler(c, diagPaths2Func)
 
+	done := c.startPhase("stores")
 	if err := c.initStores(); err != nil {
 		return nil, err
 	}
+	done()
 
+	done = c.startPhase("drivers")
 	drvRegistry, err := drvregistry.New(c.getStore(datastore.LocalScope), c.getStore(datastore.GlobalScope), c.RegisterDriver, nil, c.cfg.PluginGetter)
 	if err != nil {
 		return nil, err
@@ -154,18 +159,32 @@ func New(cfgOptions ...config.Option) (*Controller, error) {
 	}
 
 	c.drvRegistry = drvRegistry
+	if c.cfg.RouteOverlapMode != "" {
+		if err := c.SetRouteOverlapMode(c.cfg.RouteOverlapMode); err != nil {
+			return nil, err
+		}
+	}
+	done()
 
 	c.WalkNetworks(populateSpecial)
 
 	// Reserve pools first before doing cleanup. Otherwise the
 	// cleanups of endpoint/network and sandbox below will
 	// generate many unnecessary warnings
+	done = c.startPhase("reserve-pools")
 	c.reservePools()
+	done()
 
 	// Cleanup resources
+	done = c.startPhase("sandbox-cleanup")
 	c.sandboxCleanup(c.cfg.ActiveSandboxes)
+	done()
+	done = c.startPhase("endpoint-cleanup")
 	c.cleanupLocalEndpoints()
+	done()
+	done = c.startPhase("network-cleanup")
 	c.networkCleanup()
+	done()
 
 	if err := c.startExternalKeyListener(); err != nil {
 		return nil, err
@@ -175,6 +194,16 @@ func New(cfgOptions ...config.Option) (*Controller, error) {
 	return c, nil
 }
 
+// startPhase reports the start of a phase of the initialization of the
+// controller to the startup tracer, and returns the function reporting its
+// end.
+func (c *Controller) startPhase(name string) func() {
+	if c.cfg.StartupTracer == nil {
+		return func() {}
+	}
+	return c.cfg.StartupTracer(name)
+}
+
 // SetClusterProvider sets the cluster provider.
 func (c *Controller) SetClusterProvider(provider cluster.Provider) {
 	var sameProvider bool
@@ -0,0 +1,92 @@
+package datastore
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/docker/docker/libnetwork/types"
+	"github.com/docker/libkv/store"
+	"github.com/docker/libkv/store/boltdb"
+)
+
+// Backend is a KV store implementation which can back the datastore of a
+// scope. Backends are registered under the name of their provider, which is
+// selected by the Provider of the scope configuration.
+type Backend interface {
+	// New returns a store connected to the given addresses.
+	New(addrs []string, options *store.Config) (store.Store, error)
+	// ParseAddress splits the address of the scope configuration into the
+	// addresses passed to New, and an optional prefix to add to the root
+	// chain of the keys.
+	ParseAddress(addr string) (addrs []string, prefix string)
+}
+
+// BackendFunc adapts a libkv store constructor to a Backend. The address
+// of the store is a comma separated list of endpoints, optionally followed
+// by a slash and a key prefix.
+type BackendFunc func(addrs []string, options *store.Config) (store.Store, error)
+
+// New calls f(addrs, options).
+func (f BackendFunc) New(addrs []string, options *store.Config) (store.Store, error) {
+	return f(addrs, options)
+}
+
+// ParseAddress parses an URI address such as "host1:2379,host2:2379/prefix".
+func (f BackendFunc) ParseAddress(addr string) ([]string, string) {
+	parts := strings.SplitN(addr, "/", 2)
+	if len(parts) == 2 {
+		return strings.Split(parts[0], ","), parts[1]
+	}
+	return strings.Split(parts[0], ","), ""
+}
+
+// fileBackend is a Backend whose address is a comma separated list of file
+// paths.
+type fileBackend struct {
+	BackendFunc
+}
+
+func (fileBackend) ParseAddress(addr string) ([]string, string) {
+	return strings.Split(addr, ","), ""
+}
+
+var (
+	backendsMu sync.RWMutex
+	backends   = map[string]Backend{
+		string(store.BOLTDB): fileBackend{boltdb.New},
+	}
+)
+
+// RegisterBackend registers the backend of a KV store provider, replacing
+// the previous one if any. Backends are compiled in the daemon, and register
+// themselves from the init function of their package, before the stores of
+// the network controller are initialized.
+func RegisterBackend(provider string, backend Backend) {
+	backendsMu.Lock()
+	defer backendsMu.Unlock()
+	backends[provider] = backend
+}
+
+// Backends returns the sorted list of the registered KV store providers.
+func Backends() []string {
+	backendsMu.RLock()
+	defer backendsMu.RUnlock()
+	providers := make([]string, 0, len(backends))
+	for p := range backends {
+		providers = append(providers, p)
+	}
+	sort.Strings(providers)
+	return providers
+}
+
+// getBackend returns the backend registered for a KV store provider.
+func getBackend(provider string) (Backend, error) {
+	backendsMu.RLock()
+	backend, ok := backends[provider]
+	backendsMu.RUnlock()
+	if !ok {
+		return nil, types.BadRequestErrorf("unsupported datastore provider %q, supported providers: %s", provider, strings.Join(Backends(), ", "))
+	}
+	return backend, nil
+}
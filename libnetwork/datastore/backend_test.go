@@ -0,0 +1,49 @@
+package datastore
+
+import (
+	"testing"
+
+	"github.com/docker/docker/libnetwork/types"
+	"github.com/docker/libkv/store"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestRegisterBackend(t *testing.T) {
+	defer func() { rootChain = defaultRootChain }()
+
+	var gotAddrs []string
+	RegisterBackend("mock", BackendFunc(func(addrs []string, options *store.Config) (store.Store, error) {
+		gotAddrs = addrs
+		return NewMockStore(), nil
+	}))
+	defer func() {
+		backendsMu.Lock()
+		delete(backends, "mock")
+		backendsMu.Unlock()
+	}()
+	assert.Check(t, is.DeepEqual(Backends(), []string{"boltdb", "mock"}))
+
+	config := &ScopeCfg{}
+	config.Client.Provider = "mock"
+	config.Client.Address = "host1:2379,host2:2379/custom"
+	ds, err := NewDataStore(GlobalScope, config)
+	assert.NilError(t, err)
+	defer ds.Close()
+	assert.Check(t, is.DeepEqual(gotAddrs, []string{"host1:2379", "host2:2379"}))
+	assert.Check(t, is.Equal(Key("network"), "custom/docker/network/v1.0/network/"))
+
+	config.Client.Provider = "unknown"
+	_, err = NewDataStore(GlobalScope, config)
+	assert.Check(t, is.ErrorContains(err, `unsupported datastore provider "unknown"`))
+	_, ok := err.(types.BadRequestError)
+	assert.Check(t, ok)
+}
+
+func TestBoltdbBackendParseAddress(t *testing.T) {
+	backend, err := getBackend(string(store.BOLTDB))
+	assert.NilError(t, err)
+	addrs, prefix := backend.ParseAddress("/var/lib/docker/network/files/local-kv.db")
+	assert.Check(t, is.DeepEqual(addrs, []string{"/var/lib/docker/network/files/local-kv.db"}))
+	assert.Check(t, is.Equal(prefix, ""))
+}
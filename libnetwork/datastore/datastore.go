@@ -10,7 +10,6 @@ import (
 
 	"github.com/docker/docker/libnetwork/discoverapi"
 	"github.com/docker/docker/libnetwork/types"
-	"github.com/docker/libkv"
 	"github.com/docker/libkv/store"
 )
 
@@ -206,23 +205,18 @@ func newClient(scope string, kv string, addr string, config *store.Config, cache
 		config = &store.Config{}
 	}
 
-	var addrs []string
-
-	if kv == string(store.BOLTDB) {
-		// Parse file path
-		addrs = strings.Split(addr, ",")
-	} else {
-		// Parse URI
-		parts := strings.SplitN(addr, "/", 2)
-		addrs = strings.Split(parts[0], ",")
+	backend, err := getBackend(kv)
+	if err != nil {
+		return nil, err
+	}
 
+	addrs, prefix := backend.ParseAddress(addr)
+	if prefix != "" {
 		// Add the custom prefix to the root chain
-		if len(parts) == 2 {
-			rootChain = append([]string{parts[1]}, defaultRootChain...)
-		}
+		rootChain = append([]string{prefix}, defaultRootChain...)
 	}
 
-	s, err := libkv.NewStore(store.Backend(kv), addrs, config)
+	s, err := backend.New(addrs, config)
 	if err != nil {
 		return nil, err
 	}
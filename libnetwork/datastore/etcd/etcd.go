@@ -0,0 +1,610 @@
+// Package etcd implements a datastore backend on top of the v3 API of etcd,
+// through its JSON gateway.
+package etcd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/libnetwork/datastore"
+	"github.com/docker/go-connections/tlsconfig"
+	"github.com/docker/libkv/store"
+	"github.com/sirupsen/logrus"
+)
+
+// Provider is the name of the etcd v3 datastore provider.
+const Provider = "etcd"
+
+const defaultRequestTimeout = 10 * time.Second
+
+func init() {
+	datastore.RegisterBackend(Provider, datastore.BackendFunc(New))
+}
+
+// Etcd is a store.Store backed by an etcd v3 cluster.
+type Etcd struct {
+	client    *http.Client
+	endpoints []string
+	timeout   time.Duration
+	username  string
+	password  string
+
+	mu    sync.Mutex
+	token string
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// New creates a store connected to the etcd endpoints at the given
+// addresses. The endpoints are reached over https when the TLS
+// configuration of the options is set, and authenticated with the
+// username and password of the options if any.
+func New(addrs []string, options *store.Config) (store.Store, error) {
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("no etcd endpoint specified")
+	}
+	if options == nil {
+		options = &store.Config{}
+	}
+
+	tlsConfig := options.TLS
+	if tlsConfig == nil && options.ClientTLS != nil {
+		var err error
+		tlsConfig, err = tlsconfig.Client(tlsconfig.Options{
+			CAFile:   options.ClientTLS.CACertFile,
+			CertFile: options.ClientTLS.CertFile,
+			KeyFile:  options.ClientTLS.KeyFile,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("invalid etcd TLS configuration: %v", err)
+		}
+	}
+	scheme := "http"
+	if tlsConfig != nil {
+		scheme = "https"
+	}
+
+	timeout := options.ConnectionTimeout
+	if timeout == 0 {
+		timeout = defaultRequestTimeout
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// the store is reached directly, not through the proxy of the daemon.
+	transport.Proxy = nil
+	transport.TLSClientConfig = tlsConfig
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Etcd{
+		client:    &http.Client{Transport: transport},
+		endpoints: store.CreateEndpoints(addrs, scheme),
+		timeout:   timeout,
+		username:  options.Username,
+		password:  options.Password,
+		ctx:       ctx,
+		cancel:    cancel,
+	}, nil
+}
+
+// Messages of the etcd v3 JSON gateway. Keys and values are base64
+// encoded, and 64-bit integers are encoded as strings.
+
+type responseHeader struct {
+	Revision int64 `json:"revision,string"`
+}
+
+type keyValue struct {
+	Key         []byte `json:"key"`
+	Value       []byte `json:"value"`
+	ModRevision int64  `json:"mod_revision,string"`
+}
+
+type rangeRequest struct {
+	Key      []byte `json:"key"`
+	RangeEnd []byte `json:"range_end,omitempty"`
+}
+
+type rangeResponse struct {
+	Header responseHeader `json:"header"`
+	Kvs    []keyValue     `json:"kvs"`
+}
+
+type putRequest struct {
+	Key   []byte `json:"key"`
+	Value []byte `json:"value"`
+	Lease string `json:"lease,omitempty"`
+}
+
+type deleteRangeRequest struct {
+	Key      []byte `json:"key"`
+	RangeEnd []byte `json:"range_end,omitempty"`
+}
+
+type compare struct {
+	Result         string `json:"result"`
+	Target         string `json:"target"`
+	Key            []byte `json:"key"`
+	ModRevision    string `json:"mod_revision,omitempty"`
+	CreateRevision string `json:"create_revision,omitempty"`
+}
+
+type requestOp struct {
+	RequestRange       *rangeRequest       `json:"request_range,omitempty"`
+	RequestPut         *putRequest         `json:"request_put,omitempty"`
+	RequestDeleteRange *deleteRangeRequest `json:"request_delete_range,omitempty"`
+}
+
+type txnRequest struct {
+	Compare []compare   `json:"compare"`
+	Success []requestOp `json:"success"`
+	Failure []requestOp `json:"failure"`
+}
+
+type txnResponse struct {
+	Header    responseHeader `json:"header"`
+	Succeeded bool           `json:"succeeded"`
+	Responses []struct {
+		ResponseRange *rangeResponse `json:"response_range"`
+	} `json:"responses"`
+}
+
+type watchRequest struct {
+	CreateRequest struct {
+		Key           []byte `json:"key"`
+		RangeEnd      []byte `json:"range_end,omitempty"`
+		StartRevision string `json:"start_revision,omitempty"`
+	} `json:"create_request"`
+}
+
+type watchResponse struct {
+	Result *struct {
+		Canceled bool `json:"canceled"`
+		Events   []struct {
+			Type string   `json:"type"`
+			Kv   keyValue `json:"kv"`
+		} `json:"events"`
+	} `json:"result"`
+	Error *gatewayError `json:"error"`
+}
+
+type gatewayError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *gatewayError) Error() string {
+	return fmt.Sprintf("etcd error (code %d): %s", e.Code, e.Message)
+}
+
+// prefixEnd returns the end of the range of the keys starting with prefix.
+func prefixEnd(prefix []byte) []byte {
+	end := append([]byte(nil), prefix...)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	// The prefix only contains 0xff bytes: the range ends at the last key.
+	return []byte{0}
+}
+
+func (s *Etcd) getToken() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.token
+}
+
+// authenticate retrieves a new auth token for the user of the store.
+func (s *Etcd) authenticate(ctx context.Context) error {
+	var resp struct {
+		Token string `json:"token"`
+	}
+	req := struct {
+		Name     string `json:"name"`
+		Password string `json:"password"`
+	}{s.username, s.password}
+	if err := s.post(ctx, "/v3/auth/authenticate", "", req, &resp); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.token = resp.Token
+	s.mu.Unlock()
+	return nil
+}
+
+// call sends a request to the gateway, authenticating again if the auth
+// token of the store has expired.
+func (s *Etcd) call(path string, req, resp interface{}) error {
+	ctx, cancel := context.WithTimeout(s.ctx, s.timeout)
+	defer cancel()
+
+	if s.username != "" && s.getToken() == "" {
+		if err := s.authenticate(ctx); err != nil {
+			return err
+		}
+	}
+	err := s.post(ctx, path, s.getToken(), req, resp)
+	if gerr, ok := err.(*gatewayError); ok && gerr.Code == http.StatusUnauthorized && s.username != "" {
+		if err := s.authenticate(ctx); err != nil {
+			return err
+		}
+		err = s.post(ctx, path, s.getToken(), req, resp)
+	}
+	return err
+}
+
+// post sends a request to the first reachable endpoint, and decodes its
+// response.
+func (s *Etcd) post(ctx context.Context, path, token string, req, resp interface{}) error {
+	body, err := s.do(ctx, path, token, req)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	return json.NewDecoder(body).Decode(resp)
+}
+
+// do sends a request to the first reachable endpoint, and returns the body
+// of its response.
+func (s *Etcd) do(ctx context.Context, path, token string, req interface{}) (io.ReadCloser, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	lastErr := store.ErrNotReachable
+	for _, ep := range s.endpoints {
+		r, err := http.NewRequestWithContext(ctx, http.MethodPost, ep+path, bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		r.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			r.Header.Set("Authorization", token)
+		}
+		resp, err := s.client.Do(r)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, err
+			}
+			lastErr = err
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			defer resp.Body.Close()
+			gerr := &gatewayError{}
+			if err := json.NewDecoder(resp.Body).Decode(gerr); err != nil || gerr.Message == "" {
+				gerr.Message = resp.Status
+			}
+			gerr.Code = resp.StatusCode
+			return nil, gerr
+		}
+		return resp.Body, nil
+	}
+	return nil, lastErr
+}
+
+func (s *Etcd) rangeKeys(key, rangeEnd []byte) (*rangeResponse, error) {
+	resp := &rangeResponse{}
+	if err := s.call("/v3/kv/range", rangeRequest{Key: key, RangeEnd: rangeEnd}, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *Etcd) newPutRequest(key string, value []byte, options *store.WriteOptions) (*putRequest, error) {
+	req := &putRequest{Key: []byte(key), Value: value}
+	if options != nil && options.TTL > 0 {
+		var resp struct {
+			ID string `json:"ID"`
+		}
+		ttl := struct {
+			TTL string `json:"TTL"`
+		}{strconv.FormatInt(int64(options.TTL/time.Second), 10)}
+		if err := s.call("/v3/lease/grant", ttl, &resp); err != nil {
+			return nil, err
+		}
+		req.Lease = resp.ID
+	}
+	return req, nil
+}
+
+func (kv *keyValue) toPair() *store.KVPair {
+	return &store.KVPair{Key: string(kv.Key), Value: kv.Value, LastIndex: uint64(kv.ModRevision)}
+}
+
+// Get returns the value of a key.
+func (s *Etcd) Get(key string) (*store.KVPair, error) {
+	resp, err := s.rangeKeys([]byte(key), nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, store.ErrKeyNotFound
+	}
+	return resp.Kvs[0].toPair(), nil
+}
+
+// Put sets the value of a key. The key expires after the TTL of the
+// options, if any.
+func (s *Etcd) Put(key string, value []byte, options *store.WriteOptions) error {
+	req, err := s.newPutRequest(key, value, options)
+	if err != nil {
+		return err
+	}
+	return s.call("/v3/kv/put", req, &struct{}{})
+}
+
+// Delete deletes a key.
+func (s *Etcd) Delete(key string) error {
+	var resp struct {
+		Deleted int64 `json:"deleted,string"`
+	}
+	if err := s.call("/v3/kv/deleterange", deleteRangeRequest{Key: []byte(key)}, &resp); err != nil {
+		return err
+	}
+	if resp.Deleted == 0 {
+		return store.ErrKeyNotFound
+	}
+	return nil
+}
+
+// Exists checks whether a key exists.
+func (s *Etcd) Exists(key string) (bool, error) {
+	_, err := s.Get(key)
+	if err == store.ErrKeyNotFound {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// List returns the keys starting with a prefix.
+func (s *Etcd) List(keyPrefix string) ([]*store.KVPair, error) {
+	prefix := []byte(keyPrefix)
+	resp, err := s.rangeKeys(prefix, prefixEnd(prefix))
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, store.ErrKeyNotFound
+	}
+	kvs := make([]*store.KVPair, 0, len(resp.Kvs))
+	for i := range resp.Kvs {
+		kvs = append(kvs, resp.Kvs[i].toPair())
+	}
+	return kvs, nil
+}
+
+// DeleteTree deletes the keys starting with a prefix.
+func (s *Etcd) DeleteTree(keyPrefix string) error {
+	prefix := []byte(keyPrefix)
+	return s.call("/v3/kv/deleterange", deleteRangeRequest{Key: prefix, RangeEnd: prefixEnd(prefix)}, &struct{}{})
+}
+
+// txn runs op if the key was not modified since the previous pair, or does
+// not exist if previous is nil. It returns the revision of the store after
+// the operation.
+func (s *Etcd) txn(key string, previous *store.KVPair, op requestOp) (int64, error) {
+	cmp := compare{Result: "EQUAL", Key: []byte(key)}
+	if previous == nil {
+		cmp.Target = "CREATE"
+		cmp.CreateRevision = "0"
+	} else {
+		cmp.Target = "MOD"
+		cmp.ModRevision = strconv.FormatUint(previous.LastIndex, 10)
+	}
+	req := txnRequest{
+		Compare: []compare{cmp},
+		Success: []requestOp{op},
+		Failure: []requestOp{{RequestRange: &rangeRequest{Key: []byte(key)}}},
+	}
+	resp := &txnResponse{}
+	if err := s.call("/v3/kv/txn", req, resp); err != nil {
+		return 0, err
+	}
+	if resp.Succeeded {
+		return resp.Header.Revision, nil
+	}
+
+	exists := len(resp.Responses) > 0 && resp.Responses[0].ResponseRange != nil && len(resp.Responses[0].ResponseRange.Kvs) > 0
+	switch {
+	case previous == nil:
+		return 0, store.ErrKeyExists
+	case !exists:
+		return 0, store.ErrKeyNotFound
+	default:
+		return 0, store.ErrKeyModified
+	}
+}
+
+// AtomicPut sets the value of a key if it was not modified since the
+// previous pair, or creates it if previous is nil.
+func (s *Etcd) AtomicPut(key string, value []byte, previous *store.KVPair, options *store.WriteOptions) (bool, *store.KVPair, error) {
+	req, err := s.newPutRequest(key, value, options)
+	if err != nil {
+		return false, nil, err
+	}
+	rev, err := s.txn(key, previous, requestOp{RequestPut: req})
+	if err != nil {
+		return false, nil, err
+	}
+	return true, &store.KVPair{Key: key, Value: value, LastIndex: uint64(rev)}, nil
+}
+
+// AtomicDelete deletes a key if it was not modified since the previous pair.
+func (s *Etcd) AtomicDelete(key string, previous *store.KVPair) (bool, error) {
+	if previous == nil {
+		return false, store.ErrPreviousNotSpecified
+	}
+	if _, err := s.txn(key, previous, requestOp{RequestDeleteRange: &deleteRangeRequest{Key: []byte(key)}}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// watch streams the events on a range of keys from a revision, calling fn
+// for each batch of events until stopCh is closed or the watch fails.
+func (s *Etcd) watch(key, rangeEnd []byte, rev int64, stopCh <-chan struct{}, fn func(events int) bool) error {
+	ctx, cancel := context.WithCancel(s.ctx)
+	go func() {
+		select {
+		case <-stopCh:
+		case <-ctx.Done():
+		}
+		cancel()
+	}()
+
+	req := watchRequest{}
+	req.CreateRequest.Key = key
+	req.CreateRequest.RangeEnd = rangeEnd
+	req.CreateRequest.StartRevision = strconv.FormatInt(rev, 10)
+
+	if s.username != "" && s.getToken() == "" {
+		if err := s.authenticate(ctx); err != nil {
+			cancel()
+			return err
+		}
+	}
+	body, err := s.do(ctx, "/v3/watch", s.getToken(), req)
+	if err != nil {
+		cancel()
+		return err
+	}
+
+	go func() {
+		defer cancel()
+		defer body.Close()
+
+		dec := json.NewDecoder(body)
+		for {
+			var resp watchResponse
+			if err := dec.Decode(&resp); err != nil {
+				if ctx.Err() == nil {
+					logrus.WithError(err).Warnf("etcd watch on %s failed", key)
+				}
+				fn(-1)
+				return
+			}
+			if resp.Error != nil || resp.Result == nil || resp.Result.Canceled {
+				fn(-1)
+				return
+			}
+			if len(resp.Result.Events) > 0 && !fn(len(resp.Result.Events)) {
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Watch watches a key. The current value of the key is sent first, then
+// its new value each time it is modified. The channel is closed when
+// stopCh is closed or the watch fails.
+func (s *Etcd) Watch(key string, stopCh <-chan struct{}) (<-chan *store.KVPair, error) {
+	resp, err := s.rangeKeys([]byte(key), nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, store.ErrKeyNotFound
+	}
+
+	pairs := make(chan *store.KVPair, 1)
+	pairs <- resp.Kvs[0].toPair()
+	var once sync.Once
+	err = s.watch([]byte(key), nil, resp.Header.Revision+1, stopCh, func(events int) bool {
+		if events < 0 {
+			once.Do(func() { close(pairs) })
+			return false
+		}
+		pair, err := s.Get(key)
+		if err == store.ErrKeyNotFound {
+			return true
+		}
+		if err != nil {
+			once.Do(func() { close(pairs) })
+			return false
+		}
+		select {
+		case pairs <- pair:
+			return true
+		case <-stopCh:
+			once.Do(func() { close(pairs) })
+			return false
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pairs, nil
+}
+
+// WatchTree watches the keys starting with a prefix. The current list of
+// keys is sent first, then the new list each time one of them is modified.
+// The channel is closed when stopCh is closed or the watch fails.
+func (s *Etcd) WatchTree(directory string, stopCh <-chan struct{}) (<-chan []*store.KVPair, error) {
+	prefix := []byte(directory)
+	resp, err := s.rangeKeys(prefix, prefixEnd(prefix))
+	if err != nil {
+		return nil, err
+	}
+
+	list := func() ([]*store.KVPair, error) {
+		kvs, err := s.List(directory)
+		if err == store.ErrKeyNotFound {
+			return []*store.KVPair{}, nil
+		}
+		return kvs, err
+	}
+	kvs := make([]*store.KVPair, 0, len(resp.Kvs))
+	for i := range resp.Kvs {
+		kvs = append(kvs, resp.Kvs[i].toPair())
+	}
+
+	lists := make(chan []*store.KVPair, 1)
+	lists <- kvs
+	var once sync.Once
+	err = s.watch(prefix, prefixEnd(prefix), resp.Header.Revision+1, stopCh, func(events int) bool {
+		if events < 0 {
+			once.Do(func() { close(lists) })
+			return false
+		}
+		kvs, err := list()
+		if err != nil {
+			once.Do(func() { close(lists) })
+			return false
+		}
+		select {
+		case lists <- kvs:
+			return true
+		case <-stopCh:
+			once.Do(func() { close(lists) })
+			return false
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return lists, nil
+}
+
+// NewLock is not supported by the etcd datastore backend.
+func (s *Etcd) NewLock(key string, options *store.LockOptions) (store.Locker, error) {
+	return nil, store.ErrCallNotSupported
+}
+
+// Close stops the watches of the store and closes its connections.
+func (s *Etcd) Close() {
+	s.cancel()
+	s.client.CloseIdleConnections()
+}
+
+var _ store.Store = (*Etcd)(nil)
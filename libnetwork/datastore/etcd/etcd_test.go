@@ -0,0 +1,292 @@
+package etcd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/docker/libkv/store"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+// fakeGateway is a minimal in-memory implementation of the etcd v3 JSON
+// gateway.
+type fakeGateway struct {
+	mu       sync.Mutex
+	rev      int64
+	kvs      map[string]keyValue
+	watchers []chan struct{}
+	token    string
+	auths    int
+}
+
+func newFakeGateway() *fakeGateway {
+	return &fakeGateway{kvs: make(map[string]keyValue)}
+}
+
+func (g *fakeGateway) inRange(key string, r rangeRequest) bool {
+	if len(r.RangeEnd) == 0 {
+		return key == string(r.Key)
+	}
+	return key >= string(r.Key) && key < string(r.RangeEnd)
+}
+
+func (g *fakeGateway) rangeKeys(r rangeRequest) *rangeResponse {
+	resp := &rangeResponse{Header: responseHeader{Revision: g.rev}}
+	for k, kv := range g.kvs {
+		if g.inRange(k, r) {
+			resp.Kvs = append(resp.Kvs, kv)
+		}
+	}
+	sort.Slice(resp.Kvs, func(i, j int) bool { return string(resp.Kvs[i].Key) < string(resp.Kvs[j].Key) })
+	return resp
+}
+
+func (g *fakeGateway) apply(op requestOp) {
+	g.rev++
+	switch {
+	case op.RequestPut != nil:
+		g.kvs[string(op.RequestPut.Key)] = keyValue{Key: op.RequestPut.Key, Value: op.RequestPut.Value, ModRevision: g.rev}
+	case op.RequestDeleteRange != nil:
+		for k := range g.kvs {
+			if g.inRange(k, rangeRequest(*op.RequestDeleteRange)) {
+				delete(g.kvs, k)
+			}
+		}
+	}
+	for _, w := range g.watchers {
+		select {
+		case w <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (g *fakeGateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/v3/auth/authenticate" {
+		var req struct{ Name, Password string }
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if req.Name != "user" || req.Password != "secret" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"code":3,"message":"etcdserver: authentication failed"}`))
+			return
+		}
+		g.mu.Lock()
+		g.auths++
+		g.token = "token" + strconv.Itoa(g.auths)
+		g.mu.Unlock()
+		_ = json.NewEncoder(w).Encode(map[string]string{"token": g.token})
+		return
+	}
+
+	g.mu.Lock()
+	token := g.token
+	g.mu.Unlock()
+	if token != "" && r.Header.Get("Authorization") != token {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"code":16,"message":"etcdserver: invalid auth token"}`))
+		return
+	}
+
+	if r.URL.Path == "/v3/watch" {
+		g.serveWatch(w, r)
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var resp interface{}
+	switch r.URL.Path {
+	case "/v3/kv/range":
+		var req rangeRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		resp = g.rangeKeys(req)
+	case "/v3/kv/put":
+		var req putRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		g.apply(requestOp{RequestPut: &req})
+		resp = struct{}{}
+	case "/v3/kv/deleterange":
+		var req deleteRangeRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		deleted := len(g.rangeKeys(rangeRequest(req)).Kvs)
+		g.apply(requestOp{RequestDeleteRange: &req})
+		resp = map[string]string{"deleted": strconv.Itoa(deleted)}
+	case "/v3/kv/txn":
+		var req txnRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		cmp := req.Compare[0]
+		kv, ok := g.kvs[string(cmp.Key)]
+		var succeeded bool
+		if cmp.Target == "CREATE" {
+			succeeded = !ok
+		} else {
+			succeeded = ok && strconv.FormatInt(kv.ModRevision, 10) == cmp.ModRevision
+		}
+		txn := &txnResponse{Succeeded: succeeded}
+		if succeeded {
+			g.apply(req.Success[0])
+		} else {
+			txn.Responses = append(txn.Responses, struct {
+				ResponseRange *rangeResponse `json:"response_range"`
+			}{g.rangeKeys(*req.Failure[0].RequestRange)})
+		}
+		txn.Header.Revision = g.rev
+		resp = txn
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (g *fakeGateway) serveWatch(w http.ResponseWriter, r *http.Request) {
+	notify := make(chan struct{}, 1)
+	g.mu.Lock()
+	g.watchers = append(g.watchers, notify)
+	g.mu.Unlock()
+
+	_, _ = w.Write([]byte(`{"result":{"created":true}}` + "\n"))
+	w.(http.Flusher).Flush()
+	for {
+		select {
+		case <-notify:
+			_, _ = w.Write([]byte(`{"result":{"events":[{"kv":{}}]}}` + "\n"))
+			w.(http.Flusher).Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func newTestStore(t *testing.T, options *store.Config) (*fakeGateway, store.Store) {
+	g := newFakeGateway()
+	srv := httptest.NewServer(g)
+	t.Cleanup(srv.Close)
+
+	s, err := New([]string{strings.TrimPrefix(srv.URL, "http://")}, options)
+	assert.NilError(t, err)
+	t.Cleanup(s.Close)
+	return g, s
+}
+
+func TestPrefixEnd(t *testing.T) {
+	assert.Check(t, is.DeepEqual(prefixEnd([]byte("a/b/")), []byte("a/b0")))
+	assert.Check(t, is.DeepEqual(prefixEnd([]byte{'a', 0xff}), []byte("b")))
+	assert.Check(t, is.DeepEqual(prefixEnd([]byte{0xff}), []byte{0}))
+}
+
+func TestStore(t *testing.T) {
+	_, s := newTestStore(t, nil)
+
+	_, err := s.Get("docker/network/v1.0/network/n1/")
+	assert.Check(t, is.Equal(err, store.ErrKeyNotFound))
+	assert.NilError(t, s.Put("docker/network/v1.0/network/n1/", []byte("n1"), nil))
+	assert.NilError(t, s.Put("docker/network/v1.0/network/n2/", []byte("n2"), nil))
+	assert.NilError(t, s.Put("docker/network/v1.0/endpoint/e1/", []byte("e1"), nil))
+
+	kv, err := s.Get("docker/network/v1.0/network/n1/")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(kv.Value), "n1"))
+	assert.Check(t, is.Equal(kv.LastIndex, uint64(1)))
+
+	kvs, err := s.List("docker/network/v1.0/network/")
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(kvs, 2))
+	assert.Check(t, is.Equal(kvs[1].Key, "docker/network/v1.0/network/n2/"))
+
+	ok, err := s.Exists("docker/network/v1.0/endpoint/e2/")
+	assert.NilError(t, err)
+	assert.Check(t, !ok)
+
+	assert.NilError(t, s.DeleteTree("docker/network/v1.0/network/"))
+	_, err = s.List("docker/network/v1.0/network/")
+	assert.Check(t, is.Equal(err, store.ErrKeyNotFound))
+	assert.NilError(t, s.Delete("docker/network/v1.0/endpoint/e1/"))
+	assert.Check(t, is.Equal(s.Delete("docker/network/v1.0/endpoint/e1/"), store.ErrKeyNotFound))
+}
+
+func TestAtomicOperations(t *testing.T) {
+	_, s := newTestStore(t, nil)
+
+	ok, kv, err := s.AtomicPut("key", []byte("v1"), nil, nil)
+	assert.NilError(t, err)
+	assert.Check(t, ok)
+	_, _, err = s.AtomicPut("key", []byte("v2"), nil, nil)
+	assert.Check(t, is.Equal(err, store.ErrKeyExists))
+
+	_, kv2, err := s.AtomicPut("key", []byte("v2"), kv, nil)
+	assert.NilError(t, err)
+	_, _, err = s.AtomicPut("key", []byte("v3"), kv, nil)
+	assert.Check(t, is.Equal(err, store.ErrKeyModified))
+
+	_, err = s.AtomicDelete("key", kv)
+	assert.Check(t, is.Equal(err, store.ErrKeyModified))
+	_, err = s.AtomicDelete("key", nil)
+	assert.Check(t, is.Equal(err, store.ErrPreviousNotSpecified))
+	ok, err = s.AtomicDelete("key", kv2)
+	assert.NilError(t, err)
+	assert.Check(t, ok)
+	_, _, err = s.AtomicPut("key", []byte("v3"), kv2, nil)
+	assert.Check(t, is.Equal(err, store.ErrKeyNotFound))
+}
+
+func TestAuth(t *testing.T) {
+	g, s := newTestStore(t, &store.Config{Username: "user", Password: "secret"})
+
+	assert.NilError(t, s.Put("key", []byte("value"), nil))
+	assert.Check(t, is.Equal(g.auths, 1))
+
+	// An expired token is renewed.
+	g.mu.Lock()
+	g.token = "renewed"
+	g.mu.Unlock()
+	_, err := s.Get("key")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(g.auths, 2))
+
+	_, s = newTestStore(t, &store.Config{Username: "user", Password: "wrong"})
+	_, err = s.Get("key")
+	assert.Check(t, is.ErrorContains(err, "authentication failed"))
+}
+
+func TestWatch(t *testing.T) {
+	_, s := newTestStore(t, nil)
+
+	_, err := s.Watch("key", nil)
+	assert.Check(t, is.Equal(err, store.ErrKeyNotFound))
+
+	assert.NilError(t, s.Put("key", []byte("v1"), nil))
+	stopCh := make(chan struct{})
+	pairs, err := s.Watch("key", stopCh)
+	assert.NilError(t, err)
+	lists, err := s.WatchTree("k", stopCh)
+	assert.NilError(t, err)
+
+	next := func() *store.KVPair {
+		select {
+		case kv := <-pairs:
+			return kv
+		case <-time.After(10 * time.Second):
+			t.Fatal("timeout waiting for watch")
+		}
+		return nil
+	}
+	assert.Check(t, is.Equal(string(next().Value), "v1"))
+	assert.Check(t, is.Len(<-lists, 1))
+
+	assert.NilError(t, s.Put("key", []byte("v2"), nil))
+	assert.Check(t, is.Equal(string(next().Value), "v2"))
+	assert.Check(t, bytes.Equal((<-lists)[0].Value, []byte("v2")))
+
+	close(stopCh)
+	assert.Check(t, next() == nil)
+}
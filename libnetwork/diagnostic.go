@@ -0,0 +1,193 @@
+package libnetwork
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/docker/docker/libnetwork/diagnostic"
+	"github.com/docker/docker/libnetwork/driverapi"
+	"github.com/docker/docker/libnetwork/internal/caller"
+	"github.com/sirupsen/logrus"
+)
+
+// diagPaths2Func are the handlers the controller registers with the
+// diagnostic server.
+var diagPaths2Func = map[string]diagnostic.HTTPHandlerFunc{
+	"/networkdump": dumpNetworks,
+}
+
+// dumpNetworks dumps the state of the network with the ID or name given by
+// the nid parameter, or of all networks, as a single artifact to attach to
+// bug reports. NetworkDB values are base64 encoded, unless the unsafe
+// parameter is set.
+func dumpNetworks(ctx interface{}, w http.ResponseWriter, r *http.Request) {
+	r.ParseForm() //nolint:errcheck
+	diagnostic.DebugHTTPForm(r)
+	unsafe, json := diagnostic.ParseHTTPFormOptions(r)
+
+	// audit logs
+	log := logrus.WithFields(logrus.Fields{"component": "diagnostic", "remoteIP": r.RemoteAddr, "method": caller.Name(0), "url": r.URL.String()})
+	log.Info("network dump")
+
+	c, ok := ctx.(*Controller)
+	if !ok {
+		diagnostic.HTTPReply(w, diagnostic.FailCommand(fmt.Errorf("network controller not available")), json) //nolint:errcheck
+		return
+	}
+
+	var networks []Network
+	if nid := r.Form.Get("nid"); nid != "" {
+		n, err := c.NetworkByID(nid)
+		if err != nil {
+			n, err = c.NetworkByName(nid)
+		}
+		if err != nil {
+			log.WithError(err).Error("network dump failed")
+			diagnostic.HTTPReply(w, diagnostic.FailCommand(err), json) //nolint:errcheck
+			return
+		}
+		networks = append(networks, n)
+	} else {
+		networks = c.Networks()
+		sort.Slice(networks, func(i, j int) bool { return networks[i].Name() < networks[j].Name() })
+	}
+
+	rsp := &diagnostic.NetworkDumpResult{}
+	for _, n := range networks {
+		rsp.Networks = append(rsp.Networks, n.(*network).diagnosticDump(unsafe))
+	}
+	log.Info("network dump done")
+	diagnostic.HTTPReply(w, diagnostic.CommandSucceed(rsp), json) //nolint:errcheck
+}
+
+// diagnosticDump collects the state of the network. Errors are recorded in
+// the dump, so that the state that could be collected is still reported.
+func (n *network) diagnosticDump(unsafe bool) *diagnostic.NetworkDump {
+	d := &diagnostic.NetworkDump{
+		ID:     n.ID(),
+		Name:   n.Name(),
+		Driver: n.Type(),
+		Scope:  n.Scope(),
+	}
+	if b, err := json.Marshal(n); err != nil {
+		d.Errors = append(d.Errors, fmt.Sprintf("store object: %v", err))
+	} else {
+		d.Store = b
+	}
+
+	for _, ep := range n.Endpoints() {
+		d.Endpoints = append(d.Endpoints, ep.diagnosticDump(d))
+	}
+
+	if rules, err := n.firewallRules(); err != nil {
+		d.Errors = append(d.Errors, fmt.Sprintf("firewall rules: %v", err))
+	} else {
+		d.Firewall = rules
+	}
+
+	if drv, err := n.driver(false); err != nil {
+		d.Errors = append(d.Errors, fmt.Sprintf("driver: %v", err))
+	} else if sd, ok := drv.(driverapi.StateDumper); ok {
+		state, err := sd.DumpNetworkState(n.ID())
+		if err != nil {
+			d.Errors = append(d.Errors, fmt.Sprintf("driver state: %v", err))
+		}
+		d.DriverState = state
+	}
+
+	n.dumpTables(d, unsafe)
+	return d
+}
+
+// dumpTables adds the NetworkDB tables of the network to the dump, if the
+// network is a swarm-scoped network and the node is part of a swarm.
+func (n *network) dumpTables(d *diagnostic.NetworkDump, unsafe bool) {
+	if !n.isClusterEligible() {
+		return
+	}
+	agent := n.getController().getAgent()
+	if agent == nil {
+		return
+	}
+	tables := []string{libnetworkEPTable}
+	for _, t := range n.driverTables {
+		tables = append(tables, t.name)
+	}
+	d.Tables = make(map[string][]diagnostic.TableEntryObj, len(tables))
+	for _, table := range tables {
+		entries := agent.networkDB.GetTableByNetwork(table, n.ID())
+		keys := make([]string, 0, len(entries))
+		for k := range entries {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		objs := make([]diagnostic.TableEntryObj, 0, len(keys))
+		for i, k := range keys {
+			value := string(entries[k].Value)
+			if !unsafe {
+				value = base64.StdEncoding.EncodeToString(entries[k].Value)
+			}
+			objs = append(objs, diagnostic.TableEntryObj{Index: i + 1, Key: k, Value: value, Owner: entries[k].Owner()})
+		}
+		d.Tables[table] = objs
+	}
+}
+
+// diagnosticDump collects the state of the endpoint, and of the sandbox it
+// is joined to. Errors are recorded in the dump of its network.
+func (ep *Endpoint) diagnosticDump(nd *diagnostic.NetworkDump) *diagnostic.EndpointDump {
+	d := &diagnostic.EndpointDump{ID: ep.ID(), Name: ep.Name()}
+	if b, err := json.Marshal(ep); err != nil {
+		nd.Errors = append(nd.Errors, fmt.Sprintf("store object of endpoint %s: %v", ep.ID(), err))
+	} else {
+		d.Store = b
+	}
+	if sb, ok := ep.getSandbox(); ok {
+		d.Sandbox = sb.diagnosticDump()
+	}
+	return d
+}
+
+// diagnosticDump collects the interfaces and routes of the sandbox.
+func (sb *Sandbox) diagnosticDump() *diagnostic.SandboxDump {
+	d := &diagnostic.SandboxDump{ID: sb.ID(), ContainerID: sb.ContainerID(), Key: sb.Key()}
+
+	sb.mu.Lock()
+	osSbox := sb.osSbox
+	sb.mu.Unlock()
+	if osSbox == nil {
+		return d
+	}
+
+	info := osSbox.Info()
+	for _, i := range info.Interfaces() {
+		iface := &diagnostic.InterfaceDump{SrcName: i.SrcName(), DstName: i.DstName(), Master: i.Master()}
+		if i.Address() != nil {
+			iface.Address = i.Address().String()
+		}
+		if i.AddressIPv6() != nil {
+			iface.AddressIPv6 = i.AddressIPv6().String()
+		}
+		for _, r := range i.Routes() {
+			iface.Routes = append(iface.Routes, r.String())
+		}
+		d.Interfaces = append(d.Interfaces, iface)
+	}
+	if gw := info.Gateway(); gw != nil {
+		d.Gateway = gw.String()
+	}
+	if gw := info.GatewayIPv6(); gw != nil {
+		d.GatewayIPv6 = gw.String()
+	}
+	for _, r := range info.StaticRoutes() {
+		route := r.Destination.String()
+		if r.NextHop != nil {
+			route += " via " + r.NextHop.String()
+		}
+		d.Routes = append(d.Routes, route)
+	}
+	return d
+}
@@ -1,6 +1,10 @@
 package diagnostic
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
 
 // StringInterface interface that has to be implemented by messages
 type StringInterface interface {
@@ -130,3 +134,153 @@ type NetworkStatsResult struct {
 func (n *NetworkStatsResult) String() string {
 	return fmt.Sprintf("entries: %d, qlen: %d\n", n.Entries, n.QueueLen)
 }
+
+// NetworkDumpResult is the state of the networks of the node, as dumped by
+// the network controller
+type NetworkDumpResult struct {
+	Networks []*NetworkDump `json:"networks"`
+}
+
+func (n *NetworkDumpResult) String() string {
+	var output string
+	for _, nw := range n.Networks {
+		output += nw.String()
+	}
+	return output
+}
+
+// NetworkDump is the state of a network: its store object, the state of its
+// endpoints and their sandboxes, the firewall rules programmed for it, the
+// state programmed by its driver, and its NetworkDB tables
+type NetworkDump struct {
+	ID          string                     `json:"id"`
+	Name        string                     `json:"name"`
+	Driver      string                     `json:"driver"`
+	Scope       string                     `json:"scope"`
+	Store       json.RawMessage            `json:"store,omitempty"`
+	Endpoints   []*EndpointDump            `json:"endpoints,omitempty"`
+	Firewall    []string                   `json:"firewall,omitempty"`
+	DriverState map[string][]string        `json:"driverState,omitempty"`
+	Tables      map[string][]TableEntryObj `json:"tables,omitempty"`
+	// Errors holds the errors that occurred while collecting the state,
+	// which is dumped as far as it could be collected
+	Errors []string `json:"errors,omitempty"`
+}
+
+func (n *NetworkDump) String() string {
+	output := fmt.Sprintf("network %s (%s) driver:%s scope:%s\n", n.Name, n.ID, n.Driver, n.Scope)
+	if len(n.Store) > 0 {
+		output += fmt.Sprintf("  store: %s\n", n.Store)
+	}
+	for _, ep := range n.Endpoints {
+		output += ep.String()
+	}
+	if len(n.Firewall) > 0 {
+		output += "  firewall:\n"
+		for _, rule := range n.Firewall {
+			output += "    " + rule + "\n"
+		}
+	}
+	kinds := make([]string, 0, len(n.DriverState))
+	for kind := range n.DriverState {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	for _, kind := range kinds {
+		output += fmt.Sprintf("  driver %s:\n", kind)
+		for _, line := range n.DriverState[kind] {
+			output += "    " + line + "\n"
+		}
+	}
+	tables := make([]string, 0, len(n.Tables))
+	for table := range n.Tables {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+	for _, table := range tables {
+		output += fmt.Sprintf("  table %s:\n", table)
+		for _, e := range n.Tables[table] {
+			output += "    " + e.String()
+		}
+	}
+	for _, err := range n.Errors {
+		output += "  error: " + err + "\n"
+	}
+	return output
+}
+
+// EndpointDump is the state of an endpoint, and of the sandbox it is joined
+// to, if any
+type EndpointDump struct {
+	ID      string          `json:"id"`
+	Name    string          `json:"name"`
+	Store   json.RawMessage `json:"store,omitempty"`
+	Sandbox *SandboxDump    `json:"sandbox,omitempty"`
+}
+
+func (e *EndpointDump) String() string {
+	output := fmt.Sprintf("  endpoint %s (%s)\n", e.Name, e.ID)
+	if len(e.Store) > 0 {
+		output += fmt.Sprintf("    store: %s\n", e.Store)
+	}
+	if e.Sandbox != nil {
+		output += e.Sandbox.String()
+	}
+	return output
+}
+
+// SandboxDump is the state of the interfaces and routes of a sandbox
+type SandboxDump struct {
+	ID          string           `json:"id"`
+	ContainerID string           `json:"containerId"`
+	Key         string           `json:"key"`
+	Interfaces  []*InterfaceDump `json:"interfaces,omitempty"`
+	Gateway     string           `json:"gateway,omitempty"`
+	GatewayIPv6 string           `json:"gatewayIPv6,omitempty"`
+	Routes      []string         `json:"routes,omitempty"`
+}
+
+func (s *SandboxDump) String() string {
+	output := fmt.Sprintf("    sandbox %s container:%s key:%s\n", s.ID, s.ContainerID, s.Key)
+	for _, iface := range s.Interfaces {
+		output += iface.String()
+	}
+	if s.Gateway != "" {
+		output += fmt.Sprintf("      gateway: %s\n", s.Gateway)
+	}
+	if s.GatewayIPv6 != "" {
+		output += fmt.Sprintf("      gateway ipv6: %s\n", s.GatewayIPv6)
+	}
+	for _, r := range s.Routes {
+		output += "      route: " + r + "\n"
+	}
+	return output
+}
+
+// InterfaceDump is the state of an interface of a sandbox
+type InterfaceDump struct {
+	SrcName     string   `json:"srcName"`
+	DstName     string   `json:"dstName"`
+	Master      string   `json:"master,omitempty"`
+	Address     string   `json:"address,omitempty"`
+	AddressIPv6 string   `json:"addressIPv6,omitempty"`
+	Routes      []string `json:"routes,omitempty"`
+}
+
+func (i *InterfaceDump) String() string {
+	output := fmt.Sprintf("      interface %s -> %s", i.SrcName, i.DstName)
+	if i.Master != "" {
+		output += " master:" + i.Master
+	}
+	if i.Address != "" {
+		output += " address:" + i.Address
+	}
+	if i.AddressIPv6 != "" {
+		output += " address ipv6:" + i.AddressIPv6
+	}
+	output += "\n"
+	for _, r := range i.Routes {
+		output += "        route: " + r + "\n"
+	}
+	return output
+}
@@ -0,0 +1,142 @@
+package libnetwork
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/docker/docker/libnetwork/drivers/bridge"
+	"github.com/docker/docker/libnetwork/iptables"
+	"github.com/docker/docker/libnetwork/netlabel"
+	"github.com/docker/docker/libnetwork/options"
+)
+
+// firewallRules returns the iptables and nftables rules which refer to the
+// subnets or the bridge interface of the network, as "table: rule".
+func (n *network) firewallRules() ([]string, error) {
+	var pools []string
+	v4Info, v6Info := n.IpamInfo()
+	for _, info := range append(v4Info, v6Info...) {
+		if info.Pool != nil {
+			pools = append(pools, info.Pool.String())
+		}
+	}
+	matches := append([]string(nil), pools...)
+	nftMatches := append([]string(nil), pools...)
+	if n.Type() == "bridge" {
+		name := n.bridgeName()
+		matches = append(matches, " "+name+" ", " "+name+"\n")
+		nftMatches = append(nftMatches, `"`+name+`"`)
+	}
+	if len(matches) == 0 {
+		return nil, nil
+	}
+
+	rules, err := n.iptablesRules(matches)
+	if err != nil {
+		return rules, err
+	}
+	nftRules, err := nftablesRules(nftMatches)
+	return append(rules, nftRules...), err
+}
+
+// iptablesRules returns the iptables rules containing one of matches.
+func (n *network) iptablesRules(matches []string) ([]string, error) {
+
+	c := n.getController()
+	var versions []iptables.IPVersion
+	if c.iptablesEnabled() {
+		versions = append(versions, iptables.IPv4)
+	}
+	if c.ip6tablesEnabled() {
+		versions = append(versions, iptables.IPv6)
+	}
+
+	var rules []string
+	for _, v := range versions {
+		for _, table := range []iptables.Table{iptables.Filter, iptables.Nat, iptables.Mangle} {
+			out, err := iptables.GetIptable(v).Raw("-t", string(table), "-S")
+			if err != nil {
+				return rules, fmt.Errorf("listing %s rules of table %s: %v", v, table, err)
+			}
+			for _, rule := range strings.Split(string(out), "\n") {
+				for _, m := range matches {
+					if strings.Contains(rule+"\n", m) {
+						rules = append(rules, fmt.Sprintf("%s %s: %s", v, table, rule))
+						break
+					}
+				}
+			}
+		}
+	}
+	return rules, nil
+}
+
+// nftablesRules returns the rules of the nftables ruleset containing one of
+// matches, as "nft family table chain: rule". Rules programmed through
+// iptables-nft are listed both as iptables and nftables rules. Nothing is
+// returned if the nft command is not installed.
+func nftablesRules(matches []string) ([]string, error) {
+	path, err := exec.LookPath("nft")
+	if err != nil {
+		return nil, nil
+	}
+	out, err := exec.Command(path, "list", "ruleset").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("listing nftables rules: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return filterNftRuleset(string(out), matches), nil
+}
+
+// filterNftRuleset returns the rules of the output of "nft list ruleset"
+// containing one of matches, prefixed with their table and chain.
+func filterNftRuleset(ruleset string, matches []string) []string {
+	var table, chain string
+	var rules []string
+	for _, line := range strings.Split(ruleset, "\n") {
+		line = strings.TrimSpace(line)
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+			continue
+		case line == "}":
+			// end of a chain, or of a set or map
+			chain = ""
+			continue
+		case fields[0] == "table" && len(fields) >= 3:
+			table, chain = fields[1]+" "+fields[2], ""
+			continue
+		case fields[0] == "chain" && len(fields) >= 2:
+			chain = fields[1]
+			continue
+		case chain == "":
+			// sets, maps and other table declarations
+			continue
+		}
+		for _, m := range matches {
+			if strings.Contains(line, m) {
+				rules = append(rules, fmt.Sprintf("nft %s %s: %s", table, chain, line))
+				break
+			}
+		}
+	}
+	return rules
+}
+
+// bridgeName returns the name of the bridge interface of a network using the
+// bridge driver.
+func (n *network) bridgeName() string {
+	n.mu.Lock()
+	var name string
+	switch opts := n.generic[netlabel.GenericData].(type) {
+	case map[string]string:
+		name = opts[bridge.BridgeName]
+	case options.Generic:
+		name, _ = opts["BridgeName"].(string)
+	}
+	n.mu.Unlock()
+	if name == "" {
+		name = "br-" + n.ID()[:12]
+	}
+	return name
+}
@@ -0,0 +1,35 @@
+package libnetwork
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestFilterNftRuleset(t *testing.T) {
+	const ruleset = `table ip filter {
+	set allowed {
+		type ipv4_addr
+		elements = { 172.18.0.2 }
+	}
+
+	chain FORWARD {
+		type filter hook forward priority filter; policy drop;
+		iifname "br-0123456789ab" oifname != "br-0123456789ab" counter packets 0 bytes 0 accept
+		iifname "docker0" counter packets 0 bytes 0 accept
+	}
+}
+table inet nat {
+	chain POSTROUTING {
+		type nat hook postrouting priority srcnat; policy accept;
+		ip saddr 172.18.0.0/16 oifname != "br-0123456789ab" counter masquerade
+	}
+}
+`
+	rules := filterNftRuleset(ruleset, []string{"172.18.0.0/16", `"br-0123456789ab"`})
+	assert.Check(t, is.DeepEqual(rules, []string{
+		`nft ip filter FORWARD: iifname "br-0123456789ab" oifname != "br-0123456789ab" counter packets 0 bytes 0 accept`,
+		`nft inet nat POSTROUTING: ip saddr 172.18.0.0/16 oifname != "br-0123456789ab" counter masquerade`,
+	}))
+}
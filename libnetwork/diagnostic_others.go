@@ -0,0 +1,10 @@
+//go:build !linux
+// +build !linux
+
+package libnetwork
+
+// firewallRules returns the firewall rules programmed for the network, which
+// are only collected on Linux.
+func (n *network) firewallRules() ([]string, error) {
+	return nil, nil
+}
@@ -0,0 +1,62 @@
+package libnetwork
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/docker/docker/libnetwork/diagnostic"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestDumpNetworks(t *testing.T) {
+	c, nws := getTestEnv(t, []NetworkOption{})
+	n := nws[0]
+
+	ep, err := n.CreateEndpoint("ep0")
+	assert.NilError(t, err)
+	defer ep.Delete(false) //nolint:errcheck
+
+	req := httptest.NewRequest(http.MethodGet, "/networkdump?json&nid="+n.Name(), nil)
+	rec := httptest.NewRecorder()
+	dumpNetworks(c, rec, req)
+
+	var rsp struct {
+		Message string                       `json:"message"`
+		Details diagnostic.NetworkDumpResult `json:"details"`
+	}
+	assert.NilError(t, json.Unmarshal(rec.Body.Bytes(), &rsp))
+	assert.Check(t, is.Equal(rsp.Message, "OK"))
+	assert.Assert(t, is.Len(rsp.Details.Networks, 1))
+
+	d := rsp.Details.Networks[0]
+	assert.Check(t, is.Equal(d.ID, n.ID()))
+	assert.Check(t, is.Equal(d.Driver, "bridge"))
+	assert.Check(t, len(d.Store) > 0)
+	assert.Assert(t, is.Len(d.Endpoints, 1))
+	assert.Check(t, is.Equal(d.Endpoints[0].Name, "ep0"))
+	assert.Check(t, len(d.Endpoints[0].Store) > 0)
+
+	req = httptest.NewRequest(http.MethodGet, "/networkdump?nid=unknown", nil)
+	rec = httptest.NewRecorder()
+	dumpNetworks(c, rec, req)
+	assert.Check(t, is.Contains(rec.Body.String(), "FAIL"))
+}
+
+func TestNetworkDriverState(t *testing.T) {
+	_, nws := getTestEnv(t, []NetworkOption{})
+	n := nws[0]
+
+	ep, err := n.CreateEndpoint("ep0")
+	assert.NilError(t, err)
+	defer ep.Delete(false) //nolint:errcheck
+
+	state, err := n.Info().DriverState()
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(state["Bridge"], "test_nw_0"))
+	b, err := json.Marshal(state["Endpoints"])
+	assert.NilError(t, err)
+	assert.Check(t, is.Contains(string(b), ep.ID()))
+}
@@ -124,6 +124,23 @@ type InterfaceInfo interface {
 	AddressIPv6() *net.IPNet
 }
 
+// StateDumper is an optional interface for drivers which can report the data
+// plane state they programmed for a network, for the diagnostic server.
+type StateDumper interface {
+	// DumpNetworkState returns the state programmed for the network, as
+	// lines of text by kind of state.
+	DumpNetworkState(nid string) (map[string][]string, error)
+}
+
+// StateReporter is an optional interface for drivers which can report the
+// runtime state of a network on this node, such as the peers of an overlay
+// network or the ports of a bridge, for verbose network inspects.
+type StateReporter interface {
+	// NetworkState returns the driver-specific state of the network, which
+	// must be serializable to JSON.
+	NetworkState(nid string) (map[string]interface{}, error)
+}
+
 // InterfaceNameInfo provides a go interface for the drivers to assign names
 // to interfaces.
 type InterfaceNameInfo interface {
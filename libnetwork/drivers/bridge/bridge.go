@@ -60,6 +60,11 @@ type configuration struct {
 	EnableIP6Tables     bool
 	EnableUserlandProxy bool
 	UserlandProxyPath   string
+	// PublishedPortRangeStart and PublishedPortRangeEnd are the range host
+	// ports are allocated from when a port is published without a host
+	// port. If not set, the ephemeral port range of the system is used.
+	PublishedPortRangeStart int
+	PublishedPortRangeEnd   int
 }
 
 // networkConfiguration for network specific configuration
@@ -155,6 +160,11 @@ type driver struct {
 	nlh               *netlink.Handle
 	configNetwork     sync.Mutex
 	portAllocator     *portallocator.PortAllocator // Overridable for tests.
+	// portIfaceWatch starts the watch of the addresses of the host interfaces
+	// ports are bound to, and portIfaceMu serializes the updates of the port
+	// mappings of the endpoints.
+	portIfaceWatch sync.Once
+	portIfaceMu    sync.Mutex
 	sync.Mutex
 }
 
@@ -428,6 +438,12 @@ func (d *driver) configure(option map[string]interface{}) error {
 		}
 	}
 
+	if config.PublishedPortRangeStart != 0 {
+		if err := d.portAllocator.SetDynamicPortRange(config.PublishedPortRangeStart, config.PublishedPortRangeEnd); err != nil {
+			return err
+		}
+	}
+
 	d.Lock()
 	d.natChain = natChain
 	d.filterChain = filterChain
@@ -1283,6 +1299,9 @@ func (d *driver) ProgramExternalConnectivity(nid, eid string, options map[string
 		return EndpointNotFoundError(eid)
 	}
 
+	d.portIfaceMu.Lock()
+	defer d.portIfaceMu.Unlock()
+
 	endpoint.extConnConfig, err = parseConnectivityOptions(options)
 	if err != nil {
 		return err
@@ -1334,12 +1353,19 @@ func (d *driver) RevokeExternalConnectivity(nid, eid string) error {
 		return EndpointNotFoundError(eid)
 	}
 
+	d.portIfaceMu.Lock()
+	defer d.portIfaceMu.Unlock()
+
 	err = network.releasePorts(endpoint)
 	if err != nil {
 		logrus.Warn(err)
 	}
 
 	endpoint.portMapping = nil
+	// Stop binding ports on the addresses added to host interfaces.
+	if endpoint.extConnConfig != nil {
+		endpoint.extConnConfig.PortBindings = nil
+	}
 
 	// Clean the connection tracker state of the host for the specific endpoint. This is a precautionary measure to
 	// avoid new endpoints getting the same IP address to receive unexpected packets due to bad conntrack state leading
@@ -30,7 +30,15 @@ func (n *bridgeNetwork) allocatePorts(ep *bridgeEndpoint, reqDefBindIP net.IP, u
 		containerIPv6 = ep.addrv6.IP
 	}
 
-	pb, err := n.allocatePortsInternal(ep.extConnConfig.PortBindings, ep.addr.IP, containerIPv6, defHostIP, ulPxyEnabled)
+	bindings := ep.extConnConfig.PortBindings
+	if hasPortBindingIfaces(bindings) {
+		if n.driver != nil {
+			n.driver.watchPortIfaces()
+		}
+		bindings = resolvePortBindingIfaces(bindings)
+	}
+
+	pb, err := n.allocatePortsInternal(bindings, ep.addr.IP, containerIPv6, defHostIP, ulPxyEnabled)
 	if err != nil {
 		return nil, err
 	}
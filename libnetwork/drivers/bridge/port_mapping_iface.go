@@ -0,0 +1,192 @@
+//go:build linux
+// +build linux
+
+package bridge
+
+import (
+	"net"
+
+	"github.com/docker/docker/libnetwork/ns"
+	"github.com/docker/docker/libnetwork/types"
+	"github.com/sirupsen/logrus"
+	"github.com/vishvananda/netlink"
+)
+
+// hasPortBindingIfaces returns whether some of the bindings are for the
+// addresses of a host interface.
+func hasPortBindingIfaces(bindings []types.PortBinding) bool {
+	for _, b := range bindings {
+		if b.HostIface != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// resolvePortBindingIfaces replaces the bindings for host interfaces by
+// bindings for their current addresses. Interfaces without addresses yet are
+// bound once they get some, by the interface watch of the driver.
+func resolvePortBindingIfaces(bindings []types.PortBinding) []types.PortBinding {
+	bs := make([]types.PortBinding, 0, len(bindings))
+	for _, b := range bindings {
+		if b.HostIface == "" || b.HostIP != nil {
+			bs = append(bs, b)
+			continue
+		}
+		for _, ip := range ifaceAddrs(b.HostIface) {
+			c := b.GetCopy()
+			c.HostIP = ip
+			bs = append(bs, c)
+		}
+	}
+	return bs
+}
+
+// ifaceAddrs returns the addresses of a host interface ports can be bound
+// on.
+func ifaceAddrs(name string) []net.IP {
+	nlh := ns.NlHandle()
+	link, err := nlh.LinkByName(name)
+	if err != nil {
+		logrus.WithError(err).Debugf("No addresses to bind ports on for interface %s", name)
+		return nil
+	}
+	addrs, err := nlh.AddrList(link, netlink.FAMILY_ALL)
+	if err != nil {
+		logrus.WithError(err).Warnf("Failed to list the addresses of interface %s", name)
+		return nil
+	}
+	var ips []net.IP
+	for _, a := range addrs {
+		if bindableIfaceAddr(a.IP) {
+			ips = append(ips, a.IP)
+		}
+	}
+	return ips
+}
+
+// bindableIfaceAddr returns whether ports can be bound on an address of an
+// interface. Link-local IPv6 addresses need a zone to be bound.
+func bindableIfaceAddr(ip net.IP) bool {
+	return ip != nil && !ip.IsLinkLocalUnicast() && !ip.IsLoopback()
+}
+
+// watchPortIfaces starts tracking the address changes of the host interfaces,
+// to update the port bindings for them.
+func (d *driver) watchPortIfaces() {
+	d.portIfaceWatch.Do(func() {
+		ch := make(chan netlink.AddrUpdate)
+		err := netlink.AddrSubscribeWithOptions(ch, nil, netlink.AddrSubscribeOptions{
+			ErrorCallback: func(err error) {
+				logrus.WithError(err).Warn("Error while watching the addresses of port binding interfaces")
+			},
+		})
+		if err != nil {
+			logrus.WithError(err).Warn("Port bindings for host interfaces will not track address changes")
+			return
+		}
+		go func() {
+			for u := range ch {
+				d.handlePortIfaceUpdate(u)
+			}
+		}()
+	})
+}
+
+func (d *driver) handlePortIfaceUpdate(u netlink.AddrUpdate) {
+	ip := u.LinkAddress.IP
+	if !bindableIfaceAddr(ip) {
+		return
+	}
+	var iface string
+	if u.NewAddr {
+		link, err := ns.NlHandle().LinkByIndex(u.LinkIndex)
+		if err != nil {
+			return
+		}
+		iface = link.Attrs().Name
+	}
+
+	for _, n := range d.getNetworks() {
+		n.Lock()
+		eps := make([]*bridgeEndpoint, 0, len(n.endpoints))
+		for _, ep := range n.endpoints {
+			eps = append(eps, ep)
+		}
+		n.Unlock()
+
+		for _, ep := range eps {
+			n.updatePortBindingIface(ep, iface, ip, u.NewAddr)
+		}
+	}
+}
+
+// updatePortBindingIface maps the ports of the bindings of an endpoint for a
+// host interface on an address added to the interface, or unmaps the ports
+// bound on a removed address.
+func (n *bridgeNetwork) updatePortBindingIface(ep *bridgeEndpoint, iface string, ip net.IP, added bool) {
+	d := n.driver
+	d.portIfaceMu.Lock()
+	defer d.portIfaceMu.Unlock()
+
+	if ep.addr == nil || ep.extConnConfig == nil || !hasPortBindingIfaces(ep.extConnConfig.PortBindings) {
+		return
+	}
+
+	var changed bool
+	if added {
+		var containerIPv6 net.IP
+		if ep.addrv6 != nil {
+			containerIPv6 = ep.addrv6.IP
+		}
+		for _, b := range ep.extConnConfig.PortBindings {
+			if b.HostIface != iface || b.HostIP != nil || ep.hasIfacePortMapping(b, ip) {
+				continue
+			}
+			c := b.GetCopy()
+			c.HostIP = ip
+			bs, err := n.allocatePortsInternal([]types.PortBinding{c}, ep.addr.IP, containerIPv6, net.IPv4zero, d.config.EnableUserlandProxy)
+			if err != nil {
+				logrus.WithError(err).Warnf("Failed to bind port %d on address %s of interface %s for endpoint %.7s", b.Port, ip, iface, ep.id)
+				continue
+			}
+			ep.portMapping = append(ep.portMapping, bs...)
+			changed = changed || len(bs) > 0
+		}
+	} else {
+		var keep, release []types.PortBinding
+		for _, pb := range ep.portMapping {
+			if pb.HostIface != "" && pb.HostIP.Equal(ip) {
+				release = append(release, pb)
+			} else {
+				keep = append(keep, pb)
+			}
+		}
+		if len(release) == 0 {
+			return
+		}
+		if err := n.releasePortsInternal(release); err != nil {
+			logrus.WithError(err).Warnf("Failed to release the ports bound on address %s for endpoint %.7s", ip, ep.id)
+		}
+		ep.portMapping = keep
+		changed = true
+	}
+
+	if !changed {
+		return
+	}
+	if err := d.storeUpdate(ep); err != nil {
+		logrus.WithError(err).Warnf("Failed to update bridge endpoint %.7s to store", ep.id)
+	}
+}
+
+// hasIfacePortMapping returns whether the ports of a binding for a host
+// interface are already mapped on an address.
+func (ep *bridgeEndpoint) hasIfacePortMapping(b types.PortBinding, ip net.IP) bool {
+	for _, pb := range ep.portMapping {
+		if pb.HostIface == b.HostIface && pb.HostIP.Equal(ip) && pb.Proto == b.Proto && pb.Port == b.Port {
+			return true
+		}
+	}
+	return false
+}
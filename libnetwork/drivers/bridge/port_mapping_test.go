@@ -5,6 +5,7 @@ package bridge
 
 import (
 	"os"
+	"sort"
 	"testing"
 
 	"github.com/docker/docker/libnetwork/netlabel"
@@ -12,6 +13,7 @@ import (
 	"github.com/docker/docker/libnetwork/testutils"
 	"github.com/docker/docker/libnetwork/types"
 	"github.com/docker/docker/pkg/reexec"
+	"github.com/vishvananda/netlink"
 )
 
 func TestMain(m *testing.M) {
@@ -175,6 +177,52 @@ func TestPortMappingV6Config(t *testing.T) {
 	}
 }
 
+func TestResolvePortBindingIfaces(t *testing.T) {
+	defer testutils.SetupTestOSContext(t)()
+
+	nlh := ns.NlHandle()
+	link := &netlink.Bridge{LinkAttrs: netlink.LinkAttrs{Name: "pbiface0"}}
+	if err := nlh.LinkAdd(link); err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range []string{"192.0.2.10/24", "2001:db8::10/64"} {
+		addr, err := netlink.ParseAddr(a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := nlh.AddrAdd(link, addr); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	bindings := []types.PortBinding{
+		{Proto: types.TCP, Port: 80, HostPort: 8080, HostIface: "pbiface0"},
+		{Proto: types.TCP, Port: 443, HostPort: 8443},
+		{Proto: types.UDP, Port: 53, HostPort: 5353, HostIface: "missing0"},
+	}
+	bs := resolvePortBindingIfaces(bindings)
+	if len(bs) != 3 {
+		t.Fatalf("Expected 3 bindings, got %v", bs)
+	}
+	var ips []string
+	for _, b := range bs {
+		if b.Port != 80 {
+			continue
+		}
+		if b.HostIface != "pbiface0" || b.HostPort != 8080 {
+			t.Fatalf("Unexpected binding %v", b)
+		}
+		ips = append(ips, b.HostIP.String())
+	}
+	sort.Strings(ips)
+	if len(ips) != 2 || ips[0] != "192.0.2.10" || ips[1] != "2001:db8::10" {
+		t.Fatalf("Expected the ports to be bound on the addresses of the interface, got %v", ips)
+	}
+	if bs[2].Port != 443 || bs[2].HostIP != nil {
+		t.Fatalf("Expected the binding without interface to be kept, got %v", bs[2])
+	}
+}
+
 func loopbackUp() error {
 	nlHandle := ns.NlHandle()
 	iface, err := nlHandle.LinkByName("lo")
@@ -0,0 +1,90 @@
+//go:build linux
+// +build linux
+
+package bridge
+
+import (
+	"sort"
+
+	"github.com/vishvananda/netlink"
+)
+
+// portState is an interface attached to the bridge of a network.
+type portState struct {
+	Interface string
+	MAC       string
+	State     string
+}
+
+// endpointState is an endpoint of a network, with its port mappings.
+type endpointState struct {
+	ID           string
+	MAC          string
+	IPv4         string `json:",omitempty"`
+	IPv6         string `json:",omitempty"`
+	PortMappings []string
+}
+
+// NetworkState returns the bridge of the network, the interfaces attached to
+// it, and the endpoints of the network with their port mappings.
+func (d *driver) NetworkState(nid string) (map[string]interface{}, error) {
+	n, err := d.getNetwork(nid)
+	if err != nil {
+		return nil, err
+	}
+
+	n.Lock()
+	bridgeName := n.config.BridgeName
+	endpoints := make([]endpointState, 0, len(n.endpoints))
+	for _, ep := range n.endpoints {
+		s := endpointState{ID: ep.id, MAC: ep.macAddress.String(), PortMappings: []string{}}
+		if ep.addr != nil {
+			s.IPv4 = ep.addr.String()
+		}
+		if ep.addrv6 != nil {
+			s.IPv6 = ep.addrv6.String()
+		}
+		for _, pb := range ep.portMapping {
+			s.PortMappings = append(s.PortMappings, pb.String())
+		}
+		endpoints = append(endpoints, s)
+	}
+	n.Unlock()
+	sort.Slice(endpoints, func(i, j int) bool { return endpoints[i].ID < endpoints[j].ID })
+
+	ports, err := bridgePorts(d.nlh, bridgeName)
+	return map[string]interface{}{
+		"Bridge":    bridgeName,
+		"Ports":     ports,
+		"Endpoints": endpoints,
+	}, err
+}
+
+// bridgePorts returns the interfaces attached to the bridge.
+func bridgePorts(nlh *netlink.Handle, bridgeName string) ([]portState, error) {
+	ports := []portState{}
+	if nlh == nil {
+		return ports, nil
+	}
+	br, err := nlh.LinkByName(bridgeName)
+	if err != nil {
+		return ports, err
+	}
+	links, err := nlh.LinkList()
+	if err != nil {
+		return ports, err
+	}
+	for _, l := range links {
+		attrs := l.Attrs()
+		if attrs.MasterIndex != br.Attrs().Index {
+			continue
+		}
+		ports = append(ports, portState{
+			Interface: attrs.Name,
+			MAC:       attrs.HardwareAddr.String(),
+			State:     attrs.OperState.String(),
+		})
+	}
+	sort.Slice(ports, func(i, j int) bool { return ports[i].Interface < ports[j].Interface })
+	return ports, nil
+}
@@ -0,0 +1,44 @@
+//go:build linux
+// +build linux
+
+package macvlan
+
+import (
+	"github.com/docker/docker/libnetwork/ns"
+)
+
+// parentState is the state of the parent interface of a network.
+type parentState struct {
+	Name string
+	// Created is set if the parent was created by the driver, such as a
+	// VLAN sub-interface or a dummy interface for internal networks.
+	Created bool
+	Exists  bool
+	State   string `json:",omitempty"`
+	MTU     int    `json:",omitempty"`
+}
+
+// NetworkState returns the state of the parent interface of the network, the
+// macvlan mode of the network, and its number of endpoints.
+func (d *driver) NetworkState(nid string) (map[string]interface{}, error) {
+	n, err := d.getNetwork(nid)
+	if err != nil {
+		return nil, err
+	}
+
+	n.Lock()
+	endpoints := len(n.endpoints)
+	n.Unlock()
+
+	parent := parentState{Name: n.config.Parent, Created: n.config.CreatedSlaveLink}
+	if link, err := ns.NlHandle().LinkByName(parent.Name); err == nil {
+		parent.Exists = true
+		parent.State = link.Attrs().OperState.String()
+		parent.MTU = link.Attrs().MTU
+	}
+	return map[string]interface{}{
+		"Parent":    parent,
+		"Mode":      n.config.MacvlanMode,
+		"Endpoints": endpoints,
+	}, nil
+}
@@ -0,0 +1,172 @@
+//go:build linux
+// +build linux
+
+package overlay
+
+import (
+	"fmt"
+	"sort"
+	"syscall"
+
+	"github.com/docker/docker/libnetwork/types"
+	"github.com/vishvananda/netlink"
+)
+
+// DumpNetworkState returns the peers of the network known to the driver, and
+// the forwarding database and neighbor entries programmed in the namespace of
+// the network.
+func (d *driver) DumpNetworkState(nid string) (map[string][]string, error) {
+	n := d.network(nid)
+	if n == nil {
+		return nil, types.NotFoundErrorf("network %s not found", nid)
+	}
+
+	state := make(map[string][]string)
+	for _, p := range d.peerStates(nid) {
+		state["peers"] = append(state["peers"], fmt.Sprintf("%s %s vtep:%s endpoint:%s local:%t", p.IP, p.MAC, p.VTEP, p.EndpointID, p.Local))
+	}
+	sort.Strings(state["peers"])
+
+	fdb, neighbors, err := n.neighbors()
+	for _, e := range fdb {
+		state["fdb"] = append(state["fdb"], fmt.Sprintf("%s dst %s dev %s state %s", e.MAC, e.IP, e.Dev, e.State))
+	}
+	for _, e := range neighbors {
+		state["neighbors"] = append(state["neighbors"], fmt.Sprintf("%s lladdr %s dev %s state %s", e.IP, e.MAC, e.Dev, e.State))
+	}
+	return state, err
+}
+
+// NetworkState returns the VXLAN identifiers of the network, its peers known
+// to the driver, and the forwarding database and neighbor entries programmed
+// in the namespace of the network.
+func (d *driver) NetworkState(nid string) (map[string]interface{}, error) {
+	n := d.network(nid)
+	if n == nil {
+		return nil, types.NotFoundErrorf("network %s not found", nid)
+	}
+
+	n.Lock()
+	vnis := make([]uint32, 0, len(n.subnets))
+	for _, s := range n.subnets {
+		vnis = append(vnis, s.vni)
+	}
+	n.Unlock()
+
+	fdb, neighbors, err := n.neighbors()
+	return map[string]interface{}{
+		"VNIs":      vnis,
+		"Peers":     d.peerStates(nid),
+		"FDB":       fdb,
+		"Neighbors": neighbors,
+	}, err
+}
+
+// peerState is a peer of a network known to the driver.
+type peerState struct {
+	IP         string
+	MAC        string
+	VTEP       string
+	EndpointID string
+	Local      bool
+}
+
+func (d *driver) peerStates(nid string) []peerState {
+	peers := []peerState{}
+	d.peerDbNetworkWalk(nid, func(pKey *peerKey, pEntry *peerEntry) bool { //nolint:errcheck
+		peers = append(peers, peerState{
+			IP:         pKey.peerIP.String(),
+			MAC:        pKey.peerMac.String(),
+			VTEP:       pEntry.vtep.String(),
+			EndpointID: pEntry.eid,
+			Local:      pEntry.isLocal,
+		})
+		return false
+	})
+	sort.Slice(peers, func(i, j int) bool { return peers[i].IP < peers[j].IP })
+	return peers
+}
+
+// neighEntry is a forwarding database or neighbor entry of the namespace of a
+// network.
+type neighEntry struct {
+	IP    string
+	MAC   string
+	Dev   string
+	State string
+}
+
+// neighbors returns the forwarding database and neighbor entries programmed
+// in the namespace of the network. The namespace of the network is only
+// created once an endpoint of the network joins a sandbox on this node.
+func (n *network) neighbors() (fdb, neighbors []neighEntry, _ error) {
+	fdb, neighbors = []neighEntry{}, []neighEntry{}
+	sbox := n.sandbox()
+	if sbox == nil {
+		return fdb, neighbors, nil
+	}
+
+	var nlErr error
+	err := sbox.InvokeFunc(func() {
+		names := make(map[int]string)
+		linkName := func(index int) string {
+			if name, ok := names[index]; ok {
+				return name
+			}
+			name := fmt.Sprintf("if%d", index)
+			if l, err := netlink.LinkByIndex(index); err == nil {
+				name = l.Attrs().Name
+			}
+			names[index] = name
+			return name
+		}
+
+		entries, err := netlink.NeighList(0, syscall.AF_BRIDGE)
+		if err != nil {
+			nlErr = fmt.Errorf("listing forwarding database entries: %v", err)
+			return
+		}
+		for _, e := range entries {
+			fdb = append(fdb, neighEntry{IP: e.IP.String(), MAC: e.HardwareAddr.String(), Dev: linkName(e.LinkIndex), State: neighState(e.State)})
+		}
+
+		entries, err = netlink.NeighList(0, netlink.FAMILY_ALL)
+		if err != nil {
+			nlErr = fmt.Errorf("listing neighbor entries: %v", err)
+			return
+		}
+		for _, e := range entries {
+			if e.Family == syscall.AF_BRIDGE {
+				continue
+			}
+			neighbors = append(neighbors, neighEntry{IP: e.IP.String(), MAC: e.HardwareAddr.String(), Dev: linkName(e.LinkIndex), State: neighState(e.State)})
+		}
+	})
+	if err == nil {
+		err = nlErr
+	}
+	return fdb, neighbors, err
+}
+
+// neighState returns the name of the state of a neighbor entry.
+func neighState(state int) string {
+	switch state {
+	case netlink.NUD_PERMANENT:
+		return "PERMANENT"
+	case netlink.NUD_NOARP:
+		return "NOARP"
+	case netlink.NUD_REACHABLE:
+		return "REACHABLE"
+	case netlink.NUD_STALE:
+		return "STALE"
+	case netlink.NUD_DELAY:
+		return "DELAY"
+	case netlink.NUD_PROBE:
+		return "PROBE"
+	case netlink.NUD_FAILED:
+		return "FAILED"
+	case netlink.NUD_INCOMPLETE:
+		return "INCOMPLETE"
+	}
+	return fmt.Sprintf("0x%x", state)
+}
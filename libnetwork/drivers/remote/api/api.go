@@ -130,6 +130,18 @@ type DeleteEndpointResponse struct {
 	Response
 }
 
+// NetworkStateRequest retrieves the runtime state of a network from the
+// network driver.
+type NetworkStateRequest struct {
+	NetworkID string
+}
+
+// NetworkStateResponse is the response to a NetworkStateRequest.
+type NetworkStateResponse struct {
+	Response
+	State map[string]interface{}
+}
+
 // EndpointInfoRequest retrieves information about the endpoint from the network driver.
 type EndpointInfoRequest struct {
 	NetworkID  string
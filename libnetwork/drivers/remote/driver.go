@@ -31,7 +31,13 @@ func newDriver(name string, client *plugins.Client) driverapi.Driver {
 // Init makes sure a remote driver is registered when a network driver
 // plugin is activated.
 func Init(dc driverapi.DriverCallback, config map[string]interface{}) error {
+	grpcDrivers := newGRPCDrivers(dc)
 	newPluginHandler := func(name string, client *plugins.Client) {
+		if client == nil {
+			// Managed plugins only have a client if they use the HTTP protocol.
+			grpcDrivers.register(name)
+			return
+		}
 		// negotiate driver capability with client
 		d := newDriver(name, client)
 		c, err := d.(*driver).getCapabilities()
@@ -48,6 +54,9 @@ func Init(dc driverapi.DriverCallback, config map[string]interface{}) error {
 	handleFunc := plugins.Handle
 	if pg := dc.GetPluginGetter(); pg != nil {
 		handleFunc = pg.Handle
+		if dh, ok := pg.(plugingetter.DisableHandler); ok {
+			dh.HandleDisable(driverapi.NetworkPluginEndpointType, grpcDrivers.unregister)
+		}
 		activePlugins := pg.GetAllManagedPluginsByCap(driverapi.NetworkPluginEndpointType)
 		for _, ap := range activePlugins {
 			client, err := getPluginClient(ap)
@@ -90,18 +99,23 @@ func (d *driver) getCapabilities() (*driverapi.Capability, error) {
 	if err := d.call("GetCapabilities", nil, &capResp); err != nil {
 		return nil, err
 	}
+	return parseCapability(capResp.Scope, capResp.ConnectivityScope)
+}
 
+// parseCapability returns the capability of a driver reporting the given data
+// and connectivity scopes.
+func parseCapability(scope, connectivityScope string) (*driverapi.Capability, error) {
 	c := &driverapi.Capability{}
-	switch capResp.Scope {
+	switch scope {
 	case "global":
 		c.DataScope = datastore.GlobalScope
 	case "local":
 		c.DataScope = datastore.LocalScope
 	default:
-		return nil, fmt.Errorf("invalid capability: expecting 'local' or 'global', got %s", capResp.Scope)
+		return nil, fmt.Errorf("invalid capability: expecting 'local' or 'global', got %s", scope)
 	}
 
-	switch capResp.ConnectivityScope {
+	switch connectivityScope {
 	case "global":
 		c.ConnectivityScope = datastore.GlobalScope
 	case "local":
@@ -109,7 +123,7 @@ func (d *driver) getCapabilities() (*driverapi.Capability, error) {
 	case "":
 		c.ConnectivityScope = c.DataScope
 	default:
-		return nil, fmt.Errorf("invalid capability: expecting 'local' or 'global', got %s", capResp.Scope)
+		return nil, fmt.Errorf("invalid capability: expecting 'local' or 'global', got %s", connectivityScope)
 	}
 
 	return c, nil
@@ -256,6 +270,21 @@ func (d *driver) EndpointOperInfo(nid, eid string) (map[string]interface{}, erro
 	return res.Value, nil
 }
 
+// NetworkState returns the runtime state of the network in the plugin, or
+// nil if the plugin does not report it.
+func (d *driver) NetworkState(nid string) (map[string]interface{}, error) {
+	var res api.NetworkStateResponse
+	err := d.call("NetworkState", &api.NetworkStateRequest{NetworkID: nid}, &res)
+	if err != nil && plugins.IsNotFound(err) {
+		// It is not mandatory to support this method
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res.State, nil
+}
+
 // Join method is invoked when a Sandbox is attached to an endpoint.
 func (d *driver) Join(nid, eid string, sboxKey string, jinfo driverapi.JoinInfo, options map[string]interface{}) error {
 	join := &api.JoinRequest{
@@ -406,6 +406,11 @@ func TestRemoteDriver(t *testing.T) {
 			},
 		}
 	})
+	handle(t, mux, "NetworkState", func(msg map[string]interface{}) interface{} {
+		return map[string]interface{}{
+			"State": map[string]string{"Tunnels": "2"},
+		}
+	})
 	handle(t, mux, "DiscoverNew", func(msg map[string]interface{}) interface{} {
 		return map[string]string{}
 	})
@@ -462,6 +467,11 @@ func TestRemoteDriver(t *testing.T) {
 	if _, err = d.EndpointOperInfo(netID, endID); err != nil {
 		t.Fatal(err)
 	}
+	if state, err := d.(*driver).NetworkState(netID); err != nil {
+		t.Fatal(err)
+	} else if state["Tunnels"] != "2" {
+		t.Fatalf("Unexpected network state %v", state)
+	}
 	if err = d.Leave(netID, endID); err != nil {
 		t.Fatal(err)
 	}
@@ -0,0 +1,649 @@
+package remote
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/libnetwork/discoverapi"
+	"github.com/docker/docker/libnetwork/driverapi"
+	"github.com/docker/docker/libnetwork/drivers/remote/api"
+	"github.com/docker/docker/libnetwork/drivers/remote/grpcapi"
+	"github.com/docker/docker/libnetwork/types"
+	"github.com/docker/docker/pkg/plugingetter"
+	"github.com/docker/docker/pkg/plugins"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// defaultGRPCTimeout is the deadline of the calls to plugins which don't set
+// a timeout.
+const defaultGRPCTimeout = 30 * time.Second
+
+// tableEventsQueueSize is the number of table events queued for a plugin,
+// beyond which they are dropped.
+const tableEventsQueueSize = 256
+
+// grpcDrivers tracks the drivers of the plugins using the gRPC protocol, to
+// close the connection of a driver when its plugin is disabled, or enabled
+// again.
+type grpcDrivers struct {
+	dc driverapi.DriverCallback
+
+	mu      sync.Mutex
+	drivers map[string]*grpcDriver
+}
+
+func newGRPCDrivers(dc driverapi.DriverCallback) *grpcDrivers {
+	return &grpcDrivers{dc: dc, drivers: make(map[string]*grpcDriver)}
+}
+
+// register connects to the managed plugin with the given name, and registers
+// its driver.
+func (r *grpcDrivers) register(name string) {
+	pg := r.dc.GetPluginGetter()
+	if pg == nil {
+		logrus.Errorf("error registering driver for %s: no plugin getter", name)
+		return
+	}
+	p, err := pg.Get(name, driverapi.NetworkPluginEndpointType, plugingetter.Lookup)
+	if err != nil {
+		logrus.Errorf("error registering driver for %s due to %v", name, err)
+		return
+	}
+	pa, ok := p.(plugingetter.PluginAddr)
+	if !ok || pa.Protocol() != plugins.ProtocolSchemeGRPCV1 {
+		logrus.Errorf("error registering driver for %s: unsupported plugin protocol", name)
+		return
+	}
+
+	d, c, err := newGRPCDriver(name, pa.Addr(), pa.Timeout())
+	if err != nil {
+		logrus.Errorf("error getting capability for %s due to %v", name, err)
+		return
+	}
+	if err := r.dc.RegisterDriver(name, d, *c); err != nil {
+		logrus.Errorf("error registering driver for %s due to %v", name, err)
+		d.close()
+		return
+	}
+
+	r.mu.Lock()
+	if old := r.drivers[name]; old != nil {
+		old.close()
+	}
+	r.drivers[name] = d
+	r.mu.Unlock()
+}
+
+// unregister closes the connection of the driver of the plugin with the given
+// name, which was disabled. The driver stays registered, and fails the calls
+// until the plugin is enabled again.
+func (r *grpcDrivers) unregister(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if d := r.drivers[name]; d != nil {
+		d.close()
+		delete(r.drivers, name)
+	}
+}
+
+// grpcDriver is the driver of a network plugin using the gRPC protocol.
+type grpcDriver struct {
+	networkType string
+	conn        *grpc.ClientConn
+	client      grpcapi.NetworkDriverClient
+	timeout     time.Duration
+	stop        context.CancelFunc
+	tableEvents chan *grpcapi.TableEventRequest
+
+	mu    sync.Mutex
+	links map[string]*grpcapi.LinkEvent       // by endpoint ID
+	peers map[string]map[string]*grpcapi.Peer // by network ID, and address
+}
+
+// newGRPCDriver connects to the plugin listening at addr, and negotiates the
+// protocol version and the capability of the driver.
+func newGRPCDriver(name string, addr net.Addr, timeout time.Duration) (*grpcDriver, *driverapi.Capability, error) {
+	if timeout <= 0 {
+		timeout = defaultGRPCTimeout
+	}
+	conn, err := grpc.Dial(addr.Network()+"://"+addr.String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "error connecting to plugin")
+	}
+	d := &grpcDriver{
+		networkType: name,
+		conn:        conn,
+		client:      grpcapi.NewNetworkDriverClient(conn),
+		timeout:     timeout,
+		stop:        func() {},
+		tableEvents: make(chan *grpcapi.TableEventRequest, tableEventsQueueSize),
+		links:       make(map[string]*grpcapi.LinkEvent),
+		peers:       make(map[string]map[string]*grpcapi.Peer),
+	}
+
+	ctx, cancel := d.callContext()
+	defer cancel()
+	res, err := d.client.Handshake(ctx, &grpcapi.HandshakeRequest{
+		ProtocolVersion: grpcapi.ProtocolVersion,
+		Features:        []string{grpcapi.FeatureEvents},
+	}, grpc.WaitForReady(true))
+	if err != nil {
+		conn.Close()
+		return nil, nil, grpcError(err)
+	}
+	if res.ProtocolVersion == 0 || res.ProtocolVersion > grpcapi.ProtocolVersion {
+		conn.Close()
+		return nil, nil, fmt.Errorf("unsupported protocol version %d", res.ProtocolVersion)
+	}
+	c, err := parseCapability(res.Scope, res.ConnectivityScope)
+	if err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+
+	runCtx, stop := context.WithCancel(context.Background())
+	d.stop = stop
+	go d.sendTableEvents(runCtx)
+	for _, f := range res.Features {
+		if f == grpcapi.FeatureEvents {
+			go d.watchEvents(runCtx)
+		}
+	}
+	return d, c, nil
+}
+
+func (d *grpcDriver) close() {
+	d.stop()
+	d.conn.Close()
+}
+
+// callContext returns the context of a call to the plugin, which sets its
+// deadline.
+func (d *grpcDriver) callContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), d.timeout)
+}
+
+// grpcError converts the error returned by a call to a plugin.
+func grpcError(err error) error {
+	if err == nil {
+		return nil
+	}
+	s, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	switch s.Code() {
+	case codes.InvalidArgument:
+		return types.BadRequestErrorf("remote: %s", s.Message())
+	case codes.NotFound:
+		return types.NotFoundErrorf("remote: %s", s.Message())
+	case codes.PermissionDenied:
+		return types.ForbiddenErrorf("remote: %s", s.Message())
+	case codes.Unimplemented:
+		return types.NotImplementedErrorf("remote: %s", s.Message())
+	case codes.DeadlineExceeded:
+		return types.TimeoutErrorf("remote: %s", s.Message())
+	}
+	return fmt.Errorf("remote: %s", s.Message())
+}
+
+// watchEvents handles the events streamed by the plugin until ctx is done.
+func (d *grpcDriver) watchEvents(ctx context.Context) {
+	for {
+		stream, err := d.client.Events(ctx, &grpcapi.EventsRequest{}, grpc.WaitForReady(true))
+		for err == nil {
+			var ev *grpcapi.Event
+			if ev, err = stream.Recv(); err == nil {
+				d.handleEvent(ev)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Second):
+		}
+		logrus.WithError(err).WithField("driver", d.networkType).Debug("restarting the events stream of network plugin")
+	}
+}
+
+func (d *grpcDriver) handleEvent(ev *grpcapi.Event) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if l := ev.GetLink(); l != nil {
+		logrus.WithFields(logrus.Fields{
+			"driver":   d.networkType,
+			"network":  l.NetworkId,
+			"endpoint": l.EndpointId,
+			"up":       l.Up,
+		}).Infof("network plugin reported a link change: %s", l.Message)
+		d.links[l.EndpointId] = l
+	}
+	if p := ev.GetPeer(); p != nil && p.Peer != nil {
+		peers := d.peers[p.NetworkId]
+		if p.Removed {
+			delete(peers, p.Peer.Address)
+			return
+		}
+		if peers == nil {
+			peers = make(map[string]*grpcapi.Peer)
+			d.peers[p.NetworkId] = peers
+		}
+		peers[p.Peer.Address] = p.Peer
+	}
+}
+
+func (d *grpcDriver) NetworkAllocate(id string, options map[string]string, ipV4Data, ipV6Data []driverapi.IPAMData) (map[string]string, error) {
+	ctx, cancel := d.callContext()
+	defer cancel()
+	res, err := d.client.AllocateNetwork(ctx, &grpcapi.AllocateNetworkRequest{
+		NetworkId: id,
+		Options:   options,
+		Ipv4Data:  toGRPCIPAMData(ipV4Data),
+		Ipv6Data:  toGRPCIPAMData(ipV6Data),
+	})
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return res.Options, nil
+}
+
+func (d *grpcDriver) NetworkFree(id string) error {
+	ctx, cancel := d.callContext()
+	defer cancel()
+	_, err := d.client.FreeNetwork(ctx, &grpcapi.FreeNetworkRequest{NetworkId: id})
+	return grpcError(err)
+}
+
+// EventNotify queues the notification of the plugin of a change of an entry
+// of a table it registered. It doesn't block the processing of the events of
+// the other drivers: the notification is dropped if the queue is full.
+func (d *grpcDriver) EventNotify(etype driverapi.EventType, nid, tableName, key string, value []byte) {
+	req := &grpcapi.TableEventRequest{
+		Type:      grpcapi.TableEventType(etype),
+		NetworkId: nid,
+		Entry:     &grpcapi.TableEntry{TableName: tableName, Key: key, Value: value},
+	}
+	select {
+	case d.tableEvents <- req:
+	default:
+		logrus.WithFields(logrus.Fields{
+			"driver":  d.networkType,
+			"network": nid,
+			"table":   tableName,
+			"key":     key,
+		}).Warn("dropping table event: the network plugin is not keeping up")
+	}
+}
+
+// sendTableEvents sends the queued table events to the plugin, in order,
+// until ctx is done.
+func (d *grpcDriver) sendTableEvents(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case req := <-d.tableEvents:
+			callCtx, cancel := d.callContext()
+			_, err := d.client.TableEvent(callCtx, req)
+			cancel()
+			if err != nil {
+				logrus.WithError(grpcError(err)).WithFields(logrus.Fields{
+					"driver":  d.networkType,
+					"network": req.NetworkId,
+					"table":   req.Entry.TableName,
+				}).Warn("failed to notify network plugin of a table event")
+			}
+		}
+	}
+}
+
+func (d *grpcDriver) DecodeTableEntry(tablename string, key string, value []byte) (string, map[string]string) {
+	ctx, cancel := d.callContext()
+	defer cancel()
+	res, err := d.client.DecodeTableEntry(ctx, &grpcapi.DecodeTableEntryRequest{
+		Entry: &grpcapi.TableEntry{TableName: tablename, Key: key, Value: value},
+	})
+	if err != nil {
+		logrus.WithError(grpcError(err)).WithFields(logrus.Fields{
+			"driver": d.networkType,
+			"table":  tablename,
+			"key":    key,
+		}).Error("failed to decode table entry")
+		return "", nil
+	}
+	return res.EndpointId, res.Info
+}
+
+func (d *grpcDriver) CreateNetwork(id string, options map[string]interface{}, nInfo driverapi.NetworkInfo, ipV4Data, ipV6Data []driverapi.IPAMData) error {
+	opts, err := json.Marshal(options)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := d.callContext()
+	defer cancel()
+	res, err := d.client.CreateNetwork(ctx, &grpcapi.CreateNetworkRequest{
+		NetworkId: id,
+		Options:   opts,
+		Ipv4Data:  toGRPCIPAMData(ipV4Data),
+		Ipv6Data:  toGRPCIPAMData(ipV6Data),
+	})
+	if err != nil {
+		return grpcError(err)
+	}
+	for _, t := range res.Tables {
+		objType := driverapi.ObjectType(t.ObjectType)
+		if !driverapi.IsValidType(objType) {
+			return errorWithRollback(fmt.Sprintf("invalid object type %d of table %s", t.ObjectType, t.Name), d.DeleteNetwork(id))
+		}
+		if err := nInfo.TableEventRegister(t.Name, objType); err != nil {
+			return errorWithRollback(fmt.Sprintf("failed to register table %s: %v", t.Name, err), d.DeleteNetwork(id))
+		}
+	}
+	return nil
+}
+
+func (d *grpcDriver) DeleteNetwork(nid string) error {
+	ctx, cancel := d.callContext()
+	defer cancel()
+	if _, err := d.client.DeleteNetwork(ctx, &grpcapi.DeleteNetworkRequest{NetworkId: nid}); err != nil {
+		return grpcError(err)
+	}
+	d.mu.Lock()
+	delete(d.peers, nid)
+	d.mu.Unlock()
+	return nil
+}
+
+func (d *grpcDriver) CreateEndpoint(nid, eid string, ifInfo driverapi.InterfaceInfo, epOptions map[string]interface{}) error {
+	if ifInfo == nil {
+		return errors.New("must not be called with nil InterfaceInfo")
+	}
+
+	reqIface := &grpcapi.EndpointInterface{}
+	if ifInfo.Address() != nil {
+		reqIface.Address = ifInfo.Address().String()
+	}
+	if ifInfo.AddressIPv6() != nil {
+		reqIface.AddressIpv6 = ifInfo.AddressIPv6().String()
+	}
+	if ifInfo.MacAddress() != nil {
+		reqIface.MacAddress = ifInfo.MacAddress().String()
+	}
+	opts, err := json.Marshal(epOptions)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := d.callContext()
+	defer cancel()
+	res, err := d.client.CreateEndpoint(ctx, &grpcapi.CreateEndpointRequest{
+		NetworkId:  nid,
+		EndpointId: eid,
+		Interface:  reqIface,
+		Options:    opts,
+	})
+	if err != nil {
+		return grpcError(err)
+	}
+	if res.Interface == nil {
+		// Remote driver did not set any field
+		return nil
+	}
+
+	inIface, err := parseInterface(api.CreateEndpointResponse{Interface: &api.EndpointInterface{
+		Address:     res.Interface.Address,
+		AddressIPv6: res.Interface.AddressIpv6,
+		MacAddress:  res.Interface.MacAddress,
+	}})
+	if err != nil {
+		return err
+	}
+	if inIface.MacAddress != nil {
+		if err := ifInfo.SetMacAddress(inIface.MacAddress); err != nil {
+			return errorWithRollback(fmt.Sprintf("driver modified interface MAC address: %v", err), d.DeleteEndpoint(nid, eid))
+		}
+	}
+	if inIface.Address != nil {
+		if err := ifInfo.SetIPAddress(inIface.Address); err != nil {
+			return errorWithRollback(fmt.Sprintf("driver modified interface address: %v", err), d.DeleteEndpoint(nid, eid))
+		}
+	}
+	if inIface.AddressIPv6 != nil {
+		if err := ifInfo.SetIPAddress(inIface.AddressIPv6); err != nil {
+			return errorWithRollback(fmt.Sprintf("driver modified interface address: %v", err), d.DeleteEndpoint(nid, eid))
+		}
+	}
+	return nil
+}
+
+func (d *grpcDriver) DeleteEndpoint(nid, eid string) error {
+	ctx, cancel := d.callContext()
+	defer cancel()
+	if _, err := d.client.DeleteEndpoint(ctx, &grpcapi.DeleteEndpointRequest{NetworkId: nid, EndpointId: eid}); err != nil {
+		return grpcError(err)
+	}
+	d.mu.Lock()
+	delete(d.links, eid)
+	d.mu.Unlock()
+	return nil
+}
+
+// EndpointOperInfo returns the operational data reported by the plugin for the
+// endpoint, along with the last link state, and the peers on its network,
+// reported through the events of the plugin.
+func (d *grpcDriver) EndpointOperInfo(nid, eid string) (map[string]interface{}, error) {
+	ctx, cancel := d.callContext()
+	defer cancel()
+	res, err := d.client.EndpointOperInfo(ctx, &grpcapi.EndpointOperInfoRequest{NetworkId: nid, EndpointId: eid})
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	info := make(map[string]interface{})
+	if len(res.Value) > 0 {
+		if err := json.Unmarshal(res.Value, &info); err != nil {
+			return nil, errors.Wrap(err, "invalid endpoint operational data")
+		}
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if l := d.links[eid]; l != nil {
+		state := "down"
+		if l.Up {
+			state = "up"
+		}
+		info["LinkState"] = state
+	}
+	if peers := d.peers[nid]; len(peers) > 0 {
+		l := make([]*grpcapi.Peer, 0, len(peers))
+		for _, p := range peers {
+			l = append(l, p)
+		}
+		info["Peers"] = l
+	}
+	return info, nil
+}
+
+// Join method is invoked when a Sandbox is attached to an endpoint.
+func (d *grpcDriver) Join(nid, eid string, sboxKey string, jinfo driverapi.JoinInfo, options map[string]interface{}) error {
+	opts, err := json.Marshal(options)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := d.callContext()
+	defer cancel()
+	res, err := d.client.Join(ctx, &grpcapi.JoinRequest{
+		NetworkId:  nid,
+		EndpointId: eid,
+		SandboxKey: sboxKey,
+		Options:    opts,
+	})
+	if err != nil {
+		return grpcError(err)
+	}
+
+	if iface := jinfo.InterfaceName(); iface != nil && (res.SrcName != "" || res.DstPrefix != "") {
+		if err := iface.SetNames(res.SrcName, res.DstPrefix); err != nil {
+			return errorWithRollback(fmt.Sprintf("failed to set interface name: %s", err), d.Leave(nid, eid))
+		}
+	}
+
+	var addr net.IP
+	if res.Gateway != "" {
+		if addr = net.ParseIP(res.Gateway); addr == nil {
+			return fmt.Errorf(`unable to parse Gateway "%s"`, res.Gateway)
+		}
+		if jinfo.SetGateway(addr) != nil {
+			return errorWithRollback(fmt.Sprintf("failed to set gateway: %v", addr), d.Leave(nid, eid))
+		}
+	}
+	if res.GatewayIpv6 != "" {
+		if addr = net.ParseIP(res.GatewayIpv6); addr == nil {
+			return fmt.Errorf(`unable to parse GatewayIPv6 "%s"`, res.GatewayIpv6)
+		}
+		if jinfo.SetGatewayIPv6(addr) != nil {
+			return errorWithRollback(fmt.Sprintf("failed to set gateway IPv6: %v", addr), d.Leave(nid, eid))
+		}
+	}
+	if len(res.StaticRoutes) > 0 {
+		jr := api.JoinResponse{StaticRoutes: make([]api.StaticRoute, 0, len(res.StaticRoutes))}
+		for _, r := range res.StaticRoutes {
+			jr.StaticRoutes = append(jr.StaticRoutes, api.StaticRoute{Destination: r.Destination, RouteType: int(r.RouteType), NextHop: r.NextHop})
+		}
+		routes, err := parseStaticRoutes(jr)
+		if err != nil {
+			return err
+		}
+		for _, route := range routes {
+			if jinfo.AddStaticRoute(route.Destination, route.RouteType, route.NextHop) != nil {
+				return errorWithRollback(fmt.Sprintf("failed to set static route: %v", route), d.Leave(nid, eid))
+			}
+		}
+	}
+	if res.DisableGatewayService {
+		jinfo.DisableGatewayService()
+	}
+	for _, e := range res.TableEntries {
+		if err := jinfo.AddTableEntry(e.TableName, e.Key, e.Value); err != nil {
+			return errorWithRollback(fmt.Sprintf("failed to add entry %s to table %s: %v", e.Key, e.TableName, err), d.Leave(nid, eid))
+		}
+	}
+	return nil
+}
+
+// Leave method is invoked when a Sandbox detaches from an endpoint.
+func (d *grpcDriver) Leave(nid, eid string) error {
+	ctx, cancel := d.callContext()
+	defer cancel()
+	_, err := d.client.Leave(ctx, &grpcapi.LeaveRequest{NetworkId: nid, EndpointId: eid})
+	return grpcError(err)
+}
+
+// ProgramExternalConnectivity is invoked to program the rules to allow external connectivity for the endpoint.
+func (d *grpcDriver) ProgramExternalConnectivity(nid, eid string, options map[string]interface{}) error {
+	opts, err := json.Marshal(options)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := d.callContext()
+	defer cancel()
+	_, err = d.client.ProgramExternalConnectivity(ctx, &grpcapi.ProgramExternalConnectivityRequest{
+		NetworkId:  nid,
+		EndpointId: eid,
+		Options:    opts,
+	})
+	if status.Code(err) == codes.Unimplemented {
+		// It is not mandatory to support this method
+		return nil
+	}
+	return grpcError(err)
+}
+
+// RevokeExternalConnectivity method is invoked to remove any external connectivity programming related to the endpoint.
+func (d *grpcDriver) RevokeExternalConnectivity(nid, eid string) error {
+	ctx, cancel := d.callContext()
+	defer cancel()
+	_, err := d.client.RevokeExternalConnectivity(ctx, &grpcapi.RevokeExternalConnectivityRequest{NetworkId: nid, EndpointId: eid})
+	if status.Code(err) == codes.Unimplemented {
+		// It is not mandatory to support this method
+		return nil
+	}
+	return grpcError(err)
+}
+
+func (d *grpcDriver) Type() string {
+	return d.networkType
+}
+
+func (d *grpcDriver) IsBuiltIn() bool {
+	return false
+}
+
+// DiscoverNew is a notification for a new discovery event, such as a new node joining a cluster
+func (d *grpcDriver) DiscoverNew(dType discoverapi.DiscoveryType, data interface{}) error {
+	if dType != discoverapi.NodeDiscovery {
+		return nil
+	}
+	notif, err := toGRPCDiscovery(dType, data)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := d.callContext()
+	defer cancel()
+	_, err = d.client.DiscoverNew(ctx, notif)
+	return grpcError(err)
+}
+
+// DiscoverDelete is a notification for a discovery delete event, such as a node leaving a cluster
+func (d *grpcDriver) DiscoverDelete(dType discoverapi.DiscoveryType, data interface{}) error {
+	if dType != discoverapi.NodeDiscovery {
+		return nil
+	}
+	notif, err := toGRPCDiscovery(dType, data)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := d.callContext()
+	defer cancel()
+	_, err = d.client.DiscoverDelete(ctx, notif)
+	return grpcError(err)
+}
+
+func toGRPCDiscovery(dType discoverapi.DiscoveryType, data interface{}) (*grpcapi.DiscoveryNotification, error) {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	return &grpcapi.DiscoveryNotification{DiscoveryType: int32(dType), Data: b}, nil
+}
+
+func toGRPCIPAMData(data []driverapi.IPAMData) []*grpcapi.IPAMData {
+	var l []*grpcapi.IPAMData
+	for _, d := range data {
+		gd := &grpcapi.IPAMData{AddressSpace: d.AddressSpace}
+		if d.Pool != nil {
+			gd.Pool = d.Pool.String()
+		}
+		if d.Gateway != nil {
+			gd.Gateway = d.Gateway.String()
+		}
+		if len(d.AuxAddresses) > 0 {
+			gd.AuxAddresses = make(map[string]string, len(d.AuxAddresses))
+			for k, a := range d.AuxAddresses {
+				gd.AuxAddresses[k] = a.String()
+			}
+		}
+		l = append(l, gd)
+	}
+	return l
+}
@@ -0,0 +1,226 @@
+package remote
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/libnetwork/datastore"
+	"github.com/docker/docker/libnetwork/driverapi"
+	"github.com/docker/docker/libnetwork/drivers/remote/grpcapi"
+	"github.com/docker/docker/libnetwork/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+type testGRPCPlugin struct {
+	grpcapi.UnimplementedNetworkDriverServer
+	protocolVersion uint32
+	events          []*grpcapi.Event
+	joins           chan *grpcapi.JoinRequest
+	tableEvents     chan *grpcapi.TableEventRequest
+}
+
+func (p *testGRPCPlugin) Handshake(_ context.Context, req *grpcapi.HandshakeRequest) (*grpcapi.HandshakeResponse, error) {
+	return &grpcapi.HandshakeResponse{
+		ProtocolVersion: p.protocolVersion,
+		Features:        req.Features,
+		Scope:           "local",
+	}, nil
+}
+
+func (p *testGRPCPlugin) Events(_ *grpcapi.EventsRequest, stream grpcapi.NetworkDriver_EventsServer) error {
+	for _, ev := range p.events {
+		if err := stream.Send(ev); err != nil {
+			return err
+		}
+	}
+	<-stream.Context().Done()
+	return nil
+}
+
+func (p *testGRPCPlugin) CreateEndpoint(_ context.Context, req *grpcapi.CreateEndpointRequest) (*grpcapi.CreateEndpointResponse, error) {
+	return &grpcapi.CreateEndpointResponse{Interface: &grpcapi.EndpointInterface{
+		Address:    "192.168.5.7/16",
+		MacAddress: "ab:cd:ef:ee:ee:ee",
+	}}, nil
+}
+
+func (p *testGRPCPlugin) EndpointOperInfo(_ context.Context, req *grpcapi.EndpointOperInfoRequest) (*grpcapi.EndpointOperInfoResponse, error) {
+	return &grpcapi.EndpointOperInfoResponse{Value: []byte(`{"Key": "value"}`)}, nil
+}
+
+func (p *testGRPCPlugin) Join(_ context.Context, req *grpcapi.JoinRequest) (*grpcapi.JoinResponse, error) {
+	p.joins <- req
+	return &grpcapi.JoinResponse{
+		SrcName:      "veth0",
+		DstPrefix:    "eth",
+		Gateway:      "192.168.0.1",
+		StaticRoutes: []*grpcapi.StaticRoute{{Destination: "10.0.0.0/8", RouteType: 1, NextHop: "192.168.0.2"}},
+	}, nil
+}
+
+func (p *testGRPCPlugin) CreateNetwork(_ context.Context, req *grpcapi.CreateNetworkRequest) (*grpcapi.CreateNetworkResponse, error) {
+	return &grpcapi.CreateNetworkResponse{Tables: []*grpcapi.Table{
+		{Name: "test_table", ObjectType: grpcapi.ObjectType_ENDPOINT_OBJECT},
+	}}, nil
+}
+
+func (p *testGRPCPlugin) TableEvent(_ context.Context, req *grpcapi.TableEventRequest) (*grpcapi.TableEventResponse, error) {
+	p.tableEvents <- req
+	return &grpcapi.TableEventResponse{}, nil
+}
+
+func (p *testGRPCPlugin) DecodeTableEntry(_ context.Context, req *grpcapi.DecodeTableEntryRequest) (*grpcapi.DecodeTableEntryResponse, error) {
+	return &grpcapi.DecodeTableEntryResponse{
+		EndpointId: req.Entry.Key,
+		Info:       map[string]string{"value": string(req.Entry.Value)},
+	}, nil
+}
+
+func (p *testGRPCPlugin) DeleteNetwork(_ context.Context, req *grpcapi.DeleteNetworkRequest) (*grpcapi.DeleteNetworkResponse, error) {
+	return nil, status.Error(codes.NotFound, "no such network")
+}
+
+func serveTestGRPCPlugin(t *testing.T, p *testGRPCPlugin) net.Addr {
+	dir, err := os.MkdirTemp("", "grpc-driver")
+	assert.NilError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	l, err := net.Listen("unix", filepath.Join(dir, "plugin.sock"))
+	assert.NilError(t, err)
+	s := grpc.NewServer()
+	grpcapi.RegisterNetworkDriverServer(s, p)
+	go s.Serve(l)
+	t.Cleanup(s.Stop)
+	return l.Addr()
+}
+
+func TestGRPCDriver(t *testing.T) {
+	p := &testGRPCPlugin{
+		protocolVersion: grpcapi.ProtocolVersion,
+		events: []*grpcapi.Event{
+			{Event: &grpcapi.Event_Link{Link: &grpcapi.LinkEvent{NetworkId: "dummy", EndpointId: "dummy", Up: true}}},
+			{Event: &grpcapi.Event_Peer{Peer: &grpcapi.PeerEvent{NetworkId: "dummy", Peer: &grpcapi.Peer{Address: "192.168.5.8/16"}}}},
+		},
+		joins: make(chan *grpcapi.JoinRequest, 1),
+	}
+	d, c, err := newGRPCDriver("test-grpc", serveTestGRPCPlugin(t, p), time.Second)
+	assert.NilError(t, err)
+	defer d.close()
+	assert.Check(t, is.Equal(c.DataScope, datastore.LocalScope))
+	assert.Check(t, is.Equal(c.ConnectivityScope, datastore.LocalScope))
+
+	ep := &testEndpoint{
+		t:           t,
+		src:         "veth0",
+		dst:         "eth",
+		gateway:     "192.168.0.1",
+		destination: "10.0.0.0/8",
+		nextHop:     "192.168.0.2",
+		routeType:   1,
+	}
+	assert.NilError(t, d.CreateEndpoint("dummy", "dummy", ep, map[string]interface{}{"foo": "bar"}))
+	assert.Check(t, is.Equal(ep.address, "192.168.5.7/16"))
+	assert.Check(t, is.Equal(ep.macAddress, "ab:cd:ef:ee:ee:ee"))
+
+	assert.NilError(t, d.Join("dummy", "dummy", "sandbox-key", ep, map[string]interface{}{"foo": "bar"}))
+	req := <-p.joins
+	assert.Check(t, is.Equal(req.SandboxKey, "sandbox-key"))
+	var opts map[string]interface{}
+	assert.NilError(t, json.Unmarshal(req.Options, &opts))
+	assert.Check(t, is.DeepEqual(opts, map[string]interface{}{"foo": "bar"}))
+
+	// Methods which are optional for the plugins.
+	assert.NilError(t, d.ProgramExternalConnectivity("dummy", "dummy", nil))
+	assert.NilError(t, d.RevokeExternalConnectivity("dummy", "dummy"))
+
+	err = d.DeleteNetwork("dummy")
+	assert.Check(t, is.ErrorContains(err, "no such network"))
+	assert.Check(t, is.ErrorType(err, (*types.NotFoundError)(nil)))
+
+	// The events are handled asynchronously.
+	var info map[string]interface{}
+	for i := 0; i < 100; i++ {
+		info, err = d.EndpointOperInfo("dummy", "dummy")
+		assert.NilError(t, err)
+		if info["Peers"] != nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Check(t, is.Equal(info["Key"], "value"))
+	assert.Check(t, is.Equal(info["LinkState"], "up"))
+	assert.Check(t, is.DeepEqual(info["Peers"], []*grpcapi.Peer{{Address: "192.168.5.8/16"}}))
+}
+
+type testNetworkInfo struct {
+	tables map[string]driverapi.ObjectType
+}
+
+func (n *testNetworkInfo) TableEventRegister(tableName string, objType driverapi.ObjectType) error {
+	n.tables[tableName] = objType
+	return nil
+}
+
+func (n *testNetworkInfo) UpdateIpamConfig(ipV4Data []driverapi.IPAMData) {}
+
+func TestGRPCDriverTables(t *testing.T) {
+	p := &testGRPCPlugin{
+		protocolVersion: grpcapi.ProtocolVersion,
+		tableEvents:     make(chan *grpcapi.TableEventRequest, 1),
+	}
+	d, _, err := newGRPCDriver("test-grpc", serveTestGRPCPlugin(t, p), time.Second)
+	assert.NilError(t, err)
+	defer d.close()
+
+	nInfo := &testNetworkInfo{tables: make(map[string]driverapi.ObjectType)}
+	assert.NilError(t, d.CreateNetwork("dummy", nil, nInfo, nil, nil))
+	assert.Check(t, is.DeepEqual(nInfo.tables, map[string]driverapi.ObjectType{"test_table": driverapi.EndpointObject}))
+
+	d.EventNotify(driverapi.Update, "dummy", "test_table", "key", []byte("value"))
+	select {
+	case req := <-p.tableEvents:
+		assert.Check(t, is.Equal(req.Type, grpcapi.TableEventType_TABLE_EVENT_UPDATE))
+		assert.Check(t, is.Equal(req.NetworkId, "dummy"))
+		assert.Check(t, is.Equal(req.Entry.TableName, "test_table"))
+		assert.Check(t, is.Equal(req.Entry.Key, "key"))
+		assert.Check(t, is.Equal(string(req.Entry.Value), "value"))
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for the table event")
+	}
+
+	id, info := d.DecodeTableEntry("test_table", "key", []byte("value"))
+	assert.Check(t, is.Equal(id, "key"))
+	assert.Check(t, is.DeepEqual(info, map[string]string{"value": "value"}))
+}
+
+func TestGRPCDriversUnregister(t *testing.T) {
+	p := &testGRPCPlugin{protocolVersion: grpcapi.ProtocolVersion}
+	d, _, err := newGRPCDriver("test-grpc", serveTestGRPCPlugin(t, p), time.Second)
+	assert.NilError(t, err)
+
+	r := newGRPCDrivers(nil)
+	r.drivers["test-grpc"] = d
+	r.unregister("test-grpc")
+	assert.Check(t, is.Len(r.drivers, 0))
+
+	_, err = d.EndpointOperInfo("dummy", "dummy")
+	assert.Check(t, is.ErrorContains(err, ""))
+
+	// unregistering a plugin without a driver is a no-op
+	r.unregister("test-grpc")
+}
+
+func TestGRPCDriverProtocolVersion(t *testing.T) {
+	p := &testGRPCPlugin{protocolVersion: grpcapi.ProtocolVersion + 1}
+	_, _, err := newGRPCDriver("test-grpc", serveTestGRPCPlugin(t, p), time.Second)
+	assert.Check(t, is.ErrorContains(err, "unsupported protocol version"))
+}
@@ -0,0 +1,13 @@
+//go:generate protoc -I . --gogofast_out=plugins=grpc,import_path=github.com/docker/docker/libnetwork/drivers/remote/grpcapi:. driver.proto
+
+// Package grpcapi holds the gRPC protocol of the network driver plugins
+// declaring the "moby.plugins.grpc/v1" protocol scheme.
+package grpcapi // import "github.com/docker/docker/libnetwork/drivers/remote/grpcapi"
+
+// ProtocolVersion is the latest version of the protocol supported by the
+// daemon.
+const ProtocolVersion = 1
+
+// FeatureEvents is the feature of the plugins which stream notifications
+// through NetworkDriver.Events.
+const FeatureEvents = "events"
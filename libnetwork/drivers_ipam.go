@@ -1,6 +1,9 @@
 package libnetwork
 
 import (
+	"errors"
+	"net"
+
 	"github.com/docker/docker/libnetwork/drvregistry"
 	"github.com/docker/docker/libnetwork/ipamapi"
 	builtinIpam "github.com/docker/docker/libnetwork/ipams/builtin"
@@ -28,3 +31,49 @@ func initIPAMDrivers(r *drvregistry.DrvRegistry, lDs, gDs interface{}, addressPo
 
 	return nil
 }
+
+// SetDefaultAddressPools replaces the default address pools of the built-in
+// IPAM driver, from which subnets are assigned to new local networks. The
+// built-in defaults are restored if pools is empty.
+func (c *Controller) SetDefaultAddressPools(pools []*ipamutils.NetworkToSplit) error {
+	nets := ipamutils.GetLocalScopeDefaultNetworks()
+	if len(pools) > 0 {
+		var err error
+		if nets, err = ipamutils.SplitNetworks(pools); err != nil {
+			return err
+		}
+	}
+	d, _ := c.drvRegistry.IPAM(ipamapi.DefaultIPAM)
+	a, ok := d.(interface{ SetLocalDefaultPools([]*net.IPNet) })
+	if !ok {
+		return errors.New("the default IPAM driver does not support changing its address pools")
+	}
+	if err := builtinIpam.SetDefaultIPAddressPool(pools); err != nil {
+		return err
+	}
+	a.SetLocalDefaultPools(nets)
+	c.mu.Lock()
+	c.cfg.DefaultAddressPool = pools
+	c.mu.Unlock()
+	return nil
+}
+
+// SetRouteOverlapMode sets how the built-in IPAM driver handles the subnets
+// of the default address pools which overlap the routes of the host.
+func (c *Controller) SetRouteOverlapMode(mode ipamutils.RouteOverlapMode) error {
+	if err := ipamutils.ValidateRouteOverlapMode(mode); err != nil {
+		return err
+	}
+	d, _ := c.drvRegistry.IPAM(ipamapi.DefaultIPAM)
+	a, ok := d.(interface {
+		SetRouteOverlapMode(ipamutils.RouteOverlapMode)
+	})
+	if !ok {
+		return errors.New("the default IPAM driver does not support checking the routes of the host")
+	}
+	a.SetRouteOverlapMode(mode)
+	c.mu.Lock()
+	c.cfg.RouteOverlapMode = mode
+	c.mu.Unlock()
+	return nil
+}
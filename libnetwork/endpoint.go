@@ -36,6 +36,7 @@ type Endpoint struct {
 	ipamOptions       map[string]string
 	aliases           map[string]string
 	myAliases         []string
+	myAliasOptions    map[string]AliasOptions
 	svcID             string
 	svcName           string
 	virtualIP         net.IP
@@ -45,6 +46,8 @@ type Endpoint struct {
 	dbExists          bool
 	serviceEnabled    bool
 	loadBalancer      bool
+	healthGated       bool
+	healthy           bool
 	mu                sync.Mutex
 }
 
@@ -65,12 +68,19 @@ func (ep *Endpoint) MarshalJSON() ([]byte, error) {
 	epMap["anonymous"] = ep.anonymous
 	epMap["disableResolution"] = ep.disableResolution
 	epMap["myAliases"] = ep.myAliases
+	if ep.myAliasOptions != nil {
+		epMap["myAliasOptions"] = ep.myAliasOptions
+	}
 	epMap["svcName"] = ep.svcName
 	epMap["svcID"] = ep.svcID
 	epMap["virtualIP"] = ep.virtualIP.String()
 	epMap["ingressPorts"] = ep.ingressPorts
 	epMap["svcAliases"] = ep.svcAliases
 	epMap["loadBalancer"] = ep.loadBalancer
+	if ep.healthGated {
+		epMap["healthGated"] = ep.healthGated
+		epMap["healthy"] = ep.healthy
+	}
 
 	return json.Marshal(epMap)
 }
@@ -173,6 +183,12 @@ func (ep *Endpoint) UnmarshalJSON(b []byte) (err error) {
 		ep.virtualIP = net.ParseIP(vip.(string))
 	}
 
+	if v, ok := epMap["healthGated"]; ok {
+		ep.healthGated = v.(bool)
+	}
+	if v, ok := epMap["healthy"]; ok {
+		ep.healthy = v.(bool)
+	}
 	if v, ok := epMap["loadBalancer"]; ok {
 		ep.loadBalancer = v.(bool)
 	}
@@ -191,6 +207,13 @@ func (ep *Endpoint) UnmarshalJSON(b []byte) (err error) {
 	var myAliases []string
 	json.Unmarshal(ma, &myAliases) //nolint:errcheck
 	ep.myAliases = myAliases
+
+	if v, ok := epMap["myAliasOptions"]; ok {
+		mao, _ := json.Marshal(v)
+		var myAliasOptions map[string]AliasOptions
+		json.Unmarshal(mao, &myAliasOptions) //nolint:errcheck
+		ep.myAliasOptions = myAliasOptions
+	}
 	return nil
 }
 
@@ -214,6 +237,8 @@ func (ep *Endpoint) CopyTo(o datastore.KVObject) error {
 	dstEp.svcID = ep.svcID
 	dstEp.virtualIP = ep.virtualIP
 	dstEp.loadBalancer = ep.loadBalancer
+	dstEp.healthGated = ep.healthGated
+	dstEp.healthy = ep.healthy
 
 	dstEp.svcAliases = make([]string, len(ep.svcAliases))
 	copy(dstEp.svcAliases, ep.svcAliases)
@@ -241,6 +266,14 @@ func (ep *Endpoint) CopyTo(o datastore.KVObject) error {
 	dstEp.myAliases = make([]string, len(ep.myAliases))
 	copy(dstEp.myAliases, ep.myAliases)
 
+	dstEp.myAliasOptions = nil
+	if ep.myAliasOptions != nil {
+		dstEp.myAliasOptions = make(map[string]AliasOptions, len(ep.myAliasOptions))
+		for alias, opts := range ep.myAliasOptions {
+			dstEp.myAliasOptions[alias] = opts
+		}
+	}
+
 	dstEp.generic = options.Generic{}
 	for k, v := range ep.generic {
 		dstEp.generic[k] = v
@@ -281,6 +314,15 @@ func (ep *Endpoint) Network() string {
 	return ep.network.name
 }
 
+// NetworkID returns the ID of the network to which this endpoint is attached.
+func (ep *Endpoint) NetworkID() string {
+	if ep.network == nil {
+		return ""
+	}
+
+	return ep.network.id
+}
+
 func (ep *Endpoint) isAnonymous() bool {
 	ep.mu.Lock()
 	defer ep.mu.Unlock()
@@ -308,6 +350,37 @@ func (ep *Endpoint) disableService() {
 	ep.serviceEnabled = false
 }
 
+// isDNSGated returns whether the service records of the endpoint are held
+// back until its container is healthy.
+func (ep *Endpoint) isDNSGated() bool {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+	return ep.healthGated && !ep.healthy
+}
+
+// setHealthy updates the health of the container of a health gated endpoint,
+// and adds or removes the service records of the endpoint accordingly.
+func (ep *Endpoint) setHealthy(healthy bool) {
+	ep.mu.Lock()
+	if !ep.healthGated || ep.healthy == healthy {
+		ep.mu.Unlock()
+		return
+	}
+	ep.healthy = healthy
+	ep.mu.Unlock()
+
+	n := ep.getNetwork()
+	c := n.getController()
+	c.mu.Lock()
+	nw, ok := c.nmap[n.ID()]
+	c.mu.Unlock()
+	if !ok {
+		// The service records of the network are not managed by this node.
+		return
+	}
+	n.updateSvcRecord(ep, c.getLocalEps(nw), healthy)
+}
+
 func (ep *Endpoint) needResolver() bool {
 	ep.mu.Lock()
 	defer ep.mu.Unlock()
@@ -547,7 +620,7 @@ func (ep *Endpoint) sbJoin(sb *Sandbox, options ...EndpointOption) (err error) {
 		if !n.internal {
 			logrus.Debugf("Programming external connectivity on endpoint %s (%s)", ep.Name(), ep.ID())
 			if err = d.ProgramExternalConnectivity(n.ID(), ep.ID(), sb.Labels()); err != nil {
-				return types.InternalErrorf(
+				return types.InternalErrorWrapf(err,
 					"driver failed programming external connectivity on endpoint %s (%s): %v",
 					ep.Name(), ep.ID(), err)
 			}
@@ -1013,6 +1086,15 @@ func CreateOptionLoadBalancer() EndpointOption {
 	}
 }
 
+// CreateOptionHealthGated function returns an option setter for holding back
+// the service records of the endpoint while its container is not healthy.
+func CreateOptionHealthGated(healthy bool) EndpointOption {
+	return func(ep *Endpoint) {
+		ep.healthGated = true
+		ep.healthy = healthy
+	}
+}
+
 // JoinOptionPriority function returns an option setter for priority option to
 // be passed to the endpoint.Join() method.
 func JoinOptionPriority(prio int) EndpointOption {
@@ -14,6 +14,16 @@ func (nsn ErrNoSuchNetwork) Error() string {
 // NotFound denotes the type of this error
 func (nsn ErrNoSuchNetwork) NotFound() {}
 
+// ErrorCode returns the machine-readable code of this error
+func (nsn ErrNoSuchNetwork) ErrorCode() string {
+	return "network_not_found"
+}
+
+// ErrorDetails returns the name or ID of the network that was not found
+func (nsn ErrNoSuchNetwork) ErrorDetails() map[string]string {
+	return map[string]string{"network": string(nsn)}
+}
+
 // ErrNoSuchEndpoint is returned when an endpoint query finds no result
 type ErrNoSuchEndpoint string
 
@@ -10,6 +10,8 @@ import (
 	"github.com/docker/docker/libnetwork/datastore"
 	"github.com/docker/docker/libnetwork/discoverapi"
 	"github.com/docker/docker/libnetwork/ipamapi"
+	"github.com/docker/docker/libnetwork/ipamutils"
+	"github.com/docker/docker/libnetwork/netutils"
 	"github.com/docker/docker/libnetwork/types"
 	"github.com/sirupsen/logrus"
 )
@@ -33,12 +35,16 @@ type Allocator struct {
 	// stores        []datastore.Datastore
 	// Allocated addresses in each address space's subnet
 	addresses map[SubnetKey]*bitseq.Handle
+	// routeOverlap is how the predefined pools of the local address space
+	// which overlap the routes of the host are handled.
+	routeOverlap ipamutils.RouteOverlapMode
+	hostRoutes   func(v6 bool) ([]*net.IPNet, error)
 	sync.Mutex
 }
 
 // NewAllocator returns an instance of libnetwork ipam
 func NewAllocator(lcDs, glDs datastore.DataStore, lcAs, glAs []*net.IPNet) (*Allocator, error) {
-	a := &Allocator{}
+	a := &Allocator{hostRoutes: getHostRoutes}
 
 	// Load predefined subnet pools
 
@@ -381,6 +387,62 @@ func (a *Allocator) retrieveBitmask(k SubnetKey, n *net.IPNet) (*bitseq.Handle,
 	return bm, nil
 }
 
+// SetLocalDefaultPools replaces the predefined pools of the local default
+// address space, from which subnets are assigned to new networks. Networks
+// using subnets of the previous pools are not affected.
+func (a *Allocator) SetLocalDefaultPools(pools []*net.IPNet) {
+	a.Lock()
+	a.predefined[localAddressSpace] = pools
+	a.predefinedStartIndices[localAddressSpace] = 0
+	a.Unlock()
+}
+
+// SetRouteOverlapMode sets how the predefined pools of the local address
+// space which overlap the routes of the host are handled.
+func (a *Allocator) SetRouteOverlapMode(mode ipamutils.RouteOverlapMode) {
+	a.Lock()
+	a.routeOverlap = mode
+	a.Unlock()
+}
+
+// routeOverlapChecker returns a function which returns the route of the host
+// overlapping a pool, or nil if the routes are not consulted for the address
+// space.
+func (a *Allocator) routeOverlapChecker(as string, ipV6 bool) func(*net.IPNet) (*net.IPNet, error) {
+	a.Lock()
+	mode := a.routeOverlap
+	a.Unlock()
+	if as != localAddressSpace || mode == ipamutils.RouteOverlapIgnore {
+		return nil
+	}
+
+	var (
+		routes []*net.IPNet
+		loaded bool
+	)
+	return func(nw *net.IPNet) (*net.IPNet, error) {
+		if !loaded {
+			var err error
+			if routes, err = a.hostRoutes(ipV6); err != nil {
+				if mode == ipamutils.RouteOverlapFail {
+					return nil, types.InternalErrorf("failed to list the routes of the host: %v", err)
+				}
+				logrus.WithError(err).Warn("Failed to list the routes of the host, assigning default address pools without checking them")
+			}
+			loaded = true
+		}
+		for _, r := range routes {
+			if netutils.NetworkOverlaps(nw, r) {
+				if mode == ipamutils.RouteOverlapFail {
+					return nil, types.ForbiddenErrorf("default address pool %s overlaps with the route to %s of the host", nw, r)
+				}
+				return r, nil
+			}
+		}
+		return nil, nil
+	}
+}
+
 func (a *Allocator) getPredefineds(as string) []*net.IPNet {
 	a.Lock()
 	defer a.Unlock()
@@ -421,6 +483,7 @@ func (a *Allocator) getPredefinedPool(as string, ipV6 bool) (*net.IPNet, error)
 	}
 
 	predefined := a.getPredefineds(as)
+	routeOverlap := a.routeOverlapChecker(as, ipV6)
 
 	aSpace.Lock()
 	for i, nw := range predefined {
@@ -433,11 +496,23 @@ func (a *Allocator) getPredefinedPool(as string, ipV6 bool) (*net.IPNet, error)
 		}
 		// Shouldn't be necessary, but check prevents IP collisions should
 		// predefined pools overlap for any reason.
-		if !aSpace.contains(as, nw) {
-			aSpace.Unlock()
-			a.updateStartIndex(as, i+1)
-			return nw, nil
+		if aSpace.contains(as, nw) {
+			continue
 		}
+		if routeOverlap != nil {
+			r, err := routeOverlap(nw)
+			if err != nil {
+				aSpace.Unlock()
+				return nil, err
+			}
+			if r != nil {
+				logrus.Debugf("Skipping default address pool %s overlapping with the route to %s of the host", nw, r)
+				continue
+			}
+		}
+		aSpace.Unlock()
+		a.updateStartIndex(as, i+1)
+		return nw, nil
 	}
 	aSpace.Unlock()
 
@@ -598,6 +673,86 @@ func (a *Allocator) getAddress(nw *net.IPNet, bitmask *bitseq.Handle, prefAddres
 	}
 }
 
+// AllocatedPools returns the IDs of the pools allocated in the address space.
+// The parent pools, which are allocated implicitly for the pools of sub-ranges,
+// are not included.
+func (a *Allocator) AllocatedPools(addressSpace string) ([]string, error) {
+	if err := a.refresh(addressSpace); err != nil {
+		return nil, err
+	}
+	aSpace, err := a.getAddrSpace(addressSpace)
+	if err != nil {
+		return nil, err
+	}
+
+	aSpace.Lock()
+	parents := make(map[SubnetKey]bool)
+	for _, p := range aSpace.subnets {
+		if p.Range != nil {
+			parents[p.ParentKey] = true
+		}
+	}
+	pools := make([]string, 0, len(aSpace.subnets))
+	for k := range aSpace.subnets {
+		if !parents[k] {
+			pools = append(pools, k.String())
+		}
+	}
+	aSpace.Unlock()
+
+	sort.Strings(pools)
+	return pools, nil
+}
+
+// AllocatedAddresses returns the addresses allocated in the address pool of
+// the pool. For the pool of a sub-range, these are the addresses allocated in
+// its parent pool. The network and broadcast addresses reserved by the
+// allocator are not included.
+func (a *Allocator) AllocatedAddresses(poolID string) ([]net.IP, error) {
+	k := SubnetKey{}
+	if err := k.FromString(poolID); err != nil {
+		return nil, types.BadRequestErrorf("invalid pool id: %s", poolID)
+	}
+	if err := a.refresh(k.AddressSpace); err != nil {
+		return nil, err
+	}
+	aSpace, err := a.getAddrSpace(k.AddressSpace)
+	if err != nil {
+		return nil, err
+	}
+
+	aSpace.Lock()
+	p, ok := aSpace.subnets[k]
+	if !ok {
+		aSpace.Unlock()
+		return nil, types.NotFoundErrorf("cannot find address pool for poolID:%s", poolID)
+	}
+	for p.Range != nil {
+		k = p.ParentKey
+		p = aSpace.subnets[k]
+	}
+	aSpace.Unlock()
+
+	bm, err := a.retrieveBitmask(k, p.Pool)
+	if err != nil {
+		return nil, err
+	}
+	numAddresses := bm.Bits()
+	ipVer := getAddressVersion(p.Pool.IP)
+	var addresses []net.IP
+	for _, ordinal := range bm.SetBits() {
+		// Skip the addresses reserved by insertBitMask.
+		if ordinal == 0 && !(ipVer == v4 && numAddresses <= 2) {
+			continue
+		}
+		if ordinal == numAddresses-1 && ipVer == v4 && numAddresses > 2 {
+			continue
+		}
+		addresses = append(addresses, generateAddress(ordinal, p.Pool))
+	}
+	return addresses, nil
+}
+
 // DumpDatabase dumps the internal info
 func (a *Allocator) DumpDatabase() string {
 	a.Lock()
@@ -463,6 +463,31 @@ func TestPredefinedPool(t *testing.T) {
 	}
 }
 
+func TestPredefinedPoolRouteOverlap(t *testing.T) {
+	_, vpn, _ := net.ParseCIDR("172.16.0.0/12")
+	pools := []*net.IPNet{
+		{IP: net.IP{172, 17, 0, 0}, Mask: net.CIDRMask(16, 32)},
+		{IP: net.IP{192, 168, 0, 0}, Mask: net.CIDRMask(20, 32)},
+	}
+	a, err := NewAllocator(nil, nil, pools, ipamutils.GetGlobalScopeDefaultNetworks())
+	assert.NilError(t, err)
+	a.hostRoutes = func(bool) ([]*net.IPNet, error) { return []*net.IPNet{vpn}, nil }
+
+	nw, err := a.getPredefinedPool(localAddressSpace, false)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(nw.String(), "192.168.0.0/20"))
+
+	a.SetRouteOverlapMode(ipamutils.RouteOverlapFail)
+	a.SetLocalDefaultPools(pools)
+	_, err = a.getPredefinedPool(localAddressSpace, false)
+	assert.Check(t, is.ErrorContains(err, "default address pool 172.17.0.0/16 overlaps with the route to 172.16.0.0/12 of the host"))
+
+	a.SetRouteOverlapMode(ipamutils.RouteOverlapIgnore)
+	nw, err = a.getPredefinedPool(localAddressSpace, false)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(nw.String(), "172.17.0.0/16"))
+}
+
 func TestRemoveSubnet(t *testing.T) {
 	for _, store := range []bool{false, true} {
 		a, err := getAllocator(store)
@@ -1615,3 +1640,48 @@ func TestParallelPredefinedRequest4(t *testing.T) {
 func TestParallelPredefinedRequest5(t *testing.T) {
 	runParallelTests(t, 4)
 }
+
+func TestAllocatedPoolsAndAddresses(t *testing.T) {
+	a, err := getAllocator(false)
+	assert.NilError(t, err)
+
+	pid1, _, _, err := a.RequestPool(localAddressSpace, "172.28.0.0/16", "172.28.1.0/24", nil, false)
+	assert.NilError(t, err)
+	pid2, _, _, err := a.RequestPool(localAddressSpace, "172.28.0.0/16", "172.28.2.0/24", nil, false)
+	assert.NilError(t, err)
+	pid3, _, _, err := a.RequestPool(localAddressSpace, "172.29.0.0/24", "", nil, false)
+	assert.NilError(t, err)
+	pid6, _, _, err := a.RequestPool(localAddressSpace, "fd00::/64", "", nil, true)
+	assert.NilError(t, err)
+
+	pools, err := a.AllocatedPools(localAddressSpace)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(pools, []string{pid1, pid2, pid3, pid6}))
+
+	for _, r := range []struct {
+		poolID string
+		ip     string
+	}{
+		{pid1, "172.28.0.1"},
+		{pid1, "172.28.1.10"},
+		{pid2, "172.28.2.10"},
+		{pid3, "172.29.0.1"},
+		{pid6, "fd00::1"},
+	} {
+		_, _, err := a.RequestAddress(r.poolID, net.ParseIP(r.ip), nil)
+		assert.NilError(t, err)
+	}
+
+	addrs, err := a.AllocatedAddresses(pid1)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(fmt.Sprint(addrs), "[172.28.0.1 172.28.1.10 172.28.2.10]"))
+	addrs, err = a.AllocatedAddresses(pid3)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(fmt.Sprint(addrs), "[172.29.0.1]"))
+	addrs, err = a.AllocatedAddresses(pid6)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(fmt.Sprint(addrs), "[fd00::1]"))
+
+	_, err = a.AllocatedAddresses("LocalDefault/10.0.0.0/8")
+	assert.Check(t, is.ErrorType(err, (*types.NotFoundError)(nil)))
+}
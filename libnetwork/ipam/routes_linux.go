@@ -0,0 +1,32 @@
+package ipam
+
+import (
+	"net"
+
+	"github.com/docker/docker/libnetwork/ns"
+	"github.com/vishvananda/netlink"
+)
+
+// getHostRoutes returns the destinations of the routes of the host, other
+// than the default routes.
+func getHostRoutes(v6 bool) ([]*net.IPNet, error) {
+	family := netlink.FAMILY_V4
+	if v6 {
+		family = netlink.FAMILY_V6
+	}
+	routes, err := ns.NlHandle().RouteList(nil, family)
+	if err != nil {
+		return nil, err
+	}
+	var dsts []*net.IPNet
+	for _, r := range routes {
+		if r.Dst == nil {
+			continue
+		}
+		if ones, _ := r.Dst.Mask.Size(); ones == 0 {
+			continue
+		}
+		dsts = append(dsts, r.Dst)
+	}
+	return dsts, nil
+}
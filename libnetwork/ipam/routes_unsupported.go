@@ -0,0 +1,12 @@
+//go:build !linux
+// +build !linux
+
+package ipam
+
+import "net"
+
+// getHostRoutes returns no routes: the routes of the host are only
+// consulted on Linux.
+func getHostRoutes(v6 bool) ([]*net.IPNet, error) {
+	return nil, nil
+}
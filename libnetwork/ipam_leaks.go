@@ -0,0 +1,214 @@
+package libnetwork
+
+import (
+	"net"
+	"sort"
+	"time"
+
+	"github.com/docker/docker/libnetwork/ipamapi"
+	"github.com/sirupsen/logrus"
+)
+
+// ipamLeakMinAge is how long an allocation must have been reported as leaked
+// before it can be released.
+var ipamLeakMinAge = time.Minute
+
+// IPAMLeak is an allocation of an IPAM driver which is owned by no network or
+// endpoint.
+type IPAMLeak struct {
+	Driver string
+	PoolID string
+	// Address is the leaked address, or nil if the pool is leaked.
+	Address net.IP
+	// Released is set if the allocation was released.
+	Released bool
+}
+
+func (l *IPAMLeak) key() string {
+	k := l.Driver + "/" + l.PoolID
+	if l.Address != nil {
+		k += "/" + l.Address.String()
+	}
+	return k
+}
+
+// ipamOwners are the allocations of an IPAM driver owned by networks and
+// endpoints.
+type ipamOwners struct {
+	pools map[string]bool
+	// addrPools maps the ID of the pools to their address pool, which is
+	// shared by the pools of different sub-ranges of the same pool.
+	addrPools map[string]string
+	// addresses are the addresses owned in each address pool.
+	addresses map[string]map[string]bool
+}
+
+func newIPAMOwners() *ipamOwners {
+	return &ipamOwners{
+		pools:     make(map[string]bool),
+		addrPools: make(map[string]string),
+		addresses: make(map[string]map[string]bool),
+	}
+}
+
+func (o *ipamOwners) addAddress(poolID string, addr *net.IPNet) {
+	if addr == nil {
+		return
+	}
+	if ap, ok := o.addrPools[poolID]; ok {
+		o.addresses[ap][addr.IP.String()] = true
+	}
+}
+
+func (o *ipamOwners) addNetwork(n *network) {
+	for _, d := range append(n.getIPInfo(4), n.getIPInfo(6)...) {
+		if d.PoolID == "" || d.Pool == nil {
+			continue
+		}
+		o.pools[d.PoolID] = true
+		ap := d.AddressSpace + "/" + d.Pool.String()
+		o.addrPools[d.PoolID] = ap
+		if o.addresses[ap] == nil {
+			o.addresses[ap] = make(map[string]bool)
+		}
+		o.addAddress(d.PoolID, d.Gateway)
+		for _, aux := range d.AuxAddresses {
+			o.addAddress(d.PoolID, aux)
+		}
+	}
+}
+
+func (o *ipamOwners) addEndpoint(ep *Endpoint) {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+	if ep.iface == nil {
+		return
+	}
+	o.addAddress(ep.iface.v4PoolID, ep.iface.addr)
+	o.addAddress(ep.iface.v6PoolID, ep.iface.addrv6)
+}
+
+// leaks returns the allocations of the driver in its local address space which
+// are not owned.
+func (o *ipamOwners) leaks(name string, ipam ipamapi.Ipam, lister ipamapi.AllocationLister) ([]IPAMLeak, error) {
+	localAS, _, err := ipam.GetDefaultAddressSpaces()
+	if err != nil {
+		return nil, err
+	}
+	pools, err := lister.AllocatedPools(localAS)
+	if err != nil {
+		return nil, err
+	}
+
+	var leaks []IPAMLeak
+	checked := make(map[string]bool)
+	for _, poolID := range pools {
+		if !o.pools[poolID] {
+			leaks = append(leaks, IPAMLeak{Driver: name, PoolID: poolID})
+			continue
+		}
+		ap := o.addrPools[poolID]
+		if checked[ap] {
+			continue
+		}
+		checked[ap] = true
+		addrs, err := lister.AllocatedAddresses(poolID)
+		if err != nil {
+			return nil, err
+		}
+		for _, addr := range addrs {
+			if !o.addresses[ap][addr.String()] {
+				leaks = append(leaks, IPAMLeak{Driver: name, PoolID: poolID, Address: addr})
+			}
+		}
+	}
+	return leaks, nil
+}
+
+// ReconcileIPAM cross-references the pools and addresses allocated by the IPAM
+// drivers in their local address space with the networks and endpoints in the
+// store, and returns the allocations which none of them owns, such as the
+// allocations left behind by a crash of the daemon. Only the drivers which
+// implement ipamapi.AllocationLister, such as the built-in driver, are checked.
+//
+// If release is set, the leaked allocations which were already reported by
+// the previous reconciliation, at least ipamLeakMinAge before, are released.
+// Allocations are not released the first time they are reported, nor by
+// reconciliations in quick succession, as they may belong to a network or
+// endpoint being created.
+func (c *Controller) ReconcileIPAM(release bool) ([]IPAMLeak, error) {
+	owners := map[string]*ipamOwners{ipamapi.DefaultIPAM: newIPAMOwners()}
+	for _, n := range c.getNetworksFromStore() {
+		if n.hasSpecialDriver() || n.ConfigOnly() {
+			continue
+		}
+		o, ok := owners[n.ipamType]
+		if !ok {
+			o = newIPAMOwners()
+			owners[n.ipamType] = o
+		}
+		o.addNetwork(n)
+		for _, ep := range n.Endpoints() {
+			o.addEndpoint(ep)
+		}
+	}
+
+	names := make([]string, 0, len(owners))
+	for name := range owners {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var leaks []IPAMLeak
+	drivers := make(map[string]ipamapi.Ipam)
+	for _, name := range names {
+		// Plugins which are not loaded yet have no allocations to check.
+		ipam, _ := c.drvRegistry.IPAM(name)
+		lister, ok := ipam.(ipamapi.AllocationLister)
+		if !ok {
+			continue
+		}
+		l, err := owners[name].leaks(name, ipam, lister)
+		if err != nil {
+			return nil, err
+		}
+		drivers[name] = ipam
+		leaks = append(leaks, l...)
+	}
+
+	now := time.Now()
+	c.mu.Lock()
+	previous := c.ipamLeaks
+	c.ipamLeaks = make(map[string]time.Time, len(leaks))
+	for i := range leaks {
+		k := leaks[i].key()
+		if first, ok := previous[k]; ok {
+			c.ipamLeaks[k] = first
+		} else {
+			c.ipamLeaks[k] = now
+		}
+	}
+	c.mu.Unlock()
+
+	if !release {
+		return leaks, nil
+	}
+	for i := range leaks {
+		l := &leaks[i]
+		if first, ok := previous[l.key()]; !ok || now.Sub(first) < ipamLeakMinAge {
+			continue
+		}
+		var err error
+		if l.Address != nil {
+			err = drivers[l.Driver].ReleaseAddress(l.PoolID, l.Address)
+		} else {
+			err = drivers[l.Driver].ReleasePool(l.PoolID)
+		}
+		if err != nil {
+			logrus.WithError(err).Warnf("Failed to release leaked IPAM allocation %s", l.key())
+			continue
+		}
+		l.Released = true
+	}
+	return leaks, nil
+}
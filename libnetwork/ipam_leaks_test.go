@@ -0,0 +1,72 @@
+package libnetwork
+
+import (
+	"testing"
+	"time"
+
+	"github.com/docker/docker/libnetwork/ipamapi"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestReconcileIPAM(t *testing.T) {
+	c, nws := getTestEnv(t, []NetworkOption{})
+	n := nws[0].(*network)
+
+	ep, err := n.CreateEndpoint("ep0")
+	assert.NilError(t, err)
+	defer ep.Delete(false) //nolint:errcheck
+
+	leaks, err := c.ReconcileIPAM(true)
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(leaks, 0))
+
+	// Leak an address of the network, and a pool.
+	ipam, _, err := c.getIPAMDriver(ipamapi.DefaultIPAM)
+	assert.NilError(t, err)
+	poolID := n.getIPInfo(4)[0].PoolID
+	addr, _, err := ipam.RequestAddress(poolID, nil, nil)
+	assert.NilError(t, err)
+	leakedPoolID, _, _, err := ipam.RequestPool(n.addrSpace, "10.222.0.0/24", "", nil, false)
+	assert.NilError(t, err)
+
+	expected := []IPAMLeak{
+		{Driver: ipamapi.DefaultIPAM, PoolID: leakedPoolID},
+		{Driver: ipamapi.DefaultIPAM, PoolID: poolID, Address: addr.IP},
+	}
+	if poolID < leakedPoolID {
+		expected[0], expected[1] = expected[1], expected[0]
+	}
+
+	// The leaks are only released once they were reported, at least
+	// ipamLeakMinAge before.
+	leaks, err = c.ReconcileIPAM(true)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(leaks, expected))
+	leaks, err = c.ReconcileIPAM(true)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(leaks, expected))
+
+	defer func(age time.Duration) { ipamLeakMinAge = age }(ipamLeakMinAge)
+	ipamLeakMinAge = 0
+
+	expected[0].Released = true
+	expected[1].Released = true
+	leaks, err = c.ReconcileIPAM(true)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(leaks, expected))
+
+	leaks, err = c.ReconcileIPAM(false)
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(leaks, 0))
+
+	// The released address can be allocated again.
+	_, _, err = ipam.RequestAddress(poolID, addr.IP, nil)
+	assert.NilError(t, err)
+	err = ipam.ReleaseAddress(poolID, addr.IP)
+	assert.NilError(t, err)
+	_, _, _, err = ipam.RequestPool(n.addrSpace, "10.222.0.0/24", "", nil, false)
+	assert.NilError(t, err)
+	assert.NilError(t, ipam.ReleasePool(leakedPoolID))
+
+}
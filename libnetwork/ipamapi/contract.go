@@ -74,6 +74,20 @@ type Ipam interface {
 	IsBuiltIn() bool
 }
 
+// AllocationLister is implemented by the IPAM drivers which can list their
+// allocations, to find the allocations leaked by networks and endpoints which
+// no longer exist.
+type AllocationLister interface {
+	// AllocatedPools returns the IDs of the pools allocated in the address
+	// space.
+	AllocatedPools(addressSpace string) ([]string, error)
+	// AllocatedAddresses returns the addresses allocated in the address pool
+	// of the pool, including the addresses allocated through the other pools
+	// sharing the same address pool, but not the addresses reserved by the
+	// driver.
+	AllocatedAddresses(poolID string) ([]net.IP, error)
+}
+
 // Capability represents the requirements and capabilities of the IPAM driver
 type Capability struct {
 	// Whether on address request, libnetwork must
@@ -0,0 +1,246 @@
+package remote
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/libnetwork/discoverapi"
+	"github.com/docker/docker/libnetwork/ipamapi"
+	"github.com/docker/docker/libnetwork/ipams/remote/grpcapi"
+	"github.com/docker/docker/libnetwork/types"
+	"github.com/docker/docker/pkg/plugingetter"
+	"github.com/docker/docker/pkg/plugins"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// defaultGRPCTimeout is the deadline of the calls to plugins which don't set
+// a timeout.
+const defaultGRPCTimeout = 30 * time.Second
+
+// grpcAllocators tracks the allocators of the plugins using the gRPC
+// protocol, to close the connection of an allocator when its plugin is
+// disabled, or enabled again.
+type grpcAllocators struct {
+	cb ipamapi.Callback
+
+	mu         sync.Mutex
+	allocators map[string]*grpcAllocator
+}
+
+func newGRPCAllocators(cb ipamapi.Callback) *grpcAllocators {
+	return &grpcAllocators{cb: cb, allocators: make(map[string]*grpcAllocator)}
+}
+
+// register connects to the managed plugin with the given name, and registers
+// its allocator.
+func (r *grpcAllocators) register(name string) {
+	pg := r.cb.GetPluginGetter()
+	if pg == nil {
+		logrus.Errorf("error registering remote ipam driver %s: no plugin getter", name)
+		return
+	}
+	p, err := pg.Get(name, ipamapi.PluginEndpointType, plugingetter.Lookup)
+	if err != nil {
+		logrus.Errorf("error registering remote ipam driver %s due to %v", name, err)
+		return
+	}
+	pa, ok := p.(plugingetter.PluginAddr)
+	if !ok || pa.Protocol() != plugins.ProtocolSchemeGRPCV1 {
+		logrus.Errorf("error registering remote ipam driver %s: unsupported plugin protocol", name)
+		return
+	}
+
+	a, c, err := newGRPCAllocator(name, pa.Addr(), pa.Timeout())
+	if err != nil {
+		logrus.Errorf("error registering remote ipam driver %s due to %v", name, err)
+		return
+	}
+	if err := r.cb.RegisterIpamDriverWithCapabilities(name, a, c); err != nil {
+		logrus.Errorf("error registering remote ipam driver %s due to %v", name, err)
+		a.conn.Close()
+		return
+	}
+
+	r.mu.Lock()
+	if old := r.allocators[name]; old != nil {
+		old.conn.Close()
+	}
+	r.allocators[name] = a
+	r.mu.Unlock()
+}
+
+// unregister closes the connection of the allocator of the plugin with the
+// given name, which was disabled. The allocator stays registered, and fails
+// the calls until the plugin is enabled again.
+func (r *grpcAllocators) unregister(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if a := r.allocators[name]; a != nil {
+		a.conn.Close()
+		delete(r.allocators, name)
+	}
+}
+
+// grpcAllocator is the allocator of an IPAM plugin using the gRPC protocol.
+type grpcAllocator struct {
+	name    string
+	conn    *grpc.ClientConn
+	client  grpcapi.IpamDriverClient
+	timeout time.Duration
+}
+
+// newGRPCAllocator connects to the plugin listening at addr, and negotiates
+// the protocol version and the capability of the allocator.
+func newGRPCAllocator(name string, addr net.Addr, timeout time.Duration) (*grpcAllocator, *ipamapi.Capability, error) {
+	if timeout <= 0 {
+		timeout = defaultGRPCTimeout
+	}
+	conn, err := grpc.Dial(addr.Network()+"://"+addr.String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "error connecting to plugin")
+	}
+	a := &grpcAllocator{
+		name:    name,
+		conn:    conn,
+		client:  grpcapi.NewIpamDriverClient(conn),
+		timeout: timeout,
+	}
+
+	ctx, cancel := a.callContext()
+	defer cancel()
+	res, err := a.client.Handshake(ctx, &grpcapi.HandshakeRequest{ProtocolVersion: grpcapi.ProtocolVersion}, grpc.WaitForReady(true))
+	if err != nil {
+		conn.Close()
+		return nil, nil, grpcError(err)
+	}
+	if res.ProtocolVersion == 0 || res.ProtocolVersion > grpcapi.ProtocolVersion {
+		conn.Close()
+		return nil, nil, fmt.Errorf("unsupported protocol version %d", res.ProtocolVersion)
+	}
+	return a, &ipamapi.Capability{
+		RequiresMACAddress:    res.RequiresMacAddress,
+		RequiresRequestReplay: res.RequiresRequestReplay,
+	}, nil
+}
+
+// callContext returns the context of a call to the plugin, which sets its
+// deadline.
+func (a *grpcAllocator) callContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), a.timeout)
+}
+
+// grpcError converts the error returned by a call to a plugin.
+func grpcError(err error) error {
+	if err == nil {
+		return nil
+	}
+	s, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	switch s.Code() {
+	case codes.InvalidArgument:
+		return types.BadRequestErrorf("remote: %s", s.Message())
+	case codes.NotFound:
+		return types.NotFoundErrorf("remote: %s", s.Message())
+	case codes.AlreadyExists, codes.PermissionDenied:
+		return types.ForbiddenErrorf("remote: %s", s.Message())
+	case codes.ResourceExhausted:
+		return types.NoServiceErrorf("remote: %s", s.Message())
+	case codes.Unimplemented:
+		return types.NotImplementedErrorf("remote: %s", s.Message())
+	case codes.DeadlineExceeded:
+		return types.TimeoutErrorf("remote: %s", s.Message())
+	}
+	return fmt.Errorf("remote: %s", s.Message())
+}
+
+// GetDefaultAddressSpaces returns the local and global default address spaces
+func (a *grpcAllocator) GetDefaultAddressSpaces() (string, string, error) {
+	ctx, cancel := a.callContext()
+	defer cancel()
+	res, err := a.client.GetDefaultAddressSpaces(ctx, &grpcapi.GetDefaultAddressSpacesRequest{})
+	if err != nil {
+		return "", "", grpcError(err)
+	}
+	return res.LocalDefaultAddressSpace, res.GlobalDefaultAddressSpace, nil
+}
+
+// RequestPool requests an address pool in the specified address space
+func (a *grpcAllocator) RequestPool(addressSpace, pool, subPool string, options map[string]string, v6 bool) (string, *net.IPNet, map[string]string, error) {
+	ctx, cancel := a.callContext()
+	defer cancel()
+	res, err := a.client.RequestPool(ctx, &grpcapi.RequestPoolRequest{
+		AddressSpace: addressSpace,
+		Pool:         pool,
+		SubPool:      subPool,
+		Options:      options,
+		V6:           v6,
+	})
+	if err != nil {
+		return "", nil, nil, grpcError(err)
+	}
+	retPool, err := types.ParseCIDR(res.Pool)
+	return res.PoolId, retPool, res.Data, err
+}
+
+// ReleasePool removes an address pool from the specified address space
+func (a *grpcAllocator) ReleasePool(poolID string) error {
+	ctx, cancel := a.callContext()
+	defer cancel()
+	_, err := a.client.ReleasePool(ctx, &grpcapi.ReleasePoolRequest{PoolId: poolID})
+	return grpcError(err)
+}
+
+// RequestAddress requests an address from the address pool
+func (a *grpcAllocator) RequestAddress(poolID string, address net.IP, options map[string]string) (*net.IPNet, map[string]string, error) {
+	var prefAddress string
+	if address != nil {
+		prefAddress = address.String()
+	}
+	ctx, cancel := a.callContext()
+	defer cancel()
+	res, err := a.client.RequestAddress(ctx, &grpcapi.RequestAddressRequest{PoolId: poolID, Address: prefAddress, Options: options})
+	if err != nil {
+		return nil, nil, grpcError(err)
+	}
+	if res.Address == "" {
+		return nil, nil, ipamapi.ErrNoIPReturned
+	}
+	retAddress, err := types.ParseCIDR(res.Address)
+	return retAddress, res.Data, err
+}
+
+// ReleaseAddress releases the address from the specified address pool
+func (a *grpcAllocator) ReleaseAddress(poolID string, address net.IP) error {
+	var relAddress string
+	if address != nil {
+		relAddress = address.String()
+	}
+	ctx, cancel := a.callContext()
+	defer cancel()
+	_, err := a.client.ReleaseAddress(ctx, &grpcapi.ReleaseAddressRequest{PoolId: poolID, Address: relAddress})
+	return grpcError(err)
+}
+
+// DiscoverNew is a notification for a new discovery event, such as a new global datastore
+func (a *grpcAllocator) DiscoverNew(dType discoverapi.DiscoveryType, data interface{}) error {
+	return nil
+}
+
+// DiscoverDelete is a notification for a discovery delete event, such as a node leaving a cluster
+func (a *grpcAllocator) DiscoverDelete(dType discoverapi.DiscoveryType, data interface{}) error {
+	return nil
+}
+
+func (a *grpcAllocator) IsBuiltIn() bool {
+	return false
+}
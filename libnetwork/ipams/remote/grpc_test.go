@@ -0,0 +1,81 @@
+package remote
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/libnetwork/ipamapi"
+	"github.com/docker/docker/libnetwork/ipams/remote/grpcapi"
+	"github.com/docker/docker/libnetwork/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+type testGRPCPlugin struct {
+	grpcapi.UnimplementedIpamDriverServer
+}
+
+func (p *testGRPCPlugin) Handshake(_ context.Context, req *grpcapi.HandshakeRequest) (*grpcapi.HandshakeResponse, error) {
+	return &grpcapi.HandshakeResponse{ProtocolVersion: req.ProtocolVersion, RequiresMacAddress: true}, nil
+}
+
+func (p *testGRPCPlugin) GetDefaultAddressSpaces(context.Context, *grpcapi.GetDefaultAddressSpacesRequest) (*grpcapi.GetDefaultAddressSpacesResponse, error) {
+	return &grpcapi.GetDefaultAddressSpacesResponse{LocalDefaultAddressSpace: "white", GlobalDefaultAddressSpace: "blue"}, nil
+}
+
+func (p *testGRPCPlugin) RequestPool(_ context.Context, req *grpcapi.RequestPoolRequest) (*grpcapi.RequestPoolResponse, error) {
+	return &grpcapi.RequestPoolResponse{PoolId: req.AddressSpace + "/" + req.Pool, Pool: req.Pool}, nil
+}
+
+func (p *testGRPCPlugin) RequestAddress(_ context.Context, req *grpcapi.RequestAddressRequest) (*grpcapi.RequestAddressResponse, error) {
+	if req.Address == "" {
+		return nil, status.Error(codes.ResourceExhausted, "no available addresses")
+	}
+	return &grpcapi.RequestAddressResponse{Address: req.Address + "/16"}, nil
+}
+
+func TestGRPCAllocator(t *testing.T) {
+	dir, err := os.MkdirTemp("", "grpc-ipam")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	l, err := net.Listen("unix", filepath.Join(dir, "plugin.sock"))
+	assert.NilError(t, err)
+	s := grpc.NewServer()
+	grpcapi.RegisterIpamDriverServer(s, &testGRPCPlugin{})
+	go s.Serve(l)
+	defer s.Stop()
+
+	a, c, err := newGRPCAllocator("test-grpc", l.Addr(), time.Second)
+	assert.NilError(t, err)
+	defer a.conn.Close()
+	assert.Check(t, is.DeepEqual(c, &ipamapi.Capability{RequiresMACAddress: true}))
+
+	local, global, err := a.GetDefaultAddressSpaces()
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(local, "white"))
+	assert.Check(t, is.Equal(global, "blue"))
+
+	poolID, pool, _, err := a.RequestPool("white", "172.20.0.0/16", "", nil, false)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(poolID, "white/172.20.0.0/16"))
+	assert.Check(t, is.Equal(pool.String(), "172.20.0.0/16"))
+
+	addr, _, err := a.RequestAddress(poolID, net.ParseIP("172.20.0.5"), nil)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(addr.String(), "172.20.0.5/16"))
+
+	_, _, err = a.RequestAddress(poolID, nil, nil)
+	assert.Check(t, is.ErrorContains(err, "no available addresses"))
+	assert.Check(t, is.ErrorType(err, (*types.NoServiceError)(nil)))
+
+	err = a.ReleasePool(poolID)
+	assert.Check(t, is.ErrorType(err, (*types.NotImplementedError)(nil)))
+}
@@ -0,0 +1,9 @@
+//go:generate protoc -I . --gogofast_out=plugins=grpc,import_path=github.com/docker/docker/libnetwork/ipams/remote/grpcapi:. ipam.proto
+
+// Package grpcapi holds the gRPC protocol of the IPAM driver plugins
+// declaring the "moby.plugins.grpc/v1" protocol scheme.
+package grpcapi // import "github.com/docker/docker/libnetwork/ipams/remote/grpcapi"
+
+// ProtocolVersion is the latest version of the protocol supported by the
+// daemon.
+const ProtocolVersion = 1
@@ -32,8 +32,13 @@ func newAllocator(name string, client *plugins.Client) ipamapi.Ipam {
 
 // Init registers a remote ipam when its plugin is activated
 func Init(cb ipamapi.Callback, l, g interface{}) error {
-
+	grpcAllocators := newGRPCAllocators(cb)
 	newPluginHandler := func(name string, client *plugins.Client) {
+		if client == nil {
+			// Managed plugins only have a client if they use the HTTP protocol.
+			grpcAllocators.register(name)
+			return
+		}
 		a := newAllocator(name, client)
 		if cps, err := a.(*allocator).getCapabilities(); err == nil {
 			if err := cb.RegisterIpamDriverWithCapabilities(name, a, cps); err != nil {
@@ -52,6 +57,9 @@ func Init(cb ipamapi.Callback, l, g interface{}) error {
 	handleFunc := plugins.Handle
 	if pg := cb.GetPluginGetter(); pg != nil {
 		handleFunc = pg.Handle
+		if dh, ok := pg.(plugingetter.DisableHandler); ok {
+			dh.HandleDisable(ipamapi.PluginEndpointType, grpcAllocators.unregister)
+		}
 		activePlugins := pg.GetAllManagedPluginsByCap(ipamapi.PluginEndpointType)
 		for _, ap := range activePlugins {
 			client, err := getPluginClient(ap)
@@ -115,3 +115,29 @@ func addIntToIP(array net.IP, ordinal uint) {
 		ordinal >>= 8
 	}
 }
+
+// RouteOverlapMode is how the subnets of the local scope default address
+// pools which overlap a route of the host are handled when they are assigned
+// to new networks.
+type RouteOverlapMode string
+
+const (
+	// RouteOverlapSkip skips the subnets which overlap a route of the host.
+	// It is the default.
+	RouteOverlapSkip RouteOverlapMode = "skip"
+	// RouteOverlapFail fails the creation of the network instead of
+	// assigning it a subnet which overlaps a route of the host.
+	RouteOverlapFail RouteOverlapMode = "fail"
+	// RouteOverlapIgnore does not consult the routes of the host.
+	RouteOverlapIgnore RouteOverlapMode = "ignore"
+)
+
+// ValidateRouteOverlapMode returns an error if mode is not a valid route
+// overlap mode. The empty mode is the default.
+func ValidateRouteOverlapMode(mode RouteOverlapMode) error {
+	switch mode {
+	case "", RouteOverlapSkip, RouteOverlapFail, RouteOverlapIgnore:
+		return nil
+	}
+	return fmt.Errorf("invalid route overlap mode %q: must be %q, %q or %q", mode, RouteOverlapSkip, RouteOverlapFail, RouteOverlapIgnore)
+}
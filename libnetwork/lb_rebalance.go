@@ -0,0 +1,187 @@
+package libnetwork
+
+import (
+	"bytes"
+	"net"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// LBRebalance is the result of the rebalance of the load balancer of a
+// service on a network.
+type LBRebalance struct {
+	NetworkID   string
+	ServiceID   string
+	ServiceName string
+	VIP         net.IP
+	// Added are the backends which were missing from the dataplane.
+	Added []net.IP
+	// Removed are the real servers of the dataplane which are no longer
+	// backends of the service.
+	Removed []net.IP
+	// Reweighted are the backends whose weight was reset.
+	Reweighted []net.IP
+	// Drained are the backends which receive no new connections until the
+	// drain period is over, as they hold significantly more active
+	// connections than the other backends.
+	Drained []net.IP
+}
+
+// lbDestination is the state of a real server of a load balancer in the
+// dataplane.
+type lbDestination struct {
+	ip     net.IP
+	weight int
+	active int
+}
+
+type lbRebalancePlan struct {
+	add      []net.IP
+	remove   []net.IP
+	reweight []net.IP
+	drain    []net.IP
+	// weights are the weights the backends should have, keyed by IP.
+	weights map[string]int
+}
+
+// planLBRebalance compares the backends of a load balancer with its real
+// servers in the dataplane. If drain is set, the backends which hold more
+// than one and a half times the mean number of active connections of the
+// enabled backends are drained.
+func planLBRebalance(backEnds map[string]*lbBackend, dests []lbDestination, drain bool) lbRebalancePlan {
+	p := lbRebalancePlan{weights: make(map[string]int)}
+	ips := make(map[string]net.IP)
+	for _, be := range backEnds {
+		k := be.ip.String()
+		ips[k] = be.ip
+		// An IP may transiently be shared by several endpoints, in which
+		// case it stays enabled as long as one of them is.
+		if !be.disabled {
+			p.weights[k] = 1
+		} else if _, ok := p.weights[k]; !ok {
+			p.weights[k] = 0
+		}
+	}
+
+	present := make(map[string]bool)
+	active := make(map[string]int)
+	for _, d := range dests {
+		k := d.ip.String()
+		present[k] = true
+		w, ok := p.weights[k]
+		if !ok {
+			p.remove = append(p.remove, d.ip)
+			continue
+		}
+		active[k] = d.active
+		if d.weight != w {
+			p.reweight = append(p.reweight, d.ip)
+		}
+	}
+	for k, ip := range ips {
+		if !present[k] {
+			p.add = append(p.add, ip)
+		}
+	}
+
+	if drain {
+		var enabled, total int
+		for k, w := range p.weights {
+			if w > 0 {
+				enabled++
+				total += active[k]
+			}
+		}
+		for k, w := range p.weights {
+			if w > 0 && total > 0 && 2*active[k]*enabled > 3*total {
+				p.drain = append(p.drain, ips[k])
+			}
+		}
+	}
+
+	for _, l := range [][]net.IP{p.add, p.remove, p.reweight, p.drain} {
+		sortIPs(l)
+	}
+	return p
+}
+
+func sortIPs(ips []net.IP) {
+	sort.Slice(ips, func(i, j int) bool { return bytes.Compare(ips[i].To16(), ips[j].To16()) < 0 })
+}
+
+// RebalanceLoadBalancers re-synchronizes the real servers of the load
+// balancers of the services on the network, or on all the networks if nid is
+// empty, with the backends of the services, and resets their weights. If
+// drain is set, the backends which hold significantly more active connections
+// than the others receive no new connections for that long, while their
+// existing connections are left untouched.
+func (c *Controller) RebalanceLoadBalancers(nid string, drain time.Duration) ([]LBRebalance, error) {
+	c.mu.Lock()
+	services := make([]*service, 0, len(c.serviceBindings))
+	for _, s := range c.serviceBindings {
+		services = append(services, s)
+	}
+	c.mu.Unlock()
+
+	results := []LBRebalance{}
+	for _, s := range services {
+		s.Lock()
+		if s.deleted {
+			s.Unlock()
+			continue
+		}
+		for lbNID, lb := range s.loadBalancers {
+			if nid != "" && lbNID != nid {
+				continue
+			}
+			n, err := c.NetworkByID(lbNID)
+			if err != nil {
+				continue
+			}
+			res, err := n.(*network).rebalanceLB(lb, drain)
+			if err != nil {
+				s.Unlock()
+				return nil, err
+			}
+			if len(res.Drained) > 0 {
+				c.scheduleLBRestore(s, lbNID, lb, res.Drained, drain)
+			}
+			res.NetworkID = lbNID
+			res.ServiceID = s.id
+			res.ServiceName = s.name
+			res.VIP = lb.vip
+			results = append(results, res)
+		}
+		s.Unlock()
+	}
+	return results, nil
+}
+
+// scheduleLBRestore restores the weight of the drained backends of a load
+// balancer once the drain period is over, unless they were disabled or
+// removed meanwhile.
+func (c *Controller) scheduleLBRestore(s *service, nid string, lb *loadBalancer, drained []net.IP, drain time.Duration) {
+	time.AfterFunc(drain, func() {
+		s.Lock()
+		defer s.Unlock()
+		if s.deleted || s.loadBalancers[nid] != lb {
+			return
+		}
+		n, err := c.NetworkByID(nid)
+		if err != nil {
+			return
+		}
+		for _, ip := range drained {
+			for _, be := range lb.backEnds {
+				if be.ip.Equal(ip) && !be.disabled {
+					if err := n.(*network).setLBBackendWeight(ip, lb, 1); err != nil {
+						logrus.WithError(err).Warnf("Failed to restore the weight of drained backend %s of vip %s", ip, lb.vip)
+					}
+					break
+				}
+			}
+		}
+	})
+}
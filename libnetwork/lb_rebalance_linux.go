@@ -0,0 +1,117 @@
+package libnetwork
+
+import (
+	"fmt"
+	"net"
+	"syscall"
+	"time"
+
+	"github.com/moby/ipvs"
+	"github.com/vishvananda/netlink/nl"
+)
+
+// rebalanceLB re-synchronizes the real servers of the load balancer in the
+// load balancing sandbox of the network with its backends. It must be called
+// with the service of the load balancer locked.
+func (n *network) rebalanceLB(lb *loadBalancer, drain time.Duration) (LBRebalance, error) {
+	var res LBRebalance
+	if len(lb.vip) == 0 {
+		return res, nil
+	}
+	_, sb, err := n.findLBEndpointSandbox()
+	if err != nil {
+		// The load balancing sandbox of the network is not created until
+		// the first task is attached to the network on this node.
+		return res, nil
+	}
+	if sb.osSbox == nil {
+		return res, nil
+	}
+
+	i, err := ipvs.New(sb.Key())
+	if err != nil {
+		return res, fmt.Errorf("failed to create an ipvs handle for sbox %.7s (%.7s,%s) for lb rebalance: %v", sb.ID(), sb.ContainerID(), sb.Key(), err)
+	}
+	defer i.Close()
+
+	s := &ipvs.Service{
+		AddressFamily: nl.FAMILY_V4,
+		FWMark:        lb.fwMark,
+	}
+	if !i.IsServicePresent(s) {
+		return res, nil
+	}
+	ipvsDests, err := i.GetDestinations(s)
+	if err != nil {
+		return res, fmt.Errorf("failed to get real servers for vip %s fwmark %d in sbox %.7s (%.7s): %v", lb.vip, lb.fwMark, sb.ID(), sb.ContainerID(), err)
+	}
+	dests := make([]lbDestination, 0, len(ipvsDests))
+	for _, d := range ipvsDests {
+		dests = append(dests, lbDestination{ip: d.Address, weight: d.Weight, active: d.ActiveConnections})
+	}
+
+	p := planLBRebalance(lb.backEnds, dests, drain > 0)
+	for _, ip := range p.remove {
+		if err := i.DelDestination(s, n.lbDestination(ip, 0)); err != nil && err != syscall.ENOENT {
+			return res, fmt.Errorf("failed to delete real server %s for vip %s fwmark %d: %v", ip, lb.vip, lb.fwMark, err)
+		}
+	}
+	for _, ip := range p.add {
+		if err := i.NewDestination(s, n.lbDestination(ip, p.weights[ip.String()])); err != nil && err != syscall.EEXIST {
+			return res, fmt.Errorf("failed to create real server %s for vip %s fwmark %d: %v", ip, lb.vip, lb.fwMark, err)
+		}
+	}
+	for _, ip := range p.reweight {
+		if err := i.UpdateDestination(s, n.lbDestination(ip, p.weights[ip.String()])); err != nil {
+			return res, fmt.Errorf("failed to set LB weight of real server %s for vip %s fwmark %d: %v", ip, lb.vip, lb.fwMark, err)
+		}
+	}
+	for _, ip := range p.drain {
+		// Existing connections are kept on the real server, only the new
+		// connections are scheduled to the other real servers.
+		if err := i.UpdateDestination(s, n.lbDestination(ip, 0)); err != nil {
+			return res, fmt.Errorf("failed to drain real server %s for vip %s fwmark %d: %v", ip, lb.vip, lb.fwMark, err)
+		}
+	}
+
+	res.Added, res.Removed, res.Reweighted, res.Drained = p.add, p.remove, p.reweight, p.drain
+	return res, nil
+}
+
+// setLBBackendWeight sets the weight of a backend of the load balancer in the
+// load balancing sandbox of the network.
+func (n *network) setLBBackendWeight(ip net.IP, lb *loadBalancer, weight int) error {
+	_, sb, err := n.findLBEndpointSandbox()
+	if err != nil {
+		return err
+	}
+	if sb.osSbox == nil {
+		return nil
+	}
+	i, err := ipvs.New(sb.Key())
+	if err != nil {
+		return err
+	}
+	defer i.Close()
+
+	s := &ipvs.Service{
+		AddressFamily: nl.FAMILY_V4,
+		FWMark:        lb.fwMark,
+	}
+	if err := i.UpdateDestination(s, n.lbDestination(ip, weight)); err != nil && err != syscall.ENOENT {
+		return err
+	}
+	return nil
+}
+
+func (n *network) lbDestination(ip net.IP, weight int) *ipvs.Destination {
+	d := &ipvs.Destination{
+		AddressFamily: nl.FAMILY_V4,
+		Address:       ip,
+		Weight:        weight,
+	}
+	if n.loadBalancerMode == loadBalancerModeDSR {
+		d.ConnectionFlags = ipvs.ConnFwdDirectRoute
+	}
+	return d
+}
@@ -0,0 +1,43 @@
+package libnetwork
+
+import (
+	"net"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestPlanLBRebalance(t *testing.T) {
+	ip := net.ParseIP
+	backEnds := map[string]*lbBackend{
+		"ep1": {ip: ip("10.0.0.1")},
+		"ep2": {ip: ip("10.0.0.2")},
+		"ep3": {ip: ip("10.0.0.3"), disabled: true},
+		"ep4": {ip: ip("10.0.0.4")},
+		// The new endpoint of a shared IP keeps it enabled.
+		"ep5": {ip: ip("10.0.0.2"), disabled: true},
+	}
+	dests := []lbDestination{
+		{ip: ip("10.0.0.1"), weight: 1, active: 90},
+		{ip: ip("10.0.0.2"), weight: 0, active: 10},
+		{ip: ip("10.0.0.3"), weight: 1, active: 5},
+		{ip: ip("10.0.0.9"), weight: 1, active: 1},
+	}
+
+	p := planLBRebalance(backEnds, dests, false)
+	assert.Check(t, is.DeepEqual(p.add, []net.IP{ip("10.0.0.4")}))
+	assert.Check(t, is.DeepEqual(p.remove, []net.IP{ip("10.0.0.9")}))
+	assert.Check(t, is.DeepEqual(p.reweight, []net.IP{ip("10.0.0.2"), ip("10.0.0.3")}))
+	assert.Check(t, is.Len(p.drain, 0))
+	assert.Check(t, is.DeepEqual(p.weights, map[string]int{"10.0.0.1": 1, "10.0.0.2": 1, "10.0.0.3": 0, "10.0.0.4": 1}))
+
+	// 10.0.0.1 holds 90 of the 100 active connections of the three enabled
+	// backends.
+	p = planLBRebalance(backEnds, dests, true)
+	assert.Check(t, is.DeepEqual(p.drain, []net.IP{ip("10.0.0.1")}))
+
+	// Nothing is drained without active connections.
+	p = planLBRebalance(backEnds, []lbDestination{{ip: ip("10.0.0.1"), weight: 1}}, true)
+	assert.Check(t, is.Len(p.drain, 0))
+}
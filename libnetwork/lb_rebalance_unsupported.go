@@ -0,0 +1,19 @@
+//go:build !linux
+// +build !linux
+
+package libnetwork
+
+import (
+	"net"
+	"time"
+
+	"github.com/docker/docker/libnetwork/types"
+)
+
+func (n *network) rebalanceLB(lb *loadBalancer, drain time.Duration) (LBRebalance, error) {
+	return LBRebalance{}, types.NotImplementedErrorf("rebalancing load balancers is not supported on this platform")
+}
+
+func (n *network) setLBBackendWeight(ip net.IP, lb *loadBalancer, weight int) error {
+	return types.NotImplementedErrorf("rebalancing load balancers is not supported on this platform")
+}
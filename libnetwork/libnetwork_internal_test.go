@@ -565,6 +565,83 @@ func TestServiceVIPReuse(t *testing.T) {
 	}
 }
 
+func TestHealthGatedSvcRecords(t *testing.T) {
+	skip.If(t, runtime.GOOS == "windows", "test only works on linux")
+
+	defer testutils.SetupTestOSContext(t)()
+
+	c, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Stop()
+
+	n, err := c.NewNetwork("bridge", "net1", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := n.Delete(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	ep, err := n.CreateEndpoint("testep", CreateOptionHealthGated(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sb, err := c.NewSandbox("c1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := sb.Delete(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	if err := ep.Join(sb); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := ep.Leave(sb); err != nil {
+			t.Fatal(err)
+		}
+		if err := ep.Delete(false); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	// The service records of the endpoint are processed asynchronously.
+	for i := 0; ; i++ {
+		c.mu.Lock()
+		_, ok := c.nmap[n.ID()]
+		c.mu.Unlock()
+		if ok {
+			break
+		}
+		if i == 100 {
+			t.Fatal("service records of the network were not set up")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if ipList, _ := n.(*network).ResolveName("testep", types.IPv4); len(ipList) != 0 {
+		t.Fatalf("Expected no records before the container is healthy, got %v", ipList)
+	}
+
+	sb.SetHealthy(true)
+	if ipList, _ := n.(*network).ResolveName("testep", types.IPv4); len(ipList) != 1 {
+		t.Fatalf("Expected a record once the container is healthy, got %v", ipList)
+	}
+
+	sb.SetHealthy(false)
+	if ipList, _ := n.(*network).ResolveName("testep", types.IPv4); len(ipList) != 0 {
+		t.Fatalf("Expected no records while the container is unhealthy, got %v", ipList)
+	}
+}
+
 func TestIpamReleaseOnNetDriverFailures(t *testing.T) {
 	skip.If(t, runtime.GOOS == "windows", "test only works on linux")
 
@@ -341,6 +341,9 @@ func TestEndpointJoin(t *testing.T) {
 	if _, ok := stats["eth0"]; !ok {
 		t.Fatalf("Did not find eth0 statistics")
 	}
+	if ep := sb.InterfaceEndpoints()["eth0"]; ep == nil || ep.ID() != ep1.ID() || ep.NetworkID() != n1.ID() {
+		t.Fatalf("Did not find the endpoint of eth0")
+	}
 
 	// Now test the container joining another network
 	n2, err := createTestNetwork(controller, bridgeNetType, "testnetwork2",
@@ -57,6 +57,11 @@ type Network interface {
 
 	// Info returns certain operational data belonging to this network.
 	Info() NetworkInfo
+
+	// UpdateLabels replaces the labels of the network with the labels
+	// returned by update, which is passed a copy of the current labels, and
+	// returns the new labels.
+	UpdateLabels(update func(map[string]string) map[string]string) (map[string]string, error)
 }
 
 // NetworkInfo returns some configuration and operational information about the network
@@ -82,6 +87,10 @@ type NetworkInfo interface {
 	// Services returns a map of services keyed by the service name with the details
 	// of all the tasks that belong to the service. Applicable only in swarm mode.
 	Services() map[string]ServiceInfo
+	// DriverState returns the runtime state of the network in its driver,
+	// such as the peers of an overlay network, or nil if the driver does not
+	// report it.
+	DriverState() (map[string]interface{}, error)
 }
 
 // EndpointWalker is a client provided function which will be used to walk the Endpoints.
@@ -109,6 +118,9 @@ type svcInfo struct {
 	svcIPv6Map setmatrix.SetMatrix
 	ipMap      setmatrix.SetMatrix
 	service    map[string][]servicePorts
+	// aliasOptions are the DNS options of the aliases of the endpoints on
+	// this node, by lower-case alias and service ID.
+	aliasOptions map[string]map[string]AliasOptions
 }
 
 // backing container or host's info
@@ -1321,10 +1333,15 @@ func (n *network) EndpointByID(id string) (*Endpoint, error) {
 }
 
 func (n *network) updateSvcRecord(ep *Endpoint, localEps []*Endpoint, isAdd bool) {
+	if isAdd && ep.isDNSGated() {
+		logrus.Debugf("%s (%.7s).updateSvcRecord: holding back the records of %s until it is healthy", ep.ID(), n.ID(), ep.Name())
+		return
+	}
 	var ipv6 net.IP
 	epName := ep.Name()
 	if iface := ep.Iface(); iface != nil && iface.Address() != nil {
 		myAliases := ep.MyAliases()
+		myAliasOptions := ep.MyAliasOptions()
 		if iface.AddressIPv6() != nil {
 			ipv6 = iface.AddressIPv6().IP
 		}
@@ -1346,6 +1363,9 @@ func (n *network) updateSvcRecord(ep *Endpoint, localEps []*Endpoint, isAdd bool
 			}
 			for _, alias := range myAliases {
 				n.addSvcRecords(ep.ID(), alias, serviceID, iface.Address().IP, ipv6, false, "updateSvcRecord")
+				if opts, ok := myAliasOptions[alias]; ok {
+					n.setAliasOptions(alias, serviceID, &opts)
+				}
 			}
 		} else {
 			if ep.isAnonymous() {
@@ -1357,6 +1377,9 @@ func (n *network) updateSvcRecord(ep *Endpoint, localEps []*Endpoint, isAdd bool
 			}
 			for _, alias := range myAliases {
 				n.deleteSvcRecords(ep.ID(), alias, serviceID, iface.Address().IP, ipv6, false, "updateSvcRecord")
+				if _, ok := myAliasOptions[alias]; ok {
+					n.setAliasOptions(alias, serviceID, nil)
+				}
 			}
 		}
 	}
@@ -1799,6 +1822,20 @@ func (n *network) Peers() []networkdb.PeerInfo {
 	return agent.networkDB.Peers(n.ID())
 }
 
+// DriverState returns the runtime state of the network in its driver, or nil
+// if the driver does not report it.
+func (n *network) DriverState() (map[string]interface{}, error) {
+	d, err := n.driver(false)
+	if err != nil {
+		return nil, err
+	}
+	sr, ok := d.(driverapi.StateReporter)
+	if !ok {
+		return nil, nil
+	}
+	return sr.NetworkState(n.ID())
+}
+
 func (n *network) DriverOptions() map[string]string {
 	n.mu.Lock()
 	defer n.mu.Unlock()
@@ -1929,6 +1966,22 @@ func (n *network) Labels() map[string]string {
 	return lbls
 }
 
+func (n *network) UpdateLabels(update func(map[string]string) map[string]string) (map[string]string, error) {
+	labels := update(n.Labels())
+
+	n.mu.Lock()
+	n.labels = labels
+	n.mu.Unlock()
+
+	if err := n.getController().updateToStore(n); err != nil {
+		if err == datastore.ErrKeyModified {
+			return nil, types.RetryErrorf("network %s was modified concurrently, retry the update", n.Name())
+		}
+		return nil, err
+	}
+	return n.Labels(), nil
+}
+
 func (n *network) TableEventRegister(tableName string, objType driverapi.ObjectType) error {
 	if !driverapi.IsValidType(objType) {
 		return fmt.Errorf("invalid object type %v in registering table, %s", objType, tableName)
@@ -1969,6 +2022,18 @@ func (n *network) hasLoadBalancerEndpoint() bool {
 }
 
 func (n *network) ResolveName(req string, ipType int) ([]net.IP, bool) {
+	ip, _, ipv6Miss := n.resolveName(req, ipType, aliasPeer{})
+	return ip, ipv6Miss
+}
+
+func (n *network) ResolveNameTTL(req string, ipType int) ([]net.IP, uint32, bool) {
+	return n.resolveName(req, ipType, aliasPeer{})
+}
+
+// resolveName resolves a name on the network for a peer, which only sees the
+// aliases scoped to it. It also returns the lowest TTL set for the aliases
+// which resolved, or 0 if the default TTL applies.
+func (n *network) resolveName(req string, ipType int, peer aliasPeer) ([]net.IP, uint32, bool) {
 	var ipv6Miss bool
 
 	c := n.getController()
@@ -1978,7 +2043,7 @@ func (n *network) ResolveName(req string, ipType int) ([]net.IP, bool) {
 	sr, ok := c.svcRecords[networkID]
 
 	if !ok {
-		return nil, false
+		return nil, 0, false
 	}
 
 	req = strings.TrimSuffix(req, ".")
@@ -1999,17 +2064,31 @@ func (n *network) ResolveName(req string, ipType int) ([]net.IP, bool) {
 	if ok && len(ipSet) > 0 {
 		// this map is to avoid IP duplicates, this can happen during a transition period where 2 services are using the same IP
 		noDup := make(map[string]bool)
-		var ipLocal []net.IP
+		var (
+			ipLocal []net.IP
+			ttl     uint32
+		)
 		for _, ip := range ipSet {
-			if _, dup := noDup[ip.(svcMapEntry).ip]; !dup {
-				noDup[ip.(svcMapEntry).ip] = true
-				ipLocal = append(ipLocal, net.ParseIP(ip.(svcMapEntry).ip))
+			entry := ip.(svcMapEntry)
+			if opts, ok := sr.aliasOptions[req][entry.serviceID]; ok {
+				if !opts.visibleTo(peer) {
+					continue
+				}
+				if opts.TTL != 0 && (ttl == 0 || opts.TTL < ttl) {
+					ttl = opts.TTL
+				}
+			}
+			if _, dup := noDup[entry.ip]; !dup {
+				noDup[entry.ip] = true
+				ipLocal = append(ipLocal, net.ParseIP(entry.ip))
 			}
 		}
-		return ipLocal, ok
+		if len(ipLocal) > 0 {
+			return ipLocal, ttl, ok
+		}
 	}
 
-	return nil, ipv6Miss
+	return nil, 0, ipv6Miss
 }
 
 func (n *network) HandleQueryResp(name string, ip net.IP) {
@@ -418,6 +418,11 @@ type TableElem struct {
 	owner string
 }
 
+// Owner returns the name of the node which owns the entry.
+func (e *TableElem) Owner() string {
+	return e.owner
+}
+
 // GetTableByNetwork walks the networkdb by the give table and network id and
 // returns a map of keys and values
 func (nDB *NetworkDB) GetTableByNetwork(tname, nid string) map[string]*TableElem {
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"strconv"
 	"sync"
 
 	"github.com/sirupsen/logrus"
@@ -54,6 +55,16 @@ func (e ErrPortAlreadyAllocated) Error() string {
 	return fmt.Sprintf("Bind for %s:%d failed: port is already allocated", e.ip, e.port)
 }
 
+// ErrorCode returns the machine-readable code of this error
+func (e ErrPortAlreadyAllocated) ErrorCode() string {
+	return "port_already_allocated"
+}
+
+// ErrorDetails returns the address and port that are already in use
+func (e ErrPortAlreadyAllocated) ErrorDetails() map[string]string {
+	return map[string]string{"ip": e.ip, "port": strconv.Itoa(e.port)}
+}
+
 type (
 	// PortAllocator manages the transport ports database
 	PortAllocator struct {
@@ -102,6 +113,30 @@ func NewInstance() *PortAllocator {
 	}
 }
 
+// SetDynamicPortRange sets the range that ports are allocated from when no
+// port or range is requested, instead of the ephemeral port range of the
+// system. Ports that are already allocated are not released.
+func (p *PortAllocator) SetDynamicPortRange(portStart, portEnd int) error {
+	if portStart <= 0 || portEnd > 65535 || portEnd < portStart {
+		return fmt.Errorf("invalid port range: %s", getRangeKey(portStart, portEnd))
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.Begin, p.End = portStart, portEnd
+	key := getRangeKey(portStart, portEnd)
+	for _, protomap := range p.ipMap {
+		for _, pm := range protomap {
+			pm.defaultRange = key
+			if _, ok := pm.portRanges[key]; !ok {
+				pm.portRanges[key] = newPortRange(portStart, portEnd)
+			}
+		}
+	}
+	return nil
+}
+
 // RequestPort requests new port from global ports pool for specified ip and proto.
 // If port is 0 it returns first free port. Otherwise it checks port availability
 // in proto's pool and returns that port or error if port is already busy.
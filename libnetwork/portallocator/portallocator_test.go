@@ -321,3 +321,41 @@ func TestNoDuplicateBPR(t *testing.T) {
 		t.Fatalf("Acquire(0) allocated the same port twice: %d", port)
 	}
 }
+
+func TestSetDynamicPortRange(t *testing.T) {
+	p := Get()
+	defer resetPortAllocator()
+
+	port1, err := p.RequestPortInRange(defaultIP, "tcp", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := p.SetDynamicPortRange(40000, 39999); err == nil {
+		t.Fatal("Expected error for invalid range 40000-39999")
+	}
+	if err := p.SetDynamicPortRange(40000, 40001); err != nil {
+		t.Fatal(err)
+	}
+
+	// both existing and new port maps use the new range
+	for _, ip := range []net.IP{defaultIP, net.ParseIP("192.0.2.1")} {
+		for i := 0; i < 2; i++ {
+			port, err := p.RequestPortInRange(ip, "tcp", 0, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if port < 40000 || port > 40001 {
+				t.Fatalf("Expected a port between 40000 and 40001, got %d", port)
+			}
+		}
+		if _, err := p.RequestPortInRange(ip, "tcp", 0, 0); err != ErrAllPortsAllocated {
+			t.Fatalf("Expected error %s got %v", ErrAllPortsAllocated, err)
+		}
+	}
+
+	// ports allocated from the previous range are kept
+	if _, err := p.RequestPort(defaultIP, "tcp", port1); err == nil {
+		t.Fatalf("Expected port %d to still be allocated", port1)
+	}
+}
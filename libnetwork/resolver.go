@@ -43,6 +43,9 @@ type DNSBackend interface {
 	// value will be true if the name exists in docker domain but doesn't have an
 	// IPv6 address. Such queries shouldn't be forwarded to external nameservers.
 	ResolveName(name string, iplen int) ([]net.IP, bool)
+	// ResolveNameTTL is like ResolveName, and also returns the TTL of the
+	// answers, or 0 if the default TTL applies.
+	ResolveNameTTL(name string, iplen int) ([]net.IP, uint32, bool)
 	// ResolveIP returns the service name for the passed in IP. IP is in reverse dotted
 	// notation; the format used for DNS PTR records
 	ResolveIP(name string) string
@@ -246,10 +249,11 @@ func (r *resolver) handleMXQuery(query *dns.Msg) (*dns.Msg, error) {
 func (r *resolver) handleIPQuery(query *dns.Msg, ipType int) (*dns.Msg, error) {
 	var (
 		addr     []net.IP
+		ttl      uint32
 		ipv6Miss bool
 		name     = query.Question[0].Name
 	)
-	addr, ipv6Miss = r.backend.ResolveName(name, ipType)
+	addr, ttl, ipv6Miss = r.backend.ResolveNameTTL(name, ipType)
 
 	if addr == nil && ipv6Miss {
 		// Send a reply without any Answer sections
@@ -267,17 +271,20 @@ func (r *resolver) handleIPQuery(query *dns.Msg, ipType int) (*dns.Msg, error) {
 	if len(addr) > 1 {
 		addr = shuffleAddr(addr)
 	}
+	if ttl == 0 {
+		ttl = respTTL
+	}
 	if ipType == types.IPv4 {
 		for _, ip := range addr {
 			rr := new(dns.A)
-			rr.Hdr = dns.RR_Header{Name: name, Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: respTTL}
+			rr.Hdr = dns.RR_Header{Name: name, Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: ttl}
 			rr.A = ip
 			resp.Answer = append(resp.Answer, rr)
 		}
 	} else {
 		for _, ip := range addr {
 			rr := new(dns.AAAA)
-			rr.Hdr = dns.RR_Header{Name: name, Rrtype: dns.TypeAAAA, Class: dns.ClassINET, Ttl: respTTL}
+			rr.Hdr = dns.RR_Header{Name: name, Rrtype: dns.TypeAAAA, Class: dns.ClassINET, Ttl: ttl}
 			rr.AAAA = ip
 			resp.Answer = append(resp.Answer, rr)
 		}
@@ -170,6 +170,104 @@ func TestDNSIPQuery(t *testing.T) {
 	w.ClearResponse()
 }
 
+func TestDNSIPQueryAliasOptions(t *testing.T) {
+	skip.If(t, runtime.GOOS == "windows", "test only works on linux")
+
+	defer testutils.SetupTestOSContext(t)()
+	c, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Stop()
+
+	n, err := c.NewNetwork("bridge", "dtnet1", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := n.Delete(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	ep, err := n.CreateEndpoint("testep")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sb, err := c.NewSandbox("c1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := sb.Delete(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	if err := ep.Join(sb); err != nil {
+		t.Fatal(err)
+	}
+
+	nw := n.(*network)
+	nw.addSvcRecords("ep1", "web", "svc1", net.ParseIP("192.168.0.1"), net.IP{}, false, "test")
+	nw.addSvcRecords("ep2", "web", "svc2", net.ParseIP("192.168.0.2"), net.IP{}, false, "test")
+
+	w := new(tstwriter)
+	r := NewResolver(resolverIPSandbox, false, sb)
+	query := func() *dns.Msg {
+		q := new(dns.Msg)
+		q.SetQuestion("web", dns.TypeA)
+		r.(*resolver).ServeDNS(w, q)
+		resp := w.GetResponse()
+		w.ClearResponse()
+		checkNonNullResponse(t, resp)
+		checkDNSResponseCode(t, resp, dns.RcodeSuccess)
+		return resp
+	}
+
+	// The default TTL applies without alias options.
+	resp := query()
+	checkDNSAnswersCount(t, resp, 2)
+	if ttl := resp.Answer[0].Header().Ttl; ttl != respTTL {
+		t.Fatalf("Expected TTL %d, got %d", respTTL, ttl)
+	}
+
+	// The lowest TTL of the answers applies.
+	nw.setAliasOptions("web", "svc1", &AliasOptions{TTL: 30})
+	nw.setAliasOptions("web", "svc2", &AliasOptions{TTL: 5})
+	resp = query()
+	checkDNSAnswersCount(t, resp, 2)
+	if ttl := resp.Answer[0].Header().Ttl; ttl != 5 {
+		t.Fatalf("Expected TTL 5, got %d", ttl)
+	}
+
+	// An alias scoped to other peers is not resolved.
+	nw.setAliasOptions("web", "svc2", &AliasOptions{Peers: []string{"other"}})
+	resp = query()
+	checkDNSAnswersCount(t, resp, 1)
+	if answer := resp.Answer[0].(*dns.A); !answer.A.Equal(net.ParseIP("192.168.0.1")) {
+		t.Fatalf("Expected 192.168.0.1, got %v", answer.A)
+	}
+	if ttl := resp.Answer[0].Header().Ttl; ttl != 30 {
+		t.Fatalf("Expected TTL 30, got %d", ttl)
+	}
+
+	// The peers are matched against the endpoint and container names.
+	nw.setAliasOptions("web", "svc2", &AliasOptions{Peers: []string{"testep"}})
+	checkDNSAnswersCount(t, query(), 2)
+	nw.setAliasOptions("web", "svc2", &AliasOptions{Peers: []string{"c1"}})
+	checkDNSAnswersCount(t, query(), 2)
+
+	nw.setAliasOptions("web", "svc1", nil)
+	nw.setAliasOptions("web", "svc2", nil)
+	resp = query()
+	checkDNSAnswersCount(t, resp, 2)
+	if ttl := resp.Answer[0].Header().Ttl; ttl != respTTL {
+		t.Fatalf("Expected TTL %d, got %d", respTTL, ttl)
+	}
+}
+
 func newDNSHandlerServFailOnce(requests *int) func(w dns.ResponseWriter, r *dns.Msg) {
 	return func(w dns.ResponseWriter, r *dns.Msg) {
 		m := new(dns.Msg)
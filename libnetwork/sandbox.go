@@ -152,6 +152,35 @@ func (sb *Sandbox) Statistics() (map[string]*types.InterfaceStatistics, error) {
 	return m, nil
 }
 
+// InterfaceEndpoints returns the endpoints joined to the sandbox, keyed by the
+// name of their interface in the sandbox, as returned by Statistics.
+func (sb *Sandbox) InterfaceEndpoints() map[string]*Endpoint {
+	m := make(map[string]*Endpoint)
+
+	sb.mu.Lock()
+	osb := sb.osSbox
+	sb.mu.Unlock()
+	if osb == nil {
+		return m
+	}
+
+	ifaces := osb.Info().Interfaces()
+	for _, ep := range sb.Endpoints() {
+		epi := ep.Iface()
+		if epi == nil || epi.SrcName() == "" {
+			continue
+		}
+		for _, i := range ifaces {
+			if i.SrcName() == epi.SrcName() {
+				m[i.DstName()] = ep
+				break
+			}
+		}
+	}
+
+	return m
+}
+
 // Delete destroys this container after detaching it from all connected endpoints.
 func (sb *Sandbox) Delete() error {
 	return sb.delete(false)
@@ -491,6 +520,11 @@ func getLocalNwEndpoints(epList []*Endpoint) []*Endpoint {
 }
 
 func (sb *Sandbox) ResolveName(name string, ipType int) ([]net.IP, bool) {
+	ip, _, ipv6Miss := sb.ResolveNameTTL(name, ipType)
+	return ip, ipv6Miss
+}
+
+func (sb *Sandbox) ResolveNameTTL(name string, ipType int) ([]net.IP, uint32, bool) {
 	// Embedded server owns the docker network domain. Resolution should work
 	// for both container_name and container_name.network_name
 	// We allow '.' in service name and network name. For a name a.b.c.d the
@@ -537,27 +571,27 @@ func (sb *Sandbox) ResolveName(name string, ipType int) ([]net.IP, bool) {
 
 	for i := 0; i < len(reqName); i++ {
 		// First check for local container alias
-		ip, ipv6Miss := sb.resolveName(reqName[i], networkName[i], epList, true, ipType)
+		ip, ttl, ipv6Miss := sb.resolveName(reqName[i], networkName[i], epList, true, ipType)
 		if ip != nil {
-			return ip, false
+			return ip, ttl, false
 		}
 		if ipv6Miss {
-			return ip, ipv6Miss
+			return ip, 0, ipv6Miss
 		}
 
 		// Resolve the actual container name
-		ip, ipv6Miss = sb.resolveName(reqName[i], networkName[i], epList, false, ipType)
+		ip, ttl, ipv6Miss = sb.resolveName(reqName[i], networkName[i], epList, false, ipType)
 		if ip != nil {
-			return ip, false
+			return ip, ttl, false
 		}
 		if ipv6Miss {
-			return ip, ipv6Miss
+			return ip, 0, ipv6Miss
 		}
 	}
-	return nil, false
+	return nil, 0, false
 }
 
-func (sb *Sandbox) resolveName(req string, networkName string, epList []*Endpoint, alias bool, ipType int) ([]net.IP, bool) {
+func (sb *Sandbox) resolveName(req string, networkName string, epList []*Endpoint, alias bool, ipType int) ([]net.IP, uint32, bool) {
 	var ipv6Miss bool
 
 	for _, ep := range epList {
@@ -591,17 +625,17 @@ func (sb *Sandbox) resolveName(req string, networkName string, epList []*Endpoin
 			ep.mu.Unlock()
 		}
 
-		ip, miss := n.ResolveName(name, ipType)
+		ip, ttl, miss := n.resolveName(name, ipType, aliasPeer{name: ep.Name(), containerID: sb.ContainerID()})
 
 		if ip != nil {
-			return ip, false
+			return ip, ttl, false
 		}
 
 		if miss {
 			ipv6Miss = miss
 		}
 	}
-	return nil, ipv6Miss
+	return nil, 0, ipv6Miss
 }
 
 // SetKey updates the Sandbox Key.
@@ -707,6 +741,15 @@ func (sb *Sandbox) DisableService() (err error) {
 	return nil
 }
 
+// SetHealthy updates the health of the sandbox's container. The service
+// records of the endpoints created with CreateOptionHealthGated are only
+// present while it is healthy.
+func (sb *Sandbox) SetHealthy(healthy bool) {
+	for _, ep := range sb.Endpoints() {
+		ep.setHealthy(healthy)
+	}
+}
+
 func releaseOSSboxResources(osSbox osl.Sandbox, ep *Endpoint) {
 	for _, i := range osSbox.Info().Interfaces() {
 		// Only remove the interfaces owned by this endpoint from the sandbox.
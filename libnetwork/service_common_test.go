@@ -5,8 +5,11 @@ import (
 	"runtime"
 	"testing"
 
+	"github.com/docker/docker/libnetwork/networkdb"
 	"github.com/docker/docker/libnetwork/resolvconf"
 	"github.com/docker/docker/libnetwork/testutils"
+	"github.com/docker/docker/libnetwork/types"
+	"github.com/gogo/protobuf/proto"
 	"gotest.tools/v3/assert"
 	is "gotest.tools/v3/assert/cmp"
 	"gotest.tools/v3/skip"
@@ -57,6 +60,54 @@ func TestCleanupServiceDiscovery(t *testing.T) {
 	}
 }
 
+func TestEpTableEventServiceDisabled(t *testing.T) {
+	skip.If(t, runtime.GOOS == "windows", "test only works on linux")
+
+	defer testutils.SetupTestOSContext(t)()
+	c, err := New()
+	assert.NilError(t, err)
+	defer c.Stop()
+
+	n, err := c.NewNetwork("bridge", "net1", "", nil)
+	assert.NilError(t, err)
+	defer func() {
+		if err := n.Delete(); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	epRec := EndpointRecord{
+		Name:            "web.1.abc",
+		ServiceName:     "web",
+		ServiceID:       "serviceID1",
+		VirtualIP:       "10.0.0.2",
+		EndpointIP:      "10.0.0.3",
+		ServiceDisabled: true,
+	}
+	value := func() []byte {
+		b, err := proto.Marshal(&epRec)
+		assert.NilError(t, err)
+		return b
+	}
+
+	// The entry of a disabled task is not resolved when the node learns it.
+	c.handleEpTableEvent(networkdb.CreateEvent{NetworkID: n.ID(), Key: "ep1", Value: value()})
+	ips, _ := n.(*network).ResolveName("web", types.IPv4)
+	assert.Check(t, is.Len(ips, 0))
+
+	epRec.ServiceDisabled = false
+	c.handleEpTableEvent(networkdb.UpdateEvent{NetworkID: n.ID(), Key: "ep1", Value: value()})
+	ips, _ = n.(*network).ResolveName("web", types.IPv4)
+	assert.Check(t, is.DeepEqual(ips, []net.IP{net.ParseIP("10.0.0.2")}))
+	ips, _ = n.(*network).ResolveName("tasks.web", types.IPv4)
+	assert.Check(t, is.DeepEqual(ips, []net.IP{net.ParseIP("10.0.0.3")}))
+
+	epRec.ServiceDisabled = true
+	c.handleEpTableEvent(networkdb.UpdateEvent{NetworkID: n.ID(), Key: "ep1", Value: value()})
+	ips, _ = n.(*network).ResolveName("tasks.web", types.IPv4)
+	assert.Check(t, is.Len(ips, 0))
+}
+
 func TestDNSOptions(t *testing.T) {
 	skip.If(t, runtime.GOOS == "windows", "test only works on linux")
 
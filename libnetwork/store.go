@@ -5,18 +5,16 @@ import (
 	"strings"
 
 	"github.com/docker/docker/libnetwork/datastore"
-	"github.com/docker/libkv/store/boltdb"
+	_ "github.com/docker/docker/libnetwork/datastore/etcd" // register the etcd v3 datastore backend
 	"github.com/sirupsen/logrus"
 )
 
-func registerKVStores() {
-	boltdb.Register()
-}
-
+// initScopedStore initializes the store of a scope, using the datastore
+// backend registered for the provider of its configuration.
 func (c *Controller) initScopedStore(scope string, scfg *datastore.ScopeCfg) error {
 	store, err := datastore.NewDataStore(scope, scfg)
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to initialize the %s scope store: %v", scope, err)
 	}
 	c.mu.Lock()
 	c.stores = append(c.stores, store)
@@ -26,8 +24,6 @@ func (c *Controller) initScopedStore(scope string, scfg *datastore.ScopeCfg) err
 }
 
 func (c *Controller) initStores() error {
-	registerKVStores()
-
 	c.mu.Lock()
 	if c.cfg == nil {
 		c.mu.Unlock()
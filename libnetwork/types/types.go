@@ -77,6 +77,10 @@ type PortBinding struct {
 	HostIP      net.IP
 	HostPort    uint16
 	HostPortEnd uint16
+	// HostIface is the name of the host interface whose addresses the
+	// binding is for. Drivers supporting it bind the port on each address of
+	// the interface, setting HostIP in the operational bindings.
+	HostIface string `json:",omitempty"`
 }
 
 // HostAddr returns the host side transport address
@@ -116,6 +120,7 @@ func (p *PortBinding) GetCopy() PortBinding {
 		HostIP:      GetIPCopy(p.HostIP),
 		HostPort:    p.HostPort,
 		HostPortEnd: p.HostPortEnd,
+		HostIface:   p.HostIface,
 	}
 }
 
@@ -144,7 +149,8 @@ func (p *PortBinding) Equal(o *PortBinding) bool {
 	}
 
 	if p.Proto != o.Proto || p.Port != o.Port ||
-		p.HostPort != o.HostPort || p.HostPortEnd != o.HostPortEnd {
+		p.HostPort != o.HostPort || p.HostPortEnd != o.HostPortEnd ||
+		p.HostIface != o.HostIface {
 		return false
 	}
 
@@ -499,6 +505,12 @@ func InternalErrorf(format string, params ...interface{}) error {
 	return internal(fmt.Sprintf(format, params...))
 }
 
+// InternalErrorWrapf creates an instance of InternalError which keeps err
+// as its underlying error, so that it can be retrieved with errors.Unwrap
+func InternalErrorWrapf(err error, format string, params ...interface{}) error {
+	return internalWrap{msg: fmt.Sprintf(format, params...), err: err}
+}
+
 // InternalMaskableErrorf creates an instance of InternalError and MaskableError
 func InternalMaskableErrorf(format string, params ...interface{}) error {
 	return maskInternal(fmt.Sprintf(format, params...))
@@ -561,6 +573,17 @@ func (nt internal) Error() string {
 }
 func (nt internal) Internal() {}
 
+type internalWrap struct {
+	msg string
+	err error
+}
+
+func (iw internalWrap) Error() string {
+	return iw.msg
+}
+func (iw internalWrap) Internal()     {}
+func (iw internalWrap) Unwrap() error { return iw.err }
+
 type maskInternal string
 
 func (mnt maskInternal) Error() string {
@@ -169,6 +169,33 @@ func TestCompressStreamBzip2Unsupported(t *testing.T) {
 	}
 }
 
+func TestCompressStreamZstd(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := CompressStream(&buf, Zstd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := DecompressStream(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != "hello" {
+		t.Fatalf("expected %q, got %q", "hello", out)
+	}
+}
+
 func TestCompressStreamInvalid(t *testing.T) {
 	dest, err := os.Create(tmp + "dest")
 	if err != nil {
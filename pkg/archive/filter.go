@@ -0,0 +1,96 @@
+package archive // import "github.com/docker/docker/pkg/archive"
+
+import (
+	"archive/tar"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/moby/patternmatcher"
+)
+
+// FilterTar returns an uncompressed tar archive holding the entries of the
+// given uncompressed tar archive that match the include patterns, if any, and
+// don't match the exclude patterns.
+//
+// Patterns use the syntax of .dockerignore files, and are matched against the
+// names of the entries; a pattern matching a directory also matches its
+// contents. The root entry of the archive is always kept, as are hard links
+// whose target was kept.
+func FilterTar(in io.Reader, include, exclude []string) (io.ReadCloser, error) {
+	var includes, excludes *patternmatcher.PatternMatcher
+	var err error
+	if len(include) > 0 {
+		if includes, err = patternmatcher.New(include); err != nil {
+			return nil, err
+		}
+	}
+	if len(exclude) > 0 {
+		if excludes, err = patternmatcher.New(exclude); err != nil {
+			return nil, err
+		}
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(filterTar(in, pw, includes, excludes))
+	}()
+	return pr, nil
+}
+
+func filterTar(in io.Reader, out io.Writer, includes, excludes *patternmatcher.PatternMatcher) error {
+	tr := tar.NewReader(in)
+	tw := tar.NewWriter(out)
+	kept := make(map[string]struct{})
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return tw.Close()
+		}
+		if err != nil {
+			return err
+		}
+
+		name := entryName(hdr.Name)
+		if hdr.Typeflag == tar.TypeLink {
+			if _, ok := kept[entryName(hdr.Linkname)]; !ok {
+				continue
+			}
+		} else if name != "." {
+			if includes != nil {
+				ok, err := includes.MatchesOrParentMatches(name)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					continue
+				}
+			}
+			if excludes != nil {
+				ok, err := excludes.MatchesOrParentMatches(name)
+				if err != nil {
+					return err
+				}
+				if ok {
+					continue
+				}
+			}
+		}
+
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := io.Copy(tw, tr); err != nil {
+			return err
+		}
+		kept[name] = struct{}{}
+	}
+}
+
+// entryName returns the clean, relative name of a tar entry.
+func entryName(name string) string {
+	if name = strings.TrimPrefix(path.Clean("/"+name), "/"); name == "" {
+		return "."
+	}
+	return name
+}
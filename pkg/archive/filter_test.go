@@ -0,0 +1,80 @@
+package archive // import "github.com/docker/docker/pkg/archive"
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestFilterTar(t *testing.T) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, hdr := range []*tar.Header{
+		{Name: "app/", Typeflag: tar.TypeDir, Mode: 0o755},
+		{Name: "app/main.go", Typeflag: tar.TypeReg, Mode: 0o644, Size: 4},
+		{Name: "app/README.md", Typeflag: tar.TypeReg, Mode: 0o644, Size: 4},
+		{Name: "app/vendor/", Typeflag: tar.TypeDir, Mode: 0o755},
+		{Name: "app/vendor/lib.go", Typeflag: tar.TypeReg, Mode: 0o644, Size: 4},
+		{Name: "app/link.go", Typeflag: tar.TypeLink, Linkname: "app/main.go"},
+		{Name: "app/readme", Typeflag: tar.TypeLink, Linkname: "app/README.md"},
+	} {
+		assert.NilError(t, tw.WriteHeader(hdr))
+		if hdr.Size > 0 {
+			_, err := tw.Write([]byte("data"))
+			assert.NilError(t, err)
+		}
+	}
+	assert.NilError(t, tw.Close())
+
+	for _, tc := range []struct {
+		doc              string
+		include, exclude []string
+		expected         []string
+	}{
+		{
+			doc:      "no patterns",
+			expected: []string{"app/", "app/main.go", "app/README.md", "app/vendor/", "app/vendor/lib.go", "app/link.go", "app/readme"},
+		},
+		{
+			doc:      "include",
+			include:  []string{"**/*.go"},
+			expected: []string{"app/main.go", "app/vendor/lib.go", "app/link.go"},
+		},
+		{
+			doc:      "exclude",
+			exclude:  []string{"app/vendor", "**/*.md"},
+			expected: []string{"app/", "app/main.go", "app/link.go"},
+		},
+		{
+			doc:      "include and exclude",
+			include:  []string{"app"},
+			exclude:  []string{"app/vendor", "!app/vendor/lib.go"},
+			expected: []string{"app/", "app/main.go", "app/README.md", "app/vendor/lib.go", "app/link.go", "app/readme"},
+		},
+	} {
+		t.Run(tc.doc, func(t *testing.T) {
+			rc, err := FilterTar(bytes.NewReader(buf.Bytes()), tc.include, tc.exclude)
+			assert.NilError(t, err)
+			defer rc.Close()
+
+			var names []string
+			tr := tar.NewReader(rc)
+			for {
+				hdr, err := tr.Next()
+				if err == io.EOF {
+					break
+				}
+				assert.NilError(t, err)
+				names = append(names, hdr.Name)
+			}
+			assert.Check(t, is.DeepEqual(names, tc.expected))
+		})
+	}
+
+	_, err := FilterTar(bytes.NewReader(buf.Bytes()), []string{"["}, nil)
+	assert.Check(t, err != nil)
+}
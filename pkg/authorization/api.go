@@ -15,6 +15,14 @@ const (
 
 	// AuthZApiImplements is the name of the interface all AuthZ plugins implement
 	AuthZApiImplements = "authz"
+
+	// AuthZApiRequestV2 is the url for daemon request authorization using
+	// version 2 of the protocol
+	AuthZApiRequestV2 = "AuthZPluginV2.AuthZReq"
+
+	// AuthZApiImplementsV2 is the name of the interface implemented by AuthZ
+	// plugins that use version 2 of the protocol
+	AuthZApiImplementsV2 = "authzv2"
 )
 
 // PeerCertificate is a wrapper around x509.Certificate which provides a sane
@@ -86,3 +94,83 @@ type Response struct {
 	// Err stores a message in case there's an error
 	Err string `json:"Err,omitempty"`
 }
+
+// RequestV2 holds the data sent to authZ plugins using version 2 of the
+// protocol. Unlike Request, it carries the parsed request instead of its raw
+// body, and is only sent before the request is handled.
+type RequestV2 struct {
+	// User holds the user extracted by AuthN mechanism
+	User string `json:"User,omitempty"`
+
+	// UserAuthNMethod holds the mechanism used to extract user details (e.g., krb)
+	UserAuthNMethod string `json:"UserAuthNMethod,omitempty"`
+
+	// Method holds the HTTP method (GET/POST/PUT)
+	Method string `json:"Method,omitempty"`
+
+	// URI holds the full HTTP uri (e.g., /v1.43/containers/json?all=1)
+	URI string `json:"URI,omitempty"`
+
+	// APIVersion holds the API version requested by the client, if any
+	APIVersion string `json:"APIVersion,omitempty"`
+
+	// Endpoint holds the route of the request, without the version prefix
+	// (e.g., /containers/{name:.*}/start)
+	Endpoint string `json:"Endpoint,omitempty"`
+
+	// Target holds the route variables identifying the object the request
+	// operates on (e.g., {"name": "web"})
+	Target map[string]string `json:"Target,omitempty"`
+
+	// TargetID holds the ID of the object the request operates on, resolved
+	// from Target, if the object exists
+	TargetID string `json:"TargetID,omitempty"`
+
+	// Query holds the parsed query parameters of the request
+	Query map[string][]string `json:"Query,omitempty"`
+
+	// Object holds the JSON request body, if any
+	Object json.RawMessage `json:"Object,omitempty"`
+
+	// Streaming is set if the request body is a stream, such as a build
+	// context or an archive, which is not sent to the plugin. The decision
+	// applies to the whole stream.
+	Streaming bool `json:"Streaming,omitempty"`
+
+	// Headers stores the request headers, excluding credentials
+	Headers map[string]string `json:"Headers,omitempty"`
+
+	// PeerCertificates stores the request's TLS peer certificates in PEM format
+	PeerCertificates []*PeerCertificate `json:"PeerCertificates,omitempty"`
+}
+
+// ResponseV2 represents the response of an authZ plugin using version 2 of
+// the protocol
+type ResponseV2 struct {
+	// Allow indicating whether the user is allowed or not
+	Allow bool `json:"Allow"`
+
+	// Msg stores the authorization message
+	Msg string `json:"Msg,omitempty"`
+
+	// Err stores a message in case there's an error
+	Err string `json:"Err,omitempty"`
+
+	// Scope, if set, restricts the objects the response may contain
+	Scope *Scope `json:"Scope,omitempty"`
+
+	// CacheTTL is the number of seconds the daemon may reuse this decision
+	// for identical requests without a body. Zero disables caching.
+	CacheTTL int `json:"CacheTTL,omitempty"`
+}
+
+// Scope restricts the objects the client can operate on. Requests operating
+// on an object outside the scope are denied before they are handled. Objects
+// outside the scope are removed from list responses, and responses about a
+// single object outside the scope are denied. Responses that are streamed or
+// hijacked can't be filtered, and are denied when a scope is set.
+type Scope struct {
+	// Labels holds the labels an object must have, with the same values, to
+	// be in scope
+	Labels map[string]string `json:"Labels,omitempty"`
+}
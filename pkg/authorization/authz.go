@@ -3,11 +3,13 @@ package authorization // import "github.com/docker/docker/pkg/authorization"
 import (
 	"bufio"
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"mime"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/docker/docker/pkg/ioutils"
 	"github.com/sirupsen/logrus"
@@ -50,6 +52,25 @@ type Ctx struct {
 	plugins         []Plugin
 	// authReq stores the cached request object for the current transaction
 	authReq *Request
+
+	// apiVersion, endpoint and target describe the request to plugins
+	// using version 2 of the protocol
+	apiVersion string
+	endpoint   string
+	target     map[string]string
+	// object is the object the request operates on, if it was resolved
+	object *TargetObject
+	// cache holds the decisions of version 2 plugins that may be reused
+	cache *decisionCache
+	// cacheTTL is how long the decisions of version 1 plugins are reused,
+	// if the daemon enables it
+	cacheTTL time.Duration
+	// cacheableV1 is set if the decisions of version 1 plugins on the
+	// request may be reused
+	cacheableV1 bool
+	// scopes holds the scopes returned by version 2 plugins, which are
+	// applied to the response
+	scopes []*Scope
 }
 
 // AuthZRequest authorized the request to the docker daemon using authZ plugins
@@ -84,10 +105,26 @@ func (ctx *Ctx) AuthZRequest(w http.ResponseWriter, r *http.Request) error {
 		}
 	}
 
+	// Only the decisions on requests without a body, which are identified by
+	// their endpoint, the objects they operate on and their query, are
+	// reused.
+	ctx.cacheableV1 = ctx.cache != nil && ctx.cacheTTL > 0 && ctx.endpoint != "" && r.ContentLength == 0
+
+	var authReqV2 *RequestV2
 	for _, plugin := range ctx.plugins {
 		logrus.Debugf("AuthZ request using plugin %s", plugin.Name())
 
-		authRes, err := plugin.AuthZRequest(ctx.authReq)
+		if p, ok := usesV2(plugin); ok {
+			if authReqV2 == nil {
+				authReqV2 = ctx.newRequestV2(r, body)
+			}
+			if err := ctx.authZRequestV2(p, authReqV2); err != nil {
+				return err
+			}
+			continue
+		}
+
+		authRes, err := ctx.authZRequestV1(plugin, ctx.cacheableV1)
 		if err != nil {
 			return fmt.Errorf("plugin %s failed with error: %s", plugin.Name(), err)
 		}
@@ -100,8 +137,148 @@ func (ctx *Ctx) AuthZRequest(w http.ResponseWriter, r *http.Request) error {
 	return nil
 }
 
+// newRequestV2 creates the request sent to plugins using version 2 of the
+// protocol. body holds the request body, if it is sent to plugins.
+func (ctx *Ctx) newRequestV2(r *http.Request, body []byte) *RequestV2 {
+	req := &RequestV2{
+		User:             ctx.user,
+		UserAuthNMethod:  ctx.userAuthNMethod,
+		Method:           ctx.requestMethod,
+		URI:              ctx.requestURI,
+		APIVersion:       ctx.apiVersion,
+		Endpoint:         ctx.endpoint,
+		Target:           ctx.target,
+		Headers:          ctx.authReq.RequestHeaders,
+		PeerCertificates: ctx.authReq.RequestPeerCertificates,
+	}
+	if ctx.object != nil {
+		req.TargetID = ctx.object.ID
+	}
+	if r.URL != nil {
+		if q := r.URL.Query(); len(q) > 0 {
+			req.Query = q
+		}
+	}
+	if len(body) > 0 {
+		req.Object = body
+	} else if r.ContentLength != 0 && !strings.HasSuffix(ctx.requestURI, "/auth") {
+		req.Streaming = true
+	}
+	return req
+}
+
+// authZRequestV2 authorizes the request using a plugin using version 2 of
+// the protocol. Decisions on requests without a body are cached if the plugin
+// allows it.
+func (ctx *Ctx) authZRequestV2(plugin PluginV2, authReq *RequestV2) error {
+	var key string
+	cacheable := ctx.cache != nil && len(authReq.Object) == 0 && !authReq.Streaming
+	if cacheable {
+		key = decisionKey(plugin.Name(), authReq)
+		if authRes, ok := ctx.cache.get(key); ok {
+			return ctx.applyResponseV2(plugin.Name(), authRes.(*ResponseV2))
+		}
+	}
+
+	authRes, err := plugin.AuthZRequestV2(authReq)
+	if err != nil {
+		return fmt.Errorf("plugin %s failed with error: %s", plugin.Name(), err)
+	}
+	if cacheable && authRes.CacheTTL > 0 {
+		ctx.cache.set(key, authRes, ctx.targetObjects(), time.Duration(authRes.CacheTTL)*time.Second)
+	}
+	return ctx.applyResponseV2(plugin.Name(), authRes)
+}
+
+// authZRequestV1 calls a plugin using version 1 of the protocol on the
+// request. If cacheable is set, the decision of the plugin on an identical
+// request is reused, and the decision is cached.
+func (ctx *Ctx) authZRequestV1(plugin Plugin, cacheable bool) (*Response, error) {
+	if !cacheable {
+		return plugin.AuthZRequest(ctx.authReq)
+	}
+	key := decisionKeyV1(plugin.Name(), ctx)
+	if authRes, ok := ctx.cache.get(key); ok {
+		return authRes.(*Response), nil
+	}
+	authRes, err := plugin.AuthZRequest(ctx.authReq)
+	if err != nil {
+		return nil, err
+	}
+	ctx.cache.set(key, authRes, ctx.targetObjects(), ctx.cacheTTL)
+	return authRes, nil
+}
+
+func (ctx *Ctx) applyResponseV2(plugin string, authRes *ResponseV2) error {
+	if !authRes.Allow {
+		return newAuthorizationError(plugin, authRes.Msg)
+	}
+	if authRes.Scope != nil {
+		ctx.scopes = append(ctx.scopes, authRes.Scope)
+	}
+	return nil
+}
+
+// checkTargetScope denies a request operating on an object outside the
+// scopes returned by version 2 plugins. resolveErr is the error returned when
+// resolving the object: since it can't be checked, the request is denied.
+func (ctx *Ctx) checkTargetScope(resolveErr error) error {
+	if resolveErr != nil {
+		return newScopeError(fmt.Errorf("failed to resolve the object of the request: %v", resolveErr))
+	}
+	if ctx.object != nil && !inScopes(ctx.object.Labels, ctx.scopes) {
+		return newScopeError(errOutOfScope)
+	}
+	return nil
+}
+
+// targetObjects returns the names and IDs of the objects the request
+// operates on.
+func (ctx *Ctx) targetObjects() []string {
+	objects := targetObjects(ctx.target)
+	if ctx.object != nil && ctx.object.ID != "" {
+		objects = append(objects, ctx.object.ID)
+	}
+	return objects
+}
+
+// scoped returns whether a version 2 plugin restricted the response to a
+// scope.
+func (ctx *Ctx) scoped() bool {
+	return len(ctx.scopes) > 0
+}
+
+// applyScopes filters the response by the scopes returned by version 2
+// plugins.
+func (ctx *Ctx) applyScopes(rm ResponseModifier) error {
+	if m, ok := rm.(*responseModifier); ok && m.streamed {
+		return newScopeError(errScopedStream)
+	}
+	if rm.Hijacked() {
+		return newScopeError(errScopedStream)
+	}
+	if rm.StatusCode() >= http.StatusBadRequest || len(rm.RawBody()) == 0 {
+		return nil
+	}
+	if !sendBody(ctx.requestURI, rm.Header()) {
+		return newScopeError(errors.New("only JSON responses can be filtered by scope"))
+	}
+	body, err := filterByScope(rm.RawBody(), ctx.scopes)
+	if err != nil {
+		return newScopeError(err)
+	}
+	rm.OverrideBody(body)
+	return nil
+}
+
 // AuthZResponse authorized and manipulates the response from docker daemon using authZ plugins
 func (ctx *Ctx) AuthZResponse(rm ResponseModifier, r *http.Request) error {
+	if ctx.scoped() {
+		if err := ctx.applyScopes(rm); err != nil {
+			return err
+		}
+	}
+
 	ctx.authReq.ResponseStatusCode = rm.StatusCode()
 	ctx.authReq.ResponseHeaders = headers(rm.Header())
 
@@ -110,8 +287,15 @@ func (ctx *Ctx) AuthZResponse(rm ResponseModifier, r *http.Request) error {
 	}
 
 	for _, plugin := range ctx.plugins {
+		if _, ok := usesV2(plugin); ok {
+			// Plugins using version 2 of the protocol filter responses
+			// through scopes.
+			continue
+		}
 		logrus.Debugf("AuthZ response using plugin %s", plugin.Name())
 
+		// Decisions on responses are never reused, since plugins may decide
+		// on the response body.
 		authRes, err := plugin.AuthZResponse(ctx.authReq)
 		if err != nil {
 			return fmt.Errorf("plugin %s failed with error: %s", plugin.Name(), err)
@@ -187,3 +371,22 @@ func (authorizationError) Forbidden() {}
 func newAuthorizationError(plugin, msg string) authorizationError {
 	return authorizationError{error: fmt.Errorf("authorization denied by plugin %s: %s", plugin, msg)}
 }
+
+func newScopeError(err error) authorizationError {
+	return authorizationError{error: fmt.Errorf("authorization denied: %s", err)}
+}
+
+// usesV2 returns the plugin if it uses version 2 of the protocol. Plugins
+// that fail to initialize are treated as using version 1, which reports the
+// error when the plugin is called.
+func usesV2(plugin Plugin) (PluginV2, bool) {
+	p, ok := plugin.(PluginV2)
+	if !ok {
+		return nil, false
+	}
+	version, err := p.ProtocolVersion()
+	if err != nil || version != 2 {
+		return nil, false
+	}
+	return p, true
+}
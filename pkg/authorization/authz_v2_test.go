@@ -0,0 +1,244 @@
+package authorization // import "github.com/docker/docker/pkg/authorization"
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/pkg/plugingetter"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+// fakePluginV2 is an in-memory plugin using version 2 of the protocol
+type fakePluginV2 struct {
+	res      ResponseV2
+	requests []RequestV2
+}
+
+func (p *fakePluginV2) Name() string                  { return "fake-v2" }
+func (p *fakePluginV2) ProtocolVersion() (int, error) { return 2, nil }
+func (p *fakePluginV2) AuthZRequest(*Request) (*Response, error) {
+	panic("version 1 request sent to version 2 plugin")
+}
+
+func (p *fakePluginV2) AuthZResponse(*Request) (*Response, error) {
+	panic("version 1 response sent to version 2 plugin")
+}
+
+func (p *fakePluginV2) AuthZRequestV2(req *RequestV2) (*ResponseV2, error) {
+	p.requests = append(p.requests, *req)
+	res := p.res
+	return &res, nil
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	return json.NewEncoder(w).Encode(v)
+}
+
+func TestMiddlewareV2(t *testing.T) {
+	plugin := &fakePluginV2{}
+	var pluginGetter plugingetter.PluginGetter
+	m := NewMiddleware(nil, pluginGetter)
+	setAuthzPlugins(m, []Plugin{plugin})
+
+	handler := m.WrapHandler(func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+		if r.URL.Path == "/volumes" {
+			return writeJSON(w, http.StatusOK, map[string]interface{}{
+				"Volumes": []map[string]interface{}{
+					{"Name": "a", "Labels": map[string]string{"tenant": "a"}},
+					{"Name": "b", "Labels": map[string]string{"tenant": "b"}},
+				},
+			})
+		}
+		if vars["name"] != "" {
+			return writeJSON(w, http.StatusOK, map[string]interface{}{
+				"Id":     vars["name"],
+				"Config": map[string]interface{}{"Labels": map[string]string{"tenant": vars["name"]}},
+			})
+		}
+		return writeJSON(w, http.StatusOK, []map[string]interface{}{
+			{"Id": "a", "Labels": map[string]string{"tenant": "a"}},
+			{"Id": "b", "Labels": map[string]string{"tenant": "b"}},
+			{"Id": "c"},
+		})
+	})
+
+	do := func(method, uri, body string, vars map[string]string) (*httptest.ResponseRecorder, error) {
+		req := httptest.NewRequest(method, uri, strings.NewReader(body))
+		if strings.HasPrefix(body, "{") {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		resp := httptest.NewRecorder()
+		return resp, handler(context.Background(), resp, req, vars)
+	}
+
+	t.Run("denied", func(t *testing.T) {
+		plugin.res = ResponseV2{Allow: false, Msg: "nope"}
+		_, err := do(http.MethodGet, "/v1.43/containers/json", "", map[string]string{"version": "1.43"})
+		assert.Check(t, is.ErrorContains(err, "authorization denied by plugin fake-v2: nope"))
+	})
+
+	t.Run("parsed request", func(t *testing.T) {
+		plugin.requests = nil
+		plugin.res = ResponseV2{Allow: true}
+		_, err := do(http.MethodPost, "/v1.43/containers/create?name=web", `{"Image":"busybox"}`, map[string]string{"version": "1.43"})
+		assert.NilError(t, err)
+		assert.Assert(t, is.Len(plugin.requests, 1))
+		req := plugin.requests[0]
+		assert.Check(t, is.Equal(req.APIVersion, "1.43"))
+		assert.Check(t, is.DeepEqual(req.Query, map[string][]string{"name": {"web"}}))
+		assert.Check(t, is.Equal(string(req.Object), `{"Image":"busybox"}`))
+		assert.Check(t, !req.Streaming)
+	})
+
+	t.Run("streaming request", func(t *testing.T) {
+		plugin.requests = nil
+		_, err := do(http.MethodPost, "/v1.43/build", "not json", map[string]string{"version": "1.43"})
+		assert.NilError(t, err)
+		assert.Assert(t, is.Len(plugin.requests, 1))
+		assert.Check(t, plugin.requests[0].Streaming)
+		assert.Check(t, is.Len(plugin.requests[0].Object, 0))
+	})
+
+	t.Run("scoped list", func(t *testing.T) {
+		plugin.res = ResponseV2{Allow: true, Scope: &Scope{Labels: map[string]string{"tenant": "a"}}}
+		resp, err := do(http.MethodGet, "/v1.43/containers/json", "", nil)
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(strings.TrimSpace(resp.Body.String()), `[{"Id":"a","Labels":{"tenant":"a"}}]`))
+
+		resp, err = do(http.MethodGet, "/volumes", "", nil)
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(strings.TrimSpace(resp.Body.String()), `{"Volumes":[{"Labels":{"tenant":"a"},"Name":"a"}]}`))
+	})
+
+	t.Run("scoped object", func(t *testing.T) {
+		plugin.res = ResponseV2{Allow: true, Scope: &Scope{Labels: map[string]string{"tenant": "a"}}}
+		resp, err := do(http.MethodGet, "/containers/a/json", "", map[string]string{"name": "a"})
+		assert.NilError(t, err)
+		assert.Check(t, is.Contains(resp.Body.String(), `"Id":"a"`))
+
+		resp, err = do(http.MethodGet, "/containers/b/json", "", map[string]string{"name": "b"})
+		assert.Check(t, is.ErrorContains(err, "outside the scope"))
+		assert.Check(t, is.Equal(resp.Body.Len(), 0))
+	})
+
+	t.Run("cached decision", func(t *testing.T) {
+		plugin.requests = nil
+		plugin.res = ResponseV2{Allow: true, CacheTTL: 60}
+		for i := 0; i < 3; i++ {
+			_, err := do(http.MethodGet, "/v1.43/info", "", nil)
+			assert.NilError(t, err)
+		}
+		assert.Check(t, is.Len(plugin.requests, 1))
+
+		// Requests with a body are never cached.
+		for i := 0; i < 2; i++ {
+			_, err := do(http.MethodPost, "/v1.43/containers/create", `{}`, nil)
+			assert.NilError(t, err)
+		}
+		assert.Check(t, is.Len(plugin.requests, 3))
+	})
+}
+
+func TestMiddlewareV2ScopedTarget(t *testing.T) {
+	plugin := &fakePluginV2{res: ResponseV2{Allow: true, Scope: &Scope{Labels: map[string]string{"tenant": "a"}}}}
+	var pluginGetter plugingetter.PluginGetter
+	m := NewMiddleware(nil, pluginGetter)
+	setAuthzPlugins(m, []Plugin{plugin})
+	m.SetObjectResolver(func(ctx context.Context, method, endpoint string, target map[string]string) (*TargetObject, error) {
+		switch target["name"] {
+		case "a", "b":
+			return &TargetObject{ID: target["name"] + "-id", Labels: map[string]string{"tenant": target["name"]}}, nil
+		case "broken":
+			return nil, errors.New("broken")
+		}
+		return nil, nil
+	})
+
+	var handled []string
+	handler := m.WrapHandler(func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+		handled = append(handled, vars["name"])
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	})
+	start := func(name string) error {
+		req := httptest.NewRequest(http.MethodPost, "/containers/"+name+"/start", nil)
+		return handler(context.Background(), httptest.NewRecorder(), req, map[string]string{"name": name})
+	}
+
+	assert.NilError(t, start("a"))
+	assert.Assert(t, is.Len(plugin.requests, 1))
+	assert.Check(t, is.Equal(plugin.requests[0].TargetID, "a-id"))
+
+	// Requests on objects outside the scope are denied before being handled.
+	assert.Check(t, is.ErrorContains(start("b"), "outside the scope"))
+	assert.Check(t, is.ErrorContains(start("broken"), "failed to resolve"))
+	// Missing objects are reported by the handler.
+	assert.NilError(t, start("missing"))
+	assert.Check(t, is.DeepEqual(handled, []string{"a", "missing"}))
+}
+
+func TestMiddlewareV2ImpliedRequests(t *testing.T) {
+	plugin := &fakePluginV2{res: ResponseV2{Allow: true, Scope: &Scope{Labels: map[string]string{"tenant": "a"}}}}
+	var pluginGetter plugingetter.PluginGetter
+	m := NewMiddleware(nil, pluginGetter)
+	setAuthzPlugins(m, []Plugin{plugin})
+	m.SetObjectResolver(func(ctx context.Context, method, endpoint string, target map[string]string) (*TargetObject, error) {
+		name := target["name"]
+		if strings.HasPrefix(endpoint, "/containers/") {
+			return &TargetObject{ID: name, Labels: map[string]string{"tenant": name}}, nil
+		}
+		obj := &TargetObject{ID: name, Labels: map[string]string{"tenant": "a"}}
+		for _, member := range strings.Split(name, ",") {
+			obj.Implied = append(obj.Implied, ImpliedRequest{
+				Method:   method,
+				URI:      "/containers/" + member + "/start",
+				Endpoint: "/containers/{name:.*}/start",
+				Target:   map[string]string{"name": member},
+			})
+		}
+		return obj, nil
+	})
+
+	var handled []string
+	handler := m.WrapHandler(func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+		handled = append(handled, vars["name"])
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	})
+	start := func(name string) error {
+		req := httptest.NewRequest(http.MethodPost, "/v1.43/groups/"+name+"/start", nil)
+		return handler(context.Background(), httptest.NewRecorder(), req, map[string]string{"version": "1.43", "name": name})
+	}
+
+	assert.NilError(t, start("a"))
+	assert.Assert(t, is.Len(plugin.requests, 2))
+	assert.Check(t, is.Equal(plugin.requests[1].URI, "/v1.43/containers/a/start"))
+	assert.Check(t, is.Equal(plugin.requests[1].Endpoint, "/containers/{name:.*}/start"))
+	assert.Check(t, is.Equal(plugin.requests[1].TargetID, "a"))
+
+	// The request is denied if a request it implies is.
+	assert.Check(t, is.ErrorContains(start("a,b"), "outside the scope"))
+	assert.Check(t, is.DeepEqual(handled, []string{"a"}))
+}
+
+func TestScopedResponseModifierStreaming(t *testing.T) {
+	rec := httptest.NewRecorder()
+	rm := newScopedResponseModifier(rec)
+	_, err := rm.Write([]byte("data"))
+	assert.NilError(t, err)
+	rm.Flush()
+	_, err = rm.Write([]byte("more"))
+	assert.Check(t, is.ErrorIs(err, errScopedStream))
+	assert.Check(t, is.Equal(rec.Body.Len(), 0))
+
+	ctx := &Ctx{scopes: []*Scope{{Labels: map[string]string{"tenant": "a"}}}}
+	assert.Check(t, is.ErrorContains(ctx.applyScopes(rm), "cannot be filtered"))
+}
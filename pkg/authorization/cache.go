@@ -0,0 +1,137 @@
+package authorization // import "github.com/docker/docker/pkg/authorization"
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxCachedDecisions limits the number of decisions held by a decisionCache.
+const maxCachedDecisions = 4096
+
+type cachedDecision struct {
+	// res is the *ResponseV2 of a plugin using version 2 of the protocol,
+	// or the *Response of a plugin using version 1.
+	res interface{}
+	// objects holds the names and IDs of the objects the request operated
+	// on, to drop the decision when one of them is removed.
+	objects []string
+	expires time.Time
+}
+
+// decisionCache holds the decisions of plugins using version 2 of the
+// protocol, for as long as the plugin allows them to be reused, and, if the
+// daemon enables it, the decisions of plugins using version 1.
+type decisionCache struct {
+	mu        sync.Mutex
+	decisions map[string]cachedDecision
+}
+
+func newDecisionCache() *decisionCache {
+	return &decisionCache{decisions: make(map[string]cachedDecision)}
+}
+
+// decisionKey returns the key of the decision of a plugin on a request.
+func decisionKey(plugin string, req *RequestV2) string {
+	return strings.Join([]string{plugin, req.User, req.UserAuthNMethod, req.Method, req.URI}, "\x00")
+}
+
+// decisionKeyV1 returns the key of the decision of a plugin using version 1
+// of the protocol on a request, which identifies the request by its user,
+// its endpoint, the objects it operates on, and its query parameters.
+func decisionKeyV1(plugin string, ctx *Ctx) string {
+	parts := []string{"v1", plugin, ctx.user, ctx.userAuthNMethod, ctx.requestMethod, ctx.endpoint}
+	for _, k := range sortedKeys(ctx.target) {
+		parts = append(parts, k+"="+ctx.target[k])
+	}
+	parts = append(parts, rawQuery(ctx.requestURI))
+	return strings.Join(parts, "\x00")
+}
+
+// rawQuery returns the query string of a request URI.
+func rawQuery(uri string) string {
+	if i := strings.IndexByte(uri, '?'); i >= 0 {
+		return uri[i+1:]
+	}
+	return ""
+}
+
+// targetObjects returns the names and IDs of the objects a request operates
+// on, from the route variables of the request.
+func targetObjects(target map[string]string) []string {
+	var objects []string
+	for _, k := range sortedKeys(target) {
+		objects = append(objects, target[k])
+	}
+	return objects
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (c *decisionCache) get(key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	d, ok := c.decisions[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(d.expires) {
+		delete(c.decisions, key)
+		return nil, false
+	}
+	return d.res, true
+}
+
+func (c *decisionCache) set(key string, res interface{}, objects []string, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	if len(c.decisions) >= maxCachedDecisions {
+		for k, d := range c.decisions {
+			if now.After(d.expires) {
+				delete(c.decisions, k)
+			}
+		}
+		if len(c.decisions) >= maxCachedDecisions {
+			return
+		}
+	}
+	c.decisions[key] = cachedDecision{res: res, objects: objects, expires: now.Add(ttl)}
+}
+
+// invalidate drops the decisions about any of the given objects.
+func (c *decisionCache) invalidate(objects ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k, d := range c.decisions {
+		if containsAny(d.objects, objects) {
+			delete(c.decisions, k)
+		}
+	}
+}
+
+func containsAny(objects, names []string) bool {
+	for _, o := range objects {
+		for _, n := range names {
+			if o == n {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// reset drops all cached decisions.
+func (c *decisionCache) reset() {
+	c.mu.Lock()
+	c.decisions = make(map[string]cachedDecision)
+	c.mu.Unlock()
+}
@@ -0,0 +1,102 @@
+package authorization // import "github.com/docker/docker/pkg/authorization"
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/pkg/plugingetter"
+	"github.com/gorilla/mux"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+// fakePluginV1 is an in-memory plugin using version 1 of the protocol, which
+// decides on requests and allows all responses.
+type fakePluginV1 struct {
+	allow     bool
+	requests  int
+	responses int
+}
+
+func (p *fakePluginV1) Name() string { return "fake-v1" }
+
+func (p *fakePluginV1) AuthZRequest(*Request) (*Response, error) {
+	p.requests++
+	return &Response{Allow: p.allow, Msg: "nope"}, nil
+}
+
+func (p *fakePluginV1) AuthZResponse(*Request) (*Response, error) {
+	p.responses++
+	return &Response{Allow: true}, nil
+}
+
+func TestMiddlewareCacheV1(t *testing.T) {
+	plugin := &fakePluginV1{allow: true}
+	var pluginGetter plugingetter.PluginGetter
+	m := NewMiddleware(nil, pluginGetter)
+	setAuthzPlugins(m, []Plugin{plugin})
+	m.SetCacheTTL(time.Minute)
+
+	handler := m.WrapHandler(func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+		return writeJSON(w, http.StatusOK, map[string]string{"Id": vars["name"]})
+	})
+	router := mux.NewRouter()
+	router.Path("/v{version:[0-9.]+}/containers/{name:.*}/json").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := handler(r.Context(), w, r, mux.Vars(r)); err != nil {
+			w.WriteHeader(http.StatusForbidden)
+		}
+	})
+	router.Path("/v{version:[0-9.]+}/containers/create").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = handler(r.Context(), w, r, mux.Vars(r))
+	})
+
+	do := func(method, uri, body string) int {
+		req := httptest.NewRequest(method, uri, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp.Code
+	}
+
+	// Requests to the same endpoint about the same object reuse the
+	// decisions, whatever the API version.
+	assert.Check(t, is.Equal(do(http.MethodGet, "/v1.43/containers/web/json", ""), http.StatusOK))
+	assert.Check(t, is.Equal(do(http.MethodGet, "/v1.42/containers/web/json", ""), http.StatusOK))
+	assert.Check(t, is.Equal(plugin.requests, 1))
+	// Decisions on responses are never reused.
+	assert.Check(t, is.Equal(plugin.responses, 2))
+
+	// Other objects get their own decisions.
+	assert.Check(t, is.Equal(do(http.MethodGet, "/v1.43/containers/db/json", ""), http.StatusOK))
+	assert.Check(t, is.Equal(plugin.requests, 2))
+
+	// So do requests with other query parameters.
+	assert.Check(t, is.Equal(do(http.MethodGet, "/v1.43/containers/db/json?size=1", ""), http.StatusOK))
+	assert.Check(t, is.Equal(do(http.MethodGet, "/v1.43/containers/db/json?size=1", ""), http.StatusOK))
+	assert.Check(t, is.Equal(plugin.requests, 3))
+
+	// Requests with a body are never cached.
+	for i := 0; i < 2; i++ {
+		do(http.MethodPost, "/v1.43/containers/create", `{}`)
+	}
+	assert.Check(t, is.Equal(plugin.requests, 5))
+
+	// Decisions about an object are dropped when it is invalidated.
+	plugin.allow = false
+	assert.Check(t, is.Equal(do(http.MethodGet, "/v1.43/containers/web/json", ""), http.StatusOK))
+	m.InvalidateCache("web")
+	assert.Check(t, is.Equal(do(http.MethodGet, "/v1.43/containers/web/json", ""), http.StatusForbidden))
+	assert.Check(t, is.Equal(plugin.requests, 6))
+
+	// Denials are cached too, until caching is disabled.
+	plugin.allow = true
+	assert.Check(t, is.Equal(do(http.MethodGet, "/v1.43/containers/web/json", ""), http.StatusForbidden))
+	m.SetCacheTTL(0)
+	assert.Check(t, is.Equal(do(http.MethodGet, "/v1.43/containers/web/json", ""), http.StatusOK))
+	assert.Check(t, is.Equal(do(http.MethodGet, "/v1.43/containers/web/json", ""), http.StatusOK))
+	assert.Check(t, is.Equal(plugin.requests, 8))
+}
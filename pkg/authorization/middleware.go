@@ -3,25 +3,60 @@ package authorization // import "github.com/docker/docker/pkg/authorization"
 import (
 	"context"
 	"net/http"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/docker/docker/pkg/plugingetter"
+	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
 )
 
 // Middleware uses a list of plugins to
 // handle authorization in the API requests.
 type Middleware struct {
-	mu      sync.Mutex
-	plugins []Plugin
+	mu       sync.Mutex
+	plugins  []Plugin
+	cache    *decisionCache
+	cacheTTL time.Duration
+	resolver ObjectResolver
 }
 
+// TargetObject is the object a request operates on.
+type TargetObject struct {
+	ID     string
+	Labels map[string]string
+
+	// Implied are the requests on other objects the request implies, such
+	// as the requests on the members of a container group it starts. They
+	// are authorized along with the request.
+	Implied []ImpliedRequest
+}
+
+// ImpliedRequest is a request on another object implied by a request.
+type ImpliedRequest struct {
+	Method string
+	// URI is the URI of the request, without the version prefix.
+	URI string
+	// Endpoint and Target are the route of the request, without the version
+	// prefix, and its route variables.
+	Endpoint string
+	Target   map[string]string
+}
+
+// ObjectResolver resolves the object a request operates on, from the method
+// and the route of the request, without the version prefix, and its route
+// variables. It returns nil if the route does not operate on a labeled
+// object, or if the object does not exist.
+type ObjectResolver func(ctx context.Context, method, endpoint string, target map[string]string) (*TargetObject, error)
+
 // NewMiddleware creates a new Middleware
 // with a slice of plugins names.
 func NewMiddleware(names []string, pg plugingetter.PluginGetter) *Middleware {
 	SetPluginGetter(pg)
 	return &Middleware{
 		plugins: newPlugins(names),
+		cache:   newDecisionCache(),
 	}
 }
 
@@ -31,11 +66,38 @@ func (m *Middleware) getAuthzPlugins() []Plugin {
 	return m.plugins
 }
 
+func (m *Middleware) getCacheTTL() time.Duration {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.cacheTTL
+}
+
+// HasPlugins returns whether authorization plugins are configured.
+func (m *Middleware) HasPlugins() bool {
+	return len(m.getAuthzPlugins()) > 0
+}
+
+// SetObjectResolver sets the resolver of the objects requests operate on,
+// which are checked against the scopes returned by plugins before requests
+// are handled.
+func (m *Middleware) SetObjectResolver(resolver ObjectResolver) {
+	m.mu.Lock()
+	m.resolver = resolver
+	m.mu.Unlock()
+}
+
+func (m *Middleware) getObjectResolver() ObjectResolver {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.resolver
+}
+
 // SetPlugins sets the plugin used for authorization
 func (m *Middleware) SetPlugins(names []string) {
 	m.mu.Lock()
 	m.plugins = newPlugins(names)
 	m.mu.Unlock()
+	m.resetCache()
 }
 
 // RemovePlugin removes a single plugin from this authz middleware chain
@@ -49,6 +111,39 @@ func (m *Middleware) RemovePlugin(name string) {
 		}
 	}
 	m.plugins = plugins
+	m.resetCache()
+}
+
+// SetCacheTTL sets how long the decisions of plugins using version 1 of the
+// protocol on requests without a body are reused for requests of the same
+// user, to the same endpoint, about the same objects. Zero disables caching,
+// which is the default. Plugins using version 2 of the protocol set how long
+// their decisions are reused themselves.
+func (m *Middleware) SetCacheTTL(ttl time.Duration) {
+	m.mu.Lock()
+	m.cacheTTL = ttl
+	m.mu.Unlock()
+	m.resetCache()
+}
+
+// InvalidateCache drops the cached decisions about requests operating on any
+// of the objects with the given names or IDs. Without names, it drops all
+// cached decisions.
+func (m *Middleware) InvalidateCache(names ...string) {
+	if m.cache == nil {
+		return
+	}
+	if len(names) == 0 {
+		m.cache.reset()
+		return
+	}
+	m.cache.invalidate(names...)
+}
+
+func (m *Middleware) resetCache() {
+	if m.cache != nil {
+		m.cache.reset()
+	}
 }
 
 // WrapHandler returns a new handler function wrapping the previous one in the request chain.
@@ -72,13 +167,46 @@ func (m *Middleware) WrapHandler(handler func(ctx context.Context, w http.Respon
 		}
 
 		authCtx := NewCtx(plugins, user, userAuthNMethod, r.Method, r.RequestURI)
+		authCtx.apiVersion = vars["version"]
+		authCtx.endpoint, authCtx.target = requestEndpoint(r, vars)
+		authCtx.cache = m.cache
+		authCtx.cacheTTL = m.getCacheTTL()
+
+		// Resolve the object the request operates on, so that plugins get its
+		// ID, and requests on objects outside their scopes are denied.
+		resolver := m.getObjectResolver()
+		var objErr error
+		if resolver != nil && len(authCtx.target) > 0 {
+			authCtx.object, objErr = resolver(ctx, r.Method, authCtx.endpoint, authCtx.target)
+		}
 
 		if err := authCtx.AuthZRequest(w, r); err != nil {
 			logrus.Errorf("AuthZRequest for %s %s returned error: %s", r.Method, r.RequestURI, err)
 			return err
 		}
 
-		rw := NewResponseModifier(w)
+		if authCtx.scoped() {
+			if err := authCtx.checkTargetScope(objErr); err != nil {
+				logrus.Errorf("AuthZRequest for %s %s returned error: %s", r.Method, r.RequestURI, err)
+				return err
+			}
+		}
+
+		if authCtx.object != nil {
+			for _, ir := range authCtx.object.Implied {
+				if err := m.authorizeImplied(ctx, authCtx, w, r, ir, resolver); err != nil {
+					logrus.Errorf("AuthZRequest for %s %s implied by %s %s returned error: %s", ir.Method, ir.URI, r.Method, r.RequestURI, err)
+					return err
+				}
+			}
+		}
+
+		var rw ResponseModifier
+		if authCtx.scoped() {
+			rw = newScopedResponseModifier(w)
+		} else {
+			rw = NewResponseModifier(w)
+		}
 
 		var errD error
 
@@ -108,3 +236,61 @@ func (m *Middleware) WrapHandler(handler func(ctx context.Context, w http.Respon
 		return nil
 	}
 }
+
+// authorizeImplied authorizes a request implied by the request r, on behalf
+// of the same user, as if it was made without a body.
+func (m *Middleware) authorizeImplied(ctx context.Context, parent *Ctx, w http.ResponseWriter, r *http.Request, ir ImpliedRequest, resolver ObjectResolver) error {
+	uri := ir.URI
+	if parent.apiVersion != "" {
+		uri = "/v" + parent.apiVersion + uri
+	}
+	req, err := http.NewRequestWithContext(ctx, ir.Method, uri, nil)
+	if err != nil {
+		return err
+	}
+	req.RequestURI = uri
+	req.Header = r.Header.Clone()
+	req.Header.Del("Content-Length")
+	req.Header.Del("Content-Type")
+	req.TLS = r.TLS
+
+	authCtx := NewCtx(parent.plugins, parent.user, parent.userAuthNMethod, ir.Method, uri)
+	authCtx.apiVersion = parent.apiVersion
+	authCtx.endpoint, authCtx.target = ir.Endpoint, ir.Target
+	authCtx.cache = parent.cache
+	authCtx.cacheTTL = parent.cacheTTL
+
+	var objErr error
+	if resolver != nil && len(authCtx.target) > 0 {
+		authCtx.object, objErr = resolver(ctx, ir.Method, authCtx.endpoint, authCtx.target)
+	}
+	if err := authCtx.AuthZRequest(w, req); err != nil {
+		return err
+	}
+	if authCtx.scoped() {
+		return authCtx.checkTargetScope(objErr)
+	}
+	return nil
+}
+
+// requestEndpoint returns the route of the request without the version
+// prefix, and the route variables identifying the object it operates on.
+func requestEndpoint(r *http.Request, vars map[string]string) (string, map[string]string) {
+	var endpoint string
+	if route := mux.CurrentRoute(r); route != nil {
+		if tmpl, err := route.GetPathTemplate(); err == nil {
+			endpoint = strings.TrimPrefix(tmpl, "/v{version:[0-9.]+}")
+		}
+	}
+	var target map[string]string
+	for k, v := range vars {
+		if k == "version" {
+			continue
+		}
+		if target == nil {
+			target = make(map[string]string)
+		}
+		target[k] = v
+	}
+	return endpoint, target
+}
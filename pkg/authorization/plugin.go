@@ -20,6 +20,19 @@ type Plugin interface {
 	AuthZResponse(*Request) (*Response, error)
 }
 
+// PluginV2 is implemented by plugins that may use version 2 of the
+// authorization protocol
+type PluginV2 interface {
+	Plugin
+
+	// ProtocolVersion returns the version of the protocol the plugin uses
+	ProtocolVersion() (int, error)
+
+	// AuthZRequestV2 authorizes the request from the client to the daemon
+	// using version 2 of the protocol
+	AuthZRequestV2(*RequestV2) (*ResponseV2, error)
+}
+
 // newPlugins constructs and initializes the authorization plugins based on plugin names
 func newPlugins(names []string) []Plugin {
 	plugins := []Plugin{}
@@ -51,6 +64,7 @@ type authorizationPlugin struct {
 	initErr error
 	plugin  *plugins.Client
 	name    string
+	version int
 	once    sync.Once
 }
 
@@ -93,26 +107,61 @@ func (a *authorizationPlugin) AuthZResponse(authReq *Request) (*Response, error)
 	return authRes, nil
 }
 
+func (a *authorizationPlugin) ProtocolVersion() (int, error) {
+	if err := a.initPlugin(); err != nil {
+		return 0, err
+	}
+	if a.version == 2 {
+		return 2, nil
+	}
+	return 1, nil
+}
+
+func (a *authorizationPlugin) AuthZRequestV2(authReq *RequestV2) (*ResponseV2, error) {
+	if err := a.initPlugin(); err != nil {
+		return nil, err
+	}
+
+	authRes := &ResponseV2{}
+	if err := a.plugin.Call(AuthZApiRequestV2, authReq, authRes); err != nil {
+		return nil, err
+	}
+
+	return authRes, nil
+}
+
 // initPlugin initializes the authorization plugin if needed
 func (a *authorizationPlugin) initPlugin() error {
 	// Lazy loading of plugins
 	a.once.Do(func() {
 		if a.plugin == nil {
-			var plugin plugingetter.CompatPlugin
-			var e error
-
-			if pg := GetPluginGetter(); pg != nil {
-				plugin, e = pg.Get(a.name, AuthZApiImplements, plugingetter.Lookup)
-				a.SetName(plugin.Name())
+			// Prefer version 2 of the protocol if the plugin implements it
+			plugin, err := a.getPlugin(AuthZApiImplementsV2)
+			if err == nil {
+				a.version = 2
 			} else {
-				plugin, e = plugins.Get(a.name, AuthZApiImplements)
-			}
-			if e != nil {
-				a.initErr = e
-				return
+				plugin, err = a.getPlugin(AuthZApiImplements)
+				if err != nil {
+					a.initErr = err
+					return
+				}
+				a.version = 1
 			}
 			a.plugin = plugin.Client()
 		}
 	})
 	return a.initErr
 }
+
+func (a *authorizationPlugin) getPlugin(capability string) (plugingetter.CompatPlugin, error) {
+	pg := GetPluginGetter()
+	if pg == nil {
+		return plugins.Get(a.name, capability)
+	}
+	plugin, err := pg.Get(a.name, capability, plugingetter.Lookup)
+	if err != nil {
+		return nil, err
+	}
+	a.SetName(plugin.Name())
+	return plugin, nil
+}
@@ -46,6 +46,13 @@ func NewResponseModifier(rw http.ResponseWriter) ResponseModifier {
 	return &responseModifier{rw: rw, header: make(http.Header)}
 }
 
+// newScopedResponseModifier creates a ResponseModifier that buffers the whole
+// response, so that it can be filtered by scope before it is sent. Scoped
+// responses can't be streamed or hijacked.
+func newScopedResponseModifier(rw http.ResponseWriter) *responseModifier {
+	return &responseModifier{rw: rw, header: make(http.Header), scoped: true}
+}
+
 const maxBufferSize = 64 * 1024
 
 // responseModifier is used as an adapter to http.ResponseWriter in order to manipulate and explore
@@ -61,6 +68,10 @@ type responseModifier struct {
 	statusCode int
 	// hijacked indicates the request has been hijacked
 	hijacked bool
+	// scoped indicates the response is buffered until it is filtered by scope
+	scoped bool
+	// streamed indicates the handler tried to stream a scoped response
+	streamed bool
 }
 
 func (rm *responseModifier) Hijacked() bool {
@@ -119,6 +130,14 @@ func (rm *responseModifier) Write(b []byte) (int, error) {
 		return rm.rw.Write(b)
 	}
 
+	if rm.scoped {
+		if rm.streamed {
+			return 0, errScopedStream
+		}
+		rm.body = append(rm.body, b...)
+		return len(b), nil
+	}
+
 	if len(rm.body)+len(b) > maxBufferSize {
 		rm.Flush()
 	}
@@ -141,6 +160,10 @@ func (rm *responseModifier) RawHeaders() ([]byte, error) {
 
 // Hijack returns the internal connection of the wrapped http.ResponseWriter
 func (rm *responseModifier) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if rm.scoped {
+		rm.streamed = true
+		return nil, nil, errScopedStream
+	}
 	rm.hijacked = true
 	rm.FlushAll()
 
@@ -153,6 +176,10 @@ func (rm *responseModifier) Hijack() (net.Conn, *bufio.ReadWriter, error) {
 
 // Flush uses the internal flush API of the wrapped http.ResponseWriter
 func (rm *responseModifier) Flush() {
+	if rm.scoped {
+		rm.streamed = true
+		return
+	}
 	flusher, ok := rm.rw.(http.Flusher)
 	if !ok {
 		logrus.Error("Internal response writer doesn't support the Flusher interface")
@@ -0,0 +1,119 @@
+package authorization // import "github.com/docker/docker/pkg/authorization"
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+)
+
+// errOutOfScope is returned when a response about a single object is outside
+// the scope set by a plugin.
+var errOutOfScope = errors.New("object is outside the scope of the request")
+
+// errScopedStream is returned when a handler tries to stream or hijack a
+// response to which a scope applies.
+var errScopedStream = errors.New("streamed responses cannot be filtered by scope")
+
+// inScope reports whether an object with the given labels is in scope.
+func (s *Scope) inScope(labels map[string]string) bool {
+	for k, v := range s.Labels {
+		if l, ok := labels[k]; !ok || l != v {
+			return false
+		}
+	}
+	return true
+}
+
+// objectLabels returns the labels of an object in a response, and whether
+// the object has labels at all. Most list responses have them at the top
+// level, while inspect responses have them in the config or spec.
+func objectLabels(obj map[string]json.RawMessage) (map[string]string, bool, error) {
+	var labels map[string]string
+	if raw, ok := obj["Labels"]; ok {
+		err := json.Unmarshal(raw, &labels)
+		return labels, true, err
+	}
+	for _, k := range []string{"Config", "Spec"} {
+		if raw, ok := obj[k]; ok {
+			var v struct{ Labels map[string]string }
+			err := json.Unmarshal(raw, &v)
+			return v.Labels, true, err
+		}
+	}
+	return nil, false, nil
+}
+
+func inScopes(labels map[string]string, scopes []*Scope) bool {
+	for _, s := range scopes {
+		if !s.inScope(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+// filterList removes the objects outside the given scopes from a JSON array.
+func filterList(raw json.RawMessage, scopes []*Scope) (json.RawMessage, error) {
+	var items []map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &items); err != nil {
+		return nil, err
+	}
+	filtered := make([]map[string]json.RawMessage, 0, len(items))
+	for _, item := range items {
+		labels, _, err := objectLabels(item)
+		if err != nil {
+			return nil, err
+		}
+		if inScopes(labels, scopes) {
+			filtered = append(filtered, item)
+		}
+	}
+	return json.Marshal(filtered)
+}
+
+// filterByScope removes the objects outside the given scopes from a JSON
+// response body. If the body is a single object outside the scopes,
+// errOutOfScope is returned. Responses without labeled objects are returned
+// unmodified.
+func filterByScope(body []byte, scopes []*Scope) ([]byte, error) {
+	trimmed := bytes.TrimSpace(body)
+	if len(scopes) == 0 || len(trimmed) == 0 {
+		return body, nil
+	}
+
+	switch trimmed[0] {
+	case '[':
+		out, err := filterList(trimmed, scopes)
+		if err != nil {
+			return nil, err
+		}
+		return append(out, '\n'), nil
+	case '{':
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal(trimmed, &obj); err != nil {
+			return nil, err
+		}
+		labels, ok, err := objectLabels(obj)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			if !inScopes(labels, scopes) {
+				return nil, errOutOfScope
+			}
+			return body, nil
+		}
+		// The volume list is wrapped in an object.
+		if raw, ok := obj["Volumes"]; ok && len(raw) > 0 && raw[0] == '[' {
+			if obj["Volumes"], err = filterList(raw, scopes); err != nil {
+				return nil, err
+			}
+			out, err := json.Marshal(obj)
+			if err != nil {
+				return nil, err
+			}
+			return append(out, '\n'), nil
+		}
+	}
+	return body, nil
+}
@@ -0,0 +1,67 @@
+package cron // import "github.com/docker/docker/pkg/cron"
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestParseInvalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"@often",
+	} {
+		_, err := Parse(expr)
+		assert.Check(t, err != nil, "expected an error parsing %q", expr)
+	}
+}
+
+func TestNext(t *testing.T) {
+	base := time.Date(2023, time.March, 15, 10, 30, 45, 0, time.UTC) // a Wednesday
+	for _, tc := range []struct {
+		expr     string
+		expected time.Time
+	}{
+		{expr: "* * * * *", expected: time.Date(2023, time.March, 15, 10, 31, 0, 0, time.UTC)},
+		{expr: "*/15 * * * *", expected: time.Date(2023, time.March, 15, 10, 45, 0, 0, time.UTC)},
+		{expr: "0 * * * *", expected: time.Date(2023, time.March, 15, 11, 0, 0, 0, time.UTC)},
+		{expr: "@hourly", expected: time.Date(2023, time.March, 15, 11, 0, 0, 0, time.UTC)},
+		{expr: "@daily", expected: time.Date(2023, time.March, 16, 0, 0, 0, 0, time.UTC)},
+		{expr: "@weekly", expected: time.Date(2023, time.March, 19, 0, 0, 0, 0, time.UTC)},
+		{expr: "@monthly", expected: time.Date(2023, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		{expr: "@yearly", expected: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{expr: "30 9 * * mon-fri", expected: time.Date(2023, time.March, 16, 9, 30, 0, 0, time.UTC)},
+		{expr: "0 0 * * 7", expected: time.Date(2023, time.March, 19, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 12 1,15 * *", expected: time.Date(2023, time.March, 15, 12, 0, 0, 0, time.UTC)},
+		{expr: "0 0 29 feb *", expected: time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 8-18/4 * * *", expected: time.Date(2023, time.March, 15, 12, 0, 0, 0, time.UTC)},
+		// day of month and day of week are OR'ed when both are restricted
+		{expr: "0 0 1 * fri", expected: time.Date(2023, time.March, 17, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 30 2 *", expected: time.Time{}},
+	} {
+		t.Run(tc.expr, func(t *testing.T) {
+			s, err := Parse(tc.expr)
+			assert.NilError(t, err)
+			assert.Check(t, is.Equal(s.Next(base), tc.expected))
+		})
+	}
+}
+
+func TestNextIsAfter(t *testing.T) {
+	s, err := Parse("30 10 * * *")
+	assert.NilError(t, err)
+	at := time.Date(2023, time.March, 15, 10, 30, 0, 0, time.UTC)
+	assert.Check(t, is.Equal(s.Next(at), at.AddDate(0, 0, 1)))
+}
@@ -0,0 +1,84 @@
+package jsonmessage // import "github.com/docker/docker/pkg/jsonmessage"
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Types of the events of the v2 progress stream.
+const (
+	// ProgressEventStatus is a status update, of a layer if ID is set.
+	ProgressEventStatus = "status"
+	// ProgressEventProgress reports the bytes transferred for a layer.
+	ProgressEventProgress = "progress"
+	// ProgressEventRetry reports that the transfer of a layer failed, and
+	// is retried after Delay.
+	ProgressEventRetry = "retry"
+	// ProgressEventStream is a line of output, such as the output of a
+	// build step.
+	ProgressEventStream = "stream"
+	// ProgressEventAux carries auxiliary data, such as the result of a
+	// push or build.
+	ProgressEventAux = "aux"
+	// ProgressEventError reports the error the operation failed with.
+	ProgressEventError = "error"
+	// ProgressEventSummary is the last event of the stream.
+	ProgressEventSummary = "summary"
+)
+
+// ProgressEvent is an event of the v2 progress stream of pull, push, and
+// build, which unlike JSONMessage carries no human-oriented rendering of the
+// progress.
+type ProgressEvent struct {
+	Type   string `json:"type"`
+	ID     string `json:"id,omitempty"`
+	Status string `json:"status,omitempty"`
+
+	// Current and Total are the bytes of the action in Status for a
+	// progress event, and Percent is the percentage of Total done, if Total
+	// is known.
+	Current int64   `json:"current,omitempty"`
+	Total   int64   `json:"total,omitempty"`
+	Percent float64 `json:"percent,omitempty"`
+	// Overall is the progress of the transfer of all layers.
+	Overall *OverallProgress `json:"overall,omitempty"`
+
+	// Attempt is the number of the retry for a retry event, starting at 1.
+	Attempt int           `json:"attempt,omitempty"`
+	Delay   time.Duration `json:"delay,omitempty"`
+
+	Stream  string           `json:"stream,omitempty"`
+	Aux     *json.RawMessage `json:"aux,omitempty"`
+	Error   string           `json:"error,omitempty"`
+	Summary *ProgressSummary `json:"summary,omitempty"`
+}
+
+// OverallProgress is the progress of the transfer of all layers. Total only
+// includes the layers whose size is known, so it grows as transfers start.
+type OverallProgress struct {
+	Current int64   `json:"current"`
+	Total   int64   `json:"total"`
+	Percent float64 `json:"percent"`
+}
+
+// ProgressSummary summarizes the operation at the end of the v2 progress
+// stream.
+type ProgressSummary struct {
+	// Digest is the digest of the manifest pulled or pushed.
+	Digest string `json:"digest,omitempty"`
+	// ImageID is the ID of the image built.
+	ImageID string         `json:"imageID,omitempty"`
+	Layers  []LayerSummary `json:"layers,omitempty"`
+	// Bytes is the number of bytes transferred for all layers.
+	Bytes int64 `json:"bytes"`
+	// Error is the error the operation failed with, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// LayerSummary summarizes the transfer of a layer.
+type LayerSummary struct {
+	ID      string `json:"id"`
+	Status  string `json:"status,omitempty"`
+	Bytes   int64  `json:"bytes"`
+	Retries int    `json:"retries,omitempty"`
+}
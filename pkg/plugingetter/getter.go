@@ -50,3 +50,9 @@ type PluginGetter interface {
 	GetAllManagedPluginsByCap(capability string) []CompatPlugin
 	Handle(capability string, callback func(string, *plugins.Client))
 }
+
+// DisableHandler is implemented by plugin getters which notify subsystems
+// when a managed plugin is disabled.
+type DisableHandler interface {
+	HandleDisable(capability string, callback func(name string))
+}
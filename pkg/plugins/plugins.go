@@ -35,6 +35,11 @@ import (
 // ProtocolSchemeHTTPV1 is the name of the protocol used for interacting with plugins using this package.
 const ProtocolSchemeHTTPV1 = "moby.plugins.http/v1"
 
+// ProtocolSchemeGRPCV1 is the name of the protocol used by managed plugins
+// serving gRPC on their socket. The services they implement depend on their
+// capabilities.
+const ProtocolSchemeGRPCV1 = "moby.plugins.grpc/v1"
+
 var (
 	// ErrNotImplements is returned if the plugin does not implement the requested driver.
 	ErrNotImplements = errors.New("Plugin does not implement the requested driver")
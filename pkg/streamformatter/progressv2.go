@@ -0,0 +1,213 @@
+package streamformatter // import "github.com/docker/docker/pkg/streamformatter"
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/pkg/jsonmessage"
+)
+
+// completeStatuses are the statuses which report that the transfer of a
+// layer is complete.
+var completeStatuses = map[string]bool{
+	"Download complete":    true,
+	"Pull complete":        true,
+	"Already exists":       true,
+	"Pushed":               true,
+	"Layer already exists": true,
+}
+
+type layerProgress struct {
+	// action is the first action reported for the layer, which is the
+	// transfer. The progress of other actions, such as extraction, is not
+	// included in the overall progress.
+	action         string
+	current, total int64
+	status         string
+	retries        int
+}
+
+type progressV2Writer struct {
+	mu      sync.Mutex
+	out     io.Writer
+	buf     []byte
+	layers  map[string]*layerProgress
+	order   []string
+	summary jsonmessage.ProgressSummary
+	closed  bool
+}
+
+// NewProgressV2Writer returns a writer which translates the JSON messages
+// written to it into events of the v2 progress format, and writes them to out.
+// Close writes the summary of the operation, and does not close out.
+func NewProgressV2Writer(out io.Writer) io.WriteCloser {
+	return &progressV2Writer{out: out, layers: make(map[string]*layerProgress)}
+}
+
+func (w *progressV2Writer) Write(b []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf = append(w.buf, b...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		line := w.buf[:i]
+		w.buf = w.buf[i+1:]
+		if err := w.translate(line); err != nil {
+			return 0, err
+		}
+	}
+	return len(b), nil
+}
+
+// Close translates the last message if it was not terminated, and writes
+// the summary.
+func (w *progressV2Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if err := w.translate(w.buf); err != nil {
+		return err
+	}
+	w.buf = nil
+
+	summary := w.summary
+	for _, id := range w.order {
+		l := w.layers[id]
+		summary.Layers = append(summary.Layers, jsonmessage.LayerSummary{ID: id, Status: l.status, Bytes: l.current, Retries: l.retries})
+		summary.Bytes += l.current
+	}
+	return w.emit(jsonmessage.ProgressEvent{Type: jsonmessage.ProgressEventSummary, Summary: &summary})
+}
+
+func (w *progressV2Writer) translate(line []byte) error {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 {
+		return nil
+	}
+	var jm jsonmessage.JSONMessage
+	if err := json.Unmarshal(line, &jm); err != nil {
+		return w.emit(jsonmessage.ProgressEvent{Type: jsonmessage.ProgressEventStream, Stream: string(line)})
+	}
+
+	switch {
+	case jm.Error != nil:
+		w.summary.Error = jm.Error.Message
+		return w.emit(jsonmessage.ProgressEvent{Type: jsonmessage.ProgressEventError, Error: jm.Error.Message})
+	case jm.Aux != nil:
+		var result struct {
+			Digest string
+			ID     string
+		}
+		// Not all aux messages are objects, such as the BuildKit traces.
+		if json.Unmarshal(*jm.Aux, &result) == nil {
+			if result.Digest != "" {
+				w.summary.Digest = result.Digest
+			}
+			if result.ID != "" {
+				w.summary.ImageID = result.ID
+			}
+		}
+		return w.emit(jsonmessage.ProgressEvent{Type: jsonmessage.ProgressEventAux, ID: jm.ID, Aux: jm.Aux})
+	case jm.Stream != "":
+		return w.emit(jsonmessage.ProgressEvent{Type: jsonmessage.ProgressEventStream, ID: jm.ID, Stream: jm.Stream})
+	case jm.Progress != nil && (jm.Progress.Current != 0 || jm.Progress.Total != 0):
+		return w.progress(jm.ID, jm.Status, jm.Progress)
+	}
+	return w.status(jm.ID, jm.Status)
+}
+
+func (w *progressV2Writer) layer(id string) *layerProgress {
+	l, ok := w.layers[id]
+	if !ok {
+		l = &layerProgress{}
+		w.layers[id] = l
+		w.order = append(w.order, id)
+	}
+	return l
+}
+
+func (w *progressV2Writer) progress(id, action string, p *jsonmessage.JSONProgress) error {
+	ev := jsonmessage.ProgressEvent{Type: jsonmessage.ProgressEventProgress, ID: id, Status: action, Current: p.Current, Total: p.Total}
+	if p.Total > 0 {
+		ev.Percent = percent(p.Current, p.Total)
+	}
+	if id != "" && !p.HideCounts && p.Units == "" {
+		l := w.layer(id)
+		if l.action == "" {
+			l.action = action
+		}
+		if l.action == action {
+			l.current, l.total = p.Current, p.Total
+		}
+		l.status = action
+		ev.Overall = w.overall()
+	}
+	return w.emit(ev)
+}
+
+func (w *progressV2Writer) status(id, status string) error {
+	if strings.HasPrefix(status, "Digest: ") {
+		w.summary.Digest = strings.TrimPrefix(status, "Digest: ")
+	}
+	if id == "" {
+		return w.emit(jsonmessage.ProgressEvent{Type: jsonmessage.ProgressEventStatus, Status: status})
+	}
+
+	var delay int
+	if _, err := fmt.Sscanf(status, "Retrying in %d second", &delay); err == nil {
+		l := w.layer(id)
+		l.retries++
+		l.status = status
+		return w.emit(jsonmessage.ProgressEvent{Type: jsonmessage.ProgressEventRetry, ID: id, Status: status, Attempt: l.retries, Delay: time.Duration(delay) * time.Second})
+	}
+
+	ev := jsonmessage.ProgressEvent{Type: jsonmessage.ProgressEventStatus, ID: id, Status: status}
+	if completeStatuses[status] || strings.HasPrefix(status, "Mounted from ") {
+		l := w.layer(id)
+		l.current = l.total
+		l.status = status
+		ev.Overall = w.overall()
+	} else if l, ok := w.layers[id]; ok {
+		l.status = status
+	}
+	return w.emit(ev)
+}
+
+func (w *progressV2Writer) overall() *jsonmessage.OverallProgress {
+	o := &jsonmessage.OverallProgress{}
+	for _, l := range w.layers {
+		o.Current += l.current
+		o.Total += l.total
+	}
+	if o.Total > 0 {
+		o.Percent = percent(o.Current, o.Total)
+	}
+	return o
+}
+
+func percent(current, total int64) float64 {
+	if current >= total {
+		return 100
+	}
+	return float64(current*10000/total) / 100
+}
+
+func (w *progressV2Writer) emit(ev jsonmessage.ProgressEvent) error {
+	b, err := json.Marshal(&ev)
+	if err != nil {
+		return err
+	}
+	_, err = w.out.Write(appendNewline(b))
+	return err
+}
@@ -0,0 +1,129 @@
+package streamformatter // import "github.com/docker/docker/pkg/streamformatter"
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/docker/docker/pkg/progress"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func readProgressEvents(t *testing.T, r io.Reader) []jsonmessage.ProgressEvent {
+	t.Helper()
+	var events []jsonmessage.ProgressEvent
+	dec := json.NewDecoder(r)
+	for {
+		var ev jsonmessage.ProgressEvent
+		err := dec.Decode(&ev)
+		if err == io.EOF {
+			return events
+		}
+		assert.NilError(t, err)
+		events = append(events, ev)
+	}
+}
+
+func TestProgressV2WriterPull(t *testing.T) {
+	out := &bytes.Buffer{}
+	w := NewProgressV2Writer(out)
+	po := NewJSONProgressOutput(w, false)
+
+	progress.Message(po, "latest", "Pulling from library/busybox")
+	progress.Update(po, "aaa", "Pulling fs layer")
+	po.WriteProgress(progress.Progress{ID: "aaa", Action: "Downloading", Current: 50, Total: 100})
+	progress.Update(po, "aaa", "Retrying in 5 seconds")
+	po.WriteProgress(progress.Progress{ID: "aaa", Action: "Downloading", Current: 100, Total: 100})
+	progress.Update(po, "aaa", "Download complete")
+	po.WriteProgress(progress.Progress{ID: "aaa", Action: "Extracting", Current: 10, Total: 100})
+	progress.Update(po, "aaa", "Pull complete")
+	progress.Update(po, "bbb", "Already exists")
+	progress.Message(po, "", "Digest: sha256:1234")
+	assert.NilError(t, w.Close())
+
+	events := readProgressEvents(t, out)
+	assert.Assert(t, is.Len(events, 11))
+
+	assert.Check(t, is.DeepEqual(events[0], jsonmessage.ProgressEvent{Type: jsonmessage.ProgressEventStatus, ID: "latest", Status: "Pulling from library/busybox"}))
+	assert.Check(t, is.DeepEqual(events[2], jsonmessage.ProgressEvent{
+		Type:    jsonmessage.ProgressEventProgress,
+		ID:      "aaa",
+		Status:  "Downloading",
+		Current: 50,
+		Total:   100,
+		Percent: 50,
+		Overall: &jsonmessage.OverallProgress{Current: 50, Total: 100, Percent: 50},
+	}))
+	assert.Check(t, is.DeepEqual(events[3], jsonmessage.ProgressEvent{
+		Type:    jsonmessage.ProgressEventRetry,
+		ID:      "aaa",
+		Status:  "Retrying in 5 seconds",
+		Attempt: 1,
+		Delay:   5 * time.Second,
+	}))
+	// The extraction is not part of the overall progress.
+	assert.Check(t, is.DeepEqual(events[6].Overall, &jsonmessage.OverallProgress{Current: 100, Total: 100, Percent: 100}))
+	assert.Check(t, is.Equal(events[6].Percent, 10.0))
+
+	assert.Check(t, is.DeepEqual(events[10], jsonmessage.ProgressEvent{
+		Type: jsonmessage.ProgressEventSummary,
+		Summary: &jsonmessage.ProgressSummary{
+			Digest: "sha256:1234",
+			Layers: []jsonmessage.LayerSummary{
+				{ID: "aaa", Status: "Pull complete", Bytes: 100, Retries: 1},
+				{ID: "bbb", Status: "Already exists"},
+			},
+			Bytes: 100,
+		},
+	}))
+}
+
+func TestProgressV2WriterPushAndError(t *testing.T) {
+	out := &bytes.Buffer{}
+	w := NewProgressV2Writer(out)
+	po := NewJSONProgressOutput(w, false)
+
+	po.WriteProgress(progress.Progress{ID: "aaa", Action: "Pushing", Current: 30, Total: 40})
+	progress.Update(po, "aaa", "Pushed")
+	progress.Aux(po, map[string]interface{}{"Tag": "latest", "Digest": "sha256:5678", "Size": 42})
+	_, err := w.Write(FormatError(errors.New("something failed")))
+	assert.NilError(t, err)
+	assert.NilError(t, w.Close())
+	// Closing again writes no second summary.
+	assert.NilError(t, w.Close())
+
+	events := readProgressEvents(t, out)
+	assert.Assert(t, is.Len(events, 5))
+	assert.Check(t, is.Equal(events[2].Type, jsonmessage.ProgressEventAux))
+	assert.Check(t, is.DeepEqual(events[3], jsonmessage.ProgressEvent{Type: jsonmessage.ProgressEventError, Error: "something failed"}))
+	assert.Check(t, is.DeepEqual(events[4].Summary, &jsonmessage.ProgressSummary{
+		Digest: "sha256:5678",
+		Layers: []jsonmessage.LayerSummary{{ID: "aaa", Status: "Pushed", Bytes: 40}},
+		Bytes:  40,
+		Error:  "something failed",
+	}))
+}
+
+func TestProgressV2WriterPartialWrites(t *testing.T) {
+	out := &bytes.Buffer{}
+	w := NewProgressV2Writer(out)
+
+	// The last message is not terminated.
+	line := []byte(`{"stream":"Step 1/2 : FROM busybox\n"}` + streamNewline + `{"aux":{"ID":"sha256:abcd"}}`)
+	for _, b := range line {
+		_, err := w.Write([]byte{b})
+		assert.NilError(t, err)
+	}
+	assert.NilError(t, w.Close())
+
+	events := readProgressEvents(t, out)
+	assert.Assert(t, is.Len(events, 3))
+	assert.Check(t, is.DeepEqual(events[0], jsonmessage.ProgressEvent{Type: jsonmessage.ProgressEventStream, Stream: "Step 1/2 : FROM busybox\n"}))
+	assert.Check(t, is.Equal(events[1].Type, jsonmessage.ProgressEventAux))
+	assert.Check(t, is.Equal(events[2].Summary.ImageID, "sha256:abcd"))
+}
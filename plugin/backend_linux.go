@@ -60,7 +60,7 @@ func (pm *Manager) Disable(refOrID string, config *types.PluginDisableConfig) er
 	}
 
 	for _, typ := range p.GetTypes() {
-		if typ.Capability == authorization.AuthZApiImplements {
+		if typ.Capability == authorization.AuthZApiImplements || typ.Capability == authorization.AuthZApiImplementsV2 {
 			pm.config.AuthzMiddleware.RemovePlugin(p.Name())
 		}
 	}
@@ -96,7 +96,8 @@ func (pm *Manager) Inspect(refOrID string) (tp *types.Plugin, err error) {
 		return nil, err
 	}
 
-	return &p.PluginObj, nil
+	obj := p.Object()
+	return &obj, nil
 }
 
 func computePrivileges(c types.PluginConfig) types.PluginPrivileges {
@@ -213,10 +214,6 @@ func (pm *Manager) Upgrade(ctx context.Context, ref reference.Named, name string
 		return err
 	}
 
-	if p.IsEnabled() {
-		return errors.Wrap(enabledError(p.Name()), "plugin must be disabled before upgrading")
-	}
-
 	// revalidate because Pull is public
 	if _, err := reference.ParseNormalizedNamed(name); err != nil {
 		return errors.Wrapf(errdefs.InvalidParameter(err), "failed to parse %q", name)
@@ -245,7 +242,12 @@ func (pm *Manager) Upgrade(ctx context.Context, ref reference.Named, name string
 		return err
 	}
 
-	if err := pm.upgradePlugin(p, md.config, md.manifest, md.blobs, tmpRootFSDir, &privileges); err != nil {
+	if p.IsEnabled() {
+		if err := pm.upgradeEnabledPlugin(p, md.config, md.manifest, md.blobs, tmpRootFSDir, &privileges); err != nil {
+			return err
+		}
+		pm.config.LogPluginEvent(p.GetID(), p.Name(), "upgrade")
+	} else if err := pm.upgradePlugin(p, md.config, md.manifest, md.blobs, tmpRootFSDir, &privileges); err != nil {
 		return err
 	}
 	p.PluginObj.PluginReference = ref.String()
@@ -345,7 +347,7 @@ next:
 				}
 			}
 		}
-		out = append(out, p.PluginObj)
+		out = append(out, p.Object())
 	}
 	return out, nil
 }
@@ -18,14 +18,17 @@ type Store struct {
 	 * to the new model. Legacy plugins use Handle() for registering an
 	 * activation callback.*/
 	handlers map[string][]func(string, *plugins.Client)
+	// disableHandlers are called when a plugin is disabled.
+	disableHandlers map[string][]func(string)
 }
 
 // NewStore creates a Store.
 func NewStore() *Store {
 	return &Store{
-		plugins:  make(map[string]*v2.Plugin),
-		specOpts: make(map[string][]SpecOpt),
-		handlers: make(map[string][]func(string, *plugins.Client)),
+		plugins:         make(map[string]*v2.Plugin),
+		specOpts:        make(map[string][]SpecOpt),
+		handlers:        make(map[string][]func(string, *plugins.Client)),
+		disableHandlers: make(map[string][]func(string)),
 	}
 }
 
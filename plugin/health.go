@@ -0,0 +1,154 @@
+package plugin // import "github.com/docker/docker/plugin"
+
+import (
+	"net"
+	"syscall"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	v2 "github.com/docker/docker/plugin/v2"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultHealthInterval = 10 * time.Second
+	defaultHealthTimeout  = 5 * time.Second
+	defaultHealthRetries  = 3
+
+	minRestartBackoff = time.Second
+	maxRestartBackoff = time.Minute
+)
+
+// healthCheck configures the liveness probes of the plugins.
+type healthCheck struct {
+	interval time.Duration
+	timeout  time.Duration
+	retries  int
+}
+
+func defaultHealthCheck() healthCheck {
+	return healthCheck{
+		interval: defaultHealthInterval,
+		timeout:  defaultHealthTimeout,
+		retries:  defaultHealthRetries,
+	}
+}
+
+// restartBackoff returns how long to wait before restarting a plugin which
+// was already restarted the given number of times in a row.
+func restartBackoff(restarts int) time.Duration {
+	d := minRestartBackoff
+	for i := 0; i < restarts && d < maxRestartBackoff; i++ {
+		d *= 2
+	}
+	if d > maxRestartBackoff {
+		d = maxRestartBackoff
+	}
+	return d
+}
+
+// probePlugin checks that the socket of the plugin accepts connections
+// within timeout. No request is sent, as plugins are only expected to handle
+// the handshake at /Plugin.Activate once, when they are activated.
+func probePlugin(p *v2.Plugin, timeout time.Duration) error {
+	addr := p.Addr()
+	if addr == nil {
+		return errors.New("plugin has no address")
+	}
+	conn, err := net.DialTimeout(addr.Network(), addr.String(), timeout)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// monitorHealth probes the plugin until it exits. If the plugin fails enough
+// probes in a row, it's killed so that it's restarted.
+func (pm *Manager) monitorHealth(p *v2.Plugin, c *controller, exit <-chan bool) {
+	t := time.NewTicker(pm.healthCheck.interval)
+	defer t.Stop()
+
+	var failures int64
+	status := types.PluginStarting
+	for {
+		select {
+		case <-exit:
+			return
+		case <-t.C:
+		}
+
+		err := probePlugin(p, pm.healthCheck.timeout)
+		h := &types.PluginHealth{Status: status, Restarts: pm.restartCount(c)}
+		if err == nil {
+			failures = 0
+			h.Status = types.PluginHealthy
+		} else {
+			failures++
+			h.FailingStreak = failures
+			h.LastError = err.Error()
+			if failures >= int64(pm.healthCheck.retries) {
+				h.Status = types.PluginUnhealthy
+			}
+		}
+
+		select {
+		case <-exit:
+			// Don't report the probes failing because the plugin exited.
+			return
+		default:
+		}
+		p.SetHealth(h)
+		if h.Status != status {
+			status = h.Status
+			pm.config.LogPluginEvent(p.GetID(), p.Name(), "health_status: "+status)
+		}
+		if status == types.PluginUnhealthy {
+			logrus.WithError(err).WithField("plugin", p.Name()).Warn("plugin failed its health checks, restarting it")
+			if err := pm.executor.Signal(p.GetID(), syscall.SIGKILL); err != nil {
+				logrus.WithError(err).WithField("plugin", p.Name()).Error("failed to kill unhealthy plugin")
+			}
+			return
+		}
+	}
+}
+
+// restartCount returns the number of times the plugin was restarted in a row.
+func (pm *Manager) restartCount(c *controller) int64 {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+	return int64(c.restarts)
+}
+
+// restartPlugin enables the plugin again after it exited, after a delay which
+// grows each time the plugin is restarted without staying up for long.
+func (pm *Manager) restartPlugin(p *v2.Plugin, c *controller) {
+	pm.mu.Lock()
+	if time.Since(c.startedAt) > maxRestartBackoff {
+		c.restarts = 0
+	}
+	delay := restartBackoff(c.restarts)
+	c.restarts++
+	restarts := c.restarts
+	pm.mu.Unlock()
+
+	if h := p.Health(); h == nil || h.Status != types.PluginUnhealthy {
+		p.SetHealth(&types.PluginHealth{Status: types.PluginUnhealthy, Restarts: int64(restarts), LastError: "plugin exited"})
+		pm.config.LogPluginEvent(p.GetID(), p.Name(), "health_status: "+types.PluginUnhealthy)
+	}
+
+	logrus.WithField("plugin", p.Name()).Warnf("plugin exited, restarting it in %s", delay)
+	time.AfterFunc(delay, func() {
+		pm.mu.RLock()
+		restart := c.restart && pm.cMap[p] == c
+		pm.mu.RUnlock()
+		if !restart {
+			return
+		}
+		if err := pm.enable(p, c, true); err != nil {
+			logrus.WithError(err).WithField("plugin", p.Name()).Error("failed to restart plugin")
+			return
+		}
+		pm.config.LogPluginEvent(p.GetID(), p.Name(), "restart")
+	})
+}
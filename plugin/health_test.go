@@ -0,0 +1,115 @@
+package plugin // import "github.com/docker/docker/plugin"
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	v2 "github.com/docker/docker/plugin/v2"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestRestartBackoff(t *testing.T) {
+	assert.Check(t, is.Equal(restartBackoff(0), time.Second))
+	assert.Check(t, is.Equal(restartBackoff(1), 2*time.Second))
+	assert.Check(t, is.Equal(restartBackoff(3), 8*time.Second))
+	assert.Check(t, is.Equal(restartBackoff(6), time.Minute))
+	assert.Check(t, is.Equal(restartBackoff(100), time.Minute))
+}
+
+func newProbedPlugin(t *testing.T) (*v2.Plugin, net.Listener) {
+	dir, err := os.MkdirTemp("", "plugin-health")
+	assert.NilError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	l, err := net.Listen("unix", filepath.Join(dir, "plugin.sock"))
+	assert.NilError(t, err)
+	t.Cleanup(func() { l.Close() })
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	p := &v2.Plugin{PluginObj: types.Plugin{ID: "1234", Name: "health"}}
+	p.SetAddr(l.Addr())
+	return p, l
+}
+
+func TestProbePlugin(t *testing.T) {
+	p, l := newProbedPlugin(t)
+	assert.NilError(t, probePlugin(p, time.Second))
+
+	l.Close()
+	assert.Check(t, probePlugin(p, time.Second) != nil)
+}
+
+type signalExecutor struct {
+	Executor
+	signals chan syscall.Signal
+}
+
+func (e *signalExecutor) Signal(id string, signal syscall.Signal) error {
+	e.signals <- signal
+	return nil
+}
+
+func TestMonitorHealthKillsUnhealthyPlugin(t *testing.T) {
+	p, l := newProbedPlugin(t)
+
+	var (
+		mu     sync.Mutex
+		events []string
+	)
+	executor := &signalExecutor{signals: make(chan syscall.Signal, 1)}
+	pm := &Manager{
+		config: ManagerConfig{LogPluginEvent: func(_, _, action string) {
+			mu.Lock()
+			events = append(events, action)
+			mu.Unlock()
+		}},
+		executor:    executor,
+		healthCheck: healthCheck{interval: 10 * time.Millisecond, timeout: 100 * time.Millisecond, retries: 2},
+	}
+	exit := make(chan bool)
+	defer close(exit)
+	go pm.monitorHealth(p, &controller{}, exit)
+
+	poll := func(status string) {
+		t.Helper()
+		for i := 0; i < 100; i++ {
+			if h := p.Health(); h != nil && h.Status == status {
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Fatalf("plugin did not become %s: %+v", status, p.Health())
+	}
+	poll(types.PluginHealthy)
+
+	l.Close()
+	select {
+	case sig := <-executor.signals:
+		assert.Check(t, is.Equal(sig, syscall.SIGKILL))
+	case <-time.After(5 * time.Second):
+		t.Fatal("unhealthy plugin was not killed")
+	}
+	h := p.Health()
+	assert.Check(t, is.Equal(h.Status, types.PluginUnhealthy))
+	assert.Check(t, is.Equal(h.FailingStreak, int64(2)))
+	assert.Check(t, h.LastError != "")
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Check(t, is.DeepEqual(events, []string{"health_status: healthy", "health_status: unhealthy"}))
+}
@@ -12,6 +12,7 @@ import (
 	"strings"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/containerd/containerd/content"
 	"github.com/containerd/containerd/content/local"
@@ -79,6 +80,8 @@ type Manager struct {
 	blobStore content.Store
 	publisher *pubsub.Publisher
 	executor  Executor
+
+	healthCheck healthCheck
 }
 
 // controller represents the manager's control on a plugin.
@@ -86,12 +89,16 @@ type controller struct {
 	restart       bool
 	exitChan      chan bool
 	timeoutInSecs int
+	startedAt     time.Time // when the plugin was last started
+	restarts      int       // number of restarts since the plugin last stayed up
+	upgrading     bool      // the plugin is stopped to be upgraded in place
 }
 
 // NewManager returns a new plugin manager.
 func NewManager(config ManagerConfig) (*Manager, error) {
 	manager := &Manager{
-		config: config,
+		config:      config,
+		healthCheck: defaultHealthCheck(),
 	}
 	for _, dirName := range []string{manager.config.Root, manager.config.ExecRoot, manager.tmpDir()} {
 		if err := os.MkdirAll(dirName, 0700); err != nil {
@@ -140,12 +147,20 @@ func (pm *Manager) HandleExitEvent(id string) error {
 		close(c.exitChan)
 		c.exitChan = nil // ignore duplicate events (containerd issue #2299)
 	}
-	restart := c.restart
+	restart, upgrading := c.restart, c.upgrading
 	pm.mu.RUnlock()
 
 	if restart {
-		pm.enable(p, c, true)
-	} else if err := recursiveUnmount(filepath.Join(pm.config.Root, id)); err != nil {
+		pm.restartPlugin(p, c)
+		return nil
+	}
+	if upgrading {
+		// The upgrade unmounts the rootfs of the plugin itself, before
+		// replacing it.
+		return nil
+	}
+	p.SetHealth(nil)
+	if err := recursiveUnmount(filepath.Join(pm.config.Root, id)); err != nil {
 		return errors.Wrap(err, "error cleaning up plugin mounts")
 	}
 	return nil
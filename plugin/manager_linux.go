@@ -6,6 +6,7 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"reflect"
 	"time"
 
 	"github.com/containerd/containerd/content"
@@ -115,6 +116,13 @@ func (pm *Manager) pluginPostStart(p *v2.Plugin, c *controller) error {
 	pm.config.Store.SetState(p, true)
 	pm.config.Store.CallHandler(p)
 
+	pm.mu.Lock()
+	c.startedAt = time.Now()
+	restarts := c.restarts
+	pm.mu.Unlock()
+	p.SetHealth(&types.PluginHealth{Status: types.PluginStarting, Restarts: int64(restarts)})
+	go pm.monitorHealth(p, c, c.exitChan)
+
 	return pm.save(p)
 }
 
@@ -188,6 +196,8 @@ func (pm *Manager) disable(p *v2.Plugin, c *controller) error {
 	c.restart = false
 	shutdownPlugin(p, c.exitChan, pm.executor)
 	pm.config.Store.SetState(p, false)
+	pm.config.Store.CallDisableHandler(p)
+	p.SetHealth(nil)
 	return pm.save(p)
 }
 
@@ -266,6 +276,119 @@ func (pm *Manager) upgradePlugin(p *v2.Plugin, configDigest, manifestDigest dige
 	return errors.Wrap(err, "error saving upgraded plugin config")
 }
 
+// upgradeEnabledPlugin upgrades a running plugin without disabling it. The new
+// rootfs is staged next to the running one, and the plugin is restarted on it.
+// The socket is not handed off to the upgraded plugin: the plugin keeps its
+// socket address, so that requests made while it restarts are retried against
+// the upgraded plugin by the plugin clients, until they time out.
+func (pm *Manager) upgradeEnabledPlugin(p *v2.Plugin, configDigest, manifestDigest digest.Digest, blobsums []digest.Digest, tmpRootFSDir string, privileges *types.PluginPrivileges) (err error) {
+	defer func() {
+		if rmErr := os.RemoveAll(tmpRootFSDir); rmErr != nil && !os.IsNotExist(rmErr) {
+			logrus.WithError(rmErr).WithField("plugin", p.Name()).Errorf("error cleaning up plugin upgrade dir: %s", tmpRootFSDir)
+		}
+	}()
+
+	config, err := pm.setupNewPlugin(configDigest, privileges)
+	if err != nil {
+		return err
+	}
+	if err := validateLiveUpgrade(p.PluginObj.Config, config); err != nil {
+		return errors.Wrap(enabledError(p.Name()), err.Error())
+	}
+
+	pm.mu.Lock()
+	c := pm.cMap[p]
+	if c == nil {
+		pm.mu.Unlock()
+		return errors.Wrap(errDisabled(p.Name()), "plugin is not running")
+	}
+	c.restart = false
+	c.upgrading = true
+	pm.mu.Unlock()
+
+	defer func() {
+		pm.mu.Lock()
+		c.upgrading = false
+		pm.mu.Unlock()
+	}()
+
+	shutdownPlugin(p, c.exitChan, pm.executor)
+
+	orig := filepath.Join(pm.config.Root, p.PluginObj.ID, "rootfs")
+	backup := orig + "-old"
+	if err := mount.RecursiveUnmount(orig); err != nil {
+		return pm.restartAfterFailedUpgrade(p, c, errdefs.System(err))
+	}
+	if err := os.Rename(orig, backup); err != nil {
+		return pm.restartAfterFailedUpgrade(p, c, errors.Wrap(errdefs.System(err), "error backing up plugin data before upgrade"))
+	}
+	if err := os.Rename(tmpRootFSDir, orig); err != nil {
+		if mvErr := os.Rename(backup, orig); mvErr != nil {
+			return errors.Wrap(mvErr, "error restoring old plugin root on upgrade failure")
+		}
+		return pm.restartAfterFailedUpgrade(p, c, errors.Wrap(errdefs.System(err), "error upgrading"))
+	}
+
+	oldConfig, oldConfigDigest, oldManifest, oldBlobsums := p.PluginObj.Config, p.Config, p.Manifest, p.Blobsums
+	p.PluginObj.Config = config
+	p.Config = configDigest
+	p.Manifest = manifestDigest
+	p.Blobsums = blobsums
+
+	// enable saves the plugin config once the plugin is listening.
+	if err := pm.enable(p, c, true); err != nil {
+		p.PluginObj.Config, p.Config, p.Manifest, p.Blobsums = oldConfig, oldConfigDigest, oldManifest, oldBlobsums
+		if rmErr := os.RemoveAll(orig); rmErr != nil {
+			logrus.WithError(rmErr).WithField("dir", orig).Error("error cleaning up after failed upgrade")
+			return errors.Wrap(err, "error starting upgraded plugin")
+		}
+		if mvErr := os.Rename(backup, orig); mvErr != nil {
+			return errors.Wrap(mvErr, "error restoring old plugin root on upgrade failure")
+		}
+		return pm.restartAfterFailedUpgrade(p, c, errors.Wrap(err, "error starting upgraded plugin"))
+	}
+
+	if rmErr := os.RemoveAll(backup); rmErr != nil {
+		logrus.WithError(rmErr).WithField("dir", backup).Error("error cleaning up old plugin root after successful upgrade")
+	}
+	return nil
+}
+
+// restartAfterFailedUpgrade starts the plugin again on its previous rootfs
+// after it was stopped for an upgrade which failed, and returns err.
+func (pm *Manager) restartAfterFailedUpgrade(p *v2.Plugin, c *controller, err error) error {
+	if enableErr := pm.enable(p, c, true); enableErr != nil {
+		logrus.WithError(enableErr).WithField("plugin", p.Name()).Error("failed to restart plugin after failed upgrade")
+		pm.config.Store.SetState(p, false)
+		pm.config.Store.CallDisableHandler(p)
+		if saveErr := pm.save(p); saveErr != nil {
+			logrus.WithError(saveErr).WithField("plugin", p.Name()).Error("failed to save plugin state")
+		}
+	}
+	return err
+}
+
+// validateLiveUpgrade returns an error if a running plugin with the given
+// config cannot be upgraded without disabling it. The plugin must keep
+// providing the same interfaces on the same socket. Plugins propagating mounts
+// cannot be upgraded in place, as the upgraded plugin would not know about the
+// mounts made by the previous one, which containers may still be using.
+func validateLiveUpgrade(current, upgraded types.PluginConfig) error {
+	if current.PropagatedMount != "" {
+		return errors.New("plugin must be disabled before upgrading, as it propagates mounts")
+	}
+	if current.Interface.Socket != upgraded.Interface.Socket || current.Interface.ProtocolScheme != upgraded.Interface.ProtocolScheme {
+		return errors.New("plugin must be disabled before upgrading to a version using another socket")
+	}
+	if !reflect.DeepEqual(current.Interface.Types, upgraded.Interface.Types) {
+		return errors.New("plugin must be disabled before upgrading to a version implementing other interfaces")
+	}
+	if upgraded.PropagatedMount != "" {
+		return errors.New("plugin must be disabled before upgrading to a version propagating mounts")
+	}
+	return nil
+}
+
 func (pm *Manager) setupNewPlugin(configDigest digest.Digest, privileges *types.PluginPrivileges) (types.PluginConfig, error) {
 	configRA, err := pm.blobStore.ReaderAt(context.TODO(), specs.Descriptor{Digest: configDigest})
 	if err != nil {
@@ -268,3 +268,40 @@ func listenTestPlugin(sockAddr string, exit chan struct{}) (net.Listener, error)
 	}()
 	return l, nil
 }
+
+func TestValidateLiveUpgrade(t *testing.T) {
+	current := types.PluginConfig{
+		Interface: types.PluginConfigInterface{
+			Socket: "plugin.sock",
+			Types:  []types.PluginInterfaceType{{Capability: "volumedriver", Prefix: "docker", Version: "1.0"}},
+		},
+	}
+	upgraded := current
+	upgraded.Description = "upgraded"
+	if err := validateLiveUpgrade(current, upgraded); err != nil {
+		t.Fatal(err)
+	}
+
+	upgraded = current
+	upgraded.Interface.Socket = "other.sock"
+	if err := validateLiveUpgrade(current, upgraded); err == nil {
+		t.Fatal("expected an error when the socket changes")
+	}
+
+	upgraded = current
+	upgraded.Interface.Types = append([]types.PluginInterfaceType{{Capability: "networkdriver", Prefix: "docker", Version: "1.0"}}, current.Interface.Types...)
+	if err := validateLiveUpgrade(current, upgraded); err == nil {
+		t.Fatal("expected an error when the interfaces change")
+	}
+
+	upgraded = current
+	upgraded.PropagatedMount = "/data"
+	if err := validateLiveUpgrade(current, upgraded); err == nil {
+		t.Fatal("expected an error when the upgraded plugin propagates mounts")
+	}
+
+	current.PropagatedMount = "/data"
+	if err := validateLiveUpgrade(current, current); err == nil {
+		t.Fatal("expected an error when the plugin propagates mounts")
+	}
+}
@@ -230,6 +230,17 @@ func (ps *Store) Handle(capability string, callback func(string, *plugins.Client
 	}
 }
 
+// HandleDisable sets a callback for a given capability, which is called when
+// a plugin with this capability is disabled. It is used by network and ipam
+// drivers to release the resources of the driver of the plugin.
+func (ps *Store) HandleDisable(capability string, callback func(string)) {
+	typ := pluginType(capability)
+
+	ps.Lock()
+	ps.disableHandlers[typ] = append(ps.disableHandlers[typ], callback)
+	ps.Unlock()
+}
+
 // RegisterRuntimeOpt stores a list of SpecOpts for the provided capability.
 // These options are applied to the runtime spec before a plugin is started for the specified capability.
 func (ps *Store) RegisterRuntimeOpt(cap string, opts ...SpecOpt) {
@@ -248,6 +259,18 @@ func (ps *Store) CallHandler(p *v2.Plugin) {
 	}
 }
 
+// CallDisableHandler calls the registered disable callbacks. It is invoked
+// during plugin disable.
+func (ps *Store) CallDisableHandler(p *v2.Plugin) {
+	ps.RLock()
+	defer ps.RUnlock()
+	for _, typ := range p.GetTypes() {
+		for _, handler := range ps.disableHandlers[typ.String()] {
+			handler(p.Name())
+		}
+	}
+}
+
 // resolvePluginID must be protected by ps.RLock
 func (ps *Store) resolvePluginID(idOrName string) (string, error) {
 	if validFullID.MatchString(idOrName) {
@@ -62,3 +62,25 @@ func TestStoreGetPluginNotMatchCapRefs(t *testing.T) {
 		t.Fatalf("reference count should be 0, got: %d", refs)
 	}
 }
+
+func TestStoreCallDisableHandler(t *testing.T) {
+	s := NewStore()
+	p := v2.Plugin{PluginObj: types.Plugin{Name: "test:latest"}}
+
+	iType := types.PluginInterfaceType{Capability: "networkdriver", Prefix: "docker", Version: "1.0"}
+	i := types.PluginConfigInterface{Socket: "plugins.sock", Types: []types.PluginInterfaceType{iType}}
+	p.PluginObj.Config.Interface = i
+
+	var disabled []string
+	s.HandleDisable("NetworkDriver", func(name string) {
+		disabled = append(disabled, name)
+	})
+	s.HandleDisable("IpamDriver", func(name string) {
+		t.Fatalf("unexpected call of the disable handler of another capability for %s", name)
+	})
+
+	s.CallDisableHandler(&p)
+	if len(disabled) != 1 || disabled[0] != "test:latest" {
+		t.Fatalf("expected the disable handler to be called for test:latest, got: %v", disabled)
+	}
+}
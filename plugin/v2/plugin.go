@@ -32,6 +32,7 @@ type Plugin struct {
 	SwarmServiceID string
 	timeout        time.Duration
 	addr           net.Addr
+	health         *types.PluginHealth
 }
 
 const defaultPluginRuntimeDestination = "/run/docker/plugins"
@@ -305,6 +306,39 @@ func (p *Plugin) SetAddr(addr net.Addr) {
 	p.mu.Unlock()
 }
 
+// Health returns a copy of the health of the plugin, or nil if the plugin is
+// not running.
+func (p *Plugin) Health() *types.PluginHealth {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.health == nil {
+		return nil
+	}
+	h := *p.health
+	return &h
+}
+
+// SetHealth sets the health of the plugin. It is cleared when h is nil.
+func (p *Plugin) SetHealth(h *types.PluginHealth) {
+	p.mu.Lock()
+	p.health = h
+	p.mu.Unlock()
+}
+
+// Object returns a copy of the plugin object, including its health.
+func (p *Plugin) Object() types.Plugin {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	obj := p.PluginObj
+	if p.health != nil {
+		h := *p.health
+		obj.Health = &h
+	}
+	return obj
+}
+
 // Protocol is the protocol that should be used for interacting with the plugin.
 func (p *Plugin) Protocol() string {
 	if p.PluginObj.Config.Interface.ProtocolScheme != "" {
@@ -2,12 +2,11 @@
 // +build linux,!exclude_disk_quota,cgo
 
 //
-// projectquota.go - implements XFS project quota controls
+// projectquota.go - implements project quota controls
 // for setting quota limits on a newly created directory.
-// It currently supports the legacy XFS specific ioctls.
-//
-// TODO: use generic quota control ioctl FS_IOC_FS{GET,SET}XATTR
-//       for both xfs/ext4 for kernel version >= v4.5
+// It uses the XFS quotactl commands, which the kernel also
+// supports for ext4 filesystems that have project quotas
+// enabled (mounted with "prjquota").
 //
 
 package quota // import "github.com/docker/docker/quota"
@@ -273,6 +272,31 @@ func (q *Control) GetQuota(targetPath string, quota *Quota) error {
 	return nil
 }
 
+// GetUsage - get the number of bytes used by a directory that was configured
+// with SetQuota
+func (q *Control) GetUsage(targetPath string) (uint64, error) {
+	q.RLock()
+	projectID, ok := q.quotas[targetPath]
+	q.RUnlock()
+	if !ok {
+		return 0, errors.Errorf("quota not found for path: %s", targetPath)
+	}
+
+	var d C.fs_disk_quota_t
+
+	var cs = C.CString(q.backingFsBlockDev)
+	defer C.free(unsafe.Pointer(cs))
+
+	_, _, errno := unix.Syscall6(unix.SYS_QUOTACTL, C.Q_XGETPQUOTA,
+		uintptr(unsafe.Pointer(cs)), uintptr(C.__u32(projectID)),
+		uintptr(unsafe.Pointer(&d)), 0, 0)
+	if errno != 0 {
+		return 0, errors.Wrapf(errno, "Failed to get quota usage for projid %d on %s",
+			projectID, q.backingFsBlockDev)
+	}
+	return uint64(d.d_bcount) * 512, nil
+}
+
 // getProjectID - get the project id of path on xfs
 func getProjectID(targetPath string) (uint32, error) {
 	dir, err := openDir(targetPath)
@@ -17,6 +17,9 @@ type ServiceOptions struct {
 	AllowNondistributableArtifacts []string `json:"allow-nondistributable-artifacts,omitempty"`
 	Mirrors                        []string `json:"registry-mirrors,omitempty"`
 	InsecureRegistries             []string `json:"insecure-registries,omitempty"`
+	// CredentialHelpers are the credential helpers of the registries, by
+	// hostname.
+	CredentialHelpers map[string]CredentialHelper `json:"registry-credential-helpers,omitempty"`
 }
 
 // serviceConfig holds daemon configuration for the registry service.
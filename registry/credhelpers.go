@@ -0,0 +1,195 @@
+package registry // import "github.com/docker/docker/registry"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types/registry"
+	"github.com/pkg/errors"
+)
+
+const (
+	// defaultCredentialCacheTTL is how long the credentials of a credential
+	// helper are cached if its CacheTTL is not set.
+	defaultCredentialCacheTTL = 10 * time.Minute
+
+	credentialHelperTimeout = 30 * time.Second
+
+	// credentialsNotFound is the error a credential helper outputs if it
+	// has no credentials for the registry.
+	credentialsNotFound = "credentials not found in native keychain"
+)
+
+// CredentialHelper configures the credential helper which the daemon invokes
+// to get the credentials of a registry for the pulls which were given none,
+// such as the pulls of swarm tasks.
+type CredentialHelper struct {
+	// Helper is the suffix of the docker-credential-<helper> binary, such
+	// as "ecr-login" or "gcr".
+	Helper string `json:"helper"`
+	// CacheTTL is how long the credentials are cached before the helper is
+	// invoked again, such as "1h". It defaults to 10 minutes.
+	CacheTTL string `json:"cache-ttl,omitempty"`
+}
+
+func (h CredentialHelper) cacheTTL() time.Duration {
+	if ttl, err := time.ParseDuration(h.CacheTTL); err == nil && ttl > 0 {
+		return ttl
+	}
+	return defaultCredentialCacheTTL
+}
+
+// ValidateCredentialHelpers validates the credential helpers configured for
+// the registries.
+func ValidateCredentialHelpers(helpers map[string]CredentialHelper) error {
+	for hostname, h := range helpers {
+		if hasScheme(hostname) {
+			return invalidParamf("registry %s of credential helper should not contain '://'", hostname)
+		}
+		if err := validateHostPort(hostname); err != nil {
+			return err
+		}
+		if h.Helper == "" || strings.ContainsAny(h.Helper, `/\`) {
+			return invalidParamf("invalid credential helper %q for registry %s", h.Helper, hostname)
+		}
+		if h.CacheTTL != "" {
+			if ttl, err := time.ParseDuration(h.CacheTTL); err != nil || ttl < 0 {
+				return invalidParamf("invalid cache TTL %q of credential helper for registry %s", h.CacheTTL, hostname)
+			}
+		}
+	}
+	return nil
+}
+
+type cachedCredentials struct {
+	authConfig *registry.AuthConfig
+	expires    time.Time
+}
+
+// credentialHelpers gets the credentials of registries from their credential
+// helpers, and caches them.
+type credentialHelpers struct {
+	mu      sync.Mutex
+	helpers map[string]CredentialHelper
+	cache   map[string]cachedCredentials
+
+	// get invokes the helper, and is replaced in tests.
+	get func(ctx context.Context, helper, serverURL string) (*registry.AuthConfig, error)
+	now func() time.Time
+}
+
+func newCredentialHelpers() *credentialHelpers {
+	return &credentialHelpers{
+		cache: make(map[string]cachedCredentials),
+		get:   execCredentialHelper,
+		now:   time.Now,
+	}
+}
+
+// load replaces the configured helpers, and drops the cached credentials.
+func (c *credentialHelpers) load(helpers map[string]CredentialHelper) error {
+	if err := ValidateCredentialHelpers(helpers); err != nil {
+		return err
+	}
+	normalized := make(map[string]CredentialHelper, len(helpers))
+	for hostname, h := range helpers {
+		normalized[normalizeHelperHostname(hostname)] = h
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.helpers = normalized
+	c.cache = make(map[string]cachedCredentials)
+	return nil
+}
+
+// authConfig returns the credentials of the registry, or nil if no helper is
+// configured for it or the helper has no credentials for it.
+func (c *credentialHelpers) authConfig(ctx context.Context, hostname string) (*registry.AuthConfig, error) {
+	hostname = normalizeHelperHostname(hostname)
+	c.mu.Lock()
+	h, ok := c.helpers[hostname]
+	cached, isCached := c.cache[hostname]
+	c.mu.Unlock()
+	if !ok {
+		return nil, nil
+	}
+	if isCached && c.now().Before(cached.expires) {
+		return cached.authConfig, nil
+	}
+
+	serverURL := hostname
+	if hostname == IndexName {
+		serverURL = IndexServer
+	}
+	ctx, cancel := context.WithTimeout(ctx, credentialHelperTimeout)
+	defer cancel()
+	authConfig, err := c.get(ctx, h.Helper, serverURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "credential helper %s failed for registry %s", h.Helper, hostname)
+	}
+
+	c.mu.Lock()
+	// Do not cache the credentials if the helpers were reloaded meanwhile.
+	if current, ok := c.helpers[hostname]; ok && current == h {
+		c.cache[hostname] = cachedCredentials{authConfig: authConfig, expires: c.now().Add(h.cacheTTL())}
+	}
+	c.mu.Unlock()
+	return authConfig, nil
+}
+
+// normalizeHelperHostname returns the name under which the helper of the
+// registry is configured, which is docker.io for Docker Hub.
+func normalizeHelperHostname(hostname string) string {
+	switch hostname {
+	case IndexHostname, DefaultRegistryHost:
+		return IndexName
+	}
+	return hostname
+}
+
+// execCredentialHelper gets the credentials of the registry from the
+// docker-credential-<helper> binary, using the protocol of
+// github.com/docker/docker-credential-helpers.
+func execCredentialHelper(ctx context.Context, helper, serverURL string) (*registry.AuthConfig, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "docker-credential-"+helper, "get")
+	cmd.Stdin = strings.NewReader(serverURL)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		// Helpers write their errors to stdout.
+		msg := strings.TrimSpace(stdout.String())
+		if msg == credentialsNotFound {
+			return nil, nil
+		}
+		if msg == "" {
+			msg = strings.TrimSpace(stderr.String())
+		}
+		if msg != "" {
+			return nil, errors.Wrap(err, msg)
+		}
+		return nil, err
+	}
+
+	var creds struct {
+		Username string
+		Secret   string
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &creds); err != nil {
+		return nil, errors.Wrap(err, "invalid output")
+	}
+	authConfig := &registry.AuthConfig{ServerAddress: serverURL}
+	// Identity tokens are returned with the username "<token>".
+	if creds.Username == "<token>" {
+		authConfig.IdentityToken = creds.Secret
+	} else {
+		authConfig.Username = creds.Username
+		authConfig.Password = creds.Secret
+	}
+	return authConfig, nil
+}
@@ -0,0 +1,84 @@
+package registry // import "github.com/docker/docker/registry"
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/registry"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestCredentialHelpersCache(t *testing.T) {
+	now := time.Now()
+	var calls []string
+	c := newCredentialHelpers()
+	c.now = func() time.Time { return now }
+	c.get = func(_ context.Context, helper, serverURL string) (*registry.AuthConfig, error) {
+		calls = append(calls, helper+" "+serverURL)
+		return &registry.AuthConfig{ServerAddress: serverURL, Username: "user", Password: helper}, nil
+	}
+	assert.NilError(t, c.load(map[string]CredentialHelper{
+		"docker.io":        {Helper: "hub"},
+		"example.com:5000": {Helper: "example", CacheTTL: "1h"},
+	}))
+
+	ctx := context.Background()
+	ac, err := c.authConfig(ctx, DefaultRegistryHost)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(ac, &registry.AuthConfig{ServerAddress: IndexServer, Username: "user", Password: "hub"}))
+
+	_, err = c.authConfig(ctx, "example.com:5000")
+	assert.NilError(t, err)
+	_, err = c.authConfig(ctx, "example.com:5000")
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(calls, []string{"hub " + IndexServer, "example example.com:5000"}))
+
+	// The credentials of docker.io expire after the default TTL, but not
+	// those of example.com:5000.
+	now = now.Add(defaultCredentialCacheTTL + time.Second)
+	_, err = c.authConfig(ctx, IndexName)
+	assert.NilError(t, err)
+	_, err = c.authConfig(ctx, "example.com:5000")
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(calls, 3))
+
+	ac, err = c.authConfig(ctx, "other.example.com")
+	assert.NilError(t, err)
+	assert.Check(t, is.Nil(ac))
+
+	// Reloading drops the cached credentials.
+	assert.NilError(t, c.load(map[string]CredentialHelper{"example.com:5000": {Helper: "example"}}))
+	_, err = c.authConfig(ctx, "example.com:5000")
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(calls, 4))
+}
+
+func TestValidateCredentialHelpers(t *testing.T) {
+	for _, tc := range []struct {
+		helpers     map[string]CredentialHelper
+		expectedErr string
+	}{
+		{helpers: map[string]CredentialHelper{"123.dkr.ecr.us-east-1.amazonaws.com": {Helper: "ecr-login", CacheTTL: "6h"}}},
+		{
+			helpers:     map[string]CredentialHelper{"https://gcr.io": {Helper: "gcr"}},
+			expectedErr: "registry https://gcr.io of credential helper should not contain '://'",
+		},
+		{
+			helpers:     map[string]CredentialHelper{"gcr.io": {}},
+			expectedErr: `invalid credential helper "" for registry gcr.io`,
+		},
+		{
+			helpers:     map[string]CredentialHelper{"gcr.io": {Helper: "gcr", CacheTTL: "-1m"}},
+			expectedErr: `invalid cache TTL "-1m" of credential helper for registry gcr.io`,
+		},
+	} {
+		err := ValidateCredentialHelpers(tc.helpers)
+		if tc.expectedErr == "" {
+			assert.Check(t, err)
+		} else {
+			assert.Check(t, is.Error(err, tc.expectedErr))
+		}
+	}
+}
@@ -26,22 +26,28 @@ type Service interface {
 	LoadAllowNondistributableArtifacts([]string) error
 	LoadMirrors([]string) error
 	LoadInsecureRegistries([]string) error
+	LoadCredentialHelpers(map[string]CredentialHelper) error
 	IsInsecureRegistry(string) bool
+	HelperAuthConfig(ctx context.Context, hostname string) (*registry.AuthConfig, error)
 }
 
 // defaultService is a registry service. It tracks configuration data such as a list
 // of mirrors.
 type defaultService struct {
-	config *serviceConfig
-	mu     sync.RWMutex
+	config      *serviceConfig
+	mu          sync.RWMutex
+	credHelpers *credentialHelpers
 }
 
 // NewService returns a new instance of defaultService ready to be
 // installed into an engine.
 func NewService(options ServiceOptions) (Service, error) {
 	config, err := newServiceConfig(options)
-
-	return &defaultService{config: config}, err
+	s := &defaultService{config: config, credHelpers: newCredentialHelpers()}
+	if err == nil {
+		err = s.credHelpers.load(options.CredentialHelpers)
+	}
+	return s, err
 }
 
 // ServiceConfig returns a copy of the public registry service's configuration.
@@ -75,6 +81,19 @@ func (s *defaultService) LoadInsecureRegistries(registries []string) error {
 	return s.config.loadInsecureRegistries(registries)
 }
 
+// LoadCredentialHelpers loads the credential helpers of the registries for
+// Service, and drops the credentials cached from the previous helpers.
+func (s *defaultService) LoadCredentialHelpers(helpers map[string]CredentialHelper) error {
+	return s.credHelpers.load(helpers)
+}
+
+// HelperAuthConfig returns the credentials of the registry with the given
+// hostname from its credential helper, or nil if no credential helper is
+// configured for the registry, or if the helper has no credentials for it.
+func (s *defaultService) HelperAuthConfig(ctx context.Context, hostname string) (*registry.AuthConfig, error) {
+	return s.credHelpers.authConfig(ctx, hostname)
+}
+
 // Auth contacts the public registry with the provided credentials,
 // and returns OK if authentication was successful.
 // It can be used to verify the validity of a client's credentials.
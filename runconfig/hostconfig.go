@@ -36,32 +36,41 @@ func validateNetContainerMode(c *container.Config, hc *container.HostConfig) err
 			return validationError("Invalid network mode: invalid container format container:<name|id>")
 		}
 	}
+	if parts[0] == "group" {
+		if len(parts) < 2 || parts[1] == "" {
+			return validationError("Invalid network mode: invalid group format group:<name|id>")
+		}
+	}
+
+	// Members of a group share the network namespace of the group, like
+	// containers sharing the network namespace of another container.
+	shared := hc.NetworkMode.IsContainer() || hc.NetworkMode.IsGroup()
 
-	if hc.NetworkMode.IsContainer() && c.Hostname != "" {
+	if shared && c.Hostname != "" {
 		return ErrConflictNetworkHostname
 	}
 
-	if hc.NetworkMode.IsContainer() && len(hc.Links) > 0 {
+	if shared && len(hc.Links) > 0 {
 		return ErrConflictContainerNetworkAndLinks
 	}
 
-	if hc.NetworkMode.IsContainer() && len(hc.DNS) > 0 {
+	if shared && len(hc.DNS) > 0 {
 		return ErrConflictNetworkAndDNS
 	}
 
-	if hc.NetworkMode.IsContainer() && len(hc.ExtraHosts) > 0 {
+	if shared && len(hc.ExtraHosts) > 0 {
 		return ErrConflictNetworkHosts
 	}
 
-	if (hc.NetworkMode.IsContainer() || hc.NetworkMode.IsHost()) && c.MacAddress != "" {
+	if (shared || hc.NetworkMode.IsHost()) && c.MacAddress != "" {
 		return ErrConflictContainerNetworkAndMac
 	}
 
-	if hc.NetworkMode.IsContainer() && (len(hc.PortBindings) > 0 || hc.PublishAllPorts) {
+	if shared && (len(hc.PortBindings) > 0 || hc.PublishAllPorts) {
 		return ErrConflictNetworkPublishPorts
 	}
 
-	if hc.NetworkMode.IsContainer() && len(c.ExposedPorts) > 0 {
+	if shared && len(c.ExposedPorts) > 0 {
 		return ErrConflictNetworkExposePorts
 	}
 	return nil
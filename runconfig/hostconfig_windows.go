@@ -27,6 +27,9 @@ func validateNetMode(c *container.Config, hc *container.HostConfig) error {
 	if hc.NetworkMode.IsContainer() && hc.Isolation.IsHyperV() {
 		return fmt.Errorf("Using the network stack of another container is not supported while using Hyper-V Containers")
 	}
+	if hc.NetworkMode.IsGroup() {
+		return fmt.Errorf("Using the network stack of a container group is not supported on Windows")
+	}
 	return nil
 }
 
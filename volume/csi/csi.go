@@ -0,0 +1,685 @@
+// Package csi provides a volume driver that uses a Container Storage
+// Interface (CSI) plugin to provision and mount named volumes on a daemon
+// that is not part of a swarm.
+package csi // import "github.com/docker/docker/volume/csi"
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	csispec "github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/docker/docker/daemon/names"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/ioutils"
+	"github.com/docker/docker/volume"
+	units "github.com/docker/go-units"
+	"github.com/moby/locker"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// rpcTimeout is the timeout for each call made to the plugin.
+	rpcTimeout = time.Minute
+
+	metadataFileName = "volume.json"
+	mountsFileName   = "mounts.json"
+	stagingPathName  = "staged"
+	publishPathName  = "published"
+)
+
+// Driver options that are handled by the driver itself. Any other option is
+// passed to the plugin as a parameter of the volume.
+const (
+	// OptSize is the requested capacity of the volume, for example "10G".
+	OptSize = "size"
+	// OptFsType is the filesystem type the volume is formatted with.
+	OptFsType = "fs-type"
+	// OptMountFlags is a comma-separated list of mount flags.
+	OptMountFlags = "mount-flags"
+)
+
+// ErrNotFound is returned when the requested volume does not exist.
+var ErrNotFound = errors.New("volume not found")
+
+// Driver is a volume.Driver backed by a CSI plugin. Volumes are created with
+// the plugin's controller service if it provides one, and are made available
+// to containers using the plugin's node service.
+type Driver struct {
+	name    string
+	address string
+	root    string
+
+	// locks serializes the operations on each volume, which call the
+	// plugin, while mu only protects the volumes map.
+	locks   *locker.Locker
+	mu      sync.Mutex
+	volumes map[string]*csiVolume
+
+	connMu     sync.Mutex
+	cc         *grpc.ClientConn
+	identity   csispec.IdentityClient
+	node       csispec.NodeClient
+	controller csispec.ControllerClient
+	caps       capabilities
+}
+
+type capabilities struct {
+	controller   bool
+	createDelete bool
+	snapshots    bool
+	staging      bool
+	volumeStats  bool
+	topology     []map[string]string
+}
+
+// New returns a Driver with the given name that talks to the CSI plugin
+// listening on address. Volume metadata and mountpoints are kept in a
+// directory named after the driver below root.
+//
+// The plugin is not contacted until it is first needed, so it may start
+// after the daemon.
+func New(name, address, root string) (*Driver, error) {
+	d := &Driver{
+		name:    name,
+		address: normalizeAddress(address),
+		root:    filepath.Join(root, name),
+		locks:   &locker.Locker{},
+		volumes: make(map[string]*csiVolume),
+	}
+	if err := os.MkdirAll(d.root, 0o701); err != nil {
+		return nil, err
+	}
+
+	dirs, err := os.ReadDir(d.root)
+	if err != nil {
+		return nil, err
+	}
+	for _, dir := range dirs {
+		if !dir.IsDir() {
+			continue
+		}
+		v := &csiVolume{driver: d, rootPath: filepath.Join(d.root, dir.Name())}
+		if err := v.load(); err != nil {
+			logrus.WithError(err).WithField("driver", name).WithField("volume", dir.Name()).Warn("failed to load CSI volume metadata")
+			continue
+		}
+		d.volumes[v.info.Name] = v
+	}
+	return d, nil
+}
+
+// normalizeAddress returns a gRPC target for a plugin address. The address
+// is either the path of a unix socket, or a "unix://" URL.
+func normalizeAddress(address string) string {
+	if strings.HasPrefix(address, "unix://") {
+		return address
+	}
+	return "unix://" + address
+}
+
+// Name returns the name of the driver.
+func (d *Driver) Name() string {
+	return d.name
+}
+
+// Scope returns the scope of the driver. Volumes are only known to the local
+// daemon, even if the storage they refer to is shared.
+func (d *Driver) Scope() string {
+	return volume.LocalScope
+}
+
+// connect sets up the connection to the plugin, and queries its
+// capabilities. It is a no-op if the driver is already connected.
+func (d *Driver) connect(ctx context.Context) error {
+	d.connMu.Lock()
+	defer d.connMu.Unlock()
+	if d.cc != nil {
+		return nil
+	}
+
+	// even though this is a unix socket, we must set WithInsecure or the
+	// connection will not be allowed.
+	cc, err := grpc.DialContext(ctx, d.address, grpc.WithInsecure())
+	if err != nil {
+		return errors.Wrapf(err, "error connecting to CSI plugin %s", d.name)
+	}
+	identity := csispec.NewIdentityClient(cc)
+	node := csispec.NewNodeClient(cc)
+	controller := csispec.NewControllerClient(cc)
+
+	caps, err := queryCapabilities(ctx, identity, node, controller)
+	if err != nil {
+		cc.Close()
+		return errors.Wrapf(err, "error querying CSI plugin %s", d.name)
+	}
+
+	d.cc = cc
+	d.identity = identity
+	d.node = node
+	d.controller = controller
+	d.caps = caps
+	return nil
+}
+
+func queryCapabilities(ctx context.Context, identity csispec.IdentityClient, node csispec.NodeClient, controller csispec.ControllerClient) (capabilities, error) {
+	var caps capabilities
+
+	probe, err := identity.Probe(ctx, &csispec.ProbeRequest{})
+	if err != nil {
+		return caps, err
+	}
+	if probe.Ready != nil && !probe.Ready.Value {
+		return caps, status.Error(codes.FailedPrecondition, "plugin is not ready")
+	}
+
+	pluginCaps, err := identity.GetPluginCapabilities(ctx, &csispec.GetPluginCapabilitiesRequest{})
+	if err != nil {
+		return caps, err
+	}
+	for _, c := range pluginCaps.GetCapabilities() {
+		if c.GetService().GetType() == csispec.PluginCapability_Service_CONTROLLER_SERVICE {
+			caps.controller = true
+		}
+	}
+
+	nodeCaps, err := node.NodeGetCapabilities(ctx, &csispec.NodeGetCapabilitiesRequest{})
+	if err != nil {
+		return caps, err
+	}
+	for _, c := range nodeCaps.GetCapabilities() {
+		switch c.GetRpc().GetType() {
+		case csispec.NodeServiceCapability_RPC_STAGE_UNSTAGE_VOLUME:
+			caps.staging = true
+		case csispec.NodeServiceCapability_RPC_GET_VOLUME_STATS:
+			caps.volumeStats = true
+		}
+	}
+
+	info, err := node.NodeGetInfo(ctx, &csispec.NodeGetInfoRequest{})
+	if err != nil {
+		return caps, err
+	}
+	if t := info.GetAccessibleTopology(); t != nil && len(t.Segments) > 0 {
+		caps.topology = []map[string]string{t.Segments}
+	}
+
+	if caps.controller {
+		resp, err := controller.ControllerGetCapabilities(ctx, &csispec.ControllerGetCapabilitiesRequest{})
+		if err != nil {
+			return caps, err
+		}
+		for _, c := range resp.GetCapabilities() {
+			switch c.GetRpc().GetType() {
+			case csispec.ControllerServiceCapability_RPC_CREATE_DELETE_VOLUME:
+				caps.createDelete = true
+			case csispec.ControllerServiceCapability_RPC_CREATE_DELETE_SNAPSHOT:
+				caps.snapshots = true
+			}
+		}
+	}
+	return caps, nil
+}
+
+// Create creates a volume with the given name. If the plugin can provision
+// volumes, the volume is created with the plugin's controller service.
+// Otherwise the volume is expected to already exist in the storage backend
+// and the name is used as its ID.
+func (d *Driver) Create(name string, opts map[string]string) (volume.Volume, error) {
+	// the name is used as the name of the metadata directory of the volume
+	if !names.RestrictedNamePattern.MatchString(name) {
+		return nil, errdefs.InvalidParameter(errors.Errorf("%q includes invalid characters for a CSI volume name, only %q are allowed", name, names.RestrictedNameChars))
+	}
+
+	d.locks.Lock(name)
+	defer d.locks.Unlock(name)
+
+	d.mu.Lock()
+	v, ok := d.volumes[name]
+	d.mu.Unlock()
+	if ok {
+		return v, nil
+	}
+
+	info, err := parseOpts(name, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+	defer cancel()
+	if err := d.connect(ctx); err != nil {
+		return nil, errdefs.Unavailable(err)
+	}
+
+	if d.caps.createDelete {
+		req := &csispec.CreateVolumeRequest{
+			Name:               name,
+			VolumeCapabilities: []*csispec.VolumeCapability{info.capability()},
+			Parameters:         info.Parameters,
+		}
+		if info.CapacityBytes > 0 {
+			req.CapacityRange = &csispec.CapacityRange{RequiredBytes: info.CapacityBytes}
+		}
+		if len(d.caps.topology) > 0 {
+			req.AccessibilityRequirements = &csispec.TopologyRequirement{
+				Requisite: toTopology(d.caps.topology),
+				Preferred: toTopology(d.caps.topology),
+			}
+		}
+		resp, err := d.controller.CreateVolume(ctx, req)
+		if err != nil {
+			return nil, errdefs.System(errors.Wrapf(err, "error creating volume %s with CSI plugin %s", name, d.name))
+		}
+		info.VolumeID = resp.GetVolume().GetVolumeId()
+		info.Context = resp.GetVolume().GetVolumeContext()
+		if c := resp.GetVolume().GetCapacityBytes(); c > 0 {
+			info.CapacityBytes = c
+		}
+		info.Topology = fromTopology(resp.GetVolume().GetAccessibleTopology())
+	} else {
+		info.VolumeID = name
+		info.Context = info.Parameters
+		info.Topology = d.caps.topology
+	}
+
+	v = &csiVolume{driver: d, rootPath: filepath.Join(d.root, name), info: info, mounts: map[string]int{}}
+	if err := os.MkdirAll(v.rootPath, 0o701); err != nil {
+		return nil, errdefs.System(err)
+	}
+	if err := v.save(); err != nil {
+		os.RemoveAll(v.rootPath)
+		return nil, errdefs.System(err)
+	}
+	d.mu.Lock()
+	d.volumes[name] = v
+	d.mu.Unlock()
+	return v, nil
+}
+
+// Remove removes the volume. If the plugin provisioned the volume, it is
+// deleted from the storage backend.
+func (d *Driver) Remove(v volume.Volume) error {
+	cv, ok := v.(*csiVolume)
+	if !ok {
+		return errdefs.System(errors.Errorf("unknown volume type %T", v))
+	}
+	d.locks.Lock(cv.info.Name)
+	defer d.locks.Unlock(cv.info.Name)
+
+	cv.mu.Lock()
+	active := len(cv.mounts)
+	cv.mu.Unlock()
+	if active > 0 {
+		return errdefs.Conflict(errors.New("volume has active mounts"))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+	defer cancel()
+	if err := d.connect(ctx); err != nil {
+		return errdefs.Unavailable(err)
+	}
+	if d.caps.createDelete {
+		if _, err := d.controller.DeleteVolume(ctx, &csispec.DeleteVolumeRequest{VolumeId: cv.info.VolumeID}); err != nil && status.Code(err) != codes.NotFound {
+			return errdefs.System(errors.Wrapf(err, "error deleting volume %s with CSI plugin %s", cv.info.Name, d.name))
+		}
+	}
+
+	d.mu.Lock()
+	delete(d.volumes, cv.info.Name)
+	d.mu.Unlock()
+	if err := os.RemoveAll(cv.rootPath); err != nil {
+		return errdefs.System(err)
+	}
+	return nil
+}
+
+// List returns all volumes created with the driver.
+func (d *Driver) List() ([]volume.Volume, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	ls := make([]volume.Volume, 0, len(d.volumes))
+	for _, v := range d.volumes {
+		ls = append(ls, v)
+	}
+	return ls, nil
+}
+
+// Get returns the volume with the given name.
+func (d *Driver) Get(name string) (volume.Volume, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	v, ok := d.volumes[name]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return v, nil
+}
+
+// volumeInfo is the metadata of a volume that is persisted on disk.
+type volumeInfo struct {
+	Name          string
+	VolumeID      string
+	CreatedAt     time.Time
+	Options       map[string]string
+	Parameters    map[string]string       `json:",omitempty"`
+	Context       map[string]string       `json:",omitempty"`
+	CapacityBytes int64                   `json:",omitempty"`
+	Topology      []map[string]string     `json:",omitempty"`
+	FsType        string                  `json:",omitempty"`
+	MountFlags    []string                `json:",omitempty"`
+	Snapshots     map[string]snapshotInfo `json:",omitempty"`
+}
+
+func parseOpts(name string, opts map[string]string) (volumeInfo, error) {
+	info := volumeInfo{
+		Name:       name,
+		CreatedAt:  time.Now(),
+		Options:    opts,
+		Parameters: map[string]string{},
+	}
+	for k, v := range opts {
+		switch k {
+		case OptSize:
+			size, err := units.RAMInBytes(v)
+			if err != nil {
+				return info, errdefs.InvalidParameter(errors.Wrapf(err, "invalid %s option", OptSize))
+			}
+			info.CapacityBytes = size
+		case OptFsType:
+			info.FsType = v
+		case OptMountFlags:
+			for _, f := range strings.Split(v, ",") {
+				if f = strings.TrimSpace(f); f != "" {
+					info.MountFlags = append(info.MountFlags, f)
+				}
+			}
+		default:
+			info.Parameters[k] = v
+		}
+	}
+	return info, nil
+}
+
+func (i volumeInfo) capability() *csispec.VolumeCapability {
+	return &csispec.VolumeCapability{
+		AccessType: &csispec.VolumeCapability_Mount{
+			Mount: &csispec.VolumeCapability_MountVolume{
+				FsType:     i.FsType,
+				MountFlags: i.MountFlags,
+			},
+		},
+		AccessMode: &csispec.VolumeCapability_AccessMode{
+			Mode: csispec.VolumeCapability_AccessMode_SINGLE_NODE_WRITER,
+		},
+	}
+}
+
+func toTopology(segments []map[string]string) []*csispec.Topology {
+	var t []*csispec.Topology
+	for _, s := range segments {
+		t = append(t, &csispec.Topology{Segments: s})
+	}
+	return t
+}
+
+func fromTopology(t []*csispec.Topology) []map[string]string {
+	var segments []map[string]string
+	for _, s := range t {
+		if len(s.GetSegments()) > 0 {
+			segments = append(segments, s.GetSegments())
+		}
+	}
+	return segments
+}
+
+// csiVolume is a volume managed by a CSI plugin.
+type csiVolume struct {
+	driver   *Driver
+	rootPath string
+	info     volumeInfo
+
+	// mounts counts the references to the volume by mount ID. It is
+	// persisted, so that the volume stays published for the containers
+	// kept running across a restart of the daemon, and is unpublished when
+	// the daemon releases their mounts.
+	mu     sync.Mutex
+	mounts map[string]int
+}
+
+func (v *csiVolume) load() error {
+	b, err := os.ReadFile(filepath.Join(v.rootPath, metadataFileName))
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, &v.info); err != nil {
+		return err
+	}
+	v.mounts = map[string]int{}
+	b, err = os.ReadFile(filepath.Join(v.rootPath, mountsFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	return json.Unmarshal(b, &v.mounts)
+}
+
+func (v *csiVolume) save() error {
+	b, err := json.Marshal(v.info)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(v.rootPath, metadataFileName), b, 0o600)
+}
+
+// saveMounts persists the references to the volume. It must be called with
+// v.mu held.
+func (v *csiVolume) saveMounts() error {
+	b, err := json.Marshal(v.mounts)
+	if err != nil {
+		return err
+	}
+	return ioutils.AtomicWriteFile(filepath.Join(v.rootPath, mountsFileName), b, 0o600)
+}
+
+// Name returns the name of the volume.
+func (v *csiVolume) Name() string {
+	return v.info.Name
+}
+
+// DriverName returns the name of the driver the volume belongs to.
+func (v *csiVolume) DriverName() string {
+	return v.driver.name
+}
+
+// Path returns the path the volume is published at.
+func (v *csiVolume) Path() string {
+	return filepath.Join(v.rootPath, publishPathName)
+}
+
+func (v *csiVolume) stagingPath() string {
+	return filepath.Join(v.rootPath, stagingPathName)
+}
+
+// CreatedAt returns the time the volume was created.
+func (v *csiVolume) CreatedAt() (time.Time, error) {
+	return v.info.CreatedAt, nil
+}
+
+// Mount stages and publishes the volume on the first reference, and returns
+// the path it is published at.
+func (v *csiVolume) Mount(id string) (string, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+	defer cancel()
+	first := len(v.mounts) == 0
+	if first {
+		if err := v.publish(ctx); err != nil {
+			return "", err
+		}
+	}
+	v.mounts[id]++
+	if err := v.saveMounts(); err != nil {
+		v.mounts[id]--
+		if v.mounts[id] == 0 {
+			delete(v.mounts, id)
+		}
+		if first {
+			v.unpublish(ctx)
+		}
+		return "", errdefs.System(errors.Wrapf(err, "error saving the mounts of volume %s", v.info.Name))
+	}
+	return v.Path(), nil
+}
+
+func (v *csiVolume) publish(ctx context.Context) error {
+	d := v.driver
+	if err := d.connect(ctx); err != nil {
+		return errdefs.Unavailable(err)
+	}
+
+	capability := v.info.capability()
+	if d.caps.staging {
+		if err := os.MkdirAll(v.stagingPath(), 0o700); err != nil {
+			return errdefs.System(err)
+		}
+		_, err := d.node.NodeStageVolume(ctx, &csispec.NodeStageVolumeRequest{
+			VolumeId:          v.info.VolumeID,
+			StagingTargetPath: v.stagingPath(),
+			VolumeCapability:  capability,
+			VolumeContext:     v.info.Context,
+		})
+		if err != nil {
+			return errdefs.System(errors.Wrapf(err, "error staging volume %s", v.info.Name))
+		}
+	}
+
+	req := &csispec.NodePublishVolumeRequest{
+		VolumeId:         v.info.VolumeID,
+		TargetPath:       v.Path(),
+		VolumeCapability: capability,
+		VolumeContext:    v.info.Context,
+	}
+	if d.caps.staging {
+		req.StagingTargetPath = v.stagingPath()
+	}
+	if _, err := d.node.NodePublishVolume(ctx, req); err != nil {
+		if d.caps.staging {
+			v.unstage(ctx)
+		}
+		return errdefs.System(errors.Wrapf(err, "error publishing volume %s", v.info.Name))
+	}
+	return nil
+}
+
+// Unmount releases a reference to the volume. The volume is unpublished and
+// unstaged when the last reference is released.
+func (v *csiVolume) Unmount(id string) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.mounts[id] == 0 {
+		return nil
+	}
+	v.mounts[id]--
+	if v.mounts[id] == 0 {
+		delete(v.mounts, id)
+	}
+	if err := v.saveMounts(); err != nil {
+		logrus.WithError(err).WithField("volume", v.info.Name).Warn("failed to save the mounts of CSI volume")
+	}
+	if len(v.mounts) > 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+	defer cancel()
+	return v.unpublish(ctx)
+}
+
+// unpublish unpublishes and unstages the volume.
+func (v *csiVolume) unpublish(ctx context.Context) error {
+	d := v.driver
+	if err := d.connect(ctx); err != nil {
+		return errdefs.Unavailable(err)
+	}
+	if _, err := d.node.NodeUnpublishVolume(ctx, &csispec.NodeUnpublishVolumeRequest{
+		VolumeId:   v.info.VolumeID,
+		TargetPath: v.Path(),
+	}); err != nil {
+		return errdefs.System(errors.Wrapf(err, "error unpublishing volume %s", v.info.Name))
+	}
+	if d.caps.staging {
+		return v.unstage(ctx)
+	}
+	return nil
+}
+
+func (v *csiVolume) unstage(ctx context.Context) error {
+	if _, err := v.driver.node.NodeUnstageVolume(ctx, &csispec.NodeUnstageVolumeRequest{
+		VolumeId:          v.info.VolumeID,
+		StagingTargetPath: v.stagingPath(),
+	}); err != nil {
+		return errdefs.System(errors.Wrapf(err, "error unstaging volume %s", v.info.Name))
+	}
+	return nil
+}
+
+// Status returns the CSI volume ID, capacity, and accessible topology of the
+// volume. If the volume is in use and the plugin reports volume statistics,
+// the used and available bytes are included as well.
+func (v *csiVolume) Status() map[string]interface{} {
+	s := map[string]interface{}{
+		"VolumeID": v.info.VolumeID,
+	}
+	if v.info.CapacityBytes > 0 {
+		s["CapacityBytes"] = v.info.CapacityBytes
+	}
+	if len(v.info.Topology) > 0 {
+		s["AccessibleTopology"] = v.info.Topology
+	}
+
+	v.mu.Lock()
+	active := len(v.mounts)
+	v.mu.Unlock()
+	v.driver.connMu.Lock()
+	caps, node := v.driver.caps, v.driver.node
+	v.driver.connMu.Unlock()
+	if active == 0 || node == nil || !caps.volumeStats {
+		return s
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+	defer cancel()
+	resp, err := node.NodeGetVolumeStats(ctx, &csispec.NodeGetVolumeStatsRequest{
+		VolumeId:   v.info.VolumeID,
+		VolumePath: v.Path(),
+	})
+	if err != nil {
+		logrus.WithError(err).WithField("volume", v.info.Name).Debug("failed to get CSI volume stats")
+		return s
+	}
+	for _, u := range resp.GetUsage() {
+		if u.GetUnit() != csispec.VolumeUsage_BYTES {
+			continue
+		}
+		s["UsedBytes"] = u.GetUsed()
+		s["AvailableBytes"] = u.GetAvailable()
+		if u.GetTotal() > 0 {
+			s["CapacityBytes"] = u.GetTotal()
+		}
+	}
+	return s
+}
@@ -0,0 +1,254 @@
+package csi // import "github.com/docker/docker/volume/csi"
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	csispec "github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/docker/docker/errdefs"
+	"google.golang.org/grpc"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+type fakePlugin struct {
+	csispec.UnimplementedIdentityServer
+	csispec.UnimplementedNodeServer
+	csispec.UnimplementedControllerServer
+
+	mu      sync.Mutex
+	started chan struct{}
+	block   chan struct{}
+	created []string
+	deleted []string
+	staged  []string
+	publish []string
+}
+
+func (p *fakePlugin) Probe(context.Context, *csispec.ProbeRequest) (*csispec.ProbeResponse, error) {
+	return &csispec.ProbeResponse{}, nil
+}
+
+func (p *fakePlugin) GetPluginCapabilities(context.Context, *csispec.GetPluginCapabilitiesRequest) (*csispec.GetPluginCapabilitiesResponse, error) {
+	return &csispec.GetPluginCapabilitiesResponse{
+		Capabilities: []*csispec.PluginCapability{{
+			Type: &csispec.PluginCapability_Service_{
+				Service: &csispec.PluginCapability_Service{Type: csispec.PluginCapability_Service_CONTROLLER_SERVICE},
+			},
+		}},
+	}, nil
+}
+
+func (p *fakePlugin) ControllerGetCapabilities(context.Context, *csispec.ControllerGetCapabilitiesRequest) (*csispec.ControllerGetCapabilitiesResponse, error) {
+	return &csispec.ControllerGetCapabilitiesResponse{
+		Capabilities: []*csispec.ControllerServiceCapability{{
+			Type: &csispec.ControllerServiceCapability_Rpc{
+				Rpc: &csispec.ControllerServiceCapability_RPC{Type: csispec.ControllerServiceCapability_RPC_CREATE_DELETE_VOLUME},
+			},
+		}},
+	}, nil
+}
+
+func (p *fakePlugin) CreateVolume(_ context.Context, req *csispec.CreateVolumeRequest) (*csispec.CreateVolumeResponse, error) {
+	if p.block != nil {
+		p.started <- struct{}{}
+		<-p.block
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.created = append(p.created, req.Name)
+	return &csispec.CreateVolumeResponse{
+		Volume: &csispec.Volume{
+			VolumeId:           "id-" + req.Name,
+			CapacityBytes:      req.GetCapacityRange().GetRequiredBytes(),
+			AccessibleTopology: req.GetAccessibilityRequirements().GetPreferred(),
+		},
+	}, nil
+}
+
+func (p *fakePlugin) DeleteVolume(_ context.Context, req *csispec.DeleteVolumeRequest) (*csispec.DeleteVolumeResponse, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.deleted = append(p.deleted, req.VolumeId)
+	return &csispec.DeleteVolumeResponse{}, nil
+}
+
+func (p *fakePlugin) NodeGetCapabilities(context.Context, *csispec.NodeGetCapabilitiesRequest) (*csispec.NodeGetCapabilitiesResponse, error) {
+	return &csispec.NodeGetCapabilitiesResponse{
+		Capabilities: []*csispec.NodeServiceCapability{{
+			Type: &csispec.NodeServiceCapability_Rpc{
+				Rpc: &csispec.NodeServiceCapability_RPC{Type: csispec.NodeServiceCapability_RPC_STAGE_UNSTAGE_VOLUME},
+			},
+		}},
+	}, nil
+}
+
+func (p *fakePlugin) NodeGetInfo(context.Context, *csispec.NodeGetInfoRequest) (*csispec.NodeGetInfoResponse, error) {
+	return &csispec.NodeGetInfoResponse{
+		NodeId:             "node1",
+		AccessibleTopology: &csispec.Topology{Segments: map[string]string{"zone": "a"}},
+	}, nil
+}
+
+func (p *fakePlugin) NodeStageVolume(_ context.Context, req *csispec.NodeStageVolumeRequest) (*csispec.NodeStageVolumeResponse, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.staged = append(p.staged, req.VolumeId)
+	return &csispec.NodeStageVolumeResponse{}, nil
+}
+
+func (p *fakePlugin) NodeUnstageVolume(_ context.Context, req *csispec.NodeUnstageVolumeRequest) (*csispec.NodeUnstageVolumeResponse, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.staged = remove(p.staged, req.VolumeId)
+	return &csispec.NodeUnstageVolumeResponse{}, nil
+}
+
+func (p *fakePlugin) NodePublishVolume(_ context.Context, req *csispec.NodePublishVolumeRequest) (*csispec.NodePublishVolumeResponse, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.publish = append(p.publish, req.TargetPath)
+	return &csispec.NodePublishVolumeResponse{}, nil
+}
+
+func (p *fakePlugin) NodeUnpublishVolume(_ context.Context, req *csispec.NodeUnpublishVolumeRequest) (*csispec.NodeUnpublishVolumeResponse, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.publish = remove(p.publish, req.TargetPath)
+	return &csispec.NodeUnpublishVolumeResponse{}, nil
+}
+
+func remove(s []string, v string) []string {
+	var out []string
+	for _, x := range s {
+		if x != v {
+			out = append(out, x)
+		}
+	}
+	return out
+}
+
+func startFakePlugin(t *testing.T) (*fakePlugin, string) {
+	t.Helper()
+	sock := filepath.Join(t.TempDir(), "csi.sock")
+	l, err := net.Listen("unix", sock)
+	assert.NilError(t, err)
+
+	p := &fakePlugin{}
+	srv := grpc.NewServer()
+	csispec.RegisterIdentityServer(srv, p)
+	csispec.RegisterNodeServer(srv, p)
+	csispec.RegisterControllerServer(srv, p)
+	go srv.Serve(l)
+	t.Cleanup(srv.Stop)
+	return p, sock
+}
+
+func TestDriverLifecycle(t *testing.T) {
+	p, sock := startFakePlugin(t)
+	root := t.TempDir()
+
+	d, err := New("fake", sock, root)
+	assert.NilError(t, err)
+
+	_, err = d.Create("../vol1", nil)
+	assert.Check(t, errdefs.IsInvalidParameter(err))
+
+	v, err := d.Create("vol1", map[string]string{OptSize: "1G", "tier": "fast"})
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(p.created, []string{"vol1"}))
+
+	s := v.Status()
+	assert.Check(t, is.Equal(s["VolumeID"], "id-vol1"))
+	assert.Check(t, is.Equal(s["CapacityBytes"], int64(1<<30)))
+	assert.Check(t, is.DeepEqual(s["AccessibleTopology"], []map[string]string{{"zone": "a"}}))
+
+	path, err := v.Mount("ctr1")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(path, filepath.Join(root, "fake", "vol1", publishPathName)))
+	_, err = v.Mount("ctr2")
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(p.staged, []string{"id-vol1"}))
+	assert.Check(t, is.Len(p.publish, 1))
+
+	assert.Check(t, d.Remove(v) != nil, "expected removing a mounted volume to fail")
+
+	assert.NilError(t, v.Unmount("ctr1"))
+	assert.Check(t, is.Len(p.publish, 1))
+	assert.NilError(t, v.Unmount("ctr2"))
+	assert.Check(t, is.Len(p.publish, 0))
+	assert.Check(t, is.Len(p.staged, 0))
+
+	// volumes are restored from disk
+	d2, err := New("fake", "unix://"+sock, root)
+	assert.NilError(t, err)
+	v2, err := d2.Get("vol1")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(v2.Status()["VolumeID"], "id-vol1"))
+
+	assert.NilError(t, d2.Remove(v2))
+	assert.Check(t, is.DeepEqual(p.deleted, []string{"id-vol1"}))
+	ls, err := d2.List()
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(ls, 0))
+}
+
+func TestDriverRestoreMounts(t *testing.T) {
+	p, sock := startFakePlugin(t)
+	root := t.TempDir()
+
+	d, err := New("fake", sock, root)
+	assert.NilError(t, err)
+	v, err := d.Create("vol1", nil)
+	assert.NilError(t, err)
+	_, err = v.Mount("ctr1")
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(p.publish, 1))
+
+	// the references of the volume are restored with it
+	d2, err := New("fake", sock, root)
+	assert.NilError(t, err)
+	v2, err := d2.Get("vol1")
+	assert.NilError(t, err)
+	assert.Check(t, errdefs.IsConflict(d2.Remove(v2)))
+
+	_, err = v2.Mount("ctr2")
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(p.publish, 1))
+	assert.NilError(t, v2.Unmount("ctr2"))
+	assert.Check(t, is.Len(p.publish, 1))
+	assert.NilError(t, v2.Unmount("ctr1"))
+	assert.Check(t, is.Len(p.publish, 0))
+	assert.NilError(t, d2.Remove(v2))
+}
+
+func TestDriverCreateUnlocked(t *testing.T) {
+	p, sock := startFakePlugin(t)
+	p.started = make(chan struct{})
+	p.block = make(chan struct{})
+
+	d, err := New("fake", sock, t.TempDir())
+	assert.NilError(t, err)
+
+	created := make(chan error)
+	go func() {
+		_, err := d.Create("vol1", nil)
+		created <- err
+	}()
+
+	// the driver is not locked while the plugin creates the volume
+	<-p.started
+	_, err = d.Get("vol1")
+	assert.Check(t, is.ErrorIs(err, ErrNotFound))
+	ls, err := d.List()
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(ls, 0))
+
+	close(p.block)
+	assert.NilError(t, <-created)
+	_, err = d.Get("vol1")
+	assert.NilError(t, err)
+}
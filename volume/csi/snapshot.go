@@ -0,0 +1,127 @@
+package csi // import "github.com/docker/docker/volume/csi"
+
+import (
+	"context"
+	"time"
+
+	csispec "github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/volume"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// snapshotInfo is the metadata of a snapshot taken with the plugin's
+// controller service.
+type snapshotInfo struct {
+	SnapshotID string
+	CreatedAt  time.Time
+	Size       int64
+}
+
+// snapshotVolume returns the volume if the plugin supports snapshots.
+func (d *Driver) snapshotVolume(ctx context.Context, vol volume.Volume) (*csiVolume, error) {
+	v, ok := vol.(*csiVolume)
+	if !ok {
+		return nil, errdefs.System(errors.Errorf("unknown volume type %T", vol))
+	}
+	if err := d.connect(ctx); err != nil {
+		return nil, errdefs.Unavailable(err)
+	}
+	if !d.caps.snapshots {
+		return nil, errdefs.NotImplemented(errors.Errorf("CSI plugin %s does not support snapshots", d.name))
+	}
+	return v, nil
+}
+
+// CreateSnapshot takes a snapshot of the volume with the plugin's controller
+// service.
+func (d *Driver) CreateSnapshot(vol volume.Volume, name string) (volume.Snapshot, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+	defer cancel()
+	v, err := d.snapshotVolume(ctx, vol)
+	if err != nil {
+		return volume.Snapshot{}, err
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if _, ok := v.info.Snapshots[name]; ok {
+		return volume.Snapshot{}, errdefs.Conflict(errors.Errorf("snapshot %s already exists", name))
+	}
+	resp, err := d.controller.CreateSnapshot(ctx, &csispec.CreateSnapshotRequest{
+		SourceVolumeId: v.info.VolumeID,
+		// Snapshot names must be unique for the plugin, not just the volume.
+		Name: v.info.Name + "-" + name,
+	})
+	if err != nil {
+		return volume.Snapshot{}, errdefs.System(errors.Wrapf(err, "error creating snapshot of volume %s", v.info.Name))
+	}
+	info := snapshotInfo{
+		SnapshotID: resp.GetSnapshot().GetSnapshotId(),
+		CreatedAt:  time.Now(),
+		Size:       resp.GetSnapshot().GetSizeBytes(),
+	}
+	if t := resp.GetSnapshot().GetCreationTime(); t != nil {
+		info.CreatedAt = time.Unix(t.GetSeconds(), int64(t.GetNanos()))
+	}
+	if v.info.Snapshots == nil {
+		v.info.Snapshots = make(map[string]snapshotInfo)
+	}
+	v.info.Snapshots[name] = info
+	if err := v.save(); err != nil {
+		return volume.Snapshot{}, errdefs.System(err)
+	}
+	return volume.Snapshot{Name: name, CreatedAt: info.CreatedAt, Size: info.Size}, nil
+}
+
+// ListSnapshots lists the snapshots of the volume.
+func (d *Driver) ListSnapshots(vol volume.Volume) ([]volume.Snapshot, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+	defer cancel()
+	v, err := d.snapshotVolume(ctx, vol)
+	if err != nil {
+		return nil, err
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	ls := make([]volume.Snapshot, 0, len(v.info.Snapshots))
+	for name, info := range v.info.Snapshots {
+		ls = append(ls, volume.Snapshot{Name: name, CreatedAt: info.CreatedAt, Size: info.Size})
+	}
+	return ls, nil
+}
+
+// RemoveSnapshot deletes a snapshot of the volume with the plugin's
+// controller service.
+func (d *Driver) RemoveSnapshot(vol volume.Volume, name string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+	defer cancel()
+	v, err := d.snapshotVolume(ctx, vol)
+	if err != nil {
+		return err
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	info, ok := v.info.Snapshots[name]
+	if !ok {
+		return errdefs.NotFound(errors.Errorf("no such snapshot: %s", name))
+	}
+	if _, err := d.controller.DeleteSnapshot(ctx, &csispec.DeleteSnapshotRequest{SnapshotId: info.SnapshotID}); err != nil && status.Code(err) != codes.NotFound {
+		return errdefs.System(errors.Wrapf(err, "error deleting snapshot %s of volume %s", name, v.info.Name))
+	}
+	delete(v.info.Snapshots, name)
+	if err := v.save(); err != nil {
+		return errdefs.System(err)
+	}
+	return nil
+}
+
+// RestoreSnapshot is not supported, because CSI can only restore a snapshot
+// into a new volume.
+func (d *Driver) RestoreSnapshot(vol volume.Volume, name string) error {
+	return errdefs.NotImplemented(errors.Errorf("CSI plugin %s cannot restore snapshots in place", d.name))
+}
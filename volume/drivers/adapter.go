@@ -2,6 +2,7 @@ package drivers // import "github.com/docker/docker/volume/drivers"
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 	"time"
 
@@ -18,6 +19,7 @@ type volumeDriverAdapter struct {
 	scopePath    func(s string) string
 	capabilities *volume.Capability
 	proxy        volumeDriver
+	timeouts     func() Timeouts
 }
 
 func (a *volumeDriverAdapter) Name() string {
@@ -25,10 +27,17 @@ func (a *volumeDriverAdapter) Name() string {
 }
 
 func (a *volumeDriverAdapter) Create(name string, opts map[string]string) (volume.Volume, error) {
-	if err := a.proxy.Create(name, opts); err != nil {
+	var err error
+	if p, ok := a.protocolV2(); ok {
+		err = p.CreateV2(name, opts, time.Now().Add(a.getTimeouts().Create))
+	} else {
+		err = a.proxy.Create(name, opts)
+	}
+	if err != nil {
 		return nil, err
 	}
 	return &volumeAdapter{
+		driver:     a,
 		proxy:      a.proxy,
 		name:       name,
 		driverName: a.name,
@@ -37,6 +46,9 @@ func (a *volumeDriverAdapter) Create(name string, opts map[string]string) (volum
 }
 
 func (a *volumeDriverAdapter) Remove(v volume.Volume) error {
+	if p, ok := a.protocolV2(); ok {
+		return p.RemoveV2(v.Name(), time.Now().Add(a.getTimeouts().Remove))
+	}
 	return a.proxy.Remove(v.Name())
 }
 
@@ -49,6 +61,7 @@ func (a *volumeDriverAdapter) List() ([]volume.Volume, error) {
 	var out []volume.Volume
 	for _, vp := range ls {
 		out = append(out, &volumeAdapter{
+			driver:     a,
 			proxy:      a.proxy,
 			name:       vp.Name,
 			scopePath:  a.scopePath,
@@ -71,6 +84,7 @@ func (a *volumeDriverAdapter) Get(name string) (volume.Volume, error) {
 	}
 
 	return &volumeAdapter{
+		driver:     a,
 		proxy:      a.proxy,
 		name:       v.Name,
 		driverName: a.Name(),
@@ -113,7 +127,23 @@ func (a *volumeDriverAdapter) getCapabilities() volume.Capability {
 	return cap
 }
 
+// getTimeouts returns the timeouts of the calls to the driver.
+func (a *volumeDriverAdapter) getTimeouts() Timeouts {
+	if a.timeouts == nil {
+		return DefaultTimeouts
+	}
+	return a.timeouts().withDefaults()
+}
+
+// protocolV2 returns the proxy for version 2 of the volume plugin protocol,
+// if the driver implements it.
+func (a *volumeDriverAdapter) protocolV2() (volumeDriverV2, bool) {
+	p, ok := a.proxy.(volumeDriverV2)
+	return p, ok && a.getCapabilities().Protocol >= protocolV2
+}
+
 type volumeAdapter struct {
+	driver     *volumeDriverAdapter
 	proxy      volumeDriver
 	name       string
 	scopePath  func(string) string
@@ -151,19 +181,113 @@ func (a *volumeAdapter) CachedPath() string {
 }
 
 func (a *volumeAdapter) Mount(id string) (string, error) {
-	mountpoint, err := a.proxy.Mount(a.name, id)
+	return a.MountWithContext(id, volume.MountContext{})
+}
+
+func (a *volumeAdapter) MountWithContext(id string, mctx volume.MountContext) (string, error) {
+	var (
+		mountpoint string
+		err        error
+	)
+	if p, ok := a.driver.protocolV2(); ok {
+		mountpoint, err = a.mountV2(p, id, mctx)
+	} else {
+		mountpoint, err = a.proxy.Mount(a.name, id)
+	}
 	a.eMount = a.scopePath(mountpoint)
 	return a.eMount, err
 }
 
+// mountV2 mounts the volume using version 2 of the volume plugin protocol,
+// and waits for the mount to complete if the driver returned a pending mount.
+func (a *volumeAdapter) mountV2(p volumeDriverV2, id string, mctx volume.MountContext) (string, error) {
+	caps := a.driver.getCapabilities()
+	var reqCtx *volume.MountContext
+	if caps.MountContext {
+		reqCtx = &mctx
+	}
+	timeouts := a.driver.getTimeouts()
+	mountpoint, pending, err := p.MountV2(a.name, id, reqCtx, time.Now().Add(timeouts.Mount))
+	if err != nil || !pending {
+		return mountpoint, err
+	}
+	if !caps.AsyncMount {
+		return "", fmt.Errorf("volume driver %s returned a pending mount, but does not support asynchronous mounts", a.driverName)
+	}
+
+	completed, err := waitPending(timeouts.Async, func(deadline time.Time) (bool, error) {
+		mountpoint, pending, err = p.MountStatus(a.name, id, deadline)
+		return pending, err
+	})
+	if completed || err != nil {
+		return mountpoint, err
+	}
+
+	// Let the driver know that the daemon gave up on the mount, so that it
+	// can release any resources held for it.
+	if _, err := p.UnmountV2(a.name, id, time.Now().Add(timeouts.Unmount)); err != nil {
+		logrus.WithError(err).WithField("volume", a.name).WithField("driver", a.driverName).Warn("failed to unmount volume after its mount timed out")
+	}
+	return "", fmt.Errorf("timed out waiting for volume driver %s to mount volume %s", a.driverName, a.name)
+}
+
 func (a *volumeAdapter) Unmount(id string) error {
-	err := a.proxy.Unmount(a.name, id)
+	var err error
+	if p, ok := a.driver.protocolV2(); ok {
+		err = a.unmountV2(p, id)
+	} else {
+		err = a.proxy.Unmount(a.name, id)
+	}
 	if err == nil {
 		a.eMount = ""
 	}
 	return err
 }
 
+// unmountV2 unmounts the volume using version 2 of the volume plugin
+// protocol, and waits for the unmount to complete if the driver returned a
+// pending unmount.
+func (a *volumeAdapter) unmountV2(p volumeDriverV2, id string) error {
+	timeouts := a.driver.getTimeouts()
+	pending, err := p.UnmountV2(a.name, id, time.Now().Add(timeouts.Unmount))
+	if err != nil || !pending {
+		return err
+	}
+	if !a.driver.getCapabilities().AsyncUnmount {
+		return fmt.Errorf("volume driver %s returned a pending unmount, but does not support asynchronous unmounts", a.driverName)
+	}
+
+	completed, err := waitPending(timeouts.Async, func(deadline time.Time) (bool, error) {
+		return p.UnmountStatus(a.name, id, deadline)
+	})
+	if completed || err != nil {
+		return err
+	}
+	return fmt.Errorf("timed out waiting for volume driver %s to unmount volume %s", a.driverName, a.name)
+}
+
+// waitPending polls the status of a pending operation, with an increasing
+// interval, until the operation completed or failed, or the timeout passed.
+// It returns whether the operation completed.
+func waitPending(timeout time.Duration, status func(deadline time.Time) (pending bool, err error)) (bool, error) {
+	deadline := time.Now().Add(timeout)
+	interval := statusIntervalMin
+	for time.Now().Add(interval).Before(deadline) {
+		time.Sleep(interval)
+		pending, err := status(deadline)
+		if err != nil {
+			return false, err
+		}
+		if !pending {
+			return true, nil
+		}
+		if interval *= 2; interval > statusIntervalMax {
+			interval = statusIntervalMax
+		}
+	}
+	return false, nil
+}
+
 func (a *volumeAdapter) CreatedAt() (time.Time, error) {
 	return a.createdAt, nil
 }
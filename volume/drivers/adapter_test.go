@@ -0,0 +1,195 @@
+package drivers // import "github.com/docker/docker/volume/drivers"
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/pkg/idtools"
+	"github.com/docker/docker/pkg/plugins"
+	"github.com/docker/docker/volume"
+	"github.com/docker/go-connections/tlsconfig"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func newTestAdapter(t *testing.T, mux *http.ServeMux) *volumeDriverAdapter {
+	t.Helper()
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	u, _ := url.Parse(server.URL)
+	client, err := plugins.NewClient("tcp://"+u.Host, &tlsconfig.Options{InsecureSkipVerify: true})
+	assert.NilError(t, err)
+	return &volumeDriverAdapter{
+		name:      "test",
+		scopePath: func(s string) string { return s },
+		proxy:     &volumeDriverProxy{client},
+	}
+}
+
+func handle(mux *http.ServeMux, method string, fn func(req map[string]interface{}) interface{}) {
+	mux.HandleFunc("/VolumeDriver."+method, func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/vnd.docker.plugins.v1+json")
+		_ = json.NewEncoder(w).Encode(fn(req))
+	})
+}
+
+func TestVolumeAdapterMountV1(t *testing.T) {
+	mux := http.NewServeMux()
+	handle(mux, "Capabilities", func(map[string]interface{}) interface{} {
+		return map[string]interface{}{"Capabilities": volume.Capability{Scope: volume.LocalScope}}
+	})
+	handle(mux, "Mount", func(req map[string]interface{}) interface{} {
+		if _, ok := req["Deadline"]; ok {
+			return map[string]string{"Err": "unexpected deadline in version 1 request"}
+		}
+		if _, ok := req["Context"]; ok {
+			return map[string]string{"Err": "unexpected context in version 1 request"}
+		}
+		return map[string]string{"Mountpoint": "/mnt/" + req["Name"].(string)}
+	})
+
+	a := newTestAdapter(t, mux)
+	v := &volumeAdapter{driver: a, proxy: a.proxy, name: "vol", driverName: a.name, scopePath: a.scopePath}
+	mountpoint, err := v.MountWithContext("id", volume.MountContext{ContainerID: "container"})
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(mountpoint, "/mnt/vol"))
+}
+
+func TestVolumeAdapterMountV2Async(t *testing.T) {
+	var polls int32
+
+	mux := http.NewServeMux()
+	handle(mux, "Capabilities", func(map[string]interface{}) interface{} {
+		return map[string]interface{}{"Capabilities": volume.Capability{
+			Scope:        volume.LocalScope,
+			Protocol:     protocolV2,
+			AsyncMount:   true,
+			MountContext: true,
+		}}
+	})
+	handle(mux, "Mount", func(req map[string]interface{}) interface{} {
+		if _, ok := req["Deadline"]; !ok {
+			return map[string]string{"Err": "missing deadline"}
+		}
+		mctx, _ := req["Context"].(map[string]interface{})
+		if mctx["ContainerID"] != "container" {
+			return map[string]string{"Err": fmt.Sprintf("unexpected mount context: %v", req["Context"])}
+		}
+		if _, ok := mctx["UIDMaps"]; !ok {
+			return map[string]string{"Err": "missing uid mappings"}
+		}
+		return map[string]interface{}{"Pending": true}
+	})
+	handle(mux, "MountStatus", func(req map[string]interface{}) interface{} {
+		if atomic.AddInt32(&polls, 1) < 2 {
+			return map[string]interface{}{"Pending": true}
+		}
+		return map[string]string{"Mountpoint": "/mnt/" + req["Name"].(string)}
+	})
+
+	a := newTestAdapter(t, mux)
+	v := &volumeAdapter{driver: a, proxy: a.proxy, name: "vol", driverName: a.name, scopePath: a.scopePath}
+	mountpoint, err := v.MountWithContext("id", volume.MountContext{
+		ContainerID: "container",
+		UIDMaps:     []idtools.IDMap{{ContainerID: 0, HostID: 100000, Size: 65536}},
+	})
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(mountpoint, "/mnt/vol"))
+	assert.Check(t, is.Equal(atomic.LoadInt32(&polls), int32(2)))
+}
+
+func TestVolumeAdapterMountV2PendingWithoutAsync(t *testing.T) {
+	mux := http.NewServeMux()
+	handle(mux, "Capabilities", func(map[string]interface{}) interface{} {
+		return map[string]interface{}{"Capabilities": volume.Capability{Scope: volume.LocalScope, Protocol: protocolV2}}
+	})
+	handle(mux, "Mount", func(req map[string]interface{}) interface{} {
+		if _, ok := req["Context"]; ok {
+			return map[string]string{"Err": "unexpected context without the MountContext capability"}
+		}
+		return map[string]interface{}{"Pending": true}
+	})
+
+	a := newTestAdapter(t, mux)
+	v := &volumeAdapter{driver: a, proxy: a.proxy, name: "vol", driverName: a.name, scopePath: a.scopePath}
+	_, err := v.MountWithContext("id", volume.MountContext{ContainerID: "container"})
+	assert.Check(t, is.ErrorContains(err, "does not support asynchronous mounts"))
+}
+
+func TestVolumeAdapterMountV2AsyncTimeout(t *testing.T) {
+	var unmounted int32
+
+	mux := http.NewServeMux()
+	handle(mux, "Capabilities", func(map[string]interface{}) interface{} {
+		return map[string]interface{}{"Capabilities": volume.Capability{Scope: volume.LocalScope, Protocol: protocolV2, AsyncMount: true}}
+	})
+	handle(mux, "Mount", func(map[string]interface{}) interface{} {
+		return map[string]interface{}{"Pending": true}
+	})
+	handle(mux, "Unmount", func(map[string]interface{}) interface{} {
+		atomic.AddInt32(&unmounted, 1)
+		return map[string]string{}
+	})
+
+	a := newTestAdapter(t, mux)
+	a.timeouts = func() Timeouts { return Timeouts{Async: time.Millisecond} }
+	v := &volumeAdapter{driver: a, proxy: a.proxy, name: "vol", driverName: a.name, scopePath: a.scopePath}
+	_, err := v.Mount("id")
+	assert.Check(t, is.ErrorContains(err, "timed out waiting for volume driver test to mount volume vol"))
+	assert.Check(t, is.Equal(atomic.LoadInt32(&unmounted), int32(1)))
+}
+
+func TestVolumeAdapterUnmountV2Async(t *testing.T) {
+	var polls int32
+
+	mux := http.NewServeMux()
+	handle(mux, "Capabilities", func(map[string]interface{}) interface{} {
+		return map[string]interface{}{"Capabilities": volume.Capability{Scope: volume.LocalScope, Protocol: protocolV2, AsyncUnmount: true}}
+	})
+	handle(mux, "Unmount", func(req map[string]interface{}) interface{} {
+		if _, ok := req["Deadline"]; !ok {
+			return map[string]string{"Err": "missing deadline"}
+		}
+		return map[string]interface{}{"Pending": true}
+	})
+	handle(mux, "UnmountStatus", func(map[string]interface{}) interface{} {
+		if atomic.AddInt32(&polls, 1) < 2 {
+			return map[string]interface{}{"Pending": true}
+		}
+		return map[string]string{}
+	})
+
+	a := newTestAdapter(t, mux)
+	v := &volumeAdapter{driver: a, proxy: a.proxy, name: "vol", driverName: a.name, scopePath: a.scopePath, eMount: "/mnt/vol"}
+	assert.NilError(t, v.Unmount("id"))
+	assert.Check(t, is.Equal(v.CachedPath(), ""))
+	assert.Check(t, is.Equal(atomic.LoadInt32(&polls), int32(2)))
+}
+
+func TestVolumeAdapterUnmountV2PendingWithoutAsync(t *testing.T) {
+	mux := http.NewServeMux()
+	handle(mux, "Capabilities", func(map[string]interface{}) interface{} {
+		return map[string]interface{}{"Capabilities": volume.Capability{Scope: volume.LocalScope, Protocol: protocolV2}}
+	})
+	handle(mux, "Unmount", func(map[string]interface{}) interface{} {
+		return map[string]interface{}{"Pending": true}
+	})
+
+	a := newTestAdapter(t, mux)
+	v := &volumeAdapter{driver: a, proxy: a.proxy, name: "vol", driverName: a.name, scopePath: a.scopePath}
+	err := v.Unmount("id")
+	assert.Check(t, is.ErrorContains(err, "does not support asynchronous unmounts"))
+}
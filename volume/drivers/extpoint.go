@@ -48,6 +48,9 @@ type Store struct {
 	mu           sync.Mutex
 	driverLock   *locker.Locker
 	pluginGetter getter.PluginGetter
+
+	timeoutsMu sync.RWMutex
+	timeouts   map[string]Timeouts
 }
 
 // NewStore creates a new volume driver store
@@ -59,6 +62,28 @@ func NewStore(pg getter.PluginGetter) *Store {
 	}
 }
 
+// SetTimeouts sets the timeouts of the calls to the volume plugins, by plugin
+// name. The timeouts under DefaultTimeoutsKey apply to the plugins which are
+// not in the map. The timeouts apply to the drivers already in use too.
+func (s *Store) SetTimeouts(timeouts map[string]Timeouts) {
+	s.timeoutsMu.Lock()
+	s.timeouts = timeouts
+	s.timeoutsMu.Unlock()
+}
+
+// pluginTimeouts returns a function returning the timeouts of the calls to the
+// plugin with the given name.
+func (s *Store) pluginTimeouts(name string) func() Timeouts {
+	return func() Timeouts {
+		s.timeoutsMu.RLock()
+		defer s.timeoutsMu.RUnlock()
+		if t, ok := s.timeouts[name]; ok {
+			return t
+		}
+		return s.timeouts[DefaultTimeoutsKey]
+	}
+}
+
 type driverNotFoundError string
 
 func (e driverNotFoundError) Error() string {
@@ -89,7 +114,7 @@ func (s *Store) lookup(name string, mode int) (volume.Driver, error) {
 			return nil, errors.Wrap(err, "error looking up volume plugin "+name)
 		}
 
-		d, err := makePluginAdapter(p)
+		d, err := makePluginAdapter(p, s.pluginTimeouts(name))
 		if err != nil {
 			return nil, errors.Wrap(err, "error making plugin client")
 		}
@@ -200,7 +225,7 @@ func (s *Store) GetAllDrivers() ([]volume.Driver, error) {
 			continue
 		}
 
-		ext, err := makePluginAdapter(p)
+		ext, err := makePluginAdapter(p, s.pluginTimeouts(name))
 		if err != nil {
 			return nil, errors.Wrap(err, "error making plugin client")
 		}
@@ -212,9 +237,14 @@ func (s *Store) GetAllDrivers() ([]volume.Driver, error) {
 	return ds, nil
 }
 
-func makePluginAdapter(p getter.CompatPlugin) (*volumeDriverAdapter, error) {
+func makePluginAdapter(p getter.CompatPlugin, timeouts func() Timeouts) (*volumeDriverAdapter, error) {
+	newAdapter := func(c client) *volumeDriverAdapter {
+		a := &volumeDriverAdapter{name: p.Name(), scopePath: p.ScopedPath, timeouts: timeouts}
+		a.proxy = &volumeDriverProxy{&timeoutClient{client: c, timeouts: a.getTimeouts}}
+		return a
+	}
 	if pc, ok := p.(getter.PluginWithV1Client); ok {
-		return &volumeDriverAdapter{name: p.Name(), scopePath: p.ScopedPath, proxy: &volumeDriverProxy{pc.Client()}}, nil
+		return newAdapter(pc.Client()), nil
 	}
 
 	pa, ok := p.(getter.PluginAddr)
@@ -232,5 +262,5 @@ func makePluginAdapter(p getter.CompatPlugin) (*volumeDriverAdapter, error) {
 		return nil, errors.Wrap(err, "error creating plugin client")
 	}
 
-	return &volumeDriverAdapter{name: p.Name(), scopePath: p.ScopedPath, proxy: &volumeDriverProxy{client}}, nil
+	return newAdapter(client), nil
 }
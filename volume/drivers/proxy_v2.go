@@ -0,0 +1,140 @@
+package drivers // import "github.com/docker/docker/volume/drivers"
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/docker/docker/pkg/plugins"
+	"github.com/docker/docker/volume"
+)
+
+// Version 2 of the volume plugin protocol extends version 1, and is used for
+// plugins that return a Protocol of 2 or higher from VolumeDriver.Capabilities.
+// Plugins implementing it:
+//
+//   - receive a Deadline with Create, Remove, Mount, and Unmount requests, at
+//     which the daemon gives up on the call,
+//   - receive the Context a volume is mounted for with Mount requests, if they
+//     declare the MountContext capability, and
+//   - may return Pending from Mount, if they declare the AsyncMount capability,
+//     after which the daemon polls VolumeDriver.MountStatus until the volume is
+//     mounted, or mounting it failed, and
+//   - may return Pending from Unmount, if they declare the AsyncUnmount
+//     capability, after which the daemon polls VolumeDriver.UnmountStatus
+//     until the volume is unmounted, or unmounting it failed.
+const protocolV2 = 2
+
+const (
+	// asyncTimeout is how long the daemon waits for a pending mount or
+	// unmount by default.
+	asyncTimeout = 10 * time.Minute
+
+	statusIntervalMin = 250 * time.Millisecond
+	statusIntervalMax = 5 * time.Second
+)
+
+// volumeDriverV2 defines the calls of version 2 of the volume plugin protocol
+// that differ from version 1.
+type volumeDriverV2 interface {
+	CreateV2(name string, opts map[string]string, deadline time.Time) error
+	RemoveV2(name string, deadline time.Time) error
+	MountV2(name, id string, mctx *volume.MountContext, deadline time.Time) (mountpoint string, pending bool, err error)
+	MountStatus(name, id string, deadline time.Time) (mountpoint string, pending bool, err error)
+	UnmountV2(name, id string, deadline time.Time) (pending bool, err error)
+	UnmountStatus(name, id string, deadline time.Time) (pending bool, err error)
+}
+
+type volumeDriverProxyV2Response struct {
+	Mountpoint string `json:",omitempty"`
+	Pending    bool   `json:",omitempty"`
+	Err        string
+}
+
+func (pp *volumeDriverProxy) callV2(method string, req interface{}, deadline time.Time) (volumeDriverProxyV2Response, error) {
+	var ret volumeDriverProxyV2Response
+	timeout := time.Until(deadline)
+	if timeout <= 0 {
+		return ret, fmt.Errorf("%s: deadline exceeded", method)
+	}
+	// The timeout of the calls of version 2 of the protocol follows from the
+	// deadline sent to the plugin, rather than from the timeouts configured
+	// for the version 1 calls.
+	c := pp.client
+	if tc, ok := c.(*timeoutClient); ok {
+		c = tc.client
+	}
+	if err := c.CallWithOptions(method, req, &ret, plugins.WithRequestTimeout(timeout)); err != nil {
+		return ret, err
+	}
+	if ret.Err != "" {
+		return ret, errors.New(ret.Err)
+	}
+	return ret, nil
+}
+
+type volumeDriverProxyCreateV2Request struct {
+	Name     string
+	Opts     map[string]string
+	Deadline time.Time
+}
+
+func (pp *volumeDriverProxy) CreateV2(name string, opts map[string]string, deadline time.Time) error {
+	_, err := pp.callV2("VolumeDriver.Create", volumeDriverProxyCreateV2Request{Name: name, Opts: opts, Deadline: deadline}, deadline)
+	return err
+}
+
+type volumeDriverProxyRemoveV2Request struct {
+	Name     string
+	Deadline time.Time
+}
+
+func (pp *volumeDriverProxy) RemoveV2(name string, deadline time.Time) error {
+	_, err := pp.callV2("VolumeDriver.Remove", volumeDriverProxyRemoveV2Request{Name: name, Deadline: deadline}, deadline)
+	return err
+}
+
+type volumeDriverProxyMountV2Request struct {
+	Name     string
+	ID       string
+	Context  *volume.MountContext `json:",omitempty"`
+	Deadline time.Time
+}
+
+func (pp *volumeDriverProxy) MountV2(name, id string, mctx *volume.MountContext, deadline time.Time) (string, bool, error) {
+	ret, err := pp.callV2("VolumeDriver.Mount", volumeDriverProxyMountV2Request{Name: name, ID: id, Context: mctx, Deadline: deadline}, deadline)
+	return ret.Mountpoint, ret.Pending, err
+}
+
+type volumeDriverProxyMountStatusRequest struct {
+	Name     string
+	ID       string
+	Deadline time.Time
+}
+
+func (pp *volumeDriverProxy) MountStatus(name, id string, deadline time.Time) (string, bool, error) {
+	ret, err := pp.callV2("VolumeDriver.MountStatus", volumeDriverProxyMountStatusRequest{Name: name, ID: id, Deadline: deadline}, deadline)
+	return ret.Mountpoint, ret.Pending, err
+}
+
+type volumeDriverProxyUnmountV2Request struct {
+	Name     string
+	ID       string
+	Deadline time.Time
+}
+
+func (pp *volumeDriverProxy) UnmountV2(name, id string, deadline time.Time) (bool, error) {
+	ret, err := pp.callV2("VolumeDriver.Unmount", volumeDriverProxyUnmountV2Request{Name: name, ID: id, Deadline: deadline}, deadline)
+	return ret.Pending, err
+}
+
+type volumeDriverProxyUnmountStatusRequest struct {
+	Name     string
+	ID       string
+	Deadline time.Time
+}
+
+func (pp *volumeDriverProxy) UnmountStatus(name, id string, deadline time.Time) (bool, error) {
+	ret, err := pp.callV2("VolumeDriver.UnmountStatus", volumeDriverProxyUnmountStatusRequest{Name: name, ID: id, Deadline: deadline}, deadline)
+	return ret.Pending, err
+}
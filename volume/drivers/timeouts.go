@@ -0,0 +1,84 @@
+package drivers // import "github.com/docker/docker/volume/drivers"
+
+import (
+	"time"
+
+	"github.com/docker/docker/pkg/plugins"
+)
+
+// DefaultTimeoutsKey is the key of the timeouts applied to the plugins that
+// have no timeouts of their own in the map passed to Store.SetTimeouts.
+const DefaultTimeoutsKey = "*"
+
+// Timeouts are the timeouts of the calls the daemon makes to a volume plugin.
+// A zero timeout is replaced by its default.
+type Timeouts struct {
+	Create  time.Duration
+	Remove  time.Duration
+	Mount   time.Duration
+	Unmount time.Duration
+	// Other is the timeout of the other calls, such as Get, List, and Path.
+	Other time.Duration
+	// Async is how long the daemon waits for a mount or unmount which the
+	// plugin reported as pending.
+	Async time.Duration
+}
+
+// DefaultTimeouts are the timeouts of the calls to volume plugins which are
+// not configured otherwise.
+var DefaultTimeouts = Timeouts{
+	Create:  longTimeout,
+	Remove:  shortTimeout,
+	Mount:   longTimeout,
+	Unmount: shortTimeout,
+	Other:   shortTimeout,
+	Async:   asyncTimeout,
+}
+
+// withDefaults returns the timeouts, with the zero timeouts replaced by
+// their default.
+func (t Timeouts) withDefaults() Timeouts {
+	for _, d := range []struct {
+		v   *time.Duration
+		def time.Duration
+	}{
+		{&t.Create, DefaultTimeouts.Create},
+		{&t.Remove, DefaultTimeouts.Remove},
+		{&t.Mount, DefaultTimeouts.Mount},
+		{&t.Unmount, DefaultTimeouts.Unmount},
+		{&t.Other, DefaultTimeouts.Other},
+		{&t.Async, DefaultTimeouts.Async},
+	} {
+		if *d.v <= 0 {
+			*d.v = d.def
+		}
+	}
+	return t
+}
+
+// forMethod returns the timeout of a call to the given method of the plugin.
+func (t Timeouts) forMethod(method string) time.Duration {
+	switch method {
+	case "VolumeDriver.Create":
+		return t.Create
+	case "VolumeDriver.Remove":
+		return t.Remove
+	case "VolumeDriver.Mount":
+		return t.Mount
+	case "VolumeDriver.Unmount":
+		return t.Unmount
+	}
+	return t.Other
+}
+
+// timeoutClient replaces the fixed timeouts used by the generated proxy with
+// the timeouts configured for the plugin.
+type timeoutClient struct {
+	client
+	timeouts func() Timeouts
+}
+
+func (c *timeoutClient) CallWithOptions(method string, args interface{}, ret interface{}, opts ...func(*plugins.RequestOpts)) error {
+	opts = append(opts, plugins.WithRequestTimeout(c.timeouts().forMethod(method)))
+	return c.client.CallWithOptions(method, args, ret, opts...)
+}
@@ -0,0 +1,48 @@
+package drivers // import "github.com/docker/docker/volume/drivers"
+
+import (
+	"testing"
+	"time"
+
+	"github.com/docker/docker/pkg/plugins"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+type recordingClient struct {
+	timeouts map[string]time.Duration
+}
+
+func (c *recordingClient) CallWithOptions(method string, _ interface{}, _ interface{}, opts ...func(*plugins.RequestOpts)) error {
+	var o plugins.RequestOpts
+	for _, opt := range opts {
+		opt(&o)
+	}
+	c.timeouts[method] = o.Timeout
+	return nil
+}
+
+func TestStoreTimeouts(t *testing.T) {
+	s := NewStore(nil)
+	s.SetTimeouts(map[string]Timeouts{
+		DefaultTimeoutsKey: {Other: 5 * time.Second},
+		"slow":             {Mount: 10 * time.Minute, Async: time.Hour},
+	})
+
+	rc := &recordingClient{timeouts: make(map[string]time.Duration)}
+	a := &volumeDriverAdapter{name: "slow", timeouts: s.pluginTimeouts("slow")}
+	a.proxy = &volumeDriverProxy{&timeoutClient{client: rc, timeouts: a.getTimeouts}}
+
+	_, _ = a.proxy.Mount("vol", "id")
+	_, _ = a.proxy.List()
+	assert.Check(t, is.Equal(rc.timeouts["VolumeDriver.Mount"], 10*time.Minute))
+	assert.Check(t, is.Equal(rc.timeouts["VolumeDriver.List"], DefaultTimeouts.Other))
+	assert.Check(t, is.Equal(a.getTimeouts().Async, time.Hour))
+
+	// Other plugins use the default timeouts, and the timeouts of a driver
+	// follow the updates of the store.
+	assert.Check(t, is.Equal(s.pluginTimeouts("other")().withDefaults().Other, 5*time.Second))
+	s.SetTimeouts(nil)
+	_, _ = a.proxy.Mount("vol", "id")
+	assert.Check(t, is.Equal(rc.timeouts["VolumeDriver.Mount"], DefaultTimeouts.Mount))
+}
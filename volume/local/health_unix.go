@@ -0,0 +1,288 @@
+//go:build linux || freebsd
+// +build linux freebsd
+
+package local // import "github.com/docker/docker/volume/local"
+
+import (
+	"sync"
+	"time"
+
+	"github.com/moby/sys/mount"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	healthCheckInterval = 30 * time.Second
+	healthCheckTimeout  = 10 * time.Second
+	remountBackoffMin   = time.Second
+	remountBackoffMax   = 5 * time.Minute
+
+	healthy   = "healthy"
+	unhealthy = "unhealthy"
+)
+
+// remoteFSTypes are the filesystem types of volumes that are mounted from a
+// remote server, and of which the health is monitored while mounted.
+var remoteFSTypes = map[string]struct{}{
+	"nfs":  {},
+	"nfs4": {},
+	"cifs": {},
+	"smb3": {},
+}
+
+func isRemoteFS(mountType string) bool {
+	_, ok := remoteFSTypes[mountType]
+	return ok
+}
+
+// healthState is the health of a mounted remote filesystem volume.
+type healthState struct {
+	mu            sync.Mutex
+	status        string
+	err           string
+	failingStreak int
+	probing       bool
+	backoff       time.Duration
+	nextRemount   time.Time
+}
+
+// healthMonitor periodically checks that the remote filesystem volumes that
+// are mounted are still reachable. Volumes of which the server went away are
+// marked unhealthy, and remounted with an exponential backoff. The monitor
+// only runs while volumes are being monitored.
+type healthMonitor struct {
+	mu       sync.Mutex
+	volumes  map[*localVolume]struct{}
+	running  bool
+	logger   EventLogger
+	interval time.Duration
+
+	// probe and remount are replaced in tests.
+	probe   func(path string) error
+	remount func(v *localVolume) error
+}
+
+func newHealthMonitor() *healthMonitor {
+	return &healthMonitor{
+		volumes:  make(map[*localVolume]struct{}),
+		interval: healthCheckInterval,
+		probe:    probeMount,
+		remount:  remountVolume,
+	}
+}
+
+func (m *healthMonitor) setLogger(l EventLogger) {
+	m.mu.Lock()
+	m.logger = l
+	m.mu.Unlock()
+}
+
+// add starts monitoring a volume that was just mounted.
+func (m *healthMonitor) add(v *localVolume) {
+	v.health.mu.Lock()
+	v.health.status = healthy
+	v.health.err = ""
+	v.health.failingStreak = 0
+	v.health.backoff = 0
+	v.health.mu.Unlock()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.volumes[v] = struct{}{}
+	if !m.running {
+		m.running = true
+		go m.run()
+	}
+}
+
+// remove stops monitoring a volume that was unmounted.
+func (m *healthMonitor) remove(v *localVolume) {
+	m.mu.Lock()
+	delete(m.volumes, v)
+	m.mu.Unlock()
+
+	v.health.mu.Lock()
+	v.health.status = ""
+	v.health.mu.Unlock()
+}
+
+func (m *healthMonitor) run() {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		m.mu.Lock()
+		if len(m.volumes) == 0 {
+			m.running = false
+			m.mu.Unlock()
+			return
+		}
+		volumes := make([]*localVolume, 0, len(m.volumes))
+		for v := range m.volumes {
+			volumes = append(volumes, v)
+		}
+		m.mu.Unlock()
+
+		for _, v := range volumes {
+			m.check(v)
+		}
+	}
+}
+
+// check probes the mount of a volume, updates its health, and remounts it if
+// it is unhealthy and its backoff has expired.
+func (m *healthMonitor) check(v *localVolume) {
+	err := m.probeWithTimeout(v)
+	if err == nil {
+		m.setHealth(v, nil)
+		return
+	}
+	m.setHealth(v, err)
+
+	v.health.mu.Lock()
+	due := !time.Now().Before(v.health.nextRemount)
+	v.health.mu.Unlock()
+	if !due {
+		return
+	}
+
+	v.m.Lock()
+	mounted := v.active.mounted
+	if mounted {
+		err = m.remount(v)
+	}
+	v.m.Unlock()
+	if !mounted {
+		return
+	}
+
+	v.health.mu.Lock()
+	if err != nil {
+		if v.health.backoff == 0 {
+			v.health.backoff = remountBackoffMin
+		} else if v.health.backoff *= 2; v.health.backoff > remountBackoffMax {
+			v.health.backoff = remountBackoffMax
+		}
+		v.health.nextRemount = time.Now().Add(v.health.backoff)
+	}
+	v.health.mu.Unlock()
+
+	if err != nil {
+		logrus.WithError(err).WithField("volume", v.name).Warn("failed to remount unhealthy volume")
+		return
+	}
+	m.logEvent(v, "remount", nil)
+	m.setHealth(v, m.probeWithTimeout(v))
+}
+
+// probeWithTimeout probes the mount of a volume. The probe of a mount of
+// which the server is gone may block indefinitely, in which case the volume
+// is reported unhealthy until the probe returns.
+func (m *healthMonitor) probeWithTimeout(v *localVolume) error {
+	v.health.mu.Lock()
+	if v.health.probing {
+		v.health.mu.Unlock()
+		return errors.New("timed out waiting for the remote filesystem to respond")
+	}
+	v.health.probing = true
+	v.health.mu.Unlock()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- m.probe(v.path)
+		v.health.mu.Lock()
+		v.health.probing = false
+		v.health.mu.Unlock()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(healthCheckTimeout):
+		return errors.New("timed out waiting for the remote filesystem to respond")
+	}
+}
+
+func (m *healthMonitor) setHealth(v *localVolume, err error) {
+	v.health.mu.Lock()
+	if v.health.status == "" {
+		// the volume was unmounted while it was checked
+		v.health.mu.Unlock()
+		return
+	}
+	prev := v.health.status
+	if err == nil {
+		v.health.status = healthy
+		v.health.err = ""
+		v.health.failingStreak = 0
+		v.health.backoff = 0
+		v.health.nextRemount = time.Time{}
+	} else {
+		v.health.status = unhealthy
+		v.health.err = err.Error()
+		v.health.failingStreak++
+	}
+	status := v.health.status
+	v.health.mu.Unlock()
+
+	if status != prev {
+		var attributes map[string]string
+		if err != nil {
+			attributes = map[string]string{"error": err.Error()}
+		}
+		m.logEvent(v, "health_status: "+status, attributes)
+	}
+}
+
+func (m *healthMonitor) logEvent(v *localVolume, action string, attributes map[string]string) {
+	m.mu.Lock()
+	logger := m.logger
+	m.mu.Unlock()
+	if logger == nil {
+		return
+	}
+	if attributes == nil {
+		attributes = make(map[string]string)
+	}
+	attributes["driver"] = v.driverName
+	logger.LogVolumeEvent(v.name, action, attributes)
+}
+
+// healthStatus adds the health of the volume to status, if it is a mounted
+// remote filesystem volume.
+func (v *localVolume) healthStatus(status map[string]interface{}) map[string]interface{} {
+	v.health.mu.Lock()
+	defer v.health.mu.Unlock()
+	if v.health.status == "" {
+		return status
+	}
+	if status == nil {
+		status = make(map[string]interface{})
+	}
+	status["Health"] = v.health.status
+	if v.health.err != "" {
+		status["HealthError"] = v.health.err
+		status["FailingStreak"] = v.health.failingStreak
+	}
+	return status
+}
+
+func probeMount(path string) error {
+	var st unix.Statfs_t
+	if err := unix.Statfs(path, &st); err != nil {
+		return errors.Wrap(err, "remote filesystem is not reachable")
+	}
+	return nil
+}
+
+// remountVolume lazily unmounts the volume, and mounts it again. Containers
+// that are already running keep the mount they started with; the new mount
+// is used by containers that are started after the volume was remounted.
+// It must be called with the volume lock held.
+func remountVolume(v *localVolume) error {
+	if err := mount.Unmount(v.path); err != nil {
+		return err
+	}
+	return v.doMount()
+}
@@ -0,0 +1,15 @@
+package local // import "github.com/docker/docker/volume/local"
+
+// healthState is the health of a mounted remote filesystem volume. Remote
+// filesystem volumes are not supported on Windows.
+type healthState struct{}
+
+// healthMonitor checks the health of mounted remote filesystem volumes.
+// Remote filesystem volumes are not supported on Windows.
+type healthMonitor struct{}
+
+func newHealthMonitor() *healthMonitor {
+	return &healthMonitor{}
+}
+
+func (m *healthMonitor) setLogger(EventLogger) {}
@@ -37,6 +37,12 @@ var (
 	volumeNameRegex = names.RestrictedNamePattern
 )
 
+// EventLogger is used by the driver to log events about volumes, such as
+// changes in the health of remote filesystem volumes.
+type EventLogger interface {
+	LogVolumeEvent(volumeID, action string, attributes map[string]string)
+}
+
 type activeMount struct {
 	count   uint64
 	mounted bool
@@ -50,6 +56,7 @@ func New(scope string, rootIdentity idtools.Identity) (*Root, error) {
 		path:         filepath.Join(scope, volumesPathName),
 		volumes:      make(map[string]*localVolume),
 		rootIdentity: rootIdentity,
+		monitor:      newHealthMonitor(),
 	}
 
 	if err := idtools.MkdirAllAndChown(r.path, 0701, idtools.CurrentIdentity()); err != nil {
@@ -77,6 +84,7 @@ func New(scope string, rootIdentity idtools.Identity) (*Root, error) {
 			rootPath:   filepath.Join(r.path, name),
 			path:       filepath.Join(r.path, name, volumeDataPathName),
 			quotaCtl:   r.quotaCtl,
+			monitor:    r.monitor,
 		}
 
 		// unmount anything that may still be mounted (for example, from an
@@ -101,6 +109,12 @@ type Root struct {
 	quotaCtl     *quota.Control
 	volumes      map[string]*localVolume
 	rootIdentity idtools.Identity
+	monitor      *healthMonitor
+}
+
+// SetEventLogger sets the logger that is used to log events about volumes.
+func (r *Root) SetEventLogger(l EventLogger) {
+	r.monitor.setLogger(l)
 }
 
 // List lists all the volumes
@@ -130,10 +144,23 @@ func (r *Root) Create(name string, opts map[string]string) (volume.Volume, error
 		return nil, err
 	}
 
+	r.m.Lock()
+	v, exists := r.volumes[name]
+	r.m.Unlock()
+	if exists {
+		return v, nil
+	}
+
+	// Mounting a remote filesystem may block until the server times out,
+	// so the options are validated without holding the lock of the driver.
+	if err := validateMount(opts); err != nil {
+		return nil, err
+	}
+
 	r.m.Lock()
 	defer r.m.Unlock()
 
-	v, exists := r.volumes[name]
+	v, exists = r.volumes[name]
 	if exists {
 		return v, nil
 	}
@@ -144,6 +171,7 @@ func (r *Root) Create(name string, opts map[string]string) (volume.Volume, error
 		rootPath:   filepath.Join(r.path, name),
 		path:       filepath.Join(r.path, name, volumeDataPathName),
 		quotaCtl:   r.quotaCtl,
+		monitor:    r.monitor,
 	}
 
 	// Root dir does not need to be accessed by the remapped root
@@ -192,6 +220,10 @@ func (r *Root) Remove(v volume.Volume) error {
 		return err
 	}
 
+	if err := lv.removeSnapshots(); err != nil {
+		return err
+	}
+
 	// TODO(thaJeztah) is there a reason we're evaluating the data-path here, and not the volume's rootPath?
 	realPath, err := filepath.EvalSymlinks(lv.path)
 	if err != nil {
@@ -269,6 +301,10 @@ type localVolume struct {
 	active activeMount
 	// reference to Root instances quotaCtl
 	quotaCtl *quota.Control
+	// monitor checks the health of the volume while it is mounted, if it is
+	// a remote filesystem volume
+	monitor *healthMonitor
+	health  healthState
 }
 
 // Name returns the name of the given Volume.
@@ -333,7 +369,7 @@ func (v *localVolume) Unmount(id string) error {
 }
 
 func (v *localVolume) Status() map[string]interface{} {
-	return nil
+	return v.status()
 }
 
 func (v *localVolume) loadOpts() error {
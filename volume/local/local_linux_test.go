@@ -7,11 +7,15 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/docker/docker/errdefs"
 	"github.com/docker/docker/pkg/idtools"
 	"github.com/docker/docker/quota"
+	"github.com/docker/docker/volume"
+	"github.com/pkg/errors"
 	"gotest.tools/v3/assert"
 	is "gotest.tools/v3/assert/cmp"
 )
@@ -46,6 +50,7 @@ func testVolWithQuota(t *testing.T, mountPoint, backingFsDev, testDir string) {
 	if err != nil {
 		t.Fatal(err)
 	}
+	assert.Check(t, is.Equal(vol.Status()["CapacityBytes"], int64(quotaSize)))
 
 	dir, err := vol.Mount("1234")
 	if err != nil {
@@ -61,6 +66,9 @@ func testVolWithQuota(t *testing.T, mountPoint, backingFsDev, testDir string) {
 
 	// test writing file smaller than quota
 	assert.NilError(t, os.WriteFile(testfile, make([]byte, quotaSize/2), 0644))
+	used, ok := vol.Status()["UsedBytes"].(int64)
+	assert.Check(t, ok)
+	assert.Check(t, used >= quotaSize/2, "expected at least %d bytes used, got %d", quotaSize/2, used)
 	assert.NilError(t, os.Remove(testfile))
 
 	// test writing fiel larger than quota
@@ -219,3 +227,91 @@ func TestVolCreateValidation(t *testing.T) {
 		})
 	}
 }
+
+type fakeEventLogger struct {
+	mu      sync.Mutex
+	actions []string
+}
+
+func (l *fakeEventLogger) LogVolumeEvent(volumeID, action string, attributes map[string]string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.actions = append(l.actions, action)
+}
+
+func TestRemoteVolumeHealth(t *testing.T) {
+	logger := &fakeEventLogger{}
+	m := newHealthMonitor()
+	m.setLogger(logger)
+
+	var probeErr, remountErr error
+	remounts := 0
+	m.probe = func(string) error { return probeErr }
+	m.remount = func(*localVolume) error {
+		remounts++
+		return remountErr
+	}
+
+	v := &localVolume{
+		name:       "nfsvol",
+		driverName: volume.DefaultDriverName,
+		path:       t.TempDir(),
+		opts:       &optsConfig{MountType: "nfs", MountDevice: ":/export", MountOpts: "addr=127.0.0.1"},
+		active:     activeMount{count: 1, mounted: true},
+		monitor:    m,
+	}
+	m.add(v)
+	assert.Check(t, is.Equal(v.Status()["Health"], "healthy"))
+
+	m.check(v)
+	assert.Check(t, is.Len(logger.actions, 0))
+
+	// the server goes away, and remounting fails
+	probeErr = errors.New("stale file handle")
+	remountErr = errors.New("connection refused")
+	m.check(v)
+	s := v.Status()
+	assert.Check(t, is.Equal(s["Health"], "unhealthy"))
+	assert.Check(t, is.Equal(s["FailingStreak"], 1))
+	assert.Check(t, is.Equal(remounts, 1))
+
+	// the next remount is delayed by the backoff
+	m.check(v)
+	assert.Check(t, is.Equal(remounts, 1))
+	assert.Check(t, is.Equal(v.Status()["FailingStreak"], 2))
+
+	// the server comes back: the volume is remounted once the backoff expired
+	v.health.nextRemount = time.Time{}
+	remountErr = nil
+	m.check(v)
+	probeErr = nil
+	m.check(v)
+	assert.Check(t, is.Equal(remounts, 2))
+	assert.Check(t, is.Equal(v.Status()["Health"], "healthy"))
+	assert.Check(t, is.DeepEqual(logger.actions, []string{"health_status: unhealthy", "remount", "health_status: healthy"}))
+
+	m.remove(v)
+	assert.Check(t, is.Nil(v.Status()["Health"]))
+}
+
+func TestValidateRemoteFSOpts(t *testing.T) {
+	r, err := New(t.TempDir(), idtools.Identity{UID: os.Geteuid(), GID: os.Getegid()})
+	assert.NilError(t, err)
+
+	_, err = r.Create("vol1", map[string]string{"type": "nfs", "device": "/export"})
+	assert.Check(t, is.ErrorContains(err, "must be in the form [host]:/path"))
+	_, err = r.Create("vol1", map[string]string{"type": "nfs", "device": ":/export"})
+	assert.Check(t, is.ErrorContains(err, "missing server address"))
+	_, err = r.Create("vol1", map[string]string{"type": "cifs", "device": "server/share"})
+	assert.Check(t, is.ErrorContains(err, "must be in the form //server/share"))
+}
+
+func TestValidateSnapshotName(t *testing.T) {
+	for _, name := range []string{"s1", "snap.1", "snap_1-a"} {
+		assert.Check(t, validateSnapshotName(name), name)
+	}
+	for _, name := range []string{"", ".", "..", "../s1", "a/b", `a\b`, "s1@v1", "s1/"} {
+		err := validateSnapshotName(name)
+		assert.Check(t, errdefs.IsInvalidParameter(err), "%q: %v", name, err)
+	}
+}
@@ -62,9 +62,12 @@ func (r *Root) validateOpts(opts map[string]string) error {
 			return errdefs.InvalidParameter(err)
 		}
 		if size > 0 && r.quotaCtl == nil {
-			return errdefs.InvalidParameter(errors.New("quota size requested but no quota support"))
+			return errdefs.InvalidParameter(errQuotaNotSupported)
 		}
 	}
+	if err := validateRemoteFSOpts(opts); err != nil {
+		return err
+	}
 	for opt, reqopts := range mandatoryOpts {
 		if _, ok := opts[opt]; ok {
 			for _, reqopt := range reqopts {
@@ -77,6 +80,32 @@ func (r *Root) validateOpts(opts map[string]string) error {
 	return nil
 }
 
+// errQuotaNotSupported is returned when a size is requested for a volume
+// and the backing filesystem of the volumes directory does not support
+// project quotas.
+var errQuotaNotSupported = errors.New("quota size requested but no quota support: the volumes directory must be on xfs or ext4 with project quotas enabled (prjquota)")
+
+// validateRemoteFSOpts checks the device of nfs and cifs volumes, and that
+// the address of the server is given for nfs volumes, which the kernel
+// requires when mounting without the mount.nfs helper.
+func validateRemoteFSOpts(opts map[string]string) error {
+	device := opts["device"]
+	switch opts["type"] {
+	case "nfs", "nfs4":
+		if !strings.Contains(device, ":") {
+			return errdefs.InvalidParameter(errors.Errorf("invalid device for %s volume: %q: must be in the form [host]:/path, for example ':/export'", opts["type"], device))
+		}
+		if getAddress(opts["o"]) == "" && strings.HasPrefix(device, ":") {
+			return errdefs.InvalidParameter(errors.Errorf("missing server address for %s volume: set the addr option, for example o=addr=192.168.1.1", opts["type"]))
+		}
+	case "cifs", "smb3":
+		if !strings.HasPrefix(device, "//") {
+			return errdefs.InvalidParameter(errors.Errorf("invalid device for %s volume: %q: must be in the form //server/share", opts["type"], device))
+		}
+	}
+	return nil
+}
+
 func (v *localVolume) setOpts(opts map[string]string) error {
 	if len(opts) == 0 {
 		return nil
@@ -92,11 +121,20 @@ func (v *localVolume) setOpts(opts map[string]string) error {
 			return errdefs.InvalidParameter(err)
 		}
 		if size > 0 && v.quotaCtl == nil {
-			return errdefs.InvalidParameter(errors.New("quota size requested but no quota support"))
+			return errdefs.InvalidParameter(errQuotaNotSupported)
 		}
 		v.opts.Quota.Size = uint64(size)
 	}
-	return v.saveOpts()
+	if err := v.saveOpts(); err != nil {
+		return err
+	}
+	// Apply the quota right away so that the limit is in place (and
+	// reported) before the volume is first used. Volumes that mount a
+	// device get their quota applied on mount instead.
+	if v.opts.Quota.Size > 0 && !v.needsMount() {
+		return v.quotaCtl.SetQuota(v.path, v.opts.Quota)
+	}
+	return nil
 }
 
 func unmount(path string) {
@@ -114,6 +152,46 @@ func (v *localVolume) needsMount() bool {
 }
 
 func (v *localVolume) mount() error {
+	if err := v.doMount(); err != nil {
+		return err
+	}
+	if isRemoteFS(v.opts.MountType) && v.monitor != nil {
+		v.monitor.add(v)
+	}
+	return nil
+}
+
+// validateMount checks that a volume with remote filesystem options can be
+// mounted, so that invalid options or an unreachable server are reported
+// when the volume is created, and not when a container using it is started.
+// The filesystem is mounted on a temporary directory, outside the volumes
+// directory, as the volume does not exist yet.
+func validateMount(opts map[string]string) error {
+	if !isRemoteFS(opts["type"]) {
+		return nil
+	}
+	dir, err := os.MkdirTemp("", "docker-volume-validate-")
+	if err != nil {
+		return errdefs.System(err)
+	}
+	defer os.Remove(dir)
+
+	v := &localVolume{
+		path: dir,
+		opts: &optsConfig{
+			MountType:   opts["type"],
+			MountOpts:   opts["o"],
+			MountDevice: opts["device"],
+		},
+	}
+	if err := v.doMount(); err != nil {
+		return errdefs.InvalidParameter(errors.Wrap(err, "error validating volume options"))
+	}
+	unmount(dir)
+	return nil
+}
+
+func (v *localVolume) doMount() error {
 	if v.opts.MountDevice == "" {
 		return fmt.Errorf("missing device in volume options")
 	}
@@ -151,8 +229,51 @@ func (v *localVolume) postMount() error {
 	return nil
 }
 
+// status returns the size limit and usage of volumes that have a quota, and
+// the health of mounted remote filesystem volumes.
+func (v *localVolume) status() map[string]interface{} {
+	if v.opts == nil {
+		return nil
+	}
+	var status map[string]interface{}
+	if v.opts.Quota.Size > 0 && v.quotaCtl != nil {
+		status = map[string]interface{}{
+			"CapacityBytes": int64(v.opts.Quota.Size),
+		}
+		if used, ok := v.UsedBytes(); ok {
+			status["UsedBytes"] = used
+		}
+	}
+	return v.healthStatus(status)
+}
+
+// UsedBytes returns the disk space used by the volume as accounted by its
+// quota. It returns false if the volume has no quota.
+func (v *localVolume) UsedBytes() (int64, bool) {
+	if v.opts == nil || v.opts.Quota.Size == 0 || v.quotaCtl == nil {
+		return 0, false
+	}
+	used, err := v.quotaCtl.GetUsage(v.path)
+	if err != nil {
+		return 0, false
+	}
+	return int64(used), true
+}
+
+// SizeLimit returns the quota size limit of the volume in bytes, or 0 if
+// the volume has no size limit.
+func (v *localVolume) SizeLimit() int64 {
+	if v.opts == nil {
+		return 0
+	}
+	return int64(v.opts.Quota.Size)
+}
+
 func (v *localVolume) unmount() error {
 	if v.needsMount() {
+		if v.monitor != nil {
+			v.monitor.remove(v)
+		}
 		if err := mount.Unmount(v.path); err != nil {
 			if mounted, mErr := mountinfo.Mounted(v.path); mounted || mErr != nil {
 				return errdefs.System(err)
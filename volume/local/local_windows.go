@@ -33,12 +33,33 @@ func (v *localVolume) needsMount() bool {
 func (v *localVolume) mount() error {
 	return nil
 }
+
+func validateMount(_ map[string]string) error {
+	return nil
+}
+
 func (v *localVolume) unmount() error {
 	return nil
 }
 
 func unmount(_ string) {}
 
+func (v *localVolume) status() map[string]interface{} {
+	return nil
+}
+
+// UsedBytes returns the disk space used by the volume as accounted by its
+// quota. Quotas are not supported on Windows.
+func (v *localVolume) UsedBytes() (int64, bool) {
+	return 0, false
+}
+
+// SizeLimit returns the quota size limit of the volume in bytes. Quotas are
+// not supported on Windows.
+func (v *localVolume) SizeLimit() int64 {
+	return 0
+}
+
 func (v *localVolume) postMount() error {
 	return nil
 }
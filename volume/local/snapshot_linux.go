@@ -0,0 +1,474 @@
+package local // import "github.com/docker/docker/volume/local"
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/volume"
+	zfs "github.com/mistifyio/go-zfs"
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+// snapshotsPathName is the name of the directory below the volume's root
+// path where btrfs snapshots are stored.
+const snapshotsPathName = "_snapshots"
+
+// snapshotter takes snapshots of a local volume using the facilities of the
+// storage the volume is backed by.
+type snapshotter interface {
+	create(name string) (volume.Snapshot, error)
+	list() ([]volume.Snapshot, error)
+	remove(name string) error
+	restore(name string) error
+	snapshotPath(name string) (string, error)
+}
+
+// snapshotter returns the snapshotter for the volume. Snapshots are supported
+// for volumes backed by a zfs dataset ("type=zfs"), by a thin-provisioned LVM
+// logical volume, or whose data directory is a btrfs subvolume.
+func (v *localVolume) snapshotter() (snapshotter, error) {
+	if v.opts != nil && v.opts.MountDevice != "" {
+		if v.opts.MountType == "zfs" {
+			return &zfsSnapshotter{dataset: v.opts.MountDevice}, nil
+		}
+		if lv, ok := thinLogicalVolume(v.opts.MountDevice); ok {
+			return lv, nil
+		}
+		return nil, errdefs.NotImplemented(errors.Errorf("snapshots are not supported for volumes of type %q", v.opts.MountType))
+	}
+	if isBtrfsSubvolume(v.path) {
+		return &btrfsSnapshotter{path: v.path, snapshots: filepath.Join(v.rootPath, snapshotsPathName)}, nil
+	}
+	return nil, errdefs.NotImplemented(errors.New("snapshots require the volume to be backed by a btrfs subvolume, a zfs dataset, or a thin LVM volume"))
+}
+
+// validateSnapshotName checks that the name of a snapshot is a single, clean
+// path element, as it is used to build the paths and the names of the
+// snapshots in the storage of the volume.
+func validateSnapshotName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\@`) || filepath.Clean(name) != name {
+		return errdefs.InvalidParameter(errors.Errorf("invalid snapshot name: %q", name))
+	}
+	return nil
+}
+
+func (r *Root) snapshotter(vol volume.Volume) (*localVolume, snapshotter, error) {
+	v, ok := vol.(*localVolume)
+	if !ok {
+		return nil, nil, errdefs.System(errors.Errorf("unknown volume type %T", vol))
+	}
+	s, err := v.snapshotter()
+	return v, s, err
+}
+
+// CreateSnapshot takes a snapshot of the volume.
+func (r *Root) CreateSnapshot(vol volume.Volume, name string) (volume.Snapshot, error) {
+	v, s, err := r.snapshotter(vol)
+	if err != nil {
+		return volume.Snapshot{}, err
+	}
+	v.m.Lock()
+	defer v.m.Unlock()
+	return s.create(name)
+}
+
+// ListSnapshots lists the snapshots of the volume, oldest first.
+func (r *Root) ListSnapshots(vol volume.Volume) ([]volume.Snapshot, error) {
+	v, s, err := r.snapshotter(vol)
+	if err != nil {
+		return nil, err
+	}
+	v.m.Lock()
+	defer v.m.Unlock()
+	ls, err := s.list()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(ls, func(i, j int) bool { return ls[i].CreatedAt.Before(ls[j].CreatedAt) })
+	return ls, nil
+}
+
+// RemoveSnapshot removes a snapshot of the volume.
+func (r *Root) RemoveSnapshot(vol volume.Volume, name string) error {
+	v, s, err := r.snapshotter(vol)
+	if err != nil {
+		return err
+	}
+	v.m.Lock()
+	defer v.m.Unlock()
+	return s.remove(name)
+}
+
+// RestoreSnapshot reverts the volume to a snapshot. The volume must not be
+// mounted.
+func (r *Root) RestoreSnapshot(vol volume.Volume, name string) error {
+	v, s, err := r.snapshotter(vol)
+	if err != nil {
+		return err
+	}
+	v.m.Lock()
+	defer v.m.Unlock()
+	if v.active.count > 0 {
+		return errdefs.Conflict(errors.New("volume has active mounts"))
+	}
+	return s.restore(name)
+}
+
+// SnapshotPath returns the path of the read-only contents of a snapshot of
+// the volume.
+func (r *Root) SnapshotPath(vol volume.Volume, name string) (string, error) {
+	v, s, err := r.snapshotter(vol)
+	if err != nil {
+		return "", err
+	}
+	v.m.Lock()
+	defer v.m.Unlock()
+	return s.snapshotPath(name)
+}
+
+// removeSnapshots removes the snapshots that are stored within the volume's
+// root path, so that the volume can be removed.
+func (v *localVolume) removeSnapshots() error {
+	s, err := v.snapshotter()
+	if err != nil {
+		return nil
+	}
+	bs, ok := s.(*btrfsSnapshotter)
+	if !ok {
+		return nil
+	}
+	ls, err := bs.list()
+	if err != nil {
+		return err
+	}
+	for _, snap := range ls {
+		if err := bs.remove(snap.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runSnapshotCmd(name string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(name, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", errdefs.System(errors.Errorf("%s %s failed: %v: %s", name, strings.Join(args, " "), err, strings.TrimSpace(stderr.String())))
+	}
+	return stdout.String(), nil
+}
+
+// isBtrfsSubvolume returns whether path is the root of a btrfs subvolume.
+func isBtrfsSubvolume(path string) bool {
+	var sfs unix.Statfs_t
+	if err := unix.Statfs(path, &sfs); err != nil || sfs.Type != unix.BTRFS_SUPER_MAGIC {
+		return false
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	// The root directory of a btrfs subvolume always has inode number 256.
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	return ok && st.Ino == 256
+}
+
+type btrfsSnapshotter struct {
+	path      string
+	snapshots string
+}
+
+func (b *btrfsSnapshotter) create(name string) (volume.Snapshot, error) {
+	if err := validateSnapshotName(name); err != nil {
+		return volume.Snapshot{}, err
+	}
+	if err := os.MkdirAll(b.snapshots, 0o700); err != nil {
+		return volume.Snapshot{}, errdefs.System(err)
+	}
+	dst := filepath.Join(b.snapshots, name)
+	if _, err := os.Stat(dst); err == nil {
+		return volume.Snapshot{}, errdefs.Conflict(errors.Errorf("snapshot %s already exists", name))
+	}
+	if _, err := runSnapshotCmd("btrfs", "subvolume", "snapshot", "-r", b.path, dst); err != nil {
+		return volume.Snapshot{}, err
+	}
+	return volume.Snapshot{Name: name, CreatedAt: time.Now(), Size: -1}, nil
+}
+
+func (b *btrfsSnapshotter) list() ([]volume.Snapshot, error) {
+	dirs, err := os.ReadDir(b.snapshots)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errdefs.System(err)
+	}
+	var ls []volume.Snapshot
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		snap := volume.Snapshot{Name: d.Name(), Size: -1}
+		if fi, err := d.Info(); err == nil {
+			snap.CreatedAt = fi.ModTime()
+		}
+		ls = append(ls, snap)
+	}
+	return ls, nil
+}
+
+func (b *btrfsSnapshotter) get(name string) (string, error) {
+	if err := validateSnapshotName(name); err != nil {
+		return "", err
+	}
+	p := filepath.Join(b.snapshots, name)
+	if _, err := os.Stat(p); err != nil {
+		if os.IsNotExist(err) {
+			return "", errdefs.NotFound(errors.Errorf("no such snapshot: %s", name))
+		}
+		return "", errdefs.System(err)
+	}
+	return p, nil
+}
+
+func (b *btrfsSnapshotter) snapshotPath(name string) (string, error) {
+	return b.get(name)
+}
+
+func (b *btrfsSnapshotter) remove(name string) error {
+	p, err := b.get(name)
+	if err != nil {
+		return err
+	}
+	_, err = runSnapshotCmd("btrfs", "subvolume", "delete", p)
+	return err
+}
+
+func (b *btrfsSnapshotter) restore(name string) error {
+	p, err := b.get(name)
+	if err != nil {
+		return err
+	}
+	// Move the current data out of the way first, so that it can be put
+	// back if the snapshot cannot be restored.
+	old := b.path + ".old"
+	if err := os.Rename(b.path, old); err != nil {
+		return errdefs.System(err)
+	}
+	if _, err := runSnapshotCmd("btrfs", "subvolume", "snapshot", p, b.path); err != nil {
+		_ = os.Rename(old, b.path)
+		return err
+	}
+	_, err = runSnapshotCmd("btrfs", "subvolume", "delete", old)
+	return err
+}
+
+type zfsSnapshotter struct {
+	dataset string
+}
+
+func (z *zfsSnapshotter) create(name string) (volume.Snapshot, error) {
+	if err := validateSnapshotName(name); err != nil {
+		return volume.Snapshot{}, err
+	}
+	ds, err := zfs.GetDataset(z.dataset)
+	if err != nil {
+		return volume.Snapshot{}, errdefs.System(err)
+	}
+	snap, err := ds.Snapshot(name, false)
+	if err != nil {
+		return volume.Snapshot{}, errdefs.System(err)
+	}
+	return z.toSnapshot(snap), nil
+}
+
+func (z *zfsSnapshotter) list() ([]volume.Snapshot, error) {
+	ls, err := zfs.Snapshots(z.dataset)
+	if err != nil {
+		return nil, errdefs.System(err)
+	}
+	var out []volume.Snapshot
+	for _, snap := range ls {
+		// Snapshots of child datasets are included in the list.
+		if strings.HasPrefix(snap.Name, z.dataset+"@") {
+			out = append(out, z.toSnapshot(snap))
+		}
+	}
+	return out, nil
+}
+
+func (z *zfsSnapshotter) toSnapshot(ds *zfs.Dataset) volume.Snapshot {
+	snap := volume.Snapshot{
+		Name: strings.TrimPrefix(ds.Name, z.dataset+"@"),
+		Size: int64(ds.Used),
+	}
+	// zfs reports the creation time as, for example, "Mon Jan  2 15:04 2006"
+	if c, err := ds.GetProperty("creation"); err == nil {
+		if t, err := time.ParseInLocation("Mon Jan _2 15:04 2006", c, time.Local); err == nil {
+			snap.CreatedAt = t
+		}
+	}
+	return snap
+}
+
+func (z *zfsSnapshotter) get(name string) (*zfs.Dataset, error) {
+	if err := validateSnapshotName(name); err != nil {
+		return nil, err
+	}
+	ds, err := zfs.GetDataset(z.dataset + "@" + name)
+	if err != nil {
+		return nil, errdefs.NotFound(errors.Wrapf(err, "no such snapshot: %s", name))
+	}
+	return ds, nil
+}
+
+// snapshotPath returns the path of the snapshot in the ".zfs" directory of the
+// dataset's mountpoint.
+func (z *zfsSnapshotter) snapshotPath(name string) (string, error) {
+	if _, err := z.get(name); err != nil {
+		return "", err
+	}
+	ds, err := zfs.GetDataset(z.dataset)
+	if err != nil {
+		return "", errdefs.System(err)
+	}
+	if !filepath.IsAbs(ds.Mountpoint) {
+		return "", errdefs.NotImplemented(errors.Errorf("zfs dataset %s has no mountpoint: %s", z.dataset, ds.Mountpoint))
+	}
+	return filepath.Join(ds.Mountpoint, ".zfs", "snapshot", name), nil
+}
+
+func (z *zfsSnapshotter) remove(name string) error {
+	ds, err := z.get(name)
+	if err != nil {
+		return err
+	}
+	if err := ds.Destroy(zfs.DestroyDefault); err != nil {
+		return errdefs.System(err)
+	}
+	return nil
+}
+
+// restore rolls the dataset back to the snapshot. Snapshots that are more
+// recent than the snapshot are destroyed.
+func (z *zfsSnapshotter) restore(name string) error {
+	ds, err := z.get(name)
+	if err != nil {
+		return err
+	}
+	if err := ds.Rollback(true); err != nil {
+		return errdefs.System(err)
+	}
+	return nil
+}
+
+// lvmSnapshotter takes snapshots of a thin-provisioned LVM logical volume.
+// Snapshots are created in the same volume group, and named after the
+// origin volume and the snapshot.
+type lvmSnapshotter struct {
+	vg string
+	lv string
+}
+
+// thinLogicalVolume returns an lvmSnapshotter if device is a thin LVM
+// logical volume.
+func thinLogicalVolume(device string) (*lvmSnapshotter, bool) {
+	if !strings.HasPrefix(device, "/dev/") {
+		return nil, false
+	}
+	out, err := runSnapshotCmd("lvs", "--noheadings", "--separator", "|", "-o", "vg_name,lv_name,pool_lv", device)
+	if err != nil {
+		return nil, false
+	}
+	fields := strings.Split(strings.TrimSpace(out), "|")
+	if len(fields) != 3 || fields[2] == "" {
+		return nil, false
+	}
+	return &lvmSnapshotter{vg: fields[0], lv: fields[1]}, true
+}
+
+func (l *lvmSnapshotter) snapshotName(name string) string {
+	return l.lv + "-snap-" + name
+}
+
+func (l *lvmSnapshotter) create(name string) (volume.Snapshot, error) {
+	if err := validateSnapshotName(name); err != nil {
+		return volume.Snapshot{}, err
+	}
+	if _, err := runSnapshotCmd("lvcreate", "--snapshot", "--setactivationskip", "y", "--name", l.snapshotName(name), l.vg+"/"+l.lv); err != nil {
+		return volume.Snapshot{}, err
+	}
+	return volume.Snapshot{Name: name, CreatedAt: time.Now(), Size: -1}, nil
+}
+
+func (l *lvmSnapshotter) list() ([]volume.Snapshot, error) {
+	out, err := runSnapshotCmd("lvs", "--noheadings", "--separator", "|", "-o", "lv_name,origin,lv_time", "--config", "report/time_format=\"%s\"", l.vg)
+	if err != nil {
+		return nil, err
+	}
+	prefix := l.snapshotName("")
+	var ls []volume.Snapshot
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Split(strings.TrimSpace(line), "|")
+		if len(fields) != 3 || fields[1] != l.lv || !strings.HasPrefix(fields[0], prefix) {
+			continue
+		}
+		snap := volume.Snapshot{Name: strings.TrimPrefix(fields[0], prefix), Size: -1}
+		if sec, err := strconv.ParseInt(fields[2], 10, 64); err == nil {
+			snap.CreatedAt = time.Unix(sec, 0)
+		}
+		ls = append(ls, snap)
+	}
+	return ls, nil
+}
+
+func (l *lvmSnapshotter) get(name string) (string, error) {
+	if err := validateSnapshotName(name); err != nil {
+		return "", err
+	}
+	ls, err := l.list()
+	if err != nil {
+		return "", err
+	}
+	for _, snap := range ls {
+		if snap.Name == name {
+			return l.vg + "/" + l.snapshotName(name), nil
+		}
+	}
+	return "", errdefs.NotFound(errors.Errorf("no such snapshot: %s", name))
+}
+
+func (l *lvmSnapshotter) snapshotPath(name string) (string, error) {
+	return "", errdefs.NotImplemented(errors.New("the contents of LVM snapshots cannot be accessed"))
+}
+
+func (l *lvmSnapshotter) remove(name string) error {
+	lv, err := l.get(name)
+	if err != nil {
+		return err
+	}
+	_, err = runSnapshotCmd("lvremove", "--yes", lv)
+	return err
+}
+
+// restore merges the snapshot into the origin volume. The snapshot is
+// consumed by the merge.
+func (l *lvmSnapshotter) restore(name string) error {
+	lv, err := l.get(name)
+	if err != nil {
+		return err
+	}
+	_, err = runSnapshotCmd("lvconvert", "--merge", lv)
+	return err
+}
@@ -0,0 +1,8 @@
+//go:build !linux
+// +build !linux
+
+package local // import "github.com/docker/docker/volume/local"
+
+func (v *localVolume) removeSnapshots() error {
+	return nil
+}
@@ -357,6 +357,33 @@ func (p *linuxParser) ConvertTmpfsOptions(opt *mount.TmpfsOptions, readOnly bool
 		rawOpts = append(rawOpts, fmt.Sprintf("mode=%o", opt.Mode))
 	}
 
+	if opt != nil && opt.UID != nil {
+		if *opt.UID < 0 {
+			return "", fmt.Errorf("invalid tmpfs uid: %d", *opt.UID)
+		}
+		rawOpts = append(rawOpts, fmt.Sprintf("uid=%d", *opt.UID))
+	}
+
+	if opt != nil && opt.GID != nil {
+		if *opt.GID < 0 {
+			return "", fmt.Errorf("invalid tmpfs gid: %d", *opt.GID)
+		}
+		rawOpts = append(rawOpts, fmt.Sprintf("gid=%d", *opt.GID))
+	}
+
+	if opt != nil && opt.HugePages != "" {
+		switch opt.HugePages {
+		case "never", "always", "within_size", "advise":
+		default:
+			return "", fmt.Errorf("invalid tmpfs huge page policy %q: must be one of never, always, within_size, or advise", opt.HugePages)
+		}
+		rawOpts = append(rawOpts, "huge="+opt.HugePages)
+	}
+
+	if opt != nil && opt.NoSwap {
+		rawOpts = append(rawOpts, "noswap")
+	}
+
 	if opt != nil && opt.SizeBytes != 0 {
 		// calculate suffix here, making this linux specific, but that is
 		// okay, since API is that way anyways.
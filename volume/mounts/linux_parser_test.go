@@ -179,6 +179,7 @@ func TestLinuxParseMountSpecBindWithFileinfoError(t *testing.T) {
 }
 
 func TestConvertTmpfsOptions(t *testing.T) {
+	uid, gid := 1000, 0
 	type testCase struct {
 		opt                  mount.TmpfsOptions
 		readOnly             bool
@@ -198,6 +199,12 @@ func TestConvertTmpfsOptions(t *testing.T) {
 			expectedSubstrings:   []string{"ro"},
 			unexpectedSubstrings: []string{},
 		},
+		{
+			opt:                  mount.TmpfsOptions{UID: &uid, GID: &gid, HugePages: "within_size", NoSwap: true},
+			readOnly:             false,
+			expectedSubstrings:   []string{"uid=1000", "gid=0", "huge=within_size", "noswap"},
+			unexpectedSubstrings: []string{"ro", "mode="},
+		},
 	}
 	p := NewLinuxParser()
 	for _, c := range cases {
@@ -219,3 +226,17 @@ func TestConvertTmpfsOptions(t *testing.T) {
 		}
 	}
 }
+
+func TestConvertTmpfsOptionsInvalid(t *testing.T) {
+	uid := -1
+	p := NewLinuxParser()
+	for _, opt := range []mount.TmpfsOptions{
+		{HugePages: "sometimes"},
+		{UID: &uid},
+		{GID: &uid},
+	} {
+		if _, err := p.ConvertTmpfsOptions(&opt, false); err == nil {
+			t.Fatalf("expected an error converting %+v", opt)
+		}
+	}
+}
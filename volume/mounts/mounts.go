@@ -96,6 +96,12 @@ func (m *MountPoint) Cleanup() error {
 // The, optional, checkFun parameter allows doing additional checking
 // before creating the source directory on the host.
 func (m *MountPoint) Setup(mountLabel string, rootIDs idtools.Identity, checkFun func(m *MountPoint) error) (path string, err error) {
+	return m.SetupWithContext(mountLabel, rootIDs, volume.MountContext{}, checkFun)
+}
+
+// SetupWithContext sets up a mount point like Setup, and passes the context
+// the volume is mounted for to volumes that can use it.
+func (m *MountPoint) SetupWithContext(mountLabel string, rootIDs idtools.Identity, mctx volume.MountContext, checkFun func(m *MountPoint) error) (path string, err error) {
 	if m.SkipMountpointCreation {
 		return m.Source, nil
 	}
@@ -127,7 +133,12 @@ func (m *MountPoint) Setup(mountLabel string, rootIDs idtools.Identity, checkFun
 		if id == "" {
 			id = stringid.GenerateRandomID()
 		}
-		path, err := m.Volume.Mount(id)
+		var path string
+		if cm, ok := m.Volume.(volume.ContextMounter); ok {
+			path, err = cm.MountWithContext(id, mctx)
+		} else {
+			path, err = m.Volume.Mount(id)
+		}
 		if err != nil {
 			return "", errors.Wrapf(err, "error while mounting volume '%s'", m.Source)
 		}
@@ -233,6 +233,9 @@ func (p *windowsParser) validateMountConfigReg(mnt *mount.Mount, additionalValid
 			if len(opts.Propagation) > 0 {
 				return &errMountConfig{mnt, fmt.Errorf("invalid propagation mode: %s", opts.Propagation)}
 			}
+			if opts.IDMap {
+				return &errMountConfig{mnt, errors.New("idmapped mounts are not supported on Windows")}
+			}
 		}
 		if mnt.VolumeOptions != nil {
 			return &errMountConfig{mnt, errExtraField("VolumeOptions")}
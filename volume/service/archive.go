@@ -0,0 +1,132 @@
+package service // import "github.com/docker/docker/volume/service"
+
+import (
+	"context"
+	"io"
+
+	volumetypes "github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/chrootarchive"
+	"github.com/docker/docker/pkg/ioutils"
+	"github.com/docker/docker/pkg/stringid"
+	"github.com/docker/docker/volume/service/opts"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// QuiesceFunc pauses the users of a volume, so that its contents can be
+// exported in a consistent state. It is called with the references held on
+// the volume, which are typically container IDs, and returns a function that
+// resumes them.
+type QuiesceFunc func(ctx context.Context, refs []string) (resume func(), err error)
+
+// SetQuiesceFunc sets the function that is used to pause the users of a
+// volume when it is exported with the Quiesce option.
+func (s *VolumesService) SetQuiesceFunc(fn QuiesceFunc) {
+	s.quiesce = fn
+}
+
+func parseCompression(compression string) (archive.Compression, error) {
+	switch compression {
+	case "", "none":
+		return archive.Uncompressed, nil
+	case "gzip":
+		return archive.Gzip, nil
+	case "zstd":
+		return archive.Zstd, nil
+	default:
+		return archive.Uncompressed, errdefs.InvalidParameter(errors.Errorf("invalid compression %q: must be one of none, gzip, or zstd", compression))
+	}
+}
+
+// Export returns a tar archive of the contents of a volume. The volume is
+// mounted, and the containers using it are paused if the Quiesce option is
+// set, until the returned archive is closed.
+func (s *VolumesService) Export(ctx context.Context, name string, options volumetypes.ExportOptions) (io.ReadCloser, error) {
+	compression, err := parseCompression(options.Compression)
+	if err != nil {
+		return nil, err
+	}
+
+	ref := "export-" + stringid.GenerateRandomID()
+	v, err := s.vs.Get(ctx, name, opts.WithGetReference(ref))
+	if err != nil {
+		if IsNotExist(err) {
+			err = errdefs.NotFound(err)
+		}
+		return nil, err
+	}
+	path, err := v.Mount(ref)
+	if err != nil {
+		s.vs.Release(context.Background(), name, ref)
+		return nil, errors.Wrapf(err, "error mounting volume %s", name)
+	}
+	cleanup := func() {
+		if err := v.Unmount(ref); err != nil {
+			logrus.WithError(err).WithField("volume", name).Warn("failed to unmount volume after export")
+		}
+		s.vs.Release(context.Background(), name, ref)
+	}
+
+	resume := func() {}
+	if options.Quiesce && s.quiesce != nil {
+		var users []string
+		for _, r := range s.vs.getRefs(name) {
+			if r != ref {
+				users = append(users, r)
+			}
+		}
+		if resume, err = s.quiesce(ctx, users); err != nil {
+			cleanup()
+			return nil, errors.Wrapf(err, "error quiescing users of volume %s", name)
+		}
+	}
+
+	rc, err := chrootarchive.Tar(path, &archive.TarOptions{Compression: compression}, path)
+	if err != nil {
+		resume()
+		cleanup()
+		return nil, errdefs.System(err)
+	}
+	return ioutils.NewReadCloserWrapper(rc, func() error {
+		err := rc.Close()
+		resume()
+		cleanup()
+		return err
+	}), nil
+}
+
+// Import extracts a tar archive into a volume, which may be compressed with
+// any of the compression formats supported by DecompressStream. Existing
+// files in the volume are overwritten by the files in the archive. The volume
+// must not be in use by any container.
+func (s *VolumesService) Import(ctx context.Context, name string, in io.Reader) error {
+	ref := "import-" + stringid.GenerateRandomID()
+	v, err := s.vs.Get(ctx, name, opts.WithGetReference(ref))
+	if err != nil {
+		if IsNotExist(err) {
+			err = errdefs.NotFound(err)
+		}
+		return err
+	}
+	defer s.vs.Release(context.Background(), name, ref)
+
+	if refs := s.vs.getRefs(name); len(refs) > 1 {
+		return errdefs.Conflict(&OpErr{Err: errVolumeInUse, Name: name, Op: "import", Refs: refs})
+	}
+
+	path, err := v.Mount(ref)
+	if err != nil {
+		return errors.Wrapf(err, "error mounting volume %s", name)
+	}
+	defer v.Unmount(ref)
+
+	if err := chrootarchive.Untar(in, path, nil); err != nil {
+		return errors.Wrapf(err, "error importing archive into volume %s", name)
+	}
+	if s.eventLogger != nil {
+		s.eventLogger.LogVolumeEvent(name, "import", map[string]string{"driver": v.DriverName()})
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package service // import "github.com/docker/docker/volume/service"
+
+import (
+	"context"
+	"strings"
+
+	volumetypes "github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/volume"
+	"github.com/docker/docker/volume/service/opts"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// createFrom creates a volume, and populates it with the contents of another
+// volume, or of a snapshot of a volume if from is in the "volume@snapshot"
+// form. The new volume uses the driver of the source volume, unless another
+// driver is given.
+func (s *VolumesService) createFrom(ctx context.Context, name, driverName, from string, options ...opts.CreateOption) (*volumetypes.Volume, error) {
+	srcName, snapshot, fromSnapshot := strings.Cut(from, "@")
+	if fromSnapshot {
+		if err := validateSnapshotName(snapshot); err != nil {
+			return nil, err
+		}
+	}
+	if srcName == name {
+		return nil, errdefs.InvalidParameter(errors.New("a volume cannot be created from itself"))
+	}
+	if _, err := s.vs.Get(ctx, name); err == nil {
+		return nil, errdefs.Conflict(errors.Errorf("volume %s already exists", name))
+	}
+
+	// Hold a reference to the source volume, so that it is not removed while
+	// it is being copied.
+	ref := "clone-" + name
+	src, err := s.vs.Get(ctx, srcName, opts.WithGetReference(ref))
+	if err != nil {
+		if IsNotExist(err) {
+			err = errdefs.NotFound(err)
+		}
+		return nil, err
+	}
+	defer s.vs.Release(ctx, srcName, ref)
+
+	if driverName == "" {
+		driverName = src.DriverName()
+	}
+	v, err := s.vs.Create(ctx, name, driverName, options...)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.copyVolume(src, snapshot, v, ref); err != nil {
+		if rmErr := s.vs.Remove(ctx, v); rmErr != nil {
+			logrus.WithError(rmErr).WithField("volume", name).Warn("failed to remove volume after failing to populate it")
+		}
+		return nil, errors.Wrapf(err, "error populating volume %s from %s", name, from)
+	}
+
+	apiV := volumeToAPIType(v)
+	return &apiV, nil
+}
+
+// copyVolume copies the contents of src, or of the given snapshot of src, to
+// dst.
+func (s *VolumesService) copyVolume(src volume.Volume, snapshot string, dst volume.Volume, ref string) error {
+	var srcPath string
+	if snapshot != "" {
+		vd, err := s.vs.drivers.GetDriver(src.DriverName())
+		if err != nil {
+			return err
+		}
+		sp, ok := vd.(volume.SnapshotPather)
+		if !ok {
+			return errdefs.NotImplemented(errors.Errorf("volume driver %s does not support creating volumes from snapshots", src.DriverName()))
+		}
+		if srcPath, err = sp.SnapshotPath(unwrapVolume(src), snapshot); err != nil {
+			return err
+		}
+	} else {
+		p, err := src.Mount(ref)
+		if err != nil {
+			return err
+		}
+		defer src.Unmount(ref)
+		srcPath = p
+	}
+
+	dstPath, err := dst.Mount(ref)
+	if err != nil {
+		return err
+	}
+	defer dst.Unmount(ref)
+	return copyVolumeData(srcPath, dstPath)
+}
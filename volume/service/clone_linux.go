@@ -0,0 +1,15 @@
+package service // import "github.com/docker/docker/volume/service"
+
+import (
+	"github.com/docker/docker/daemon/graphdriver/copy"
+	"github.com/docker/docker/errdefs"
+)
+
+// copyVolumeData copies the contents of src to dst. Files are cloned if the
+// filesystem supports reflinks, and copied otherwise.
+func copyVolumeData(src, dst string) error {
+	if err := copy.DirCopy(src, dst, copy.Content, true); err != nil {
+		return errdefs.System(err)
+	}
+	return nil
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+// +build !linux
+
+package service // import "github.com/docker/docker/volume/service"
+
+import (
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+)
+
+func copyVolumeData(src, dst string) error {
+	return errdefs.NotImplemented(errors.New("creating a volume from another volume is not supported on this platform"))
+}
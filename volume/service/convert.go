@@ -9,9 +9,7 @@ import (
 	"github.com/docker/docker/api/types/filters"
 	volumetypes "github.com/docker/docker/api/types/volume"
 	"github.com/docker/docker/errdefs"
-	"github.com/docker/docker/pkg/directory"
 	"github.com/docker/docker/volume"
-	"github.com/sirupsen/logrus"
 )
 
 // convertOpts are used to pass options to `volumeToAPI`
@@ -31,6 +29,12 @@ type pathCacher interface {
 	CachedPath() string
 }
 
+// sizeLimiter is implemented by volumes that can have a size limit, such as
+// local volumes created with the "size" option.
+type sizeLimiter interface {
+	SizeLimit() int64
+}
+
 func (s *VolumesService) volumesToAPI(ctx context.Context, volumes []volume.Volume, opts ...convertOpt) []*volumetypes.Volume {
 	var (
 		out        = make([]*volumetypes.Volume, 0, len(volumes))
@@ -67,12 +71,11 @@ func (s *VolumesService) volumesToAPI(ctx context.Context, volumes []volume.Volu
 			if apiV.Mountpoint == "" {
 				apiV.Mountpoint = p
 			}
-			sz, err := directory.Size(ctx, p)
-			if err != nil {
-				logrus.WithError(err).WithField("volume", v.Name()).Warnf("Failed to determine size of volume")
-				sz = -1
-			}
+			sz := s.volumeSize(ctx, v)
 			apiV.UsageData = &volumetypes.UsageData{Size: sz, RefCount: int64(s.vs.CountReferences(v))}
+			if sl, ok := unwrapVolume(v).(sizeLimiter); ok {
+				apiV.UsageData.SizeLimit = sl.SizeLimit()
+			}
 		}
 
 		out = append(out, &apiV)
@@ -102,6 +105,9 @@ func filtersToBy(filter filters.Args, acceptedFilters map[string]bool) (By, erro
 	if err := filter.Validate(acceptedFilters); err != nil {
 		return nil, err
 	}
+	if err := filter.ValidateLabelSelectors("label"); err != nil {
+		return nil, err
+	}
 	var bys []By
 	if drivers := filter.Get("driver"); len(drivers) > 0 {
 		bys = append(bys, ByDriver(drivers...))
@@ -113,6 +119,17 @@ func filtersToBy(filter filters.Args, acceptedFilters map[string]bool) (By, erro
 	}
 	bys = append(bys, byLabelFilter(filter))
 
+	if filter.Contains("until") {
+		until, err := getUntilFromFilters(filter)
+		if err != nil {
+			return nil, err
+		}
+		bys = append(bys, CustomFilter(func(v volume.Volume) bool {
+			createdAt, err := v.CreatedAt()
+			return err == nil && createdAt.Before(until)
+		}))
+	}
+
 	if filter.Contains("dangling") {
 		var dangling bool
 		if filter.ExactMatch("dangling", "true") || filter.ExactMatch("dangling", "1") {
@@ -10,11 +10,14 @@ import (
 	"github.com/pkg/errors"
 )
 
-func setupDefaultDriver(store *drivers.Store, root string, rootIDs idtools.Identity) error {
+func setupDefaultDriver(store *drivers.Store, root string, rootIDs idtools.Identity, logger VolumeEventLogger) error {
 	d, err := local.New(root, rootIDs)
 	if err != nil {
 		return errors.Wrap(err, "error setting up default driver")
 	}
+	if logger != nil {
+		d.SetEventLogger(logger)
+	}
 	if !store.Register(d, volume.DefaultDriverName) {
 		return errors.New("local volume driver could not be registered")
 	}
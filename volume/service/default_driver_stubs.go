@@ -8,4 +8,6 @@ import (
 	"github.com/docker/docker/volume/drivers"
 )
 
-func setupDefaultDriver(_ *drivers.Store, _ string, _ idtools.Identity) error { return nil }
+func setupDefaultDriver(_ *drivers.Store, _ string, _ idtools.Identity, _ VolumeEventLogger) error {
+	return nil
+}
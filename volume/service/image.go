@@ -0,0 +1,70 @@
+package service // import "github.com/docker/docker/volume/service"
+
+import (
+	"context"
+
+	volumetypes "github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/volume"
+	"github.com/docker/docker/volume/service/opts"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// ImageLabel is the label set on volumes that are populated from an image.
+// Its value is the image reference the volume was populated from. Volumes
+// with this label are always mounted read-only into containers.
+const ImageLabel = "com.docker.volume.image"
+
+// ImageMountFunc mounts the root filesystem of an image. It returns the path
+// the image is mounted at, and a function that unmounts it.
+type ImageMountFunc func(ctx context.Context, ref string) (path string, release func(), err error)
+
+// SetImageMountFunc sets the function that is used to mount images when a
+// volume is created from an image.
+func (s *VolumesService) SetImageMountFunc(fn ImageMountFunc) {
+	s.imageMount = fn
+}
+
+// createFromImage creates a volume, and populates it with the contents of the
+// root filesystem of an image, which must be present locally.
+func (s *VolumesService) createFromImage(ctx context.Context, name, driverName, ref string, options ...opts.CreateOption) (*volumetypes.Volume, error) {
+	if s.imageMount == nil {
+		return nil, errdefs.NotImplemented(errors.New("creating volumes from images is not supported"))
+	}
+	if _, err := s.vs.Get(ctx, name); err == nil {
+		return nil, errdefs.Conflict(errors.Errorf("volume %s already exists", name))
+	}
+
+	src, release, err := s.imageMount(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	options = append(options, opts.WithCreateLabel(ImageLabel, ref))
+	v, err := s.vs.Create(ctx, name, driverName, options...)
+	if err != nil {
+		return nil, err
+	}
+	if err := populateFromImage(v, src, "image-"+name); err != nil {
+		if rmErr := s.vs.Remove(ctx, v); rmErr != nil {
+			logrus.WithError(rmErr).WithField("volume", name).Warn("failed to remove volume after failing to populate it")
+		}
+		return nil, errors.Wrapf(err, "error populating volume %s from image %s", name, ref)
+	}
+
+	apiV := volumeToAPIType(v)
+	return &apiV, nil
+}
+
+// populateFromImage copies the root filesystem of an image, mounted at src,
+// to the volume.
+func populateFromImage(v volume.Volume, src, ref string) error {
+	dst, err := v.Mount(ref)
+	if err != nil {
+		return err
+	}
+	defer v.Unmount(ref)
+	return copyVolumeData(src, dst)
+}
@@ -0,0 +1,66 @@
+package service // import "github.com/docker/docker/volume/service"
+
+import (
+	"context"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/daemon/labels"
+	"github.com/docker/docker/errdefs"
+)
+
+// UpdateLabels replaces the labels of the volume with the given name with the
+// labels returned by update, which is passed the current labels and must not
+// modify them. The labels are persisted in the volume metadata, and the new
+// labels are returned.
+func (s *VolumeStore) UpdateLabels(ctx context.Context, name string, update func(map[string]string) map[string]string) (map[string]string, error) {
+	name = normalizeVolumeName(name)
+	s.locks.Lock(name)
+	defer s.locks.Unlock(name)
+
+	v, err := s.getVolume(ctx, name, "")
+	if err != nil {
+		if IsNotExist(err) {
+			err = errdefs.NotFound(err)
+		}
+		return nil, &OpErr{Err: err, Name: name, Op: "update labels"}
+	}
+	var current map[string]string
+	if w, ok := v.(volumeWrapper); ok {
+		current = w.labels
+	}
+	labels := update(current)
+
+	meta, err := s.getMeta(name)
+	if err != nil {
+		return nil, &OpErr{Err: err, Name: name, Op: "update labels"}
+	}
+	meta.Name = name
+	meta.Driver = v.DriverName()
+	meta.Labels = labels
+	if err := s.setMeta(name, meta); err != nil {
+		return nil, &OpErr{Err: err, Name: name, Op: "update labels"}
+	}
+
+	s.globalLock.Lock()
+	s.labels[name] = labels
+	if w, ok := s.names[name].(volumeWrapper); ok {
+		w.labels = labels
+		s.names[name] = w
+	}
+	s.globalLock.Unlock()
+
+	if s.eventLogger != nil {
+		s.eventLogger.LogVolumeEvent(name, "update", map[string]string{"driver": v.DriverName()})
+	}
+	return labels, nil
+}
+
+// UpdateLabels adds and removes labels of a volume, and returns the new
+// labels. The labels set by the daemon, such as the labels marking anonymous
+// and image volumes, cannot be updated.
+func (s *VolumesService) UpdateLabels(ctx context.Context, name string, update types.LabelsUpdate) (map[string]string, error) {
+	if err := labels.ValidateUpdate(update, labels.VolumePrefix); err != nil {
+		return nil, err
+	}
+	return s.vs.UpdateLabels(ctx, name, update.Apply)
+}
@@ -0,0 +1,53 @@
+package service // import "github.com/docker/docker/volume/service"
+
+import (
+	"context"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/errdefs"
+	volumedrivers "github.com/docker/docker/volume/drivers"
+	"github.com/docker/docker/volume/service/opts"
+	"github.com/docker/docker/volume/testutils"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestServiceUpdateLabels(t *testing.T) {
+	t.Parallel()
+
+	ds := volumedrivers.NewStore(nil)
+	assert.Assert(t, ds.Register(testutils.NewFakeDriver("d1"), "d1"))
+
+	ctx := context.Background()
+	service, cleanup := newTestService(t, ds)
+	defer cleanup()
+
+	_, err := service.Create(ctx, "v1", "d1", opts.WithCreateLabels(map[string]string{"a": "1", "b": "2"}))
+	assert.NilError(t, err)
+
+	labels, err := service.UpdateLabels(ctx, "v1", types.LabelsUpdate{Add: map[string]string{"c": "3"}, Remove: []string{"a"}})
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(labels, map[string]string{"b": "2", "c": "3"}))
+
+	v, err := service.Get(ctx, "v1")
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(v.Labels, map[string]string{"b": "2", "c": "3"}))
+
+	ls, _, err := service.List(ctx, filters.NewArgs(filters.Arg("label", "c=3")))
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(ls, 1))
+	assert.Check(t, is.Equal(ls[0].Name, "v1"))
+
+	ls, _, err = service.List(ctx, filters.NewArgs(filters.Arg("label", "a")))
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(ls, 0))
+
+	_, err = service.UpdateLabels(ctx, "notexist", types.LabelsUpdate{Remove: []string{"a"}})
+	assert.Check(t, errdefs.IsNotFound(err))
+	_, err = service.UpdateLabels(ctx, "v1", types.LabelsUpdate{Remove: []string{AnonymousLabel}})
+	assert.Check(t, errdefs.IsInvalidParameter(err))
+	_, err = service.UpdateLabels(ctx, "v1", types.LabelsUpdate{Add: map[string]string{ImageLabel: "busybox"}})
+	assert.Check(t, errdefs.IsInvalidParameter(err))
+}
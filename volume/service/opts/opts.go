@@ -9,6 +9,8 @@ type CreateConfig struct {
 	Options   map[string]string
 	Labels    map[string]string
 	Reference string
+	From      string
+	Image     string
 }
 
 // WithCreateLabel creates a CreateOption which adds a label with the given key/value pair
@@ -46,6 +48,23 @@ func WithCreateReference(ref string) CreateOption {
 	}
 }
 
+// WithCreateFrom creates a CreateOption which populates the new volume with
+// the contents of an existing volume, or of a snapshot of a volume if from is
+// in the "volume@snapshot" form.
+func WithCreateFrom(from string) CreateOption {
+	return func(cfg *CreateConfig) {
+		cfg.From = from
+	}
+}
+
+// WithCreateImage creates a CreateOption which populates the new volume with
+// the contents of an image.
+func WithCreateImage(ref string) CreateOption {
+	return func(cfg *CreateConfig) {
+		cfg.Image = ref
+	}
+}
+
 // GetConfig is used with `GetOption` to set options for the volumes service's
 // `Get` implementation.
 type GetConfig struct {
@@ -0,0 +1,212 @@
+package service // import "github.com/docker/docker/volume/service"
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/docker/docker/api/types/filters"
+	timetypes "github.com/docker/docker/api/types/time"
+	volumetypes "github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/directory"
+	"github.com/docker/docker/pkg/stringid"
+	"github.com/docker/docker/volume"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// pruneJobRetention is how long the result of an asynchronous prune is kept
+// after it finished.
+const pruneJobRetention = time.Hour
+
+// pruneJob is an asynchronous volume prune.
+type pruneJob struct {
+	mu     sync.Mutex
+	status volumetypes.PruneJob
+	cancel func()
+	done   time.Time
+}
+
+func (j *pruneJob) snapshot() *volumetypes.PruneJob {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	out := j.status
+	out.VolumesDeleted = append([]string{}, j.status.VolumesDeleted...)
+	return &out
+}
+
+// getUntilFromFilters returns the time given with the "until" filter.
+func getUntilFromFilters(filter filters.Args) (time.Time, error) {
+	untilFilters := filter.Get("until")
+	if len(untilFilters) > 1 {
+		return time.Time{}, errdefs.InvalidParameter(errors.New("more than one until filter specified"))
+	}
+	ts, err := timetypes.GetTimestamp(untilFilters[0], time.Now())
+	if err != nil {
+		return time.Time{}, errdefs.InvalidParameter(err)
+	}
+	seconds, nanoseconds, err := timetypes.ParseTimestamps(ts, 0)
+	if err != nil {
+		return time.Time{}, errdefs.InvalidParameter(err)
+	}
+	return time.Unix(seconds, nanoseconds), nil
+}
+
+// findPrunable returns the unused local volumes that match the prune filters.
+func (s *VolumesService) findPrunable(ctx context.Context, filter filters.Args) ([]volume.Volume, error) {
+	if err := withPrune(filter); err != nil {
+		return nil, err
+	}
+
+	by, err := filtersToBy(filter, acceptedPruneFilters)
+	if err != nil {
+		return nil, err
+	}
+	// volumes with options, including a size limit alone, are never
+	// pruned, as they were configured deliberately.
+	ls, _, err := s.vs.Find(ctx, And(ByDriver(volume.DefaultDriverName), ByReferenced(false), by, CustomFilter(func(v volume.Volume) bool {
+		dv, ok := v.(volume.DetailedVolume)
+		return ok && len(dv.Options()) == 0
+	})))
+	return ls, err
+}
+
+// pruneVolumes removes the given volumes, calling deleted for each volume
+// that was removed. It stops when ctx is canceled.
+func (s *VolumesService) pruneVolumes(ctx context.Context, ls []volume.Volume, deleted func(name string, size uint64)) error {
+	var reclaimed uint64
+	defer func() {
+		s.eventLogger.LogVolumeEvent("", "prune", map[string]string{
+			"reclaimed": strconv.FormatInt(int64(reclaimed), 10),
+		})
+	}()
+	for _, v := range ls {
+		select {
+		case <-ctx.Done():
+			err := ctx.Err()
+			if err == context.Canceled {
+				err = nil
+			}
+			return err
+		default:
+		}
+
+		vSize, err := directory.Size(ctx, v.Path())
+		if err != nil {
+			logrus.WithField("volume", v.Name()).WithError(err).Warn("could not determine size of volume")
+		}
+		if err := s.vs.Remove(ctx, v); err != nil {
+			logrus.WithError(err).WithField("volume", v.Name()).Warnf("Could not determine size of volume")
+			continue
+		}
+		if s.usage != nil {
+			s.usage.delete(v.Name())
+		}
+		reclaimed += uint64(vSize)
+		deleted(v.Name(), uint64(vSize))
+	}
+	return nil
+}
+
+// PruneAsync starts removing the (local) volumes which match the passed in
+// filter arguments in the background, and returns a job of which the
+// progress can be retrieved with PruneJob.
+func (s *VolumesService) PruneAsync(ctx context.Context, filter filters.Args) (*volumetypes.PruneJob, error) {
+	if !atomic.CompareAndSwapInt32(&s.pruneRunning, 0, 1) {
+		return nil, errdefs.Conflict(errors.New("a prune operation is already running"))
+	}
+
+	ls, err := s.findPrunable(ctx, filter)
+	if err != nil {
+		atomic.StoreInt32(&s.pruneRunning, 0)
+		return nil, err
+	}
+
+	pruneCtx, cancel := context.WithCancel(context.Background())
+	job := &pruneJob{
+		status: volumetypes.PruneJob{
+			ID:             stringid.GenerateRandomID(),
+			Status:         volumetypes.PruneJobRunning,
+			Total:          len(ls),
+			VolumesDeleted: []string{},
+			StartedAt:      time.Now().UTC().Format(time.RFC3339Nano),
+		},
+		cancel: cancel,
+	}
+
+	s.pruneJobsMu.Lock()
+	if s.pruneJobs == nil {
+		s.pruneJobs = make(map[string]*pruneJob)
+	}
+	for id, j := range s.pruneJobs {
+		j.mu.Lock()
+		expired := !j.done.IsZero() && time.Since(j.done) > pruneJobRetention
+		j.mu.Unlock()
+		if expired {
+			delete(s.pruneJobs, id)
+		}
+	}
+	s.pruneJobs[job.status.ID] = job
+	s.pruneJobsMu.Unlock()
+
+	go func() {
+		defer atomic.StoreInt32(&s.pruneRunning, 0)
+		defer cancel()
+
+		err := s.pruneVolumes(pruneCtx, ls, func(name string, size uint64) {
+			job.mu.Lock()
+			job.status.VolumesDeleted = append(job.status.VolumesDeleted, name)
+			job.status.SpaceReclaimed += size
+			job.mu.Unlock()
+		})
+
+		job.mu.Lock()
+		defer job.mu.Unlock()
+		switch {
+		case err != nil:
+			job.status.Status = volumetypes.PruneJobFailed
+			job.status.Error = err.Error()
+		case pruneCtx.Err() != nil:
+			job.status.Status = volumetypes.PruneJobCanceled
+		default:
+			job.status.Status = volumetypes.PruneJobCompleted
+		}
+		job.done = time.Now()
+		job.status.FinishedAt = job.done.UTC().Format(time.RFC3339Nano)
+	}()
+
+	return job.snapshot(), nil
+}
+
+func (s *VolumesService) getPruneJob(id string) (*pruneJob, error) {
+	s.pruneJobsMu.Lock()
+	job, ok := s.pruneJobs[id]
+	s.pruneJobsMu.Unlock()
+	if !ok {
+		return nil, errdefs.NotFound(errors.Errorf("no such prune job: %s", id))
+	}
+	return job, nil
+}
+
+// PruneJob returns the progress, or the result, of an asynchronous prune.
+func (s *VolumesService) PruneJob(ctx context.Context, id string) (*volumetypes.PruneJob, error) {
+	job, err := s.getPruneJob(id)
+	if err != nil {
+		return nil, err
+	}
+	return job.snapshot(), nil
+}
+
+// CancelPruneJob stops an asynchronous prune. Volumes that were already
+// removed are not restored.
+func (s *VolumesService) CancelPruneJob(ctx context.Context, id string) error {
+	job, err := s.getPruneJob(id)
+	if err != nil {
+		return err
+	}
+	job.cancel()
+	return nil
+}
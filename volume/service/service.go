@@ -2,14 +2,13 @@ package service // import "github.com/docker/docker/volume/service"
 
 import (
 	"context"
-	"strconv"
+	"sync"
 	"sync/atomic"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/filters"
 	volumetypes "github.com/docker/docker/api/types/volume"
 	"github.com/docker/docker/errdefs"
-	"github.com/docker/docker/pkg/directory"
 	"github.com/docker/docker/pkg/idtools"
 	"github.com/docker/docker/pkg/plugingetter"
 	"github.com/docker/docker/pkg/stringid"
@@ -17,11 +16,12 @@ import (
 	"github.com/docker/docker/volume/drivers"
 	"github.com/docker/docker/volume/service/opts"
 	"github.com/pkg/errors"
-	"github.com/sirupsen/logrus"
 )
 
 type ds interface {
 	GetDriverList() []string
+	Register(volume.Driver, string) bool
+	SetTimeouts(map[string]drivers.Timeouts)
 }
 
 // VolumeEventLogger interface provides methods to log volume-related events
@@ -37,12 +37,20 @@ type VolumesService struct {
 	ds           ds
 	pruneRunning int32
 	eventLogger  VolumeEventLogger
+	quiesce      QuiesceFunc
+	imageMount   ImageMountFunc
+
+	usage            *usageCache
+	stopUsageScanner func()
+
+	pruneJobsMu sync.Mutex
+	pruneJobs   map[string]*pruneJob
 }
 
 // NewVolumeService creates a new volume service
 func NewVolumeService(root string, pg plugingetter.PluginGetter, rootIDs idtools.Identity, logger VolumeEventLogger) (*VolumesService, error) {
 	ds := drivers.NewStore(pg)
-	if err := setupDefaultDriver(ds, root, rootIDs); err != nil {
+	if err := setupDefaultDriver(ds, root, rootIDs, logger); err != nil {
 		return nil, err
 	}
 
@@ -50,7 +58,24 @@ func NewVolumeService(root string, pg plugingetter.PluginGetter, rootIDs idtools
 	if err != nil {
 		return nil, err
 	}
-	return &VolumesService{vs: vs, ds: ds, eventLogger: logger}, nil
+	s := &VolumesService{vs: vs, ds: ds, eventLogger: logger, usage: newUsageCache()}
+	volumeUsage.setCache(s.usage)
+	s.startUsageScanner(usageScanInterval)
+	return s, nil
+}
+
+// RegisterDriver registers an additional volume driver with the given name.
+func (s *VolumesService) RegisterDriver(d volume.Driver, name string) error {
+	if !s.ds.Register(d, name) {
+		return errdefs.Conflict(errors.Errorf("volume driver %s is already registered", name))
+	}
+	return nil
+}
+
+// SetPluginTimeouts sets the timeouts of the calls to volume plugins, by
+// plugin name. See drivers.Store.SetTimeouts.
+func (s *VolumesService) SetPluginTimeouts(timeouts map[string]drivers.Timeouts) {
+	s.ds.SetTimeouts(timeouts)
 }
 
 // GetDriverList gets the list of registered volume drivers
@@ -74,6 +99,19 @@ func (s *VolumesService) Create(ctx context.Context, name, driverName string, op
 		name = stringid.GenerateRandomID()
 		options = append(options, opts.WithCreateLabel(AnonymousLabel, ""))
 	}
+	var cfg opts.CreateConfig
+	for _, o := range options {
+		o(&cfg)
+	}
+	if cfg.From != "" && cfg.Image != "" {
+		return nil, errdefs.InvalidParameter(errors.New("a volume cannot be created from both a volume and an image"))
+	}
+	if cfg.From != "" {
+		return s.createFrom(ctx, name, driverName, cfg.From, options...)
+	}
+	if cfg.Image != "" {
+		return s.createFromImage(ctx, name, driverName, cfg.Image, options...)
+	}
 	v, err := s.vs.Create(ctx, name, driverName, options...)
 	if err != nil {
 		return nil, err
@@ -122,6 +160,22 @@ func (s *VolumesService) Mount(ctx context.Context, vol *volumetypes.Volume, ref
 	return v.Mount(ref)
 }
 
+// MountWithContext mounts the volume like Mount, and passes the context it is
+// mounted for to volume drivers that can use it.
+func (s *VolumesService) MountWithContext(ctx context.Context, vol *volumetypes.Volume, ref string, mctx volume.MountContext) (string, error) {
+	v, err := s.vs.Get(ctx, vol.Name, opts.WithGetDriver(vol.Driver))
+	if err != nil {
+		if IsNotExist(err) {
+			err = errdefs.NotFound(err)
+		}
+		return "", err
+	}
+	if cm, ok := v.(volume.ContextMounter); ok {
+		return cm.MountWithContext(ref, mctx)
+	}
+	return v.Mount(ref)
+}
+
 // Unmount unmounts the volume.
 // Note that depending on the implementation, the volume may still be mounted due to other resources using it.
 //
@@ -161,6 +215,9 @@ func (s *VolumesService) Remove(ctx context.Context, name string, rmOpts ...opts
 	}
 
 	err = s.vs.Remove(ctx, v, rmOpts...)
+	if err == nil && s.usage != nil {
+		s.usage.delete(name)
+	}
 	if IsNotExist(err) {
 		err = nil
 	} else if IsInUse(err) {
@@ -174,6 +231,7 @@ func (s *VolumesService) Remove(ctx context.Context, name string, rmOpts ...opts
 var acceptedPruneFilters = map[string]bool{
 	"label":  true,
 	"label!": true,
+	"until":  true,
 	// All tells the filter to consider all volumes not just anonymous ones.
 	"all": true,
 }
@@ -185,15 +243,35 @@ var acceptedListFilters = map[string]bool{
 	"label":    true,
 }
 
+// hasMountOptions returns whether the options of a local volume configure a
+// (non-local) mount. A size limit alone does not, as the data of such volumes
+// is still stored on local disk.
+func hasMountOptions(opts map[string]string) bool {
+	for k := range opts {
+		if k != "size" {
+			return true
+		}
+	}
+	return false
+}
+
+// localDataVolumes matches the volumes of the local driver that store their
+// data on local disk.
+func localDataVolumes() By {
+	return And(ByDriver(volume.DefaultDriverName), CustomFilter(func(v volume.Volume) bool {
+		dv, ok := v.(volume.DetailedVolume)
+		return ok && !hasMountOptions(dv.Options())
+	}))
+}
+
 // LocalVolumesSize gets all local volumes and fetches their size on disk
 // Note that this intentionally skips volumes which have mount options. Typically
 // volumes with mount options are not really local even if they are using the
-// local driver.
+// local driver. Sizes are measured periodically in the background, so the
+// returned size of a volume may lag behind its actual size, unless the
+// volume has a size limit.
 func (s *VolumesService) LocalVolumesSize(ctx context.Context) ([]*volumetypes.Volume, error) {
-	ls, _, err := s.vs.Find(ctx, And(ByDriver(volume.DefaultDriverName), CustomFilter(func(v volume.Volume) bool {
-		dv, ok := v.(volume.DetailedVolume)
-		return ok && len(dv.Options()) == 0
-	})))
+	ls, _, err := s.vs.Find(ctx, localDataVolumes())
 	if err != nil {
 		return nil, err
 	}
@@ -209,49 +287,16 @@ func (s *VolumesService) Prune(ctx context.Context, filter filters.Args) (*types
 	}
 	defer atomic.StoreInt32(&s.pruneRunning, 0)
 
-	if err := withPrune(filter); err != nil {
-		return nil, err
-	}
-
-	by, err := filtersToBy(filter, acceptedPruneFilters)
+	ls, err := s.findPrunable(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
-	ls, _, err := s.vs.Find(ctx, And(ByDriver(volume.DefaultDriverName), ByReferenced(false), by, CustomFilter(func(v volume.Volume) bool {
-		dv, ok := v.(volume.DetailedVolume)
-		return ok && len(dv.Options()) == 0
-	})))
-	if err != nil {
-		return nil, err
-	}
-
 	rep := &types.VolumesPruneReport{VolumesDeleted: make([]string, 0, len(ls))}
-	for _, v := range ls {
-		select {
-		case <-ctx.Done():
-			err := ctx.Err()
-			if err == context.Canceled {
-				err = nil
-			}
-			return rep, err
-		default:
-		}
-
-		vSize, err := directory.Size(ctx, v.Path())
-		if err != nil {
-			logrus.WithField("volume", v.Name()).WithError(err).Warn("could not determine size of volume")
-		}
-		if err := s.vs.Remove(ctx, v); err != nil {
-			logrus.WithError(err).WithField("volume", v.Name()).Warnf("Could not determine size of volume")
-			continue
-		}
-		rep.SpaceReclaimed += uint64(vSize)
-		rep.VolumesDeleted = append(rep.VolumesDeleted, v.Name())
-	}
-	s.eventLogger.LogVolumeEvent("", "prune", map[string]string{
-		"reclaimed": strconv.FormatInt(int64(rep.SpaceReclaimed), 10),
+	err = s.pruneVolumes(ctx, ls, func(name string, size uint64) {
+		rep.SpaceReclaimed += size
+		rep.VolumesDeleted = append(rep.VolumesDeleted, name)
 	})
-	return rep, nil
+	return rep, err
 }
 
 // List gets the list of volumes which match the past in filters
@@ -270,7 +315,29 @@ func (s *VolumesService) List(ctx context.Context, filter filters.Args) (volumes
 	return s.volumesToAPI(ctx, volumes, useCachedPath(true)), warnings, nil
 }
 
+// GetMatching returns the volume with the given name if it matches filter,
+// or nil otherwise. Unlike List, only this volume is looked up.
+func (s *VolumesService) GetMatching(ctx context.Context, name string, filter filters.Args) (*volumetypes.Volume, error) {
+	by, err := filtersToBy(filter, acceptedListFilters)
+	if err != nil {
+		return nil, err
+	}
+	v, err := s.vs.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	ls := []volume.Volume{v}
+	volumes, _, err := s.vs.Find(ctx, FromList(&ls, by))
+	if err != nil || len(volumes) == 0 {
+		return nil, err
+	}
+	return s.volumesToAPI(ctx, volumes, useCachedPath(true))[0], nil
+}
+
 // Shutdown shuts down the image service and dependencies
 func (s *VolumesService) Shutdown() error {
+	if s.stopUsageScanner != nil {
+		s.stopUsageScanner()
+	}
 	return s.vs.Shutdown()
 }
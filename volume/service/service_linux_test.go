@@ -6,14 +6,18 @@ import (
 	"path/filepath"
 	"testing"
 
+	volumetypes "github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/errdefs"
 	"github.com/docker/docker/pkg/idtools"
 	"github.com/docker/docker/volume"
 	volumedrivers "github.com/docker/docker/volume/drivers"
 	"github.com/docker/docker/volume/local"
 	"github.com/docker/docker/volume/service/opts"
 	"github.com/docker/docker/volume/testutils"
+	"github.com/pkg/errors"
 	"gotest.tools/v3/assert"
 	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/skip"
 )
 
 func TestLocalVolumeSize(t *testing.T) {
@@ -63,3 +67,177 @@ func TestLocalVolumeSize(t *testing.T) {
 		}
 	}
 }
+
+func TestLocalVolumeCreateFrom(t *testing.T) {
+	t.Parallel()
+
+	ds := volumedrivers.NewStore(nil)
+	dir := t.TempDir()
+
+	l, err := local.New(dir, idtools.Identity{UID: os.Getuid(), GID: os.Getegid()})
+	assert.NilError(t, err)
+	assert.Assert(t, ds.Register(l, volume.DefaultDriverName))
+
+	service, cleanup := newTestService(t, ds)
+	defer cleanup()
+
+	ctx := context.Background()
+	src, err := service.Create(ctx, "src", volume.DefaultDriverName)
+	assert.NilError(t, err)
+	assert.NilError(t, os.MkdirAll(filepath.Join(src.Mountpoint, "sub"), 0755))
+	assert.NilError(t, os.WriteFile(filepath.Join(src.Mountpoint, "sub", "data"), []byte("hello"), 0644))
+
+	_, err = service.Create(ctx, "clone", "", opts.WithCreateFrom("notexist"))
+	assert.Check(t, errdefs.IsNotFound(err), err)
+	_, err = service.Create(ctx, "src", "", opts.WithCreateFrom("src"))
+	assert.Check(t, errdefs.IsInvalidParameter(err), err)
+	_, err = service.Create(ctx, "clone", "", opts.WithCreateFrom("src@../../.."))
+	assert.Check(t, errdefs.IsInvalidParameter(err), err)
+
+	clone, err := service.Create(ctx, "clone", "", opts.WithCreateFrom("src"))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(clone.Driver, volume.DefaultDriverName))
+	data, err := os.ReadFile(filepath.Join(clone.Mountpoint, "sub", "data"))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(data), "hello"))
+
+	_, err = service.Create(ctx, "clone", "", opts.WithCreateFrom("src"))
+	assert.Check(t, errdefs.IsConflict(err), err)
+
+	// the source volume must not keep a reference after cloning
+	assert.NilError(t, service.Remove(ctx, "src"))
+}
+
+func TestLocalVolumeCreateFromImage(t *testing.T) {
+	t.Parallel()
+
+	ds := volumedrivers.NewStore(nil)
+	dir := t.TempDir()
+
+	l, err := local.New(dir, idtools.Identity{UID: os.Getuid(), GID: os.Getegid()})
+	assert.NilError(t, err)
+	assert.Assert(t, ds.Register(l, volume.DefaultDriverName))
+
+	service, cleanup := newTestService(t, ds)
+	defer cleanup()
+
+	ctx := context.Background()
+	_, err = service.Create(ctx, "model", "", opts.WithCreateImage("example/model:v2"))
+	assert.Check(t, errdefs.IsNotImplemented(err), err)
+
+	rootfs := t.TempDir()
+	assert.NilError(t, os.MkdirAll(filepath.Join(rootfs, "weights"), 0755))
+	assert.NilError(t, os.WriteFile(filepath.Join(rootfs, "weights", "data"), []byte("hello"), 0644))
+
+	var released bool
+	service.SetImageMountFunc(func(_ context.Context, ref string) (string, func(), error) {
+		if ref != "example/model:v2" {
+			return "", nil, errdefs.NotFound(errors.New("no such image: " + ref))
+		}
+		return rootfs, func() { released = true }, nil
+	})
+
+	_, err = service.Create(ctx, "model", "", opts.WithCreateImage("notexist"))
+	assert.Check(t, errdefs.IsNotFound(err), err)
+	_, err = service.Create(ctx, "model", "", opts.WithCreateImage("example/model:v2"), opts.WithCreateFrom("src"))
+	assert.Check(t, errdefs.IsInvalidParameter(err), err)
+
+	v, err := service.Create(ctx, "model", "", opts.WithCreateImage("example/model:v2"))
+	assert.NilError(t, err)
+	assert.Check(t, released)
+	assert.Check(t, is.Equal(v.Labels[ImageLabel], "example/model:v2"))
+	data, err := os.ReadFile(filepath.Join(v.Mountpoint, "weights", "data"))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(data), "hello"))
+
+	_, err = service.Create(ctx, "model", "", opts.WithCreateImage("example/model:v2"))
+	assert.Check(t, errdefs.IsConflict(err), err)
+}
+
+func TestLocalVolumeExportImport(t *testing.T) {
+	skip.If(t, os.Getuid() != 0, "skipping test that requires root")
+	t.Parallel()
+
+	ds := volumedrivers.NewStore(nil)
+	dir := t.TempDir()
+
+	l, err := local.New(dir, idtools.Identity{UID: os.Getuid(), GID: os.Getegid()})
+	assert.NilError(t, err)
+	assert.Assert(t, ds.Register(l, volume.DefaultDriverName))
+
+	service, cleanup := newTestService(t, ds)
+	defer cleanup()
+
+	var quiesced []string
+	resumed := false
+	service.SetQuiesceFunc(func(ctx context.Context, refs []string) (func(), error) {
+		quiesced = refs
+		return func() { resumed = true }, nil
+	})
+
+	ctx := context.Background()
+	src, err := service.Create(ctx, "src", volume.DefaultDriverName, opts.WithCreateReference("ctr1"))
+	assert.NilError(t, err)
+	assert.NilError(t, os.WriteFile(filepath.Join(src.Mountpoint, "data"), []byte("hello"), 0644))
+
+	rc, err := service.Export(ctx, "src", volumetypes.ExportOptions{Compression: "zstd", Quiesce: true})
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(quiesced, []string{"ctr1"}))
+
+	dst, err := service.Create(ctx, "dst", volume.DefaultDriverName, opts.WithCreateReference("ctr2"))
+	assert.NilError(t, err)
+	err = service.Import(ctx, "dst", rc)
+	assert.Check(t, errdefs.IsConflict(err), "expected importing into a volume in use to fail, got %v", err)
+	assert.NilError(t, service.Release(ctx, "dst", "ctr2"))
+
+	err = service.Import(ctx, "dst", rc)
+	assert.NilError(t, err)
+	assert.NilError(t, rc.Close())
+	assert.Check(t, resumed)
+
+	data, err := os.ReadFile(filepath.Join(dst.Mountpoint, "data"))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(data), "hello"))
+
+	_, err = service.Export(ctx, "src", volumetypes.ExportOptions{Compression: "bzip2"})
+	assert.Check(t, errdefs.IsInvalidParameter(err))
+}
+
+func TestLocalVolumeSizeCached(t *testing.T) {
+	t.Parallel()
+
+	ds := volumedrivers.NewStore(nil)
+	dir := t.TempDir()
+
+	l, err := local.New(dir, idtools.Identity{UID: os.Getuid(), GID: os.Getegid()})
+	assert.NilError(t, err)
+	assert.Assert(t, ds.Register(l, volume.DefaultDriverName))
+
+	service, cleanup := newTestService(t, ds)
+	defer cleanup()
+	service.usage = newUsageCache()
+
+	ctx := context.Background()
+	v, err := service.Create(ctx, "test1", volume.DefaultDriverName)
+	assert.NilError(t, err)
+
+	ls, err := service.LocalVolumesSize(ctx)
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(ls, 1))
+	assert.Check(t, is.Equal(ls[0].UsageData.Size, int64(0)))
+
+	// the cached size is returned until the volume is scanned again
+	assert.NilError(t, os.WriteFile(filepath.Join(v.Mountpoint, "data"), make([]byte, 1024), 0644))
+	ls, err = service.LocalVolumesSize(ctx)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(ls[0].UsageData.Size, int64(0)))
+
+	service.scanUsage(ctx)
+	ls, err = service.LocalVolumesSize(ctx)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(ls[0].UsageData.Size, int64(1024)))
+
+	assert.NilError(t, service.Remove(ctx, "test1"))
+	_, ok := service.usage.get("test1")
+	assert.Check(t, !ok)
+}
@@ -3,9 +3,12 @@ package service
 import (
 	"context"
 	"os"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/docker/docker/api/types/filters"
+	volumetypes "github.com/docker/docker/api/types/volume"
 	"github.com/docker/docker/errdefs"
 	"github.com/docker/docker/volume"
 	volumedrivers "github.com/docker/docker/volume/drivers"
@@ -13,6 +16,7 @@ import (
 	"github.com/docker/docker/volume/testutils"
 	"gotest.tools/v3/assert"
 	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/poll"
 )
 
 func TestServiceCreate(t *testing.T) {
@@ -231,6 +235,74 @@ func TestServicePrune(t *testing.T) {
 	assert.Assert(t, is.Equal(pr.VolumesDeleted[0], "test"))
 }
 
+func TestServicePruneUntil(t *testing.T) {
+	t.Parallel()
+
+	ds := volumedrivers.NewStore(nil)
+	assert.Assert(t, ds.Register(testutils.NewFakeDriver(volume.DefaultDriverName), volume.DefaultDriverName))
+
+	service, cleanup := newTestService(t, ds)
+	defer cleanup()
+	ctx := context.Background()
+
+	_, err := service.Create(ctx, "test", volume.DefaultDriverName)
+	assert.NilError(t, err)
+
+	pr, err := service.Prune(ctx, filters.NewArgs(filters.Arg("all", "true"), filters.Arg("until", "1h")))
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(pr.VolumesDeleted, 0))
+
+	until := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+	pr, err = service.Prune(ctx, filters.NewArgs(filters.Arg("all", "true"), filters.Arg("until", until)))
+	assert.NilError(t, err)
+	assert.Assert(t, is.DeepEqual(pr.VolumesDeleted, []string{"test"}))
+
+	_, err = service.Prune(ctx, filters.NewArgs(filters.Arg("until", "banana")))
+	assert.Assert(t, errdefs.IsInvalidParameter(err), err)
+}
+
+func TestServicePruneAsync(t *testing.T) {
+	t.Parallel()
+
+	ds := volumedrivers.NewStore(nil)
+	assert.Assert(t, ds.Register(testutils.NewFakeDriver(volume.DefaultDriverName), volume.DefaultDriverName))
+
+	service, cleanup := newTestService(t, ds)
+	defer cleanup()
+	ctx := context.Background()
+
+	_, err := service.Create(ctx, "test", volume.DefaultDriverName)
+	assert.NilError(t, err)
+	_, err = service.Create(ctx, "test2", volume.DefaultDriverName)
+	assert.NilError(t, err)
+
+	job, err := service.PruneAsync(ctx, filters.NewArgs(filters.Arg("all", "true")))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(job.Total, 2))
+
+	poll.WaitOn(t, func(poll.LogT) poll.Result {
+		job, err = service.PruneJob(ctx, job.ID)
+		if err != nil {
+			return poll.Error(err)
+		}
+		if job.Status == volumetypes.PruneJobRunning {
+			return poll.Continue("prune job is still running")
+		}
+		return poll.Success()
+	}, poll.WithDelay(10*time.Millisecond))
+	assert.Check(t, is.Equal(job.Status, volumetypes.PruneJobCompleted))
+	assert.Check(t, is.Len(job.VolumesDeleted, 2))
+	assert.Check(t, job.FinishedAt != "")
+
+	_, err = service.Get(ctx, "test")
+	assert.Assert(t, IsNotExist(err), err)
+
+	_, err = service.PruneJob(ctx, "nosuchjob")
+	assert.Assert(t, errdefs.IsNotFound(err), err)
+	err = service.CancelPruneJob(ctx, "nosuchjob")
+	assert.Assert(t, errdefs.IsNotFound(err), err)
+}
+
 func newTestService(t *testing.T, ds *volumedrivers.Store) (*VolumesService, func()) {
 	t.Helper()
 
@@ -249,3 +321,27 @@ func newTestService(t *testing.T, ds *volumedrivers.Store) (*VolumesService, fun
 type dummyEventLogger struct{}
 
 func (dummyEventLogger) LogVolumeEvent(_, _ string, _ map[string]string) {}
+
+func TestUsageCacheRemovedDuringScan(t *testing.T) {
+	c := newUsageCache()
+	c.set("v1", 1)
+
+	c.startScan()
+	c.delete("v1")
+	c.replace(map[string]int64{"v1": 1, "v2": 2})
+	c.stopScan()
+
+	_, ok := c.get("v1")
+	assert.Check(t, !ok)
+	size, ok := c.get("v2")
+	assert.Check(t, ok)
+	assert.Check(t, is.Equal(size, int64(2)))
+
+	// removals are only tracked during scans
+	c.delete("v2")
+	c.startScan()
+	c.replace(map[string]int64{"v2": 2})
+	c.stopScan()
+	_, ok = c.get("v2")
+	assert.Check(t, ok)
+}
@@ -0,0 +1,187 @@
+package service // import "github.com/docker/docker/volume/service"
+
+import (
+	"context"
+	"time"
+
+	volumetypes "github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/daemon/names"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/volume"
+	"github.com/pkg/errors"
+)
+
+// snapshotDriver returns the volume with the given name, and its driver if
+// the driver supports snapshots.
+func (s *VolumeStore) snapshotDriver(ctx context.Context, name string) (volume.Volume, volume.SnapshotDriver, error) {
+	v, err := s.getVolume(ctx, name, "")
+	if err != nil {
+		if IsNotExist(err) {
+			err = errdefs.NotFound(err)
+		}
+		return nil, nil, err
+	}
+	vd, err := s.drivers.GetDriver(v.DriverName())
+	if err != nil {
+		return nil, nil, &OpErr{Err: err, Name: v.DriverName(), Op: "snapshot"}
+	}
+	sd, ok := vd.(volume.SnapshotDriver)
+	if !ok {
+		return nil, nil, errdefs.NotImplemented(errors.Errorf("volume driver %s does not support snapshots", v.DriverName()))
+	}
+	return unwrapVolume(v), sd, nil
+}
+
+// validateSnapshotName checks that a snapshot name is valid, before it is
+// passed to a volume driver.
+func validateSnapshotName(snapshot string) error {
+	if !names.RestrictedNamePattern.MatchString(snapshot) {
+		return errdefs.InvalidParameter(errors.Errorf("invalid snapshot name %q, only %q are allowed", snapshot, names.RestrictedNameChars))
+	}
+	return nil
+}
+
+// CreateSnapshot takes a snapshot of the volume with the given name.
+func (s *VolumeStore) CreateSnapshot(ctx context.Context, name, snapshot string) (volume.Volume, volume.Snapshot, error) {
+	if err := validateSnapshotName(snapshot); err != nil {
+		return nil, volume.Snapshot{}, err
+	}
+
+	name = normalizeVolumeName(name)
+	s.locks.Lock(name)
+	defer s.locks.Unlock(name)
+
+	v, sd, err := s.snapshotDriver(ctx, name)
+	if err != nil {
+		return nil, volume.Snapshot{}, err
+	}
+	snap, err := sd.CreateSnapshot(v, snapshot)
+	if err != nil {
+		return nil, volume.Snapshot{}, &OpErr{Err: err, Name: name, Op: "snapshot"}
+	}
+	if s.eventLogger != nil {
+		s.eventLogger.LogVolumeEvent(name, "snapshot", map[string]string{"driver": v.DriverName(), "snapshot": snap.Name})
+	}
+	return v, snap, nil
+}
+
+// ListSnapshots lists the snapshots of the volume with the given name.
+func (s *VolumeStore) ListSnapshots(ctx context.Context, name string) (volume.Volume, []volume.Snapshot, error) {
+	name = normalizeVolumeName(name)
+	s.locks.Lock(name)
+	defer s.locks.Unlock(name)
+
+	v, sd, err := s.snapshotDriver(ctx, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	ls, err := sd.ListSnapshots(v)
+	if err != nil {
+		return nil, nil, &OpErr{Err: err, Name: name, Op: "list snapshots"}
+	}
+	return v, ls, nil
+}
+
+// RemoveSnapshot removes a snapshot of the volume with the given name.
+func (s *VolumeStore) RemoveSnapshot(ctx context.Context, name, snapshot string) error {
+	if err := validateSnapshotName(snapshot); err != nil {
+		return err
+	}
+	name = normalizeVolumeName(name)
+	s.locks.Lock(name)
+	defer s.locks.Unlock(name)
+
+	v, sd, err := s.snapshotDriver(ctx, name)
+	if err != nil {
+		return err
+	}
+	if err := sd.RemoveSnapshot(v, snapshot); err != nil {
+		return &OpErr{Err: err, Name: name, Op: "remove snapshot"}
+	}
+	if s.eventLogger != nil {
+		s.eventLogger.LogVolumeEvent(name, "snapshot-remove", map[string]string{"driver": v.DriverName(), "snapshot": snapshot})
+	}
+	return nil
+}
+
+// RestoreSnapshot reverts the volume with the given name to a snapshot. The
+// volume must not be referenced by any container.
+func (s *VolumeStore) RestoreSnapshot(ctx context.Context, name, snapshot string) error {
+	if err := validateSnapshotName(snapshot); err != nil {
+		return err
+	}
+	name = normalizeVolumeName(name)
+	s.locks.Lock(name)
+	defer s.locks.Unlock(name)
+
+	if s.hasRef(name) {
+		return &OpErr{Err: errVolumeInUse, Name: name, Op: "restore", Refs: s.getRefs(name)}
+	}
+	v, sd, err := s.snapshotDriver(ctx, name)
+	if err != nil {
+		return err
+	}
+	if err := sd.RestoreSnapshot(v, snapshot); err != nil {
+		return &OpErr{Err: err, Name: name, Op: "restore"}
+	}
+	if s.eventLogger != nil {
+		s.eventLogger.LogVolumeEvent(name, "restore", map[string]string{"driver": v.DriverName(), "snapshot": snapshot})
+	}
+	return nil
+}
+
+// CreateSnapshot takes a snapshot of a volume. If no snapshot name is given,
+// a name is generated from the current time.
+func (s *VolumesService) CreateSnapshot(ctx context.Context, name string, options volumetypes.SnapshotCreateOptions) (*volumetypes.Snapshot, error) {
+	snapshot := options.Name
+	if snapshot == "" {
+		snapshot = time.Now().UTC().Format("20060102T150405Z")
+	}
+	v, snap, err := s.vs.CreateSnapshot(ctx, name, snapshot)
+	if err != nil {
+		return nil, err
+	}
+	out := snapshotToAPIType(v, snap)
+	return &out, nil
+}
+
+// ListSnapshots lists the snapshots of a volume.
+func (s *VolumesService) ListSnapshots(ctx context.Context, name string) ([]volumetypes.Snapshot, error) {
+	v, ls, err := s.vs.ListSnapshots(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]volumetypes.Snapshot, 0, len(ls))
+	for _, snap := range ls {
+		out = append(out, snapshotToAPIType(v, snap))
+	}
+	return out, nil
+}
+
+// RemoveSnapshot removes a snapshot of a volume.
+func (s *VolumesService) RemoveSnapshot(ctx context.Context, name, snapshot string) error {
+	return s.vs.RemoveSnapshot(ctx, name, snapshot)
+}
+
+// RestoreSnapshot reverts a volume to one of its snapshots. An error is
+// returned if the volume is in use.
+func (s *VolumesService) RestoreSnapshot(ctx context.Context, name, snapshot string) error {
+	err := s.vs.RestoreSnapshot(ctx, name, snapshot)
+	if IsInUse(err) {
+		err = errdefs.Conflict(err)
+	}
+	return err
+}
+
+func snapshotToAPIType(v volume.Volume, snap volume.Snapshot) volumetypes.Snapshot {
+	out := volumetypes.Snapshot{
+		Name:   snap.Name,
+		Volume: v.Name(),
+		Driver: v.DriverName(),
+		Size:   snap.Size,
+	}
+	if !snap.CreatedAt.IsZero() {
+		out.CreatedAt = snap.CreatedAt.Format(time.RFC3339)
+	}
+	return out
+}
@@ -0,0 +1,97 @@
+package service // import "github.com/docker/docker/volume/service"
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	volumetypes "github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/volume"
+	volumedrivers "github.com/docker/docker/volume/drivers"
+	"github.com/docker/docker/volume/service/opts"
+	"github.com/docker/docker/volume/testutils"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+type fakeSnapshotDriver struct {
+	volume.Driver
+	snapshots map[string][]volume.Snapshot
+	restored  string
+}
+
+func (d *fakeSnapshotDriver) CreateSnapshot(v volume.Volume, name string) (volume.Snapshot, error) {
+	snap := volume.Snapshot{Name: name, CreatedAt: time.Now(), Size: -1}
+	d.snapshots[v.Name()] = append(d.snapshots[v.Name()], snap)
+	return snap, nil
+}
+
+func (d *fakeSnapshotDriver) ListSnapshots(v volume.Volume) ([]volume.Snapshot, error) {
+	return d.snapshots[v.Name()], nil
+}
+
+func (d *fakeSnapshotDriver) RemoveSnapshot(v volume.Volume, name string) error {
+	d.snapshots[v.Name()] = nil
+	return nil
+}
+
+func (d *fakeSnapshotDriver) RestoreSnapshot(v volume.Volume, name string) error {
+	d.restored = name
+	return nil
+}
+
+func TestServiceSnapshots(t *testing.T) {
+	t.Parallel()
+
+	sd := &fakeSnapshotDriver{Driver: testutils.NewFakeDriver("snap"), snapshots: map[string][]volume.Snapshot{}}
+	ds := volumedrivers.NewStore(nil)
+	assert.Assert(t, ds.Register(sd, "snap"))
+	assert.Assert(t, ds.Register(testutils.NewFakeDriver("nosnap"), "nosnap"))
+
+	ctx := context.Background()
+	service, cleanup := newTestService(t, ds)
+	defer cleanup()
+
+	_, err := service.Create(ctx, "v1", "snap")
+	assert.NilError(t, err)
+	_, err = service.Create(ctx, "v2", "nosnap")
+	assert.NilError(t, err)
+
+	_, err = service.CreateSnapshot(ctx, "v2", volumetypes.SnapshotCreateOptions{Name: "s1"})
+	assert.Check(t, errdefs.IsNotImplemented(err), err)
+	_, err = service.CreateSnapshot(ctx, "notexist", volumetypes.SnapshotCreateOptions{Name: "s1"})
+	assert.Check(t, errdefs.IsNotFound(err), err)
+	_, err = service.CreateSnapshot(ctx, "v1", volumetypes.SnapshotCreateOptions{Name: "../s1"})
+	assert.Check(t, errdefs.IsInvalidParameter(err), err)
+
+	snap, err := service.CreateSnapshot(ctx, "v1", volumetypes.SnapshotCreateOptions{Name: "s1"})
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(snap.Name, "s1"))
+	assert.Check(t, is.Equal(snap.Volume, "v1"))
+	assert.Check(t, is.Equal(snap.Driver, "snap"))
+
+	ls, err := service.ListSnapshots(ctx, "v1")
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(ls, 1))
+
+	_, err = service.Create(ctx, "v1", "snap", opts.WithCreateReference("ctr1"))
+	assert.NilError(t, err)
+	err = service.RestoreSnapshot(ctx, "v1", "s1")
+	assert.Check(t, errdefs.IsConflict(err), err)
+
+	assert.NilError(t, service.Release(ctx, "v1", "ctr1"))
+	err = service.RestoreSnapshot(ctx, "v1", "../s1")
+	assert.Check(t, errdefs.IsInvalidParameter(err), err)
+	err = service.RemoveSnapshot(ctx, "v1", "s1/..")
+	assert.Check(t, errdefs.IsInvalidParameter(err), err)
+	assert.Check(t, is.Equal(sd.restored, ""))
+
+	assert.NilError(t, service.RestoreSnapshot(ctx, "v1", "s1"))
+	assert.Check(t, is.Equal(sd.restored, "s1"))
+
+	assert.NilError(t, service.RemoveSnapshot(ctx, "v1", "s1"))
+	ls, err = service.ListSnapshots(ctx, "v1")
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(ls, 0))
+}
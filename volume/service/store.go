@@ -58,6 +58,13 @@ func (v volumeWrapper) Scope() string {
 	return v.scope
 }
 
+func (v volumeWrapper) MountWithContext(id string, mctx volume.MountContext) (string, error) {
+	if vv, ok := v.Volume.(volume.ContextMounter); ok {
+		return vv.MountWithContext(id, mctx)
+	}
+	return v.Volume.Mount(id)
+}
+
 func (v volumeWrapper) CachedPath() string {
 	if vv, ok := v.Volume.(interface {
 		CachedPath() string
@@ -0,0 +1,216 @@
+package service // import "github.com/docker/docker/volume/service"
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/pkg/directory"
+	"github.com/docker/docker/volume"
+	metrics "github.com/docker/go-metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+// usageScanInterval is the interval at which the disk usage of local volumes
+// is measured in the background.
+const usageScanInterval = 10 * time.Minute
+
+var (
+	metricsNS   = metrics.NewNamespace("engine", "volume", nil)
+	volumeUsage = &usageCollector{
+		desc: metricsNS.NewDesc("usage", "The disk space used by local volumes", metrics.Bytes, "volume"),
+	}
+)
+
+func init() {
+	metricsNS.Add(volumeUsage)
+	metrics.Register(metricsNS)
+}
+
+// usageReporter is implemented by volumes that account for the disk space
+// they use, such as local volumes with a size limit, so that their usage
+// does not have to be measured by walking their contents.
+type usageReporter interface {
+	UsedBytes() (int64, bool)
+}
+
+// usageCache holds the disk usage of local volumes, as measured by the last
+// background scan, or by the last time it was requested.
+type usageCache struct {
+	mu    sync.Mutex
+	sizes map[string]int64
+
+	// removed holds the volumes removed while a background scan is in
+	// progress, whose measured sizes must not be added back to the cache.
+	removed map[string]struct{}
+}
+
+func newUsageCache() *usageCache {
+	return &usageCache{sizes: make(map[string]int64)}
+}
+
+func (c *usageCache) get(name string) (int64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	size, ok := c.sizes[name]
+	return size, ok
+}
+
+func (c *usageCache) set(name string, size int64) {
+	c.mu.Lock()
+	c.sizes[name] = size
+	c.mu.Unlock()
+}
+
+func (c *usageCache) delete(name string) {
+	c.mu.Lock()
+	delete(c.sizes, name)
+	if c.removed != nil {
+		c.removed[name] = struct{}{}
+	}
+	c.mu.Unlock()
+}
+
+// startScan starts tracking the volumes removed during a background scan.
+func (c *usageCache) startScan() {
+	c.mu.Lock()
+	c.removed = make(map[string]struct{})
+	c.mu.Unlock()
+}
+
+// stopScan stops tracking the volumes removed during a background scan.
+func (c *usageCache) stopScan() {
+	c.mu.Lock()
+	c.removed = nil
+	c.mu.Unlock()
+}
+
+// replace replaces the cache with the sizes measured by a background scan,
+// except for the volumes removed since the scan started.
+func (c *usageCache) replace(sizes map[string]int64) {
+	c.mu.Lock()
+	for name := range c.removed {
+		delete(sizes, name)
+	}
+	c.sizes = sizes
+	c.mu.Unlock()
+}
+
+// usageCollector exposes the disk usage of local volumes of the volume
+// service of the daemon as metrics.
+type usageCollector struct {
+	desc  *prometheus.Desc
+	mu    sync.Mutex
+	cache *usageCache
+}
+
+func (u *usageCollector) setCache(c *usageCache) {
+	u.mu.Lock()
+	u.cache = c
+	u.mu.Unlock()
+}
+
+// Describe implements prometheus.Collector.
+func (u *usageCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- u.desc
+}
+
+// Collect implements prometheus.Collector.
+func (u *usageCollector) Collect(ch chan<- prometheus.Metric) {
+	u.mu.Lock()
+	c := u.cache
+	u.mu.Unlock()
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for name, size := range c.sizes {
+		if size < 0 {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(u.desc, prometheus.GaugeValue, float64(size), name)
+	}
+}
+
+// volumeSize returns the disk space used by a volume. The usage reported by
+// the volume is used if available. Otherwise, the usage as measured by the
+// last background scan is used, and the contents of the volume are only
+// walked if it has not been measured yet.
+func (s *VolumesService) volumeSize(ctx context.Context, v volume.Volume) int64 {
+	if s.usage == nil {
+		return measureSize(ctx, v)
+	}
+	if _, reported := usedBytes(v); !reported {
+		if size, ok := s.usage.get(v.Name()); ok {
+			return size
+		}
+	}
+	size := measureSize(ctx, v)
+	s.usage.set(v.Name(), size)
+	return size
+}
+
+func usedBytes(v volume.Volume) (int64, bool) {
+	if ur, ok := unwrapVolume(v).(usageReporter); ok {
+		return ur.UsedBytes()
+	}
+	return 0, false
+}
+
+// measureSize returns the disk space used by a volume, as reported by the
+// volume, or by walking its contents.
+func measureSize(ctx context.Context, v volume.Volume) int64 {
+	if size, ok := usedBytes(v); ok {
+		return size
+	}
+	size, err := directory.Size(ctx, v.Path())
+	if err != nil {
+		logrus.WithError(err).WithField("volume", v.Name()).Warnf("Failed to determine size of volume")
+		return -1
+	}
+	return size
+}
+
+// scanUsage measures the disk usage of all local volumes, and replaces the
+// usage cache with the results.
+func (s *VolumesService) scanUsage(ctx context.Context) {
+	s.usage.startScan()
+	defer s.usage.stopScan()
+
+	ls, _, err := s.vs.Find(ctx, localDataVolumes())
+	if err != nil {
+		logrus.WithError(err).Warn("Failed to list volumes to determine their disk usage")
+		return
+	}
+	sizes := make(map[string]int64, len(ls))
+	for _, v := range ls {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+		sizes[v.Name()] = measureSize(ctx, v)
+	}
+	s.usage.replace(sizes)
+}
+
+// startUsageScanner periodically measures the disk usage of local volumes in
+// the background, until the service is shut down.
+func (s *VolumesService) startUsageScanner(interval time.Duration) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.stopUsageScanner = cancel
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.scanUsage(ctx)
+			}
+		}
+	}()
+}
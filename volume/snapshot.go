@@ -0,0 +1,37 @@
+package volume // import "github.com/docker/docker/volume"
+
+import "time"
+
+// Snapshot is a point-in-time copy of a volume.
+type Snapshot struct {
+	// Name is the name of the snapshot, which is unique for the volume.
+	Name string
+	// CreatedAt is the time the snapshot was taken.
+	CreatedAt time.Time
+	// Size is the space used by the snapshot in bytes, or -1 if it is not
+	// known.
+	Size int64
+}
+
+// SnapshotDriver is implemented by volume drivers that can take snapshots
+// of their volumes.
+type SnapshotDriver interface {
+	// CreateSnapshot takes a snapshot of the volume with the given name.
+	CreateSnapshot(vol Volume, name string) (Snapshot, error)
+	// ListSnapshots lists the snapshots of the volume.
+	ListSnapshots(vol Volume) ([]Snapshot, error)
+	// RemoveSnapshot removes the snapshot of the volume with the given name.
+	RemoveSnapshot(vol Volume, name string) error
+	// RestoreSnapshot reverts the volume to the snapshot with the given
+	// name. The volume must not be in use.
+	RestoreSnapshot(vol Volume, name string) error
+}
+
+// SnapshotPather is implemented by snapshot drivers that can expose the
+// contents of a snapshot on the host, so that a volume can be created from
+// the snapshot.
+type SnapshotPather interface {
+	// SnapshotPath returns the path of the contents of the snapshot of the
+	// volume with the given name. The contents must not be modified.
+	SnapshotPath(vol Volume, name string) (string, error)
+}
@@ -2,6 +2,8 @@ package volume // import "github.com/docker/docker/volume"
 
 import (
 	"time"
+
+	"github.com/docker/docker/pkg/idtools"
 )
 
 // DefaultDriverName is the driver name used for the driver
@@ -39,6 +41,20 @@ type Capability struct {
 	// A `local` scope indicates that the driver only manages volumes resources local to the host
 	// Scope is declared by the driver
 	Scope string
+	// Protocol is the version of the volume plugin protocol that the driver
+	// implements. Drivers that do not declare it implement version 1.
+	Protocol int
+	// AsyncMount indicates that the driver may return a pending mount, of
+	// which the status is polled until the volume is mounted. It requires
+	// version 2 of the protocol.
+	AsyncMount bool
+	// AsyncUnmount indicates that the driver may return a pending unmount,
+	// of which the status is polled until the volume is unmounted. It
+	// requires version 2 of the protocol.
+	AsyncUnmount bool
+	// MountContext indicates that the driver wants to receive the context a
+	// volume is mounted for. It requires version 2 of the protocol.
+	MountContext bool
 }
 
 // Volume is a place to store data. It is backed by a specific driver, and can be mounted.
@@ -60,6 +76,25 @@ type Volume interface {
 	Status() map[string]interface{}
 }
 
+// MountContext describes what a volume is mounted for.
+type MountContext struct {
+	// ContainerID is the ID of the container the volume is mounted for.
+	ContainerID string `json:",omitempty"`
+	// UIDMaps and GIDMaps are the user namespace mappings of the daemon,
+	// which apply to the container.
+	UIDMaps []idtools.IDMap `json:",omitempty"`
+	GIDMaps []idtools.IDMap `json:",omitempty"`
+}
+
+// ContextMounter is implemented by volumes that can use the context they are
+// mounted for, such as volumes of plugins implementing version 2 of the
+// volume plugin protocol.
+type ContextMounter interface {
+	// MountWithContext mounts the volume like Mount, and passes the context
+	// it is mounted for to the driver.
+	MountWithContext(id string, mctx MountContext) (string, error)
+}
+
 // DetailedVolume wraps a Volume with user-defined labels, options, and cluster scope (e.g., `local` or `global`)
 type DetailedVolume interface {
 	Labels() map[string]string